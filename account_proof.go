@@ -0,0 +1,115 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"encoding/binary"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/pkg/errors"
+)
+
+// AccountProof proves the balance, stake, reward and gas balance of an
+// account against the Merkle root of the state of the ledger at a block, by
+// proving each field on its own.
+type AccountProof struct {
+	ID AccountID
+
+	Balance    avl.Proof
+	Stake      avl.Proof
+	Reward     avl.Proof
+	GasBalance avl.Proof
+}
+
+// AccountBalances are the fields of an account an AccountProof proves, each
+// being zero should the account not have it set.
+type AccountBalances struct {
+	Balance    uint64
+	Stake      uint64
+	Reward     uint64
+	GasBalance uint64
+}
+
+type accountProofField struct {
+	key   []byte
+	proof *avl.Proof
+	value *uint64
+}
+
+func (p *AccountProof) fields(balances *AccountBalances) []accountProofField {
+	return []accountProofField{
+		{key: keyAccountBalance[:], proof: &p.Balance, value: &balances.Balance},
+		{key: keyAccountStake[:], proof: &p.Stake, value: &balances.Stake},
+		{key: keyAccountReward[:], proof: &p.Reward, value: &balances.Reward},
+		{key: keyAccountContractGasBalance[:], proof: &p.GasBalance, value: &balances.GasBalance},
+	}
+}
+
+// ProveAccount returns a proof of the balances of the account with ID id in
+// the ledger state tree.
+func ProveAccount(tree *avl.Tree, id AccountID) (AccountProof, error) {
+	proof := AccountProof{ID: id}
+
+	for _, field := range proof.fields(new(AccountBalances)) {
+		var err error
+
+		if *field.proof, err = tree.Prove(accountFieldKey(id, field.key)); err != nil {
+			return proof, errors.Wrapf(err, "failed to prove field %x of account %x", field.key, id)
+		}
+	}
+
+	return proof, nil
+}
+
+// Verify checks the proof against root, being the Merkle root of a block,
+// returning the balances of the account it proves.
+func (p AccountProof) Verify(root MerkleNodeID) (AccountBalances, error) {
+	var balances AccountBalances
+
+	for _, field := range p.fields(&balances) {
+		value, exists, err := field.proof.Verify(root, accountFieldKey(p.ID, field.key))
+		if err != nil {
+			return balances, errors.Wrapf(err, "invalid proof of field %x of account %x", field.key, p.ID)
+		}
+
+		if !exists {
+			continue
+		}
+
+		if len(value) != 8 {
+			return balances, errors.Errorf("field %x of account %x is %d bytes long, not 8", field.key, p.ID, len(value))
+		}
+
+		*field.value = binary.LittleEndian.Uint64(value)
+	}
+
+	return balances, nil
+}
+
+// accountFieldKey returns the key of the field with prefix key of the account
+// with ID id in the ledger state tree.
+func accountFieldKey(id AccountID, key []byte) []byte {
+	k := make([]byte, 0, len(keyAccounts)+len(key)+len(id))
+	k = append(k, keyAccounts[:]...)
+	k = append(k, key...)
+	k = append(k, id[:]...)
+
+	return k
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountProof(t *testing.T) {
+	tree := avl.New(store.NewInmem())
+
+	alice, bob := AccountID{1}, AccountID{2}
+
+	WriteAccountBalance(tree, alice, 100)
+	WriteAccountStake(tree, alice, 20)
+	WriteAccountContractGasBalance(tree, alice, 3)
+	WriteAccountBalance(tree, bob, 50)
+	WriteAccountReward(tree, bob, 7)
+
+	root := tree.Checksum()
+
+	proof, err := ProveAccount(tree, alice)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	balances, err := proof.Verify(root)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountBalances{Balance: 100, Stake: 20, GasBalance: 3}, balances)
+
+	// An account absent from the tree is proven to have nothing.
+	proof, err = ProveAccount(tree, AccountID{3})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	balances, err = proof.Verify(root)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountBalances{}, balances)
+
+	// The proof of an account does not prove another account.
+	proof, err = ProveAccount(tree, bob)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	proof.ID = alice
+
+	_, err = proof.Verify(root)
+	assert.Equal(t, avl.ErrInvalidProof, errors.Cause(err))
+
+	// Nor does it prove anything against another root.
+	proof.ID = bob
+
+	WriteAccountBalance(tree, bob, 51)
+
+	_, err = proof.Verify(tree.Checksum())
+	assert.Equal(t, avl.ErrInvalidProof, errors.Cause(err))
+
+	balances, err = proof.Verify(root)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountBalances{Balance: 50, Reward: 7}, balances)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+)
+
+// ErrUnknownAdmissionHook is returned when enabling an admission hook which
+// was never registered.
+var ErrUnknownAdmissionHook = errors.New("unknown admission hook")
+
+// AdmissionHook screens transactions before our node admits them into its
+// mempool, be it through gossip or the API, and hence before it gossips
+// them, such as to screen senders against a sanctions list or to apply
+// custom spam heuristics.
+//
+// Hooks are compiled into the node, registered under a name through
+// RegisterAdmissionHook, typically from the init function of a package
+// imported for its side effects, and enabled per node through
+// WithAdmissionHooks. Hooks only ever affect which transactions our node
+// admits and relays, and never consensus.
+type AdmissionHook interface {
+	// Admit returns an error should tx be rejected. Otherwise, it may return
+	// annotations of tx, which are logged upon admitting it.
+	Admit(snapshot *avl.Tree, tx Transaction) (map[string]string, error)
+}
+
+// AdmissionHookFunc adapts a function into an AdmissionHook.
+type AdmissionHookFunc func(snapshot *avl.Tree, tx Transaction) (map[string]string, error)
+
+// Admit calls f.
+func (f AdmissionHookFunc) Admit(snapshot *avl.Tree, tx Transaction) (map[string]string, error) {
+	return f(snapshot, tx)
+}
+
+var (
+	admissionHooksLock sync.RWMutex
+	admissionHooks     = make(map[string]AdmissionHook)
+)
+
+// RegisterAdmissionHook registers hook under name. It panics should a hook
+// already be registered under name.
+func RegisterAdmissionHook(name string, hook AdmissionHook) {
+	admissionHooksLock.Lock()
+	defer admissionHooksLock.Unlock()
+
+	if _, dup := admissionHooks[name]; dup {
+		panic(fmt.Sprintf("wavelet: admission hook %q registered twice", name))
+	}
+
+	admissionHooks[name] = hook
+}
+
+// LookupAdmissionHook returns the admission hook registered under name.
+func LookupAdmissionHook(name string) (AdmissionHook, error) {
+	admissionHooksLock.RLock()
+	defer admissionHooksLock.RUnlock()
+
+	hook, ok := admissionHooks[name]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownAdmissionHook, "%q", name)
+	}
+
+	return hook, nil
+}
+
+// AdmissionHooks returns the names of all registered admission hooks in
+// lexicographical order.
+func AdmissionHooks() []string {
+	admissionHooksLock.RLock()
+	defer admissionHooksLock.RUnlock()
+
+	names := make([]string, 0, len(admissionHooks))
+	for name := range admissionHooks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// namedAdmissionHook is an admission hook enabled on our node.
+type namedAdmissionHook struct {
+	name string
+	hook AdmissionHook
+}
+
+// AdmitTransaction decides whether our node admits tx into its mempool. It
+// checks the stamp of tx, and has every enabled admission hook screen tx in
+// the order they were enabled in, the first rejection being final.
+// Annotations of tx are logged should it be admitted.
+func (l *Ledger) AdmitTransaction(tx Transaction) error {
+	if err := l.CheckStamp(tx); err != nil {
+		return err
+	}
+
+	if len(l.admissionHooks) == 0 {
+		return nil
+	}
+
+	snapshot := l.Snapshot()
+
+	var annotations map[string]interface{}
+
+	for _, h := range l.admissionHooks {
+		notes, err := h.hook.Admit(snapshot, tx)
+		if err != nil {
+			return errors.Wrapf(err, "rejected by admission hook %q", h.name)
+		}
+
+		for k, v := range notes {
+			if annotations == nil {
+				annotations = make(map[string]interface{})
+			}
+
+			annotations[h.name+"."+k] = v
+		}
+	}
+
+	if len(annotations) > 0 {
+		logger := log.TX("annotated")
+		logger.Info().Hex("tx_id", tx.ID[:]).Fields(annotations).Msg("Admission hooks annotated transaction.")
+	}
+
+	return nil
+}
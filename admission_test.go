@@ -0,0 +1,70 @@
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmissionHooks(t *testing.T) {
+	sanctioned, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	alice, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	var annotated []TransactionID
+
+	RegisterAdmissionHook("test.sanctions", AdmissionHookFunc(func(_ *avl.Tree, tx Transaction) (map[string]string, error) {
+		if tx.Sender == sanctioned.PublicKey() {
+			return nil, errors.New("sender is sanctioned")
+		}
+
+		return nil, nil
+	}))
+
+	RegisterAdmissionHook("test.annotate", AdmissionHookFunc(func(_ *avl.Tree, tx Transaction) (map[string]string, error) {
+		annotated = append(annotated, tx.ID)
+		return map[string]string{"screened": "true"}, nil
+	}))
+
+	assert.Panics(t, func() {
+		RegisterAdmissionHook("test.annotate", AdmissionHookFunc(nil))
+	})
+
+	assert.Subset(t, AdmissionHooks(), []string{"test.annotate", "test.sanctions"})
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	_, err = NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys), WithoutGC(), WithAdmissionHooks("test.missing"))
+	assert.Equal(t, ErrUnknownAdmissionHook, errors.Cause(err))
+
+	// Without hooks enabled, every transaction is admitted.
+	ledger, err := NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys), WithoutGC())
+	assert.NoError(t, err)
+
+	rejected := NewTransaction(sanctioned, 1, 0, sys.TagTransfer, nil)
+	assert.NoError(t, ledger.AdmitTransaction(rejected))
+
+	ledger, err = NewLedger(
+		store.NewInmem(), skademlia.NewClient(":0", keys), WithoutGC(),
+		WithAdmissionHooks("test.sanctions", "test.annotate"),
+	)
+	assert.NoError(t, err)
+
+	// Hooks screen transactions in order, the first rejection being final.
+	assert.Error(t, ledger.AdmitTransaction(rejected))
+	assert.Empty(t, annotated)
+
+	admitted := NewTransaction(alice, 1, 0, sys.TagTransfer, nil)
+	assert.NoError(t, ledger.AdmitTransaction(admitted))
+	assert.Equal(t, []TransactionID{admitted.ID}, annotated)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+)
+
+var (
+	errAdminNoAuth        = errors.New("the admin API must be authenticated by a token, client certificates, or both")
+	errAdminClientCANoTLS = errors.New("client certificates of the admin API may only be verified over TLS")
+)
+
+// AdminConfig configures the admin API, which serves the routes privileged
+// to the operators of a node on a listener of its own, authenticated apart
+// from the public API.
+type AdminConfig struct {
+	// Addr is the address to listen at, such as 127.0.0.1:9001.
+	Addr string
+
+	// Token, if set, is the bearer token every request must carry.
+	Token string
+
+	// CertFile and KeyFile, if set, are the paths of the PEM-encoded
+	// certificate and private key to serve the admin API over TLS with.
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile, if set, is the path of a PEM-encoded bundle of the
+	// certificate authorities client certificates must be issued by, such
+	// that only clients presenting one are let through.
+	ClientCAFile string
+}
+
+// admin is the admin API of a gateway.
+type admin struct {
+	config    AdminConfig
+	tlsConfig *tls.Config
+	router    *fasthttprouter.Router
+}
+
+// EnableAdmin serves the routes privileged to the operators of the node, such
+// as /node/restart, /node/loglevels, /node/prune, /webhooks and /debug, on the
+// admin API configured by cfg rather than on the public API. It must be called
+// before the gateway is started, and the admin API served by StartAdmin.
+func (g *Gateway) EnableAdmin(cfg AdminConfig) error {
+	if cfg.Token == "" && cfg.ClientCAFile == "" {
+		return errAdminNoAuth
+	}
+
+	if cfg.ClientCAFile != "" && cfg.CertFile == "" {
+		return errAdminClientCANoTLS
+	}
+
+	a := &admin{config: cfg}
+
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to load the certificate of the admin API")
+		}
+
+		a.tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := ioutil.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the client certificate authorities of the admin API")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.Errorf("no certificates found in %s", cfg.ClientCAFile)
+		}
+
+		a.tlsConfig.ClientCAs = pool
+		a.tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	g.admin = a
+
+	return nil
+}
+
+// StartAdmin serves the admin API, should it have been enabled by
+// EnableAdmin. It must be called after StartHTTP or StartHTTPS.
+func (g *Gateway) StartAdmin() {
+	if g.admin == nil {
+		return
+	}
+
+	logger := log.Node()
+
+	ln, err := net.Listen("tcp", g.admin.config.Addr)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("Failed to listen at %s.", g.admin.config.Addr)
+	}
+
+	if g.admin.tlsConfig != nil {
+		ln = tls.NewListener(ln, g.admin.tlsConfig)
+	}
+
+	logger.Info().
+		Str("addr", ln.Addr().String()).
+		Bool("tls", g.admin.tlsConfig != nil).
+		Bool("mtls", g.admin.config.ClientCAFile != "").
+		Msg("Started admin API server.")
+
+	s := &fasthttp.Server{
+		Handler: g.admin.router.Handler,
+	}
+	g.servers = append(g.servers, s)
+
+	go func() {
+		if err := s.Serve(ln); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to start admin API server.")
+		}
+	}()
+}
+
+// auth only lets through requests carrying the token of the admin API, should
+// it have one. Client certificates are verified beforehand, as connections
+// are accepted.
+func (a *admin) auth(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if a.config.Token == "" || subtle.ConstantTimeCompare([]byte(oAuth2(ctx)), []byte(a.config.Token)) == 1 {
+			next(ctx)
+			return
+		}
+
+		ctx.Error(fasthttp.StatusMessage(fasthttp.StatusUnauthorized), fasthttp.StatusUnauthorized)
+		ctx.Response.Header.Set("WWW-Authenticate", "Bearer realm=Admin")
+	}
+}
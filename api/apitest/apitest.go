@@ -0,0 +1,483 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package apitest provides an in-memory fake of the HTTP and websocket API of
+// a node, such that applications talking to a node through wctl may be tested
+// hermetically.
+//
+// The fake serves a canned ledger state set with SetLedger and SetAccount.
+// Transactions sent to it are accepted as scripted by AcceptTransactions,
+// and settled with Apply and Reject. Websocket events are injected with Emit.
+package apitest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"github.com/valyala/fastjson"
+)
+
+// ErrUnknownTransaction is returned by Apply and Reject for transactions the
+// server was never sent.
+var ErrUnknownTransaction = errors.New("apitest: unknown transaction")
+
+const (
+	statusApplied  = "applied"
+	statusReceived = "received"
+)
+
+// Server is a fake node serving its API over HTTP on a local port.
+type Server struct {
+	srv *httptest.Server
+
+	// URL is the base URL of the server, such as http://127.0.0.1:1234.
+	URL string
+
+	mu sync.Mutex
+
+	ledger   wctl.LedgerStatusResponse
+	accounts map[[32]byte]wctl.Account
+
+	txs   map[[32]byte]*wctl.Transaction
+	order [][32]byte
+
+	accept   func(wctl.TxRequest) error
+	handlers map[string]http.HandlerFunc
+
+	upgrader    websocket.Upgrader
+	subscribers map[*subscriber]struct{}
+	wg          sync.WaitGroup
+}
+
+// NewServer starts a fake node at the height 0 of its ledger, accepting all
+// transactions sent to it. It should be closed once done with.
+func NewServer() *Server {
+	s := &Server{
+		accounts:    make(map[[32]byte]wctl.Account),
+		txs:         make(map[[32]byte]*wctl.Transaction),
+		handlers:    make(map[string]http.HandlerFunc),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+
+	s.ledger.SyncStatus = "Node is fully synced"
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+
+	return s
+}
+
+// Config returns the configuration of a wctl client talking to the server.
+// Its PrivateKey is left for the caller to set.
+func (s *Server) Config() wctl.Config {
+	host, port, _ := net.SplitHostPort(s.srv.Listener.Addr().String())
+	p, _ := strconv.ParseUint(port, 10, 16)
+
+	return wctl.Config{APIHost: host, APIPort: uint16(p)}
+}
+
+// Close disconnects all websockets, and shuts the server down.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for sub := range s.subscribers {
+		sub.close()
+	}
+	s.mu.Unlock()
+
+	s.srv.CloseClientConnections()
+	s.wg.Wait()
+	s.srv.Close()
+}
+
+// SetLedger sets the status of the ledger served at /ledger.
+func (s *Server) SetLedger(ledger wctl.LedgerStatusResponse) {
+	s.mu.Lock()
+	s.ledger = ledger
+	s.mu.Unlock()
+}
+
+// Ledger returns the status of the ledger served at /ledger.
+func (s *Server) Ledger() wctl.LedgerStatusResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ledger
+}
+
+// SetAccount sets the state of the account account.PublicKey. Accounts never
+// set are served as being empty.
+func (s *Server) SetAccount(account wctl.Account) {
+	s.mu.Lock()
+	s.accounts[account.PublicKey] = account
+	s.mu.Unlock()
+}
+
+// AcceptTransactions sets the function deciding whether transactions sent
+// to /tx/send are accepted. Transactions for which it returns an error are
+// responded to with 400 Bad Request and the error. Passing nil accepts all
+// transactions, as the server does by default.
+func (s *Server) AcceptTransactions(accept func(tx wctl.TxRequest) error) {
+	s.mu.Lock()
+	s.accept = accept
+	s.mu.Unlock()
+}
+
+// AddTransaction adds tx to the transactions served by the server, as if it
+// was sent to it.
+func (s *Server) AddTransaction(tx wctl.Transaction) {
+	s.mu.Lock()
+	s.addTransaction(tx)
+	s.mu.Unlock()
+}
+
+func (s *Server) addTransaction(tx wctl.Transaction) {
+	if _, exists := s.txs[tx.ID]; !exists {
+		s.order = append(s.order, tx.ID)
+	}
+
+	s.txs[tx.ID] = &tx
+}
+
+// Transactions returns the transactions the server was sent, in the order
+// they were received.
+func (s *Server) Transactions() []wctl.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txs := make([]wctl.Transaction, 0, len(s.order))
+	for _, id := range s.order {
+		txs = append(txs, *s.txs[id])
+	}
+
+	return txs
+}
+
+// Apply marks the transaction id as applied at the current height of the
+// ledger, and emits its events.TxApplied.
+func (s *Server) Apply(id [32]byte) error {
+	tx, err := s.settle(id)
+	if err != nil {
+		return err
+	}
+
+	return s.Emit(events.TxApplied{TxID: tx.ID, SenderID: tx.Sender, Tag: tx.Tag, Time: time.Now()})
+}
+
+// Reject marks the transaction id as rejected upon being applied for reason,
+// and emits its events.TxFailed. As with a node, the transaction is
+// nonetheless reported as applied by /tx, its rejection only being known of
+// through its event.
+func (s *Server) Reject(id [32]byte, reason string) error {
+	tx, err := s.settle(id)
+	if err != nil {
+		return err
+	}
+
+	return s.Emit(events.TxFailed{TxID: tx.ID, SenderID: tx.Sender, Tag: tx.Tag, Error: reason, Time: time.Now()})
+}
+
+// settle finalizes the transaction id at the current height of the ledger.
+func (s *Server) settle(id [32]byte) (wctl.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, exists := s.txs[id]
+	if !exists {
+		return wctl.Transaction{}, ErrUnknownTransaction
+	}
+
+	tx.Status = statusApplied
+	tx.Height = s.ledger.Block.Index
+
+	return *tx, nil
+}
+
+// Finalize advances the ledger by one block, and emits its events.Finalized.
+func (s *Server) Finalize() events.Finalized {
+	s.mu.Lock()
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], s.ledger.Block.Index+1)
+
+	s.ledger.Block.Index++
+	s.ledger.Block.ID = sha256.Sum256(append(s.ledger.Block.ID[:], buf[:]...))
+
+	ev := events.Finalized{BlockID: s.ledger.Block.ID, BlockHeight: s.ledger.Block.Index, Message: "Finalized block."}
+
+	s.mu.Unlock()
+
+	_ = s.Emit(ev)
+
+	return ev
+}
+
+// Handle serves the route path with h, taking precedence over the routes the
+// server serves by default. Routes the server does not serve respond with 404
+// Not Found.
+func (s *Server) Handle(path string, h http.HandlerFunc) {
+	s.mu.Lock()
+	s.handlers[path] = h
+	s.mu.Unlock()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) { // nolint:gocyclo
+	s.mu.Lock()
+	h := s.handlers[r.URL.Path]
+	s.mu.Unlock()
+
+	if h != nil {
+		h(w, r)
+		return
+	}
+
+	path := r.URL.Path
+
+	switch {
+	case path == wctl.RouteLedger && r.Method == http.MethodGet:
+		s.serveLedger(w)
+	case path == wctl.RouteTime && r.Method == http.MethodGet:
+		writeJSON(w, []byte(fmt.Sprintf(`{"time":%d}`, time.Now().UnixNano()/int64(time.Millisecond))))
+	case strings.HasPrefix(path, wctl.RouteAccount+"/") && r.Method == http.MethodGet:
+		s.serveAccount(w, strings.TrimPrefix(path, wctl.RouteAccount+"/"))
+	case path == wctl.RouteTxSend && r.Method == http.MethodPost:
+		s.serveSendTransaction(w, r)
+	case path == wctl.RouteTxList && r.Method == http.MethodGet:
+		s.serveListTransactions(w, r)
+	case strings.HasPrefix(path, wctl.RouteTxList+"/") && r.Method == http.MethodGet:
+		s.serveTransaction(w, strings.TrimPrefix(path, wctl.RouteTxList+"/"))
+	case strings.HasPrefix(path, "/poll/"):
+		s.serveWebsocket(w, r, strings.TrimPrefix(path, "/poll/"))
+	default:
+		writeError(w, http.StatusNotFound, errors.Errorf("no route %s %s", r.Method, path))
+	}
+}
+
+func (s *Server) serveLedger(w http.ResponseWriter) {
+	var arena fastjson.Arena
+
+	writeJSON(w, marshalLedger(&arena, s.Ledger()).MarshalTo(nil))
+}
+
+func (s *Server) serveAccount(w http.ResponseWriter, param string) {
+	var id [32]byte
+
+	if err := decodeID(id[:], param); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "account ID must be presented as valid hex"))
+		return
+	}
+
+	s.mu.Lock()
+	account, exists := s.accounts[id]
+	s.mu.Unlock()
+
+	if !exists {
+		account = wctl.Account{PublicKey: id}
+	}
+
+	var arena fastjson.Arena
+
+	writeJSON(w, marshalAccount(&arena, account).MarshalTo(nil))
+}
+
+func (s *Server) serveSendTransaction(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req wctl.TxRequest
+	if err := req.UnmarshalJSON(body); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "error parsing request body"))
+		return
+	}
+
+	s.mu.Lock()
+	accept := s.accept
+	s.mu.Unlock()
+
+	if accept != nil {
+		if err := accept(req); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "error adding your transaction to graph"))
+			return
+		}
+	}
+
+	id := req.ID()
+
+	s.mu.Lock()
+	if _, exists := s.txs[id]; !exists {
+		s.addTransaction(wctl.Transaction{
+			ID:        id,
+			Sender:    req.Sender,
+			Status:    statusReceived,
+			Nonce:     req.Nonce,
+			Height:    req.Block,
+			Tag:       req.Tag,
+			Payload:   req.Payload,
+			Scheme:    req.Scheme,
+			Version:   req.Version,
+			Stamp:     req.Stamp,
+			Tip:       req.Tip,
+			Signature: req.Signature,
+		})
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, []byte(`{"id":"`+hex.EncodeToString(id[:])+`"}`))
+}
+
+func (s *Server) serveListTransactions(w http.ResponseWriter, r *http.Request) {
+	var (
+		sender        [32]byte
+		offset, limit uint64
+		err           error
+	)
+
+	query := r.URL.Query()
+
+	if raw := query.Get("sender"); raw != "" {
+		if err := decodeID(sender[:], raw); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "sender ID must be presented as valid hex"))
+			return
+		}
+	}
+
+	for _, arg := range []struct {
+		key string
+		dst *uint64
+	}{{"offset", &offset}, {"limit", &limit}} {
+		if raw := query.Get(arg.key); raw != "" {
+			if *arg.dst, err = strconv.ParseUint(raw, 10, 64); err != nil {
+				writeError(w, http.StatusBadRequest, errors.Wrapf(err, "could not parse %s", arg.key))
+				return
+			}
+		}
+	}
+
+	txs := s.Transactions()
+
+	// Transactions are listed as a node lists them, by height and then by ID.
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].Height != txs[j].Height {
+			return txs[i].Height < txs[j].Height
+		}
+
+		return string(txs[i].ID[:]) < string(txs[j].ID[:])
+	})
+
+	var arena fastjson.Arena
+
+	list := arena.NewArray()
+	n := 0
+
+	for _, tx := range txs {
+		if sender != ([32]byte{}) && tx.Sender != sender {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+
+		if limit > 0 && uint64(n) == limit {
+			break
+		}
+
+		list.SetArrayItem(n, marshalTransaction(&arena, tx))
+		n++
+	}
+
+	writeJSON(w, list.MarshalTo(nil))
+}
+
+func (s *Server) serveTransaction(w http.ResponseWriter, param string) {
+	var id [32]byte
+
+	if err := decodeID(id[:], param); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "transaction ID must be presented as valid hex"))
+		return
+	}
+
+	s.mu.Lock()
+	tx, exists := s.txs[id]
+	if exists {
+		cpy := *tx
+		tx = &cpy
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		writeError(w, http.StatusNotFound, errors.Errorf("could not find transaction with ID %x", id))
+		return
+	}
+
+	var arena fastjson.Arena
+
+	writeJSON(w, marshalTransaction(&arena, *tx).MarshalTo(nil))
+}
+
+func decodeID(dst []byte, s string) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+
+	if len(buf) != len(dst) {
+		return errors.Errorf("ID must be %d bytes long", len(dst))
+	}
+
+	copy(dst, buf)
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// writeError responds with err as a node renders errors.
+func writeError(w http.ResponseWriter, status int, err error) {
+	var arena fastjson.Arena
+
+	o := arena.NewObject()
+	o.Set("status", arena.NewString(http.StatusText(status)))
+	o.Set("error", arena.NewString(err.Error()))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(o.MarshalTo(nil))
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package apitest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClient(t *testing.T, s *Server) *wctl.Client {
+	_, key, err := edwards25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	cfg := s.Config()
+	cfg.PrivateKey = key
+	cfg.TxPollInterval = 10 * time.Millisecond
+
+	c, err := wctl.NewClient(cfg)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return c
+}
+
+func TestServerState(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	ledger := s.Ledger()
+	ledger.Block.Index = 7
+	ledger.NumAccounts = 2
+	ledger.Features = []string{"fees"}
+	ledger.Snowball = wctl.SnowballParams{K: 10, Alpha: 0.8, Beta: 50}
+	s.SetLedger(ledger)
+
+	c := newClient(t, s)
+	defer c.Close()
+
+	assert.EqualValues(t, 7, c.Block.Load())
+
+	status, err := c.LedgerStatus()
+	if assert.NoError(t, err) {
+		assert.Equal(t, ledger.Block, status.Block)
+		assert.Equal(t, ledger.NumAccounts, status.NumAccounts)
+		assert.Equal(t, ledger.Features, status.Features)
+		assert.Equal(t, ledger.Snowball, status.Snowball)
+	}
+
+	s.SetAccount(wctl.Account{
+		PublicKey: [32]byte{1},
+		Balance:   100,
+		Stake:     10,
+		Recovery:  &wctl.Recovery{Guardians: [][32]byte{{2}}, Threshold: 1, Delay: 5},
+	})
+
+	account, err := c.GetAccount([32]byte{1})
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, 100, account.Balance)
+		assert.EqualValues(t, 10, account.Stake)
+		assert.Equal(t, &wctl.Recovery{Guardians: [][32]byte{{2}}, Threshold: 1, Delay: 5}, account.Recovery)
+	}
+
+	// Accounts never set are empty.
+	account, err = c.GetAccount([32]byte{3})
+	if assert.NoError(t, err) {
+		assert.Equal(t, [32]byte{3}, account.PublicKey)
+		assert.Zero(t, account.Balance)
+	}
+
+	_, err = c.GetTransaction([32]byte{4})
+	assert.True(t, errors.Is(err, wctl.ErrNotFound))
+}
+
+func TestServerTransactions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := newClient(t, s)
+	defer c.Close()
+
+	s.AcceptTransactions(func(tx wctl.TxRequest) error {
+		if tx.Tag != byte(sys.TagTransfer) {
+			return errors.New("only transfers are accepted")
+		}
+
+		return nil
+	})
+
+	_, err := c.SendTransaction(byte(sys.TagStake), []byte{1})
+	assert.Error(t, err)
+
+	res, err := c.SendTransaction(byte(sys.TagTransfer), []byte{2})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	txs := s.Transactions()
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, res.ID, txs[0].ID)
+		assert.EqualValues(t, c.PublicKey, txs[0].Sender)
+		assert.Equal(t, []byte{2}, txs[0].Payload)
+	}
+
+	tx, err := c.GetTransaction(res.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "received", tx.Status)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, s.Apply(res.ID))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err = c.WaitForTransaction(ctx, res.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "applied", tx.Status)
+	}
+
+	res, err = c.SendTransaction(byte(sys.TagTransfer), []byte{3})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, s.Reject(res.ID, "insufficient balance"))
+	}()
+
+	_, err = c.WaitForTransaction(ctx, res.ID)
+
+	var rejected *wctl.TxRejectedError
+	if assert.True(t, errors.As(err, &rejected)) {
+		assert.Equal(t, "insufficient balance", rejected.Reason)
+	}
+
+	assert.Equal(t, ErrUnknownTransaction, s.Apply([32]byte{1}))
+
+	list, err := c.ListTransactions("", "", 0, 1)
+	if assert.NoError(t, err) {
+		assert.Len(t, list, 1)
+	}
+}
+
+func TestServerEmit(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := newClient(t, s)
+	defer c.Close()
+
+	finalized := make(chan wctl.Finalized, 1)
+	c.OnFinalized = func(ev wctl.Finalized) {
+		finalized <- ev
+	}
+
+	balances := make(chan wctl.BalanceUpdate, 2)
+	c.OnBalanceUpdated = func(ev wctl.BalanceUpdate) {
+		balances <- ev
+	}
+
+	applied := make(chan wctl.TxApplied, 1)
+	c.OnTxApplied = func(ev wctl.TxApplied) {
+		applied <- ev
+	}
+
+	stopAccounts, err := c.PollAccountsFiltered([32]byte{1})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	defer stopAccounts()
+
+	stopTxs, err := c.PollTransactions()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	defer stopTxs()
+
+	ev := s.Finalize()
+
+	select {
+	case got := <-finalized:
+		assert.Equal(t, ev, got)
+		assert.EqualValues(t, 1, got.BlockHeight)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the finalized event")
+	}
+
+	assert.EqualValues(t, 1, s.Ledger().Block.Index)
+
+	// Events are filtered as a node filters them.
+	now := time.Now().UTC().Truncate(time.Second)
+
+	assert.NoError(t, s.Emit(events.BalanceUpdate{AccountID: [32]byte{2}, Balance: 1, Time: now}))
+	assert.NoError(t, s.Emit(events.BalanceUpdate{AccountID: [32]byte{1}, Balance: 2, Time: now}))
+
+	select {
+	case got := <-balances:
+		assert.Equal(t, wctl.BalanceUpdate{AccountID: [32]byte{1}, Balance: 2, Time: now}, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the balance event")
+	}
+
+	assert.NoError(t, s.Emit(wctl.TxApplied{TxID: [32]byte{3}, Tag: byte(sys.TagTransfer), Time: now}))
+
+	select {
+	case got := <-applied:
+		assert.Equal(t, [32]byte{3}, got.TxID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the applied event")
+	}
+
+	assert.Error(t, s.Emit("not an event"))
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package apitest
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/valyala/fastjson"
+)
+
+// The functions below render the state of the server as a node renders it,
+// for wctl to parse it as it parses the responses of a node.
+
+func setHex(arena *fastjson.Arena, o *fastjson.Value, key string, b []byte) {
+	o.Set(key, arena.NewString(hex.EncodeToString(b)))
+}
+
+func setUint64(arena *fastjson.Arena, o *fastjson.Value, key string, v uint64) {
+	o.Set(key, arena.NewNumberString(strconv.FormatUint(v, 10)))
+}
+
+func newIDs(arena *fastjson.Arena, ids [][32]byte) *fastjson.Value {
+	list := arena.NewArray()
+
+	for i := range ids {
+		list.SetArrayItem(i, arena.NewString(hex.EncodeToString(ids[i][:])))
+	}
+
+	return list
+}
+
+func newBlock(arena *fastjson.Arena, merkleRoot [16]byte, height uint64, id [32]byte, txs uint64) *fastjson.Value {
+	o := arena.NewObject()
+
+	setHex(arena, o, "merkle_root", merkleRoot[:])
+	setUint64(arena, o, "height", height)
+	setHex(arena, o, "id", id[:])
+	setUint64(arena, o, "transactions", txs)
+
+	return o
+}
+
+func marshalLedger(arena *fastjson.Arena, l wctl.LedgerStatusResponse) *fastjson.Value {
+	o := arena.NewObject()
+
+	setHex(arena, o, "public_key", l.PublicKey[:])
+	o.Set("address", arena.NewString(l.HostAddress))
+	o.Set("num_accounts", arena.NewNumberInt(l.NumAccounts))
+	o.Set("sync_status", arena.NewString(l.SyncStatus))
+	o.Set("block", newBlock(arena, l.Block.MerkleRoot, l.Block.Index, l.Block.ID, l.Block.Txs))
+
+	setUint64(arena, o, "num_tx", l.NumTx)
+	setUint64(arena, o, "num_missing_tx", l.NumMissingTx)
+	setUint64(arena, o, "num_tx_in_store", l.NumTxInStore)
+	setUint64(arena, o, "num_accounts_in_store", l.AccountsLen)
+	o.Set("stamp_difficulty", arena.NewNumberInt(l.StampDifficulty))
+
+	if l.Archival {
+		o.Set("archival", arena.NewTrue())
+	} else {
+		o.Set("archival", arena.NewFalse())
+	}
+
+	features := arena.NewArray()
+	for i, f := range l.Features {
+		features.SetArrayItem(i, arena.NewString(f))
+	}
+
+	o.Set("features", features)
+
+	snowball := arena.NewObject()
+	snowball.Set("k", arena.NewNumberInt(l.Snowball.K))
+	snowball.Set("alpha", arena.NewNumberFloat64(l.Snowball.Alpha))
+	snowball.Set("beta", arena.NewNumberInt(l.Snowball.Beta))
+	o.Set("snowball", snowball)
+
+	pruning := arena.NewObject()
+	if l.Pruning.Enabled {
+		pruning.Set("enabled", arena.NewTrue())
+	} else {
+		pruning.Set("enabled", arena.NewFalse())
+	}
+
+	setUint64(arena, pruning, "retained_blocks", l.Pruning.RetainedBlocks)
+	setUint64(arena, pruning, "retained_from", l.Pruning.RetainedFrom)
+	setUint64(arena, pruning, "num_pruned_diffs", l.Pruning.NumPrunedDiffs)
+	setUint64(arena, pruning, "num_compactions", l.Pruning.NumCompactions)
+
+	if l.Pruning.CompactInterval > 0 {
+		pruning.Set("compact_interval", arena.NewString(l.Pruning.CompactInterval.String()))
+	}
+
+	if !l.Pruning.LastCompaction.IsZero() {
+		pruning.Set("last_compaction_at", arena.NewString(l.Pruning.LastCompaction.Format(time.RFC3339)))
+	}
+
+	pruning.Set("last_compaction_error", arena.NewString(l.Pruning.LastCompactionError))
+	o.Set("pruning", pruning)
+
+	if p := l.Preferred; p != nil {
+		o.Set("preferred", newBlock(arena, p.MerkleRoot, p.Index, p.ID, p.Txs))
+	} else {
+		o.Set("preferred", arena.NewNull())
+	}
+
+	o.Set("preferred_votes", arena.NewNumberInt(l.PreferredVotes))
+
+	peers := arena.NewArray()
+	for i, p := range l.Peers {
+		peer := arena.NewObject()
+		peer.Set("address", arena.NewString(p.Address))
+		setHex(arena, peer, "public_key", p.PublicKey[:])
+
+		peers.SetArrayItem(i, peer)
+	}
+
+	o.Set("peers", peers)
+
+	return o
+}
+
+func marshalAccount(arena *fastjson.Arena, a wctl.Account) *fastjson.Value { // nolint:gocyclo
+	o := arena.NewObject()
+
+	setHex(arena, o, "public_key", a.PublicKey[:])
+	setUint64(arena, o, "balance", a.Balance)
+	setUint64(arena, o, "gas_balance", a.GasBalance)
+	setUint64(arena, o, "stake", a.Stake)
+	setUint64(arena, o, "reward", a.Reward)
+
+	if a.IsContract {
+		o.Set("is_contract", arena.NewTrue())
+		setUint64(arena, o, "num_mem_pages", a.NumPages)
+	} else {
+		o.Set("is_contract", arena.NewFalse())
+	}
+
+	if a.Sponsor != ([32]byte{}) {
+		setHex(arena, o, "sponsor", a.Sponsor[:])
+		setUint64(arena, o, "fee_allowance", a.FeeAllowance)
+	}
+
+	if r := a.Recovery; r != nil {
+		recovery := arena.NewObject()
+		recovery.Set("guardians", newIDs(arena, r.Guardians))
+		recovery.Set("threshold", arena.NewNumberInt(int(r.Threshold)))
+		setUint64(arena, recovery, "delay", r.Delay)
+
+		o.Set("recovery", recovery)
+	}
+
+	if r := a.PendingRecovery; r != nil {
+		pending := arena.NewObject()
+		setHex(arena, pending, "recipient", r.Recipient[:])
+		setUint64(arena, pending, "block", r.Block)
+		pending.Set("approvals", newIDs(arena, r.Approvals))
+
+		o.Set("pending_recovery", pending)
+	}
+
+	if m := a.Multisig; m != nil {
+		multisig := arena.NewObject()
+		multisig.Set("participants", newIDs(arena, m.Participants))
+		multisig.Set("threshold", arena.NewNumberInt(int(m.Threshold)))
+
+		proposals := arena.NewArray()
+		for i, p := range m.Proposals {
+			proposal := arena.NewObject()
+			setHex(arena, proposal, "id", p.ID[:])
+			setHex(arena, proposal, "proposer", p.Proposer[:])
+			setHex(arena, proposal, "recipient", p.Recipient[:])
+			setUint64(arena, proposal, "amount", p.Amount)
+			setUint64(arena, proposal, "block", p.Block)
+			proposal.Set("approvals", newIDs(arena, p.Approvals))
+
+			proposals.SetArrayItem(i, proposal)
+		}
+
+		multisig.Set("proposals", proposals)
+		o.Set("multisig", multisig)
+	}
+
+	return o
+}
+
+func marshalTransaction(arena *fastjson.Arena, tx wctl.Transaction) *fastjson.Value {
+	o := arena.NewObject()
+
+	setHex(arena, o, "id", tx.ID[:])
+	setHex(arena, o, "sender", tx.Sender[:])
+	o.Set("status", arena.NewString(tx.Status))
+	setUint64(arena, o, "nonce", tx.Nonce)
+	setUint64(arena, o, "height", tx.Height)
+	o.Set("tag", arena.NewNumberInt(int(tx.Tag)))
+	o.Set("payload", arena.NewString(base64.StdEncoding.EncodeToString(tx.Payload)))
+
+	if tx.Scheme != 0 {
+		o.Set("scheme", arena.NewNumberInt(int(tx.Scheme)))
+	}
+
+	if tx.Version != 0 {
+		o.Set("version", arena.NewNumberInt(int(tx.Version)))
+	}
+
+	if tx.Stamp != 0 {
+		setUint64(arena, o, "stamp", tx.Stamp)
+	}
+
+	if tx.Tip != 0 {
+		setUint64(arena, o, "tip", tx.Tip)
+	}
+
+	setHex(arena, o, "signature", tx.Signature[:])
+
+	return o
+}
@@ -0,0 +1,271 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+	"github.com/valyala/fastjson"
+)
+
+// subscriberBuffer is the number of events queued up for a websocket, past
+// which further events are dropped.
+const subscriberBuffer = 1024
+
+// filterKeys maps the query parameters by which the websockets of each module
+// may be filtered to the keys of the events they match, as a node does.
+var filterKeys = map[string]map[string]string{
+	log.ModuleNetwork:   {},
+	log.ModuleConsensus: {},
+	log.ModuleAccounts:  {"id": "account_id"},
+	log.ModuleContract:  {"id": "contract_id"},
+	log.ModuleTX:        {"id": "tx_id", "sender": "sender_id", "tag": "tag", "event": events.KeyEvent},
+	log.ModuleMetrics:   {},
+}
+
+// batched are the modules whose events are sent as arrays of events.
+var batched = map[string]bool{
+	log.ModuleContract: true,
+	log.ModuleTX:       true,
+}
+
+// subscriber is a websocket polling the events of a module.
+type subscriber struct {
+	mod     string
+	filters map[string]string
+
+	queue chan []byte
+
+	once sync.Once
+	done chan struct{}
+}
+
+func (sub *subscriber) close() {
+	sub.once.Do(func() { close(sub.done) })
+}
+
+// matches reports whether the event o passes the filters of sub.
+func (sub *subscriber) matches(o *fastjson.Value) bool {
+	for key, want := range sub.filters {
+		v := o.Get(key)
+		if v == nil {
+			return false
+		}
+
+		got := v.String()
+		if v.Type() == fastjson.TypeString {
+			got = string(v.GetStringBytes())
+		}
+
+		if got != want {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) serveWebsocket(w http.ResponseWriter, r *http.Request, mod string) {
+	keys, ok := filterKeys[mod]
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.Errorf("no websocket for module %q", mod))
+		return
+	}
+
+	sub := &subscriber{
+		mod:     mod,
+		filters: make(map[string]string),
+		queue:   make(chan []byte, subscriberBuffer),
+		done:    make(chan struct{}),
+	}
+
+	query := r.URL.Query()
+	for param, key := range keys {
+		if value := query.Get(param); value != "" {
+			sub.filters[key] = value
+		}
+	}
+
+	// The subscriber is registered before the handshake completes, for
+	// events emitted once the client is connected to never be missed.
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+
+		s.wg.Done()
+	}()
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	// Messages from the client are only read for its closing the websocket
+	// to be noticed.
+	go func() {
+		defer sub.close()
+
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-sub.done:
+			return
+		case msg := <-sub.queue:
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Emit sends ev to the websockets polling its module whose filters it
+// matches. ev must be one of the events of package events, such as
+// events.BalanceUpdate, or equivalently of its aliases in package wctl.
+func (s *Server) Emit(ev interface{}) error {
+	mod, o, err := encodeEvent(ev)
+	if err != nil {
+		return err
+	}
+
+	var msg []byte
+
+	if batched[mod] {
+		var arena fastjson.Arena
+
+		list := arena.NewArray()
+		list.SetArrayItem(0, o)
+
+		msg = list.MarshalTo(nil)
+	} else {
+		msg = o.MarshalTo(nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		if sub.mod != mod || !sub.matches(o) {
+			continue
+		}
+
+		select {
+		case sub.queue <- msg:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// encodeEvent returns the module of ev, and ev as a node logs it.
+func encodeEvent(ev interface{}) (string, *fastjson.Value, error) { // nolint:gocyclo
+	var mod, name string
+
+	switch ev.(type) {
+	case events.BalanceUpdate:
+		mod, name = log.ModuleAccounts, events.EventBalanceUpdated
+	case events.GasBalanceUpdate:
+		mod, name = log.ModuleAccounts, events.EventGasBalanceUpdated
+	case events.NumPagesUpdated:
+		mod, name = log.ModuleAccounts, events.EventNumPagesUpdated
+	case events.StakeUpdated:
+		mod, name = log.ModuleAccounts, events.EventStakeUpdated
+	case events.RewardUpdated:
+		mod, name = log.ModuleAccounts, events.EventRewardUpdated
+	case events.PeerJoin:
+		mod, name = log.ModuleNetwork, events.EventPeerJoined
+	case events.PeerLeave:
+		mod, name = log.ModuleNetwork, events.EventPeerLeft
+	case events.PeerBan:
+		mod, name = log.ModuleNetwork, events.EventPeerBanned
+	case events.Proposal:
+		mod, name = log.ModuleConsensus, events.EventProposal
+	case events.Finalized:
+		mod, name = log.ModuleConsensus, events.EventFinalized
+	case events.ValidatorJoin:
+		mod, name = log.ModuleConsensus, events.EventValidatorJoined
+	case events.ValidatorLeave:
+		mod, name = log.ModuleConsensus, events.EventValidatorLeft
+	case events.Round:
+		mod, name = log.ModuleConsensus, events.EventRound
+	case events.ContractGas:
+		mod, name = log.ModuleContract, events.EventContractGas
+	case events.ContractLog:
+		mod, name = log.ModuleContract, events.EventContractLog
+	case events.TxApplied:
+		mod, name = log.ModuleTX, events.EventTxApplied
+	case events.TxGossipError:
+		mod, name = log.ModuleTX, events.EventTxGossip
+	case events.TxFailed:
+		mod, name = log.ModuleTX, events.EventTxRejected
+	case events.TxConflict:
+		mod, name = log.ModuleTX, events.EventTxConflict
+	case events.Metrics:
+		mod = log.ModuleMetrics
+	default:
+		return "", nil, fmt.Errorf("apitest: unknown event type %T", ev)
+	}
+
+	buf, err := ev.(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return "", nil, err
+	}
+
+	o, err := fastjson.ParseBytes(buf)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var arena fastjson.Arena
+
+	o.Set(events.KeyMod, arena.NewString(mod))
+
+	if name != "" {
+		o.Set(events.KeyEvent, arena.NewString(name))
+	}
+
+	// Gossip errors are logged by a node at the error level.
+	if name == events.EventTxGossip {
+		o.Set("level", arena.NewString("error"))
+	}
+
+	return mod, o, nil
+}
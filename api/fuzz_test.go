@@ -0,0 +1,29 @@
+// +build unit
+
+package api
+
+import (
+	"testing"
+
+	"github.com/valyala/fastjson"
+)
+
+func FuzzSendTransactionRequestBind(f *testing.F) {
+	f.Add([]byte(`{
+		"sender": "3132333435363738393031323334353637383930313233343536373839303132",
+		"nonce": 1,
+		"block": 2,
+		"tag": 1,
+		"payload": "7061796c6f6164",
+		"signature": "3132333435363738393031323334353637383930313233343536373839303132` +
+		`3132333435363738393031323334353637383930313233343536373839303132"
+	}`))
+	f.Add([]byte(`{"sender": "", "nonce": 1, "block": 2, "tag": 4, "payload": "", "scheme": 1, "signature": ""}`))
+	f.Add([]byte(`{}`))
+
+	var parser fastjson.Parser
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		_ = new(sendTransactionRequest).bind(&parser, body)
+	})
+}
@@ -0,0 +1,1030 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/internal/graphql"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fastjson"
+)
+
+// The GraphQL API serves the following schema. Integers past the range of
+// GraphQL's Int, such as balances, are responded as JSON numbers all the same.
+//
+//	type Query {
+//	  transaction(id: String!): Transaction
+//	  transactions(sender: String, tag: Int, fromRound: Int, toRound: Int, offset: Int, limit: Int): [Transaction!]!
+//	  account(id: String!, round: Int): Account!
+//	  contract(id: String!): Contract
+//	  round(index: Int): Round
+//	}
+//
+//	type Subscription {
+//	  transactionApplied(sender: String, tag: Int): Transaction!
+//	  accountUpdated(id: String): Account!
+//	  contractLog(id: String): ContractLog!
+//	  roundFinalized: Round!
+//	}
+//
+//	type Transaction {
+//	  id: String!, sender: Account!, nonce: Int!, height: Int!, tag: Int!,
+//	  payload: String!, signature: String!, status: String!,
+//	  round: Round, contract: Contract
+//	}
+//
+//	type Account {
+//	  id: String!, balance: Int!, gasBalance: Int!, stake: Int!, reward: Int!,
+//	  isContract: Boolean!, numMemPages: Int!, contract: Contract,
+//	  transactions(tag: Int, offset: Int, limit: Int): [Transaction!]!
+//	}
+//
+//	type Contract {
+//	  id: String!, numMemPages: Int!, gasBalance: Int!, account: Account!,
+//	  transaction: Transaction, logs(offset: Int, limit: Int): [ContractLog!]!
+//	}
+//
+//	type ContractLog {
+//	  index: Int!, round: Int!, transaction: Transaction, topic: String!, data: String!
+//	}
+//
+//	type Round {
+//	  index: Int!, id: String!, merkleRoot: String!, numTransactions: Int!,
+//	  transactions(offset: Int, limit: Int): [Transaction!]!
+//	}
+//
+// IDs are hex-encoded, and accounts and contracts may be looked up by the
+// names registered to them. Payloads are base64-encoded.
+
+const (
+	// graphqlMaxDepth is the maximum depth the fields selected by an
+	// operation may be nested, as every level may resolve many objects.
+	graphqlMaxDepth = 8
+
+	// graphqlMaxMessageSize is the maximum size of the messages read from
+	// websockets serving subscriptions, which carry whole documents.
+	graphqlMaxMessageSize = 64 * 1024
+
+	// graphqlMaxSubscriptions is the maximum number of operations running at
+	// once over a websocket.
+	graphqlMaxSubscriptions = 32
+
+	// graphqlProtocol is the subprotocol of websockets serving subscriptions,
+	// being that of the subscriptions-transport-ws library.
+	graphqlProtocol = "graphql-ws"
+)
+
+// Types of the messages of the graphql-ws subprotocol.
+const (
+	gqlConnectionInit      = "connection_init"
+	gqlConnectionAck       = "connection_ack"
+	gqlConnectionError     = "connection_error"
+	gqlConnectionTerminate = "connection_terminate"
+	gqlStart               = "start"
+	gqlStop                = "stop"
+	gqlData                = "data"
+	gqlError               = "error"
+	gqlComplete            = "complete"
+)
+
+var graphqlUpgrader = websocket.FastHTTPUpgrader{ // nolint:gochecknoglobals
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	Subprotocols:    []string{graphqlProtocol},
+	CheckOrigin: func(ctx *fasthttp.RequestCtx) bool {
+		return true
+	},
+}
+
+// graphql executes a GraphQL query posted as JSON, or given by the query
+// parameters query, operationName and variables. Subscriptions are served
+// over a websocket should the request be upgraded.
+func (g *Gateway) graphql(ctx *fasthttp.RequestCtx) {
+	if bytes.EqualFold(ctx.Request.Header.Peek("Upgrade"), []byte("websocket")) {
+		if err := g.serveGraphQLWebsocket(ctx); err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "failed to init websocket session")))
+		}
+
+		return
+	}
+
+	var (
+		req graphql.Request
+		err error
+	)
+
+	if ctx.IsPost() {
+		req, err = graphql.DecodeRequest(bytes.NewReader(ctx.PostBody()))
+	} else {
+		req, err = graphqlRequestFromArgs(ctx.QueryArgs())
+	}
+
+	if err != nil {
+		g.renderGraphQLError(ctx, err)
+		return
+	}
+
+	query, err := graphql.Prepare(req, graphqlMaxDepth)
+	if err != nil {
+		g.renderGraphQLError(ctx, err)
+		return
+	}
+
+	if query.Type() == graphql.OperationSubscription {
+		g.renderGraphQLError(ctx, errors.New("subscriptions must be made over a websocket"))
+		return
+	}
+
+	g.render(ctx, &graphqlResponse{query: query, root: &gqlQuery{g: g}})
+}
+
+func graphqlRequestFromArgs(args *fasthttp.Args) (graphql.Request, error) {
+	req := graphql.Request{
+		Query:         string(args.Peek("query")),
+		OperationName: string(args.Peek("operationName")),
+	}
+
+	if raw := args.Peek("variables"); len(raw) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+
+		if err := dec.Decode(&req.Variables); err != nil {
+			return req, errors.Wrap(err, "could not parse variables")
+		}
+	}
+
+	return req, nil
+}
+
+// renderGraphQLError responds to a GraphQL request which could not be
+// executed at all.
+func (g *Gateway) renderGraphQLError(ctx *fasthttp.RequestCtx, err error) {
+	arena := g.arenaPool.Get()
+	b := graphql.ErrorResponse(arena, err).MarshalTo(nil)
+	arena.Reset()
+	g.arenaPool.Put(arena)
+
+	ctx.SetContentType("application/json")
+	ctx.Response.SetStatusCode(http.StatusBadRequest)
+	ctx.Response.SetBody(b)
+}
+
+// gqlQuery is the root of queries, resolving objects against the latest state
+// of the ledger.
+type gqlQuery struct {
+	g *Gateway
+}
+
+func (q *gqlQuery) TypeName() string {
+	return "Query"
+}
+
+func (q *gqlQuery) Field(name string, args graphql.Args) (interface{}, error) { // nolint:gocyclo
+	g := q.g
+	snapshot := g.ledger.Snapshot()
+
+	switch name {
+	case "transaction":
+		id, err := gqlID(g, snapshot, args, "id", "transaction")
+		if err != nil {
+			return nil, err
+		}
+
+		if tx := g.ledger.Transactions().Find(id); tx != nil {
+			return &gqlTransaction{g: g, tx: tx}, nil
+		}
+
+		return nil, nil
+	case "transactions":
+		var filter transactionFilter
+
+		if _, given := args["sender"]; given {
+			sender, err := gqlID(g, snapshot, args, "sender", "sender")
+			if err != nil {
+				return nil, err
+			}
+
+			filter.sender = sender
+		}
+
+		offset, limit, err := gqlPage(args)
+		if err != nil {
+			return nil, err
+		}
+
+		uintArgs := []struct {
+			key string
+			dst *uint64
+		}{
+			{"tag", &filter.tag},
+			{"fromRound", &filter.fromHeight},
+			{"toRound", &filter.toHeight},
+		}
+
+		for _, arg := range uintArgs {
+			if *arg.dst, _, err = args.Uint64(arg.key); err != nil {
+				return nil, err
+			}
+		}
+
+		return g.gqlTransactions(g.findTransactions(filter, offset, limit)), nil
+	case "account":
+		index, given, err := args.Uint64("round")
+		if err != nil {
+			return nil, err
+		}
+
+		// Archival nodes may be queried for the account as of a past block.
+		if given {
+			if snapshot, _, err = g.ledger.SnapshotAt(index); err != nil {
+				return nil, err
+			}
+		}
+
+		id, err := gqlID(g, snapshot, args, "id", "account")
+		if err != nil {
+			return nil, err
+		}
+
+		return &gqlAccount{g: g, acc: g.readAccount(snapshot, id), snapshot: snapshot}, nil
+	case "contract":
+		id, err := gqlID(g, snapshot, args, "id", "contract")
+		if err != nil {
+			return nil, err
+		}
+
+		return gqlContractOf(g, snapshot, id), nil
+	case "round":
+		index, given, err := args.Uint64("index")
+		if err != nil {
+			return nil, err
+		}
+
+		if !given {
+			return &gqlRound{g: g, block: g.ledger.Blocks().Latest()}, nil
+		}
+
+		if block := g.findBlock(index); block != nil {
+			return &gqlRound{g: g, block: block}, nil
+		}
+
+		return nil, nil
+	}
+
+	return nil, graphql.ErrNoSuchField
+}
+
+// gqlID parses the ID given as the argument name, resolving names registered
+// through the name service.
+func gqlID(g *Gateway, snapshot *avl.Tree, args graphql.Args, name, kind string) ([32]byte, error) {
+	param, given, err := args.String(name)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	if !given {
+		return [32]byte{}, errors.Errorf("argument %q must be given", name)
+	}
+
+	id, errRes := g.resolveID(snapshot, param, kind)
+	if errRes != nil {
+		return id, errRes.Err
+	}
+
+	return id, nil
+}
+
+// gqlPage returns the offset and limit arguments paging through a list, the
+// limit defaulting to and being capped at maxPaginationLimit.
+func gqlPage(args graphql.Args) (uint64, uint64, error) {
+	offset, _, err := args.Uint64("offset")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	limit, _, err := args.Uint64("limit")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if limit == 0 || limit > maxPaginationLimit {
+		limit = maxPaginationLimit
+	}
+
+	return offset, limit, nil
+}
+
+// findBlock returns the block with index index, looking through the archive
+// of blocks should our node be an archival node. It returns nil should the
+// block not be kept.
+func (g *Gateway) findBlock(index uint64) *wavelet.Block {
+	if block, err := g.ledger.Blocks().GetByIndex(index); err == nil {
+		return block
+	}
+
+	if g.ledger.Archival() {
+		if _, block, err := g.ledger.SnapshotAt(index); err == nil {
+			return block
+		}
+	}
+
+	return nil
+}
+
+func (g *Gateway) gqlTransactions(list transactionList) []graphql.Object {
+	objects := make([]graphql.Object, 0, len(list))
+
+	for _, item := range list {
+		objects = append(objects, &gqlTransaction{g: g, tx: item.tx})
+	}
+
+	return objects
+}
+
+type gqlTransaction struct {
+	g  *Gateway
+	tx *wavelet.Transaction
+}
+
+func (t *gqlTransaction) TypeName() string {
+	return "Transaction"
+}
+
+func (t *gqlTransaction) Field(name string, args graphql.Args) (interface{}, error) {
+	g, tx := t.g, t.tx
+
+	switch name {
+	case "id":
+		return hex.EncodeToString(tx.ID[:]), nil
+	case "sender":
+		snapshot := g.ledger.Snapshot()
+		return &gqlAccount{g: g, acc: g.readAccount(snapshot, tx.Sender), snapshot: snapshot}, nil
+	case "nonce":
+		return tx.Nonce, nil
+	case "height":
+		return tx.Block, nil
+	case "tag":
+		return int(tx.Tag), nil
+	case "payload":
+		return base64.StdEncoding.EncodeToString(tx.Payload), nil
+	case "signature":
+		return hex.EncodeToString(tx.Signature[:]), nil
+	case "status":
+		if tx.Block <= g.ledger.Blocks().Latest().Index {
+			return statusApplied, nil
+		}
+
+		return statusReceived, nil
+	case "round":
+		if block := g.findBlock(tx.Block); block != nil {
+			return &gqlRound{g: g, block: block}, nil
+		}
+
+		return nil, nil
+	case "contract":
+		if tx.Tag != sys.TagContract {
+			return nil, nil
+		}
+
+		return gqlContractOf(g, g.ledger.Snapshot(), tx.ID), nil
+	}
+
+	return nil, graphql.ErrNoSuchField
+}
+
+type gqlAccount struct {
+	g        *Gateway
+	acc      *account
+	snapshot *avl.Tree
+}
+
+func (a *gqlAccount) TypeName() string {
+	return "Account"
+}
+
+func (a *gqlAccount) Field(name string, args graphql.Args) (interface{}, error) {
+	acc := a.acc
+
+	switch name {
+	case "id":
+		return hex.EncodeToString(acc.id[:]), nil
+	case "balance":
+		return acc.balance, nil
+	case "gasBalance":
+		return acc.gasBalance, nil
+	case "stake":
+		return acc.stake, nil
+	case "reward":
+		return acc.reward, nil
+	case "isContract":
+		return acc.isContract, nil
+	case "numMemPages":
+		return acc.numPages, nil
+	case "contract":
+		if !acc.isContract {
+			return nil, nil
+		}
+
+		return gqlContractOf(a.g, a.snapshot, acc.id), nil
+	case "transactions":
+		offset, limit, err := gqlPage(args)
+		if err != nil {
+			return nil, err
+		}
+
+		tag, _, err := args.Uint64("tag")
+		if err != nil {
+			return nil, err
+		}
+
+		return a.g.gqlTransactions(a.g.findTransactions(transactionFilter{sender: acc.id, tag: tag}, offset, limit)), nil
+	}
+
+	return nil, graphql.ErrNoSuchField
+}
+
+type gqlContract struct {
+	g        *Gateway
+	id       wavelet.TransactionID
+	snapshot *avl.Tree
+}
+
+// gqlContractOf returns the contract with ID id, or nil should there be no
+// such contract.
+func gqlContractOf(g *Gateway, snapshot *avl.Tree, id wavelet.TransactionID) interface{} {
+	if _, exists := wavelet.ReadAccountContractCode(snapshot, id); !exists {
+		return nil
+	}
+
+	return &gqlContract{g: g, id: id, snapshot: snapshot}
+}
+
+func (c *gqlContract) TypeName() string {
+	return "Contract"
+}
+
+func (c *gqlContract) Field(name string, args graphql.Args) (interface{}, error) {
+	g := c.g
+
+	switch name {
+	case "id":
+		return hex.EncodeToString(c.id[:]), nil
+	case "numMemPages":
+		numPages, _ := wavelet.ReadAccountContractNumPages(c.snapshot, c.id)
+		return numPages, nil
+	case "gasBalance":
+		gasBalance, _ := wavelet.ReadAccountContractGasBalance(c.snapshot, c.id)
+		return gasBalance, nil
+	case "account":
+		return &gqlAccount{g: g, acc: g.readAccount(c.snapshot, c.id), snapshot: c.snapshot}, nil
+	case "transaction":
+		if tx := g.ledger.Transactions().Find(c.id); tx != nil {
+			return &gqlTransaction{g: g, tx: tx}, nil
+		}
+
+		return nil, nil
+	case "logs":
+		offset, limit, err := gqlPage(args)
+		if err != nil {
+			return nil, err
+		}
+
+		logs, _, err := g.ledger.ContractLogs(c.id, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]graphql.Object, 0, len(logs))
+
+		for _, l := range logs {
+			objects = append(objects, &gqlContractLog{g: g, log: l})
+		}
+
+		return objects, nil
+	}
+
+	return nil, graphql.ErrNoSuchField
+}
+
+type gqlContractLog struct {
+	g   *Gateway
+	log wavelet.ContractLog
+}
+
+func (l *gqlContractLog) TypeName() string {
+	return "ContractLog"
+}
+
+func (l *gqlContractLog) Field(name string, args graphql.Args) (interface{}, error) {
+	switch name {
+	case "index":
+		return l.log.Index, nil
+	case "round":
+		return l.log.Block, nil
+	case "transaction":
+		if tx := l.g.ledger.Transactions().Find(l.log.TxID); tx != nil {
+			return &gqlTransaction{g: l.g, tx: tx}, nil
+		}
+
+		return nil, nil
+	case "topic":
+		return hex.EncodeToString(l.log.Topic), nil
+	case "data":
+		return hex.EncodeToString(l.log.Data), nil
+	}
+
+	return nil, graphql.ErrNoSuchField
+}
+
+type gqlRound struct {
+	g     *Gateway
+	block *wavelet.Block
+}
+
+func (r *gqlRound) TypeName() string {
+	return "Round"
+}
+
+func (r *gqlRound) Field(name string, args graphql.Args) (interface{}, error) {
+	block := r.block
+
+	switch name {
+	case "index":
+		return block.Index, nil
+	case "id":
+		return hex.EncodeToString(block.ID[:]), nil
+	case "merkleRoot":
+		return hex.EncodeToString(block.Merkle[:]), nil
+	case "numTransactions":
+		return len(block.Transactions), nil
+	case "transactions":
+		offset, limit, err := gqlPage(args)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := block.Transactions
+
+		if offset >= uint64(len(ids)) {
+			ids = nil
+		} else {
+			ids = ids[offset:]
+		}
+
+		if uint64(len(ids)) > limit {
+			ids = ids[:limit]
+		}
+
+		// Transactions which were since pruned are left out.
+		objects := make([]graphql.Object, 0, len(ids))
+
+		for _, id := range ids {
+			if tx := r.g.ledger.Transactions().Find(id); tx != nil {
+				objects = append(objects, &gqlTransaction{g: r.g, tx: tx})
+			}
+		}
+
+		return objects, nil
+	}
+
+	return nil, graphql.ErrNoSuchField
+}
+
+// gqlSubscription is a kind of events subscriptions may be made to.
+type gqlSubscription struct {
+	// mod is the module of the sink the events are read from.
+	mod string
+
+	// event is the name of the events subscribed to, should only some of the
+	// events of the module be.
+	event string
+
+	// filters maps the arguments of the subscription to the keys of the
+	// events they filter.
+	filters map[string]string
+
+	// resolve resolves the object an event is about, returning nil should it
+	// no longer be found.
+	resolve func(g *Gateway, v *fastjson.Value) graphql.Object
+}
+
+var gqlSubscriptions = map[string]gqlSubscription{ // nolint:gochecknoglobals
+	"transactionApplied": {
+		mod:     log.ModuleTX,
+		event:   events.EventTxApplied,
+		filters: map[string]string{"sender": "sender_id", "tag": "tag"},
+		resolve: func(g *Gateway, v *fastjson.Value) graphql.Object {
+			var ev events.TxApplied
+
+			if err := ev.UnmarshalValue(v); err != nil {
+				return nil
+			}
+
+			if tx := g.ledger.Transactions().Find(ev.TxID); tx != nil {
+				return &gqlTransaction{g: g, tx: tx}
+			}
+
+			return nil
+		},
+	},
+	// Accounts are pushed on every event updating any of their balances.
+	"accountUpdated": {
+		mod:     log.ModuleAccounts,
+		filters: map[string]string{"id": "account_id"},
+		resolve: func(g *Gateway, v *fastjson.Value) graphql.Object {
+			var id wavelet.AccountID
+
+			raw := v.GetStringBytes("account_id")
+			if n, err := hex.Decode(id[:], raw); err != nil || n != len(id) {
+				return nil
+			}
+
+			snapshot := g.ledger.Snapshot()
+
+			return &gqlAccount{g: g, acc: g.readAccount(snapshot, id), snapshot: snapshot}
+		},
+	},
+	"contractLog": {
+		mod:     log.ModuleContract,
+		event:   events.EventContractLog,
+		filters: map[string]string{"id": "contract_id"},
+		resolve: func(g *Gateway, v *fastjson.Value) graphql.Object {
+			var ev events.ContractLog
+
+			if err := ev.UnmarshalValue(v); err != nil {
+				return nil
+			}
+
+			return &gqlContractLog{g: g, log: wavelet.ContractLog{
+				Contract: ev.ContractID,
+				Block:    ev.Block,
+				TxID:     ev.TxID,
+				Index:    ev.Index,
+				Topic:    ev.Topic,
+				Data:     ev.Data,
+			}}
+		},
+	},
+	"roundFinalized": {
+		mod:   log.ModuleConsensus,
+		event: events.EventFinalized,
+		resolve: func(g *Gateway, v *fastjson.Value) graphql.Object {
+			var ev events.Finalized
+
+			if err := ev.UnmarshalValue(v); err != nil {
+				return nil
+			}
+
+			if block := g.findBlock(ev.BlockHeight); block != nil {
+				return &gqlRound{g: g, block: block}
+			}
+
+			return nil
+		},
+	},
+}
+
+// gqlEvent is the root of an execution of a subscription, resolving the
+// field subscribed to as the object an event is about.
+type gqlEvent struct {
+	field  string
+	object graphql.Object
+}
+
+func (e *gqlEvent) TypeName() string {
+	return "Subscription"
+}
+
+func (e *gqlEvent) Field(name string, args graphql.Args) (interface{}, error) {
+	if name != e.field {
+		return nil, graphql.ErrNoSuchField
+	}
+
+	return e.object, nil
+}
+
+// gqlSession serves the operations started over a websocket through the
+// graphql-ws subprotocol.
+type gqlSession struct {
+	g    *Gateway
+	conn *websocket.Conn
+
+	out chan []byte
+
+	// done is closed once the websocket is no longer read from, and closed
+	// once it is no longer written to.
+	done, closed chan struct{}
+
+	// running are the clients of the sinks the subscriptions running are
+	// read from, by the IDs of their operations.
+	running map[string]*gqlRunning
+}
+
+type gqlRunning struct {
+	sink   *sink
+	client *client
+}
+
+func (g *Gateway) serveGraphQLWebsocket(ctx *fasthttp.RequestCtx) error {
+	return graphqlUpgrader.Upgrade(ctx, func(conn *websocket.Conn) {
+		s := &gqlSession{
+			g:       g,
+			conn:    conn,
+			out:     make(chan []byte, 256),
+			done:    make(chan struct{}),
+			closed:  make(chan struct{}),
+			running: make(map[string]*gqlRunning),
+		}
+
+		go s.writeWorker()
+		s.readWorker()
+	})
+}
+
+func (s *gqlSession) readWorker() {
+	defer func() {
+		for id := range s.running {
+			s.stop(id)
+		}
+
+		close(s.done)
+		_ = s.conn.Close()
+	}()
+
+	s.conn.SetReadLimit(graphqlMaxMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+	s.conn.SetPongHandler(func(string) error {
+		_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	var msg struct {
+		ID      string          `json:"id"`
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	}
+
+	for {
+		_, buf, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		msg.ID, msg.Type, msg.Payload = "", "", nil
+
+		if err := json.Unmarshal(buf, &msg); err != nil {
+			s.send(gqlConnectionError, "", gqlErrorPayload(errors.Wrap(err, "could not parse message")))
+			continue
+		}
+
+		switch msg.Type {
+		case gqlConnectionInit:
+			s.send(gqlConnectionAck, "", nil)
+		case gqlStart:
+			if err := s.start(msg.ID, msg.Payload); err != nil {
+				s.send(gqlError, msg.ID, gqlErrorPayload(err))
+			}
+		case gqlStop:
+			if _, running := s.running[msg.ID]; running {
+				s.stop(msg.ID)
+				s.send(gqlComplete, msg.ID, nil)
+			}
+		case gqlConnectionTerminate:
+			return
+		default:
+			s.send(gqlConnectionError, "", gqlErrorPayload(errors.Errorf("unknown message type %q", msg.Type)))
+		}
+	}
+}
+
+func (s *gqlSession) writeWorker() {
+	defer close(s.closed)
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg := <-s.out:
+			_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := s.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				_ = s.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := s.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				_ = s.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// start executes the operation of the request carried by payload. Queries
+// are executed once, while subscriptions are executed for every event they
+// are subscribed to until they are stopped.
+func (s *gqlSession) start(id string, payload json.RawMessage) error {
+	if id == "" {
+		return errors.New("operations must be given an id")
+	}
+
+	if _, running := s.running[id]; running {
+		return errors.Errorf("operation %q is already running", id)
+	}
+
+	req, err := graphql.DecodeRequest(bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	query, err := graphql.Prepare(req, graphqlMaxDepth)
+	if err != nil {
+		return err
+	}
+
+	if query.Type() != graphql.OperationSubscription {
+		s.execute(id, query, &gqlQuery{g: s.g})
+		s.send(gqlComplete, id, nil)
+
+		return nil
+	}
+
+	if len(s.running) >= graphqlMaxSubscriptions {
+		return errors.Errorf("at most %d subscriptions may run at once", graphqlMaxSubscriptions)
+	}
+
+	field, args, err := query.RootField()
+	if err != nil {
+		return err
+	}
+
+	sub, exists := gqlSubscriptions[field]
+	if !exists {
+		return errors.Errorf("cannot subscribe to field %q", field)
+	}
+
+	filters := make(map[string]string)
+
+	if sub.event != "" {
+		filters[log.KeyEvent] = sub.event
+	}
+
+	for arg := range args {
+		key, ok := sub.filters[arg]
+		if !ok {
+			return errors.Errorf("subscriptions to %q may not be filtered by %q", field, arg)
+		}
+
+		value, err := gqlFilter(s.g, args, arg, key)
+		if err != nil {
+			return err
+		}
+
+		filters[key] = value
+	}
+
+	s.g.sinksLock.RLock()
+	sink, exists := s.g.sinks[sub.mod]
+	s.g.sinksLock.RUnlock()
+
+	if !exists {
+		return errors.Errorf("module %q does not stream events", sub.mod)
+	}
+
+	running := &gqlRunning{sink: sink, client: sink.subscribeFiltered(filters)}
+	s.running[id] = running
+
+	go func() {
+		var parser fastjson.Parser
+
+		for buf := range running.client.queue {
+			v, err := parser.ParseBytes(buf)
+			if err != nil {
+				continue
+			}
+
+			if object := sub.resolve(s.g, v); object != nil {
+				s.execute(id, query, &gqlEvent{field: field, object: object})
+			}
+		}
+	}()
+
+	return nil
+}
+
+// gqlFilter renders the argument name of a subscription as the value events
+// are filtered by under key.
+func gqlFilter(g *Gateway, args graphql.Args, name, key string) (string, error) {
+	if name == "tag" {
+		tag, _, err := args.Uint64(name)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatUint(tag, 10), nil
+	}
+
+	id, err := gqlID(g, g.ledger.Snapshot(), args, name, strings.TrimSuffix(key, "_id"))
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id[:]), nil
+}
+
+func (s *gqlSession) stop(id string) {
+	running := s.running[id]
+	delete(s.running, id)
+
+	running.sink.unsubscribe(running.client)
+}
+
+func (s *gqlSession) execute(id string, query *graphql.Query, root graphql.Object) {
+	arena := s.g.arenaPool.Get()
+	payload := query.Execute(arena, root)
+	s.sendValue(arena, gqlData, id, payload)
+	arena.Reset()
+	s.g.arenaPool.Put(arena)
+}
+
+func gqlErrorPayload(err error) func(arena *fastjson.Arena) *fastjson.Value {
+	return func(arena *fastjson.Arena) *fastjson.Value {
+		o := arena.NewObject()
+		o.Set("message", arena.NewString(err.Error()))
+
+		return o
+	}
+}
+
+// send queues a message of the type typ to be written to the websocket, its
+// payload being built by payload should it not be nil.
+func (s *gqlSession) send(typ, id string, payload func(arena *fastjson.Arena) *fastjson.Value) {
+	arena := s.g.arenaPool.Get()
+
+	var value *fastjson.Value
+	if payload != nil {
+		value = payload(arena)
+	}
+
+	s.sendValue(arena, typ, id, value)
+	arena.Reset()
+	s.g.arenaPool.Put(arena)
+}
+
+func (s *gqlSession) sendValue(arena *fastjson.Arena, typ, id string, payload *fastjson.Value) {
+	o := arena.NewObject()
+	o.Set("type", arena.NewString(typ))
+
+	if id != "" {
+		o.Set("id", arena.NewString(id))
+	}
+
+	if payload != nil {
+		o.Set("payload", payload)
+	}
+
+	select {
+	case s.out <- o.MarshalTo(nil):
+	case <-s.closed:
+	}
+}
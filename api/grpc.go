@@ -0,0 +1,264 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/perlin-network/wavelet"
+	apigrpc "github.com/perlin-network/wavelet/api/grpc"
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/valyala/fastjson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ apigrpc.WaveletServer = (*grpcServer)(nil)
+
+// grpcServer serves the API of a gateway over gRPC, sharing its handling of
+// requests with the HTTP API.
+type grpcServer struct {
+	g *Gateway
+}
+
+// StartGRPC serves the API over gRPC at port, alongside the HTTP API. It must
+// be called after StartHTTP or StartHTTPS.
+func (g *Gateway) StartGRPC(port int) {
+	logger := log.Node()
+
+	ln, err := net.Listen("tcp4", ":"+strconv.Itoa(port))
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("Failed to listen to port %d.", port)
+	}
+
+	logger.Info().Int("port", port).Msg("Started gRPC API server.")
+
+	g.grpcServer = grpc.NewServer()
+	apigrpc.RegisterWaveletServer(g.grpcServer, &grpcServer{g: g})
+
+	go func() {
+		if err := g.grpcServer.Serve(ln); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to start gRPC server.")
+		}
+	}()
+}
+
+func (s *grpcServer) SendTransaction(
+	ctx context.Context, req *ledgerpb.Transaction,
+) (*apigrpc.SendTransactionResponse, error) {
+	tx, err := wavelet.TransactionFromProto(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errRes := s.g.addTransaction(tx); errRes != nil {
+		return nil, grpcError(errRes)
+	}
+
+	return &apigrpc.SendTransactionResponse{Id: tx.ID[:]}, nil
+}
+
+func (s *grpcServer) GetAccount(ctx context.Context, req *apigrpc.GetAccountRequest) (*ledgerpb.Account, error) {
+	snapshot := s.g.ledger.Snapshot()
+
+	var id wavelet.AccountID
+
+	if len(req.Name) > 0 {
+		if err := wavelet.ValidateName(req.Name); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		resolved, errRes := s.g.resolveID(snapshot, req.Name, "account")
+		if errRes != nil {
+			return nil, grpcError(errRes)
+		}
+
+		id = resolved
+	} else {
+		if len(req.Id) != wavelet.SizeAccountID {
+			return nil, status.Errorf(codes.InvalidArgument, "account ID must be %d bytes long", wavelet.SizeAccountID)
+		}
+
+		copy(id[:], req.Id)
+	}
+
+	return s.g.readAccount(snapshot, id).proto(), nil
+}
+
+func (s *grpcServer) GetLedger(ctx context.Context, _ *empty.Empty) (*apigrpc.LedgerStatus, error) {
+	ledger := s.g.ledger
+
+	block := ledger.Blocks().Latest()
+	accountsLen := wavelet.ReadAccountsLen(ledger.Snapshot())
+
+	res := &apigrpc.LedgerStatus{
+		NumAccounts:     accountsLen,
+		PreferredVotes:  uint64(ledger.Finalizer().Progress()),
+		Block:           block.Proto(),
+		NumMissingTx:    uint64(ledger.Transactions().MissingLen()),
+		NumTx:           uint64(ledger.Transactions().PendingLen()),
+		NumTxInStore:    uint64(ledger.Transactions().Len()),
+		StampDifficulty: uint64(ledger.StampDifficulty()),
+	}
+
+	if preferred := ledger.Finalizer().Preferred(); preferred != nil {
+		res.Preferred = preferred.Value().(*wavelet.Block).Proto()
+	}
+
+	for _, f := range sys.ActiveFeatures(block.Index + 1) {
+		res.Features = append(res.Features, string(f))
+	}
+
+	if s.g.keys != nil {
+		publicKey := s.g.keys.PublicKey()
+		res.PublicKey = publicKey[:]
+	}
+
+	if s.g.client != nil {
+		res.Address = s.g.client.ID().Address()
+
+		for _, peer := range s.g.client.ClosestPeerIDs() {
+			publicKey := peer.PublicKey()
+			res.Peers = append(res.Peers, &apigrpc.Peer{PublicKey: publicKey[:], Address: peer.Address()})
+		}
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) StreamEvents(req *apigrpc.StreamEventsRequest, stream apigrpc.Wavelet_StreamEventsServer) error {
+	s.g.sinksLock.RLock()
+	sink, exists := s.g.sinks[req.Mod]
+	s.g.sinksLock.RUnlock()
+
+	if !exists {
+		return status.Errorf(codes.NotFound, "module %q does not stream events", req.Mod)
+	}
+
+	for key := range req.Filters {
+		if _, ok := sink.filters[key]; !ok {
+			return status.Errorf(codes.InvalidArgument, "events of module %q may not be filtered by %q", req.Mod, key)
+		}
+	}
+
+	client := sink.subscribe(func(queryKey string) string {
+		return req.Filters[queryKey]
+	})
+	defer sink.unsubscribe(client)
+
+	var parser fastjson.Parser
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case buf := <-client.queue:
+			v, err := parser.ParseBytes(buf)
+			if err != nil {
+				continue
+			}
+
+			pb, ok := eventToProto(v)
+			if !ok {
+				continue
+			}
+
+			if err := stream.Send(pb); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventToProto converts an event logged by the node to its protobuf
+// definition, should it have one.
+func eventToProto(v *fastjson.Value) (*ledgerpb.Event, bool) {
+	var ev events.Event
+
+	switch mod, name := string(v.GetStringBytes(events.KeyMod)), string(v.GetStringBytes(events.KeyEvent)); {
+	case mod == log.ModuleAccounts && name == events.EventBalanceUpdated:
+		ev = new(events.BalanceUpdate)
+	case mod == log.ModuleAccounts && name == events.EventGasBalanceUpdated:
+		ev = new(events.GasBalanceUpdate)
+	case mod == log.ModuleAccounts && name == events.EventNumPagesUpdated:
+		ev = new(events.NumPagesUpdated)
+	case mod == log.ModuleAccounts && name == events.EventStakeUpdated:
+		ev = new(events.StakeUpdated)
+	case mod == log.ModuleAccounts && name == events.EventRewardUpdated:
+		ev = new(events.RewardUpdated)
+	case mod == log.ModuleNetwork && name == events.EventPeerJoined:
+		ev = new(events.PeerJoin)
+	case mod == log.ModuleNetwork && name == events.EventPeerLeft:
+		ev = new(events.PeerLeave)
+	case mod == log.ModuleConsensus && name == events.EventProposal:
+		ev = new(events.Proposal)
+	case mod == log.ModuleConsensus && name == events.EventFinalized:
+		ev = new(events.Finalized)
+	case mod == log.ModuleContract && name == events.EventContractGas:
+		ev = new(events.ContractGas)
+	case mod == log.ModuleContract && name == events.EventContractLog:
+		ev = new(events.ContractLog)
+	case mod == log.ModuleTX && name == events.EventTxApplied:
+		ev = new(events.TxApplied)
+	case mod == log.ModuleTX && (name == events.EventTxRejected || name == events.EventTxFailed):
+		ev = new(events.TxFailed)
+	case mod == log.ModuleTX && name == events.EventTxGossip && string(v.GetStringBytes("level")) == "error":
+		ev = new(events.TxGossipError)
+	case mod == log.ModuleTX && name == events.EventTxConflict:
+		ev = new(events.TxConflict)
+	default:
+		return nil, false
+	}
+
+	if err := ev.UnmarshalValue(v); err != nil {
+		return nil, false
+	}
+
+	pb, err := events.ToProto(ev)
+	if err != nil {
+		return nil, false
+	}
+
+	return pb, true
+}
+
+// grpcError converts an error of the HTTP API to the status of a gRPC call.
+func grpcError(e *errResponse) error {
+	code := codes.Internal
+
+	switch e.HTTPStatusCode {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	}
+
+	return status.Error(code, e.Err.Error())
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/valyala/fasthttp"
+)
+
+// SetHealthThresholds sets the conditions under which the node is reported
+// healthy at /healthz, and ready at /readyz. It must be called before the
+// gateway is started.
+func (g *Gateway) SetHealthThresholds(t wavelet.HealthThresholds) {
+	g.health = t
+}
+
+// getHealthz reports on whether the node is live, being its database
+// reachable and consensus not stalled, responding with 503 should it not be.
+func (g *Gateway) getHealthz(ctx *fasthttp.RequestCtx) {
+	g.renderHealth(ctx, &healthResponse{health: g.ledger.CheckHealth(g.health)})
+}
+
+// getReadyz reports on whether the node is ready to serve clients, being it
+// live, connected to enough peers and in sync with them, responding with 503
+// should it not be.
+func (g *Gateway) getReadyz(ctx *fasthttp.RequestCtx) {
+	g.renderHealth(ctx, &healthResponse{health: g.ledger.CheckHealth(g.health), ready: true})
+}
+
+func (g *Gateway) renderHealth(ctx *fasthttp.RequestCtx, res *healthResponse) {
+	g.render(ctx, res)
+
+	if ctx.Response.StatusCode() == http.StatusOK && !res.ok() {
+		ctx.Response.SetStatusCode(http.StatusServiceUnavailable)
+	}
+}
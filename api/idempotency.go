@@ -0,0 +1,192 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// headerIdempotencyKey carries a key unique to a request, for retries of
+	// it to be responded to with the response to the first attempt.
+	headerIdempotencyKey = "Idempotency-Key"
+
+	// maxIdempotencyKeys is the maximum number of responses remembered, past
+	// which the oldest is forgotten.
+	maxIdempotencyKeys = 4096
+
+	// idempotencyTTL is how long responses are remembered for.
+	idempotencyTTL = 10 * time.Minute
+
+	// maxIdempotencyKeyLen bounds the length of keys remembered.
+	maxIdempotencyKeyLen = 128
+)
+
+// idempotentResponse is the response to the first request with some key,
+// done being closed once it was served.
+type idempotentResponse struct {
+	done chan struct{}
+
+	status      int
+	contentType []byte
+	body        []byte
+
+	served time.Time
+}
+
+// idempotencyCache remembers the responses to requests carrying an
+// Idempotency-Key header.
+type idempotencyCache struct {
+	sync.Mutex
+	responses map[string]*idempotentResponse
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{responses: make(map[string]*idempotentResponse)}
+}
+
+// idempotent responds to requests with the response to the first request to
+// the same path with the same Idempotency-Key, for clients to safely retry
+// requests such as for submitting transactions. Requests arriving while the
+// first is being served wait for it. Responses with a 5xx status are not
+// remembered, for retries to be served anew.
+func (c *idempotencyCache) idempotent(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		key := ctx.Request.Header.Peek(headerIdempotencyKey)
+		if len(key) == 0 || len(key) > maxIdempotencyKeyLen {
+			next(ctx)
+			return
+		}
+
+		id := string(ctx.Path()) + " " + string(key)
+
+		res, first := c.begin(id)
+		if !first {
+			<-res.done
+
+			if res.status != 0 {
+				ctx.SetStatusCode(res.status)
+				ctx.Response.Header.SetContentTypeBytes(res.contentType)
+				ctx.SetBody(res.body)
+
+				return
+			}
+
+			// The first request failed, so this one is served anew.
+			next(ctx)
+
+			return
+		}
+
+		// Requests which panic are not remembered.
+		panicked := true
+
+		defer func() {
+			if panicked {
+				c.finish(id, res, nil)
+			}
+		}()
+
+		next(ctx)
+
+		panicked = false
+
+		c.finish(id, res, &ctx.Response)
+	}
+}
+
+// begin returns the response to the request with id, and whether the
+// request is the first with it, in which case the caller must finish it.
+func (c *idempotencyCache) begin(id string) (*idempotentResponse, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if res, exists := c.responses[id]; exists {
+		select {
+		case <-res.done:
+			if res.status != 0 && time.Since(res.served) < idempotencyTTL {
+				return res, false
+			}
+		default:
+			return res, false
+		}
+	}
+
+	if len(c.responses) >= maxIdempotencyKeys {
+		c.evict()
+	}
+
+	res := &idempotentResponse{done: make(chan struct{})}
+	c.responses[id] = res
+
+	return res, true
+}
+
+// finish records the response to the first request with id, being nil
+// should it have failed.
+func (c *idempotencyCache) finish(id string, res *idempotentResponse, response *fasthttp.Response) {
+	c.Lock()
+	defer c.Unlock()
+
+	if response != nil && response.StatusCode() < http.StatusInternalServerError {
+		res.status = response.StatusCode()
+		res.contentType = append([]byte(nil), response.Header.ContentType()...)
+		res.body = append([]byte(nil), response.Body()...)
+		res.served = time.Now()
+	} else if c.responses[id] == res {
+		delete(c.responses, id)
+	}
+
+	close(res.done)
+}
+
+// evict forgets the oldest response served, and every expired one. The
+// cache must be locked.
+func (c *idempotencyCache) evict() {
+	var (
+		oldest   string
+		oldestAt time.Time
+	)
+
+	for id, res := range c.responses {
+		select {
+		case <-res.done:
+		default:
+			continue
+		}
+
+		if time.Since(res.served) >= idempotencyTTL {
+			delete(c.responses, id)
+			continue
+		}
+
+		if oldest == "" || res.served.Before(oldestAt) {
+			oldest, oldestAt = id, res.served
+		}
+	}
+
+	if len(c.responses) >= maxIdempotencyKeys && oldest != "" {
+		delete(c.responses, oldest)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestIdempotent(t *testing.T) {
+	var (
+		lock   sync.Mutex
+		served int
+		status = http.StatusOK
+	)
+
+	handler := newIdempotencyCache().idempotent(func(ctx *fasthttp.RequestCtx) {
+		lock.Lock()
+		served++
+		n := served
+		lock.Unlock()
+
+		// Give concurrent requests a chance to arrive while this is served.
+		time.Sleep(10 * time.Millisecond)
+
+		ctx.SetStatusCode(status)
+		ctx.SetContentType("application/json")
+		ctx.SetBodyString(strconv.Itoa(n))
+	})
+
+	serve := func(path, key string) (int, string) {
+		var ctx fasthttp.RequestCtx
+
+		ctx.Request.SetRequestURI(path)
+
+		if key != "" {
+			ctx.Request.Header.Set(headerIdempotencyKey, key)
+		}
+
+		handler(&ctx)
+
+		return ctx.Response.StatusCode(), string(ctx.Response.Body())
+	}
+
+	// Concurrent retries wait for, and are responded to as, the first.
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			code, body := serve("/tx/send", "a")
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, "1", body)
+		}()
+	}
+
+	wg.Wait()
+
+	_, body := serve("/tx/send", "a")
+	assert.Equal(t, "1", body)
+
+	// Keys are scoped by path, and requests without keys always served.
+	_, body = serve("/tx/relay", "a")
+	assert.Equal(t, "2", body)
+
+	_, body = serve("/tx/send", "")
+	assert.Equal(t, "3", body)
+
+	_, body = serve("/tx/send", "")
+	assert.Equal(t, "4", body)
+
+	// Client errors are remembered, but not server errors.
+	status = http.StatusBadRequest
+
+	code, body := serve("/tx/send", "b")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "5", body)
+
+	_, body = serve("/tx/send", "b")
+	assert.Equal(t, "5", body)
+
+	status = http.StatusServiceUnavailable
+
+	_, body = serve("/tx/send", "c")
+	assert.Equal(t, "6", body)
+
+	status = http.StatusOK
+
+	code, body = serve("/tx/send", "c")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "7", body)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+	"github.com/valyala/fasthttp"
+)
+
+// apiMetrics are the metrics the gateway records of the requests it serves.
+type apiMetrics struct {
+	requests metrics.Meter
+	errors   metrics.Meter
+	latency  metrics.Timer
+}
+
+func newAPIMetrics(registry metrics.Registry) *apiMetrics {
+	return &apiMetrics{
+		requests: metrics.GetOrRegisterMeter("api.requests", registry),
+		errors:   metrics.GetOrRegisterMeter("api.errors", registry),
+		latency:  metrics.GetOrRegisterTimer("api.latency", registry),
+	}
+}
+
+// EnableMetrics has the gateway record metrics of the requests it serves,
+// and serve all metrics of the node at /metrics in the Prometheus format. It
+// must be called before the gateway is started.
+func (g *Gateway) EnableMetrics() {
+	g.enableMetrics = true
+}
+
+// instrument records the rate of requests, the rate of requests failing
+// with a 5xx status code, and the latency of requests.
+func (m *apiMetrics) instrument(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		start := time.Now()
+
+		next(ctx)
+
+		m.latency.UpdateSince(start)
+		m.requests.Mark(1)
+
+		if ctx.Response.StatusCode() >= http.StatusInternalServerError {
+			m.errors.Mark(1)
+		}
+	}
+}
+
+func (g *Gateway) getMetrics(ctx *fasthttp.RequestCtx) {
+	var buf bytes.Buffer
+
+	if err := g.ledger.Metrics().WritePrometheus(&buf); err != nil {
+		g.renderError(ctx, ErrInternal(errors.Wrap(err, "failed to write metrics")))
+		return
+	}
+
+	ctx.SetContentType("text/plain; version=0.0.4")
+	ctx.SetStatusCode(http.StatusOK)
+	ctx.SetBody(buf.Bytes())
+}
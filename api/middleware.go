@@ -23,12 +23,16 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/canonical"
 	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/trace"
 	"github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
 	"net/http"
 	"os"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -77,21 +81,13 @@ func (g *Gateway) contractScope(next fasthttp.RequestHandler) fasthttp.RequestHa
 			return
 		}
 
-		slice, err := hex.DecodeString(param)
-		if err != nil {
-			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "contract ID must be presented as valid hex")))
+		contractID, errRes := g.resolveID(g.ledger.Snapshot(), param, "contract")
+		if errRes != nil {
+			g.renderError(ctx, errRes)
 			return
 		}
 
-		if len(slice) != wavelet.SizeTransactionID {
-			g.renderError(ctx, ErrBadRequest(errors.Errorf("contract ID must be %d bytes long", wavelet.SizeTransactionID)))
-			return
-		}
-
-		var contractID wavelet.TransactionID
-		copy(contractID[:], slice)
-
-		ctx.SetUserValue("contract_id", contractID)
+		ctx.SetUserValue("contract_id", wavelet.TransactionID(contractID))
 
 		next(ctx)
 	}
@@ -125,3 +121,76 @@ func (g *Gateway) auth(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 		ctx.Response.Header.Set("WWW-Authenticate", "Bearer realm=Restricted")
 	}
 }
+
+// signedJSON only lets through requests whose JSON body was signed, as the
+// canonical JSON of domain, by the public key carried in the headers of
+// package canonical. The public key is then available to next as the user
+// value "signer".
+func (g *Gateway) signedJSON(domain string) middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			signer, err := verifySignedJSON(&ctx.Request, domain)
+			if err != nil {
+				g.renderError(ctx, ErrUnauthorized(err))
+				return
+			}
+
+			ctx.SetUserValue("signer", signer)
+
+			next(ctx)
+		}
+	}
+}
+
+func verifySignedJSON(req *fasthttp.Request, domain string) (wavelet.AccountID, error) {
+	var signer wavelet.AccountID
+
+	publicKey, err := hex.DecodeString(string(req.Header.Peek(canonical.HeaderPublicKey)))
+	if err != nil || len(publicKey) != wavelet.SizeAccountID {
+		return signer, errors.Errorf("%s must be a hex-encoded %d-byte public key",
+			canonical.HeaderPublicKey, wavelet.SizeAccountID)
+	}
+
+	signature, err := hex.DecodeString(string(req.Header.Peek(canonical.HeaderSignature)))
+	if err != nil || len(signature) == 0 {
+		return signer, errors.Errorf("%s must be a hex-encoded signature", canonical.HeaderSignature)
+	}
+
+	// The signature scheme is optional, and defaults to Ed25519.
+	scheme := security.SchemeEd25519
+
+	if raw := req.Header.Peek(canonical.HeaderScheme); len(raw) > 0 {
+		s, err := strconv.ParseUint(string(raw), 10, 8)
+		if err != nil {
+			return signer, errors.Wrapf(err, "invalid %s", canonical.HeaderScheme)
+		}
+
+		scheme = security.Scheme(s)
+	}
+
+	if err := canonical.VerifyJSON(scheme, publicKey, domain, req.Body(), signature); err != nil {
+		return signer, err
+	}
+
+	copy(signer[:], publicKey)
+
+	return signer, nil
+}
+
+// startRequestSpan starts a span of the request served with ctx, being a child
+// of the trace context the client sent in the traceparent header, if any. It
+// returns nil should tracing be disabled.
+func startRequestSpan(ctx *fasthttp.RequestCtx, name string, attrs ...trace.Attribute) *trace.Span {
+	if !trace.Enabled() {
+		return nil
+	}
+
+	parent, _ := trace.ParseTraceparent(string(ctx.Request.Header.Peek(trace.HeaderTraceparent)))
+
+	attrs = append(attrs,
+		trace.String("http.method", string(ctx.Method())),
+		trace.String("http.target", string(ctx.Path())),
+	)
+
+	return trace.Start(name, trace.KindServer, parent, attrs...)
+}
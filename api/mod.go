@@ -29,6 +29,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -36,9 +37,15 @@ import (
 	"github.com/buaazp/fasthttprouter"
 	"github.com/perlin-network/noise/skademlia"
 	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/events"
 	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/security"
 	"github.com/perlin-network/wavelet/store"
 	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/trace"
 	"github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/pprofhandler"
@@ -63,13 +70,32 @@ type Gateway struct {
 	router  *fasthttprouter.Router
 	servers []*fasthttp.Server
 
+	grpcServer *grpc.Server
+
+	// admin is the admin API serving privileged routes, or nil should they
+	// be served by the public API.
+	admin *admin
+
 	sinks     map[string]*sink
 	sinksLock sync.RWMutex
 
 	enableTimeout bool
+	enableMetrics bool
+
+	// health are the conditions under which the node is reported healthy
+	// and ready.
+	health wavelet.HealthThresholds
+
+	// Metrics of the requests served, should metrics be enabled.
+	metrics *apiMetrics
 
 	rateLimiter *rateLimiter
 
+	relayers    *relayerBook
+	webhooks    *webhookBook
+	peers       *peerBook
+	idempotency *idempotencyCache
+
 	parserPool *fastjson.ParserPool
 	arenaPool  *fastjson.ArenaPool
 }
@@ -80,6 +106,11 @@ func New() *Gateway {
 		parserPool:  new(fastjson.ParserPool),
 		arenaPool:   new(fastjson.ArenaPool),
 		rateLimiter: newRateLimiter(1000),
+		relayers:    newRelayerBook(),
+		webhooks:    newWebhookBook(),
+		peers:       newPeerBook(),
+		idempotency: newIdempotencyCache(),
+		health:      wavelet.DefaultHealthThresholds(),
 	}
 }
 
@@ -89,11 +120,15 @@ func (g *Gateway) setup() {
 	sinkConsensus := g.registerWebsocketSink("ws://consensus/")
 	sinkAccounts := g.registerWebsocketSink("ws://accounts/?id=account_id")
 	sinkContracts := g.registerWebsocketSink("ws://contract/?id=contract_id")
-	sinkTransactions := g.registerWebsocketSink("ws://tx/?id=tx_id&sender=sender_id&tag=tag")
+	sinkTransactions := g.registerWebsocketSink("ws://tx/?id=tx_id&sender=sender_id&tag=tag&event=event")
 	sinkMetrics := g.registerWebsocketSink("ws://metrics/")
 
 	log.SetWriter(log.LoggerWebsocket, g)
 
+	if g.enableMetrics {
+		g.metrics = newAPIMetrics(g.ledger.Metrics().Registry())
+	}
+
 	// Setup HTTP router.
 
 	r := fasthttprouter.New()
@@ -111,29 +146,121 @@ func (g *Gateway) setup() {
 	r.GET("/poll/tx", g.applyMiddleware(g.poll(sinkTransactions), "/poll/tx"))
 	r.GET("/poll/metrics", g.applyMiddleware(g.poll(sinkMetrics), "/poll/metrics"))
 
-	// Debug endpoint.
-	r.GET("/debug/*p", g.applyMiddleware(pprofhandler.PprofHandler, "/debug/*p"))
+	// OpenAPI document endpoint.
+	r.GET("/swagger.json", g.applyMiddleware(g.getSwagger, "/swagger.json"))
+
+	// Metrics endpoint.
+	if g.metrics != nil {
+		r.GET("/metrics", g.applyMiddleware(g.getMetrics, "/metrics"))
+	}
+
+	// Health endpoints, which are not rate limited for probes to never be
+	// turned away.
+	r.GET("/healthz", g.applyMiddleware(g.getHealthz, ""))
+	r.GET("/readyz", g.applyMiddleware(g.getReadyz, ""))
 
 	// Ledger endpoint.
 	r.GET("/ledger", g.applyMiddleware(g.ledgerStatus, "/ledger"))
+	r.GET("/time", g.applyMiddleware(g.getTime, "/time"))
+	r.GET("/snapshot", g.applyMiddleware(g.getSnapshot, "/snapshot"))
+	r.GET("/ledger/gas", g.applyMiddleware(g.getGasSchedule, "/ledger/gas"))
 
 	// Account endpoints.
 	r.GET("/accounts/:id", g.applyMiddleware(g.getAccount, ""))
+	r.GET("/accounts/:id/proof", g.applyMiddleware(g.getAccountProof, "/accounts/:id/proof"))
+	r.GET("/accounts/:id/delegations", g.applyMiddleware(g.getAccountDelegations, "/accounts/:id/delegations"))
+	r.GET("/accounts/:id/slashings", g.applyMiddleware(g.getAccountSlashings, "/accounts/:id/slashings"))
 
 	// Contract endpoints.
 	r.GET("/contract/:id/page/:index", g.applyMiddleware(g.getContractPages, "/contract/:id/page/:index", g.contractScope))
 	r.GET("/contract/:id/page", g.applyMiddleware(g.getContractPages, "/contract/:id/page", g.contractScope))
 	r.GET("/contract/:id", g.applyMiddleware(g.getContractCode, "/contract/:id", g.contractScope))
+	r.GET("/contract/:id/upgrades", g.applyMiddleware(g.getContractUpgrades, "/contract/:id/upgrades", g.contractScope))
+	r.POST("/contract/:id/call", g.applyMiddleware(g.callContract, "/contract/:id/call", g.contractScope))
+	r.GET("/contract/:id/logs", g.applyMiddleware(g.getContractLogs, "/contract/:id/logs", g.contractScope))
+	r.GET("/contract/:id/storage/:key", g.applyMiddleware(
+		g.getContractStorage, "/contract/:id/storage/:key", g.contractScope,
+	))
+	r.GET("/contract/:id/storage", g.applyMiddleware(
+		g.scanContractStorage, "/contract/:id/storage", g.contractScope,
+	))
+	r.GET("/contract/:id/logs/poll", g.applyMiddleware(
+		g.pollContractLogs(sinkContracts), "/contract/:id/logs/poll", g.contractScope,
+	))
+
+	// Name service endpoints.
+	r.GET("/name/:name", g.applyMiddleware(g.getName, "/name/:name"))
+
+	// Randomness beacon endpoints.
+	r.GET("/block/:index/randomness", g.applyMiddleware(g.getRandomness, "/block/:index/randomness"))
 
 	// Transaction endpoints.
-	r.POST("/tx/send", g.applyMiddleware(g.sendTransaction, ""))
+	r.POST("/tx/send", g.applyMiddleware(g.sendTransaction, "", g.idempotency.idempotent))
+	r.POST("/tx/relay", g.applyMiddleware(
+		g.relayTransaction, "", g.signedJSON(canonical.DomainRelay), g.idempotency.idempotent,
+	))
+	r.POST("/tx/estimate", g.applyMiddleware(g.estimateFee, "/tx/estimate"))
 	r.GET("/tx/:id", g.applyMiddleware(g.getTransaction, ""))
+	r.GET("/tx/:id/data", g.applyMiddleware(g.getData, ""))
+	r.GET("/tx/:id/diff", g.applyMiddleware(g.getTransactionDiff, ""))
 	r.GET("/tx", g.applyMiddleware(g.listTransactions, "/tx"))
 
+	// Round endpoints.
+	r.GET("/rounds", g.applyMiddleware(g.listRounds, "/rounds"))
+	r.GET("/rounds/:index", g.applyMiddleware(g.getRound, "/rounds/:index"))
+	r.GET("/poll/rounds", g.applyMiddleware(g.pollRounds(sinkConsensus), "/poll/rounds"))
+
+	// Validator endpoints.
+	r.GET("/validators", g.applyMiddleware(g.listValidators, "/validators"))
+
+	// Mempool endpoints.
+	r.GET("/mempool", g.applyMiddleware(g.listMempool, "/mempool"))
+	r.GET("/mempool/count", g.applyMiddleware(g.countMempool, "/mempool/count"))
+	r.GET("/mempool/contains/:id", g.applyMiddleware(g.mempoolContains, "/mempool/contains/:id"))
+
+	// GraphQL endpoints, GET requests being upgraded to websockets serving
+	// subscriptions.
+	r.GET("/graphql", g.applyMiddleware(g.graphql, "/graphql"))
+	r.POST("/graphql", g.applyMiddleware(g.graphql, "/graphql"))
+
+	// Relayer endpoints.
+	r.GET("/relayer/:id", g.applyMiddleware(g.getRelayer, "/relayer/:id"))
+
+	r.GET("/node/peers", g.applyMiddleware(g.listPeerScores, "/node/peers"))
+
+	// Privileged endpoints are served by the admin API should it be enabled,
+	// and by the public API restricted to the secret of the node otherwise.
+	// The debug endpoint is only restricted on the admin API.
+	admin, auth := r, middleware(g.auth)
+
+	var debug []middleware
+
+	if g.admin != nil {
+		admin, auth = fasthttprouter.New(), g.admin.auth
+
+		debug = append(debug, auth)
+		g.admin.router = admin
+	}
+
+	// Debug endpoint.
+	admin.GET("/debug/*p", g.applyMiddleware(pprofhandler.PprofHandler, "/debug/*p", debug...))
+
 	// Connectivity endpoints
-	r.POST("/node/connect", g.applyMiddleware(g.connect, "/node/connect", g.auth))
-	r.POST("/node/disconnect", g.applyMiddleware(g.disconnect, "/node/disconnect", g.auth))
-	r.POST("/node/restart", g.applyMiddleware(g.restart, "/node/restart", g.auth))
+	admin.POST("/node/connect", g.applyMiddleware(g.connect, "/node/connect", auth))
+	admin.POST("/node/disconnect", g.applyMiddleware(g.disconnect, "/node/disconnect", auth))
+	admin.POST("/node/restart", g.applyMiddleware(g.restart, "/node/restart", auth))
+	admin.POST("/node/snowball", g.applyMiddleware(g.updateSnowball, "/node/snowball", auth))
+	admin.GET("/node/loglevels", g.applyMiddleware(g.getLogLevels, "/node/loglevels", auth))
+	admin.POST("/node/loglevels", g.applyMiddleware(g.updateLogLevels, "/node/loglevels", auth))
+	admin.POST("/node/peers/:id/ban", g.applyMiddleware(g.banPeer, "/node/peers/:id/ban", auth))
+	admin.DELETE("/node/peers/:id/ban", g.applyMiddleware(g.unbanPeer, "/node/peers/:id/ban", auth))
+	admin.POST("/node/prune", g.applyMiddleware(g.prune, "/node/prune", auth))
+
+	// Webhook endpoints.
+	admin.POST("/webhooks", g.applyMiddleware(g.registerWebhook, "/webhooks", auth))
+	admin.GET("/webhooks", g.applyMiddleware(g.listWebhooks, "/webhooks", auth))
+	admin.GET("/webhooks/:id", g.applyMiddleware(g.getWebhook, "/webhooks/:id", auth))
+	admin.DELETE("/webhooks/:id", g.applyMiddleware(g.deleteWebhook, "/webhooks/:id", auth))
 
 	g.router = r
 }
@@ -160,6 +287,10 @@ func (g *Gateway) applyMiddleware(
 		}
 	}
 
+	if g.metrics != nil {
+		list = append([]middleware{g.metrics.instrument}, list...)
+	}
+
 	if g.enableTimeout {
 		list = append(list, timeout(60*time.Second, "Request timeout!"))
 	}
@@ -185,9 +316,10 @@ func (g *Gateway) StartHTTP(
 
 	logger.Info().Int("port", port).Msg("Started HTTP API server.")
 
-	registerPeerCallbacks(c)
+	g.registerPeerCallbacks(c)
 
-	go g.start(ln, nil, c, l, k, kv)
+	g.init(c, l, k, kv)
+	go g.start(ln, nil)
 }
 
 // Only support tls-alpn-01.
@@ -236,20 +368,22 @@ func (g *Gateway) StartHTTPS(
 	logger.Info().Int("port", 443).Msg("Started HTTPS API server.")
 	logger.Info().Int("port", httpPort).Msg("Started HTTP API server.")
 
-	registerPeerCallbacks(c)
+	g.registerPeerCallbacks(c)
 
-	go g.start(tlsLn, ln, c, l, k, kv)
+	g.init(c, l, k, kv)
+	go g.start(tlsLn, ln)
 }
 
-func registerPeerCallbacks(c *skademlia.Client) {
+func (g *Gateway) registerPeerCallbacks(c *skademlia.Client) {
 	if c == nil {
 		return
 	}
 
 	c.OnPeerJoin(func(conn *grpc.ClientConn, id *skademlia.ID) {
 		publicKey := id.PublicKey()
+		g.peers.join(publicKey)
 
-		logger := log.Network("joined")
+		logger := log.Network(events.EventPeerJoined)
 		logger.Info().
 			Hex("public_key", publicKey[:]).
 			Str("address", id.Address()).
@@ -258,8 +392,9 @@ func registerPeerCallbacks(c *skademlia.Client) {
 
 	c.OnPeerLeave(func(conn *grpc.ClientConn, id *skademlia.ID) {
 		publicKey := id.PublicKey()
+		g.peers.leave(publicKey)
 
-		logger := log.Network("left")
+		logger := log.Network(events.EventPeerLeft)
 		logger.Info().
 			Hex("public_key", publicKey[:]).
 			Str("address", id.Address()).
@@ -267,13 +402,9 @@ func registerPeerCallbacks(c *skademlia.Client) {
 	})
 }
 
-func (g *Gateway) start(
-	ln net.Listener, ln2 net.Listener, c *skademlia.Client,
-	l *wavelet.Ledger, k *skademlia.Keypair, kv store.KV,
-) {
-	stop := g.rateLimiter.cleanup(10 * time.Minute)
-	defer stop()
-
+// init sets up the gateway to serve the ledger, before any of its servers
+// are started.
+func (g *Gateway) init(c *skademlia.Client, l *wavelet.Ledger, k *skademlia.Keypair, kv store.KV) {
 	g.client = c
 	g.ledger = l
 	g.kv = kv
@@ -281,6 +412,11 @@ func (g *Gateway) start(
 
 	g.enableTimeout = false
 	g.setup()
+}
+
+func (g *Gateway) start(ln net.Listener, ln2 net.Listener) {
+	stop := g.rateLimiter.cleanup(10 * time.Minute)
+	defer stop()
 
 	logger := log.Node()
 
@@ -313,48 +449,192 @@ func (g *Gateway) Shutdown() {
 	for _, s := range g.servers {
 		_ = s.Shutdown()
 	}
+
+	if g.grpcServer != nil {
+		g.grpcServer.Stop()
+	}
+
+	g.webhooks.removeAll()
 }
 
 func (g *Gateway) sendTransaction(ctx *fasthttp.RequestCtx) {
-	req := &sendTransactionRequest{}
+	span := startRequestSpan(ctx, "api.sendTransaction")
+	defer span.End()
+
+	tx, errRes := g.submitTransaction(ctx.PostBody(), span)
+	if errRes != nil {
+		g.renderError(ctx, errRes)
+		return
+	}
+
+	g.render(ctx, &sendTransactionResponse{ledger: g.ledger, tx: tx})
+}
+
+// estimateFee dry-runs a transaction, which need not be signed, against the
+// latest state of the ledger, and responds with what it would cost its sender.
+func (g *Gateway) estimateFee(ctx *fasthttp.RequestCtx) {
+	req := &estimateFeeRequest{}
 
 	parser := g.parserPool.Get()
 	defer g.parserPool.Put(parser)
 
-	err := req.bind(parser, ctx.PostBody())
+	if err := req.bind(parser, ctx.PostBody()); err != nil {
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	block := g.ledger.Blocks().Latest()
 
+	tx := wavelet.Transaction{
+		Sender:  req.sender,
+		Block:   block.Index,
+		Tag:     sys.Tag(req.Tag),
+		Payload: req.payload,
+	}
+
+	estimate, err := wavelet.EstimateFee(g.ledger.Snapshot(), block, tx)
 	if err != nil {
 		g.renderError(ctx, ErrBadRequest(err))
 		return
 	}
 
-	tx := wavelet.NewSignedTransaction(
-		req.sender, req.Nonce, req.Block,
-		sys.Tag(req.Tag), req.payload, req.signature,
-	)
+	g.render(ctx, &feeEstimateResponse{estimate: estimate})
+}
 
-	snapshot := g.ledger.Snapshot()
+// relayTransaction submits a transaction on behalf of its sender, the
+// relayer being whoever signed the request. Transactions are validated the
+// same way as through /tx/send, as they are signed by their sender; the
+// relayer is merely accounted for.
+func (g *Gateway) relayTransaction(ctx *fasthttp.RequestCtx) {
+	relayer, ok := ctx.UserValue("signer").(wavelet.AccountID)
+	if !ok {
+		g.renderError(ctx, ErrUnauthorized(errors.New("could not identify relayer")))
+		return
+	}
 
-	if err := wavelet.ValidateTransaction(snapshot, tx); err != nil {
-		g.renderError(ctx, ErrBadRequest(err))
+	span := startRequestSpan(ctx, "api.relayTransaction", trace.Hex("relayer", relayer[:]))
+	defer span.End()
+
+	tx, errRes := g.submitTransaction(ctx.PostBody(), span)
+	g.relayers.record(relayer, tx)
 
+	if errRes != nil {
+		g.renderError(ctx, errRes)
 		return
 	}
 
+	g.render(ctx, &sendTransactionResponse{ledger: g.ledger, tx: tx})
+}
+
+// submitTransaction validates the transaction carried by body, and adds it to
+// the ledger.
+func (g *Gateway) submitTransaction(body []byte, span *trace.Span) (*wavelet.Transaction, *errResponse) {
+	req := &sendTransactionRequest{}
+
+	parser := g.parserPool.Get()
+	defer g.parserPool.Put(parser)
+
+	if err := req.bind(parser, body); err != nil {
+		span.SetError(err)
+		return nil, ErrBadRequest(err)
+	}
+
+	tx := wavelet.NewSignedTippedTransaction(
+		req.Version, security.Scheme(req.Scheme), req.sender, req.Nonce, req.Block,
+		sys.Tag(req.Tag), req.payload, req.Stamp, req.Tip, req.signature,
+	)
+
+	span.SetAttributes(wavelet.TraceTx(&tx)...)
+
+	if errRes := g.addTransaction(tx); errRes != nil {
+		span.SetError(errRes.Err)
+		return nil, errRes
+	}
+
+	// Spans of later stages of the life of the transaction join the trace of
+	// the request.
+	trace.RememberTx(tx.ID, span.Context())
+
+	return &tx, nil
+}
+
+// addTransaction validates tx against the latest state of the ledger, and
+// adds it to the ledger should it be admitted.
+func (g *Gateway) addTransaction(tx wavelet.Transaction) *errResponse {
+	if err := wavelet.ValidateTransaction(g.ledger.Snapshot(), tx); err != nil {
+		return ErrBadRequest(err)
+	}
+
+	if err := g.ledger.AdmitTransaction(tx); err != nil {
+		return ErrBadRequest(err)
+	}
+
+	if err := g.ledger.Transactions().CheckReplacement(tx); err != nil {
+		return ErrBadRequest(err)
+	}
+
 	g.ledger.AddTransaction(tx)
 
-	g.render(ctx, &sendTransactionResponse{ledger: g.ledger, tx: &tx})
+	return nil
+}
+
+func (g *Gateway) getRelayer(ctx *fasthttp.RequestCtx) {
+	param, ok := ctx.UserValue("id").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not cast id into string")))
+		return
+	}
+
+	slice, err := hex.DecodeString(param)
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "relayer ID must be presented as valid hex")))
+		return
+	}
+
+	if len(slice) != wavelet.SizeAccountID {
+		g.renderError(ctx, ErrBadRequest(errors.Errorf("relayer ID must be %d bytes long", wavelet.SizeAccountID)))
+		return
+	}
+
+	var id wavelet.AccountID
+	copy(id[:], slice)
+
+	stats, exists := g.relayers.get(id)
+	if !exists {
+		g.renderError(ctx, ErrNotFound(errors.Errorf("relayer %x has not relayed any transaction", id)))
+		return
+	}
+
+	g.render(ctx, &relayerResponse{id: id, stats: stats})
 }
 
 func (g *Gateway) ledgerStatus(ctx *fasthttp.RequestCtx) {
+	// Archival nodes may be queried for the ledger as of a past block.
+	if raw := string(ctx.QueryArgs().Peek("round")); len(raw) > 0 {
+		snapshot, block, errRes := g.snapshotAt(raw)
+		if errRes != nil {
+			g.renderError(ctx, errRes)
+			return
+		}
+
+		g.render(ctx, &ledgerRoundResponse{snapshot: snapshot, block: block})
+
+		return
+	}
+
 	g.render(ctx, &ledgerStatusResponse{client: g.client, ledger: g.ledger, publicKey: g.keys.PublicKey()})
 }
 
+// listTransactions lists the transactions the node has archived, ordered by
+// height and then by ID such that they may be paged through with offset and
+// limit. Transactions may be filtered by sender, tag, and range of heights.
 func (g *Gateway) listTransactions(ctx *fasthttp.RequestCtx) {
 	var (
-		sender        wavelet.AccountID
-		offset, limit uint64
-		err           error
+		sender               wavelet.AccountID
+		tag                  uint64
+		offset, limit        uint64
+		fromHeight, toHeight uint64
+		err                  error
 	)
 
 	queryArgs := ctx.QueryArgs()
@@ -373,25 +653,45 @@ func (g *Gateway) listTransactions(ctx *fasthttp.RequestCtx) {
 		copy(sender[:], slice)
 	}
 
-	if raw := string(queryArgs.Peek("offset")); len(raw) > 0 {
-		offset, err = strconv.ParseUint(raw, 10, 64)
-
-		if err != nil {
-			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "could not parse offset")))
-			return
-		}
+	uintArgs := []struct {
+		key string
+		dst *uint64
+	}{
+		{"offset", &offset},
+		{"limit", &limit},
+		{"tag", &tag},
+		{"from_height", &fromHeight},
+		{"to_height", &toHeight},
 	}
 
-	if raw := string(queryArgs.Peek("limit")); len(raw) > 0 {
-		limit, err = strconv.ParseUint(raw, 10, 64)
+	for _, arg := range uintArgs {
+		if raw := string(queryArgs.Peek(arg.key)); len(raw) > 0 {
+			*arg.dst, err = strconv.ParseUint(raw, 10, 64)
 
-		if err != nil {
-			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "could not parse limit")))
-			return
+			if err != nil {
+				g.renderError(ctx, ErrBadRequest(errors.Wrapf(err, "could not parse %s", arg.key)))
+				return
+			}
 		}
 	}
 
-	if limit > maxPaginationLimit {
+	g.render(ctx, g.findTransactions(transactionFilter{
+		sender: sender, tag: tag, fromHeight: fromHeight, toHeight: toHeight,
+	}, offset, limit))
+}
+
+// transactionFilter filters the transactions listed by findTransactions. Zero
+// fields do not filter transactions.
+type transactionFilter struct {
+	sender               wavelet.AccountID
+	tag                  uint64
+	fromHeight, toHeight uint64
+}
+
+// findTransactions lists the transactions our node has kept which pass
+// filter, ordered by the block they were created for and then by ID.
+func (g *Gateway) findTransactions(filter transactionFilter, offset, limit uint64) transactionList {
+	if limit == 0 || limit > maxPaginationLimit {
 		limit = maxPaginationLimit
 	}
 
@@ -403,13 +703,13 @@ func (g *Gateway) listTransactions(ctx *fasthttp.RequestCtx) {
 	// TODO: maybe there is be a better way to do this? Currently, this iterates
 	// the entire transaction list
 	g.ledger.Transactions().Iterate(func(tx *wavelet.Transaction) bool {
-		if tx.Block < offset {
+		if tx.Block < filter.fromHeight || (filter.toHeight > 0 && tx.Block > filter.toHeight) {
 			return true
 		}
-		if uint64(len(transactions)) >= limit {
+		if filter.sender != wavelet.ZeroAccountID && tx.Sender != filter.sender {
 			return true
 		}
-		if sender != wavelet.ZeroAccountID && tx.Sender != sender {
+		if filter.tag != 0 && uint64(tx.Tag) != filter.tag {
 			return true
 		}
 
@@ -422,7 +722,26 @@ func (g *Gateway) listTransactions(ctx *fasthttp.RequestCtx) {
 		return true
 	})
 
-	g.render(ctx, transactions)
+	sort.Slice(transactions, func(i, j int) bool {
+		a, b := transactions[i].tx, transactions[j].tx
+		if a.Block != b.Block {
+			return a.Block < b.Block
+		}
+
+		return bytes.Compare(a.ID[:], b.ID[:]) < 0
+	})
+
+	if offset >= uint64(len(transactions)) {
+		transactions = transactions[:0]
+	} else {
+		transactions = transactions[offset:]
+	}
+
+	if uint64(len(transactions)) > limit {
+		transactions = transactions[:limit]
+	}
+
+	return transactions
 }
 
 func (g *Gateway) getTransaction(ctx *fasthttp.RequestCtx) {
@@ -467,7 +786,7 @@ func (g *Gateway) getTransaction(ctx *fasthttp.RequestCtx) {
 	g.render(ctx, res)
 }
 
-func (g *Gateway) getAccount(ctx *fasthttp.RequestCtx) {
+func (g *Gateway) getData(ctx *fasthttp.RequestCtx) {
 	param, ok := ctx.UserValue("id").(string)
 	if !ok {
 		g.renderError(ctx, ErrBadRequest(errors.New("id must be a string")))
@@ -476,137 +795,931 @@ func (g *Gateway) getAccount(ctx *fasthttp.RequestCtx) {
 
 	slice, err := hex.DecodeString(param)
 	if err != nil {
-		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "account ID must be presented as valid hex")))
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "transaction ID must be presented as valid hex")))
 		return
 	}
 
-	if len(slice) != wavelet.SizeAccountID {
-		g.renderError(ctx, ErrBadRequest(errors.Errorf("account ID must be %d bytes long", wavelet.SizeAccountID)))
+	if len(slice) != wavelet.SizeTransactionID {
+		g.renderError(ctx, ErrBadRequest(errors.Errorf("transaction ID must be %d bytes long", wavelet.SizeTransactionID)))
 		return
 	}
 
-	var id wavelet.AccountID
+	var id wavelet.TransactionID
 
 	copy(id[:], slice)
 
-	snapshot := g.ledger.Snapshot()
-	balance, _ := wavelet.ReadAccountBalance(snapshot, id)
-	gasBalance, _ := wavelet.ReadAccountContractGasBalance(snapshot, id)
-	stake, _ := wavelet.ReadAccountStake(snapshot, id)
-	reward, _ := wavelet.ReadAccountReward(snapshot, id)
-	_, isContract := wavelet.ReadAccountContractCode(snapshot, id)
-	numPages, _ := wavelet.ReadAccountContractNumPages(snapshot, id)
+	blob, exists := wavelet.ReadData(g.ledger.Snapshot(), id)
+	if !exists {
+		g.renderError(ctx, ErrNotFound(errors.Errorf("could not find data anchored by transaction with ID %x", id)))
+		return
+	}
 
-	g.render(ctx, &account{
-		ledger:     g.ledger,
-		id:         id,
-		balance:    balance,
-		gasBalance: gasBalance,
-		stake:      stake,
-		reward:     reward,
-		isContract: isContract,
-		numPages:   numPages,
-	})
+	g.render(ctx, &dataResponse{id: id, blob: blob})
 }
 
-func (g *Gateway) getContractCode(ctx *fasthttp.RequestCtx) {
-	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+func (g *Gateway) getTransactionDiff(ctx *fasthttp.RequestCtx) {
+	param, ok := ctx.UserValue("id").(string)
 	if !ok {
-		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a string")))
 		return
 	}
 
-	code, available := wavelet.ReadAccountContractCode(g.ledger.Snapshot(), id)
+	slice, err := hex.DecodeString(param)
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "transaction ID must be presented as valid hex")))
+		return
+	}
 
-	if len(code) == 0 || !available {
-		g.renderError(ctx, ErrNotFound(errors.Errorf("could not find contract with ID %x", id)))
+	if len(slice) != wavelet.SizeTransactionID {
+		g.renderError(ctx, ErrBadRequest(errors.Errorf("transaction ID must be %d bytes long", wavelet.SizeTransactionID)))
 		return
 	}
 
-	ctx.Response.Header.Set("Content-Disposition", "attachment; filename="+hex.EncodeToString(id[:])+".wasm")
-	ctx.Response.Header.Set("Content-Type", "application/wasm")
-	ctx.Response.Header.Set("Content-Length", strconv.Itoa(len(code)))
+	var id wavelet.TransactionID
 
-	_, _ = io.Copy(ctx, bytes.NewReader(code))
-}
+	copy(id[:], slice)
+
+	diff, err := g.ledger.TransactionDiff(id)
+	if err != nil {
+		if errors.Cause(err) == store.ErrNotFound {
+			g.renderError(ctx, ErrNotFound(errors.Errorf("could not find state diff of transaction with ID %x", id)))
+			return
+		}
+
+		g.renderError(ctx, ErrInternal(err))
 
-func (g *Gateway) getContractPages(ctx *fasthttp.RequestCtx) {
-	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
-	if !ok {
-		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
 		return
 	}
 
-	var (
-		idx uint64
-		err error
-	)
+	g.render(ctx, &diffResponse{diff: diff})
+}
 
-	rawIdx, ok := ctx.UserValue("index").(string)
-	if !ok {
-		g.renderError(ctx, ErrBadRequest(errors.New("could not cast index into string")))
-		return
+// listMempool lists the transactions pending finalization, in the order they
+// would be proposed in, paged through by offset and limit.
+func (g *Gateway) listMempool(ctx *fasthttp.RequestCtx) {
+	var offset, limit uint64
+
+	queryArgs := ctx.QueryArgs()
+
+	uintArgs := []struct {
+		key string
+		dst *uint64
+	}{
+		{"offset", &offset},
+		{"limit", &limit},
 	}
 
-	if len(rawIdx) != 0 {
-		idx, err = strconv.ParseUint(rawIdx, 10, 64)
+	for _, arg := range uintArgs {
+		if raw := string(queryArgs.Peek(arg.key)); len(raw) > 0 {
+			var err error
 
-		if err != nil {
-			g.renderError(ctx, ErrBadRequest(errors.New("could not parse page index")))
-			return
+			if *arg.dst, err = strconv.ParseUint(raw, 10, 64); err != nil {
+				g.renderError(ctx, ErrBadRequest(errors.Wrapf(err, "could not parse %s", arg.key)))
+				return
+			}
 		}
 	}
 
-	snapshot := g.ledger.Snapshot()
+	if limit == 0 || limit > maxPaginationLimit {
+		limit = maxPaginationLimit
+	}
 
-	numPages, available := wavelet.ReadAccountContractNumPages(snapshot, id)
+	ids := g.ledger.Transactions().PendingIDs()
 
-	if !available {
-		g.renderError(ctx, ErrNotFound(errors.Errorf("could not find any pages for contract with ID %x", id)))
-		return
+	if offset >= uint64(len(ids)) {
+		ids = ids[:0]
+	} else {
+		ids = ids[offset:]
 	}
 
-	if idx >= numPages {
-		err := errors.Errorf("contract with ID %x only has %d pages, but you requested page %d", id, numPages, idx)
-		g.renderError(ctx, ErrBadRequest(err))
-
-		return
+	if uint64(len(ids)) > limit {
+		ids = ids[:limit]
 	}
 
-	page, available := wavelet.ReadAccountContractPage(snapshot, id, idx)
+	transactions := make(transactionList, 0, len(ids))
 
-	if len(page) == 0 || !available {
-		_, _ = ctx.Write([]byte{})
-		return
+	for _, id := range ids {
+		// The transaction may have been pruned since its ID was listed.
+		if tx := g.ledger.Transactions().Find(id); tx != nil {
+			transactions = append(transactions, &transaction{tx: tx, status: statusReceived})
+		}
 	}
 
-	_, _ = ctx.Write(page)
+	g.render(ctx, transactions)
 }
 
-func (g *Gateway) connect(ctx *fasthttp.RequestCtx) {
-	parser := g.parserPool.Get()
-	v, err := parser.ParseBytes(ctx.PostBody())
-	g.parserPool.Put(parser)
+func (g *Gateway) countMempool(ctx *fasthttp.RequestCtx) {
+	g.render(ctx, &mempoolCountResponse{
+		count:   g.ledger.Transactions().PendingLen(),
+		missing: g.ledger.Transactions().MissingLen(),
+	})
+}
 
-	if err != nil {
-		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "error parsing request body")))
+func (g *Gateway) mempoolContains(ctx *fasthttp.RequestCtx) {
+	param, ok := ctx.UserValue("id").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a string")))
 		return
 	}
 
-	addressVal := v.Get("address")
-	if addressVal == nil {
-		g.renderError(ctx, ErrBadRequest(errors.New("address is missing")))
+	slice, err := hex.DecodeString(param)
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "transaction ID must be presented as valid hex")))
 		return
 	}
 
-	address, err := addressVal.StringBytes()
-	if err != nil {
-		g.renderError(ctx, ErrInternal(errors.Wrap(err, "error extracting address from payload")))
+	if len(slice) != wavelet.SizeTransactionID {
+		g.renderError(ctx, ErrBadRequest(errors.Errorf("transaction ID must be %d bytes long", wavelet.SizeTransactionID)))
 		return
 	}
 
-	if _, err := g.client.Dial(string(address)); err != nil {
-		g.renderError(ctx, ErrInternal(errors.Wrap(err, "error connecting to peer")))
+	var id wavelet.TransactionID
+
+	copy(id[:], slice)
+
+	latest := g.ledger.Blocks().Latest()
+
+	g.render(ctx, &mempoolContainsResponse{
+		id:       id,
+		contains: g.ledger.Transactions().HasPending(latest.ID, id),
+	})
+}
+
+func (g *Gateway) getAccount(ctx *fasthttp.RequestCtx) {
+	param, ok := ctx.UserValue("id").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a string")))
+		return
+	}
+
+	snapshot := g.ledger.Snapshot()
+
+	// Archival nodes may be queried for the account as of a past block.
+	if raw := string(ctx.QueryArgs().Peek("round")); len(raw) > 0 {
+		var errRes *errResponse
+
+		if snapshot, _, errRes = g.snapshotAt(raw); errRes != nil {
+			g.renderError(ctx, errRes)
+			return
+		}
+	}
+
+	id, errRes := g.resolveID(snapshot, param, "account")
+	if errRes != nil {
+		g.renderError(ctx, errRes)
+		return
+	}
+
+	g.render(ctx, g.readAccount(snapshot, id))
+}
+
+// snapshotAt returns the state of the ledger as of the block whose index is
+// raw, alongside the block.
+func (g *Gateway) snapshotAt(raw string) (*avl.Tree, *wavelet.Block, *errResponse) {
+	index, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil, nil, ErrBadRequest(errors.Wrap(err, "could not parse round"))
+	}
+
+	snapshot, block, err := g.ledger.SnapshotAt(index)
+	if err != nil {
+		return nil, nil, stateErrResponse(err)
+	}
+
+	return snapshot, block, nil
+}
+
+// stateErrResponse responds with 404 should the state of a block queried not
+// be kept.
+func stateErrResponse(err error) *errResponse {
+	if _, ok := errors.Cause(err).(*wavelet.StateNotKeptError); ok {
+		return ErrNotFound(err)
+	}
+
+	return ErrInternal(err)
+}
+
+// getAccountProof proves the balances of an account against the Merkle root
+// of the latest block whose state was committed, or of the block given should
+// our node be an archival node.
+func (g *Gateway) getAccountProof(ctx *fasthttp.RequestCtx) {
+	param, ok := ctx.UserValue("id").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a string")))
+		return
+	}
+
+	id, errRes := g.resolveID(g.ledger.Snapshot(), param, "account")
+	if errRes != nil {
+		g.renderError(ctx, errRes)
+		return
+	}
+
+	var (
+		proof wavelet.AccountProof
+		block *wavelet.Block
+		err   error
+	)
+
+	if raw := string(ctx.QueryArgs().Peek("block")); len(raw) > 0 {
+		var index uint64
+
+		if index, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "could not parse block")))
+			return
+		}
+
+		proof, block, err = g.ledger.ProveAccountAt(id, index)
+		if err != nil {
+			g.renderError(ctx, stateErrResponse(err))
+			return
+		}
+	} else if proof, block, err = g.ledger.ProveAccount(id); err != nil {
+		g.renderError(ctx, ErrInternal(err))
+		return
+	}
+
+	g.render(ctx, &accountProofResponse{proof: proof, block: block})
+}
+
+// getAccountDelegations responds with the PERLs delegated to an account as a
+// validator, and the PERLs it delegates to validators, bonded or unbonding.
+func (g *Gateway) getAccountDelegations(ctx *fasthttp.RequestCtx) {
+	param, ok := ctx.UserValue("id").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a string")))
+		return
+	}
+
+	snapshot := g.ledger.Snapshot()
+
+	id, errRes := g.resolveID(snapshot, param, "account")
+	if errRes != nil {
+		g.renderError(ctx, errRes)
+		return
+	}
+
+	res := &accountDelegationsResponse{id: id}
+
+	res.commission, _ = wavelet.ReadAccountCommission(snapshot, id)
+	res.delegators, _ = wavelet.ReadAccountDelegators(snapshot, id)
+	res.delegations, _ = wavelet.ReadAccountDelegations(snapshot, id)
+	res.unbondings, _ = wavelet.ReadAccountUnbondings(snapshot, id)
+
+	g.render(ctx, res)
+}
+
+// getAccountSlashings responds with the slashing events of an account as a
+// validator, and the last block it was seen active at.
+func (g *Gateway) getAccountSlashings(ctx *fasthttp.RequestCtx) {
+	param, ok := ctx.UserValue("id").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a string")))
+		return
+	}
+
+	snapshot := g.ledger.Snapshot()
+
+	id, errRes := g.resolveID(snapshot, param, "account")
+	if errRes != nil {
+		g.renderError(ctx, errRes)
+		return
+	}
+
+	res := &accountSlashingsResponse{id: id}
+
+	res.lastActive, res.active = wavelet.ReadAccountLastActive(snapshot, id)
+	res.events, _ = wavelet.ReadAccountSlashings(snapshot, id)
+
+	g.render(ctx, res)
+}
+
+func (g *Gateway) listValidators(ctx *fasthttp.RequestCtx) {
+	snapshot := g.ledger.Snapshot()
+
+	res := &validatorsResponse{block: g.ledger.Blocks().Latest().Index, now: time.Now()}
+
+	self := g.keys.PublicKey()
+
+	for _, validator := range wavelet.ReadValidators(snapshot) {
+		v := validatorStatus{Validator: validator}
+
+		if delegators, exists := wavelet.ReadAccountDelegators(snapshot, validator.ID); exists {
+			for _, delegator := range delegators {
+				v.delegated += delegator.Amount
+			}
+		}
+
+		v.lastActive, v.active = wavelet.ReadAccountLastActive(snapshot, validator.ID)
+
+		if validator.ID == self {
+			v.since, v.connected = g.peers.started, true
+		} else {
+			v.since, v.connected = g.peers.since(validator.ID)
+		}
+
+		res.validators = append(res.validators, v)
+	}
+
+	g.render(ctx, res)
+}
+
+func (g *Gateway) listPeerScores(ctx *fasthttp.RequestCtx) {
+	reputation := g.ledger.Reputation()
+
+	res := &peerScoresResponse{policy: reputation.Policy(), now: time.Now()}
+
+	// Peers we are connected to but have yet to score are listed with a
+	// score of 0.
+	res.connected = make(map[wavelet.AccountID]struct{})
+
+	if g.client != nil {
+		for _, id := range g.client.ClosestPeerIDs() {
+			reputation.Score(id)
+			res.connected[id.PublicKey()] = struct{}{}
+		}
+	}
+
+	res.scores = reputation.Scores()
+
+	g.render(ctx, res)
+}
+
+// readAccount reads the state of the account id from snapshot.
+func (g *Gateway) readAccount(snapshot *avl.Tree, id wavelet.AccountID) *account {
+	balance, _ := wavelet.ReadAccountBalance(snapshot, id)
+	gasBalance, _ := wavelet.ReadAccountContractGasBalance(snapshot, id)
+	stake, _ := wavelet.ReadAccountStake(snapshot, id)
+	reward, _ := wavelet.ReadAccountReward(snapshot, id)
+	_, isContract := wavelet.ReadAccountContractCode(snapshot, id)
+	numPages, _ := wavelet.ReadAccountContractNumPages(snapshot, id)
+	feeAllowance, _ := wavelet.ReadAccountFeeAllowance(snapshot, id)
+
+	acc := &account{
+		ledger:     g.ledger,
+		id:         id,
+		balance:    balance,
+		gasBalance: gasBalance,
+		stake:      stake,
+		reward:     reward,
+		isContract: isContract,
+		numPages:   numPages,
+
+		feeAllowance: feeAllowance,
+	}
+
+	if config, exists := wavelet.ReadAccountRecoveryConfig(snapshot, id); exists {
+		acc.recoveryConfig = &config
+	}
+
+	if recovery, exists := wavelet.ReadAccountPendingRecovery(snapshot, id); exists {
+		acc.pendingRecovery = &recovery
+	}
+
+	if multisig, exists := wavelet.ReadAccountMultisig(snapshot, id); exists {
+		acc.multisig = &multisig
+		acc.multisigProposals = make(map[wavelet.TransactionID]wavelet.MultisigProposal, len(multisig.Proposals))
+
+		for _, proposalID := range multisig.Proposals {
+			if proposal, pending := wavelet.ReadMultisigProposal(snapshot, proposalID); pending {
+				acc.multisigProposals[proposalID] = proposal
+			}
+		}
+	}
+
+	return acc
+}
+
+func (g *Gateway) getName(ctx *fasthttp.RequestCtx) {
+	name, ok := ctx.UserValue("name").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not cast name into string")))
+		return
+	}
+
+	if err := wavelet.ValidateName(name); err != nil {
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	record, exists := wavelet.ReadName(g.ledger.Snapshot(), name)
+	if !exists || g.ledger.Blocks().Latest().Index+1 >= record.Expiry {
+		g.renderError(ctx, ErrNotFound(errors.Errorf("name %q is not registered", name)))
+		return
+	}
+
+	g.render(ctx, &nameResponse{name: name, record: record})
+}
+
+// resolveID parses param as a hex-encoded ID, or resolves it through the name
+// service should it be a name. IDs are 64 characters long, which is longer
+// than any name.
+func (g *Gateway) resolveID(snapshot *avl.Tree, param string, kind string) ([32]byte, *errResponse) {
+	var id [32]byte
+
+	if len(param) <= sys.MaxNameLength && wavelet.ValidateName(param) == nil {
+		target, exists := wavelet.ResolveName(snapshot, param, g.ledger.Blocks().Latest().Index+1)
+		if !exists {
+			return id, ErrNotFound(errors.Errorf("name %q is not registered", param))
+		}
+
+		return target, nil
+	}
+
+	slice, err := hex.DecodeString(param)
+	if err != nil {
+		return id, ErrBadRequest(errors.Wrapf(err, "%s ID must be presented as valid hex", kind))
+	}
+
+	if len(slice) != len(id) {
+		return id, ErrBadRequest(errors.Errorf("%s ID must be %d bytes long", kind, len(id)))
+	}
+
+	copy(id[:], slice)
+
+	return id, nil
+}
+
+func (g *Gateway) getContractCode(ctx *fasthttp.RequestCtx) {
+	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+		return
+	}
+
+	code, available := wavelet.ReadAccountContractCode(g.ledger.Snapshot(), id)
+
+	if len(code) == 0 || !available {
+		g.renderError(ctx, ErrNotFound(errors.Errorf("could not find contract with ID %x", id)))
+		return
+	}
+
+	ctx.Response.Header.Set("Content-Disposition", "attachment; filename="+hex.EncodeToString(id[:])+".wasm")
+	ctx.Response.Header.Set("Content-Type", "application/wasm")
+	ctx.Response.Header.Set("Content-Length", strconv.Itoa(len(code)))
+
+	_, _ = io.Copy(ctx, bytes.NewReader(code))
+}
+
+// getContractUpgrades renders the account which may upgrade the code of a
+// smart contract, should it have one, and the upgrades of its code so far.
+func (g *Gateway) getContractUpgrades(ctx *fasthttp.RequestCtx) {
+	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+		return
+	}
+
+	snapshot := g.ledger.Snapshot()
+
+	code, available := wavelet.ReadAccountContractCode(snapshot, id)
+	if len(code) == 0 || !available {
+		g.renderError(ctx, ErrNotFound(errors.Errorf("could not find contract with ID %x", id)))
+		return
+	}
+
+	res := &contractUpgradesResponse{
+		codeHash: wavelet.ContractCodeHash(code),
+		upgrades: wavelet.ReadAccountContractUpgrades(snapshot, id),
+	}
+
+	res.upgrader, res.upgradable = wavelet.ReadAccountContractUpgrader(snapshot, id)
+
+	g.render(ctx, res)
+}
+
+// callContract simulates calling a smart contract function against the
+// latest state of the ledger, without committing its effects, and responds
+// with its result and the gas it spent.
+func (g *Gateway) callContract(ctx *fasthttp.RequestCtx) {
+	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+		return
+	}
+
+	req := &contractCallRequest{}
+
+	parser := g.parserPool.Get()
+	defer g.parserPool.Put(parser)
+
+	if err := req.bind(parser, ctx.PostBody()); err != nil {
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	call, err := wavelet.SimulateContractCall(
+		g.ledger.Snapshot(), g.ledger.Blocks().Latest(), req.sender, id, req.Amount, req.GasLimit, req.Func, req.params,
+	)
+
+	switch errors.Cause(err) {
+	case nil:
+	case wavelet.ErrContractNotFound:
+		g.renderError(ctx, ErrNotFound(err))
+		return
+	default:
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	g.render(ctx, &contractCallResponse{call: call})
+}
+
+// getContractLogs lists the logs a smart contract emitted, in order of
+// emission, such that they may be paged through with offset and limit. Should
+// from_block be given, offset is counted from the first log emitted at or
+// after that block.
+func (g *Gateway) getContractLogs(ctx *fasthttp.RequestCtx) {
+	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+		return
+	}
+
+	var (
+		offset, limit, fromBlock uint64
+		err                      error
+	)
+
+	queryArgs := ctx.QueryArgs()
+
+	uintArgs := []struct {
+		key string
+		dst *uint64
+	}{
+		{"offset", &offset},
+		{"limit", &limit},
+		{"from_block", &fromBlock},
+	}
+
+	for _, arg := range uintArgs {
+		if raw := string(queryArgs.Peek(arg.key)); len(raw) > 0 {
+			*arg.dst, err = strconv.ParseUint(raw, 10, 64)
+
+			if err != nil {
+				g.renderError(ctx, ErrBadRequest(errors.Wrapf(err, "could not parse %s", arg.key)))
+				return
+			}
+		}
+	}
+
+	if limit == 0 || limit > maxPaginationLimit {
+		limit = maxPaginationLimit
+	}
+
+	if fromBlock > 0 {
+		first, err := g.ledger.SearchContractLogs(id, fromBlock)
+		if err != nil {
+			g.renderError(ctx, ErrInternal(err))
+			return
+		}
+
+		offset += first
+	}
+
+	logs, total, err := g.ledger.ContractLogs(id, offset, limit)
+	if err != nil {
+		g.renderError(ctx, ErrInternal(err))
+		return
+	}
+
+	g.render(ctx, &contractLogList{logs: logs, total: total})
+}
+
+// pollContractLogs streams the logs a smart contract emits over a websocket
+// as they are finalized, through the sink of contract events.
+func (g *Gateway) pollContractLogs(sink *sink) func(ctx *fasthttp.RequestCtx) {
+	return func(ctx *fasthttp.RequestCtx) {
+		id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+		if !ok {
+			g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+			return
+		}
+
+		filters := map[string]string{
+			"contract_id": hex.EncodeToString(id[:]),
+			log.KeyEvent:  events.EventContractLog,
+		}
+
+		if err := sink.serveFiltered(ctx, filters); err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "failed to init websocket session")))
+		}
+	}
+}
+
+// getContractStorage renders the value of a key of the storage of a smart
+// contract, alongside a proof of it against the Merkle root of the latest
+// block should proof be set, in which case keys which are not set are proven
+// to be unset rather than not found.
+func (g *Gateway) getContractStorage(ctx *fasthttp.RequestCtx) {
+	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+		return
+	}
+
+	param, ok := ctx.UserValue("key").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not cast key into string")))
+		return
+	}
+
+	key, err := hex.DecodeString(param)
+	if err != nil || len(key) == 0 || len(key) > sys.ContractMaxStorageKeySize {
+		g.renderError(ctx, ErrBadRequest(errors.Errorf(
+			"key must be hex-encoded, and 1 to %d bytes long", sys.ContractMaxStorageKeySize,
+		)))
+
+		return
+	}
+
+	if !ctx.QueryArgs().GetBool("proof") {
+		value, exists := wavelet.ReadContractStorage(g.ledger.Snapshot(), id, key)
+		if !exists {
+			g.renderError(ctx, ErrNotFound(errors.Errorf("key %x of the storage of contract %x is not set", key, id)))
+			return
+		}
+
+		g.render(ctx, &contractStorageResponse{id: id, key: key, value: value, exists: true})
+
+		return
+	}
+
+	proof, block, err := g.ledger.ProveContractStorage(id, key)
+	if err != nil {
+		g.renderError(ctx, ErrInternal(err))
+		return
+	}
+
+	value, exists, err := proof.Verify(block.Merkle)
+	if err != nil {
+		g.renderError(ctx, ErrInternal(err))
+		return
+	}
+
+	g.render(ctx, &contractStorageResponse{
+		id: id, key: key, value: value, exists: exists, proof: &proof, block: block,
+	})
+}
+
+// scanContractStorage renders the keys of the storage of a smart contract
+// starting with the hex-encoded prefix, in order, starting from the key after
+// the hex-encoded after.
+func (g *Gateway) scanContractStorage(ctx *fasthttp.RequestCtx) {
+	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+		return
+	}
+
+	queryArgs := ctx.QueryArgs()
+
+	var (
+		prefix, after []byte
+		limit         uint64
+		err           error
+	)
+
+	for _, arg := range []struct {
+		key string
+		dst *[]byte
+	}{{"prefix", &prefix}, {"after", &after}} {
+		if raw := string(queryArgs.Peek(arg.key)); len(raw) > 0 {
+			if *arg.dst, err = hex.DecodeString(raw); err != nil {
+				g.renderError(ctx, ErrBadRequest(errors.Wrapf(err, "could not parse %s", arg.key)))
+				return
+			}
+		}
+	}
+
+	if raw := string(queryArgs.Peek("limit")); len(raw) > 0 {
+		if limit, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "could not parse limit")))
+			return
+		}
+	}
+
+	if limit == 0 || limit > maxPaginationLimit {
+		limit = maxPaginationLimit
+	}
+
+	entries := wavelet.ScanContractStorage(g.ledger.Snapshot(), id, prefix, after, limit)
+
+	g.render(ctx, &contractStorageList{entries: entries})
+}
+
+func (g *Gateway) getContractPages(ctx *fasthttp.RequestCtx) {
+	id, ok := ctx.UserValue("contract_id").(wavelet.TransactionID)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("id must be a TransactionID")))
+		return
+	}
+
+	var (
+		idx uint64
+		err error
+	)
+
+	rawIdx, ok := ctx.UserValue("index").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not cast index into string")))
+		return
+	}
+
+	if len(rawIdx) != 0 {
+		idx, err = strconv.ParseUint(rawIdx, 10, 64)
+
+		if err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.New("could not parse page index")))
+			return
+		}
+	}
+
+	snapshot := g.ledger.Snapshot()
+
+	numPages, available := wavelet.ReadAccountContractNumPages(snapshot, id)
+
+	if !available {
+		g.renderError(ctx, ErrNotFound(errors.Errorf("could not find any pages for contract with ID %x", id)))
+		return
+	}
+
+	if idx >= numPages {
+		err := errors.Errorf("contract with ID %x only has %d pages, but you requested page %d", id, numPages, idx)
+		g.renderError(ctx, ErrBadRequest(err))
+
+		return
+	}
+
+	page, available := wavelet.ReadAccountContractPage(snapshot, id, idx)
+
+	if len(page) == 0 || !available {
+		_, _ = ctx.Write([]byte{})
+		return
+	}
+
+	_, _ = ctx.Write(page)
+}
+
+func (g *Gateway) getRandomness(ctx *fasthttp.RequestCtx) {
+	rawIdx, ok := ctx.UserValue("index").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not cast index into string")))
+		return
+	}
+
+	idx, err := strconv.ParseUint(rawIdx, 10, 64)
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not parse block index")))
+		return
+	}
+
+	value, exists := wavelet.ReadBeacon(g.ledger.Snapshot(), idx)
+	if !exists {
+		g.renderError(ctx, ErrNotFound(errors.Errorf("no randomness was recorded for block %d", idx)))
+		return
+	}
+
+	g.render(ctx, &randomnessResponse{index: idx, value: value})
+}
+
+// listRounds renders the rounds our node finalized, starting from the round
+// of the latest block and going back.
+func (g *Gateway) listRounds(ctx *fasthttp.RequestCtx) {
+	from, limit := g.ledger.Blocks().Latest().Index, uint64(0)
+
+	queryArgs := ctx.QueryArgs()
+
+	uintArgs := []struct {
+		key string
+		dst *uint64
+	}{
+		{"from", &from},
+		{"limit", &limit},
+	}
+
+	for _, arg := range uintArgs {
+		if raw := string(queryArgs.Peek(arg.key)); len(raw) > 0 {
+			var err error
+
+			if *arg.dst, err = strconv.ParseUint(raw, 10, 64); err != nil {
+				g.renderError(ctx, ErrBadRequest(errors.Wrapf(err, "could not parse %s", arg.key)))
+				return
+			}
+		}
+	}
+
+	if limit == 0 || limit > maxPaginationLimit {
+		limit = maxPaginationLimit
+	}
+
+	rounds, err := g.ledger.Rounds(from, limit)
+	if err != nil {
+		g.renderError(ctx, ErrInternal(err))
+		return
+	}
+
+	g.render(ctx, &roundList{rounds: rounds})
+}
+
+func (g *Gateway) getRound(ctx *fasthttp.RequestCtx) {
+	rawIdx, ok := ctx.UserValue("index").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not cast index into string")))
+		return
+	}
+
+	idx, err := strconv.ParseUint(rawIdx, 10, 64)
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not parse block index")))
+		return
+	}
+
+	round, err := g.ledger.Round(idx)
+	if err != nil {
+		if errors.Cause(err) == store.ErrNotFound {
+			g.renderError(ctx, ErrNotFound(errors.Errorf("no round was kept of block %d", idx)))
+			return
+		}
+
+		g.renderError(ctx, ErrInternal(err))
+
+		return
+	}
+
+	g.render(ctx, &roundResponse{round: round})
+}
+
+// pollRounds streams the rounds our node finalizes over a websocket, through
+// the sink of consensus events.
+func (g *Gateway) pollRounds(sink *sink) func(ctx *fasthttp.RequestCtx) {
+	return func(ctx *fasthttp.RequestCtx) {
+		filters := map[string]string{log.KeyEvent: events.EventRound}
+
+		if err := sink.serveFiltered(ctx, filters); err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "failed to init websocket session")))
+		}
+	}
+}
+
+func (g *Gateway) getTime(ctx *fasthttp.RequestCtx) {
+	g.render(ctx, &timeResponse{now: time.Now()})
+}
+
+// getGasSchedule renders the gas schedule applying to the next block to be
+// finalized, being the block transactions sent now are applied in at the
+// earliest.
+func (g *Gateway) getGasSchedule(ctx *fasthttp.RequestCtx) {
+	height := g.ledger.Blocks().Latest().Index + 1
+
+	g.render(ctx, &gasScheduleResponse{height: height, schedule: sys.GasScheduleAt(height)})
+}
+
+// getSnapshot responds with a snapshot of the latest state of the ledger,
+// which nodes may bootstrap from through `wavelet snapshot import`.
+func (g *Gateway) getSnapshot(ctx *fasthttp.RequestCtx) {
+	var buf bytes.Buffer
+
+	block, err := g.ledger.ExportSnapshot(&buf)
+	if err != nil {
+		g.renderError(ctx, ErrInternal(errors.Wrap(err, "failed to export snapshot")))
+		return
+	}
+
+	ctx.Response.Header.Set("X-Wavelet-Snapshot-Height", strconv.FormatUint(block.Index, 10))
+	ctx.Response.Header.Set("X-Wavelet-Snapshot-Block", hex.EncodeToString(block.ID[:]))
+	ctx.Response.Header.Set("X-Wavelet-Snapshot-Merkle-Root", hex.EncodeToString(block.Merkle[:]))
+	ctx.Response.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"wavelet-%d.snap\"", block.Index))
+
+	ctx.SetContentType("application/octet-stream")
+	ctx.SetStatusCode(http.StatusOK)
+	ctx.SetBody(buf.Bytes())
+}
+
+func (g *Gateway) connect(ctx *fasthttp.RequestCtx) {
+	parser := g.parserPool.Get()
+	v, err := parser.ParseBytes(ctx.PostBody())
+	g.parserPool.Put(parser)
+
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "error parsing request body")))
+		return
+	}
+
+	addressVal := v.Get("address")
+	if addressVal == nil {
+		g.renderError(ctx, ErrBadRequest(errors.New("address is missing")))
+		return
+	}
+
+	address, err := addressVal.StringBytes()
+	if err != nil {
+		g.renderError(ctx, ErrInternal(errors.Wrap(err, "error extracting address from payload")))
+		return
+	}
+
+	if _, err := g.client.Dial(string(address)); err != nil {
+		g.renderError(ctx, ErrInternal(errors.Wrap(err, "error connecting to peer")))
 		return
 	}
 
@@ -643,6 +1756,104 @@ func (g *Gateway) disconnect(ctx *fasthttp.RequestCtx) {
 	g.render(ctx, &msgResponse{msg: fmt.Sprintf("Successfully disconnected from %s", address)})
 }
 
+func (g *Gateway) banPeer(ctx *fasthttp.RequestCtx) {
+	id, ok := g.peerID(ctx)
+	if !ok {
+		return
+	}
+
+	var duration time.Duration
+
+	if body := ctx.PostBody(); len(body) != 0 {
+		parser := g.parserPool.Get()
+		v, err := parser.ParseBytes(body)
+		g.parserPool.Put(parser)
+
+		if err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "error parsing request body")))
+			return
+		}
+
+		if val := v.Get("duration"); val != nil {
+			raw, err := val.StringBytes()
+			if err != nil {
+				g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "duration must be a string")))
+				return
+			}
+
+			if duration, err = time.ParseDuration(string(raw)); err != nil || duration <= 0 {
+				g.renderError(ctx, ErrBadRequest(errors.New("duration must be a positive Go duration, such as 1m30s")))
+				return
+			}
+		}
+	}
+
+	score := g.ledger.Reputation().Ban(id, duration)
+
+	g.render(ctx, &msgResponse{
+		msg: fmt.Sprintf("Banned peer %x until %s", id, score.BannedUntil.UTC().Format(time.RFC3339)),
+	})
+}
+
+func (g *Gateway) unbanPeer(ctx *fasthttp.RequestCtx) {
+	id, ok := g.peerID(ctx)
+	if !ok {
+		return
+	}
+
+	if !g.ledger.Reputation().Unban(id) {
+		g.renderError(ctx, ErrNotFound(errors.Errorf("peer %x is not banned", id)))
+		return
+	}
+
+	g.render(ctx, &msgResponse{msg: fmt.Sprintf("Unbanned peer %x", id)})
+}
+
+// peerID reads the public key of the peer a request is about, rendering an
+// error should it be malformed.
+func (g *Gateway) peerID(ctx *fasthttp.RequestCtx) (wavelet.AccountID, bool) {
+	var id wavelet.AccountID
+
+	param, ok := ctx.UserValue("id").(string)
+	if !ok {
+		g.renderError(ctx, ErrBadRequest(errors.New("could not cast id into string")))
+		return id, false
+	}
+
+	slice, err := hex.DecodeString(param)
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "peer ID must be presented as valid hex")))
+		return id, false
+	}
+
+	if len(slice) != wavelet.SizeAccountID {
+		g.renderError(ctx, ErrBadRequest(errors.Errorf("peer ID must be %d bytes long", wavelet.SizeAccountID)))
+		return id, false
+	}
+
+	copy(id[:], slice)
+
+	return id, true
+}
+
+func (g *Gateway) prune(ctx *fasthttp.RequestCtx) {
+	status, err := g.ledger.Prune()
+
+	if err == wavelet.ErrPruningDisabled {
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	if err != nil {
+		g.renderError(ctx, ErrInternal(errors.Wrap(err, "error pruning")))
+		return
+	}
+
+	g.render(ctx, &msgResponse{
+		msg: fmt.Sprintf("Pruned the data of blocks before %d, %d diffs in total", status.RetainedFrom, status.PrunedDiffs),
+	})
+}
+
 func (g *Gateway) restart(ctx *fasthttp.RequestCtx) {
 	if err := g.kv.Close(); err != nil {
 		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "error closing storage")))
@@ -677,6 +1888,124 @@ func (g *Gateway) restart(ctx *fasthttp.RequestCtx) {
 	}
 }
 
+// updateSnowball adjusts the Snowball consensus protocol parameters the node
+// runs with, for tuning them on a running testnet. Parameters left out of the
+// request are kept as they are.
+func (g *Gateway) updateSnowball(ctx *fasthttp.RequestCtx) {
+	parser := g.parserPool.Get()
+	v, err := parser.ParseBytes(ctx.PostBody())
+	g.parserPool.Put(parser)
+
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "error parsing request body")))
+		return
+	}
+
+	k, alpha, beta := conf.GetSnowballK(), conf.GetSnowballAlpha(), conf.GetSnowballBeta()
+
+	if val := v.Get("k"); val != nil {
+		if k, err = val.Int(); err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "k must be an integer")))
+			return
+		}
+	}
+
+	if val := v.Get("alpha"); val != nil {
+		if alpha, err = val.Float64(); err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "alpha must be a number")))
+			return
+		}
+	}
+
+	if val := v.Get("beta"); val != nil {
+		if beta, err = val.Int(); err != nil {
+			g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "beta must be an integer")))
+			return
+		}
+	}
+
+	if err := conf.ValidateSnowball(k, alpha, beta); err != nil {
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	conf.Update(conf.WithSnowballK(k), conf.WithSnowballAlpha(alpha), conf.WithSnowballBeta(beta))
+
+	logger := log.Node()
+	logger.Info().
+		Int("k", k).
+		Float64("alpha", alpha).
+		Int("beta", beta).
+		Msg("Updated Snowball consensus protocol parameters.")
+
+	g.render(ctx, &snowballResponse{})
+}
+
+func (g *Gateway) getLogLevels(ctx *fasthttp.RequestCtx) {
+	g.render(ctx, &logLevelsResponse{})
+}
+
+// updateLogLevels sets the log levels of the modules keyed in the request body,
+// leaving the levels of others as they are. No level is changed should any of
+// the modules or levels be invalid.
+func (g *Gateway) updateLogLevels(ctx *fasthttp.RequestCtx) {
+	parser := g.parserPool.Get()
+	v, err := parser.ParseBytes(ctx.PostBody())
+	g.parserPool.Put(parser)
+
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "error parsing request body")))
+		return
+	}
+
+	o, err := v.Object()
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(errors.Wrap(err, "request body must be an object")))
+		return
+	}
+
+	levels := make(map[string]string)
+
+	o.Visit(func(key []byte, val *fastjson.Value) {
+		if err != nil {
+			return
+		}
+
+		var b []byte
+		if b, err = val.StringBytes(); err != nil {
+			err = errors.Wrapf(err, "level of %q must be a string", key)
+			return
+		}
+
+		if err = log.ValidateLevel(string(key), string(b)); err != nil {
+			return
+		}
+
+		levels[string(key)] = string(b)
+	})
+
+	if err != nil {
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	logger := log.Node()
+
+	for module, level := range levels {
+		if err := log.SetModuleLevel(module, level); err != nil {
+			g.renderError(ctx, ErrBadRequest(err))
+			return
+		}
+
+		logger.Info().
+			Str("module", module).
+			Str("level", level).
+			Msg("Updated log level.")
+	}
+
+	g.render(ctx, &logLevelsResponse{})
+}
+
 func (g *Gateway) notFound() func(ctx *fasthttp.RequestCtx) {
 	methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
 
@@ -781,6 +2110,20 @@ func (g *Gateway) Write(buf []byte) (n int, err error) {
 }
 
 func (g *Gateway) render(ctx *fasthttp.RequestCtx, m marshalableJSON) {
+	if p, ok := m.(marshalableProto); ok && acceptsProto(ctx) {
+		b, err := p.marshalProto()
+		if err != nil {
+			ctx.Error(fmt.Sprintf(`{ "error": "render error: %s" }`, err.Error()), http.StatusInternalServerError)
+			return
+		}
+
+		ctx.SetContentType(contentTypeProto)
+		ctx.Response.SetStatusCode(http.StatusOK)
+		ctx.Response.SetBody(b)
+
+		return
+	}
+
 	arena := g.arenaPool.Get()
 	b, err := m.marshalJSON(arena)
 	arena.Reset()
@@ -796,6 +2139,16 @@ func (g *Gateway) render(ctx *fasthttp.RequestCtx, m marshalableJSON) {
 	ctx.Response.SetBody(b)
 }
 
+// contentTypeProto is the media type of responses rendered as their protobuf
+// definitions, as published under ledgerpb.
+const contentTypeProto = "application/x-protobuf"
+
+// acceptsProto returns whether the client accepts responses rendered as
+// their protobuf definitions.
+func acceptsProto(ctx *fasthttp.RequestCtx) bool {
+	return bytes.Contains(ctx.Request.Header.Peek("Accept"), []byte(contentTypeProto))
+}
+
 func (g *Gateway) renderError(ctx *fasthttp.RequestCtx, e *errResponse) {
 	arena := g.arenaPool.Get()
 	b := e.marshalJSON(arena)
@@ -24,7 +24,10 @@ package api
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
@@ -34,6 +37,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -44,17 +50,30 @@ import (
 	"github.com/perlin-network/noise/cipher"
 	"github.com/perlin-network/noise/edwards25519"
 	"github.com/perlin-network/noise/handshake"
+	"github.com/perlin-network/wavelet/avl"
 	"github.com/perlin-network/wavelet/conf"
 
 	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/perlin-network/noise/skademlia"
 	"github.com/perlin-network/wavelet"
+	apigrpc "github.com/perlin-network/wavelet/api/grpc"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/security"
 	"github.com/perlin-network/wavelet/store"
 	"github.com/perlin-network/wavelet/sys"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fastjson"
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestListTransaction(t *testing.T) {
@@ -158,6 +177,76 @@ func TestListTransaction(t *testing.T) {
 	}
 }
 
+func TestListTransactionPages(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	for i := uint64(0); i < 6; i++ {
+		tag := sys.TagTransfer
+		if i%2 == 1 {
+			tag = sys.TagStake
+		}
+
+		gateway.ledger.AddTransaction(newTransaction(keys, tag, i, 3-i/2, []byte{byte(i)}))
+	}
+
+	list := func(query string) []string {
+		request := httptest.NewRequest("GET", "http://localhost/tx?"+query, nil)
+
+		w, err := serve(gateway.router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return nil
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.StatusCode, string(response))
+
+		var txs []struct {
+			Height uint64 `json:"height"`
+			Tag    int    `json:"tag"`
+		}
+
+		assert.NoError(t, json.Unmarshal(response, &txs))
+
+		var res []string
+		for _, tx := range txs {
+			res = append(res, fmt.Sprintf("%d/%d", tx.Height, tx.Tag))
+		}
+
+		return res
+	}
+
+	// Transactions are ordered by height, such that they may be paged through.
+	all := list("")
+	assert.Len(t, all, 6)
+
+	heights := make([]string, 0, len(all))
+	for _, tx := range all {
+		heights = append(heights, tx[:1])
+	}
+
+	assert.Equal(t, []string{"1", "1", "2", "2", "3", "3"}, heights)
+
+	assert.Equal(t, all[:4], append(list("limit=2"), list("limit=2&offset=2")...))
+	assert.Equal(t, all[4:], list("limit=2&offset=4"))
+	assert.Empty(t, list("limit=2&offset=6"))
+
+	// Transactions are filtered by tag and height.
+	assert.Equal(t, []string{"1/3", "2/3", "3/3"}, list(fmt.Sprintf("tag=%d", sys.TagStake)))
+	assert.Equal(t, all[2:4], list("from_height=2&to_height=2"))
+	assert.Equal(t, []string{"2/1", "3/1"}, list(fmt.Sprintf("tag=%d&from_height=2", sys.TagTransfer)))
+}
+
 func TestGetTransaction(t *testing.T) {
 	gateway := New()
 	gateway.setup()
@@ -237,6 +326,97 @@ func TestGetTransaction(t *testing.T) {
 	}
 }
 
+func TestMempool(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	tx := newTransaction(keys, sys.TagTransfer, 0, 0, []byte{1, 2, 3})
+	gateway.ledger.AddTransaction(tx)
+
+	listed, err := (&transaction{tx: &tx, status: statusReceived}).marshalJSON(new(fastjson.Arena))
+	assert.NoError(t, err)
+
+	missing := "3132333435363738393031323334353637383930313233343536373839303132"
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "list",
+			url:      "/mempool",
+			wantCode: http.StatusOK,
+			wantBody: "[" + string(listed) + "]",
+		},
+		{
+			name:     "list past the end",
+			url:      "/mempool?offset=1",
+			wantCode: http.StatusOK,
+			wantBody: "[]",
+		},
+		{
+			name:     "invalid limit",
+			url:      "/mempool?limit=-1",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "count",
+			url:      "/mempool/count",
+			wantCode: http.StatusOK,
+			wantBody: `{"count":1,"missing":0}`,
+		},
+		{
+			name:     "contains",
+			url:      "/mempool/contains/" + hex.EncodeToString(tx.ID[:]),
+			wantCode: http.StatusOK,
+			wantBody: fmt.Sprintf(`{"id":"%x","contains":true}`, tx.ID),
+		},
+		{
+			name:     "does not contain",
+			url:      "/mempool/contains/" + missing,
+			wantCode: http.StatusOK,
+			wantBody: fmt.Sprintf(`{"id":"%s","contains":false}`, missing),
+		},
+		{
+			name:     "invalid id length",
+			url:      "/mempool/contains/3132",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "http://localhost"+tc.url, nil)
+
+			w, err := serve(gateway.router, request)
+			if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+				return
+			}
+
+			defer func() {
+				_ = w.Body.Close()
+			}()
+
+			response, err := ioutil.ReadAll(w.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.wantCode, w.StatusCode, "status code")
+
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, string(bytes.TrimSpace(response)))
+			}
+		})
+	}
+}
+
 func TestSendTransaction(t *testing.T) {
 	gateway := New()
 	gateway.setup()
@@ -315,6 +495,297 @@ func TestSendTransactionRandom(t *testing.T) {
 	}
 }
 
+func TestRelayTransaction(t *testing.T) {
+	defer wavelet.ScheduleFeatures(sys.FeatureData, sys.FeatureCanonicalTransactions, sys.FeatureStamps)()
+
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	sender, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	relayer, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	// Stamped transactions pay no fee, such that the sender needs no balance.
+	tx, err := wavelet.NewStampedTransaction(
+		security.NewEd25519Signer(sender.PrivateKey()), 1, 0, sys.TagData, []byte("hello"), sys.MinStampDifficulty,
+	)
+	assert.NoError(t, err)
+
+	body := func(signature wavelet.Signature) []byte {
+		return []byte(fmt.Sprintf(
+			`{"sender":"%x","nonce":%d,"block":%d,"tag":%d,"payload":"%x","version":%d,"stamp":%d,"signature":"%x"}`,
+			tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Version, tx.Stamp, signature,
+		))
+	}
+
+	relay := func(body []byte, signer *skademlia.Keypair) int {
+		request := httptest.NewRequest("POST", "http://localhost/tx/relay", bytes.NewReader(body))
+
+		if signer != nil {
+			signature, err := canonical.SignJSON(security.NewEd25519Signer(signer.PrivateKey()), canonical.DomainRelay, body)
+			assert.NoError(t, err)
+
+			publicKey := signer.PublicKey()
+
+			request.Header.Set(canonical.HeaderPublicKey, hex.EncodeToString(publicKey[:]))
+			request.Header.Set(canonical.HeaderSignature, hex.EncodeToString(signature))
+		}
+
+		w, err := serve(gateway.router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0
+		}
+
+		_ = w.Body.Close()
+
+		return w.StatusCode
+	}
+
+	stats := func(id wavelet.AccountID) (int, string) {
+		request := httptest.NewRequest("GET", "http://localhost/relayer/"+hex.EncodeToString(id[:]), nil)
+
+		w, err := serve(gateway.router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, ""
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, string(response)
+	}
+
+	// Relayed requests must be signed by their relayer.
+	assert.Equal(t, http.StatusUnauthorized, relay(body(tx.Signature), nil))
+
+	code, _ := stats(relayer.PublicKey())
+	assert.Equal(t, http.StatusNotFound, code)
+
+	assert.Equal(t, http.StatusOK, relay(body(tx.Signature), relayer))
+	assert.True(t, gateway.ledger.Transactions().Has(tx.ID))
+
+	// Transactions must still be signed by their sender.
+	assert.Equal(t, http.StatusBadRequest, relay(body(wavelet.Signature{}), relayer))
+
+	code, response := stats(relayer.PublicKey())
+	assert.Equal(t, http.StatusOK, code)
+
+	publicKey := relayer.PublicKey()
+
+	assert.NoError(t, compareJSON([]byte(fmt.Sprintf(
+		`{"id":"%x","submitted":2,"accepted":1,"rejected":1,"fees":0}`, publicKey,
+	)), []byte(response)))
+}
+
+func TestEstimateFee(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	// The sender is funded by the genesis of the ledger.
+	sender := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+	var recipient wavelet.AccountID
+	recipient[0] = 1
+
+	estimate := func(amount uint64) (int, string) {
+		payload, err := wavelet.Transfer{Recipient: recipient, Amount: amount}.Marshal()
+		assert.NoError(t, err)
+
+		body := fmt.Sprintf(`{"sender":"%s","tag":%d,"payload":"%x"}`, sender, sys.TagTransfer, payload)
+		request := httptest.NewRequest("POST", "http://localhost/tx/estimate", strings.NewReader(body))
+
+		w, err := serve(gateway.router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, ""
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, string(response)
+	}
+
+	code, response := estimate(1000)
+	assert.Equal(t, http.StatusOK, code)
+	assert.NoError(t, compareJSON([]byte(fmt.Sprintf(`{"fee":%d,"gas":0}`, sys.DefaultTransactionFee)), []byte(response)))
+
+	code, response = estimate(10000000000000000000)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, response, `"code":"insufficient_balance"`)
+}
+
+func TestGRPC(t *testing.T) {
+	defer wavelet.ScheduleFeatures(sys.FeatureData, sys.FeatureCanonicalTransactions, sys.FeatureStamps)()
+
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	ln := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer()
+	apigrpc.RegisterWaveletServer(server, &grpcServer{g: gateway})
+
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufconn", grpc.WithInsecure(), grpc.WithContextDialer(
+		func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	client := apigrpc.NewWaveletClient(conn)
+	ctx := context.Background()
+
+	t.Run("account", func(t *testing.T) {
+		// The account is funded by the genesis of the ledger.
+		var id wavelet.AccountID
+		_, err := hex.Decode(id[:], []byte("400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"))
+		assert.NoError(t, err)
+
+		balance, _ := wavelet.ReadAccountBalance(gateway.ledger.Snapshot(), id)
+		assert.NotZero(t, balance)
+
+		acc, err := client.GetAccount(ctx, &apigrpc.GetAccountRequest{Id: id[:]})
+		if assert.NoError(t, err) {
+			assert.Equal(t, id[:], acc.Id)
+			assert.Equal(t, balance, acc.Balance)
+		}
+
+		_, err = client.GetAccount(ctx, &apigrpc.GetAccountRequest{Id: id[:16]})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.GetAccount(ctx, &apigrpc.GetAccountRequest{Name: "alice"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("ledger", func(t *testing.T) {
+		res, err := client.GetLedger(ctx, &empty.Empty{})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		block := gateway.ledger.Blocks().Latest()
+		assert.Equal(t, block.ID[:], res.Block.Id)
+		assert.Equal(t, block.Index, res.Block.Index)
+		assert.Equal(t, block.Merkle[:], res.Block.Merkle)
+		assert.Equal(t, uint64(gateway.ledger.StampDifficulty()), res.StampDifficulty)
+	})
+
+	t.Run("send", func(t *testing.T) {
+		sender, err := skademlia.NewKeys(1, 1)
+		assert.NoError(t, err)
+
+		// Stamped transactions pay no fee, such that the sender needs no balance.
+		tx, err := wavelet.NewStampedTransaction(
+			security.NewEd25519Signer(sender.PrivateKey()), 1, 0, sys.TagData, []byte("hello"), sys.MinStampDifficulty,
+		)
+		assert.NoError(t, err)
+
+		res, err := client.SendTransaction(ctx, tx.Proto())
+		if assert.NoError(t, err) {
+			assert.Equal(t, tx.ID[:], res.Id)
+			assert.True(t, gateway.ledger.Transactions().Has(tx.ID))
+		}
+
+		invalid := tx.Proto()
+		invalid.Signature = make([]byte, wavelet.SizeSignature)
+
+		_, err = client.SendTransaction(ctx, invalid)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		invalid.Sender = nil
+
+		_, err = client.SendTransaction(ctx, invalid)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("events", func(t *testing.T) {
+		_, err := streamFirstEvent(ctx, client, &apigrpc.StreamEventsRequest{Mod: "unknown"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = streamFirstEvent(ctx, client, &apigrpc.StreamEventsRequest{
+			Mod: "tx", Filters: map[string]string{"unknown": "1"},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		txID := wavelet.TransactionID{7}
+		sender := wavelet.AccountID{4, 5, 6}
+
+		applied := func(sender wavelet.AccountID) []byte {
+			return []byte(fmt.Sprintf(
+				`{"mod":"tx","event":"applied","tx_id":"%x","sender_id":"%x","tag":1,"time":"%s"}`,
+				txID, sender, time.Unix(1, 0).Format(time.RFC3339),
+			))
+		}
+
+		// Events are logged until the stream has subscribed to them. Events
+		// of other senders, and events without protobuf definitions are not
+		// streamed.
+		stop := make(chan struct{})
+		defer close(stop)
+
+		go func() {
+			for {
+				_, _ = gateway.Write([]byte(`{"mod":"tx","event":"annotated"}`))
+				_, _ = gateway.Write(applied(wavelet.AccountID{8}))
+				_, _ = gateway.Write(applied(sender))
+
+				select {
+				case <-stop:
+					return
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+		}()
+
+		ev, err := streamFirstEvent(ctx, client, &apigrpc.StreamEventsRequest{
+			Mod: "tx", Filters: map[string]string{"sender": hex.EncodeToString(sender[:])},
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, &ledgerpb.TransactionApplied{
+				Id: txID[:], SenderId: sender[:], Tag: 1, Time: int64(time.Second),
+			}, ev.GetTransactionApplied())
+		}
+	})
+}
+
+func streamFirstEvent(
+	ctx context.Context, client apigrpc.WaveletClient, req *apigrpc.StreamEventsRequest,
+) (*ledgerpb.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamEvents(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream.Recv()
+}
+
 // Test POST APIs with completely random payload
 func TestPostPayloadRandom(t *testing.T) {
 	gateway := New()
@@ -414,6 +885,26 @@ func TestGetAccount(t *testing.T) {
 			wantCode:     http.StatusOK,
 			wantResponse: &account{ledger: gateway.ledger, id: id},
 		},
+		{
+			name:         "round of latest block",
+			url:          "/accounts/" + idHex + "?round=0",
+			wantCode:     http.StatusOK,
+			wantResponse: &account{ledger: gateway.ledger, id: id},
+		},
+		{
+			name:     "round not kept",
+			url:      "/accounts/" + idHex + "?round=5",
+			wantCode: http.StatusNotFound,
+			wantResponse: testErrResponse{
+				StatusText: "Not Found",
+				ErrorText:  "state of block 5 is not kept, only that of block 0",
+			},
+		},
+		{
+			name:     "round not a number",
+			url:      "/accounts/" + idHex + "?round=x",
+			wantCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range tests { // nolint:dupl
@@ -444,30 +935,1205 @@ func TestGetAccount(t *testing.T) {
 	}
 }
 
-func TestGetContractCode(t *testing.T) {
+func TestGetAccountProto(t *testing.T) {
 	gateway := New()
 	gateway.setup()
 
 	gateway.ledger = createLedger(t)
 
-	idHex := "1c331c1d1c331c1d1c331c1d1c331c1d1c331c1d1c331c1d1c331c1d1c331c1d"
-	idBytes, err := hex.DecodeString(idHex)
-	assert.NoError(t, err)
+	id := wavelet.AccountID{1, 2, 3}
 
-	var id32 wavelet.AccountID
-	copy(id32[:], idBytes)
+	request := httptest.NewRequest("GET", "http://localhost/accounts/"+hex.EncodeToString(id[:]), nil)
+	request.Header.Set("Accept", "application/x-protobuf")
 
-	s := gateway.ledger.Snapshot()
-	wavelet.WriteAccountContractCode(s, id32, []byte("contract code"))
+	w, err := serve(gateway.router, request)
+	if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+		return
+	}
 
-	tests := []struct {
-		name      string
-		url       string
-		wantCode  int
-		wantError marshalableJSON
-	}{
-		{
-			name:     "missing id",
+	defer func() {
+		_ = w.Body.Close()
+	}()
+
+	response, err := ioutil.ReadAll(w.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, w.StatusCode)
+	assert.Equal(t, "application/x-protobuf", w.Header.Get("Content-Type"))
+
+	var pb ledgerpb.Account
+	if assert.NoError(t, pb.Unmarshal(response)) {
+		assert.Equal(t, ledgerpb.Account{Id: id[:]}, pb)
+	}
+}
+
+func TestGetAccountProof(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	idHex := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+	get := func(url string) (int, []byte) {
+		w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost"+url, nil))
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, nil
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, response
+	}
+
+	code, response := get("/accounts/" + idHex + "/proof")
+	if !assert.Equal(t, http.StatusOK, code, string(response)) {
+		return
+	}
+
+	v, err := fastjson.ParseBytes(response)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	block := gateway.ledger.Blocks().Latest()
+
+	assert.Equal(t, idHex, string(v.GetStringBytes("id")))
+	assert.Equal(t, hex.EncodeToString(block.Merkle[:]), string(v.GetStringBytes("block", "merkle_root")))
+	assert.Equal(t, block.Index, v.GetUint64("block", "height"))
+
+	proof := wavelet.AccountProof{}
+	_, err = hex.Decode(proof.ID[:], []byte(idHex))
+	assert.NoError(t, err)
+
+	for key, dst := range map[string]*avl.Proof{
+		"balance":     &proof.Balance,
+		"stake":       &proof.Stake,
+		"reward":      &proof.Reward,
+		"gas_balance": &proof.GasBalance,
+	} {
+		for _, item := range v.GetArray(key, "path") {
+			buf, err := hex.DecodeString(string(item.GetStringBytes()))
+			assert.NoError(t, err)
+
+			dst.Path = append(dst.Path, buf)
+		}
+
+		for _, item := range v.GetArray(key, "lefts") {
+			buf, err := hex.DecodeString(string(item.GetStringBytes()))
+			assert.NoError(t, err)
+
+			if len(buf) == 0 {
+				buf = nil
+			}
+
+			dst.Lefts = append(dst.Lefts, buf)
+		}
+	}
+
+	balances, err := proof.Verify(block.Merkle)
+	assert.NoError(t, err)
+	assert.Equal(t, wavelet.AccountBalances{Balance: 10000000000000000000, Reward: 5000000}, balances)
+
+	code, _ = get("/accounts/" + idHex + "/proof?block=0")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, response = get("/accounts/" + idHex + "/proof?block=3")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, `{"status":"Not Found","error":"state of block 3 is not kept, only that of block 0"}`, string(response))
+
+	code, _ = get("/accounts/" + idHex + "/proof?block=x")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = get("/accounts/nobody/proof")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestGetAccountDelegations(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	idHex := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+	get := func(url string) (int, []byte) {
+		w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost"+url, nil))
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, nil
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, response
+	}
+
+	code, response := get("/accounts/" + idHex + "/delegations")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t,
+		`{"public_key":"`+idHex+`","commission":0,"delegated":0,"delegators":[],"delegations":[],"unbonding":[]}`,
+		string(response),
+	)
+
+	code, _ = get("/accounts/nobody/delegations")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestGetAccountSlashings(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	idHex := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+	get := func(url string) (int, []byte) {
+		w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost"+url, nil))
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, nil
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, response
+	}
+
+	code, response := get("/accounts/" + idHex + "/slashings")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t,
+		`{"public_key":"`+idHex+`","last_active":null,"slashed":0,"events":[]}`,
+		string(response),
+	)
+
+	code, _ = get("/accounts/nobody/slashings")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestListValidators(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+	gateway.keys = keys
+
+	// Stakes are set through the genesis, as writes to snapshots of the
+	// ledger are not committed.
+	validator, staker, self := wavelet.AccountID{1}, wavelet.AccountID{2}, keys.PublicKey()
+
+	genesis := fmt.Sprintf(`{"%x": {"stake": %d}, "%x": {"stake": %d}, "%x": {"stake": %d}}`,
+		validator, sys.MinimumStake, staker, sys.MinimumStake-1, self, sys.MinimumStake*2,
+	)
+
+	gateway.ledger, err = wavelet.NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys), wavelet.WithGenesis(&genesis))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost/validators", nil))
+	if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+		return
+	}
+
+	defer func() {
+		_ = w.Body.Close()
+	}()
+
+	response, err := ioutil.ReadAll(w.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.StatusCode)
+
+	v, err := fastjson.ParseBytes(response)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.EqualValues(t, 0, v.GetUint64("block"))
+	assert.Equal(t, sys.MinimumStake*3, v.GetUint64("total_stake"))
+
+	// The account staking less than the minimum stake is not a validator.
+	validators := make(map[string]*fastjson.Value)
+	for _, v := range v.GetArray("validators") {
+		validators[string(v.GetStringBytes("public_key"))] = v
+	}
+
+	if !assert.Len(t, validators, 2) {
+		return
+	}
+
+	v = validators[hex.EncodeToString(validator[:])]
+	if assert.NotNil(t, v) {
+		assert.Equal(t, sys.MinimumStake, v.GetUint64("stake"))
+		assert.EqualValues(t, 0, v.GetUint64("delegated"))
+		assert.Equal(t, fastjson.TypeNull, v.Get("last_active").Type())
+		assert.False(t, v.GetBool("connected"))
+		assert.EqualValues(t, 0, v.GetUint64("uptime"))
+	}
+
+	// The node is always connected to itself.
+	v = validators[hex.EncodeToString(self[:])]
+	if assert.NotNil(t, v) {
+		assert.Equal(t, sys.MinimumStake*2, v.GetUint64("stake"))
+		assert.True(t, v.GetBool("connected"))
+	}
+}
+
+func TestListPeerScores(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	gateway.ledger, err = wavelet.NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	id := wavelet.AccountID{1}
+
+	peer := skademlia.NewID("127.0.0.1:3000", id, [blake2b.Size256]byte{})
+	gateway.ledger.Reputation().Report(peer, wavelet.ViolationInvalidTx)
+
+	w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost/node/peers", nil))
+	if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+		return
+	}
+
+	defer func() {
+		_ = w.Body.Close()
+	}()
+
+	response, err := ioutil.ReadAll(w.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.StatusCode)
+
+	v, err := fastjson.ParseBytes(response)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.EqualValues(t, -100, v.GetFloat64("ban_score"))
+	assert.Equal(t, "10m0s", string(v.GetStringBytes("ban_duration")))
+	assert.EqualValues(t, 500, v.GetUint64("max_requests_per_second"))
+
+	peers := v.GetArray("peers")
+	if !assert.Len(t, peers, 1) {
+		return
+	}
+
+	assert.Equal(t, hex.EncodeToString(id[:]), string(peers[0].GetStringBytes("public_key")))
+	assert.Equal(t, "127.0.0.1:3000", string(peers[0].GetStringBytes("address")))
+	assert.False(t, peers[0].GetBool("connected"))
+	assert.InDelta(t, -5, peers[0].GetFloat64("score"), 0.1)
+	assert.EqualValues(t, 1, peers[0].GetUint64("violations", "invalid_tx"))
+	assert.EqualValues(t, 0, peers[0].GetUint64("violations", "invalid_block"))
+	assert.Equal(t, fastjson.TypeNull, peers[0].Get("banned_until").Type())
+}
+
+func TestBanPeer(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	gateway.ledger, err = wavelet.NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	currentSecret := conf.GetSecret()
+	defer conf.Update(conf.WithSecret(currentSecret))
+	conf.Update(conf.WithSecret("secret"))
+
+	id := wavelet.AccountID{1}
+	path := "http://localhost/node/peers/" + hex.EncodeToString(id[:]) + "/ban"
+
+	request := func(method, path, body string, auth bool) int {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+
+		if auth {
+			request.Header.Set("Authorization", "Bearer secret")
+		}
+
+		w, err := serve(gateway.router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0
+		}
+
+		_ = w.Body.Close()
+
+		return w.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodPost, path, "", false))
+	assert.False(t, gateway.ledger.Reputation().Banned(id))
+
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "http://localhost/node/peers/01/ban", "", true))
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, path, `{"duration":"-1h"}`, true))
+	assert.False(t, gateway.ledger.Reputation().Banned(id))
+
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, path, `{"duration":"1h"}`, true))
+	assert.True(t, gateway.ledger.Reputation().Banned(id))
+
+	assert.Equal(t, http.StatusOK, request(http.MethodDelete, path, "", true))
+	assert.False(t, gateway.ledger.Reputation().Banned(id))
+
+	// Peers which are not banned may not be unbanned.
+	assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, path, "", true))
+
+	// Pruning may only be triggered should it be enabled.
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "http://localhost/node/prune", "", true))
+
+	gateway.ledger, err = wavelet.NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys),
+		wavelet.WithPruning(16, 0))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "http://localhost/node/prune", "", true))
+}
+
+func TestUpdateSnowball(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	currentSecret := conf.GetSecret()
+	defer conf.Update(conf.WithSecret(currentSecret))
+	conf.Update(conf.WithSecret("secret"))
+
+	k, alpha, beta := conf.GetSnowballK(), conf.GetSnowballAlpha(), conf.GetSnowballBeta()
+	defer conf.Update(conf.WithSnowballK(k), conf.WithSnowballAlpha(alpha), conf.WithSnowballBeta(beta))
+
+	post := func(body string, auth bool) (int, string) {
+		request := httptest.NewRequest(http.MethodPost, "http://localhost/node/snowball", strings.NewReader(body))
+
+		if auth {
+			request.Header.Set("Authorization", "Bearer secret")
+		}
+
+		w, err := serve(gateway.router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, ""
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, string(response)
+	}
+
+	code, _ := post(`{"k":10}`, false)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, k, conf.GetSnowballK())
+
+	// Parameters left out are kept as they are.
+	code, response := post(`{"k":10,"alpha":0.9}`, true)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, fmt.Sprintf(`{"k":10,"alpha":0.9,"beta":%d}`, beta), response)
+
+	assert.Equal(t, 10, conf.GetSnowballK())
+	assert.Equal(t, 0.9, conf.GetSnowballAlpha())
+	assert.Equal(t, beta, conf.GetSnowballBeta())
+
+	code, response = post(`{"beta":0}`, true)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, response, "snowball beta must be between")
+	assert.Equal(t, beta, conf.GetSnowballBeta())
+
+	code, response = post(`{"alpha":"high"}`, true)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, response, "alpha must be a number")
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	gateway := New()
+	gateway.ledger = createLedger(t)
+	gateway.setup()
+
+	get := func(path string) (int, *fastjson.Value) {
+		w, err := serve(gateway.router, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, nil
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		v, err := fastjson.ParseBytes(response)
+		assert.NoError(t, err)
+
+		return w.StatusCode, v
+	}
+
+	// Liveness only checks the database and consensus.
+	code, v := get("/healthz")
+	if assert.Equal(t, http.StatusOK, code) {
+		assert.Equal(t, "ok", string(v.GetStringBytes("status")))
+		assert.True(t, v.GetBool("checks", "storage", "ok"))
+		assert.True(t, v.GetBool("checks", "consensus", "ok"))
+		assert.Nil(t, v.Get("checks", "peers"))
+	}
+
+	// Nodes without peers are not ready.
+	code, v = get("/readyz")
+	if assert.Equal(t, http.StatusServiceUnavailable, code) {
+		assert.Equal(t, "unavailable", string(v.GetStringBytes("status")))
+		assert.False(t, v.GetBool("checks", "peers", "ok"))
+		assert.Equal(t, 0, v.GetInt("checks", "peers", "num_peers"))
+		assert.Equal(t, 1, v.GetInt("checks", "peers", "min_peers"))
+		assert.False(t, v.GetBool("checks", "sync", "reconciled"))
+	}
+
+	gateway.SetHealthThresholds(wavelet.HealthThresholds{MaxStall: time.Minute})
+
+	code, v = get("/readyz")
+	if assert.Equal(t, http.StatusOK, code) {
+		assert.Equal(t, "ok", string(v.GetStringBytes("status")))
+		assert.Equal(t, "1m0s", string(v.GetStringBytes("checks", "consensus", "max_stall")))
+	}
+}
+
+func TestUpdateLogLevels(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	currentSecret := conf.GetSecret()
+	defer conf.Update(conf.WithSecret(currentSecret))
+	conf.Update(conf.WithSecret("secret"))
+
+	levels := log.Levels()
+	defer func() {
+		for module, level := range levels {
+			_ = log.SetModuleLevel(module, level.String())
+		}
+	}()
+
+	send := func(method, body string) (int, *fastjson.Value) {
+		request := httptest.NewRequest(method, "http://localhost/node/loglevels", strings.NewReader(body))
+		request.Header.Set("Authorization", "Bearer secret")
+
+		w, err := serve(gateway.router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, nil
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		v, err := fastjson.ParseBytes(response)
+		assert.NoError(t, err)
+
+		return w.StatusCode, v
+	}
+
+	code, v := send(http.MethodGet, "")
+	if assert.Equal(t, http.StatusOK, code) {
+		assert.Equal(t, levels[log.ModuleTX].String(), string(v.GetStringBytes(log.ModuleTX)))
+	}
+
+	code, v = send(http.MethodPost, `{"tx":"warn","sync":"error"}`)
+	if assert.Equal(t, http.StatusOK, code) {
+		assert.Equal(t, "warn", string(v.GetStringBytes(log.ModuleTX)))
+		assert.Equal(t, "error", string(v.GetStringBytes(log.ModuleSync)))
+		assert.Equal(t, levels[log.ModuleNode].String(), string(v.GetStringBytes(log.ModuleNode)))
+	}
+
+	// No level is changed should any of them be invalid.
+	code, v = send(http.MethodPost, `{"tx":"info","nope":"info"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, string(v.GetStringBytes("error")), "unknown module")
+	assert.Equal(t, "warn", log.Level(log.ModuleTX).String())
+
+	code, _ = send(http.MethodPost, `{"tx":"loud"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestAdminAPI(t *testing.T) {
+	gateway := New()
+
+	assert.Equal(t, errAdminNoAuth, gateway.EnableAdmin(AdminConfig{Addr: "127.0.0.1:0"}))
+	assert.Equal(t, errAdminClientCANoTLS, gateway.EnableAdmin(AdminConfig{
+		Addr: "127.0.0.1:0", ClientCAFile: "ca.pem",
+	}))
+	assert.Nil(t, gateway.admin)
+
+	if !assert.NoError(t, gateway.EnableAdmin(AdminConfig{Addr: "127.0.0.1:0", Token: "admin"})) {
+		return
+	}
+
+	gateway.setup()
+
+	currentSecret := conf.GetSecret()
+	defer conf.Update(conf.WithSecret(currentSecret))
+	conf.Update(conf.WithSecret("secret"))
+
+	get := func(router *fasthttprouter.Router, path, token string) int {
+		request := httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		w, err := serve(router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0
+		}
+
+		_ = w.Body.Close()
+
+		return w.StatusCode
+	}
+
+	// Privileged routes are no longer served by the public API, even to
+	// requests carrying the secret of the node.
+	assert.Equal(t, http.StatusNotFound, get(gateway.router, "/node/loglevels", "secret"))
+	assert.Equal(t, http.StatusNotFound, get(gateway.router, "/webhooks", "secret"))
+	assert.Equal(t, http.StatusNotFound, get(gateway.router, "/debug/pprof/", ""))
+
+	// The admin API only serves them to requests carrying its token.
+	assert.Equal(t, http.StatusUnauthorized, get(gateway.admin.router, "/node/loglevels", ""))
+	assert.Equal(t, http.StatusUnauthorized, get(gateway.admin.router, "/node/loglevels", "secret"))
+	assert.Equal(t, http.StatusUnauthorized, get(gateway.admin.router, "/debug/pprof/", ""))
+	assert.Equal(t, http.StatusOK, get(gateway.admin.router, "/node/loglevels", "admin"))
+	assert.Equal(t, http.StatusOK, get(gateway.admin.router, "/webhooks", "admin"))
+
+	// Public routes are not served by the admin API.
+	assert.Equal(t, http.StatusNotFound, get(gateway.admin.router, "/ledger", "admin"))
+}
+
+func TestRounds(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Rounds are stored directly, as the ledger finalizes no blocks here.
+	kv := store.NewInmem()
+
+	gateway.ledger, err = wavelet.NewLedger(kv, skademlia.NewClient(":0", keys))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	start := time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	for index := uint64(1); index <= 3; index++ {
+		block := wavelet.NewBlock(index, wavelet.MerkleNodeID{byte(index)}, wavelet.TransactionID{byte(index)})
+		startedAt := start.Add(time.Duration(index) * time.Second)
+
+		round := wavelet.NewRound(block, 1, 0, startedAt, startedAt.Add(250*time.Millisecond))
+		assert.NoError(t, wavelet.StoreRound(kv, round))
+	}
+
+	get := func(url string) (int, []byte) {
+		w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost"+url, nil))
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, nil
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, response
+	}
+
+	code, response := get("/rounds/2")
+	assert.Equal(t, http.StatusOK, code)
+
+	block := wavelet.NewBlock(2, wavelet.MerkleNodeID{2}, wavelet.TransactionID{2})
+	id := wavelet.TransactionID{2}
+
+	assert.Equal(t, fmt.Sprintf(
+		`{"index":2,"id":"%x","merkle_root":"%x","start_id":"%x","end_id":"%x","num_tx":1,"num_applied_tx":1,`+
+			`"num_rejected_tx":0,"started_at":"2019-10-15T00:00:02Z","finalized_at":"2019-10-15T00:00:02.25Z",`+
+			`"duration":"250ms"}`,
+		block.ID, block.Merkle, id, id,
+	), string(response))
+
+	code, _ = get("/rounds/4")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = get("/rounds/latest")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Rounds are listed going back from the given block.
+	code, response = get("/rounds?from=3&limit=2")
+	assert.Equal(t, http.StatusOK, code)
+
+	v, err := fastjson.ParseBytes(response)
+	if assert.NoError(t, err) {
+		rounds := v.GetArray()
+		if assert.Len(t, rounds, 2) {
+			assert.EqualValues(t, 3, rounds[0].GetUint64("index"))
+			assert.EqualValues(t, 2, rounds[1].GetUint64("index"))
+		}
+	}
+
+	// No round is kept of the genesis block, which is the latest block.
+	code, response = get("/rounds")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "[]", string(response))
+}
+
+func TestGetContractStorage(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	idHex := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+	get := func(url string) (int, []byte) {
+		w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost"+url, nil))
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, nil
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, response
+	}
+
+	code, _ := get("/contract/" + idHex + "/storage/6b6579")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// Keys which are not set are proven to be unset.
+	code, response := get("/contract/" + idHex + "/storage/6b6579?proof=true")
+	if !assert.Equal(t, http.StatusOK, code, string(response)) {
+		return
+	}
+
+	v, err := fastjson.ParseBytes(response)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	block := gateway.ledger.Blocks().Latest()
+
+	assert.Equal(t, idHex, string(v.GetStringBytes("contract_id")))
+	assert.Equal(t, "6b6579", string(v.GetStringBytes("key")))
+	assert.False(t, v.GetBool("exists"))
+	assert.Equal(t, hex.EncodeToString(block.Merkle[:]), string(v.GetStringBytes("block", "merkle_root")))
+
+	proof := wavelet.ContractStorageProof{Key: []byte("key")}
+	_, err = hex.Decode(proof.ID[:], []byte(idHex))
+	assert.NoError(t, err)
+
+	for _, item := range v.GetArray("proof", "path") {
+		buf, err := hex.DecodeString(string(item.GetStringBytes()))
+		assert.NoError(t, err)
+
+		proof.Proof.Path = append(proof.Proof.Path, buf)
+	}
+
+	for _, item := range v.GetArray("proof", "lefts") {
+		buf, err := hex.DecodeString(string(item.GetStringBytes()))
+		assert.NoError(t, err)
+
+		if len(buf) == 0 {
+			buf = nil
+		}
+
+		proof.Proof.Lefts = append(proof.Proof.Lefts, buf)
+	}
+
+	_, exists, err := proof.Verify(block.Merkle)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	code, response = get("/contract/" + idHex + "/storage?prefix=6b&limit=10")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"entries":[]}`, string(response))
+
+	code, _ = get("/contract/" + idHex + "/storage/xyz")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = get("/contract/" + idHex + "/storage?after=xyz")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestGetContractUpgrades(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	get := func(url string) int {
+		w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost"+url, nil))
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0
+		}
+
+		_ = w.Body.Close()
+
+		return w.StatusCode
+	}
+
+	assert.Equal(t, http.StatusNotFound,
+		get("/contract/400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405/upgrades"))
+	assert.Equal(t, http.StatusBadRequest, get("/contract/XYZ/upgrades"))
+}
+
+func TestGetSnapshot(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	w, err := serve(gateway.router, httptest.NewRequest("GET", "http://localhost/snapshot", nil))
+	if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+		return
+	}
+
+	defer func() {
+		_ = w.Body.Close()
+	}()
+
+	if !assert.Equal(t, http.StatusOK, w.StatusCode) {
+		return
+	}
+
+	block := gateway.ledger.Blocks().Latest()
+
+	assert.Equal(t, "0", w.Header.Get("X-Wavelet-Snapshot-Height"))
+	assert.Equal(t, hex.EncodeToString(block.ID[:]), w.Header.Get("X-Wavelet-Snapshot-Block"))
+	assert.Equal(t, hex.EncodeToString(block.Merkle[:]), w.Header.Get("X-Wavelet-Snapshot-Merkle-Root"))
+
+	kv := store.NewInmem()
+
+	imported, err := wavelet.ImportSnapshot(kv, w.Body)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, block.ID, imported.ID)
+
+	height, err := wavelet.SnapshotHeight(kv)
+	assert.NoError(t, err)
+	assert.Equal(t, block.Index, height)
+}
+
+func TestGetName(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	tests := []struct {
+		name      string
+		url       string
+		wantCode  int
+		wantError marshalableJSON
+	}{
+		{
+			name:     "invalid name",
+			url:      "/name/Alice",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "name not registered",
+			url:      "/name/alice",
+			wantCode: http.StatusNotFound,
+			wantError: testErrResponse{
+				StatusText: "Not Found",
+				ErrorText:  `name "alice" is not registered`,
+			},
+		},
+		{
+			name:     "account name not registered",
+			url:      "/accounts/alice",
+			wantCode: http.StatusNotFound,
+			wantError: testErrResponse{
+				StatusText: "Not Found",
+				ErrorText:  `name "alice" is not registered`,
+			},
+		},
+		{
+			name:     "contract name not registered",
+			url:      "/contract/alice",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests { // nolint:dupl
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "http://localhost"+tc.url, nil)
+
+			w, err := serve(gateway.router, request)
+			if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+				return
+			}
+
+			defer func() {
+				_ = w.Body.Close()
+			}()
+
+			response, err := ioutil.ReadAll(w.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.wantCode, w.StatusCode, "status code")
+
+			if tc.wantError != nil {
+				r, err := tc.wantError.marshalJSON(new(fastjson.ArenaPool).Get())
+				assert.Nil(t, err)
+				assert.Equal(t, string(r), string(bytes.TrimSpace(response)))
+			}
+		})
+	}
+}
+
+func TestGetData(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	id := "3132333435363738393031323334353637383930313233343536373839303132"
+
+	tests := []struct {
+		name      string
+		url       string
+		wantCode  int
+		wantError marshalableJSON
+	}{
+		{
+			name:     "id not hex",
+			url:      "/tx/-----/data",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid id length",
+			url:      "/tx/3132/data",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "data not exist",
+			url:      "/tx/" + id + "/data",
+			wantCode: http.StatusNotFound,
+			wantError: testErrResponse{
+				StatusText: "Not Found",
+				ErrorText:  fmt.Sprintf("could not find data anchored by transaction with ID %s", id),
+			},
+		},
+	}
+
+	for _, tc := range tests { // nolint:dupl
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "http://localhost"+tc.url, nil)
+
+			w, err := serve(gateway.router, request)
+			if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+				return
+			}
+
+			defer func() {
+				_ = w.Body.Close()
+			}()
+
+			response, err := ioutil.ReadAll(w.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.wantCode, w.StatusCode, "status code")
+
+			if tc.wantError != nil {
+				r, err := tc.wantError.marshalJSON(new(fastjson.ArenaPool).Get())
+				assert.Nil(t, err)
+				assert.Equal(t, string(r), string(bytes.TrimSpace(response)))
+			}
+		})
+	}
+}
+
+func TestGetTransactionDiff(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	kv := store.NewInmem()
+
+	ledger, err := wavelet.NewLedger(kv, skademlia.NewClient(":0", keys))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = ledger
+
+	diff := wavelet.TransactionDiff{
+		ID: wavelet.TransactionID{1},
+		Accounts: []wavelet.AccountDiff{
+			{
+				ID:      wavelet.AccountID{2},
+				Balance: &wavelet.FieldDiff{Before: 10, After: 3},
+			},
+			{
+				ID:              wavelet.AccountID{3},
+				GasBalance:      &wavelet.FieldDiff{Before: 0, After: 5},
+				ContractCreated: true,
+				ContractPages:   []uint64{0, 2},
+			},
+		},
+	}
+
+	if !assert.NoError(t, wavelet.StoreTransactionDiffs(kv, 1, []wavelet.TransactionDiff{diff})) {
+		return
+	}
+
+	id := "3132333435363738393031323334353637383930313233343536373839303132"
+
+	tests := []struct {
+		name      string
+		url       string
+		wantCode  int
+		wantError marshalableJSON
+		wantBody  string
+	}{
+		{
+			name:     "id not hex",
+			url:      "/tx/-----/diff",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid id length",
+			url:      "/tx/3132/diff",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "diff not exist",
+			url:      "/tx/" + id + "/diff",
+			wantCode: http.StatusNotFound,
+			wantError: testErrResponse{
+				StatusText: "Not Found",
+				ErrorText:  fmt.Sprintf("could not find state diff of transaction with ID %s", id),
+			},
+		},
+		{
+			name:     "success",
+			url:      "/tx/" + hex.EncodeToString(diff.ID[:]) + "/diff",
+			wantCode: http.StatusOK,
+			wantBody: fmt.Sprintf(
+				`{"id":"%x","accounts":[{"id":"%x","balance":{"before":10,"after":3}},`+
+					`{"id":"%x","gas_balance":{"before":0,"after":5},"contract_created":true,"contract_pages":[0,2]}]}`,
+				diff.ID, diff.Accounts[0].ID, diff.Accounts[1].ID,
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "http://localhost"+tc.url, nil)
+
+			w, err := serve(gateway.router, request)
+			if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+				return
+			}
+
+			defer func() {
+				_ = w.Body.Close()
+			}()
+
+			response, err := ioutil.ReadAll(w.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.wantCode, w.StatusCode, "status code")
+
+			if tc.wantError != nil {
+				r, err := tc.wantError.marshalJSON(new(fastjson.ArenaPool).Get())
+				assert.Nil(t, err)
+				assert.Equal(t, string(r), string(bytes.TrimSpace(response)))
+			}
+
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, string(bytes.TrimSpace(response)))
+			}
+		})
+	}
+}
+
+func TestGetContractLogs(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	kv := store.NewInmem()
+
+	ledger, err := wavelet.NewLedger(kv, skademlia.NewClient(":0", keys))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = ledger
+
+	contract := wavelet.AccountID{3}
+
+	logs := []wavelet.ContractLog{
+		{Contract: contract, Block: 1, TxID: wavelet.TransactionID{1}, Topic: []byte("a"), Data: []byte{0x01}},
+		{Contract: contract, Block: 1, TxID: wavelet.TransactionID{1}, Topic: []byte("b"), Data: []byte{0x02}},
+		{Contract: contract, Block: 4, TxID: wavelet.TransactionID{2}, Topic: []byte("c")},
+	}
+
+	if !assert.NoError(t, wavelet.StoreContractLogs(kv, logs)) {
+		return
+	}
+
+	id := hex.EncodeToString(contract[:])
+
+	logJSON := func(i int) string {
+		return fmt.Sprintf(
+			`{"index":%d,"block":%d,"tx_id":"%x","topic":"%x","data":"%x"}`,
+			logs[i].Index, logs[i].Block, logs[i].TxID, logs[i].Topic, logs[i].Data,
+		)
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "id not hex",
+			url:      "/contract/-----/logs",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid offset",
+			url:      "/contract/" + id + "/logs?offset=-1",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "no logs",
+			url:      "/contract/" + hex.EncodeToString(make([]byte, wavelet.SizeAccountID)) + "/logs",
+			wantCode: http.StatusOK,
+			wantBody: `{"total":0,"logs":[]}`,
+		},
+		{
+			name:     "all logs",
+			url:      "/contract/" + id + "/logs",
+			wantCode: http.StatusOK,
+			wantBody: `{"total":3,"logs":[` + logJSON(0) + "," + logJSON(1) + "," + logJSON(2) + `]}`,
+		},
+		{
+			name:     "paginated",
+			url:      "/contract/" + id + "/logs?offset=1&limit=1",
+			wantCode: http.StatusOK,
+			wantBody: `{"total":3,"logs":[` + logJSON(1) + `]}`,
+		},
+		{
+			name:     "from block",
+			url:      "/contract/" + id + "/logs?from_block=2",
+			wantCode: http.StatusOK,
+			wantBody: `{"total":3,"logs":[` + logJSON(2) + `]}`,
+		},
+		{
+			name:     "from block past the last log",
+			url:      "/contract/" + id + "/logs?from_block=5",
+			wantCode: http.StatusOK,
+			wantBody: `{"total":3,"logs":[]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "http://localhost"+tc.url, nil)
+
+			w, err := serve(gateway.router, request)
+			if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+				return
+			}
+
+			defer func() {
+				_ = w.Body.Close()
+			}()
+
+			response, err := ioutil.ReadAll(w.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.wantCode, w.StatusCode, "status code")
+
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, string(bytes.TrimSpace(response)))
+			}
+		})
+	}
+}
+
+func TestGetContractCode(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	idHex := "1c331c1d1c331c1d1c331c1d1c331c1d1c331c1d1c331c1d1c331c1d1c331c1d"
+	idBytes, err := hex.DecodeString(idHex)
+	assert.NoError(t, err)
+
+	var id32 wavelet.AccountID
+	copy(id32[:], idBytes)
+
+	s := gateway.ledger.Snapshot()
+	wavelet.WriteAccountContractCode(s, id32, []byte("contract code"))
+
+	tests := []struct {
+		name      string
+		url       string
+		wantCode  int
+		wantError marshalableJSON
+	}{
+		{
+			name:     "missing id",
 			url:      "/contract/",
 			wantCode: http.StatusNotFound,
 		},
@@ -492,32 +2158,139 @@ func TestGetContractCode(t *testing.T) {
 		},
 	}
 
-	for _, tc := range tests { // nolint:dupl
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			request := httptest.NewRequest("GET", "http://localhost"+tc.url, nil)
+	for _, tc := range tests { // nolint:dupl
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "http://localhost"+tc.url, nil)
+
+			w, err := serve(gateway.router, request)
+			if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+				return
+			}
+
+			defer func() {
+				_ = w.Body.Close()
+			}()
+
+			response, err := ioutil.ReadAll(w.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.wantCode, w.StatusCode, "status code")
+
+			if tc.wantError != nil {
+				r, err := tc.wantError.marshalJSON(new(fastjson.ArenaPool).Get())
+				assert.Nil(t, err)
+				assert.Equal(t, string(r), string(bytes.TrimSpace(response)))
+			}
+		})
+	}
+}
+
+func TestCallContract(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	code, err := ioutil.ReadFile("../testdata/transfer_back.wasm")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Contracts are deployed through the genesis, as writes to snapshots of
+	// the ledger are not committed. The genesis holds the memory of the
+	// contract having been spawned.
+	keys, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	state := avl.New(store.NewInmem())
+	block := wavelet.NewBlock(0, state.Checksum())
+
+	wavelet.WriteAccountBalance(state, keys.PublicKey(), 1000000)
+
+	payload, err := wavelet.Contract{GasLimit: 100000, Code: code}.Marshal()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	spawn := newTransaction(keys, sys.TagContract, 1, block.Index, payload)
+	if !assert.NoError(t, wavelet.ApplyTransaction(state, &block, &spawn)) {
+		return
+	}
+
+	dir, err := ioutil.TempDir("", "wavelet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	contract := hex.EncodeToString(spawn.ID[:])
+	path := filepath.Join(dir, contract)
+
+	assert.NoError(t, ioutil.WriteFile(path+".wasm", code, 0644))
+
+	globals, _ := wavelet.ReadAccountContractGlobals(state, spawn.ID)
+	assert.NoError(t, ioutil.WriteFile(path+".globals.dmp", globals, 0644))
+
+	numPages, _ := wavelet.ReadAccountContractNumPages(state, spawn.ID)
+	for i := uint64(0); i < numPages; i++ {
+		page, _ := wavelet.ReadAccountContractPage(state, spawn.ID, i)
+		assert.NoError(t, ioutil.WriteFile(fmt.Sprintf("%s.%d.dmp", path, i), page, 0644))
+	}
+
+	gateway.ledger, err = wavelet.NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys), wavelet.WithGenesis(&dir))
+	if !assert.NoError(t, err) {
+		return
+	}
 
-			w, err := serve(gateway.router, request)
-			if !assert.NoError(t, err) || !assert.NotNil(t, w) {
-				return
-			}
+	call := func(id, body string) (int, *fastjson.Value) {
+		request := httptest.NewRequest("POST", "http://localhost/contract/"+id+"/call", strings.NewReader(body))
 
-			defer func() {
-				_ = w.Body.Close()
-			}()
+		w, err := serve(gateway.router, request)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, nil
+		}
 
-			response, err := ioutil.ReadAll(w.Body)
-			assert.NoError(t, err)
+		defer func() {
+			_ = w.Body.Close()
+		}()
 
-			assert.Equal(t, tc.wantCode, w.StatusCode, "status code")
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
 
-			if tc.wantError != nil {
-				r, err := tc.wantError.marshalJSON(new(fastjson.ArenaPool).Get())
-				assert.Nil(t, err)
-				assert.Equal(t, string(r), string(bytes.TrimSpace(response)))
-			}
-		})
+		v, err := fastjson.ParseBytes(response)
+		assert.NoError(t, err)
+
+		return w.StatusCode, v
+	}
+
+	status, v := call(contract, `{"func":"on_money_received","amount":1000,"gas_limit":500000}`)
+	if assert.Equal(t, http.StatusOK, status) {
+		assert.True(t, v.GetUint64("gas") > 0)
+		assert.False(t, v.GetBool("gas_limit_exceeded"))
+		assert.Nil(t, v.Get("failure"))
+		assert.EqualValues(t, 1, v.GetUint64("queued"))
+	}
+
+	// Failures of the function are reported, rather than being an error.
+	status, v = call(contract, `{"func":"on_money_received","amount":1000,"gas_limit":1}`)
+	if assert.Equal(t, http.StatusOK, status) {
+		assert.EqualValues(t, 1, v.GetUint64("gas"))
+		assert.True(t, v.GetBool("gas_limit_exceeded"))
+		assert.NotEmpty(t, v.GetStringBytes("failure"))
 	}
+
+	status, _ = call(contract, `{"amount":1000}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = call(contract, `{"func":"missing"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = call(contract, `{"func":"on_money_received","params":"zz"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = call("3132333435363738393031323334353637383930313233343536373839303132", `{"func":"on_money_received"}`)
+	assert.Equal(t, http.StatusNotFound, status)
 }
 
 func TestGetContractPages(t *testing.T) {
@@ -621,12 +2394,157 @@ func TestGetLedger(t *testing.T) {
 	publicKey := keys.PublicKey()
 
 	expectedJSON := fmt.Sprintf(
-		`{"public_key":"%s","address":"127.0.0.1:%d","num_accounts":3,"preferred_votes":0,"block":{"merkle_root":"19be72d52438349e8fa2c4705f1cd954","height":0,"id":"2d301376b242d1dec15ac1d0e5b30c41e11a4ad743f79c59bec204b0e01b36bd","transactions":0},"preferred":null,"num_missing_tx":0,"num_tx":0,"num_tx_in_store":0,"num_accounts_in_store":3,"peers":null}`,
+		`{"public_key":"%s","address":"127.0.0.1:%d","num_accounts":3,"preferred_votes":0,"block":{"merkle_root":"19be72d52438349e8fa2c4705f1cd954","height":0,"id":"2d301376b242d1dec15ac1d0e5b30c41e11a4ad743f79c59bec204b0e01b36bd","transactions":0},"preferred":null,"num_missing_tx":0,"num_tx":0,"num_tx_in_store":0,"num_accounts_in_store":3,"stamp_difficulty":%d,"archival":false,"features":[],"snowball":{"k":%d,"alpha":%g,"beta":%d},"pruning":{"enabled":false,"retained_blocks":0,"retained_from":0,"num_pruned_diffs":0,"compact_interval":"0s","num_compactions":0,"last_compaction_at":null},"peers":null}`,
 		hex.EncodeToString(publicKey[:]),
 		listener.Addr().(*net.TCPAddr).Port,
+		sys.MinStampDifficulty,
+		conf.GetSnowballK(), conf.GetSnowballAlpha(), conf.GetSnowballBeta(),
 	)
 
 	assert.NoError(t, compareJSON([]byte(expectedJSON), response))
+
+	// Only the block and number of accounts are reported as of a round.
+	w, err = serve(gateway.router, httptest.NewRequest("GET", "http://localhost/ledger?round=0", nil))
+	if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+		return
+	}
+
+	response, err = ioutil.ReadAll(w.Body)
+	assert.NoError(t, err)
+	_ = w.Body.Close()
+
+	assert.Equal(t, http.StatusOK, w.StatusCode)
+	assert.NoError(t, compareJSON([]byte(`{"num_accounts":3,"block":{"merkle_root":"19be72d52438349e8fa2c4705f1cd954","height":0,"id":"2d301376b242d1dec15ac1d0e5b30c41e11a4ad743f79c59bec204b0e01b36bd","transactions":0}}`), response))
+
+	w, err = serve(gateway.router, httptest.NewRequest("GET", "http://localhost/ledger?round=1", nil))
+	if assert.NoError(t, err) && assert.NotNil(t, w) {
+		assert.Equal(t, http.StatusNotFound, w.StatusCode)
+		_ = w.Body.Close()
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	request := httptest.NewRequest("GET", "http://localhost/metrics", nil)
+
+	// Metrics are only served once enabled.
+	w, err := serve(gateway.router, request)
+	if assert.NoError(t, err) && assert.NotNil(t, w) {
+		assert.Equal(t, http.StatusNotFound, w.StatusCode)
+		_ = w.Body.Close()
+	}
+
+	gateway = New()
+	gateway.ledger = createLedger(t)
+	gateway.EnableMetrics()
+	gateway.setup()
+
+	w, err = serve(gateway.router, httptest.NewRequest("GET", "http://localhost/time", nil))
+	if assert.NoError(t, err) && assert.NotNil(t, w) {
+		_ = w.Body.Close()
+	}
+
+	w, err = serve(gateway.router, request)
+	if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+		return
+	}
+
+	defer func() {
+		_ = w.Body.Close()
+	}()
+
+	response, err := ioutil.ReadAll(w.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, w.StatusCode)
+	assert.Contains(t, w.Header.Get("Content-Type"), "text/plain")
+
+	// Requests are recorded once served, and hence only the request to /time
+	// has been recorded.
+	assert.Contains(t, string(response), "\nwavelet_api_requests_total 1\n")
+	assert.Contains(t, string(response), "\nwavelet_api_latency_seconds_count 1\n")
+	assert.Contains(t, string(response), "\nwavelet_block_height 0\n")
+	assert.Contains(t, string(response), "\nwavelet_mempool_pending 0\n")
+	assert.Contains(t, string(response), "# TYPE wavelet_consensus_latency_seconds summary\n")
+}
+
+func TestGetTime(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	before := time.Now()
+
+	request := httptest.NewRequest("GET", "http://localhost/time", nil)
+
+	w, err := serve(gateway.router, request)
+	if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+		return
+	}
+
+	defer func() {
+		_ = w.Body.Close()
+	}()
+
+	after := time.Now()
+
+	response, err := ioutil.ReadAll(w.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, w.StatusCode)
+
+	var res struct {
+		Time int64 `json:"time"`
+	}
+
+	if !assert.NoError(t, json.Unmarshal(response, &res)) {
+		return
+	}
+
+	millis := int64(time.Millisecond)
+	assert.True(t, res.Time >= before.UnixNano()/millis && res.Time <= after.UnixNano()/millis, res.Time)
+}
+
+func TestGetGasSchedule(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	request := httptest.NewRequest("GET", "http://localhost/ledger/gas", nil)
+
+	w, err := serve(gateway.router, request)
+	if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+		return
+	}
+
+	defer func() {
+		_ = w.Body.Close()
+	}()
+
+	response, err := ioutil.ReadAll(w.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, w.StatusCode)
+
+	var res struct {
+		sys.GasSchedule
+		Height uint64 `json:"height"`
+	}
+
+	if !assert.NoError(t, json.Unmarshal(response, &res)) {
+		return
+	}
+
+	// The schedule applies to the block following the genesis block.
+	expected := sys.GasScheduleAt(1)
+
+	assert.EqualValues(t, 1, res.Height)
+	assert.Equal(t, expected.Version, res.Version)
+	assert.Equal(t, expected.DefaultInstruction, res.DefaultInstruction)
+	assert.Equal(t, expected.Instructions, res.Instructions)
+	assert.Equal(t, expected.HostFunctions, res.HostFunctions)
 }
 
 func TestConnectDisconnectErrors(t *testing.T) {
@@ -746,6 +2664,260 @@ func TestConnectDisconnectErrors(t *testing.T) {
 	}
 }
 
+func TestWebhooks(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	defer gateway.webhooks.removeAll()
+
+	currentSecret := conf.GetSecret()
+	defer conf.Update(conf.WithSecret(currentSecret))
+	conf.Update(conf.WithSecret("secret"))
+
+	type delivery struct {
+		body      []byte
+		signature string
+	}
+
+	deliveries := make(chan delivery, 16)
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		// Fail the first attempt, such that the event is retried.
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		deliveries <- delivery{body: body, signature: r.Header.Get(webhookSignatureHeader)}
+	}))
+	defer server.Close()
+
+	request := func(method, path, body string, auth bool) (int, string) {
+		req := httptest.NewRequest(method, "http://localhost"+path, strings.NewReader(body))
+		if auth {
+			req.Header.Set("Authorization", "Bearer secret")
+		}
+
+		w, err := serve(gateway.router, req)
+		if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+			return 0, ""
+		}
+
+		defer func() {
+			_ = w.Body.Close()
+		}()
+
+		response, err := ioutil.ReadAll(w.Body)
+		assert.NoError(t, err)
+
+		return w.StatusCode, string(bytes.TrimSpace(response))
+	}
+
+	code, _ := request(http.MethodPost, "/webhooks", `{"url":"`+server.URL+`","trigger":"tx_applied"}`, false)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, res := request(http.MethodPost, "/webhooks", `{"url":"`+server.URL+`","trigger":"nope"}`, true)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, `{"status":"Bad Request","error":"unknown trigger \"nope\""}`, res)
+
+	code, _ = request(http.MethodPost, "/webhooks", `{"url":"/relative","trigger":"tx_applied"}`, true)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, res = request(http.MethodPost, "/webhooks",
+		`{"url":"`+server.URL+`","trigger":"tx_applied","filters":{"nope":"1"}}`, true)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, `{"status":"Bad Request","error":"events of trigger \"tx_applied\" may not be filtered by \"nope\""}`, res)
+
+	code, res = request(http.MethodPost, "/webhooks",
+		`{"url":"`+server.URL+`","trigger":"tx_applied","secret":"hush","filters":{"sender":"abcd"}}`, true)
+	if !assert.Equal(t, http.StatusOK, code, res) {
+		return
+	}
+
+	v, err := fastjson.Parse(res)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.EqualValues(t, 1, v.GetUint64("id"))
+	assert.Equal(t, "hush", string(v.GetStringBytes("secret")))
+	assert.Equal(t, "abcd", string(v.GetStringBytes("filters", "sender")))
+
+	applied, rejected := log.TX(events.EventTxApplied), log.TX(events.EventTxRejected)
+	rejected.Log().Str("sender_id", "abcd").Msg("")
+	applied.Log().Str("sender_id", "ef01").Msg("")
+	applied.Log().Str("sender_id", "abcd").Msg("")
+
+	select {
+	case d := <-deliveries:
+		ev, err := fastjson.ParseBytes(d.body)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, events.EventTxApplied, string(ev.GetStringBytes("event")))
+		assert.Equal(t, "abcd", string(ev.GetStringBytes("sender_id")))
+
+		mac := hmac.New(sha256.New, []byte("hush"))
+		_, _ = mac.Write(d.body)
+
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), d.signature)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the event to be posted to the webhook")
+	}
+
+	assert.Eventually(t, func() bool {
+		_, res := request(http.MethodGet, "/webhooks/1", "", true)
+
+		v, err := fastjson.Parse(res)
+
+		return err == nil && v.GetUint64("delivered") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	code, res = request(http.MethodGet, "/webhooks/1", "", true)
+	assert.Equal(t, http.StatusOK, code)
+
+	v, err = fastjson.Parse(res)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, v.Exists("secret"))
+	assert.EqualValues(t, 0, v.GetUint64("failed"))
+	assert.EqualValues(t, http.StatusOK, v.GetInt("last_status"))
+	assert.True(t, v.Exists("last_delivered_at"))
+
+	code, res = request(http.MethodGet, "/webhooks", "", true)
+	assert.Equal(t, http.StatusOK, code)
+
+	v, err = fastjson.Parse(res)
+	if assert.NoError(t, err) {
+		assert.Len(t, v.GetArray(), 1)
+	}
+
+	select {
+	case d := <-deliveries:
+		t.Fatalf("unexpected event posted to the webhook: %s", d.body)
+	default:
+	}
+
+	code, res = request(http.MethodDelete, "/webhooks/1", "", true)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"msg":"Deleted webhook 1"}`, res)
+
+	code, _ = request(http.MethodGet, "/webhooks/1", "", true)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = request(http.MethodDelete, "/webhooks/1", "", true)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestGraphQL(t *testing.T) {
+	gateway := New()
+	gateway.setup()
+
+	gateway.ledger = createLedger(t)
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	tx := newTransaction(keys, sys.TagTransfer, 0, 0, []byte{1, 2, 3})
+	gateway.ledger.AddTransaction(tx)
+
+	sender := keys.PublicKey()
+
+	txID := hex.EncodeToString(tx.ID[:])
+	senderID := hex.EncodeToString(sender[:])
+
+	tests := []struct {
+		name         string
+		method       string
+		query        string
+		wantCode     int
+		wantResponse string
+	}{
+		{
+			name:     "nested query",
+			method:   "POST",
+			query:    `{"query": "query($id: String!) { transaction(id: $id) { id status sender { id balance } } }", "variables": {"id": "` + txID + `"}}`,
+			wantCode: http.StatusOK,
+			wantResponse: `{"data":{"transaction":{"id":"` + txID + `","status":"applied","sender":{"id":"` + senderID +
+				`","balance":0}}}}`,
+		},
+		{
+			name:         "query over GET",
+			method:       "GET",
+			query:        "query=" + url.QueryEscape(`{ round { index numTransactions } }`),
+			wantCode:     http.StatusOK,
+			wantResponse: `{"data":{"round":{"index":0,"numTransactions":0}}}`,
+		},
+		{
+			name:     "field errors",
+			method:   "POST",
+			query:    `{"query": "{ transaction(id: \"zz\") { id } }"}`,
+			wantCode: http.StatusOK,
+			wantResponse: `{"data":{"transaction":null},"errors":[{"message":"transaction ID must be presented as valid hex: ` +
+				`encoding/hex: invalid byte: U+007A 'z'","path":["transaction"]}]}`,
+		},
+		{
+			name:         "subscription over HTTP",
+			method:       "POST",
+			query:        `{"query": "subscription { roundFinalized { index } }"}`,
+			wantCode:     http.StatusBadRequest,
+			wantResponse: `{"errors":[{"message":"subscriptions must be made over a websocket"}]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var request *http.Request
+
+			if tc.method == "GET" {
+				request = httptest.NewRequest("GET", "http://localhost/graphql?"+tc.query, nil)
+			} else {
+				request = httptest.NewRequest("POST", "http://localhost/graphql", strings.NewReader(tc.query))
+			}
+
+			w, err := serve(gateway.router, request)
+			if !assert.NoError(t, err) || !assert.NotNil(t, w) {
+				return
+			}
+
+			defer func() {
+				_ = w.Body.Close()
+			}()
+
+			response, err := ioutil.ReadAll(w.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.wantCode, w.StatusCode, "status code")
+			assert.Equal(t, tc.wantResponse, string(bytes.TrimSpace(response)))
+		})
+	}
+}
+
+func TestErrResponseCode(t *testing.T) {
+	var arena fastjson.Arena
+
+	err := errors.Wrapf(wavelet.ErrInsufficientBalance, "sender current balance %d is not enough", 1)
+	assert.Equal(t,
+		`{"status":"Bad Request","error":"sender current balance 1 is not enough: insufficient balance",`+
+			`"code":"insufficient_balance"}`,
+		string(ErrBadRequest(err).marshalJSON(&arena)),
+	)
+
+	// Errors without codes are rendered as is.
+	assert.Equal(t,
+		`{"status":"Not Found","error":"account not found"}`,
+		string(ErrNotFound(errors.New("account not found")).marshalJSON(&arena)),
+	)
+}
+
 func compareJSON(expected []byte, response []byte) error {
 	if bytes.Equal(bytes.TrimSpace(response), expected) {
 		return nil
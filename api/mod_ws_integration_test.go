@@ -71,6 +71,31 @@ func TestPollLog(t *testing.T) {
 		assert.Equal(t, 1, len(messages))
 	})
 
+	t.Run("tx-sender-event-filter", func(t *testing.T) {
+		u := url.URL{Scheme: "ws", Host: ":8080", Path: `/poll/tx`, RawQuery: "sender=abcd&event=applied"}
+		c, cleanup := tryConnectWebsocket(t, u)
+		defer cleanup()
+
+		// log messages of other senders and events ahead of the one matching
+		applied, rejected := log.TX("applied"), log.TX("rejected")
+		applied.Log().Str("sender_id", "ef01").Msg("")
+		rejected.Log().Str("sender_id", "abcd").Msg("")
+		applied.Log().Str("sender_id", "abcd").Msg("")
+
+		messages := readAllMessages(t, c, 1)
+		if !assert.Equal(t, 1, len(messages)) {
+			return
+		}
+
+		v, err := fastjson.ParseBytes(messages[0])
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "abcd", string(v.GetStringBytes("sender_id")))
+		assert.Equal(t, "applied", string(v.GetStringBytes("event")))
+	})
+
 	t.Run("accounts-grouping", func(t *testing.T) {
 		u := url.URL{Scheme: "ws", Host: ":8080", Path: `/poll/accounts`}
 		c, cleanup := tryConnectWebsocket(t, u)
@@ -22,12 +22,23 @@ package api
 import (
 	"encoding/base64"
 	"encoding/hex"
+	"math"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/perlin-network/noise/edwards25519"
 	"github.com/perlin-network/noise/skademlia"
 	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/internal/graphql"
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/security"
 	"github.com/perlin-network/wavelet/sys"
 	"github.com/pkg/errors"
 	"github.com/valyala/fastjson"
@@ -41,12 +52,38 @@ var (
 	_ marshalableJSON = (*sendTransactionResponse)(nil)
 
 	_ marshalableJSON = (*ledgerStatusResponse)(nil)
+	_ marshalableJSON = (*ledgerRoundResponse)(nil)
+
+	_ marshalableJSON = (*feeEstimateResponse)(nil)
 
 	_ marshalableJSON = (*transaction)(nil)
 
 	_ marshalableJSON = (*account)(nil)
 
 	_ marshalableJSON = (*msgResponse)(nil)
+
+	_ marshalableJSON = (*nameResponse)(nil)
+
+	_ marshalableJSON = (*dataResponse)(nil)
+
+	_ marshalableJSON = (*diffResponse)(nil)
+
+	_ marshalableJSON = (*webhookResponse)(nil)
+	_ marshalableJSON = (*webhookList)(nil)
+
+	_ marshalableJSON = (*accountProofResponse)(nil)
+)
+
+// marshalableProto is implemented by responses which may alternatively be
+// rendered as their protobuf definitions, should a client accept them.
+type marshalableProto interface {
+	marshalProto() ([]byte, error)
+}
+
+var (
+	_ marshalableProto = (*transaction)(nil)
+
+	_ marshalableProto = (*account)(nil)
 )
 
 type msgResponse struct {
@@ -67,6 +104,10 @@ type sendTransactionRequest struct {
 	Block     uint64 `json:"block"`
 	Tag       byte   `json:"tag"`
 	Payload   string `json:"payload"`
+	Scheme    byte   `json:"scheme,omitempty"`
+	Version   byte   `json:"version,omitempty"`
+	Stamp     uint64 `json:"stamp,omitempty"`
+	Tip       uint64 `json:"tip,omitempty"`
 	Signature string `json:"signature"`
 
 	sender    edwards25519.PublicKey
@@ -129,76 +170,1035 @@ func (s *sendTransactionRequest) bind(parser *fastjson.Parser, body []byte) erro
 		return errors.New("missing payload")
 	}
 
-	payload, err := payloadVal.StringBytes()
-	if err != nil {
-		return errors.Wrap(err, "invalid payload")
-	}
+	payload, err := payloadVal.StringBytes()
+	if err != nil {
+		return errors.Wrap(err, "invalid payload")
+	}
+
+	signatureVal := v.Get("signature")
+	if signatureVal == nil {
+		return errors.New("missing signature")
+	}
+
+	signature, err := signatureVal.StringBytes()
+	if err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+
+	// The signature scheme is optional, and defaults to Ed25519.
+	if schemeVal := v.Get("scheme"); schemeVal != nil {
+		scheme, err := schemeVal.Uint()
+		if err != nil || scheme > math.MaxUint8 {
+			return errors.New("invalid signature scheme")
+		}
+
+		if _, err := security.LookupVerifier(security.Scheme(scheme)); err != nil {
+			return err
+		}
+
+		// Transactions may only be signed with schemes other than Ed25519
+		// once they activate, which they will have by the time the
+		// transaction is applied.
+		if scheme != uint(security.SchemeEd25519) && !sys.FeatureActive(sys.FeatureSignatureSchemes, block+1) {
+			return errors.Errorf("signature scheme %s is not yet active", security.Scheme(scheme))
+		}
+
+		s.Scheme = byte(scheme)
+	}
+
+	// The version of the encoding the signature signs is optional, and
+	// defaults to the legacy encoding.
+	if versionVal := v.Get("version"); versionVal != nil {
+		version, err := versionVal.Uint()
+		if err != nil || (version != 0 && version != uint(canonical.Version)) {
+			return errors.New("unsupported transaction version")
+		}
+
+		if version != 0 && !sys.FeatureActive(sys.FeatureCanonicalTransactions, block+1) {
+			return errors.Errorf("transaction version %d is not yet active", version)
+		}
+
+		s.Version = byte(version)
+	}
+
+	// The proof-of-work stamp is optional, and only signed by the canonical
+	// encoding.
+	if stampVal := v.Get("stamp"); stampVal != nil {
+		stamp, err := stampVal.Uint64()
+		if err != nil {
+			return errors.Wrap(err, "invalid stamp")
+		}
+
+		if stamp != 0 && s.Version != canonical.Version {
+			return errors.Errorf("stamped transactions must be of version %d", canonical.Version)
+		}
+
+		if stamp != 0 && !sys.FeatureActive(sys.FeatureStamps, block+1) {
+			return errors.New("transaction stamps are not yet active")
+		}
+
+		s.Stamp = stamp
+	}
+
+	// The tip is optional, and likewise only signed by the canonical encoding.
+	if tipVal := v.Get("tip"); tipVal != nil {
+		tip, err := tipVal.Uint64()
+		if err != nil {
+			return errors.Wrap(err, "invalid tip")
+		}
+
+		if tip != 0 && s.Version != canonical.Version {
+			return errors.Errorf("tipped transactions must be of version %d", canonical.Version)
+		}
+
+		if tip != 0 && !sys.FeatureActive(sys.FeatureReplacement, block+1) {
+			return errors.New("transaction tips are not yet active")
+		}
+
+		s.Tip = tip
+	}
+
+	s.Sender = string(sender)
+	s.Nonce = nonce
+	s.Block = block
+	s.Tag = byte(tag)
+	s.Payload = string(payload)
+	s.Signature = string(signature)
+
+	senderBuf, err := hex.DecodeString(s.Sender)
+	if err != nil {
+		return errors.Wrap(err, "sender public key provided is not hex-formatted")
+	}
+
+	if len(senderBuf) != wavelet.SizeAccountID {
+		return errors.Errorf("sender public key must be size %d", wavelet.SizeAccountID)
+	}
+
+	copy(s.sender[:], senderBuf)
+
+	if sys.Tag(s.Tag) > sys.TagSlashing {
+		return errors.New("unknown transaction tag specified")
+	}
+
+	s.payload, err = hex.DecodeString(s.Payload)
+	if err != nil {
+		return errors.Wrap(err, "payload provided is not hex-formatted")
+	}
+
+	signatureBuf, err := hex.DecodeString(s.Signature)
+	if err != nil {
+		return errors.Wrap(err, "signature provided is not hex-formatted")
+	}
+
+	if len(signatureBuf) != wavelet.SizeSignature {
+		return errors.Errorf("signature must be size %d", wavelet.SizeSignature)
+	}
+
+	copy(s.signature[:], signatureBuf)
+
+	return nil
+}
+
+type sendTransactionResponse struct {
+	// Internal fields.
+	ledger *wavelet.Ledger
+	tx     *wavelet.Transaction
+}
+
+func (s *sendTransactionResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	if s.ledger == nil || s.tx == nil {
+		return nil, errors.New("insufficient parameters were provided")
+	}
+
+	o := arena.NewObject()
+
+	o.Set("id", arena.NewString(hex.EncodeToString(s.tx.ID[:])))
+
+	return o.MarshalTo(nil), nil
+}
+
+type estimateFeeRequest struct {
+	Sender  string `json:"sender"`
+	Tag     byte   `json:"tag"`
+	Payload string `json:"payload"`
+
+	sender  wavelet.AccountID
+	payload []byte
+}
+
+func (s *estimateFeeRequest) bind(parser *fastjson.Parser, body []byte) error {
+	if err := fastjson.ValidateBytes(body); err != nil {
+		return errors.Wrap(err, "invalid json")
+	}
+
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return err
+	}
+
+	senderVal := v.Get("sender")
+	if senderVal == nil {
+		return errors.New("missing sender")
+	}
+
+	sender, err := senderVal.StringBytes()
+	if err != nil {
+		return errors.Wrap(err, "invalid sender")
+	}
+
+	tagVal := v.Get("tag")
+	if tagVal == nil {
+		return errors.New("missing tag")
+	}
+
+	tag, err := tagVal.Uint()
+	if err != nil {
+		return errors.Wrap(err, "invalid tag")
+	}
+
+	payloadVal := v.Get("payload")
+	if payloadVal == nil {
+		return errors.New("missing payload")
+	}
+
+	payload, err := payloadVal.StringBytes()
+	if err != nil {
+		return errors.Wrap(err, "invalid payload")
+	}
+
+	s.Sender = string(sender)
+	s.Tag = byte(tag)
+	s.Payload = string(payload)
+
+	senderBuf, err := hex.DecodeString(s.Sender)
+	if err != nil {
+		return errors.Wrap(err, "sender public key provided is not hex-formatted")
+	}
+
+	if len(senderBuf) != wavelet.SizeAccountID {
+		return errors.Errorf("sender public key must be size %d", wavelet.SizeAccountID)
+	}
+
+	copy(s.sender[:], senderBuf)
+
+	if tag > uint(sys.TagSlashing) {
+		return errors.New("unknown transaction tag specified")
+	}
+
+	s.payload, err = hex.DecodeString(s.Payload)
+	if err != nil {
+		return errors.Wrap(err, "payload provided is not hex-formatted")
+	}
+
+	return nil
+}
+
+// maxContractCallGasLimit bounds the gas simulated contract calls may spend,
+// such that calls may not keep the node busy.
+const maxContractCallGasLimit = 100000000
+
+type contractCallRequest struct {
+	Sender   string `json:"sender"`
+	Func     string `json:"func"`
+	Params   string `json:"params"`
+	Amount   uint64 `json:"amount"`
+	GasLimit uint64 `json:"gas_limit"`
+
+	sender wavelet.AccountID
+	params []byte
+}
+
+func (s *contractCallRequest) bind(parser *fastjson.Parser, body []byte) error {
+	if err := fastjson.ValidateBytes(body); err != nil {
+		return errors.Wrap(err, "invalid json")
+	}
+
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return err
+	}
+
+	s.Func = string(v.GetStringBytes("func"))
+	if len(s.Func) == 0 {
+		return errors.New("missing func")
+	}
+
+	s.Sender = string(v.GetStringBytes("sender"))
+	s.Params = string(v.GetStringBytes("params"))
+	s.Amount = v.GetUint64("amount")
+	s.GasLimit = v.GetUint64("gas_limit")
+
+	if s.Sender != "" {
+		senderBuf, err := hex.DecodeString(s.Sender)
+		if err != nil {
+			return errors.Wrap(err, "sender public key provided is not hex-formatted")
+		}
+
+		if len(senderBuf) != wavelet.SizeAccountID {
+			return errors.Errorf("sender public key must be size %d", wavelet.SizeAccountID)
+		}
+
+		copy(s.sender[:], senderBuf)
+	}
+
+	if s.params, err = hex.DecodeString(s.Params); err != nil {
+		return errors.Wrap(err, "params provided are not hex-formatted")
+	}
+
+	if s.GasLimit == 0 || s.GasLimit > maxContractCallGasLimit {
+		s.GasLimit = maxContractCallGasLimit
+	}
+
+	return nil
+}
+
+type contractCallResponse struct {
+	// Internal fields.
+	call wavelet.ContractCall
+}
+
+func (s *contractCallResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("result", arena.NewString(hex.EncodeToString(s.call.Result)))
+	o.Set("gas", arena.NewNumberString(strconv.FormatUint(s.call.Gas, 10)))
+
+	if s.call.GasLimitExceeded {
+		o.Set("gas_limit_exceeded", arena.NewTrue())
+	} else {
+		o.Set("gas_limit_exceeded", arena.NewFalse())
+	}
+
+	if s.call.Err != nil {
+		o.Set("failure", arena.NewString(s.call.Err.Error()))
+	}
+
+	o.Set("queued", arena.NewNumberString(strconv.Itoa(len(s.call.Queue))))
+
+	return o.MarshalTo(nil), nil
+}
+
+type contractLogList struct {
+	// Internal fields.
+	logs  []wavelet.ContractLog
+	total uint64
+}
+
+func (s *contractLogList) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	list := arena.NewArray()
+
+	for i, l := range s.logs {
+		o := arena.NewObject()
+
+		o.Set("index", arena.NewNumberString(strconv.FormatUint(l.Index, 10)))
+		o.Set("block", arena.NewNumberString(strconv.FormatUint(l.Block, 10)))
+		o.Set("tx_id", arena.NewString(hex.EncodeToString(l.TxID[:])))
+		o.Set("topic", arena.NewString(hex.EncodeToString(l.Topic)))
+		o.Set("data", arena.NewString(hex.EncodeToString(l.Data)))
+
+		list.SetArrayItem(i, o)
+	}
+
+	o := arena.NewObject()
+
+	o.Set("total", arena.NewNumberString(strconv.FormatUint(s.total, 10)))
+	o.Set("logs", list)
+
+	return o.MarshalTo(nil), nil
+}
+
+type contractStorageResponse struct {
+	// Internal fields.
+	id     wavelet.TransactionID
+	key    []byte
+	value  []byte
+	exists bool
+
+	// Set should the value be proven.
+	proof *wavelet.ContractStorageProof
+	block *wavelet.Block
+}
+
+func (s *contractStorageResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("contract_id", arena.NewString(hex.EncodeToString(s.id[:])))
+	o.Set("key", arena.NewString(hex.EncodeToString(s.key)))
+	o.Set("value", arena.NewString(hex.EncodeToString(s.value)))
+
+	if s.exists {
+		o.Set("exists", arena.NewTrue())
+	} else {
+		o.Set("exists", arena.NewFalse())
+	}
+
+	if s.proof == nil {
+		return o.MarshalTo(nil), nil
+	}
+
+	block := arena.NewObject()
+	block.Set("merkle_root", arena.NewString(hex.EncodeToString(s.block.Merkle[:])))
+	block.Set("height", arena.NewNumberString(strconv.FormatUint(s.block.Index, 10)))
+	block.Set("id", arena.NewString(hex.EncodeToString(s.block.ID[:])))
+
+	o.Set("block", block)
+
+	path, lefts := arena.NewArray(), arena.NewArray()
+
+	for i := range s.proof.Proof.Path {
+		path.SetArrayItem(i, arena.NewString(hex.EncodeToString(s.proof.Proof.Path[i])))
+		lefts.SetArrayItem(i, arena.NewString(hex.EncodeToString(s.proof.Proof.Lefts[i])))
+	}
+
+	proof := arena.NewObject()
+	proof.Set("path", path)
+	proof.Set("lefts", lefts)
+
+	o.Set("proof", proof)
+
+	return o.MarshalTo(nil), nil
+}
+
+type contractUpgradesResponse struct {
+	// Internal fields.
+	codeHash   [32]byte
+	upgrader   wavelet.AccountID
+	upgradable bool
+	upgrades   []wavelet.ContractUpgrade
+}
+
+func (s *contractUpgradesResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("code_hash", arena.NewString(hex.EncodeToString(s.codeHash[:])))
+
+	if s.upgradable {
+		o.Set("upgrader", arena.NewString(hex.EncodeToString(s.upgrader[:])))
+	} else {
+		o.Set("upgrader", arena.NewNull())
+	}
+
+	list := arena.NewArray()
+
+	for i, upgrade := range s.upgrades {
+		v := arena.NewObject()
+
+		v.Set("block", arena.NewNumberString(strconv.FormatUint(upgrade.Block, 10)))
+		v.Set("tx_id", arena.NewString(hex.EncodeToString(upgrade.TxID[:])))
+		v.Set("upgrader", arena.NewString(hex.EncodeToString(upgrade.Upgrader[:])))
+		v.Set("code_hash", arena.NewString(hex.EncodeToString(upgrade.CodeHash[:])))
+
+		list.SetArrayItem(i, v)
+	}
+
+	o.Set("upgrades", list)
+
+	return o.MarshalTo(nil), nil
+}
+
+type accountDelegationsResponse struct {
+	// Internal fields.
+	id          wavelet.AccountID
+	commission  uint16
+	delegators  []wavelet.DelegatedStake
+	delegations []wavelet.DelegatedStake
+	unbondings  []wavelet.UnbondingStake
+}
+
+func (s *accountDelegationsResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("public_key", arena.NewString(hex.EncodeToString(s.id[:])))
+	o.Set("commission", arena.NewNumberInt(int(s.commission)))
+
+	var delegated uint64
+
+	for _, delegator := range s.delegators {
+		delegated += delegator.Amount
+	}
+
+	o.Set("delegated", arena.NewNumberString(strconv.FormatUint(delegated, 10)))
+	o.Set("delegators", delegatedStakesToJSON(arena, s.delegators, "delegator"))
+	o.Set("delegations", delegatedStakesToJSON(arena, s.delegations, "validator"))
+
+	unbondings := arena.NewArray()
+
+	for i, unbonding := range s.unbondings {
+		v := arena.NewObject()
+
+		v.Set("validator", arena.NewString(hex.EncodeToString(unbonding.Validator[:])))
+		v.Set("amount", arena.NewNumberString(strconv.FormatUint(unbonding.Amount, 10)))
+		v.Set("block", arena.NewNumberString(strconv.FormatUint(unbonding.Block, 10)))
+
+		unbondings.SetArrayItem(i, v)
+	}
+
+	o.Set("unbonding", unbondings)
+
+	return o.MarshalTo(nil), nil
+}
+
+type accountSlashingsResponse struct {
+	// Internal fields.
+	id         wavelet.AccountID
+	lastActive uint64
+	active     bool
+	events     []wavelet.SlashingEvent
+}
+
+func (s *accountSlashingsResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("public_key", arena.NewString(hex.EncodeToString(s.id[:])))
+
+	if s.active {
+		o.Set("last_active", arena.NewNumberString(strconv.FormatUint(s.lastActive, 10)))
+	} else {
+		o.Set("last_active", arena.NewNull())
+	}
+
+	var slashed uint64
+
+	events := arena.NewArray()
+
+	for i, event := range s.events {
+		slashed += event.Amount
+
+		v := arena.NewObject()
+
+		if event.Opcode == sys.ReportDoubleSign {
+			v.Set("reason", arena.NewString("double_sign"))
+			v.Set("nonce", arena.NewNumberString(strconv.FormatUint(event.Nonce, 10)))
+		} else {
+			v.Set("reason", arena.NewString("downtime"))
+		}
+
+		v.Set("reporter", arena.NewString(hex.EncodeToString(event.Reporter[:])))
+		v.Set("tx_id", arena.NewString(hex.EncodeToString(event.TxID[:])))
+		v.Set("amount", arena.NewNumberString(strconv.FormatUint(event.Amount, 10)))
+		v.Set("burned", arena.NewNumberString(strconv.FormatUint(event.Burned, 10)))
+		v.Set("block", arena.NewNumberString(strconv.FormatUint(event.Block, 10)))
+
+		events.SetArrayItem(i, v)
+	}
+
+	o.Set("slashed", arena.NewNumberString(strconv.FormatUint(slashed, 10)))
+	o.Set("events", events)
+
+	return o.MarshalTo(nil), nil
+}
+
+// validatorStatus is a validator along with its uptime as observed by the node.
+type validatorStatus struct {
+	wavelet.Validator
+
+	delegated  uint64
+	lastActive uint64
+	active     bool
+
+	since     time.Time
+	connected bool
+}
+
+type validatorsResponse struct {
+	// Internal fields.
+	block      uint64
+	now        time.Time
+	validators []validatorStatus
+}
+
+func (s *validatorsResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	var total uint64
+
+	list := arena.NewArray()
+
+	for i, validator := range s.validators {
+		total += validator.Stake
+
+		v := arena.NewObject()
+
+		v.Set("public_key", arena.NewString(hex.EncodeToString(validator.ID[:])))
+		v.Set("stake", arena.NewNumberString(strconv.FormatUint(validator.Stake, 10)))
+		v.Set("delegated", arena.NewNumberString(strconv.FormatUint(validator.delegated, 10)))
+
+		if validator.active {
+			v.Set("last_active", arena.NewNumberString(strconv.FormatUint(validator.lastActive, 10)))
+		} else {
+			v.Set("last_active", arena.NewNull())
+		}
+
+		var uptime time.Duration
+
+		if validator.connected {
+			v.Set("connected", arena.NewTrue())
+			uptime = s.now.Sub(validator.since)
+		} else {
+			v.Set("connected", arena.NewFalse())
+		}
+
+		v.Set("uptime", arena.NewNumberString(strconv.FormatInt(int64(uptime/time.Second), 10)))
+
+		list.SetArrayItem(i, v)
+	}
+
+	o.Set("block", arena.NewNumberString(strconv.FormatUint(s.block, 10)))
+	o.Set("total_stake", arena.NewNumberString(strconv.FormatUint(total, 10)))
+	o.Set("validators", list)
+
+	return o.MarshalTo(nil), nil
+}
+
+// peerScoresResponse reports the reputation of the peers the node is
+// connected to or has scored, alongside the policy it bans peers by.
+type peerScoresResponse struct {
+	// Internal fields.
+	policy    wavelet.PeerPolicy
+	now       time.Time
+	scores    []wavelet.PeerScore
+	connected map[wavelet.AccountID]struct{}
+}
+
+func (s *peerScoresResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	list := arena.NewArray()
+
+	for i, score := range s.scores {
+		v := arena.NewObject()
+
+		v.Set("public_key", arena.NewString(hex.EncodeToString(score.ID[:])))
+		v.Set("address", arena.NewString(score.Address))
+
+		if _, connected := s.connected[score.ID]; connected {
+			v.Set("connected", arena.NewTrue())
+		} else {
+			v.Set("connected", arena.NewFalse())
+		}
+
+		v.Set("score", arena.NewNumberFloat64(score.Score))
+		v.Set("latency", arena.NewString(score.Latency.String()))
+
+		violations := arena.NewObject()
+
+		for kind := wavelet.Violation(0); kind < wavelet.NumViolations; kind++ {
+			violations.Set(kind.String(), arena.NewNumberString(strconv.FormatUint(score.Violations[kind], 10)))
+		}
+
+		v.Set("violations", violations)
+
+		if score.Banned(s.now) {
+			v.Set("banned_until", arena.NewString(score.BannedUntil.UTC().Format(time.RFC3339)))
+		} else {
+			v.Set("banned_until", arena.NewNull())
+		}
+
+		list.SetArrayItem(i, v)
+	}
+
+	o.Set("ban_score", arena.NewNumberFloat64(s.policy.BanScore))
+	o.Set("ban_duration", arena.NewString(s.policy.BanDuration.String()))
+	o.Set("max_requests_per_second", arena.NewNumberString(strconv.FormatUint(s.policy.MaxRequestsPerSecond, 10)))
+	o.Set("peers", list)
+
+	return o.MarshalTo(nil), nil
+}
+
+// snowballResponse reports the Snowball consensus protocol parameters the
+// node currently runs with.
+type snowballResponse struct{}
+
+func (s *snowballResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	return snowballToJSON(arena).MarshalTo(nil), nil
+}
+
+// snowballToJSON renders the Snowball consensus protocol parameters the node
+// currently runs with.
+func snowballToJSON(arena *fastjson.Arena) *fastjson.Value {
+	o := arena.NewObject()
+
+	o.Set("k", arena.NewNumberInt(conf.GetSnowballK()))
+	o.Set("alpha", arena.NewNumberFloat64(conf.GetSnowballAlpha()))
+	o.Set("beta", arena.NewNumberInt(conf.GetSnowballBeta()))
+
+	return o
+}
+
+// logLevelsResponse reports the log level of every module of the node.
+type logLevelsResponse struct{}
+
+func (s *logLevelsResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	for module, level := range log.Levels() {
+		o.Set(module, arena.NewString(level.String()))
+	}
+
+	return o.MarshalTo(nil), nil
+}
+
+// healthResponse reports on the conditions of the liveness of the node, or
+// of its readiness should ready be true.
+type healthResponse struct {
+	health wavelet.Health
+	ready  bool
+}
+
+func (s *healthResponse) ok() bool {
+	if s.ready {
+		return s.health.Ready()
+	}
+
+	return s.health.Live()
+}
+
+func (s *healthResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	h := s.health
+
+	o := arena.NewObject()
+
+	if s.ok() {
+		o.Set("status", arena.NewString("ok"))
+	} else {
+		o.Set("status", arena.NewString("unavailable"))
+	}
+
+	checks := arena.NewObject()
+
+	storage := arena.NewObject()
+	storage.Set("ok", boolToJSON(arena, h.Storage.OK))
+	storage.Set("latency", arena.NewString(h.Storage.Latency.String()))
+
+	if h.Storage.Error != "" {
+		storage.Set("error", arena.NewString(h.Storage.Error))
+	}
+
+	checks.Set("storage", storage)
+
+	consensus := arena.NewObject()
+	consensus.Set("ok", boolToJSON(arena, h.Consensus.OK))
+	consensus.Set("block_index", arena.NewNumberString(strconv.FormatUint(h.Consensus.BlockIndex, 10)))
+	consensus.Set("round_duration", arena.NewString(h.Consensus.RoundDuration.String()))
+	consensus.Set("max_stall", arena.NewString(h.Consensus.MaxStall.String()))
+
+	checks.Set("consensus", consensus)
+
+	if s.ready {
+		peers := arena.NewObject()
+		peers.Set("ok", boolToJSON(arena, h.Peers.OK))
+		peers.Set("num_peers", arena.NewNumberInt(h.Peers.NumPeers))
+		peers.Set("min_peers", arena.NewNumberInt(h.Peers.MinPeers))
+
+		checks.Set("peers", peers)
+
+		sync := arena.NewObject()
+		sync.Set("ok", boolToJSON(arena, h.Sync.OK))
+		sync.Set("reconciled", boolToJSON(arena, h.Sync.Reconciled))
+		sync.Set("syncing", boolToJSON(arena, h.Sync.Syncing))
+		sync.Set("lag", arena.NewNumberString(strconv.FormatUint(h.Sync.Lag, 10)))
+		sync.Set("max_lag", arena.NewNumberString(strconv.FormatUint(h.Sync.MaxLag, 10)))
+
+		checks.Set("sync", sync)
+	}
+
+	o.Set("checks", checks)
+
+	return o.MarshalTo(nil), nil
+}
+
+func boolToJSON(arena *fastjson.Arena, b bool) *fastjson.Value {
+	if b {
+		return arena.NewTrue()
+	}
+
+	return arena.NewFalse()
+}
+
+// delegatedStakesToJSON lists stakes, with the account of each keyed by key.
+func delegatedStakesToJSON(arena *fastjson.Arena, stakes []wavelet.DelegatedStake, key string) *fastjson.Value {
+	list := arena.NewArray()
+
+	for i, stake := range stakes {
+		v := arena.NewObject()
+
+		v.Set(key, arena.NewString(hex.EncodeToString(stake.Account[:])))
+		v.Set("amount", arena.NewNumberString(strconv.FormatUint(stake.Amount, 10)))
+
+		list.SetArrayItem(i, v)
+	}
+
+	return list
+}
+
+type contractStorageList struct {
+	// Internal fields.
+	entries []wavelet.ContractStorageEntry
+}
+
+func (s *contractStorageList) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	list := arena.NewArray()
+
+	for i, entry := range s.entries {
+		o := arena.NewObject()
+
+		o.Set("key", arena.NewString(hex.EncodeToString(entry.Key)))
+		o.Set("value", arena.NewString(hex.EncodeToString(entry.Value)))
+
+		list.SetArrayItem(i, o)
+	}
+
+	o := arena.NewObject()
+	o.Set("entries", list)
+
+	return o.MarshalTo(nil), nil
+}
+
+type feeEstimateResponse struct {
+	// Internal fields.
+	estimate wavelet.FeeEstimate
+}
+
+func (s *feeEstimateResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("fee", arena.NewNumberString(strconv.FormatUint(s.estimate.Fee, 10)))
+	o.Set("gas", arena.NewNumberString(strconv.FormatUint(s.estimate.Gas, 10)))
+
+	return o.MarshalTo(nil), nil
+}
+
+type randomnessResponse struct {
+	// Internal fields.
+	index uint64
+	value [32]byte
+}
+
+func (s *randomnessResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("index", arena.NewNumberString(strconv.FormatUint(s.index, 10)))
+	o.Set("randomness", arena.NewString(hex.EncodeToString(s.value[:])))
+
+	return o.MarshalTo(nil), nil
+}
+
+type roundResponse struct {
+	// Internal fields.
+	round wavelet.Round
+}
+
+func (s *roundResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	return roundToJSON(arena, s.round).MarshalTo(nil), nil
+}
+
+type roundList struct {
+	// Internal fields.
+	rounds []wavelet.Round
+}
+
+func (s *roundList) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	list := arena.NewArray()
+
+	for i, round := range s.rounds {
+		list.SetArrayItem(i, roundToJSON(arena, round))
+	}
+
+	return list.MarshalTo(nil), nil
+}
+
+func roundToJSON(arena *fastjson.Arena, round wavelet.Round) *fastjson.Value {
+	o := arena.NewObject()
+
+	o.Set("index", arena.NewNumberString(strconv.FormatUint(round.Index, 10)))
+	o.Set("id", arena.NewString(hex.EncodeToString(round.ID[:])))
+	o.Set("merkle_root", arena.NewString(hex.EncodeToString(round.Merkle[:])))
+	o.Set("start_id", arena.NewString(hex.EncodeToString(round.StartID[:])))
+	o.Set("end_id", arena.NewString(hex.EncodeToString(round.EndID[:])))
+	o.Set("num_tx", arena.NewNumberInt(int(round.NumTx)))
+	o.Set("num_applied_tx", arena.NewNumberInt(int(round.NumApplied)))
+	o.Set("num_rejected_tx", arena.NewNumberInt(int(round.NumRejected)))
+	o.Set("started_at", arena.NewString(round.StartedAt.UTC().Format(time.RFC3339Nano)))
+	o.Set("finalized_at", arena.NewString(round.FinalizedAt.UTC().Format(time.RFC3339Nano)))
+	o.Set("duration", arena.NewString(round.Duration().String()))
+
+	return o
+}
+
+type timeResponse struct {
+	// Internal fields.
+	now time.Time
+}
+
+func (s *timeResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("time", arena.NewNumberString(strconv.FormatInt(s.now.UnixNano()/int64(time.Millisecond), 10)))
+
+	return o.MarshalTo(nil), nil
+}
+
+type gasScheduleResponse struct {
+	// Internal fields.
+	height   uint64
+	schedule *sys.GasSchedule
+}
+
+func (s *gasScheduleResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("version", arena.NewNumberInt(int(s.schedule.Version)))
+	o.Set("height", arena.NewNumberString(strconv.FormatUint(s.height, 10)))
+	o.Set("default_instruction", arena.NewNumberString(strconv.FormatInt(s.schedule.DefaultInstruction, 10)))
+	o.Set("instructions", marshalGasCosts(arena, s.schedule.Instructions))
+	o.Set("host_functions", marshalGasCosts(arena, s.schedule.HostFunctions))
+
+	return o.MarshalTo(nil), nil
+}
+
+// marshalGasCosts marshals costs as an object, with its keys sorted such that
+// the schedule is always rendered the same.
+func marshalGasCosts(arena *fastjson.Arena, costs map[string]int64) *fastjson.Value {
+	keys := make([]string, 0, len(costs))
+	for key := range costs {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	o := arena.NewObject()
+
+	for _, key := range keys {
+		o.Set(key, arena.NewNumberString(strconv.FormatInt(costs[key], 10)))
+	}
+
+	return o
+}
+
+type nameResponse struct {
+	// Internal fields.
+	name   string
+	record wavelet.NameRecord
+}
+
+func (s *nameResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("name", arena.NewString(s.name))
+	o.Set("owner", arena.NewString(hex.EncodeToString(s.record.Owner[:])))
+	o.Set("target", arena.NewString(hex.EncodeToString(s.record.Target[:])))
+	o.Set("expiry", arena.NewNumberString(strconv.FormatUint(s.record.Expiry, 10)))
+
+	return o.MarshalTo(nil), nil
+}
+
+type dataResponse struct {
+	// Internal fields.
+	id   wavelet.TransactionID
+	blob []byte
+}
+
+func (s *dataResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("id", arena.NewString(hex.EncodeToString(s.id[:])))
+	o.Set("data", arena.NewString(hex.EncodeToString(s.blob)))
+
+	return o.MarshalTo(nil), nil
+}
+
+type mempoolCountResponse struct {
+	// Internal fields.
+	count   int
+	missing int
+}
+
+func (s *mempoolCountResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("count", arena.NewNumberInt(s.count))
+	o.Set("missing", arena.NewNumberInt(s.missing))
+
+	return o.MarshalTo(nil), nil
+}
+
+type mempoolContainsResponse struct {
+	// Internal fields.
+	id       wavelet.TransactionID
+	contains bool
+}
+
+func (s *mempoolContainsResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("id", arena.NewString(hex.EncodeToString(s.id[:])))
+
+	if s.contains {
+		o.Set("contains", arena.NewTrue())
+	} else {
+		o.Set("contains", arena.NewFalse())
+	}
+
+	return o.MarshalTo(nil), nil
+}
 
-	signatureVal := v.Get("signature")
-	if signatureVal == nil {
-		return errors.New("missing signature")
-	}
+type diffResponse struct {
+	// Internal fields.
+	diff wavelet.TransactionDiff
+}
 
-	signature, err := signatureVal.StringBytes()
-	if err != nil {
-		return errors.Wrap(err, "invalid signature")
-	}
+func (s *diffResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
 
-	s.Sender = string(sender)
-	s.Nonce = nonce
-	s.Block = block
-	s.Tag = byte(tag)
-	s.Payload = string(payload)
-	s.Signature = string(signature)
+	o.Set("id", arena.NewString(hex.EncodeToString(s.diff.ID[:])))
 
-	senderBuf, err := hex.DecodeString(s.Sender)
-	if err != nil {
-		return errors.Wrap(err, "sender public key provided is not hex-formatted")
-	}
+	accounts := arena.NewArray()
 
-	if len(senderBuf) != wavelet.SizeAccountID {
-		return errors.Errorf("sender public key must be size %d", wavelet.SizeAccountID)
-	}
+	for i, diff := range s.diff.Accounts {
+		account := arena.NewObject()
 
-	copy(s.sender[:], senderBuf)
+		account.Set("id", arena.NewString(hex.EncodeToString(diff.ID[:])))
 
-	if sys.Tag(s.Tag) > sys.TagBatch {
-		return errors.New("unknown transaction tag specified")
-	}
+		fields := []struct {
+			key  string
+			diff *wavelet.FieldDiff
+		}{
+			{"balance", diff.Balance},
+			{"stake", diff.Stake},
+			{"reward", diff.Reward},
+			{"gas_balance", diff.GasBalance},
+		}
 
-	s.payload, err = hex.DecodeString(s.Payload)
-	if err != nil {
-		return errors.Wrap(err, "payload provided is not hex-formatted")
-	}
+		for _, field := range fields {
+			if field.diff == nil {
+				continue
+			}
 
-	signatureBuf, err := hex.DecodeString(s.Signature)
-	if err != nil {
-		return errors.Wrap(err, "signature provided is not hex-formatted")
-	}
+			v := arena.NewObject()
+			v.Set("before", arena.NewNumberString(strconv.FormatUint(field.diff.Before, 10)))
+			v.Set("after", arena.NewNumberString(strconv.FormatUint(field.diff.After, 10)))
 
-	if len(signatureBuf) != wavelet.SizeSignature {
-		return errors.Errorf("signature must be size %d", wavelet.SizeSignature)
-	}
+			account.Set(field.key, v)
+		}
 
-	copy(s.signature[:], signatureBuf)
+		if diff.ContractCreated {
+			account.Set("contract_created", arena.NewTrue())
+		}
 
-	return nil
-}
+		if len(diff.ContractPages) > 0 {
+			pages := arena.NewArray()
 
-type sendTransactionResponse struct {
-	// Internal fields.
-	ledger *wavelet.Ledger
-	tx     *wavelet.Transaction
-}
+			for j, page := range diff.ContractPages {
+				pages.SetArrayItem(j, arena.NewNumberString(strconv.FormatUint(page, 10)))
+			}
 
-func (s *sendTransactionResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
-	if s.ledger == nil || s.tx == nil {
-		return nil, errors.New("insufficient parameters were provided")
-	}
+			account.Set("contract_pages", pages)
+		}
 
-	o := arena.NewObject()
+		accounts.SetArrayItem(i, account)
+	}
 
-	o.Set("id", arena.NewString(hex.EncodeToString(s.tx.ID[:])))
+	o.Set("accounts", accounts)
 
 	return o.MarshalTo(nil), nil
 }
@@ -273,6 +1273,58 @@ func (s *ledgerStatusResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error
 		arena.NewNumberInt(s.ledger.Transactions().Len()))
 	o.Set("num_accounts_in_store",
 		arena.NewNumberString(strconv.FormatUint(accountsLen, 10)))
+	o.Set("stamp_difficulty",
+		arena.NewNumberInt(s.ledger.StampDifficulty()))
+
+	if s.ledger.Archival() {
+		o.Set("archival", arena.NewTrue())
+	} else {
+		o.Set("archival", arena.NewFalse())
+	}
+
+	// Report the features applying to the next block to be finalized.
+	features := arena.NewArray()
+
+	for i, f := range sys.ActiveFeatures(block.Index + 1) {
+		features.SetArrayItem(i, arena.NewString(string(f)))
+	}
+
+	o.Set("features", features)
+	o.Set("snowball", snowballToJSON(arena))
+
+	{
+		pruning := s.ledger.PruningStatus()
+
+		pruningObj := arena.NewObject()
+
+		if pruning.Enabled {
+			pruningObj.Set("enabled", arena.NewTrue())
+		} else {
+			pruningObj.Set("enabled", arena.NewFalse())
+		}
+
+		pruningObj.Set("retained_blocks",
+			arena.NewNumberString(strconv.FormatUint(pruning.RetainedBlocks, 10)))
+		pruningObj.Set("retained_from",
+			arena.NewNumberString(strconv.FormatUint(pruning.RetainedFrom, 10)))
+		pruningObj.Set("num_pruned_diffs",
+			arena.NewNumberString(strconv.FormatUint(pruning.PrunedDiffs, 10)))
+		pruningObj.Set("compact_interval", arena.NewString(pruning.CompactInterval.String()))
+		pruningObj.Set("num_compactions",
+			arena.NewNumberString(strconv.FormatUint(pruning.Compactions, 10)))
+
+		if pruning.Compactions > 0 {
+			pruningObj.Set("last_compaction_at", arena.NewString(pruning.LastCompaction.Format(time.RFC3339)))
+		} else {
+			pruningObj.Set("last_compaction_at", arena.NewNull())
+		}
+
+		if pruning.LastCompactionErr != "" {
+			pruningObj.Set("last_compaction_error", arena.NewString(pruning.LastCompactionErr))
+		}
+
+		o.Set("pruning", pruningObj)
+	}
 
 	peers := s.client.ClosestPeerIDs()
 	if len(peers) > 0 {
@@ -296,6 +1348,198 @@ func (s *ledgerStatusResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error
 	return o.MarshalTo(nil), nil
 }
 
+// ledgerRoundResponse is the state of the ledger as of a past block.
+type ledgerRoundResponse struct {
+	// Internal fields.
+	snapshot *avl.Tree
+	block    *wavelet.Block
+}
+
+func (s *ledgerRoundResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	if s.snapshot == nil || s.block == nil {
+		return nil, errors.New("insufficient parameters were provided")
+	}
+
+	o := arena.NewObject()
+
+	o.Set("num_accounts",
+		arena.NewNumberString(strconv.FormatUint(wavelet.ReadAccountsLen(s.snapshot), 10)))
+
+	blockObj := arena.NewObject()
+	blockObj.Set("merkle_root",
+		arena.NewString(hex.EncodeToString(s.block.Merkle[:])))
+	blockObj.Set("height",
+		arena.NewNumberString(strconv.FormatUint(s.block.Index, 10)))
+	blockObj.Set("id",
+		arena.NewString(hex.EncodeToString(s.block.ID[:])))
+	blockObj.Set("transactions",
+		arena.NewNumberInt(len(s.block.Transactions)))
+
+	o.Set("block", blockObj)
+
+	return o.MarshalTo(nil), nil
+}
+
+// graphqlResponse is the response to a GraphQL query, executed as it is
+// rendered.
+type graphqlResponse struct {
+	// Internal fields.
+	query *graphql.Query
+	root  graphql.Object
+}
+
+func (s *graphqlResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	if s.query == nil || s.root == nil {
+		return nil, errors.New("insufficient parameters were provided")
+	}
+
+	return s.query.Execute(arena, s.root).MarshalTo(nil), nil
+}
+
+type relayerResponse struct {
+	// Internal fields.
+	id    wavelet.AccountID
+	stats relayerStats
+}
+
+func (s *relayerResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("id", arena.NewString(hex.EncodeToString(s.id[:])))
+	o.Set("submitted", arena.NewNumberString(strconv.FormatUint(s.stats.submitted, 10)))
+	o.Set("accepted", arena.NewNumberString(strconv.FormatUint(s.stats.accepted, 10)))
+	o.Set("rejected", arena.NewNumberString(strconv.FormatUint(s.stats.rejected, 10)))
+	o.Set("fees", arena.NewNumberString(strconv.FormatUint(s.stats.fees, 10)))
+
+	return o.MarshalTo(nil), nil
+}
+
+type registerWebhookRequest struct {
+	URL     string            `json:"url"`
+	Trigger string            `json:"trigger"`
+	Secret  string            `json:"secret"`
+	Filters map[string]string `json:"filters"`
+}
+
+func (s *registerWebhookRequest) bind(parser *fastjson.Parser, body []byte) error {
+	if err := fastjson.ValidateBytes(body); err != nil {
+		return errors.Wrap(err, "invalid json")
+	}
+
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return err
+	}
+
+	s.URL = string(v.GetStringBytes("url"))
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return errors.Wrap(err, "invalid url")
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	s.Trigger = string(v.GetStringBytes("trigger"))
+	if _, exists := webhookTriggers[s.Trigger]; !exists {
+		return errors.Errorf("unknown trigger %q", s.Trigger)
+	}
+
+	s.Secret = string(v.GetStringBytes("secret"))
+	s.Filters = make(map[string]string)
+
+	if filters := v.GetObject("filters"); filters != nil {
+		filters.Visit(func(key []byte, val *fastjson.Value) {
+			if err != nil {
+				return
+			}
+
+			var b []byte
+			if b, err = val.StringBytes(); err != nil {
+				err = errors.Wrapf(err, "filter %q must be a string", key)
+				return
+			}
+
+			s.Filters[string(key)] = string(b)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type webhookResponse struct {
+	// Internal fields.
+	hook *webhook
+
+	// withSecret has the secret of the webhook be rendered, which is only the
+	// case upon registering it.
+	withSecret bool
+}
+
+func (s *webhookResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	return s.getObject(arena).MarshalTo(nil), nil
+}
+
+func (s *webhookResponse) getObject(arena *fastjson.Arena) *fastjson.Value {
+	o := arena.NewObject()
+
+	o.Set("id", arena.NewNumberString(strconv.FormatUint(s.hook.id, 10)))
+	o.Set("url", arena.NewString(s.hook.url))
+	o.Set("trigger", arena.NewString(s.hook.trigger))
+
+	filters := arena.NewObject()
+	for key, val := range s.hook.filters {
+		filters.Set(key, arena.NewString(val))
+	}
+
+	o.Set("filters", filters)
+	o.Set("created_at", arena.NewString(s.hook.created.Format(time.RFC3339)))
+
+	if s.withSecret {
+		o.Set("secret", arena.NewString(string(s.hook.secret)))
+	}
+
+	stats := s.hook.getStats()
+
+	o.Set("delivered", arena.NewNumberString(strconv.FormatUint(stats.delivered, 10)))
+	o.Set("failed", arena.NewNumberString(strconv.FormatUint(stats.failed, 10)))
+	o.Set("dropped", arena.NewNumberString(strconv.FormatUint(stats.dropped, 10)))
+	o.Set("pending", arena.NewNumberInt(len(s.hook.queue)))
+
+	if !stats.lastAttempt.IsZero() {
+		o.Set("last_attempt_at", arena.NewString(stats.lastAttempt.Format(time.RFC3339)))
+		o.Set("last_status", arena.NewNumberInt(stats.lastStatus))
+	}
+
+	if stats.lastError != "" {
+		o.Set("last_error", arena.NewString(stats.lastError))
+	}
+
+	if !stats.lastDelivered.IsZero() {
+		o.Set("last_delivered_at", arena.NewString(stats.lastDelivered.Format(time.RFC3339)))
+	}
+
+	return o
+}
+
+type webhookList []*webhook
+
+func (s webhookList) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	list := arena.NewArray()
+
+	for i, hook := range s {
+		list.SetArrayItem(i, (&webhookResponse{hook: hook}).getObject(arena))
+	}
+
+	return list.MarshalTo(nil), nil
+}
+
 type transaction struct {
 	// Internal fields.
 	tx     *wavelet.Transaction
@@ -325,11 +1569,35 @@ func (s *transaction) getObject(arena *fastjson.Arena) (*fastjson.Value, error)
 	o.Set("height", arena.NewNumberString(strconv.FormatUint(s.tx.Block, 10)))
 	o.Set("tag", arena.NewNumberInt(int(s.tx.Tag)))
 	o.Set("payload", arena.NewString(base64.StdEncoding.EncodeToString(s.tx.Payload)))
+	if s.tx.Scheme != security.SchemeEd25519 {
+		o.Set("scheme", arena.NewNumberInt(int(s.tx.Scheme)))
+	}
+
+	if s.tx.Version != 0 {
+		o.Set("version", arena.NewNumberInt(int(s.tx.Version)))
+	}
+
+	if s.tx.Stamp != 0 {
+		o.Set("stamp", arena.NewNumberString(strconv.FormatUint(s.tx.Stamp, 10)))
+	}
+
+	if s.tx.Tip != 0 {
+		o.Set("tip", arena.NewNumberString(strconv.FormatUint(s.tx.Tip, 10)))
+	}
+
 	o.Set("signature", arena.NewString(hex.EncodeToString(s.tx.Signature[:])))
 
 	return o, nil
 }
 
+func (s *transaction) marshalProto() ([]byte, error) {
+	if s.tx == nil {
+		return nil, errors.New("insufficient fields specified")
+	}
+
+	return s.tx.Proto().Marshal()
+}
+
 type transactionList []*transaction
 
 func (s transactionList) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
@@ -358,6 +1626,14 @@ type account struct {
 	reward     uint64
 	isContract bool
 	numPages   uint64
+
+	feeAllowance wavelet.FeeAllowance
+
+	recoveryConfig  *wavelet.RecoveryConfig
+	pendingRecovery *wavelet.PendingRecovery
+
+	multisig          *wavelet.MultisigAccount
+	multisigProposals map[wavelet.TransactionID]wavelet.MultisigProposal
 }
 
 func (s *account) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
@@ -383,6 +1659,140 @@ func (s *account) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
 		o.Set("num_mem_pages", arena.NewNumberString(strconv.FormatUint(s.numPages, 10)))
 	}
 
+	if s.feeAllowance.Allowance != 0 {
+		o.Set("sponsor", arena.NewString(hex.EncodeToString(s.feeAllowance.Sponsor[:])))
+		o.Set("fee_allowance", arena.NewNumberString(strconv.FormatUint(s.feeAllowance.Allowance, 10)))
+	}
+
+	if s.recoveryConfig != nil {
+		recovery := arena.NewObject()
+
+		recovery.Set("guardians", accountIDsToJSON(arena, s.recoveryConfig.Guardians))
+		recovery.Set("threshold", arena.NewNumberInt(int(s.recoveryConfig.Threshold)))
+		recovery.Set("delay", arena.NewNumberString(strconv.FormatUint(s.recoveryConfig.Delay, 10)))
+
+		o.Set("recovery", recovery)
+	}
+
+	if s.pendingRecovery != nil {
+		pending := arena.NewObject()
+
+		pending.Set("recipient", arena.NewString(hex.EncodeToString(s.pendingRecovery.Recipient[:])))
+		pending.Set("block", arena.NewNumberString(strconv.FormatUint(s.pendingRecovery.Block, 10)))
+		pending.Set("approvals", accountIDsToJSON(arena, s.pendingRecovery.Approvals))
+
+		o.Set("pending_recovery", pending)
+	}
+
+	if s.multisig != nil {
+		multisig := arena.NewObject()
+
+		multisig.Set("participants", accountIDsToJSON(arena, s.multisig.Participants))
+		multisig.Set("threshold", arena.NewNumberInt(int(s.multisig.Threshold)))
+
+		proposals := arena.NewArray()
+		count := 0
+
+		for _, id := range s.multisig.Proposals {
+			proposal, pending := s.multisigProposals[id]
+			if !pending {
+				continue
+			}
+
+			p := arena.NewObject()
+
+			p.Set("id", arena.NewString(hex.EncodeToString(id[:])))
+			p.Set("proposer", arena.NewString(hex.EncodeToString(proposal.Proposer[:])))
+			p.Set("recipient", arena.NewString(hex.EncodeToString(proposal.Recipient[:])))
+			p.Set("amount", arena.NewNumberString(strconv.FormatUint(proposal.Amount, 10)))
+			p.Set("block", arena.NewNumberString(strconv.FormatUint(proposal.Block, 10)))
+			p.Set("approvals", accountIDsToJSON(arena, proposal.Approvals))
+
+			proposals.SetArrayItem(count, p)
+			count++
+		}
+
+		multisig.Set("proposals", proposals)
+
+		o.Set("multisig", multisig)
+	}
+
+	return o.MarshalTo(nil), nil
+}
+
+func accountIDsToJSON(arena *fastjson.Arena, ids []wavelet.AccountID) *fastjson.Value {
+	list := arena.NewArray()
+
+	for i, id := range ids {
+		list.SetArrayItem(i, arena.NewString(hex.EncodeToString(id[:])))
+	}
+
+	return list
+}
+
+func (s *account) marshalProto() ([]byte, error) {
+	if s.ledger == nil || s.id == wavelet.ZeroAccountID {
+		return nil, errors.New("insufficient fields specified")
+	}
+
+	return s.proto().Marshal()
+}
+
+func (s *account) proto() *ledgerpb.Account {
+	return &ledgerpb.Account{
+		Id:         s.id[:],
+		Balance:    s.balance,
+		GasBalance: s.gasBalance,
+		Stake:      s.stake,
+		Reward:     s.reward,
+		IsContract: s.isContract,
+		NumPages:   s.numPages,
+	}
+}
+
+type accountProofResponse struct {
+	// Internal fields.
+	proof wavelet.AccountProof
+	block *wavelet.Block
+}
+
+func (s *accountProofResponse) marshalJSON(arena *fastjson.Arena) ([]byte, error) {
+	o := arena.NewObject()
+
+	o.Set("id", arena.NewString(hex.EncodeToString(s.proof.ID[:])))
+
+	block := arena.NewObject()
+	block.Set("merkle_root", arena.NewString(hex.EncodeToString(s.block.Merkle[:])))
+	block.Set("height", arena.NewNumberString(strconv.FormatUint(s.block.Index, 10)))
+	block.Set("id", arena.NewString(hex.EncodeToString(s.block.ID[:])))
+
+	o.Set("block", block)
+
+	fields := []struct {
+		key   string
+		proof avl.Proof
+	}{
+		{"balance", s.proof.Balance},
+		{"stake", s.proof.Stake},
+		{"reward", s.proof.Reward},
+		{"gas_balance", s.proof.GasBalance},
+	}
+
+	for _, field := range fields {
+		path, lefts := arena.NewArray(), arena.NewArray()
+
+		for i := range field.proof.Path {
+			path.SetArrayItem(i, arena.NewString(hex.EncodeToString(field.proof.Path[i])))
+			lefts.SetArrayItem(i, arena.NewString(hex.EncodeToString(field.proof.Lefts[i])))
+		}
+
+		proof := arena.NewObject()
+		proof.Set("path", path)
+		proof.Set("lefts", lefts)
+
+		o.Set(field.key, proof)
+	}
+
 	return o.MarshalTo(nil), nil
 }
 
@@ -398,11 +1808,26 @@ func (e *errResponse) marshalJSON(arena *fastjson.Arena) []byte {
 
 	if e.Err != nil {
 		o.Set("error", arena.NewString(e.Err.Error()))
+
+		if code, ok := errCodes[errors.Cause(e.Err)]; ok {
+			o.Set("code", arena.NewString(code))
+		}
 	}
 
 	return o.MarshalTo(nil)
 }
 
+// errCodes are machine-readable codes rendered alongside errors caused by
+// them, so that clients need not match against error messages.
+var errCodes = map[error]string{
+	wavelet.ErrInsufficientBalance: "insufficient_balance",
+	wavelet.ErrTxInvalidSignature:  "invalid_signature",
+	wavelet.ErrTxStampTooWeak:      "stamp_too_weak",
+	wavelet.ErrTxNotReplaceable:    "not_replaceable",
+	wavelet.ErrTagInactive:         "tag_inactive",
+	wavelet.ErrInvalidContractCode: "invalid_contract",
+}
+
 func ErrBadRequest(err error) *errResponse { // nolint:golint
 	return &errResponse{
 		Err:            err,
@@ -410,6 +1835,13 @@ func ErrBadRequest(err error) *errResponse { // nolint:golint
 	}
 }
 
+func ErrUnauthorized(err error) *errResponse { // nolint:golint
+	return &errResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusUnauthorized,
+	}
+}
+
 func ErrNotFound(err error) *errResponse { // nolint:golint
 	return &errResponse{
 		Err:            err,
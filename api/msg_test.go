@@ -22,9 +22,17 @@
 package api
 
 import (
+	"encoding/hex"
+	"net/http"
 	"testing"
 
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
 	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
 	"github.com/valyala/fastjson"
 )
 
@@ -108,3 +116,120 @@ func TestSendTransactionRequestMissingFields(t *testing.T) {
 	`
 	assert.Error(t, req.bind(&fastjson.Parser{}, []byte(missingSignature)))
 }
+
+// Not parallel, as the schedule of features is global.
+func TestSendTransactionRequestVersion(t *testing.T) {
+	defer wavelet.ScheduleFeatures(sys.FeatureCanonicalTransactions)()
+
+	body := func(version string) []byte {
+		return []byte(`
+		{
+			"sender": "3132333435363738393031323334353637383930313233343536373839303132",
+			"nonce": 1,
+			"block": 2,
+			"tag": 1,
+			"payload": "7061796C6F6164",
+			` + version + `
+			"signature": "31323334353637383930313233343536373839303132333435363738393031323132333435363738393031323334353637383930313233343536373839303132"
+		}
+	`)
+	}
+
+	req := new(sendTransactionRequest)
+	assert.NoError(t, req.bind(&fastjson.Parser{}, body(``)))
+	assert.Zero(t, req.Version)
+
+	req = new(sendTransactionRequest)
+	assert.NoError(t, req.bind(&fastjson.Parser{}, body(`"version": 1,`)))
+	assert.EqualValues(t, 1, req.Version)
+
+	for _, version := range []string{`"version": 2,`, `"version": -1,`, `"version": "1",`} {
+		assert.Error(t, new(sendTransactionRequest).bind(&fastjson.Parser{}, body(version)))
+	}
+
+	// The canonical encoding may only be signed once it activates at the
+	// block succeeding the one the transaction is created against.
+	sys.FeatureActivations[sys.FeatureCanonicalTransactions] = 4
+	assert.Error(t, new(sendTransactionRequest).bind(&fastjson.Parser{}, body(`"version": 1,`)))
+	assert.NoError(t, new(sendTransactionRequest).bind(&fastjson.Parser{}, body(``)))
+
+	sys.FeatureActivations[sys.FeatureCanonicalTransactions] = 3
+	assert.NoError(t, new(sendTransactionRequest).bind(&fastjson.Parser{}, body(`"version": 1,`)))
+}
+
+// Not parallel, as the schedule of features is global.
+func TestSendTransactionRequestStamp(t *testing.T) {
+	defer wavelet.ScheduleFeatures(sys.FeatureCanonicalTransactions, sys.FeatureStamps)()
+
+	body := func(fields string) []byte {
+		return []byte(`
+		{
+			"sender": "3132333435363738393031323334353637383930313233343536373839303132",
+			"nonce": 1,
+			"block": 2,
+			"tag": 1,
+			"payload": "7061796C6F6164",
+			` + fields + `
+			"signature": "31323334353637383930313233343536373839303132333435363738393031323132333435363738393031323334353637383930313233343536373839303132"
+		}
+	`)
+	}
+
+	req := new(sendTransactionRequest)
+	assert.NoError(t, req.bind(&fastjson.Parser{}, body(`"version": 1, "stamp": 42,`)))
+	assert.EqualValues(t, 42, req.Stamp)
+
+	// Only the canonical encoding signs the stamp.
+	for _, fields := range []string{`"stamp": 42,`, `"version": 1, "stamp": -1,`, `"version": 1, "stamp": "42",`} {
+		assert.Error(t, new(sendTransactionRequest).bind(&fastjson.Parser{}, body(fields)))
+	}
+
+	// Stamps may only be carried once they activate.
+	sys.FeatureActivations[sys.FeatureStamps] = 4
+	assert.Error(t, new(sendTransactionRequest).bind(&fastjson.Parser{}, body(`"version": 1, "stamp": 42,`)))
+}
+
+func TestSignedJSON(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	signature, err := canonical.SignJSON(security.NewEd25519Signer(keys.PrivateKey()), "test", []byte(`{"a":1,"b":2}`))
+	assert.NoError(t, err)
+
+	var signer interface{}
+
+	handler := New().signedJSON("test")(func(ctx *fasthttp.RequestCtx) {
+		signer = ctx.UserValue("signer")
+	})
+
+	serve := func(body string, headers map[string]string) int {
+		var ctx fasthttp.RequestCtx
+
+		ctx.Request.SetBodyString(body)
+
+		for k, v := range headers {
+			ctx.Request.Header.Set(k, v)
+		}
+
+		handler(&ctx)
+
+		return ctx.Response.StatusCode()
+	}
+
+	publicKey := keys.PublicKey()
+
+	headers := map[string]string{
+		canonical.HeaderPublicKey: hex.EncodeToString(publicKey[:]),
+		canonical.HeaderSignature: hex.EncodeToString(signature),
+	}
+
+	// The body may be re-encoded by any JSON library along the way.
+	assert.Equal(t, http.StatusOK, serve(`{ "b": 2, "a": 1.0 }`, headers))
+	assert.Equal(t, wavelet.AccountID(publicKey), signer)
+
+	assert.Equal(t, http.StatusUnauthorized, serve(`{"a":1,"b":3}`, headers))
+	assert.Equal(t, http.StatusUnauthorized, serve(`{"a":1,"b":2}`, nil))
+
+	headers[canonical.HeaderScheme] = "1"
+	assert.Equal(t, http.StatusUnauthorized, serve(`{"a":1,"b":2}`, headers))
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+const securityBearer = "bearer"
+
+// Build returns the OpenAPI document describing routes. The path parameters
+// of every route must be listed in its Params.
+func Build(routes []Route) (*Document, error) {
+	r := newReflector(overrides)
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Wavelet",
+			Description: "HTTP API of a Wavelet node.",
+			Version:     sys.Version,
+		},
+		Paths: make(map[string]*PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				securityBearer: {
+					Type:   "http",
+					Scheme: "bearer",
+					Description: "The secret of the node. Should the node serve an admin API, routes " +
+						"requiring it are served by the admin API alone, and require its token instead.",
+				},
+			},
+		},
+	}
+
+	errorSchema := r.schemaOf(reflect.TypeOf(Error{}))
+
+	for _, route := range routes {
+		op, err := buildOperation(r, route, errorSchema)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %s", route.Method, route.Path)
+		}
+
+		item, exists := doc.Paths[route.Path]
+		if !exists {
+			item = &PathItem{}
+			doc.Paths[route.Path] = item
+		}
+
+		method := strings.ToLower(route.Method)
+		if _, taken := (*item)[method]; taken {
+			return nil, errors.Errorf("%s %s is documented twice", route.Method, route.Path)
+		}
+
+		(*item)[method] = op
+	}
+
+	doc.Components.Schemas = r.schemas
+
+	return doc, nil
+}
+
+func buildOperation(r *reflector, route Route, errorSchema *Schema) (*Operation, error) {
+	op := &Operation{
+		OperationID: route.ID,
+		Summary:     route.Summary,
+		Websocket:   route.Websocket,
+		Responses: map[string]*Response{
+			"default": {
+				Description: "Error.",
+				Content:     map[string]*MediaType{contentJSON: {Schema: errorSchema}},
+			},
+		},
+	}
+
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+
+	if route.Auth {
+		op.Security = []map[string][]string{{securityBearer: {}}}
+	}
+
+	for _, p := range route.Params {
+		if p.In == "path" && !strings.Contains(route.Path, "{"+p.Name+"}") {
+			return nil, errors.Errorf("path parameter %q is not in the path", p.Name)
+		}
+
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        p.Name,
+			In:          p.In,
+			Description: p.Description,
+			Required:    p.In == "path",
+			Schema:      &Schema{Type: p.Type},
+		})
+	}
+
+	for _, segment := range strings.Split(route.Path, "/") {
+		if !strings.HasPrefix(segment, "{") {
+			continue
+		}
+
+		name := strings.Trim(segment, "{}")
+
+		found := false
+		for _, p := range route.Params {
+			found = found || (p.In == "path" && p.Name == name)
+		}
+
+		if !found {
+			return nil, errors.Errorf("path parameter %q is not documented", name)
+		}
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{contentJSON: {Schema: r.schemaOf(reflect.TypeOf(route.Request))}},
+		}
+	}
+
+	switch {
+	case route.Websocket:
+		op.Responses["101"] = &Response{Description: "Switching to the websocket protocol."}
+	case route.Response != nil:
+		op.Responses["200"] = &Response{
+			Description: "OK.",
+			Content:     map[string]*MediaType{contentJSON: {Schema: r.schemaOf(reflect.TypeOf(route.Response))}},
+		}
+	case route.ContentType != "":
+		op.Responses["200"] = &Response{
+			Description: "OK.",
+			Content:     map[string]*MediaType{route.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}
+	default:
+		op.Responses["200"] = &Response{Description: "OK."}
+	}
+
+	return op, nil
+}
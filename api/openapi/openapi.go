@@ -0,0 +1,300 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package openapi describes the HTTP API of a node as an OpenAPI 3 document,
+// and generates typed clients of it for languages other than Go.
+//
+// The document is built from the table of routes of the API, the schemas of
+// requests and responses being reflected from the structs wctl decodes them
+// into. It is generated into package api by cmd/openapi, for nodes to serve
+// it at /swagger.json.
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version is the version of the OpenAPI specification documents conform to.
+const Version = "3.0.2"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps the lowercase HTTP methods a path is served for to their
+// operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+
+	// Websocket is set for operations upgrading the connection to a
+	// websocket, which typed clients leave out.
+	Websocket bool `json:"x-websocket,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema is the subset of JSON schemas OpenAPI supports which the API needs.
+type Schema struct {
+	Ref string `json:"$ref,omitempty"`
+
+	Type        string `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	Nullable    bool   `json:"nullable,omitempty"`
+
+	MinLength int `json:"minLength,omitempty"`
+	MaxLength int `json:"maxLength,omitempty"`
+
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+}
+
+const refPrefix = "#/components/schemas/"
+
+// RefName returns the name of the component s refers to, or an empty string
+// should s not be a reference.
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, refPrefix)
+}
+
+// MarshalIndent encodes doc as indented JSON, its keys being sorted such
+// that the same document always encodes the same.
+func (doc *Document) MarshalIndent() ([]byte, error) {
+	buf, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, '\n'), nil
+}
+
+var (
+	typeTime     = reflect.TypeOf(time.Time{})
+	typeDuration = reflect.TypeOf(time.Duration(0))
+)
+
+// reflector derives schemas from Go types, registering the structs it comes
+// across as components.
+type reflector struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+
+	// overrides replaces the schemas of fields the API encodes differently
+	// from their Go type, keyed by the name of their struct and their JSON
+	// key joined by a dot.
+	overrides map[string]*Schema
+}
+
+func newReflector(overrides map[string]*Schema) *reflector {
+	return &reflector{
+		schemas:   make(map[string]*Schema),
+		names:     make(map[reflect.Type]string),
+		overrides: overrides,
+	}
+}
+
+// schemaOf returns the schema of t, being a reference to a component should
+// t be a named struct.
+func (r *reflector) schemaOf(t reflect.Type) *Schema { // nolint:gocyclo
+	switch t {
+	case typeTime:
+		return &Schema{Type: "string", Format: "date-time"}
+	case typeDuration:
+		return &Schema{Type: "string", Format: "duration", Description: "Go duration, such as 1m30s."}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "hex", MinLength: 2 * t.Len(), MaxLength: 2 * t.Len()}
+		}
+
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "hex"}
+		}
+
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Ptr:
+		elem := r.schemaOf(t.Elem())
+
+		if elem.Ref != "" {
+			return &Schema{AllOf: []*Schema{elem}, Nullable: true}
+		}
+
+		elem.Nullable = true
+
+		return elem
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+
+		return &Schema{Ref: refPrefix + r.component(t)}
+	}
+
+	return &Schema{}
+}
+
+// component registers the named struct t as a component, and returns its
+// name. Structs of different packages sharing a name are told apart by the
+// name of their package.
+func (r *reflector) component(t reflect.Type) string {
+	if name, exists := r.names[t]; exists {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = strings.Title(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+
+	r.names[t] = name
+
+	// The name is reserved before the struct is reflected upon, for structs
+	// referring to themselves to terminate.
+	r.schemas[name] = nil
+	r.schemas[name] = r.structSchema(t)
+
+	return name
+}
+
+// structSchema returns the schema of the struct t as encoding/json encodes
+// it. Fields tagged omitempty, and nullable fields, are not required.
+func (r *reflector) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	r.addFields(s, t)
+
+	sort.Strings(s.Required)
+
+	return s
+}
+
+func (r *reflector) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx != -1 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		// Fields of embedded structs are promoted, as by encoding/json.
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			r.addFields(s, field.Type)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema, overridden := r.overrides[t.Name()+"."+name]
+		if !overridden {
+			schema = r.schemaOf(field.Type)
+		}
+
+		s.Properties[name] = schema
+
+		if !strings.Contains(opts, "omitempty") && !schema.Nullable {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
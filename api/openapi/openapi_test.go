@@ -0,0 +1,172 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package openapi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/perlin-network/wavelet/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// refs returns the names of the components s refers to, directly or not.
+func refs(s *Schema) []string {
+	if s == nil {
+		return nil
+	}
+
+	var names []string
+
+	if s.Ref != "" {
+		names = append(names, s.RefName())
+	}
+
+	names = append(names, refs(s.Items)...)
+	names = append(names, refs(s.AdditionalProperties)...)
+
+	for _, p := range s.Properties {
+		names = append(names, refs(p)...)
+	}
+
+	for _, a := range s.AllOf {
+		names = append(names, refs(a)...)
+	}
+
+	return names
+}
+
+func TestBuild(t *testing.T) {
+	doc, err := Build(Routes)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	var schemas []*Schema
+
+	for _, s := range doc.Components.Schemas {
+		schemas = append(schemas, s)
+	}
+
+	ids := make(map[string]bool)
+
+	for path, item := range doc.Paths {
+		for method, op := range *item {
+			assert.False(t, ids[op.OperationID], "operation ID %s of %s %s is taken", op.OperationID, method, path)
+			ids[op.OperationID] = true
+
+			assert.Contains(t, op.Responses, "default")
+
+			if op.RequestBody != nil {
+				schemas = append(schemas, op.RequestBody.Content[contentJSON].Schema)
+			}
+
+			for _, res := range op.Responses {
+				for _, media := range res.Content {
+					schemas = append(schemas, media.Schema)
+				}
+			}
+		}
+	}
+
+	for _, s := range schemas {
+		for _, name := range refs(s) {
+			assert.NotNil(t, doc.Components.Schemas[name], "component %s is referred to but not declared", name)
+		}
+	}
+
+	tx := doc.Components.Schemas["Transaction"]
+	if assert.NotNil(t, tx) {
+		assert.Equal(t, "byte", tx.Properties["payload"].Format)
+		assert.Equal(t, "hex", tx.Properties["id"].Format)
+		assert.Equal(t, 64, tx.Properties["id"].MaxLength)
+	}
+
+	get := (*doc.Paths["/tx/{id}"])["get"]
+	if assert.NotNil(t, get) && assert.Len(t, get.Parameters, 1) {
+		assert.Equal(t, "path", get.Parameters[0].In)
+		assert.True(t, get.Parameters[0].Required)
+	}
+
+	connect := (*doc.Paths["/node/connect"])["post"]
+	if assert.NotNil(t, connect) {
+		assert.NotEmpty(t, connect.Security)
+	}
+}
+
+// TestSwaggerJSON checks that the document served by nodes was regenerated
+// since the routes or the structs of the API last changed.
+func TestSwaggerJSON(t *testing.T) {
+	doc, err := Build(Routes)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	buf, err := doc.MarshalIndent()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, string(buf), api.SwaggerJSON, "api/swagger_gen.go is out of date; run go generate ./api")
+}
+
+func TestBuildUndocumentedPathParam(t *testing.T) {
+	_, err := Build([]Route{{Method: "GET", Path: "/tx/{id}", ID: "getTransaction"}})
+	assert.Error(t, err)
+
+	_, err = Build([]Route{{Method: "GET", Path: "/tx", ID: "getTransaction", Params: []Param{path("id", "")}}})
+	assert.Error(t, err)
+
+	_, err = Build([]Route{
+		{Method: "GET", Path: "/tx", ID: "a"},
+		{Method: "GET", Path: "/tx", ID: "b"},
+	})
+	assert.Error(t, err)
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	doc, err := Build(Routes)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, GenerateTypeScript(doc, &buf)) {
+		t.FailNow()
+	}
+
+	ts := buf.String()
+
+	assert.True(t, strings.HasPrefix(ts, "// Code generated"))
+	assert.Contains(t, ts, "export interface Transaction {\n")
+	assert.Contains(t, ts, "export interface Error_ {\n")
+	assert.Contains(t, ts, "export class Client {\n")
+	assert.Contains(t, ts,
+		`getTransaction(id: string): Promise<Transaction> {
+    return this.request("GET", "/tx/" + encodeURIComponent(id), undefined, undefined, undefined, "application/json");`)
+	assert.Contains(t, ts, `sendTransaction(body: TxRequest, headers: { "Idempotency-Key"?: string; "traceparent"?: string } = {}): Promise<TxResponse>`)
+	assert.Contains(t, ts, "getSnapshot(): Promise<ArrayBuffer>")
+
+	// Websockets are left out of typed clients.
+	assert.NotContains(t, ts, "/poll/")
+}
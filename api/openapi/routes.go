@@ -0,0 +1,604 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package openapi
+
+import (
+	"time"
+
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/wctl"
+)
+
+const (
+	contentJSON   = "application/json"
+	contentBinary = "application/octet-stream"
+	contentWasm   = "application/wasm"
+	contentText   = "text/plain"
+)
+
+// Route is an endpoint of the API. Paths are templated as by OpenAPI, such
+// as /accounts/{id}, and every parameter in them must be listed in Params.
+type Route struct {
+	Method  string
+	Path    string
+	ID      string
+	Summary string
+	Tag     string
+	Params  []Param
+
+	// Request and Response are values of the types of the JSON bodies of
+	// requests and responses, being nil should there be none.
+	Request  interface{}
+	Response interface{}
+
+	// ContentType is the type of responses which are not JSON.
+	ContentType string
+
+	// Auth is whether requests must carry the secret of the node as a bearer
+	// token, or are served by the admin API should it be enabled.
+	Auth bool
+
+	Websocket bool
+}
+
+// Param is a path, query or header parameter of a route.
+type Param struct {
+	Name        string
+	In          string
+	Type        string
+	Description string
+}
+
+func path(name, description string) Param {
+	return Param{Name: name, In: "path", Type: "string", Description: description}
+}
+
+func query(name, typ, description string) Param {
+	return Param{Name: name, In: "query", Type: typ, Description: description}
+}
+
+func header(name, description string) Param {
+	return Param{Name: name, In: "header", Type: "string", Description: description}
+}
+
+var (
+	paramAccountID  = path("id", "Hex-encoded account ID.")
+	paramContractID = path("id", "Hex-encoded contract ID.")
+	paramTxID       = path("id", "Hex-encoded transaction ID.")
+
+	paramOffset = query("offset", "integer", "Number of items to skip.")
+	paramLimit  = query("limit", "integer", "Maximum number of items returned.")
+
+	paramIdempotencyKey = header(wctl.HeaderIdempotencyKey,
+		"Key unique to the request, for retries of it to be responded to as the first attempt was.")
+	paramTraceparent = header(wctl.HeaderTraceparent,
+		"W3C trace context of the client, for the transaction to be traced as part of its trace.")
+)
+
+// Error is the body of all responses with an error status.
+type Error struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+
+	// Code identifies the cause of some errors, and unlike the error
+	// message is stable.
+	Code string `json:"code,omitempty"`
+}
+
+// The types below describe the bodies of requests and responses for which
+// wctl has no struct matching them.
+
+type EstimateFeeRequest struct {
+	Sender  [32]byte `json:"sender"`
+	Tag     byte     `json:"tag"`
+	Payload []byte   `json:"payload"`
+}
+
+type ContractCallRequest struct {
+	Sender   [32]byte `json:"sender"`
+	Func     string   `json:"func"`
+	Params   []byte   `json:"params"`
+	Amount   uint64   `json:"amount,omitempty"`
+	GasLimit uint64   `json:"gas_limit,omitempty"`
+}
+
+type AVLProof struct {
+	Path  [][]byte `json:"path"`
+	Lefts [][]byte `json:"lefts"`
+}
+
+type AccountProof struct {
+	ID    [32]byte `json:"id"`
+	Block struct {
+		MerkleRoot [16]byte `json:"merkle_root"`
+		Height     uint64   `json:"height"`
+		ID         [32]byte `json:"id"`
+	} `json:"block"`
+
+	Balance    AVLProof `json:"balance"`
+	Stake      AVLProof `json:"stake"`
+	Reward     AVLProof `json:"reward"`
+	GasBalance AVLProof `json:"gas_balance"`
+}
+
+type Randomness struct {
+	Index      uint64   `json:"index"`
+	Randomness [32]byte `json:"randomness"`
+}
+
+type MempoolContains struct {
+	ID       [32]byte `json:"id"`
+	Contains bool     `json:"contains"`
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type GraphQLResponse struct {
+	Data   map[string]interface{}   `json:"data,omitempty"`
+	Errors []map[string]interface{} `json:"errors,omitempty"`
+}
+
+type ConnectRequest struct {
+	Address string `json:"address"`
+}
+
+type RestartRequest struct {
+	Hard bool `json:"hard,omitempty"`
+}
+
+type BanPeerRequest struct {
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+type WebhookRequest struct {
+	URL     string            `json:"url"`
+	Trigger string            `json:"trigger"`
+	Secret  string            `json:"secret,omitempty"`
+	Filters map[string]string `json:"filters,omitempty"`
+}
+
+type Webhook struct {
+	ID        uint64            `json:"id"`
+	URL       string            `json:"url"`
+	Trigger   string            `json:"trigger"`
+	Filters   map[string]string `json:"filters"`
+	CreatedAt time.Time         `json:"created_at"`
+
+	// Secret is only rendered upon registering the webhook.
+	Secret string `json:"secret,omitempty"`
+
+	Delivered uint64 `json:"delivered"`
+	Failed    uint64 `json:"failed"`
+	Dropped   uint64 `json:"dropped"`
+	Pending   int    `json:"pending"`
+
+	LastAttemptAt   *time.Time `json:"last_attempt_at,omitempty"`
+	LastStatus      int        `json:"last_status,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at,omitempty"`
+}
+
+// overrides are the fields of wctl structs the API encodes differently from
+// their Go type.
+var overrides = map[string]*Schema{
+	"Transaction.payload": {Type: "string", Format: "byte", Description: "Base64-encoded payload."},
+	"TimeResponse.time":   {Type: "integer", Format: "int64", Description: "Milliseconds since the Unix epoch."},
+}
+
+// Routes are the endpoints of the API, in the order they are documented.
+var Routes = []Route{
+	// Health.
+	{
+		Method: "GET", Path: "/healthz", ID: "getHealth", Tag: "health",
+		Summary: "Whether the node is live, being its database reachable and consensus not stalled. " +
+			"Responds with 503 should it not be.",
+		Response: wctl.Health{},
+	},
+	{
+		Method: "GET", Path: "/readyz", ID: "getReadiness", Tag: "health",
+		Summary: "Whether the node is live, connected to enough peers and in sync with them. " +
+			"Responds with 503 should it not be.",
+		Response: wctl.Health{},
+	},
+
+	// Ledger.
+	{
+		Method: "GET", Path: "/ledger", ID: "getLedgerStatus", Tag: "ledger",
+		Summary:  "Status of the ledger, as of the latest block or of an archived round.",
+		Params:   []Param{query("round", "integer", "Height of an archived block to report the ledger as of.")},
+		Response: wctl.LedgerStatusResponse{},
+	},
+	{
+		Method: "GET", Path: "/time", ID: "getTime", Tag: "ledger",
+		Summary:  "Time on the clock of the node.",
+		Response: wctl.TimeResponse{},
+	},
+	{
+		Method: "GET", Path: "/snapshot", ID: "getSnapshot", Tag: "ledger",
+		Summary:     "Snapshot of the state of the ledger as of the latest block.",
+		ContentType: contentBinary,
+	},
+	{
+		Method: "GET", Path: "/ledger/gas", ID: "getGasSchedule", Tag: "ledger",
+		Summary:  "Gas schedule smart contracts are charged by.",
+		Response: wctl.GasSchedule{},
+	},
+	{
+		Method: "GET", Path: "/block/{index}/randomness", ID: "getRandomness", Tag: "ledger",
+		Summary:  "Randomness of the beacon recorded for a block.",
+		Params:   []Param{path("index", "Height of the block.")},
+		Response: Randomness{},
+	},
+	{
+		Method: "GET", Path: "/metrics", ID: "getMetrics", Tag: "ledger",
+		Summary:     "Metrics of the node in the Prometheus text format, if enabled.",
+		ContentType: contentText,
+	},
+
+	// Accounts.
+	{
+		Method: "GET", Path: "/accounts/{id}", ID: "getAccount", Tag: "accounts",
+		Summary:  "State of an account, as of the latest block or of an archived round.",
+		Params:   []Param{paramAccountID, query("round", "integer", "Height of an archived block.")},
+		Response: wctl.Account{},
+	},
+	{
+		Method: "GET", Path: "/accounts/{id}/proof", ID: "getAccountProof", Tag: "accounts",
+		Summary:  "Merkle proofs of the balances of an account.",
+		Params:   []Param{paramAccountID, query("block", "integer", "Height of the block to prove against.")},
+		Response: AccountProof{},
+	},
+	{
+		Method: "GET", Path: "/accounts/{id}/delegations", ID: "getDelegations", Tag: "accounts",
+		Summary:  "Stake delegated to and by an account.",
+		Params:   []Param{paramAccountID},
+		Response: wctl.Delegations{},
+	},
+	{
+		Method: "GET", Path: "/accounts/{id}/slashings", ID: "getSlashings", Tag: "accounts",
+		Summary:  "Slashings of the stake of an account.",
+		Params:   []Param{paramAccountID},
+		Response: wctl.Slashings{},
+	},
+
+	// Contracts.
+	{
+		Method: "GET", Path: "/contract/{id}", ID: "getContractCode", Tag: "contracts",
+		Summary:     "WebAssembly code of a contract.",
+		Params:      []Param{paramContractID},
+		ContentType: contentWasm,
+	},
+	{
+		Method: "GET", Path: "/contract/{id}/page", ID: "getContractPages", Tag: "contracts",
+		Summary:     "Memory pages of a contract.",
+		Params:      []Param{paramContractID},
+		ContentType: contentBinary,
+	},
+	{
+		Method: "GET", Path: "/contract/{id}/page/{index}", ID: "getContractPage", Tag: "contracts",
+		Summary:     "A memory page of a contract.",
+		Params:      []Param{paramContractID, path("index", "Index of the page.")},
+		ContentType: contentBinary,
+	},
+	{
+		Method: "GET", Path: "/contract/{id}/upgrades", ID: "getContractUpgrades", Tag: "contracts",
+		Summary:  "Upgrades of the code of a contract.",
+		Params:   []Param{paramContractID},
+		Response: wctl.ContractUpgrades{},
+	},
+	{
+		Method: "POST", Path: "/contract/{id}/call", ID: "callContract", Tag: "contracts",
+		Summary:  "Simulates calling a function of a contract, without sending a transaction.",
+		Params:   []Param{paramContractID},
+		Request:  ContractCallRequest{},
+		Response: wctl.ContractCallResult{},
+	},
+	{
+		Method: "GET", Path: "/contract/{id}/logs", ID: "getContractLogs", Tag: "contracts",
+		Summary: "Logs emitted by a contract.",
+		Params: []Param{
+			paramContractID, paramOffset, paramLimit,
+			query("from_block", "integer", "Height of the first block to list logs of."),
+		},
+		Response: wctl.ContractLogList{},
+	},
+	{
+		Method: "GET", Path: "/contract/{id}/storage/{key}", ID: "getContractStorage", Tag: "contracts",
+		Summary: "Value of a key of the storage of a contract.",
+		Params: []Param{
+			paramContractID, path("key", "Hex-encoded key."),
+			query("proof", "boolean", "Whether to prove the value against the latest block."),
+		},
+		Response: wctl.ContractStorageValue{},
+	},
+	{
+		Method: "GET", Path: "/contract/{id}/storage", ID: "scanContractStorage", Tag: "contracts",
+		Summary: "Entries of the storage of a contract, ordered by key.",
+		Params: []Param{
+			paramContractID,
+			query("prefix", "string", "Hex-encoded prefix of the keys listed."),
+			query("after", "string", "Hex-encoded key after which to list keys."),
+			paramLimit,
+		},
+		Response: wctl.ContractStorageList{},
+	},
+	{
+		Method: "GET", Path: "/contract/{id}/logs/poll", ID: "pollContractLogs", Tag: "contracts",
+		Summary:   "Websocket streaming the logs emitted by a contract.",
+		Params:    []Param{paramContractID},
+		Websocket: true,
+	},
+
+	// Names.
+	{
+		Method: "GET", Path: "/name/{name}", ID: "getName", Tag: "names",
+		Summary:  "Record of a registered name.",
+		Params:   []Param{path("name", "Name to resolve.")},
+		Response: wctl.NameRecord{},
+	},
+
+	// Transactions.
+	{
+		Method: "POST", Path: "/tx/send", ID: "sendTransaction", Tag: "transactions",
+		Summary:  "Submits a signed transaction.",
+		Params:   []Param{paramIdempotencyKey, paramTraceparent},
+		Request:  wctl.TxRequest{},
+		Response: wctl.TxResponse{},
+	},
+	{
+		Method: "POST", Path: "/tx/relay", ID: "relayTransaction", Tag: "transactions",
+		Summary: "Submits a signed transaction on behalf of its sender, the request being signed by the relayer.",
+		Params: []Param{
+			paramIdempotencyKey,
+			paramTraceparent,
+			header(canonical.HeaderPublicKey, "Hex-encoded public key of the relayer."),
+			header(canonical.HeaderSignature, "Hex-encoded signature of the canonical JSON of the body."),
+		},
+		Request:  wctl.TxRequest{},
+		Response: wctl.TxResponse{},
+	},
+	{
+		Method: "POST", Path: "/tx/estimate", ID: "estimateFee", Tag: "transactions",
+		Summary:  "Estimates the fee of a transaction by dry-running it.",
+		Request:  EstimateFeeRequest{},
+		Response: wctl.FeeEstimate{},
+	},
+	{
+		Method: "GET", Path: "/tx/{id}", ID: "getTransaction", Tag: "transactions",
+		Summary:  "A transaction.",
+		Params:   []Param{paramTxID},
+		Response: wctl.Transaction{},
+	},
+	{
+		Method: "GET", Path: "/tx/{id}/data", ID: "getData", Tag: "transactions",
+		Summary:  "Data anchored by a transaction.",
+		Params:   []Param{paramTxID},
+		Response: wctl.AnchoredData{},
+	},
+	{
+		Method: "GET", Path: "/tx/{id}/diff", ID: "getTransactionDiff", Tag: "transactions",
+		Summary:  "Changes a transaction made to the state of accounts.",
+		Params:   []Param{paramTxID},
+		Response: wctl.TransactionDiff{},
+	},
+	{
+		Method: "GET", Path: "/tx", ID: "listTransactions", Tag: "transactions",
+		Summary: "Transactions archived by the node, ordered by height and then by ID.",
+		Params: []Param{
+			query("sender", "string", "Hex-encoded ID of the sender."),
+			query("tag", "integer", "Tag of the transactions."),
+			paramOffset, paramLimit,
+			query("from_height", "integer", "Lowest height of the transactions."),
+			query("to_height", "integer", "Highest height of the transactions."),
+		},
+		Response: []wctl.Transaction{},
+	},
+
+	// Rounds.
+	{
+		Method: "GET", Path: "/rounds", ID: "listRounds", Tag: "rounds",
+		Summary:  "Latest rounds finalized, newest first.",
+		Params:   []Param{query("from", "integer", "Index of the newest round listed."), paramLimit},
+		Response: []wctl.Round{},
+	},
+	{
+		Method: "GET", Path: "/rounds/{index}", ID: "getRound", Tag: "rounds",
+		Summary:  "A round finalized.",
+		Params:   []Param{path("index", "Index of the round.")},
+		Response: wctl.Round{},
+	},
+	{
+		Method: "GET", Path: "/poll/rounds", ID: "pollRounds", Tag: "rounds",
+		Summary:   "Websocket streaming rounds as they are finalized.",
+		Websocket: true,
+	},
+
+	// Validators.
+	{
+		Method: "GET", Path: "/validators", ID: "listValidators", Tag: "validators",
+		Summary:  "Validators as of the latest block.",
+		Response: wctl.Validators{},
+	},
+
+	// Mempool.
+	{
+		Method: "GET", Path: "/mempool", ID: "listMempool", Tag: "mempool",
+		Summary:  "Transactions pending in the mempool.",
+		Params:   []Param{paramOffset, paramLimit},
+		Response: []wctl.Transaction{},
+	},
+	{
+		Method: "GET", Path: "/mempool/count", ID: "countMempool", Tag: "mempool",
+		Summary:  "Number of transactions pending in the mempool.",
+		Response: wctl.MempoolCount{},
+	},
+	{
+		Method: "GET", Path: "/mempool/contains/{id}", ID: "mempoolContains", Tag: "mempool",
+		Summary:  "Whether a transaction is pending in the mempool.",
+		Params:   []Param{paramTxID},
+		Response: MempoolContains{},
+	},
+
+	// GraphQL.
+	{
+		Method: "POST", Path: "/graphql", ID: "graphql", Tag: "graphql",
+		Summary:  "Executes a GraphQL query. GET requests are upgraded to websockets serving subscriptions.",
+		Request:  GraphQLRequest{},
+		Response: GraphQLResponse{},
+	},
+
+	// Relayers.
+	{
+		Method: "GET", Path: "/relayer/{id}", ID: "getRelayer", Tag: "relayers",
+		Summary:  "Statistics of the transactions a relayer submitted.",
+		Params:   []Param{path("id", "Hex-encoded ID of the relayer.")},
+		Response: wctl.RelayerStats{},
+	},
+
+	// Node.
+	{
+		Method: "POST", Path: "/node/connect", ID: "connect", Tag: "node", Auth: true,
+		Summary:  "Connects to a peer.",
+		Request:  ConnectRequest{},
+		Response: wctl.MsgResponse{},
+	},
+	{
+		Method: "POST", Path: "/node/disconnect", ID: "disconnect", Tag: "node", Auth: true,
+		Summary:  "Disconnects from a peer.",
+		Request:  ConnectRequest{},
+		Response: wctl.MsgResponse{},
+	},
+	{
+		Method: "POST", Path: "/node/restart", ID: "restart", Tag: "node", Auth: true,
+		Summary:  "Restarts the node, a hard restart wiping its database.",
+		Request:  RestartRequest{},
+		Response: wctl.MsgResponse{},
+	},
+	{
+		Method: "POST", Path: "/node/snowball", ID: "updateSnowball", Tag: "node", Auth: true,
+		Summary:  "Updates the Snowball parameters the node runs with, zero parameters being kept.",
+		Request:  wctl.SnowballParams{},
+		Response: wctl.SnowballParams{},
+	},
+	{
+		Method: "GET", Path: "/node/loglevels", ID: "getLogLevels", Tag: "node", Auth: true,
+		Summary:  "Log level of every module of the node.",
+		Response: wctl.LogLevels{},
+	},
+	{
+		Method: "POST", Path: "/node/loglevels", ID: "updateLogLevels", Tag: "node", Auth: true,
+		Summary:  "Updates the log levels of the modules keyed, the levels of others being kept.",
+		Request:  wctl.LogLevels{},
+		Response: wctl.LogLevels{},
+	},
+	{
+		Method: "GET", Path: "/node/peers", ID: "listPeerScores", Tag: "node",
+		Summary:  "Reputation scores of the peers of the node.",
+		Response: wctl.PeerScores{},
+	},
+	{
+		Method: "POST", Path: "/node/peers/{id}/ban", ID: "banPeer", Tag: "node", Auth: true,
+		Summary:  "Bans a peer, for the ban duration of the node unless a duration is given.",
+		Params:   []Param{path("id", "Hex-encoded public key of the peer.")},
+		Request:  BanPeerRequest{},
+		Response: wctl.MsgResponse{},
+	},
+	{
+		Method: "DELETE", Path: "/node/peers/{id}/ban", ID: "unbanPeer", Tag: "node", Auth: true,
+		Summary:  "Lifts the ban of a peer, which starts over with a score of 0.",
+		Params:   []Param{path("id", "Hex-encoded public key of the peer.")},
+		Response: wctl.MsgResponse{},
+	},
+	{
+		Method: "POST", Path: "/node/prune", ID: "prune", Tag: "node", Auth: true,
+		Summary:  "Prunes the data of past blocks and compacts the database, should pruning be enabled.",
+		Response: wctl.MsgResponse{},
+	},
+
+	// Webhooks.
+	{
+		Method: "POST", Path: "/webhooks", ID: "registerWebhook", Tag: "webhooks", Auth: true,
+		Summary:  "Registers a webhook.",
+		Request:  WebhookRequest{},
+		Response: Webhook{},
+	},
+	{
+		Method: "GET", Path: "/webhooks", ID: "listWebhooks", Tag: "webhooks", Auth: true,
+		Summary:  "Webhooks registered.",
+		Response: []Webhook{},
+	},
+	{
+		Method: "GET", Path: "/webhooks/{id}", ID: "getWebhook", Tag: "webhooks", Auth: true,
+		Summary:  "A webhook registered.",
+		Params:   []Param{path("id", "ID of the webhook.")},
+		Response: Webhook{},
+	},
+	{
+		Method: "DELETE", Path: "/webhooks/{id}", ID: "deleteWebhook", Tag: "webhooks", Auth: true,
+		Summary:  "Deletes a webhook.",
+		Params:   []Param{path("id", "ID of the webhook.")},
+		Response: wctl.MsgResponse{},
+	},
+
+	// Websockets.
+	{
+		Method: "GET", Path: "/poll/network", ID: "pollNetwork", Tag: "websockets", Websocket: true,
+		Summary: "Websocket streaming peers joining, leaving and being banned.",
+	},
+	{
+		Method: "GET", Path: "/poll/consensus", ID: "pollConsensus", Tag: "websockets", Websocket: true,
+		Summary: "Websocket streaming proposals and finalized blocks.",
+	},
+	{
+		Method: "GET", Path: "/poll/accounts", ID: "pollAccounts", Tag: "websockets", Websocket: true,
+		Summary: "Websocket streaming updates to accounts.",
+		Params:  []Param{query("id", "string", "Hex-encoded ID of the account.")},
+	},
+	{
+		Method: "GET", Path: "/poll/contract", ID: "pollContracts", Tag: "websockets", Websocket: true,
+		Summary: "Websocket streaming the gas spent and logs emitted by contracts.",
+		Params:  []Param{query("id", "string", "Hex-encoded ID of the contract.")},
+	},
+	{
+		Method: "GET", Path: "/poll/tx", ID: "pollTransactions", Tag: "websockets", Websocket: true,
+		Summary: "Websocket streaming transactions being applied, rejected and conflicting.",
+		Params: []Param{
+			query("id", "string", "Hex-encoded ID of the transaction."),
+			query("sender", "string", "Hex-encoded ID of the sender."),
+			query("tag", "integer", "Tag of the transactions."),
+			query("event", "string", "Name of the event, such as applied."),
+		},
+	},
+	{
+		Method: "GET", Path: "/poll/metrics", ID: "pollMetrics", Tag: "websockets", Websocket: true,
+		Summary: "Websocket streaming the metrics of the node every second.",
+	},
+
+	// Documentation.
+	{
+		Method: "GET", Path: "/swagger.json", ID: "getOpenAPI", Tag: "documentation",
+		Summary:  "This document.",
+		Response: map[string]interface{}{},
+	},
+}
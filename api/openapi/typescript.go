@@ -0,0 +1,342 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// tsPrelude is the part of TypeScript clients which does not depend on the
+// document they are generated from.
+const tsPrelude = `export class ApiError extends Error {
+  constructor(public readonly statusCode: number, public readonly body: Error_) {
+    super(body && body.error ? body.error : "HTTP " + statusCode);
+  }
+}
+
+export interface ClientOptions {
+  // token is the secret of the node, required by routes restricted to its
+  // operators, or the token of its admin API should baseURL be that of it.
+  token?: string;
+  fetch?: typeof fetch;
+}
+
+type Query = { [key: string]: string | number | boolean | undefined };
+
+export class Client {
+  private readonly fetch: typeof fetch;
+
+  constructor(private readonly baseURL: string, private readonly options: ClientOptions = {}) {
+    this.fetch = options.fetch || fetch;
+  }
+
+  private async request(
+    method: string,
+    path: string,
+    query?: Query,
+    headers?: { [key: string]: string | undefined },
+    body?: unknown,
+    accept: string = "application/json",
+  ): Promise<any> {
+    const params = Object.entries(query || {})
+      .filter(([, v]) => v !== undefined)
+      .map(([k, v]) => encodeURIComponent(k) + "=" + encodeURIComponent(String(v)))
+      .join("&");
+
+    const init: RequestInit = { method, headers: { Accept: accept } };
+    const h = init.headers as { [key: string]: string };
+
+    for (const [k, v] of Object.entries(headers || {})) {
+      if (v !== undefined) {
+        h[k] = v;
+      }
+    }
+
+    if (this.options.token) {
+      h["Authorization"] = "Bearer " + this.options.token;
+    }
+
+    if (body !== undefined) {
+      h["Content-Type"] = "application/json";
+      init.body = JSON.stringify(body);
+    }
+
+    const res = await this.fetch(this.baseURL + path + (params ? "?" + params : ""), init);
+
+    if (!res.ok) {
+      throw new ApiError(res.status, await res.json().catch(() => undefined));
+    }
+
+    if (accept === "application/json") {
+      return res.json();
+    }
+
+    return accept.startsWith("text/") ? res.text() : res.arrayBuffer();
+  }
+`
+
+// GenerateTypeScript writes a TypeScript client of the API described by doc
+// to w. Every schema of the document is declared as an interface, and every
+// operation which does not upgrade to a websocket as a method of the class
+// Client, named after the operation ID.
+func GenerateTypeScript(doc *Document, w io.Writer) error {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by cmd/openapi from the OpenAPI document of %s %s. DO NOT EDIT.\n\n",
+		doc.Info.Title, doc.Info.Version)
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(&b, "export interface %s %s\n\n", tsName(name), tsObject(doc.Components.Schemas[name], ""))
+	}
+
+	b.WriteString(tsPrelude)
+
+	for _, op := range sortedOperations(doc) {
+		if op.Websocket {
+			continue
+		}
+
+		b.WriteString("\n")
+		tsMethod(&b, op)
+	}
+
+	b.WriteString("}\n")
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// tsName returns the TypeScript name of the component name, Error being
+// renamed for it not to shadow the builtin class of the same name.
+func tsName(name string) string {
+	if name == "Error" {
+		return "Error_"
+	}
+
+	return name
+}
+
+// tsType returns the TypeScript type of values of the schema s.
+func tsType(s *Schema, indent string) string {
+	var typ string
+
+	switch {
+	case s.Ref != "":
+		typ = tsName(s.RefName())
+	case len(s.AllOf) == 1:
+		typ = tsType(s.AllOf[0], indent)
+	case s.Type == "string":
+		typ = "string"
+	case s.Type == "integer" || s.Type == "number":
+		typ = "number"
+	case s.Type == "boolean":
+		typ = "boolean"
+	case s.Type == "array":
+		typ = "Array<" + tsType(s.Items, indent) + ">"
+	case s.Type == "object" && s.Properties == nil && s.AdditionalProperties != nil:
+		typ = "{ [key: string]: " + tsType(s.AdditionalProperties, indent) + " }"
+	case s.Type == "object":
+		typ = tsObject(s, indent)
+	default:
+		typ = "unknown"
+	}
+
+	if s.Nullable {
+		typ += " | null"
+	}
+
+	return typ
+}
+
+// tsObject returns the TypeScript type of objects of the schema s, fields
+// which are not required being optional.
+func tsObject(s *Schema, indent string) string {
+	if len(s.Properties) == 0 {
+		return "{}"
+	}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+
+	b.WriteString("{\n")
+
+	for _, key := range keys {
+		optional := "?"
+		if required[key] {
+			optional = ""
+		}
+
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, tsKey(key), optional, tsType(s.Properties[key], indent+"  "))
+	}
+
+	b.WriteString(indent + "}")
+
+	return b.String()
+}
+
+// tsKey quotes the object key key should it not be a valid identifier.
+func tsKey(key string) string {
+	for i, c := range key {
+		if !(c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			return fmt.Sprintf("%q", key)
+		}
+	}
+
+	return key
+}
+
+// tsIdent turns the parameter name into a TypeScript identifier.
+func tsIdent(name string) string {
+	parts := strings.FieldsFunc(name, func(c rune) bool { return c == '-' || c == '_' })
+
+	for i := range parts {
+		if i == 0 {
+			parts[i] = strings.ToLower(parts[i][:1]) + parts[i][1:]
+		} else {
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+// operation is an operation of a document, alongside its method and path.
+type operation struct {
+	*Operation
+
+	method string
+	path   string
+}
+
+// sortedOperations returns the operations of doc ordered by path and method.
+func sortedOperations(doc *Document) []operation {
+	var ops []operation
+
+	for path, item := range doc.Paths {
+		for method, op := range *item {
+			ops = append(ops, operation{Operation: op, method: method, path: path})
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].path != ops[j].path {
+			return ops[i].path < ops[j].path
+		}
+
+		return ops[i].method < ops[j].method
+	})
+
+	return ops
+}
+
+// tsMethod writes the method of the class Client calling op. Path parameters
+// are positional arguments, and query and header parameters are grouped into
+// optional objects following the body of the request.
+func tsMethod(b *bytes.Buffer, op operation) {
+	var (
+		args          []string
+		path          = "\"" + op.path + "\""
+		query, header []string
+	)
+
+	for _, p := range op.Parameters {
+		ident := tsIdent(p.Name)
+
+		switch p.In {
+		case "path":
+			args = append(args, ident+": "+tsType(p.Schema, ""))
+			path = strings.Replace(path, "{"+p.Name+"}", "\" + encodeURIComponent("+ident+") + \"", 1)
+		case "query":
+			query = append(query, fmt.Sprintf("%s?: %s", tsKey(p.Name), tsType(p.Schema, "")))
+		case "header":
+			header = append(header, fmt.Sprintf("%q?: string", p.Name))
+		}
+	}
+
+	path = strings.TrimSuffix(path, " + \"\"")
+
+	body := "undefined"
+
+	if op.RequestBody != nil {
+		args = append(args, "body: "+tsType(op.RequestBody.Content[contentJSON].Schema, "  "))
+		body = "body"
+	}
+
+	queryArg, headerArg := "undefined", "undefined"
+
+	if len(query) > 0 {
+		args = append(args, "query: { "+strings.Join(query, "; ")+" } = {}")
+		queryArg = "query"
+	}
+
+	if len(header) > 0 {
+		args = append(args, "headers: { "+strings.Join(header, "; ")+" } = {}")
+		headerArg = "headers"
+	}
+
+	result, accept := "void", contentJSON
+
+	if res := op.Responses["200"]; res != nil {
+		for contentType, media := range res.Content {
+			accept = contentType
+
+			switch {
+			case contentType == contentJSON:
+				result = tsType(media.Schema, "  ")
+			case strings.HasPrefix(contentType, "text/"):
+				result = "string"
+			default:
+				result = "ArrayBuffer"
+			}
+		}
+	}
+
+	if op.Summary != "" {
+		fmt.Fprintf(b, "  // %s\n", op.Summary)
+	}
+
+	fmt.Fprintf(b, "  %s(%s): Promise<%s> {\n", op.OperationID, strings.Join(args, ", "), result)
+	fmt.Fprintf(b, "    return this.request(%q, %s, %s, %s, %s, %q);\n",
+		strings.ToUpper(op.method), path, queryArg, headerArg, body, accept)
+	b.WriteString("  }\n")
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perlin-network/wavelet"
+)
+
+// peerBook tracks since when peers have been connected to the node, which
+// stands in for the uptime of validators as observed by the node.
+type peerBook struct {
+	sync.Mutex
+	started   time.Time
+	connected map[wavelet.AccountID]time.Time
+}
+
+func newPeerBook() *peerBook {
+	return &peerBook{started: time.Now(), connected: make(map[wavelet.AccountID]time.Time)}
+}
+
+func (b *peerBook) join(id wavelet.AccountID) {
+	b.Lock()
+	if _, exists := b.connected[id]; !exists {
+		b.connected[id] = time.Now()
+	}
+	b.Unlock()
+}
+
+func (b *peerBook) leave(id wavelet.AccountID) {
+	b.Lock()
+	delete(b.connected, id)
+	b.Unlock()
+}
+
+// since returns since when the peer id has been connected to the node, or
+// false if it is not connected.
+func (b *peerBook) since(id wavelet.AccountID) (time.Time, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	since, connected := b.connected[id]
+
+	return since, connected
+}
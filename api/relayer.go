@@ -0,0 +1,111 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perlin-network/wavelet"
+)
+
+// maxRelayers is the maximum number of relayers accounted for, past which the
+// relayer seen least recently is forgotten.
+const maxRelayers = 4096
+
+// relayerStats accounts for the transactions a relayer submitted on behalf of
+// their senders through the node.
+type relayerStats struct {
+	submitted uint64
+	accepted  uint64
+	rejected  uint64
+
+	// fees sums up the fees of the transactions accepted, which relayers may
+	// settle with senders off-chain.
+	fees uint64
+
+	lastSeen time.Time
+}
+
+// relayerBook accounts for relayers since the node started.
+type relayerBook struct {
+	sync.Mutex
+	stats map[wavelet.AccountID]*relayerStats
+}
+
+func newRelayerBook() *relayerBook {
+	return &relayerBook{stats: make(map[wavelet.AccountID]*relayerStats)}
+}
+
+// record accounts for a transaction relayer submitted, tx being nil should
+// it have been rejected.
+func (b *relayerBook) record(relayer wavelet.AccountID, tx *wavelet.Transaction) {
+	b.Lock()
+	defer b.Unlock()
+
+	stats, exists := b.stats[relayer]
+	if !exists {
+		if len(b.stats) >= maxRelayers {
+			b.evict()
+		}
+
+		stats = new(relayerStats)
+		b.stats[relayer] = stats
+	}
+
+	stats.submitted++
+	stats.lastSeen = time.Now()
+
+	if tx == nil {
+		stats.rejected++
+		return
+	}
+
+	stats.accepted++
+	stats.fees += tx.Fee()
+}
+
+// evict forgets the relayer seen least recently.
+func (b *relayerBook) evict() {
+	var (
+		oldest   wavelet.AccountID
+		lastSeen time.Time
+	)
+
+	for id, stats := range b.stats {
+		if lastSeen.IsZero() || stats.lastSeen.Before(lastSeen) {
+			oldest, lastSeen = id, stats.lastSeen
+		}
+	}
+
+	delete(b.stats, oldest)
+}
+
+func (b *relayerBook) get(relayer wavelet.AccountID) (relayerStats, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	stats, exists := b.stats[relayer]
+	if !exists {
+		return relayerStats{}, false
+	}
+
+	return *stats, true
+}
@@ -0,0 +1,423 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rosetta
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// The Construction API builds transfers of PERLs to accounts, transactions
+// placing and withdrawing stake, and transactions withdrawing rewards. Each
+// is of the version wavelet.TransactionVersionAt its block, signed with
+// Ed25519, and is described by the operations it intends to make other than
+// paying its fee:
+//
+//	transfer: a TRANSFER operation debiting the sender, and another crediting the recipient
+//	placing or withdrawing stake: a STAKE operation crediting or debiting the sender's stake sub-account
+//	withdrawing rewards: a REWARD operation debiting the sender's reward sub-account
+//
+// Unsigned and signed transactions are hex-encoded in the wire format of
+// transactions, unsigned transactions bearing an empty signature.
+
+func (s *Server) constructionDerive(body []byte) (interface{}, *Error) {
+	var req ConstructionDeriveRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	if req.PublicKey.CurveType != CurveType {
+		return nil, ErrInvalidRequest.wrap(errors.Errorf("public keys must be of curve type %s", CurveType))
+	}
+
+	id, err := decodeID(req.PublicKey.HexBytes)
+	if err != nil {
+		return nil, ErrInvalidRequest.wrap(errors.Wrap(err, "public key is invalid"))
+	}
+
+	return ConstructionDeriveResponse{
+		AccountIdentifier: AccountIdentifier{Address: hex.EncodeToString(id[:])},
+	}, nil
+}
+
+func (s *Server) constructionPreprocess(body []byte) (interface{}, *Error) {
+	var req ConstructionPreprocessRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	_, tag, payload, err := payloadOf(req.Operations)
+	if err != nil {
+		return nil, ErrInvalidOperations.wrap(err)
+	}
+
+	tx := wavelet.Transaction{Tag: tag, Payload: payload}
+
+	return ConstructionPreprocessResponse{Options: ConstructionOptions{Fee: tx.Fee()}}, nil
+}
+
+func (s *Server) constructionMetadata(body []byte) (interface{}, *Error) {
+	var req ConstructionMetadataRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	// Nonces need only be unique per sender, and so are taken from the
+	// clock like wctl does.
+	return ConstructionMetadataResponse{
+		Metadata: ConstructionMetadata{
+			Block: s.ledger.Blocks().Latest().Index,
+			Nonce: uint64(time.Now().UnixNano()),
+		},
+		SuggestedFee: []Amount{{Value: strconv.FormatUint(req.Options.Fee, 10), Currency: PERL}},
+	}, nil
+}
+
+func (s *Server) constructionPayloads(body []byte) (interface{}, *Error) {
+	var req ConstructionPayloadsRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	sender, tag, payload, err := payloadOf(req.Operations)
+	if err != nil {
+		return nil, ErrInvalidOperations.wrap(err)
+	}
+
+	tx := wavelet.Transaction{
+		Sender:  sender,
+		Nonce:   req.Metadata.Nonce,
+		Block:   req.Metadata.Block,
+		Tag:     tag,
+		Payload: payload,
+		Version: wavelet.TransactionVersionAt(req.Metadata.Block),
+	}
+
+	return ConstructionPayloadsResponse{
+		UnsignedTransaction: hex.EncodeToString(tx.Marshal()),
+		Payloads: []SigningPayload{{
+			HexBytes:          hex.EncodeToString(tx.Message()),
+			AccountIdentifier: &AccountIdentifier{Address: hex.EncodeToString(sender[:])},
+			SignatureType:     SignatureType,
+		}},
+	}, nil
+}
+
+func (s *Server) constructionCombine(body []byte) (interface{}, *Error) {
+	var req ConstructionCombineRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	tx, e := decodeTransaction(req.UnsignedTransaction)
+	if e != nil {
+		return nil, e
+	}
+
+	if len(req.Signatures) != 1 {
+		return nil, ErrInvalidRequest.wrap(errors.New("transactions are signed by exactly one signature"))
+	}
+
+	sig := req.Signatures[0]
+
+	if sig.SignatureType != SignatureType {
+		return nil, ErrInvalidRequest.wrap(errors.Errorf("signatures must be of type %s", SignatureType))
+	}
+
+	if sig.PublicKey.HexBytes != hex.EncodeToString(tx.Sender[:]) {
+		return nil, ErrInvalidRequest.wrap(errors.New("transactions must be signed by their sender"))
+	}
+
+	buf, err := hex.DecodeString(sig.HexBytes)
+	if err != nil || len(buf) != wavelet.SizeSignature {
+		return nil, ErrInvalidRequest.wrap(errors.Errorf("signatures must be %d hex-encoded bytes", wavelet.SizeSignature))
+	}
+
+	if err := security.Verify(tx.Scheme, tx.Sender[:], tx.Message(), buf); err != nil {
+		return nil, ErrInvalidRequest.wrap(err)
+	}
+
+	var signature wavelet.Signature
+	copy(signature[:], buf)
+
+	tx = wavelet.NewSignedTippedTransaction(
+		tx.Version, tx.Scheme, tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Stamp, tx.Tip, signature,
+	)
+
+	return ConstructionCombineResponse{SignedTransaction: hex.EncodeToString(tx.Marshal())}, nil
+}
+
+func (s *Server) constructionParse(body []byte) (interface{}, *Error) {
+	var req ConstructionParseRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	tx, e := decodeTransaction(req.Transaction)
+	if e != nil {
+		return nil, e
+	}
+
+	ops, err := intent(tx)
+	if err != nil {
+		return nil, ErrInvalidTransaction.wrap(err)
+	}
+
+	res := ConstructionParseResponse{Operations: ops}
+
+	if req.Signed {
+		res.AccountIdentifierSigners = []AccountIdentifier{{Address: hex.EncodeToString(tx.Sender[:])}}
+	}
+
+	return res, nil
+}
+
+func (s *Server) constructionHash(body []byte) (interface{}, *Error) {
+	var req ConstructionHashRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	tx, e := decodeTransaction(req.SignedTransaction)
+	if e != nil {
+		return nil, e
+	}
+
+	return TransactionIdentifierResponse{
+		TransactionIdentifier: TransactionIdentifier{Hash: hex.EncodeToString(tx.ID[:])},
+	}, nil
+}
+
+func (s *Server) constructionSubmit(body []byte) (interface{}, *Error) {
+	var req ConstructionSubmitRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	tx, e := decodeTransaction(req.SignedTransaction)
+	if e != nil {
+		return nil, e
+	}
+
+	if err := wavelet.ValidateTransaction(s.ledger.Snapshot(), tx); err != nil {
+		return nil, ErrTransactionRejected.wrap(err)
+	}
+
+	if err := s.ledger.AdmitTransaction(tx); err != nil {
+		return nil, ErrTransactionRejected.wrap(err)
+	}
+
+	if err := s.ledger.Transactions().CheckReplacement(tx); err != nil {
+		return nil, ErrTransactionRejected.wrap(err)
+	}
+
+	s.ledger.AddTransaction(tx)
+
+	return TransactionIdentifierResponse{
+		TransactionIdentifier: TransactionIdentifier{Hash: hex.EncodeToString(tx.ID[:])},
+	}, nil
+}
+
+func decodeTransaction(s string) (wavelet.Transaction, *Error) {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return wavelet.Transaction{}, ErrInvalidTransaction.wrap(err)
+	}
+
+	tx, err := wavelet.ParseTransaction(buf)
+	if err != nil {
+		return tx, ErrInvalidTransaction.wrap(err)
+	}
+
+	return tx, nil
+}
+
+// intent returns the operations tx intends to make other than paying its fee,
+// should tx be a transaction the Construction API builds.
+func intent(tx wavelet.Transaction) ([]Operation, error) {
+	sender := hex.EncodeToString(tx.Sender[:])
+
+	switch tx.Tag {
+	case sys.TagTransfer:
+		transfer, err := wavelet.ParseTransfer(tx.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if transfer.GasLimit != 0 || transfer.GasDeposit != 0 || len(transfer.FuncName) > 0 {
+			break
+		}
+
+		amount := strconv.FormatUint(transfer.Amount, 10)
+
+		return []Operation{
+			intendedOperation(0, OpTransfer, sender, "", "-"+amount),
+			intendedOperation(1, OpTransfer, hex.EncodeToString(transfer.Recipient[:]), "", amount),
+		}, nil
+	case sys.TagStake:
+		stake, err := wavelet.ParseStake(tx.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		amount := strconv.FormatUint(stake.Amount, 10)
+
+		switch stake.Opcode {
+		case sys.PlaceStake:
+			return []Operation{intendedOperation(0, OpStake, sender, SubAccountStake, amount)}, nil
+		case sys.WithdrawStake:
+			return []Operation{intendedOperation(0, OpStake, sender, SubAccountStake, "-"+amount)}, nil
+		case sys.WithdrawReward:
+			return []Operation{intendedOperation(0, OpReward, sender, SubAccountReward, "-"+amount)}, nil
+		}
+	}
+
+	return nil, errors.Errorf("transactions of tag %d other than transfers to accounts, stakes, and reward "+
+		"withdrawals are not supported", tx.Tag)
+}
+
+func intendedOperation(index int64, typ, address, sub, value string) Operation {
+	account := &AccountIdentifier{Address: address}
+	if sub != "" {
+		account.SubAccount = &SubAccountIdentifier{Address: sub}
+	}
+
+	return Operation{
+		OperationIdentifier: OperationIdentifier{Index: index},
+		Type:                typ,
+		Account:             account,
+		Amount:              &Amount{Value: value, Currency: PERL},
+	}
+}
+
+// payloadOf returns the sender, tag, and payload of the transaction intending
+// to make the operations ops, being those intent returns.
+func payloadOf(ops []Operation) (sender wavelet.AccountID, tag sys.Tag, payload []byte, err error) {
+	switch {
+	case len(ops) == 2 && ops[0].Type == OpTransfer && ops[1].Type == OpTransfer:
+		sender, payload, err = transferPayloadOf(ops[0], ops[1])
+		tag = sys.TagTransfer
+	case len(ops) == 1 && (ops[0].Type == OpStake || ops[0].Type == OpReward):
+		sender, payload, err = stakePayloadOf(ops[0])
+		tag = sys.TagStake
+	default:
+		err = errors.New("operations must be of a transfer, a stake, or a reward withdrawal")
+	}
+
+	if err != nil {
+		return sender, tag, nil, err
+	}
+
+	if _, err = wavelet.ParsePayload(tag, payload); err != nil {
+		return sender, tag, nil, err
+	}
+
+	return sender, tag, payload, nil
+}
+
+func transferPayloadOf(from, to Operation) (wavelet.AccountID, []byte, error) {
+	if to.Amount != nil && strings.HasPrefix(to.Amount.Value, "-") {
+		from, to = to, from
+	}
+
+	sender, fromNegative, fromAmount, err := parseOperation(from, "")
+	if err != nil {
+		return sender, nil, err
+	}
+
+	recipient, toNegative, toAmount, err := parseOperation(to, "")
+	if err != nil {
+		return sender, nil, err
+	}
+
+	if !fromNegative || toNegative || fromAmount != toAmount {
+		return sender, nil, errors.New("transfers must debit the sender the amount credited to the recipient")
+	}
+
+	payload, err := wavelet.Transfer{Recipient: recipient, Amount: toAmount}.Marshal()
+
+	return sender, payload, err
+}
+
+func stakePayloadOf(op Operation) (wavelet.AccountID, []byte, error) {
+	sub := SubAccountStake
+	if op.Type == OpReward {
+		sub = SubAccountReward
+	}
+
+	sender, negative, amount, err := parseOperation(op, sub)
+	if err != nil {
+		return sender, nil, err
+	}
+
+	stake := wavelet.Stake{Opcode: sys.PlaceStake, Amount: amount}
+
+	switch {
+	case op.Type == OpReward && !negative:
+		return sender, nil, errors.New("rewards may only be withdrawn")
+	case op.Type == OpReward:
+		stake.Opcode = sys.WithdrawReward
+	case negative:
+		stake.Opcode = sys.WithdrawStake
+	}
+
+	payload, err := stake.Marshal()
+
+	return sender, payload, err
+}
+
+// parseOperation returns the account op is made to, which must be under the
+// sub-account sub, and the sign and magnitude of its amount.
+func parseOperation(op Operation, sub string) (id wavelet.AccountID, negative bool, amount uint64, err error) {
+	if op.Account == nil || op.Amount == nil {
+		return id, false, 0, errors.New("operations must specify their account and amount")
+	}
+
+	if (op.Account.SubAccount == nil && sub != "") ||
+		(op.Account.SubAccount != nil && op.Account.SubAccount.Address != sub) {
+		return id, false, 0, errors.Errorf("%s operations must be made to sub-account %q", op.Type, sub)
+	}
+
+	if op.Amount.Currency != PERL {
+		return id, false, 0, errors.Errorf("amounts must be of currency %s", PERL.Symbol)
+	}
+
+	if id, err = decodeID(op.Account.Address); err != nil {
+		return id, false, 0, errors.Wrap(err, "address is invalid")
+	}
+
+	value := op.Amount.Value
+	if negative = strings.HasPrefix(value, "-"); negative {
+		value = value[1:]
+	}
+
+	if amount, err = strconv.ParseUint(value, 10, 64); err != nil {
+		return id, false, 0, errors.Wrap(err, "amount is invalid")
+	}
+
+	return id, negative, amount, nil
+}
@@ -0,0 +1,425 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rosetta
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+func (s *Server) networkList(body []byte) (interface{}, *Error) {
+	return NetworkListResponse{NetworkIdentifiers: []NetworkIdentifier{s.network}}, nil
+}
+
+func (s *Server) networkStatus(body []byte) (interface{}, *Error) {
+	var req NetworkRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	latest := s.ledger.Blocks().Latest()
+	oldest := s.oldestBlock()
+
+	// Our node may no longer keep the genesis block, in which case the oldest
+	// block it keeps stands in for it.
+	genesis := s.findBlock(0)
+	if genesis == nil {
+		genesis = oldest
+	}
+
+	res := NetworkStatusResponse{
+		CurrentBlockIdentifier: blockIdentifier(latest),
+		GenesisBlockIdentifier: blockIdentifier(genesis),
+		OldestBlockIdentifier:  blockIdentifier(oldest),
+		Peers:                  []Peer{},
+	}
+
+	if s.client != nil {
+		for _, peer := range s.client.ClosestPeerIDs() {
+			publicKey := peer.PublicKey()
+
+			res.Peers = append(res.Peers, Peer{
+				PeerID:   hex.EncodeToString(publicKey[:]),
+				Metadata: map[string]interface{}{"address": peer.Address()},
+			})
+		}
+	}
+
+	return res, nil
+}
+
+func (s *Server) networkOptions(body []byte) (interface{}, *Error) {
+	var req NetworkRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	return NetworkOptionsResponse{
+		Version: Version{RosettaVersion: RosettaVersion, NodeVersion: sys.Version},
+		Allow: Allow{
+			OperationStatuses:       []OperationStatus{{Status: StatusSuccess, Successful: true}},
+			OperationTypes:          operationTypes(),
+			Errors:                  errs,
+			HistoricalBalanceLookup: s.ledger.Archival(),
+		},
+	}, nil
+}
+
+func (s *Server) block(body []byte) (interface{}, *Error) {
+	var req BlockRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	block, e := s.lookupBlock(req.BlockIdentifier)
+	if e != nil {
+		return nil, e
+	}
+
+	res := &Block{
+		BlockIdentifier:       blockIdentifier(block),
+		ParentBlockIdentifier: blockIdentifier(block),
+		Transactions:          make([]Transaction, 0, len(block.Transactions)+1),
+	}
+
+	if block.Index > 0 {
+		res.ParentBlockIdentifier = BlockIdentifier{Index: int64(block.Index - 1)}
+
+		if parent := s.findBlock(block.Index - 1); parent != nil {
+			res.ParentBlockIdentifier.Hash = hex.EncodeToString(parent.ID[:])
+		}
+	}
+
+	for _, id := range block.Transactions {
+		tx, e := s.blockTransactionOf(id)
+		if e != nil {
+			return nil, e
+		}
+
+		res.Transactions = append(res.Transactions, tx)
+	}
+
+	// The changes made by the block itself are reported as a transaction
+	// whose hash is the ID of the block.
+	diff, e := s.diff(block.ID)
+	if e != nil {
+		return nil, e
+	}
+
+	if diff != nil {
+		res.Transactions = append(res.Transactions, Transaction{
+			TransactionIdentifier: TransactionIdentifier{Hash: hex.EncodeToString(block.ID[:])},
+			Operations:            blockOperations(*diff),
+		})
+	}
+
+	return BlockResponse{Block: res}, nil
+}
+
+func (s *Server) blockTransaction(body []byte) (interface{}, *Error) {
+	var req BlockTransactionRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	index, hash := req.BlockIdentifier.Index, req.BlockIdentifier.Hash
+
+	block, e := s.lookupBlock(PartialBlockIdentifier{Index: &index, Hash: &hash})
+	if e != nil {
+		return nil, e
+	}
+
+	id, err := decodeID(req.TransactionIdentifier.Hash)
+	if err != nil {
+		return nil, ErrInvalidRequest.wrap(errors.Wrap(err, "transaction hash is invalid"))
+	}
+
+	if id == block.ID {
+		diff, e := s.diff(block.ID)
+		if e != nil {
+			return nil, e
+		}
+
+		if diff != nil {
+			return BlockTransactionResponse{Transaction: Transaction{
+				TransactionIdentifier: req.TransactionIdentifier,
+				Operations:            blockOperations(*diff),
+			}}, nil
+		}
+	}
+
+	for _, included := range block.Transactions {
+		if included != id {
+			continue
+		}
+
+		tx, e := s.blockTransactionOf(id)
+		if e != nil {
+			return nil, e
+		}
+
+		return BlockTransactionResponse{Transaction: tx}, nil
+	}
+
+	return nil, ErrTransactionNotFound.wrap(errors.Errorf("transaction %x is not a part of block %d", id, block.Index))
+}
+
+func (s *Server) accountBalance(body []byte) (interface{}, *Error) {
+	var req AccountBalanceRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	id, err := decodeID(req.AccountIdentifier.Address)
+	if err != nil {
+		return nil, ErrInvalidAccount.wrap(errors.Wrap(err, "address is invalid"))
+	}
+
+	snapshot, block, e := s.snapshotAt(req.BlockIdentifier)
+	if e != nil {
+		return nil, e
+	}
+
+	var balance uint64
+
+	switch sub := req.AccountIdentifier.SubAccount; {
+	case sub == nil:
+		balance, _ = wavelet.ReadAccountBalance(snapshot, id)
+	case sub.Address == SubAccountStake:
+		balance, _ = wavelet.ReadAccountStake(snapshot, id)
+	case sub.Address == SubAccountReward:
+		balance, _ = wavelet.ReadAccountReward(snapshot, id)
+	case sub.Address == SubAccountGas:
+		balance, _ = wavelet.ReadAccountContractGasBalance(snapshot, id)
+	default:
+		return nil, ErrInvalidAccount.wrap(errors.Errorf("unknown sub-account %q", sub.Address))
+	}
+
+	return AccountBalanceResponse{
+		BlockIdentifier: blockIdentifier(block),
+		Balances:        []Amount{{Value: strconv.FormatUint(balance, 10), Currency: PERL}},
+	}, nil
+}
+
+func (s *Server) mempool(body []byte) (interface{}, *Error) {
+	var req NetworkRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	res := MempoolResponse{TransactionIdentifiers: []TransactionIdentifier{}}
+
+	for _, id := range s.ledger.Transactions().PendingIDs() {
+		res.TransactionIdentifiers = append(res.TransactionIdentifiers,
+			TransactionIdentifier{Hash: hex.EncodeToString(id[:])})
+	}
+
+	return res, nil
+}
+
+func (s *Server) mempoolTransaction(body []byte) (interface{}, *Error) {
+	var req MempoolTransactionRequest
+	if err := s.decode(body, &req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+
+	id, err := decodeID(req.TransactionIdentifier.Hash)
+	if err != nil {
+		return nil, ErrInvalidRequest.wrap(errors.Wrap(err, "transaction hash is invalid"))
+	}
+
+	latest := s.ledger.Blocks().Latest()
+
+	tx := s.ledger.Transactions().Find(id)
+	if tx == nil || !s.ledger.Transactions().HasPending(latest.ID, id) {
+		return nil, ErrTransactionNotFound.wrap(errors.Errorf("transaction %x is not pending", id))
+	}
+
+	// The operations of a pending transaction are those it intends to make,
+	// being unknown for transactions the Construction API does not build.
+	ops, _ := intent(*tx)
+	if ops == nil {
+		ops = []Operation{}
+	}
+
+	return MempoolTransactionResponse{Transaction: Transaction{
+		TransactionIdentifier: req.TransactionIdentifier,
+		Operations:            ops,
+	}}, nil
+}
+
+// blockTransactionOf returns the transaction with ID id, which a block
+// includes. Transactions the block rejected made no changes, and so have no
+// operations.
+func (s *Server) blockTransactionOf(id wavelet.TransactionID) (Transaction, *Error) {
+	res := Transaction{
+		TransactionIdentifier: TransactionIdentifier{Hash: hex.EncodeToString(id[:])},
+		Operations:            []Operation{},
+	}
+
+	diff, e := s.diff(id)
+	if e != nil || diff == nil {
+		return res, e
+	}
+
+	res.Operations = transactionOperations(*diff, s.ledger.Transactions().Find(id), StatusSuccess)
+
+	return res, nil
+}
+
+// diff returns the changes made by the transaction or block with ID id, or nil
+// should it have made none.
+func (s *Server) diff(id [32]byte) (*wavelet.TransactionDiff, *Error) {
+	diff, err := s.ledger.TransactionDiff(id)
+	if err != nil {
+		if errors.Cause(err) == store.ErrNotFound {
+			return nil, nil
+		}
+
+		return nil, ErrInternal.wrap(err)
+	}
+
+	return &diff, nil
+}
+
+// oldestBlock returns the oldest block whose transactions' changes our node
+// keeps.
+func (s *Server) oldestBlock() *wavelet.Block {
+	oldest := s.ledger.Blocks().Oldest()
+
+	if s.ledger.Archival() {
+		if block := s.findBlock(0); block != nil {
+			oldest = block
+		}
+	}
+
+	if status := s.ledger.PruningStatus(); status.Enabled && status.RetainedFrom > oldest.Index {
+		if block := s.findBlock(status.RetainedFrom); block != nil {
+			oldest = block
+		}
+	}
+
+	return oldest
+}
+
+// findBlock returns the block with index index, or nil should our node not
+// keep it. Archival nodes keep every block.
+func (s *Server) findBlock(index uint64) *wavelet.Block {
+	if block, err := s.ledger.Blocks().GetByIndex(index); err == nil {
+		return block
+	}
+
+	if s.ledger.Archival() {
+		if _, block, err := s.ledger.SnapshotAt(index); err == nil {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// lookupBlock returns the block identified by id.
+func (s *Server) lookupBlock(id PartialBlockIdentifier) (*wavelet.Block, *Error) {
+	var block *wavelet.Block
+
+	switch {
+	case id.Index != nil:
+		if *id.Index < 0 {
+			return nil, ErrInvalidRequest.wrap(errors.New("block index must not be negative"))
+		}
+
+		block = s.findBlock(uint64(*id.Index))
+	case id.Hash != nil:
+		for _, b := range s.ledger.Blocks().Clone() {
+			if b != nil && hex.EncodeToString(b.ID[:]) == *id.Hash {
+				block = b
+			}
+		}
+	default:
+		block = s.ledger.Blocks().Latest()
+	}
+
+	if block == nil {
+		return nil, ErrBlockNotFound.wrap(errors.New("our node does not keep the block"))
+	}
+
+	if id.Hash != nil && hex.EncodeToString(block.ID[:]) != *id.Hash {
+		return nil, ErrBlockNotFound.wrap(errors.Errorf("block %d is not of hash %s", block.Index, *id.Hash))
+	}
+
+	return block, nil
+}
+
+// snapshotAt returns the state of the ledger as of the block identified by id,
+// or the latest block should id be nil, alongside the block.
+func (s *Server) snapshotAt(id *PartialBlockIdentifier) (*avl.Tree, *wavelet.Block, *Error) {
+	var (
+		snapshot *avl.Tree
+		block    *wavelet.Block
+		err      error
+	)
+
+	if id != nil && (id.Index != nil || id.Hash != nil) {
+		found, e := s.lookupBlock(*id)
+		if e != nil {
+			return nil, nil, e
+		}
+
+		snapshot, block, err = s.ledger.SnapshotAt(found.Index)
+	} else {
+		snapshot, block, err = s.ledger.SnapshotLatest()
+	}
+
+	if err != nil {
+		if _, ok := errors.Cause(err).(*wavelet.StateNotKeptError); ok {
+			return nil, nil, ErrStateNotKept.wrap(err)
+		}
+
+		return nil, nil, ErrInternal.wrap(err)
+	}
+
+	return snapshot, block, nil
+}
+
+func blockIdentifier(block *wavelet.Block) BlockIdentifier {
+	return BlockIdentifier{Index: int64(block.Index), Hash: hex.EncodeToString(block.ID[:])}
+}
+
+// decodeID decodes the hex-encoded ID of an account, transaction, or block.
+func decodeID(s string) (id [32]byte, err error) {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return id, err
+	}
+
+	if len(buf) != len(id) {
+		return id, errors.Errorf("must be %d bytes long", len(id))
+	}
+
+	copy(id[:], buf)
+
+	return id, nil
+}
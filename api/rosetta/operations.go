@@ -0,0 +1,177 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rosetta
+
+import (
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+const (
+	StatusSuccess = "SUCCESS"
+
+	// OpFee is the type of the operation debiting the fee of a transaction
+	// from its sender.
+	OpFee = "FEE"
+
+	// OpReward is the type of the operations crediting and withdrawing the
+	// rewards of validators.
+	OpReward = "REWARD"
+
+	OpTransfer = "TRANSFER"
+	OpStake    = "STAKE"
+
+	// The balances of an account other than its balance of PERLs are kept
+	// under sub-accounts.
+	SubAccountStake  = "stake"
+	SubAccountReward = "reward"
+	SubAccountGas    = "gas"
+)
+
+// tagTypes are the types of the operations of transactions of each tag other
+// than their fees.
+var tagTypes = func() map[sys.Tag]string {
+	types := make(map[sys.Tag]string, len(sys.TagLabels))
+
+	for label, tag := range sys.TagLabels {
+		types[tag] = strings.ToUpper(label)
+	}
+
+	return types
+}()
+
+// operationTypes returns every type of operation, sorted.
+func operationTypes() []string {
+	types := []string{OpFee, OpReward}
+
+	for _, typ := range tagTypes {
+		types = append(types, typ)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// operationType returns the type of the operations of tx other than its fee.
+func operationType(tx *wavelet.Transaction) string {
+	if tx.Tag == sys.TagStake {
+		if stake, err := wavelet.ParseStake(tx.Payload); err == nil && stake.Opcode == sys.WithdrawReward {
+			return OpReward
+		}
+	}
+
+	return tagTypes[tx.Tag]
+}
+
+// transactionOperations returns the operations of the transaction whose
+// changes diff records. tx is the transaction, or nil should our node no
+// longer keep it, in which case its operations are typed OpTransfer and its
+// fee is not split out.
+func transactionOperations(diff wavelet.TransactionDiff, tx *wavelet.Transaction, status string) []Operation {
+	if tx == nil {
+		return operations(diff, OpTransfer, wavelet.ZeroAccountID, 0, status)
+	}
+
+	var fee uint64
+
+	if hex.EncodeToString(tx.Sender[:]) != sys.FaucetAddress {
+		fee = tx.Fee()
+	}
+
+	return operations(diff, operationType(tx), tx.Sender, fee, status)
+}
+
+// blockOperations returns the operations of the changes a block itself made,
+// which diff records.
+func blockOperations(diff wavelet.TransactionDiff) []Operation {
+	return operations(diff, OpReward, wavelet.ZeroAccountID, 0, StatusSuccess)
+}
+
+// operations returns the operations of type typ making up the changes diff
+// records, in the order the accounts changed are recorded. Changes made to
+// rewards are always of type OpReward.
+//
+// The fee paid by sender is split out of the change made to its balance. Fees
+// paid by a sponsor are not split out of the change made to the balance of the
+// sponsor.
+func operations(
+	diff wavelet.TransactionDiff, typ string, sender wavelet.AccountID, fee uint64, status string,
+) []Operation {
+	ops := make([]Operation, 0, len(diff.Accounts))
+
+	add := func(opType string, id wavelet.AccountID, sub string, value string) {
+		account := &AccountIdentifier{Address: hex.EncodeToString(id[:])}
+		if sub != "" {
+			account.SubAccount = &SubAccountIdentifier{Address: sub}
+		}
+
+		ops = append(ops, Operation{
+			OperationIdentifier: OperationIdentifier{Index: int64(len(ops))},
+			Type:                opType,
+			Status:              status,
+			Account:             account,
+			Amount:              &Amount{Value: value, Currency: PERL},
+		})
+	}
+
+	for _, account := range diff.Accounts {
+		if d := account.Balance; d != nil {
+			before := d.Before
+
+			if fee > 0 && account.ID == sender && before >= fee && before-fee >= d.After {
+				add(OpFee, account.ID, "", "-"+strconv.FormatUint(fee, 10))
+				before -= fee
+			}
+
+			if before != d.After {
+				add(typ, account.ID, "", change(before, d.After))
+			}
+		}
+
+		if d := account.Stake; d != nil && d.Before != d.After {
+			add(typ, account.ID, SubAccountStake, change(d.Before, d.After))
+		}
+
+		if d := account.Reward; d != nil && d.Before != d.After {
+			add(OpReward, account.ID, SubAccountReward, change(d.Before, d.After))
+		}
+
+		if d := account.GasBalance; d != nil && d.Before != d.After {
+			add(typ, account.ID, SubAccountGas, change(d.Before, d.After))
+		}
+	}
+
+	return ops
+}
+
+// change returns the signed change from before to after.
+func change(before, after uint64) string {
+	if after >= before {
+		return strconv.FormatUint(after-before, 10)
+	}
+
+	return "-" + strconv.FormatUint(before-after, 10)
+}
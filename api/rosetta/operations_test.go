@@ -0,0 +1,144 @@
+// +build unit
+
+package rosetta
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func opSummary(ops []Operation) []string {
+	summary := make([]string, 0, len(ops))
+
+	for _, op := range ops {
+		s := op.Type + " " + op.Account.Address[:2]
+		if op.Account.SubAccount != nil {
+			s += "/" + op.Account.SubAccount.Address
+		}
+
+		summary = append(summary, s+" "+op.Amount.Value)
+	}
+
+	return summary
+}
+
+func TestTransactionOperations(t *testing.T) {
+	alice, bob := wavelet.AccountID{0xaa}, wavelet.AccountID{0xbb}
+
+	payload, err := wavelet.Transfer{Recipient: bob, Amount: 100}.Marshal()
+	require.NoError(t, err)
+
+	tx := &wavelet.Transaction{Sender: alice, Tag: sys.TagTransfer, Payload: payload}
+	fee := tx.Fee()
+
+	diff := wavelet.TransactionDiff{Accounts: []wavelet.AccountDiff{
+		{ID: alice, Balance: &wavelet.FieldDiff{Before: 1000, After: 1000 - 100 - fee}},
+		{ID: bob, Balance: &wavelet.FieldDiff{Before: 0, After: 100}},
+	}}
+
+	// The fee is split out of the change made to the balance of the sender.
+	assert.Equal(t, []string{
+		"FEE aa -" + strconv.FormatUint(fee, 10),
+		"TRANSFER aa -100",
+		"TRANSFER bb 100",
+	}, opSummary(transactionOperations(diff, tx, StatusSuccess)))
+
+	// Unless the transaction is no longer kept.
+	assert.Equal(t, []string{
+		"TRANSFER aa -" + strconv.FormatUint(100+fee, 10),
+		"TRANSFER bb 100",
+	}, opSummary(transactionOperations(diff, nil, StatusSuccess)))
+
+	ops := transactionOperations(diff, tx, StatusSuccess)
+	for i, op := range ops {
+		assert.Equal(t, int64(i), op.OperationIdentifier.Index)
+		assert.Equal(t, StatusSuccess, op.Status)
+		assert.Equal(t, PERL, op.Amount.Currency)
+	}
+}
+
+func TestStakeOperations(t *testing.T) {
+	alice := wavelet.AccountID{0xaa}
+
+	payload, err := wavelet.Stake{Opcode: sys.PlaceStake, Amount: 50}.Marshal()
+	require.NoError(t, err)
+
+	tx := &wavelet.Transaction{Sender: alice, Tag: sys.TagStake, Payload: payload}
+	fee := tx.Fee()
+
+	diff := wavelet.TransactionDiff{Accounts: []wavelet.AccountDiff{
+		{ID: alice, Balance: &wavelet.FieldDiff{Before: 1000, After: 1000 - 50 - fee}, Stake: &wavelet.FieldDiff{After: 50}},
+	}}
+
+	assert.Equal(t, []string{
+		"FEE aa -" + strconv.FormatUint(fee, 10),
+		"STAKE aa -50",
+		"STAKE aa/stake 50",
+	}, opSummary(transactionOperations(diff, tx, StatusSuccess)))
+
+	// Rewards distributed by a block are of type REWARD.
+	diff = wavelet.TransactionDiff{Accounts: []wavelet.AccountDiff{
+		{ID: alice, Reward: &wavelet.FieldDiff{Before: 10, After: 12}},
+	}}
+
+	assert.Equal(t, []string{"REWARD aa/reward 2"}, opSummary(blockOperations(diff)))
+}
+
+func TestConstructionOperations(t *testing.T) {
+	alice := hex.EncodeToString(make([]byte, 32))
+	bob := "bb" + alice[2:]
+
+	amount := func(value string) *Amount {
+		return &Amount{Value: value, Currency: PERL}
+	}
+
+	reward := strconv.FormatUint(sys.MinimumRewardWithdraw, 10)
+
+	tests := [][]Operation{
+		{
+			{Type: OpTransfer, Account: &AccountIdentifier{Address: alice}, Amount: amount("-100")},
+			{OperationIdentifier: OperationIdentifier{Index: 1}, Type: OpTransfer,
+				Account: &AccountIdentifier{Address: bob}, Amount: amount("100")},
+		},
+		{{Type: OpStake, Account: &AccountIdentifier{Address: alice,
+			SubAccount: &SubAccountIdentifier{Address: SubAccountStake}}, Amount: amount("100")}},
+		{{Type: OpStake, Account: &AccountIdentifier{Address: alice,
+			SubAccount: &SubAccountIdentifier{Address: SubAccountStake}}, Amount: amount("-100")}},
+		{{Type: OpReward, Account: &AccountIdentifier{Address: alice,
+			SubAccount: &SubAccountIdentifier{Address: SubAccountReward}}, Amount: amount("-" + reward)}},
+	}
+
+	// The operations a transaction is built from are those it is parsed into.
+	for _, ops := range tests {
+		sender, tag, payload, err := payloadOf(ops)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		parsed, err := intent(wavelet.Transaction{Sender: sender, Tag: tag, Payload: payload})
+		if assert.NoError(t, err) {
+			assert.Equal(t, ops, parsed)
+		}
+	}
+
+	invalid := [][]Operation{
+		nil,
+		{tests[0][0], tests[0][0]},
+		{{Type: OpStake, Account: &AccountIdentifier{Address: alice}, Amount: amount("100")}},
+		{{Type: OpReward, Account: &AccountIdentifier{Address: alice,
+			SubAccount: &SubAccountIdentifier{Address: SubAccountReward}}, Amount: amount(reward)}},
+		{{Type: OpStake, Account: &AccountIdentifier{Address: alice,
+			SubAccount: &SubAccountIdentifier{Address: SubAccountStake}}, Amount: amount("x")}},
+	}
+
+	for _, ops := range invalid {
+		_, _, _, err := payloadOf(ops)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package rosetta implements the Rosetta Data and Construction APIs, such that
+// exchanges may integrate wavelet through the tooling they use for other
+// chains.
+//
+// Transactions are reported by the changes they made to accounts, as recorded
+// by the ledger's transaction diffs. Changes made by a block itself, being the
+// rewards distributed to validators and reward withdrawals paid out, are
+// reported as a transaction whose hash is the ID of the block.
+package rosetta
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	RosettaVersion = "1.4.10"
+
+	Blockchain = "wavelet"
+
+	CurveType     = "edwards25519"
+	SignatureType = "ed25519"
+)
+
+// PERL is the currency every amount is denominated in.
+var PERL = Currency{Symbol: "PERL", Decimals: 0}
+
+// Error is an error of the Rosetta API. Every error our node may respond with
+// is listed by /network/options.
+type Error struct {
+	Code      int32                  `json:"code"`
+	Message   string                 `json:"message"`
+	Retriable bool                   `json:"retriable"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+var (
+	ErrInvalidRequest      = &Error{Code: 1, Message: "invalid request"}
+	ErrUnsupportedNetwork  = &Error{Code: 2, Message: "network is not supported"}
+	ErrBlockNotFound       = &Error{Code: 3, Message: "block not found", Retriable: true}
+	ErrTransactionNotFound = &Error{Code: 4, Message: "transaction not found", Retriable: true}
+	ErrInvalidAccount      = &Error{Code: 5, Message: "invalid account"}
+	ErrStateNotKept        = &Error{Code: 6, Message: "state of block is not kept"}
+	ErrInvalidOperations   = &Error{Code: 7, Message: "operations are not supported"}
+	ErrInvalidTransaction  = &Error{Code: 8, Message: "invalid transaction"}
+	ErrTransactionRejected = &Error{Code: 9, Message: "transaction rejected"}
+	ErrInternal            = &Error{Code: 10, Message: "internal error", Retriable: true}
+
+	errs = []*Error{
+		ErrInvalidRequest, ErrUnsupportedNetwork, ErrBlockNotFound, ErrTransactionNotFound, ErrInvalidAccount,
+		ErrStateNotKept, ErrInvalidOperations, ErrInvalidTransaction, ErrTransactionRejected, ErrInternal,
+	}
+)
+
+func (e *Error) Error() string {
+	if err, ok := e.Details["error"]; ok {
+		return e.Message + ": " + err.(string)
+	}
+
+	return e.Message
+}
+
+// wrap returns a copy of e detailing err.
+func (e *Error) wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Details = map[string]interface{}{"error": err.Error()}
+
+	return &wrapped
+}
+
+type handler func(body []byte) (interface{}, *Error)
+
+// Server serves the Rosetta APIs of a ledger, on a port of its own.
+type Server struct {
+	ledger  *wavelet.Ledger
+	client  *skademlia.Client
+	network NetworkIdentifier
+
+	server *fasthttp.Server
+}
+
+// New returns a server of the Rosetta APIs of ledger, identifying the network
+// it is a part of by network. client is the client our node is connected to
+// its peers with.
+func New(ledger *wavelet.Ledger, client *skademlia.Client, network string) *Server {
+	return &Server{
+		ledger:  ledger,
+		client:  client,
+		network: NetworkIdentifier{Blockchain: Blockchain, Network: network},
+	}
+}
+
+func (s *Server) router() *fasthttprouter.Router {
+	r := fasthttprouter.New()
+
+	// Data API.
+	r.POST("/network/list", s.handle(s.networkList))
+	r.POST("/network/status", s.handle(s.networkStatus))
+	r.POST("/network/options", s.handle(s.networkOptions))
+	r.POST("/block", s.handle(s.block))
+	r.POST("/block/transaction", s.handle(s.blockTransaction))
+	r.POST("/account/balance", s.handle(s.accountBalance))
+	r.POST("/mempool", s.handle(s.mempool))
+	r.POST("/mempool/transaction", s.handle(s.mempoolTransaction))
+
+	// Construction API.
+	r.POST("/construction/derive", s.handle(s.constructionDerive))
+	r.POST("/construction/preprocess", s.handle(s.constructionPreprocess))
+	r.POST("/construction/metadata", s.handle(s.constructionMetadata))
+	r.POST("/construction/payloads", s.handle(s.constructionPayloads))
+	r.POST("/construction/combine", s.handle(s.constructionCombine))
+	r.POST("/construction/parse", s.handle(s.constructionParse))
+	r.POST("/construction/hash", s.handle(s.constructionHash))
+	r.POST("/construction/submit", s.handle(s.constructionSubmit))
+
+	return r
+}
+
+// Start serves the Rosetta APIs at port.
+func (s *Server) Start(port int) {
+	logger := log.Node()
+
+	ln, err := net.Listen("tcp4", ":"+strconv.Itoa(port))
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("Failed to listen to port %d.", port)
+	}
+
+	logger.Info().Int("port", port).Msg("Started Rosetta API server.")
+
+	s.server = &fasthttp.Server{
+		Handler: s.router().Handler,
+	}
+
+	go func() {
+		if err := s.server.Serve(ln); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to start Rosetta API server.")
+		}
+	}()
+}
+
+func (s *Server) Shutdown() {
+	if s.server != nil {
+		_ = s.server.Shutdown()
+	}
+}
+
+// handle serves fn, responding with the JSON encoding of its response, or of
+// its error with status 500, as the Rosetta API specifies.
+func (s *Server) handle(fn handler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		res, e := fn(ctx.PostBody())
+
+		status := http.StatusOK
+		if e != nil {
+			res, status = e, http.StatusInternalServerError
+		}
+
+		buf, err := json.Marshal(res)
+		if err != nil {
+			buf, _ = json.Marshal(ErrInternal.wrap(err))
+			status = http.StatusInternalServerError
+		}
+
+		ctx.SetContentType("application/json")
+		ctx.Response.SetStatusCode(status)
+		ctx.Response.SetBody(buf)
+	}
+}
+
+// decode decodes the JSON request body into req, checking that network, which
+// req holds, identifies our network.
+func (s *Server) decode(body []byte, req interface{}, network **NetworkIdentifier) *Error {
+	if err := json.Unmarshal(body, req); err != nil {
+		return ErrInvalidRequest.wrap(err)
+	}
+
+	if *network == nil {
+		return ErrInvalidRequest.wrap(errors.New("network_identifier must be given"))
+	}
+
+	if **network != s.network {
+		return ErrUnsupportedNetwork.wrap(errors.Errorf("our node is a part of network %s/%s",
+			s.network.Blockchain, s.network.Network))
+	}
+
+	return nil
+}
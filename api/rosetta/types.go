@@ -0,0 +1,285 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rosetta
+
+// The types below are those of the Rosetta API specification, with
+// identifiers and amounts encoded as the specification mandates. Only the
+// fields our node populates or reads are declared.
+
+type NetworkIdentifier struct {
+	Blockchain string `json:"blockchain"`
+	Network    string `json:"network"`
+}
+
+type BlockIdentifier struct {
+	Index int64  `json:"index"`
+	Hash  string `json:"hash"`
+}
+
+// PartialBlockIdentifier identifies a block by either of, or both, its index
+// and hash. The latest block is identified should neither be given.
+type PartialBlockIdentifier struct {
+	Index *int64  `json:"index,omitempty"`
+	Hash  *string `json:"hash,omitempty"`
+}
+
+type TransactionIdentifier struct {
+	Hash string `json:"hash"`
+}
+
+type AccountIdentifier struct {
+	Address    string                `json:"address"`
+	SubAccount *SubAccountIdentifier `json:"sub_account,omitempty"`
+}
+
+type SubAccountIdentifier struct {
+	Address string `json:"address"`
+}
+
+type Currency struct {
+	Symbol   string `json:"symbol"`
+	Decimals int32  `json:"decimals"`
+}
+
+// Amount is a signed, base-10 quantity of a currency.
+type Amount struct {
+	Value    string   `json:"value"`
+	Currency Currency `json:"currency"`
+}
+
+type OperationIdentifier struct {
+	Index int64 `json:"index"`
+}
+
+type Operation struct {
+	OperationIdentifier OperationIdentifier `json:"operation_identifier"`
+
+	Type    string             `json:"type"`
+	Status  string             `json:"status,omitempty"`
+	Account *AccountIdentifier `json:"account,omitempty"`
+	Amount  *Amount            `json:"amount,omitempty"`
+}
+
+type Transaction struct {
+	TransactionIdentifier TransactionIdentifier `json:"transaction_identifier"`
+	Operations            []Operation           `json:"operations"`
+}
+
+type Block struct {
+	BlockIdentifier       BlockIdentifier `json:"block_identifier"`
+	ParentBlockIdentifier BlockIdentifier `json:"parent_block_identifier"`
+	Timestamp             int64           `json:"timestamp"`
+	Transactions          []Transaction   `json:"transactions"`
+}
+
+type Peer struct {
+	PeerID   string                 `json:"peer_id"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type Version struct {
+	RosettaVersion string `json:"rosetta_version"`
+	NodeVersion    string `json:"node_version"`
+}
+
+type OperationStatus struct {
+	Status     string `json:"status"`
+	Successful bool   `json:"successful"`
+}
+
+type Allow struct {
+	OperationStatuses       []OperationStatus `json:"operation_statuses"`
+	OperationTypes          []string          `json:"operation_types"`
+	Errors                  []*Error          `json:"errors"`
+	HistoricalBalanceLookup bool              `json:"historical_balance_lookup"`
+}
+
+type PublicKey struct {
+	HexBytes  string `json:"hex_bytes"`
+	CurveType string `json:"curve_type"`
+}
+
+type SigningPayload struct {
+	HexBytes          string             `json:"hex_bytes"`
+	AccountIdentifier *AccountIdentifier `json:"account_identifier,omitempty"`
+	SignatureType     string             `json:"signature_type,omitempty"`
+}
+
+type Signature struct {
+	SigningPayload SigningPayload `json:"signing_payload"`
+	PublicKey      PublicKey      `json:"public_key"`
+	SignatureType  string         `json:"signature_type"`
+	HexBytes       string         `json:"hex_bytes"`
+}
+
+// Requests and responses of the Data API.
+
+type NetworkRequest struct {
+	NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+}
+
+type NetworkListResponse struct {
+	NetworkIdentifiers []NetworkIdentifier `json:"network_identifiers"`
+}
+
+type NetworkStatusResponse struct {
+	CurrentBlockIdentifier BlockIdentifier `json:"current_block_identifier"`
+	CurrentBlockTimestamp  int64           `json:"current_block_timestamp"`
+	GenesisBlockIdentifier BlockIdentifier `json:"genesis_block_identifier"`
+	OldestBlockIdentifier  BlockIdentifier `json:"oldest_block_identifier"`
+	Peers                  []Peer          `json:"peers"`
+}
+
+type NetworkOptionsResponse struct {
+	Version Version `json:"version"`
+	Allow   Allow   `json:"allow"`
+}
+
+type BlockRequest struct {
+	NetworkIdentifier *NetworkIdentifier     `json:"network_identifier"`
+	BlockIdentifier   PartialBlockIdentifier `json:"block_identifier"`
+}
+
+type BlockResponse struct {
+	Block *Block `json:"block"`
+}
+
+type BlockTransactionRequest struct {
+	NetworkIdentifier     *NetworkIdentifier    `json:"network_identifier"`
+	BlockIdentifier       BlockIdentifier       `json:"block_identifier"`
+	TransactionIdentifier TransactionIdentifier `json:"transaction_identifier"`
+}
+
+type BlockTransactionResponse struct {
+	Transaction Transaction `json:"transaction"`
+}
+
+type AccountBalanceRequest struct {
+	NetworkIdentifier *NetworkIdentifier      `json:"network_identifier"`
+	AccountIdentifier AccountIdentifier       `json:"account_identifier"`
+	BlockIdentifier   *PartialBlockIdentifier `json:"block_identifier,omitempty"`
+}
+
+type AccountBalanceResponse struct {
+	BlockIdentifier BlockIdentifier `json:"block_identifier"`
+	Balances        []Amount        `json:"balances"`
+}
+
+type MempoolResponse struct {
+	TransactionIdentifiers []TransactionIdentifier `json:"transaction_identifiers"`
+}
+
+type MempoolTransactionRequest struct {
+	NetworkIdentifier     *NetworkIdentifier    `json:"network_identifier"`
+	TransactionIdentifier TransactionIdentifier `json:"transaction_identifier"`
+}
+
+type MempoolTransactionResponse struct {
+	Transaction Transaction `json:"transaction"`
+}
+
+// Requests and responses of the Construction API.
+
+type ConstructionDeriveRequest struct {
+	NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+	PublicKey         PublicKey          `json:"public_key"`
+}
+
+type ConstructionDeriveResponse struct {
+	AccountIdentifier AccountIdentifier `json:"account_identifier"`
+}
+
+type ConstructionPreprocessRequest struct {
+	NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+	Operations        []Operation        `json:"operations"`
+}
+
+// ConstructionOptions are the options the metadata of a transaction is
+// fetched with: the fee the transaction is to pay.
+type ConstructionOptions struct {
+	Fee uint64 `json:"fee"`
+}
+
+type ConstructionPreprocessResponse struct {
+	Options ConstructionOptions `json:"options"`
+}
+
+type ConstructionMetadataRequest struct {
+	NetworkIdentifier *NetworkIdentifier  `json:"network_identifier"`
+	Options           ConstructionOptions `json:"options"`
+}
+
+// ConstructionMetadata is the metadata a transaction is constructed with: the
+// index of the block it is created at, and its nonce.
+type ConstructionMetadata struct {
+	Block uint64 `json:"block"`
+	Nonce uint64 `json:"nonce"`
+}
+
+type ConstructionMetadataResponse struct {
+	Metadata     ConstructionMetadata `json:"metadata"`
+	SuggestedFee []Amount             `json:"suggested_fee"`
+}
+
+type ConstructionPayloadsRequest struct {
+	NetworkIdentifier *NetworkIdentifier   `json:"network_identifier"`
+	Operations        []Operation          `json:"operations"`
+	Metadata          ConstructionMetadata `json:"metadata"`
+}
+
+type ConstructionPayloadsResponse struct {
+	UnsignedTransaction string           `json:"unsigned_transaction"`
+	Payloads            []SigningPayload `json:"payloads"`
+}
+
+type ConstructionCombineRequest struct {
+	NetworkIdentifier   *NetworkIdentifier `json:"network_identifier"`
+	UnsignedTransaction string             `json:"unsigned_transaction"`
+	Signatures          []Signature        `json:"signatures"`
+}
+
+type ConstructionCombineResponse struct {
+	SignedTransaction string `json:"signed_transaction"`
+}
+
+type ConstructionParseRequest struct {
+	NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+	Signed            bool               `json:"signed"`
+	Transaction       string             `json:"transaction"`
+}
+
+type ConstructionParseResponse struct {
+	Operations               []Operation         `json:"operations"`
+	AccountIdentifierSigners []AccountIdentifier `json:"account_identifier_signers,omitempty"`
+}
+
+type ConstructionHashRequest struct {
+	NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+	SignedTransaction string             `json:"signed_transaction"`
+}
+
+type ConstructionSubmitRequest struct {
+	NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+	SignedTransaction string             `json:"signed_transaction"`
+}
+
+type TransactionIdentifierResponse struct {
+	TransactionIdentifier TransactionIdentifier `json:"transaction_identifier"`
+}
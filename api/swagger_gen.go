@@ -0,0 +1,4539 @@
+// Code generated by cmd/openapi. DO NOT EDIT.
+
+package api
+
+// SwaggerJSON is the OpenAPI document of the API, served at /swagger.json.
+const SwaggerJSON = `{
+  "openapi": "3.0.2",
+  "info": {
+    "title": "Wavelet",
+    "description": "HTTP API of a Wavelet node.",
+    "version": "v0.2.1-testing"
+  },
+  "paths": {
+    "/accounts/{id}": {
+      "get": {
+        "operationId": "getAccount",
+        "summary": "State of an account, as of the latest block or of an archived round.",
+        "tags": [
+          "accounts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded account ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "round",
+            "in": "query",
+            "description": "Height of an archived block.",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Account"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/accounts/{id}/delegations": {
+      "get": {
+        "operationId": "getDelegations",
+        "summary": "Stake delegated to and by an account.",
+        "tags": [
+          "accounts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded account ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Delegations"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/accounts/{id}/proof": {
+      "get": {
+        "operationId": "getAccountProof",
+        "summary": "Merkle proofs of the balances of an account.",
+        "tags": [
+          "accounts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded account ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "block",
+            "in": "query",
+            "description": "Height of the block to prove against.",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/AccountProof"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/accounts/{id}/slashings": {
+      "get": {
+        "operationId": "getSlashings",
+        "summary": "Slashings of the stake of an account.",
+        "tags": [
+          "accounts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded account ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Slashings"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/block/{index}/randomness": {
+      "get": {
+        "operationId": "getRandomness",
+        "summary": "Randomness of the beacon recorded for a block.",
+        "tags": [
+          "ledger"
+        ],
+        "parameters": [
+          {
+            "name": "index",
+            "in": "path",
+            "description": "Height of the block.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Randomness"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/contract/{id}": {
+      "get": {
+        "operationId": "getContractCode",
+        "summary": "WebAssembly code of a contract.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/wasm": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/contract/{id}/call": {
+      "post": {
+        "operationId": "callContract",
+        "summary": "Simulates calling a function of a contract, without sending a transaction.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/ContractCallRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/ContractCallResult"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/contract/{id}/logs": {
+      "get": {
+        "operationId": "getContractLogs",
+        "summary": "Logs emitted by a contract.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "offset",
+            "in": "query",
+            "description": "Number of items to skip.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "limit",
+            "in": "query",
+            "description": "Maximum number of items returned.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "from_block",
+            "in": "query",
+            "description": "Height of the first block to list logs of.",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/ContractLogList"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/contract/{id}/logs/poll": {
+      "get": {
+        "operationId": "pollContractLogs",
+        "summary": "Websocket streaming the logs emitted by a contract.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "101": {
+            "description": "Switching to the websocket protocol."
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "x-websocket": true
+      }
+    },
+    "/contract/{id}/page": {
+      "get": {
+        "operationId": "getContractPages",
+        "summary": "Memory pages of a contract.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/octet-stream": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/contract/{id}/page/{index}": {
+      "get": {
+        "operationId": "getContractPage",
+        "summary": "A memory page of a contract.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "index",
+            "in": "path",
+            "description": "Index of the page.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/octet-stream": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/contract/{id}/storage": {
+      "get": {
+        "operationId": "scanContractStorage",
+        "summary": "Entries of the storage of a contract, ordered by key.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "prefix",
+            "in": "query",
+            "description": "Hex-encoded prefix of the keys listed.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "after",
+            "in": "query",
+            "description": "Hex-encoded key after which to list keys.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "limit",
+            "in": "query",
+            "description": "Maximum number of items returned.",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/ContractStorageList"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/contract/{id}/storage/{key}": {
+      "get": {
+        "operationId": "getContractStorage",
+        "summary": "Value of a key of the storage of a contract.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "key",
+            "in": "path",
+            "description": "Hex-encoded key.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "proof",
+            "in": "query",
+            "description": "Whether to prove the value against the latest block.",
+            "schema": {
+              "type": "boolean"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/ContractStorageValue"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/contract/{id}/upgrades": {
+      "get": {
+        "operationId": "getContractUpgrades",
+        "summary": "Upgrades of the code of a contract.",
+        "tags": [
+          "contracts"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded contract ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/ContractUpgrades"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/graphql": {
+      "post": {
+        "operationId": "graphql",
+        "summary": "Executes a GraphQL query. GET requests are upgraded to websockets serving subscriptions.",
+        "tags": [
+          "graphql"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/GraphQLRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/GraphQLResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/healthz": {
+      "get": {
+        "operationId": "getHealth",
+        "summary": "Whether the node is live, being its database reachable and consensus not stalled. Responds with 503 should it not be.",
+        "tags": [
+          "health"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Health"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/ledger": {
+      "get": {
+        "operationId": "getLedgerStatus",
+        "summary": "Status of the ledger, as of the latest block or of an archived round.",
+        "tags": [
+          "ledger"
+        ],
+        "parameters": [
+          {
+            "name": "round",
+            "in": "query",
+            "description": "Height of an archived block to report the ledger as of.",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/LedgerStatusResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/ledger/gas": {
+      "get": {
+        "operationId": "getGasSchedule",
+        "summary": "Gas schedule smart contracts are charged by.",
+        "tags": [
+          "ledger"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/GasSchedule"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/mempool": {
+      "get": {
+        "operationId": "listMempool",
+        "summary": "Transactions pending in the mempool.",
+        "tags": [
+          "mempool"
+        ],
+        "parameters": [
+          {
+            "name": "offset",
+            "in": "query",
+            "description": "Number of items to skip.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "limit",
+            "in": "query",
+            "description": "Maximum number of items returned.",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "array",
+                  "items": {
+                    "$ref": "#/components/schemas/Transaction"
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/mempool/contains/{id}": {
+      "get": {
+        "operationId": "mempoolContains",
+        "summary": "Whether a transaction is pending in the mempool.",
+        "tags": [
+          "mempool"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded transaction ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MempoolContains"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/mempool/count": {
+      "get": {
+        "operationId": "countMempool",
+        "summary": "Number of transactions pending in the mempool.",
+        "tags": [
+          "mempool"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MempoolCount"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/metrics": {
+      "get": {
+        "operationId": "getMetrics",
+        "summary": "Metrics of the node in the Prometheus text format, if enabled.",
+        "tags": [
+          "ledger"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "text/plain": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/name/{name}": {
+      "get": {
+        "operationId": "getName",
+        "summary": "Record of a registered name.",
+        "tags": [
+          "names"
+        ],
+        "parameters": [
+          {
+            "name": "name",
+            "in": "path",
+            "description": "Name to resolve.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/NameRecord"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/node/connect": {
+      "post": {
+        "operationId": "connect",
+        "summary": "Connects to a peer.",
+        "tags": [
+          "node"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/ConnectRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MsgResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    },
+    "/node/disconnect": {
+      "post": {
+        "operationId": "disconnect",
+        "summary": "Disconnects from a peer.",
+        "tags": [
+          "node"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/ConnectRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MsgResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    },
+    "/node/loglevels": {
+      "get": {
+        "operationId": "getLogLevels",
+        "summary": "Log level of every module of the node.",
+        "tags": [
+          "node"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "additionalProperties": {
+                    "type": "string"
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      },
+      "post": {
+        "operationId": "updateLogLevels",
+        "summary": "Updates the log levels of the modules keyed, the levels of others being kept.",
+        "tags": [
+          "node"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "type": "object",
+                "additionalProperties": {
+                  "type": "string"
+                }
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "additionalProperties": {
+                    "type": "string"
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    },
+    "/node/peers": {
+      "get": {
+        "operationId": "listPeerScores",
+        "summary": "Reputation scores of the peers of the node.",
+        "tags": [
+          "node"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/PeerScores"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/node/peers/{id}/ban": {
+      "delete": {
+        "operationId": "unbanPeer",
+        "summary": "Lifts the ban of a peer, which starts over with a score of 0.",
+        "tags": [
+          "node"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded public key of the peer.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MsgResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      },
+      "post": {
+        "operationId": "banPeer",
+        "summary": "Bans a peer, for the ban duration of the node unless a duration is given.",
+        "tags": [
+          "node"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded public key of the peer.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/BanPeerRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MsgResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    },
+    "/node/prune": {
+      "post": {
+        "operationId": "prune",
+        "summary": "Prunes the data of past blocks and compacts the database, should pruning be enabled.",
+        "tags": [
+          "node"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MsgResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    },
+    "/node/restart": {
+      "post": {
+        "operationId": "restart",
+        "summary": "Restarts the node, a hard restart wiping its database.",
+        "tags": [
+          "node"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/RestartRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MsgResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    },
+    "/node/snowball": {
+      "post": {
+        "operationId": "updateSnowball",
+        "summary": "Updates the Snowball parameters the node runs with, zero parameters being kept.",
+        "tags": [
+          "node"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/SnowballParams"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/SnowballParams"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    },
+    "/poll/accounts": {
+      "get": {
+        "operationId": "pollAccounts",
+        "summary": "Websocket streaming updates to accounts.",
+        "tags": [
+          "websockets"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "query",
+            "description": "Hex-encoded ID of the account.",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "101": {
+            "description": "Switching to the websocket protocol."
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "x-websocket": true
+      }
+    },
+    "/poll/consensus": {
+      "get": {
+        "operationId": "pollConsensus",
+        "summary": "Websocket streaming proposals and finalized blocks.",
+        "tags": [
+          "websockets"
+        ],
+        "responses": {
+          "101": {
+            "description": "Switching to the websocket protocol."
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "x-websocket": true
+      }
+    },
+    "/poll/contract": {
+      "get": {
+        "operationId": "pollContracts",
+        "summary": "Websocket streaming the gas spent and logs emitted by contracts.",
+        "tags": [
+          "websockets"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "query",
+            "description": "Hex-encoded ID of the contract.",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "101": {
+            "description": "Switching to the websocket protocol."
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "x-websocket": true
+      }
+    },
+    "/poll/metrics": {
+      "get": {
+        "operationId": "pollMetrics",
+        "summary": "Websocket streaming the metrics of the node every second.",
+        "tags": [
+          "websockets"
+        ],
+        "responses": {
+          "101": {
+            "description": "Switching to the websocket protocol."
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "x-websocket": true
+      }
+    },
+    "/poll/network": {
+      "get": {
+        "operationId": "pollNetwork",
+        "summary": "Websocket streaming peers joining, leaving and being banned.",
+        "tags": [
+          "websockets"
+        ],
+        "responses": {
+          "101": {
+            "description": "Switching to the websocket protocol."
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "x-websocket": true
+      }
+    },
+    "/poll/rounds": {
+      "get": {
+        "operationId": "pollRounds",
+        "summary": "Websocket streaming rounds as they are finalized.",
+        "tags": [
+          "rounds"
+        ],
+        "responses": {
+          "101": {
+            "description": "Switching to the websocket protocol."
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "x-websocket": true
+      }
+    },
+    "/poll/tx": {
+      "get": {
+        "operationId": "pollTransactions",
+        "summary": "Websocket streaming transactions being applied, rejected and conflicting.",
+        "tags": [
+          "websockets"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "query",
+            "description": "Hex-encoded ID of the transaction.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "sender",
+            "in": "query",
+            "description": "Hex-encoded ID of the sender.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "tag",
+            "in": "query",
+            "description": "Tag of the transactions.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "event",
+            "in": "query",
+            "description": "Name of the event, such as applied.",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "101": {
+            "description": "Switching to the websocket protocol."
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "x-websocket": true
+      }
+    },
+    "/readyz": {
+      "get": {
+        "operationId": "getReadiness",
+        "summary": "Whether the node is live, connected to enough peers and in sync with them. Responds with 503 should it not be.",
+        "tags": [
+          "health"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Health"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/relayer/{id}": {
+      "get": {
+        "operationId": "getRelayer",
+        "summary": "Statistics of the transactions a relayer submitted.",
+        "tags": [
+          "relayers"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded ID of the relayer.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/RelayerStats"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/rounds": {
+      "get": {
+        "operationId": "listRounds",
+        "summary": "Latest rounds finalized, newest first.",
+        "tags": [
+          "rounds"
+        ],
+        "parameters": [
+          {
+            "name": "from",
+            "in": "query",
+            "description": "Index of the newest round listed.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "limit",
+            "in": "query",
+            "description": "Maximum number of items returned.",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "array",
+                  "items": {
+                    "$ref": "#/components/schemas/Round"
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/rounds/{index}": {
+      "get": {
+        "operationId": "getRound",
+        "summary": "A round finalized.",
+        "tags": [
+          "rounds"
+        ],
+        "parameters": [
+          {
+            "name": "index",
+            "in": "path",
+            "description": "Index of the round.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Round"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/snapshot": {
+      "get": {
+        "operationId": "getSnapshot",
+        "summary": "Snapshot of the state of the ledger as of the latest block.",
+        "tags": [
+          "ledger"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/octet-stream": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/swagger.json": {
+      "get": {
+        "operationId": "getOpenAPI",
+        "summary": "This document.",
+        "tags": [
+          "documentation"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "additionalProperties": {}
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/time": {
+      "get": {
+        "operationId": "getTime",
+        "summary": "Time on the clock of the node.",
+        "tags": [
+          "ledger"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/TimeResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/tx": {
+      "get": {
+        "operationId": "listTransactions",
+        "summary": "Transactions archived by the node, ordered by height and then by ID.",
+        "tags": [
+          "transactions"
+        ],
+        "parameters": [
+          {
+            "name": "sender",
+            "in": "query",
+            "description": "Hex-encoded ID of the sender.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "tag",
+            "in": "query",
+            "description": "Tag of the transactions.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "offset",
+            "in": "query",
+            "description": "Number of items to skip.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "limit",
+            "in": "query",
+            "description": "Maximum number of items returned.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "from_height",
+            "in": "query",
+            "description": "Lowest height of the transactions.",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "to_height",
+            "in": "query",
+            "description": "Highest height of the transactions.",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "array",
+                  "items": {
+                    "$ref": "#/components/schemas/Transaction"
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/tx/estimate": {
+      "post": {
+        "operationId": "estimateFee",
+        "summary": "Estimates the fee of a transaction by dry-running it.",
+        "tags": [
+          "transactions"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/EstimateFeeRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/FeeEstimate"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/tx/relay": {
+      "post": {
+        "operationId": "relayTransaction",
+        "summary": "Submits a signed transaction on behalf of its sender, the request being signed by the relayer.",
+        "tags": [
+          "transactions"
+        ],
+        "parameters": [
+          {
+            "name": "Idempotency-Key",
+            "in": "header",
+            "description": "Key unique to the request, for retries of it to be responded to as the first attempt was.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "traceparent",
+            "in": "header",
+            "description": "W3C trace context of the client, for the transaction to be traced as part of its trace.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "X-Wavelet-Public-Key",
+            "in": "header",
+            "description": "Hex-encoded public key of the relayer.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "X-Wavelet-Signature",
+            "in": "header",
+            "description": "Hex-encoded signature of the canonical JSON of the body.",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/TxRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/TxResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/tx/send": {
+      "post": {
+        "operationId": "sendTransaction",
+        "summary": "Submits a signed transaction.",
+        "tags": [
+          "transactions"
+        ],
+        "parameters": [
+          {
+            "name": "Idempotency-Key",
+            "in": "header",
+            "description": "Key unique to the request, for retries of it to be responded to as the first attempt was.",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "traceparent",
+            "in": "header",
+            "description": "W3C trace context of the client, for the transaction to be traced as part of its trace.",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/TxRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/TxResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/tx/{id}": {
+      "get": {
+        "operationId": "getTransaction",
+        "summary": "A transaction.",
+        "tags": [
+          "transactions"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded transaction ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Transaction"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/tx/{id}/data": {
+      "get": {
+        "operationId": "getData",
+        "summary": "Data anchored by a transaction.",
+        "tags": [
+          "transactions"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded transaction ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/AnchoredData"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/tx/{id}/diff": {
+      "get": {
+        "operationId": "getTransactionDiff",
+        "summary": "Changes a transaction made to the state of accounts.",
+        "tags": [
+          "transactions"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "Hex-encoded transaction ID.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/TransactionDiff"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/validators": {
+      "get": {
+        "operationId": "listValidators",
+        "summary": "Validators as of the latest block.",
+        "tags": [
+          "validators"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Validators"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/webhooks": {
+      "get": {
+        "operationId": "listWebhooks",
+        "summary": "Webhooks registered.",
+        "tags": [
+          "webhooks"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "array",
+                  "items": {
+                    "$ref": "#/components/schemas/Webhook"
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      },
+      "post": {
+        "operationId": "registerWebhook",
+        "summary": "Registers a webhook.",
+        "tags": [
+          "webhooks"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/WebhookRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Webhook"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    },
+    "/webhooks/{id}": {
+      "delete": {
+        "operationId": "deleteWebhook",
+        "summary": "Deletes a webhook.",
+        "tags": [
+          "webhooks"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "ID of the webhook.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/MsgResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      },
+      "get": {
+        "operationId": "getWebhook",
+        "summary": "A webhook registered.",
+        "tags": [
+          "webhooks"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "description": "ID of the webhook.",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Webhook"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        },
+        "security": [
+          {
+            "bearer": []
+          }
+        ]
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "AVLProof": {
+        "type": "object",
+        "properties": {
+          "lefts": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "hex"
+            }
+          },
+          "path": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "hex"
+            }
+          }
+        },
+        "required": [
+          "lefts",
+          "path"
+        ]
+      },
+      "Account": {
+        "type": "object",
+        "properties": {
+          "balance": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "fee_allowance": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "gas_balance": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "is_contract": {
+            "type": "boolean"
+          },
+          "multisig": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/Multisig"
+              }
+            ]
+          },
+          "num_mem_pages": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "pending_recovery": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/PendingRecovery"
+              }
+            ]
+          },
+          "public_key": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "recovery": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/Recovery"
+              }
+            ]
+          },
+          "reward": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "sponsor": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "stake": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "balance",
+          "gas_balance",
+          "is_contract",
+          "public_key",
+          "reward",
+          "stake"
+        ]
+      },
+      "AccountDiff": {
+        "type": "object",
+        "properties": {
+          "balance": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/FieldDiff"
+              }
+            ]
+          },
+          "contract_created": {
+            "type": "boolean"
+          },
+          "contract_pages": {
+            "type": "array",
+            "items": {
+              "type": "integer",
+              "format": "int64"
+            }
+          },
+          "gas_balance": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/FieldDiff"
+              }
+            ]
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "reward": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/FieldDiff"
+              }
+            ]
+          },
+          "stake": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/FieldDiff"
+              }
+            ]
+          }
+        },
+        "required": [
+          "id"
+        ]
+      },
+      "AccountProof": {
+        "type": "object",
+        "properties": {
+          "balance": {
+            "$ref": "#/components/schemas/AVLProof"
+          },
+          "block": {
+            "type": "object",
+            "properties": {
+              "height": {
+                "type": "integer",
+                "format": "int64"
+              },
+              "id": {
+                "type": "string",
+                "format": "hex",
+                "minLength": 64,
+                "maxLength": 64
+              },
+              "merkle_root": {
+                "type": "string",
+                "format": "hex",
+                "minLength": 32,
+                "maxLength": 32
+              }
+            },
+            "required": [
+              "height",
+              "id",
+              "merkle_root"
+            ]
+          },
+          "gas_balance": {
+            "$ref": "#/components/schemas/AVLProof"
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "reward": {
+            "$ref": "#/components/schemas/AVLProof"
+          },
+          "stake": {
+            "$ref": "#/components/schemas/AVLProof"
+          }
+        },
+        "required": [
+          "balance",
+          "block",
+          "gas_balance",
+          "id",
+          "reward",
+          "stake"
+        ]
+      },
+      "AnchoredData": {
+        "type": "object",
+        "properties": {
+          "data": {
+            "type": "string",
+            "format": "hex"
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "data",
+          "id"
+        ]
+      },
+      "BanPeerRequest": {
+        "type": "object",
+        "properties": {
+          "duration": {
+            "type": "string",
+            "format": "duration",
+            "description": "Go duration, such as 1m30s."
+          }
+        }
+      },
+      "ConnectRequest": {
+        "type": "object",
+        "properties": {
+          "address": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "address"
+        ]
+      },
+      "ConsensusHealth": {
+        "type": "object",
+        "properties": {
+          "block_index": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "max_stall": {
+            "type": "string",
+            "format": "duration",
+            "description": "Go duration, such as 1m30s."
+          },
+          "ok": {
+            "type": "boolean"
+          },
+          "round_duration": {
+            "type": "string",
+            "format": "duration",
+            "description": "Go duration, such as 1m30s."
+          }
+        },
+        "required": [
+          "block_index",
+          "max_stall",
+          "ok",
+          "round_duration"
+        ]
+      },
+      "ContractCallRequest": {
+        "type": "object",
+        "properties": {
+          "amount": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "func": {
+            "type": "string"
+          },
+          "gas_limit": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "params": {
+            "type": "string",
+            "format": "hex"
+          },
+          "sender": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "func",
+          "params",
+          "sender"
+        ]
+      },
+      "ContractCallResult": {
+        "type": "object",
+        "properties": {
+          "failure": {
+            "type": "string"
+          },
+          "gas": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "gas_limit_exceeded": {
+            "type": "boolean"
+          },
+          "queued": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "result": {
+            "type": "string",
+            "format": "hex"
+          }
+        },
+        "required": [
+          "failure",
+          "gas",
+          "gas_limit_exceeded",
+          "queued",
+          "result"
+        ]
+      },
+      "ContractLog": {
+        "type": "object",
+        "properties": {
+          "block": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "contract_id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "data": {
+            "type": "string",
+            "format": "hex"
+          },
+          "index": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "message": {
+            "type": "string"
+          },
+          "time": {
+            "type": "string",
+            "format": "date-time"
+          },
+          "topic": {
+            "type": "string",
+            "format": "hex"
+          },
+          "tx_id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "block",
+          "contract_id",
+          "data",
+          "index",
+          "message",
+          "time",
+          "topic",
+          "tx_id"
+        ]
+      },
+      "ContractLogList": {
+        "type": "object",
+        "properties": {
+          "logs": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/ContractLog"
+            }
+          },
+          "total": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "logs",
+          "total"
+        ]
+      },
+      "ContractStorageEntry": {
+        "type": "object",
+        "properties": {
+          "key": {
+            "type": "string",
+            "format": "hex"
+          },
+          "value": {
+            "type": "string",
+            "format": "hex"
+          }
+        },
+        "required": [
+          "key",
+          "value"
+        ]
+      },
+      "ContractStorageList": {
+        "type": "object",
+        "properties": {
+          "entries": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/ContractStorageEntry"
+            }
+          }
+        },
+        "required": [
+          "entries"
+        ]
+      },
+      "ContractStorageValue": {
+        "type": "object",
+        "properties": {
+          "block": {
+            "type": "object",
+            "properties": {
+              "height": {
+                "type": "integer",
+                "format": "int64"
+              },
+              "id": {
+                "type": "string",
+                "format": "hex",
+                "minLength": 64,
+                "maxLength": 64
+              },
+              "merkle_root": {
+                "type": "string",
+                "format": "hex",
+                "minLength": 32,
+                "maxLength": 32
+              }
+            },
+            "required": [
+              "height",
+              "id",
+              "merkle_root"
+            ]
+          },
+          "exists": {
+            "type": "boolean"
+          },
+          "key": {
+            "type": "string",
+            "format": "hex"
+          },
+          "value": {
+            "type": "string",
+            "format": "hex"
+          }
+        },
+        "required": [
+          "block",
+          "exists",
+          "key",
+          "value"
+        ]
+      },
+      "ContractUpgrade": {
+        "type": "object",
+        "properties": {
+          "block": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "code_hash": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "tx_id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "upgrader": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "block",
+          "code_hash",
+          "tx_id",
+          "upgrader"
+        ]
+      },
+      "ContractUpgrades": {
+        "type": "object",
+        "properties": {
+          "code_hash": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "upgrader": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "upgrades": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/ContractUpgrade"
+            }
+          }
+        },
+        "required": [
+          "code_hash",
+          "upgrader",
+          "upgrades"
+        ]
+      },
+      "DelegatedStake": {
+        "type": "object",
+        "properties": {
+          "account": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "amount": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "account",
+          "amount"
+        ]
+      },
+      "Delegations": {
+        "type": "object",
+        "properties": {
+          "commission": {
+            "type": "integer",
+            "format": "int32"
+          },
+          "delegated": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "delegations": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/DelegatedStake"
+            }
+          },
+          "delegators": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/DelegatedStake"
+            }
+          },
+          "public_key": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "unbonding": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/UnbondingStake"
+            }
+          }
+        },
+        "required": [
+          "commission",
+          "delegated",
+          "delegations",
+          "delegators",
+          "public_key",
+          "unbonding"
+        ]
+      },
+      "Error": {
+        "type": "object",
+        "properties": {
+          "code": {
+            "type": "string"
+          },
+          "error": {
+            "type": "string"
+          },
+          "status": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "error",
+          "status"
+        ]
+      },
+      "EstimateFeeRequest": {
+        "type": "object",
+        "properties": {
+          "payload": {
+            "type": "string",
+            "format": "hex"
+          },
+          "sender": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "tag": {
+            "type": "integer",
+            "format": "int32"
+          }
+        },
+        "required": [
+          "payload",
+          "sender",
+          "tag"
+        ]
+      },
+      "FeeEstimate": {
+        "type": "object",
+        "properties": {
+          "fee": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "gas": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "fee",
+          "gas"
+        ]
+      },
+      "FieldDiff": {
+        "type": "object",
+        "properties": {
+          "after": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "before": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "after",
+          "before"
+        ]
+      },
+      "GasSchedule": {
+        "type": "object",
+        "properties": {
+          "default_instruction": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "height": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "host_functions": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "integer",
+              "format": "int64"
+            }
+          },
+          "instructions": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "integer",
+              "format": "int64"
+            }
+          },
+          "version": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "default_instruction",
+          "height",
+          "host_functions",
+          "instructions",
+          "version"
+        ]
+      },
+      "GraphQLRequest": {
+        "type": "object",
+        "properties": {
+          "operationName": {
+            "type": "string"
+          },
+          "query": {
+            "type": "string"
+          },
+          "variables": {
+            "type": "object",
+            "additionalProperties": {}
+          }
+        },
+        "required": [
+          "query"
+        ]
+      },
+      "GraphQLResponse": {
+        "type": "object",
+        "properties": {
+          "data": {
+            "type": "object",
+            "additionalProperties": {}
+          },
+          "errors": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "additionalProperties": {}
+            }
+          }
+        }
+      },
+      "Health": {
+        "type": "object",
+        "properties": {
+          "checks": {
+            "$ref": "#/components/schemas/HealthChecks"
+          },
+          "status": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "checks",
+          "status"
+        ]
+      },
+      "HealthChecks": {
+        "type": "object",
+        "properties": {
+          "consensus": {
+            "$ref": "#/components/schemas/ConsensusHealth"
+          },
+          "peers": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/PeersHealth"
+              }
+            ]
+          },
+          "storage": {
+            "$ref": "#/components/schemas/StorageHealth"
+          },
+          "sync": {
+            "nullable": true,
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/SyncHealth"
+              }
+            ]
+          }
+        },
+        "required": [
+          "consensus",
+          "storage"
+        ]
+      },
+      "LedgerStatusResponse": {
+        "type": "object",
+        "properties": {
+          "address": {
+            "type": "string"
+          },
+          "archival": {
+            "type": "boolean"
+          },
+          "block": {
+            "type": "object",
+            "properties": {
+              "height": {
+                "type": "integer",
+                "format": "int64"
+              },
+              "id": {
+                "type": "string",
+                "format": "hex",
+                "minLength": 64,
+                "maxLength": 64
+              },
+              "merkle_root": {
+                "type": "string",
+                "format": "hex",
+                "minLength": 32,
+                "maxLength": 32
+              },
+              "transactions": {
+                "type": "integer",
+                "format": "int64"
+              }
+            },
+            "required": [
+              "height",
+              "id",
+              "merkle_root",
+              "transactions"
+            ]
+          },
+          "features": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "num_accounts": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "num_accounts_in_store": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "num_missing_tx": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "num_tx": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "num_tx_in_store": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "peers": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/Peer"
+            }
+          },
+          "preferred": {
+            "type": "object",
+            "nullable": true,
+            "properties": {
+              "height": {
+                "type": "integer",
+                "format": "int64"
+              },
+              "id": {
+                "type": "string",
+                "format": "hex",
+                "minLength": 64,
+                "maxLength": 64
+              },
+              "merkle_root": {
+                "type": "string",
+                "format": "hex",
+                "minLength": 32,
+                "maxLength": 32
+              },
+              "transactions": {
+                "type": "integer",
+                "format": "int64"
+              }
+            },
+            "required": [
+              "height",
+              "id",
+              "merkle_root",
+              "transactions"
+            ]
+          },
+          "preferred_votes": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "pruning": {
+            "$ref": "#/components/schemas/PruningStatus"
+          },
+          "public_key": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "snowball": {
+            "$ref": "#/components/schemas/SnowballParams"
+          },
+          "stamp_difficulty": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "sync_status": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "address",
+          "archival",
+          "block",
+          "features",
+          "num_accounts",
+          "num_accounts_in_store",
+          "num_missing_tx",
+          "num_tx",
+          "num_tx_in_store",
+          "peers",
+          "preferred_votes",
+          "pruning",
+          "public_key",
+          "snowball",
+          "stamp_difficulty",
+          "sync_status"
+        ]
+      },
+      "MempoolContains": {
+        "type": "object",
+        "properties": {
+          "contains": {
+            "type": "boolean"
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "contains",
+          "id"
+        ]
+      },
+      "MempoolCount": {
+        "type": "object",
+        "properties": {
+          "count": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "missing": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "count",
+          "missing"
+        ]
+      },
+      "MsgResponse": {
+        "type": "object",
+        "properties": {
+          "msg": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "msg"
+        ]
+      },
+      "Multisig": {
+        "type": "object",
+        "properties": {
+          "participants": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "hex",
+              "minLength": 64,
+              "maxLength": 64
+            }
+          },
+          "proposals": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/MultisigProposal"
+            }
+          },
+          "threshold": {
+            "type": "integer",
+            "format": "int32"
+          }
+        },
+        "required": [
+          "participants",
+          "proposals",
+          "threshold"
+        ]
+      },
+      "MultisigProposal": {
+        "type": "object",
+        "properties": {
+          "amount": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "approvals": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "hex",
+              "minLength": 64,
+              "maxLength": 64
+            }
+          },
+          "block": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "proposer": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "recipient": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "amount",
+          "approvals",
+          "block",
+          "id",
+          "proposer",
+          "recipient"
+        ]
+      },
+      "NameRecord": {
+        "type": "object",
+        "properties": {
+          "expiry": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "name": {
+            "type": "string"
+          },
+          "owner": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "target": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "expiry",
+          "name",
+          "owner",
+          "target"
+        ]
+      },
+      "Peer": {
+        "type": "object",
+        "properties": {
+          "address": {
+            "type": "string"
+          },
+          "public_key": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "address",
+          "public_key"
+        ]
+      },
+      "PeerScore": {
+        "type": "object",
+        "properties": {
+          "address": {
+            "type": "string"
+          },
+          "banned_until": {
+            "type": "string",
+            "format": "date-time",
+            "nullable": true
+          },
+          "connected": {
+            "type": "boolean"
+          },
+          "latency": {
+            "type": "string",
+            "format": "duration",
+            "description": "Go duration, such as 1m30s."
+          },
+          "public_key": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "score": {
+            "type": "number",
+            "format": "double"
+          },
+          "violations": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "integer",
+              "format": "int64"
+            }
+          }
+        },
+        "required": [
+          "address",
+          "connected",
+          "latency",
+          "public_key",
+          "score",
+          "violations"
+        ]
+      },
+      "PeerScores": {
+        "type": "object",
+        "properties": {
+          "ban_duration": {
+            "type": "string",
+            "format": "duration",
+            "description": "Go duration, such as 1m30s."
+          },
+          "ban_score": {
+            "type": "number",
+            "format": "double"
+          },
+          "max_requests_per_second": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "peers": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/PeerScore"
+            }
+          }
+        },
+        "required": [
+          "ban_duration",
+          "ban_score",
+          "max_requests_per_second",
+          "peers"
+        ]
+      },
+      "PeersHealth": {
+        "type": "object",
+        "properties": {
+          "min_peers": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "num_peers": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "ok": {
+            "type": "boolean"
+          }
+        },
+        "required": [
+          "min_peers",
+          "num_peers",
+          "ok"
+        ]
+      },
+      "PendingRecovery": {
+        "type": "object",
+        "properties": {
+          "approvals": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "hex",
+              "minLength": 64,
+              "maxLength": 64
+            }
+          },
+          "block": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "recipient": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "approvals",
+          "block",
+          "recipient"
+        ]
+      },
+      "PruningStatus": {
+        "type": "object",
+        "properties": {
+          "compact_interval": {
+            "type": "string",
+            "format": "duration",
+            "description": "Go duration, such as 1m30s."
+          },
+          "enabled": {
+            "type": "boolean"
+          },
+          "last_compaction_at": {
+            "type": "string",
+            "format": "date-time"
+          },
+          "last_compaction_error": {
+            "type": "string"
+          },
+          "num_compactions": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "num_pruned_diffs": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "retained_blocks": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "retained_from": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "compact_interval",
+          "enabled",
+          "last_compaction_at",
+          "last_compaction_error",
+          "num_compactions",
+          "num_pruned_diffs",
+          "retained_blocks",
+          "retained_from"
+        ]
+      },
+      "Randomness": {
+        "type": "object",
+        "properties": {
+          "index": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "randomness": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "index",
+          "randomness"
+        ]
+      },
+      "Recovery": {
+        "type": "object",
+        "properties": {
+          "delay": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "guardians": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "hex",
+              "minLength": 64,
+              "maxLength": 64
+            }
+          },
+          "threshold": {
+            "type": "integer",
+            "format": "int32"
+          }
+        },
+        "required": [
+          "delay",
+          "guardians",
+          "threshold"
+        ]
+      },
+      "RelayerStats": {
+        "type": "object",
+        "properties": {
+          "accepted": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "fees": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "rejected": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "submitted": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "accepted",
+          "fees",
+          "id",
+          "rejected",
+          "submitted"
+        ]
+      },
+      "RestartRequest": {
+        "type": "object",
+        "properties": {
+          "hard": {
+            "type": "boolean"
+          }
+        }
+      },
+      "Round": {
+        "type": "object",
+        "properties": {
+          "duration": {
+            "type": "string",
+            "format": "duration",
+            "description": "Go duration, such as 1m30s."
+          },
+          "end_id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "finalized_at": {
+            "type": "string",
+            "format": "date-time"
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "index": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "merkle_root": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 32,
+            "maxLength": 32
+          },
+          "num_applied_tx": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "num_rejected_tx": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "num_tx": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "start_id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "started_at": {
+            "type": "string",
+            "format": "date-time"
+          }
+        },
+        "required": [
+          "duration",
+          "end_id",
+          "finalized_at",
+          "id",
+          "index",
+          "merkle_root",
+          "num_applied_tx",
+          "num_rejected_tx",
+          "num_tx",
+          "start_id",
+          "started_at"
+        ]
+      },
+      "SlashingEvent": {
+        "type": "object",
+        "properties": {
+          "amount": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "block": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "burned": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "nonce": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "reason": {
+            "type": "string"
+          },
+          "reporter": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "tx_id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "amount",
+          "block",
+          "burned",
+          "nonce",
+          "reason",
+          "reporter",
+          "tx_id"
+        ]
+      },
+      "Slashings": {
+        "type": "object",
+        "properties": {
+          "events": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/SlashingEvent"
+            }
+          },
+          "last_active": {
+            "type": "integer",
+            "format": "int64",
+            "nullable": true
+          },
+          "public_key": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "slashed": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "events",
+          "public_key",
+          "slashed"
+        ]
+      },
+      "SnowballParams": {
+        "type": "object",
+        "properties": {
+          "alpha": {
+            "type": "number",
+            "format": "double"
+          },
+          "beta": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "k": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "alpha",
+          "beta",
+          "k"
+        ]
+      },
+      "StorageHealth": {
+        "type": "object",
+        "properties": {
+          "error": {
+            "type": "string"
+          },
+          "latency": {
+            "type": "string",
+            "format": "duration",
+            "description": "Go duration, such as 1m30s."
+          },
+          "ok": {
+            "type": "boolean"
+          }
+        },
+        "required": [
+          "latency",
+          "ok"
+        ]
+      },
+      "SyncHealth": {
+        "type": "object",
+        "properties": {
+          "lag": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "max_lag": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "ok": {
+            "type": "boolean"
+          },
+          "reconciled": {
+            "type": "boolean"
+          },
+          "syncing": {
+            "type": "boolean"
+          }
+        },
+        "required": [
+          "lag",
+          "max_lag",
+          "ok",
+          "reconciled",
+          "syncing"
+        ]
+      },
+      "TimeResponse": {
+        "type": "object",
+        "properties": {
+          "time": {
+            "type": "integer",
+            "format": "int64",
+            "description": "Milliseconds since the Unix epoch."
+          }
+        },
+        "required": [
+          "time"
+        ]
+      },
+      "Transaction": {
+        "type": "object",
+        "properties": {
+          "height": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "nonce": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "payload": {
+            "type": "string",
+            "format": "byte",
+            "description": "Base64-encoded payload."
+          },
+          "scheme": {
+            "type": "integer",
+            "format": "int32"
+          },
+          "sender": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "signature": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 128,
+            "maxLength": 128
+          },
+          "stamp": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "status": {
+            "type": "string"
+          },
+          "tag": {
+            "type": "integer",
+            "format": "int32"
+          },
+          "tip": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "version": {
+            "type": "integer",
+            "format": "int32"
+          }
+        },
+        "required": [
+          "height",
+          "id",
+          "nonce",
+          "payload",
+          "sender",
+          "signature",
+          "status",
+          "tag"
+        ]
+      },
+      "TransactionDiff": {
+        "type": "object",
+        "properties": {
+          "accounts": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/AccountDiff"
+            }
+          },
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "accounts",
+          "id"
+        ]
+      },
+      "TxRequest": {
+        "type": "object",
+        "properties": {
+          "block": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "nonce": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "payload": {
+            "type": "string",
+            "format": "hex"
+          },
+          "scheme": {
+            "type": "integer",
+            "format": "int32"
+          },
+          "sender": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "signature": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 128,
+            "maxLength": 128
+          },
+          "stamp": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "tag": {
+            "type": "integer",
+            "format": "int32"
+          },
+          "tip": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "version": {
+            "type": "integer",
+            "format": "int32"
+          }
+        },
+        "required": [
+          "block",
+          "nonce",
+          "payload",
+          "sender",
+          "signature",
+          "tag"
+        ]
+      },
+      "TxResponse": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "id"
+        ]
+      },
+      "UnbondingStake": {
+        "type": "object",
+        "properties": {
+          "amount": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "block": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "validator": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          }
+        },
+        "required": [
+          "amount",
+          "block",
+          "validator"
+        ]
+      },
+      "Validator": {
+        "type": "object",
+        "properties": {
+          "connected": {
+            "type": "boolean"
+          },
+          "delegated": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "last_active": {
+            "type": "integer",
+            "format": "int64",
+            "nullable": true
+          },
+          "public_key": {
+            "type": "string",
+            "format": "hex",
+            "minLength": 64,
+            "maxLength": 64
+          },
+          "stake": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "uptime": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "connected",
+          "delegated",
+          "public_key",
+          "stake",
+          "uptime"
+        ]
+      },
+      "Validators": {
+        "type": "object",
+        "properties": {
+          "block": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "total_stake": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "validators": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/Validator"
+            }
+          }
+        },
+        "required": [
+          "block",
+          "total_stake",
+          "validators"
+        ]
+      },
+      "Webhook": {
+        "type": "object",
+        "properties": {
+          "created_at": {
+            "type": "string",
+            "format": "date-time"
+          },
+          "delivered": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "dropped": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "failed": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "filters": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string"
+            }
+          },
+          "id": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "last_attempt_at": {
+            "type": "string",
+            "format": "date-time",
+            "nullable": true
+          },
+          "last_delivered_at": {
+            "type": "string",
+            "format": "date-time",
+            "nullable": true
+          },
+          "last_error": {
+            "type": "string"
+          },
+          "last_status": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "pending": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "secret": {
+            "type": "string"
+          },
+          "trigger": {
+            "type": "string"
+          },
+          "url": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "created_at",
+          "delivered",
+          "dropped",
+          "failed",
+          "filters",
+          "id",
+          "pending",
+          "trigger",
+          "url"
+        ]
+      },
+      "WebhookRequest": {
+        "type": "object",
+        "properties": {
+          "filters": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string"
+            }
+          },
+          "secret": {
+            "type": "string"
+          },
+          "trigger": {
+            "type": "string"
+          },
+          "url": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "trigger",
+          "url"
+        ]
+      }
+    },
+    "securitySchemes": {
+      "bearer": {
+        "type": "http",
+        "scheme": "bearer",
+        "description": "The secret of the node. Should the node serve an admin API, routes requiring it are served by the admin API alone, and require its token instead."
+      }
+    }
+  }
+}
+`
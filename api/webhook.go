@@ -0,0 +1,431 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/internal/backoff"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// maxWebhooks is the maximum number of webhooks registered at once.
+	maxWebhooks = 64
+
+	// webhookQueueSize is the maximum number of events awaiting delivery to
+	// a webhook, past which further events are dropped.
+	webhookQueueSize = 1024
+
+	webhookTimeout     = 5 * time.Second
+	webhookMaxAttempts = 5
+
+	// webhookSignatureHeader carries the hex-encoded HMAC-SHA256 of the body
+	// of a delivery, keyed by the secret of the webhook.
+	webhookSignatureHeader = "X-Wavelet-Signature"
+	webhookIDHeader        = "X-Wavelet-Webhook"
+)
+
+// webhookBackoff paces the attempts at delivering an event to a webhook.
+var webhookBackoff = backoff.Backoff{ // nolint:gochecknoglobals
+	Min:    500 * time.Millisecond,
+	Max:    30 * time.Second,
+	Factor: 2,
+	Jitter: true,
+}
+
+// webhookTrigger is a kind of events webhooks may be posted.
+type webhookTrigger struct {
+	// mod is the module of the sink the events are read from.
+	mod string
+
+	// filters are the keys and values events must carry, on top of those the
+	// webhook itself filters events by.
+	filters map[string]string
+}
+
+var webhookTriggers = map[string]webhookTrigger{ // nolint:gochecknoglobals
+	"account":    {mod: log.ModuleAccounts},
+	"tx_applied": {mod: log.ModuleTX, filters: map[string]string{log.KeyEvent: events.EventTxApplied}},
+	"contract":   {mod: log.ModuleContract},
+}
+
+// webhookStats accounts for the deliveries of events to a webhook.
+type webhookStats struct {
+	delivered uint64
+	failed    uint64
+	dropped   uint64
+
+	lastAttempt   time.Time
+	lastStatus    int
+	lastError     string
+	lastDelivered time.Time
+}
+
+// webhook posts the events of a trigger to a URL as they are logged, signing
+// them with its secret and retrying failed deliveries.
+type webhook struct {
+	id      uint64
+	url     string
+	trigger string
+	filters map[string]string
+	secret  []byte
+	created time.Time
+
+	sink   *sink
+	client *client
+
+	queue chan []byte
+	stop  chan struct{}
+
+	statsLock sync.Mutex
+	stats     webhookStats
+}
+
+func (w *webhook) run() {
+	go w.forward()
+
+	client := &http.Client{Timeout: webhookTimeout}
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case body := <-w.queue:
+			w.deliver(client, body)
+		}
+	}
+}
+
+// forward moves events from the sink to the queue of deliveries, such that
+// the sink is not held up by deliveries being retried.
+func (w *webhook) forward() {
+	for buf := range w.client.queue {
+		select {
+		case w.queue <- buf:
+		default:
+			w.statsLock.Lock()
+			w.stats.dropped++
+			w.statsLock.Unlock()
+		}
+	}
+}
+
+// deliver posts body to the webhook until it is accepted, or until it was
+// attempted webhookMaxAttempts times.
+func (w *webhook) deliver(client *http.Client, body []byte) {
+	mac := hmac.New(sha256.New, w.secret)
+	_, _ = mac.Write(body)
+
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	for attempt := 1; ; attempt++ {
+		status, err := w.post(client, body, signature)
+
+		w.statsLock.Lock()
+
+		w.stats.lastAttempt = time.Now()
+		w.stats.lastStatus = status
+		w.stats.lastError = ""
+
+		switch {
+		case err == nil:
+			w.stats.delivered++
+			w.stats.lastDelivered = w.stats.lastAttempt
+		case attempt >= webhookMaxAttempts:
+			w.stats.failed++
+			w.stats.lastError = err.Error()
+		default:
+			w.stats.lastError = err.Error()
+		}
+
+		w.statsLock.Unlock()
+
+		if err == nil {
+			return
+		}
+
+		if attempt >= webhookMaxAttempts {
+			logger := log.Node()
+			logger.Warn().Err(err).Str("url", w.url).Msg("Gave up on delivering an event to a webhook.")
+
+			return
+		}
+
+		timer := time.NewTimer(webhookBackoff.ForAttempt(float64(attempt - 1)))
+
+		select {
+		case <-timer.C:
+		case <-w.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// post posts body as JSON to the webhook, returning the status code it
+// responded with.
+func (w *webhook) post(client *http.Client, body []byte, signature string) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookIDHeader, strconv.FormatUint(w.id, 10))
+	req.Header.Set(webhookSignatureHeader, "sha256="+signature)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		return res.StatusCode, errors.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+func (w *webhook) getStats() webhookStats {
+	w.statsLock.Lock()
+	defer w.statsLock.Unlock()
+
+	return w.stats
+}
+
+// webhookBook keeps track of the webhooks registered since the node started.
+type webhookBook struct {
+	sync.Mutex
+
+	lastID uint64
+	hooks  map[uint64]*webhook
+}
+
+func newWebhookBook() *webhookBook {
+	return &webhookBook{hooks: make(map[uint64]*webhook)}
+}
+
+// register assigns hook an ID, and starts posting it the events of sink
+// carrying all of the keys and values of filters.
+func (b *webhookBook) register(hook *webhook, sink *sink, filters map[string]string) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if len(b.hooks) >= maxWebhooks {
+		return errors.Errorf("at most %d webhooks may be registered", maxWebhooks)
+	}
+
+	b.lastID++
+
+	hook.id = b.lastID
+	hook.created = time.Now()
+	hook.sink = sink
+	hook.queue = make(chan []byte, webhookQueueSize)
+	hook.stop = make(chan struct{})
+	hook.client = sink.subscribeFiltered(filters)
+
+	b.hooks[hook.id] = hook
+
+	go hook.run()
+
+	return nil
+}
+
+func (b *webhookBook) get(id uint64) (*webhook, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	hook, exists := b.hooks[id]
+
+	return hook, exists
+}
+
+// list returns all webhooks, ordered by ID.
+func (b *webhookBook) list() []*webhook {
+	b.Lock()
+	defer b.Unlock()
+
+	hooks := make([]*webhook, 0, len(b.hooks))
+
+	for _, hook := range b.hooks {
+		hooks = append(hooks, hook)
+	}
+
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].id < hooks[j].id
+	})
+
+	return hooks
+}
+
+// remove stops posting events to the webhook with ID id, abandoning any
+// events awaiting delivery.
+func (b *webhookBook) remove(id uint64) bool {
+	b.Lock()
+	hook, exists := b.hooks[id]
+	delete(b.hooks, id)
+	b.Unlock()
+
+	if !exists {
+		return false
+	}
+
+	hook.sink.unsubscribe(hook.client)
+	close(hook.stop)
+
+	return true
+}
+
+// removeAll stops posting events to all webhooks.
+func (b *webhookBook) removeAll() {
+	for _, hook := range b.list() {
+		b.remove(hook.id)
+	}
+}
+
+// registerWebhook registers a webhook which events of the trigger requested
+// are posted to, responding with the webhook including its secret.
+func (g *Gateway) registerWebhook(ctx *fasthttp.RequestCtx) {
+	req := &registerWebhookRequest{}
+
+	parser := g.parserPool.Get()
+	defer g.parserPool.Put(parser)
+
+	if err := req.bind(parser, ctx.PostBody()); err != nil {
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	trigger := webhookTriggers[req.Trigger]
+
+	g.sinksLock.RLock()
+	sink, exists := g.sinks[trigger.mod]
+	g.sinksLock.RUnlock()
+
+	if !exists {
+		g.renderError(ctx, ErrInternal(errors.Errorf("module %q does not stream events", trigger.mod)))
+		return
+	}
+
+	for key := range req.Filters {
+		if _, ok := sink.filters[key]; !ok {
+			g.renderError(ctx, ErrBadRequest(errors.Errorf("events of trigger %q may not be filtered by %q", req.Trigger, key)))
+			return
+		}
+	}
+
+	filters := sink.resolveFilters(func(queryKey string) string {
+		return req.Filters[queryKey]
+	})
+
+	for key, val := range trigger.filters {
+		filters[key] = val
+	}
+
+	hook := &webhook{
+		url:     req.URL,
+		trigger: req.Trigger,
+		filters: req.Filters,
+		secret:  []byte(req.Secret),
+	}
+
+	if len(hook.secret) == 0 {
+		var secret [32]byte
+
+		if _, err := rand.Read(secret[:]); err != nil {
+			g.renderError(ctx, ErrInternal(errors.Wrap(err, "failed to generate webhook secret")))
+			return
+		}
+
+		hook.secret = []byte(hex.EncodeToString(secret[:]))
+	}
+
+	if err := g.webhooks.register(hook, sink, filters); err != nil {
+		g.renderError(ctx, ErrBadRequest(err))
+		return
+	}
+
+	g.render(ctx, &webhookResponse{hook: hook, withSecret: true})
+}
+
+func (g *Gateway) listWebhooks(ctx *fasthttp.RequestCtx) {
+	g.render(ctx, webhookList(g.webhooks.list()))
+}
+
+func (g *Gateway) getWebhook(ctx *fasthttp.RequestCtx) {
+	hook, errRes := g.webhookByID(ctx)
+	if errRes != nil {
+		g.renderError(ctx, errRes)
+		return
+	}
+
+	g.render(ctx, &webhookResponse{hook: hook})
+}
+
+func (g *Gateway) deleteWebhook(ctx *fasthttp.RequestCtx) {
+	hook, errRes := g.webhookByID(ctx)
+	if errRes != nil {
+		g.renderError(ctx, errRes)
+		return
+	}
+
+	g.webhooks.remove(hook.id)
+
+	g.render(ctx, &msgResponse{msg: fmt.Sprintf("Deleted webhook %d", hook.id)})
+}
+
+// webhookByID looks up the webhook whose ID is the id parameter of the path.
+func (g *Gateway) webhookByID(ctx *fasthttp.RequestCtx) (*webhook, *errResponse) {
+	param, ok := ctx.UserValue("id").(string)
+	if !ok {
+		return nil, ErrBadRequest(errors.New("could not cast id into string"))
+	}
+
+	id, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest(errors.Wrap(err, "could not parse webhook ID"))
+	}
+
+	hook, exists := g.webhooks.get(id)
+	if !exists {
+		return nil, ErrNotFound(errors.Errorf("could not find webhook with ID %d", id))
+	}
+
+	return hook, nil
+}
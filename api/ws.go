@@ -116,13 +116,16 @@ func (c *client) writeWorker() {
 func (s *sink) serve(ctx *fasthttp.RequestCtx) error {
 	values := ctx.QueryArgs()
 
-	filters := make(map[string]string)
-	for queryKey, key := range s.filters {
-		if queryValue := values.Peek(queryKey); len(queryValue) > 0 {
-			filters[key] = string(queryValue)
-		}
-	}
+	filters := s.resolveFilters(func(queryKey string) string {
+		return string(values.Peek(queryKey))
+	})
+
+	return s.serveFiltered(ctx, filters)
+}
 
+// serveFiltered is serve, with messages being filtered by the given keys of
+// the messages rather than by query parameters.
+func (s *sink) serveFiltered(ctx *fasthttp.RequestCtx, filters map[string]string) error {
 	return upgrader.Upgrade(ctx, func(conn *websocket.Conn) {
 		client := &client{
 			filters: filters,
@@ -139,6 +142,46 @@ func (s *sink) serve(ctx *fasthttp.RequestCtx) error {
 	})
 }
 
+// subscribe joins a client to the sink without a websocket, such that
+// messages are read straight from its queue until it is unsubscribed.
+func (s *sink) subscribe(query func(queryKey string) string) *client {
+	return s.subscribeFiltered(s.resolveFilters(query))
+}
+
+// subscribeFiltered is subscribe, with messages being filtered by the given
+// keys of the messages rather than by query parameters.
+func (s *sink) subscribeFiltered(filters map[string]string) *client {
+	client := &client{
+		filters: filters,
+		sink:    s,
+		queue:   make(chan []byte, 256),
+		done:    make(chan struct{}),
+	}
+
+	s.join <- client
+
+	return client
+}
+
+func (s *sink) unsubscribe(client *client) {
+	s.leave <- client
+	close(client.done)
+}
+
+// resolveFilters maps the query parameters a client filters messages by to
+// the keys of the messages they filter.
+func (s *sink) resolveFilters(query func(queryKey string) string) map[string]string {
+	filters := make(map[string]string)
+
+	for queryKey, key := range s.filters {
+		if queryValue := query(queryKey); len(queryValue) > 0 {
+			filters[key] = queryValue
+		}
+	}
+
+	return filters
+}
+
 type broadcastItem struct {
 	buf   []byte
 	value *fastjson.Value
@@ -0,0 +1,153 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+)
+
+// StateNotKeptError is returned when querying the state of a block which our
+// node did not keep, as it is not an archival node or did not finalize the
+// block itself.
+type StateNotKeptError struct {
+	Index  uint64
+	Latest uint64
+
+	Archival bool
+}
+
+func (e *StateNotKeptError) Error() string {
+	if e.Archival {
+		return fmt.Sprintf("state of block %d is not kept, only that of blocks finalized by our node up to block %d",
+			e.Index, e.Latest)
+	}
+
+	return fmt.Sprintf("state of block %d is not kept, only that of block %d", e.Index, e.Latest)
+}
+
+// StoreArchivedBlock saves block to kv, such that the state of the ledger
+// as of block may be looked up by the index of block.
+func StoreArchivedBlock(kv store.KV, block Block) error {
+	if err := kv.Put(archivedBlockKey(block.Index), block.Marshal()); err != nil {
+		return errors.Wrapf(err, "error archiving block %d", block.Index)
+	}
+
+	return nil
+}
+
+// LoadArchivedBlock loads the block with index index from the blocks saved
+// to kv by StoreArchivedBlock.
+func LoadArchivedBlock(kv store.KV, index uint64) (*Block, error) {
+	buf, err := kv.Get(archivedBlockKey(index))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error loading archived block %d", index)
+	}
+
+	block, err := UnmarshalBlock(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	return &block, nil
+}
+
+func archivedBlockKey(index uint64) []byte {
+	key := make([]byte, len(keyArchivedBlocks)+8)
+
+	copy(key, keyArchivedBlocks[:])
+	binary.BigEndian.PutUint64(key[len(keyArchivedBlocks):], index)
+
+	return key
+}
+
+// Archival returns whether our node keeps the state of the ledger as of
+// every block it finalizes.
+func (l *Ledger) Archival() bool {
+	return l.archival
+}
+
+// SnapshotLatest returns a snapshot of the latest state of the ledger,
+// alongside the block whose state it is.
+func (l *Ledger) SnapshotLatest() (*avl.Tree, *Block, error) {
+	snapshot := l.accounts.Snapshot()
+
+	block, err := l.blocks.committed(snapshot.Checksum())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return snapshot, block, nil
+}
+
+// SnapshotAt returns a snapshot of the state of the ledger as of the block
+// with index index, alongside the block. Only archival nodes keep the state
+// of blocks other than the latest, a *StateNotKeptError being returned for
+// blocks whose state is not kept.
+func (l *Ledger) SnapshotAt(index uint64) (*avl.Tree, *Block, error) {
+	snapshot, block, err := l.SnapshotLatest()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if block.Index == index {
+		return snapshot, block, nil
+	}
+
+	notKept := &StateNotKeptError{Index: index, Latest: block.Index, Archival: l.archival}
+
+	if !l.archival || index > block.Index {
+		return nil, nil, notKept
+	}
+
+	archived, err := LoadArchivedBlock(l.db, index)
+	if err != nil {
+		if errors.Cause(err) == store.ErrNotFound {
+			return nil, nil, notKept
+		}
+
+		return nil, nil, err
+	}
+
+	snapshot, err = snapshot.SnapshotAt(archived.Merkle)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load state of block %d", index)
+	}
+
+	return snapshot, archived, nil
+}
+
+// archive archives block, should our node be an archival node.
+func (l *Ledger) archive(block Block) {
+	if !l.archival {
+		return
+	}
+
+	if err := StoreArchivedBlock(l.db, block); err != nil {
+		logger := log.Node()
+		logger.Error().Err(err).Msg("Failed to archive block")
+	}
+}
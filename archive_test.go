@@ -0,0 +1,81 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchivedState(t *testing.T) {
+	kv := store.NewInmem()
+
+	accounts := NewAccounts(kv)
+
+	var blocks []Block
+
+	for i := 0; i < 3; i++ {
+		snapshot := accounts.Snapshot()
+		WriteAccountBalance(snapshot, AccountID{1}, uint64(i+1)*10)
+
+		if !assert.NoError(t, accounts.Commit(snapshot)) {
+			return
+		}
+
+		block := NewBlock(uint64(i), snapshot.Checksum())
+		if !assert.NoError(t, StoreArchivedBlock(kv, block)) {
+			return
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	_, err := LoadArchivedBlock(kv, 3)
+	assert.Equal(t, store.ErrNotFound, errors.Cause(err))
+
+	// So long as old roots are not collected, the state of every archived
+	// block may be looked up.
+	for i, block := range blocks {
+		archived, err := LoadArchivedBlock(kv, block.Index)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, block.ID, archived.ID)
+
+		snapshot, err := accounts.Snapshot().SnapshotAt(archived.Merkle)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, block.Merkle, snapshot.Checksum())
+
+		balance, _ := ReadAccountBalance(snapshot, AccountID{1})
+		assert.EqualValues(t, (i+1)*10, balance)
+	}
+
+	notKept := &StateNotKeptError{Index: 1, Latest: 2}
+	assert.Equal(t, "state of block 1 is not kept, only that of block 2", notKept.Error())
+}
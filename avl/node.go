@@ -474,6 +474,21 @@ func (n *node) dfs(t *Tree, allowMissingNodes bool, cb func(*node) (bool, error)
 }
 
 func DeserializeFromDifference(r io.Reader, localViewID uint64) (*node, error) { // nolint:golint
+	n, err := deserializeFromDifference(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.viewID <= localViewID {
+		return nil, errors.New("got view id < local view id")
+	}
+
+	return n, nil
+}
+
+// deserializeFromDifference reads a node written by serializeForDifference,
+// regardless of its view ID.
+func deserializeFromDifference(r io.Reader) (*node, error) {
 	var (
 		buf64 [8]byte
 		id    [MerkleHashSize]byte
@@ -490,9 +505,6 @@ func DeserializeFromDifference(r io.Reader, localViewID uint64) (*node, error) {
 	}
 
 	viewID := binary.LittleEndian.Uint64(buf64[:])
-	if viewID <= localViewID {
-		return nil, errors.New("got view id < local view id")
-	}
 
 	_, err = r.Read(buf64[:1])
 	if err != nil {
@@ -661,7 +673,12 @@ func deserialize(r *bytes.Reader) (*node, error) {
 		return nil, err
 	}
 
-	n.key = make([]byte, binary.LittleEndian.Uint32(buf64[:4]))
+	keyLen := binary.LittleEndian.Uint32(buf64[:4])
+	if int64(keyLen) > int64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	n.key = make([]byte, keyLen)
 
 	if _, err := r.Read(n.key); err != nil {
 		return nil, err
@@ -672,7 +689,12 @@ func deserialize(r *bytes.Reader) (*node, error) {
 			return nil, err
 		}
 
-		n.value = make([]byte, binary.LittleEndian.Uint32(buf64[:4]))
+		valueLen := binary.LittleEndian.Uint32(buf64[:4])
+		if int64(valueLen) > int64(r.Len()) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		n.value = make([]byte, valueLen)
 
 		if _, err := r.Read(n.value); err != nil {
 			return nil, err
@@ -0,0 +1,171 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package avl
+
+import (
+	"bytes"
+
+	"github.com/minio/highwayhash"
+	"github.com/pkg/errors"
+	"github.com/valyala/bytebufferpool"
+)
+
+var ErrInvalidProof = errors.New("avl: invalid proof")
+
+// Proof proves the value of a key in a tree, or that the key is absent from
+// it, against the Merkle root of the tree.
+//
+// It holds the serialized nodes a lookup of the key visits, from the root
+// down to a leaf. Lefts[i] holds the serialized left child of Path[i] should
+// the lookup have gone right at Path[i], such that the lookup may be replayed,
+// and is nil otherwise.
+type Proof struct {
+	Path  [][]byte
+	Lefts [][]byte
+}
+
+// Prove returns a proof of the value of key in the tree, or of key being
+// absent from the tree.
+func (t *Tree) Prove(key []byte) (Proof, error) {
+	var proof Proof
+
+	for n := t.root; n != nil; {
+		proof.Path = append(proof.Path, serializeNode(n))
+
+		if n.kind == NodeLeafValue {
+			proof.Lefts = append(proof.Lefts, nil)
+			break
+		}
+
+		left, err := t.loadLeft(n)
+		if err != nil {
+			return proof, err
+		}
+
+		if bytes.Compare(key, left.key) <= 0 {
+			proof.Lefts = append(proof.Lefts, nil)
+			n = left
+
+			continue
+		}
+
+		proof.Lefts = append(proof.Lefts, serializeNode(left))
+
+		if n, err = t.loadRight(n); err != nil {
+			return proof, err
+		}
+	}
+
+	return proof, nil
+}
+
+// Verify checks the proof against the Merkle root of a tree, returning the
+// value of key in the tree, and whether key exists in the tree at all.
+func (p Proof) Verify(root [MerkleHashSize]byte, key []byte) ([]byte, bool, error) {
+	if len(p.Path) == 0 {
+		if root != ([MerkleHashSize]byte{}) {
+			return nil, false, errors.Wrap(ErrInvalidProof, "proof is empty, yet the tree is not")
+		}
+
+		return nil, false, nil
+	}
+
+	if len(p.Lefts) != len(p.Path) {
+		return nil, false, errors.Wrap(ErrInvalidProof, "proof has a left child missing")
+	}
+
+	path := make([]*node, len(p.Path))
+
+	for i, buf := range p.Path {
+		n, err := deserializeProofNode(buf)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "node %d of the proof is invalid", i)
+		}
+
+		path[i] = n
+	}
+
+	if path[0].id != root {
+		return nil, false, errors.Wrap(ErrInvalidProof, "proof does not start at the root")
+	}
+
+	for i, n := range path[:len(path)-1] {
+		if n.kind != NodeNonLeaf {
+			return nil, false, errors.Wrapf(ErrInvalidProof, "node %d of the proof is a leaf", i)
+		}
+
+		next := path[i+1]
+
+		if p.Lefts[i] == nil {
+			if next.id != n.left || bytes.Compare(key, next.key) > 0 {
+				return nil, false, errors.Wrapf(ErrInvalidProof, "lookup may not go left at node %d", i)
+			}
+
+			continue
+		}
+
+		left, err := deserializeProofNode(p.Lefts[i])
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "left child of node %d of the proof is invalid", i)
+		}
+
+		if left.id != n.left || next.id != n.right || bytes.Compare(key, left.key) <= 0 {
+			return nil, false, errors.Wrapf(ErrInvalidProof, "lookup may not go right at node %d", i)
+		}
+	}
+
+	leaf := path[len(path)-1]
+
+	if leaf.kind != NodeLeafValue {
+		return nil, false, errors.Wrap(ErrInvalidProof, "proof does not end at a leaf")
+	}
+
+	if !bytes.Equal(leaf.key, key) {
+		return nil, false, nil
+	}
+
+	return leaf.value, true, nil
+}
+
+func serializeNode(n *node) []byte {
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	if err := n.serialize(buf); err != nil {
+		panic(err)
+	}
+
+	return append([]byte(nil), buf.Bytes()...)
+}
+
+// deserializeProofNode deserializes a node of a proof, whose ID is the hash
+// of buf. buf must be the exact serialization of the node.
+func deserializeProofNode(buf []byte) (*node, error) {
+	n, err := deserialize(bytes.NewReader(buf))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidProof, err.Error())
+	}
+
+	if n.id != highwayhash.Sum128(buf, hashKey) {
+		return nil, errors.Wrap(ErrInvalidProof, "node is not serialized canonically")
+	}
+
+	return n, nil
+}
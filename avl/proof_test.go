@@ -0,0 +1,117 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package avl
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProof(t *testing.T) {
+	tree := New(store.NewInmem())
+
+	key := func(i uint64) []byte {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], i)
+
+		return buf[:]
+	}
+
+	// An empty tree proves every key absent.
+	proof, err := tree.Prove(key(0))
+	assert.NoError(t, err)
+
+	_, exists, err := proof.Verify(tree.Checksum(), key(0))
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// Only even keys are inserted, for odd keys to be absent.
+	for i := uint64(0); i <= 200; i += 2 {
+		tree.Insert(key(i), key(i*10))
+	}
+
+	root := tree.Checksum()
+
+	for i := uint64(0); i <= 201; i++ {
+		proof, err := tree.Prove(key(i))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		value, exists, err := proof.Verify(root, key(i))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, i%2 == 0, exists, i)
+
+		if exists {
+			assert.Equal(t, key(i*10), value)
+		}
+	}
+
+	proof, err = tree.Prove(key(42))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The proof of a key does not prove any other key present or absent.
+	_, _, err = proof.Verify(root, key(44))
+	assert.Equal(t, ErrInvalidProof, errors.Cause(err))
+
+	_, _, err = proof.Verify(root, key(43))
+	assert.Equal(t, ErrInvalidProof, errors.Cause(err))
+
+	// Nor does it prove anything against another root.
+	tree.Insert(key(1), key(10))
+
+	_, _, err = proof.Verify(tree.Checksum(), key(42))
+	assert.Equal(t, ErrInvalidProof, errors.Cause(err))
+
+	// Tampering with the value of the leaf is caught.
+	leaf := proof.Path[len(proof.Path)-1]
+	leaf[len(leaf)-10]++
+
+	_, _, err = proof.Verify(root, key(42))
+	assert.Equal(t, ErrInvalidProof, errors.Cause(err))
+
+	leaf[len(leaf)-10]--
+
+	// So is leaving nodes out.
+	truncated := Proof{Path: proof.Path[:len(proof.Path)-1], Lefts: proof.Lefts[:len(proof.Lefts)-1]}
+
+	_, _, err = truncated.Verify(root, key(42))
+	assert.Equal(t, ErrInvalidProof, errors.Cause(err))
+
+	_, _, err = Proof{}.Verify(root, key(42))
+	assert.Equal(t, ErrInvalidProof, errors.Cause(err))
+
+	// The untampered proof remains valid.
+	value, exists, err := proof.Verify(root, key(42))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, key(420), value)
+}
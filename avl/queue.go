@@ -12,6 +12,10 @@ type diffQueue struct {
 	viewID uint64
 	ids    map[[MerkleHashSize]byte]struct{}
 
+	// full is whether nodes of any view ID are accepted, as they are when
+	// the whole tree is rebuilt.
+	full bool
+
 	Reader    io.Reader
 	OnDequeue func(*node) error
 }
@@ -34,7 +38,17 @@ func (h *diffQueue) Has(id [MerkleHashSize]byte) bool {
 }
 
 func (h *diffQueue) Dequeue() (*node, error) {
-	node, err := DeserializeFromDifference(h.Reader, h.viewID)
+	var (
+		node *node
+		err  error
+	)
+
+	if h.full {
+		node, err = deserializeFromDifference(h.Reader)
+	} else {
+		node, err = DeserializeFromDifference(h.Reader, h.viewID)
+	}
+
 	if err != nil {
 		return nil, err
 	}
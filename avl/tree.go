@@ -116,6 +116,25 @@ func (t *Tree) Snapshot() *Tree {
 	return &Tree{kv: t.kv, cache: t.cache, maxWriteBatchSize: t.maxWriteBatchSize, root: t.root}
 }
 
+// SnapshotAt returns a snapshot of the tree as of the version whose root has
+// ID root, which must not have been garbage collected.
+func (t *Tree) SnapshotAt(root [MerkleHashSize]byte) (*Tree, error) {
+	snapshot := &Tree{kv: t.kv, cache: t.cache, maxWriteBatchSize: t.maxWriteBatchSize}
+
+	if root == ([MerkleHashSize]byte{}) {
+		return snapshot, nil
+	}
+
+	n, err := t.loadNode(root)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.root = n
+
+	return snapshot, nil
+}
+
 func (t *Tree) Revert(snapshot *Tree) {
 	t.root = snapshot.root
 }
@@ -377,6 +396,12 @@ func (t *Tree) SetViewID(viewID uint64) {
 }
 
 func (t *Tree) iterateDiff(prevViewID uint64, callback func(n *node) bool) {
+	t.iterateNodes(func(n *node) bool { return n.viewID > prevViewID }, callback)
+}
+
+// iterateNodes walks the tree depth-first from its root, skipping the
+// subtrees of nodes include does not return true for.
+func (t *Tree) iterateNodes(include func(n *node) bool, callback func(n *node) bool) {
 	var stack queue.Queue
 
 	stack.PushBack(t.root)
@@ -384,7 +409,7 @@ func (t *Tree) iterateDiff(prevViewID uint64, callback func(n *node) bool) {
 	for stack.Len() > 0 {
 		current := stack.PopBack().(*node)
 
-		if current.viewID <= prevViewID {
+		if !include(current) {
 			continue
 		}
 
@@ -402,9 +427,19 @@ func (t *Tree) iterateDiff(prevViewID uint64, callback func(n *node) bool) {
 
 // DumpDiff writes the AVL tree difference into a io.Writer.
 func (t *Tree) DumpDiff(prevViewID uint64, wr io.Writer) error {
+	return t.dump(func(n *node) bool { return n.viewID > prevViewID }, wr)
+}
+
+// Dump writes all nodes of the AVL tree into a io.Writer, in the format of
+// DumpDiff, such that ApplyDump rebuilds the tree from scratch.
+func (t *Tree) Dump(wr io.Writer) error {
+	return t.dump(func(*node) bool { return true }, wr)
+}
+
+func (t *Tree) dump(include func(n *node) bool, wr io.Writer) error {
 	nodeIDs := make([][MerkleHashSize]byte, 0)
 
-	t.iterateDiff(prevViewID, func(n *node) bool {
+	t.iterateNodes(include, func(n *node) bool {
 		nodeIDs = append(nodeIDs, n.id)
 		return true
 	})
@@ -422,7 +457,7 @@ func (t *Tree) DumpDiff(prevViewID uint64, wr io.Writer) error {
 
 	var lastErr error
 
-	t.iterateDiff(prevViewID, func(n *node) bool {
+	t.iterateNodes(include, func(n *node) bool {
 		if err := n.serializeForDifference(wr); err != nil {
 			lastErr = err
 			return false
@@ -445,6 +480,15 @@ func (t *Tree) IterateLeafDiff(prevViewID uint64, callback func(key, value []byt
 }
 
 func (t *Tree) ApplyDiffWithUpdateNotifier(diff io.Reader, updateNotifier func(key, value []byte)) error {
+	return t.applyDiff(diff, updateNotifier, false)
+}
+
+// ApplyDump rebuilds the tree out of all of its nodes, as written by Dump.
+func (t *Tree) ApplyDump(dump io.Reader) error {
+	return t.applyDiff(dump, nil, true)
+}
+
+func (t *Tree) applyDiff(diff io.Reader, updateNotifier func(key, value []byte), full bool) error {
 	// Deserialize header
 	var nodeCount uint64
 	if err := binary.Read(diff, binary.LittleEndian, &nodeCount); err != nil {
@@ -461,6 +505,7 @@ func (t *Tree) ApplyDiffWithUpdateNotifier(diff io.Reader, updateNotifier func(k
 	)
 
 	preloaded := newDiffQueue(t.kv, DiffsKeyPrefix)
+	preloaded.full = full
 
 	for i := uint64(0); i < nodeCount; i++ {
 		if _, err := diff.Read(id[:]); err != nil {
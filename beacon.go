@@ -0,0 +1,160 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"encoding/binary"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/security/vrf"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+)
+
+// The randomness beacon yields a 32-byte random value for every block. The
+// value of a block starts off as a hash of the value of the previous block,
+// and validators fold contributions into it through beacon transactions:
+//
+//	beacon(n) = blake2b256(... blake2b256(blake2b256(beacon(n-1) | n) | vrf_1) ... | vrf_m)
+//
+// A contribution is the VRF output of a validator's key over the index and
+// beacon value of the block the transaction was created at. As VRF outputs
+// are unique, validators may not grind the beacon, though they may withhold
+// their contributions. Values are recorded in the ledger state, such that
+// contracts may read the values of the last sys.BeaconRetention blocks.
+
+// NewBeaconContribution creates the payload of a beacon transaction created
+// at the block at index, whose beacon value is previous.
+func NewBeaconContribution(key edwards25519.PrivateKey, index uint64, previous [32]byte) Beacon {
+	proof, _ := vrf.Prove(key, beaconInput(index, previous))
+
+	return Beacon{Proof: proof}
+}
+
+func beaconInput(index uint64, previous [32]byte) []byte {
+	const prefix = "wavelet-beacon"
+
+	buf := make([]byte, len(prefix)+8+len(previous))
+
+	copy(buf, prefix)
+	binary.BigEndian.PutUint64(buf[len(prefix):], index)
+	copy(buf[len(prefix)+8:], previous[:])
+
+	return buf
+}
+
+// previousBeacon returns the beacon value of the latest finalized block
+// current, given the ledger state after it. Blocks finalized before the
+// beacon was introduced have their ID serve as their beacon value.
+func previousBeacon(tree *avl.Tree, current *Block) [32]byte {
+	if value, exists := ReadBeacon(tree, current.Index); exists {
+		return value
+	}
+
+	return current.ID
+}
+
+// initialBeacon returns the beacon value of the block at height succeeding
+// current before any contributions are folded in.
+func initialBeacon(tree *avl.Tree, current *Block, height uint64) [32]byte {
+	previous := previousBeacon(tree, current)
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], height)
+
+	return blake2b.Sum256(append(previous[:], buf[:]...))
+}
+
+// applyBeacon records the initial beacon value of the block at height
+// succeeding current into tree, and prunes the value recorded
+// sys.BeaconRetention blocks before it.
+func applyBeacon(tree *avl.Tree, current *Block, height uint64) {
+	WriteBeacon(tree, height, initialBeacon(tree, current, height))
+
+	if height > sys.BeaconRetention {
+		DeleteBeacon(tree, height-sys.BeaconRetention)
+	}
+}
+
+// verifyBeaconContribution checks that the beacon transaction tx was made by
+// a validator over a recorded beacon value, and returns its VRF output.
+func verifyBeaconContribution(
+	readBeacon func(uint64) ([32]byte, bool), stake uint64, tx *Transaction, beacon Beacon,
+) (vrf.Output, error) {
+	if tx.Scheme != security.SchemeEd25519 {
+		return vrf.Output{}, errors.Errorf("beacon: contributions must be signed with %s keys", security.SchemeEd25519)
+	}
+
+	if stake < sys.MinimumStake {
+		return vrf.Output{}, errors.Errorf(
+			"beacon: %x has a stake of %d PERLs, but only validators staking at least %d PERLs may contribute",
+			tx.Sender, stake, sys.MinimumStake,
+		)
+	}
+
+	previous, exists := readBeacon(tx.Block)
+	if !exists {
+		return vrf.Output{}, errors.Errorf("beacon: no beacon value is recorded for block %d", tx.Block)
+	}
+
+	output, err := vrf.Verify(tx.Sender, beaconInput(tx.Block, previous), beacon.Proof)
+	if err != nil {
+		return output, errors.Wrapf(err, "beacon: invalid contribution from %x", tx.Sender)
+	}
+
+	return output, nil
+}
+
+func applyBeaconTransaction(ctx *CollapseContext, block *Block, tx *Transaction) error {
+	payload, err := ParseBeacon(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	height := block.Index + 1
+
+	if tx.Block >= height {
+		return errors.Errorf("beacon: contribution made at block %d may not be applied at block %d", tx.Block, height)
+	}
+
+	if _, contributed := ctx.contributors[tx.Sender]; contributed {
+		return errors.Errorf("beacon: %x already contributed to block %d", tx.Sender, height)
+	}
+
+	stake, _ := ctx.ReadAccountStake(tx.Sender)
+
+	output, err := verifyBeaconContribution(ctx.ReadBeacon, stake, tx, payload)
+	if err != nil {
+		return err
+	}
+
+	current, exists := ctx.ReadBeacon(height)
+	if !exists {
+		current = initialBeacon(ctx.tree, block, height)
+	}
+
+	ctx.WriteBeacon(height, blake2b.Sum256(append(current[:], output[:]...)))
+	ctx.contributors[tx.Sender] = struct{}{}
+
+	return nil
+}
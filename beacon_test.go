@@ -0,0 +1,209 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/life/exec"
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyBeacon(t *testing.T) {
+	tree := avl.New(store.NewInmem())
+	genesis := NewBlock(0, tree.Checksum())
+
+	// Blocks preceding the beacon have their ID serve as their beacon value.
+	assert.Equal(t, genesis.ID, previousBeacon(tree, &genesis))
+
+	applyBeacon(tree, &genesis, 1)
+
+	first, exists := ReadBeacon(tree, 1)
+	require.True(t, exists)
+	assert.NotEqual(t, genesis.ID, first)
+
+	// The beacon advances deterministically without contributions.
+	other := avl.New(store.NewInmem())
+	applyBeacon(other, &genesis, 1)
+
+	value, _ := ReadBeacon(other, 1)
+	assert.Equal(t, first, value)
+
+	block := NewBlock(1, tree.Checksum())
+	applyBeacon(tree, &block, 2)
+
+	second, _ := ReadBeacon(tree, 2)
+	assert.NotEqual(t, first, second)
+
+	// Values are pruned once they are sys.BeaconRetention blocks old.
+	block = NewBlock(sys.BeaconRetention, tree.Checksum())
+	applyBeacon(tree, &block, sys.BeaconRetention+1)
+
+	_, exists = ReadBeacon(tree, 1)
+	assert.False(t, exists)
+
+	_, exists = ReadBeacon(tree, 2)
+	assert.True(t, exists)
+}
+
+func TestCollapseBeaconUnscheduled(t *testing.T) {
+	accounts := NewAccounts(store.NewInmem())
+	genesis := NewBlock(0, accounts.Snapshot().Checksum())
+
+	// No beacon value is recorded until the beacon activates, leaving the
+	// state of blocks as it was before the beacon was introduced.
+	res, err := collapseTransactions(1, nil, &genesis, accounts)
+	require.NoError(t, err)
+
+	_, exists := ReadBeacon(res.snapshot, 1)
+	assert.False(t, exists)
+
+	defer ScheduleFeatures(sys.FeatureBeacon)()
+
+	res, err = collapseTransactions(1, nil, &genesis, accounts)
+	require.NoError(t, err)
+
+	_, exists = ReadBeacon(res.snapshot, 1)
+	assert.True(t, exists)
+}
+
+func TestBeaconTransaction(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureBeacon)()
+
+	validator, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	other, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	tree := avl.New(store.NewInmem())
+
+	for _, keys := range []*skademlia.Keypair{validator, other} {
+		WriteAccountBalance(tree, keys.PublicKey(), 1000000)
+	}
+
+	WriteAccountStake(tree, validator.PublicKey(), sys.MinimumStake)
+
+	genesis := NewBlock(0, tree.Checksum())
+	applyBeacon(tree, &genesis, 1)
+
+	previous, _ := ReadBeacon(tree, 1)
+
+	block := NewBlock(1, tree.Checksum())
+	applyBeacon(tree, &block, 2)
+
+	initial, _ := ReadBeacon(tree, 2)
+
+	contribution := func(keys *skademlia.Keypair, index uint64, previous [32]byte) Transaction {
+		payload, err := NewBeaconContribution(keys.PrivateKey(), index, previous).Marshal()
+		require.NoError(t, err)
+
+		return NewTransaction(keys, 1, index, sys.TagBeacon, payload)
+	}
+
+	tx := contribution(validator, 1, previous)
+	require.NoError(t, ValidateTransaction(tree, tx))
+
+	ctx := NewCollapseContext(tree)
+	require.NoError(t, ctx.ApplyTransaction(&block, &tx))
+
+	// Validators may contribute once per block.
+	assert.Error(t, ctx.ApplyTransaction(&block, &tx))
+	require.NoError(t, ctx.Flush())
+
+	value, _ := ReadBeacon(tree, 2)
+	assert.NotEqual(t, initial, value)
+
+	// Only validators may contribute.
+	tx = contribution(other, 1, previous)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// Contributions must be over the beacon value of the block they were
+	// created at, and may only be applied to blocks succeeding it.
+	tx = contribution(validator, 1, initial)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = contribution(validator, 2, value)
+	assert.NoError(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// Contributions may not be batched.
+	var batch Batch
+	require.NoError(t, batch.AddTransfer(Transfer{Recipient: other.PublicKey(), Amount: 1}))
+
+	batch.Tags[0] = byte(sys.TagBeacon)
+
+	payload, err := batch.Marshal()
+	require.NoError(t, err)
+
+	_, err = ParseBatch(payload)
+	assert.Error(t, err)
+}
+
+func TestRandomness(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureBeacon)()
+
+	tree := avl.New(store.NewInmem())
+	WriteBeacon(tree, 1, [32]byte{1, 2, 3})
+
+	read := func(block *Block, index uint64, size int) (int64, []byte) {
+		vm := &exec.VirtualMachine{
+			Memory:    make([]byte, size),
+			CallStack: []exec.Frame{{Locals: []int64{int64(index), 0, int64(size)}}},
+		}
+
+		executor := &ContractExecutor{tree: tree, block: block}
+		ret := executor.ResolveFunc("env", "_randomness")(vm)
+
+		return ret, vm.Memory
+	}
+
+	block := NewBlock(1, tree.Checksum())
+
+	ret, out := read(&block, 1, 32)
+	assert.EqualValues(t, 0, ret)
+	assert.Equal(t, []byte{1, 2, 3}, out[:3])
+
+	// Values of blocks not yet finalized, or never recorded, may not be read.
+	ret, _ = read(&block, 2, 32)
+	assert.EqualValues(t, 1, ret)
+
+	ret, _ = read(&block, 0, 32)
+	assert.EqualValues(t, 1, ret)
+
+	ret, _ = read(&block, 1, 16)
+	assert.EqualValues(t, 1, ret)
+
+	// The import is unknown to contracts until the beacon activates.
+	sys.FeatureActivations[sys.FeatureBeacon] = 3
+
+	assert.Panics(t, func() {
+		(&ContractExecutor{tree: tree, block: &block}).ResolveFunc("env", "_randomness")
+	})
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package bench provides a reproducible suite of micro and macro benchmarks
+// over wavelet, and renders their results as reports that may be compared
+// across versions with tools such as benchstat.
+package bench
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// Benchmark is a named benchmark of the suite.
+type Benchmark struct {
+	Name string
+	F    func(b *testing.B)
+}
+
+// Options configure the benchmarks of the suite.
+type Options struct {
+	// ContractPath is the path to the WASM contract invoked by the contract
+	// benchmarks. They are skipped should the contract not be found.
+	ContractPath string
+
+	// Nodes is the number of nodes of the in-process devnet, the faucet
+	// included.
+	Nodes int
+
+	// Batch is the number of transactions submitted to the devnet per
+	// iteration of the end-to-end benchmarks.
+	Batch int
+}
+
+// DefaultOptions returns the options the suite is run with by default.
+func DefaultOptions() Options {
+	return Options{
+		ContractPath: "testdata/transfer_back.wasm",
+		Nodes:        3,
+		Batch:        100,
+	}
+}
+
+// Suite returns all benchmarks of the suite, micro benchmarks first.
+func Suite(opts Options) []Benchmark {
+	return append(Micro(opts), Macro(opts)...)
+}
+
+// Result is the result of running a benchmark.
+type Result struct {
+	Name    string `json:"name"`
+	Skipped bool   `json:"skipped,omitempty"`
+
+	N           int                `json:"n"`
+	NsPerOp     int64              `json:"ns_per_op"`
+	BytesPerOp  int64              `json:"bytes_per_op"`
+	AllocsPerOp int64              `json:"allocs_per_op"`
+	Extra       map[string]float64 `json:"extra,omitempty"`
+}
+
+// Run runs the benchmarks whose names match filter, in order, for roughly
+// benchtime each. A nil filter matches all benchmarks, and a zero benchtime
+// defaults to one second.
+func Run(benchmarks []Benchmark, filter *regexp.Regexp, benchtime time.Duration) ([]Result, error) {
+	if benchtime > 0 {
+		// testing.Benchmark only reads the benchmark time from the flags of
+		// package testing.
+		testing.Init()
+
+		if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+			return nil, errors.Wrap(err, "failed to set benchmark time")
+		}
+	}
+
+	var results []Result
+
+	for _, bm := range benchmarks {
+		if filter != nil && !filter.MatchString(bm.Name) {
+			continue
+		}
+
+		r := testing.Benchmark(bm.F)
+
+		results = append(results, Result{
+			Name:        bm.Name,
+			Skipped:     r.N == 0,
+			N:           r.N,
+			NsPerOp:     r.NsPerOp(),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+			AllocsPerOp: r.AllocsPerOp(),
+			Extra:       r.Extra,
+		})
+	}
+
+	return results, nil
+}
+
+// WriteReport writes results in the text format of `go test -bench`, such
+// that reports of different versions may be compared with benchstat.
+func WriteReport(w io.Writer, results []Result) error {
+	if _, err := fmt.Fprintf(w, "goos: %s\ngoarch: %s\npkg: github.com/perlin-network/wavelet\n", runtime.GOOS,
+		runtime.GOARCH); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "version: %s\ncommit: %s\n", sys.Version, sys.GitCommit); err != nil {
+		return err
+	}
+
+	// As with `go test`, names are suffixed with GOMAXPROCS unless it is one.
+	var suffix string
+	if procs := runtime.GOMAXPROCS(0); procs > 1 {
+		suffix = fmt.Sprintf("-%d", procs)
+	}
+
+	for _, r := range results {
+		name := "Benchmark" + r.Name + suffix
+
+		if r.Skipped {
+			if _, err := fmt.Fprintf(w, "%s\tSKIP\n", name); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		line := fmt.Sprintf("%s\t%8d\t%10d ns/op", name, r.N, r.NsPerOp)
+
+		units := make([]string, 0, len(r.Extra))
+		for unit := range r.Extra {
+			units = append(units, unit)
+		}
+
+		sort.Strings(units)
+
+		for _, unit := range units {
+			line += fmt.Sprintf("\t%10.2f %s", r.Extra[unit], unit)
+		}
+
+		line += fmt.Sprintf("\t%8d B/op\t%8d allocs/op", r.BytesPerOp, r.AllocsPerOp)
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON writes results as a JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(results)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAndReport(t *testing.T) {
+	benchmarks := []Benchmark{
+		{Name: "Noop", F: func(b *testing.B) {
+			b.ReportMetric(42, "tx/s")
+		}},
+		{Name: "Skipped", F: func(b *testing.B) { b.Skip() }},
+		{Name: "Filtered", F: func(b *testing.B) { t.Fatal("filtered benchmark was run") }},
+	}
+
+	results, err := Run(benchmarks, regexp.MustCompile("^(Noop|Skipped)$"), 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "Noop", results[0].Name)
+	assert.False(t, results[0].Skipped)
+	assert.NotZero(t, results[0].N)
+	assert.Equal(t, 42.0, results[0].Extra["tx/s"])
+
+	assert.True(t, results[1].Skipped)
+
+	var report bytes.Buffer
+	require.NoError(t, WriteReport(&report, results))
+
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	assert.Regexp(t, `^BenchmarkNoop(-\d+)?\t\s*\d+\t\s*\d+ ns/op\t\s*42.00 tx/s\t\s*\d+ B/op\t\s*\d+ allocs/op$`,
+		lines[len(lines)-2])
+	assert.Regexp(t, `^BenchmarkSkipped(-\d+)?\tSKIP$`, lines[len(lines)-1])
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, results))
+
+	var decoded []Result
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, results, decoded)
+}
+
+func BenchmarkSuite(b *testing.B) {
+	opts := DefaultOptions()
+	opts.ContractPath = "../" + opts.ContractPath
+
+	for _, bm := range Suite(opts) {
+		b.Run(bm.Name, bm.F)
+	}
+}
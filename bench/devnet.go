@@ -0,0 +1,252 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bench
+
+import (
+	"encoding/hex"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise"
+	"github.com/perlin-network/noise/cipher"
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/noise/handshake"
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// faucetKey is the private key of the account funded by the default genesis.
+const faucetKey = "87a6813c3b4cf534b6ae82db9b1409fa7dbd5c13dba5858970b56084c4a930eb" +
+	"400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+// Macro returns the macro benchmarks of the suite, which measure the ledger
+// end-to-end on an in-process devnet.
+func Macro(opts Options) []Benchmark {
+	return []Benchmark{
+		{Name: "Devnet/TPS", F: benchmarkDevnetTPS(opts.Nodes, opts.Batch)},
+	}
+}
+
+// node is a node of an in-process devnet, whose state is kept in memory.
+type node struct {
+	keys   *skademlia.Keypair
+	client *skademlia.Client
+	ledger *wavelet.Ledger
+	server *grpc.Server
+	addr   string
+	nonce  uint64
+}
+
+func newNode(keys *skademlia.Keypair, peers []string) (*node, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(ln.Addr().(*net.TCPAddr).Port))
+
+	client := skademlia.NewClient(addr, keys, skademlia.WithC1(sys.SKademliaC1), skademlia.WithC2(sys.SKademliaC2))
+	client.SetCredentials(noise.NewCredentials(addr, handshake.NewECDH(), cipher.NewAEAD(), client.Protocol()))
+
+	ledger, err := wavelet.NewLedger(store.NewInmem(), client, wavelet.WithoutGC())
+	if err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+
+	server := client.Listen()
+	wavelet.RegisterWaveletServer(server, ledger.Protocol())
+
+	go func() {
+		_ = server.Serve(ln)
+	}()
+
+	n := &node{keys: keys, client: client, ledger: ledger, server: server, addr: addr}
+
+	for _, peer := range peers {
+		if _, err := client.Dial(peer); err != nil {
+			n.close()
+			return nil, errors.Wrapf(err, "failed to dial %s", peer)
+		}
+	}
+
+	client.Bootstrap()
+
+	return n, nil
+}
+
+func (n *node) balance() uint64 {
+	balance, _ := wavelet.ReadAccountBalance(n.ledger.Snapshot(), n.keys.PublicKey())
+	return balance
+}
+
+func (n *node) pay(to *node, amount uint64) error {
+	payload, err := wavelet.Transfer{Recipient: to.keys.PublicKey(), Amount: amount}.Marshal()
+	if err != nil {
+		return err
+	}
+
+	n.nonce++
+	n.ledger.AddTransaction(wavelet.NewTransaction(
+		n.keys, n.nonce, n.ledger.Blocks().Latest().Index, sys.TagTransfer, payload,
+	))
+
+	return nil
+}
+
+func (n *node) close() {
+	n.server.Stop()
+	n.ledger.Close()
+}
+
+// newDevnet starts a devnet of size nodes, the first of which is the faucet.
+func newDevnet(size int) ([]*node, error) {
+	var key edwards25519.PrivateKey
+	if _, err := hex.Decode(key[:], []byte(faucetKey)); err != nil {
+		return nil, err
+	}
+
+	var nodes []*node
+
+	closeAll := func() {
+		for _, n := range nodes {
+			n.close()
+		}
+	}
+
+	for i := 0; i < size; i++ {
+		var (
+			keys *skademlia.Keypair
+			err  error
+		)
+
+		if i == 0 {
+			keys, err = skademlia.LoadKeys(key, sys.SKademliaC1, sys.SKademliaC2)
+		} else {
+			keys, err = skademlia.NewKeys(sys.SKademliaC1, sys.SKademliaC2)
+		}
+
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+
+		var peers []string
+		if i > 0 {
+			peers = append(peers, nodes[0].addr)
+		}
+
+		n, err := newNode(keys, peers)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	// Wait for every node to discover all others.
+	err := waitFor(30*time.Second, func() bool {
+		for _, n := range nodes {
+			if len(n.client.ClosestPeerIDs()) < size-1 {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	if err != nil {
+		closeAll()
+		return nil, errors.Wrap(err, "devnet failed to bootstrap")
+	}
+
+	return nodes, nil
+}
+
+func waitFor(timeout time.Duration, fn func() bool) error {
+	deadline := time.Now().Add(timeout)
+
+	for !fn() {
+		if time.Now().After(deadline) {
+			return errors.New("timed out")
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return nil
+}
+
+// benchmarkDevnetTPS measures the number of transfers per second a devnet
+// of the given number of nodes finalizes. Every iteration, the faucet submits
+// batch transfers to another node, and waits for that node to see all of
+// them applied.
+func benchmarkDevnetTPS(size, batch int) func(b *testing.B) {
+	return func(b *testing.B) {
+		if size < 2 {
+			size = 2
+		}
+
+		if batch < 1 {
+			batch = 1
+		}
+
+		nodes, err := newDevnet(size)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		defer func() {
+			for _, n := range nodes {
+				n.close()
+			}
+		}()
+
+		faucet, recipient := nodes[0], nodes[1]
+		balance := recipient.balance()
+
+		b.ResetTimer()
+
+		start := time.Now()
+
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < batch; j++ {
+				if err := faucet.pay(recipient, 1); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			balance += uint64(batch)
+
+			if err := waitFor(30*time.Second, func() bool { return recipient.balance() >= balance }); err != nil {
+				b.Fatalf("transfers were not finalized: %v", err)
+			}
+		}
+
+		b.ReportMetric(float64(b.N*batch)/time.Since(start).Seconds(), "tx/s")
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package bench
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// Micro returns the micro benchmarks of the suite, which each measure a
+// single operation in isolation.
+func Micro(opts Options) []Benchmark {
+	return []Benchmark{
+		{Name: "Tx/Sign", F: benchmarkTxSign},
+		{Name: "Tx/Verify", F: benchmarkTxVerify},
+		{Name: "Tx/Marshal", F: benchmarkTxMarshal},
+		{Name: "Tx/Unmarshal", F: benchmarkTxUnmarshal},
+		{Name: "Transfer/Marshal", F: benchmarkTransferMarshal},
+		{Name: "Transfer/Unmarshal", F: benchmarkTransferUnmarshal},
+		{Name: "MerkleRoot/100", F: benchmarkMerkleRoot(100)},
+		{Name: "MerkleRoot/10000", F: benchmarkMerkleRoot(10000)},
+		{Name: "Contract/Invoke", F: benchmarkContractInvoke(opts.ContractPath)},
+	}
+}
+
+func newKeys(b *testing.B) *skademlia.Keypair {
+	keys, err := skademlia.NewKeys(1, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return keys
+}
+
+func newTransfer(b *testing.B) []byte {
+	payload, err := wavelet.Transfer{
+		Recipient:  wavelet.AccountID{1},
+		Amount:     1337,
+		GasLimit:   100000,
+		GasDeposit: 10,
+		FuncName:   []byte("on_money_received"),
+		FuncParams: make([]byte, 64),
+	}.Marshal()
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return payload
+}
+
+func benchmarkTxSign(b *testing.B) {
+	keys := newKeys(b)
+	payload := newTransfer(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		wavelet.NewTransaction(keys, uint64(i), 0, sys.TagTransfer, payload)
+	}
+}
+
+func benchmarkTxVerify(b *testing.B) {
+	tx := wavelet.NewTransaction(newKeys(b), 1, 0, sys.TagTransfer, newTransfer(b))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if !tx.VerifySignature() {
+			b.Fatal("signature is invalid")
+		}
+	}
+}
+
+func benchmarkTxMarshal(b *testing.B) {
+	tx := wavelet.NewTransaction(newKeys(b), 1, 0, sys.TagTransfer, newTransfer(b))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tx.Marshal()
+	}
+}
+
+func benchmarkTxUnmarshal(b *testing.B) {
+	buf := wavelet.NewTransaction(newKeys(b), 1, 0, sys.TagTransfer, newTransfer(b)).Marshal()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := wavelet.UnmarshalTransaction(bytes.NewReader(buf)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkTransferMarshal(b *testing.B) {
+	transfer, err := wavelet.ParseTransfer(newTransfer(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := transfer.Marshal(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkTransferUnmarshal(b *testing.B) {
+	payload := newTransfer(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := wavelet.ParseTransfer(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkMerkleRoot measures computing the Merkle root of a ledger state
+// of size accounts after one of its accounts is updated.
+func benchmarkMerkleRoot(size int) func(b *testing.B) {
+	return func(b *testing.B) {
+		tree := avl.New(store.NewInmem())
+
+		var id wavelet.AccountID
+
+		for i := 0; i < size; i++ {
+			binary.BigEndian.PutUint64(id[:], uint64(i))
+			wavelet.WriteAccountBalance(tree, id, uint64(i))
+		}
+
+		tree.Checksum()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			binary.BigEndian.PutUint64(id[:], uint64(i%size))
+			wavelet.WriteAccountBalance(tree, id, uint64(i))
+
+			tree.Checksum()
+		}
+	}
+}
+
+// benchmarkContractInvoke measures invoking the on_money_received function
+// of the contract at path, which transfers half of the amount received back
+// to its sender.
+func benchmarkContractInvoke(path string) func(b *testing.B) {
+	return func(b *testing.B) {
+		code, err := ioutil.ReadFile(path)
+		if err != nil {
+			b.Skipf("contract %q not found", path)
+		}
+
+		keys := newKeys(b)
+
+		tree := avl.New(store.NewInmem())
+		wavelet.WriteAccountBalance(tree, keys.PublicKey(), math.MaxUint64/2)
+
+		block := wavelet.NewBlock(1, tree.Checksum())
+
+		payload, err := wavelet.Contract{GasLimit: 100000, Code: code}.Marshal()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		contract := wavelet.NewTransaction(keys, 1, block.Index, sys.TagContract, payload)
+		if err := wavelet.ApplyTransaction(tree, &block, &contract); err != nil {
+			b.Fatal(err)
+		}
+
+		payload, err = wavelet.Transfer{
+			Recipient: contract.ID,
+			Amount:    200,
+			GasLimit:  500000,
+			FuncName:  []byte("on_money_received"),
+		}.Marshal()
+
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			tx := wavelet.NewTransaction(keys, uint64(i)+2, block.Index, sys.TagTransfer, payload)
+
+			if err := wavelet.ApplyTransaction(tree, &block, &tx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
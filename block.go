@@ -94,12 +94,25 @@ func UnmarshalBlock(r io.Reader) (Block, error) {
 		return block, errors.Wrap(err, "failed to decode block's transactions length")
 	}
 
-	block.Transactions = make([]TransactionID, binary.BigEndian.Uint32(buf[:4]))
+	// Transaction IDs are appended as they are read, such that a bogus
+	// number of transactions may not exhaust memory.
+	count := binary.BigEndian.Uint32(buf[:4])
 
-	for i := 0; i < len(block.Transactions); i++ {
-		if _, err := io.ReadFull(r, block.Transactions[i][:]); err != nil {
+	capacity := count
+	if capacity > 1024 {
+		capacity = 1024
+	}
+
+	block.Transactions = make([]TransactionID, 0, capacity)
+
+	for i := uint32(0); i < count; i++ {
+		var id TransactionID
+
+		if _, err := io.ReadFull(r, id[:]); err != nil {
 			return block, errors.Wrap(err, "failed to decode one of the transactions")
 		}
+
+		block.Transactions = append(block.Transactions, id)
 	}
 
 	block.ID = blake2b.Sum256(block.Marshal())
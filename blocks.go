@@ -105,6 +105,29 @@ func (b *Blocks) GetByIndex(ix uint64) (*Block, error) {
 	return block, nil
 }
 
+// committed returns the block whose Merkle root is root, being either the
+// latest block or the one preceding it.
+func (b *Blocks) committed(root MerkleNodeID) (*Block, error) {
+	// Blocks are saved right before their state is committed, such that the
+	// state may still be that of the block preceding the latest.
+	block := b.Latest()
+
+	if block.Merkle != root && block.Index > 0 {
+		previous, err := b.GetByIndex(block.Index - 1)
+		if err != nil {
+			return nil, err
+		}
+
+		block = previous
+	}
+
+	if block.Merkle != root {
+		return nil, fmt.Errorf("ledger state does not match the Merkle root of block %d", block.Index)
+	}
+
+	return block, nil
+}
+
 func (b *Blocks) Clone() []*Block {
 	b.RLock()
 
@@ -0,0 +1,116 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package canonical implements the canonical binary encoding of content that
+// is signed, such that every node and client, whatever its platform or
+// language, signs and verifies the exact same bytes.
+//
+// An encoding starts with the version of the encoding, followed by a domain
+// naming the kind of content encoded, such that a signature of one kind of
+// content may never be mistaken for that of another. Fields follow in a
+// fixed order:
+//
+//   - integers are big-endian, and of a fixed size,
+//   - byte strings of a fixed size are written as is,
+//   - byte strings of a variable size are prefixed with their length as a
+//     big-endian uint32.
+//
+// The domain is encoded as a variable-size byte string.
+//
+// JSON documents which are signed, such as API request bodies, are signed
+// as the canonical encoding of their domain followed by their canonical
+// JSON, as returned by JSON.
+package canonical
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Version is the version of the encoding produced by Encoder.
+const Version byte = 1
+
+// Encoder encodes fields into a canonical encoding.
+type Encoder struct {
+	buf []byte
+}
+
+// New starts the canonical encoding of content of the given domain.
+func New(domain string) *Encoder {
+	e := &Encoder{buf: make([]byte, 0, 64)}
+	e.buf = append(e.buf, Version)
+
+	return e.String(domain)
+}
+
+// Uint8 appends v.
+func (e *Encoder) Uint8(v uint8) *Encoder {
+	e.buf = append(e.buf, v)
+	return e
+}
+
+// Uint32 appends v.
+func (e *Encoder) Uint32(v uint32) *Encoder {
+	var buf [4]byte
+
+	binary.BigEndian.PutUint32(buf[:], v)
+	e.buf = append(e.buf, buf[:]...)
+
+	return e
+}
+
+// Uint64 appends v.
+func (e *Encoder) Uint64(v uint64) *Encoder {
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], v)
+	e.buf = append(e.buf, buf[:]...)
+
+	return e
+}
+
+// Fixed appends b, which must be of a size fixed by the domain, such as a
+// public key or a hash.
+func (e *Encoder) Fixed(b []byte) *Encoder {
+	e.buf = append(e.buf, b...)
+	return e
+}
+
+// Bytes appends b prefixed with its length. It panics should b be longer
+// than math.MaxUint32 bytes.
+func (e *Encoder) Bytes(b []byte) *Encoder {
+	if uint64(len(b)) > math.MaxUint32 {
+		panic("canonical: byte string too long to be encoded")
+	}
+
+	e.Uint32(uint32(len(b)))
+	e.buf = append(e.buf, b...)
+
+	return e
+}
+
+// String appends s prefixed with its length.
+func (e *Encoder) String(s string) *Encoder {
+	return e.Bytes([]byte(s))
+}
+
+// Encode returns the encoding.
+func (e *Encoder) Encode() []byte {
+	return e.buf
+}
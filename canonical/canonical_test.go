@@ -0,0 +1,124 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package canonical
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoder(t *testing.T) {
+	buf := New("test").
+		Uint8(0x01).
+		Uint32(0x02030405).
+		Uint64(0x060708090a0b0c0d).
+		Fixed([]byte{0xaa, 0xbb}).
+		Bytes([]byte{0xcc}).
+		Bytes(nil).
+		String("hi").
+		Encode()
+
+	// Implementations of the encoding in other languages must produce these
+	// exact bytes.
+	assert.Equal(t, ""+
+		"01"+ // version
+		"00000004"+"74657374"+ // domain
+		"01"+
+		"02030405"+
+		"060708090a0b0c0d"+
+		"aabb"+
+		"00000001"+"cc"+
+		"00000000"+
+		"00000002"+"6869",
+		hex.EncodeToString(buf),
+	)
+}
+
+func TestEncoderDomains(t *testing.T) {
+	// Content of distinct domains never encodes the same, even should the
+	// domains be prefixes of one another.
+	assert.NotEqual(t, New("a").Bytes([]byte("b")).Encode(), New("ab").Encode())
+	assert.NotEqual(t, New("a").Encode(), New("b").Encode())
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{`{ "b": 1, "a": [true, false, null] }`, `{"a":[true,false,null],"b":1}`},
+		{`{"a":{"d":1,"c":2},"B":3}`, `{"B":3,"a":{"c":2,"d":1}}`},
+		{`"A\/é \"\\"`, "\"A/é \\\"\\\\\""},
+		{`"\b\f\n\r\t\u0000\u001F"`, `"\b\f\n\r\t\u0000\u001f"`},
+		{`{"é":1,"z":2}`, "{\"z\":2,\"é\":1}"},
+		{`[0, -0, 18446744073709551615, -9223372036854775808]`, `[0,0,18446744073709551615,-9223372036854775808]`},
+		{`[1.0, 1e3, -2.5e1, 9007199254740991.0]`, `[1,1000,-25,9007199254740991]`},
+	}
+
+	for _, test := range tests {
+		out, err := JSON([]byte(test.in))
+		if assert.NoError(t, err, test.in) {
+			assert.Equal(t, test.out, string(out))
+		}
+	}
+
+	for _, in := range []string{
+		`{"a":1,"a":2}`,
+		`1.5`,
+		`1e400`,
+		`9007199254740993.0`,
+		`18446744073709551616`,
+		"\"\xff\"",
+		`{"a":`,
+	} {
+		_, err := JSON([]byte(in))
+		assert.Error(t, err, in)
+	}
+}
+
+func TestSignJSON(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	signer := security.NewEd25519Signer(keys.PrivateKey())
+
+	signature, err := SignJSON(signer, "test", []byte(`{"amount":1000,"recipient":"ab"}`))
+	assert.NoError(t, err)
+
+	pub := keys.PublicKey()
+
+	// Signatures survive the document being re-encoded by another library.
+	assert.NoError(t, VerifyJSON(
+		security.SchemeEd25519, pub[:], "test", []byte("{\n  \"recipient\": \"ab\",\n  \"amount\": 1e3\n}"), signature,
+	))
+
+	// Yet neither its content nor its domain may change.
+	assert.Error(t, VerifyJSON(
+		security.SchemeEd25519, pub[:], "test", []byte(`{"amount":1001,"recipient":"ab"}`), signature,
+	))
+	assert.Error(t, VerifyJSON(
+		security.SchemeEd25519, pub[:], "other", []byte(`{"amount":1000,"recipient":"ab"}`), signature,
+	))
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package canonical
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/perlin-network/wavelet/security"
+	"github.com/pkg/errors"
+	"github.com/valyala/fastjson"
+)
+
+// HTTP headers carrying the signature of the canonical JSON of a request
+// body, along with the public key and scheme it was signed with. The public
+// key and signature are hex-encoded, and the scheme is optional, defaulting
+// to Ed25519.
+const (
+	HeaderPublicKey = "X-Wavelet-Public-Key"
+	HeaderSignature = "X-Wavelet-Signature"
+	HeaderScheme    = "X-Wavelet-Signature-Scheme"
+)
+
+// DomainRelay is the domain relayers sign the bodies of the transactions they
+// submit on behalf of their senders under.
+const DomainRelay = "wavelet/relay"
+
+// maxSafeInteger is the largest integer up to which every integer is exactly
+// representable as a float64, being Number.MAX_SAFE_INTEGER of JavaScript.
+const maxSafeInteger = 1<<53 - 1
+
+var (
+	// ErrDuplicateKey is returned for JSON objects holding a key twice.
+	ErrDuplicateKey = errors.New("canonical: duplicate JSON object key")
+
+	// ErrNumber is returned for JSON numbers which are not integers, or are
+	// integers that may not be represented exactly.
+	ErrNumber = errors.New("canonical: JSON numbers must be exact integers")
+
+	// ErrUTF8 is returned for JSON strings which are not valid UTF-8.
+	ErrUTF8 = errors.New("canonical: JSON strings must be valid UTF-8")
+)
+
+// JSON returns the canonical form of the JSON document b, such that
+// documents which are equal in content are equal in bytes, whichever JSON
+// library produced them:
+//
+//   - insignificant whitespace is removed,
+//   - object keys are sorted by their UTF-8 bytes, and may not repeat,
+//   - strings only escape '"', '\' and control characters, the latter as
+//     \b, \f, \n, \r, \t or otherwise \u00xx in lower case,
+//   - numbers must be integers, and are written in base 10 without a sign
+//     unless negative, nor leading zeros, fractions or exponents. Integers
+//     written with fractions or exponents must be at most 2^53-1 in
+//     magnitude, so as to be exactly representable by every library.
+func JSON(b []byte) ([]byte, error) {
+	var p fastjson.Parser
+
+	v, err := p.ParseBytes(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "canonical: invalid JSON")
+	}
+
+	return appendJSON(make([]byte, 0, len(b)), v)
+}
+
+// JSONMessage returns the message signed for the JSON document b of the
+// given domain, being the canonical encoding of the domain and the
+// canonical JSON of b.
+func JSONMessage(domain string, b []byte) ([]byte, error) {
+	canonical, err := JSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(domain).Bytes(canonical).Encode(), nil
+}
+
+// SignJSON signs the JSON document b of the given domain.
+func SignJSON(signer security.Signer, domain string, b []byte) ([]byte, error) {
+	msg, err := JSONMessage(domain, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.Sign(msg)
+}
+
+// VerifyJSON verifies the signature of the JSON document b of the given
+// domain. Signatures remain valid however b is re-encoded, so long as its
+// content is left intact.
+func VerifyJSON(scheme security.Scheme, publicKey []byte, domain string, b, signature []byte) error {
+	msg, err := JSONMessage(domain, b)
+	if err != nil {
+		return err
+	}
+
+	return security.Verify(scheme, publicKey, msg, signature)
+}
+
+func appendJSON(dst []byte, v *fastjson.Value) ([]byte, error) {
+	switch v.Type() {
+	case fastjson.TypeNull:
+		return append(dst, "null"...), nil
+	case fastjson.TypeTrue:
+		return append(dst, "true"...), nil
+	case fastjson.TypeFalse:
+		return append(dst, "false"...), nil
+	case fastjson.TypeString:
+		return appendString(dst, v.GetStringBytes())
+	case fastjson.TypeNumber:
+		return appendNumber(dst, v.MarshalTo(nil))
+	case fastjson.TypeArray:
+		dst = append(dst, '[')
+
+		for i, item := range v.GetArray() {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+
+			var err error
+
+			if dst, err = appendJSON(dst, item); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(dst, ']'), nil
+	case fastjson.TypeObject:
+		return appendObject(dst, v.GetObject())
+	default:
+		return nil, errors.Errorf("canonical: unknown JSON type %s", v.Type())
+	}
+}
+
+func appendObject(dst []byte, o *fastjson.Object) ([]byte, error) {
+	type field struct {
+		key   []byte
+		value *fastjson.Value
+	}
+
+	fields := make([]field, 0, o.Len())
+
+	o.Visit(func(key []byte, v *fastjson.Value) {
+		fields = append(fields, field{key: key, value: v})
+	})
+
+	sort.Slice(fields, func(i, j int) bool {
+		return bytes.Compare(fields[i].key, fields[j].key) < 0
+	})
+
+	dst = append(dst, '{')
+
+	for i, f := range fields {
+		if i > 0 {
+			if bytes.Equal(fields[i-1].key, f.key) {
+				return nil, errors.Wrapf(ErrDuplicateKey, "key %q", f.key)
+			}
+
+			dst = append(dst, ',')
+		}
+
+		var err error
+
+		if dst, err = appendString(dst, f.key); err != nil {
+			return nil, err
+		}
+
+		dst = append(dst, ':')
+
+		if dst, err = appendJSON(dst, f.value); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(dst, '}'), nil
+}
+
+func appendString(dst []byte, s []byte) ([]byte, error) {
+	if !utf8.Valid(s) {
+		return nil, ErrUTF8
+	}
+
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c == '\b':
+			dst = append(dst, '\\', 'b')
+		case c == '\f':
+			dst = append(dst, '\\', 'f')
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		case c < 0x20:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			dst = append(dst, c)
+		}
+	}
+
+	return append(dst, '"'), nil
+}
+
+func appendNumber(dst []byte, raw []byte) ([]byte, error) {
+	s := string(raw)
+
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return strconv.AppendUint(dst, u, 10), nil
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return strconv.AppendInt(dst, i, 10), nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > maxSafeInteger {
+		return nil, errors.Wrapf(ErrNumber, "number %s", s)
+	}
+
+	return strconv.AppendInt(dst, int64(f), 10), nil
+}
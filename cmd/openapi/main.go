@@ -0,0 +1,166 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Command openapi writes the OpenAPI document of the HTTP API of a node, and
+// typed clients of the API generated from it.
+//
+// The document served by nodes at /swagger.json is generated into package api
+// by go generate, which runs:
+//
+//	openapi spec -go api -o swagger_gen.go
+//
+// A TypeScript client is generated by running:
+//
+//	openapi client -lang typescript -o wavelet.ts
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/perlin-network/wavelet/api/openapi"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func main() {
+	app := cli.NewApp()
+
+	app.Name = "openapi"
+	app.Author = "Perlin"
+	app.Email = "support@perlin.net"
+	app.Version = sys.Version
+	app.Usage = "generate the OpenAPI document of the wavelet HTTP API, and typed clients of it"
+
+	cli.VersionPrinter = func(c *cli.Context) {
+		fmt.Printf("Version:    %s\n", sys.Version)
+		fmt.Printf("Go Version: %s\n", sys.GoVersion)
+		fmt.Printf("Git Commit: %s\n", sys.GitCommit)
+		fmt.Printf("OS/Arch:    %s\n", sys.OSArch)
+		fmt.Printf("Built:      %s\n", c.App.Compiled.Format(time.ANSIC))
+	}
+
+	output := cli.StringFlag{
+		Name:  "o",
+		Usage: "file to write to, instead of the standard output",
+	}
+
+	app.Commands = []cli.Command{
+		{
+			Name:  "spec",
+			Usage: "write the OpenAPI document of the API as JSON",
+			Flags: []cli.Flag{
+				output,
+				cli.StringFlag{
+					Name:  "go",
+					Usage: "write a Go source file of the given package declaring the document as the constant SwaggerJSON",
+				},
+			},
+			Action: spec,
+		},
+		{
+			Name:  "client",
+			Usage: "write a typed client of the API",
+			Flags: []cli.Flag{
+				output,
+				cli.StringFlag{
+					Name:  "lang",
+					Usage: "language of the client: typescript",
+					Value: "typescript",
+				},
+			},
+			Action: client,
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func spec(c *cli.Context) error {
+	doc, err := openapi.Build(openapi.Routes)
+	if err != nil {
+		return errors.Wrap(err, "failed to build the OpenAPI document")
+	}
+
+	buf, err := doc.MarshalIndent()
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the OpenAPI document")
+	}
+
+	if pkg := c.String("go"); pkg != "" {
+		if bytes.Contains(buf, []byte("`")) {
+			return errors.New("the OpenAPI document contains a backquote, and cannot be a raw string literal")
+		}
+
+		var b bytes.Buffer
+
+		fmt.Fprintf(&b, "// Code generated by cmd/openapi. DO NOT EDIT.\n\n")
+		fmt.Fprintf(&b, "package %s\n\n", pkg)
+		fmt.Fprintf(&b, "// SwaggerJSON is the OpenAPI document of the API, served at /swagger.json.\n")
+		fmt.Fprintf(&b, "const SwaggerJSON = `%s`\n", buf)
+
+		buf = b.Bytes()
+	}
+
+	return write(c.String("o"), func(w io.Writer) error {
+		_, err := w.Write(buf)
+		return err
+	})
+}
+
+func client(c *cli.Context) error {
+	doc, err := openapi.Build(openapi.Routes)
+	if err != nil {
+		return errors.Wrap(err, "failed to build the OpenAPI document")
+	}
+
+	switch lang := strings.ToLower(c.String("lang")); lang {
+	case "typescript", "ts":
+		return write(c.String("o"), func(w io.Writer) error {
+			return openapi.GenerateTypeScript(doc, w)
+		})
+	default:
+		return errors.Errorf("clients cannot be generated in %q", lang)
+	}
+}
+
+// write calls fn with the file at path, or the standard output should path be
+// empty. The file is only written to once fn succeeds.
+func write(path string, fn func(w io.Writer) error) error {
+	if path == "" {
+		return fn(os.Stdout)
+	}
+
+	var buf bytes.Buffer
+
+	if err := fn(&buf); err != nil {
+		return err
+	}
+
+	return errors.Wrapf(ioutil.WriteFile(path, buf.Bytes(), 0644), "failed to write %s", path)
+}
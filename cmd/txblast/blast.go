@@ -0,0 +1,142 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// sendFunc sends a transaction of the given kind using the client-th client.
+type sendFunc func(client int, kind op) error
+
+// blaster sends transactions drawn from a mix across a number of clients,
+// recording the outcome of each.
+type blaster struct {
+	clients int
+	mix     mix
+	send    sendFunc
+	rec     *recorder
+
+	// limit, if non-zero, is the total number of transactions to send.
+	limit uint64
+
+	seed int64
+}
+
+// closedLoop sends transactions from concurrency workers, each of which only
+// sends its next transaction once its previous one was accepted or
+// rejected. It returns once ctx is done or the limit is reached.
+func (b *blaster) closedLoop(ctx context.Context, concurrency int) {
+	var (
+		wg      sync.WaitGroup
+		claimed atomic.Uint64
+	)
+
+	wg.Add(concurrency)
+
+	for w := 0; w < concurrency; w++ {
+		go func(w int) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(b.seed + int64(w)))
+
+			for ctx.Err() == nil {
+				if b.limit > 0 && claimed.Inc() > b.limit {
+					return
+				}
+
+				b.sendOne(w%b.clients, b.mix.pick(r))
+			}
+		}(w)
+	}
+
+	wg.Wait()
+}
+
+// openLoop sends transactions at a fixed rate per second regardless of how
+// quickly they are accepted, with at most maxInflight transactions in
+// flight at once. Transactions due while maxInflight transactions are in
+// flight are dropped. It returns once ctx is done or the limit is reached,
+// and all transactions in flight have completed.
+func (b *blaster) openLoop(ctx context.Context, rate float64, maxInflight int) {
+	var wg sync.WaitGroup
+
+	defer wg.Wait()
+
+	inflight := make(chan struct{}, maxInflight)
+
+	r := rand.New(rand.NewSource(b.seed))
+
+	tick := time.Duration(float64(time.Second) / rate)
+	if tick < 10*time.Millisecond {
+		tick = 10 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	start := time.Now()
+
+	var issued uint64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			due := uint64(rate * now.Sub(start).Seconds())
+
+			for ; issued < due; issued++ {
+				if b.limit > 0 && issued >= b.limit {
+					return
+				}
+
+				select {
+				case inflight <- struct{}{}:
+				default:
+					b.rec.drop()
+					continue
+				}
+
+				wg.Add(1)
+
+				go func(client int, kind op) {
+					defer func() {
+						<-inflight
+						wg.Done()
+					}()
+
+					b.sendOne(client, kind)
+				}(int(issued%uint64(b.clients)), b.mix.pick(r))
+			}
+		}
+	}
+}
+
+func (b *blaster) sendOne(client int, kind op) {
+	start := time.Now()
+	err := b.send(client, kind)
+	b.rec.record(kind, time.Since(start), err)
+}
@@ -0,0 +1,482 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Command txblast load tests a wavelet network by driving many API clients
+// at it with a configurable mix of transactions, reporting the throughput,
+// latency and errors observed.
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/noise/skademlia"
+	logger "github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	modeClosed = "closed"
+	modeOpen   = "open"
+)
+
+func main() {
+	http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost = 100
+
+	app := cli.NewApp()
+
+	app.Name = "txblast"
+	app.Author = "Perlin"
+	app.Email = "support@perlin.net"
+	app.Version = sys.Version
+	app.Usage = "a load testing tool that blasts transactions at a wavelet network"
+
+	cli.VersionPrinter = func(c *cli.Context) {
+		fmt.Printf("Version:    %s\n", sys.Version)
+		fmt.Printf("Go Version: %s\n", sys.GoVersion)
+		fmt.Printf("Git Commit: %s\n", sys.GitCommit)
+		fmt.Printf("OS/Arch:    %s\n", sys.OSArch)
+		fmt.Printf("Built:      %s\n", c.App.Compiled.Format(time.ANSIC))
+	}
+
+	app.Before = func(context *cli.Context) error {
+		log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger().Output(logger.NewConsoleWriter(nil))
+
+		return nil
+	}
+
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "hosts",
+			Usage: "comma-separated HTTP API addresses of the nodes to send transactions to",
+			Value: "127.0.0.1:9000",
+		},
+		cli.StringFlag{
+			Name:  "api.secret",
+			Usage: "shared secret to authenticate to the nodes' HTTP API with",
+		},
+		cli.BoolFlag{
+			Name:  "https",
+			Usage: "connect to the nodes' HTTP API over HTTPS",
+		},
+		cli.StringFlag{
+			Name: "wallets",
+			Usage: "comma-separated private keys in hex format, or paths to wallet files, of the accounts to send " +
+				"transactions from",
+			Value: "87a6813c3b4cf534b6ae82db9b1409fa7dbd5c13dba5858970b56084c4a930eb400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405", // nolint:lll
+		},
+		cli.IntFlag{
+			Name: "clients",
+			Usage: "the number of API clients to create, where the i-th client connects to the i-th host using the " +
+				"i-th wallet, wrapping around. defaults to one client per host and wallet pair.",
+		},
+		cli.StringFlag{
+			Name:  "mix",
+			Usage: "weighted mix of transactions to send, out of transfer, call, stake and unstake (e.g. transfer=70,call=30)",
+			Value: string(opTransfer),
+		},
+		cli.StringFlag{
+			Name:  "mode",
+			Usage: "closed: workers send their next transaction once their previous one completes; open: send at a fixed rate",
+			Value: modeClosed,
+		},
+		cli.IntFlag{
+			Name:  "concurrency",
+			Usage: "the number of workers sending transactions in closed-loop mode",
+			Value: 16,
+		},
+		cli.Float64Flag{
+			Name:  "rate",
+			Usage: "the number of transactions to send per second in open-loop mode",
+			Value: 100,
+		},
+		cli.IntFlag{
+			Name:  "max-inflight",
+			Usage: "the number of transactions that may be in flight in open-loop mode, past which they are dropped",
+			Value: 1000,
+		},
+		cli.DurationFlag{
+			Name:  "duration",
+			Usage: "how long to send transactions for",
+			Value: 30 * time.Second,
+		},
+		cli.Uint64Flag{
+			Name:  "count",
+			Usage: "stop after sending this many transactions, if non-zero",
+		},
+		cli.DurationFlag{
+			Name:  "interval",
+			Usage: "how often to log progress",
+			Value: 5 * time.Second,
+		},
+		cli.StringFlag{
+			Name:  "recipient",
+			Usage: "hex-encoded recipient of transfers. defaults to the sender itself.",
+		},
+		cli.Uint64Flag{
+			Name:  "amount",
+			Usage: "the amount of PERLs to transfer, stake or unstake per transaction",
+			Value: 1,
+		},
+		cli.StringFlag{
+			Name:  "contract",
+			Usage: "hex-encoded ID of the smart contract to call",
+		},
+		cli.StringFlag{
+			Name:  "func",
+			Usage: "name of the smart contract function to call",
+		},
+		cli.StringFlag{
+			Name:  "params",
+			Usage: "hex-encoded parameters to call the smart contract function with",
+		},
+		cli.Uint64Flag{
+			Name:  "gas-limit",
+			Usage: "gas limit of smart contract calls",
+			Value: 100000,
+		},
+		cli.Int64Flag{
+			Name:  "seed",
+			Usage: "seed used to draw transactions from the mix. defaults to the current time.",
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "print the final report as JSON",
+		},
+	}
+
+	app.Action = blast
+
+	sort.Sort(cli.FlagsByName(app.Flags))
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Printf("failed to parse configuration/command-line arguments: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func blast(c *cli.Context) error {
+	m, err := parseMix(c.String("mix"))
+	if err != nil {
+		return err
+	}
+
+	mode := c.String("mode")
+	if mode != modeClosed && mode != modeOpen {
+		return errors.Errorf("unknown mode %q: must be either %q or %q", mode, modeClosed, modeOpen)
+	}
+
+	if mode == modeOpen && c.Float64("rate") <= 0 {
+		return errors.New("rate must be positive in open-loop mode")
+	}
+
+	hosts := splitList(c.String("hosts"))
+	if len(hosts) == 0 {
+		return errors.New("at least one host must be specified")
+	}
+
+	var keys []edwards25519.PrivateKey
+
+	for _, wallet := range splitList(c.String("wallets")) {
+		key, err := loadKey(wallet)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return errors.New("at least one wallet must be specified")
+	}
+
+	var recipient *[32]byte
+
+	if s := c.String("recipient"); s != "" {
+		id, err := decodeID(s)
+		if err != nil {
+			return errors.Wrap(err, "invalid recipient")
+		}
+
+		recipient = &id
+	}
+
+	var (
+		contract [32]byte
+		params   []byte
+	)
+
+	if m.has(opCall) {
+		if contract, err = decodeID(c.String("contract")); err != nil {
+			return errors.Wrap(err, "a valid --contract must be specified to send calls")
+		}
+
+		if c.String("func") == "" {
+			return errors.New("a --func must be specified to send calls")
+		}
+
+		if params, err = hex.DecodeString(c.String("params")); err != nil {
+			return errors.Wrap(err, "invalid params")
+		}
+	}
+
+	numClients := c.Int("clients")
+	if numClients <= 0 {
+		numClients = len(hosts) * len(keys)
+	}
+
+	clients := make([]*wctl.Client, 0, numClients)
+
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+
+	for i := 0; i < numClients; i++ {
+		client, err := connect(c, hosts[i%len(hosts)], keys[i%len(keys)])
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect client %d to %s", i, hosts[i%len(hosts)])
+		}
+
+		clients = append(clients, client)
+	}
+
+	amount := c.Uint64("amount")
+	fn := wctl.FunctionCall{Name: c.String("func"), GasLimit: c.Uint64("gas-limit"), Params: [][]byte{params}}
+
+	send := func(i int, kind op) error {
+		client := clients[i]
+
+		var err error
+
+		switch kind {
+		case opTransfer:
+			to := client.PublicKey
+			if recipient != nil {
+				to = *recipient
+			}
+
+			_, err = wctl.NewTransfer(to).Amount(amount).Send(client)
+		case opCall:
+			_, err = wctl.NewTransfer(contract).GasLimit(fn.GasLimit).Invoke(fn.Name, fn.Params...).Send(client)
+		case opStake:
+			_, err = client.PlaceStake(amount)
+		case opUnstake:
+			_, err = client.WithdrawStake(amount)
+		}
+
+		return err
+	}
+
+	seed := c.Int64("seed")
+	if !c.IsSet("seed") {
+		seed = time.Now().UnixNano()
+	}
+
+	b := &blaster{
+		clients: len(clients),
+		mix:     m,
+		send:    send,
+		rec:     newRecorder(),
+		limit:   c.Uint64("count"),
+		seed:    seed,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Duration("duration"))
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+
+	defer signal.Stop(signals)
+
+	go func() {
+		select {
+		case <-signals:
+			log.Info().Msg("Interrupted; finishing up.")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	log.Info().
+		Str("mode", mode).
+		Str("mix", m.String()).
+		Int("clients", len(clients)).
+		Int64("seed", seed).
+		Msg("Blasting transactions.")
+
+	done := make(chan struct{})
+	go logProgress(b.rec, c.Duration("interval"), done)
+
+	switch mode {
+	case modeClosed:
+		b.closedLoop(ctx, c.Int("concurrency"))
+	case modeOpen:
+		b.openLoop(ctx, c.Float64("rate"), c.Int("max-inflight"))
+	}
+
+	close(done)
+
+	rep := b.rec.report(mode)
+
+	if c.Bool("json") {
+		return rep.WriteJSON(os.Stdout)
+	}
+
+	return rep.WriteText(os.Stdout)
+}
+
+// logProgress periodically logs the number of transactions sent, and the
+// rate at which they were sent since the last time progress was logged.
+func logProgress(rec *recorder, interval time.Duration, done <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last uint64
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			sent, failed := rec.counts()
+
+			log.Info().
+				Uint64("sent", sent).
+				Uint64("failed", failed).
+				Float64("tx_per_sec", float64(sent-last)/interval.Seconds()).
+				Msg("Blasting...")
+
+			last = sent
+		}
+	}
+}
+
+func connect(c *cli.Context, host string, key edwards25519.PrivateKey) (*wctl.Client, error) {
+	addr, portStr, err := net.SplitHostPort(host)
+	if err != nil || len(addr) == 0 {
+		return nil, errors.Errorf("host and port must be specified [example: 127.0.0.1:9000], got %q", host)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode port")
+	}
+
+	client, err := wctl.NewClient(wctl.Config{
+		APIHost:    addr,
+		APIPort:    uint16(port),
+		APISecret:  c.String("api.secret"),
+		PrivateKey: key,
+		UseHTTPS:   c.Bool("https"),
+	})
+	if err != nil {
+		// NewClient may return a client alongside an error.
+		if client != nil {
+			client.Close()
+		}
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// loadKey loads a private key either from a wallet file, or from the given
+// hex-encoded private key should no such file exist.
+func loadKey(wallet string) (edwards25519.PrivateKey, error) {
+	var privateKey edwards25519.PrivateKey
+
+	buf, err := ioutil.ReadFile(wallet)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return privateKey, errors.Wrapf(err, "failed to read wallet %q", wallet)
+		}
+
+		buf = []byte(wallet)
+	}
+
+	buf = []byte(strings.TrimSpace(string(buf)))
+
+	if hex.DecodedLen(len(buf)) != edwards25519.SizePrivateKey {
+		return privateKey, errors.Errorf("wallet %q is neither a file nor a hex-encoded private key", wallet)
+	}
+
+	if _, err := hex.Decode(privateKey[:], buf); err != nil {
+		return privateKey, errors.Wrapf(err, "failed to decode private key of wallet %q", wallet)
+	}
+
+	keys, err := skademlia.LoadKeys(privateKey, sys.SKademliaC1, sys.SKademliaC2)
+	if err != nil {
+		return privateKey, errors.Wrapf(err, "the private key of wallet %q is invalid", wallet)
+	}
+
+	return keys.PrivateKey(), nil
+}
+
+func decodeID(s string) ([32]byte, error) {
+	var id [32]byte
+
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return id, err
+	}
+
+	if len(buf) != len(id) {
+		return id, errors.Errorf("must be %d bytes, got %d", len(id), len(buf))
+	}
+
+	copy(id[:], buf)
+
+	return id, nil
+}
+
+func splitList(s string) []string {
+	var list []string
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// op is a kind of transaction that may be blasted at a network.
+type op string
+
+const (
+	opTransfer op = "transfer"
+	opCall     op = "call"
+	opStake    op = "stake"
+	opUnstake  op = "unstake"
+)
+
+var ops = []op{opTransfer, opCall, opStake, opUnstake}
+
+// mix is a weighted set of transaction kinds, from which the kind of each
+// transaction sent is drawn at random.
+type mix struct {
+	ops     []op
+	weights []int
+	total   int
+}
+
+// parseMix parses a mix of the form "transfer=70,call=20,stake=10". A kind
+// given without a weight has a weight of 1.
+func parseMix(s string) (mix, error) {
+	var m mix
+
+	seen := make(map[op]struct{})
+
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		name, weight := field, 1
+
+		if i := strings.IndexByte(field, '='); i >= 0 {
+			w, err := strconv.Atoi(strings.TrimSpace(field[i+1:]))
+			if err != nil || w < 0 {
+				return m, errors.Errorf("invalid weight in %q", field)
+			}
+
+			name, weight = strings.TrimSpace(field[:i]), w
+		}
+
+		kind := op(name)
+
+		if !kind.valid() {
+			return m, errors.Errorf("unknown transaction kind %q", name)
+		}
+
+		if _, exists := seen[kind]; exists {
+			return m, errors.Errorf("transaction kind %q is specified more than once", name)
+		}
+
+		seen[kind] = struct{}{}
+
+		if weight == 0 {
+			continue
+		}
+
+		m.ops = append(m.ops, kind)
+		m.weights = append(m.weights, weight)
+		m.total += weight
+	}
+
+	if m.total == 0 {
+		return m, errors.New("transaction mix must have at least one kind with a positive weight")
+	}
+
+	return m, nil
+}
+
+// pick draws a transaction kind from the mix.
+func (m mix) pick(r *rand.Rand) op {
+	n := r.Intn(m.total)
+
+	for i, w := range m.weights {
+		if n < w {
+			return m.ops[i]
+		}
+
+		n -= w
+	}
+
+	return m.ops[len(m.ops)-1]
+}
+
+// has reports whether kind may be drawn from the mix.
+func (m mix) has(kind op) bool {
+	for _, o := range m.ops {
+		if o == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m mix) String() string {
+	fields := make([]string, 0, len(m.ops))
+
+	for i, o := range m.ops {
+		fields = append(fields, string(o)+"="+strconv.Itoa(m.weights[i]))
+	}
+
+	sort.Strings(fields)
+
+	return strings.Join(fields, ",")
+}
+
+func (o op) valid() bool {
+	for _, kind := range ops {
+		if o == kind {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// maxErrorLength is the length error messages are truncated to, such that
+// errors differing only in their details are grouped together.
+const maxErrorLength = 120
+
+// recorder collects the outcome and latency of every transaction sent.
+//
+// A recorder is safe for concurrent use.
+type recorder struct {
+	mu sync.Mutex
+
+	start   time.Time
+	ops     map[op]*opStats
+	errors  map[string]uint64
+	dropped uint64
+}
+
+type opStats struct {
+	sent      uint64
+	failed    uint64
+	latencies []time.Duration
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		start:  time.Now(),
+		ops:    make(map[op]*opStats),
+		errors: make(map[string]uint64),
+	}
+}
+
+// record accounts for a transaction of the given kind having been sent,
+// having taken latency to be accepted or rejected with err by the node.
+func (r *recorder) record(kind op, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, exists := r.ops[kind]
+	if !exists {
+		stats = new(opStats)
+		r.ops[kind] = stats
+	}
+
+	stats.sent++
+	stats.latencies = append(stats.latencies, latency)
+
+	if err != nil {
+		stats.failed++
+
+		msg := err.Error()
+		if len(msg) > maxErrorLength {
+			msg = msg[:maxErrorLength] + "..."
+		}
+
+		r.errors[msg]++
+	}
+}
+
+// drop accounts for a transaction that was due to be sent in open-loop
+// mode, but was not as too many transactions were still in flight.
+func (r *recorder) drop() {
+	r.mu.Lock()
+	r.dropped++
+	r.mu.Unlock()
+}
+
+// counts returns the number of transactions sent and failed so far.
+func (r *recorder) counts() (sent, failed uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stats := range r.ops {
+		sent += stats.sent
+		failed += stats.failed
+	}
+
+	return sent, failed
+}
+
+// report summarizes the transactions sent in the given mode so far.
+func (r *recorder) report(mode string) report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rep := report{
+		Mode:     mode,
+		Duration: time.Since(r.start).Seconds(),
+		Dropped:  r.dropped,
+	}
+
+	for kind, stats := range r.ops {
+		rep.Sent += stats.sent
+		rep.Failed += stats.failed
+
+		rep.Ops = append(rep.Ops, summarize(kind, stats))
+	}
+
+	rep.Succeeded = rep.Sent - rep.Failed
+
+	if rep.Duration > 0 {
+		rep.Throughput = float64(rep.Succeeded) / rep.Duration
+	}
+
+	sort.Slice(rep.Ops, func(i, j int) bool {
+		return rep.Ops[i].Kind < rep.Ops[j].Kind
+	})
+
+	for msg, count := range r.errors {
+		rep.Errors = append(rep.Errors, errorCount{Error: msg, Count: count})
+	}
+
+	sort.Slice(rep.Errors, func(i, j int) bool {
+		if rep.Errors[i].Count != rep.Errors[j].Count {
+			return rep.Errors[i].Count > rep.Errors[j].Count
+		}
+
+		return rep.Errors[i].Error < rep.Errors[j].Error
+	})
+
+	return rep
+}
+
+func summarize(kind op, stats *opStats) opReport {
+	latencies := append([]time.Duration(nil), stats.latencies...)
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	var total time.Duration
+
+	for _, latency := range latencies {
+		total += latency
+	}
+
+	rep := opReport{
+		Kind:   string(kind),
+		Sent:   stats.sent,
+		Failed: stats.failed,
+		P50:    millis(percentile(latencies, 50)),
+		P90:    millis(percentile(latencies, 90)),
+		P99:    millis(percentile(latencies, 99)),
+	}
+
+	if len(latencies) > 0 {
+		rep.Mean = millis(total / time.Duration(len(latencies)))
+		rep.Max = millis(latencies[len(latencies)-1])
+	}
+
+	return rep
+}
+
+// percentile returns the p-th percentile of sorted using the nearest-rank
+// method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// report is the summary of a run. Latencies are in milliseconds, and
+// measure the time taken for a node to accept or reject a transaction.
+type report struct {
+	Mode       string       `json:"mode"`
+	Duration   float64      `json:"duration_sec"`
+	Sent       uint64       `json:"sent"`
+	Succeeded  uint64       `json:"succeeded"`
+	Failed     uint64       `json:"failed"`
+	Dropped    uint64       `json:"dropped"`
+	Throughput float64      `json:"tx_per_sec"`
+	Ops        []opReport   `json:"ops"`
+	Errors     []errorCount `json:"errors,omitempty"`
+}
+
+type opReport struct {
+	Kind   string  `json:"kind"`
+	Sent   uint64  `json:"sent"`
+	Failed uint64  `json:"failed"`
+	Mean   float64 `json:"mean_ms"`
+	P50    float64 `json:"p50_ms"`
+	P90    float64 `json:"p90_ms"`
+	P99    float64 `json:"p99_ms"`
+	Max    float64 `json:"max_ms"`
+}
+
+type errorCount struct {
+	Error string `json:"error"`
+	Count uint64 `json:"count"`
+}
+
+// WriteText writes the report as a human-readable table.
+func (r report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "mode: %s\n", r.Mode)
+	fmt.Fprintf(w, "duration: %.2fs\n", r.Duration)
+	fmt.Fprintf(w, "sent: %d, succeeded: %d, failed: %d, dropped: %d\n", r.Sent, r.Succeeded, r.Failed, r.Dropped)
+	fmt.Fprintf(w, "throughput: %.2f tx/s\n\n", r.Throughput)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(tw, "kind\tsent\tfailed\tmean\tp50\tp90\tp99\tmax\t")
+
+	for _, o := range r.Ops {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2fms\t%.2fms\t%.2fms\t%.2fms\t%.2fms\t\n",
+			o.Kind, o.Sent, o.Failed, o.Mean, o.P50, o.P90, o.P99, o.Max,
+		)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintln(w, "\nerrors:")
+
+		for _, e := range r.Errors {
+			fmt.Fprintf(w, "  %6d  %s\n", e.Count, e.Error)
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	m, err := parseMix("transfer=70, call=20,stake=10,unstake=0")
+	require.NoError(t, err)
+
+	assert.Equal(t, []op{opTransfer, opCall, opStake}, m.ops)
+	assert.Equal(t, 100, m.total)
+	assert.False(t, m.has(opUnstake))
+	assert.Equal(t, "call=20,stake=10,transfer=70", m.String())
+
+	m, err = parseMix("stake")
+	require.NoError(t, err)
+	assert.Equal(t, []op{opStake}, m.ops)
+
+	for _, s := range []string{"", "transfer=0", "mint=1", "transfer=x", "transfer=-1", "stake,stake=2"} {
+		_, err := parseMix(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMixPick(t *testing.T) {
+	m, err := parseMix("transfer=3,call=1")
+	require.NoError(t, err)
+
+	r := rand.New(rand.NewSource(42))
+	counts := make(map[op]int)
+
+	for i := 0; i < 10000; i++ {
+		counts[m.pick(r)]++
+	}
+
+	assert.Len(t, counts, 2)
+	assert.InDelta(t, 7500, counts[opTransfer], 300)
+	assert.InDelta(t, 2500, counts[opCall], 300)
+}
+
+func TestRecorderReport(t *testing.T) {
+	rec := newRecorder()
+
+	for i := 1; i <= 100; i++ {
+		rec.record(opTransfer, time.Duration(i)*time.Millisecond, nil)
+	}
+
+	rec.record(opStake, time.Millisecond, errors.New("insufficient balance"))
+	rec.record(opStake, time.Millisecond, errors.New("insufficient balance"))
+	rec.record(opStake, time.Millisecond, errors.New(strings.Repeat("x", 2*maxErrorLength)))
+	rec.drop()
+
+	rep := rec.report(modeClosed)
+
+	assert.EqualValues(t, 103, rep.Sent)
+	assert.EqualValues(t, 100, rep.Succeeded)
+	assert.EqualValues(t, 3, rep.Failed)
+	assert.EqualValues(t, 1, rep.Dropped)
+
+	require.Len(t, rep.Ops, 2)
+	assert.Equal(t, "stake", rep.Ops[0].Kind)
+	assert.Equal(t, "transfer", rep.Ops[1].Kind)
+
+	transfers := rep.Ops[1]
+	assert.Equal(t, 50.5, transfers.Mean)
+	assert.Equal(t, 50.0, transfers.P50)
+	assert.Equal(t, 90.0, transfers.P90)
+	assert.Equal(t, 99.0, transfers.P99)
+	assert.Equal(t, 100.0, transfers.Max)
+
+	require.Len(t, rep.Errors, 2)
+	assert.Equal(t, errorCount{Error: "insufficient balance", Count: 2}, rep.Errors[0])
+	assert.Len(t, rep.Errors[1].Error, maxErrorLength+len("..."))
+
+	var text bytes.Buffer
+	require.NoError(t, rep.WriteText(&text))
+	assert.Contains(t, text.String(), "sent: 103, succeeded: 100, failed: 3, dropped: 1")
+	assert.Contains(t, text.String(), "insufficient balance")
+
+	var buf bytes.Buffer
+	require.NoError(t, rep.WriteJSON(&buf))
+
+	var decoded report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, rep, decoded)
+}
+
+func TestClosedLoop(t *testing.T) {
+	m, err := parseMix("transfer=1,stake=1")
+	require.NoError(t, err)
+
+	var (
+		mu      sync.Mutex
+		clients = make(map[int]int)
+		sent    int
+	)
+
+	// Hold the first send of every worker until all workers have sent, such
+	// that each of them is known to have sent a transaction.
+	var barrier sync.WaitGroup
+	barrier.Add(4)
+
+	b := &blaster{
+		clients: 3,
+		mix:     m,
+		rec:     newRecorder(),
+		limit:   50,
+		send: func(client int, kind op) error {
+			mu.Lock()
+			clients[client]++
+			sent++
+			first := sent <= 4
+			mu.Unlock()
+
+			if first {
+				barrier.Done()
+				barrier.Wait()
+			}
+
+			if kind == opStake {
+				return errors.New("stake failed")
+			}
+
+			return nil
+		},
+	}
+
+	b.closedLoop(context.Background(), 4)
+
+	rep := b.rec.report(modeClosed)
+	assert.EqualValues(t, 50, rep.Sent)
+	assert.EqualValues(t, 0, rep.Dropped)
+
+	// Worker w sends using client w % 3.
+	assert.Len(t, clients, 3)
+
+	for _, o := range rep.Ops {
+		if o.Kind == string(opStake) {
+			assert.Equal(t, o.Sent, o.Failed)
+		} else {
+			assert.Zero(t, o.Failed)
+		}
+	}
+}
+
+func TestOpenLoopDropsWhenSaturated(t *testing.T) {
+	m, err := parseMix("transfer")
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+
+	b := &blaster{
+		clients: 2,
+		mix:     m,
+		rec:     newRecorder(),
+		limit:   20,
+		send: func(client int, kind op) error {
+			<-release
+			return nil
+		},
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		b.openLoop(context.Background(), 1000, 5)
+		close(done)
+	}()
+
+	// Wait for all 20 transactions to be issued, 15 of which are dropped as
+	// the first 5 are blocked.
+	require.Eventually(t, func() bool {
+		return b.rec.report(modeOpen).Dropped == 15
+	}, 5*time.Second, 10*time.Millisecond)
+
+	close(release)
+	<-done
+
+	rep := b.rec.report(modeOpen)
+	assert.EqualValues(t, 5, rep.Sent)
+	assert.EqualValues(t, 5, rep.Succeeded)
+	assert.EqualValues(t, 15, rep.Dropped)
+}
+
+func TestOpenLoopStopsOnCancel(t *testing.T) {
+	m, err := parseMix("transfer")
+	require.NoError(t, err)
+
+	b := &blaster{
+		clients: 1,
+		mix:     m,
+		rec:     newRecorder(),
+		send:    func(client int, kind op) error { return nil },
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	b.openLoop(ctx, 100, 10)
+
+	rep := b.rec.report(modeOpen)
+	assert.True(t, rep.Sent > 0 && rep.Sent <= 25, "sent %d", rep.Sent)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type alertKind string
+
+const (
+	// alertDivergence is raised when nodes at the same block disagree on the
+	// block, or on the state of a watched account or contract.
+	alertDivergence alertKind = "divergence"
+
+	// alertTransfer is raised when the balance of a watched account or
+	// contract drops by more than the allowed outflow.
+	alertTransfer alertKind = "transfer"
+
+	// alertHeartbeat is raised when a node has been unreachable, or has not
+	// finalized a block, for longer than the heartbeat interval.
+	alertHeartbeat alertKind = "heartbeat"
+
+	// alertRecovered is raised when a node which missed its heartbeat resumes
+	// finalizing blocks.
+	alertRecovered alertKind = "recovered"
+)
+
+type alert struct {
+	Kind    alertKind `json:"kind"`
+	Host    string    `json:"host"`
+	ID      string    `json:"id,omitempty"`
+	Block   uint64    `json:"block"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// notifier raises alerts by logging them, and posting them as JSON to a set of
+// webhooks, retrying failed posts according to a backoff policy.
+type notifier struct {
+	webhooks []string
+	client   http.Client
+	backoff  wctl.Backoff
+}
+
+func newNotifier(webhooks []string, timeout time.Duration, backoff wctl.Backoff) *notifier {
+	return &notifier{webhooks: webhooks, client: http.Client{Timeout: timeout}, backoff: backoff}
+}
+
+func (n *notifier) notify(a alert) {
+	event := log.Warn()
+	if a.Kind == alertRecovered {
+		event = log.Info()
+	}
+
+	event.
+		Str("kind", string(a.Kind)).
+		Str("host", a.Host).
+		Str("id", a.ID).
+		Uint64("block", a.Block).
+		Msg(a.Message)
+
+	if len(n.webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(a)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to encode alert.")
+		return
+	}
+
+	for _, url := range n.webhooks {
+		url := url
+
+		if err := n.backoff.Retry(func() error { return n.post(url, body) }); err != nil {
+			log.Error().Err(err).Str("url", url).Msg("Failed to post alert to webhook.")
+		}
+	}
+}
+
+func (n *notifier) post(url string, body []byte) error {
+	res, err := n.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	_ = res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		err := errors.Errorf("webhook responded with status %d", res.StatusCode)
+
+		// Client errors will not go away by posting the alert again.
+		if res.StatusCode/100 == 4 {
+			return wctl.Permanent(err)
+		}
+
+		return err
+	}
+
+	return nil
+}
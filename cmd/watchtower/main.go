@@ -0,0 +1,268 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Command watchtower monitors a set of accounts and smart contracts across one
+// or more wavelet nodes. It cross-checks the state reported by the nodes, and
+// raises alerts on divergence between them, on unexpected outflows of PERLs
+// from the watched accounts, and on nodes missing their heartbeat.
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func main() {
+	app := cli.NewApp()
+
+	app.Name = "watchtower"
+	app.Author = "Perlin"
+	app.Email = "support@perlin.net"
+	app.Version = sys.Version
+	app.Usage = "a monitoring companion that watches accounts and contracts across wavelet nodes"
+
+	cli.VersionPrinter = func(c *cli.Context) {
+		fmt.Printf("Version:    %s\n", sys.Version)
+		fmt.Printf("Go Version: %s\n", sys.GoVersion)
+		fmt.Printf("Git Commit: %s\n", sys.GitCommit)
+		fmt.Printf("OS/Arch:    %s\n", sys.OSArch)
+		fmt.Printf("Built:      %s\n", c.App.Compiled.Format(time.ANSIC))
+	}
+
+	app.Before = func(context *cli.Context) error {
+		log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger().Output(logger.NewConsoleWriter(nil))
+
+		return nil
+	}
+
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "hosts",
+			Usage: "comma-separated HTTP API addresses of the nodes to watch",
+			Value: "127.0.0.1:9000",
+		},
+		cli.StringFlag{
+			Name:  "api.secret",
+			Usage: "shared secret to authenticate to the nodes' HTTP API with",
+		},
+		cli.BoolFlag{
+			Name:  "https",
+			Usage: "connect to the nodes' HTTP API over HTTPS",
+		},
+		cli.StringFlag{
+			Name:  "accounts",
+			Usage: "comma-separated hex-encoded IDs of the accounts to watch",
+		},
+		cli.StringFlag{
+			Name:  "contracts",
+			Usage: "comma-separated hex-encoded IDs of the smart contracts to watch",
+		},
+		cli.DurationFlag{
+			Name:  "interval",
+			Usage: "how often to query the nodes",
+			Value: 5 * time.Second,
+		},
+		cli.DurationFlag{
+			Name:  "heartbeat",
+			Usage: "how long a node may be unreachable or not finalize a block before an alert is raised",
+			Value: time.Minute,
+		},
+		cli.Uint64Flag{
+			Name: "max-outflow",
+			Usage: "the largest drop in the balance of a watched account or contract between two queries which does " +
+				"not raise an alert",
+		},
+		cli.StringFlag{
+			Name:  "webhooks",
+			Usage: "comma-separated URLs to post alerts to as JSON",
+		},
+		cli.DurationFlag{
+			Name:  "webhook-timeout",
+			Usage: "how long to wait for a webhook to accept an alert",
+			Value: 5 * time.Second,
+		},
+	}
+
+	app.Action = watch
+
+	sort.Sort(cli.FlagsByName(app.Flags))
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Printf("failed to parse configuration/command-line arguments: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func watch(c *cli.Context) error {
+	hosts := splitList(c.String("hosts"))
+	if len(hosts) == 0 {
+		return errors.New("at least one host must be specified")
+	}
+
+	accounts, err := decodeIDs(c.String("accounts"))
+	if err != nil {
+		return errors.Wrap(err, "invalid accounts")
+	}
+
+	contracts, err := decodeIDs(c.String("contracts"))
+	if err != nil {
+		return errors.Wrap(err, "invalid contracts")
+	}
+
+	if c.Duration("interval") <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	nodes := make([]*node, 0, len(hosts))
+
+	defer func() {
+		for _, n := range nodes {
+			n.close()
+		}
+	}()
+
+	for _, host := range hosts {
+		n, err := newNode(host, c.String("api.secret"), c.Bool("https"))
+		if err != nil {
+			return err
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	w := newWatchtower(accounts, contracts, c.Duration("heartbeat"), c.Uint64("max-outflow"))
+
+	// Give up on posting an alert before the next round of queries is due.
+	backoff := wctl.DefaultBackoff
+	backoff.MaxElapsed = c.Duration("interval")
+
+	notifier := newNotifier(splitList(c.String("webhooks")), c.Duration("webhook-timeout"), backoff)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+
+	defer signal.Stop(signals)
+
+	log.Info().
+		Strs("hosts", hosts).
+		Int("accounts", len(accounts)).
+		Int("contracts", len(contracts)).
+		Msg("Watching.")
+
+	ticker := time.NewTicker(c.Duration("interval"))
+	defer ticker.Stop()
+
+	for {
+		observations, errs := observeAll(nodes, w.ids)
+
+		for _, a := range w.step(time.Now(), observations, errs) {
+			notifier.notify(a)
+		}
+
+		select {
+		case <-signals:
+			log.Info().Msg("Interrupted; stopping.")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// observeAll observes all nodes concurrently.
+func observeAll(nodes []*node, ids [][32]byte) (map[string]observation, map[string]error) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		obs  = make(map[string]observation, len(nodes))
+		errs = make(map[string]error)
+	)
+
+	for _, n := range nodes {
+		wg.Add(1)
+
+		go func(n *node) {
+			defer wg.Done()
+
+			o, err := n.observe(ids)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs[n.host] = err
+				return
+			}
+
+			obs[n.host] = o
+		}(n)
+	}
+
+	wg.Wait()
+
+	return obs, errs
+}
+
+func decodeIDs(s string) ([][32]byte, error) {
+	var ids [][32]byte
+
+	for _, item := range splitList(s) {
+		var id [32]byte
+
+		buf, err := hex.DecodeString(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%q is not hex-encoded", item)
+		}
+
+		if len(buf) != len(id) {
+			return nil, errors.Errorf("%q must be %d bytes, got %d", item, len(id), len(buf))
+		}
+
+		copy(id[:], buf)
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func splitList(s string) []string {
+	var list []string
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+)
+
+// maxObserveAttempts is how many times a node is queried for a consistent
+// observation, should it finalize blocks while being queried.
+const maxObserveAttempts = 3
+
+// node observes watched accounts and contracts through the HTTP API of a
+// wavelet node, connecting to it lazily so that nodes which are down when the
+// watchtower starts are reported rather than fatal.
+type node struct {
+	host   string
+	config wctl.Config
+	client *wctl.Client
+}
+
+func newNode(host, secret string, https bool) (*node, error) {
+	addr, portStr, err := net.SplitHostPort(host)
+	if err != nil || len(addr) == 0 {
+		return nil, errors.Errorf("host and port must be specified [example: 127.0.0.1:9000], got %q", host)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode port")
+	}
+
+	return &node{
+		host: host,
+		config: wctl.Config{
+			APIHost:   addr,
+			APIPort:   uint16(port),
+			APISecret: secret,
+			UseHTTPS:  https,
+		},
+	}, nil
+}
+
+// observe queries the node for the state of the accounts and contracts with
+// the given IDs. As the node may finalize a block in between queries, the
+// ledger status is queried before and after, retrying should they differ.
+func (n *node) observe(ids [][32]byte) (observation, error) {
+	if n.client == nil {
+		client, err := wctl.NewClient(n.config)
+		if err != nil {
+			// NewClient may return a client alongside an error.
+			if client != nil {
+				client.Close()
+			}
+
+			return observation{}, errors.Wrap(err, "failed to connect")
+		}
+
+		n.client = client
+	}
+
+	for attempt := 0; attempt < maxObserveAttempts; attempt++ {
+		obs, consistent, err := n.observeOnce(ids)
+		if err != nil {
+			n.close()
+			return observation{}, err
+		}
+
+		if consistent {
+			return obs, nil
+		}
+	}
+
+	return observation{}, errors.Errorf("node finalized blocks while being queried %d times in a row", maxObserveAttempts)
+}
+
+func (n *node) observeOnce(ids [][32]byte) (observation, bool, error) {
+	before, err := n.client.LedgerStatus()
+	if err != nil {
+		return observation{}, false, errors.Wrap(err, "failed to query ledger status")
+	}
+
+	obs := observation{
+		block:    before.Block.Index,
+		blockID:  before.Block.ID,
+		accounts: make(map[[32]byte]wctl.Account, len(ids)),
+	}
+
+	for _, id := range ids {
+		account, err := n.client.GetAccount(id)
+		if err != nil {
+			return observation{}, false, errors.Wrapf(err, "failed to query account %x", id)
+		}
+
+		obs.accounts[id] = *account
+	}
+
+	after, err := n.client.LedgerStatus()
+	if err != nil {
+		return observation{}, false, errors.Wrap(err, "failed to query ledger status")
+	}
+
+	return obs, after.Block.ID == before.Block.ID, nil
+}
+
+func (n *node) close() {
+	if n.client != nil {
+		n.client.Close()
+		n.client = nil
+	}
+}
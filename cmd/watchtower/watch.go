@@ -0,0 +1,293 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/perlin-network/wavelet/wctl"
+)
+
+// observation is the state of the watched accounts and contracts reported by
+// a node as of its latest block.
+type observation struct {
+	block   uint64
+	blockID [32]byte
+
+	accounts map[[32]byte]wctl.Account
+}
+
+// nodeState is what the watchtower remembers of a node between rounds.
+type nodeState struct {
+	observed     bool
+	block        uint64
+	lastProgress time.Time
+	missed       bool
+}
+
+// balanceState is the latest balance observed of a watched account or
+// contract, and the block it was observed at.
+type balanceState struct {
+	block   uint64
+	balance uint64
+}
+
+// watchtower cross-checks the observations of a set of nodes every round,
+// raising alerts on divergence between nodes, outflows from watched accounts,
+// and nodes which stopped making progress.
+type watchtower struct {
+	ids       [][32]byte
+	contracts map[[32]byte]struct{}
+
+	// heartbeat is how long a node may go without finalizing a block before
+	// it is considered to have missed its heartbeat.
+	heartbeat time.Duration
+
+	// maxOutflow is the largest decrease in the balance of a watched account
+	// between two observations which does not raise an alert.
+	maxOutflow uint64
+
+	nodes    map[string]*nodeState
+	balances map[[32]byte]balanceState
+
+	// Block index at which a divergence was last reported, per account, the
+	// zero ID standing for divergence of the blocks themselves.
+	diverged map[[32]byte]uint64
+}
+
+func newWatchtower(accounts, contracts [][32]byte, heartbeat time.Duration, maxOutflow uint64) *watchtower {
+	w := &watchtower{
+		contracts:  make(map[[32]byte]struct{}, len(contracts)),
+		heartbeat:  heartbeat,
+		maxOutflow: maxOutflow,
+		nodes:      make(map[string]*nodeState),
+		balances:   make(map[[32]byte]balanceState),
+		diverged:   make(map[[32]byte]uint64),
+	}
+
+	w.ids = append(w.ids, accounts...)
+	w.ids = append(w.ids, contracts...)
+
+	for _, id := range contracts {
+		w.contracts[id] = struct{}{}
+	}
+
+	return w
+}
+
+// step takes the observations made of each node in a round at time now, or
+// the errors encountered observing them, and returns the alerts to raise.
+func (w *watchtower) step(now time.Time, observations map[string]observation, errs map[string]error) []alert {
+	var alerts []alert
+
+	alerts = append(alerts, w.checkHeartbeats(now, observations, errs)...)
+	alerts = append(alerts, w.checkDivergence(now, observations)...)
+	alerts = append(alerts, w.checkTransfers(now, observations)...)
+
+	return alerts
+}
+
+func (w *watchtower) checkHeartbeats(now time.Time, observations map[string]observation, errs map[string]error) []alert {
+	var alerts []alert
+
+	for _, host := range sortedHosts(observations, errs) {
+		state, ok := w.nodes[host]
+		if !ok {
+			state = &nodeState{lastProgress: now}
+			w.nodes[host] = state
+		}
+
+		if obs, ok := observations[host]; ok && (!state.observed || obs.block > state.block) {
+			if state.missed {
+				alerts = append(alerts, alert{
+					Kind: alertRecovered, Host: host, Block: obs.block, Time: now,
+					Message: fmt.Sprintf("node has resumed finalizing blocks, now at block %d", obs.block),
+				})
+			}
+
+			state.observed = true
+			state.block = obs.block
+			state.lastProgress = now
+			state.missed = false
+
+			continue
+		}
+
+		if state.missed || now.Sub(state.lastProgress) < w.heartbeat {
+			continue
+		}
+
+		state.missed = true
+
+		message := fmt.Sprintf("node has not finalized a block past block %d for %s", state.block, now.Sub(state.lastProgress))
+		if err := errs[host]; err != nil {
+			message = fmt.Sprintf("node has been unreachable for %s: %v", now.Sub(state.lastProgress), err)
+		}
+
+		alerts = append(alerts, alert{Kind: alertHeartbeat, Host: host, Block: state.block, Time: now, Message: message})
+	}
+
+	return alerts
+}
+
+func (w *watchtower) checkDivergence(now time.Time, observations map[string]observation) []alert {
+	var alerts []alert
+
+	// Only nodes at the same block are expected to agree with each other.
+	byBlock := make(map[uint64][]string)
+
+	for _, host := range sortedHosts(observations, nil) {
+		byBlock[observations[host].block] = append(byBlock[observations[host].block], host)
+	}
+
+	for block, hosts := range byBlock {
+		if len(hosts) < 2 {
+			continue
+		}
+
+		first := observations[hosts[0]]
+
+		for _, host := range hosts[1:] {
+			obs := observations[host]
+
+			if obs.blockID != first.blockID && w.diverged[[32]byte{}] < block {
+				w.diverged[[32]byte{}] = block
+
+				alerts = append(alerts, alert{
+					Kind: alertDivergence, Host: host, Block: block, Time: now,
+					Message: fmt.Sprintf(
+						"node reports block %d to have ID %x, whereas node %s reports it to have ID %x",
+						block, obs.blockID, hosts[0], first.blockID,
+					),
+				})
+			}
+
+			for _, id := range w.ids {
+				a, b := first.accounts[id], obs.accounts[id]
+				if sameState(a, b) || w.diverged[id] >= block {
+					continue
+				}
+
+				w.diverged[id] = block
+
+				alerts = append(alerts, alert{
+					Kind: alertDivergence, Host: host, ID: hex.EncodeToString(id[:]), Block: block, Time: now,
+					Message: fmt.Sprintf(
+						"node reports %s as of block %d, whereas node %s reports %s",
+						describe(b), block, hosts[0], describe(a),
+					),
+				})
+			}
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Block < alerts[j].Block
+	})
+
+	return alerts
+}
+
+func (w *watchtower) checkTransfers(now time.Time, observations map[string]observation) []alert {
+	var alerts []alert
+
+	for _, id := range w.ids {
+		// Follow the balance as reported by whichever node is furthest ahead.
+		var (
+			latest *observation
+			host   string
+		)
+
+		for _, h := range sortedHosts(observations, nil) {
+			obs := observations[h]
+			if _, ok := obs.accounts[id]; ok && (latest == nil || obs.block > latest.block) {
+				latest, host = &obs, h
+			}
+		}
+
+		if latest == nil {
+			continue
+		}
+
+		balance := latest.accounts[id].Balance
+
+		prev, seen := w.balances[id]
+		if seen && latest.block <= prev.block {
+			continue
+		}
+
+		w.balances[id] = balanceState{block: latest.block, balance: balance}
+
+		if !seen || balance >= prev.balance || prev.balance-balance <= w.maxOutflow {
+			continue
+		}
+
+		kind := "account"
+		if _, ok := w.contracts[id]; ok {
+			kind = "contract"
+		}
+
+		alerts = append(alerts, alert{
+			Kind: alertTransfer, Host: host, ID: hex.EncodeToString(id[:]), Block: latest.block, Time: now,
+			Message: fmt.Sprintf(
+				"balance of %s dropped by %d PERLs from %d to %d between blocks %d and %d",
+				kind, prev.balance-balance, prev.balance, balance, prev.block, latest.block,
+			),
+		})
+	}
+
+	return alerts
+}
+
+func sameState(a, b wctl.Account) bool {
+	return a.Balance == b.Balance && a.GasBalance == b.GasBalance && a.Stake == b.Stake && a.Reward == b.Reward &&
+		a.IsContract == b.IsContract && a.NumPages == b.NumPages
+}
+
+func describe(a wctl.Account) string {
+	s := fmt.Sprintf("balance %d, stake %d, reward %d", a.Balance, a.Stake, a.Reward)
+
+	if a.IsContract {
+		s += fmt.Sprintf(", gas balance %d, %d memory pages", a.GasBalance, a.NumPages)
+	}
+
+	return s
+}
+
+func sortedHosts(observations map[string]observation, errs map[string]error) []string {
+	hosts := make([]string, 0, len(observations)+len(errs))
+
+	for host := range observations {
+		hosts = append(hosts, host)
+	}
+
+	for host := range errs {
+		if _, ok := observations[host]; !ok {
+			hosts = append(hosts, host)
+		}
+	}
+
+	sort.Strings(hosts)
+
+	return hosts
+}
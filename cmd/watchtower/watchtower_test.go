@@ -0,0 +1,123 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchtower(t *testing.T) {
+	account := [32]byte{1}
+	contract := [32]byte{2}
+
+	w := newWatchtower([][32]byte{account}, [][32]byte{contract}, time.Minute, 10)
+
+	at := func(block uint64, balance uint64) observation {
+		return observation{
+			block:   block,
+			blockID: [32]byte{byte(block)},
+			accounts: map[[32]byte]wctl.Account{
+				account:  {Balance: balance},
+				contract: {Balance: 100, IsContract: true},
+			},
+		}
+	}
+
+	kinds := func(alerts []alert) []alertKind {
+		var kinds []alertKind
+
+		for _, a := range alerts {
+			kinds = append(kinds, a.Kind)
+		}
+
+		return kinds
+	}
+
+	now := time.Now()
+
+	// Nodes which agree raise no alerts.
+	assert.Empty(t, w.step(now, map[string]observation{"a": at(1, 1000), "b": at(1, 1000)}, nil))
+
+	// Outflows within the allowance raise no alerts.
+	now = now.Add(time.Second)
+	assert.Empty(t, w.step(now, map[string]observation{"a": at(2, 990), "b": at(2, 990)}, nil))
+
+	// Larger outflows are reported once, regardless of the number of nodes.
+	now = now.Add(time.Second)
+	alerts := w.step(now, map[string]observation{"a": at(3, 900), "b": at(3, 900)}, nil)
+	if assert.Equal(t, []alertKind{alertTransfer}, kinds(alerts)) {
+		assert.Equal(t, "0100000000000000000000000000000000000000000000000000000000000000", alerts[0].ID)
+		assert.EqualValues(t, 3, alerts[0].Block)
+	}
+
+	// Nodes at the same block disagreeing on state diverge, which is reported once.
+	now = now.Add(time.Second)
+	diverged := at(4, 900)
+	diverged.accounts[contract] = wctl.Account{Balance: 50, IsContract: true}
+
+	alerts = w.step(now, map[string]observation{"a": at(4, 900), "b": diverged}, nil)
+	if assert.Equal(t, []alertKind{alertDivergence}, kinds(alerts)) {
+		assert.Equal(t, "b", alerts[0].Host)
+	}
+
+	assert.Empty(t, w.step(now, map[string]observation{"a": at(4, 900), "b": diverged}, nil))
+
+	// So do nodes disagreeing on a block.
+	now = now.Add(time.Second)
+	forked := at(5, 900)
+	forked.blockID = [32]byte{0xff}
+
+	assert.Equal(t, []alertKind{alertDivergence}, kinds(w.step(now, map[string]observation{"a": at(5, 900), "b": forked}, nil)))
+
+	// Nodes at different blocks are not compared.
+	now = now.Add(time.Second)
+	assert.Empty(t, w.step(now, map[string]observation{"a": at(6, 900), "b": diverged}, nil))
+
+	// Nodes which are unreachable or stuck miss their heartbeat once the
+	// heartbeat interval has passed, and are reported once.
+	now = now.Add(30 * time.Second)
+	assert.Empty(t, w.step(now, map[string]observation{"a": at(7, 900)}, map[string]error{"b": errors.New("refused")}))
+
+	now = now.Add(31 * time.Second)
+	alerts = w.step(now, map[string]observation{"a": at(8, 900)}, map[string]error{"b": errors.New("refused")})
+	if assert.Equal(t, []alertKind{alertHeartbeat}, kinds(alerts)) {
+		assert.Equal(t, "b", alerts[0].Host)
+	}
+
+	now = now.Add(time.Minute)
+	alerts = w.step(now, map[string]observation{"a": at(8, 900)}, map[string]error{"b": errors.New("refused")})
+	if assert.Equal(t, []alertKind{alertHeartbeat}, kinds(alerts)) {
+		assert.Equal(t, "a", alerts[0].Host)
+	}
+
+	// Nodes making progress again recover.
+	now = now.Add(time.Second)
+	assert.Equal(t,
+		[]alertKind{alertRecovered, alertRecovered},
+		kinds(w.step(now, map[string]observation{"a": at(9, 900), "b": at(9, 900)}, nil)),
+	)
+}
@@ -25,6 +25,8 @@ import (
 	"github.com/perlin-network/wavelet/sys"
 	"io/ioutil"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/perlin-network/wavelet"
 	"gopkg.in/urfave/cli.v1"
@@ -137,48 +139,15 @@ func (cli *CLI) call(ctx *cli.Context) {
 		GasLimit: gasLimit,
 	}
 
-	for i := 4; i < len(cmd); i++ {
-		arg := cmd[i]
-
-		switch arg[0] {
-		case 'S':
-			fn.AddParams(wctl.EncodeString(arg[1:]))
-		case 'B':
-			fn.AddParams(wctl.EncodeBytes([]byte(arg[1:])))
-		case '1', '2', '4', '8':
-			var val uint64
-			if _, err := fmt.Sscanf(arg[1:], "%d", &val); err != nil {
-				cli.logger.Error().Err(err).
-					Msgf("Got an error parsing integer: %+v", arg[1:])
-				return
-			}
-
-			switch arg[0] {
-			case '1':
-				fn.AddParams(wctl.EncodeByte(byte(val)))
-			case '2':
-				fn.AddParams(wctl.EncodeUint16(uint16(val)))
-			case '4':
-				fn.AddParams(wctl.EncodeUint32(uint32(val)))
-			case '8':
-				fn.AddParams(wctl.EncodeUint64(val))
-			}
-		case 'H':
-			buf, err := wctl.DecodeHex(arg[1:])
-			if err != nil {
-				cli.logger.Error().Err(err).
-					Msgf("Cannot decode hex: %s", arg[1:])
-				return
-			}
-
-			fn.AddParams(buf)
-		default:
-			cli.logger.Error().
-				Str("prefix", string(arg[0])).
-				Msgf("Invalid argument prefix specified")
-
+	for _, arg := range cmd[4:] {
+		param, err := wctl.ParseParam(arg)
+		if err != nil {
+			cli.logger.Error().Err(err).
+				Msgf("Cannot parse function parameter: %s", arg)
 			return
 		}
+
+		fn.AddParams(param)
 	}
 
 	tx, err := cli.client.Call(recipient, fn)
@@ -431,6 +400,53 @@ func (cli *CLI) restart(ctx *cli.Context) {
 	cli.logger.Info().Msg(m.Message)
 }
 
+func (cli *CLI) logLevel(ctx *cli.Context) {
+	cmd := ctx.Args()
+
+	levels := make(wctl.LogLevels)
+
+	for _, arg := range cmd {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			cli.logger.Error().Msg("Invalid usage: loglevel [module=level...]")
+			return
+		}
+
+		levels[parts[0]] = parts[1]
+	}
+
+	var err error
+
+	if len(levels) > 0 {
+		levels, err = cli.client.UpdateLogLevels(levels)
+	} else {
+		levels, err = cli.client.LogLevels()
+	}
+
+	if err != nil {
+		cli.logger.Error().
+			Err(err).
+			Msg("Failed to get or set log levels.")
+
+		return
+	}
+
+	modules := make([]string, 0, len(levels))
+	for module := range levels {
+		modules = append(modules, module)
+	}
+
+	sort.Strings(modules)
+
+	ev := cli.logger.Info()
+
+	for _, module := range modules {
+		ev = ev.Str(module, levels[module])
+	}
+
+	ev.Msg("Log levels.")
+}
+
 func (cli *CLI) version(ctx *cli.Context) {
 	cli.logger.Info().
 		Str("git_commit", sys.GitCommit).
@@ -442,8 +458,16 @@ func (cli *CLI) version(ctx *cli.Context) {
 }
 
 func (cli *CLI) updateParameters(ctx *cli.Context) {
+	if err := conf.ValidateSnowball(
+		ctx.Int("snowball.k"), ctx.Float64("snowball.alpha"), ctx.Int("snowball.beta"),
+	); err != nil {
+		cli.logger.Error().Err(err).Msg("Invalid consensus parameters.")
+		return
+	}
+
 	conf.Update(
 		conf.WithSnowballK(ctx.Int("snowball.k")),
+		conf.WithSnowballAlpha(ctx.Float64("snowball.alpha")),
 		conf.WithSnowballBeta(ctx.Int("snowball.beta")),
 		conf.WithSyncVoteThreshold(ctx.Float64("vote.sync.threshold")),
 		conf.WithFinalizationVoteThreshold(ctx.Float64("vote.finalization.threshold")),
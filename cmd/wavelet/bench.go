@@ -0,0 +1,99 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"io"
+	"regexp"
+
+	"github.com/perlin-network/wavelet/bench"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func benchCommand(stdout io.Writer) cli.Command {
+	defaults := bench.DefaultOptions()
+
+	return cli.Command{
+		Name:  "bench",
+		Usage: "run the benchmark suite, and print a report comparable with benchstat",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "run",
+				Usage: "Only run benchmarks whose names match the regular expression.",
+			},
+			cli.DurationFlag{
+				Name:  "benchtime",
+				Usage: "Approximate time to run each benchmark for.",
+			},
+			cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the report as JSON.",
+			},
+			cli.StringFlag{
+				Name:  "contract",
+				Value: defaults.ContractPath,
+				Usage: "Path to the WASM contract invoked by contract benchmarks.",
+			},
+			cli.IntFlag{
+				Name:  "nodes",
+				Value: defaults.Nodes,
+				Usage: "Number of nodes of the devnet of end-to-end benchmarks.",
+			},
+			cli.IntFlag{
+				Name:  "batch",
+				Value: defaults.Batch,
+				Usage: "Number of transactions submitted per iteration of end-to-end benchmarks.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var filter *regexp.Regexp
+
+			if expr := c.String("run"); expr != "" {
+				var err error
+
+				if filter, err = regexp.Compile(expr); err != nil {
+					return errors.Wrap(err, "invalid benchmark filter")
+				}
+			}
+
+			// Silence the nodes of the devnet.
+			log.ClearWriter(log.LoggerWavelet)
+
+			suite := bench.Suite(bench.Options{
+				ContractPath: c.String("contract"),
+				Nodes:        c.Int("nodes"),
+				Batch:        c.Int("batch"),
+			})
+
+			results, err := bench.Run(suite, filter, c.Duration("benchtime"))
+			if err != nil {
+				return err
+			}
+
+			if c.Bool("json") {
+				return bench.WriteJSON(stdout, results)
+			}
+
+			return bench.WriteReport(stdout, results)
+		},
+	}
+}
@@ -32,8 +32,10 @@ import (
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/benpye/readline"
+	"github.com/perlin-network/wavelet"
 	"github.com/perlin-network/wavelet/conf"
 	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/sys"
 	"github.com/perlin-network/wavelet/wctl"
 	"github.com/rs/zerolog"
 )
@@ -58,6 +60,10 @@ type CLI struct {
 	stdout  io.Writer
 	nocolor bool
 
+	// jsonLogs has entries of all modules be logged as JSON, rather than the
+	// entries of some modules be printed for humans to read.
+	jsonLogs bool
+
 	cleanup func()
 }
 
@@ -81,6 +87,12 @@ func CLIWithNoColor(b bool) CLIOption {
 	}
 }
 
+func CLIWithJSONLogs(b bool) CLIOption {
+	return func(cli *CLI) {
+		cli.jsonLogs = b
+	}
+}
+
 func NewCLI(client *wctl.Client, opts ...CLIOption) (*CLI, error) {
 	// Set CLI callbacks, mainly loggers
 	cleanup, err := setEvents(client)
@@ -190,6 +202,12 @@ func NewCLI(client *wctl.Client, opts ...CLIOption) (*CLI, error) {
 				},
 			},
 		},
+		{
+			Name:        "loglevel",
+			Aliases:     []string{"ll"},
+			Action:      a(c.logLevel),
+			Description: "print out the log levels of the modules of the node, or set them with module=level pairs",
+		},
 		{
 			Name:        "version",
 			Aliases:     []string{"v"},
@@ -212,6 +230,11 @@ func NewCLI(client *wctl.Client, opts ...CLIOption) (*CLI, error) {
 					Value: conf.GetSnowballK(),
 					Usage: "snowball K consensus parameter",
 				},
+				cli.Float64Flag{
+					Name:  "snowball.alpha",
+					Value: conf.GetSnowballAlpha(),
+					Usage: "snowball Alpha consensus parameter",
+				},
 				cli.IntFlag{
 					Name:  "snowball.beta",
 					Value: conf.GetSnowballBeta(),
@@ -363,6 +386,11 @@ func NewCLI(client *wctl.Client, opts ...CLIOption) (*CLI, error) {
 
 	c.rl = rl
 
+	if c.jsonLogs {
+		log.SetSink(log.LoggerWavelet, log.NewJSONWriter(rl.Stdout()))
+		return c, nil
+	}
+
 	log.SetWriter(
 		log.LoggerWavelet,
 		log.NewConsoleWriter(
@@ -431,6 +459,23 @@ func a(f func(*cli.Context)) func(*cli.Context) error {
 func (cli *CLI) parseRecipient(arg string) ([32]byte, bool) {
 	var recipient [32]byte
 
+	if len(arg) <= sys.MaxNameLength && wavelet.ValidateName(arg) == nil {
+		recipient, err := cli.client.Resolve(arg)
+		if err != nil {
+			cli.logger.Error().Err(err).
+				Msg("The name you specified could not be resolved.")
+			return recipient, false
+		}
+
+		return recipient, true
+	}
+
+	if hex.DecodedLen(len(arg)) != len(recipient) {
+		cli.logger.Error().Int("length", hex.DecodedLen(len(arg))).
+			Msg("The ID you specified is invalid.")
+		return recipient, false
+	}
+
 	i, err := hex.Decode(recipient[:], []byte(arg))
 	if err != nil {
 		cli.logger.Error().Err(err).
@@ -102,6 +102,10 @@ func onContractGas(u wctl.ContractGas) {
 func onContractLog(u wctl.ContractLog) {
 	logger.Info().
 		Hex("contract_id", u.ContractID[:]).
+		Uint64("block", u.Block).
+		Hex("tx_id", u.TxID[:]).
+		Hex("topic", u.Topic).
+		Hex("data", u.Data).
 		Msg(u.Message)
 }
 
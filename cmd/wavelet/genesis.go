@@ -0,0 +1,109 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/perlin-network/wavelet/genesis"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func genesisCommand(stdout io.Writer) cli.Command {
+	defaults := genesis.DefaultOptions()
+
+	return cli.Command{
+		Name:  "genesis",
+		Usage: "manage genesis files of test networks",
+		Subcommands: []cli.Command{
+			{
+				Name:  "generate",
+				Usage: "generate a genesis directory and the keys of its accounts",
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "accounts",
+						Value: defaults.Accounts,
+						Usage: "Number of accounts, validators included.",
+					},
+					cli.IntFlag{
+						Name:  "validators",
+						Value: defaults.Validators,
+						Usage: "Number of accounts which stake at genesis.",
+					},
+					cli.StringFlag{
+						Name:  "distribution",
+						Value: defaults.Distribution,
+						Usage: "Distribution of balances amongst accounts: " + strings.Join(genesis.Distributions, ", ") + ".",
+					},
+					cli.Uint64Flag{
+						Name:  "supply",
+						Value: defaults.Supply,
+						Usage: "Total balance of all accounts.",
+					},
+					cli.Uint64Flag{
+						Name:  "stake",
+						Value: defaults.Stake,
+						Usage: "Stake of each validator.",
+					},
+					cli.Int64Flag{
+						Name:  "seed",
+						Usage: "Seed of the distribution of balances. Random if not set.",
+					},
+					cli.StringFlag{
+						Name:  "out, o",
+						Value: "testnet",
+						Usage: "Directory to write the genesis and keys to.",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					opts := genesis.Options{
+						Accounts:     c.Int("accounts"),
+						Validators:   c.Int("validators"),
+						Distribution: c.String("distribution"),
+						Supply:       c.Uint64("supply"),
+						Stake:        c.Uint64("stake"),
+						Seed:         defaults.Seed,
+					}
+
+					if c.IsSet("seed") {
+						opts.Seed = c.Int64("seed")
+					}
+
+					g, err := genesis.Generate(opts)
+					if err != nil {
+						return errors.Wrap(err, "failed to generate genesis")
+					}
+
+					if err := g.Write(c.String("out")); err != nil {
+						return err
+					}
+
+					_, err = fmt.Fprintf(stdout, "Generated %d accounts, %d of which are validators, in %s.\n",
+						opts.Accounts, opts.Validators, c.String("out"))
+
+					return err
+				},
+			},
+		},
+	}
+}
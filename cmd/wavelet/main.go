@@ -26,14 +26,17 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/perlin-network/noise/edwards25519"
 	"github.com/perlin-network/noise/skademlia"
 	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/api"
 	"github.com/perlin-network/wavelet/cmd/wavelet/node"
 	"github.com/perlin-network/wavelet/conf"
 	"github.com/perlin-network/wavelet/log"
@@ -88,6 +91,13 @@ func Run(args []string, stdin io.ReadCloser, stdout io.Writer, disableGC bool) {
 			Usage:  "Enable port forwarding: only required for personal PCs.",
 			EnvVar: "WAVELET_NAT",
 		}),
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name:  "nat.protocol",
+			Value: node.NATAuto,
+			Usage: "Protocol to forward ports with, being one of auto (UPnP, then NAT-PMP), upnp or pmp. Should " +
+				"forwarding fail, the node runs relay-only, only connecting to peers itself.",
+			EnvVar: "WAVELET_NAT_PROTOCOL",
+		}),
 		altsrc.NewStringFlag(cli.StringFlag{
 			Name:   "update-url",
 			Value:  "https://updates.perlin.net/wavelet",
@@ -123,6 +133,66 @@ func Run(args []string, stdin io.ReadCloser, stdout io.Writer, disableGC bool) {
 			Usage:  "Port to connect to to manage the node.",
 			EnvVar: "WAVELET_CLI_PORT",
 		}),
+		altsrc.NewUintFlag(cli.UintFlag{
+			Name:   "api.grpc.port",
+			Value:  0,
+			Usage:  "Host a local gRPC API at port, alongside the HTTP API. Disabled should it be 0.",
+			EnvVar: "WAVELET_API_GRPC_PORT",
+		}),
+		altsrc.NewUintFlag(cli.UintFlag{
+			Name:   "api.rosetta.port",
+			Value:  0,
+			Usage:  "Host the Rosetta Data and Construction APIs at port. Disabled should it be 0.",
+			EnvVar: "WAVELET_API_ROSETTA_PORT",
+		}),
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name:   "api.rosetta.network",
+			Value:  "mainnet",
+			Usage:  "Name of the network the Rosetta API identifies.",
+			EnvVar: "WAVELET_API_ROSETTA_NETWORK",
+		}),
+		altsrc.NewBoolFlag(cli.BoolFlag{
+			Name:   "api.metrics",
+			Usage:  "Serve metrics of the node at /metrics of the HTTP API, in the Prometheus format.",
+			EnvVar: "WAVELET_API_METRICS",
+		}),
+		altsrc.NewIntFlag(cli.IntFlag{
+			Name:   "health.peers.min",
+			Value:  wavelet.DefaultHealthThresholds().MinPeers,
+			Usage:  "Fewest peers the node may be connected to, and be reported ready at /readyz of the HTTP API.",
+			EnvVar: "WAVELET_HEALTH_PEERS_MIN",
+		}),
+		altsrc.NewDurationFlag(cli.DurationFlag{
+			Name:  "health.stall.max",
+			Value: wavelet.DefaultHealthThresholds().MaxStall,
+			Usage: "Longest a round of consensus may go on without the node finalizing a block, past which the " +
+				"node is reported unhealthy at /healthz of the HTTP API. Never should it be 0.",
+			EnvVar: "WAVELET_HEALTH_STALL_MAX",
+		}),
+		altsrc.NewUint64Flag(cli.Uint64Flag{
+			Name:   "health.sync.lag.max",
+			Value:  wavelet.DefaultHealthThresholds().MaxSyncLag,
+			Usage:  "Most blocks the node may be behind its peers, and be reported ready at /readyz of the HTTP API.",
+			EnvVar: "WAVELET_HEALTH_SYNC_LAG_MAX",
+		}),
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name: "otlp.endpoint",
+			Usage: "Base URL of the OTLP/HTTP receiver of an OpenTelemetry collector to export traces of " +
+				"transactions to, such as http://localhost:4318. Tracing is disabled should it be empty.",
+			EnvVar: "WAVELET_OTLP_ENDPOINT",
+		}),
+		altsrc.NewStringSliceFlag(cli.StringSliceFlag{
+			Name:   "otlp.header",
+			Usage:  "Header to export traces with, as key=value. May be specified multiple times.",
+			EnvVar: "WAVELET_OTLP_HEADERS",
+		}),
+		altsrc.NewFloat64Flag(cli.Float64Flag{
+			Name:  "trace.sample",
+			Value: 1,
+			Usage: "Ratio of traces started by the node which are sampled, between 0 and 1. Traces started by " +
+				"clients or peers are sampled as their traceparent says.",
+			EnvVar: "WAVELET_TRACE_SAMPLE",
+		}),
 		altsrc.NewStringFlag(cli.StringFlag{
 			Name:   "api.host",
 			Usage:  "Host for the API HTTPS node.",
@@ -139,6 +209,33 @@ func Run(args []string, stdin io.ReadCloser, stdout io.Writer, disableGC bool) {
 			Usage:  "Shared secret to restrict access to some api",
 			EnvVar: "WAVELET_API_SECRET",
 		},
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name: "admin.addr",
+			Usage: "Address to host the admin API at, such as 127.0.0.1:9001, serving privileged routes such as " +
+				"/node/restart and /debug in place of the HTTP API. Disabled should it be empty.",
+			EnvVar: "WAVELET_ADMIN_ADDR",
+		}),
+		cli.StringFlag{
+			Name:   "admin.token",
+			Usage:  "Bearer token requests to the admin API must carry.",
+			EnvVar: "WAVELET_ADMIN_TOKEN",
+		},
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name:   "admin.tls.cert",
+			Usage:  "Path of the PEM-encoded certificate to serve the admin API over TLS with.",
+			EnvVar: "WAVELET_ADMIN_TLS_CERT",
+		}),
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name:   "admin.tls.key",
+			Usage:  "Path of the PEM-encoded private key of admin.tls.cert.",
+			EnvVar: "WAVELET_ADMIN_TLS_KEY",
+		}),
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name: "admin.tls.client_ca",
+			Usage: "Path of the PEM-encoded certificate authorities clients of the admin API must present a " +
+				"certificate issued by.",
+			EnvVar: "WAVELET_ADMIN_TLS_CLIENT_CA",
+		}),
 		altsrc.NewStringFlag(cli.StringFlag{
 			Name: "wallet",
 			Usage: "Path to file containing hex-encoded private key. If the path specified is invalid, or no file " +
@@ -163,11 +260,93 @@ func Run(args []string, stdin io.ReadCloser, stdout io.Writer, disableGC bool) {
 			Usage:  "Minimum log level to output. Possible values: debug, info, warn, error, fatal, panic.",
 			EnvVar: "WAVELET_LOGLEVEL",
 		}),
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name:  "log.format",
+			Value: "console",
+			Usage: "Format of log output. Possible values: console, json. JSON output carries the entries of all " +
+				"modules, one object per line, for them to be shipped to log aggregators.",
+			EnvVar: "WAVELET_LOG_FORMAT",
+		}),
 		altsrc.NewBoolFlag(cli.BoolFlag{
 			Name:   "log.nocolor",
 			Usage:  "Disable color in log output.",
 			EnvVar: "WAVELET_LOG_NOCOLOR",
 		}),
+		altsrc.NewBoolFlag(cli.BoolFlag{
+			Name:   "beacon",
+			Usage:  "Contribute to the randomness beacon after every block should the node be a validator.",
+			EnvVar: "WAVELET_BEACON",
+		}),
+		altsrc.NewStringSliceFlag(cli.StringSliceFlag{
+			Name: "admission",
+			Usage: "Name of a compiled-in admission hook to screen transactions with before admitting them. May " +
+				"be specified multiple times, hooks screening transactions in the order specified.",
+			EnvVar: "WAVELET_ADMISSION",
+		}),
+		altsrc.NewStringSliceFlag(cli.StringSliceFlag{
+			Name: "alert.webhook",
+			Usage: "URL to post alerts to as JSON, such as on observing conflicting transactions from the same " +
+				"sender. May be specified multiple times.",
+			EnvVar: "WAVELET_ALERT_WEBHOOK",
+		}),
+		altsrc.NewBoolFlag(cli.BoolFlag{
+			Name: "archival",
+			Usage: "Keep the state of the ledger as of every block finalized, such that accounts may be queried as " +
+				"of past rounds. Costs more disk, as the state is never garbage collected.",
+			EnvVar: "WAVELET_ARCHIVAL",
+		}),
+		altsrc.NewUint64Flag(cli.Uint64Flag{
+			Name: "db.retain.blocks",
+			Usage: "Number of latest blocks whose transaction diffs are retained in the database, older ones being " +
+				"pruned. Diffs of all blocks are retained should it be 0.",
+			EnvVar: "WAVELET_DB_RETAIN_BLOCKS",
+		}),
+		altsrc.NewDurationFlag(cli.DurationFlag{
+			Name:   "db.compact.interval",
+			Value:  time.Hour,
+			Usage:  "How often to compact the database when pruning it. Never should it be 0.",
+			EnvVar: "WAVELET_DB_COMPACT_INTERVAL",
+		}),
+		altsrc.NewStringSliceFlag(cli.StringSliceFlag{
+			Name: "seed",
+			Usage: "Domain of a DNS seed to discover peers from, advertising them as SRV records under " +
+				"_wavelet._tcp.<seed> or as TXT records of host:port addresses. May be specified multiple times.",
+			EnvVar: "WAVELET_SEEDS",
+		}),
+		altsrc.NewIntFlag(cli.IntFlag{
+			Name:   "peer.max",
+			Value:  wavelet.DefaultMaxPeers,
+			Usage:  "Maximum number of peers to connect to, past which the least reputable peers are disconnected from.",
+			EnvVar: "WAVELET_PEER_MAX",
+		}),
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name:  "compression",
+			Value: wavelet.CompressionSnappy,
+			Usage: "Encoding to compress gossiped transactions with, being one of zstd, snappy or none. Peers not " +
+				"accepting it are gossiped to compressed with snappy. Should it be none, no message to peers is " +
+				"compressed, sparing the CPU of the node.",
+			EnvVar: "WAVELET_COMPRESSION",
+		}),
+		altsrc.NewFloat64Flag(cli.Float64Flag{
+			Name:  "peer.ban.score",
+			Value: wavelet.DefaultPeerPolicy().BanScore,
+			Usage: "Score at or below which peers are banned. Peers start with a score of 0, which drops with every " +
+				"protocol violation they commit and recovers over time.",
+			EnvVar: "WAVELET_PEER_BAN_SCORE",
+		}),
+		altsrc.NewDurationFlag(cli.DurationFlag{
+			Name:   "peer.ban.duration",
+			Value:  wavelet.DefaultPeerPolicy().BanDuration,
+			Usage:  "How long peers are banned for.",
+			EnvVar: "WAVELET_PEER_BAN_DURATION",
+		}),
+		altsrc.NewUint64Flag(cli.Uint64Flag{
+			Name:  "peer.max.rps",
+			Value: wavelet.DefaultPeerPolicy().MaxRequestsPerSecond,
+			Usage: "Number of requests a peer may make every second before being penalized for excessive traffic. " +
+				"Unlimited should it be 0.",
+			EnvVar: "WAVELET_PEER_MAX_RPS",
+		}),
 		altsrc.NewIntFlag(cli.IntFlag{
 			Name:   "memory.max",
 			Value:  0,
@@ -188,16 +367,75 @@ func Run(args []string, stdin io.ReadCloser, stdout io.Writer, disableGC bool) {
 			Value: sys.MinimumStake,
 			Usage: "minimum stake to garner validator rewards and have importance in consensus",
 		}),
+		altsrc.NewUint64Flag(cli.Uint64Flag{
+			Name:  "sys.slashing.double_sign",
+			Value: uint64(sys.SlashingDoubleSignFraction),
+			Usage: "fraction of its stake slashed from a validator which signed two conflicting transactions, in " +
+				"hundredths of a percent",
+		}),
+		altsrc.NewUint64Flag(cli.Uint64Flag{
+			Name:  "sys.slashing.downtime",
+			Value: uint64(sys.SlashingDowntimeFraction),
+			Usage: "fraction of its stake slashed from a validator reported for downtime, in hundredths of a percent",
+		}),
+		altsrc.NewUint64Flag(cli.Uint64Flag{
+			Name:  "sys.slashing.downtime_blocks",
+			Value: sys.SlashingDowntimeBlocks,
+			Usage: "number of blocks a validator may go without having a transaction applied before it may be " +
+				"slashed for downtime",
+		}),
+		altsrc.NewUint64Flag(cli.Uint64Flag{
+			Name:  "sys.slashing.reporter_share",
+			Value: uint64(sys.SlashingReporterShare),
+			Usage: "share of the PERLs slashed from a validator rewarded to the reporter of its misbehavior, in " +
+				"hundredths of a percent. The rest are burned",
+		}),
+		altsrc.NewStringSliceFlag(cli.StringSliceFlag{
+			Name: "sys.feature",
+			Usage: "schedule a protocol feature to activate from a block height, as feature=height, or every " +
+				"feature as all=height. Features are unscheduled by default. All nodes of a network must agree on " +
+				"the schedule. May be specified multiple times.",
+			EnvVar: "WAVELET_FEATURE",
+		}),
+		altsrc.NewStringFlag(cli.StringFlag{
+			Name: "sys.gas_schedule",
+			Usage: "JSON file replacing the costs of a version of the gas schedule, as served by /ledger/gas. All " +
+				"nodes of a network must agree on the schedule.",
+			EnvVar: "WAVELET_GAS_SCHEDULE",
+		}),
+		altsrc.NewIntFlag(cli.IntFlag{
+			Name:  "sys.apply_workers",
+			Value: conf.GetApplyWorkers(),
+			Usage: "Number of workers applying transactions of a block which touch distinct accounts concurrently. " +
+				"Transactions are applied one at a time should it be 1.",
+			EnvVar: "WAVELET_APPLY_WORKERS",
+		}),
+		altsrc.NewIntFlag(cli.IntFlag{
+			Name:  "sys.contract_cache_size",
+			Value: conf.GetContractModuleCacheSize(),
+			Usage: "Number of compiled smart contracts cached by the hash of their code, the least recently used " +
+				"being evicted first. Smart contracts are compiled on every call should it be 0.",
+			EnvVar: "WAVELET_CONTRACT_CACHE_SIZE",
+		}),
 		altsrc.NewIntFlag(cli.IntFlag{
-			Name:   "sys.snowball.k",
-			Value:  conf.GetSnowballK(),
-			Usage:  "Snowball consensus protocol parameter k",
+			Name:  "sys.snowball.k",
+			Value: conf.GetSnowballK(),
+			Usage: fmt.Sprintf("Snowball consensus protocol parameter k, being the number of peers queried "+
+				"(%d to %d)", conf.MinSnowballK, conf.MaxSnowballK),
 			EnvVar: "WAVELET_SNOWBALL_K",
 		}),
+		altsrc.NewFloat64Flag(cli.Float64Flag{
+			Name:  "sys.snowball.alpha",
+			Value: conf.GetSnowballAlpha(),
+			Usage: "Snowball consensus protocol parameter alpha, being the fraction of queried peers which must agree " +
+				"(above 0.5, and at most 1)",
+			EnvVar: "WAVELET_SNOWBALL_ALPHA",
+		}),
 		altsrc.NewIntFlag(cli.IntFlag{
-			Name:   "sys.snowball.beta",
-			Value:  conf.GetSnowballBeta(),
-			Usage:  "Snowball consensus protocol parameter beta",
+			Name:  "sys.snowball.beta",
+			Value: conf.GetSnowballBeta(),
+			Usage: fmt.Sprintf("Snowball consensus protocol parameter beta, being the number of consecutive queries "+
+				"which must agree (%d to %d)", conf.MinSnowballBeta, conf.MaxSnowballBeta),
 			EnvVar: "WAVELET_SNOWBALL_BETA",
 		}),
 		cli.StringFlag{
@@ -229,6 +467,12 @@ func Run(args []string, stdin io.ReadCloser, stdout io.Writer, disableGC bool) {
 		return start(c, stdin, stdout, disableGC)
 	}
 
+	app.Commands = []cli.Command{
+		benchCommand(stdout),
+		genesisCommand(stdout),
+		snapshotCommand(stdout),
+	}
+
 	sort.Sort(cli.FlagsByName(app.Flags))
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -250,6 +494,14 @@ func start(c *cli.Context, stdin io.ReadCloser, stdout io.Writer, disableGC bool
 		log.SetLevel(c.String("loglevel"))
 	}
 
+	switch c.String("log.format") {
+	case "console":
+	case "json":
+		log.SetSink(log.LoggerWavelet, log.NewJSONWriter(stdout))
+	default:
+		return errors.Errorf("unknown log format %q", c.String("log.format"))
+	}
+
 	// Start the background updater
 	// go periodicUpdateRoutine(c.String("update-url"))
 
@@ -272,23 +524,60 @@ func start(c *cli.Context, stdin io.ReadCloser, stdout io.Writer, disableGC bool
 		secret = base64.StdEncoding.EncodeToString(sha[:])
 	}
 
+	if err := conf.ValidateSnowball(
+		c.Int("sys.snowball.k"), c.Float64("sys.snowball.alpha"), c.Int("sys.snowball.beta"),
+	); err != nil {
+		return err
+	}
+
 	conf.Update(
 		conf.WithSnowballK(c.Int("sys.snowball.k")),
+		conf.WithSnowballAlpha(c.Float64("sys.snowball.alpha")),
 		conf.WithSnowballBeta(c.Int("sys.snowball.beta")),
 		conf.WithQueryTimeout(c.Duration("sys.query_timeout")),
 		conf.WithSecret(secret),
+		conf.WithApplyWorkers(c.Int("sys.apply_workers")),
+		conf.WithContractModuleCacheSize(c.Int("sys.contract_cache_size")),
 	)
 
 	// set the the sys variables
 	sys.DefaultTransactionFee = c.Uint64("sys.transaction_fee_amount")
 	sys.MinimumStake = c.Uint64("sys.min_stake")
+	sys.SlashingDowntimeBlocks = c.Uint64("sys.slashing.downtime_blocks")
+
+	if err := setSlashingFractions(
+		c.Uint64("sys.slashing.double_sign"), c.Uint64("sys.slashing.downtime"), c.Uint64("sys.slashing.reporter_share"),
+	); err != nil {
+		return err
+	}
+
+	if err := scheduleFeatures(c.StringSlice("sys.feature")); err != nil {
+		return err
+	}
+
+	if path := c.String("sys.gas_schedule"); path != "" {
+		if err := loadGasSchedule(path); err != nil {
+			return err
+		}
+	}
+
+	traceHeaders, err := parseHeaders(c.StringSlice("otlp.header"))
+	if err != nil {
+		return err
+	}
 
 	var wctlCfg wctl.Config
 	wctlCfg.APISecret = conf.GetSecret()
 
 	if config.ServerAddr == "" {
+		peerPolicy := wavelet.DefaultPeerPolicy()
+		peerPolicy.BanScore = c.Float64("peer.ban.score")
+		peerPolicy.BanDuration = c.Duration("peer.ban.duration")
+		peerPolicy.MaxRequestsPerSecond = c.Uint64("peer.max.rps")
+
 		srvCfg := node.Config{
 			NAT:         c.Bool("nat"),
+			NATProtocol: c.String("nat.protocol"),
 			Host:        c.String("host"),
 			Port:        c.Uint("port"),
 			Wallet:      w,
@@ -296,9 +585,34 @@ func start(c *cli.Context, stdin io.ReadCloser, stdout io.Writer, disableGC bool
 			Peers:       c.Args(),
 			Database:    c.String("db"),
 			MaxMemoryMB: c.Uint64("memory.max"),
+			Beacon:      c.Bool("beacon"),
+			Admission:   c.StringSlice("admission"),
+			Webhooks:    c.StringSlice("alert.webhook"),
+			// Pruning
+			RetainBlocks:    c.Uint64("db.retain.blocks"),
+			CompactInterval: c.Duration("db.compact.interval"),
+			Archival:        c.Bool("archival"),
+			PeerPolicy:      &peerPolicy,
+			Seeds:           c.StringSlice("seed"),
+			MaxPeers:        c.Int("peer.max"),
+			Compression:     c.String("compression"),
 			// HTTPS
-			APIHost:       c.String("api.host"),
-			APICertsCache: c.String("api.certs"),
+			APIHost:        c.String("api.host"),
+			APICertsCache:  c.String("api.certs"),
+			GRPCPort:       c.Uint("api.grpc.port"),
+			RosettaPort:    c.Uint("api.rosetta.port"),
+			RosettaNetwork: c.String("api.rosetta.network"),
+			Metrics:        c.Bool("api.metrics"),
+			Admin:          adminConfig(c),
+			Health: &wavelet.HealthThresholds{
+				MinPeers:   c.Int("health.peers.min"),
+				MaxStall:   c.Duration("health.stall.max"),
+				MaxSyncLag: c.Uint64("health.sync.lag.max"),
+			},
+			// Tracing
+			TraceEndpoint:    c.String("otlp.endpoint"),
+			TraceHeaders:     traceHeaders,
+			TraceSampleRatio: c.Float64("trace.sample"),
 			// Debugging only
 			NoGC: disableGC,
 		}
@@ -328,6 +642,18 @@ func start(c *cli.Context, stdin io.ReadCloser, stdout io.Writer, disableGC bool
 		}
 
 		wctlCfg.PrivateKey = srv.Keys.PrivateKey()
+
+		// The CLI makes privileged requests to the admin API, unless it is
+		// served over TLS, which the CLI holds no client certificate for.
+		if srvCfg.Admin != nil && srvCfg.Admin.CertFile == "" {
+			admin, err := adminEndpoint(srvCfg.Admin.Addr)
+			if err != nil {
+				return err
+			}
+
+			wctlCfg.Admin = admin
+			wctlCfg.AdminToken = srvCfg.Admin.Token
+		}
 		wctlCfg.APIHost = c.String("cli.host")
 
 		if wctlCfg.APIHost == "" {
@@ -365,6 +691,10 @@ func start(c *cli.Context, stdin io.ReadCloser, stdout io.Writer, disableGC bool
 		opts = append(opts, CLIWithNoColor(true))
 	}
 
+	if c.String("log.format") == "json" {
+		opts = append(opts, CLIWithJSONLogs(true))
+	}
+
 	shell, err := NewCLI(client, opts...)
 	if err != nil {
 		return fmt.Errorf("failed to spawn the CLI: %v", err)
@@ -375,6 +705,126 @@ func start(c *cli.Context, stdin io.ReadCloser, stdout io.Writer, disableGC bool
 	return nil
 }
 
+// scheduleFeatures overrides the heights protocol features activate from with
+// a list of feature=height pairs, all=height scheduling every feature.
+func scheduleFeatures(schedule []string) error {
+	for _, entry := range schedule {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("feature must be specified as feature=height, got %q", entry)
+		}
+
+		height, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid activation height for feature %q", parts[0])
+		}
+
+		if parts[0] == "all" {
+			sys.ScheduleFeatures(height)
+			continue
+		}
+
+		sys.FeatureActivations[sys.Feature(parts[0])] = height
+	}
+
+	return nil
+}
+
+// adminConfig returns the configuration of the admin API, or nil should it
+// be disabled.
+func adminConfig(c *cli.Context) *api.AdminConfig {
+	if c.String("admin.addr") == "" {
+		return nil
+	}
+
+	return &api.AdminConfig{
+		Addr:         c.String("admin.addr"),
+		Token:        c.String("admin.token"),
+		CertFile:     c.String("admin.tls.cert"),
+		KeyFile:      c.String("admin.tls.key"),
+		ClientCAFile: c.String("admin.tls.client_ca"),
+	}
+}
+
+// adminEndpoint returns the endpoint the admin API listening at addr is
+// reached at locally.
+func adminEndpoint(addr string) (*wctl.Endpoint, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid admin.addr")
+	}
+
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid admin.addr")
+	}
+
+	return &wctl.Endpoint{Host: host, Port: uint16(p)}, nil
+}
+
+// parseHeaders parses a list of key=value pairs into the headers to export
+// traces with.
+func parseHeaders(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	headers := make(map[string]string, len(pairs))
+
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Errorf("header must be specified as key=value, got %q", pair)
+		}
+
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return headers, nil
+}
+
+// setSlashingFractions sets the fractions of stake slashed from misbehaving
+// validators, and the share of it rewarded to reporters, all of which are in
+// hundredths of a percent.
+func setSlashingFractions(doubleSign, downtime, reporterShare uint64) error {
+	fractions := []struct {
+		name  string
+		value uint64
+		param *uint16
+	}{
+		{"sys.slashing.double_sign", doubleSign, &sys.SlashingDoubleSignFraction},
+		{"sys.slashing.downtime", downtime, &sys.SlashingDowntimeFraction},
+		{"sys.slashing.reporter_share", reporterShare, &sys.SlashingReporterShare},
+	}
+
+	for _, fraction := range fractions {
+		if fraction.value > 10000 {
+			return errors.Errorf("%s must be at most 10000 hundredths of a percent, got %d", fraction.name, fraction.value)
+		}
+
+		*fraction.param = uint16(fraction.value)
+	}
+
+	return nil
+}
+
+// loadGasSchedule replaces the costs of a version of the gas schedule with
+// those of the JSON file at path.
+func loadGasSchedule(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open gas schedule")
+	}
+
+	defer f.Close()
+
+	return sys.LoadGasSchedule(f)
+}
+
 // returns hex-encoded
 func wallet(wallet string) (string, error) {
 	var keys *skademlia.Keypair
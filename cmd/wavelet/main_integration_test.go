@@ -536,7 +536,7 @@ func TestMain_UpdateParams(t *testing.T) {
 		Var    string
 		Value  interface{}
 	}{
-		{"snowball.k", "snowballK", 123},
+		{"snowball.k", "snowballK", 48},
 		{"snowball.beta", "snowballBeta", 789},
 		{"vote.sync.threshold", "syncVoteThreshold", 12.34},
 		{"vote.finalization.threshold", "finalizationVoteThreshold", 56.78},
@@ -0,0 +1,170 @@
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/nat"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+)
+
+// Protocols to map the port our node listens for peers on with.
+const (
+	// NATAuto tries UPnP, and then NAT-PMP.
+	NATAuto = "auto"
+	NATUPnP = "upnp"
+	NATPMP  = "pmp"
+)
+
+// natLifetime is how long port mappings last, being renewed halfway through.
+const natLifetime = 30 * time.Minute
+
+// portMapping is a port mapped on the gateway of our node, such that peers
+// may dial our node from outside its network.
+type portMapping struct {
+	provider nat.Provider
+	protocol string
+	port     uint16
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// natProtocols returns the protocols to try mapping ports with, in order,
+// for protocol.
+func natProtocols(protocol string) ([]string, error) {
+	switch protocol {
+	case NATAuto, "":
+		return []string{NATUPnP, NATPMP}, nil
+	case NATUPnP, NATPMP:
+		return []string{protocol}, nil
+	default:
+		return nil, errors.Errorf("unknown NAT protocol %q, must be one of %s, %s or %s",
+			protocol, NATAuto, NATUPnP, NATPMP)
+	}
+}
+
+// mapPort maps port on the gateway of our node through the first of
+// protocols supported by it, returning the external IP address of the
+// gateway should it report one. The mapping is renewed until it is closed.
+func mapPort(protocols []string, port uint16) (*portMapping, net.IP, error) {
+	err := errors.New("no NAT protocol to map port with")
+
+	for _, protocol := range protocols {
+		var (
+			provider nat.Provider
+			ip       net.IP
+		)
+
+		provider, ip, err = discoverGateway(protocol, port)
+		if err != nil {
+			continue
+		}
+
+		m := &portMapping{
+			provider: provider,
+			protocol: protocol,
+			port:     port,
+			stop:     make(chan struct{}),
+		}
+
+		m.wg.Add(1)
+
+		go m.renew()
+
+		return m, ip, nil
+	}
+
+	return nil, nil, err
+}
+
+// discoverGateway finds a gateway supporting protocol and maps port on it.
+// The providers of the noise library panic should they find no gateway,
+// which is recovered from.
+func discoverGateway(protocol string, port uint16) (provider nat.Provider, ip net.IP, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			provider, ip, err = nil, nil, errors.Errorf("%s: %v", protocol, r)
+		}
+	}()
+
+	if protocol == NATUPnP {
+		provider = nat.NewUPnP()
+	} else {
+		provider = nat.NewPMP()
+	}
+
+	if err := provider.AddMapping("tcp", port, port, natLifetime); err != nil {
+		return nil, nil, errors.Wrapf(err, "%s: failed to map port %d", protocol, port)
+	}
+
+	// Gateways behind another NAT report a private external address.
+	if ip, err = provider.ExternalIP(); err != nil || nat.IsPrivateIP(ip) {
+		ip = nil
+	}
+
+	return provider, ip, nil
+}
+
+func (m *portMapping) renew() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(natLifetime / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			if err := m.provider.AddMapping("tcp", m.port, m.port, natLifetime); err != nil {
+				logger := log.Node()
+				logger.Warn().Err(err).
+					Str("protocol", m.protocol).
+					Uint16("port", m.port).
+					Msg("Failed to renew port mapping.")
+			}
+		}
+	}
+}
+
+// Close stops renewing the mapping, and unmaps the port.
+func (m *portMapping) Close() error {
+	close(m.stop)
+	m.wg.Wait()
+
+	return m.provider.DeleteMapping("tcp", m.port, m.port)
+}
+
+// lookupExternalIP looks up the IP address our node reaches the internet
+// from, for when its gateway does not report it.
+func lookupExternalIP() (net.IP, error) {
+	client := http.Client{Timeout: 10 * time.Second}
+
+	resp, err := client.Get("http://myexternalip.com/raw")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get external IP: %v", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get external IP: %v", err)
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(string(raw)))
+	if ip == nil {
+		return nil, fmt.Errorf("failed to get external IP: %q is not an IP address", raw)
+	}
+
+	return ip, nil
+}
@@ -3,9 +3,7 @@ package node
 import (
 	"encoding/hex"
 	"fmt"
-	"io/ioutil"
 	"net"
-	"net/http"
 	"strconv"
 	"time"
 
@@ -13,21 +11,27 @@ import (
 	"github.com/perlin-network/noise/cipher"
 	"github.com/perlin-network/noise/edwards25519"
 	"github.com/perlin-network/noise/handshake"
-	"github.com/perlin-network/noise/nat"
 	"github.com/perlin-network/noise/skademlia"
 	"github.com/perlin-network/wavelet"
 	"github.com/perlin-network/wavelet/api"
+	"github.com/perlin-network/wavelet/api/rosetta"
 	"github.com/perlin-network/wavelet/internal/snappy"
 	"github.com/perlin-network/wavelet/log"
 	"github.com/perlin-network/wavelet/store"
 	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/trace"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 )
 
 type Config struct {
-	NAT         bool
+	NAT bool
+
+	// NATProtocol is the protocol to map ports through NAT with, being one
+	// of NATAuto, NATUPnP or NATPMP.
+	NATProtocol string
+
 	Host        string
 	Port        uint
 	Wallet      string // hex encoded
@@ -37,10 +41,78 @@ type Config struct {
 	Database    string
 	MaxMemoryMB uint64
 
+	// Beacon is whether to contribute to the randomness beacon as a
+	// validator.
+	Beacon bool
+
+	// Admission names the admission hooks to screen transactions with, in
+	// order.
+	Admission []string
+
+	// Webhooks are the URLs to post alerts to.
+	Webhooks []string
+
+	// RetainBlocks is the number of latest blocks whose transaction diffs
+	// are retained, or 0 to retain those of all blocks.
+	RetainBlocks uint64
+
+	// CompactInterval is how often to compact the database when pruning,
+	// or 0 not to.
+	CompactInterval time.Duration
+
+	// Archival is whether to keep the state of the ledger as of every block
+	// finalized, such that it may be queried.
+	Archival bool
+
+	// PeerPolicy is the policy to score and ban peers by, or nil for the
+	// default policy.
+	PeerPolicy *wavelet.PeerPolicy
+
+	// Seeds are the domains of the DNS seeds to discover peers from.
+	Seeds []string
+
+	// MaxPeers is the most peers to connect to, or 0 for the default.
+	MaxPeers int
+
+	// Compression is the encoding to compress messages to peers with, being
+	// one of wavelet.CompressionZstd, wavelet.CompressionSnappy or
+	// wavelet.CompressionNone, or empty for snappy.
+	Compression string
+
 	// HTTPS
 	APIHost       string
 	APICertsCache string
 
+	// GRPCPort is the port to serve the API over gRPC at, alongside HTTP, or
+	// 0 not to.
+	GRPCPort uint
+
+	// RosettaPort is the port to serve the Rosetta API at, or 0 not to.
+	// RosettaNetwork names the network the Rosetta API identifies.
+	RosettaPort    uint
+	RosettaNetwork string
+
+	// Admin, if set, is the admin API to serve the routes privileged to the
+	// operators of the node on, in place of the API.
+	Admin *api.AdminConfig
+
+	// Metrics is whether to serve metrics of the node at /metrics of the
+	// API, in the Prometheus format.
+	Metrics bool
+
+	// TraceEndpoint is the base URL of the OTLP/HTTP receiver of an
+	// OpenTelemetry collector to export traces of transactions to, or empty
+	// not to trace. TraceHeaders are set on every export request, and
+	// TraceSampleRatio is the ratio of traces started by the node sampled.
+	TraceEndpoint    string
+	TraceHeaders     map[string]string
+	TraceSampleRatio float64
+
+	// Health are the conditions under which the node is reported healthy at
+	// /healthz of the API, and ready at /readyz. The defaults of
+	// wavelet.DefaultHealthThresholds apply should it be nil.
+	Health *wavelet.HealthThresholds
+
 	// Only for testing
 	NoGC bool
 }
@@ -64,12 +136,19 @@ type Wavelet struct {
 	Keys    *skademlia.Keypair
 	Ledger  *wavelet.Ledger
 	Gateway *api.Gateway
+	Rosetta *rosetta.Server
 	Server  *grpc.Server
 
 	config   *Config
 	db       store.KV
 	logger   zerolog.Logger
 	listener net.Listener
+
+	// RelayOnly is whether peers are unable to dial our node, its port
+	// failing to be mapped through NAT.
+	RelayOnly bool
+
+	nat *portMapping
 }
 
 func New(cfg *Config) (*Wavelet, error) {
@@ -86,6 +165,12 @@ func New(cfg *Config) (*Wavelet, error) {
 		Gateway: api.New(),
 	}
 
+	if cfg.Admin != nil {
+		if err := w.Gateway.EnableAdmin(*cfg.Admin); err != nil {
+			return nil, err
+		}
+	}
+
 	// Make a logger
 	logger := log.Node()
 	w.logger = logger
@@ -104,35 +189,39 @@ func New(cfg *Config) (*Wavelet, error) {
 	)
 
 	if cfg.NAT {
-		if len(cfg.Peers) > 1 {
-			resolver := nat.NewPMP()
-
-			if err := resolver.AddMapping("tcp",
-				uint16(listener.Addr().(*net.TCPAddr).Port),
-				uint16(listener.Addr().(*net.TCPAddr).Port),
-				30*time.Minute,
-			); err != nil {
-				return nil, fmt.Errorf("failed to initialize NAT: %v", err)
-			}
-		}
-
-		resp, err := http.Get("http://myexternalip.com/raw")
+		protocols, err := natProtocols(cfg.NATProtocol)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get external IP: %v", err)
+			return nil, err
 		}
 
-		defer func() {
-			_ = resp.Body.Close()
-		}()
+		port := uint16(listener.Addr().(*net.TCPAddr).Port)
 
-		ip, err := ioutil.ReadAll(resp.Body)
+		mapping, ip, err := mapPort(protocols, port)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get external IP: %v", err)
-		}
+			// Peers are unable to dial our node, which only connects to
+			// peers itself and relays through them.
+			w.RelayOnly = true
+
+			logger.Warn().Err(err).
+				Uint16("port", port).
+				Msg("Failed to map port through NAT. Running relay-only, such that peers may not dial our node.")
+		} else {
+			w.nat = mapping
+
+			if ip == nil {
+				if ip, err = lookupExternalIP(); err != nil {
+					_ = mapping.Close()
+					return nil, err
+				}
+			}
 
-		addr = net.JoinHostPort(
-			string(ip), strconv.Itoa(listener.Addr().(*net.TCPAddr).Port),
-		)
+			addr = net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+
+			logger.Info().
+				Str("protocol", mapping.protocol).
+				Str("external_addr", addr).
+				Msg("Mapped port through NAT.")
+		}
 	}
 
 	logger.Info().Str("addr", addr).
@@ -158,15 +247,30 @@ func New(cfg *Config) (*Wavelet, error) {
 
 	w.Keys = keys
 
+	compression := cfg.Compression
+	if compression == "" {
+		compression = wavelet.CompressionSnappy
+	}
+
+	if err := wavelet.ValidCompression(compression); err != nil {
+		return nil, err
+	}
+
+	callOpts := []grpc.CallOption{
+		grpc.MaxCallRecvMsgSize(9 * 1024 * 1024),
+		grpc.MaxCallSendMsgSize(3 * 1024 * 1024),
+	}
+
+	// Nodes short on CPU compress no message to peers.
+	if compression != wavelet.CompressionNone {
+		callOpts = append(callOpts, grpc.UseCompressor(snappy.Name))
+	}
+
 	client := skademlia.NewClient(
 		addr, keys,
 		skademlia.WithC1(sys.SKademliaC1),
 		skademlia.WithC2(sys.SKademliaC2),
-		skademlia.WithDialOptions(grpc.WithDefaultCallOptions(
-			grpc.UseCompressor(snappy.Name),
-			grpc.MaxCallRecvMsgSize(9*1024*1024),
-			grpc.MaxCallSendMsgSize(3*1024*1024),
-		)),
+		skademlia.WithDialOptions(grpc.WithDefaultCallOptions(callOpts...)),
 	)
 
 	client.SetCredentials(noise.NewCredentials(
@@ -196,6 +300,40 @@ func New(cfg *Config) (*Wavelet, error) {
 		opts = append(opts, wavelet.WithMaxMemoryMB(cfg.MaxMemoryMB))
 	}
 
+	if cfg.Beacon {
+		opts = append(opts, wavelet.WithBeaconContributions())
+	}
+
+	if len(cfg.Admission) > 0 {
+		opts = append(opts, wavelet.WithAdmissionHooks(cfg.Admission...))
+	}
+
+	if len(cfg.Webhooks) > 0 {
+		opts = append(opts, wavelet.WithAlertWebhooks(cfg.Webhooks...))
+	}
+
+	if cfg.RetainBlocks > 0 {
+		opts = append(opts, wavelet.WithPruning(cfg.RetainBlocks, cfg.CompactInterval))
+	}
+
+	if cfg.Archival {
+		opts = append(opts, wavelet.WithArchival())
+	}
+
+	if cfg.PeerPolicy != nil {
+		opts = append(opts, wavelet.WithPeerPolicy(*cfg.PeerPolicy))
+	}
+
+	if len(cfg.Seeds) > 0 {
+		opts = append(opts, wavelet.WithSeeds(cfg.Seeds...))
+	}
+
+	opts = append(opts, wavelet.WithCompression(compression))
+
+	if cfg.MaxPeers > 0 {
+		opts = append(opts, wavelet.WithMaxPeers(cfg.MaxPeers))
+	}
+
 	ledger, err := wavelet.NewLedger(kv, client, opts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating ledger")
@@ -211,6 +349,28 @@ func (w *Wavelet) Start() {
 		w.config.APIPort = 9000
 	}
 
+	if w.config.Metrics {
+		w.Gateway.EnableMetrics()
+	}
+
+	if w.config.Health != nil {
+		w.Gateway.SetHealthThresholds(*w.config.Health)
+	}
+
+	if w.config.TraceEndpoint != "" {
+		publicKey := w.Keys.PublicKey()
+
+		cfg := trace.DefaultConfig(w.config.TraceEndpoint)
+		cfg.Headers = w.config.TraceHeaders
+		cfg.SampleRatio = w.config.TraceSampleRatio
+		cfg.Resource["service.version"] = sys.Version
+		cfg.Resource["service.instance.id"] = hex.EncodeToString(publicKey[:])
+
+		if err := trace.Enable(cfg); err != nil {
+			w.logger.Warn().Err(err).Msg("Failed to export traces.")
+		}
+	}
+
 	if w.config.APIHost != "" {
 		w.Gateway.StartHTTPS(
 			int(w.config.APIPort),
@@ -225,6 +385,17 @@ func (w *Wavelet) Start() {
 		)
 	}
 
+	w.Gateway.StartAdmin()
+
+	if w.config.GRPCPort != 0 {
+		w.Gateway.StartGRPC(int(w.config.GRPCPort))
+	}
+
+	if w.config.RosettaPort != 0 {
+		w.Rosetta = rosetta.New(w.Ledger, w.Net, w.config.RosettaNetwork)
+		w.Rosetta.Start(int(w.config.RosettaPort))
+	}
+
 	w.Server = w.Net.Listen()
 
 	go func() {
@@ -244,6 +415,8 @@ func (w *Wavelet) Start() {
 		}
 	}
 
+	w.Ledger.Discovery().Bootstrap()
+
 	if peers := w.Net.Bootstrap(); len(peers) > 0 {
 		var ids []string
 
@@ -257,8 +430,21 @@ func (w *Wavelet) Start() {
 
 func (w *Wavelet) Close() error {
 	w.Gateway.Shutdown()
+
+	trace.Disable()
+
+	if w.Rosetta != nil {
+		w.Rosetta.Shutdown()
+	}
+
 	w.Server.Stop()
 	w.Ledger.Close()
 
+	if w.nat != nil {
+		if err := w.nat.Close(); err != nil {
+			w.logger.Warn().Err(err).Msg("Failed to unmap port through NAT.")
+		}
+	}
+
 	return w.db.Close()
 }
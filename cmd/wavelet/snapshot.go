@@ -0,0 +1,188 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func snapshotCommand(stdout io.Writer) cli.Command {
+	dbFlag := cli.StringFlag{
+		Name:   "db",
+		Usage:  "Directory path to the database of the node, which must not be running.",
+		EnvVar: "WAVELET_DB_PATH",
+	}
+
+	return cli.Command{
+		Name:  "snapshot",
+		Usage: "export and import snapshots of the ledger state, to bootstrap nodes without syncing from genesis",
+		Subcommands: []cli.Command{
+			{
+				Name:      "export",
+				Usage:     "write a snapshot of the state of the ledger at a block to a file",
+				ArgsUsage: "<round> <file>",
+				Flags:     []cli.Flag{dbFlag},
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 2 {
+						return errors.New("expected a round and a file, see --help")
+					}
+
+					kv, err := openSnapshotDB(c.String("db"))
+					if err != nil {
+						return err
+					}
+
+					defer kv.Close()
+
+					round, err := parseSnapshotRound(kv, c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					path := c.Args().Get(1)
+
+					// The snapshot is written aside first, such that a
+					// failed export leaves no partial snapshot behind.
+					f, err := os.Create(path + ".tmp")
+					if err != nil {
+						return errors.Wrap(err, "failed to create snapshot file")
+					}
+
+					block, err := wavelet.ExportSnapshot(kv, round, f)
+					if closeErr := f.Close(); err == nil {
+						err = closeErr
+					}
+
+					if err == nil {
+						err = os.Rename(path+".tmp", path)
+					}
+
+					if err != nil {
+						_ = os.Remove(path + ".tmp")
+						return errors.Wrap(err, "failed to export snapshot")
+					}
+
+					_, err = fmt.Fprintf(stdout, "Exported the state of block %d (%x) with merkle root %x to %s.\n",
+						block.Index, block.ID, block.Merkle, path)
+
+					return err
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     "restore the state of the ledger from a snapshot file, or from the /snapshot endpoint of a peer",
+				ArgsUsage: "<file or url>",
+				Flags:     []cli.Flag{dbFlag},
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return errors.New("expected a file or url, see --help")
+					}
+
+					r, err := openSnapshot(c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					defer r.Close()
+
+					kv, err := openSnapshotDB(c.String("db"))
+					if err != nil {
+						return err
+					}
+
+					defer kv.Close()
+
+					block, err := wavelet.ImportSnapshot(kv, r)
+					if err != nil {
+						return errors.Wrap(err, "failed to import snapshot")
+					}
+
+					_, err = fmt.Fprintf(stdout, "Imported the state of block %d (%x) with merkle root %x.\n",
+						block.Index, block.ID, block.Merkle)
+
+					return err
+				},
+			},
+		},
+	}
+}
+
+func openSnapshotDB(path string) (store.KV, error) {
+	if path == "" {
+		return nil, errors.New("the database of the node must be given through --db")
+	}
+
+	kv, err := store.NewLevelDB(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open database located at %s", path)
+	}
+
+	return kv, nil
+}
+
+// parseSnapshotRound parses the index of the block to export the state of,
+// which may be "latest".
+func parseSnapshotRound(kv store.KV, arg string) (uint64, error) {
+	if arg == "latest" {
+		return wavelet.SnapshotHeight(kv)
+	}
+
+	round, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid round %q", arg)
+	}
+
+	return round, nil
+}
+
+// openSnapshot opens the snapshot at src, downloading it should src be an
+// HTTP(S) URL.
+func openSnapshot(src string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open snapshot file")
+		}
+
+		return f, nil
+	}
+
+	res, err := http.Get(src) // nolint:gosec
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download snapshot")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, errors.Errorf("failed to download snapshot: got status %d", res.StatusCode)
+	}
+
+	return res.Body, nil
+}
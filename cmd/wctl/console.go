@@ -0,0 +1,389 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benpye/readline"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	consoleName   = "console"
+	consolePrompt = "wctl> "
+
+	// sessionKey is the key of the session in the metadata of the app
+	// running the commands of a console.
+	sessionKey = "session"
+)
+
+// consoleRoutes are the routes of the node's HTTP API completed as the
+// argument of get.
+var consoleRoutes = []string{
+	wctl.RouteLedger,
+	wctl.RouteLedgerGas,
+	wctl.RouteAccount + "/",
+	wctl.RouteContract + "/",
+	wctl.RouteTxList,
+	wctl.RouteTxList + "/",
+	wctl.RouteRelayer + "/",
+	wctl.RouteName + "/",
+	wctl.RouteTime,
+	wctl.RouteMempool,
+	wctl.RouteMempool + "/count",
+	wctl.RouteMempool + "/contains/",
+}
+
+// consoleCommand returns the console command, which runs all other commands.
+func consoleCommand() cli.Command {
+	return cli.Command{
+		Name:  consoleName,
+		Usage: "open an interactive shell running wctl commands, which share a client of the node and the passphrase",
+		Description: "Commands are run as they would be by wctl, with the global flags the console was opened with. " +
+			"Commands and account names are completed with tab, and the passphrase is only prompted for once. " +
+			"The console additionally runs 'get <route>', pretty-printing the JSON a route of the node's HTTP API " +
+			"responds with, 'lock', forgetting the passphrase, and 'exit'.",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "history",
+				Value: defaultHistory(),
+				Usage: "File the history of commands run in the console is kept in.",
+			},
+		},
+		Action: console,
+	}
+}
+
+// session is the state shared by all commands run in a console.
+type session struct {
+	// ctx is the context of the console command, which holds the global
+	// flags of wctl.
+	ctx *cli.Context
+	rl  *readline.Instance
+
+	// passphrase is remembered once prompted for.
+	passphrase []byte
+	client     *wctl.Client
+
+	// accounts lists the names and addresses of the accounts of the
+	// keystore to complete, until a command is run.
+	accounts []string
+}
+
+// sessionOf returns the session of the console c is run in, or nil should
+// c not be run in a console.
+func sessionOf(c *cli.Context) *session {
+	s, _ := c.App.Metadata[sessionKey].(*session)
+	return s
+}
+
+// dial returns the client of the node shared by the session, connecting it
+// once first needed.
+func (s *session) dial() (*wctl.Client, error) {
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	client, err := wctl.NewClient(clientConfig(s.ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	s.client = client
+
+	return client, nil
+}
+
+// completeAccounts returns the names and addresses of the accounts of the
+// keystore. Keystores of JSON key files are only listed once their
+// passphrase is known, such that completion never prompts for it.
+func (s *session) completeAccounts(string) []string {
+	if s.accounts != nil {
+		return s.accounts
+	}
+
+	if s.ctx.GlobalBool("keystore.json") && s.ctx.GlobalString("passphrase") == "" && s.passphrase == nil {
+		return nil
+	}
+
+	w, err := openWallet(s.ctx)
+	if err != nil {
+		return nil
+	}
+
+	defer w.Close()
+
+	accounts, err := w.List()
+	if err != nil {
+		return nil
+	}
+
+	s.accounts = make([]string, 0, 2*len(accounts))
+
+	for _, a := range accounts {
+		s.accounts = append(s.accounts, a.Name, hex.EncodeToString(a.PublicKey[:]))
+	}
+
+	return s.accounts
+}
+
+// completeRoutes returns the routes of the node's HTTP API, alongside the
+// routes of the accounts of the keystore.
+func (s *session) completeRoutes(line string) []string {
+	routes := append([]string(nil), consoleRoutes...)
+
+	for _, account := range s.completeAccounts(line) {
+		if len(account) == hex.EncodedLen(32) {
+			routes = append(routes, wctl.RouteAccount+"/"+account)
+		}
+	}
+
+	return routes
+}
+
+func console(c *cli.Context) error {
+	if c.NArg() != 0 {
+		return errors.New("expected 0 argument(s)")
+	}
+
+	s := &session{ctx: c}
+
+	// Completion reads the keystore through the context of the console.
+	c.App.Metadata[sessionKey] = s
+
+	app := newApp(nil)
+	app.HideVersion = true
+	app.Metadata[sessionKey] = s
+	app.Commands = append(consoleCommands(app.Commands), cli.Command{
+		Name:      "get",
+		Usage:     "query a route of the node's HTTP API, pretty-printing the JSON it responds with",
+		ArgsUsage: "<route>",
+		Action:    clientAction(1, consoleGet),
+	}, cli.Command{
+		Name:  "lock",
+		Usage: "forget the passphrase, such that it is prompted for again",
+		Action: func(*cli.Context) error {
+			s.passphrase, s.accounts = nil, nil
+			return nil
+		},
+	}, cli.Command{
+		Name:  "exit",
+		Usage: "close the console",
+		Action: func(*cli.Context) error {
+			return s.rl.Close()
+		},
+	})
+
+	if err := os.MkdirAll(filepath.Dir(c.String("history")), 0700); err != nil {
+		return errors.Wrap(err, "failed to create directory of the console history")
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:            consolePrompt,
+		AutoComplete:      consoleCompleter(s, app.Commands),
+		HistoryFile:       c.String("history"),
+		InterruptPrompt:   "^C",
+		EOFPrompt:         "exit",
+		HistorySearchFold: true,
+		Stdout:            c.App.Writer,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to open console")
+	}
+
+	defer rl.Close()
+
+	s.rl = rl
+	app.Writer, app.ErrWriter = rl.Stdout(), rl.Stdout()
+
+	defer func() {
+		if s.client != nil {
+			s.client.Close()
+		}
+	}()
+
+	globals := globalArgs(c)
+
+	fmt.Fprintf(rl.Stdout(), "Connected to %s:%d. Run 'help' for commands, and 'exit' to close the console.\n",
+		c.GlobalString("api.host"), c.GlobalUint("api.port"))
+
+	for {
+		line, err := rl.Readline()
+
+		switch {
+		case err == readline.ErrInterrupt:
+			if len(line) == 0 {
+				return nil
+			}
+
+			continue
+		case err != nil:
+			// Either stdin was closed, or the console by exit.
+			return nil
+		}
+
+		args := splitLine(line)
+		if len(args) == 0 {
+			continue
+		}
+
+		if err := app.Run(append(append([]string{"wctl"}, globals...), args...)); err != nil {
+			fmt.Fprintln(rl.Stdout(), "error:", err)
+		}
+
+		// Commands may have changed the accounts of the keystore.
+		s.accounts = nil
+	}
+}
+
+func consoleGet(c *cli.Context, client *wctl.Client) error {
+	route := c.Args().Get(0)
+
+	if !strings.HasPrefix(route, "/") {
+		route = "/" + route
+	}
+
+	buf, err := client.Request(route, wctl.ReqGet, nil)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+
+	if err := json.Indent(&out, buf, "", "  "); err != nil {
+		_, err = fmt.Fprintln(c.App.Writer, string(buf))
+		return err
+	}
+
+	_, err = fmt.Fprintln(c.App.Writer, out.String())
+
+	return err
+}
+
+// consoleCommands returns the commands of wctl which may be run in a
+// console, being all but console itself.
+func consoleCommands(commands []cli.Command) []cli.Command {
+	filtered := make([]cli.Command, 0, len(commands))
+
+	for _, cmd := range commands {
+		if cmd.Name != consoleName {
+			filtered = append(filtered, cmd)
+		}
+	}
+
+	return filtered
+}
+
+// consoleCompleter completes the names of commands and their subcommands,
+// the routes given to get, and the names and addresses of accounts as the
+// arguments of all other commands.
+func consoleCompleter(s *session, commands []cli.Command) *readline.PrefixCompleter {
+	items := []readline.PrefixCompleterInterface{readline.PcItem("help")}
+
+	for _, cmd := range commands {
+		items = append(items, commandCompleter(s, cmd))
+	}
+
+	return readline.NewPrefixCompleter(items...)
+}
+
+func commandCompleter(s *session, cmd cli.Command) readline.PrefixCompleterInterface {
+	if cmd.Name == "get" {
+		return readline.PcItem(cmd.Name, readline.PcItemDynamic(s.completeRoutes))
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		children := make([]readline.PrefixCompleterInterface, 0, len(cmd.Subcommands))
+
+		for _, sub := range cmd.Subcommands {
+			children = append(children, commandCompleter(s, sub))
+		}
+
+		return readline.PcItem(cmd.Name, children...)
+	}
+
+	// Complete accounts for as many arguments as the command takes.
+	var args []readline.PrefixCompleterInterface
+
+	for i := strings.Count(cmd.ArgsUsage, "<"); i > 0; i-- {
+		args = []readline.PrefixCompleterInterface{readline.PcItemDynamic(s.completeAccounts, args...)}
+	}
+
+	return readline.PcItem(cmd.Name, args...)
+}
+
+// globalArgs returns the global flags set when opening the console, such
+// that they are given to every command run in it.
+func globalArgs(c *cli.Context) []string {
+	var args []string
+
+	for _, flag := range c.App.Flags {
+		name := strings.Split(flag.GetName(), ",")[0]
+
+		if c.GlobalIsSet(name) {
+			args = append(args, fmt.Sprintf("--%s=%s", name, c.GlobalGeneric(name)))
+		}
+	}
+
+	return args
+}
+
+// splitLine splits a line of the console into arguments separated by
+// spaces, which may be quoted.
+func splitLine(line string) []string {
+	r := csv.NewReader(strings.NewReader(line))
+	r.Comma = ' '
+
+	args, err := r.Read()
+	if err != nil {
+		return strings.Fields(line)
+	}
+
+	filtered := args[:0]
+
+	for _, arg := range args {
+		if arg != "" {
+			filtered = append(filtered, arg)
+		}
+	}
+
+	return filtered
+}
+
+// defaultHistory returns the default file the history of the console is
+// kept in, being ~/.wavelet/wctl_history.
+func defaultHistory() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "wctl_history"
+	}
+
+	return filepath.Join(home, ".wavelet", "wctl_history")
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestSplitLine(t *testing.T) {
+	assert.Equal(t, []string{"wallet", "send", "alice", "bob", "10"}, splitLine("wallet  send alice bob 10 "))
+	assert.Equal(t, []string{"contract", "call", "a b", "c"}, splitLine(`contract call "a b" c`))
+	assert.Empty(t, splitLine("   "))
+}
+
+func TestConsoleGlobalArgs(t *testing.T) {
+	var args []string
+
+	app := newApp(nil)
+	app.Commands = []cli.Command{{
+		Name: "console",
+		Action: func(c *cli.Context) error {
+			args = globalArgs(c)
+			return nil
+		},
+	}}
+
+	assert.NoError(t, app.Run([]string{"wctl", "--api.port", "9001", "--api.https", "console"}))
+	assert.Equal(t, []string{"--api.port=9001", "--api.https=true"}, args)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"io/ioutil"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/wallet"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var contractCommand = cli.Command{
+	Name:  "contract",
+	Usage: "manage smart contracts",
+	Subcommands: []cli.Command{
+		{
+			Name:      "deploy",
+			Usage:     "spawn a smart contract from an account, calling its init function with the given parameters",
+			ArgsUsage: "<name> <file>",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name: "params",
+					Usage: "Parameter of the init function, prefixed by its type: S for a string, B for length-prefixed " +
+						"bytes, 1, 2, 4 or 8 for an unsigned integer of that many bytes, or H for hex-encoded bytes. " +
+						"May be repeated, e.g. --params Sname --params 8100.",
+				},
+				cli.Uint64Flag{
+					Name:  "gas-limit",
+					Value: wctl.DefaultSpawnGasLimit,
+					Usage: "Maximum amount of PERLs to spend on gas.",
+				},
+				cli.Uint64Flag{Name: "gas-deposit", Usage: "Amount of PERLs to deposit into the gas balance of the contract."},
+			},
+			Action: walletAction(2, contractDeploy),
+		},
+		{
+			Name:      "call",
+			Usage:     "call a smart contract function from an account",
+			ArgsUsage: "<name> <contract> <func>",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "params",
+					Usage: "Parameter of the function, prefixed by its type like for deploy. May be repeated.",
+				},
+				cli.Uint64Flag{Name: "amount", Usage: "Amount of PERLs to send to the contract."},
+				cli.Uint64Flag{
+					Name:  "gas-limit",
+					Value: wctl.DefaultSpawnGasLimit,
+					Usage: "Maximum amount of PERLs to spend on gas.",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Call the function against the latest state of the ledger without sending a transaction.",
+				},
+			},
+			Action: walletAction(3, contractCall),
+		},
+	},
+}
+
+func contractDeploy(c *cli.Context, w *wallet.Wallet) error {
+	code, err := ioutil.ReadFile(c.Args().Get(1))
+	if err != nil {
+		return errors.Wrap(err, "failed to read contract code")
+	}
+
+	spawn, err := contractSpawn(c, code)
+	if err != nil {
+		return err
+	}
+
+	res, err := w.Deploy(c.Args().Get(0), spawn)
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("id", res.ID)}, "Deployed smart contract %x.\n", res.ID)
+}
+
+func contractCall(c *cli.Context, w *wallet.Wallet) error {
+	contract, err := decodeAddress(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	fn := wctl.FunctionCall{
+		Name:     c.Args().Get(2),
+		Amount:   c.Uint64("amount"),
+		GasLimit: c.Uint64("gas-limit"),
+	}
+
+	for _, arg := range c.StringSlice("params") {
+		param, err := wctl.ParseParam(arg)
+		if err != nil {
+			return errors.Wrap(err, "invalid parameter")
+		}
+
+		fn.AddParams(param)
+	}
+
+	if !c.Bool("dry-run") {
+		res, err := w.Call(c.Args().Get(0), contract, fn)
+		if err != nil {
+			return err
+		}
+
+		o := output.Object{output.F("id", res.ID), output.F("contract", contract), output.F("func", fn.Name)}
+
+		return printObject(c, o, "Called %s of smart contract %x in transaction %x.\n", fn.Name, contract, res.ID)
+	}
+
+	res, err := w.Simulate(c.Args().Get(0), contract, fn)
+	if err != nil {
+		return err
+	}
+
+	o := output.Object{
+		output.F("result", res.Result),
+		output.F("gas", res.Gas),
+		output.F("queued", res.Queued),
+		output.F("failure", res.Failure),
+	}
+
+	if res.Failure == "" {
+		return printObject(c, o, "Result: %x\nGas: %d\nQueued transactions: %d\n", res.Result, res.Gas, res.Queued)
+	}
+
+	return printObject(c, o, "Result: %x\nGas: %d\nQueued transactions: %d\nFailure: %s\n",
+		res.Result, res.Gas, res.Queued, res.Failure)
+}
+
+// contractSpawn returns the smart contract described by code and the flags
+// of the deploy command.
+func contractSpawn(c *cli.Context, code []byte) (wctl.ContractSpawn, error) {
+	spawn := wctl.ContractSpawn{
+		Code:       code,
+		GasLimit:   c.Uint64("gas-limit"),
+		GasDeposit: c.Uint64("gas-deposit"),
+	}
+
+	for _, arg := range c.StringSlice("params") {
+		param, err := wctl.ParseParam(arg)
+		if err != nil {
+			return spawn, errors.Wrap(err, "invalid init parameter")
+		}
+
+		spawn.AddParams(param)
+	}
+
+	return spawn, nil
+}
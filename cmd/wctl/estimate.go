@@ -0,0 +1,77 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package main
+
+import (
+	"strconv"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wallet"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var estimateCommand = cli.Command{
+	Name:      "estimate",
+	Usage:     "estimate the cost of sending PERLs from an account, optionally invoking a smart contract",
+	ArgsUsage: "<name> <recipient> <amount>",
+	Flags: []cli.Flag{
+		cli.StringFlag{Name: "func", Usage: "Smart contract function to invoke."},
+		cli.Uint64Flag{Name: "gas-limit", Usage: "Maximum amount of PERLs to spend on gas."},
+		cli.Uint64Flag{Name: "gas-deposit", Usage: "Amount of PERLs to deposit into the gas balance of the contract."},
+	},
+	Action: walletAction(3, estimate),
+}
+
+func estimate(c *cli.Context, w *wallet.Wallet) error {
+	recipient, err := decodeAddress(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	amount, err := strconv.ParseUint(c.Args().Get(2), 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid amount")
+	}
+
+	builder := wctl.NewTransfer(recipient).
+		Amount(amount).
+		GasLimit(c.Uint64("gas-limit")).
+		GasDeposit(c.Uint64("gas-deposit"))
+
+	if fn := c.String("func"); fn != "" {
+		builder.Invoke(fn)
+	}
+
+	payload, err := builder.Payload()
+	if err != nil {
+		return err
+	}
+
+	res, err := w.Estimate(c.Args().Get(0), byte(sys.TagTransfer), payload)
+	if err != nil {
+		return err
+	}
+
+	o := output.Object{output.F("fee", res.Fee), output.F("gas", res.Gas), output.F("total", res.Fee+res.Gas)}
+
+	return printObject(c, o, "Fee: %d PERL(s)\nGas: %d PERL(s)\nTotal: %d PERL(s)\n", res.Fee, res.Gas, res.Fee+res.Gas)
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/wallet"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var keysCommand = cli.Command{
+	Name:  "keys",
+	Usage: "manage the JSON key files of the keystore, encrypted with scrypt and AES-GCM",
+	Subcommands: []cli.Command{
+		{
+			Name:      "create",
+			Usage:     "generate a new key file",
+			ArgsUsage: "<name>",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "mnemonic",
+					Usage: "derive the key from a newly generated 24-word mnemonic, which is printed such that the key may be recovered with 'keys recover'",
+				},
+				mnemonicPassphraseFlag,
+			},
+			Action: keysAction(1, true, keysCreate),
+		},
+		{
+			Name:      "recover",
+			Usage:     "recover a key from its mnemonic, read from a file, prompted for in a terminal, or otherwise read from stdin",
+			ArgsUsage: "<name>",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "mnemonic-file",
+					Usage: "file to read the mnemonic from",
+				},
+				mnemonicPassphraseFlag,
+			},
+			Action: keysAction(1, true, keysRecover),
+		},
+		{
+			Name:      "import",
+			Usage:     "import a key from a file holding either a key file, or a hex-encoded private key",
+			ArgsUsage: "<name> <file>",
+			Action:    keysAction(2, true, keysImport),
+		},
+		{
+			Name:      "export",
+			Usage:     "print the key file of a key, as encrypted with the passphrase it was created with",
+			ArgsUsage: "<name>",
+			Action:    keysAction(1, false, keysExport),
+		},
+		{
+			Name:   "list",
+			Usage:  "list all key files",
+			Action: keysAction(0, false, keysList),
+		},
+	},
+}
+
+var mnemonicPassphraseFlag = cli.StringFlag{
+	Name:  "mnemonic-passphrase",
+	Usage: "optional passphrase the key is derived from alongside the mnemonic, distinct from the passphrase of the key file",
+}
+
+// keysAction wraps a keys subcommand taking nargs arguments. The key files
+// are only decrypted, and hence a passphrase only asked for, should
+// decrypt be set.
+func keysAction(
+	nargs int, decrypt bool, fn func(c *cli.Context, keystore *wallet.DirKeystore) error,
+) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.NArg() != nargs {
+			return errors.Errorf("expected %d argument(s): %s", nargs, c.Command.ArgsUsage)
+		}
+
+		var (
+			passphrase []byte
+			err        error
+		)
+
+		if decrypt {
+			if passphrase, err = readPassphrase(c, true); err != nil {
+				return err
+			}
+		}
+
+		keystore, err := wallet.NewKeyFileDirKeystore(c.GlobalString("keystore"), passphrase, scryptParams(c))
+		if err != nil {
+			return err
+		}
+
+		return fn(c, keystore)
+	}
+}
+
+func keysCreate(c *cli.Context, keystore *wallet.DirKeystore) error {
+	if !c.Bool("mnemonic") {
+		_, key, err := edwards25519.GenerateKey(nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate private key")
+		}
+
+		return storeKeyFile(c, keystore, key)
+	}
+
+	mnemonic, err := security.NewMnemonic()
+	if err != nil {
+		return err
+	}
+
+	hd, err := security.HDKeyFromMnemonic(mnemonic, c.String("mnemonic-passphrase"))
+	if err != nil {
+		return err
+	}
+
+	if err := keystore.StoreHD(c.Args().Get(0), hd); err != nil {
+		return err
+	}
+
+	return printStored(c, keystore, hd.PrivateKey, mnemonic)
+}
+
+func keysRecover(c *cli.Context, keystore *wallet.DirKeystore) error {
+	mnemonic, err := readMnemonic(c)
+	if err != nil {
+		return err
+	}
+
+	hd, err := security.HDKeyFromMnemonic(mnemonic, c.String("mnemonic-passphrase"))
+	if err != nil {
+		return err
+	}
+
+	return storeHDKeyFile(c, keystore, hd)
+}
+
+func keysImport(c *cli.Context, keystore *wallet.DirKeystore) error {
+	path, passphrase := c.Args().Get(1), []byte(c.GlobalString("passphrase"))
+
+	// Key files of HD keys are imported along with their chain code, such
+	// that accounts may still be derived from them.
+	if buf, err := ioutil.ReadFile(path); err == nil && security.IsKeyFile(buf) {
+		if hd, err := security.DecryptHDKeyFile(buf, passphrase); err == nil {
+			return storeHDKeyFile(c, keystore, hd)
+		}
+	}
+
+	key, err := wctl.LoadKeyFile(path, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return storeKeyFile(c, keystore, key)
+}
+
+func storeKeyFile(c *cli.Context, keystore *wallet.DirKeystore, key edwards25519.PrivateKey) error {
+	if err := keystore.Store(c.Args().Get(0), key); err != nil {
+		return err
+	}
+
+	return printStored(c, keystore, key, "")
+}
+
+func storeHDKeyFile(c *cli.Context, keystore *wallet.DirKeystore, hd security.HDKey) error {
+	if err := keystore.StoreHD(c.Args().Get(0), hd); err != nil {
+		return err
+	}
+
+	return printStored(c, keystore, hd.PrivateKey, "")
+}
+
+// printStored prints the stored key, alongside the mnemonic it was
+// generated from, if any.
+func printStored(c *cli.Context, keystore *wallet.DirKeystore, key edwards25519.PrivateKey, mnemonic string) error {
+	name := c.Args().Get(0)
+
+	path, err := keystore.Path(name)
+	if err != nil {
+		return err
+	}
+
+	o := output.Object{output.F("name", name), output.F("address", key.Public()), output.F("path", path)}
+
+	if mnemonic == "" {
+		return printObject(c, o, "Stored key %q with address %x at %s.\n", name, key.Public(), path)
+	}
+
+	return printObject(c, append(o, output.F("mnemonic", mnemonic)),
+		"Stored key %q with address %x at %s.\n\n"+
+			"Write down the mnemonic below, and keep it secret. It recovers the key with 'wctl keys recover'.\n\n%s\n",
+		name, key.Public(), path, mnemonic)
+}
+
+func keysExport(c *cli.Context, keystore *wallet.DirKeystore) error {
+	buf, err := readKeyFile(keystore, c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("name", c.Args().Get(0)), output.F("key_file", string(buf))}, "%s\n", buf)
+}
+
+func keysList(c *cli.Context, keystore *wallet.DirKeystore) error {
+	names, err := keystore.Names()
+	if err != nil {
+		return err
+	}
+
+	list := output.NewList("name", "address")
+
+	for _, name := range names {
+		buf, err := readKeyFile(keystore, name)
+		if err != nil {
+			return err
+		}
+
+		address, err := security.KeyFileAddress(buf)
+		if err != nil {
+			return errors.Wrapf(err, "invalid key file of %q", name)
+		}
+
+		list.Add(name, address)
+	}
+
+	return printList(c, list)
+}
+
+// readKeyFile reads the key file of name, without decrypting it.
+func readKeyFile(keystore *wallet.DirKeystore, name string) ([]byte, error) {
+	path, err := keystore.Path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, errors.Wrapf(wallet.ErrAccountNotFound, "%q", name)
+	}
+
+	return buf, err
+}
+
+// readMnemonic reads a mnemonic from the file given by --mnemonic-file,
+// prompts for it should stdin be a terminal or wctl run a console, or
+// otherwise reads it from stdin.
+func readMnemonic(c *cli.Context) (string, error) {
+	if path := c.String("mnemonic-file"); path != "" {
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read mnemonic")
+		}
+
+		return string(buf), nil
+	}
+
+	if s := sessionOf(c); s != nil {
+		buf, err := s.rl.ReadPassword("Mnemonic: ")
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read mnemonic")
+		}
+
+		return string(buf), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	if !terminal.IsTerminal(fd) {
+		buf, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read mnemonic")
+		}
+
+		return string(buf), nil
+	}
+
+	fmt.Fprint(os.Stderr, "Mnemonic: ")
+	defer fmt.Fprintln(os.Stderr)
+
+	buf, err := terminal.ReadPassword(fd)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read mnemonic")
+	}
+
+	return string(buf), nil
+}
+
+// readPassphrase returns the passphrase given by the global flags, or
+// prompts for it should none be given and stdin be a terminal, such that it
+// need not be left in the shell history. The passphrase is prompted for
+// twice should confirm be set. Within a console, the passphrase is only
+// prompted for once, and remembered by the session.
+func readPassphrase(c *cli.Context, confirm bool) ([]byte, error) {
+	if passphrase := c.GlobalString("passphrase"); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	s := sessionOf(c)
+
+	if s != nil && s.passphrase != nil && !confirm {
+		return s.passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	if !terminal.IsTerminal(fd) {
+		return nil, errors.New("a passphrase is required: pass --passphrase, or run wctl in a terminal to be prompted")
+	}
+
+	prompt := func(msg string) ([]byte, error) {
+		fmt.Fprint(os.Stderr, msg)
+		defer fmt.Fprintln(os.Stderr)
+
+		return terminal.ReadPassword(fd)
+	}
+
+	if s != nil {
+		prompt = s.rl.ReadPassword
+	}
+
+	passphrase, err := prompt("Passphrase: ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read passphrase")
+	}
+
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+
+	if confirm {
+		again, err := prompt("Repeat passphrase: ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read passphrase")
+		}
+
+		if string(again) != string(passphrase) {
+			return nil, errors.New("passphrases do not match")
+		}
+	}
+
+	if s != nil {
+		s.passphrase = passphrase
+	}
+
+	return passphrase, nil
+}
@@ -0,0 +1,390 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Command wctl is a command-line client of a Wavelet node's HTTP API.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wallet"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func main() {
+	if err := Run(os.Args, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Run runs wctl with the given arguments, writing all output to stdout.
+func Run(args []string, stdout io.Writer) error {
+	return newApp(stdout).Run(args)
+}
+
+// newApp returns the wctl command-line application, writing all output to
+// stdout.
+func newApp(stdout io.Writer) *cli.App {
+	app := cli.NewApp()
+
+	app.Name = "wctl"
+	app.Author = "Perlin"
+	app.Email = "support@perlin.net"
+	app.Version = sys.Version
+	app.Usage = "a command-line client for Wavelet nodes"
+	app.Writer = stdout
+	app.ErrWriter = stdout
+	app.Metadata = make(map[string]interface{})
+
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:   "output",
+			Value:  string(output.Text),
+			Usage:  "Format to print results in: text, json, yaml, table, or csv.",
+			EnvVar: "WCTL_OUTPUT",
+		},
+		cli.StringFlag{
+			Name:   "api.host",
+			Value:  "127.0.0.1",
+			Usage:  "Host of the node's HTTP API.",
+			EnvVar: "WCTL_API_HOST",
+		},
+		cli.UintFlag{
+			Name:   "api.port",
+			Value:  9000,
+			Usage:  "Port of the node's HTTP API.",
+			EnvVar: "WCTL_API_PORT",
+		},
+		cli.StringFlag{
+			Name:   "api.secret",
+			Usage:  "Shared secret to access restricted APIs of the node.",
+			EnvVar: "WCTL_API_SECRET",
+		},
+		cli.BoolFlag{
+			Name:   "api.https",
+			Usage:  "Connect to the node's HTTP API over HTTPS.",
+			EnvVar: "WCTL_API_HTTPS",
+		},
+		cli.StringFlag{
+			Name:   "api.tls.ca",
+			Usage:  "PEM-encoded bundle of certificate authorities to verify the node's certificate against.",
+			EnvVar: "WCTL_API_TLS_CA",
+		},
+		cli.StringFlag{
+			Name:   "api.tls.cert",
+			Usage:  "PEM-encoded client certificate to authenticate to the node with.",
+			EnvVar: "WCTL_API_TLS_CERT",
+		},
+		cli.StringFlag{
+			Name:   "api.tls.key",
+			Usage:  "PEM-encoded private key of the client certificate.",
+			EnvVar: "WCTL_API_TLS_KEY",
+		},
+		cli.StringFlag{
+			Name: "api.proxy",
+			Usage: "URL of the HTTP or SOCKS5 proxy to connect to the node through, such as socks5://host:1080. " +
+				"Taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables if blank.",
+			EnvVar: "WCTL_API_PROXY",
+		},
+		cli.StringSliceFlag{
+			Name: "api.tls.pin",
+			Usage: "SHA-256 fingerprint of a certificate the node may present, which is then trusted even if " +
+				"self-signed. May be given multiple times.",
+			EnvVar: "WCTL_API_TLS_PINS",
+		},
+		cli.DurationFlag{
+			Name:   "timeout",
+			Value:  5 * time.Second,
+			Usage:  "Timeout of requests to the node.",
+			EnvVar: "WCTL_TIMEOUT",
+		},
+		cli.StringFlag{
+			Name:   "keystore",
+			Value:  defaultKeystore(),
+			Usage:  "Directory the private keys of accounts are stored in.",
+			EnvVar: "WCTL_KEYSTORE",
+		},
+		cli.BoolFlag{
+			Name: "keystore.json",
+			Usage: "Store private keys in the keystore directory as JSON key files, encrypted with scrypt and AES-GCM. " +
+				"The passphrase is prompted for should none be given.",
+			EnvVar: "WCTL_KEYSTORE_JSON",
+		},
+		cli.BoolFlag{
+			Name:   "keyring",
+			Usage:  "Store private keys in the keyring of the operating system instead of the keystore directory.",
+			EnvVar: "WCTL_KEYRING",
+		},
+		cli.StringFlag{
+			Name:   "keyring.service",
+			Value:  wallet.DefaultKeyringService,
+			Usage:  "Service of the keyring private keys are stored under.",
+			EnvVar: "WCTL_KEYRING_SERVICE",
+		},
+		cli.StringFlag{
+			Name:   "passphrase",
+			Usage:  "Passphrase to encrypt private keys in the keystore directory with. Keys are stored unencrypted if blank.",
+			EnvVar: "WCTL_PASSPHRASE",
+		},
+		cli.UintFlag{
+			Name:   "kdf.time",
+			Value:  uint(security.DefaultParams.Time),
+			Usage:  "Number of argon2id passes when encrypting private keys.",
+			EnvVar: "WCTL_KDF_TIME",
+		},
+		cli.UintFlag{
+			Name:   "kdf.memory",
+			Value:  uint(security.DefaultParams.Memory),
+			Usage:  "Memory in KiB used by argon2id when encrypting private keys.",
+			EnvVar: "WCTL_KDF_MEMORY",
+		},
+		cli.UintFlag{
+			Name:   "kdf.threads",
+			Value:  uint(security.DefaultParams.Threads),
+			Usage:  "Number of argon2id threads when encrypting private keys.",
+			EnvVar: "WCTL_KDF_THREADS",
+		},
+		cli.UintFlag{
+			Name:   "scrypt.n",
+			Value:  uint(security.DefaultScryptParams.N),
+			Usage:  "CPU and memory cost of scrypt when encrypting JSON key files.",
+			EnvVar: "WCTL_SCRYPT_N",
+		},
+		cli.UintFlag{
+			Name:   "scrypt.r",
+			Value:  uint(security.DefaultScryptParams.R),
+			Usage:  "Block size of scrypt when encrypting JSON key files.",
+			EnvVar: "WCTL_SCRYPT_R",
+		},
+		cli.UintFlag{
+			Name:   "scrypt.p",
+			Value:  uint(security.DefaultScryptParams.P),
+			Usage:  "Parallelization of scrypt when encrypting JSON key files.",
+			EnvVar: "WCTL_SCRYPT_P",
+		},
+		cli.UintFlag{
+			Name: "account-index",
+			Usage: "Sign with the key of the account at this index, derived from the key of the account named in the " +
+				"command. Requires --keystore.json, and accounts created from a mnemonic.",
+			EnvVar: "WCTL_ACCOUNT_INDEX",
+		},
+	}
+
+	app.Commands = []cli.Command{
+		walletCommand,
+		keysCommand,
+		estimateCommand,
+		signCommand,
+		broadcastCommand,
+		contractCommand,
+		multisigCommand,
+		thresholdCommand,
+		mempoolCommand,
+		validatorsCommand,
+		accountCommand,
+		txCommand,
+		consoleCommand(),
+	}
+
+	app.Before = func(c *cli.Context) error {
+		_, err := output.ParseFormat(c.GlobalString("output"))
+		return err
+	}
+
+	app.CommandNotFound = func(c *cli.Context, command string) {
+		fmt.Fprintf(c.App.Writer, "No such command %q. Run `wctl help` for usage.\n", command)
+	}
+
+	return app
+}
+
+// defaultKeystore returns the default directory of the keystore, being
+// ~/.wavelet/keystore.
+func defaultKeystore() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "keystore"
+	}
+
+	return filepath.Join(home, ".wavelet", "keystore")
+}
+
+// clientConfig returns the wctl.Config described by the global flags,
+// without a private key.
+func clientConfig(c *cli.Context) wctl.Config {
+	return wctl.Config{
+		APIHost:   c.GlobalString("api.host"),
+		APIPort:   uint16(c.GlobalUint("api.port")),
+		APISecret: c.GlobalString("api.secret"),
+		UseHTTPS:  c.GlobalBool("api.https"),
+		Timeout:   c.GlobalDuration("timeout"),
+
+		TLSCAFile:          c.GlobalString("api.tls.ca"),
+		TLSCertFile:        c.GlobalString("api.tls.cert"),
+		TLSKeyFile:         c.GlobalString("api.tls.key"),
+		PinnedCertificates: c.GlobalStringSlice("api.tls.pin"),
+		ProxyURL:           c.GlobalString("api.proxy"),
+	}
+}
+
+// clientAction returns an action calling fn with a client of the node given
+// by the global flags, checking that nargs arguments were given.
+func clientAction(nargs int, fn func(c *cli.Context, client *wctl.Client) error) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.NArg() != nargs {
+			return errors.Errorf("expected %d argument(s): %s", nargs, c.Command.ArgsUsage)
+		}
+
+		client, release, err := dial(c)
+		if err != nil {
+			return err
+		}
+
+		defer release()
+
+		return fn(c, client)
+	}
+}
+
+// dial returns a client of the node given by the global flags, and a
+// function releasing it. Commands run in a console share the client of the
+// console's session.
+func dial(c *cli.Context) (*wctl.Client, func(), error) {
+	if s := sessionOf(c); s != nil {
+		client, err := s.dial()
+		return client, func() {}, err
+	}
+
+	client, err := wctl.NewClient(clientConfig(c))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, client.Close, nil
+}
+
+// printObject prints the result of a command in the output format given by
+// the global flags, or as text formatted by format should it be text.
+func printObject(c *cli.Context, o output.Object, format string, args ...interface{}) error {
+	f := output.Format(c.GlobalString("output"))
+
+	if f == output.Text {
+		_, err := fmt.Fprintf(c.App.Writer, format, args...)
+		return err
+	}
+
+	return f.WriteObject(c.App.Writer, o)
+}
+
+// printList prints the results of a command in the output format given by
+// the global flags.
+func printList(c *cli.Context, l *output.List) error {
+	return output.Format(c.GlobalString("output")).WriteList(c.App.Writer, l)
+}
+
+// kdfParams returns the argon2id parameters given by the global flags.
+func kdfParams(c *cli.Context) security.Params {
+	return security.Params{
+		Time:    uint32(c.GlobalUint("kdf.time")),
+		Memory:  uint32(c.GlobalUint("kdf.memory")),
+		Threads: uint8(c.GlobalUint("kdf.threads")),
+	}
+}
+
+// scryptParams returns the scrypt parameters given by the global flags.
+func scryptParams(c *cli.Context) security.ScryptParams {
+	return security.ScryptParams{
+		N: int(c.GlobalUint("scrypt.n")),
+		R: int(c.GlobalUint("scrypt.r")),
+		P: int(c.GlobalUint("scrypt.p")),
+	}
+}
+
+// openKeystore opens the keystore given by the global flags. Keystore
+// directories are encrypted should a passphrase be given, or should keys be
+// stored as JSON key files. Keys are derived at the account index, should
+// one be given.
+func openKeystore(c *cli.Context) (wallet.Keystore, error) {
+	if c.GlobalIsSet("account-index") && !c.GlobalBool("keystore.json") {
+		return nil, errors.New("--account-index requires --keystore.json")
+	}
+
+	if c.GlobalBool("keyring") {
+		ring, err := wallet.SystemKeyring()
+		if err != nil {
+			return nil, err
+		}
+
+		return wallet.NewKeyringKeystore(ring, c.GlobalString("keyring.service")), nil
+	}
+
+	var (
+		keystore *wallet.DirKeystore
+		err      error
+	)
+
+	passphrase := c.GlobalString("passphrase")
+
+	switch {
+	case c.GlobalBool("keystore.json"):
+		var prompted []byte
+
+		if prompted, err = readPassphrase(c, false); err != nil {
+			return nil, err
+		}
+
+		if keystore, err = wallet.NewKeyFileDirKeystore(c.GlobalString("keystore"), prompted, scryptParams(c)); err != nil {
+			return nil, err
+		}
+
+		if c.GlobalIsSet("account-index") {
+			return wallet.NewAccountKeystore(keystore, uint32(c.GlobalUint("account-index"))), nil
+		}
+	case passphrase != "":
+		keystore, err = wallet.NewEncryptedDirKeystore(c.GlobalString("keystore"), []byte(passphrase), kdfParams(c))
+	default:
+		keystore, err = wallet.NewDirKeystore(c.GlobalString("keystore"))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keystore, nil
+}
+
+// openWallet opens the wallet of the keystore given by the global flags.
+func openWallet(c *cli.Context) (*wallet.Wallet, error) {
+	keystore, err := openKeystore(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet.New(keystore, wallet.DialConfig(clientConfig(c))), nil
+}
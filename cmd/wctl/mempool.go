@@ -0,0 +1,104 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package main
+
+import (
+	"fmt"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/wctl"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var mempoolCommand = cli.Command{
+	Name:  "mempool",
+	Usage: "inspect the transactions pending finalization on the node",
+	Subcommands: []cli.Command{
+		{
+			Name:  "list",
+			Usage: "list the transactions pending finalization, in the order they would be proposed in",
+			Flags: []cli.Flag{
+				cli.Uint64Flag{Name: "offset", Usage: "Number of transactions to skip."},
+				cli.Uint64Flag{Name: "limit", Usage: "Maximum number of transactions to list."},
+			},
+			Action: clientAction(0, mempoolList),
+		},
+		{
+			Name:   "count",
+			Usage:  "count the transactions pending finalization",
+			Action: clientAction(0, mempoolCount),
+		},
+		{
+			Name:      "contains",
+			Usage:     "check whether a transaction is pending finalization",
+			ArgsUsage: "<id>",
+			Action:    clientAction(1, mempoolContains),
+		},
+	},
+}
+
+func mempoolList(c *cli.Context, client *wctl.Client) error {
+	txs, err := client.ListMempool(c.Uint64("offset"), c.Uint64("limit"))
+	if err != nil {
+		return err
+	}
+
+	if len(txs) == 0 && output.Format(c.GlobalString("output")) == output.Text {
+		fmt.Fprintln(c.App.Writer, "No transactions are pending.")
+		return nil
+	}
+
+	list := output.NewList("id", "sender", "nonce", "block", "tag")
+
+	for _, tx := range txs {
+		list.Add(tx.ID, tx.Sender, tx.Nonce, tx.Height, tx.Tag)
+	}
+
+	return printList(c, list)
+}
+
+func mempoolCount(c *cli.Context, client *wctl.Client) error {
+	count, err := client.CountMempool()
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("count", count.Count), output.F("missing", count.Missing)},
+		"Pending: %d transaction(s)\nMissing: %d transaction(s)\n", count.Count, count.Missing)
+}
+
+func mempoolContains(c *cli.Context, client *wctl.Client) error {
+	id, err := decodeTxID(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	contains, err := client.MempoolContains(id)
+	if err != nil {
+		return err
+	}
+
+	o := output.Object{output.F("id", id), output.F("pending", contains)}
+
+	if contains {
+		return printObject(c, o, "Transaction %x is pending.\n", id)
+	}
+
+	return printObject(c, o, "Transaction %x is not pending.\n", id)
+}
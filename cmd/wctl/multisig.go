@@ -0,0 +1,156 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/wallet"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var multisigCommand = cli.Command{
+	Name:  "multisig",
+	Usage: "manage accounts whose PERLs are only moved once enough of their participants approve",
+	Subcommands: []cli.Command{
+		{
+			Name:      "create",
+			Usage:     "create a multisig account from an account, whose address is the ID of the transaction",
+			ArgsUsage: "<name> <threshold> <participant>[,<participant>...]",
+			Action:    walletAction(3, multisigCreate),
+		},
+		{
+			Name:      "propose",
+			Usage:     "propose transferring PERLs from a multisig account as its participant, approving the transfer",
+			ArgsUsage: "<name> <multisig> <recipient> <amount>",
+			Action:    walletAction(4, multisigPropose),
+		},
+		{
+			Name:      "approve",
+			Usage:     "approve a transfer proposed from a multisig account as its participant",
+			ArgsUsage: "<name> <proposal>",
+			Action:    walletAction(2, multisigApprove),
+		},
+		{
+			Name:      "execute",
+			Usage:     "transfer the PERLs of a proposal approved by enough participants of its multisig account",
+			ArgsUsage: "<name> <proposal>",
+			Action:    walletAction(2, multisigExecute),
+		},
+		{
+			Name:      "cancel",
+			Usage:     "cancel a transfer proposed from a multisig account by the account",
+			ArgsUsage: "<name> <proposal>",
+			Action:    walletAction(2, multisigCancel),
+		},
+	},
+}
+
+func multisigCreate(c *cli.Context, w *wallet.Wallet) error {
+	threshold, err := strconv.ParseUint(c.Args().Get(1), 10, 8)
+	if err != nil {
+		return errors.Wrap(err, "invalid threshold")
+	}
+
+	var participants [][32]byte
+
+	for _, s := range strings.Split(c.Args().Get(2), ",") {
+		participant, err := decodeAddress(s)
+		if err != nil {
+			return errors.Wrapf(err, "invalid participant %q", s)
+		}
+
+		participants = append(participants, participant)
+	}
+
+	res, err := w.CreateMultisig(c.Args().Get(0), participants, uint8(threshold))
+	if err != nil {
+		return err
+	}
+
+	o := output.Object{output.F("id", res.ID), output.F("threshold", threshold), output.F("participants", len(participants))}
+
+	return printObject(c, o, "Created multisig account %x, requiring %d of %d participants to approve transfers.\n",
+		res.ID, threshold, len(participants))
+}
+
+func multisigPropose(c *cli.Context, w *wallet.Wallet) error {
+	account, err := decodeAddress(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	recipient, err := decodeAddress(c.Args().Get(2))
+	if err != nil {
+		return err
+	}
+
+	amount, err := strconv.ParseUint(c.Args().Get(3), 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid amount")
+	}
+
+	res, err := w.ProposeMultisig(c.Args().Get(0), account, recipient, amount)
+	if err != nil {
+		return err
+	}
+
+	o := output.Object{
+		output.F("id", res.ID), output.F("multisig", account), output.F("recipient", recipient),
+		output.F("amount", amount),
+	}
+
+	return printObject(c, o, "Proposed sending %d PERL(s) from %x to %x in proposal %x.\n",
+		amount, account, recipient, res.ID)
+}
+
+func multisigApprove(c *cli.Context, w *wallet.Wallet) error {
+	return multisigProposalAction(c, w.ApproveMultisig, "Approved proposal %x in transaction %x.\n")
+}
+
+func multisigExecute(c *cli.Context, w *wallet.Wallet) error {
+	return multisigProposalAction(c, w.ExecuteMultisig, "Executed proposal %x in transaction %x.\n")
+}
+
+func multisigCancel(c *cli.Context, w *wallet.Wallet) error {
+	return multisigProposalAction(c, w.CancelMultisig, "Cancelled proposal %x in transaction %x.\n")
+}
+
+// multisigProposalAction acts upon the proposal given as the second argument
+// of c from the account named by the first.
+func multisigProposalAction(
+	c *cli.Context, fn func(name string, proposal [32]byte) (*wctl.TxResponse, error), format string,
+) error {
+	proposal, err := decodeTxID(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	res, err := fn(c.Args().Get(0), proposal)
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("id", res.ID), output.F("proposal", proposal)}, format, proposal, res.ID)
+}
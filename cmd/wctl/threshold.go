@@ -0,0 +1,252 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/security/threshold"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wallet"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// A validator keeps its node key for consensus, but may split the key of the
+// account it stakes and receives delegations and rewards through amongst
+// several hosts, such that no single host may move its PERLs. Each host
+// serves its share with `wctl threshold serve`, and transactions of the
+// account are signed with `wctl threshold sign` before being broadcasted with
+// `wctl broadcast`.
+var thresholdCommand = cli.Command{
+	Name:  "threshold",
+	Usage: "sign transactions of an account whose key is split amongst several hosts",
+	Subcommands: []cli.Command{
+		{
+			Name: "split",
+			Usage: "split the key of an account into shares, any threshold of which may sign, writing them and " +
+				"their group into a directory. The key should be removed from the keystore afterwards",
+			ArgsUsage: "<name> <threshold> <shares> <dir>",
+			Action:    walletAction(4, thresholdSplit),
+		},
+		{
+			Name:      "serve",
+			Usage:     "serve a key share to the coordinators of signing sessions",
+			ArgsUsage: "<share file>",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "addr", Value: ":3100", Usage: "Address to serve the key share at."},
+				cli.StringFlag{
+					Name:   "token",
+					Usage:  "Bearer token coordinators must present.",
+					EnvVar: "WCTL_THRESHOLD_TOKEN",
+				},
+			},
+			Action: thresholdServe,
+		},
+		{
+			Name:      "sign",
+			Usage:     "sign a transaction of an account whose key is split into a file, to be broadcasted by `wctl broadcast`",
+			ArgsUsage: "<group file> <tag> <hex payload> <file>",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "signatory",
+					Usage: "Participant to sign with, given as <id>=<url>. May be repeated.",
+				},
+				cli.StringFlag{
+					Name:   "token",
+					Usage:  "Bearer token to present to signatories.",
+					EnvVar: "WCTL_THRESHOLD_TOKEN",
+				},
+				cli.Uint64Flag{Name: "nonce", Usage: "Nonce of the transaction. Defaults to the current time in nanoseconds."},
+				cli.Uint64Flag{Name: "block", Usage: "Index of the block the transaction is created at."},
+			},
+			Action: thresholdSign,
+		},
+	},
+}
+
+func thresholdSplit(c *cli.Context, w *wallet.Wallet) error {
+	t, err := strconv.ParseUint(c.Args().Get(1), 10, 16)
+	if err != nil {
+		return errors.Wrap(err, "invalid threshold")
+	}
+
+	n, err := strconv.ParseUint(c.Args().Get(2), 10, 16)
+	if err != nil {
+		return errors.Wrap(err, "invalid number of shares")
+	}
+
+	key, err := w.Keystore().Load(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	shares, group, err := threshold.Split(key, int(t), int(n), nil)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := readPassphrase(c, true)
+	if err != nil {
+		return err
+	}
+
+	dir := c.Args().Get(3)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrap(err, "failed to create directory")
+	}
+
+	buf, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "group.json"), buf, 0644); err != nil {
+		return errors.Wrap(err, "failed to write group")
+	}
+
+	for _, share := range shares {
+		buf, err := security.Encrypt(share.Marshal(), passphrase, kdfParams(c))
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("share-%d", share.ID))
+		if err := ioutil.WriteFile(path, buf, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write share %d", share.ID)
+		}
+	}
+
+	o := output.Object{
+		output.F("public_key", group.PublicKey), output.F("threshold", t), output.F("shares", n), output.F("dir", dir),
+	}
+
+	return printObject(c, o, "Split the key of %x into %d shares, any %d of which may sign, in %s.\n",
+		group.PublicKey, n, t, dir)
+}
+
+func thresholdServe(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.Errorf("expected 1 argument(s): %s", c.Command.ArgsUsage)
+	}
+
+	token := c.String("token")
+	if token == "" {
+		return errors.New("a token must be given for coordinators to present")
+	}
+
+	buf, err := ioutil.ReadFile(c.Args().Get(0))
+	if err != nil {
+		return errors.Wrap(err, "failed to read key share")
+	}
+
+	passphrase, err := readPassphrase(c, false)
+	if err != nil {
+		return err
+	}
+
+	if buf, err = security.Decrypt(buf, passphrase); err != nil {
+		return errors.Wrap(err, "failed to decrypt key share")
+	}
+
+	share, err := threshold.UnmarshalKeyShare(buf)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.App.Writer, "Serving share %d of %x at %s.\n", share.ID, share.GroupKey, c.String("addr"))
+
+	return http.ListenAndServe(c.String("addr"), threshold.NewHandler(threshold.NewParticipant(share), token))
+}
+
+func thresholdSign(c *cli.Context) error {
+	if c.NArg() != 4 {
+		return errors.Errorf("expected 4 argument(s): %s", c.Command.ArgsUsage)
+	}
+
+	buf, err := ioutil.ReadFile(c.Args().Get(0))
+	if err != nil {
+		return errors.Wrap(err, "failed to read group")
+	}
+
+	var group threshold.Group
+	if err := json.Unmarshal(buf, &group); err != nil {
+		return errors.Wrap(err, "invalid group")
+	}
+
+	tag, ok := sys.TagLabels[c.Args().Get(1)]
+	if !ok {
+		return errors.Errorf("unknown tag %q", c.Args().Get(1))
+	}
+
+	payload, err := hex.DecodeString(c.Args().Get(2))
+	if err != nil {
+		return errors.Wrap(err, "invalid payload")
+	}
+
+	var signatories []threshold.Signatory
+
+	for _, s := range c.StringSlice("signatory") {
+		fields := strings.SplitN(s, "=", 2)
+		if len(fields) != 2 {
+			return errors.Errorf("signatory %q must be given as <id>=<url>", s)
+		}
+
+		id, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil {
+			return errors.Wrapf(err, "invalid ID of signatory %q", s)
+		}
+
+		signatories = append(signatories, threshold.NewRemote(uint16(id), fields[1], c.String("token")))
+	}
+
+	coordinator, err := threshold.NewCoordinator(group, signatories...)
+	if err != nil {
+		return err
+	}
+
+	req, err := wctl.SignTransaction(coordinator, c.Uint64("nonce"), c.Uint64("block"), byte(tag), payload)
+	if err != nil {
+		return err
+	}
+
+	if buf, err = req.MarshalJSON(); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(c.Args().Get(3), buf, 0600); err != nil {
+		return errors.Wrap(err, "failed to write signed transaction")
+	}
+
+	o := output.Object{output.F("id", req.ID()), output.F("sender", req.Sender), output.F("file", c.Args().Get(3))}
+
+	return printObject(c, o, "Signed transaction %x of %x into %s.\n", req.ID(), req.Sender, c.Args().Get(3))
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package main
+
+import (
+	"io/ioutil"
+	"strconv"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/wallet"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var signCommand = cli.Command{
+	Name:      "sign",
+	Usage:     "sign a transfer of PERLs from an account into a file, without connecting to the node",
+	ArgsUsage: "<name> <recipient> <amount> <file>",
+	Flags: []cli.Flag{
+		cli.StringFlag{Name: "func", Usage: "Smart contract function to invoke."},
+		cli.Uint64Flag{Name: "gas-limit", Usage: "Maximum amount of PERLs to spend on gas."},
+		cli.Uint64Flag{Name: "gas-deposit", Usage: "Amount of PERLs to deposit into the gas balance of the contract."},
+		cli.Uint64Flag{Name: "nonce", Usage: "Nonce of the transaction. Defaults to the current time in nanoseconds."},
+		cli.Uint64Flag{Name: "block", Usage: "Index of the block the transaction is created at."},
+	},
+	Action: walletAction(4, sign),
+}
+
+var broadcastCommand = cli.Command{
+	Name:      "broadcast",
+	Usage:     "broadcast a transaction signed by `wctl sign` to the node",
+	ArgsUsage: "<file>",
+	Action:    broadcast,
+}
+
+func sign(c *cli.Context, w *wallet.Wallet) error {
+	recipient, err := decodeAddress(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	amount, err := strconv.ParseUint(c.Args().Get(2), 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid amount")
+	}
+
+	key, err := w.Keystore().Load(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	builder := wctl.NewTransfer(recipient).
+		Amount(amount).
+		GasLimit(c.Uint64("gas-limit")).
+		GasDeposit(c.Uint64("gas-deposit")).
+		Nonce(c.Uint64("nonce")).
+		Block(c.Uint64("block"))
+
+	if fn := c.String("func"); fn != "" {
+		builder.Invoke(fn)
+	}
+
+	req, err := builder.Sign(key)
+	if err != nil {
+		return err
+	}
+
+	buf, err := req.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(c.Args().Get(3), buf, 0600); err != nil {
+		return errors.Wrap(err, "failed to write signed transaction")
+	}
+
+	o := output.Object{output.F("id", req.ID()), output.F("file", c.Args().Get(3))}
+
+	return printObject(c, o, "Signed transaction %x into %s.\n", req.ID(), c.Args().Get(3))
+}
+
+func broadcast(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.Errorf("expected 1 argument(s): %s", c.Command.ArgsUsage)
+	}
+
+	buf, err := ioutil.ReadFile(c.Args().Get(0))
+	if err != nil {
+		return errors.Wrap(err, "failed to read signed transaction")
+	}
+
+	var req wctl.TxRequest
+
+	if err := req.UnmarshalJSON(buf); err != nil {
+		return errors.Wrap(err, "invalid signed transaction")
+	}
+
+	client, release, err := dial(c)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	res, err := client.BroadcastTransaction(&req)
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("id", res.ID)}, "Broadcasted transaction %x.\n", res.ID)
+}
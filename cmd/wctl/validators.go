@@ -0,0 +1,59 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package main
+
+import (
+	"fmt"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/wctl"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var validatorsCommand = cli.Command{
+	Name:   "validators",
+	Usage:  "list the validators of the ledger, along with their stakes and uptime as observed by the node",
+	Action: clientAction(0, validatorsList),
+}
+
+func validatorsList(c *cli.Context, client *wctl.Client) error {
+	res, err := client.GetValidators()
+	if err != nil {
+		return err
+	}
+
+	if len(res.Validators) == 0 && output.Format(c.GlobalString("output")) == output.Text {
+		fmt.Fprintln(c.App.Writer, "No accounts are validating.")
+		return nil
+	}
+
+	list := output.NewList("public_key", "stake", "delegated", "last_active", "connected", "uptime")
+
+	for _, v := range res.Validators {
+		// Validators never seen active are listed with no last activity.
+		var lastActive interface{}
+		if v.LastActive != nil {
+			lastActive = *v.LastActive
+		}
+
+		list.Add(v.PublicKey, v.Stake, v.Delegated, lastActive, v.Connected, v.Uptime)
+	}
+
+	return printList(c, list)
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/wallet"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var walletCommand = cli.Command{
+	Name:  "wallet",
+	Usage: "manage the named accounts of the keystore",
+	Subcommands: []cli.Command{
+		{
+			Name:      "create",
+			Usage:     "generate a new account",
+			ArgsUsage: "<name>",
+			Action:    walletAction(1, walletCreate),
+		},
+		{
+			Name:      "import",
+			Usage:     "import an account from its hex-encoded private key, which may be encrypted by export",
+			ArgsUsage: "<name> <private key>",
+			Action:    walletAction(2, walletImport),
+		},
+		{
+			Name:      "export",
+			Usage:     "print the private key of an account, hex-encoded and encrypted with the passphrase",
+			ArgsUsage: "<name>",
+			Action:    walletAction(1, walletExport),
+		},
+		{
+			Name:      "remove",
+			Usage:     "delete an account from the keystore",
+			ArgsUsage: "<name>",
+			Action:    walletAction(1, walletRemove),
+		},
+		{
+			Name:   "list",
+			Usage:  "list all accounts",
+			Action: walletAction(0, walletList),
+		},
+		{
+			Name:      "balance",
+			Usage:     "query the balances of accounts, or of all accounts if none are given",
+			ArgsUsage: "[name...]",
+			Action:    walletAction(-1, walletBalance),
+		},
+		{
+			Name:      "send",
+			Usage:     "send PERLs from an account to a recipient",
+			ArgsUsage: "<name> <recipient> <amount>",
+			Action:    walletAction(3, walletSend),
+		},
+		{
+			Name:      "receive",
+			Usage:     "print the address PERLs may be sent to an account with",
+			ArgsUsage: "<name>",
+			Action:    walletAction(1, walletReceive),
+		},
+		{
+			Name:      "history",
+			Usage:     "list the transactions sent by an account",
+			ArgsUsage: "<name>",
+			Flags: []cli.Flag{
+				cli.Uint64Flag{Name: "offset", Usage: "Number of transactions to skip."},
+				cli.Uint64Flag{Name: "limit", Usage: "Maximum number of transactions to list."},
+			},
+			Action: walletAction(1, walletHistory),
+		},
+	},
+}
+
+// walletAction wraps a wallet subcommand taking nargs arguments, or any
+// number of arguments should nargs be negative.
+func walletAction(nargs int, fn func(c *cli.Context, w *wallet.Wallet) error) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if nargs >= 0 && c.NArg() != nargs {
+			return errors.Errorf("expected %d argument(s): %s", nargs, c.Command.ArgsUsage)
+		}
+
+		w, err := openWallet(c)
+		if err != nil {
+			return err
+		}
+
+		defer w.Close()
+
+		return fn(c, w)
+	}
+}
+
+func walletCreate(c *cli.Context, w *wallet.Wallet) error {
+	a, err := w.Create(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("name", a.Name), output.F("address", a.PublicKey)},
+		"Created account %q with address %x.\n", a.Name, a.PublicKey)
+}
+
+func walletImport(c *cli.Context, w *wallet.Wallet) error {
+	buf, err := hex.DecodeString(c.Args().Get(1))
+	if err != nil {
+		return errors.Wrap(err, "private key must be hex-encoded")
+	}
+
+	var key edwards25519.PrivateKey
+
+	switch {
+	case security.IsEncrypted(buf):
+		passphrase, err := readPassphrase(c, false)
+		if err != nil {
+			return err
+		}
+
+		if key, err = security.DecryptKey(buf, passphrase); err != nil {
+			return err
+		}
+	case len(buf) == edwards25519.SizePrivateKey:
+		copy(key[:], buf)
+	default:
+		return errors.Errorf("private key must be %d hex characters", hex.EncodedLen(edwards25519.SizePrivateKey))
+	}
+
+	a, err := w.Import(c.Args().Get(0), key)
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("name", a.Name), output.F("address", a.PublicKey)},
+		"Imported account %q with address %x.\n", a.Name, a.PublicKey)
+}
+
+func walletExport(c *cli.Context, w *wallet.Wallet) error {
+	key, err := w.Keystore().Load(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	// Keys may only be exported encrypted.
+	passphrase, err := readPassphrase(c, true)
+	if err != nil {
+		return err
+	}
+
+	buf, err := security.EncryptKey(key, passphrase, kdfParams(c))
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("name", c.Args().Get(0)), output.F("key", buf)}, "%x\n", buf)
+}
+
+func walletRemove(c *cli.Context, w *wallet.Wallet) error {
+	if err := w.Remove(c.Args().Get(0)); err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("name", c.Args().Get(0))}, "Removed account %q.\n", c.Args().Get(0))
+}
+
+func walletList(c *cli.Context, w *wallet.Wallet) error {
+	accounts, err := w.List()
+	if err != nil {
+		return err
+	}
+
+	list := output.NewList("name", "address")
+
+	for _, a := range accounts {
+		list.Add(a.Name, a.PublicKey)
+	}
+
+	return printList(c, list)
+}
+
+func walletBalance(c *cli.Context, w *wallet.Wallet) error {
+	names := []string(c.Args())
+
+	if len(names) == 0 {
+		accounts, err := w.List()
+		if err != nil {
+			return err
+		}
+
+		for _, a := range accounts {
+			names = append(names, a.Name)
+		}
+	}
+
+	list := output.NewList("name", "balance", "gas_balance", "stake", "reward")
+
+	for _, name := range names {
+		a, err := w.Open(name)
+		if err != nil {
+			return err
+		}
+
+		list.Add(a.Name, a.Balance, a.GasBalance, a.Stake, a.Reward)
+	}
+
+	return printList(c, list)
+}
+
+func walletSend(c *cli.Context, w *wallet.Wallet) error {
+	recipient, err := decodeAddress(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	amount, err := strconv.ParseUint(c.Args().Get(2), 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid amount")
+	}
+
+	res, err := w.Send(c.Args().Get(0), recipient, amount)
+	if err != nil {
+		return err
+	}
+
+	o := output.Object{output.F("id", res.ID), output.F("recipient", recipient), output.F("amount", amount)}
+
+	return printObject(c, o, "Sent %d PERL(s) to %x in transaction %x.\n", amount, recipient, res.ID)
+}
+
+func walletReceive(c *cli.Context, w *wallet.Wallet) error {
+	address, err := w.Receive(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	return printObject(c, output.Object{output.F("name", c.Args().Get(0)), output.F("address", address)}, "%s\n", address)
+}
+
+func walletHistory(c *cli.Context, w *wallet.Wallet) error {
+	txs, err := w.History(c.Args().Get(0), c.Uint64("offset"), c.Uint64("limit"))
+	if err != nil {
+		return err
+	}
+
+	list := output.NewList("id", "nonce", "tag", "status")
+
+	for _, tx := range txs {
+		list.Add(tx.ID, tx.Nonce, tx.Tag, tx.Status)
+	}
+
+	return printList(c, list)
+}
+
+// decodeAddress decodes a hex-encoded account address.
+func decodeAddress(s string) ([32]byte, error) {
+	var address [32]byte
+
+	buf, err := hex.DecodeString(s)
+	if err != nil || len(buf) != len(address) {
+		return address, errors.Errorf("address must be %d hex characters", hex.EncodedLen(len(address)))
+	}
+
+	copy(address[:], buf)
+
+	return address, nil
+}
+
+// decodeTxID decodes a hex-encoded transaction ID.
+func decodeTxID(s string) ([32]byte, error) {
+	var id [32]byte
+
+	buf, err := hex.DecodeString(s)
+	if err != nil || len(buf) != len(id) {
+		return id, errors.Errorf("transaction ID must be %d hex characters", hex.EncodedLen(len(id)))
+	}
+
+	copy(id[:], buf)
+
+	return id, nil
+}
@@ -0,0 +1,453 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/security/threshold"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletCommands(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		err := Run(append([]string{"wctl", "--keystore", dir, "wallet"}, args...), &out)
+
+		return out.String(), err
+	}
+
+	key := "87a6813c3b4cf534b6ae82db9b1409fa7dbd5c13dba5858970b56084c4a930eb400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+	address := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+	out, err := run("import", "alice", key)
+	assert.NoError(t, err)
+	assert.Contains(t, out, address)
+
+	_, err = run("import", "alice", key)
+	assert.Error(t, err)
+
+	_, err = run("import", "bob", "abcd")
+	assert.Error(t, err)
+
+	_, err = run("create", "bob")
+	assert.NoError(t, err)
+
+	out, err = run("receive", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, address, strings.TrimSpace(out))
+
+	out, err = run("list")
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[1], "alice"))
+		assert.True(t, strings.HasPrefix(lines[2], "bob"))
+	}
+
+	_, err = run("remove", "bob")
+	assert.NoError(t, err)
+
+	_, err = run("receive", "bob")
+	assert.Error(t, err)
+
+	_, err = run("send", "alice", "nothex", "1")
+	assert.Error(t, err)
+
+	_, err = run("receive")
+	assert.Error(t, err)
+
+	// Keys may only be exported encrypted.
+	_, err = run("export", "alice")
+	assert.Error(t, err)
+}
+
+func TestWalletEncryptedKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(passphrase string, args ...string) (string, error) {
+		var out bytes.Buffer
+		err := Run(append([]string{
+			"wctl", "--keystore", dir, "--passphrase", passphrase,
+			"--kdf.time", "1", "--kdf.memory", "64", "--kdf.threads", "1",
+			"wallet",
+		}, args...), &out)
+
+		return strings.TrimSpace(out.String()), err
+	}
+
+	out, err := run("secret", "create", "alice")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "alice")
+
+	address, err := run("secret", "receive", "alice")
+	assert.NoError(t, err)
+
+	_, err = run("wrong", "receive", "alice")
+	assert.Error(t, err)
+
+	exported, err := run("secret", "export", "alice")
+	assert.NoError(t, err)
+
+	// Exported keys are decrypted with the passphrase when imported.
+	_, err = run("secret", "import", "bob", exported)
+	assert.NoError(t, err)
+
+	_, err = run("other", "import", "carol", exported)
+	assert.Error(t, err)
+
+	bob, err := run("secret", "receive", "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, address, bob)
+}
+
+func TestSignCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) error {
+		return Run(append([]string{"wctl", "--keystore", dir}, args...), ioutil.Discard)
+	}
+
+	key := "87a6813c3b4cf534b6ae82db9b1409fa7dbd5c13dba5858970b56084c4a930eb400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+	address := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+	file := filepath.Join(dir, "tx.json")
+
+	assert.NoError(t, run("wallet", "import", "alice", key))
+
+	// Signing requires no node to be running. The account sends PERLs to its own address.
+	assert.NoError(t, run("sign", "--nonce", "7", "--block", "3", "alice", address, "10", file))
+
+	buf, err := ioutil.ReadFile(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var req wctl.TxRequest
+	if !assert.NoError(t, req.UnmarshalJSON(buf)) {
+		return
+	}
+
+	assert.Equal(t, address, hex.EncodeToString(req.Sender[:]))
+	assert.EqualValues(t, 7, req.Nonce)
+	assert.EqualValues(t, 3, req.Block)
+	assert.EqualValues(t, sys.TagTransfer, req.Tag)
+
+	tx := wavelet.NewSignedTransactionWithVersion(
+		req.Version, security.Scheme(req.Scheme), req.Sender, req.Nonce, req.Block, sys.Tag(req.Tag), req.Payload,
+		req.Signature,
+	)
+	assert.True(t, tx.VerifySignature())
+
+	transfer, err := wavelet.ParseTransfer(req.Payload)
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, 10, transfer.Amount)
+	}
+
+	assert.Error(t, run("sign", "bob", address, "10", file))
+	assert.Error(t, run("broadcast", filepath.Join(dir, "missing.json")))
+}
+
+func TestThresholdCommands(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) error {
+		return Run(append([]string{
+			"wctl", "--keystore", dir, "--passphrase", "secret",
+			"--kdf.time", "1", "--kdf.memory", "64", "--kdf.threads", "1",
+		}, args...), ioutil.Discard)
+	}
+
+	key := "87a6813c3b4cf534b6ae82db9b1409fa7dbd5c13dba5858970b56084c4a930eb400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+	address := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+	shares := filepath.Join(dir, "shares")
+	file := filepath.Join(dir, "tx.json")
+
+	assert.NoError(t, run("wallet", "import", "alice", key))
+	assert.NoError(t, run("threshold", "split", "alice", "2", "3", shares))
+
+	// Serve the second and third shares, as `wctl threshold serve` would.
+	var signatories []string
+
+	for _, id := range []int{2, 3} {
+		buf, err := ioutil.ReadFile(filepath.Join(shares, fmt.Sprintf("share-%d", id)))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		buf, err = security.Decrypt(buf, []byte("secret"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		share, err := threshold.UnmarshalKeyShare(buf)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		server := httptest.NewServer(threshold.NewHandler(threshold.NewParticipant(share), "token"))
+		defer server.Close()
+
+		signatories = append(signatories, "--signatory", fmt.Sprintf("%d=%s", id, server.URL))
+	}
+
+	payload, err := wavelet.Stake{Opcode: sys.PlaceStake, Amount: 100}.Marshal()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	args := append([]string{"threshold", "sign", "--token", "token", "--nonce", "7"}, signatories...)
+
+	assert.NoError(t, run(append(args, filepath.Join(shares, "group.json"), "stake", hex.EncodeToString(payload), file)...))
+
+	buf, err := ioutil.ReadFile(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var req wctl.TxRequest
+	if !assert.NoError(t, req.UnmarshalJSON(buf)) {
+		return
+	}
+
+	assert.Equal(t, address, hex.EncodeToString(req.Sender[:]))
+	assert.EqualValues(t, sys.TagStake, req.Tag)
+
+	tx := wavelet.NewSignedTransactionWithVersion(
+		req.Version, security.Scheme(req.Scheme), req.Sender, req.Nonce, req.Block, sys.Tag(req.Tag), req.Payload,
+		req.Signature,
+	)
+	assert.True(t, tx.VerifySignature())
+
+	// A single share falls short of the threshold.
+	assert.Error(t, run(append(args[:len(args)-2], filepath.Join(shares, "group.json"), "stake", hex.EncodeToString(payload), file)...))
+}
+
+func TestKeysCommands(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(passphrase string, args ...string) (string, error) {
+		var out bytes.Buffer
+		err := Run(append([]string{
+			"wctl", "--keystore", dir, "--passphrase", passphrase, "--scrypt.n", "16",
+		}, args...), &out)
+
+		return strings.TrimSpace(out.String()), err
+	}
+
+	key := "87a6813c3b4cf534b6ae82db9b1409fa7dbd5c13dba5858970b56084c4a930eb400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+	address := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+	// Keys are imported from files, rather than the command line.
+	keyPath := filepath.Join(dir, "key.txt")
+	assert.NoError(t, ioutil.WriteFile(keyPath, []byte(key), 0600))
+
+	out, err := run("secret", "keys", "import", "alice", keyPath)
+	assert.NoError(t, err)
+	assert.Contains(t, out, address)
+
+	_, err = run("secret", "keys", "import", "alice", keyPath)
+	assert.Error(t, err)
+
+	_, err = run("secret", "keys", "create", "bob")
+	assert.NoError(t, err)
+
+	// Key files are listed without being decrypted.
+	out, err = run("", "keys", "list")
+	assert.NoError(t, err)
+
+	lines := strings.Split(out, "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, []string{"alice", address}, strings.Fields(lines[1]))
+		assert.True(t, strings.HasPrefix(lines[2], "bob"))
+	}
+
+	// Exported key files may be imported elsewhere, and decrypt to the
+	// same key.
+	exported, err := run("", "keys", "export", "alice")
+	assert.NoError(t, err)
+
+	exportedPath := filepath.Join(dir, "exported")
+	assert.NoError(t, ioutil.WriteFile(exportedPath, []byte(exported), 0600))
+
+	loaded, err := wctl.LoadKeyFile(exportedPath, []byte("secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, key, hex.EncodeToString(loaded[:]))
+
+	_, err = run("wrong", "keys", "import", "carol", exportedPath)
+	assert.Error(t, err)
+
+	_, err = run("", "keys", "export", "carol")
+	assert.Error(t, err)
+
+	// Other commands use the key files with --keystore.json, and otherwise
+	// require a passphrase to be prompted for outside of a terminal.
+	out, err = run("secret", "--keystore.json", "wallet", "receive", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, address, out)
+
+	_, err = run("wrong", "--keystore.json", "wallet", "receive", "alice")
+	assert.Error(t, err)
+
+	_, err = run("", "--keystore.json", "wallet", "receive", "alice")
+	assert.Error(t, err)
+
+	_, err = run("", "keys", "create", "dave")
+	assert.Error(t, err)
+}
+
+func TestKeysRecoverCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		err := Run(append([]string{
+			"wctl", "--keystore", dir, "--passphrase", "secret", "--scrypt.n", "16",
+		}, args...), &out)
+
+		return strings.TrimSpace(out.String()), err
+	}
+
+	address := func(name string) string {
+		out, err := run("--keystore.json", "wallet", "receive", name)
+		assert.NoError(t, err)
+
+		return out
+	}
+
+	out, err := run("keys", "create", "--mnemonic", "--mnemonic-passphrase", "extra", "alice")
+	assert.NoError(t, err)
+
+	lines := strings.Split(out, "\n")
+	mnemonic := lines[len(lines)-1]
+	assert.Len(t, strings.Fields(mnemonic), 24)
+
+	mnemonicPath := filepath.Join(dir, "mnemonic.txt")
+	assert.NoError(t, ioutil.WriteFile(mnemonicPath, []byte(mnemonic+"\n"), 0600))
+
+	// The mnemonic recovers the same key, so long as the same mnemonic
+	// passphrase is given.
+	_, err = run("keys", "recover", "--mnemonic-file", mnemonicPath, "--mnemonic-passphrase", "extra", "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, address("alice"), address("bob"))
+
+	_, err = run("keys", "recover", "--mnemonic-file", mnemonicPath, "carol")
+	assert.NoError(t, err)
+	assert.NotEqual(t, address("alice"), address("carol"))
+
+	// Mnemonics whose checksum does not match are rejected.
+	assert.NoError(t, ioutil.WriteFile(mnemonicPath, []byte(strings.Repeat("abandon ", 24)), 0600))
+
+	_, err = run("keys", "recover", "--mnemonic-file", mnemonicPath, "dave")
+	assert.Error(t, err)
+
+	// Each account index signs with its own key, derived alike from
+	// recovered keys.
+	account := func(index, name string) string {
+		out, err := run("--keystore.json", "--account-index", index, "wallet", "receive", name)
+		assert.NoError(t, err)
+
+		return out
+	}
+
+	assert.NotEqual(t, address("alice"), account("0", "alice"))
+	assert.NotEqual(t, account("0", "alice"), account("1", "alice"))
+	assert.Equal(t, account("1", "alice"), account("1", "bob"))
+
+	_, err = run("--account-index", "1", "wallet", "receive", "alice")
+	assert.Error(t, err)
+}
+
+func TestWalletOutputFormats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(format string, args ...string) (string, error) {
+		var out bytes.Buffer
+		err := Run(append([]string{"wctl", "--keystore", dir, "--output", format, "wallet"}, args...), &out)
+
+		return out.String(), err
+	}
+
+	key := "87a6813c3b4cf534b6ae82db9b1409fa7dbd5c13dba5858970b56084c4a930eb400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+	address := "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405"
+
+	out, err := run("json", "import", "alice", key)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"name\": \"alice\",\n  \"address\": \""+address+"\"\n}\n", out)
+
+	out, err = run("yaml", "list")
+	assert.NoError(t, err)
+	assert.Equal(t, "- name: \"alice\"\n  address: \""+address+"\"\n", out)
+
+	out, err = run("csv", "list")
+	assert.NoError(t, err)
+	assert.Equal(t, "name,address\nalice,"+address+"\n", out)
+
+	out, err = run("table", "receive", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "NAME   ADDRESS\nalice  "+address+"\n", out)
+
+	_, err = run("xml", "list")
+	assert.Error(t, err)
+}
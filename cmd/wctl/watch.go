@@ -0,0 +1,348 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/perlin-network/wavelet/internal/output"
+	"github.com/perlin-network/wavelet/wctl"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	statusUnknown  = "unknown"
+	statusReceived = "received"
+	statusApplied  = "applied"
+	statusFailed   = "failed"
+	statusConflict = "conflict"
+)
+
+var accountCommand = cli.Command{
+	Name:  "account",
+	Usage: "query accounts of the ledger",
+	Subcommands: []cli.Command{
+		{
+			Name:      "watch",
+			Usage:     "print changes to the balance, gas balance, stake and reward of an account until interrupted",
+			ArgsUsage: "<address>",
+			Action:    clientAction(1, accountWatch),
+		},
+	},
+}
+
+var txCommand = cli.Command{
+	Name:  "tx",
+	Usage: "query transactions of the ledger",
+	Subcommands: []cli.Command{
+		{
+			Name:      "watch",
+			Usage:     "print the status transitions of a transaction until interrupted",
+			ArgsUsage: "<id>",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "until-final",
+					Usage: "Exit once the transaction is finalized, with an error should it be reported to have failed.",
+				},
+				cli.DurationFlag{
+					Name:  "interval",
+					Value: time.Second,
+					Usage: "Interval to query the status of the transaction at, alongside listening for its events.",
+				},
+			},
+			Action: clientAction(1, txWatch),
+		},
+	},
+}
+
+// accountChange is a change to a field of an account.
+type accountChange struct {
+	field string
+	value uint64
+}
+
+func accountWatch(c *cli.Context, client *wctl.Client) error {
+	id, err := decodeAddress(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := interruptible()
+	defer cancel()
+
+	changes := make(chan accountChange)
+	errs := make(chan error, 1)
+
+	change := func(field string, value uint64) {
+		select {
+		case changes <- accountChange{field: field, value: value}:
+		case <-ctx.Done():
+		}
+	}
+
+	client.OnBalanceUpdated = func(u wctl.BalanceUpdate) { change("balance", u.Balance) }
+	client.OnGasBalanceUpdated = func(u wctl.GasBalanceUpdate) { change("gas_balance", u.GasBalance) }
+	client.OnStakeUpdated = func(u wctl.StakeUpdated) { change("stake", u.Stake) }
+	client.OnRewardUpdated = func(u wctl.RewardUpdated) { change("reward", u.Reward) }
+	client.OnError = watchErrors(errs)
+
+	// The client may be shared by the commands of a console.
+	defer func() {
+		client.OnBalanceUpdated, client.OnGasBalanceUpdated = nil, nil
+		client.OnStakeUpdated, client.OnRewardUpdated = nil, nil
+		client.OnError = nil
+	}()
+
+	stop, err := client.PollAccountsFilteredCtx(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	defer stop()
+
+	account, err := client.GetAccountCtx(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	state := map[string]uint64{
+		"balance":     account.Balance,
+		"gas_balance": account.GasBalance,
+		"stake":       account.Stake,
+		"reward":      account.Reward,
+	}
+
+	err = printObject(c, output.Object{
+		output.F("account", id),
+		output.F("balance", account.Balance),
+		output.F("gas_balance", account.GasBalance),
+		output.F("stake", account.Stake),
+		output.F("reward", account.Reward),
+	}, "Account %x has a balance of %d, gas balance of %d, stake of %d and reward of %d PERL(s).\n",
+		id, account.Balance, account.GasBalance, account.Stake, account.Reward)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case ch := <-changes:
+			previous := state[ch.field]
+			if previous == ch.value {
+				continue
+			}
+
+			state[ch.field] = ch.value
+
+			o := output.Object{
+				output.F("account", id),
+				output.F("field", ch.field),
+				output.F("previous", previous),
+				output.F("value", ch.value),
+			}
+
+			if err := printObject(c, o, "%s: %d -> %d\n", ch.field, previous, ch.value); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// txStatus is a status of a transaction, alongside why it was reached.
+type txStatus struct {
+	status string
+	reason string
+}
+
+func (s txStatus) final() bool {
+	return s.status == statusApplied || s.status == statusFailed
+}
+
+func txWatch(c *cli.Context, client *wctl.Client) error {
+	id, err := decodeTxID(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := interruptible()
+	defer cancel()
+
+	statuses := make(chan txStatus)
+	errs := make(chan error, 1)
+
+	transition := func(s txStatus) {
+		select {
+		case statuses <- s:
+		case <-ctx.Done():
+		}
+	}
+
+	client.OnTxApplied = func(wctl.TxApplied) {
+		transition(txStatus{status: statusApplied})
+	}
+	client.OnTxFailed = func(e wctl.TxFailed) {
+		transition(txStatus{status: statusFailed, reason: e.Error})
+	}
+	client.OnTxConflict = func(e wctl.TxConflict) {
+		transition(txStatus{status: statusConflict, reason: fmt.Sprintf("conflicts with %x", e.ConflictingTxID)})
+	}
+	client.OnError = watchErrors(errs)
+
+	// The client may be shared by the commands of a console.
+	defer func() {
+		client.OnTxApplied, client.OnTxFailed, client.OnTxConflict = nil, nil, nil
+		client.OnError = nil
+	}()
+
+	stop, err := client.PollTransactionsFilteredCtx(ctx, wctl.TxEventFilter{ID: id})
+	if err != nil {
+		return err
+	}
+
+	defer stop()
+
+	ticker := time.NewTicker(c.Duration("interval"))
+	defer ticker.Stop()
+
+	var current txStatus
+
+	// update prints the transition to s, returning whether watching is done.
+	update := func(s txStatus) (bool, error) {
+		// Finalized transactions may still be reported to have failed by
+		// their event, should they have been queried as applied first.
+		if s == current || (current.final() && s.status != statusFailed) {
+			return false, nil
+		}
+
+		current = s
+
+		o := output.Object{output.F("id", id), output.F("status", s.status), output.F("reason", s.reason)}
+
+		var err error
+
+		if s.reason == "" {
+			err = printObject(c, o, "Transaction %x is %s.\n", id, s.status)
+		} else {
+			err = printObject(c, o, "Transaction %x is %s: %s.\n", id, s.status, s.reason)
+		}
+
+		if err != nil || !s.final() || !c.Bool("until-final") {
+			return false, err
+		}
+
+		if s.status == statusFailed {
+			return true, fmt.Errorf("transaction %x failed: %s", id, s.reason)
+		}
+
+		return true, nil
+	}
+
+	// query returns the status of the transaction as seen by the node, which
+	// only tells apart received transactions from finalized ones.
+	query := func() (txStatus, error) {
+		tx, err := client.GetTransactionCtx(ctx, id)
+
+		switch {
+		case errors.Is(err, wctl.ErrNotFound):
+			if current.status != "" {
+				return current, nil
+			}
+
+			return txStatus{status: statusUnknown}, nil
+		case err != nil:
+			return current, err
+		case tx.Status == statusReceived && current.status == statusConflict:
+			// Conflicts are only reported by events.
+			return current, nil
+		}
+
+		return txStatus{status: tx.Status}, nil
+	}
+
+	s, err := query()
+	if err != nil {
+		return err
+	}
+
+	for {
+		done, err := update(s)
+		if done || err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case <-ticker.C:
+			if s, err = query(); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				return err
+			}
+		case s = <-statuses:
+		}
+	}
+}
+
+// watchErrors returns an OnError callback passing the first error on to
+// errs, such that watching stops on it.
+func watchErrors(errs chan<- error) func(error) {
+	return func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+}
+
+// interruptible returns a context which is cancelled once wctl is
+// interrupted, and a function releasing it.
+func interruptible() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
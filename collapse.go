@@ -20,7 +20,10 @@
 package wavelet
 
 import (
+	"bytes"
 	"encoding/hex"
+	"sort"
+
 	"github.com/perlin-network/wavelet/avl"
 	"github.com/perlin-network/wavelet/log"
 	"github.com/perlin-network/wavelet/sys"
@@ -33,6 +36,10 @@ func collapseTransactions(
 	snapshot := accounts.Snapshot()
 	snapshot.SetViewID(height)
 
+	if sys.FeatureActive(sys.FeatureBeacon, height) {
+		applyBeacon(snapshot, block, height)
+	}
+
 	res := &collapseResults{
 		snapshot: snapshot,
 		ctx:      NewCollapseContext(snapshot),
@@ -49,70 +56,164 @@ func collapseTransactions(
 
 	stakes := make(map[AccountID]uint64)
 
-	// Apply transactions in reverse order from the end of the round
-	// all the way down to the beginning of the round.
-	for _, tx := range txs {
-		if hex.EncodeToString(tx.Sender[:]) != sys.FaucetAddress {
-			fee := tx.Fee()
-
-			senderBalance, _ := res.ctx.ReadAccountBalance(tx.Sender)
-			if senderBalance < fee {
-				res.rejected = append(res.rejected, tx)
-				res.rejectedErrors = append(
-					res.rejectedErrors,
-					errors.Errorf(
-						"stake: sender %x does not have enough PERLs to pay transaction fees (comprised of %d PERLs)",
-						tx.Sender, fee,
-					),
-				)
-				res.rejectedCount += tx.LogicalUnits()
+	// PERLs delegated to the validators of stakes weigh towards their
+	// rewards as though they staked them.
+	delegated := make(map[AccountID]uint64)
 
-				continue
-			}
+	// Of transactions from the same sender and of the same nonce, only one is
+	// ever applied once transactions may be replaced.
+	replacement := sys.FeatureActive(sys.FeatureReplacement, height)
+
+	var replaced map[*Transaction]TransactionID
+	if replacement {
+		replaced = replacedTransactions(txs)
+	}
 
-			res.ctx.WriteAccountBalance(tx.Sender, senderBalance-fee)
-			totalFee += fee
+	// record tallies the outcome of applying tx to ctx.
+	record := func(ctx *CollapseContext, tx *Transaction, r roundTxResult) {
+		totalFee += r.fee
 
-			stake, _ := res.ctx.ReadAccountStake(tx.Sender)
-			if stake >= sys.MinimumStake {
-				if _, ok := stakes[tx.Sender]; !ok {
-					stakes[tx.Sender] = stake
-				} else {
-					stakes[tx.Sender] += stake
-				}
+		if r.staked {
+			delegators, _ := res.ctx.ReadAccountDelegators(tx.Sender)
+			delegation := totalDelegatedStake(delegators)
 
-				totalStake += stake
-			}
+			stakes[tx.Sender] += r.stake
+			delegated[tx.Sender] += delegation
+			totalStake += r.stake + delegation
 		}
 
-		if err := res.ctx.ApplyTransaction(block, tx); err != nil {
+		if r.err != nil {
 			res.rejected = append(res.rejected, tx)
-			res.rejectedErrors = append(res.rejectedErrors, err)
+			res.rejectedErrors = append(res.rejectedErrors, r.err)
 			res.rejectedCount += tx.LogicalUnits()
 
-			logger := log.Node()
-			logger.Error().Err(err).Msg("error applying transaction")
+			if r.failed {
+				logger := log.Node()
+				logger.Error().Err(r.err).Msg("error applying transaction")
 
-			continue
+				// Logs are only kept of transactions which were applied.
+				ctx.takeLogs(height, tx.ID)
+			}
+
+			return
+		}
+
+		if replacement {
+			res.ctx.WriteAccountNonce(tx.Sender, tx.Nonce, tx.ID)
 		}
 
 		// Update statistics.
 
 		res.applied = append(res.applied, tx)
 		res.appliedCount += tx.LogicalUnits()
+		res.diffs = append(res.diffs, ctx.takeDiff(tx.ID))
+		res.logs = append(res.logs, ctx.takeLogs(height, tx.ID)...)
 	}
 
+	// Transactions touching distinct accounts are batched, and applied
+	// concurrently should there be more than one of them.
+	var (
+		batch   []*isolatedTx
+		touched = make(map[AccountID]struct{})
+	)
+
+	flush := func() {
+		applyIsolated(block, batch)
+
+		for _, item := range batch {
+			if item.ctx != nil {
+				res.ctx.merge(item.ctx)
+			}
+
+			record(item.ctx, item.tx, item.result)
+		}
+
+		batch, touched = batch[:0], make(map[AccountID]struct{})
+	}
+
+	// Apply transactions in reverse order from the end of the round
+	// all the way down to the beginning of the round.
+	for _, tx := range txs {
+		if by, ok := replaced[tx]; ok {
+			// Kept in the batch, such that transactions are recorded in order.
+			batch = append(batch, &isolatedTx{
+				tx:     tx,
+				result: roundTxResult{err: errors.Wrapf(ErrTxReplaced, "by %x", by)},
+			})
+
+			continue
+		}
+
+		if replacement {
+			if by, used := res.ctx.ReadAccountNonce(tx.Sender, tx.Nonce); used {
+				batch = append(batch, &isolatedTx{
+					tx:     tx,
+					result: roundTxResult{err: errors.Wrapf(ErrTxNonceUsed, "by %x", by)},
+				})
+
+				continue
+			}
+		}
+
+		accounts, ok := touchedAccounts(res.ctx, tx)
+		if !ok {
+			// Transactions whose accounts are not known in advance are
+			// applied on their own, after all transactions before them.
+			flush()
+			record(res.ctx, tx, applyRoundTransaction(res.ctx, block, tx))
+
+			continue
+		}
+
+		for _, id := range accounts {
+			if _, conflicts := touched[id]; conflicts {
+				flush()
+				break
+			}
+		}
+
+		for _, id := range accounts {
+			touched[id] = struct{}{}
+		}
+
+		batch = append(batch, &isolatedTx{tx: tx, ctx: res.ctx.isolate(accounts)})
+	}
+
+	flush()
+
+	if sys.FeatureActive(sys.FeatureSlashing, block.Index+1) {
+		markActiveValidators(res.ctx, res.applied, block.Index+1)
+	}
+
+	// Rewards distributed to validators are not part of any transaction, and
+	// so are recorded as changes made by the block itself.
+	res.ctx.recordDiff()
+
 	if totalStake > 0 {
-		for sender, stake := range stakes {
-			rewardeeBalance, _ := res.ctx.ReadAccountReward(sender)
+		// Validators are rewarded in order, such that the accounts of their
+		// delegators are added to the context in the same order by all nodes.
+		validators := make([]AccountID, 0, len(stakes))
+
+		for sender := range stakes {
+			validators = append(validators, sender)
+		}
+
+		sort.Slice(validators, func(i, j int) bool {
+			return bytes.Compare(validators[i][:], validators[j][:]) < 0
+		})
+
+		for _, sender := range validators {
+			stake := stakes[sender] + delegated[sender]
 
 			reward := float64(totalFee) * (float64(stake) / float64(totalStake))
-			res.ctx.WriteAccountReward(sender, rewardeeBalance+uint64(reward))
+			rewardValidator(res.ctx, sender, stakes[sender], delegated[sender], uint64(reward))
 		}
 	}
 
 	res.ctx.processRewardWithdrawals(block.Index)
 
+	res.blockDiff = res.ctx.takeDiff(TransactionID{})
+
 	if err := res.ctx.Flush(); err != nil {
 		return res, err
 	}
@@ -122,6 +223,105 @@ func collapseTransactions(
 	return res, nil
 }
 
+// roundTxResult is the outcome of charging a transaction of a round its fee,
+// and applying it.
+type roundTxResult struct {
+	fee uint64 // The fee charged.
+
+	// The stake of the sender, rewarded a share of the fees of the round
+	// should staked be set.
+	stake  uint64
+	staked bool
+
+	// Why the transaction was rejected, which is failed should it have been
+	// rejected upon being applied rather than for its fee.
+	err    error
+	failed bool
+}
+
+// applyRoundTransaction charges tx its fee, and applies it to ctx while
+// recording the changes it makes.
+func applyRoundTransaction(ctx *CollapseContext, block *Block, tx *Transaction) roundTxResult {
+	var r roundTxResult
+
+	ctx.recordDiff()
+
+	if hex.EncodeToString(tx.Sender[:]) != sys.FaucetAddress {
+		fee := tx.Fee()
+
+		payer, sponsored := feePayer(ctx.ReadAccountFeeAllowance, ctx.ReadAccountBalance, tx.Sender, fee)
+
+		payerBalance, _ := ctx.ReadAccountBalance(payer)
+		if payerBalance < fee {
+			r.err = errors.Errorf(
+				"stake: sender %x does not have enough PERLs to pay transaction fees (comprised of %d PERLs)",
+				tx.Sender, fee,
+			)
+
+			return r
+		}
+
+		ctx.WriteAccountBalance(payer, payerBalance-fee)
+		r.fee = fee
+
+		if sponsored {
+			grant, _ := ctx.ReadAccountFeeAllowance(tx.Sender)
+			grant.Allowance -= fee
+
+			ctx.WriteAccountFeeAllowance(tx.Sender, grant)
+		}
+
+		stake, _ := ctx.ReadAccountStake(tx.Sender)
+		if stake >= sys.MinimumStake {
+			r.stake, r.staked = stake, true
+		}
+	}
+
+	if err := ctx.ApplyTransaction(block, tx); err != nil {
+		r.err, r.failed = err, true
+	}
+
+	return r
+}
+
+// replacedTransactions returns the transactions among txs replaced by another
+// transaction of txs from the same sender and of the same nonce paying a
+// higher fee, mapped to the ID of the transaction replacing them. Of
+// transactions paying the same fee, the first one is kept.
+func replacedTransactions(txs []*Transaction) map[*Transaction]TransactionID {
+	var (
+		kept     = make(map[senderNonce]*Transaction, len(txs))
+		replaced map[*Transaction]TransactionID
+	)
+
+	for _, tx := range txs {
+		key := senderNonce{sender: tx.Sender, nonce: tx.Nonce}
+
+		prev, exists := kept[key]
+		if !exists {
+			kept[key] = tx
+			continue
+		}
+
+		if replaced == nil {
+			replaced = make(map[*Transaction]TransactionID)
+		}
+
+		if tx.Fee() > prev.Fee() {
+			kept[key], replaced[prev] = tx, tx.ID
+		} else {
+			replaced[tx] = prev.ID
+		}
+	}
+
+	// Point every replaced transaction to the transaction finally kept.
+	for tx := range replaced {
+		replaced[tx] = kept[senderNonce{sender: tx.Sender, nonce: tx.Nonce}].ID
+	}
+
+	return replaced
+}
+
 // WARNING: While using this, the tree must not be modified.
 type CollapseContext struct {
 	tree     *avl.Tree
@@ -139,9 +339,58 @@ type CollapseContext struct {
 	contracts           map[TransactionID][]byte
 	contractGasBalances map[TransactionID]uint64
 	contractVMs         map[AccountID]*VMState
+	feeAllowances       map[AccountID]FeeAllowance
+	recoveryConfigs     map[AccountID]RecoveryConfig
+	pendingRecoveries   map[AccountID]PendingRecovery
+	contractUpgraders   map[AccountID]AccountID
+	contractUpgrades    map[AccountID][]ContractUpgrade
+	multisigs           map[AccountID]MultisigAccount
+	delegators          map[AccountID][]DelegatedStake
+	delegations         map[AccountID][]DelegatedStake
+	commissions         map[AccountID]uint16
+	unbondings          map[AccountID][]UnbondingStake
+	lastActive          map[AccountID]uint64
+	slashings           map[AccountID][]SlashingEvent
+
+	// Contracts whose memory and globals were reset by upgrading their code
+	contractResets map[AccountID]struct{}
 
 	rewardWithdrawalRequests []RewardWithdrawalRequest
 
+	// To preserve order of state insertions of beacon values
+	beaconIndices []uint64
+	beacons       map[uint64][32]byte
+	contributors  map[AccountID]struct{}
+
+	// To preserve order of state insertions of names
+	nameKeys []string
+	names    map[string]NameRecord
+
+	// To preserve order of state insertions of anchored data
+	dataIDs []TransactionID
+	data    map[TransactionID][]byte
+
+	// To preserve order of state insertions of transfers proposed from multisig accounts
+	multisigProposalIDs []TransactionID
+	multisigProposals   map[TransactionID]MultisigProposal
+
+	// To preserve order of state insertions of the nonces transactions were
+	// applied with
+	nonceKeys []senderNonce
+	nonces    map[senderNonce]TransactionID
+
+	// To preserve order of state insertions of the storage of contracts, by
+	// their key in the ledger state tree
+	storageKeys []string
+	storage     map[string][]byte
+
+	// Changes made by the transaction currently being applied, nil when not recording
+	diff           *stateDiff
+	contractMemory map[AccountID][]byte
+
+	// Logs emitted by smart contracts while applying the transaction currently being applied
+	logs []ContractLog
+
 	VMCache *VMLRU
 }
 
@@ -167,6 +416,27 @@ func (c *CollapseContext) init() {
 	c.contracts = make(map[TransactionID][]byte)
 	c.contractGasBalances = make(map[TransactionID]uint64)
 	c.contractVMs = make(map[AccountID]*VMState)
+	c.feeAllowances = make(map[AccountID]FeeAllowance)
+	c.recoveryConfigs = make(map[AccountID]RecoveryConfig)
+	c.pendingRecoveries = make(map[AccountID]PendingRecovery)
+	c.contractUpgraders = make(map[AccountID]AccountID)
+	c.contractUpgrades = make(map[AccountID][]ContractUpgrade)
+	c.contractResets = make(map[AccountID]struct{})
+	c.multisigs = make(map[AccountID]MultisigAccount)
+	c.multisigProposals = make(map[TransactionID]MultisigProposal)
+	c.delegators = make(map[AccountID][]DelegatedStake)
+	c.delegations = make(map[AccountID][]DelegatedStake)
+	c.commissions = make(map[AccountID]uint16)
+	c.unbondings = make(map[AccountID][]UnbondingStake)
+	c.lastActive = make(map[AccountID]uint64)
+	c.slashings = make(map[AccountID][]SlashingEvent)
+	c.beacons = make(map[uint64][32]byte)
+	c.contributors = make(map[AccountID]struct{})
+	c.names = make(map[string]NameRecord)
+	c.data = make(map[TransactionID][]byte)
+	c.nonces = make(map[senderNonce]TransactionID)
+	c.storage = make(map[string][]byte)
+	c.contractMemory = make(map[AccountID][]byte)
 
 	c.VMCache = NewVMLRU(4)
 }
@@ -244,6 +514,189 @@ func (c *CollapseContext) ReadAccountContractCode(id TransactionID) ([]byte, boo
 	return code, exists
 }
 
+func (c *CollapseContext) ReadAccountFeeAllowance(id AccountID) (FeeAllowance, bool) {
+	if allowance, ok := c.feeAllowances[id]; ok {
+		return allowance, allowance.Allowance > 0
+	}
+
+	allowance, exists := ReadAccountFeeAllowance(c.tree, id)
+	if exists {
+		c.feeAllowances[id] = allowance
+	}
+
+	return allowance, exists
+}
+
+func (c *CollapseContext) ReadAccountRecoveryConfig(id AccountID) (RecoveryConfig, bool) {
+	if config, ok := c.recoveryConfigs[id]; ok {
+		return config, len(config.Guardians) > 0
+	}
+
+	config, exists := ReadAccountRecoveryConfig(c.tree, id)
+	if exists {
+		c.recoveryConfigs[id] = config
+	}
+
+	return config, exists
+}
+
+func (c *CollapseContext) ReadAccountPendingRecovery(id AccountID) (PendingRecovery, bool) {
+	if recovery, ok := c.pendingRecoveries[id]; ok {
+		return recovery, len(recovery.Approvals) > 0
+	}
+
+	recovery, exists := ReadAccountPendingRecovery(c.tree, id)
+	if exists {
+		c.pendingRecoveries[id] = recovery
+	}
+
+	return recovery, exists
+}
+
+func (c *CollapseContext) ReadAccountMultisig(id AccountID) (MultisigAccount, bool) {
+	if multisig, ok := c.multisigs[id]; ok {
+		return multisig, true
+	}
+
+	multisig, exists := ReadAccountMultisig(c.tree, id)
+	if exists {
+		c.multisigs[id] = multisig
+	}
+
+	return multisig, exists
+}
+
+func (c *CollapseContext) ReadAccountDelegators(id AccountID) ([]DelegatedStake, bool) {
+	if delegators, ok := c.delegators[id]; ok {
+		return delegators, len(delegators) > 0
+	}
+
+	delegators, exists := ReadAccountDelegators(c.tree, id)
+	if exists {
+		c.delegators[id] = delegators
+	}
+
+	return delegators, exists
+}
+
+func (c *CollapseContext) ReadAccountDelegations(id AccountID) ([]DelegatedStake, bool) {
+	if delegations, ok := c.delegations[id]; ok {
+		return delegations, len(delegations) > 0
+	}
+
+	delegations, exists := ReadAccountDelegations(c.tree, id)
+	if exists {
+		c.delegations[id] = delegations
+	}
+
+	return delegations, exists
+}
+
+func (c *CollapseContext) ReadAccountCommission(id AccountID) (uint16, bool) {
+	if commission, ok := c.commissions[id]; ok {
+		return commission, true
+	}
+
+	commission, exists := ReadAccountCommission(c.tree, id)
+	if exists {
+		c.commissions[id] = commission
+	}
+
+	return commission, exists
+}
+
+func (c *CollapseContext) ReadAccountUnbondings(id AccountID) ([]UnbondingStake, bool) {
+	if unbondings, ok := c.unbondings[id]; ok {
+		return unbondings, len(unbondings) > 0
+	}
+
+	unbondings, exists := ReadAccountUnbondings(c.tree, id)
+	if exists {
+		c.unbondings[id] = unbondings
+	}
+
+	return unbondings, exists
+}
+
+func (c *CollapseContext) ReadAccountLastActive(id AccountID) (uint64, bool) {
+	if block, ok := c.lastActive[id]; ok {
+		return block, true
+	}
+
+	block, exists := ReadAccountLastActive(c.tree, id)
+	if exists {
+		c.lastActive[id] = block
+	}
+
+	return block, exists
+}
+
+func (c *CollapseContext) ReadAccountSlashings(id AccountID) ([]SlashingEvent, bool) {
+	if events, ok := c.slashings[id]; ok {
+		return events, len(events) > 0
+	}
+
+	events, exists := ReadAccountSlashings(c.tree, id)
+	if exists {
+		c.slashings[id] = events
+	}
+
+	return events, exists
+}
+
+func (c *CollapseContext) ReadMultisigProposal(id TransactionID) (MultisigProposal, bool) {
+	if proposal, ok := c.multisigProposals[id]; ok {
+		return proposal, len(proposal.Approvals) > 0
+	}
+
+	proposal, exists := ReadMultisigProposal(c.tree, id)
+	if exists {
+		c.multisigProposals[id] = proposal
+	}
+
+	return proposal, exists
+}
+
+func (c *CollapseContext) ReadBeacon(index uint64) ([32]byte, bool) {
+	if value, ok := c.beacons[index]; ok {
+		return value, true
+	}
+
+	return ReadBeacon(c.tree, index)
+}
+
+func (c *CollapseContext) ReadName(name string) (NameRecord, bool) {
+	if record, ok := c.names[name]; ok {
+		return record, true
+	}
+
+	return ReadName(c.tree, name)
+}
+
+func (c *CollapseContext) ReadContractStorage(id AccountID, key []byte) ([]byte, bool) {
+	if value, ok := c.storage[string(contractStorageKey(id, key))]; ok {
+		return value, len(value) > 0
+	}
+
+	return ReadContractStorage(c.tree, id, key)
+}
+
+func (c *CollapseContext) ReadAccountContractUpgrader(id AccountID) (AccountID, bool) {
+	if upgrader, ok := c.contractUpgraders[id]; ok {
+		return upgrader, upgrader != ZeroAccountID
+	}
+
+	return ReadAccountContractUpgrader(c.tree, id)
+}
+
+func (c *CollapseContext) ReadAccountContractUpgrades(id AccountID) []ContractUpgrade {
+	if upgrades, ok := c.contractUpgrades[id]; ok {
+		return upgrades
+	}
+
+	return ReadAccountContractUpgrades(c.tree, id)
+}
+
 func (c *CollapseContext) GetContractState(id AccountID) (*VMState, bool) {
 	vm, exists := c.contractVMs[id]
 	return vm, exists
@@ -260,34 +713,271 @@ func (c *CollapseContext) addAccount(id AccountID) {
 
 func (c *CollapseContext) WriteAccountBalance(id AccountID, balance uint64) {
 	c.addAccount(id)
+
+	if c.diff != nil {
+		before, _ := c.ReadAccountBalance(id)
+		c.diff.recordField(&c.diff.account(id).Balance, before, balance)
+	}
+
 	c.balances[id] = balance
 }
 
 func (c *CollapseContext) WriteAccountStake(id AccountID, stake uint64) {
 	c.addAccount(id)
+
+	if c.diff != nil {
+		before, _ := c.ReadAccountStake(id)
+		c.diff.recordField(&c.diff.account(id).Stake, before, stake)
+	}
+
 	c.stakes[id] = stake
 }
 
 func (c *CollapseContext) WriteAccountReward(id AccountID, reward uint64) {
 	c.addAccount(id)
+
+	if c.diff != nil {
+		before, _ := c.ReadAccountReward(id)
+		c.diff.recordField(&c.diff.account(id).Reward, before, reward)
+	}
+
 	c.rewards[id] = reward
 }
 
 func (c *CollapseContext) WriteAccountContractGasBalance(id TransactionID, gasBalance uint64) {
 	c.addAccount(id)
+
+	if c.diff != nil {
+		before, _ := c.ReadAccountContractGasBalance(id)
+		c.diff.recordField(&c.diff.account(id).GasBalance, before, gasBalance)
+	}
+
 	c.contractGasBalances[id] = gasBalance
 }
 
 func (c *CollapseContext) WriteAccountContractCode(id TransactionID, code []byte) {
 	c.addAccount(id)
+
+	if c.diff != nil {
+		c.diff.account(id).ContractCreated = true
+	}
+
 	c.contracts[id] = code
 }
 
+func (c *CollapseContext) WriteAccountFeeAllowance(id AccountID, allowance FeeAllowance) {
+	c.addAccount(id)
+	c.feeAllowances[id] = allowance
+}
+
+func (c *CollapseContext) WriteAccountRecoveryConfig(id AccountID, config RecoveryConfig) {
+	c.addAccount(id)
+	c.recoveryConfigs[id] = config
+}
+
+func (c *CollapseContext) WriteAccountPendingRecovery(id AccountID, recovery PendingRecovery) {
+	c.addAccount(id)
+	c.pendingRecoveries[id] = recovery
+}
+
+func (c *CollapseContext) WriteAccountMultisig(id AccountID, multisig MultisigAccount) {
+	c.addAccount(id)
+	c.multisigs[id] = multisig
+}
+
+func (c *CollapseContext) WriteAccountDelegators(id AccountID, delegators []DelegatedStake) {
+	c.addAccount(id)
+	c.delegators[id] = delegators
+}
+
+func (c *CollapseContext) WriteAccountDelegations(id AccountID, delegations []DelegatedStake) {
+	c.addAccount(id)
+	c.delegations[id] = delegations
+}
+
+func (c *CollapseContext) WriteAccountCommission(id AccountID, commission uint16) {
+	c.addAccount(id)
+	c.commissions[id] = commission
+}
+
+func (c *CollapseContext) WriteAccountUnbondings(id AccountID, unbondings []UnbondingStake) {
+	c.addAccount(id)
+	c.unbondings[id] = unbondings
+}
+
+func (c *CollapseContext) WriteAccountLastActive(id AccountID, block uint64) {
+	c.addAccount(id)
+	c.lastActive[id] = block
+}
+
+func (c *CollapseContext) WriteAccountSlashings(id AccountID, events []SlashingEvent) {
+	c.addAccount(id)
+	c.slashings[id] = events
+}
+
+func (c *CollapseContext) WriteMultisigProposal(id TransactionID, proposal MultisigProposal) {
+	if !c.multisigProposalWritten(id) {
+		c.multisigProposalIDs = append(c.multisigProposalIDs, id)
+	}
+
+	c.multisigProposals[id] = proposal
+}
+
+func (c *CollapseContext) multisigProposalWritten(id TransactionID) bool {
+	for _, written := range c.multisigProposalIDs {
+		if written == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *CollapseContext) WriteBeacon(index uint64, value [32]byte) {
+	if _, ok := c.beacons[index]; !ok {
+		c.beaconIndices = append(c.beaconIndices, index)
+	}
+
+	c.beacons[index] = value
+}
+
+func (c *CollapseContext) WriteName(name string, record NameRecord) {
+	if _, ok := c.names[name]; !ok {
+		c.nameKeys = append(c.nameKeys, name)
+	}
+
+	c.names[name] = record
+}
+
+func (c *CollapseContext) WriteData(id TransactionID, blob []byte) {
+	if _, ok := c.data[id]; !ok {
+		c.dataIDs = append(c.dataIDs, id)
+	}
+
+	c.data[id] = blob
+}
+
+// ReadAccountNonce returns the ID of the transaction of the given nonce which
+// was applied from the account id, should there be one.
+func (c *CollapseContext) ReadAccountNonce(id AccountID, nonce uint64) (TransactionID, bool) {
+	if tx, ok := c.nonces[senderNonce{sender: id, nonce: nonce}]; ok {
+		return tx, true
+	}
+
+	return ReadAccountNonce(c.tree, id, nonce)
+}
+
+func (c *CollapseContext) WriteAccountNonce(id AccountID, nonce uint64, tx TransactionID) {
+	key := senderNonce{sender: id, nonce: nonce}
+
+	if _, ok := c.nonces[key]; !ok {
+		c.nonceKeys = append(c.nonceKeys, key)
+	}
+
+	c.nonces[key] = tx
+}
+
+// WriteContractStorage sets key of the storage of the contract id to value,
+// removing it should value be empty.
+func (c *CollapseContext) WriteContractStorage(id AccountID, key, value []byte) {
+	c.addAccount(id)
+
+	k := string(contractStorageKey(id, key))
+
+	if _, ok := c.storage[k]; !ok {
+		c.storageKeys = append(c.storageKeys, k)
+	}
+
+	c.storage[k] = value
+}
+
+func (c *CollapseContext) WriteAccountContractUpgrader(id AccountID, upgrader AccountID) {
+	c.addAccount(id)
+	c.contractUpgraders[id] = upgrader
+}
+
+func (c *CollapseContext) WriteAccountContractUpgrades(id AccountID, upgrades []ContractUpgrade) {
+	c.addAccount(id)
+	c.contractUpgrades[id] = upgrades
+}
+
+// ResetContractState discards the memory and globals of the contract id, such
+// that it is next executed as though it was just spawned.
+func (c *CollapseContext) ResetContractState(id AccountID) {
+	c.addAccount(id)
+
+	c.contractResets[id] = struct{}{}
+	c.contractMemory[id] = nil
+
+	delete(c.contractVMs, id)
+	c.VMCache.Remove(id)
+}
+
+// contractStateReset returns whether the state of the contract id was reset,
+// and the contract is yet to be executed since.
+func (c *CollapseContext) contractStateReset(id AccountID) bool {
+	if _, reset := c.contractResets[id]; !reset {
+		return false
+	}
+
+	_, executed := c.contractVMs[id]
+
+	return !executed
+}
+
 func (c *CollapseContext) SetContractState(id AccountID, state *VMState) {
 	c.addAccount(id)
+
+	if c.diff != nil {
+		// The memory of a contract is modified in place while it is executed, so
+		// compare against a copy of the memory as of the last call instead.
+		before, ok := c.contractMemory[id]
+		if !ok {
+			before = LoadContractMemorySnapshot(c.tree, id)
+		}
+
+		c.diff.recordPages(id, changedPages(before, state.Memory))
+		c.contractMemory[id] = append([]byte(nil), state.Memory...)
+	}
+
 	c.contractVMs[id] = state
 }
 
+// recordDiff starts recording the changes made to accounts by the next
+// transaction to be applied.
+func (c *CollapseContext) recordDiff() {
+	c.diff = newStateDiff()
+}
+
+// takeDiff stops recording changes, and returns the changes recorded for the
+// transaction with ID id.
+func (c *CollapseContext) takeDiff(id TransactionID) TransactionDiff {
+	diff := c.diff.finish(id)
+	c.diff = nil
+
+	return diff
+}
+
+// emitLogs keeps the logs emitted by a smart contract function which
+// succeeded.
+func (c *CollapseContext) emitLogs(logs []ContractLog) {
+	c.logs = append(c.logs, logs...)
+}
+
+// takeLogs returns the logs emitted while applying the transaction with ID id
+// in the block with index block, and forgets them.
+func (c *CollapseContext) takeLogs(block uint64, id TransactionID) []ContractLog {
+	logs := c.logs
+	c.logs = nil
+
+	for i := range logs {
+		logs[i].Block = block
+		logs[i].TxID = id
+	}
+
+	return logs
+}
+
 func (c *CollapseContext) StoreRewardWithdrawalRequest(rw RewardWithdrawalRequest) {
 	c.rewardWithdrawalRequests = append(c.rewardWithdrawalRequests, rw)
 }
@@ -371,12 +1061,92 @@ func (c *CollapseContext) Flush() error {
 			WriteAccountContractCode(c.tree, id, code)
 		}
 
+		if allowance, ok := c.feeAllowances[id]; ok {
+			WriteAccountFeeAllowance(c.tree, id, allowance)
+		}
+
+		if config, ok := c.recoveryConfigs[id]; ok {
+			WriteAccountRecoveryConfig(c.tree, id, config)
+		}
+
+		if recovery, ok := c.pendingRecoveries[id]; ok {
+			WriteAccountPendingRecovery(c.tree, id, recovery)
+		}
+
+		if upgrader, ok := c.contractUpgraders[id]; ok {
+			WriteAccountContractUpgrader(c.tree, id, upgrader)
+		}
+
+		if upgrades, ok := c.contractUpgrades[id]; ok {
+			WriteAccountContractUpgrades(c.tree, id, upgrades)
+		}
+
+		if _, ok := c.contractResets[id]; ok {
+			DeleteAccountContractState(c.tree, id)
+		}
+
+		if multisig, ok := c.multisigs[id]; ok {
+			WriteAccountMultisig(c.tree, id, multisig)
+		}
+
+		if delegators, ok := c.delegators[id]; ok {
+			WriteAccountDelegators(c.tree, id, delegators)
+		}
+
+		if delegations, ok := c.delegations[id]; ok {
+			WriteAccountDelegations(c.tree, id, delegations)
+		}
+
+		if commission, ok := c.commissions[id]; ok {
+			WriteAccountCommission(c.tree, id, commission)
+		}
+
+		if unbondings, ok := c.unbondings[id]; ok {
+			WriteAccountUnbondings(c.tree, id, unbondings)
+		}
+
+		if block, ok := c.lastActive[id]; ok {
+			WriteAccountLastActive(c.tree, id, block)
+		}
+
+		if events, ok := c.slashings[id]; ok {
+			WriteAccountSlashings(c.tree, id, events)
+		}
+
 		if vm, ok := c.contractVMs[id]; ok {
 			SaveContractMemorySnapshot(c.tree, id, vm.Memory)
 			SaveContractGlobals(c.tree, id, vm.Globals)
 		}
 	}
 
+	for _, index := range c.beaconIndices {
+		WriteBeacon(c.tree, index, c.beacons[index])
+	}
+
+	for _, name := range c.nameKeys {
+		WriteName(c.tree, name, c.names[name])
+	}
+
+	for _, id := range c.dataIDs {
+		WriteData(c.tree, id, c.data[id])
+	}
+
+	for _, id := range c.multisigProposalIDs {
+		WriteMultisigProposal(c.tree, id, c.multisigProposals[id])
+	}
+
+	for _, key := range c.nonceKeys {
+		WriteAccountNonce(c.tree, key.sender, key.nonce, c.nonces[key])
+	}
+
+	for _, k := range c.storageKeys {
+		if value := c.storage[k]; len(value) > 0 {
+			c.tree.Insert([]byte(k), value)
+		} else {
+			c.tree.Delete([]byte(k))
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,209 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// isolatedAccountKeys are the keys of the state of an account copied into
+// an isolated context.
+var isolatedAccountKeys = [][]byte{
+	keyAccountBalance[:],
+	keyAccountStake[:],
+	keyAccountReward[:],
+	keyAccountFeeAllowance[:],
+}
+
+// isolatedTx is a transaction of a round applied to a context of its own,
+// holding only the state of the accounts it touches.
+type isolatedTx struct {
+	tx *Transaction
+
+	// ctx is nil should the transaction have been rejected before being
+	// applied.
+	ctx    *CollapseContext
+	result roundTxResult
+}
+
+// touchedAccounts returns the accounts tx reads or writes, should they be
+// known before applying it. These are known for transfers to accounts which
+// are not smart contracts and for stakes, which is to say transactions which
+// never execute a smart contract.
+//
+// The state of c is peeked at without being cached, such that c is left as
+// it would be had tx been applied to it directly.
+func touchedAccounts(c *CollapseContext, tx *Transaction) ([]AccountID, bool) {
+	accounts := []AccountID{tx.Sender}
+
+	switch tx.Tag {
+	case sys.TagTransfer:
+		payload, err := ParseTransfer(tx.Payload)
+		if err != nil {
+			return nil, false
+		}
+
+		if _, ok := c.contracts[payload.Recipient]; ok {
+			return nil, false
+		}
+
+		if _, ok := ReadAccountContractCode(c.tree, payload.Recipient); ok {
+			return nil, false
+		}
+
+		accounts = append(accounts, payload.Recipient)
+	case sys.TagStake:
+	default:
+		return nil, false
+	}
+
+	// Fees may be paid by the sponsor of the sender.
+	if hex.EncodeToString(tx.Sender[:]) != sys.FaucetAddress {
+		grant, ok := c.feeAllowances[tx.Sender]
+		if !ok {
+			grant, _ = ReadAccountFeeAllowance(c.tree, tx.Sender)
+		}
+
+		if grant.Allowance > 0 {
+			accounts = append(accounts, grant.Sponsor)
+		}
+	}
+
+	return accounts, true
+}
+
+// isolate returns a context of its own holding the state of accounts as
+// seen by c, to which a transaction touching only accounts may be applied
+// concurrently with others. Its changes are then merged into c.
+func (c *CollapseContext) isolate(accounts []AccountID) *CollapseContext {
+	tree := avl.New(store.NewInmem())
+
+	for _, id := range accounts {
+		for _, key := range isolatedAccountKeys {
+			if buf, exists := readUnderAccounts(c.tree, id, key); exists {
+				writeUnderAccounts(tree, id, key, buf)
+			}
+		}
+
+		// Changes not yet flushed by c take precedence over its tree.
+
+		if balance, ok := c.balances[id]; ok {
+			WriteAccountBalance(tree, id, balance)
+		}
+
+		if stake, ok := c.stakes[id]; ok {
+			WriteAccountStake(tree, id, stake)
+		}
+
+		if reward, ok := c.rewards[id]; ok {
+			WriteAccountReward(tree, id, reward)
+		}
+
+		if allowance, ok := c.feeAllowances[id]; ok {
+			WriteAccountFeeAllowance(tree, id, allowance)
+		}
+	}
+
+	return NewCollapseContext(tree)
+}
+
+// merge applies the changes made to the isolated context child to c, as
+// though they had been made to c directly. Accounts are added to c in the
+// order child first wrote to them, such that c is flushed the same way.
+func (c *CollapseContext) merge(child *CollapseContext) {
+	// Values read by child are cached by c as they would have been had
+	// they been read through c.
+
+	for id, balance := range child.balances {
+		c.balances[id] = balance
+	}
+
+	for id, stake := range child.stakes {
+		c.stakes[id] = stake
+	}
+
+	for id, reward := range child.rewards {
+		c.rewards[id] = reward
+	}
+
+	for id, allowance := range child.feeAllowances {
+		c.feeAllowances[id] = allowance
+	}
+
+	for _, id := range child.accountIDs {
+		c.addAccount(id)
+	}
+
+	c.rewardWithdrawalRequests = append(c.rewardWithdrawalRequests, child.rewardWithdrawalRequests...)
+}
+
+// applyIsolated applies every transaction of batch with a context to it,
+// concurrently across as many workers as configured by conf.GetApplyWorkers.
+func applyIsolated(block *Block, batch []*isolatedTx) {
+	pending := make([]*isolatedTx, 0, len(batch))
+
+	for _, item := range batch {
+		if item.ctx != nil {
+			pending = append(pending, item)
+		}
+	}
+
+	workers := conf.GetApplyWorkers()
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+
+	if workers <= 1 {
+		for _, item := range pending {
+			item.result = applyRoundTransaction(item.ctx, block, item.tx)
+		}
+
+		return
+	}
+
+	jobs := make(chan *isolatedTx, len(pending))
+
+	for _, item := range pending {
+		jobs <- item
+	}
+
+	close(jobs)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for item := range jobs {
+				item.result = applyRoundTransaction(item.ctx, block, item.tx)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
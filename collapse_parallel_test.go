@@ -0,0 +1,173 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollapseTransactionsParallel(t *testing.T) {
+	keys := make([]*skademlia.Keypair, 8)
+
+	for i := range keys {
+		k, err := skademlia.NewKeys(1, 1)
+		require.NoError(t, err)
+
+		keys[i] = k
+	}
+
+	code, err := ioutil.ReadFile("testdata/transfer_back.wasm")
+	require.NoError(t, err)
+
+	transfer := func(from, to int, nonce, amount uint64) *Transaction {
+		payload, err := Transfer{Recipient: keys[to].PublicKey(), Amount: amount}.Marshal()
+		require.NoError(t, err)
+
+		tx := NewTransaction(keys[from], nonce, 0, sys.TagTransfer, payload)
+
+		return &tx
+	}
+
+	stake := func(from int, nonce uint64, opcode byte, amount uint64) *Transaction {
+		payload, err := Stake{Opcode: opcode, Amount: amount}.Marshal()
+		require.NoError(t, err)
+
+		tx := NewTransaction(keys[from], nonce, 0, sys.TagStake, payload)
+
+		return &tx
+	}
+
+	spawnPayload, err := buildContractSpawnPayload(100000, 0, code).Marshal()
+	require.NoError(t, err)
+
+	spawn := NewTransaction(keys[4], 1, 0, sys.TagContract, spawnPayload)
+
+	txs := []*Transaction{
+		// Transfers between distinct accounts are applied concurrently.
+		transfer(0, 1, 1, 100),
+		transfer(2, 3, 1, 200),
+		stake(4, 2, sys.PlaceStake, sys.MinimumStake),
+		// Conflicts with the first transfer, and so is applied after it.
+		transfer(1, 5, 1, 50),
+		// Rejected for sending more than the sender has.
+		transfer(6, 7, 1, 1<<40),
+		// Smart contracts are applied on their own.
+		&spawn,
+		transfer(4, 0, 3, 10),
+		stake(0, 2, sys.WithdrawReward, sys.MinimumRewardWithdraw),
+		transfer(3, 2, 2, 150),
+	}
+
+	collapse := func(workers int) *collapseResults {
+		defaultWorkers := conf.GetApplyWorkers()
+		conf.Update(conf.WithApplyWorkers(workers))
+
+		defer func() {
+			conf.Update(conf.WithApplyWorkers(defaultWorkers))
+		}()
+
+		accounts := NewAccounts(store.NewInmem())
+
+		for i, k := range keys {
+			WriteAccountBalance(accounts.tree, k.PublicKey(), 1000000*uint64(i+1))
+		}
+
+		WriteAccountStake(accounts.tree, keys[0].PublicKey(), sys.MinimumStake)
+		WriteAccountReward(accounts.tree, keys[0].PublicKey(), sys.MinimumRewardWithdraw)
+
+		block := NewBlock(0, accounts.tree.Checksum())
+
+		res, err := collapseTransactions(block.Index+1, txs, &block, accounts)
+		require.NoError(t, err)
+
+		return res
+	}
+
+	serial, parallel := collapse(1), collapse(4)
+
+	assert.Len(t, serial.applied, len(txs)-1)
+	assert.Len(t, serial.rejected, 1)
+
+	// Transactions applied concurrently leave the same state behind as had
+	// they been applied one at a time.
+	assert.Equal(t, serial.snapshot.Checksum(), parallel.snapshot.Checksum())
+	assert.Equal(t, serial.applied, parallel.applied)
+	assert.Equal(t, serial.rejected, parallel.rejected)
+
+	if assert.Len(t, parallel.rejectedErrors, len(serial.rejectedErrors)) {
+		for i, err := range serial.rejectedErrors {
+			assert.EqualError(t, parallel.rejectedErrors[i], err.Error())
+		}
+	}
+
+	assert.Equal(t, serial.diffs, parallel.diffs)
+	assert.Equal(t, serial.blockDiff.Accounts, parallel.blockDiff.Accounts)
+	assert.Equal(t, serial.ctx.accountIDs, parallel.ctx.accountIDs)
+}
+
+func TestTouchedAccounts(t *testing.T) {
+	accounts := NewAccounts(store.NewInmem())
+	ctx := NewCollapseContext(accounts.tree)
+
+	alice, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	bob, sponsor, contract := AccountID{1}, AccountID{2}, AccountID{3}
+
+	WriteAccountContractCode(accounts.tree, contract, []byte{1})
+
+	transfer := func(recipient AccountID) *Transaction {
+		payload, err := Transfer{Recipient: recipient, Amount: 1}.Marshal()
+		require.NoError(t, err)
+
+		tx := NewTransaction(alice, 1, 0, sys.TagTransfer, payload)
+
+		return &tx
+	}
+
+	touched, ok := touchedAccounts(ctx, transfer(bob))
+	assert.True(t, ok)
+	assert.Equal(t, []AccountID{alice.PublicKey(), bob}, touched)
+
+	// Transfers to smart contracts may touch any account.
+	_, ok = touchedAccounts(ctx, transfer(contract))
+	assert.False(t, ok)
+
+	// Fees may be paid by sponsors.
+	ctx.WriteAccountFeeAllowance(alice.PublicKey(), FeeAllowance{Sponsor: sponsor, Allowance: 10})
+
+	touched, ok = touchedAccounts(ctx, transfer(bob))
+	assert.True(t, ok)
+	assert.Equal(t, []AccountID{alice.PublicKey(), bob, sponsor}, touched)
+
+	// Peeking at the state leaves the context untouched.
+	_, cached := ctx.balances[bob]
+	assert.False(t, cached)
+}
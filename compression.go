@@ -0,0 +1,154 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package wavelet
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// Encodings gossiped batches of transactions may be compressed with.
+const (
+	CompressionNone   = "none"
+	CompressionSnappy = "snappy"
+	CompressionZstd   = "zstd"
+)
+
+// acceptEncodingKey is the header a node responds to gossip with, listing the
+// encodings it accepts gossip compressed with.
+const acceptEncodingKey = "wavelet-accept-encoding"
+
+// maxDecompressedSize is the largest a compressed batch of transactions may
+// be once decompressed, such that peers may not exhaust our memory.
+const maxDecompressedSize = 64 * 1024 * 1024
+
+// zstdEncoder compresses gossip with zstd. Its EncodeAll may be called
+// concurrently, and it may only fail to be created given invalid options.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// ValidCompression returns an error should compression not name an encoding
+// gossip may be compressed with.
+func ValidCompression(compression string) error {
+	switch compression {
+	case CompressionNone, CompressionSnappy, CompressionZstd:
+		return nil
+	default:
+		return errors.Errorf("unknown compression %q, must be one of %s, %s or %s",
+			compression, CompressionZstd, CompressionSnappy, CompressionNone)
+	}
+}
+
+// acceptedEncodings returns the encodings a node compressing gossip with
+// compression accepts gossip compressed with. Nodes not compressing gossip,
+// so as to spare their CPU, accept no compressed gossip.
+func acceptedEncodings(compression string) []string {
+	if compression == CompressionNone {
+		return []string{CompressionNone}
+	}
+
+	return []string{CompressionZstd, CompressionSnappy, CompressionNone}
+}
+
+// negotiateEncoding returns the encoding to compress gossip to a peer with,
+// being the one our node prefers should the peer accept it, then snappy, and
+// otherwise none. The encodings the peer accepts are read from the header md
+// it responded to gossip with. Peers not responding with them, such as older
+// nodes, do not accept compressed gossip.
+func negotiateEncoding(preferred string, md metadata.MD) string {
+	accepted := make(map[string]struct{})
+
+	for _, value := range md.Get(acceptEncodingKey) {
+		for _, encoding := range strings.Split(value, ",") {
+			accepted[strings.TrimSpace(encoding)] = struct{}{}
+		}
+	}
+
+	for _, encoding := range []string{preferred, CompressionSnappy} {
+		if encoding == CompressionNone {
+			break
+		}
+
+		if _, ok := accepted[encoding]; ok {
+			return encoding
+		}
+	}
+
+	return CompressionNone
+}
+
+func compress(encoding string, buf []byte) ([]byte, error) {
+	switch encoding {
+	case CompressionSnappy:
+		return snappy.Encode(nil, buf), nil
+	case CompressionZstd:
+		return zstdEncoder.EncodeAll(buf, nil), nil
+	default:
+		return nil, errors.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+func decompress(encoding string, buf []byte) ([]byte, error) {
+	switch encoding {
+	case CompressionSnappy:
+		size, err := snappy.DecodedLen(buf)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress with snappy")
+		}
+
+		if size > maxDecompressedSize {
+			return nil, errors.Errorf("decompressed size of %d bytes exceeds the max of %d bytes",
+				size, maxDecompressedSize)
+		}
+
+		decompressed, err := snappy.Decode(nil, buf)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress with snappy")
+		}
+
+		return decompressed, nil
+	case CompressionZstd:
+		r, err := zstd.NewReader(bytes.NewReader(buf),
+			zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxDecompressedSize))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress with zstd")
+		}
+
+		defer r.Close()
+
+		decompressed, err := ioutil.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress with zstd")
+		}
+
+		if len(decompressed) > maxDecompressedSize {
+			return nil, errors.Errorf("decompressed size exceeds the max of %d bytes", maxDecompressedSize)
+		}
+
+		return decompressed, nil
+	default:
+		return nil, errors.Errorf("unknown encoding %q", encoding)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+// +build unit
+
+package wavelet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCompression(t *testing.T) {
+	buf := bytes.Repeat([]byte("wavelet"), 1024)
+
+	for _, enc := range []string{CompressionSnappy, CompressionZstd} {
+		compressed, err := compress(enc, buf)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		assert.Less(t, len(compressed), len(buf)/10, enc)
+
+		decompressed, err := decompress(enc, compressed)
+		assert.NoError(t, err)
+		assert.Equal(t, buf, decompressed)
+
+		_, err = decompress(enc, buf)
+		assert.Error(t, err, enc)
+	}
+
+	// Batches decompressing to more than the max are rejected.
+	bomb, err := compress(CompressionZstd, make([]byte, maxDecompressedSize+1))
+	if assert.NoError(t, err) {
+		_, err = decompress(CompressionZstd, bomb)
+		assert.Error(t, err)
+	}
+
+	_, err = compress("lz4", buf)
+	assert.Error(t, err)
+
+	assert.NoError(t, ValidCompression(CompressionNone))
+	assert.Error(t, ValidCompression("lz4"))
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	accepting := func(encodings ...string) metadata.MD {
+		md := metadata.MD{}
+
+		for _, enc := range encodings {
+			md.Append(acceptEncodingKey, enc)
+		}
+
+		return md
+	}
+
+	assert.Equal(t, CompressionZstd, negotiateEncoding(CompressionZstd, accepting("zstd,snappy,none")))
+	assert.Equal(t, CompressionSnappy, negotiateEncoding(CompressionSnappy, accepting("zstd", "snappy")))
+	assert.Equal(t, CompressionSnappy, negotiateEncoding(CompressionZstd, accepting("snappy, none")))
+	assert.Equal(t, CompressionNone, negotiateEncoding(CompressionZstd, accepting("none")))
+	assert.Equal(t, CompressionNone, negotiateEncoding(CompressionNone, accepting("zstd,snappy,none")))
+
+	// Older nodes respond with no encodings.
+	assert.Equal(t, CompressionNone, negotiateEncoding(CompressionZstd, metadata.MD{}))
+}
+
+func TestGossipCompressed(t *testing.T) {
+	testnet, err := NewTestNetwork()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer testnet.Cleanup()
+
+	alice, err := testnet.AddNode()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	conn, err := alice.Client().Dial(testnet.Faucet().Addr())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	client := NewWaveletClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// The encodings accepted are negotiated by responding to gossip.
+	var header metadata.MD
+
+	_, err = client.Gossip(ctx, &GossipRequest{}, grpc.Header(&header))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"zstd,snappy,none"}, header.Get(acceptEncodingKey))
+	assert.Equal(t, CompressionZstd, negotiateEncoding(CompressionZstd, header))
+
+	payload, err := Transfer{Recipient: alice.PublicKey(), Amount: 1}.Marshal()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tx := NewTransaction(testnet.Faucet().Keys(), 1, 0, sys.TagTransfer, payload)
+
+	raw, err := (&GossipRequest{Txs: []*ledgerpb.Transaction{tx.Proto()}}).Marshal()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	compressed, err := compress(CompressionZstd, raw)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = client.Gossip(ctx, &GossipRequest{Encoding: CompressionZstd, Compressed: compressed})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, testnet.Faucet().Ledger().Transactions().Has(tx.ID))
+
+	// Batches failing to decompress are rejected.
+	_, err = client.Gossip(ctx, &GossipRequest{Encoding: CompressionSnappy, Compressed: compressed})
+	assert.Error(t, err)
+}
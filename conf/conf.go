@@ -2,6 +2,7 @@ package conf
 
 import (
 	"fmt"
+	"runtime"
 	"sync"
 	"time"
 
@@ -45,6 +46,12 @@ type config struct {
 	// Max number of transactions within the block
 	blockTxLimit uint64
 
+	// Number of workers applying non-conflicting transactions of a block concurrently
+	applyWorkers int
+
+	// Number of compiled smart contracts cached, none being cached should it be 0
+	contractModuleCacheSize int
+
 	// shared secret for http api authorization
 	secret string
 }
@@ -82,6 +89,10 @@ func defaultConfig() config {
 		pruningLimit: 30,
 
 		blockTxLimit: 1 << 16,
+
+		applyWorkers: runtime.NumCPU(),
+
+		contractModuleCacheSize: 128,
 	}
 
 	if sys.VersionMeta == "testnet" {
@@ -91,6 +102,33 @@ func defaultConfig() config {
 	return defConf
 }
 
+// Ranges the Snowball consensus protocol parameters must fall within. Alpha
+// must be a strict majority, being above 0.5 and at most 1.
+const (
+	MinSnowballK    = 1
+	MaxSnowballK    = 64
+	MinSnowballBeta = 1
+	MaxSnowballBeta = 10000
+)
+
+// ValidateSnowball returns an error should any of the Snowball consensus
+// protocol parameters k, alpha and beta fall out of their ranges.
+func ValidateSnowball(k int, alpha float64, beta int) error {
+	if k < MinSnowballK || k > MaxSnowballK {
+		return fmt.Errorf("snowball k must be between %d and %d, but got %d", MinSnowballK, MaxSnowballK, k)
+	}
+
+	if !(alpha > 0.5 && alpha <= 1) {
+		return fmt.Errorf("snowball alpha must be above 0.5 and at most 1, but got %g", alpha)
+	}
+
+	if beta < MinSnowballBeta || beta > MaxSnowballBeta {
+		return fmt.Errorf("snowball beta must be between %d and %d, but got %d", MinSnowballBeta, MaxSnowballBeta, beta)
+	}
+
+	return nil
+}
+
 type Option func(*config)
 
 // updateWeights update transaction number and round depth weights for consensus vote counting
@@ -106,6 +144,12 @@ func WithSnowballK(sk int) Option {
 	}
 }
 
+func WithSnowballAlpha(sa float64) Option {
+	return func(c *config) {
+		c.SnowballAlpha = sa
+	}
+}
+
 func WithSyncVoteThreshold(sa float64) Option {
 	return func(c *config) {
 		c.syncVoteThreshold = sa
@@ -208,6 +252,18 @@ func WithMissingTxPullLimit(n uint64) Option {
 	}
 }
 
+func WithApplyWorkers(n int) Option {
+	return func(c *config) {
+		c.applyWorkers = n
+	}
+}
+
+func WithContractModuleCacheSize(n int) Option {
+	return func(c *config) {
+		c.contractModuleCacheSize = n
+	}
+}
+
 func GetSnowballK() int {
 	l.RLock()
 	t := c.snowballK
@@ -360,6 +416,22 @@ func GetMissingTxPullLimit() uint64 {
 	return t
 }
 
+func GetApplyWorkers() int {
+	l.RLock()
+	t := c.applyWorkers
+	l.RUnlock()
+
+	return t
+}
+
+func GetContractModuleCacheSize() int {
+	l.RLock()
+	t := c.contractModuleCacheSize
+	l.RUnlock()
+
+	return t
+}
+
 func Update(options ...Option) {
 	l.Lock()
 
@@ -12,6 +12,7 @@ import (
 func TestGet(t *testing.T) {
 	assert.EqualValues(t, 2, GetSnowballK())
 	assert.EqualValues(t, 150, GetSnowballBeta())
+	assert.EqualValues(t, 0.8, GetSnowballAlpha())
 
 	assert.EqualValues(t, 0.8, GetSyncVoteThreshold())
 	assert.EqualValues(t, 0.8, GetFinalizationVoteThreshold())
@@ -35,6 +36,7 @@ func TestUpdate(t *testing.T) {
 	Update(
 		WithSnowballK(10),
 		WithSnowballBeta(69),
+		WithSnowballAlpha(0.9),
 
 		WithSyncVoteThreshold(1),
 		WithFinalizationVoteThreshold(2),
@@ -54,6 +56,7 @@ func TestUpdate(t *testing.T) {
 
 	assert.EqualValues(t, 10, GetSnowballK())
 	assert.EqualValues(t, 69, GetSnowballBeta())
+	assert.EqualValues(t, 0.9, GetSnowballAlpha())
 
 	assert.EqualValues(t, 1, GetSyncVoteThreshold())
 	assert.EqualValues(t, 2, GetFinalizationVoteThreshold())
@@ -71,6 +74,19 @@ func TestUpdate(t *testing.T) {
 	assert.EqualValues(t, "shambles", GetSecret())
 }
 
+func TestValidateSnowball(t *testing.T) {
+	assert.NoError(t, ValidateSnowball(GetSnowballK(), GetSnowballAlpha(), GetSnowballBeta()))
+	assert.NoError(t, ValidateSnowball(MinSnowballK, 1, MinSnowballBeta))
+	assert.NoError(t, ValidateSnowball(MaxSnowballK, 0.51, MaxSnowballBeta))
+
+	assert.Error(t, ValidateSnowball(MinSnowballK-1, 0.8, 150))
+	assert.Error(t, ValidateSnowball(MaxSnowballK+1, 0.8, 150))
+	assert.Error(t, ValidateSnowball(10, 0.5, 150))
+	assert.Error(t, ValidateSnowball(10, 1.01, 150))
+	assert.Error(t, ValidateSnowball(10, 0.8, MinSnowballBeta-1))
+	assert.Error(t, ValidateSnowball(10, 0.8, MaxSnowballBeta+1))
+}
+
 func resetConfig() {
 	c = defaultConfig()
 }
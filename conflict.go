@@ -0,0 +1,108 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+)
+
+const alertWebhookTimeout = 5 * time.Second
+
+type senderNonce struct {
+	sender AccountID
+	nonce  uint64
+}
+
+// TransactionConflict is a pair of distinct transactions from the same sender
+// sharing the same nonce. As wallets pick a fresh nonce for every transaction,
+// a conflict suggests the sender is attempting to double-spend.
+type TransactionConflict struct {
+	Sender AccountID
+	Nonce  uint64
+
+	First  TransactionID // The transaction observed first.
+	Second TransactionID // The transaction observed last.
+}
+
+// reportConflicts raises an alert for every conflict, by counting it in our
+// metrics, emitting it as an event to websocket clients, and posting it to all
+// of our alert webhooks.
+func (l *Ledger) reportConflicts(conflicts []TransactionConflict) {
+	if len(conflicts) == 0 {
+		return
+	}
+
+	l.metrics.conflictedTX.Inc(int64(len(conflicts)))
+
+	for _, conflict := range conflicts {
+		logger := log.TX(events.EventTxConflict)
+		logger.Warn().
+			Hex("tx_id", conflict.Second[:]).
+			Hex("conflicting_tx_id", conflict.First[:]).
+			Hex("sender_id", conflict.Sender[:]).
+			Uint64("nonce", conflict.Nonce).
+			Msg("Observed conflicting transactions from the same sender.")
+
+		if len(l.alertWebhooks) == 0 {
+			continue
+		}
+
+		body, err := events.TxConflict{
+			TxID:            conflict.Second,
+			ConflictingTxID: conflict.First,
+			SenderID:        conflict.Sender,
+			Nonce:           conflict.Nonce,
+			Time:            time.Now(),
+			Message:         "Observed conflicting transactions from the same sender.",
+		}.MarshalJSON()
+		if err != nil {
+			continue
+		}
+
+		for _, url := range l.alertWebhooks {
+			go l.postAlert(url, body)
+		}
+	}
+}
+
+// postAlert posts body as JSON to the webhook at url.
+func (l *Ledger) postAlert(url string, body []byte) {
+	client := http.Client{Timeout: alertWebhookTimeout}
+
+	res, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err == nil {
+		_ = res.Body.Close()
+
+		if res.StatusCode/100 != 2 {
+			err = errors.Errorf("webhook responded with status %d", res.StatusCode)
+		}
+	}
+
+	if err != nil {
+		logger := log.Node()
+		logger.Warn().Err(err).Str("url", url).Msg("Failed to post alert to webhook.")
+	}
+}
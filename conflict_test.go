@@ -0,0 +1,214 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionsConflicts(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	manager := NewTransactions(Block{})
+
+	first := NewTransaction(keys, 1, 0, sys.TagTransfer, []byte("first"))
+	second := NewTransaction(keys, 1, 0, sys.TagTransfer, []byte("second"))
+	other := NewTransaction(keys, 2, 0, sys.TagTransfer, []byte("second"))
+
+	assert.Empty(t, manager.BatchAdd([]Transaction{first, other}))
+
+	// Re-adding a transaction does not conflict with itself.
+	assert.Empty(t, manager.BatchAdd([]Transaction{first}))
+
+	assert.Equal(t, []TransactionConflict{
+		{Sender: keys.PublicKey(), Nonce: 1, First: first.ID, Second: second.ID},
+	}, manager.BatchAdd([]Transaction{second}))
+}
+
+func TestTransactionsReplace(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureReplacement)()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	tipped := func(tip uint64) Transaction {
+		tx, err := NewTippedTransaction(security.NewEd25519Signer(keys.PrivateKey()), 1, 0, sys.TagTransfer, nil, tip)
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	manager := NewTransactions(Block{})
+
+	original, cheaper, replacement := tipped(1), tipped(0), tipped(2)
+
+	assert.Empty(t, manager.BatchAdd([]Transaction{original}))
+
+	// Transactions paying no higher a fee may not replace a pending transaction.
+	assert.True(t, errors.Cause(manager.CheckReplacement(cheaper)) == ErrTxNotReplaceable)
+	assert.Equal(t, []TransactionConflict{
+		{Sender: keys.PublicKey(), Nonce: 1, First: original.ID, Second: cheaper.ID},
+	}, manager.BatchAdd([]Transaction{cheaper}))
+	assert.Equal(t, []TransactionID{original.ID}, manager.PendingIDs())
+
+	// Transactions paying a higher fee replace it without conflicting.
+	assert.NoError(t, manager.CheckReplacement(replacement))
+	assert.Empty(t, manager.BatchAdd([]Transaction{replacement}))
+	assert.Equal(t, []TransactionID{replacement.ID}, manager.PendingIDs())
+
+	// Finalized transactions may no longer be replaced, and supersede any
+	// pending transaction of the same nonce.
+	manager.ReshufflePending(NewBlock(1, ZeroMerkleNodeID, original.ID))
+
+	assert.Empty(t, manager.PendingIDs())
+	assert.True(t, errors.Cause(manager.CheckReplacement(tipped(3))) == ErrTxNotReplaceable)
+}
+
+func TestTransactionsReplaceUnscheduled(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	original, err := NewTippedTransaction(security.NewEd25519Signer(keys.PrivateKey()), 1, 0, sys.TagTransfer, nil, 1)
+	require.NoError(t, err)
+
+	replacement, err := NewTippedTransaction(security.NewEd25519Signer(keys.PrivateKey()), 1, 0, sys.TagTransfer, nil, 2)
+	require.NoError(t, err)
+
+	manager := NewTransactions(Block{})
+
+	assert.Empty(t, manager.BatchAdd([]Transaction{original}))
+
+	// Transactions may not be replaced until replacement is scheduled.
+	assert.True(t, errors.Cause(manager.CheckReplacement(replacement)) == ErrTxNotReplaceable)
+	assert.Len(t, manager.BatchAdd([]Transaction{replacement}), 1)
+	assert.Equal(t, []TransactionID{original.ID}, manager.PendingIDs())
+}
+
+func TestCollapseReplacedTransactions(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureReplacement)()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	accounts := NewAccounts(store.NewInmem())
+	WriteAccountBalance(accounts.tree, keys.PublicKey(), initialBalance)
+
+	payload, err := Transfer{Recipient: AccountID{1}, Amount: 1}.Marshal()
+	require.NoError(t, err)
+
+	tipped := func(nonce, tip uint64) *Transaction {
+		tx, err := NewTippedTransaction(
+			security.NewEd25519Signer(keys.PrivateKey()), nonce, 0, sys.TagTransfer, payload, tip,
+		)
+		require.NoError(t, err)
+
+		return &tx
+	}
+
+	// Of transactions of the same nonce, only the one paying the highest fee
+	// is applied, the first one listed being kept should they pay the same.
+	original, replacement, tie, other := tipped(1, 1), tipped(1, 2), tipped(1, 2), tipped(2, 0)
+
+	block := NewBlock(1, accounts.tree.Checksum())
+
+	res, err := collapseTransactions(block.Index, []*Transaction{original, replacement, tie, other}, &block, accounts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Transaction{replacement, other}, res.applied)
+	assert.Equal(t, []*Transaction{original, tie}, res.rejected)
+
+	for _, err := range res.rejectedErrors {
+		assert.Equal(t, ErrTxReplaced, errors.Cause(err))
+	}
+
+	require.NoError(t, accounts.Commit(res.snapshot))
+
+	// Transactions of a nonce a transaction was applied with in a previous
+	// block are rejected, however high a fee they pay.
+	late, next := tipped(1, 3), tipped(3, 0)
+
+	block = NewBlock(2, accounts.tree.Checksum())
+
+	res, err = collapseTransactions(block.Index, []*Transaction{late, next}, &block, accounts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Transaction{next}, res.applied)
+	assert.Equal(t, []*Transaction{late}, res.rejected)
+
+	if assert.Len(t, res.rejectedErrors, 1) {
+		assert.Equal(t, ErrTxNonceUsed, errors.Cause(res.rejectedErrors[0]))
+	}
+}
+
+func TestReportConflicts(t *testing.T) {
+	alerts := make(chan []byte, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		alerts <- body
+	}))
+	defer server.Close()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	ledger, err := NewLedger(
+		store.NewInmem(), skademlia.NewClient(":0", keys), WithoutGC(), WithAlertWebhooks(server.URL),
+	)
+	require.NoError(t, err)
+
+	defer ledger.Close()
+
+	first := NewTransaction(keys, 1, 0, sys.TagTransfer, []byte("first"))
+	second := NewTransaction(keys, 1, 0, sys.TagTransfer, []byte("second"))
+
+	ledger.AddTransaction(first, second)
+
+	assert.EqualValues(t, 1, ledger.metrics.conflictedTX.Count())
+
+	select {
+	case body := <-alerts:
+		var alert events.TxConflict
+
+		require.NoError(t, alert.UnmarshalJSON(body))
+		assert.Equal(t, [32]byte(second.ID), alert.TxID)
+		assert.Equal(t, [32]byte(first.ID), alert.ConflictingTxID)
+		assert.Equal(t, [32]byte(keys.PublicKey()), alert.SenderID)
+		assert.EqualValues(t, 1, alert.Nonce)
+	case <-time.After(alertWebhookTimeout):
+		t.Fatal("timed out waiting for alert to be posted to webhook")
+	}
+}
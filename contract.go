@@ -25,6 +25,7 @@ import (
 	"crypto/sha512"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"reflect"
 	"unsafe"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/perlin-network/life/utils"
 	"github.com/perlin-network/noise/edwards25519"
 	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/internal/groth16"
 	"github.com/perlin-network/wavelet/sys"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/blake2b"
@@ -43,6 +45,7 @@ var (
 
 	_ exec.ImportResolver = (*ContractExecutor)(nil)
 	_ compiler.GasPolicy  = (*ContractExecutor)(nil)
+	_ compiler.GasPolicy  = (*sys.GasSchedule)(nil)
 )
 
 const (
@@ -59,6 +62,32 @@ type ContractExecutor struct {
 	Error   []byte
 
 	Queue []*Transaction
+
+	// Logs emitted through _emit_log, in order of emission.
+	Logs []ContractLog
+
+	// The ledger state and latest finalized block the contract is executed
+	// against.
+	tree  *avl.Tree
+	block *Block
+
+	// The gas schedule applying to the block the contract is executed in.
+	schedule *sys.GasSchedule
+
+	// CallResult is the data the contract last called through _call_contract
+	// reported through _result.
+	CallResult []byte
+
+	// Changes made by the contracts called, nil should calls be unavailable.
+	calls *contractCalls
+
+	// The executor of the contract which called this one, if any, and the
+	// number of calls this one is nested in.
+	caller *ContractExecutor
+	depth  int
+
+	tx      *Transaction
+	vmCache *VMLRU
 }
 
 type VMState struct {
@@ -108,8 +137,47 @@ func SnapshotVMState(vm *exec.VirtualMachine) VMState {
 	}
 }
 
+// GetCost returns the cost of key in the gas schedule the contract is executed under, which is the first version of it
+// should the contract not be executed yet.
 func (e *ContractExecutor) GetCost(key string) int64 {
-	return 1 // FIXME(kenta): Remove for testnet.
+	if e.schedule == nil {
+		return sys.GasSchedules[0].GetCost(key)
+	}
+
+	return e.schedule.GetCost(key)
+}
+
+// charge adds the cost of key, n times over, to the gas used by vm, panicking as the interpreter does should it exceed
+// the gas limit.
+func (e *ContractExecutor) charge(vm *exec.VirtualMachine, key string, n uint64) {
+	cost := uint64(e.GetCost(key))
+
+	if cost != 0 && n > math.MaxUint64/cost {
+		panic("gas limit exceeded")
+	}
+
+	vm.AddAndCheckGas(cost * n)
+}
+
+// height returns the height of the block the contract is executed in, being
+// the block succeeding e.block, or 0 should it be executed outside of any
+// block.
+func (e *ContractExecutor) height() uint64 {
+	if e.block == nil {
+		return 0
+	}
+
+	return e.block.Index + 1
+}
+
+// requireFeature panics as though resolving an unknown import should f be
+// yet to activate at the height the contract is executed in. Contracts
+// importing functions introduced by f hence fail as they did before f
+// activated.
+func (e *ContractExecutor) requireFeature(f sys.Feature) {
+	if !sys.FeatureActive(f, e.height()) {
+		panic("unknown field")
+	}
 }
 
 func (e *ContractExecutor) ResolveFunc(module, field string) exec.FunctionImport {
@@ -138,6 +206,81 @@ func (e *ContractExecutor) ResolveFunc(module, field string) exec.FunctionImport
 					Payload: payload,
 				})
 
+				return 0
+			}
+		case "_call_contract":
+			return func(vm *exec.VirtualMachine) int64 {
+				frame := vm.GetCurrentFrame()
+				idPtr := int(uint32(frame.Locals[0]))
+				amount, gasLimit := uint64(frame.Locals[1]), uint64(frame.Locals[2])
+				namePtr, nameLen := int(uint32(frame.Locals[3])), int(uint32(frame.Locals[4]))
+				paramsPtr, paramsLen := int(uint32(frame.Locals[5])), int(uint32(frame.Locals[6]))
+
+				e.charge(vm, "wavelet.call", 1)
+
+				var id AccountID
+				copy(id[:], vm.Memory[idPtr:idPtr+SizeAccountID])
+
+				name := string(vm.Memory[namePtr : namePtr+nameLen])
+				params := append([]byte(nil), vm.Memory[paramsPtr:paramsPtr+paramsLen]...)
+
+				if err := e.call(vm, id, amount, gasLimit, name, params); err != nil {
+					return 1
+				}
+
+				return 0
+			}
+		case "_storage_get":
+			return func(vm *exec.VirtualMachine) int64 {
+				frame := vm.GetCurrentFrame()
+				keyPtr, keyLen := int(uint32(frame.Locals[0])), int(uint32(frame.Locals[1]))
+				outPtr, outLen := int(uint32(frame.Locals[2])), int(uint32(frame.Locals[3]))
+
+				e.charge(vm, "wavelet.storage.read", 1)
+
+				value, exists, err := e.readStorage(vm.Memory[keyPtr : keyPtr+keyLen])
+				if err != nil || !exists {
+					return -1
+				}
+
+				e.charge(vm, "wavelet.storage.read.byte", uint64(len(value)))
+
+				if outLen > len(value) {
+					outLen = len(value)
+				}
+
+				copy(vm.Memory[outPtr:outPtr+outLen], value)
+
+				return int64(len(value))
+			}
+		case "_storage_set":
+			return func(vm *exec.VirtualMachine) int64 {
+				frame := vm.GetCurrentFrame()
+				keyPtr, keyLen := int(uint32(frame.Locals[0])), int(uint32(frame.Locals[1]))
+				valuePtr, valueLen := int(uint32(frame.Locals[2])), int(uint32(frame.Locals[3]))
+
+				e.charge(vm, "wavelet.storage.write", 1)
+				e.charge(vm, "wavelet.storage.write.byte", uint64(keyLen+valueLen))
+
+				key := append([]byte(nil), vm.Memory[keyPtr:keyPtr+keyLen]...)
+				value := append([]byte(nil), vm.Memory[valuePtr:valuePtr+valueLen]...)
+
+				if err := e.writeStorage(key, value); err != nil {
+					return 1
+				}
+
+				return 0
+			}
+		case "_call_result_len":
+			return func(vm *exec.VirtualMachine) int64 {
+				return int64(len(e.CallResult))
+			}
+		case "_call_result":
+			return func(vm *exec.VirtualMachine) int64 {
+				frame := vm.GetCurrentFrame()
+
+				outPtr := int(uint32(frame.Locals[0]))
+				copy(vm.Memory[outPtr:], e.CallResult)
 				return 0
 			}
 		case "_payload_len":
@@ -175,15 +318,45 @@ func (e *ContractExecutor) ResolveFunc(module, field string) exec.FunctionImport
 
 				return 0
 			}
-		case "_verify_ed25519":
+		case "_emit_log":
+			e.requireFeature(sys.FeatureContractLogs)
+
 			return func(vm *exec.VirtualMachine) int64 {
-				vm.Gas += uint64(e.GetCost("wavelet.verify.ed25519"))
+				frame := vm.GetCurrentFrame()
+				topicPtr, topicLen := int(uint32(frame.Locals[0])), int(uint32(frame.Locals[1]))
+				dataPtr, dataLen := int(uint32(frame.Locals[2])), int(uint32(frame.Locals[3]))
+
+				if topicLen > sys.ContractMaxLogTopicSize || dataLen > sys.ContractMaxLogDataSize ||
+					len(e.Logs) >= sys.ContractMaxLogs {
+					return 1
+				}
+
+				e.charge(vm, "wavelet.log", 1)
+				e.charge(vm, "wavelet.log.byte", uint64(topicLen+dataLen))
 
+				l := ContractLog{
+					Contract: e.ID,
+					Topic:    make([]byte, topicLen),
+					Data:     make([]byte, dataLen),
+				}
+
+				copy(l.Topic, vm.Memory[topicPtr:topicPtr+topicLen])
+				copy(l.Data, vm.Memory[dataPtr:dataPtr+dataLen])
+
+				e.Logs = append(e.Logs, l)
+
+				return 0
+			}
+		case "_verify_ed25519":
+			return func(vm *exec.VirtualMachine) int64 {
 				frame := vm.GetCurrentFrame()
 				keyPtr, keyLen := int(uint32(frame.Locals[0])), int(uint32(frame.Locals[1]))
 				dataPtr, dataLen := int(uint32(frame.Locals[2])), int(uint32(frame.Locals[3]))
 				sigPtr, sigLen := int(uint32(frame.Locals[4])), int(uint32(frame.Locals[5]))
 
+				e.charge(vm, "wavelet.verify.ed25519", 1)
+				e.charge(vm, "wavelet.verify.ed25519.byte", uint64(dataLen))
+
 				if keyLen != edwards25519.SizePublicKey || sigLen != edwards25519.SizeSignature {
 					return 1
 				}
@@ -205,9 +378,69 @@ func (e *ContractExecutor) ResolveFunc(module, field string) exec.FunctionImport
 
 				return 1
 			}
+		case "_verify_groth16":
+			e.requireFeature(sys.FeatureGroth16)
+
+			return func(vm *exec.VirtualMachine) int64 {
+				frame := vm.GetCurrentFrame()
+				vkPtr, vkLen := int(uint32(frame.Locals[0])), int(uint32(frame.Locals[1]))
+				proofPtr, proofLen := int(uint32(frame.Locals[2])), int(uint32(frame.Locals[3]))
+				inputsPtr, inputsLen := int(uint32(frame.Locals[4])), int(uint32(frame.Locals[5]))
+
+				// Charge for the pairings, and for every public input, before
+				// anything is decoded such that invalid proofs cost the same.
+				e.charge(vm, "wavelet.verify.groth16", 1)
+				e.charge(vm, "wavelet.verify.groth16.input", uint64(inputsLen/groth16.SizeScalar))
+
+				vk, err := groth16.UnmarshalVerifyingKey(vm.Memory[vkPtr : vkPtr+vkLen])
+				if err != nil {
+					return 1
+				}
+
+				proof, err := groth16.UnmarshalProof(vm.Memory[proofPtr : proofPtr+proofLen])
+				if err != nil {
+					return 1
+				}
+
+				inputs, err := groth16.UnmarshalInputs(vm.Memory[inputsPtr : inputsPtr+inputsLen])
+				if err != nil {
+					return 1
+				}
+
+				if err := groth16.Verify(vk, proof, inputs); err != nil {
+					return 1
+				}
+
+				return 0
+			}
+		case "_randomness":
+			e.requireFeature(sys.FeatureBeacon)
+
+			return func(vm *exec.VirtualMachine) int64 {
+				e.charge(vm, "wavelet.randomness", 1)
+
+				frame := vm.GetCurrentFrame()
+				index := uint64(frame.Locals[0])
+				outPtr, outLen := int(uint32(frame.Locals[1])), int(uint32(frame.Locals[2]))
+
+				// Only the beacon values of finalized blocks may be read, as
+				// that of the block being finalized is yet to be decided.
+				if outLen != blake2b.Size256 || e.tree == nil || e.block == nil || index > e.block.Index {
+					return 1
+				}
+
+				value, exists := ReadBeacon(e.tree, index)
+				if !exists {
+					return 1
+				}
+
+				copy(vm.Memory[outPtr:outPtr+outLen], value[:])
+
+				return 0
+			}
 		case "_hash_blake2b_256":
-			return buildHashImpl(
-				uint64(e.GetCost("wavelet.hash.blake2b256")),
+			return e.buildHashImpl(
+				"wavelet.hash.blake2b256",
 				blake2b.Size256,
 				func(data, out []byte) {
 					b := blake2b.Sum256(data)
@@ -215,8 +448,8 @@ func (e *ContractExecutor) ResolveFunc(module, field string) exec.FunctionImport
 				},
 			)
 		case "_hash_blake2b_512":
-			return buildHashImpl(
-				uint64(e.GetCost("wavelet.hash.blake2b512")),
+			return e.buildHashImpl(
+				"wavelet.hash.blake2b512",
 				blake2b.Size,
 				func(data, out []byte) {
 					b := blake2b.Sum512(data)
@@ -224,8 +457,8 @@ func (e *ContractExecutor) ResolveFunc(module, field string) exec.FunctionImport
 				},
 			)
 		case "_hash_sha256":
-			return buildHashImpl(
-				uint64(e.GetCost("wavelet.hash.sha256")),
+			return e.buildHashImpl(
+				"wavelet.hash.sha256",
 				sha256.Size,
 				func(data, out []byte) {
 					b := sha256.Sum256(data)
@@ -233,8 +466,8 @@ func (e *ContractExecutor) ResolveFunc(module, field string) exec.FunctionImport
 				},
 			)
 		case "_hash_sha512":
-			return buildHashImpl(
-				uint64(e.GetCost("wavelet.hash.sha512")),
+			return e.buildHashImpl(
+				"wavelet.hash.sha512",
 				sha512.Size,
 				func(data, out []byte) {
 					b := sha512.Sum512(data)
@@ -253,6 +486,58 @@ func (e *ContractExecutor) ResolveGlobal(module, field string) int64 {
 	panic("global variables are disallowed in smart contracts")
 }
 
+// compileContract returns the virtual machine code compiles into, metering
+// instructions as per schedule, as instantiated before executing any of its
+// code. Compiled code is cached in ContractModules by the hash of the version
+// of schedule and code, and must only be cloned.
+func compileContract(code []byte, schedule *sys.GasSchedule) (*exec.VirtualMachine, error) {
+	hash := contractModuleKey(code, schedule)
+
+	if module, ok := ContractModules.Load(hash); ok {
+		return module, nil
+	}
+
+	config := exec.VMConfig{
+		DefaultMemoryPages: sys.ContractDefaultMemoryPages,
+		MaxMemoryPages:     sys.ContractMaxMemoryPages,
+
+		DefaultTableSize: sys.ContractTableSize,
+		MaxTableSize:     sys.ContractTableSize,
+
+		MaxValueSlots:     sys.ContractMaxValueSlots,
+		MaxCallStackDepth: sys.ContractMaxCallStackDepth,
+	}
+
+	// Imports are resolved by the executor of every clone once called.
+	e := &ContractExecutor{schedule: schedule}
+
+	module, err := exec.NewVirtualMachine(code, config, e, schedule)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot initialize vm")
+	}
+
+	module.GasPolicy, module.ImportResolver = nil, nil
+
+	ContractModules.Put(hash, module)
+
+	return module, nil
+}
+
+// contractModuleKey returns the key code compiled as per schedule is cached by.
+func contractModuleKey(code []byte, schedule *sys.GasSchedule) [blake2b.Size256]byte {
+	var version [4]byte
+	binary.BigEndian.PutUint32(version[:], schedule.Version)
+
+	h, _ := blake2b.New256(nil)
+	_, _ = h.Write(version[:])
+	_, _ = h.Write(code)
+
+	var key [blake2b.Size256]byte
+	copy(key[:], h.Sum(nil))
+
+	return key
+}
+
 // contractState is an optional parameter that is used to pass the VMState of the contract.
 // If you cache the VMState, you can pass it.
 // If it's nil, we'll try to load the state from the tree.
@@ -267,6 +552,10 @@ func (e *ContractExecutor) Execute( // nolint:gocognit
 		err error
 	)
 
+	e.tree, e.block, e.tx, e.vmCache = tree, block, tx, vmCache
+
+	e.schedule = sys.GasScheduleAt(e.height())
+
 	if cached, ok := vmCache.Load(id); ok {
 		vm, err = CloneVM(cached, e, e)
 		if err != nil {
@@ -275,24 +564,19 @@ func (e *ContractExecutor) Execute( // nolint:gocognit
 
 		vm.Config.GasLimit = gasLimit
 	} else {
-		config := exec.VMConfig{
-			DefaultMemoryPages: sys.ContractDefaultMemoryPages,
-			MaxMemoryPages:     sys.ContractMaxMemoryPages,
-
-			DefaultTableSize: sys.ContractTableSize,
-			MaxTableSize:     sys.ContractTableSize,
-
-			MaxValueSlots:     sys.ContractMaxValueSlots,
-			MaxCallStackDepth: sys.ContractMaxCallStackDepth,
-			GasLimit:          gasLimit,
+		module, err := compileContract(code, e.schedule)
+		if err != nil {
+			return nil, err
 		}
 
-		vm, err = exec.NewVirtualMachine(code, config, e, e)
+		vm, err = CloneVM(module, e, e)
 		if err != nil {
-			return nil, errors.Wrap(err, "cannot initialize vm")
+			return nil, errors.Wrap(err, "cannot clone vm")
 		}
 
-		cloned, err := CloneVM(vm, nil, nil)
+		vm.Config.GasLimit = gasLimit
+
+		cloned, err := CloneVM(module, nil, nil)
 		if err != nil {
 			return nil, errors.Wrap(err, "cannot clone vm")
 		}
@@ -314,6 +598,9 @@ func (e *ContractExecutor) Execute( // nolint:gocognit
 		if err != nil {
 			return nil, errors.New("unable to apply state")
 		}
+	} else if e.calls != nil && e.calls.ctx.contractStateReset(id) {
+		// The code of the contract was upgraded, discarding its state.
+		firstRun = true
 	} else if mem := LoadContractMemorySnapshot(tree, id); mem != nil {
 		vm.Memory = mem
 		if globals, exists := LoadContractGlobals(tree, id); exists {
@@ -511,13 +798,18 @@ func buildContractPayload(block *Block, tx *Transaction, amount uint64, params [
 	return p
 }
 
-func buildHashImpl(gas uint64, size int, f func(data, out []byte)) func(vm *exec.VirtualMachine) int64 {
+// buildHashImpl returns the implementation of a hash function of the ledger, charging the cost of key alongside that
+// of every byte hashed.
+func (e *ContractExecutor) buildHashImpl(
+	key string, size int, f func(data, out []byte),
+) func(vm *exec.VirtualMachine) int64 {
 	return func(vm *exec.VirtualMachine) int64 {
-		vm.Gas += gas
-
 		frame := vm.GetCurrentFrame()
 		dataPtr, dataLen := int(uint32(frame.Locals[0])), int(uint32(frame.Locals[1]))
 		outPtr, outLen := int(uint32(frame.Locals[2])), int(uint32(frame.Locals[3]))
+
+		e.charge(vm, key, 1)
+		e.charge(vm, key+".byte", uint64(dataLen))
 		if outLen != size {
 			return 1
 		}
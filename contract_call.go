@@ -0,0 +1,91 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// ErrContractNotFound is returned when calling a smart contract that does
+// not exist.
+var ErrContractNotFound = errors.New("contract: not found")
+
+// ContractCall is the outcome of calling a smart contract function without
+// committing its effects.
+type ContractCall struct {
+	// Result is the data the function reported through _result, if any.
+	Result []byte
+
+	// Gas is the gas the function spent, being the gas limit should it have
+	// been exceeded.
+	Gas              uint64
+	GasLimitExceeded bool
+
+	// Err is why the function failed, if it did.
+	Err error
+
+	// Queue are the transactions the function would have sent.
+	Queue []*Transaction
+}
+
+// SimulateContractCall calls the function name of the smart contract id with
+// params against snapshot, as if sender sent amount PERLs to it in a
+// transaction created at block. Neither snapshot nor the memory of the
+// contract are written to, and the transactions the function sends are not
+// applied. An error is only returned should the function not be called, with
+// failures of the function itself being reported through ContractCall.Err.
+func SimulateContractCall(
+	snapshot *avl.Tree, block *Block, sender, id AccountID, amount, gasLimit uint64, name string, params []byte,
+) (ContractCall, error) {
+	var call ContractCall
+
+	code, exists := ReadAccountContractCode(snapshot, id)
+	if !exists || len(code) == 0 {
+		return call, errors.Wrapf(ErrContractNotFound, "%x", id)
+	}
+
+	if gasLimit == 0 {
+		return call, errors.New("contract: gas limit must be greater than zero")
+	}
+
+	tx := &Transaction{Sender: sender, Tag: sys.TagTransfer}
+	if block != nil {
+		tx.Block = block.Index
+	}
+
+	// Contracts called are executed against a collapse context which is
+	// never flushed.
+	executor := &ContractExecutor{calls: newContractCalls(NewCollapseContext(snapshot))}
+
+	_, err := executor.Execute(id, block, tx, amount, gasLimit, name, params, code, snapshot, NewVMLRU(1), nil)
+	if err != nil && errors.Cause(err) == ErrContractFunctionNotFound {
+		return call, err
+	}
+
+	call.Result = executor.Error
+	call.Gas = executor.Gas
+	call.GasLimitExceeded = executor.GasLimitExceeded
+	call.Err = err
+	call.Queue = executor.Queue
+
+	return call, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateContractCall(t *testing.T) {
+	state := avl.New(store.NewInmem())
+	block := NewBlock(0, state.Checksum())
+
+	account, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	code, err := ioutil.ReadFile("testdata/transfer_back.wasm")
+	assert.NoError(t, err)
+
+	payload, err := buildContractSpawnPayload(100000, 0, code).Marshal()
+	assert.NoError(t, err)
+
+	WriteAccountBalance(state, account.PublicKey(), 100000)
+
+	spawn := buildSignedTransaction(account, sys.TagContract, 1, block.Index, payload)
+	assert.NoError(t, ApplyTransaction(state, &block, &spawn))
+
+	contractID := spawn.ID
+	checksum := state.Checksum()
+
+	// Calls spend gas, and queue the transactions the contract sends,
+	// without the state being modified.
+	call, err := SimulateContractCall(state, &block, account.PublicKey(), contractID, 1000, 500000, "on_money_received", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, call.Err)
+	assert.True(t, call.Gas > 0 && call.Gas <= 500000)
+	assert.False(t, call.GasLimitExceeded)
+	assert.NotEmpty(t, call.Queue)
+	assert.Equal(t, checksum, state.Checksum())
+
+	// Calls are deterministic, having not been committed.
+	again, err := SimulateContractCall(state, &block, account.PublicKey(), contractID, 1000, 500000, "on_money_received", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, call.Gas, again.Gas)
+
+	// Failing functions are reported along with the gas they spent.
+	call, err = SimulateContractCall(state, &block, account.PublicKey(), contractID, 1000, 1, "on_money_received", nil)
+	assert.NoError(t, err)
+	assert.Error(t, call.Err)
+	assert.True(t, call.GasLimitExceeded)
+	assert.EqualValues(t, 1, call.Gas)
+
+	_, err = SimulateContractCall(state, &block, account.PublicKey(), contractID, 0, 500000, "missing", nil)
+	assert.Equal(t, ErrContractFunctionNotFound, errors.Cause(err))
+
+	_, err = SimulateContractCall(state, &block, account.PublicKey(), account.PublicKey(), 0, 500000, "on_money_received", nil)
+	assert.Equal(t, ErrContractNotFound, errors.Cause(err))
+
+	_, err = SimulateContractCall(state, &block, account.PublicKey(), contractID, 0, 0, "on_money_received", nil)
+	assert.Error(t, err)
+}
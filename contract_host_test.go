@@ -0,0 +1,116 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/life/exec"
+	"github.com/perlin-network/wavelet/internal/groth16"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+)
+
+// Not parallel, as the schedule of features is global.
+func TestVerifyGroth16(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureGroth16)()
+
+	// A verifying key and proof made up of points at infinity verify for any
+	// input, as every pairing then equals one.
+	vk := make([]byte, groth16.SizeG1+3*groth16.SizeG2+2*groth16.SizeG1)
+	proof := make([]byte, groth16.SizeProof)
+	inputs := make([]byte, groth16.SizeScalar)
+
+	verify := func(vk, proof, inputs []byte) (int64, uint64) {
+		memory := append(append(append([]byte(nil), vk...), proof...), inputs...)
+
+		vm := &exec.VirtualMachine{
+			Memory: memory,
+			CallStack: []exec.Frame{{Locals: []int64{
+				0, int64(len(vk)),
+				int64(len(vk)), int64(len(proof)),
+				int64(len(vk) + len(proof)), int64(len(inputs)),
+			}}},
+		}
+
+		executor := new(ContractExecutor)
+		ret := executor.ResolveFunc("env", "_verify_groth16")(vm)
+
+		return ret, vm.Gas
+	}
+
+	ret, gas := verify(vk, proof, inputs)
+	assert.EqualValues(t, 0, ret)
+	assert.NotZero(t, gas)
+
+	// Gas is charged regardless of whether the proof is valid.
+	ret, invalidGas := verify(vk, proof[1:], inputs)
+	assert.EqualValues(t, 1, ret)
+	assert.Equal(t, gas, invalidGas)
+
+	// The number of public inputs must match the verifying key.
+	ret, _ = verify(vk, proof, append(inputs, inputs...))
+	assert.EqualValues(t, 1, ret)
+
+	// Points off the curve are rejected.
+	proof[0] = 1
+
+	ret, _ = verify(vk, proof, inputs)
+	assert.EqualValues(t, 1, ret)
+
+	// The import is unknown to contracts until the feature activates.
+	sys.FeatureActivations[sys.FeatureGroth16] = 1
+
+	assert.Panics(t, func() {
+		new(ContractExecutor).ResolveFunc("env", "_verify_groth16")
+	})
+}
+
+func TestHostFunctionGas(t *testing.T) {
+	hash := func(schedule *sys.GasSchedule, size int, limit uint64) *exec.VirtualMachine {
+		vm := &exec.VirtualMachine{
+			Config: exec.VMConfig{GasLimit: limit},
+			Memory: make([]byte, size+32),
+			CallStack: []exec.Frame{{Locals: []int64{
+				0, int64(size),
+				int64(size), 32,
+			}}},
+		}
+
+		executor := &ContractExecutor{schedule: schedule}
+		assert.EqualValues(t, 0, executor.ResolveFunc("env", "_hash_sha256")(vm))
+
+		return vm
+	}
+
+	// The first version of the schedule charges a flat cost.
+	assert.EqualValues(t, 1, hash(&sys.GasScheduleV1, 1000, 0).Gas)
+
+	// Later versions charge for every byte hashed.
+	cost := sys.GasScheduleV2.GetCost("wavelet.hash.sha256") + 1000*sys.GasScheduleV2.GetCost("wavelet.hash.sha256.byte")
+	assert.EqualValues(t, cost, hash(&sys.GasScheduleV2, 1000, 0).Gas)
+
+	// Host functions stop contracts exceeding their gas limit.
+	assert.PanicsWithValue(t, "gas limit exceeded", func() {
+		hash(&sys.GasScheduleV2, 1000, uint64(cost-1))
+	})
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"github.com/perlin-network/life/exec"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrContractCallDepth is why a call through _call_contract fails should
+	// it be nested in more than sys.ContractMaxCallDepth calls.
+	ErrContractCallDepth = errors.New("contract: call depth exceeded")
+
+	// ErrContractReentrancy is why a call through _call_contract fails should
+	// the contract called be executing already.
+	ErrContractReentrancy = errors.New("contract: re-entrant call")
+)
+
+// contractCalls are the changes made by the contracts called by a contract,
+// which are only merged into the changes of its caller, or written to the
+// collapse context should it not have one, once the call succeeds.
+type contractCalls struct {
+	parent *contractCalls
+	ctx    *CollapseContext
+
+	// To preserve order of state insertions of balances and contract states
+	balanceIDs []AccountID
+	balances   map[AccountID]uint64
+
+	stateIDs []AccountID
+	states   map[AccountID]*VMState
+
+	// To preserve order of state insertions of the storage of contracts, by
+	// their key in the ledger state tree
+	storageKeys []string
+	storage     map[string]contractStorageWrite
+}
+
+type contractStorageWrite struct {
+	id         AccountID
+	key, value []byte
+}
+
+func newContractCalls(ctx *CollapseContext) *contractCalls {
+	return &contractCalls{
+		ctx:      ctx,
+		balances: make(map[AccountID]uint64),
+		states:   make(map[AccountID]*VMState),
+		storage:  make(map[string]contractStorageWrite),
+	}
+}
+
+// child returns the changes of a call made by the contract c is of.
+func (c *contractCalls) child() *contractCalls {
+	child := newContractCalls(c.ctx)
+	child.parent = c
+
+	return child
+}
+
+func (c *contractCalls) readBalance(id AccountID) uint64 {
+	for calls := c; calls != nil; calls = calls.parent {
+		if balance, ok := calls.balances[id]; ok {
+			return balance
+		}
+	}
+
+	balance, _ := c.ctx.ReadAccountBalance(id)
+
+	return balance
+}
+
+func (c *contractCalls) writeBalance(id AccountID, balance uint64) {
+	if _, ok := c.balances[id]; !ok {
+		c.balanceIDs = append(c.balanceIDs, id)
+	}
+
+	c.balances[id] = balance
+}
+
+// readState returns a copy of the state of the contract id, such that
+// executing it leaves the state intact should the call fail. Nil is returned
+// should the state be in the ledger state instead.
+func (c *contractCalls) readState(id AccountID) *VMState {
+	for calls := c; calls != nil; calls = calls.parent {
+		if state, ok := calls.states[id]; ok {
+			return copyVMState(state)
+		}
+	}
+
+	if state, ok := c.ctx.GetContractState(id); ok {
+		return copyVMState(state)
+	}
+
+	return nil
+}
+
+func (c *contractCalls) writeState(id AccountID, state *VMState) {
+	if _, ok := c.states[id]; !ok {
+		c.stateIDs = append(c.stateIDs, id)
+	}
+
+	c.states[id] = state
+}
+
+func (c *contractCalls) readStorage(id AccountID, key []byte) ([]byte, bool) {
+	k := string(contractStorageKey(id, key))
+
+	for calls := c; calls != nil; calls = calls.parent {
+		if write, ok := calls.storage[k]; ok {
+			return write.value, len(write.value) > 0
+		}
+	}
+
+	return c.ctx.ReadContractStorage(id, key)
+}
+
+func (c *contractCalls) writeStorage(id AccountID, key, value []byte) {
+	k := string(contractStorageKey(id, key))
+
+	if _, ok := c.storage[k]; !ok {
+		c.storageKeys = append(c.storageKeys, k)
+	}
+
+	c.storage[k] = contractStorageWrite{id: id, key: key, value: value}
+}
+
+// commit merges the changes into those of the parent, or writes them to the
+// collapse context should there be no parent.
+func (c *contractCalls) commit() {
+	for _, id := range c.balanceIDs {
+		if c.parent != nil {
+			c.parent.writeBalance(id, c.balances[id])
+		} else {
+			c.ctx.WriteAccountBalance(id, c.balances[id])
+		}
+	}
+
+	for _, id := range c.stateIDs {
+		if c.parent != nil {
+			c.parent.writeState(id, c.states[id])
+		} else {
+			c.ctx.SetContractState(id, c.states[id])
+		}
+	}
+
+	for _, k := range c.storageKeys {
+		write := c.storage[k]
+
+		if c.parent != nil {
+			c.parent.writeStorage(write.id, write.key, write.value)
+		} else {
+			c.ctx.WriteContractStorage(write.id, write.key, write.value)
+		}
+	}
+}
+
+func copyVMState(state *VMState) *VMState {
+	return &VMState{
+		Globals: append([]int64(nil), state.Globals...),
+		Memory:  append([]byte(nil), state.Memory...),
+	}
+}
+
+// call calls the function name of the contract id on behalf of the contract
+// executed by vm, sending it amount PERLs from the balance of the contract,
+// and forwarding up to gasLimit of the gas left to vm, or all of it should
+// gasLimit be 0. The gas spent by the call is charged to vm. Should the call
+// fail, all its changes are reverted, including those of the calls it made.
+func (e *ContractExecutor) call(
+	vm *exec.VirtualMachine, id AccountID, amount, gasLimit uint64, name string, params []byte,
+) error {
+	e.CallResult = nil
+
+	if e.calls == nil || e.block == nil || !sys.FeatureActive(sys.FeatureContractCalls, e.height()) {
+		return errors.New("contract: calls are unavailable")
+	}
+
+	if e.depth >= sys.ContractMaxCallDepth {
+		return ErrContractCallDepth
+	}
+
+	for caller := e; caller != nil; caller = caller.caller {
+		if caller.ID == id {
+			return errors.Wrapf(ErrContractReentrancy, "%x", id)
+		}
+	}
+
+	code, exists := e.calls.ctx.ReadAccountContractCode(id)
+	if !exists || len(code) == 0 {
+		return errors.Wrapf(ErrContractNotFound, "%x", id)
+	}
+
+	left := vm.Config.GasLimit - vm.Gas
+	if gasLimit == 0 || gasLimit > left {
+		gasLimit = left
+	}
+
+	if gasLimit == 0 {
+		return errors.New("contract: no gas left to call with")
+	}
+
+	calls := e.calls.child()
+
+	balance := calls.readBalance(e.ID)
+	if balance < amount {
+		return errors.Errorf("contract: %x tried to send %d PERLs to %x, but only has %d PERLs",
+			e.ID, amount, id, balance)
+	}
+
+	calls.writeBalance(e.ID, balance-amount)
+	calls.writeBalance(id, calls.readBalance(id)+amount)
+
+	tx := &Transaction{Sender: e.ID, Tag: sys.TagTransfer}
+	if e.tx != nil {
+		tx.ID, tx.Block = e.tx.ID, e.tx.Block
+	}
+
+	callee := &ContractExecutor{calls: calls, caller: e, depth: e.depth + 1}
+
+	state, err := callee.Execute(
+		id, e.block, tx, amount, gasLimit, name, params, code, e.tree, e.vmCache, calls.readState(id),
+	)
+
+	vm.AddAndCheckGas(callee.Gas)
+
+	e.CallResult = callee.Error
+
+	if err != nil {
+		return err
+	}
+
+	calls.writeState(id, state)
+	calls.commit()
+
+	e.Logs = append(e.Logs, callee.Logs...)
+	e.Queue = append(e.Queue, callee.Queue...)
+
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+// +build unit
+
+package wavelet
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildCallParams returns the parameters of the function call of
+// testdata/call_contract.wasm, having it call the function name of the
+// contract id with params.
+func buildCallParams(id TransactionID, amount, gasLimit uint64, name string, params []byte) []byte {
+	buf := append([]byte(nil), id[:]...)
+	buf = append(buf, make([]byte, 16)...)
+
+	binary.LittleEndian.PutUint64(buf[SizeAccountID:], amount)
+	binary.LittleEndian.PutUint64(buf[SizeAccountID+8:], gasLimit)
+
+	buf = append(buf, byte(len(name)))
+	buf = append(buf, name...)
+
+	return append(buf, params...)
+}
+
+// spawnCallContracts spawns n contracts of testdata/call_contract.wasm, and
+// returns the transactions spawning them.
+func spawnCallContracts(t *testing.T, sender *skademlia.Keypair, n int) []*Transaction {
+	code, err := ioutil.ReadFile("testdata/call_contract.wasm")
+	require.NoError(t, err)
+
+	payload, err := buildContractSpawnPayload(100000, 0, code).Marshal()
+	require.NoError(t, err)
+
+	txs := make([]*Transaction, n)
+
+	for i := range txs {
+		tx := NewTransaction(sender, uint64(i+1), 0, sys.TagContract, payload)
+		txs[i] = &tx
+	}
+
+	return txs
+}
+
+func TestContractCallContract(t *testing.T) {
+	// Callees forwarded too little gas are only starved of it by the costs of
+	// the second gas schedule.
+	defer ScheduleFeatures(sys.FeatureContractCalls, sys.FeatureContractLogs, sys.FeatureGasScheduleV2)()
+
+	accounts := NewAccounts(store.NewInmem())
+
+	alice, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	WriteAccountBalance(accounts.tree, alice.PublicKey(), 1000000000)
+
+	spawns := spawnCallContracts(t, alice, 3)
+	a, b, c := spawns[0].ID, spawns[1].ID, spawns[2].ID
+
+	nonce := uint64(len(spawns))
+
+	send := func(transfer Transfer) *Transaction {
+		payload, err := transfer.Marshal()
+		require.NoError(t, err)
+
+		nonce++
+		tx := NewTransaction(alice, nonce, 0, sys.TagTransfer, payload)
+
+		return &tx
+	}
+
+	call := func(params []byte) *Transaction {
+		return send(Transfer{Recipient: a, GasLimit: 10000000, FuncName: []byte("call"), FuncParams: params})
+	}
+
+	txs := append(spawns, send(Transfer{Recipient: a, Amount: 1000}),
+		// The callee is sent PERLs by the caller, and reports its result.
+		call(buildCallParams(b, 10, 0, "ok", nil)),
+		// Changes of callees which fail are reverted.
+		call(buildCallParams(b, 10, 0, "fail", nil)),
+		// Calls fail should the caller not afford them, or not forward enough
+		// gas.
+		call(buildCallParams(b, 10000, 0, "ok", nil)),
+		call(buildCallParams(b, 0, 10, "ok", nil)),
+		// Contracts being executed may not be called.
+		call(buildCallParams(a, 0, 0, "ok", nil)),
+		call(buildCallParams(b, 0, 0, "call", buildCallParams(a, 0, 0, "ok", nil))),
+		// Callees may call other contracts, with only the changes of the
+		// calls which fail being reverted.
+		call(buildCallParams(b, 0, 0, "call", buildCallParams(c, 5, 0, "ok", nil))),
+		call(buildCallParams(b, 0, 0, "call", buildCallParams(c, 5, 0, "fail", nil))),
+	)
+
+	block := NewBlock(0, accounts.tree.Checksum())
+
+	res, err := collapseTransactions(block.Index+1, txs, &block, accounts)
+	require.NoError(t, err)
+	require.Len(t, res.applied, len(txs))
+
+	var logs []string
+
+	for _, l := range res.logs {
+		logs = append(logs, string(l.Data))
+	}
+
+	assert.Equal(t, []string{
+		"\x00ok",
+		"\x01",
+		"\x01",
+		"\x01",
+		"\x01",
+		"\x01", "\x00\x01",
+		"\x00ok", "\x00\x00ok",
+		"\x01", "\x00\x01",
+	}, logs)
+
+	counter := func(id TransactionID) byte {
+		state, ok := res.ctx.GetContractState(id)
+		require.True(t, ok)
+
+		return state.Memory[0]
+	}
+
+	assert.EqualValues(t, 8, counter(a))
+	assert.EqualValues(t, 4, counter(b))
+	assert.EqualValues(t, 1, counter(c))
+
+	balance := func(id TransactionID) uint64 {
+		balance, _ := res.ctx.ReadAccountBalance(id)
+		return balance
+	}
+
+	assert.EqualValues(t, 990, balance(a))
+	assert.EqualValues(t, 5, balance(b))
+	assert.EqualValues(t, 5, balance(c))
+}
+
+func TestContractCallDepth(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureContractCalls, sys.FeatureContractLogs)()
+
+	accounts := NewAccounts(store.NewInmem())
+
+	alice, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	WriteAccountBalance(accounts.tree, alice.PublicKey(), 1000000000)
+
+	txs := spawnCallContracts(t, alice, sys.ContractMaxCallDepth+2)
+
+	// Every contract calls the next, with the last contract being called
+	// once the maximum call depth is reached.
+	var params []byte
+
+	for i := len(txs) - 1; i > 0; i-- {
+		name := "call"
+		if i == len(txs)-1 {
+			name = "ok"
+		}
+
+		params = buildCallParams(txs[i].ID, 0, 0, name, params)
+	}
+
+	payload, err := Transfer{
+		Recipient: txs[0].ID, GasLimit: 100000000, FuncName: []byte("call"), FuncParams: params,
+	}.Marshal()
+	require.NoError(t, err)
+
+	tx := NewTransaction(alice, uint64(len(txs)+1), 0, sys.TagTransfer, payload)
+	txs = append(txs, &tx)
+
+	block := NewBlock(0, accounts.tree.Checksum())
+
+	res, err := collapseTransactions(block.Index+1, txs, &block, accounts)
+	require.NoError(t, err)
+	require.Len(t, res.applied, len(txs))
+	require.NotEmpty(t, res.logs)
+
+	expected := make([]byte, sys.ContractMaxCallDepth+1)
+	expected[sys.ContractMaxCallDepth] = 1
+
+	last := res.logs[len(res.logs)-1]
+	assert.Equal(t, AccountID(txs[0].ID), last.Contract)
+	assert.Equal(t, expected, last.Data)
+}
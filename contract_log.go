@@ -0,0 +1,119 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package wavelet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// ContractLog is a log a smart contract emitted through _emit_log while a
+// transaction was being applied. Logs are only kept should the function
+// which emitted them not have failed.
+type ContractLog struct {
+	Contract AccountID
+
+	// Block is the index of the block the transaction was finalized in.
+	Block uint64
+
+	// TxID is the ID of the transaction whose application emitted the log.
+	TxID TransactionID
+
+	// Index is the position of the log amongst all logs of the contract.
+	Index uint64
+
+	Topic []byte
+	Data  []byte
+}
+
+func (l ContractLog) Marshal() []byte {
+	w := bytes.NewBuffer(make([]byte, 0, SizeAccountID+8+SizeTransactionID+8+4+len(l.Topic)+4+len(l.Data)))
+
+	var buf [8]byte
+
+	w.Write(l.Contract[:])
+
+	binary.BigEndian.PutUint64(buf[:], l.Block)
+	w.Write(buf[:])
+
+	w.Write(l.TxID[:])
+
+	binary.BigEndian.PutUint64(buf[:], l.Index)
+	w.Write(buf[:])
+
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(l.Topic)))
+	w.Write(buf[:4])
+	w.Write(l.Topic)
+
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(l.Data)))
+	w.Write(buf[:4])
+	w.Write(l.Data)
+
+	return w.Bytes()
+}
+
+func UnmarshalContractLog(r io.Reader) (ContractLog, error) {
+	var (
+		l   ContractLog
+		buf [8]byte
+	)
+
+	if _, err := io.ReadFull(r, l.Contract[:]); err != nil {
+		return l, errors.Wrap(err, "failed to decode contract log contract ID")
+	}
+
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return l, errors.Wrap(err, "failed to decode contract log block index")
+	}
+
+	l.Block = binary.BigEndian.Uint64(buf[:])
+
+	if _, err := io.ReadFull(r, l.TxID[:]); err != nil {
+		return l, errors.Wrap(err, "failed to decode contract log transaction ID")
+	}
+
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return l, errors.Wrap(err, "failed to decode contract log index")
+	}
+
+	l.Index = binary.BigEndian.Uint64(buf[:])
+
+	for _, field := range []*[]byte{&l.Topic, &l.Data} {
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return l, errors.Wrap(err, "failed to decode contract log length")
+		}
+
+		size := binary.BigEndian.Uint32(buf[:4])
+		if int(size) > sys.ContractMaxLogDataSize {
+			return l, errors.Errorf("contract log of %d bytes is too large", size)
+		}
+
+		*field = make([]byte, size)
+
+		if _, err := io.ReadFull(r, *field); err != nil {
+			return l, errors.Wrap(err, "failed to decode contract log contents")
+		}
+	}
+
+	return l, nil
+}
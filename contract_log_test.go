@@ -0,0 +1,154 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+// +build unit
+
+package wavelet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel, as the schedule of features is global.
+func TestContractLogs(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureContractLogs)()
+
+	kv := store.NewInmem()
+	accounts := NewAccounts(kv)
+
+	alice, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	WriteAccountBalance(accounts.tree, alice.PublicKey(), 10000000)
+
+	code, err := ioutil.ReadFile("testdata/emit_log.wasm")
+	require.NoError(t, err)
+
+	payload, err := buildContractSpawnPayload(100000, 0, code).Marshal()
+	require.NoError(t, err)
+
+	spawn := NewTransaction(alice, 1, 0, sys.TagContract, payload)
+
+	call := func(nonce uint64, name string) *Transaction {
+		payload, err := Transfer{Recipient: spawn.ID, GasLimit: 100000, FuncName: []byte(name)}.Marshal()
+		require.NoError(t, err)
+
+		tx := NewTransaction(alice, nonce, 0, sys.TagTransfer, payload)
+
+		return &tx
+	}
+
+	block := NewBlock(0, accounts.tree.Checksum())
+
+	// Logs of functions which fail are not kept.
+	txs := []*Transaction{&spawn, call(2, "emit"), call(3, "fail")}
+
+	res, err := collapseTransactions(block.Index+1, txs, &block, accounts)
+	require.NoError(t, err)
+	require.Len(t, res.applied, 3)
+	require.Len(t, res.logs, 3)
+
+	for i, expected := range []struct {
+		tx   TransactionID
+		data string
+	}{{spawn.ID, "hello"}, {txs[1].ID, "hello"}, {txs[1].ID, "world"}} {
+		l := res.logs[i]
+
+		assert.Equal(t, AccountID(spawn.ID), l.Contract)
+		assert.Equal(t, block.Index+1, l.Block)
+		assert.Equal(t, expected.tx, l.TxID)
+		assert.Equal(t, "greeting", string(l.Topic))
+		assert.Equal(t, expected.data, string(l.Data))
+	}
+
+	// Logs are numbered as they are stored, and are appended to the logs
+	// kept of the contract.
+	require.NoError(t, StoreContractLogs(kv, res.logs))
+
+	later := ContractLog{Contract: spawn.ID, Block: 5, Topic: []byte("later")}
+	require.NoError(t, StoreContractLogs(kv, []ContractLog{later}))
+
+	n, err := ReadContractLogsLen(kv, spawn.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, n)
+
+	logs, err := LoadContractLogs(kv, spawn.ID, 1, 2)
+	if assert.NoError(t, err) && assert.Len(t, logs, 2) {
+		assert.Equal(t, res.logs[1:3], logs)
+		assert.EqualValues(t, 1, logs[0].Index)
+	}
+
+	logs, err = LoadContractLogs(kv, spawn.ID, 3, 10)
+	if assert.NoError(t, err) && assert.Len(t, logs, 1) {
+		assert.EqualValues(t, 3, logs[0].Index)
+		assert.Equal(t, "later", string(logs[0].Topic))
+	}
+
+	logs, err = LoadContractLogs(kv, spawn.ID, 4, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+
+	for block, expected := range map[uint64]uint64{0: 0, 1: 0, 2: 3, 5: 3, 6: 4} {
+		index, err := SearchContractLogs(kv, spawn.ID, block)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, index, "block %d", block)
+	}
+
+	n, err = ReadContractLogsLen(kv, alice.PublicKey())
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	// Functions emitting logs fail, and hence emit none, until the feature
+	// activates at the height they are executed in.
+	sys.FeatureActivations[sys.FeatureContractLogs] = 2
+
+	accounts = NewAccounts(store.NewInmem())
+	WriteAccountBalance(accounts.tree, alice.PublicKey(), 10000000)
+
+	res, err = collapseTransactions(block.Index+1, txs[:2], &block, accounts)
+	require.NoError(t, err)
+	assert.Len(t, res.applied, 2)
+	assert.Empty(t, res.logs)
+}
+
+func TestContractLogMarshal(t *testing.T) {
+	l := ContractLog{
+		Contract: AccountID{1},
+		Block:    2,
+		TxID:     TransactionID{3},
+		Index:    4,
+		Topic:    []byte("topic"),
+		Data:     []byte{5, 6, 7},
+	}
+
+	decoded, err := UnmarshalContractLog(bytes.NewReader(l.Marshal()))
+	if assert.NoError(t, err) {
+		assert.Equal(t, l, decoded)
+	}
+
+	_, err = UnmarshalContractLog(bytes.NewReader(l.Marshal()[:SizeAccountID+4]))
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// ContractStorageEntry is a key of the storage of a smart contract, and its
+// value.
+type ContractStorageEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// contractStorageKey returns the key of key of the storage of the contract
+// with ID id in the ledger state tree. Keys of the storage of a contract share
+// a prefix, such that they may be scanned in order.
+func contractStorageKey(id AccountID, key []byte) []byte {
+	k := make([]byte, 0, len(keyAccounts)+len(keyAccountContractStorage)+len(id)+len(key))
+	k = append(k, keyAccounts[:]...)
+	k = append(k, keyAccountContractStorage[:]...)
+	k = append(k, id[:]...)
+	k = append(k, key...)
+
+	return k
+}
+
+// ReadContractStorage returns the value of key of the storage of the contract
+// with ID id.
+func ReadContractStorage(tree *avl.Tree, id AccountID, key []byte) ([]byte, bool) {
+	return tree.Lookup(contractStorageKey(id, key))
+}
+
+// WriteContractStorage sets key of the storage of the contract with ID id to
+// value, removing it should value be empty.
+func WriteContractStorage(tree *avl.Tree, id AccountID, key, value []byte) {
+	if len(value) == 0 {
+		tree.Delete(contractStorageKey(id, key))
+		return
+	}
+
+	tree.Insert(contractStorageKey(id, key), value)
+}
+
+// ScanContractStorage returns up to limit keys of the storage of the contract
+// with ID id which start with prefix, in order, starting from the first key
+// after after should it be set.
+func ScanContractStorage(tree *avl.Tree, id AccountID, prefix, after []byte, limit uint64) []ContractStorageEntry {
+	var entries []ContractStorageEntry
+
+	if limit == 0 {
+		return entries
+	}
+
+	base, full := contractStorageKey(id, nil), contractStorageKey(id, prefix)
+
+	start := full
+	if after != nil && bytes.Compare(contractStorageKey(id, after), start) > 0 {
+		start = contractStorageKey(id, after)
+	}
+
+	tree.IterateFrom(start, func(k, v []byte) bool {
+		if !bytes.HasPrefix(k, full) {
+			return false
+		}
+
+		key := k[len(base):]
+
+		if after != nil && bytes.Compare(key, after) <= 0 {
+			return true
+		}
+
+		entries = append(entries, ContractStorageEntry{
+			Key:   append([]byte(nil), key...),
+			Value: append([]byte(nil), v...),
+		})
+
+		return uint64(len(entries)) < limit
+	})
+
+	return entries
+}
+
+// ContractStorageProof proves the value of a key of the storage of a smart
+// contract, or the key being unset, against the Merkle root of the state of
+// the ledger at a block.
+type ContractStorageProof struct {
+	ID  AccountID
+	Key []byte
+
+	Proof avl.Proof
+}
+
+// ProveContractStorage returns a proof of the value of key of the storage of
+// the contract with ID id in the ledger state tree.
+func ProveContractStorage(tree *avl.Tree, id AccountID, key []byte) (ContractStorageProof, error) {
+	proof := ContractStorageProof{ID: id, Key: key}
+
+	var err error
+
+	if proof.Proof, err = tree.Prove(contractStorageKey(id, key)); err != nil {
+		return proof, errors.Wrapf(err, "failed to prove key %x of the storage of contract %x", key, id)
+	}
+
+	return proof, nil
+}
+
+// Verify checks the proof against root, being the Merkle root of a block,
+// returning the value of the key it proves, and whether the key is set.
+func (p ContractStorageProof) Verify(root MerkleNodeID) ([]byte, bool, error) {
+	value, exists, err := p.Proof.Verify(root, contractStorageKey(p.ID, p.Key))
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "invalid proof of key %x of the storage of contract %x", p.Key, p.ID)
+	}
+
+	return value, exists, nil
+}
+
+// readStorage returns the value of key of the storage of the contract being
+// executed, as changed by the contracts executed before it.
+func (e *ContractExecutor) readStorage(key []byte) ([]byte, bool, error) {
+	if e.calls == nil || e.block == nil || !sys.FeatureActive(sys.FeatureContractStorage, e.height()) {
+		return nil, false, errors.New("contract: storage is unavailable")
+	}
+
+	value, exists := e.calls.readStorage(e.ID, key)
+
+	return value, exists, nil
+}
+
+// writeStorage sets key of the storage of the contract being executed to
+// value, removing it should value be empty. The change is only written once
+// the contract, and all the contracts calling it, succeed.
+func (e *ContractExecutor) writeStorage(key, value []byte) error {
+	if e.calls == nil || e.block == nil || !sys.FeatureActive(sys.FeatureContractStorage, e.height()) {
+		return errors.New("contract: storage is unavailable")
+	}
+
+	if len(key) == 0 || len(key) > sys.ContractMaxStorageKeySize {
+		return errors.Errorf("contract: storage keys must be 1 to %d bytes long", sys.ContractMaxStorageKeySize)
+	}
+
+	if len(value) > sys.ContractMaxStorageValueSize {
+		return errors.Errorf("contract: storage values must be at most %d bytes long", sys.ContractMaxStorageValueSize)
+	}
+
+	e.calls.writeStorage(e.ID, key, value)
+
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/life/exec"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractStorage(t *testing.T) {
+	tree := avl.New(store.NewInmem())
+
+	a, b := AccountID{1}, AccountID{2}
+
+	WriteContractStorage(tree, a, []byte("apple"), []byte{1})
+	WriteContractStorage(tree, a, []byte("apricot"), []byte{2})
+	WriteContractStorage(tree, a, []byte("banana"), []byte{3})
+	WriteContractStorage(tree, b, []byte("avocado"), []byte{4})
+
+	value, exists := ReadContractStorage(tree, a, []byte("apricot"))
+	assert.True(t, exists)
+	assert.Equal(t, []byte{2}, value)
+
+	// Storage is private to every contract.
+	_, exists = ReadContractStorage(tree, b, []byte("apricot"))
+	assert.False(t, exists)
+
+	// Keys are scanned in order, and only those of the contract.
+	entries := ScanContractStorage(tree, a, []byte("a"), nil, 10)
+	assert.Equal(t, []ContractStorageEntry{
+		{Key: []byte("apple"), Value: []byte{1}},
+		{Key: []byte("apricot"), Value: []byte{2}},
+	}, entries)
+
+	assert.Len(t, ScanContractStorage(tree, a, nil, nil, 10), 3)
+	assert.Len(t, ScanContractStorage(tree, a, nil, nil, 2), 2)
+
+	entries = ScanContractStorage(tree, a, nil, []byte("apricot"), 10)
+	assert.Equal(t, []ContractStorageEntry{{Key: []byte("banana"), Value: []byte{3}}}, entries)
+
+	// Empty values remove keys.
+	WriteContractStorage(tree, a, []byte("banana"), nil)
+
+	_, exists = ReadContractStorage(tree, a, []byte("banana"))
+	assert.False(t, exists)
+}
+
+func TestContractStorageProof(t *testing.T) {
+	tree := avl.New(store.NewInmem())
+
+	id := AccountID{1}
+
+	WriteContractStorage(tree, id, []byte("key"), []byte("value"))
+	WriteAccountBalance(tree, id, 100)
+
+	root := tree.Checksum()
+
+	proof, err := ProveContractStorage(tree, id, []byte("key"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	value, exists, err := proof.Verify(root)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []byte("value"), value)
+
+	// Keys which are not set are proven to be unset.
+	proof, err = ProveContractStorage(tree, id, []byte("missing"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, exists, err = proof.Verify(root)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// The proof of a key does not prove the value of another key.
+	proof, err = ProveContractStorage(tree, id, []byte("key"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	other := proof
+	other.ID = AccountID{2}
+
+	_, exists, err = other.Verify(root)
+	assert.False(t, exists && err == nil)
+
+	// Nor does it prove anything against another root.
+	WriteContractStorage(tree, id, []byte("key"), []byte("changed"))
+
+	_, _, err = proof.Verify(tree.Checksum())
+	assert.Equal(t, avl.ErrInvalidProof, errors.Cause(err))
+}
+
+func TestContractStorageHostFunctions(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureContractStorage)()
+
+	tree := avl.New(store.NewInmem())
+	ctx := NewCollapseContext(tree)
+
+	executor := &ContractExecutor{
+		ID:       AccountID{1},
+		schedule: &sys.GasScheduleV2,
+		block:    &Block{Index: 1},
+		calls:    newContractCalls(ctx),
+	}
+
+	call := func(name string, memory []byte, locals ...int64) (int64, *exec.VirtualMachine) {
+		vm := &exec.VirtualMachine{Memory: memory, CallStack: []exec.Frame{{Locals: locals}}}
+		return executor.ResolveFunc("env", name)(vm), vm
+	}
+
+	memory := append([]byte("keyvalue"), make([]byte, 8)...)
+
+	ret, vm := call("_storage_set", memory, 0, 3, 3, 5)
+	assert.EqualValues(t, 0, ret)
+	assert.EqualValues(t, sys.GasScheduleV2.GetCost("wavelet.storage.write")+
+		8*sys.GasScheduleV2.GetCost("wavelet.storage.write.byte"), vm.Gas)
+
+	// Values are read back by the contract, truncated to fit its buffer.
+	ret, _ = call("_storage_get", memory, 0, 3, 8, 4)
+	assert.EqualValues(t, 5, ret)
+	assert.Equal(t, []byte("valu"), memory[8:12])
+
+	ret, _ = call("_storage_get", memory, 3, 5, 8, 8)
+	assert.EqualValues(t, -1, ret)
+
+	// Keys may not be empty.
+	ret, _ = call("_storage_set", memory, 0, 0, 3, 5)
+	assert.EqualValues(t, 1, ret)
+
+	// Writes only reach the ledger state once the contract succeeds.
+	_, exists := ReadContractStorage(tree, executor.ID, []byte("key"))
+	assert.False(t, exists)
+
+	executor.calls.commit()
+
+	if !assert.NoError(t, ctx.Flush()) {
+		return
+	}
+
+	value, exists := ReadContractStorage(tree, executor.ID, []byte("key"))
+	assert.True(t, exists)
+	assert.Equal(t, []byte("value"), value)
+}
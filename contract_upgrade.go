@@ -0,0 +1,146 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+)
+
+// The code of smart contracts is frozen once spawned, unless they opt in to
+// being upgraded by an upgrader, which is configured by the very transaction
+// spawning the contract, being a batch which spawns the contract and then
+// configures its upgrader. As the ID of the contract is the ID of the batch,
+// the batch refers to the contract by the zero ID instead. The upgrader may
+// then replace the code of the contract, hand the role over to another
+// account, or renounce it, freezing the code of the contract for good.
+//
+// Upgrading the code of a contract keeps its balances and its key-value
+// storage, while its memory and globals are discarded, such that the new
+// code is next executed as though it was just spawned. Every upgrade is
+// recorded alongside the hash of the new code.
+
+// upgradeContract returns the ID of the contract an upgrade transaction acts
+// upon.
+func upgradeContract(tx *Transaction, payload Upgrade) AccountID {
+	if payload.Opcode == sys.ConfigureUpgrader && payload.Contract == ZeroAccountID {
+		return tx.ID
+	}
+
+	return payload.Contract
+}
+
+// verifyUpgrade checks that the sender of tx may act upon the contract of
+// payload, given the upgrader of the contract.
+func verifyUpgrade(upgrader AccountID, configured bool, tx *Transaction, payload Upgrade) error {
+	if configured {
+		if upgrader != tx.Sender {
+			return errors.Errorf("upgrade: contract %x may only be upgraded by %x", payload.Contract, upgrader)
+		}
+
+		return nil
+	}
+
+	// Contracts are identified by the ID of the transaction spawning them.
+	if payload.Opcode == sys.ConfigureUpgrader && payload.Contract == tx.ID {
+		return nil
+	}
+
+	return errors.Errorf("upgrade: contract %x may not be upgraded", payload.Contract)
+}
+
+// ContractCodeHash returns the hash of the code of a smart contract recorded
+// upon upgrading it.
+func ContractCodeHash(code []byte) [32]byte {
+	return blake2b.Sum256(code)
+}
+
+func applyUpgradeTransaction(ctx *CollapseContext, block *Block, tx *Transaction) error {
+	payload, err := ParseUpgrade(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	payload.Contract = upgradeContract(tx, payload)
+
+	if _, exists := ctx.ReadAccountContractCode(payload.Contract); !exists {
+		return errors.Errorf("upgrade: contract %x does not exist", payload.Contract)
+	}
+
+	upgrader, configured := ctx.ReadAccountContractUpgrader(payload.Contract)
+
+	if err := verifyUpgrade(upgrader, configured, tx, payload); err != nil {
+		return err
+	}
+
+	switch payload.Opcode {
+	case sys.ConfigureUpgrader:
+		ctx.WriteAccountContractUpgrader(payload.Contract, payload.Upgrader)
+	case sys.UpgradeContract:
+		if err := validateContractCode(payload.Code, block.Index+1); err != nil {
+			return err
+		}
+
+		ctx.WriteAccountContractCode(payload.Contract, payload.Code)
+		ctx.ResetContractState(payload.Contract)
+
+		// The upgrades read may be held by the context already.
+		upgrades := append([]ContractUpgrade(nil), ctx.ReadAccountContractUpgrades(payload.Contract)...)
+		upgrades = append(upgrades, ContractUpgrade{
+			Block:    block.Index + 1,
+			TxID:     tx.ID,
+			Upgrader: tx.Sender,
+			CodeHash: ContractCodeHash(payload.Code),
+		})
+
+		ctx.WriteAccountContractUpgrades(payload.Contract, upgrades)
+	}
+
+	return nil
+}
+
+func validateUpgradeTransaction(snapshot *avl.Tree, tx Transaction) error {
+	payload, err := ParseUpgrade(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	payload.Contract = upgradeContract(&tx, payload)
+
+	upgrader, configured := ReadAccountContractUpgrader(snapshot, payload.Contract)
+
+	if err := verifyUpgrade(upgrader, configured, &tx, payload); err != nil {
+		return err
+	}
+
+	if payload.Opcode == sys.UpgradeContract {
+		if err := validateContractCode(payload.Code, tx.earliestHeight()); err != nil {
+			return err
+		}
+	}
+
+	if bal, _ := ReadAccountBalance(snapshot, tx.Sender); bal < sponsoredFee(snapshot, tx) {
+		return errors.Wrapf(ErrInsufficientBalance, "sender current balance %d is not enough", bal)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractUpgrade(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureContractUpgrades, sys.FeatureContractLogs)()
+
+	alice, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	bob, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	tree := avl.New(store.NewInmem())
+
+	WriteAccountBalance(tree, alice.PublicKey(), 1000000000)
+	WriteAccountBalance(tree, bob.PublicKey(), 1000000000)
+
+	block := NewBlock(0, tree.Checksum())
+
+	code, err := ioutil.ReadFile("testdata/call_contract.wasm")
+	require.NoError(t, err)
+
+	upgraded, err := ioutil.ReadFile("testdata/emit_log.wasm")
+	require.NoError(t, err)
+
+	var nonce uint64
+
+	send := func(keys *skademlia.Keypair, payload Payload) *Transaction {
+		buf, err := payload.Marshal()
+		require.NoError(t, err)
+
+		nonce++
+		tx := NewTransaction(keys, nonce, block.Index, payload.Tag(), buf)
+
+		return &tx
+	}
+
+	invoke := func(id TransactionID, name string) *Transaction {
+		return send(alice, Transfer{Recipient: id, GasLimit: 100000, FuncName: []byte(name)})
+	}
+
+	// Contracts are deployed with an upgrader by the batch spawning them.
+	var batch Batch
+
+	require.NoError(t, batch.AddContract(buildContractSpawnPayload(100000, 0, code)))
+	require.NoError(t, batch.AddUpgrade(Upgrade{Opcode: sys.ConfigureUpgrader, Upgrader: alice.PublicKey()}))
+
+	spawn := send(alice, batch)
+	require.NoError(t, ValidateTransaction(tree, *spawn))
+	require.NoError(t, ApplyTransaction(tree, &block, spawn))
+
+	id := spawn.ID
+
+	upgrader, upgradable := ReadAccountContractUpgrader(tree, id)
+	require.True(t, upgradable)
+	assert.EqualValues(t, alice.PublicKey(), upgrader)
+
+	// The contract counts the functions called at address 0 of its memory.
+	require.NoError(t, ApplyTransaction(tree, &block, invoke(id, "ok")))
+	assert.EqualValues(t, 1, LoadContractMemorySnapshot(tree, id)[0])
+
+	WriteContractStorage(tree, id, []byte("key"), []byte("value"))
+
+	// Only the upgrader may upgrade the contract.
+	tx := send(bob, Upgrade{Opcode: sys.UpgradeContract, Contract: id, Code: upgraded})
+	assert.Error(t, ValidateTransaction(tree, *tx))
+	assert.Error(t, ApplyTransaction(tree, &block, tx))
+
+	tx = send(alice, Upgrade{Opcode: sys.UpgradeContract, Contract: id, Code: []byte("not wasm")})
+	assert.Error(t, ApplyTransaction(tree, &block, tx))
+
+	// Upgrading the code discards the memory of the contract, including that
+	// of the code cached by the context, while its storage is kept.
+	ctx := NewCollapseContext(tree)
+
+	require.NoError(t, ctx.ApplyTransaction(&block, invoke(id, "ok")))
+
+	upgrade := send(alice, Upgrade{Opcode: sys.UpgradeContract, Contract: id, Code: upgraded})
+	require.NoError(t, ValidateTransaction(tree, *upgrade))
+	require.NoError(t, ctx.ApplyTransaction(&block, upgrade))
+	require.NoError(t, ctx.ApplyTransaction(&block, invoke(id, "emit")))
+	require.NoError(t, ctx.Flush())
+
+	current, _ := ReadAccountContractCode(tree, id)
+	assert.Equal(t, upgraded, current)
+
+	assert.Equal(t, "greeting", string(LoadContractMemorySnapshot(tree, id)[:8]))
+
+	value, exists := ReadContractStorage(tree, id, []byte("key"))
+	assert.True(t, exists)
+	assert.Equal(t, []byte("value"), value)
+
+	assert.Equal(t, []ContractUpgrade{{
+		Block:    block.Index + 1,
+		TxID:     upgrade.ID,
+		Upgrader: alice.PublicKey(),
+		CodeHash: ContractCodeHash(upgraded),
+	}}, ReadAccountContractUpgrades(tree, id))
+
+	// The upgrader may hand its role over, or renounce it.
+	tx = send(alice, Upgrade{Opcode: sys.ConfigureUpgrader, Contract: id, Upgrader: bob.PublicKey()})
+	require.NoError(t, ApplyTransaction(tree, &block, tx))
+
+	tx = send(alice, Upgrade{Opcode: sys.UpgradeContract, Contract: id, Code: code})
+	assert.Error(t, ApplyTransaction(tree, &block, tx))
+
+	tx = send(bob, Upgrade{Opcode: sys.ConfigureUpgrader, Contract: id})
+	require.NoError(t, ApplyTransaction(tree, &block, tx))
+
+	_, upgradable = ReadAccountContractUpgrader(tree, id)
+	assert.False(t, upgradable)
+
+	tx = send(bob, Upgrade{Opcode: sys.UpgradeContract, Contract: id, Code: code})
+	assert.Error(t, ValidateTransaction(tree, *tx))
+	assert.Error(t, ApplyTransaction(tree, &block, tx))
+}
+
+func TestContractUpgradeFrozen(t *testing.T) {
+	alice, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	tree := avl.New(store.NewInmem())
+
+	WriteAccountBalance(tree, alice.PublicKey(), 1000000000)
+
+	block := NewBlock(0, tree.Checksum())
+
+	code, err := ioutil.ReadFile("testdata/call_contract.wasm")
+	require.NoError(t, err)
+
+	payload, err := buildContractSpawnPayload(100000, 0, code).Marshal()
+	require.NoError(t, err)
+
+	spawn := NewTransaction(alice, 1, block.Index, sys.TagContract, payload)
+	require.NoError(t, ApplyTransaction(tree, &block, &spawn))
+
+	// Contracts spawned without an upgrader may not be given one later on,
+	// even by the account which spawned them.
+	for i, upgrade := range []Upgrade{
+		{Opcode: sys.ConfigureUpgrader, Contract: spawn.ID, Upgrader: alice.PublicKey()},
+		{Opcode: sys.ConfigureUpgrader, Upgrader: alice.PublicKey()},
+		{Opcode: sys.UpgradeContract, Contract: spawn.ID, Code: code},
+	} {
+		buf, err := upgrade.Marshal()
+		require.NoError(t, err)
+
+		tx := NewTransaction(alice, uint64(i+2), block.Index, sys.TagUpgrade, buf)
+		assert.Error(t, ApplyTransaction(tree, &block, &tx))
+	}
+
+	_, upgradable := ReadAccountContractUpgrader(tree, spawn.ID)
+	assert.False(t, upgradable)
+	assert.Empty(t, ReadAccountContractUpgrades(tree, spawn.ID))
+}
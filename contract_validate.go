@@ -0,0 +1,158 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+
+	"github.com/go-interpreter/wagon/disasm"
+	wagon "github.com/go-interpreter/wagon/wasm"
+	ops "github.com/go-interpreter/wagon/wasm/operators"
+	wasm "github.com/perlin-network/life/wasm-validation"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidContractCode is returned for smart contracts whose code may not
+// be spawned, or upgraded to.
+var ErrInvalidContractCode = errors.New("contract: invalid code")
+
+// contractImports are the functions smart contracts may import, being those
+// resolved by ContractExecutor.ResolveFunc.
+var contractImports = map[string]map[string]struct{}{
+	"env": {
+		"abort":             {},
+		"_send_transaction": {},
+		"_call_contract":    {},
+		"_call_result_len":  {},
+		"_call_result":      {},
+		"_storage_get":      {},
+		"_storage_set":      {},
+		"_payload_len":      {},
+		"_payload":          {},
+		"_result":           {},
+		"_log":              {},
+		"_emit_log":         {},
+		"_verify_ed25519":   {},
+		"_verify_groth16":   {},
+		"_randomness":       {},
+		"_hash_blake2b_256": {},
+		"_hash_blake2b_512": {},
+		"_hash_sha256":      {},
+		"_hash_sha512":      {},
+	},
+}
+
+// nonDeterministicOps are the float instructions whose results may differ
+// across the platforms nodes run on, be it by the bits of the NaNs they
+// produce, their rounding, or the integers out of range floats truncate to.
+// Loading, storing, comparing, negating and reinterpreting floats, and
+// converting integers into floats, are deterministic and hence allowed.
+var nonDeterministicOps = map[byte]struct{}{
+	ops.F32Ceil: {}, ops.F32Floor: {}, ops.F32Trunc: {}, ops.F32Nearest: {}, ops.F32Sqrt: {},
+	ops.F32Add: {}, ops.F32Sub: {}, ops.F32Mul: {}, ops.F32Div: {}, ops.F32Min: {}, ops.F32Max: {},
+
+	ops.F64Ceil: {}, ops.F64Floor: {}, ops.F64Trunc: {}, ops.F64Nearest: {}, ops.F64Sqrt: {},
+	ops.F64Add: {}, ops.F64Sub: {}, ops.F64Mul: {}, ops.F64Div: {}, ops.F64Min: {}, ops.F64Max: {},
+
+	ops.I32TruncSF32: {}, ops.I32TruncUF32: {}, ops.I32TruncSF64: {}, ops.I32TruncUF64: {},
+	ops.I64TruncSF32: {}, ops.I64TruncUF32: {}, ops.I64TruncSF64: {}, ops.I64TruncUF64: {},
+
+	ops.F32DemoteF64: {}, ops.F64PromoteF32: {},
+}
+
+// ValidateContractCode checks that code is a WebAssembly module which every
+// node executes the same way, being at most sys.ContractMaxCodeSize bytes
+// long, only importing the functions of the ledger, declaring no more memory
+// than smart contracts may use, and executing no non-deterministic float
+// instructions. Errors returned are caused by ErrInvalidContractCode.
+func ValidateContractCode(code []byte) error {
+	if len(code) > sys.ContractMaxCodeSize {
+		return errors.Wrapf(ErrInvalidContractCode, "code is %d bytes long, exceeding %d bytes", len(code),
+			sys.ContractMaxCodeSize)
+	}
+
+	if err := wasm.GetValidator().ValidateWasm(code); err != nil {
+		return errors.Wrapf(ErrInvalidContractCode, "invalid wasm: %v", err)
+	}
+
+	module, err := wagon.ReadModule(bytes.NewReader(code), nil)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidContractCode, "invalid wasm: %v", err)
+	}
+
+	if module.Import != nil {
+		for _, entry := range module.Import.Entries {
+			if entry.Type.Kind() != wagon.ExternalFunction {
+				return errors.Wrapf(ErrInvalidContractCode, "import %s.%s is not a function", entry.ModuleName,
+					entry.FieldName)
+			}
+
+			if _, allowed := contractImports[entry.ModuleName][entry.FieldName]; !allowed {
+				return errors.Wrapf(ErrInvalidContractCode, "import %s.%s is not provided by the ledger",
+					entry.ModuleName, entry.FieldName)
+			}
+		}
+	}
+
+	if module.Memory != nil {
+		for _, entry := range module.Memory.Entries {
+			if entry.Limits.Initial > uint32(sys.ContractMaxMemoryPages) {
+				return errors.Wrapf(ErrInvalidContractCode, "memory of %d pages exceeds %d pages",
+					entry.Limits.Initial, sys.ContractMaxMemoryPages)
+			}
+		}
+	}
+
+	for i, fn := range module.FunctionIndexSpace {
+		if fn.Body == nil {
+			continue
+		}
+
+		d, err := disasm.Disassemble(fn, module)
+		if err != nil {
+			return errors.Wrapf(ErrInvalidContractCode, "invalid wasm: function %d: %v", i, err)
+		}
+
+		for _, instr := range d.Code {
+			if _, banned := nonDeterministicOps[instr.Op.Code]; banned {
+				return errors.Wrapf(ErrInvalidContractCode, "function %d uses non-deterministic float instruction %s",
+					i, instr.Op.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateContractCode validates code spawned, or upgraded to, at block
+// height. Prior to sys.FeatureDeterministicContracts activating, code was
+// only checked to be valid WebAssembly.
+func validateContractCode(code []byte, height uint64) error {
+	if sys.FeatureActive(sys.FeatureDeterministicContracts, height) {
+		return ValidateContractCode(code)
+	}
+
+	if err := wasm.GetValidator().ValidateWasm(code); err != nil {
+		return errors.Wrap(err, "invalid wasm")
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// wasmFloatModule returns a module of a single function returning an f64
+// computed by body, being the instructions following two f64.const 1.
+func wasmFloatModule(body ...byte) []byte {
+	one := []byte{0x44, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f}
+
+	code := append([]byte{0x00}, one...)
+	code = append(code, one...)
+	code = append(append(code, body...), 0x0b)
+
+	module := append([]byte(nil), wasmHeader...)
+	module = append(module, 0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7c) // type () -> f64
+	module = append(module, 0x03, 0x02, 0x01, 0x00)                   // func of type 0
+	module = append(module, 0x0a, byte(len(code)+2), 0x01, byte(len(code)))
+
+	return append(module, code...)
+}
+
+func TestValidateContractCode(t *testing.T) {
+	for _, file := range []string{"testdata/transfer_back.wasm", "testdata/call_contract.wasm", "testdata/emit_log.wasm"} {
+		code, err := ioutil.ReadFile(file)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.NoError(t, ValidateContractCode(code), file)
+	}
+
+	// Comparing, negating and dropping floats is deterministic.
+	assert.NoError(t, ValidateContractCode(wasmFloatModule(0x9a, 0x1a))) // f64.neg, drop
+	assert.NoError(t, ValidateContractCode(wasmFloatModule(0x61, 0x1a, 0x44, 0, 0, 0, 0, 0, 0, 0, 0)))
+
+	invalid := map[string][]byte{
+		"f64.add":  wasmFloatModule(0xa0),
+		"f64.div":  wasmFloatModule(0xa3),
+		"f64.sqrt": wasmFloatModule(0x9f, 0xa0),
+		"i64.trunc_s/f64": wasmFloatModule(
+			0x1a, 0xb0, 0x1a, 0x44, 0, 0, 0, 0, 0, 0, 0, 0,
+		),
+		"unknown import": append(append([]byte(nil), wasmHeader...),
+			0x01, 0x04, 0x01, 0x60, 0x00, 0x00,
+			0x02, 0x10, 0x01, 0x03, 'e', 'n', 'v', 0x08, '_', 'u', 'n', 'k', 'n', 'o', 'w', 'n', 0x00, 0x00,
+		),
+		"memory":  append(append([]byte(nil), wasmHeader...), 0x05, 0x04, 0x01, 0x00, 0x88, 0x27),
+		"garbage": []byte("not wasm"),
+		"size":    make([]byte, sys.ContractMaxCodeSize+1),
+	}
+
+	for name, code := range invalid {
+		err := ValidateContractCode(code)
+		assert.Equal(t, ErrInvalidContractCode, errors.Cause(err), name)
+	}
+}
+
+func TestValidateContractTransactionNonDeterministic(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureDeterministicContracts)()
+
+	tree := avl.New(store.NewInmem())
+
+	sender := AccountID{1}
+	WriteAccountBalance(tree, sender, 1000000)
+
+	payload, err := Contract{GasLimit: 1000, Code: wasmFloatModule(0xa0)}.Marshal()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tx := Transaction{Sender: sender, Tag: sys.TagContract, Payload: payload}
+
+	err = validateTransaction(tree, tx, false)
+	assert.Equal(t, ErrInvalidContractCode, errors.Cause(err))
+}
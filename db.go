@@ -26,6 +26,7 @@ import (
 	"github.com/golang/snappy"
 	"github.com/perlin-network/wavelet/avl"
 	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
 	"github.com/pkg/errors"
 	"io"
 	"strconv"
@@ -41,6 +42,19 @@ var (
 	keyBlockStoredCount     = [...]byte{0x6}
 	keyRewardWithdrawals    = [...]byte{0x7}
 	keyTransactionFinalized = [...]byte{0x8}
+	keyBeacon               = [...]byte{0x9}
+	keyNames                = [...]byte{0xa}
+	keyData                 = [...]byte{0xb}
+	keyTransactionDiffs     = [...]byte{0xc}
+	keyContractLogs         = [...]byte{0xd}
+	keyContractLogsLen      = [...]byte{0xe}
+	keyTransactionDiffBlock = [...]byte{0xf}
+	keyPrunedHeight         = [...]byte{0x10}
+	keyArchivedBlocks       = [...]byte{0x11}
+	keyMultisigProposals    = [...]byte{0x12}
+	keyRounds               = [...]byte{0x13}
+	keyPeers                = [...]byte{0x14}
+	keyHealthProbe          = [...]byte{0x15}
 
 	// Account-local prefixes.
 	keyAccountBalance            = [...]byte{0x2}
@@ -51,6 +65,20 @@ var (
 	keyAccountContractPages      = [...]byte{0x7}
 	keyAccountContractGasBalance = [...]byte{0x8}
 	keyAccountContractGlobals    = [...]byte{0x9}
+	keyAccountFeeAllowance       = [...]byte{0xa}
+	keyAccountRecoveryConfig     = [...]byte{0xb}
+	keyAccountPendingRecovery    = [...]byte{0xc}
+	keyAccountContractStorage    = [...]byte{0xd}
+	keyAccountContractUpgrader   = [...]byte{0xe}
+	keyAccountContractUpgrades   = [...]byte{0xf}
+	keyAccountMultisig           = [...]byte{0x10}
+	keyAccountDelegators         = [...]byte{0x11}
+	keyAccountCommission         = [...]byte{0x12}
+	keyAccountDelegations        = [...]byte{0x13}
+	keyAccountUnbondings         = [...]byte{0x14}
+	keyAccountLastActive         = [...]byte{0x15}
+	keyAccountSlashings          = [...]byte{0x16}
+	keyAccountNonces             = [...]byte{0x17}
 )
 
 type RewardWithdrawalRequest struct {
@@ -229,6 +257,24 @@ func WriteAccountContractPage(tree *avl.Tree, id TransactionID, idx uint64, page
 	writeUnderAccounts(tree, id, k, encoded)
 }
 
+// DeleteAccountContractState removes the memory and globals of a smart
+// contract, such that it is next executed as though it was just spawned.
+func DeleteAccountContractState(tree *avl.Tree, id TransactionID) {
+	numPages, _ := ReadAccountContractNumPages(tree, id)
+
+	for idx := uint64(0); idx < numPages; idx++ {
+		k := make([]byte, len(keyAccountContractPages)+8)
+		copy(k, keyAccountContractPages[:])
+
+		binary.LittleEndian.PutUint64(k[len(keyAccountContractPages):], idx)
+
+		deleteUnderAccounts(tree, id, k)
+	}
+
+	deleteUnderAccounts(tree, id, keyAccountContractNumPages[:])
+	deleteUnderAccounts(tree, id, keyAccountContractGlobals[:])
+}
+
 func ReadAccountContractGasBalance(tree *avl.Tree, id TransactionID) (uint64, bool) {
 	buf, exists := readUnderAccounts(tree, id, keyAccountContractGasBalance[:])
 	if !exists || len(buf) == 0 {
@@ -245,6 +291,612 @@ func WriteAccountContractGasBalance(tree *avl.Tree, id TransactionID, gasBalance
 	writeUnderAccounts(tree, id, keyAccountContractGasBalance[:], buf[:])
 }
 
+// FeeAllowance is the allowance of PERLs a sponsor pays the transaction fees
+// of an account with.
+type FeeAllowance struct {
+	Sponsor   AccountID
+	Allowance uint64
+}
+
+// ReadAccountFeeAllowance returns the fee allowance granted to an account.
+// Revoked and exhausted allowances are reported as not existing.
+func ReadAccountFeeAllowance(tree *avl.Tree, id AccountID) (FeeAllowance, bool) {
+	var allowance FeeAllowance
+
+	buf, exists := readUnderAccounts(tree, id, keyAccountFeeAllowance[:])
+	if !exists || len(buf) != SizeAccountID+8 {
+		return allowance, false
+	}
+
+	copy(allowance.Sponsor[:], buf[:SizeAccountID])
+	allowance.Allowance = binary.LittleEndian.Uint64(buf[SizeAccountID:])
+
+	return allowance, allowance.Allowance > 0
+}
+
+func WriteAccountFeeAllowance(tree *avl.Tree, id AccountID, allowance FeeAllowance) {
+	buf := make([]byte, SizeAccountID+8)
+
+	copy(buf, allowance.Sponsor[:])
+	binary.LittleEndian.PutUint64(buf[SizeAccountID:], allowance.Allowance)
+
+	writeUnderAccounts(tree, id, keyAccountFeeAllowance[:], buf)
+}
+
+// RecoveryConfig is the set of guardians who may recover an account, of
+// which Threshold must approve a recovery Delay blocks before it completes.
+type RecoveryConfig struct {
+	Guardians []AccountID
+	Threshold uint8
+	Delay     uint64
+}
+
+// PendingRecovery is a recovery of an account to Recipient initiated at the
+// block at index Block, and the guardians who approved it so far.
+type PendingRecovery struct {
+	Recipient AccountID
+	Block     uint64
+	Approvals []AccountID
+}
+
+func ReadAccountRecoveryConfig(tree *avl.Tree, id AccountID) (RecoveryConfig, bool) {
+	var config RecoveryConfig
+
+	buf, exists := readUnderAccounts(tree, id, keyAccountRecoveryConfig[:])
+	if !exists || len(buf) < 1+8 || (len(buf)-1-8)%SizeAccountID != 0 {
+		return config, false
+	}
+
+	config.Threshold = buf[0]
+	config.Delay = binary.LittleEndian.Uint64(buf[1:9])
+	config.Guardians = readAccountIDs(buf[9:])
+
+	return config, len(config.Guardians) > 0
+}
+
+// WriteAccountRecoveryConfig records the recovery configuration of an
+// account. A configuration without guardians is removed.
+func WriteAccountRecoveryConfig(tree *avl.Tree, id AccountID, config RecoveryConfig) {
+	if len(config.Guardians) == 0 {
+		deleteUnderAccounts(tree, id, keyAccountRecoveryConfig[:])
+		return
+	}
+
+	buf := make([]byte, 1+8, 1+8+len(config.Guardians)*SizeAccountID)
+
+	buf[0] = config.Threshold
+	binary.LittleEndian.PutUint64(buf[1:9], config.Delay)
+
+	writeUnderAccounts(tree, id, keyAccountRecoveryConfig[:], appendAccountIDs(buf, config.Guardians))
+}
+
+func ReadAccountPendingRecovery(tree *avl.Tree, id AccountID) (PendingRecovery, bool) {
+	var recovery PendingRecovery
+
+	buf, exists := readUnderAccounts(tree, id, keyAccountPendingRecovery[:])
+	if !exists || len(buf) < SizeAccountID+8 || (len(buf)-SizeAccountID-8)%SizeAccountID != 0 {
+		return recovery, false
+	}
+
+	copy(recovery.Recipient[:], buf[:SizeAccountID])
+	recovery.Block = binary.LittleEndian.Uint64(buf[SizeAccountID : SizeAccountID+8])
+	recovery.Approvals = readAccountIDs(buf[SizeAccountID+8:])
+
+	return recovery, len(recovery.Approvals) > 0
+}
+
+// WriteAccountPendingRecovery records the pending recovery of an account. A
+// recovery without approvals is removed.
+func WriteAccountPendingRecovery(tree *avl.Tree, id AccountID, recovery PendingRecovery) {
+	if len(recovery.Approvals) == 0 {
+		deleteUnderAccounts(tree, id, keyAccountPendingRecovery[:])
+		return
+	}
+
+	buf := make([]byte, SizeAccountID+8, SizeAccountID+8+len(recovery.Approvals)*SizeAccountID)
+
+	copy(buf, recovery.Recipient[:])
+	binary.LittleEndian.PutUint64(buf[SizeAccountID:], recovery.Block)
+
+	writeUnderAccounts(tree, id, keyAccountPendingRecovery[:], appendAccountIDs(buf, recovery.Approvals))
+}
+
+// ReadAccountContractUpgrader returns the account which may upgrade the code
+// of a smart contract, should the contract have one.
+func ReadAccountContractUpgrader(tree *avl.Tree, id TransactionID) (AccountID, bool) {
+	var upgrader AccountID
+
+	buf, exists := readUnderAccounts(tree, id, keyAccountContractUpgrader[:])
+	if !exists || len(buf) != SizeAccountID {
+		return upgrader, false
+	}
+
+	copy(upgrader[:], buf)
+
+	return upgrader, true
+}
+
+// WriteAccountContractUpgrader records the account which may upgrade the
+// code of a smart contract. A zero upgrader is removed.
+func WriteAccountContractUpgrader(tree *avl.Tree, id TransactionID, upgrader AccountID) {
+	if upgrader == ZeroAccountID {
+		deleteUnderAccounts(tree, id, keyAccountContractUpgrader[:])
+		return
+	}
+
+	writeUnderAccounts(tree, id, keyAccountContractUpgrader[:], upgrader[:])
+}
+
+// ContractUpgrade is an upgrade of the code of a smart contract to code of
+// hash CodeHash by Upgrader, through transaction TxID applied at the block at
+// index Block.
+type ContractUpgrade struct {
+	Block    uint64
+	TxID     TransactionID
+	Upgrader AccountID
+	CodeHash [32]byte
+}
+
+const sizeContractUpgrade = 8 + SizeTransactionID + SizeAccountID + 32
+
+// ReadAccountContractUpgrades returns the upgrades of the code of a smart
+// contract, from the earliest to the latest.
+func ReadAccountContractUpgrades(tree *avl.Tree, id TransactionID) []ContractUpgrade {
+	buf, exists := readUnderAccounts(tree, id, keyAccountContractUpgrades[:])
+	if !exists || len(buf)%sizeContractUpgrade != 0 {
+		return nil
+	}
+
+	upgrades := make([]ContractUpgrade, 0, len(buf)/sizeContractUpgrade)
+
+	for ; len(buf) > 0; buf = buf[sizeContractUpgrade:] {
+		var upgrade ContractUpgrade
+
+		upgrade.Block = binary.LittleEndian.Uint64(buf[:8])
+		copy(upgrade.TxID[:], buf[8:])
+		copy(upgrade.Upgrader[:], buf[8+SizeTransactionID:])
+		copy(upgrade.CodeHash[:], buf[8+SizeTransactionID+SizeAccountID:])
+
+		upgrades = append(upgrades, upgrade)
+	}
+
+	return upgrades
+}
+
+func WriteAccountContractUpgrades(tree *avl.Tree, id TransactionID, upgrades []ContractUpgrade) {
+	buf := make([]byte, 0, len(upgrades)*sizeContractUpgrade)
+
+	for _, upgrade := range upgrades {
+		var block [8]byte
+
+		binary.LittleEndian.PutUint64(block[:], upgrade.Block)
+
+		buf = append(buf, block[:]...)
+		buf = append(buf, upgrade.TxID[:]...)
+		buf = append(buf, upgrade.Upgrader[:]...)
+		buf = append(buf, upgrade.CodeHash[:]...)
+	}
+
+	writeUnderAccounts(tree, id, keyAccountContractUpgrades[:], buf)
+}
+
+// MultisigAccount is an account whose PERLs are only moved once Threshold of
+// its participants approve, and the transfers proposed from it which are yet
+// to be executed or cancelled.
+type MultisigAccount struct {
+	Participants []AccountID
+	Threshold    uint8
+	Proposals    []TransactionID
+}
+
+// MultisigProposal is a transfer of Amount PERLs from the multisig account
+// Account to Recipient proposed by Proposer at the block at index Block, and
+// the participants who approved it so far.
+type MultisigProposal struct {
+	Account   AccountID
+	Proposer  AccountID
+	Recipient AccountID
+	Amount    uint64
+	Block     uint64
+	Approvals []AccountID
+}
+
+const sizeMultisigProposal = SizeAccountID*3 + 8 + 8
+
+func ReadAccountMultisig(tree *avl.Tree, id AccountID) (MultisigAccount, bool) {
+	var multisig MultisigAccount
+
+	buf, exists := readUnderAccounts(tree, id, keyAccountMultisig[:])
+	if !exists || len(buf) < 1+1 {
+		return multisig, false
+	}
+
+	multisig.Threshold = buf[0]
+	count := int(buf[1])
+	buf = buf[2:]
+
+	if count == 0 || len(buf) < count*SizeAccountID || (len(buf)-count*SizeAccountID)%SizeTransactionID != 0 {
+		return multisig, false
+	}
+
+	multisig.Participants = readAccountIDs(buf[:count*SizeAccountID])
+
+	for buf = buf[count*SizeAccountID:]; len(buf) > 0; buf = buf[SizeTransactionID:] {
+		var proposal TransactionID
+		copy(proposal[:], buf)
+
+		multisig.Proposals = append(multisig.Proposals, proposal)
+	}
+
+	return multisig, true
+}
+
+func WriteAccountMultisig(tree *avl.Tree, id AccountID, multisig MultisigAccount) {
+	buf := make([]byte, 2, 2+len(multisig.Participants)*SizeAccountID+len(multisig.Proposals)*SizeTransactionID)
+
+	buf[0] = multisig.Threshold
+	buf[1] = byte(len(multisig.Participants))
+
+	buf = appendAccountIDs(buf, multisig.Participants)
+
+	for _, proposal := range multisig.Proposals {
+		buf = append(buf, proposal[:]...)
+	}
+
+	writeUnderAccounts(tree, id, keyAccountMultisig[:], buf)
+}
+
+// ReadMultisigProposal returns the transfer proposed from a multisig account
+// by the transaction with ID id, should it be pending.
+func ReadMultisigProposal(tree *avl.Tree, id TransactionID) (MultisigProposal, bool) {
+	var proposal MultisigProposal
+
+	buf, exists := tree.Lookup(append(keyMultisigProposals[:], id[:]...))
+	if !exists || len(buf) < sizeMultisigProposal || (len(buf)-sizeMultisigProposal)%SizeAccountID != 0 {
+		return proposal, false
+	}
+
+	copy(proposal.Account[:], buf[:SizeAccountID])
+	copy(proposal.Proposer[:], buf[SizeAccountID:SizeAccountID*2])
+	copy(proposal.Recipient[:], buf[SizeAccountID*2:SizeAccountID*3])
+	proposal.Amount = binary.LittleEndian.Uint64(buf[SizeAccountID*3:])
+	proposal.Block = binary.LittleEndian.Uint64(buf[SizeAccountID*3+8:])
+	proposal.Approvals = readAccountIDs(buf[sizeMultisigProposal:])
+
+	return proposal, len(proposal.Approvals) > 0
+}
+
+// WriteMultisigProposal records the transfer proposed from a multisig account
+// by the transaction with ID id. A proposal without approvals is removed.
+func WriteMultisigProposal(tree *avl.Tree, id TransactionID, proposal MultisigProposal) {
+	key := append(keyMultisigProposals[:], id[:]...)
+
+	if len(proposal.Approvals) == 0 {
+		tree.Delete(key)
+		return
+	}
+
+	buf := make([]byte, sizeMultisigProposal, sizeMultisigProposal+len(proposal.Approvals)*SizeAccountID)
+
+	copy(buf, proposal.Account[:])
+	copy(buf[SizeAccountID:], proposal.Proposer[:])
+	copy(buf[SizeAccountID*2:], proposal.Recipient[:])
+	binary.LittleEndian.PutUint64(buf[SizeAccountID*3:], proposal.Amount)
+	binary.LittleEndian.PutUint64(buf[SizeAccountID*3+8:], proposal.Block)
+
+	tree.Insert(key, appendAccountIDs(buf, proposal.Approvals))
+}
+
+// DelegatedStake is an amount of PERLs bonded by a delegator to a validator.
+// Listed under a validator, Account is the delegator, and listed under a
+// delegator, Account is the validator.
+type DelegatedStake struct {
+	Account AccountID
+	Amount  uint64
+}
+
+// UnbondingStake is an amount of PERLs undelegated from Validator, which may
+// be claimed back from the block at index Block onwards.
+type UnbondingStake struct {
+	Validator AccountID
+	Amount    uint64
+	Block     uint64
+}
+
+const (
+	sizeDelegatedStake = SizeAccountID + 8
+	sizeUnbondingStake = SizeAccountID + 8 + 8
+)
+
+// ReadAccountDelegators returns the accounts delegating PERLs to the
+// validator id.
+func ReadAccountDelegators(tree *avl.Tree, id AccountID) ([]DelegatedStake, bool) {
+	return readDelegatedStakes(tree, id, keyAccountDelegators[:])
+}
+
+func WriteAccountDelegators(tree *avl.Tree, id AccountID, delegators []DelegatedStake) {
+	writeDelegatedStakes(tree, id, keyAccountDelegators[:], delegators)
+}
+
+// ReadAccountDelegations returns the validators the account id delegates
+// PERLs to.
+func ReadAccountDelegations(tree *avl.Tree, id AccountID) ([]DelegatedStake, bool) {
+	return readDelegatedStakes(tree, id, keyAccountDelegations[:])
+}
+
+func WriteAccountDelegations(tree *avl.Tree, id AccountID, delegations []DelegatedStake) {
+	writeDelegatedStakes(tree, id, keyAccountDelegations[:], delegations)
+}
+
+func readDelegatedStakes(tree *avl.Tree, id AccountID, key []byte) ([]DelegatedStake, bool) {
+	buf, exists := readUnderAccounts(tree, id, key)
+	if !exists || len(buf) == 0 || len(buf)%sizeDelegatedStake != 0 {
+		return nil, false
+	}
+
+	stakes := make([]DelegatedStake, len(buf)/sizeDelegatedStake)
+
+	for i := range stakes {
+		copy(stakes[i].Account[:], buf[:SizeAccountID])
+		stakes[i].Amount = binary.LittleEndian.Uint64(buf[SizeAccountID:sizeDelegatedStake])
+
+		buf = buf[sizeDelegatedStake:]
+	}
+
+	return stakes, true
+}
+
+// writeDelegatedStakes records a list of delegated stakes of an account. An
+// empty list is removed.
+func writeDelegatedStakes(tree *avl.Tree, id AccountID, key []byte, stakes []DelegatedStake) {
+	if len(stakes) == 0 {
+		deleteUnderAccounts(tree, id, key)
+		return
+	}
+
+	buf := make([]byte, len(stakes)*sizeDelegatedStake)
+
+	for i, stake := range stakes {
+		copy(buf[i*sizeDelegatedStake:], stake.Account[:])
+		binary.LittleEndian.PutUint64(buf[i*sizeDelegatedStake+SizeAccountID:], stake.Amount)
+	}
+
+	writeUnderAccounts(tree, id, key, buf)
+}
+
+// ReadAccountCommission returns the commission the validator id charges on
+// the rewards of its delegators, in hundredths of a percent.
+func ReadAccountCommission(tree *avl.Tree, id AccountID) (uint16, bool) {
+	buf, exists := readUnderAccounts(tree, id, keyAccountCommission[:])
+	if !exists || len(buf) != 2 {
+		return 0, false
+	}
+
+	return binary.LittleEndian.Uint16(buf), true
+}
+
+func WriteAccountCommission(tree *avl.Tree, id AccountID, commission uint16) {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], commission)
+
+	writeUnderAccounts(tree, id, keyAccountCommission[:], buf[:])
+}
+
+// ReadAccountUnbondings returns the PERLs the account id undelegated which
+// are yet to be claimed back.
+func ReadAccountUnbondings(tree *avl.Tree, id AccountID) ([]UnbondingStake, bool) {
+	buf, exists := readUnderAccounts(tree, id, keyAccountUnbondings[:])
+	if !exists || len(buf) == 0 || len(buf)%sizeUnbondingStake != 0 {
+		return nil, false
+	}
+
+	unbondings := make([]UnbondingStake, len(buf)/sizeUnbondingStake)
+
+	for i := range unbondings {
+		copy(unbondings[i].Validator[:], buf[:SizeAccountID])
+		unbondings[i].Amount = binary.LittleEndian.Uint64(buf[SizeAccountID : SizeAccountID+8])
+		unbondings[i].Block = binary.LittleEndian.Uint64(buf[SizeAccountID+8 : sizeUnbondingStake])
+
+		buf = buf[sizeUnbondingStake:]
+	}
+
+	return unbondings, true
+}
+
+// WriteAccountUnbondings records the PERLs the account id undelegated which
+// are yet to be claimed back. An empty list is removed.
+func WriteAccountUnbondings(tree *avl.Tree, id AccountID, unbondings []UnbondingStake) {
+	if len(unbondings) == 0 {
+		deleteUnderAccounts(tree, id, keyAccountUnbondings[:])
+		return
+	}
+
+	buf := make([]byte, len(unbondings)*sizeUnbondingStake)
+
+	for i, unbonding := range unbondings {
+		offset := i * sizeUnbondingStake
+
+		copy(buf[offset:], unbonding.Validator[:])
+		binary.LittleEndian.PutUint64(buf[offset+SizeAccountID:], unbonding.Amount)
+		binary.LittleEndian.PutUint64(buf[offset+SizeAccountID+8:], unbonding.Block)
+	}
+
+	writeUnderAccounts(tree, id, keyAccountUnbondings[:], buf)
+}
+
+// ReadAccountLastActive returns the index of the last block the validator id
+// had a transaction applied at.
+func ReadAccountLastActive(tree *avl.Tree, id AccountID) (uint64, bool) {
+	buf, exists := readUnderAccounts(tree, id, keyAccountLastActive[:])
+	if !exists || len(buf) != 8 {
+		return 0, false
+	}
+
+	return binary.LittleEndian.Uint64(buf), true
+}
+
+func WriteAccountLastActive(tree *avl.Tree, id AccountID, block uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], block)
+
+	writeUnderAccounts(tree, id, keyAccountLastActive[:], buf[:])
+}
+
+// accountNonceKey returns the key under which the ID of the transaction of
+// nonce applied from the account id is kept in the ledger state tree.
+func accountNonceKey(id AccountID, nonce uint64) []byte {
+	k := make([]byte, 0, len(keyAccounts)+len(keyAccountNonces)+len(id)+8)
+	k = append(k, keyAccounts[:]...)
+	k = append(k, keyAccountNonces[:]...)
+	k = append(k, id[:]...)
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], nonce)
+
+	return append(k, buf[:]...)
+}
+
+// ReadAccountNonce returns the ID of the transaction of the given nonce which
+// was applied from the account id, should there be one.
+func ReadAccountNonce(tree *avl.Tree, id AccountID, nonce uint64) (TransactionID, bool) {
+	var tx TransactionID
+
+	buf, exists := tree.Lookup(accountNonceKey(id, nonce))
+	if !exists || len(buf) != SizeTransactionID {
+		return tx, false
+	}
+
+	copy(tx[:], buf)
+
+	return tx, true
+}
+
+func WriteAccountNonce(tree *avl.Tree, id AccountID, nonce uint64, tx TransactionID) {
+	tree.Insert(accountNonceKey(id, nonce), tx[:])
+}
+
+// Validator is an account staking at least sys.MinimumStake PERLs, whose
+// votes weigh in consensus by its stake.
+type Validator struct {
+	ID    AccountID
+	Stake uint64
+}
+
+// ReadValidators returns the validators of tree in ascending order of their
+// IDs.
+func ReadValidators(tree *avl.Tree) []Validator {
+	var validators []Validator
+
+	prefix := append(keyAccounts[:], keyAccountStake[:]...)
+
+	tree.IteratePrefix(prefix, func(key, value []byte) bool {
+		if len(key) != SizeAccountID || len(value) < 8 {
+			return true
+		}
+
+		if stake := binary.LittleEndian.Uint64(value); stake >= sys.MinimumStake {
+			var validator Validator
+
+			copy(validator.ID[:], key)
+			validator.Stake = stake
+
+			validators = append(validators, validator)
+		}
+
+		return true
+	})
+
+	return validators
+}
+
+// SlashingEvent is Amount PERLs slashed from the stake of a validator at the
+// block at index Block, upon the transaction with ID TxID sent by Reporter
+// reporting it for the misbehavior Opcode. Of the PERLs slashed, Burned PERLs
+// were burned and the rest rewarded to Reporter. Nonce is the nonce of the
+// conflicting transactions of validators reported for double-signing.
+type SlashingEvent struct {
+	Opcode   byte
+	Reporter AccountID
+	TxID     TransactionID
+	Nonce    uint64
+	Amount   uint64
+	Burned   uint64
+	Block    uint64
+}
+
+const sizeSlashingEvent = 1 + SizeAccountID + SizeTransactionID + 8*4
+
+// ReadAccountSlashings returns the slashing events of the validator id, from
+// oldest to newest.
+func ReadAccountSlashings(tree *avl.Tree, id AccountID) ([]SlashingEvent, bool) {
+	buf, exists := readUnderAccounts(tree, id, keyAccountSlashings[:])
+	if !exists || len(buf) == 0 || len(buf)%sizeSlashingEvent != 0 {
+		return nil, false
+	}
+
+	events := make([]SlashingEvent, len(buf)/sizeSlashingEvent)
+
+	for i := range events {
+		events[i].Opcode = buf[0]
+		copy(events[i].Reporter[:], buf[1:1+SizeAccountID])
+		copy(events[i].TxID[:], buf[1+SizeAccountID:1+SizeAccountID+SizeTransactionID])
+
+		buf = buf[1+SizeAccountID+SizeTransactionID:]
+
+		events[i].Nonce = binary.LittleEndian.Uint64(buf[:8])
+		events[i].Amount = binary.LittleEndian.Uint64(buf[8:16])
+		events[i].Burned = binary.LittleEndian.Uint64(buf[16:24])
+		events[i].Block = binary.LittleEndian.Uint64(buf[24:32])
+
+		buf = buf[32:]
+	}
+
+	return events, true
+}
+
+func WriteAccountSlashings(tree *avl.Tree, id AccountID, events []SlashingEvent) {
+	buf := make([]byte, len(events)*sizeSlashingEvent)
+
+	for i, event := range events {
+		offset := i * sizeSlashingEvent
+
+		buf[offset] = event.Opcode
+		copy(buf[offset+1:], event.Reporter[:])
+		copy(buf[offset+1+SizeAccountID:], event.TxID[:])
+
+		offset += 1 + SizeAccountID + SizeTransactionID
+
+		binary.LittleEndian.PutUint64(buf[offset:], event.Nonce)
+		binary.LittleEndian.PutUint64(buf[offset+8:], event.Amount)
+		binary.LittleEndian.PutUint64(buf[offset+16:], event.Burned)
+		binary.LittleEndian.PutUint64(buf[offset+24:], event.Block)
+	}
+
+	writeUnderAccounts(tree, id, keyAccountSlashings[:], buf)
+}
+
+func readAccountIDs(buf []byte) []AccountID {
+	if len(buf) == 0 {
+		return nil
+	}
+
+	ids := make([]AccountID, len(buf)/SizeAccountID)
+
+	for i := range ids {
+		copy(ids[i][:], buf[i*SizeAccountID:])
+	}
+
+	return ids
+}
+
+func appendAccountIDs(buf []byte, ids []AccountID) []byte {
+	for _, id := range ids {
+		buf = append(buf, id[:]...)
+	}
+
+	return buf
+}
+
 func readUnderAccounts(tree *avl.Tree, id AccountID, key []byte) ([]byte, bool) {
 	k := make([]byte, 0, len(keyAccounts)+len(key)+len(id))
 	k = append(k, keyAccounts[:]...)
@@ -268,6 +920,367 @@ func writeUnderAccounts(tree *avl.Tree, id AccountID, key, value []byte) {
 	tree.Insert(k, value)
 }
 
+func deleteUnderAccounts(tree *avl.Tree, id AccountID, key []byte) {
+	k := make([]byte, 0, len(keyAccounts)+len(key)+len(id))
+	k = append(k, keyAccounts[:]...)
+	k = append(k, key...)
+	k = append(k, id[:]...)
+
+	tree.Delete(k)
+}
+
+// ReadBeacon returns the value of the randomness beacon of the block at index.
+func ReadBeacon(tree *avl.Tree, index uint64) ([32]byte, bool) {
+	var value [32]byte
+
+	buf, exists := tree.Lookup(beaconKey(index))
+	if !exists || len(buf) != len(value) {
+		return value, false
+	}
+
+	copy(value[:], buf)
+
+	return value, true
+}
+
+func WriteBeacon(tree *avl.Tree, index uint64, value [32]byte) {
+	tree.Insert(beaconKey(index), value[:])
+}
+
+func DeleteBeacon(tree *avl.Tree, index uint64) {
+	tree.Delete(beaconKey(index))
+}
+
+// NameRecord is the owner of a name of the name service, the account or
+// contract it resolves to, and the index of the block it expires at.
+type NameRecord struct {
+	Owner  AccountID
+	Target AccountID
+	Expiry uint64
+}
+
+// ReadName returns the record of name, which may have expired.
+func ReadName(tree *avl.Tree, name string) (NameRecord, bool) {
+	var record NameRecord
+
+	buf, exists := tree.Lookup(nameKey(name))
+	if !exists || len(buf) != SizeAccountID*2+8 {
+		return record, false
+	}
+
+	copy(record.Owner[:], buf[:SizeAccountID])
+	copy(record.Target[:], buf[SizeAccountID:SizeAccountID*2])
+	record.Expiry = binary.LittleEndian.Uint64(buf[SizeAccountID*2:])
+
+	return record, true
+}
+
+func WriteName(tree *avl.Tree, name string, record NameRecord) {
+	buf := make([]byte, SizeAccountID*2+8)
+
+	copy(buf, record.Owner[:])
+	copy(buf[SizeAccountID:], record.Target[:])
+	binary.LittleEndian.PutUint64(buf[SizeAccountID*2:], record.Expiry)
+
+	tree.Insert(nameKey(name), buf)
+}
+
+// ResolveName returns the account or contract name resolves to at the block
+// at index height.
+func ResolveName(tree *avl.Tree, name string, height uint64) (AccountID, bool) {
+	record, exists := ReadName(tree, name)
+	if !exists || height >= record.Expiry {
+		return ZeroAccountID, false
+	}
+
+	return record.Target, true
+}
+
+func nameKey(name string) []byte {
+	key := make([]byte, len(keyNames)+len(name))
+
+	copy(key, keyNames[:])
+	copy(key[len(keyNames):], name)
+
+	return key
+}
+
+// ReadData returns the blob anchored by the data transaction with ID id.
+func ReadData(tree *avl.Tree, id TransactionID) ([]byte, bool) {
+	return tree.Lookup(append(keyData[:], id[:]...))
+}
+
+func WriteData(tree *avl.Tree, id TransactionID, blob []byte) {
+	tree.Insert(append(keyData[:], id[:]...), blob)
+}
+
+// StoreTransactionDiffs saves the state diffs of transactions applied in the block
+// with index block to kv. Diffs are kept by each node for its own API, and are not
+// a part of the ledger state.
+func StoreTransactionDiffs(kv store.KV, block uint64, diffs []TransactionDiff) error {
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	batch := kv.NewWriteBatch()
+
+	// The IDs of the transactions are indexed by block, such that the diffs of
+	// a block may be pruned.
+	ids := make([]byte, 0, len(diffs)*SizeTransactionID)
+
+	for _, diff := range diffs {
+		if err := batch.Put(append(keyTransactionDiffs[:], diff.ID[:]...), diff.Marshal()); err != nil {
+			return errors.Wrap(err, "error batching transaction diff")
+		}
+
+		ids = append(ids, diff.ID[:]...)
+	}
+
+	if err := batch.Put(transactionDiffBlockKey(block), ids); err != nil {
+		return errors.Wrap(err, "error batching transaction diff index")
+	}
+
+	if err := kv.CommitWriteBatch(batch); err != nil {
+		return errors.Wrap(err, "error storing transaction diffs")
+	}
+
+	return nil
+}
+
+// LoadTransactionDiff loads the state diff of the applied transaction with ID id
+// from kv.
+func LoadTransactionDiff(kv store.KV, id TransactionID) (TransactionDiff, error) {
+	buf, err := kv.Get(append(keyTransactionDiffs[:], id[:]...))
+	if err != nil {
+		return TransactionDiff{}, errors.Wrap(err, "error loading transaction diff")
+	}
+
+	return UnmarshalTransactionDiff(bytes.NewReader(buf))
+}
+
+// PruneTransactionDiffs deletes the state diffs of transactions applied in the
+// block with index block from kv, returning the number of diffs deleted.
+func PruneTransactionDiffs(kv store.KV, block uint64) (int, error) {
+	key := transactionDiffBlockKey(block)
+
+	ids, err := kv.Get(key)
+	if err != nil {
+		if errors.Cause(err) == store.ErrNotFound {
+			return 0, nil
+		}
+
+		return 0, errors.Wrap(err, "error loading transaction diff index")
+	}
+
+	batch := kv.NewWriteBatch()
+
+	for i := 0; i+SizeTransactionID <= len(ids); i += SizeTransactionID {
+		if err := batch.Delete(append(keyTransactionDiffs[:], ids[i:i+SizeTransactionID]...)); err != nil {
+			return 0, errors.Wrap(err, "error batching transaction diff deletion")
+		}
+	}
+
+	if err := batch.Delete(key); err != nil {
+		return 0, errors.Wrap(err, "error batching transaction diff index deletion")
+	}
+
+	if err := kv.CommitWriteBatch(batch); err != nil {
+		return 0, errors.Wrap(err, "error pruning transaction diffs")
+	}
+
+	return len(ids) / SizeTransactionID, nil
+}
+
+// ReadPrunedHeight returns the index of the oldest block whose data kept by
+// our node for its own API was not pruned.
+func ReadPrunedHeight(kv store.KV) uint64 {
+	buf, err := kv.Get(keyPrunedHeight[:])
+	if err != nil || len(buf) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(buf)
+}
+
+func WritePrunedHeight(kv store.KV, height uint64) error {
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], height)
+
+	return kv.Put(keyPrunedHeight[:], buf[:])
+}
+
+func transactionDiffBlockKey(block uint64) []byte {
+	key := make([]byte, len(keyTransactionDiffBlock)+8)
+
+	copy(key, keyTransactionDiffBlock[:])
+	binary.BigEndian.PutUint64(key[len(keyTransactionDiffBlock):], block)
+
+	return key
+}
+
+// StoreContractLogs appends logs to the logs kept of each contract in kv,
+// numbering them in order of emission. Like diffs, logs are kept by each node
+// for its own API, and are not a part of the ledger state.
+func StoreContractLogs(kv store.KV, logs []ContractLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	var (
+		ids  []AccountID
+		lens = make(map[AccountID]uint64)
+	)
+
+	batch := kv.NewWriteBatch()
+
+	for i := range logs {
+		id := logs[i].Contract
+
+		n, ok := lens[id]
+		if !ok {
+			var err error
+
+			if n, err = ReadContractLogsLen(kv, id); err != nil {
+				return err
+			}
+
+			ids = append(ids, id)
+		}
+
+		logs[i].Index = n
+		lens[id] = n + 1
+
+		if err := batch.Put(contractLogKey(id, n), logs[i].Marshal()); err != nil {
+			return errors.Wrap(err, "error batching contract log")
+		}
+	}
+
+	for _, id := range ids {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], lens[id])
+
+		if err := batch.Put(append(keyContractLogsLen[:], id[:]...), buf[:]); err != nil {
+			return errors.Wrap(err, "error batching number of contract logs")
+		}
+	}
+
+	if err := kv.CommitWriteBatch(batch); err != nil {
+		return errors.Wrap(err, "error storing contract logs")
+	}
+
+	return nil
+}
+
+// ReadContractLogsLen returns the number of logs kept of the contract with ID
+// id in kv.
+func ReadContractLogsLen(kv store.KV, id AccountID) (uint64, error) {
+	buf, err := kv.Get(append(keyContractLogsLen[:], id[:]...))
+	if err != nil {
+		if errors.Cause(err) == store.ErrNotFound {
+			return 0, nil
+		}
+
+		return 0, errors.Wrap(err, "error loading number of contract logs")
+	}
+
+	if len(buf) != 8 {
+		return 0, errors.New("number of contract logs is malformed")
+	}
+
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+// LoadContractLogs loads up to limit logs of the contract with ID id from kv,
+// starting from the log at index offset.
+func LoadContractLogs(kv store.KV, id AccountID, offset, limit uint64) ([]ContractLog, error) {
+	n, err := ReadContractLogsLen(kv, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset >= n {
+		return nil, nil
+	}
+
+	if limit > n-offset {
+		limit = n - offset
+	}
+
+	keys := make([][]byte, 0, limit)
+
+	for i := offset; i < offset+limit; i++ {
+		keys = append(keys, contractLogKey(id, i))
+	}
+
+	bufs, err := kv.MultiGet(keys...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading contract logs")
+	}
+
+	logs := make([]ContractLog, 0, len(bufs))
+
+	for _, buf := range bufs {
+		l, err := UnmarshalContractLog(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+
+		logs = append(logs, l)
+	}
+
+	return logs, nil
+}
+
+// SearchContractLogs returns the index of the first log of the contract with
+// ID id in kv emitted at or after the block with index block, being the
+// number of logs kept should there be none.
+func SearchContractLogs(kv store.KV, id AccountID, block uint64) (uint64, error) {
+	n, err := ReadContractLogsLen(kv, id)
+	if err != nil {
+		return 0, err
+	}
+
+	// Logs are kept in order of the blocks they were emitted at.
+	lo, hi := uint64(0), n
+
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		logs, err := LoadContractLogs(kv, id, mid, 1)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(logs) == 0 || logs[0].Block >= block {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	return lo, nil
+}
+
+func contractLogKey(id AccountID, index uint64) []byte {
+	key := make([]byte, len(keyContractLogs)+SizeAccountID+8)
+
+	copy(key, keyContractLogs[:])
+	copy(key[len(keyContractLogs):], id[:])
+	binary.BigEndian.PutUint64(key[len(keyContractLogs)+SizeAccountID:], index)
+
+	return key
+}
+
+func beaconKey(index uint64) []byte {
+	key := make([]byte, len(keyBeacon)+8)
+
+	copy(key, keyBeacon[:])
+	binary.BigEndian.PutUint64(key[len(keyBeacon):], index)
+
+	return key
+}
+
 func ReadAccountsLen(tree *avl.Tree) uint64 {
 	buf, exists := tree.Lookup(keyAccountsLen[:])
 	if !exists {
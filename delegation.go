@@ -0,0 +1,309 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"math/bits"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// Delegation lets accounts holding too few PERLs to validate share in the
+// rewards of a validator. A delegator bonds PERLs to a validator staking at
+// least sys.MinimumStake, which weigh towards the share of the fees of a block
+// the validator is rewarded as though the validator staked them itself.
+//
+// The share of a reward earned by PERLs delegated to a validator is split
+// among its delegators in proportion to the PERLs each of them bonded, less
+// the commission the validator charges. Rewards are credited to the rewards
+// of the delegators, which they withdraw like any validator would.
+//
+// Undelegated PERLs stay bonded for sys.DelegationUnbondingBlocks blocks, no
+// longer earning rewards, before the delegator may claim them back.
+
+// verifyDelegation checks that the sender of tx may act upon the PERLs it
+// delegates at the block at index height.
+func verifyDelegation(
+	readBalance, readStake func(AccountID) (uint64, bool),
+	readDelegators, readDelegations func(AccountID) ([]DelegatedStake, bool),
+	readUnbondings func(AccountID) ([]UnbondingStake, bool),
+	height uint64, tx *Transaction, payload Delegation,
+) error {
+	switch payload.Opcode {
+	case sys.DelegateStake:
+		if payload.Validator == tx.Sender {
+			return errors.Errorf("delegation: %x may not delegate to itself", tx.Sender)
+		}
+
+		if stake, _ := readStake(payload.Validator); stake < sys.MinimumStake {
+			return errors.Errorf(
+				"delegation: %x stakes %d PERLs, but validators must stake at least %d PERLs",
+				payload.Validator, stake, sys.MinimumStake,
+			)
+		}
+
+		if balance, _ := readBalance(tx.Sender); balance < payload.Amount {
+			return errors.Wrapf(
+				ErrInsufficientBalance, "delegation: %x attempt to delegate %d PERLs, but only has %d PERLs",
+				tx.Sender, payload.Amount, balance,
+			)
+		}
+
+		delegators, _ := readDelegators(payload.Validator)
+		if findDelegatedStake(delegators, tx.Sender) < 0 && len(delegators) >= sys.MaxDelegators {
+			return errors.Errorf("delegation: %x already has %d delegators", payload.Validator, len(delegators))
+		}
+
+		delegations, _ := readDelegations(tx.Sender)
+		if findDelegatedStake(delegations, payload.Validator) < 0 && len(delegations) >= sys.MaxDelegations {
+			return errors.Errorf("delegation: %x already delegates to %d validators", tx.Sender, len(delegations))
+		}
+	case sys.UndelegateStake:
+		delegations, _ := readDelegations(tx.Sender)
+
+		var delegated uint64
+		if i := findDelegatedStake(delegations, payload.Validator); i >= 0 {
+			delegated = delegations[i].Amount
+		}
+
+		if delegated < payload.Amount {
+			return errors.Errorf(
+				"delegation: %x attempt to undelegate %d PERLs from %x, but only has delegated %d PERLs",
+				tx.Sender, payload.Amount, payload.Validator, delegated,
+			)
+		}
+
+		if unbondings, _ := readUnbondings(tx.Sender); len(unbondings) >= sys.MaxUnbondings {
+			return errors.Errorf("delegation: %x already has %d undelegations unbonding", tx.Sender, len(unbondings))
+		}
+	case sys.ClaimUnbonded:
+		unbondings, _ := readUnbondings(tx.Sender)
+
+		if _, claimable := splitUnbondings(unbondings, height); claimable == 0 {
+			return errors.Errorf("delegation: %x has no unbonded PERLs to claim", tx.Sender)
+		}
+	}
+
+	return nil
+}
+
+// findDelegatedStake returns the index of the stake delegated by or to
+// account among stakes, or -1 should there be none.
+func findDelegatedStake(stakes []DelegatedStake, account AccountID) int {
+	for i, stake := range stakes {
+		if stake.Account == account {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// addDelegatedStake returns a copy of stakes with amount PERLs added to the
+// stake of account, or removed from it should remove be set. Stakes left with
+// no PERLs are removed.
+func addDelegatedStake(stakes []DelegatedStake, account AccountID, amount uint64, remove bool) []DelegatedStake {
+	updated := make([]DelegatedStake, 0, len(stakes)+1)
+	found := false
+
+	for _, stake := range stakes {
+		if stake.Account == account {
+			found = true
+
+			if remove {
+				stake.Amount -= amount
+			} else {
+				stake.Amount += amount
+			}
+		}
+
+		if stake.Amount > 0 {
+			updated = append(updated, stake)
+		}
+	}
+
+	if !found && !remove {
+		updated = append(updated, DelegatedStake{Account: account, Amount: amount})
+	}
+
+	return updated
+}
+
+// splitUnbondings returns the undelegations among unbondings which are still
+// bonded at the block at index height, and the sum of the PERLs of the rest.
+func splitUnbondings(unbondings []UnbondingStake, height uint64) ([]UnbondingStake, uint64) {
+	var (
+		bonded    []UnbondingStake
+		claimable uint64
+	)
+
+	for _, unbonding := range unbondings {
+		if height < unbonding.Block {
+			bonded = append(bonded, unbonding)
+		} else {
+			claimable += unbonding.Amount
+		}
+	}
+
+	return bonded, claimable
+}
+
+// totalDelegatedStake returns the sum of the PERLs delegated to a validator.
+func totalDelegatedStake(delegators []DelegatedStake) uint64 {
+	var total uint64
+
+	for _, delegator := range delegators {
+		total += delegator.Amount
+	}
+
+	return total
+}
+
+// mulDiv returns a * b / c, which must not overflow.
+func mulDiv(a, b, c uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	quo, _ := bits.Div64(hi, lo, c)
+
+	return quo
+}
+
+// rewardValidator credits reward to a validator staking stake PERLs of its
+// own and having delegated PERLs delegated to it. The share of the reward
+// earned by the delegated PERLs, less the commission of the validator, is
+// split among its delegators, with whatever is left after rounding credited
+// to the validator.
+func rewardValidator(ctx *CollapseContext, validator AccountID, stake, delegated, reward uint64) {
+	remaining := reward
+
+	if delegated > 0 {
+		delegators, _ := ctx.ReadAccountDelegators(validator)
+		total := totalDelegatedStake(delegators)
+
+		if total > 0 {
+			commission, _ := ctx.ReadAccountCommission(validator)
+
+			shared := mulDiv(reward, delegated, stake+delegated)
+			shared -= mulDiv(shared, uint64(commission), uint64(sys.MaxCommission))
+
+			for _, delegator := range delegators {
+				share := mulDiv(shared, delegator.Amount, total)
+				if share == 0 {
+					continue
+				}
+
+				delegatorReward, _ := ctx.ReadAccountReward(delegator.Account)
+				ctx.WriteAccountReward(delegator.Account, delegatorReward+share)
+
+				remaining -= share
+			}
+		}
+	}
+
+	validatorReward, _ := ctx.ReadAccountReward(validator)
+	ctx.WriteAccountReward(validator, validatorReward+remaining)
+}
+
+func applyDelegationTransaction(ctx *CollapseContext, block *Block, tx *Transaction) error {
+	payload, err := ParseDelegation(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	height := block.Index + 1
+
+	if err := verifyDelegation(
+		ctx.ReadAccountBalance, ctx.ReadAccountStake, ctx.ReadAccountDelegators, ctx.ReadAccountDelegations,
+		ctx.ReadAccountUnbondings, height, tx, payload,
+	); err != nil {
+		return err
+	}
+
+	switch payload.Opcode {
+	case sys.DelegateStake, sys.UndelegateStake:
+		remove := payload.Opcode == sys.UndelegateStake
+
+		delegators, _ := ctx.ReadAccountDelegators(payload.Validator)
+		delegations, _ := ctx.ReadAccountDelegations(tx.Sender)
+
+		ctx.WriteAccountDelegators(payload.Validator, addDelegatedStake(delegators, tx.Sender, payload.Amount, remove))
+		ctx.WriteAccountDelegations(tx.Sender, addDelegatedStake(delegations, payload.Validator, payload.Amount, remove))
+
+		if !remove {
+			balance, _ := ctx.ReadAccountBalance(tx.Sender)
+			ctx.WriteAccountBalance(tx.Sender, balance-payload.Amount)
+
+			break
+		}
+
+		unbondings, _ := ctx.ReadAccountUnbondings(tx.Sender)
+		unbondings = append(append([]UnbondingStake(nil), unbondings...), UnbondingStake{
+			Validator: payload.Validator,
+			Amount:    payload.Amount,
+			Block:     height + sys.DelegationUnbondingBlocks,
+		})
+
+		ctx.WriteAccountUnbondings(tx.Sender, unbondings)
+	case sys.ClaimUnbonded:
+		unbondings, _ := ctx.ReadAccountUnbondings(tx.Sender)
+		bonded, claimable := splitUnbondings(unbondings, height)
+
+		balance, _ := ctx.ReadAccountBalance(tx.Sender)
+
+		ctx.WriteAccountBalance(tx.Sender, balance+claimable)
+		ctx.WriteAccountUnbondings(tx.Sender, bonded)
+	case sys.SetCommission:
+		ctx.WriteAccountCommission(tx.Sender, payload.Commission)
+	}
+
+	return nil
+}
+
+func validateDelegationTransaction(snapshot *avl.Tree, tx Transaction) error {
+	payload, err := ParseDelegation(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	readBalance := func(id AccountID) (uint64, bool) {
+		return ReadAccountBalance(snapshot, id)
+	}
+
+	readStake := func(id AccountID) (uint64, bool) {
+		return ReadAccountStake(snapshot, id)
+	}
+
+	readDelegators := func(id AccountID) ([]DelegatedStake, bool) {
+		return ReadAccountDelegators(snapshot, id)
+	}
+
+	readDelegations := func(id AccountID) ([]DelegatedStake, bool) {
+		return ReadAccountDelegations(snapshot, id)
+	}
+
+	readUnbondings := func(id AccountID) ([]UnbondingStake, bool) {
+		return ReadAccountUnbondings(snapshot, id)
+	}
+
+	return verifyDelegation(
+		readBalance, readStake, readDelegators, readDelegations, readUnbondings, tx.earliestHeight(), &tx, payload,
+	)
+}
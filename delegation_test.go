@@ -0,0 +1,159 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegationTransaction(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureDelegation)()
+
+	keys := make([]*skademlia.Keypair, 3)
+
+	for i := range keys {
+		var err error
+
+		keys[i], err = skademlia.NewKeys(1, 1)
+		require.NoError(t, err)
+	}
+
+	validator, delegator, other := keys[0], keys[1], keys[2]
+
+	tree := avl.New(store.NewInmem())
+
+	WriteAccountBalance(tree, delegator.PublicKey(), 1000)
+	WriteAccountBalance(tree, other.PublicKey(), 1000)
+
+	delegate := func(keys *skademlia.Keypair, index uint64, delegation Delegation) Transaction {
+		payload, err := delegation.Marshal()
+		require.NoError(t, err)
+
+		return NewTransaction(keys, 1, index, sys.TagDelegation, payload)
+	}
+
+	block := NewBlock(10, tree.Checksum())
+
+	bond := Delegation{Opcode: sys.DelegateStake, Validator: validator.PublicKey(), Amount: 300}
+
+	// PERLs may only be delegated to validators.
+	tx := delegate(delegator, 10, bond)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	WriteAccountStake(tree, validator.PublicKey(), sys.MinimumStake)
+
+	// Accounts may not delegate more PERLs than they hold, nor to themselves.
+	tx = delegate(delegator, 10, Delegation{Opcode: sys.DelegateStake, Validator: validator.PublicKey(), Amount: 1001})
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = delegate(validator, 10, Delegation{Opcode: sys.DelegateStake, Validator: validator.PublicKey(), Amount: 1})
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = delegate(delegator, 10, bond)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = delegate(other, 10, Delegation{Opcode: sys.DelegateStake, Validator: validator.PublicKey(), Amount: 200})
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	balance, _ := ReadAccountBalance(tree, delegator.PublicKey())
+	assert.EqualValues(t, 400, balance)
+
+	delegators, _ := ReadAccountDelegators(tree, validator.PublicKey())
+	assert.Equal(t, []DelegatedStake{
+		{Account: delegator.PublicKey(), Amount: 600},
+		{Account: other.PublicKey(), Amount: 200},
+	}, delegators)
+
+	delegations, _ := ReadAccountDelegations(tree, delegator.PublicKey())
+	assert.Equal(t, []DelegatedStake{{Account: validator.PublicKey(), Amount: 600}}, delegations)
+
+	tx = delegate(validator, 10, Delegation{Opcode: sys.SetCommission, Commission: 1000})
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	// Of a reward of 900 PERLs, 800 are earned by delegated PERLs, of which
+	// the validator charges a commission of 10%. The remaining 720 PERLs are
+	// split among the delegators in proportion to their delegations.
+	ctx := NewCollapseContext(tree)
+	rewardValidator(ctx, validator.PublicKey(), 100, 800, 900)
+	require.NoError(t, ctx.Flush())
+
+	reward, _ := ReadAccountReward(tree, validator.PublicKey())
+	assert.EqualValues(t, 180, reward)
+
+	reward, _ = ReadAccountReward(tree, delegator.PublicKey())
+	assert.EqualValues(t, 540, reward)
+
+	reward, _ = ReadAccountReward(tree, other.PublicKey())
+	assert.EqualValues(t, 180, reward)
+
+	// Accounts may not undelegate more PERLs than they delegated.
+	tx = delegate(other, 10, Delegation{Opcode: sys.UndelegateStake, Validator: validator.PublicKey(), Amount: 201})
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = delegate(other, 10, Delegation{Opcode: sys.UndelegateStake, Validator: validator.PublicKey(), Amount: 200})
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	delegators, _ = ReadAccountDelegators(tree, validator.PublicKey())
+	assert.Equal(t, []DelegatedStake{{Account: delegator.PublicKey(), Amount: 600}}, delegators)
+
+	_, exists := ReadAccountDelegations(tree, other.PublicKey())
+	assert.False(t, exists)
+
+	unbondings, _ := ReadAccountUnbondings(tree, other.PublicKey())
+	assert.Equal(t, []UnbondingStake{
+		{Validator: validator.PublicKey(), Amount: 200, Block: 11 + sys.DelegationUnbondingBlocks},
+	}, unbondings)
+
+	// Undelegated PERLs may only be claimed once their unbonding period elapsed.
+	claim := Delegation{Opcode: sys.ClaimUnbonded}
+
+	tx = delegate(other, 10, claim)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	later := NewBlock(10+sys.DelegationUnbondingBlocks, tree.Checksum())
+
+	tx = delegate(other, 10+sys.DelegationUnbondingBlocks, claim)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &later, &tx))
+
+	balance, _ = ReadAccountBalance(tree, other.PublicKey())
+	assert.EqualValues(t, 1000, balance)
+
+	_, exists = ReadAccountUnbondings(tree, other.PublicKey())
+	assert.False(t, exists)
+
+	assert.Error(t, ApplyTransaction(tree, &later, &tx))
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	accountDiffBalance byte = 1 << iota
+	accountDiffStake
+	accountDiffReward
+	accountDiffGasBalance
+	accountDiffContractCreated
+)
+
+// FieldDiff is the value of an account field before and after a transaction
+// was applied.
+type FieldDiff struct {
+	Before uint64
+	After  uint64
+}
+
+// AccountDiff is the set of changes a single transaction made to an account.
+// Fields the transaction did not change are nil.
+type AccountDiff struct {
+	ID AccountID
+
+	Balance    *FieldDiff
+	Stake      *FieldDiff
+	Reward     *FieldDiff
+	GasBalance *FieldDiff
+
+	// ContractCreated is set if the transaction spawned the account as a smart contract.
+	ContractCreated bool
+
+	// ContractPages are the indices of the memory pages of the smart contract the
+	// transaction modified, in ascending order.
+	ContractPages []uint64
+}
+
+// TransactionDiff is the set of accounts changed by applying a transaction,
+// ordered by when the transaction first changed them. Fees paid are included,
+// but not the rewards distributed to validators at the end of a block.
+type TransactionDiff struct {
+	ID       TransactionID
+	Accounts []AccountDiff
+}
+
+func (d TransactionDiff) Marshal() []byte {
+	w := bytes.NewBuffer(make([]byte, 0, SizeTransactionID+4+len(d.Accounts)*(SizeAccountID+1+4)))
+
+	w.Write(d.ID[:])
+
+	var buf [8]byte
+
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(d.Accounts)))
+	w.Write(buf[:4])
+
+	for _, account := range d.Accounts {
+		w.Write(account.ID[:])
+
+		var flags byte
+
+		fields := []*FieldDiff{account.Balance, account.Stake, account.Reward, account.GasBalance}
+
+		for i, field := range fields {
+			if field != nil {
+				flags |= 1 << uint(i)
+			}
+		}
+
+		if account.ContractCreated {
+			flags |= accountDiffContractCreated
+		}
+
+		w.WriteByte(flags)
+
+		for _, field := range fields {
+			if field == nil {
+				continue
+			}
+
+			binary.BigEndian.PutUint64(buf[:], field.Before)
+			w.Write(buf[:])
+
+			binary.BigEndian.PutUint64(buf[:], field.After)
+			w.Write(buf[:])
+		}
+
+		binary.BigEndian.PutUint32(buf[:4], uint32(len(account.ContractPages)))
+		w.Write(buf[:4])
+
+		for _, page := range account.ContractPages {
+			binary.BigEndian.PutUint64(buf[:], page)
+			w.Write(buf[:])
+		}
+	}
+
+	return w.Bytes()
+}
+
+func UnmarshalTransactionDiff(r io.Reader) (TransactionDiff, error) {
+	var d TransactionDiff
+
+	if _, err := io.ReadFull(r, d.ID[:]); err != nil {
+		return d, errors.Wrap(err, "failed to decode transaction diff ID")
+	}
+
+	var buf [8]byte
+
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return d, errors.Wrap(err, "failed to decode number of accounts in transaction diff")
+	}
+
+	numAccounts := binary.BigEndian.Uint32(buf[:4])
+
+	for i := uint32(0); i < numAccounts; i++ {
+		var account AccountDiff
+
+		if _, err := io.ReadFull(r, account.ID[:]); err != nil {
+			return d, errors.Wrap(err, "failed to decode account diff ID")
+		}
+
+		if _, err := io.ReadFull(r, buf[:1]); err != nil {
+			return d, errors.Wrap(err, "failed to decode account diff flags")
+		}
+
+		flags := buf[0]
+
+		for j, field := range []**FieldDiff{&account.Balance, &account.Stake, &account.Reward, &account.GasBalance} {
+			if flags&(1<<uint(j)) == 0 {
+				continue
+			}
+
+			var diff FieldDiff
+
+			if _, err := io.ReadFull(r, buf[:]); err != nil {
+				return d, errors.Wrap(err, "failed to decode account diff field")
+			}
+
+			diff.Before = binary.BigEndian.Uint64(buf[:])
+
+			if _, err := io.ReadFull(r, buf[:]); err != nil {
+				return d, errors.Wrap(err, "failed to decode account diff field")
+			}
+
+			diff.After = binary.BigEndian.Uint64(buf[:])
+
+			*field = &diff
+		}
+
+		account.ContractCreated = flags&accountDiffContractCreated != 0
+
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return d, errors.Wrap(err, "failed to decode number of contract pages in account diff")
+		}
+
+		numPages := binary.BigEndian.Uint32(buf[:4])
+
+		for j := uint32(0); j < numPages; j++ {
+			if _, err := io.ReadFull(r, buf[:]); err != nil {
+				return d, errors.Wrap(err, "failed to decode contract page index in account diff")
+			}
+
+			account.ContractPages = append(account.ContractPages, binary.BigEndian.Uint64(buf[:]))
+		}
+
+		d.Accounts = append(d.Accounts, account)
+	}
+
+	return d, nil
+}
+
+// stateDiff records the changes made to accounts while a single transaction
+// is being applied to a CollapseContext.
+type stateDiff struct {
+	ids      []AccountID
+	accounts map[AccountID]*AccountDiff
+}
+
+func newStateDiff() *stateDiff {
+	return &stateDiff{accounts: make(map[AccountID]*AccountDiff)}
+}
+
+func (s *stateDiff) account(id AccountID) *AccountDiff {
+	account, ok := s.accounts[id]
+	if !ok {
+		account = &AccountDiff{ID: id}
+
+		s.accounts[id] = account
+		s.ids = append(s.ids, id)
+	}
+
+	return account
+}
+
+// recordField keeps the value a field had before the first write to it, and
+// the value of the latest write.
+func (s *stateDiff) recordField(field **FieldDiff, before, after uint64) {
+	if *field == nil {
+		*field = &FieldDiff{Before: before}
+	}
+
+	(*field).After = after
+}
+
+func (s *stateDiff) recordPages(id AccountID, pages []uint64) {
+	if len(pages) == 0 {
+		return
+	}
+
+	account := s.account(id)
+
+	seen := make(map[uint64]struct{}, len(account.ContractPages))
+	for _, page := range account.ContractPages {
+		seen[page] = struct{}{}
+	}
+
+	for _, page := range pages {
+		if _, ok := seen[page]; !ok {
+			account.ContractPages = append(account.ContractPages, page)
+		}
+	}
+
+	sort.Slice(account.ContractPages, func(i, j int) bool {
+		return account.ContractPages[i] < account.ContractPages[j]
+	})
+}
+
+// finish returns the recorded changes, leaving out fields which were written
+// to but ended up with the value they started with.
+func (s *stateDiff) finish(id TransactionID) TransactionDiff {
+	diff := TransactionDiff{ID: id}
+
+	for _, accountID := range s.ids {
+		account := *s.accounts[accountID]
+
+		for _, field := range []**FieldDiff{&account.Balance, &account.Stake, &account.Reward, &account.GasBalance} {
+			if *field != nil && (*field).Before == (*field).After {
+				*field = nil
+			}
+		}
+
+		if account.Balance == nil && account.Stake == nil && account.Reward == nil && account.GasBalance == nil &&
+			!account.ContractCreated && len(account.ContractPages) == 0 {
+			continue
+		}
+
+		diff.Accounts = append(diff.Accounts, account)
+	}
+
+	return diff
+}
+
+// changedPages returns the indices of the pages which differ between two
+// snapshots of the memory of a smart contract. Memory past the end of the
+// shorter snapshot is treated as being zero.
+func changedPages(before, after []byte) []uint64 {
+	size := len(before)
+	if len(after) > size {
+		size = len(after)
+	}
+
+	var (
+		pages []uint64
+		zero  [PageSize]byte
+	)
+
+	page := func(mem []byte, start, end int) []byte {
+		if start >= len(mem) {
+			return zero[:end-start]
+		}
+
+		if end > len(mem) {
+			return append(mem[start:len(mem):len(mem)], zero[:end-len(mem)]...)
+		}
+
+		return mem[start:end]
+	}
+
+	for start := 0; start < size; start += PageSize {
+		end := start + PageSize
+		if end > size {
+			end = size
+		}
+
+		if !bytes.Equal(page(before, start, end), page(after, start, end)) {
+			pages = append(pages, uint64(start/PageSize))
+		}
+	}
+
+	return pages
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionDiffs(t *testing.T) {
+	kv := store.NewInmem()
+	accounts := NewAccounts(kv)
+
+	alice, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	bob, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	WriteAccountBalance(accounts.tree, alice.PublicKey(), 1000000)
+
+	transfer := func(nonce uint64, amount uint64) *Transaction {
+		payload, err := Transfer{Recipient: bob.PublicKey(), Amount: amount}.Marshal()
+		require.NoError(t, err)
+
+		tx := NewTransaction(alice, nonce, 0, sys.TagTransfer, payload)
+
+		return &tx
+	}
+
+	code, err := ioutil.ReadFile("testdata/transfer_back.wasm")
+	require.NoError(t, err)
+
+	payload, err := buildContractSpawnPayload(100000, 0, code).Marshal()
+	require.NoError(t, err)
+
+	spawn := NewTransaction(alice, 3, 0, sys.TagContract, payload)
+
+	block := NewBlock(0, accounts.tree.Checksum())
+
+	// The second transfer is rejected, and so has no diff.
+	txs := []*Transaction{transfer(1, 100), transfer(2, 10000000), &spawn}
+
+	res, err := collapseTransactions(block.Index+1, txs, &block, accounts)
+	require.NoError(t, err)
+	require.Len(t, res.applied, 2)
+	require.Len(t, res.diffs, 2)
+
+	// No validators were staked, and so the block itself changed nothing.
+	assert.Empty(t, res.blockDiff.Accounts)
+
+	diff := res.diffs[0]
+	assert.Equal(t, txs[0].ID, diff.ID)
+
+	if assert.Len(t, diff.Accounts, 2) {
+		assert.Equal(t, AccountID(alice.PublicKey()), diff.Accounts[0].ID)
+		assert.Equal(t, &FieldDiff{Before: 1000000, After: 1000000 - 100 - txs[0].Fee()}, diff.Accounts[0].Balance)
+		assert.Nil(t, diff.Accounts[0].Stake)
+
+		assert.Equal(t, AccountID(bob.PublicKey()), diff.Accounts[1].ID)
+		assert.Equal(t, &FieldDiff{Before: 0, After: 100}, diff.Accounts[1].Balance)
+	}
+
+	diff = res.diffs[1]
+	assert.Equal(t, spawn.ID, diff.ID)
+
+	if assert.Len(t, diff.Accounts, 2) {
+		assert.Equal(t, AccountID(alice.PublicKey()), diff.Accounts[0].ID)
+		assert.Equal(t, AccountID(spawn.ID), diff.Accounts[1].ID)
+		assert.True(t, diff.Accounts[1].ContractCreated)
+	}
+
+	// Diffs survive being stored.
+	require.NoError(t, StoreTransactionDiffs(kv, 1, res.diffs))
+
+	for _, expected := range res.diffs {
+		diff, err := LoadTransactionDiff(kv, expected.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, diff)
+		}
+	}
+
+	_, err = LoadTransactionDiff(kv, txs[1].ID)
+	assert.Equal(t, store.ErrNotFound, errors.Cause(err))
+}
+
+func TestTransactionDiffMarshal(t *testing.T) {
+	diff := TransactionDiff{
+		ID: TransactionID{1},
+		Accounts: []AccountDiff{
+			{ID: AccountID{2}, Stake: &FieldDiff{Before: 5, After: 3}, Reward: &FieldDiff{Before: 0, After: 2}},
+			{ID: AccountID{3}, GasBalance: &FieldDiff{Before: 7, After: 1}, ContractPages: []uint64{0, 4}},
+		},
+	}
+
+	decoded, err := UnmarshalTransactionDiff(bytes.NewReader(diff.Marshal()))
+	if assert.NoError(t, err) {
+		assert.Equal(t, diff, decoded)
+	}
+
+	_, err = UnmarshalTransactionDiff(bytes.NewReader(diff.Marshal()[:SizeTransactionID+10]))
+	assert.Error(t, err)
+}
+
+func TestChangedPages(t *testing.T) {
+	before := make([]byte, PageSize*2)
+	after := make([]byte, PageSize*3)
+
+	assert.Empty(t, changedPages(before, after))
+
+	after[PageSize+1] = 1
+	after[PageSize*2+2] = 1
+
+	assert.Equal(t, []uint64{1, 2}, changedPages(before, after))
+	assert.Equal(t, []uint64{1, 2}, changedPages(after, before))
+	assert.Equal(t, []uint64{0}, changedPages(nil, []byte{1}))
+}
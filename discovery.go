@@ -0,0 +1,450 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package wavelet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxPeers is the number of peers our node connects to by
+	// default, past which it stops discovering peers and disconnects from
+	// the least reputable ones.
+	DefaultMaxPeers = 32
+
+	// SeedService is the service DNS seeds advertise peers under as SRV
+	// records, being looked up as _wavelet._tcp.<seed>.
+	SeedService = "wavelet"
+
+	// maxExchangedPeers is the most addresses of peers exchanged at once.
+	maxExchangedPeers = 32
+
+	// discoveryInterval is how often peers are exchanged and persisted.
+	discoveryInterval = 1 * time.Minute
+)
+
+// SeedResolver looks up the DNS records of seeds. It is implemented by
+// *net.Resolver.
+type SeedResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// ResolveSeeds looks up the addresses of the peers advertised by DNS seeds.
+// A seed advertises peers either as SRV records under _wavelet._tcp.<seed>,
+// or as TXT records of <seed> listing host:port addresses separated by
+// commas or spaces. An error is returned only should no seed resolve.
+func ResolveSeeds(ctx context.Context, resolver SeedResolver, seeds []string) ([]string, error) {
+	var (
+		addrs []string
+		err   error
+	)
+
+	seen := make(map[string]struct{})
+	add := func(addr string) {
+		if _, exists := seen[addr]; !exists {
+			seen[addr] = struct{}{}
+			addrs = append(addrs, addr)
+		}
+	}
+
+	resolved := 0
+
+	for _, seed := range seeds {
+		found, serr := resolveSeed(ctx, resolver, seed)
+		if serr != nil {
+			err = serr
+			continue
+		}
+
+		resolved++
+
+		for _, addr := range found {
+			add(addr)
+		}
+	}
+
+	if resolved == 0 && err != nil {
+		return nil, err
+	}
+
+	return addrs, nil
+}
+
+func resolveSeed(ctx context.Context, resolver SeedResolver, seed string) ([]string, error) {
+	var addrs []string
+
+	_, records, srvErr := resolver.LookupSRV(ctx, SeedService, "tcp", seed)
+
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		addrs = append(addrs, net.JoinHostPort(host, strconv.FormatUint(uint64(record.Port), 10)))
+	}
+
+	txts, txtErr := resolver.LookupTXT(ctx, seed)
+
+	for _, txt := range txts {
+		for _, field := range strings.FieldsFunc(txt, func(r rune) bool {
+			return r == ',' || r == ' '
+		}) {
+			if _, _, err := net.SplitHostPort(field); err == nil {
+				addrs = append(addrs, field)
+			}
+		}
+	}
+
+	if srvErr != nil && txtErr != nil {
+		return nil, errors.Wrapf(srvErr, "failed to resolve seed %s", seed)
+	}
+
+	return addrs, nil
+}
+
+// StorePeers stores the addresses of peers in kv, such that they may be
+// dialed should our node restart.
+func StorePeers(kv store.KV, addrs []string) error {
+	var buf bytes.Buffer
+
+	var n [2]byte
+
+	for _, addr := range addrs {
+		binary.BigEndian.PutUint16(n[:], uint16(len(addr)))
+		buf.Write(n[:])
+		buf.WriteString(addr)
+	}
+
+	if err := kv.Put(keyPeers[:], buf.Bytes()); err != nil {
+		return errors.Wrap(err, "error storing peers")
+	}
+
+	return nil
+}
+
+// LoadPeers loads the addresses of peers stored in kv.
+func LoadPeers(kv store.KV) ([]string, error) {
+	buf, err := kv.Get(keyPeers[:])
+	if err != nil {
+		if errors.Cause(err) == store.ErrNotFound {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "error loading peers")
+	}
+
+	var addrs []string
+
+	r := bytes.NewReader(buf)
+
+	var n [2]byte
+
+	for r.Len() > 0 {
+		if _, err := io.ReadFull(r, n[:]); err != nil {
+			return nil, errors.Wrap(err, "failed to decode peer address length")
+		}
+
+		addr := make([]byte, binary.BigEndian.Uint16(n[:]))
+
+		if _, err := io.ReadFull(r, addr); err != nil {
+			return nil, errors.Wrap(err, "failed to decode peer address")
+		}
+
+		addrs = append(addrs, string(addr))
+	}
+
+	return addrs, nil
+}
+
+// Discovery discovers peers for our node to connect to beyond the ones it
+// is launched with, from DNS seeds, from the peers it was connected to
+// before restarting, and by exchanging the addresses of peers with the
+// ones it is connected to. It keeps our node connected to at most a maximum
+// number of peers, disconnecting from the least reputable ones past it.
+type Discovery struct {
+	kv         store.KV
+	client     *skademlia.Client
+	reputation *Reputation
+
+	resolver SeedResolver
+	seeds    []string
+	maxPeers int
+}
+
+// NewDiscovery returns a Discovery connecting client to up to maxPeers
+// peers, discovered from seeds and the peers stored in kv.
+func NewDiscovery(
+	kv store.KV, client *skademlia.Client, reputation *Reputation, seeds []string, maxPeers int,
+) *Discovery {
+	if maxPeers <= 0 {
+		maxPeers = DefaultMaxPeers
+	}
+
+	return &Discovery{
+		kv:         kv,
+		client:     client,
+		reputation: reputation,
+		resolver:   net.DefaultResolver,
+		seeds:      seeds,
+		maxPeers:   maxPeers,
+	}
+}
+
+// MaxPeers returns the most peers our node connects to.
+func (d *Discovery) MaxPeers() int {
+	return d.maxPeers
+}
+
+// Peers returns the number of peers our node is connected to.
+func (d *Discovery) Peers() int {
+	return len(d.client.ClosestPeerIDs())
+}
+
+// Bootstrap dials the peers stored before our node restarted and the ones
+// advertised by DNS seeds, until our node is connected to the maximum
+// number of peers.
+func (d *Discovery) Bootstrap() {
+	logger := log.Network("discovery")
+
+	stored, err := LoadPeers(d.kv)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to load the peers stored.")
+	}
+
+	var seeded []string
+
+	if len(d.seeds) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		seeded, err = ResolveSeeds(ctx, d.resolver, d.seeds)
+		cancel()
+
+		if err != nil {
+			logger.Warn().Err(err).Strs("seeds", d.seeds).Msg("Failed to resolve DNS seeds.")
+		}
+	}
+
+	logger.Info().
+		Int("num_stored", len(stored)).
+		Int("num_seeded", len(seeded)).
+		Msg("Bootstrapping from stored and seeded peers.")
+
+	d.Dial(append(stored, seeded...))
+}
+
+// Dial dials the peers at addrs in a random order, skipping the ones our
+// node is connected to, until it is connected to the maximum number of
+// peers. It returns the number of peers dialed.
+func (d *Discovery) Dial(addrs []string) int {
+	connected := d.connected()
+
+	rand.Shuffle(len(addrs), func(i, j int) {
+		addrs[i], addrs[j] = addrs[j], addrs[i]
+	})
+
+	dialed := 0
+
+	for _, addr := range addrs {
+		if len(connected) >= d.maxPeers {
+			break
+		}
+
+		if _, exists := connected[addr]; exists || addr == d.client.ID().Address() {
+			continue
+		}
+
+		connected[addr] = struct{}{}
+
+		if _, err := d.client.Dial(addr, skademlia.WithTimeout(3*time.Second)); err != nil {
+			logger := log.Network("discovery")
+			logger.Debug().Err(err).Str("addr", addr).Msg("Failed to dial discovered peer.")
+
+			delete(connected, addr)
+
+			continue
+		}
+
+		dialed++
+	}
+
+	return dialed
+}
+
+// Exchange asks the peers our node is connected to for the addresses of
+// their peers, dialing the ones our node is not connected to should it be
+// connected to less than the maximum number of peers.
+func (d *Discovery) Exchange() {
+	missing := d.maxPeers - d.Peers()
+	if missing <= 0 {
+		return
+	}
+
+	limit := missing
+	if limit > maxExchangedPeers {
+		limit = maxExchangedPeers
+	}
+
+	var addrs []string
+
+	for _, p := range d.reputation.Filter(d.client.ClosestPeers()) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		res, err := NewWaveletClient(p.Conn()).ExchangePeers(ctx, &PeerExchangeRequest{Limit: uint32(limit)})
+		cancel()
+
+		if err != nil {
+			continue
+		}
+
+		if len(res.Addresses) > limit {
+			res.Addresses = res.Addresses[:limit]
+		}
+
+		addrs = append(addrs, res.Addresses...)
+	}
+
+	if dialed := d.Dial(addrs); dialed > 0 {
+		logger := log.Network("discovery")
+		logger.Info().Int("num_dialed", dialed).Msg("Dialed peers exchanged with our peers.")
+	}
+}
+
+// Trim disconnects from the least reputable peers past the maximum number of
+// peers.
+func (d *Discovery) Trim() {
+	ids := d.client.ClosestPeerIDs()
+	if len(ids) <= d.maxPeers {
+		return
+	}
+
+	scores := make(map[AccountID]float64, len(ids))
+
+	for _, id := range ids {
+		scores[id.PublicKey()] = d.reputation.Score(id).Score
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		return scores[ids[i].PublicKey()] > scores[ids[j].PublicKey()]
+	})
+
+	for _, id := range ids[d.maxPeers:] {
+		_ = d.client.DisconnectByAddress(id.Address())
+	}
+}
+
+// Healthy returns the addresses of the peers our node is connected to which
+// are neither banned nor penalized, the most reputable ones first, up to
+// limit.
+func (d *Discovery) Healthy(limit int) []string {
+	ids := d.client.ClosestPeerIDs()
+	scores := make([]PeerScore, 0, len(ids))
+
+	for _, id := range ids {
+		score := d.reputation.Score(id)
+		if score.Score < 0 || score.Banned(time.Now()) {
+			continue
+		}
+
+		scores = append(scores, score)
+	}
+
+	// Peers whose latency is yet to be observed go last.
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Latency == 0 || scores[j].Latency == 0 {
+			return scores[j].Latency == 0 && scores[i].Latency != 0
+		}
+
+		return scores[i].Latency < scores[j].Latency
+	})
+
+	if len(scores) > limit {
+		scores = scores[:limit]
+	}
+
+	addrs := make([]string, 0, len(scores))
+
+	for _, score := range scores {
+		addrs = append(addrs, score.Address)
+	}
+
+	return addrs
+}
+
+// Persist stores the addresses of the healthy peers our node is connected
+// to, such that they are dialed should our node restart. Nothing is stored
+// should our node not be connected to any healthy peer, so as to keep the
+// peers stored before.
+func (d *Discovery) Persist() error {
+	addrs := d.Healthy(d.maxPeers)
+	if len(addrs) == 0 {
+		return nil
+	}
+
+	return StorePeers(d.kv, addrs)
+}
+
+// Run exchanges peers, disconnects from the peers past the maximum number of
+// peers, and persists the healthy peers every discovery interval until ctx
+// is done.
+func (d *Discovery) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(discoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.persist()
+			return
+		case <-ticker.C:
+			d.Trim()
+			d.Exchange()
+			d.persist()
+		}
+	}
+}
+
+func (d *Discovery) persist() {
+	if err := d.Persist(); err != nil {
+		logger := log.Network("discovery")
+		logger.Warn().Err(err).Msg("Failed to store peers.")
+	}
+}
+
+func (d *Discovery) connected() map[string]struct{} {
+	connected := make(map[string]struct{})
+
+	for _, id := range d.client.ClosestPeerIDs() {
+		connected[id.Address()] = struct{}{}
+	}
+
+	return connected
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+// +build unit
+
+package wavelet
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSeedResolver struct {
+	srv map[string][]*net.SRV
+	txt map[string][]string
+}
+
+func (r testSeedResolver) LookupSRV(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	records, exists := r.srv["_"+service+"._"+proto+"."+name]
+	if !exists {
+		return "", nil, errors.New("no such host")
+	}
+
+	return "", records, nil
+}
+
+func (r testSeedResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, exists := r.txt[name]
+	if !exists {
+		return nil, errors.New("no such host")
+	}
+
+	return records, nil
+}
+
+func TestResolveSeeds(t *testing.T) {
+	resolver := testSeedResolver{
+		srv: map[string][]*net.SRV{
+			"_wavelet._tcp.seed.example.com": {
+				{Target: "node1.example.com.", Port: 3000},
+				{Target: "node2.example.com.", Port: 3001},
+			},
+		},
+		txt: map[string][]string{
+			"seed.example.com":  {"node2.example.com:3001, 10.0.0.1:3000", "v=spf1 -all"},
+			"other.example.com": {"10.0.0.2:3000 10.0.0.3:3000"},
+		},
+	}
+
+	addrs, err := ResolveSeeds(context.Background(), resolver, []string{"seed.example.com", "other.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"node1.example.com:3000",
+		"node2.example.com:3001",
+		"10.0.0.1:3000",
+		"10.0.0.2:3000",
+		"10.0.0.3:3000",
+	}, addrs)
+
+	// Seeds which do not resolve are skipped, unless none resolve.
+	addrs, err = ResolveSeeds(context.Background(), resolver, []string{"missing.example.com", "other.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2:3000", "10.0.0.3:3000"}, addrs)
+
+	_, err = ResolveSeeds(context.Background(), resolver, []string{"missing.example.com"})
+	assert.Error(t, err)
+}
+
+func TestStorePeers(t *testing.T) {
+	kv := store.NewInmem()
+
+	addrs, err := LoadPeers(kv)
+	assert.NoError(t, err)
+	assert.Empty(t, addrs)
+
+	assert.NoError(t, StorePeers(kv, []string{"127.0.0.1:3000", "[::1]:3001", "node.example.com:3002"}))
+
+	addrs, err = LoadPeers(kv)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1:3000", "[::1]:3001", "node.example.com:3002"}, addrs)
+}
+
+func TestExchangePeers(t *testing.T) {
+	testnet, err := NewTestNetwork()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer testnet.Cleanup()
+
+	alice, err := testnet.AddNode()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	bob, err := testnet.AddNode()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	conn, err := alice.Client().Dial(testnet.Faucet().Addr())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// The faucet shares its peers, but not the requester itself.
+	res, err := NewWaveletClient(conn).ExchangePeers(ctx, &PeerExchangeRequest{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Contains(t, res.Addresses, bob.Addr())
+	assert.NotContains(t, res.Addresses, alice.Addr())
+
+	res, err = NewWaveletClient(conn).ExchangePeers(ctx, &PeerExchangeRequest{Limit: 1})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, res.Addresses, 1)
+
+	// Banned peers are not shared.
+	for _, id := range testnet.Faucet().Client().ClosestPeerIDs() {
+		if id.Address() == bob.Addr() {
+			for !testnet.Faucet().Ledger().Reputation().Report(id, ViolationInvalidChunk) {
+			}
+		}
+	}
+
+	res, err = NewWaveletClient(conn).ExchangePeers(ctx, &PeerExchangeRequest{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NotContains(t, res.Addresses, bob.Addr())
+
+	// Healthy peers are persisted, such that they are dialed upon restarting.
+	discovery := alice.Ledger().Discovery()
+	assert.NoError(t, discovery.Persist())
+
+	addrs, err := LoadPeers(alice.KV())
+	assert.NoError(t, err)
+	assert.Contains(t, addrs, testnet.Faucet().Addr())
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package wavelet
+
+import (
+	"github.com/perlin-network/wavelet/avl"
+)
+
+// FeeEstimate is what applying a transaction would cost its sender.
+type FeeEstimate struct {
+	// Fee is the transaction fee paid by the sender, being none should the
+	// transaction be stamped, or its fee be paid by a sponsor.
+	Fee uint64
+
+	// Gas is the gas spent invoking smart contracts.
+	Gas uint64
+}
+
+// EstimateFee dry-runs tx against snapshot as if it were applied in block,
+// and returns what it would cost its sender. The snapshot is left untouched.
+// The signature of tx is not verified, such that transactions may be
+// estimated ahead of being signed.
+func EstimateFee(snapshot *avl.Tree, block *Block, tx Transaction) (FeeEstimate, error) {
+	if err := validateTransaction(snapshot, tx, false); err != nil {
+		return FeeEstimate{}, err
+	}
+
+	ctx := NewCollapseContext(snapshot)
+
+	// Fees are paid ahead of applying transactions, leaving less to pay for
+	// gas with.
+	fee := tx.Fee()
+
+	payer, _ := feePayer(ctx.ReadAccountFeeAllowance, ctx.ReadAccountBalance, tx.Sender, fee)
+	if balance, _ := ctx.ReadAccountBalance(payer); balance >= fee {
+		ctx.WriteAccountBalance(payer, balance-fee)
+	}
+
+	state := &contractExecutorState{GasPayer: tx.Sender}
+
+	if err := applyTransaction(block, ctx, &tx, state); err != nil {
+		return FeeEstimate{}, err
+	}
+
+	return FeeEstimate{Fee: sponsoredFee(snapshot, tx), Gas: state.GasUsed}, nil
+}
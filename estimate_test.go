@@ -0,0 +1,102 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+// +build unit
+
+package wavelet
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateFee(t *testing.T) {
+	state := avl.New(store.NewInmem())
+	block := NewBlock(0, state.Checksum())
+
+	account, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	code, err := ioutil.ReadFile("testdata/transfer_back.wasm")
+	assert.NoError(t, err)
+
+	payload, err := buildContractSpawnPayload(100000, 0, code).Marshal()
+	assert.NoError(t, err)
+
+	WriteAccountBalance(state, account.PublicKey(), 100000)
+
+	spawn := buildSignedTransaction(account, sys.TagContract, 1, block.Index, payload)
+	assert.NoError(t, ApplyTransaction(state, &block, &spawn))
+
+	contractID := spawn.ID
+
+	// Transfers to accounts spend no gas.
+	var recipient AccountID
+	recipient[0] = 1
+
+	WriteAccountBalance(state, account.PublicKey(), 1000000000)
+
+	payload, err = buildTransferPayload(recipient, 1000).Marshal()
+	assert.NoError(t, err)
+
+	tx := buildSignedTransaction(account, sys.TagTransfer, 2, block.Index, payload)
+
+	estimate, err := EstimateFee(state, &block, tx)
+	assert.NoError(t, err)
+	assert.Equal(t, FeeEstimate{Fee: tx.Fee()}, estimate)
+
+	// Invocations spend as much gas as applying them would, without the
+	// state being modified.
+	WriteAccountContractGasBalance(state, contractID, 1000000)
+
+	payload, err = buildTransferWithInvocationPayload(
+		contractID, 200000000, 500000, []byte("on_money_received"), nil, 0,
+	).Marshal()
+	assert.NoError(t, err)
+
+	tx = buildSignedTransaction(account, sys.TagTransfer, 3, block.Index, payload)
+
+	checksum := state.Checksum()
+
+	estimate, err = EstimateFee(state, &block, tx)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Fee(), estimate.Fee)
+	assert.True(t, estimate.Gas > 0 && estimate.Gas <= 500000)
+	assert.Equal(t, checksum, state.Checksum())
+
+	assert.NoError(t, ApplyTransaction(state, &block, &tx))
+
+	gasBalance, _ := ReadAccountContractGasBalance(state, contractID)
+	assert.Equal(t, 1000000-estimate.Gas, gasBalance)
+
+	// Transactions which may not be afforded are not estimated.
+	payload, err = buildTransferPayload(recipient, 2000000000).Marshal()
+	assert.NoError(t, err)
+
+	tx = buildSignedTransaction(account, sys.TagTransfer, 4, block.Index, payload)
+
+	_, err = EstimateFee(state, &block, tx)
+	assert.Equal(t, ErrInsufficientBalance, errors.Cause(err))
+}
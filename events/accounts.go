@@ -0,0 +1,204 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+// Mod: accounts
+type (
+	BalanceUpdate struct {
+		AccountID [32]byte  `json:"account_id"`
+		Balance   uint64    `json:"balance"`
+		Time      time.Time `json:"time"`
+	}
+
+	GasBalanceUpdate struct {
+		AccountID  [32]byte  `json:"account_id"`
+		GasBalance uint64    `json:"gas_balance"`
+		Time       time.Time `json:"time"`
+	}
+
+	NumPagesUpdated struct {
+		AccountID [32]byte  `json:"account_id"`
+		NumPages  uint64    `json:"num_pages_updated"`
+		Time      time.Time `json:"time"`
+	}
+
+	StakeUpdated struct {
+		AccountID [32]byte  `json:"account_id"`
+		Stake     uint64    `json:"stake"`
+		Time      time.Time `json:"time"`
+	}
+
+	RewardUpdated struct {
+		AccountID [32]byte  `json:"account_id"`
+		Reward    uint64    `json:"reward"`
+		Time      time.Time `json:"time"`
+	}
+)
+
+func (e *BalanceUpdate) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.AccountID[:], "account_id"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.Balance = v.GetUint64("balance")
+
+	return nil
+}
+
+func (e *BalanceUpdate) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e BalanceUpdate) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "account_id", e.AccountID[:])
+	setUint64(&arena, o, "balance", e.Balance)
+	setTime(&arena, o, "time", e.Time)
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *GasBalanceUpdate) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.AccountID[:], "account_id"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.GasBalance = v.GetUint64("gas_balance")
+
+	return nil
+}
+
+func (e *GasBalanceUpdate) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e GasBalanceUpdate) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "account_id", e.AccountID[:])
+	setUint64(&arena, o, "gas_balance", e.GasBalance)
+	setTime(&arena, o, "time", e.Time)
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *NumPagesUpdated) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.AccountID[:], "account_id"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.NumPages = v.GetUint64("num_pages_updated")
+
+	return nil
+}
+
+func (e *NumPagesUpdated) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e NumPagesUpdated) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "account_id", e.AccountID[:])
+	setUint64(&arena, o, "num_pages_updated", e.NumPages)
+	setTime(&arena, o, "time", e.Time)
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *StakeUpdated) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.AccountID[:], "account_id"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.Stake = v.GetUint64("stake")
+
+	return nil
+}
+
+func (e *StakeUpdated) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e StakeUpdated) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "account_id", e.AccountID[:])
+	setUint64(&arena, o, "stake", e.Stake)
+	setTime(&arena, o, "time", e.Time)
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *RewardUpdated) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.AccountID[:], "account_id"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.Reward = v.GetUint64("reward")
+
+	return nil
+}
+
+func (e *RewardUpdated) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e RewardUpdated) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "account_id", e.AccountID[:])
+	setUint64(&arena, o, "reward", e.Reward)
+	setTime(&arena, o, "time", e.Time)
+
+	return o.MarshalTo(nil), nil
+}
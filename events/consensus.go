@@ -0,0 +1,234 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+// Mod: consensus
+type (
+	Proposal struct {
+		BlockID    [32]byte `json:"block_id"`
+		BlockIndex uint64   `json:"block_index"`
+		NumTxs     uint64   `json:"num_transactions"`
+		Message    string   `json:"message"`
+	}
+
+	Finalized struct {
+		BlockID     [32]byte `json:"new_block_id"`
+		BlockHeight uint64   `json:"new_block_height"`
+		NumApplied  int      `json:"num_applied_tx"`
+		NumRejected int      `json:"num_rejected_tx"`
+		NumPruned   int      `json:"num_pruned_tx"`
+		Message     string   `json:"message"`
+	}
+
+	// ValidatorUpdate is logged once an account joins or leaves the set of
+	// validators, its stake having crossed the minimum stake at the block at
+	// height BlockHeight.
+	ValidatorUpdate struct {
+		AccountID   [32]byte  `json:"public_key"`
+		Stake       uint64    `json:"stake"`
+		BlockHeight uint64    `json:"block_height"`
+		Time        time.Time `json:"time"`
+		Message     string    `json:"message"`
+	}
+
+	ValidatorJoin  struct{ ValidatorUpdate }
+	ValidatorLeave struct{ ValidatorUpdate }
+
+	// Round is logged once a block is finalized, summarizing it for
+	// explorers. StartID and EndID are the IDs of the first and last
+	// transactions of the block.
+	Round struct {
+		Index       uint64        `json:"index"`
+		ID          [32]byte      `json:"id"`
+		MerkleRoot  [16]byte      `json:"merkle_root"`
+		StartID     [32]byte      `json:"start_id"`
+		EndID       [32]byte      `json:"end_id"`
+		NumTx       uint64        `json:"num_tx"`
+		NumApplied  uint64        `json:"num_applied_tx"`
+		NumRejected uint64        `json:"num_rejected_tx"`
+		StartedAt   time.Time     `json:"started_at"`
+		FinalizedAt time.Time     `json:"finalized_at"`
+		Duration    time.Duration `json:"duration"`
+		Message     string        `json:"message"`
+	}
+)
+
+func (e *Proposal) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.BlockID[:], "block_id"); err != nil {
+		return err
+	}
+
+	e.BlockIndex = v.GetUint64("block_index")
+	e.NumTxs = v.GetUint64("num_transactions")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *Proposal) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e Proposal) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "block_id", e.BlockID[:])
+	setUint64(&arena, o, "block_index", e.BlockIndex)
+	setUint64(&arena, o, "num_transactions", e.NumTxs)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *Finalized) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.BlockID[:], "new_block_id"); err != nil {
+		return err
+	}
+
+	e.BlockHeight = v.GetUint64("new_block_height")
+	e.NumApplied = v.GetInt("num_applied_tx")
+	e.NumRejected = v.GetInt("num_rejected_tx")
+	e.NumPruned = v.GetInt("num_pruned_tx")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *Finalized) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e Finalized) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "new_block_id", e.BlockID[:])
+	setUint64(&arena, o, "new_block_height", e.BlockHeight)
+	o.Set("num_applied_tx", arena.NewNumberInt(e.NumApplied))
+	o.Set("num_rejected_tx", arena.NewNumberInt(e.NumRejected))
+	o.Set("num_pruned_tx", arena.NewNumberInt(e.NumPruned))
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *ValidatorUpdate) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.AccountID[:], "public_key"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.Stake = v.GetUint64("stake")
+	e.BlockHeight = v.GetUint64("block_height")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *ValidatorUpdate) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e ValidatorUpdate) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "public_key", e.AccountID[:])
+	setUint64(&arena, o, "stake", e.Stake)
+	setUint64(&arena, o, "block_height", e.BlockHeight)
+	setTime(&arena, o, "time", e.Time)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *Round) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.ID[:], "id"); err != nil {
+		return err
+	}
+
+	if err := parseHex(v, e.MerkleRoot[:], "merkle_root"); err != nil {
+		return err
+	}
+
+	if err := parseHex(v, e.StartID[:], "start_id"); err != nil {
+		return err
+	}
+
+	if err := parseHex(v, e.EndID[:], "end_id"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.StartedAt, "started_at"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.FinalizedAt, "finalized_at"); err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(string(v.GetStringBytes("duration")))
+	if err != nil {
+		return NewErrUnmarshalFail(v, "duration", err)
+	}
+
+	e.Duration = duration
+	e.Index = v.GetUint64("index")
+	e.NumTx = v.GetUint64("num_tx")
+	e.NumApplied = v.GetUint64("num_applied_tx")
+	e.NumRejected = v.GetUint64("num_rejected_tx")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *Round) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e Round) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setUint64(&arena, o, "index", e.Index)
+	setHex(&arena, o, "id", e.ID[:])
+	setHex(&arena, o, "merkle_root", e.MerkleRoot[:])
+	setHex(&arena, o, "start_id", e.StartID[:])
+	setHex(&arena, o, "end_id", e.EndID[:])
+	setUint64(&arena, o, "num_tx", e.NumTx)
+	setUint64(&arena, o, "num_applied_tx", e.NumApplied)
+	setUint64(&arena, o, "num_rejected_tx", e.NumRejected)
+	o.Set("started_at", arena.NewString(e.StartedAt.Format(time.RFC3339Nano)))
+	o.Set("finalized_at", arena.NewString(e.FinalizedAt.Format(time.RFC3339Nano)))
+	o.Set("duration", arena.NewString(e.Duration.String()))
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
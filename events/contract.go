@@ -0,0 +1,152 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+// Mod: contract
+type (
+	ContractGas struct {
+		SenderID   [32]byte  `json:"sender_id"`
+		ContractID [32]byte  `json:"contract_id"`
+		Gas        uint64    `json:"gas"`
+		GasLimit   uint64    `json:"gas_limit"`
+		Time       time.Time `json:"time"`
+		Message    string    `json:"message"`
+	}
+
+	// ContractLog is a log emitted by a smart contract through _emit_log,
+	// once the block the transaction emitting it was applied in is
+	// finalized. Only Message is set for logs of older nodes.
+	ContractLog struct {
+		ContractID [32]byte  `json:"contract_id"`
+		Block      uint64    `json:"block"`
+		TxID       [32]byte  `json:"tx_id"`
+		Index      uint64    `json:"index"`
+		Topic      []byte    `json:"topic"`
+		Data       []byte    `json:"data"`
+		Time       time.Time `json:"time"`
+		Message    string    `json:"message"`
+	}
+)
+
+func (e *ContractGas) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.SenderID[:], "sender_id"); err != nil {
+		return err
+	}
+
+	if err := parseHex(v, e.ContractID[:], "contract_id"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.Gas = v.GetUint64("gas")
+	e.GasLimit = v.GetUint64("gas_limit")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *ContractGas) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e ContractGas) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "sender_id", e.SenderID[:])
+	setHex(&arena, o, "contract_id", e.ContractID[:])
+	setUint64(&arena, o, "gas", e.Gas)
+	setUint64(&arena, o, "gas_limit", e.GasLimit)
+	setTime(&arena, o, "time", e.Time)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *ContractLog) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.ContractID[:], "contract_id"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	if v.Exists("tx_id") {
+		if err := parseHex(v, e.TxID[:], "tx_id"); err != nil {
+			return err
+		}
+	}
+
+	for _, field := range []struct {
+		key string
+		dst *[]byte
+	}{{"topic", &e.Topic}, {"data", &e.Data}} {
+		key, dst := field.key, field.dst
+
+		src := v.GetStringBytes(key)
+		if len(src) == 0 {
+			continue
+		}
+
+		buf, err := hex.DecodeString(string(src))
+		if err != nil {
+			return NewErrUnmarshalFail(v, key, err)
+		}
+
+		*dst = buf
+	}
+
+	e.Block = v.GetUint64("block")
+	e.Index = v.GetUint64("index")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *ContractLog) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e ContractLog) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "contract_id", e.ContractID[:])
+	setUint64(&arena, o, "block", e.Block)
+	setHex(&arena, o, "tx_id", e.TxID[:])
+	setUint64(&arena, o, "index", e.Index)
+	setHex(&arena, o, "topic", e.Topic)
+	setHex(&arena, o, "data", e.Data)
+	setTime(&arena, o, "time", e.Time)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package events models the events emitted by a node through its websocket
+// API, such that they may be shared between the node and its clients.
+//
+// Every event may be decoded either through its fastjson fast path
+// UnmarshalValue, or through encoding/json.
+package events
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/perlin-network/wavelet/log"
+	"github.com/valyala/fastjson"
+)
+
+const (
+	KeyMod   = log.KeyModule
+	KeyEvent = log.KeyEvent
+
+	// Mod: accounts
+	EventBalanceUpdated    = "balance_updated"
+	EventGasBalanceUpdated = "gas_balance_updated"
+	EventNumPagesUpdated   = "num_pages_updated"
+	EventStakeUpdated      = "stake_updated"
+	EventRewardUpdated     = "reward_updated"
+
+	// Mod: network
+	EventPeerJoined = "joined"
+	EventPeerLeft   = "left"
+	EventPeerBanned = "banned"
+
+	// Mod: consensus
+	EventProposal        = "proposal"
+	EventFinalized       = "finalized"
+	EventValidatorJoined = "validator_joined"
+	EventValidatorLeft   = "validator_left"
+	EventRound           = "round"
+
+	// Mod: contract
+	EventContractGas = "gas"
+	EventContractLog = "log"
+
+	// Mod: tx
+	EventTxApplied  = "applied"
+	EventTxRejected = "rejected"
+	EventTxGossip   = "gossip"
+	EventTxConflict = "conflict"
+
+	// EventTxFailed is the name older nodes used for EventTxRejected.
+	EventTxFailed = "failed"
+)
+
+// TimeLayout is the layout of all timestamps inside events.
+const TimeLayout = time.RFC3339
+
+// Event is implemented by all events emitted by a node.
+type Event interface {
+	UnmarshalValue(v *fastjson.Value) error
+	UnmarshalJSON(b []byte) error
+	MarshalJSON() ([]byte, error)
+}
+
+var (
+	_ Event = (*BalanceUpdate)(nil)
+	_ Event = (*GasBalanceUpdate)(nil)
+	_ Event = (*NumPagesUpdated)(nil)
+	_ Event = (*StakeUpdated)(nil)
+	_ Event = (*RewardUpdated)(nil)
+	_ Event = (*PeerUpdate)(nil)
+	_ Event = (*PeerBan)(nil)
+	_ Event = (*Proposal)(nil)
+	_ Event = (*Finalized)(nil)
+	_ Event = (*ValidatorUpdate)(nil)
+	_ Event = (*Round)(nil)
+	_ Event = (*ContractGas)(nil)
+	_ Event = (*ContractLog)(nil)
+	_ Event = (*TxApplied)(nil)
+	_ Event = (*TxGossipError)(nil)
+	_ Event = (*TxFailed)(nil)
+	_ Event = (*TxConflict)(nil)
+	_ Event = (*Metrics)(nil)
+)
+
+var ErrInvalidHexLength = errors.New("invalid hex bytes length")
+
+type ErrInvalidPayload struct {
+	JSONValue string
+}
+
+type ErrMismatchMod struct {
+	ErrInvalidPayload
+	WantedMod string
+	GotMod    string
+}
+
+// CheckMod returns an error should the "mod" field of v not be mod.
+func CheckMod(v *fastjson.Value, mod string) error {
+	if string(v.GetStringBytes(KeyMod)) != mod {
+		return &ErrMismatchMod{
+			ErrInvalidPayload: ErrInvalidPayload{
+				JSONValue: v.String(),
+			},
+			WantedMod: mod,
+			GotMod:    string(v.GetStringBytes(KeyMod)),
+		}
+	}
+
+	return nil
+}
+
+func (err *ErrMismatchMod) Error() string {
+	return "Mismatched \"mod\" field in JSON: expected " + err.WantedMod +
+		", got " + err.GotMod
+}
+
+type ErrInvalidEvent struct {
+	ErrInvalidPayload
+	Event string
+}
+
+func NewErrInvalidEvent(v *fastjson.Value, event string) *ErrInvalidEvent { // nolint:interfacer
+	return &ErrInvalidEvent{
+		ErrInvalidPayload: ErrInvalidPayload{
+			JSONValue: v.String(),
+		},
+		Event: event,
+	}
+}
+
+func (err *ErrInvalidEvent) Error() string {
+	return "Unsupported event: " + err.Event
+}
+
+type ErrUnmarshalFail struct {
+	ErrInvalidPayload
+	Key        string
+	Underlying error
+}
+
+func NewErrUnmarshalFail(v *fastjson.Value, key string, err error) *ErrUnmarshalFail { // nolint:interfacer
+	return &ErrUnmarshalFail{
+		ErrInvalidPayload: ErrInvalidPayload{
+			JSONValue: v.String(),
+		},
+		Key:        key,
+		Underlying: err,
+	}
+}
+
+func (err *ErrUnmarshalFail) Error() string {
+	return "Error unmarshalling key " + err.Key + ": " + err.Underlying.Error()
+}
+
+func unmarshalJSON(b []byte, ev Event) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	return ev.UnmarshalValue(v)
+}
+
+func parseHex(v *fastjson.Value, dst []byte, key string) error {
+	src := v.GetStringBytes(key)
+
+	// Check the length upfront, as hex.Decode panics should dst be too short.
+	if hex.DecodedLen(len(src)) != len(dst) {
+		return NewErrUnmarshalFail(v, key, ErrInvalidHexLength)
+	}
+
+	i, err := hex.Decode(dst, src)
+	if err != nil {
+		return NewErrUnmarshalFail(v, key, err)
+	}
+
+	if i != len(dst) {
+		return NewErrUnmarshalFail(v, key, ErrInvalidHexLength)
+	}
+
+	return nil
+}
+
+func parseTime(v *fastjson.Value, t *time.Time, key string) error {
+	parsed, err := time.Parse(TimeLayout, string(v.GetStringBytes(key)))
+	if err != nil {
+		return NewErrUnmarshalFail(v, key, err)
+	}
+
+	*t = parsed
+
+	return nil
+}
+
+func setHex(arena *fastjson.Arena, o *fastjson.Value, key string, src []byte) {
+	o.Set(key, arena.NewString(hex.EncodeToString(src)))
+}
+
+func setTime(arena *fastjson.Arena, o *fastjson.Value, key string, t time.Time) {
+	o.Set(key, arena.NewString(t.Format(TimeLayout)))
+}
+
+func setUint64(arena *fastjson.Arena, o *fastjson.Value, key string, u uint64) {
+	o.Set(key, arena.NewNumberString(strconv.FormatUint(u, 10)))
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package events
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fastjson"
+)
+
+func TestEventsRoundTrip(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	id := [32]byte{1, 2, 3}
+	id2 := [32]byte{4, 5, 6}
+
+	peer := PeerUpdate{AccountID: id, Address: "127.0.0.1:3000", Time: now, Message: "Peer has joined."}
+	validator := ValidatorUpdate{AccountID: id, Stake: 100, BlockHeight: 12, Time: now, Message: "Validator has joined."}
+
+	evs := []Event{
+		&BalanceUpdate{AccountID: id, Balance: 1, Time: now},
+		&GasBalanceUpdate{AccountID: id, GasBalance: 2, Time: now},
+		&NumPagesUpdated{AccountID: id, NumPages: 3, Time: now},
+		&StakeUpdated{AccountID: id, Stake: 4, Time: now},
+		&RewardUpdated{AccountID: id, Reward: 5, Time: now},
+		&peer,
+		&PeerJoin{PeerUpdate: peer},
+		&PeerLeave{PeerUpdate: peer},
+		&PeerBan{
+			AccountID: id, Address: "127.0.0.1:3000", Violation: "invalid_block", Score: -105.5,
+			Until: now.Add(10 * time.Minute), Time: now, Message: "Peer has been banned.",
+		},
+		&Proposal{BlockID: id, BlockIndex: 6, NumTxs: 7, Message: "Proposing block..."},
+		&Finalized{BlockID: id, BlockHeight: 8, NumApplied: 9, NumRejected: 10, NumPruned: 11, Message: "Finalized block."},
+		&validator,
+		&ValidatorJoin{ValidatorUpdate: validator},
+		&ValidatorLeave{ValidatorUpdate: validator},
+		&Round{
+			Index: 8, ID: id, MerkleRoot: [16]byte{7}, StartID: id, EndID: id2, NumTx: 3, NumApplied: 2, NumRejected: 1,
+			StartedAt: now.Add(-1500 * time.Millisecond), FinalizedAt: now, Duration: 1500 * time.Millisecond,
+			Message: "Finalized round.",
+		},
+		&ContractGas{SenderID: id, ContractID: id2, Gas: 12, GasLimit: 13, Time: now, Message: "Deducted PERLs for gas."},
+		&ContractLog{
+			ContractID: id2, Block: 14, TxID: id, Index: 15, Topic: []byte("transfer"), Data: []byte{1, 2}, Time: now,
+			Message: "hello",
+		},
+		&TxApplied{TxID: id, SenderID: id2, Tag: 1, Time: now},
+		&TxGossipError{Error: "failed", Time: now, Message: "Failed to send batch"},
+		&TxFailed{TxID: id, SenderID: id2, Tag: 2, Error: "insufficient balance", Time: now},
+		&TxConflict{TxID: id, ConflictingTxID: id2, SenderID: id, Nonce: 3, Time: now, Message: "conflict"},
+		&Metrics{
+			BlocksQueried: 1, BlocksFinalized: 0.5, TxGossiped: 2, TxReceived: 3, TxAccepted: 4, TxDownloaded: 5,
+			TxConflicts: 6,
+			BpsQueried: 1.5, TpsGossiped: 2.5, TpsReceived: 3.5, TpsAccepted: 4.5, TpsDownloaded: 5.5,
+			QueryLatencyMaxMS: 10, QueryLatencyMinMS: 1, QueryLatencyMeanMS: 5.5, Time: now, Message: "Updated metrics.",
+		},
+	}
+
+	for _, ev := range evs {
+		typ := reflect.TypeOf(ev).Elem()
+
+		t.Run(typ.Name(), func(t *testing.T) {
+			buf, err := json.Marshal(ev)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			viaJSON := reflect.New(typ).Interface().(Event)
+			if assert.NoError(t, json.Unmarshal(buf, viaJSON)) {
+				assert.Equal(t, ev, viaJSON)
+			}
+
+			v, err := fastjson.ParseBytes(buf)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			viaValue := reflect.New(typ).Interface().(Event)
+			if assert.NoError(t, viaValue.UnmarshalValue(v)) {
+				assert.Equal(t, ev, viaValue)
+			}
+		})
+	}
+}
+
+func TestEventsErrors(t *testing.T) {
+	v := fastjson.MustParse(`{"mod":"accounts","event":"balance_updated","account_id":"0102","time":"2019-01-01T00:00:00Z"}`)
+
+	var ev BalanceUpdate
+
+	err := ev.UnmarshalValue(v)
+	if assert.IsType(t, (*ErrUnmarshalFail)(nil), err) {
+		assert.Equal(t, ErrInvalidHexLength, err.(*ErrUnmarshalFail).Underlying)
+	}
+
+	assert.NoError(t, CheckMod(v, "accounts"))
+	assert.IsType(t, (*ErrMismatchMod)(nil), CheckMod(v, "tx"))
+}
+
+func TestEventsProtoRoundTrip(t *testing.T) {
+	now := time.Now().UTC()
+	id := [32]byte{1, 2, 3}
+	id2 := [32]byte{4, 5, 6}
+
+	peer := PeerUpdate{AccountID: id, Address: "127.0.0.1:3000", Time: now}
+
+	evs := []Event{
+		&BalanceUpdate{AccountID: id, Balance: 1, Time: now},
+		&GasBalanceUpdate{AccountID: id, GasBalance: 2, Time: now},
+		&NumPagesUpdated{AccountID: id, NumPages: 3, Time: now},
+		&StakeUpdated{AccountID: id, Stake: 4, Time: now},
+		&RewardUpdated{AccountID: id, Reward: 5},
+		&PeerJoin{PeerUpdate: peer},
+		&PeerLeave{PeerUpdate: peer},
+		&Proposal{BlockID: id, BlockIndex: 6, NumTxs: 7},
+		&Finalized{BlockID: id, BlockHeight: 8, NumApplied: 9, NumRejected: 10, NumPruned: 11},
+		&ContractGas{SenderID: id, ContractID: id2, Gas: 12, GasLimit: 13, Time: now},
+		&ContractLog{ContractID: id2, Time: now, Message: "hello"},
+		&TxApplied{TxID: id, SenderID: id2, Tag: 1, Time: now},
+		&TxGossipError{Error: "failed", Time: now},
+		&TxFailed{TxID: id, SenderID: id2, Tag: 2, Error: "insufficient balance", Time: now},
+		&TxConflict{TxID: id, ConflictingTxID: id2, SenderID: id, Nonce: 3, Time: now},
+	}
+
+	for _, ev := range evs {
+		t.Run(reflect.TypeOf(ev).Elem().Name(), func(t *testing.T) {
+			pb, err := ToProto(ev)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			buf, err := pb.Marshal()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			decoded := new(ledgerpb.Event)
+			if !assert.NoError(t, decoded.Unmarshal(buf)) {
+				return
+			}
+
+			got, err := FromProto(decoded)
+			if assert.NoError(t, err) {
+				assert.Equal(t, ev, got)
+			}
+		})
+	}
+
+	_, err := ToProto(&Metrics{})
+	assert.Error(t, err)
+
+	_, err = FromProto(&ledgerpb.Event{Event: &ledgerpb.Event_BalanceUpdated{
+		BalanceUpdated: &ledgerpb.BalanceUpdated{AccountId: []byte{1, 2}},
+	}})
+	assert.Equal(t, ErrInvalidLength, err)
+}
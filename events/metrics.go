@@ -0,0 +1,102 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+// Mod: metrics
+type Metrics struct {
+	BlocksQueried      uint64    `json:"blocks.queried"`
+	BlocksFinalized    float64   `json:"blocks.finalized"` // mean
+	TxGossiped         uint64    `json:"tx.gossiped"`
+	TxReceived         uint64    `json:"tx.received"`
+	TxAccepted         uint64    `json:"tx.accepted"`
+	TxDownloaded       uint64    `json:"tx.downloaded"`
+	TxConflicts        uint64    `json:"tx.conflicts"`
+	BpsQueried         float64   `json:"bps.queried"`
+	TpsGossiped        float64   `json:"tps.gossiped"`
+	TpsReceived        float64   `json:"tps.received"`
+	TpsAccepted        float64   `json:"tps.accepted"`
+	TpsDownloaded      float64   `json:"tps.downloaded"`
+	QueryLatencyMaxMS  int64     `json:"query.latency.max.ms"`
+	QueryLatencyMinMS  int64     `json:"query.latency.min.ms"`
+	QueryLatencyMeanMS float64   `json:"query.latency.mean.ms"`
+	Time               time.Time `json:"time"`
+	Message            string    `json:"message"`
+}
+
+func (e *Metrics) UnmarshalValue(v *fastjson.Value) error {
+	e.BlocksQueried = v.GetUint64("blocks.queried")
+	e.BlocksFinalized = v.GetFloat64("blocks.finalized")
+	e.TxGossiped = v.GetUint64("tx.gossiped")
+	e.TxReceived = v.GetUint64("tx.received")
+	e.TxAccepted = v.GetUint64("tx.accepted")
+	e.TxDownloaded = v.GetUint64("tx.downloaded")
+	e.TxConflicts = v.GetUint64("tx.conflicts")
+	e.BpsQueried = v.GetFloat64("bps.queried")
+	e.TpsGossiped = v.GetFloat64("tps.gossiped")
+	e.TpsReceived = v.GetFloat64("tps.received")
+	e.TpsAccepted = v.GetFloat64("tps.accepted")
+	e.TpsDownloaded = v.GetFloat64("tps.downloaded")
+	e.QueryLatencyMaxMS = v.GetInt64("query.latency.max.ms")
+	e.QueryLatencyMinMS = v.GetInt64("query.latency.min.ms")
+	e.QueryLatencyMeanMS = v.GetFloat64("query.latency.mean.ms")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return parseTime(v, &e.Time, "time")
+}
+
+func (e *Metrics) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e Metrics) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	float := func(f float64) *fastjson.Value {
+		return arena.NewNumberString(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+
+	setUint64(&arena, o, "blocks.queried", e.BlocksQueried)
+	o.Set("blocks.finalized", float(e.BlocksFinalized))
+	setUint64(&arena, o, "tx.gossiped", e.TxGossiped)
+	setUint64(&arena, o, "tx.received", e.TxReceived)
+	setUint64(&arena, o, "tx.accepted", e.TxAccepted)
+	setUint64(&arena, o, "tx.downloaded", e.TxDownloaded)
+	setUint64(&arena, o, "tx.conflicts", e.TxConflicts)
+	o.Set("bps.queried", float(e.BpsQueried))
+	o.Set("tps.gossiped", float(e.TpsGossiped))
+	o.Set("tps.received", float(e.TpsReceived))
+	o.Set("tps.accepted", float(e.TpsAccepted))
+	o.Set("tps.downloaded", float(e.TpsDownloaded))
+	o.Set("query.latency.max.ms", arena.NewNumberString(strconv.FormatInt(e.QueryLatencyMaxMS, 10)))
+	o.Set("query.latency.min.ms", arena.NewNumberString(strconv.FormatInt(e.QueryLatencyMinMS, 10)))
+	o.Set("query.latency.mean.ms", float(e.QueryLatencyMeanMS))
+	setTime(&arena, o, "time", e.Time)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
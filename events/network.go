@@ -0,0 +1,122 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+// Mod: network
+type (
+	PeerUpdate struct {
+		AccountID [32]byte  `json:"public_key"`
+		Address   string    `json:"address"` // IP:port
+		Time      time.Time `json:"time"`
+		Message   string    `json:"message"`
+	}
+
+	PeerJoin  struct{ PeerUpdate }
+	PeerLeave struct{ PeerUpdate }
+
+	// PeerBan is emitted when a peer is banned, having its score drop too
+	// low by committing the given violation.
+	PeerBan struct {
+		AccountID [32]byte  `json:"public_key"`
+		Address   string    `json:"address"` // IP:port
+		Violation string    `json:"violation"`
+		Score     float64   `json:"score"`
+		Until     time.Time `json:"until"`
+		Time      time.Time `json:"time"`
+		Message   string    `json:"message"`
+	}
+)
+
+func (e *PeerUpdate) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.AccountID[:], "public_key"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.Address = string(v.GetStringBytes("address"))
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *PeerUpdate) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e PeerUpdate) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "public_key", e.AccountID[:])
+	o.Set("address", arena.NewString(e.Address))
+	setTime(&arena, o, "time", e.Time)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *PeerBan) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.AccountID[:], "public_key"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Until, "until"); err != nil {
+		return err
+	}
+
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.Address = string(v.GetStringBytes("address"))
+	e.Violation = string(v.GetStringBytes("violation"))
+	e.Score = v.GetFloat64("score")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *PeerBan) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e PeerBan) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "public_key", e.AccountID[:])
+	o.Set("address", arena.NewString(e.Address))
+	o.Set("violation", arena.NewString(e.Violation))
+	o.Set("score", arena.NewNumberFloat64(e.Score))
+	setTime(&arena, o, "until", e.Until)
+	setTime(&arena, o, "time", e.Time)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
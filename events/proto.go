@@ -0,0 +1,222 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/perlin-network/wavelet/ledgerpb"
+)
+
+var ErrInvalidLength = errors.New("invalid bytes length")
+
+// ToProto converts ev to its protobuf definition. Human-readable messages
+// carried by events are not part of their protobuf definitions, and are
+// hence dropped.
+func ToProto(ev Event) (*ledgerpb.Event, error) { // nolint:gocyclo
+	switch e := ev.(type) {
+	case *BalanceUpdate:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_BalanceUpdated{BalanceUpdated: &ledgerpb.BalanceUpdated{
+			AccountId: e.AccountID[:], Balance: e.Balance, Time: toNano(e.Time),
+		}}}, nil
+	case *GasBalanceUpdate:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_GasBalanceUpdated{GasBalanceUpdated: &ledgerpb.GasBalanceUpdated{
+			AccountId: e.AccountID[:], GasBalance: e.GasBalance, Time: toNano(e.Time),
+		}}}, nil
+	case *NumPagesUpdated:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_NumPagesUpdated{NumPagesUpdated: &ledgerpb.NumPagesUpdated{
+			AccountId: e.AccountID[:], NumPages: e.NumPages, Time: toNano(e.Time),
+		}}}, nil
+	case *StakeUpdated:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_StakeUpdated{StakeUpdated: &ledgerpb.StakeUpdated{
+			AccountId: e.AccountID[:], Stake: e.Stake, Time: toNano(e.Time),
+		}}}, nil
+	case *RewardUpdated:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_RewardUpdated{RewardUpdated: &ledgerpb.RewardUpdated{
+			AccountId: e.AccountID[:], Reward: e.Reward, Time: toNano(e.Time),
+		}}}, nil
+	case *PeerJoin:
+		return peerToProto(&e.PeerUpdate, true), nil
+	case *PeerLeave:
+		return peerToProto(&e.PeerUpdate, false), nil
+	case *Proposal:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_BlockProposed{BlockProposed: &ledgerpb.BlockProposed{
+			BlockId: e.BlockID[:], BlockIndex: e.BlockIndex, NumTransactions: e.NumTxs,
+		}}}, nil
+	case *Finalized:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_BlockFinalized{BlockFinalized: &ledgerpb.BlockFinalized{
+			BlockId:     e.BlockID[:],
+			BlockIndex:  e.BlockHeight,
+			NumApplied:  uint64(e.NumApplied),
+			NumRejected: uint64(e.NumRejected),
+			NumPruned:   uint64(e.NumPruned),
+		}}}, nil
+	case *ContractGas:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_ContractGasUsed{ContractGasUsed: &ledgerpb.ContractGasUsed{
+			SenderId: e.SenderID[:], ContractId: e.ContractID[:], Gas: e.Gas, GasLimit: e.GasLimit, Time: toNano(e.Time),
+		}}}, nil
+	case *ContractLog:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_ContractLogged{ContractLogged: &ledgerpb.ContractLogged{
+			ContractId: e.ContractID[:], Message: e.Message, Time: toNano(e.Time),
+		}}}, nil
+	case *TxApplied:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_TransactionApplied{TransactionApplied: &ledgerpb.TransactionApplied{
+			Id: e.TxID[:], SenderId: e.SenderID[:], Tag: uint32(e.Tag), Time: toNano(e.Time),
+		}}}, nil
+	case *TxFailed:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_TransactionRejected{TransactionRejected: &ledgerpb.TransactionRejected{
+			Id: e.TxID[:], SenderId: e.SenderID[:], Tag: uint32(e.Tag), Error: e.Error, Time: toNano(e.Time),
+		}}}, nil
+	case *TxGossipError:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_TransactionGossipFailed{
+			TransactionGossipFailed: &ledgerpb.TransactionGossipFailed{Error: e.Error, Time: toNano(e.Time)},
+		}}, nil
+	case *TxConflict:
+		return &ledgerpb.Event{Event: &ledgerpb.Event_TransactionConflicted{TransactionConflicted: &ledgerpb.TransactionConflicted{
+			Id: e.TxID[:], ConflictingId: e.ConflictingTxID[:], SenderId: e.SenderID[:], Nonce: e.Nonce, Time: toNano(e.Time),
+		}}}, nil
+	default:
+		return nil, fmt.Errorf("event %T has no protobuf definition", ev)
+	}
+}
+
+// FromProto converts the protobuf definition of an event back into an event.
+func FromProto(pb *ledgerpb.Event) (Event, error) { // nolint:gocyclo
+	switch e := pb.Event.(type) {
+	case *ledgerpb.Event_BalanceUpdated:
+		ev := &BalanceUpdate{Balance: e.BalanceUpdated.Balance, Time: fromNano(e.BalanceUpdated.Time)}
+		return ev, copyID(ev.AccountID[:], e.BalanceUpdated.AccountId)
+	case *ledgerpb.Event_GasBalanceUpdated:
+		ev := &GasBalanceUpdate{GasBalance: e.GasBalanceUpdated.GasBalance, Time: fromNano(e.GasBalanceUpdated.Time)}
+		return ev, copyID(ev.AccountID[:], e.GasBalanceUpdated.AccountId)
+	case *ledgerpb.Event_NumPagesUpdated:
+		ev := &NumPagesUpdated{NumPages: e.NumPagesUpdated.NumPages, Time: fromNano(e.NumPagesUpdated.Time)}
+		return ev, copyID(ev.AccountID[:], e.NumPagesUpdated.AccountId)
+	case *ledgerpb.Event_StakeUpdated:
+		ev := &StakeUpdated{Stake: e.StakeUpdated.Stake, Time: fromNano(e.StakeUpdated.Time)}
+		return ev, copyID(ev.AccountID[:], e.StakeUpdated.AccountId)
+	case *ledgerpb.Event_RewardUpdated:
+		ev := &RewardUpdated{Reward: e.RewardUpdated.Reward, Time: fromNano(e.RewardUpdated.Time)}
+		return ev, copyID(ev.AccountID[:], e.RewardUpdated.AccountId)
+	case *ledgerpb.Event_PeerUpdated:
+		peer := PeerUpdate{Address: e.PeerUpdated.Address, Time: fromNano(e.PeerUpdated.Time)}
+		if err := copyID(peer.AccountID[:], e.PeerUpdated.PublicKey); err != nil {
+			return nil, err
+		}
+
+		if e.PeerUpdated.Joined {
+			return &PeerJoin{PeerUpdate: peer}, nil
+		}
+
+		return &PeerLeave{PeerUpdate: peer}, nil
+	case *ledgerpb.Event_BlockProposed:
+		ev := &Proposal{BlockIndex: e.BlockProposed.BlockIndex, NumTxs: e.BlockProposed.NumTransactions}
+		return ev, copyID(ev.BlockID[:], e.BlockProposed.BlockId)
+	case *ledgerpb.Event_BlockFinalized:
+		ev := &Finalized{
+			BlockHeight: e.BlockFinalized.BlockIndex,
+			NumApplied:  int(e.BlockFinalized.NumApplied),
+			NumRejected: int(e.BlockFinalized.NumRejected),
+			NumPruned:   int(e.BlockFinalized.NumPruned),
+		}
+		return ev, copyID(ev.BlockID[:], e.BlockFinalized.BlockId)
+	case *ledgerpb.Event_ContractGasUsed:
+		ev := &ContractGas{
+			Gas:      e.ContractGasUsed.Gas,
+			GasLimit: e.ContractGasUsed.GasLimit,
+			Time:     fromNano(e.ContractGasUsed.Time),
+		}
+		if err := copyID(ev.SenderID[:], e.ContractGasUsed.SenderId); err != nil {
+			return nil, err
+		}
+		return ev, copyID(ev.ContractID[:], e.ContractGasUsed.ContractId)
+	case *ledgerpb.Event_ContractLogged:
+		ev := &ContractLog{Message: e.ContractLogged.Message, Time: fromNano(e.ContractLogged.Time)}
+		return ev, copyID(ev.ContractID[:], e.ContractLogged.ContractId)
+	case *ledgerpb.Event_TransactionApplied:
+		ev := &TxApplied{Tag: byte(e.TransactionApplied.Tag), Time: fromNano(e.TransactionApplied.Time)}
+		if err := copyID(ev.TxID[:], e.TransactionApplied.Id); err != nil {
+			return nil, err
+		}
+		return ev, copyID(ev.SenderID[:], e.TransactionApplied.SenderId)
+	case *ledgerpb.Event_TransactionRejected:
+		ev := &TxFailed{
+			Tag:   byte(e.TransactionRejected.Tag),
+			Error: e.TransactionRejected.Error,
+			Time:  fromNano(e.TransactionRejected.Time),
+		}
+		if err := copyID(ev.TxID[:], e.TransactionRejected.Id); err != nil {
+			return nil, err
+		}
+		return ev, copyID(ev.SenderID[:], e.TransactionRejected.SenderId)
+	case *ledgerpb.Event_TransactionGossipFailed:
+		return &TxGossipError{
+			Error: e.TransactionGossipFailed.Error,
+			Time:  fromNano(e.TransactionGossipFailed.Time),
+		}, nil
+	case *ledgerpb.Event_TransactionConflicted:
+		ev := &TxConflict{Nonce: e.TransactionConflicted.Nonce, Time: fromNano(e.TransactionConflicted.Time)}
+		if err := copyID(ev.TxID[:], e.TransactionConflicted.Id); err != nil {
+			return nil, err
+		}
+		if err := copyID(ev.ConflictingTxID[:], e.TransactionConflicted.ConflictingId); err != nil {
+			return nil, err
+		}
+		return ev, copyID(ev.SenderID[:], e.TransactionConflicted.SenderId)
+	default:
+		return nil, fmt.Errorf("unsupported protobuf event %T", pb.Event)
+	}
+}
+
+func peerToProto(e *PeerUpdate, joined bool) *ledgerpb.Event {
+	return &ledgerpb.Event{Event: &ledgerpb.Event_PeerUpdated{PeerUpdated: &ledgerpb.PeerUpdated{
+		PublicKey: e.AccountID[:], Address: e.Address, Time: toNano(e.Time), Joined: joined,
+	}}}
+}
+
+func copyID(dst []byte, src []byte) error {
+	if len(src) != len(dst) {
+		return ErrInvalidLength
+	}
+
+	copy(dst, src)
+
+	return nil
+}
+
+// toNano returns t in nanoseconds since the Unix epoch, with the zero time
+// being 0.
+func toNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixNano()
+}
+
+func fromNano(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, ns).UTC()
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+// Mod: tx
+type (
+	TxApplied struct {
+		TxID     [32]byte  `json:"tx_id"`
+		SenderID [32]byte  `json:"sender_id"`
+		Tag      byte      `json:"tag"`
+		Time     time.Time `json:"time"`
+	}
+
+	TxGossipError struct {
+		Error   string    `json:"error"`
+		Time    time.Time `json:"time"`
+		Message string    `json:"message"`
+	}
+
+	// TxFailed is emitted when a transaction was rejected upon being applied.
+	TxFailed struct {
+		TxID     [32]byte  `json:"tx_id"`
+		SenderID [32]byte  `json:"sender_id"`
+		Tag      byte      `json:"tag"`
+		Error    string    `json:"error"`
+		Time     time.Time `json:"time"`
+	}
+
+	// TxConflict is emitted when two distinct transactions from the same sender
+	// sharing the same nonce were observed, TxID being the one observed last.
+	TxConflict struct {
+		TxID            [32]byte  `json:"tx_id"`
+		ConflictingTxID [32]byte  `json:"conflicting_tx_id"`
+		SenderID        [32]byte  `json:"sender_id"`
+		Nonce           uint64    `json:"nonce"`
+		Time            time.Time `json:"time"`
+		Message         string    `json:"message"`
+	}
+)
+
+func (e *TxApplied) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.TxID[:], "tx_id"); err != nil {
+		return err
+	}
+
+	if err := parseHex(v, e.SenderID[:], "sender_id"); err != nil {
+		return err
+	}
+
+	e.Tag = byte(v.GetUint("tag"))
+
+	return parseTime(v, &e.Time, "time")
+}
+
+func (e *TxApplied) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e TxApplied) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "tx_id", e.TxID[:])
+	setHex(&arena, o, "sender_id", e.SenderID[:])
+	o.Set("tag", arena.NewNumberInt(int(e.Tag)))
+	setTime(&arena, o, "time", e.Time)
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *TxGossipError) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseTime(v, &e.Time, "time"); err != nil {
+		return err
+	}
+
+	e.Error = string(v.GetStringBytes("error"))
+	e.Message = string(v.GetStringBytes("message"))
+
+	return nil
+}
+
+func (e *TxGossipError) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e TxGossipError) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	o.Set("error", arena.NewString(e.Error))
+	setTime(&arena, o, "time", e.Time)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *TxFailed) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.TxID[:], "tx_id"); err != nil {
+		return err
+	}
+
+	if err := parseHex(v, e.SenderID[:], "sender_id"); err != nil {
+		return err
+	}
+
+	e.Tag = byte(v.GetUint("tag"))
+	e.Error = string(v.GetStringBytes("error"))
+
+	return parseTime(v, &e.Time, "time")
+}
+
+func (e *TxFailed) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e TxFailed) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "tx_id", e.TxID[:])
+	setHex(&arena, o, "sender_id", e.SenderID[:])
+	o.Set("tag", arena.NewNumberInt(int(e.Tag)))
+	o.Set("error", arena.NewString(e.Error))
+	setTime(&arena, o, "time", e.Time)
+
+	return o.MarshalTo(nil), nil
+}
+
+func (e *TxConflict) UnmarshalValue(v *fastjson.Value) error {
+	if err := parseHex(v, e.TxID[:], "tx_id"); err != nil {
+		return err
+	}
+
+	if err := parseHex(v, e.ConflictingTxID[:], "conflicting_tx_id"); err != nil {
+		return err
+	}
+
+	if err := parseHex(v, e.SenderID[:], "sender_id"); err != nil {
+		return err
+	}
+
+	e.Nonce = v.GetUint64("nonce")
+	e.Message = string(v.GetStringBytes("message"))
+
+	return parseTime(v, &e.Time, "time")
+}
+
+func (e *TxConflict) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, e)
+}
+
+func (e TxConflict) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	setHex(&arena, o, "tx_id", e.TxID[:])
+	setHex(&arena, o, "conflicting_tx_id", e.ConflictingTxID[:])
+	setHex(&arena, o, "sender_id", e.SenderID[:])
+	setUint64(&arena, o, "nonce", e.Nonce)
+	setTime(&arena, o, "time", e.Time)
+	o.Set("message", arena.NewString(e.Message))
+
+	return o.MarshalTo(nil), nil
+}
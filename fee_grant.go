@@ -0,0 +1,134 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// Fee grants let a sponsor pay the transaction fees of another account, such
+// that dApps may onboard users who hold no PERLs. A sponsor records a standing
+// allowance for a grantee through a fee grant transaction. Whenever the
+// grantee pays a fee, it is deducted from the balance of the sponsor and from
+// the allowance, so long as both cover it. Otherwise, the grantee pays the fee
+// itself.
+//
+// An account has at most one sponsor. A grant may only be replaced by another
+// sponsor once it is exhausted, or once its sponsor may no longer pay fees, so
+// that a sponsor may not be displaced by a third party. Only the sponsor of a
+// grant may revoke it.
+
+// feePayer returns the account paying a fee on behalf of sender, which is its
+// sponsor should the allowance and balance of its sponsor cover the fee.
+func feePayer(
+	readAllowance func(AccountID) (FeeAllowance, bool), readBalance func(AccountID) (uint64, bool),
+	sender AccountID, fee uint64,
+) (AccountID, bool) {
+	grant, exists := readAllowance(sender)
+	if !exists || grant.Allowance < fee {
+		return sender, false
+	}
+
+	if balance, _ := readBalance(grant.Sponsor); balance < fee {
+		return sender, false
+	}
+
+	return grant.Sponsor, true
+}
+
+// verifyFeeGrant checks that the sender of tx may record or revoke a fee grant.
+func verifyFeeGrant(
+	readAllowance func(AccountID) (FeeAllowance, bool), readBalance func(AccountID) (uint64, bool),
+	tx *Transaction, grant FeeGrant,
+) error {
+	if grant.Grantee == tx.Sender {
+		return errors.Errorf("fee grant: %x may not sponsor itself", tx.Sender)
+	}
+
+	current, exists := readAllowance(grant.Grantee)
+
+	if grant.Allowance == 0 {
+		if !exists || current.Sponsor != tx.Sender {
+			return errors.Errorf("fee grant: %x has no grant from %x to revoke", grant.Grantee, tx.Sender)
+		}
+
+		return nil
+	}
+
+	if exists && current.Sponsor != tx.Sender {
+		if balance, _ := readBalance(current.Sponsor); balance >= sys.DefaultTransactionFee {
+			return errors.Errorf("fee grant: %x is already sponsored by %x", grant.Grantee, current.Sponsor)
+		}
+	}
+
+	return nil
+}
+
+// sponsoredFee returns the part of the fee of tx paid by its sender, being
+// none should a sponsor pay it.
+func sponsoredFee(snapshot *avl.Tree, tx Transaction) uint64 {
+	readAllowance := func(id AccountID) (FeeAllowance, bool) {
+		return ReadAccountFeeAllowance(snapshot, id)
+	}
+
+	readBalance := func(id AccountID) (uint64, bool) {
+		return ReadAccountBalance(snapshot, id)
+	}
+
+	if _, sponsored := feePayer(readAllowance, readBalance, tx.Sender, tx.Fee()); sponsored {
+		return 0
+	}
+
+	return tx.Fee()
+}
+
+func applyFeeGrantTransaction(ctx *CollapseContext, tx *Transaction) error {
+	payload, err := ParseFeeGrant(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyFeeGrant(ctx.ReadAccountFeeAllowance, ctx.ReadAccountBalance, tx, payload); err != nil {
+		return err
+	}
+
+	ctx.WriteAccountFeeAllowance(payload.Grantee, FeeAllowance{Sponsor: tx.Sender, Allowance: payload.Allowance})
+
+	return nil
+}
+
+func validateFeeGrantTransaction(snapshot *avl.Tree, tx Transaction) error {
+	payload, err := ParseFeeGrant(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	readAllowance := func(id AccountID) (FeeAllowance, bool) {
+		return ReadAccountFeeAllowance(snapshot, id)
+	}
+
+	readBalance := func(id AccountID) (uint64, bool) {
+		return ReadAccountBalance(snapshot, id)
+	}
+
+	return verifyFeeGrant(readAllowance, readBalance, &tx, payload)
+}
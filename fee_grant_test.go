@@ -0,0 +1,175 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeGrantTransaction(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureFeeGrants)()
+
+	sponsor, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	other, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	user, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	tree := avl.New(store.NewInmem())
+
+	WriteAccountBalance(tree, sponsor.PublicKey(), 1000)
+	WriteAccountBalance(tree, other.PublicKey(), 1000)
+
+	block := NewBlock(0, tree.Checksum())
+
+	grant := func(keys *skademlia.Keypair, grantee AccountID, allowance uint64) Transaction {
+		payload, err := FeeGrant{Grantee: grantee, Allowance: allowance}.Marshal()
+		require.NoError(t, err)
+
+		return NewTransaction(keys, 1, 0, sys.TagFeeGrant, payload)
+	}
+
+	// Accounts may not sponsor themselves.
+	tx := grant(sponsor, sponsor.PublicKey(), 100)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// There is no grant to revoke yet.
+	tx = grant(sponsor, user.PublicKey(), 0)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = grant(sponsor, user.PublicKey(), 100)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	allowance, exists := ReadAccountFeeAllowance(tree, user.PublicKey())
+	require.True(t, exists)
+	assert.Equal(t, FeeAllowance{Sponsor: sponsor.PublicKey(), Allowance: 100}, allowance)
+
+	// Other sponsors may neither displace nor revoke a grant in use.
+	tx = grant(other, user.PublicKey(), 200)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = grant(other, user.PublicKey(), 0)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// Sponsors may raise their own grants.
+	tx = grant(sponsor, user.PublicKey(), 300)
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	allowance, _ = ReadAccountFeeAllowance(tree, user.PublicKey())
+	assert.EqualValues(t, 300, allowance.Allowance)
+
+	// Grants of sponsors who may no longer pay fees may be replaced.
+	WriteAccountBalance(tree, sponsor.PublicKey(), sys.DefaultTransactionFee-1)
+
+	tx = grant(other, user.PublicKey(), 200)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	allowance, _ = ReadAccountFeeAllowance(tree, user.PublicKey())
+	assert.Equal(t, FeeAllowance{Sponsor: other.PublicKey(), Allowance: 200}, allowance)
+
+	tx = grant(other, user.PublicKey(), 0)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	_, exists = ReadAccountFeeAllowance(tree, user.PublicKey())
+	assert.False(t, exists)
+
+	// Fee grants may not be batched.
+	var batch Batch
+	require.NoError(t, batch.AddTransfer(Transfer{Recipient: other.PublicKey(), Amount: 1}))
+
+	batch.Tags[0] = byte(sys.TagFeeGrant)
+
+	payload, err := batch.Marshal()
+	require.NoError(t, err)
+
+	_, err = ParseBatch(payload)
+	assert.Error(t, err)
+}
+
+func TestSponsoredFees(t *testing.T) {
+	sponsor, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	user, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	recipient, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	accounts := NewAccounts(store.NewInmem())
+
+	WriteAccountBalance(accounts.tree, sponsor.PublicKey(), 1000)
+	WriteAccountBalance(accounts.tree, user.PublicKey(), 10)
+	WriteAccountFeeAllowance(accounts.tree, user.PublicKey(), FeeAllowance{
+		Sponsor:   sponsor.PublicKey(),
+		Allowance: 3,
+	})
+
+	transfer := func(nonce uint64, amount uint64) *Transaction {
+		payload, err := Transfer{Recipient: recipient.PublicKey(), Amount: amount}.Marshal()
+		require.NoError(t, err)
+
+		tx := NewTransaction(user, nonce, 0, sys.TagTransfer, payload)
+
+		return &tx
+	}
+
+	// The whole balance of the user may be sent, as its sponsor pays the fee.
+	require.NoError(t, ValidateTransaction(accounts.tree, *transfer(1, 10)))
+
+	block := NewBlock(0, accounts.tree.Checksum())
+
+	// The first fee is paid by the sponsor. The remaining allowance does not
+	// cover the second, so the user pays it instead.
+	txs := []*Transaction{transfer(1, 5), transfer(2, 1)}
+
+	res, err := collapseTransactions(block.Index+1, txs, &block, accounts)
+	require.NoError(t, err)
+	require.Len(t, res.applied, 2)
+
+	balance, _ := ReadAccountBalance(res.snapshot, sponsor.PublicKey())
+	assert.EqualValues(t, 1000-txs[0].Fee(), balance)
+
+	balance, _ = ReadAccountBalance(res.snapshot, user.PublicKey())
+	assert.EqualValues(t, 10-5-1-txs[1].Fee(), balance)
+
+	allowance, exists := ReadAccountFeeAllowance(res.snapshot, user.PublicKey())
+	assert.True(t, exists)
+	assert.EqualValues(t, 3-txs[0].Fee(), allowance.Allowance)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The fuzz targets below cover all input a node decodes from its peers or
+// API clients, and check that malformed input is rejected with an error
+// rather than a panic. Their seed corpora are run alongside the unit tests;
+// to fuzz a target, run e.g.:
+//
+//	go test -tags unit -run '^$' -fuzz FuzzUnmarshalTransaction .
+
+func fuzzKeys(f *testing.F) *skademlia.Keypair {
+	keys, err := skademlia.NewKeys(1, 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	return keys
+}
+
+func fuzzPayloads(f *testing.F) map[sys.Tag][][]byte {
+	keys := fuzzKeys(f)
+
+	transfer, err := Transfer{
+		Recipient: keys.PublicKey(), Amount: 1337, GasLimit: 42, GasDeposit: 10,
+		FuncName: []byte("hello"), FuncParams: []byte("world"),
+	}.Marshal()
+	require.NoError(f, err)
+
+	stake, err := Stake{Opcode: sys.PlaceStake, Amount: 1337}.Marshal()
+	require.NoError(f, err)
+
+	code, err := ioutil.ReadFile("testdata/transfer_back.wasm")
+	require.NoError(f, err)
+
+	contract, err := Contract{GasLimit: 100000, Params: []byte("params"), Code: code}.Marshal()
+	require.NoError(f, err)
+
+	var batch Batch
+	require.NoError(f, batch.AddTransfer(Transfer{Recipient: keys.PublicKey(), Amount: 1}))
+	require.NoError(f, batch.AddStake(Stake{Opcode: sys.WithdrawStake, Amount: 1}))
+
+	batched, err := batch.Marshal()
+	require.NoError(f, err)
+
+	recovery, err := Recovery{
+		Opcode:    sys.ConfigureRecovery,
+		Guardians: []AccountID{keys.PublicKey()},
+		Threshold: 1,
+	}.Marshal()
+	require.NoError(f, err)
+
+	doubleSign, err := Slashing{
+		Opcode: sys.ReportDoubleSign,
+		Evidence: [2]Transaction{
+			NewTransaction(keys, 1, 1, sys.TagTransfer, transfer), NewTransaction(keys, 1, 2, sys.TagTransfer, transfer),
+		},
+	}.Marshal()
+	require.NoError(f, err)
+
+	return map[sys.Tag][][]byte{
+		sys.TagTransfer:   {transfer, transfer[:SizeAccountID+8]},
+		sys.TagStake:      {stake},
+		sys.TagContract:   {contract},
+		sys.TagBatch:      {batched},
+		sys.TagBeacon:     {make([]byte, 80)},
+		sys.TagFeeGrant:   {make([]byte, SizeAccountID+8)},
+		sys.TagRecovery:   {recovery, {sys.CancelRecovery}},
+		sys.TagName:       {{sys.RenewName, 5, 'a', 'l', 'i', 'c', 'e'}},
+		sys.TagData:       {[]byte("document hash")},
+		sys.TagUpgrade:    {append([]byte{sys.UpgradeContract}, make([]byte, SizeAccountID+8)...)},
+		sys.TagMultisig:   {append([]byte{sys.ApproveMultisig}, make([]byte, SizeTransactionID)...)},
+		sys.TagDelegation: {{sys.ClaimUnbonded}, {sys.SetCommission, 0xf4, 0x01}},
+		sys.TagSlashing:   {doubleSign, append([]byte{sys.ReportDowntime}, make([]byte, SizeAccountID)...)},
+	}
+}
+
+func FuzzUnmarshalTransaction(f *testing.F) {
+	keys := fuzzKeys(f)
+
+	for tag, payloads := range fuzzPayloads(f) {
+		for _, payload := range payloads {
+			tx := NewTransaction(keys, 1, 2, tag, payload)
+			f.Add(tx.Marshal())
+		}
+	}
+
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tx, err := UnmarshalTransaction(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		// Every transaction has exactly one encoding.
+		buf := tx.Marshal()
+		require.True(t, bytes.HasPrefix(data, buf), "transaction re-encoded differently")
+
+		decoded, err := UnmarshalTransaction(bytes.NewReader(buf))
+		require.NoError(t, err)
+		assert.Equal(t, tx, decoded)
+	})
+}
+
+func FuzzUnmarshalBlock(f *testing.F) {
+	block := NewBlock(1, MerkleNodeID{1}, TransactionID{2}, TransactionID{3})
+
+	f.Add(block.Marshal())
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		block, err := UnmarshalBlock(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		buf := block.Marshal()
+		require.True(t, bytes.HasPrefix(data, buf), "block re-encoded differently")
+	})
+}
+
+func FuzzParsePayload(f *testing.F) {
+	for tag, payloads := range fuzzPayloads(f) {
+		for _, payload := range payloads {
+			f.Add(byte(tag), payload)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, tag byte, data []byte) {
+		payload, err := ParsePayload(sys.Tag(tag), data)
+		if err != nil {
+			return
+		}
+
+		if batch, ok := payload.(Batch); ok {
+			if _, err := batch.Entries(); err != nil {
+				return
+			}
+		}
+
+		buf, err := payload.Marshal()
+		if err != nil {
+			return
+		}
+
+		// Optional fields may be encoded as empty rather than omitted, such
+		// that only re-encoding a parsed payload is stable.
+		reparsed, err := ParsePayload(sys.Tag(tag), buf)
+		require.NoError(t, err)
+
+		rebuf, err := reparsed.Marshal()
+		require.NoError(t, err)
+		assert.Equal(t, buf, rebuf)
+	})
+}
+
+func FuzzSpawnContract(f *testing.F) {
+	for _, payload := range fuzzPayloads(f)[sys.TagContract] {
+		f.Add(payload)
+	}
+
+	keys := fuzzKeys(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if _, err := ParseContract(data); err != nil {
+			return
+		}
+
+		tx := NewTransaction(keys, 1, 0, sys.TagContract, data)
+
+		tree := avl.New(store.NewInmem())
+		block := NewBlock(1, tree.Checksum(), tx.ID)
+
+		// Bound the gas, and thus time, any contract may consume.
+		WriteAccountBalance(tree, tx.Sender, 1000000)
+
+		_ = ApplyTransaction(tree, &block, &tx)
+	})
+}
+
+func FuzzParseJSON(f *testing.F) {
+	f.Add(`{"recipient": "400056ee68a7cc2695222df05ea76875bc27ec6e61e8e62317c336157019c405", "amount": 1}`, "transfer")
+	f.Add(`{"operation": 0, "amount": 1}`, "stake")
+	f.Add(`{"payload": [{"tag": 1, "payload": {"operation": 1, "amount": 2}}]}`, "batch")
+
+	f.Fuzz(func(t *testing.T, data string, tag string) {
+		// Contract payloads read their code from a path, which is not fuzzed.
+		if tag == "contract" {
+			return
+		}
+
+		_, _ = ParseJSON([]byte(data), tag)
+	})
+}
@@ -139,16 +139,13 @@ func restoreFromJSON(tree *avl.Tree, json []byte) error {
 		}
 
 		var id AccountID
-		var n int
 
-		n, err = hex.Decode(id[:], key)
-
-		if n != cap(id) && err == nil {
+		if hex.DecodedLen(len(key)) != len(id) {
 			err = errors.Errorf("got an invalid account ID: %x", key)
 			return
 		}
 
-		if err != nil {
+		if _, err = hex.Decode(id[:], key); err != nil {
 			err = errors.Wrapf(err, "got an invalid account ID: %x", key)
 			return
 		}
@@ -163,7 +160,7 @@ func restoreFromJSON(tree *avl.Tree, json []byte) error {
 		err = restoreAccount(tree, id, val)
 	})
 
-	return nil
+	return err
 }
 
 func restoreContractGlobals(tree *avl.Tree, id TransactionID, path string) error {
@@ -0,0 +1,244 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package genesis generates the genesis of test networks: a set of randomly
+// keyed accounts whose balances follow some distribution, the first few of
+// which stake to be validators.
+package genesis
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// Distributions lists the names of the supported distributions of balances.
+var Distributions = []string{"equal", "uniform", "pareto"}
+
+// paretoShape is the shape of the Pareto distribution of balances, for which
+// 20% of accounts hold 80% of the supply.
+const paretoShape = 1.16
+
+// Options configures the genesis to be generated.
+type Options struct {
+	// Accounts is the number of accounts, validators included.
+	Accounts int
+
+	// Validators is the number of accounts which stake at genesis.
+	Validators int
+
+	// Distribution is the name of the distribution the supply is split
+	// among accounts by. See Distributions.
+	Distribution string
+
+	// Supply is the total balance of all accounts.
+	Supply uint64
+
+	// Stake is the stake of each validator, in addition to its balance.
+	Stake uint64
+
+	// Seed seeds the distribution of balances. Keys are always random.
+	Seed int64
+}
+
+// DefaultOptions returns the options of a genesis of 100 accounts, 4 of
+// which are validators, whose balances are Pareto distributed.
+func DefaultOptions() Options {
+	return Options{
+		Accounts:     100,
+		Validators:   4,
+		Distribution: "pareto",
+		Supply:       10000000000000000000,
+		Stake:        sys.MinimumStake * 10000,
+		Seed:         time.Now().UnixNano(),
+	}
+}
+
+// Account is an account of a generated genesis.
+type Account struct {
+	Keys      *skademlia.Keypair
+	Balance   uint64
+	Stake     uint64
+	Validator bool
+}
+
+// Genesis is a generated genesis. Validators come first amongst accounts.
+type Genesis struct {
+	Accounts []Account
+}
+
+// Generate generates a genesis as configured by opts.
+func Generate(opts Options) (*Genesis, error) {
+	if opts.Accounts <= 0 {
+		return nil, errors.New("there must be at least one account")
+	}
+
+	if opts.Validators < 0 || opts.Validators > opts.Accounts {
+		return nil, errors.Errorf("the number of validators must be between 0 and %d", opts.Accounts)
+	}
+
+	if opts.Validators > 0 && opts.Stake < sys.MinimumStake {
+		return nil, errors.Errorf("validators must stake at least %d PERLs", sys.MinimumStake)
+	}
+
+	// Stakes may be withdrawn into balances, hence supply and stakes must
+	// fit together.
+	total := new(big.Int).Mul(big.NewInt(int64(opts.Validators)), new(big.Int).SetUint64(opts.Stake))
+	if !total.Add(total, new(big.Int).SetUint64(opts.Supply)).IsUint64() {
+		return nil, errors.New("the supply and stakes of validators add up to more than 2^64-1 PERLs")
+	}
+
+	balances, err := distribute(opts.Distribution, opts.Supply, opts.Accounts, rand.New(rand.NewSource(opts.Seed)))
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Genesis{Accounts: make([]Account, opts.Accounts)}
+
+	for i := range g.Accounts {
+		keys, err := skademlia.NewKeys(sys.SKademliaC1, sys.SKademliaC2)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate keys")
+		}
+
+		g.Accounts[i] = Account{Keys: keys, Balance: balances[i]}
+
+		if i < opts.Validators {
+			g.Accounts[i].Stake = opts.Stake
+			g.Accounts[i].Validator = true
+		}
+	}
+
+	return g, nil
+}
+
+// distribute splits supply into n balances summing up to supply.
+func distribute(distribution string, supply uint64, n int, r *rand.Rand) ([]uint64, error) {
+	weights := make([]float64, n)
+
+	for i := range weights {
+		switch distribution {
+		case "equal":
+			weights[i] = 1
+		case "uniform":
+			weights[i] = r.Float64()
+		case "pareto":
+			weights[i] = math.Pow(1-r.Float64(), -1/paretoShape)
+		default:
+			return nil, errors.Errorf("unknown distribution %q, expected one of %v", distribution, Distributions)
+		}
+	}
+
+	var total float64
+
+	for _, w := range weights {
+		total += w
+	}
+
+	balances := make([]uint64, n)
+	remaining := supply
+
+	for i, w := range weights {
+		share, _ := new(big.Float).Mul(new(big.Float).SetUint64(supply), big.NewFloat(w/total)).Uint64()
+		if share > remaining {
+			share = remaining
+		}
+
+		balances[i] = share
+		remaining -= share
+	}
+
+	// Whatever is left over from rounding goes to the first account, such
+	// that balances sum up to exactly the supply.
+	balances[0] += remaining
+
+	return balances, nil
+}
+
+type accountJSON struct {
+	Balance uint64 `json:"balance"`
+	Stake   uint64 `json:"stake,omitempty"`
+}
+
+// MarshalJSON encodes the genesis as the JSON contents accepted by
+// wavelet.WithGenesis.
+func (g *Genesis) MarshalJSON() ([]byte, error) {
+	accounts := make(map[string]accountJSON, len(g.Accounts))
+
+	for _, a := range g.Accounts {
+		id := a.Keys.PublicKey()
+		accounts[hex.EncodeToString(id[:])] = accountJSON{Balance: a.Balance, Stake: a.Stake}
+	}
+
+	return json.Marshal(accounts)
+}
+
+// Write writes the genesis to dir, creating it if it does not exist. The
+// account files of the genesis are written to dir/genesis, which may be
+// passed to the genesis flag of a node, and the hex-encoded private key of
+// each account to dir/keys/validators or dir/keys/accounts, which may be
+// passed to the wallet flag of a node.
+func (g *Genesis) Write(dir string) error {
+	genesisDir := filepath.Join(dir, "genesis")
+	validatorsDir := filepath.Join(dir, "keys", "validators")
+	accountsDir := filepath.Join(dir, "keys", "accounts")
+
+	for _, d := range []string{genesisDir, validatorsDir, accountsDir} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			return errors.Wrapf(err, "failed to create directory %s", d)
+		}
+	}
+
+	for _, a := range g.Accounts {
+		id := a.Keys.PublicKey()
+		name := hex.EncodeToString(id[:])
+
+		buf, err := json.MarshalIndent(accountJSON{Balance: a.Balance, Stake: a.Stake}, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(genesisDir, name+".json"), buf, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write genesis of account %s", name)
+		}
+
+		keysDir := accountsDir
+		if a.Validator {
+			keysDir = validatorsDir
+		}
+
+		key := a.Keys.PrivateKey()
+
+		if err := ioutil.WriteFile(filepath.Join(keysDir, name+".txt"), []byte(hex.EncodeToString(key[:])), 0600); err != nil {
+			return errors.Wrapf(err, "failed to write key of account %s", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package genesis
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistribute(t *testing.T) {
+	for _, distribution := range Distributions {
+		for _, supply := range []uint64{0, 1, 999, 10000000000000000000, math.MaxUint64} {
+			balances, err := distribute(distribution, supply, 1000, rand.New(rand.NewSource(1)))
+			require.NoError(t, err)
+			require.Len(t, balances, 1000)
+
+			var total uint64
+
+			for _, balance := range balances {
+				require.True(t, total+balance >= total, "%s: balances of a supply of %d overflow", distribution, supply)
+				total += balance
+			}
+
+			assert.Equal(t, supply, total, distribution)
+		}
+	}
+
+	_, err := distribute("zipf", 1000, 10, rand.New(rand.NewSource(1)))
+	assert.Error(t, err)
+}
+
+func TestDistributePareto(t *testing.T) {
+	balances, err := distribute("pareto", 10000000000000000000, 10000, rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+
+	sort.Slice(balances, func(i, j int) bool { return balances[i] > balances[j] })
+
+	var top, total float64
+
+	for i, balance := range balances {
+		if i < len(balances)/5 {
+			top += float64(balance)
+		}
+
+		total += float64(balance)
+	}
+
+	// The richest 20% should hold roughly 80% of the supply.
+	assert.InDelta(t, 0.8, top/total, 0.1)
+}
+
+func TestGenerate(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Accounts = 10
+	opts.Validators = 3
+
+	g, err := Generate(opts)
+	require.NoError(t, err)
+	require.Len(t, g.Accounts, 10)
+
+	seen := make(map[[32]byte]struct{})
+
+	for i, a := range g.Accounts {
+		assert.Equal(t, i < 3, a.Validator)
+
+		if a.Validator {
+			assert.Equal(t, opts.Stake, a.Stake)
+		} else {
+			assert.Zero(t, a.Stake)
+		}
+
+		seen[a.Keys.PublicKey()] = struct{}{}
+	}
+
+	assert.Len(t, seen, 10)
+
+	invalid := []func(*Options){
+		func(o *Options) { o.Accounts = 0 },
+		func(o *Options) { o.Validators = o.Accounts + 1 },
+		func(o *Options) { o.Validators = -1 },
+		func(o *Options) { o.Stake = sys.MinimumStake - 1 },
+		func(o *Options) { o.Supply = math.MaxUint64 },
+		func(o *Options) { o.Distribution = "zipf" },
+	}
+
+	for _, fn := range invalid {
+		opts := DefaultOptions()
+		fn(&opts)
+
+		_, err := Generate(opts)
+		assert.Error(t, err)
+	}
+}
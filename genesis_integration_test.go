@@ -31,8 +31,10 @@ import (
 	"testing"
 
 	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/genesis"
 	"github.com/perlin-network/wavelet/store"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -442,3 +444,56 @@ func BenchmarkPerformInception(b *testing.B) {
 		performInception(tree, &testRestoreDir)
 	}
 }
+
+func TestPerformInceptionGenerated(t *testing.T) {
+	opts := genesis.DefaultOptions()
+	opts.Accounts = 20
+	opts.Validators = 3
+
+	g, err := genesis.Generate(opts)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "genesis")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, g.Write(dir))
+
+	contents, err := g.MarshalJSON()
+	require.NoError(t, err)
+
+	genesisDir := filepath.Join(dir, "genesis")
+	genesisJSON := string(contents)
+
+	fromDir := avl.New(store.NewInmem())
+	performInception(fromDir, &genesisDir)
+
+	fromJSON := avl.New(store.NewInmem())
+	performInception(fromJSON, &genesisJSON)
+
+	assert.Equal(t, fromDir.Checksum(), fromJSON.Checksum())
+
+	for _, a := range g.Accounts {
+		a := a
+
+		var stake *uint64
+
+		keysDir := filepath.Join(dir, "keys", "accounts")
+
+		if a.Validator {
+			stake = &a.Stake
+			keysDir = filepath.Join(dir, "keys", "validators")
+		}
+
+		checkAccount(t, fromDir, a.Keys.PublicKey(), &a.Balance, nil, stake)
+
+		key, err := ioutil.ReadFile(filepath.Join(keysDir, fmt.Sprintf("%x.txt", a.Keys.PublicKey())))
+		require.NoError(t, err)
+
+		privateKey := a.Keys.PrivateKey()
+		assert.Equal(t, hex.EncodeToString(privateKey[:]), string(key))
+	}
+
+	assert.Equal(t, uint64(opts.Accounts), ReadAccountsLen(fromDir))
+}
@@ -12,14 +12,17 @@ require (
 	github.com/buaazp/fasthttprouter v0.1.1
 	github.com/chzyer/logex v1.1.10 // indirect
 	github.com/chzyer/test v0.0.0-20180213035817-a1ea475d72b1 // indirect
+	github.com/consensys/gnark-crypto v0.5.3
 	github.com/dgraph-io/badger/v2 v2.0.0
 	github.com/djherbis/buffer v1.1.0
 	github.com/fasthttp/websocket v1.4.0
+	github.com/go-interpreter/wagon v0.0.0
 	github.com/gogo/protobuf v1.3.0
 	github.com/golang/protobuf v1.3.2
 	github.com/golang/snappy v0.0.1
 	github.com/gorilla/websocket v1.4.0
 	github.com/huandu/skiplist v0.0.0-20180112095830-8e883b265e1b
+	github.com/klauspost/compress v1.11.13
 	github.com/minio/highwayhash v1.0.0
 	github.com/mmcloughlin/avo v0.0.0-20190927041150-15d6a9a17e53 // indirect
 	github.com/perlin-network/life v0.0.0-20190723115110-3091ed0c1be8
@@ -35,9 +38,9 @@ require (
 	github.com/valyala/fasthttp v1.3.0
 	github.com/valyala/fastjson v1.4.1
 	go.uber.org/atomic v1.5.0
-	golang.org/x/crypto v0.0.0-20190911031432-227b76d455e7
-	golang.org/x/net v0.0.0-20191105084925-a882066a44e0
-	golang.org/x/sys v0.0.0-20190919044723-0c1ff786ef13 // indirect
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
+	golang.org/x/net v0.0.0-20210226172049-e18ecbb05110
+	golang.org/x/text v0.3.3
 	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
 	google.golang.org/appengine v1.6.2 // indirect
 	google.golang.org/genproto v0.0.0-20190916214212-f660b8655731 // indirect
@@ -22,25 +22,42 @@ package wavelet
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/perlin-network/noise/skademlia"
 	"github.com/perlin-network/wavelet/conf"
 	"github.com/perlin-network/wavelet/internal/debounce"
+	"github.com/perlin-network/wavelet/ledgerpb"
 	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
 )
 
 type Gossiper struct {
 	client  *skademlia.Client
 	metrics *Metrics
 
+	// compression is the encoding our node prefers compressing gossip with.
+	compression string
+
+	// encodings are the encodings negotiated to compress gossip to peers
+	// with, keyed by their address. Gossip to peers not yet gossiped to is
+	// not compressed.
+	encodingsLock sync.Mutex
+	encodings     map[string]string
+
 	debouncer *debounce.Limiter
 }
 
-func NewGossiper(ctx context.Context, client *skademlia.Client, metrics *Metrics) *Gossiper {
+func NewGossiper(ctx context.Context, client *skademlia.Client, metrics *Metrics, compression string) *Gossiper {
 	g := &Gossiper{
-		client:  client,
-		metrics: metrics,
+		client:      client,
+		metrics:     metrics,
+		compression: compression,
+		encodings:   make(map[string]string),
 	}
 
 	g.debouncer = debounce.NewLimiter(
@@ -70,9 +87,62 @@ func (g *Gossiper) Gossip(transactions [][]byte) {
 		return
 	}
 
-	batch := &GossipRequest{Transactions: transactions}
+	// Batches carry transactions binary encoded alongside their protobuf
+	// encoding, such that nodes yet to upgrade to the latter keep receiving
+	// gossip.
+	batch := &GossipRequest{
+		Transactions: make([][]byte, 0, len(transactions)),
+		Txs:          make([]*ledgerpb.Transaction, 0, len(transactions)),
+	}
+
+	for _, buf := range transactions {
+		tx, err := ParseTransaction(buf)
+		if err != nil {
+			logger.Err(err).Msg("Failed to unmarshal transaction")
+			continue
+		}
+
+		batch.Transactions = append(batch.Transactions, buf)
+		batch.Txs = append(batch.Txs, tx.Proto())
+	}
+
+	raw, err := batch.Marshal()
+	if err != nil {
+		logger.Err(err).Msg("Failed to marshal batch")
+		return
+	}
 
-	var wg sync.WaitGroup
+	// The batch is compressed once with every encoding negotiated with the
+	// peers gossiped to.
+	requests := map[string]*GossipRequest{CompressionNone: batch}
+
+	for _, p := range peers {
+		enc := g.encoding(p.ID().Address())
+
+		if _, exists := requests[enc]; exists {
+			continue
+		}
+
+		compressed, err := compress(enc, raw)
+		if err != nil {
+			logger.Err(err).Str("encoding", enc).Msg("Failed to compress batch")
+
+			requests[enc] = batch
+
+			continue
+		}
+
+		requests[enc] = &GossipRequest{Encoding: enc, Compressed: compressed}
+	}
+
+	start := time.Now()
+
+	spans, traceMD := startGossipSpans(batch.Txs)
+
+	var (
+		wg     sync.WaitGroup
+		failed uint32
+	)
 
 	for _, p := range peers {
 		wg.Add(1)
@@ -85,12 +155,72 @@ func (g *Gossiper) Gossip(transactions [][]byte) {
 			ctx, cancel := context.WithTimeout(context.Background(), conf.GetGossipTimeout())
 			defer cancel()
 
-			_, err := client.Gossip(ctx, batch)
-			if err != nil {
+			if len(traceMD) > 0 {
+				ctx = metadata.AppendToOutgoingContext(ctx, traceMD...)
+			}
+
+			req := requests[g.encoding(p.ID().Address())]
+
+			var header metadata.MD
+
+			opts := []grpc.CallOption{grpc.Header(&header)}
+
+			// Compressed batches are not compressed again by the transport.
+			if len(req.Compressed) > 0 {
+				opts = append(opts, grpc.UseCompressor(encoding.Identity))
+			}
+
+			if _, err := client.Gossip(ctx, req, opts...); err != nil {
 				logger.Err(err).Msg("Failed to send batch")
+				atomic.AddUint32(&failed, 1)
+
+				return
+			}
+
+			g.negotiate(p.ID().Address(), header)
+
+			if g.metrics != nil {
+				g.metrics.gossipedBytes.Inc(int64(len(raw)))
+				g.metrics.gossipedWireBytes.Inc(int64(req.Size()))
 			}
 		}(p)
 	}
 
 	wg.Wait()
+
+	for _, span := range spans {
+		span.SetAttributes(
+			trace.Int64("gossip.num_peers", int64(len(peers))),
+			trace.Int64("gossip.num_failed", int64(atomic.LoadUint32(&failed))),
+			trace.Int64("gossip.batch_size", int64(len(batch.Txs))),
+		)
+		span.End()
+	}
+
+	if g.metrics != nil {
+		g.metrics.gossipLatency.UpdateSince(start)
+	}
+}
+
+// encoding returns the encoding negotiated to compress gossip to the peer at
+// addr with.
+func (g *Gossiper) encoding(addr string) string {
+	g.encodingsLock.Lock()
+	defer g.encodingsLock.Unlock()
+
+	if enc, exists := g.encodings[addr]; exists {
+		return enc
+	}
+
+	return CompressionNone
+}
+
+// negotiate negotiates the encoding to compress gossip to the peer at addr
+// with, from the header it responded to gossip with.
+func (g *Gossiper) negotiate(addr string, header metadata.MD) {
+	enc := negotiateEncoding(g.compression, header)
+
+	g.encodingsLock.Lock()
+	g.encodings[addr] = enc
+	g.encodingsLock.Unlock()
 }
@@ -0,0 +1,173 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+)
+
+// HealthThresholds are the conditions under which our node is deemed
+// healthy, and ready to serve clients.
+type HealthThresholds struct {
+	// MinPeers is the fewest peers our node may be connected to, and be
+	// ready.
+	MinPeers int
+
+	// MaxStall is the longest a round of consensus may go on without our
+	// node finalizing a block, past which consensus is deemed stalled, or 0
+	// for consensus to never be deemed stalled.
+	MaxStall time.Duration
+
+	// MaxSyncLag is the most blocks our node may be behind its peers, and be
+	// ready.
+	MaxSyncLag uint64
+}
+
+// DefaultHealthThresholds returns thresholds under which our node is ready
+// once in sync with at least one peer, and healthy unless a round of
+// consensus goes on for over two minutes.
+func DefaultHealthThresholds() HealthThresholds {
+	return HealthThresholds{
+		MinPeers: 1,
+		MaxStall: 2 * time.Minute,
+	}
+}
+
+// Health reports on the conditions of the health of our node, checked
+// against HealthThresholds.
+type Health struct {
+	Storage   StorageHealth
+	Peers     PeersHealth
+	Consensus ConsensusHealth
+	Sync      SyncHealth
+}
+
+// StorageHealth reports on whether the database of our node may be written
+// to and read from.
+type StorageHealth struct {
+	OK bool
+
+	// Error is why the database failed to be written to or read from.
+	Error string
+
+	// Latency is how long writing to and reading from the database took.
+	Latency time.Duration
+}
+
+// PeersHealth reports on whether our node is connected to enough peers.
+type PeersHealth struct {
+	OK bool
+
+	NumPeers int
+	MinPeers int
+}
+
+// ConsensusHealth reports on whether consensus has stalled.
+type ConsensusHealth struct {
+	OK bool
+
+	// BlockIndex is the index of the latest block finalized.
+	BlockIndex uint64
+
+	// RoundDuration is how long the round being finalized has gone on,
+	// which is 0 should our node not prefer a block for the round.
+	RoundDuration time.Duration
+	MaxStall      time.Duration
+}
+
+// SyncHealth reports on whether our node is in sync with its peers.
+type SyncHealth struct {
+	OK bool
+
+	SyncStatus
+	MaxLag uint64
+}
+
+// Live returns true should the database of our node be reachable, and
+// consensus not have stalled. Nodes which are not live may recover by being
+// restarted.
+func (h Health) Live() bool {
+	return h.Storage.OK && h.Consensus.OK
+}
+
+// Ready returns true should our node be live, connected to enough peers and
+// in sync with them, such that it may serve clients.
+func (h Health) Ready() bool {
+	return h.Live() && h.Peers.OK && h.Sync.OK
+}
+
+// CheckHealth checks the conditions of the health of our node against t.
+func (l *Ledger) CheckHealth(t HealthThresholds) Health {
+	var h Health
+
+	start := time.Now()
+
+	if err := probeStorage(l.db, start); err != nil {
+		h.Storage.Error = err.Error()
+	} else {
+		h.Storage.OK = true
+	}
+
+	h.Storage.Latency = time.Since(start)
+
+	h.Peers = PeersHealth{NumPeers: len(l.client.ClosestPeers()), MinPeers: t.MinPeers}
+	h.Peers.OK = h.Peers.NumPeers >= h.Peers.MinPeers
+
+	h.Consensus = ConsensusHealth{BlockIndex: l.blocks.LatestHeight(), MaxStall: t.MaxStall}
+
+	if roundStart := l.roundStart.Load(); roundStart != 0 {
+		h.Consensus.RoundDuration = time.Since(time.Unix(0, roundStart))
+	}
+
+	h.Consensus.OK = t.MaxStall == 0 || h.Consensus.RoundDuration <= t.MaxStall
+
+	h.Sync = SyncHealth{SyncStatus: l.syncManager.Status(), MaxLag: t.MaxSyncLag}
+	// Nodes which need no peers to be ready, such as those of a network of
+	// one, need not have been told by peers that they are in sync.
+	h.Sync.OK = (h.Sync.Reconciled || t.MinPeers == 0) && h.Sync.Lag <= h.Sync.MaxLag
+
+	return h
+}
+
+// probeStorage writes now to the database, and reads it back.
+func probeStorage(db store.KV, now time.Time) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(now.UnixNano()))
+
+	if err := db.Put(keyHealthProbe[:], buf[:]); err != nil {
+		return errors.Wrap(err, "failed to write to the database")
+	}
+
+	read, err := db.Get(keyHealthProbe[:])
+	if err != nil {
+		return errors.Wrap(err, "failed to read from the database")
+	}
+
+	if !bytes.Equal(read, buf[:]) {
+		return errors.New("read back other than what was written to the database")
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHealth(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ledger, err := NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer ledger.Close()
+
+	// Without peers, the node is live yet not ready, for it has neither
+	// enough peers nor been told by any whether it is in sync.
+	h := ledger.CheckHealth(DefaultHealthThresholds())
+
+	assert.True(t, h.Storage.OK)
+	assert.Empty(t, h.Storage.Error)
+	assert.True(t, h.Consensus.OK)
+	assert.Zero(t, h.Consensus.RoundDuration)
+	assert.False(t, h.Peers.OK)
+	assert.Equal(t, 0, h.Peers.NumPeers)
+	assert.False(t, h.Sync.OK)
+	assert.False(t, h.Sync.Reconciled)
+
+	assert.True(t, h.Live())
+	assert.False(t, h.Ready())
+
+	// Nodes needing no peers need not be told whether they are in sync.
+	h = ledger.CheckHealth(HealthThresholds{MaxStall: time.Minute})
+	assert.True(t, h.Ready())
+
+	// Consensus stalls once a round goes on for longer than allowed.
+	ledger.roundStart.Store(time.Now().Add(-time.Hour).UnixNano())
+
+	h = ledger.CheckHealth(HealthThresholds{MaxStall: time.Minute})
+	assert.False(t, h.Consensus.OK)
+	assert.True(t, h.Consensus.RoundDuration >= time.Hour)
+	assert.False(t, h.Live())
+	assert.False(t, h.Ready())
+
+	h = ledger.CheckHealth(HealthThresholds{})
+	assert.True(t, h.Consensus.OK)
+}
+
+func TestSyncStatusLag(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ledger, err := NewLedger(store.NewInmem(), skademlia.NewClient(":0", keys))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer ledger.Close()
+
+	// Without peers, our node is never told whether it is in sync.
+	s := ledger.syncManager
+
+	assert.Equal(t, SyncStatus{}, s.Status())
+
+	s.reconciled.Store(true)
+	s.syncing.Store(true)
+
+	// Nodes behind peers by an unknown number of blocks are behind by at
+	// least the number past which nodes sync.
+	assert.Equal(t, SyncStatus{Reconciled: true, Syncing: true, Lag: conf.GetSyncIfBlockIndicesDifferBy()}, s.Status())
+
+	s.target.Store(42)
+	assert.EqualValues(t, 42-ledger.Blocks().LatestHeight(), s.Status().Lag)
+}
@@ -0,0 +1,251 @@
+// Package curve provides the scalar and group arithmetic of Ed25519 missing
+// from package edwards25519, as needed by threshold signatures and VRFs.
+//
+// Scalars are 32-byte little-endian integers modulo the order of the Ed25519
+// base point. Arithmetic on secret scalars goes through ScMulAdd, which runs
+// in constant time. Only public scalars, such as Lagrange coefficients, may
+// be computed with math/big.
+package curve
+
+import (
+	"crypto/sha512"
+	"io"
+	"math/big"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+)
+
+// Order is the order l of the Ed25519 base point.
+var Order, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+var (
+	ScalarZero [32]byte
+	ScalarOne  = [32]byte{1}
+)
+
+// ScalarMulAdd returns a*b+c modulo l.
+func ScalarMulAdd(a, b, c [32]byte) [32]byte {
+	var s [32]byte
+	edwards25519.ScMulAdd(&s, &a, &b, &c)
+
+	return s
+}
+
+// ScalarAdd returns a+b modulo l.
+func ScalarAdd(a, b [32]byte) [32]byte {
+	return ScalarMulAdd(a, ScalarOne, b)
+}
+
+// ScalarMul returns a*b modulo l.
+func ScalarMul(a, b [32]byte) [32]byte {
+	return ScalarMulAdd(a, b, ScalarZero)
+}
+
+// ScalarReduce reduces any 32-byte integer modulo l.
+func ScalarReduce(a [32]byte) [32]byte {
+	return ScalarMul(a, ScalarOne)
+}
+
+// ScalarNeg returns -a modulo l. It is not constant-time.
+func ScalarNeg(a [32]byte) [32]byte {
+	return ScalarFromBig(new(big.Int).Sub(Order, ScalarToBig(a)))
+}
+
+func ScalarFromUint(x uint64) [32]byte {
+	return ScalarFromBig(new(big.Int).SetUint64(x))
+}
+
+func ScalarFromBig(x *big.Int) [32]byte {
+	var s [32]byte
+
+	b := new(big.Int).Mod(x, Order).Bytes()
+	for i := range b {
+		s[i] = b[len(b)-1-i]
+	}
+
+	return s
+}
+
+func ScalarToBig(s [32]byte) *big.Int {
+	var b [32]byte
+	for i := range s {
+		b[i] = s[len(s)-1-i]
+	}
+
+	return new(big.Int).SetBytes(b[:])
+}
+
+// ScalarIsCanonical reports whether s is fully reduced modulo l.
+func ScalarIsCanonical(s [32]byte) bool {
+	return ScalarToBig(s).Cmp(Order) < 0
+}
+
+// HashToScalar reduces the SHA-512 digest of the concatenation of parts
+// modulo l.
+func HashToScalar(parts ...[]byte) [32]byte {
+	digest := Hash(parts...)
+
+	var s [32]byte
+	edwards25519.ScReduce(&s, &digest)
+
+	return s
+}
+
+func Hash(parts ...[]byte) [64]byte {
+	h := sha512.New()
+	for _, part := range parts {
+		_, _ = h.Write(part)
+	}
+
+	var digest [64]byte
+	h.Sum(digest[:0])
+
+	return digest
+}
+
+// RandomScalar returns a uniformly random scalar read from rand.
+func RandomScalar(rand io.Reader) ([32]byte, error) {
+	var buf [64]byte
+	if _, err := io.ReadFull(rand, buf[:]); err != nil {
+		return ScalarZero, errors.Wrap(err, "failed to read randomness")
+	}
+
+	var s [32]byte
+	edwards25519.ScReduce(&s, &buf)
+
+	return s, nil
+}
+
+// ExpandSecret derives the secret scalar of key the same way
+// edwards25519.Sign does, reduced modulo l.
+func ExpandSecret(key edwards25519.PrivateKey) [32]byte {
+	digest := Hash(key[:edwards25519.SizePrivateKey/2])
+
+	var secret [32]byte
+	copy(secret[:], digest[:32])
+
+	secret[0] &= 248
+	secret[31] &= 63
+	secret[31] |= 64
+
+	return ScalarReduce(secret)
+}
+
+// Point is an element of the Ed25519 group.
+type Point = edwards25519.ExtendedGroupElement
+
+// d2 is 2*d, d being the curve constant -121665/121666.
+var d2 edwards25519.FieldElement
+
+func init() {
+	var num, den, inv, d edwards25519.FieldElement
+
+	edwards25519.FeFromBytes(&num, &[32]byte{0x41, 0xdb, 0x01}) // 121665
+	edwards25519.FeFromBytes(&den, &[32]byte{0x42, 0xdb, 0x01}) // 121666
+
+	edwards25519.FeNeg(&num, &num)
+	edwards25519.FeInvert(&inv, &den)
+	edwards25519.FeMul(&d, &num, &inv)
+	edwards25519.FeAdd(&d2, &d, &d)
+}
+
+func Identity() *Point {
+	var p Point
+	p.Zero()
+
+	return &p
+}
+
+func ScalarMultBase(s [32]byte) *Point {
+	var p Point
+	edwards25519.GeScalarMultBase(&p, &s)
+
+	return &p
+}
+
+func ScalarMult(s [32]byte, q *Point) *Point {
+	var p Point
+	edwards25519.GeScalarMult(&p, &s, q)
+
+	return &p
+}
+
+// AddPoints returns p+q, using the unified addition formulas for twisted
+// Edwards curves with a = -1 in extended coordinates.
+func AddPoints(p, q *Point) *Point {
+	var a, b, c, d, e, f, g, h, t0, t1 edwards25519.FieldElement
+
+	edwards25519.FeSub(&t0, &p.Y, &p.X)
+	edwards25519.FeSub(&t1, &q.Y, &q.X)
+	edwards25519.FeMul(&a, &t0, &t1)
+
+	edwards25519.FeAdd(&t0, &p.Y, &p.X)
+	edwards25519.FeAdd(&t1, &q.Y, &q.X)
+	edwards25519.FeMul(&b, &t0, &t1)
+
+	edwards25519.FeMul(&t0, &p.T, &q.T)
+	edwards25519.FeMul(&c, &t0, &d2)
+
+	edwards25519.FeMul(&t0, &p.Z, &q.Z)
+	edwards25519.FeAdd(&d, &t0, &t0)
+
+	edwards25519.FeSub(&e, &b, &a)
+	edwards25519.FeSub(&f, &d, &c)
+	edwards25519.FeAdd(&g, &d, &c)
+	edwards25519.FeAdd(&h, &b, &a)
+
+	var r Point
+
+	edwards25519.FeMul(&r.X, &e, &f)
+	edwards25519.FeMul(&r.Y, &g, &h)
+	edwards25519.FeMul(&r.T, &e, &h)
+	edwards25519.FeMul(&r.Z, &f, &g)
+
+	return &r
+}
+
+// MulByCofactor returns 8*p.
+func MulByCofactor(p *Point) *Point {
+	var r edwards25519.CompletedGroupElement
+
+	q := *p
+
+	for i := 0; i < 3; i++ {
+		q.Double(&r)
+		r.ToExtended(&q)
+	}
+
+	return &q
+}
+
+// orderMinusOne is l-1, the multiple of a point of order l which is its
+// negation.
+var orderMinusOne = ScalarFromBig(new(big.Int).Sub(Order, big.NewInt(1)))
+
+// InPrimeOrderSubgroup reports whether p is of order l, being neither the
+// identity nor of small or mixed order.
+func InPrimeOrderSubgroup(p *Point) bool {
+	return !IsIdentity(p) && IsIdentity(AddPoints(ScalarMult(orderMinusOne, p), p))
+}
+
+// IsIdentity reports whether p is the identity element.
+func IsIdentity(p *Point) bool {
+	return EncodePoint(p) == EncodePoint(Identity())
+}
+
+func EncodePoint(p *Point) [32]byte {
+	var b [32]byte
+	p.ToBytes(&b)
+
+	return b
+}
+
+func DecodePoint(b [32]byte) (*Point, error) {
+	var p Point
+	if !p.FromBytes(&b) {
+		return nil, errors.New("invalid curve point")
+	}
+
+	return &p, nil
+}
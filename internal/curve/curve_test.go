@@ -0,0 +1,46 @@
+// +build unit
+
+package curve
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddPoints(t *testing.T) {
+	a, b := ScalarFromUint(1234567), ScalarFromUint(7654321)
+
+	sum := AddPoints(ScalarMultBase(a), ScalarMultBase(b))
+	assert.Equal(t, EncodePoint(ScalarMultBase(ScalarAdd(a, b))), EncodePoint(sum))
+
+	assert.Equal(t, EncodePoint(ScalarMultBase(a)), EncodePoint(AddPoints(Identity(), ScalarMultBase(a))))
+	assert.True(t, IsIdentity(AddPoints(ScalarMultBase(a), ScalarMultBase(ScalarNeg(a)))))
+}
+
+func TestMulByCofactor(t *testing.T) {
+	a := ScalarFromUint(42)
+
+	assert.Equal(t, EncodePoint(ScalarMultBase(ScalarMul(a, ScalarFromUint(8)))), EncodePoint(MulByCofactor(ScalarMultBase(a))))
+}
+
+func TestInPrimeOrderSubgroup(t *testing.T) {
+	assert.True(t, InPrimeOrderSubgroup(ScalarMultBase(ScalarFromUint(42))))
+	assert.False(t, InPrimeOrderSubgroup(Identity()))
+
+	// The point of order 4 with y = 0, on its own and added to a point of
+	// order l.
+	torsion, err := DecodePoint([32]byte{})
+	assert.NoError(t, err)
+
+	assert.False(t, InPrimeOrderSubgroup(torsion))
+	assert.False(t, InPrimeOrderSubgroup(AddPoints(ScalarMultBase(ScalarFromUint(42)), torsion)))
+}
+
+func TestExpandSecret(t *testing.T) {
+	public, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, [32]byte(public), EncodePoint(ScalarMultBase(ExpandSecret(key))))
+}
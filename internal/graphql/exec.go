@@ -0,0 +1,600 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fastjson"
+)
+
+// ErrNoSuchField is returned by objects which have no field of the name
+// selected.
+var ErrNoSuchField = errors.New("no such field")
+
+// Object is a value of an object type, whose fields are resolved as they are
+// selected.
+//
+// Fields resolve to nil, strings, booleans, integers, floats, []string, an
+// Object or []Object. Fields resolving to no object must return nil rather
+// than a nil Object.
+type Object interface {
+	TypeName() string
+	Field(name string, args Args) (interface{}, error)
+}
+
+// Request is a GraphQL request, as posted over HTTP or started over a
+// websocket.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// DecodeRequest decodes a GraphQL request from its JSON encoding.
+func DecodeRequest(r io.Reader) (Request, error) {
+	var req Request
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	if err := dec.Decode(&req); err != nil {
+		return req, errors.Wrap(err, "failed to decode request")
+	}
+
+	return req, nil
+}
+
+// Query is a request whose document was parsed, and whose operation and
+// variables were resolved, such that it may be executed any number of times.
+type Query struct {
+	doc *Document
+	op  *Operation
+
+	vars map[string]interface{}
+}
+
+// Prepare parses the document of req, picking the operation to execute and
+// resolving its variables. Mutations, and operations selecting fields nested
+// deeper than maxDepth, are rejected.
+func Prepare(req Request, maxDepth int) (*Query, error) {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	var op *Operation
+
+	for _, candidate := range doc.Operations {
+		if req.OperationName == "" {
+			if len(doc.Operations) > 1 {
+				return nil, errors.New("operationName must be given for documents defining many operations")
+			}
+
+			op = candidate
+
+			break
+		}
+
+		if candidate.Name == req.OperationName {
+			op = candidate
+			break
+		}
+	}
+
+	if op == nil {
+		return nil, errors.Errorf("document does not define operation %q", req.OperationName)
+	}
+
+	if op.Type == OperationMutation {
+		return nil, errors.New("mutations are not supported")
+	}
+
+	q := &Query{doc: doc, op: op, vars: make(map[string]interface{})}
+
+	for _, def := range op.Variables {
+		value, given := req.Variables[def.Name]
+
+		switch {
+		case given && value != nil:
+			q.vars[def.Name] = normalize(value)
+		case def.HasDefault && !given:
+			q.vars[def.Name] = def.Default
+		case def.NonNull:
+			return nil, errors.Errorf("variable $%s of a non-null type must be given", def.Name)
+		case given:
+			q.vars[def.Name] = nil
+		}
+	}
+
+	depth, err := q.depth(op.Selections, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	if depth > maxDepth {
+		return nil, errors.Errorf("operation selects fields nested %d deep, past the maximum of %d", depth, maxDepth)
+	}
+
+	return q, nil
+}
+
+// depth returns how deep the fields selected by selections are nested,
+// checking that the fragments spread are defined and do not spread themselves.
+func (q *Query) depth(selections []Selection, spreading map[string]bool) (int, error) {
+	max := 0
+
+	for _, selection := range selections {
+		var (
+			depth int
+			err   error
+		)
+
+		switch {
+		case selection.Field != nil:
+			depth, err = q.depth(selection.Field.Selections, spreading)
+			depth++
+		case selection.Inline != nil:
+			depth, err = q.depth(selection.Inline.Selections, spreading)
+		default:
+			fragment, exists := q.doc.Fragments[selection.Spread]
+			if !exists {
+				return 0, errors.Errorf("fragment %q is not defined", selection.Spread)
+			}
+
+			if spreading[fragment.Name] {
+				return 0, errors.Errorf("fragment %q spreads itself", fragment.Name)
+			}
+
+			spreading[fragment.Name] = true
+			depth, err = q.depth(fragment.Selections, spreading)
+			delete(spreading, fragment.Name)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if depth > max {
+			max = depth
+		}
+	}
+
+	return max, nil
+}
+
+// Type returns whether the query is a query or a subscription.
+func (q *Query) Type() string {
+	return q.op.Type
+}
+
+// RootField returns the name and arguments of the only field selected by a
+// subscription, which determines the events it is subscribed to.
+func (q *Query) RootField() (string, Args, error) {
+	var fields []*Field
+
+	for _, selection := range q.op.Selections {
+		if selection.Field == nil || len(selection.Directives) > 0 {
+			return "", nil, errors.New("subscriptions must select a single field, without fragments or directives")
+		}
+
+		fields = append(fields, selection.Field)
+	}
+
+	if len(fields) != 1 {
+		return "", nil, errors.New("subscriptions must select a single field")
+	}
+
+	args, err := q.args(fields[0].Arguments)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fields[0].Name, args, nil
+}
+
+// Execute resolves the fields the query selects of root, responding with the
+// data resolved alongside the errors raised resolving fields, if any.
+func (q *Query) Execute(arena *fastjson.Arena, root Object) *fastjson.Value {
+	e := &executor{q: q, arena: arena}
+
+	res := arena.NewObject()
+	res.Set("data", e.selectionSet(root, q.op.Selections, nil))
+
+	if len(e.errs) > 0 {
+		res.Set("errors", e.errs.marshal(arena))
+	}
+
+	return res
+}
+
+// ErrorResponse is the response to a request which could not be executed at
+// all because of err.
+func ErrorResponse(arena *fastjson.Arena, err error) *fastjson.Value {
+	res := arena.NewObject()
+	res.Set("errors", fieldErrors{{msg: err.Error()}}.marshal(arena))
+
+	return res
+}
+
+type fieldError struct {
+	msg  string
+	path []interface{}
+}
+
+type fieldErrors []fieldError
+
+func (errs fieldErrors) marshal(arena *fastjson.Arena) *fastjson.Value {
+	list := arena.NewArray()
+
+	for i, err := range errs {
+		o := arena.NewObject()
+		o.Set("message", arena.NewString(err.msg))
+
+		if len(err.path) > 0 {
+			path := arena.NewArray()
+
+			for j, elem := range err.path {
+				if index, ok := elem.(int); ok {
+					path.SetArrayItem(j, arena.NewNumberInt(index))
+				} else {
+					path.SetArrayItem(j, arena.NewString(elem.(string)))
+				}
+			}
+
+			o.Set("path", path)
+		}
+
+		list.SetArrayItem(i, o)
+	}
+
+	return list
+}
+
+type executor struct {
+	q     *Query
+	arena *fastjson.Arena
+
+	errs fieldErrors
+}
+
+func (e *executor) fail(path []interface{}, err error) *fastjson.Value {
+	e.errs = append(e.errs, fieldError{msg: err.Error(), path: append([]interface{}{}, path...)})
+	return e.arena.NewNull()
+}
+
+func (e *executor) selectionSet(obj Object, selections []Selection, path []interface{}) *fastjson.Value {
+	var keys []string
+
+	fields := make(map[string][]*Field)
+
+	e.collect(obj.TypeName(), selections, path, &keys, fields)
+
+	o := e.arena.NewObject()
+
+	for _, key := range keys {
+		o.Set(key, e.field(obj, fields[key], append(path, key)))
+	}
+
+	return o
+}
+
+// collect gathers the fields selected of an object of type typ, grouped by
+// the key they are responded under, in the order they were selected.
+func (e *executor) collect(typ string, selections []Selection, path []interface{}, keys *[]string,
+	fields map[string][]*Field) {
+	for _, selection := range selections {
+		include, err := e.include(selection.Directives)
+		if err != nil {
+			e.fail(path, err)
+			continue
+		}
+
+		if !include {
+			continue
+		}
+
+		switch {
+		case selection.Field != nil:
+			key := selection.Field.Key()
+
+			if _, exists := fields[key]; !exists {
+				*keys = append(*keys, key)
+			}
+
+			fields[key] = append(fields[key], selection.Field)
+		case selection.Inline != nil:
+			if selection.Inline.On == "" || selection.Inline.On == typ {
+				e.collect(typ, selection.Inline.Selections, path, keys, fields)
+			}
+		default:
+			if fragment := e.q.doc.Fragments[selection.Spread]; fragment.On == typ {
+				e.collect(typ, fragment.Selections, path, keys, fields)
+			}
+		}
+	}
+}
+
+// include evaluates the @skip and @include directives of a selection.
+func (e *executor) include(directives []Argument) (bool, error) {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			return false, errors.Errorf("directive @%s is not supported", directive.Name)
+		}
+
+		args, err := e.q.args(directive.Value.([]Argument))
+		if err != nil {
+			return false, err
+		}
+
+		cond, given, err := args.Bool("if")
+		if err != nil {
+			return false, err
+		}
+
+		if !given {
+			return false, errors.Errorf("directive @%s requires argument \"if\"", directive.Name)
+		}
+
+		if cond == (directive.Name == "skip") {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (e *executor) field(obj Object, fields []*Field, path []interface{}) *fastjson.Value {
+	field := fields[0]
+
+	if field.Name == "__typename" {
+		return e.arena.NewString(obj.TypeName())
+	}
+
+	args, err := e.q.args(field.Arguments)
+	if err != nil {
+		return e.fail(path, err)
+	}
+
+	value, err := obj.Field(field.Name, args)
+	if err == ErrNoSuchField {
+		return e.fail(path, errors.Errorf("cannot query field %q on type %q", field.Name, obj.TypeName()))
+	}
+
+	if err != nil {
+		return e.fail(path, err)
+	}
+
+	var selections []Selection
+
+	for _, f := range fields {
+		selections = append(selections, f.Selections...)
+	}
+
+	return e.complete(field, value, selections, path)
+}
+
+func (e *executor) complete(field *Field, value interface{}, selections []Selection, // nolint:gocyclo
+	path []interface{}) *fastjson.Value {
+	switch v := value.(type) {
+	case nil:
+		return e.arena.NewNull()
+	case Object:
+		if len(selections) == 0 {
+			return e.fail(path, errors.Errorf("field %q of type %q must select subfields", field.Name, v.TypeName()))
+		}
+
+		return e.selectionSet(v, selections, path)
+	case []Object:
+		if len(selections) == 0 {
+			return e.fail(path, errors.Errorf("field %q of a list of objects must select subfields", field.Name))
+		}
+
+		list := e.arena.NewArray()
+
+		for i, item := range v {
+			list.SetArrayItem(i, e.selectionSet(item, selections, append(path, i)))
+		}
+
+		return list
+	}
+
+	if len(selections) > 0 {
+		return e.fail(path, errors.Errorf("field %q is of a scalar type, and has no subfields", field.Name))
+	}
+
+	switch v := value.(type) {
+	case string:
+		return e.arena.NewString(v)
+	case bool:
+		if v {
+			return e.arena.NewTrue()
+		}
+
+		return e.arena.NewFalse()
+	case int:
+		return e.arena.NewNumberInt(v)
+	case int64:
+		return e.arena.NewNumberString(strconv.FormatInt(v, 10))
+	case uint64:
+		return e.arena.NewNumberString(strconv.FormatUint(v, 10))
+	case float64:
+		return e.arena.NewNumberFloat64(v)
+	case []string:
+		list := e.arena.NewArray()
+
+		for i, item := range v {
+			list.SetArrayItem(i, e.arena.NewString(item))
+		}
+
+		return list
+	default:
+		return e.fail(path, errors.Errorf("field %q resolved to a value of unsupported type %T", field.Name, value))
+	}
+}
+
+// args resolves the values of arguments, substituting variables.
+func (q *Query) args(arguments []Argument) (Args, error) {
+	args := make(Args, len(arguments))
+
+	for _, arg := range arguments {
+		value, given, err := q.resolve(arg.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %q", arg.Name)
+		}
+
+		if given {
+			args[arg.Name] = value
+		}
+	}
+
+	return args, nil
+}
+
+// resolve substitutes the variables referred to within a value literal,
+// reporting whether a variable standing for the value itself was given.
+func (q *Query) resolve(value interface{}) (interface{}, bool, error) {
+	switch v := value.(type) {
+	case Variable:
+		defined := false
+
+		for _, def := range q.op.Variables {
+			if def.Name == string(v) {
+				defined = true
+				break
+			}
+		}
+
+		if !defined {
+			return nil, false, errors.Errorf("variable $%s is not defined", v)
+		}
+
+		resolved, given := q.vars[string(v)]
+
+		return resolved, given, nil
+	case Enum:
+		return string(v), true, nil
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+
+		for _, item := range v {
+			resolved, _, err := q.resolve(item)
+			if err != nil {
+				return nil, false, err
+			}
+
+			list = append(list, resolved)
+		}
+
+		return list, true, nil
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			resolved, given, err := q.resolve(item)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if given {
+				object[key] = resolved
+			}
+		}
+
+		return object, true, nil
+	}
+
+	return value, true, nil
+}
+
+// normalize converts the numbers of a variable decoded from JSON into the
+// integers and floats value literals are parsed into.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+
+		return string(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = normalize(v[key])
+		}
+	}
+
+	return value
+}
+
+// Args are the arguments given to a field, with variables substituted.
+// Arguments which were not given, or whose variable was not given, are absent.
+type Args map[string]interface{}
+
+// String returns the string argument name, and whether it was given.
+func (a Args) String(name string) (string, bool, error) {
+	value, exists := a[name]
+	if !exists || value == nil {
+		return "", false, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", false, errors.Errorf("argument %q must be a string", name)
+	}
+
+	return s, true, nil
+}
+
+// Bool returns the boolean argument name, and whether it was given.
+func (a Args) Bool(name string) (bool, bool, error) {
+	value, exists := a[name]
+	if !exists || value == nil {
+		return false, false, nil
+	}
+
+	b, ok := value.(bool)
+	if !ok {
+		return false, false, errors.Errorf("argument %q must be a boolean", name)
+	}
+
+	return b, true, nil
+}
+
+// Uint64 returns the non-negative integer argument name, and whether it was
+// given. Integers past the range of GraphQL's Int may be given as decimal
+// strings.
+func (a Args) Uint64(name string) (uint64, bool, error) {
+	value, exists := a[name]
+	if !exists || value == nil {
+		return 0, false, nil
+	}
+
+	switch v := value.(type) {
+	case int64:
+		if v >= 0 {
+			return uint64(v), true, nil
+		}
+	case string:
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return n, true, nil
+		}
+	}
+
+	return 0, false, errors.Errorf("argument %q must be a non-negative integer", name)
+}
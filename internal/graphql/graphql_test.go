@@ -0,0 +1,153 @@
+// +build unit
+
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fastjson"
+)
+
+type testObject struct {
+	typ    string
+	fields map[string]interface{}
+}
+
+func (o *testObject) TypeName() string {
+	return o.typ
+}
+
+func (o *testObject) Field(name string, args Args) (interface{}, error) {
+	if name == "echo" {
+		s, _, err := args.String("value")
+		return s, err
+	}
+
+	value, exists := o.fields[name]
+	if !exists {
+		return nil, ErrNoSuchField
+	}
+
+	return value, nil
+}
+
+func testRoot() Object {
+	sender := &testObject{typ: "Account", fields: map[string]interface{}{
+		"id":      "ab",
+		"balance": uint64(1 << 62),
+	}}
+
+	tx := &testObject{typ: "Transaction", fields: map[string]interface{}{
+		"id":     "cd",
+		"tag":    1,
+		"sender": sender,
+	}}
+
+	return &testObject{typ: "Query", fields: map[string]interface{}{
+		"transaction":  tx,
+		"transactions": []Object{tx, tx},
+		"missing":      nil,
+	}}
+}
+
+func execute(t *testing.T, req Request) string {
+	q, err := Prepare(req, 8)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+
+	return string(q.Execute(new(fastjson.Arena), testRoot()).MarshalTo(nil))
+}
+
+func TestExecuteNested(t *testing.T) {
+	res := execute(t, Request{Query: `{
+		transaction { id sender { id balance } }
+		txs: transactions { tag }
+		missing { id }
+	}`})
+
+	assert.Equal(t, `{"data":{"transaction":{"id":"cd","sender":{"id":"ab","balance":4611686018427387904}},`+
+		`"txs":[{"tag":1},{"tag":1}],"missing":null}}`, res)
+}
+
+func TestExecuteFragmentsAndDirectives(t *testing.T) {
+	res := execute(t, Request{
+		Query: `query Q($skip: Boolean = false) {
+			transaction {
+				...fields
+				... on Account { balance }
+				sender @skip(if: $skip) { id }
+				__typename
+			}
+		}
+
+		fragment fields on Transaction { id tag }`,
+		Variables: map[string]interface{}{"skip": true},
+	})
+
+	assert.Equal(t, `{"data":{"transaction":{"id":"cd","tag":1,"__typename":"Transaction"}}}`, res)
+}
+
+func TestExecuteArguments(t *testing.T) {
+	res := execute(t, Request{
+		Query:     `query($v: String!) { a: echo(value: "lit\n") b: echo(value: $v) }`,
+		Variables: map[string]interface{}{"v": "var"},
+	})
+
+	assert.Equal(t, `{"data":{"a":"lit\n","b":"var"}}`, res)
+}
+
+func TestExecuteFieldErrors(t *testing.T) {
+	res := execute(t, Request{Query: `{ transaction { id nope } }`})
+
+	assert.Equal(t, `{"data":{"transaction":{"id":"cd","nope":null}},"errors":[{"message":`+
+		`"cannot query field \"nope\" on type \"Transaction\"","path":["transaction","nope"]}]}`, res)
+}
+
+func TestPrepareErrors(t *testing.T) {
+	tests := []struct {
+		req Request
+		err string
+	}{
+		{Request{Query: `{ id`}, "unexpected end of document"},
+		{Request{Query: `mutation { id }`}, "mutations are not supported"},
+		{Request{Query: `query A { id } query B { id }`}, "operationName must be given"},
+		{Request{Query: `query A { id }`, OperationName: "B"}, `does not define operation "B"`},
+		{Request{Query: `query($v: Int!) { id }`}, "must be given"},
+		{Request{Query: `{ ...f } fragment f on Query { ...f }`}, "spreads itself"},
+		{Request{Query: `{ a { b { c { d { e { f { g { h { i } } } } } } } } }`}, "past the maximum of 8"},
+	}
+
+	for _, tc := range tests {
+		_, err := Prepare(tc.req, 8)
+		if assert.Error(t, err, tc.req.Query) {
+			assert.True(t, strings.Contains(err.Error(), tc.err), err.Error())
+		}
+	}
+}
+
+func TestRootField(t *testing.T) {
+	q, err := Prepare(Request{Query: `subscription { applied(tag: 1) { id } }`}, 8)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	name, args, err := q.RootField()
+	assert.NoError(t, err)
+	assert.Equal(t, "applied", name)
+
+	tag, given, err := args.Uint64("tag")
+	assert.NoError(t, err)
+	assert.True(t, given)
+	assert.Equal(t, uint64(1), tag)
+
+	q, err = Prepare(Request{Query: `subscription { a { id } b { id } }`}, 8)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, _, err = q.RootField()
+	assert.Error(t, err)
+}
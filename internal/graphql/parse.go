@@ -0,0 +1,838 @@
+// Package graphql implements the subset of GraphQL served by the API of a
+// node. Queries and subscriptions may select fields with aliases, arguments,
+// variables, fragments and the @skip and @include directives, and are executed
+// against objects which resolve their own fields. Mutations, validation of the
+// types of variables and introspection beyond __typename are not supported.
+package graphql
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	OperationQuery        = "query"
+	OperationMutation     = "mutation"
+	OperationSubscription = "subscription"
+)
+
+// Document is a parsed GraphQL document.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query, mutation or subscription of a document.
+type Operation struct {
+	Type       string
+	Name       string
+	Variables  []VariableDefinition
+	Selections []Selection
+}
+
+// VariableDefinition declares a variable of an operation.
+type VariableDefinition struct {
+	Name    string
+	NonNull bool
+
+	// Default is the value literal the variable defaults to, should
+	// HasDefault be set.
+	Default    interface{}
+	HasDefault bool
+}
+
+// Fragment is a named or inline fragment. On is empty for inline fragments
+// without a type condition.
+type Fragment struct {
+	Name       string
+	On         string
+	Selections []Selection
+}
+
+// Selection is either a field, the spread of a named fragment or an inline
+// fragment.
+type Selection struct {
+	Field  *Field
+	Spread string
+	Inline *Fragment
+
+	Directives []Argument
+}
+
+// Field selects the field Name of an object, under the key Alias should it be
+// set.
+type Field struct {
+	Alias      string
+	Name       string
+	Arguments  []Argument
+	Selections []Selection
+}
+
+// Key returns the key the field is responded under.
+func (f *Field) Key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+
+	return f.Name
+}
+
+// Argument is a named value literal, given to a field or a directive. The
+// arguments of directives are kept in Value as []Argument.
+type Argument struct {
+	Name  string
+	Value interface{}
+}
+
+// Variable is a reference to a variable within a value literal.
+type Variable string
+
+// Enum is an enum value within a value literal.
+type Enum string
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+// Parse parses the GraphQL document src.
+func Parse(src string) (*Document, error) {
+	p := &parser{src: src}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.isPunct("{"):
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			doc.Operations = append(doc.Operations, &Operation{Type: OperationQuery, Selections: selections})
+		case p.isName("fragment"):
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				return nil, errors.Errorf("fragment %q is defined more than once", fragment.Name)
+			}
+
+			doc.Fragments[fragment.Name] = fragment
+		case p.isName(OperationQuery), p.isName(OperationMutation), p.isName(OperationSubscription):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+
+			doc.Operations = append(doc.Operations, op)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, errors.New("document does not define any operation")
+	}
+
+	return doc, nil
+}
+
+func (p *parser) operation() (*Operation, error) {
+	op := &Operation{Type: p.tok.value}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isPunct("(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		for !p.isPunct(")") {
+			def, err := p.variableDefinition()
+			if err != nil {
+				return nil, err
+			}
+
+			op.Variables = append(op.Variables, def)
+		}
+
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	op.Selections = selections
+
+	return op, nil
+}
+
+func (p *parser) variableDefinition() (VariableDefinition, error) {
+	var def VariableDefinition
+
+	if err := p.expectPunct("$"); err != nil {
+		return def, err
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return def, err
+	}
+
+	def.Name = name
+
+	if err := p.expectPunct(":"); err != nil {
+		return def, err
+	}
+
+	if def.NonNull, err = p.typeRef(); err != nil {
+		return def, err
+	}
+
+	if p.isPunct("=") {
+		if err := p.advance(); err != nil {
+			return def, err
+		}
+
+		if def.Default, err = p.value(true); err != nil {
+			return def, err
+		}
+
+		def.HasDefault = true
+	}
+
+	return def, nil
+}
+
+// typeRef skips over a type reference, returning whether it is non-null.
+func (p *parser) typeRef() (bool, error) {
+	if p.isPunct("[") {
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+
+		if _, err := p.typeRef(); err != nil {
+			return false, err
+		}
+
+		if err := p.expectPunct("]"); err != nil {
+			return false, err
+		}
+	} else if _, err := p.name(); err != nil {
+		return false, err
+	}
+
+	if p.isPunct("!") {
+		return true, p.advance()
+	}
+
+	return false, nil
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "on" {
+		return nil, errors.New("fragments may not be named \"on\"")
+	}
+
+	if !p.isName("on") {
+		return nil, p.unexpected()
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	on, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fragment{Name: name, On: on, Selections: selections}, nil
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []Selection
+
+	for !p.isPunct("}") {
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+
+		selections = append(selections, selection)
+	}
+
+	if len(selections) == 0 {
+		return nil, errors.Errorf("%s: selection sets may not be empty", p.position(p.tok.pos))
+	}
+
+	return selections, p.advance()
+}
+
+func (p *parser) selection() (Selection, error) {
+	var (
+		selection Selection
+		err       error
+	)
+
+	if p.isPunct("...") {
+		if err := p.advance(); err != nil {
+			return selection, err
+		}
+
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			selection.Spread = p.tok.value
+
+			if err := p.advance(); err != nil {
+				return selection, err
+			}
+
+			selection.Directives, err = p.directives()
+
+			return selection, err
+		}
+
+		fragment := new(Fragment)
+
+		if p.isName("on") {
+			if err := p.advance(); err != nil {
+				return selection, err
+			}
+
+			if fragment.On, err = p.name(); err != nil {
+				return selection, err
+			}
+		}
+
+		if selection.Directives, err = p.directives(); err != nil {
+			return selection, err
+		}
+
+		if fragment.Selections, err = p.selectionSet(); err != nil {
+			return selection, err
+		}
+
+		selection.Inline = fragment
+
+		return selection, nil
+	}
+
+	field := new(Field)
+
+	if field.Name, err = p.name(); err != nil {
+		return selection, err
+	}
+
+	if p.isPunct(":") {
+		if err := p.advance(); err != nil {
+			return selection, err
+		}
+
+		field.Alias = field.Name
+
+		if field.Name, err = p.name(); err != nil {
+			return selection, err
+		}
+	}
+
+	if field.Arguments, err = p.arguments(false); err != nil {
+		return selection, err
+	}
+
+	if selection.Directives, err = p.directives(); err != nil {
+		return selection, err
+	}
+
+	if p.isPunct("{") {
+		if field.Selections, err = p.selectionSet(); err != nil {
+			return selection, err
+		}
+	}
+
+	selection.Field = field
+
+	return selection, nil
+}
+
+func (p *parser) arguments(constant bool) ([]Argument, error) {
+	if !p.isPunct("(") {
+		return nil, nil
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var args []Argument
+
+	for !p.isPunct(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.value(constant)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, Argument{Name: name, Value: value})
+	}
+
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]Argument, error) {
+	var directives []Argument
+
+	for p.isPunct("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := p.arguments(false)
+		if err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, Argument{Name: name, Value: args})
+	}
+
+	return directives, nil
+}
+
+// value parses a value literal. Variables may not be referred to within
+// constant values.
+func (p *parser) value(constant bool) (interface{}, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokenInt:
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s: integer %s is out of range", p.position(tok.pos), tok.value)
+		}
+
+		return n, p.advance()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s: float %s is out of range", p.position(tok.pos), tok.value)
+		}
+
+		return f, p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		var value interface{}
+
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = Enum(tok.value)
+		}
+
+		return value, p.advance()
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, errors.Errorf("%s: variables may not be referred to here", p.position(tok.pos))
+			}
+
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+
+			return Variable(name), nil
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			list := []interface{}{}
+
+			for !p.isPunct("]") {
+				item, err := p.value(constant)
+				if err != nil {
+					return nil, err
+				}
+
+				list = append(list, item)
+			}
+
+			return list, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			object := map[string]interface{}{}
+
+			for !p.isPunct("}") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+
+				if object[name], err = p.value(constant); err != nil {
+					return nil, err
+				}
+			}
+
+			return object, p.advance()
+		}
+	}
+
+	return nil, p.unexpected()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+
+	name := p.tok.value
+
+	return name, p.advance()
+}
+
+func (p *parser) expectPunct(punct string) error {
+	if !p.isPunct(punct) {
+		return p.unexpected()
+	}
+
+	return p.advance()
+}
+
+func (p *parser) isPunct(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == punct
+}
+
+func (p *parser) isName(name string) bool {
+	return p.tok.kind == tokenName && p.tok.value == name
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return errors.Errorf("%s: unexpected end of document", p.position(p.tok.pos))
+	}
+
+	return errors.Errorf("%s: unexpected %q", p.position(p.tok.pos), p.tok.value)
+}
+
+// position renders the line and column of the byte at pos of the document.
+func (p *parser) position(pos int) string {
+	line := 1 + strings.Count(p.src[:pos], "\n")
+	column := 1 + utf8.RuneCountInString(p.src[strings.LastIndexByte(p.src[:pos], '\n')+1:pos])
+
+	return "syntax error at line " + strconv.Itoa(line) + ", column " + strconv.Itoa(column)
+}
+
+// advance lexes the next token of the document, skipping over whitespace,
+// commas and comments.
+func (p *parser) advance() error { // nolint:gocognit
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '\r' {
+				p.pos++
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(p.src[p.pos:], "\ufeff") {
+			p.pos += len("\ufeff")
+			continue
+		}
+
+		break
+	}
+
+	start := p.pos
+
+	if p.pos == len(p.src) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return nil
+	}
+
+	c := p.src[p.pos]
+
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokenPunct, value: "...", pos: start}
+	case strings.IndexByte("!$&():=@[]{|}", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenPunct, value: string(c), pos: start}
+	case c == '_' || isLetter(c):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+
+		p.tok = token{kind: tokenName, value: p.src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		return p.number()
+	case c == '"':
+		return p.string()
+	default:
+		return errors.Errorf("%s: unexpected character %q", p.position(start), c)
+	}
+
+	return nil
+}
+
+func (p *parser) number() error {
+	start := p.pos
+	kind := tokenInt
+
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+
+	digits := func() int {
+		n := 0
+
+		for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+			p.pos++
+			n++
+		}
+
+		return n
+	}
+
+	if digits() == 0 {
+		return errors.Errorf("%s: invalid number", p.position(start))
+	}
+
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		p.pos++
+		kind = tokenFloat
+
+		if digits() == 0 {
+			return errors.Errorf("%s: invalid number", p.position(start))
+		}
+	}
+
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		p.pos++
+		kind = tokenFloat
+
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+
+		if digits() == 0 {
+			return errors.Errorf("%s: invalid number", p.position(start))
+		}
+	}
+
+	if p.pos < len(p.src) && (p.src[p.pos] == '_' || isLetter(p.src[p.pos]) || p.src[p.pos] == '.') {
+		return errors.Errorf("%s: invalid number", p.position(start))
+	}
+
+	p.tok = token{kind: kind, value: p.src[start:p.pos], pos: start}
+
+	return nil
+}
+
+func (p *parser) string() error {
+	start := p.pos
+
+	// Block strings are taken as is, save for escaped triple quotes.
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		end := p.pos + 3
+
+		for {
+			i := strings.Index(p.src[end:], `"""`)
+			if i < 0 {
+				return errors.Errorf("%s: unterminated string", p.position(start))
+			}
+
+			end += i
+
+			if p.src[end-1] != '\\' {
+				break
+			}
+
+			end += 3
+		}
+
+		value := strings.Replace(p.src[p.pos+3:end], `\"""`, `"""`, -1)
+
+		p.pos = end + 3
+		p.tok = token{kind: tokenString, value: value, pos: start}
+
+		return nil
+	}
+
+	p.pos++
+
+	var b strings.Builder
+
+	for {
+		if p.pos >= len(p.src) || p.src[p.pos] == '\n' || p.src[p.pos] == '\r' {
+			return errors.Errorf("%s: unterminated string", p.position(start))
+		}
+
+		c := p.src[p.pos]
+
+		if c == '"' {
+			p.pos++
+			break
+		}
+
+		if c != '\\' {
+			b.WriteByte(c)
+			p.pos++
+
+			continue
+		}
+
+		if p.pos+1 >= len(p.src) {
+			return errors.Errorf("%s: unterminated string", p.position(start))
+		}
+
+		escaped := p.src[p.pos+1]
+		p.pos += 2
+
+		switch escaped {
+		case '"', '\\', '/':
+			b.WriteByte(escaped)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if p.pos+4 > len(p.src) {
+				return errors.Errorf("%s: invalid unicode escape", p.position(p.pos))
+			}
+
+			r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 16)
+			if err != nil {
+				return errors.Errorf("%s: invalid unicode escape", p.position(p.pos))
+			}
+
+			b.WriteRune(rune(r))
+			p.pos += 4
+		default:
+			return errors.Errorf("%s: invalid escape \\%c", p.position(p.pos-2), escaped)
+		}
+	}
+
+	p.tok = token{kind: tokenString, value: b.String(), pos: start}
+
+	return nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
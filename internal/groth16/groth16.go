@@ -0,0 +1,258 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package groth16 verifies Groth16 zk-SNARK proofs over BN254, the curve of
+// Ethereum's alt_bn128 precompiles, as produced by snarkjs and gnark.
+//
+// Points are encoded as for the alt_bn128 precompiles of EIP-196 and
+// EIP-197: G1 points are 64 bytes being X | Y, and G2 points are 128 bytes
+// being X.imag | X.real | Y.imag | Y.real, each coordinate a 32-byte
+// big-endian integer. The point at infinity is encoded as zeros. Scalars
+// are 32-byte big-endian integers smaller than the order of the curve.
+//
+// A verifying key is encoded as alpha (G1) | beta (G2) | gamma (G2) |
+// delta (G2) | IC_0 .. IC_n (G1), n being the number of public inputs. A proof
+// is encoded as A (G1) | B (G2) | C (G1).
+package groth16
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/pkg/errors"
+)
+
+const (
+	SizeG1     = 64
+	SizeG2     = 128
+	SizeScalar = 32
+
+	// SizeProof is the size of an encoded proof.
+	SizeProof = SizeG1 + SizeG2 + SizeG1
+)
+
+// ErrInvalidProof is returned when a proof does not verify.
+var ErrInvalidProof = errors.New("groth16: invalid proof")
+
+type VerifyingKey struct {
+	Alpha *bn254.G1Affine
+	Beta  *bn254.G2Affine
+	Gamma *bn254.G2Affine
+	Delta *bn254.G2Affine
+
+	// IC holds one point more than there are public inputs.
+	IC []*bn254.G1Affine
+}
+
+type Proof struct {
+	A *bn254.G1Affine
+	B *bn254.G2Affine
+	C *bn254.G1Affine
+}
+
+// NumInputs returns the number of public inputs of the verifying key buf
+// encodes, without decoding it.
+func NumInputs(buf []byte) (int, error) {
+	n := len(buf) - SizeG1 - 3*SizeG2
+
+	if n < SizeG1 || n%SizeG1 != 0 {
+		return 0, errors.Errorf("groth16: verifying key of %d bytes is malformed", len(buf))
+	}
+
+	return n/SizeG1 - 1, nil
+}
+
+func UnmarshalVerifyingKey(buf []byte) (*VerifyingKey, error) {
+	n, err := NumInputs(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	vk := &VerifyingKey{IC: make([]*bn254.G1Affine, n+1)}
+
+	if vk.Alpha, err = unmarshalG1(buf[:SizeG1]); err != nil {
+		return nil, errors.Wrap(err, "bad alpha")
+	}
+
+	buf = buf[SizeG1:]
+
+	for _, g := range []**bn254.G2Affine{&vk.Beta, &vk.Gamma, &vk.Delta} {
+		if *g, err = unmarshalG2(buf[:SizeG2]); err != nil {
+			return nil, errors.Wrap(err, "bad verifying key")
+		}
+
+		buf = buf[SizeG2:]
+	}
+
+	for i := range vk.IC {
+		if vk.IC[i], err = unmarshalG1(buf[:SizeG1]); err != nil {
+			return nil, errors.Wrapf(err, "bad IC %d", i)
+		}
+
+		buf = buf[SizeG1:]
+	}
+
+	return vk, nil
+}
+
+func (vk *VerifyingKey) Marshal() []byte {
+	buf := make([]byte, 0, SizeG1+3*SizeG2+len(vk.IC)*SizeG1)
+
+	buf = append(buf, marshalG1(vk.Alpha)...)
+
+	for _, g := range []*bn254.G2Affine{vk.Beta, vk.Gamma, vk.Delta} {
+		buf = append(buf, marshalG2(g)...)
+	}
+
+	for _, ic := range vk.IC {
+		buf = append(buf, marshalG1(ic)...)
+	}
+
+	return buf
+}
+
+func UnmarshalProof(buf []byte) (*Proof, error) {
+	if len(buf) != SizeProof {
+		return nil, errors.Errorf("groth16: proof must be %d bytes, but got %d bytes", SizeProof, len(buf))
+	}
+
+	var (
+		proof Proof
+		err   error
+	)
+
+	if proof.A, err = unmarshalG1(buf[:SizeG1]); err != nil {
+		return nil, errors.Wrap(err, "bad A")
+	}
+
+	if proof.B, err = unmarshalG2(buf[SizeG1 : SizeG1+SizeG2]); err != nil {
+		return nil, errors.Wrap(err, "bad B")
+	}
+
+	if proof.C, err = unmarshalG1(buf[SizeG1+SizeG2:]); err != nil {
+		return nil, errors.Wrap(err, "bad C")
+	}
+
+	return &proof, nil
+}
+
+func (p *Proof) Marshal() []byte {
+	buf := make([]byte, 0, SizeProof)
+
+	buf = append(buf, marshalG1(p.A)...)
+	buf = append(buf, marshalG2(p.B)...)
+	buf = append(buf, marshalG1(p.C)...)
+
+	return buf
+}
+
+// UnmarshalInputs decodes concatenated 32-byte public inputs.
+func UnmarshalInputs(buf []byte) ([]*big.Int, error) {
+	if len(buf)%SizeScalar != 0 {
+		return nil, errors.Errorf("groth16: public inputs must be a multiple of %d bytes", SizeScalar)
+	}
+
+	order := fr.Modulus()
+	inputs := make([]*big.Int, len(buf)/SizeScalar)
+
+	for i := range inputs {
+		inputs[i] = new(big.Int).SetBytes(buf[i*SizeScalar : (i+1)*SizeScalar])
+
+		if inputs[i].Cmp(order) >= 0 {
+			return nil, errors.Errorf("groth16: public input %d is not reduced", i)
+		}
+	}
+
+	return inputs, nil
+}
+
+// Verify checks that proof proves knowledge of a witness to the circuit of vk
+// given the public inputs, by checking that
+//
+//	e(A, B) = e(alpha, beta) * e(IC_0 + sum(inputs_i * IC_i), gamma) * e(C, delta).
+func Verify(vk *VerifyingKey, proof *Proof, inputs []*big.Int) error {
+	if len(inputs)+1 != len(vk.IC) {
+		return errors.Errorf("groth16: expected %d public inputs, but got %d", len(vk.IC)-1, len(inputs))
+	}
+
+	// x = IC_0 + sum(inputs_i * IC_i)
+	var x bn254.G1Jac
+	x.FromAffine(vk.IC[0])
+
+	for i, input := range inputs {
+		var term bn254.G1Jac
+		term.FromAffine(vk.IC[i+1])
+
+		x.AddAssign(term.ScalarMultiplication(&term, input))
+	}
+
+	var xAffine, negA bn254.G1Affine
+	xAffine.FromJacobian(&x)
+	negA.Neg(proof.A)
+
+	// Check that e(-A, B) * e(alpha, beta) * e(x, gamma) * e(C, delta) = 1.
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{negA, *vk.Alpha, xAffine, *proof.C},
+		[]bn254.G2Affine{*proof.B, *vk.Beta, *vk.Gamma, *vk.Delta},
+	)
+	if err != nil {
+		return errors.Wrap(err, "groth16: pairing failed")
+	}
+
+	if !ok {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
+
+func marshalG1(g *bn254.G1Affine) []byte {
+	buf := g.RawBytes()
+	return buf[:]
+}
+
+func marshalG2(g *bn254.G2Affine) []byte {
+	buf := g.RawBytes()
+	return buf[:]
+}
+
+// unmarshalG1 decodes a canonically encoded G1 point.
+func unmarshalG1(buf []byte) (*bn254.G1Affine, error) {
+	g := new(bn254.G1Affine)
+
+	if _, err := g.SetBytes(buf); err != nil || !bytes.Equal(marshalG1(g), buf) {
+		return nil, errors.New("groth16: invalid G1 point")
+	}
+
+	return g, nil
+}
+
+// unmarshalG2 decodes a canonically encoded G2 point, which is checked to lie
+// within the subgroup of prime order.
+func unmarshalG2(buf []byte) (*bn254.G2Affine, error) {
+	g := new(bn254.G2Affine)
+
+	if _, err := g.SetBytes(buf); err != nil || !bytes.Equal(marshalG2(g), buf) {
+		return nil, errors.New("groth16: invalid G2 point")
+	}
+
+	return g, nil
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package groth16
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func g1(k *big.Int) *bn254.G1Affine {
+	_, _, base, _ := bn254.Generators()
+	return new(bn254.G1Affine).ScalarMultiplication(&base, k)
+}
+
+func g2(k *big.Int) *bn254.G2Affine {
+	_, _, _, base := bn254.Generators()
+	return new(bn254.G2Affine).ScalarMultiplication(&base, k)
+}
+
+func randomScalar(t testing.TB) *big.Int {
+	k, err := rand.Int(rand.Reader, fr.Modulus())
+	assert.NoError(t, err)
+
+	return k
+}
+
+// setup returns a verifying key for a circuit of n public inputs alongside its
+// trapdoor, which lets simulate forge proofs for any inputs.
+func setup(t testing.TB, n int) (*VerifyingKey, []*big.Int) {
+	trapdoor := make([]*big.Int, 4+n+1)
+
+	for i := range trapdoor {
+		trapdoor[i] = randomScalar(t)
+	}
+
+	vk := &VerifyingKey{
+		Alpha: g1(trapdoor[0]),
+		Beta:  g2(trapdoor[1]),
+		Gamma: g2(trapdoor[2]),
+		Delta: g2(trapdoor[3]),
+	}
+
+	for _, k := range trapdoor[4:] {
+		vk.IC = append(vk.IC, g1(k))
+	}
+
+	return vk, trapdoor
+}
+
+// simulate forges a proof for inputs by picking A and B at random, and solving
+// for C such that a*b = alpha*beta + x*gamma + c*delta.
+func simulate(t testing.TB, trapdoor []*big.Int, inputs []*big.Int) *Proof {
+	a, b := randomScalar(t), randomScalar(t)
+
+	x := new(big.Int).Set(trapdoor[4])
+	for i, input := range inputs {
+		x.Add(x, new(big.Int).Mul(input, trapdoor[5+i]))
+	}
+
+	c := new(big.Int).Mul(a, b)
+	c.Sub(c, new(big.Int).Mul(trapdoor[0], trapdoor[1]))
+	c.Sub(c, new(big.Int).Mul(x, trapdoor[2]))
+	c.Mul(c, new(big.Int).ModInverse(trapdoor[3], fr.Modulus()))
+	c.Mod(c, fr.Modulus())
+
+	return &Proof{A: g1(a), B: g2(b), C: g1(c)}
+}
+
+func TestVerify(t *testing.T) {
+	vk, trapdoor := setup(t, 2)
+
+	inputs := []*big.Int{big.NewInt(3), big.NewInt(5)}
+	proof := simulate(t, trapdoor, inputs)
+
+	assert.NoError(t, Verify(vk, proof, inputs))
+
+	assert.Equal(t, ErrInvalidProof, Verify(vk, proof, []*big.Int{big.NewInt(3), big.NewInt(6)}))
+	assert.Error(t, Verify(vk, proof, inputs[:1]))
+
+	other, _ := setup(t, 2)
+	assert.Equal(t, ErrInvalidProof, Verify(other, proof, inputs))
+}
+
+func TestMarshal(t *testing.T) {
+	vk, trapdoor := setup(t, 1)
+
+	inputs := []*big.Int{big.NewInt(42)}
+	proof := simulate(t, trapdoor, inputs)
+
+	vkBuf := vk.Marshal()
+
+	n, err := NumInputs(vkBuf)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	decodedVK, err := UnmarshalVerifyingKey(vkBuf)
+	assert.NoError(t, err)
+	assert.Equal(t, vkBuf, decodedVK.Marshal())
+
+	proofBuf := proof.Marshal()
+	assert.Len(t, proofBuf, SizeProof)
+
+	decodedProof, err := UnmarshalProof(proofBuf)
+	assert.NoError(t, err)
+
+	input := make([]byte, SizeScalar)
+	input[SizeScalar-1] = 42
+
+	decodedInputs, err := UnmarshalInputs(input)
+	assert.NoError(t, err)
+
+	assert.NoError(t, Verify(decodedVK, decodedProof, decodedInputs))
+
+	// Malformed encodings are rejected.
+	_, err = UnmarshalVerifyingKey(vkBuf[1:])
+	assert.Error(t, err)
+
+	_, err = UnmarshalProof(proofBuf[1:])
+	assert.Error(t, err)
+
+	tampered := append([]byte(nil), proofBuf...)
+	tampered[SizeG1-1] ^= 1
+
+	_, err = UnmarshalProof(tampered)
+	assert.Error(t, err)
+
+	_, err = UnmarshalInputs(fr.Modulus().Bytes())
+	assert.Error(t, err)
+
+}
+
+// TestEncoding checks that points are encoded as for Ethereum's alt_bn128
+// precompiles, which snarkjs and gnark export verifying keys and proofs for.
+func TestEncoding(t *testing.T) {
+	one := big.NewInt(1)
+
+	buf := marshalG1(g1(one))
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001"+
+		"0000000000000000000000000000000000000000000000000000000000000002", hex.EncodeToString(buf))
+
+	// The G2 generator of EIP-197, with the imaginary part of each coordinate
+	// first.
+	buf = marshalG2(g2(one))
+	assert.Equal(t, "198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c2"+
+		"1800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed"+
+		"090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b"+
+		"12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa", hex.EncodeToString(buf))
+
+	// The point at infinity is encoded as zeros.
+	assert.Equal(t, make([]byte, SizeG1), marshalG1(g1(new(big.Int))))
+
+	decoded, err := unmarshalG1(make([]byte, SizeG1))
+	assert.NoError(t, err)
+	assert.True(t, decoded.IsInfinity())
+
+	// Coordinates must be reduced: the generator is (1, 2), and so is (1+p, 2)
+	// once reduced.
+	unreduced := marshalG1(g1(one))
+	copy(unreduced[:32], new(big.Int).Add(one, fp.Modulus()).Bytes())
+
+	_, err = unmarshalG1(unreduced)
+	assert.Error(t, err)
+}
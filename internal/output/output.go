@@ -0,0 +1,300 @@
+// Package output renders the results of commands as text, JSON, YAML,
+// aligned tables, or CSV, such that scripts may parse them reliably.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+// Format is the format results are rendered in.
+type Format string
+
+const (
+	// Text renders objects as lines of fields, and tables aligned. Commands
+	// typically render objects as a sentence of their own instead.
+	Text Format = "text"
+	// JSON renders objects as JSON objects, and tables as arrays of them.
+	JSON Format = "json"
+	// YAML renders objects as YAML mappings, and tables as sequences of them.
+	YAML Format = "yaml"
+	// Table renders objects and tables aligned, with a header of columns.
+	Table Format = "table"
+	// CSV renders objects and tables as CSV, with a header of columns.
+	CSV Format = "csv"
+)
+
+// Formats lists all formats.
+var Formats = []Format{Text, JSON, YAML, Table, CSV}
+
+// ParseFormat returns the format named s.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+
+	return "", errors.Errorf("unknown output format %q", s)
+}
+
+// Field is a named value of an object. Values are strings, booleans,
+// integers, or byte slices and arrays, which are hex-encoded.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Object is a single result, being a list of fields.
+type Object []Field
+
+// F returns the field of value named name.
+func F(name string, value interface{}) Field {
+	return Field{Name: name, Value: value}
+}
+
+// List is a list of results, each being a row of values, one per column.
+// Column names are snake_case, and upper-cased in headers of tables.
+type List struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// NewList returns an empty list of the given columns.
+func NewList(columns ...string) *List {
+	return &List{Columns: columns}
+}
+
+// Add appends a row of values to t, which must have a value per column.
+func (t *List) Add(values ...interface{}) {
+	t.Rows = append(t.Rows, values)
+}
+
+// WriteObject renders o to w.
+func (f Format) WriteObject(w io.Writer, o Object) error {
+	t := &List{Columns: make([]string, len(o)), Rows: [][]interface{}{make([]interface{}, len(o))}}
+
+	for i, field := range o {
+		t.Columns[i], t.Rows[0][i] = field.Name, field.Value
+	}
+
+	switch f {
+	case Text:
+		var buf bytes.Buffer
+
+		for _, field := range o {
+			name := strings.Replace(field.Name, "_", " ", -1)
+			fmt.Fprintf(&buf, "%s%s: %s\n", strings.ToUpper(name[:1]), name[1:], text(field.Value))
+		}
+
+		_, err := w.Write(buf.Bytes())
+
+		return err
+	case JSON:
+		return writeJSON(w, t, true)
+	case YAML:
+		return writeYAML(w, t, true)
+	default:
+		return f.WriteList(w, t)
+	}
+}
+
+// WriteList renders t to w. Text renders lists as Table does.
+func (f Format) WriteList(w io.Writer, t *List) error {
+	switch f {
+	case Text, Table:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+		headers := make([]string, len(t.Columns))
+		for i, column := range t.Columns {
+			headers[i] = strings.ToUpper(strings.Replace(column, "_", " ", -1))
+		}
+
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+		for _, row := range t.Rows {
+			values := make([]string, len(row))
+			for i, value := range row {
+				values[i] = text(value)
+			}
+
+			fmt.Fprintln(tw, strings.Join(values, "\t"))
+		}
+
+		return tw.Flush()
+	case JSON:
+		return writeJSON(w, t, false)
+	case YAML:
+		return writeYAML(w, t, false)
+	case CSV:
+		cw := csv.NewWriter(w)
+
+		if err := cw.Write(t.Columns); err != nil {
+			return err
+		}
+
+		for _, row := range t.Rows {
+			values := make([]string, len(row))
+			for i, value := range row {
+				values[i] = text(value)
+			}
+
+			if err := cw.Write(values); err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+
+		return cw.Error()
+	default:
+		return errors.Errorf("unknown output format %q", string(f))
+	}
+}
+
+// writeJSON renders the rows of t as an indented array of objects, or only
+// its first row should object be set.
+func writeJSON(w io.Writer, t *List, object bool) error {
+	var buf bytes.Buffer
+
+	if !object {
+		buf.WriteByte('[')
+	}
+
+	for i, row := range t.Rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.WriteByte('{')
+
+		for j, value := range row {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeJSONValue(&buf, t.Columns[j]); err != nil {
+				return err
+			}
+
+			buf.WriteByte(':')
+
+			if err := writeJSONValue(&buf, value); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+	}
+
+	if !object {
+		buf.WriteByte(']')
+	}
+
+	var out bytes.Buffer
+
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+
+	out.WriteByte('\n')
+
+	_, err := w.Write(out.Bytes())
+
+	return err
+}
+
+func writeJSONValue(buf *bytes.Buffer, value interface{}) error {
+	if s, ok := hexValue(value); ok {
+		value = s
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %v", value)
+	}
+
+	buf.Write(encoded)
+
+	return nil
+}
+
+// writeYAML renders the rows of t as a sequence of mappings, or only its
+// first row should object be set. Strings are double-quoted, such that they
+// are never mistaken for numbers or booleans.
+func writeYAML(w io.Writer, t *List, object bool) error {
+	var buf bytes.Buffer
+
+	if !object && len(t.Rows) == 0 {
+		buf.WriteString("[]\n")
+	}
+
+	for _, row := range t.Rows {
+		for j, value := range row {
+			switch {
+			case object:
+			case j == 0:
+				buf.WriteString("- ")
+			default:
+				buf.WriteString("  ")
+			}
+
+			buf.WriteString(t.Columns[j])
+			buf.WriteString(": ")
+
+			if err := writeJSONValue(&buf, value); err != nil {
+				return err
+			}
+
+			buf.WriteByte('\n')
+		}
+
+		if object {
+			break
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// text renders value as plain text.
+func text(value interface{}) string {
+	if s, ok := hexValue(value); ok {
+		return s
+	}
+
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// hexValue hex-encodes value should it be a byte slice or array.
+func hexValue(value interface{}) (string, bool) {
+	if buf, ok := value.([]byte); ok {
+		return hex.EncodeToString(buf), true
+	}
+
+	v := reflect.ValueOf(value)
+
+	if v.Kind() != reflect.Array || v.Type().Elem().Kind() != reflect.Uint8 {
+		return "", false
+	}
+
+	buf := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(buf), v)
+
+	return hex.EncodeToString(buf), true
+}
@@ -0,0 +1,80 @@
+// +build unit
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testList() *List {
+	l := NewList("name", "address", "balance")
+	l.Add("alice", [2]byte{0xab, 0xcd}, uint64(10))
+	l.Add("bob, jr.", []byte{0xef}, uint64(0))
+
+	return l
+}
+
+func TestWriteList(t *testing.T) {
+	tests := map[Format]string{
+		Table: "NAME      ADDRESS  BALANCE\nalice     abcd     10\nbob, jr.  ef       0\n",
+		CSV:   "name,address,balance\nalice,abcd,10\n\"bob, jr.\",ef,0\n",
+		JSON: `[
+  {
+    "name": "alice",
+    "address": "abcd",
+    "balance": 10
+  },
+  {
+    "name": "bob, jr.",
+    "address": "ef",
+    "balance": 0
+  }
+]
+`,
+		YAML: "- name: \"alice\"\n  address: \"abcd\"\n  balance: 10\n" +
+			"- name: \"bob, jr.\"\n  address: \"ef\"\n  balance: 0\n",
+	}
+
+	for f, expected := range tests {
+		var buf bytes.Buffer
+
+		assert.NoError(t, f.WriteList(&buf, testList()), f)
+		assert.Equal(t, expected, buf.String(), f)
+	}
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, YAML.WriteList(&buf, NewList("id")))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestWriteObject(t *testing.T) {
+	o := Object{F("fee", uint64(2)), F("gas_limit", 3), F("failure", "")}
+
+	tests := map[Format]string{
+		Text:  "Fee: 2\nGas limit: 3\nFailure: \n",
+		Table: "FEE  GAS LIMIT  FAILURE\n2    3          \n",
+		CSV:   "fee,gas_limit,failure\n2,3,\n",
+		JSON:  "{\n  \"fee\": 2,\n  \"gas_limit\": 3,\n  \"failure\": \"\"\n}\n",
+		YAML:  "fee: 2\ngas_limit: 3\nfailure: \"\"\n",
+	}
+
+	for f, expected := range tests {
+		var buf bytes.Buffer
+
+		assert.NoError(t, f.WriteObject(&buf, o), f)
+		assert.Equal(t, expected, buf.String(), f)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, YAML, f)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/perlin-network/noise/skademlia"
 	"github.com/perlin-network/wavelet/avl"
 	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/events"
 	"github.com/perlin-network/wavelet/internal/backoff"
 	"github.com/perlin-network/wavelet/internal/cuckoo"
 	"github.com/perlin-network/wavelet/internal/filebuffer"
@@ -40,6 +41,7 @@ import (
 	"github.com/perlin-network/wavelet/store"
 	"github.com/perlin-network/wavelet/sys"
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
 )
@@ -47,6 +49,11 @@ import (
 var (
 	ErrMissingTx          = errors.New("missing transaction")
 	ErrTxInvalidSignature = errors.New("bad tx signature")
+	ErrTxStampTooWeak     = errors.New("tx stamp does not meet the required difficulty")
+	ErrTxNotReplaceable   = errors.New("tx may not replace the transaction of the same sender and nonce")
+	ErrTxReplaced         = errors.New("tx was replaced by a transaction paying a higher fee")
+	ErrTxNonceUsed        = errors.New("tx nonce was used by a transaction applied before")
+	ErrPruningDisabled    = errors.New("pruning is disabled")
 )
 
 type Ledger struct {
@@ -70,24 +77,85 @@ type Ledger struct {
 	filePool    *filebuffer.Pool
 	syncManager *SyncManager
 
+	// stateChunks are the chunks of the latest finalized state served to
+	// peers syncing their state from scratch.
+	stateChunks *stateChunksCache
+
+	// reputation scores peers by the violations they commit, and bans them.
+	reputation *Reputation
+
+	// discovery discovers peers beyond the ones our node is launched with.
+	discovery       *Discovery
+	cancelDiscovery context.CancelFunc
+
 	stopWG   sync.WaitGroup
 	cancelGC context.CancelFunc
 
+	// archival is whether to keep the state of the ledger as of every block
+	// finalized.
+	archival bool
+
+	// pruner prunes the data kept of past blocks, should pruning be enabled.
+	pruner       *Pruner
+	cancelPruner context.CancelFunc
+
 	transactionFilterLock sync.RWMutex
 	transactionFilter     *cuckoo.Filter
 
 	queryPeerBlockCache  *PeerBlockLRU
 	queryBlockValidCache map[BlockID]struct{}
 
+	// queryBlockInvalidCache keeps the block proposals which failed
+	// validation, alongside the peers penalized for proposing them.
+	queryBlockInvalidCache map[BlockID]map[AccountID]struct{}
+
 	queryWorkerPool *worker.Pool
 
+	// compression is the encoding our node compresses gossip with, by which
+	// it accepts compressed gossip.
+	compression string
+
+	// beacon is whether to contribute to the randomness beacon of blocks
+	// should our node be a validator.
+	beacon bool
+
+	// admissionHooks screen transactions before our node admits them.
+	admissionHooks []namedAdmissionHook
+
+	// alertWebhooks are the URLs alerts are posted to.
+	alertWebhooks []string
+
 	collapseResultsLogger *CollapseResultsLogger
+
+	// lastFinalized is when our node last finalized a block, or started
+	// should it not have.
+	lastFinalized time.Time
+
+	// roundStart is when our node started preferring a block for the round
+	// being finalized in Unix nanoseconds, or 0 should it not prefer one.
+	roundStart atomic.Int64
 }
 
 type config struct {
 	GCDisabled  bool
 	Genesis     *string
 	MaxMemoryMB uint64
+	Beacon      bool
+
+	AdmissionHooks []string
+	AlertWebhooks  []string
+
+	PruneBlocks     uint64
+	CompactInterval time.Duration
+
+	Archival bool
+
+	PeerPolicy *PeerPolicy
+
+	Seeds    []string
+	MaxPeers int
+
+	Compression string
 }
 
 type Option func(cfg *config)
@@ -111,13 +179,106 @@ func WithMaxMemoryMB(n uint64) Option {
 	}
 }
 
+// WithBeaconContributions has our node contribute to the randomness beacon
+// after every finalized block carrying transactions, should it be a
+// validator.
+func WithBeaconContributions() Option {
+	return func(cfg *config) {
+		cfg.Beacon = true
+	}
+}
+
+// WithAdmissionHooks enables the admission hooks registered under the given
+// names, which screen transactions in the given order before our node admits
+// them.
+func WithAdmissionHooks(names ...string) Option {
+	return func(cfg *config) {
+		cfg.AdmissionHooks = append(cfg.AdmissionHooks, names...)
+	}
+}
+
+// WithAlertWebhooks has our node post alerts, such as on observing conflicting
+// transactions, as JSON to the given URLs.
+func WithAlertWebhooks(urls ...string) Option {
+	return func(cfg *config) {
+		cfg.AlertWebhooks = append(cfg.AlertWebhooks, urls...)
+	}
+}
+
+// WithPruning has our node only retain the data it keeps for its own API,
+// such as the state diffs of transactions, of the given number of latest
+// blocks, and compact its database every compactInterval should it be
+// non-zero.
+func WithPruning(blocks uint64, compactInterval time.Duration) Option {
+	return func(cfg *config) {
+		cfg.PruneBlocks = blocks
+		cfg.CompactInterval = compactInterval
+	}
+}
+
+// WithArchival has our node keep the state of the ledger as of every block
+// it finalizes, such that it may be queried, at the cost of disk space. The
+// state is not garbage collected as a result.
+func WithArchival() Option {
+	return func(cfg *config) {
+		cfg.Archival = true
+	}
+}
+
+// WithPeerPolicy has our node score and ban peers according to policy, rather
+// than DefaultPeerPolicy.
+func WithPeerPolicy(policy PeerPolicy) Option {
+	return func(cfg *config) {
+		cfg.PeerPolicy = &policy
+	}
+}
+
+// WithSeeds has our node discover peers from the DNS seeds seeds.
+func WithSeeds(seeds ...string) Option {
+	return func(cfg *config) {
+		cfg.Seeds = seeds
+	}
+}
+
+// WithMaxPeers has our node connect to at most max peers, rather than
+// DefaultMaxPeers.
+func WithMaxPeers(max int) Option {
+	return func(cfg *config) {
+		cfg.MaxPeers = max
+	}
+}
+
+// WithCompression has our node compress the transactions it gossips with the
+// encoding compression should peers accept it, rather than with snappy. Nodes
+// short on CPU may disable compressing gossip with CompressionNone.
+func WithCompression(compression string) Option {
+	return func(cfg *config) {
+		cfg.Compression = compression
+	}
+}
+
 func NewLedger(kv store.KV, client *skademlia.Client, opts ...Option) (*Ledger, error) {
-	var cfg config
+	cfg := config{Compression: CompressionSnappy}
 
 	for _, opt := range opts {
 		opt(&cfg)
 	}
 
+	if err := ValidCompression(cfg.Compression); err != nil {
+		return nil, err
+	}
+
+	admissionHooks := make([]namedAdmissionHook, 0, len(cfg.AdmissionHooks))
+
+	for _, name := range cfg.AdmissionHooks {
+		hook, err := LookupAdmissionHook(name)
+		if err != nil {
+			return nil, err
+		}
+
+		admissionHooks = append(admissionHooks, namedAdmissionHook{name: name, hook: hook})
+	}
+
 	metrics := NewMetrics(context.TODO())
 	indexer := radix.NewIndexer()
 	accounts := NewAccounts(kv)
@@ -150,12 +311,27 @@ func NewLedger(kv store.KV, client *skademlia.Client, opts ...Option) (*Ledger,
 	transactions := NewTransactions(*block)
 	transactions.BatchMarkFinalized(LoadFinalizedTransactionIDs(accounts.tree)...)
 
-	gossiper := NewGossiper(context.TODO(), client, metrics)
+	gossiper := NewGossiper(context.TODO(), client, metrics, cfg.Compression)
 	finalizer := NewSnowball()
 
 	filePool := filebuffer.NewPool(sys.SyncPooledFileSize, "")
 
-	syncManager := NewSyncManager(client, accounts, blocks, filePool)
+	policy := DefaultPeerPolicy()
+	if cfg.PeerPolicy != nil {
+		policy = *cfg.PeerPolicy
+	}
+
+	reputation := NewReputation(policy)
+
+	// Banned peers are disconnected from, their requests being rejected
+	// until their ban expires.
+	reputation.onBan = func(address string) {
+		go func() {
+			_ = client.DisconnectByAddress(address)
+		}()
+	}
+
+	syncManager := NewSyncManager(client, accounts, blocks, filePool, reputation)
 
 	ledger := &Ledger{
 		client:  client,
@@ -172,15 +348,36 @@ func NewLedger(kv store.KV, client *skademlia.Client, opts ...Option) (*Ledger,
 
 		filePool:    filePool,
 		syncManager: syncManager,
+		stateChunks: newStateChunksCache(filePool),
+		reputation:  reputation,
+		discovery:   NewDiscovery(kv, client, reputation, cfg.Seeds, cfg.MaxPeers),
 
 		transactionFilter: cuckoo.NewFilter(),
 
 		queryPeerBlockCache:  NewPeerBlockLRU(16),
 		queryBlockValidCache: make(map[BlockID]struct{}),
 
+		queryBlockInvalidCache: make(map[BlockID]map[AccountID]struct{}),
+
 		queryWorkerPool: worker.NewWorkerPool(),
 
 		collapseResultsLogger: NewCollapseResultsLogger(),
+
+		beacon:      cfg.Beacon,
+		compression: cfg.Compression,
+
+		admissionHooks: admissionHooks,
+		alertWebhooks:  cfg.AlertWebhooks,
+
+		archival: cfg.Archival,
+
+		lastFinalized: time.Now(),
+	}
+
+	metrics.observe(ledger)
+
+	if committed, err := blocks.committed(accounts.tree.Checksum()); err == nil {
+		ledger.archive(*committed)
 	}
 
 	var kickstart sync.Once
@@ -224,11 +421,14 @@ func NewLedger(kv store.KV, client *skademlia.Client, opts ...Option) (*Ledger,
 				Msg("Failed to save preferred block to database")
 		}
 
+		ledger.archive(block)
+
 		ledger.consensusStop = make(chan struct{})
 		ledger.PerformConsensus()
 	})
 
-	if !cfg.GCDisabled {
+	// Archival nodes keep every version of the state.
+	if !cfg.GCDisabled && !cfg.Archival {
 		ctx, cancel := context.WithCancel(context.Background())
 
 		ledger.stopWG.Add(1)
@@ -238,6 +438,26 @@ func NewLedger(kv store.KV, client *skademlia.Client, opts ...Option) (*Ledger,
 		ledger.cancelGC = cancel
 	}
 
+	if cfg.PruneBlocks > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		ledger.pruner = NewPruner(kv, cfg.PruneBlocks, cfg.CompactInterval)
+
+		ledger.stopWG.Add(1)
+
+		go ledger.pruner.Run(ctx, &ledger.stopWG)
+
+		ledger.cancelPruner = cancel
+	}
+
+	discoveryCtx, cancelDiscovery := context.WithCancel(context.Background())
+
+	ledger.stopWG.Add(1)
+
+	go ledger.discovery.Run(discoveryCtx, &ledger.stopWG)
+
+	ledger.cancelDiscovery = cancelDiscovery
+
 	stallDetector := stall.NewStallDetector(stall.Config{
 		MaxMemoryMB: cfg.MaxMemoryMB,
 	}, stall.Delegate{
@@ -274,6 +494,12 @@ func (l *Ledger) Close() {
 		l.cancelGC()
 	}
 
+	if l.cancelPruner != nil {
+		l.cancelPruner()
+	}
+
+	l.cancelDiscovery()
+
 	l.queryWorkerPool.Stop()
 
 	l.stallDetector.Stop()
@@ -286,7 +512,8 @@ func (l *Ledger) Close() {
 // AddTransaction adds a transaction to the ledger and adds it's id to a probabilistic
 // data structure used to sync transactions.
 func (l *Ledger) AddTransaction(txs ...Transaction) {
-	l.transactions.BatchAdd(txs)
+	l.reportConflicts(l.transactions.BatchAdd(txs))
+
 	l.transactionFilterLock.Lock()
 
 	for _, tx := range txs {
@@ -297,6 +524,139 @@ func (l *Ledger) AddTransaction(txs ...Transaction) {
 	l.transactionFilterLock.Unlock()
 }
 
+// ContractLogs returns up to limit logs of the smart contract with ID id,
+// starting from the log at index offset, alongside the number of logs kept
+// of it.
+func (l *Ledger) ContractLogs(id AccountID, offset, limit uint64) ([]ContractLog, uint64, error) {
+	n, err := ReadContractLogsLen(l.db, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logs, err := LoadContractLogs(l.db, id, offset, limit)
+
+	return logs, n, err
+}
+
+// SearchContractLogs returns the index of the first log of the smart
+// contract with ID id emitted at or after the block with index block.
+func (l *Ledger) SearchContractLogs(id AccountID, block uint64) (uint64, error) {
+	return SearchContractLogs(l.db, id, block)
+}
+
+// Round returns the round of the block with index index, should our node have
+// finalized it.
+func (l *Ledger) Round(index uint64) (Round, error) {
+	return LoadRound(l.db, index)
+}
+
+// Rounds returns up to limit rounds our node finalized, starting from the
+// round of the block with index from and going back.
+func (l *Ledger) Rounds(from, limit uint64) ([]Round, error) {
+	return LoadRounds(l.db, from, limit)
+}
+
+// ProveAccount proves the balances of the account with ID id against the
+// Merkle root of the latest block whose state was committed, returning the
+// proof alongside the block.
+func (l *Ledger) ProveAccount(id AccountID) (AccountProof, *Block, error) {
+	snapshot := l.accounts.Snapshot()
+
+	block, err := l.blocks.committed(snapshot.Checksum())
+	if err != nil {
+		return AccountProof{}, nil, err
+	}
+
+	proof, err := ProveAccount(snapshot, id)
+	if err != nil {
+		return proof, nil, err
+	}
+
+	return proof, block, nil
+}
+
+// ProveContractStorage proves the value of key of the storage of the smart
+// contract id against the Merkle root of the latest block whose state our
+// node committed, returning the proof alongside the block.
+func (l *Ledger) ProveContractStorage(id AccountID, key []byte) (ContractStorageProof, *Block, error) {
+	snapshot := l.accounts.Snapshot()
+
+	block, err := l.blocks.committed(snapshot.Checksum())
+	if err != nil {
+		return ContractStorageProof{}, nil, err
+	}
+
+	proof, err := ProveContractStorage(snapshot, id, key)
+	if err != nil {
+		return proof, nil, err
+	}
+
+	return proof, block, nil
+}
+
+// PruningStatus reports on the pruning of the data our node keeps of past
+// blocks.
+func (l *Ledger) PruningStatus() PruningStatus {
+	if l.pruner == nil {
+		return PruningStatus{}
+	}
+
+	return l.pruner.Status()
+}
+
+// Prune prunes the data of the past blocks falling out of those retained as
+// of the latest block, and compacts our database, rather than waiting for the
+// next block to be finalized or the next compaction to be due.
+func (l *Ledger) Prune() (PruningStatus, error) {
+	if l.pruner == nil {
+		return PruningStatus{}, ErrPruningDisabled
+	}
+
+	if err := l.pruner.Prune(l.blocks.Latest().Index); err != nil {
+		return l.pruner.Status(), err
+	}
+
+	if err := l.pruner.Compact(); err != nil {
+		return l.pruner.Status(), errors.Wrap(err, "failed to compact database")
+	}
+
+	return l.pruner.Status(), nil
+}
+
+// ProveAccountAt is ProveAccount, proving the balances of the account as of
+// the block with index index. Only archival nodes keep the state of blocks
+// other than the latest.
+func (l *Ledger) ProveAccountAt(id AccountID, index uint64) (AccountProof, *Block, error) {
+	snapshot, block, err := l.SnapshotAt(index)
+	if err != nil {
+		return AccountProof{}, nil, err
+	}
+
+	proof, err := ProveAccount(snapshot, id)
+	if err != nil {
+		return proof, nil, err
+	}
+
+	return proof, block, nil
+}
+
+// StampDifficulty returns the proof-of-work difficulty stamped transactions
+// must meet for the ledger to admit them, which rises with the number of
+// transactions pending finalization.
+func (l *Ledger) StampDifficulty() int {
+	return StampDifficultyForLoad(l.transactions.PendingLen())
+}
+
+// CheckStamp returns ErrTxStampTooWeak should tx be stamped with a proof of
+// work not meeting the difficulty the ledger currently requires.
+func (l *Ledger) CheckStamp(tx Transaction) error {
+	if tx.Stamp != 0 && tx.StampDifficulty() < l.StampDifficulty() {
+		return ErrTxStampTooWeak
+	}
+
+	return nil
+}
+
 // Find searches through complete transaction and account indices for a specified
 // query string. All indices that queried are in the form of tries. It is safe
 // to call this method concurrently.
@@ -349,12 +709,28 @@ func (l *Ledger) Protocol() *Protocol {
 	return &Protocol{ledger: l}
 }
 
+// Metrics returns the metrics the ledger records, along with those other
+// modules of the node register.
+func (l *Ledger) Metrics() *Metrics {
+	return l.metrics
+}
+
 // Finalizer returns the Snowball finalizer which finalizes the contents of individual
 // blocks.
 func (l *Ledger) Finalizer() *Snowball {
 	return l.finalizer
 }
 
+// Reputation returns the scores of peers, by which the ledger bans them.
+func (l *Ledger) Reputation() *Reputation {
+	return l.reputation
+}
+
+// Discovery returns the discovery of peers of our node.
+func (l *Ledger) Discovery() *Discovery {
+	return l.discovery
+}
+
 // Blocks returns the block manager for the ledger.
 func (l *Ledger) Blocks() *Blocks {
 	return l.blocks
@@ -365,6 +741,12 @@ func (l *Ledger) Transactions() *Transactions {
 	return l.transactions
 }
 
+// TransactionDiff returns the changes made to accounts by the transaction with
+// ID id, should this node have applied it.
+func (l *Ledger) TransactionDiff(id TransactionID) (TransactionDiff, error) {
+	return LoadTransactionDiff(l.db, id)
+}
+
 // Restart restart wavelet process by means of stall detector (approach is platform dependent)
 func (l *Ledger) Restart() error {
 	return l.stallDetector.TryRestart()
@@ -505,7 +887,7 @@ func (l *Ledger) SyncTransactions() { // nolint:gocognit
 					transactions := make([]Transaction, 0, len(txResponse.Transactions))
 
 					for _, txBody := range txResponse.Transactions {
-						tx, err := UnmarshalTransaction(bytes.NewReader(txBody))
+						tx, err := ParseTransaction(txBody)
 						if err != nil {
 							logger.Error().Err(err).Msg("failed to unmarshal synced transaction")
 							continue
@@ -610,7 +992,7 @@ func (l *Ledger) PullMissingTransactions() {
 				response.txs = make([]Transaction, 0, len(batch.Transactions))
 
 				for _, buf := range batch.Transactions {
-					tx, err := UnmarshalTransaction(bytes.NewReader(buf))
+					tx, err := ParseTransaction(buf)
 					if err != nil {
 						logger.Error().
 							Err(err).
@@ -674,9 +1056,14 @@ func (l *Ledger) PullMissingTransactions() {
 // FinalizeBlocks continuously attempts to finalize blocks.
 func (l *Ledger) FinalizeBlocks() {
 	defer l.consensus.Done()
+	defer l.roundStart.Store(0)
 
 	b := &backoff.Backoff{Min: 0 * time.Second, Max: 200 * time.Millisecond, Factor: 1.25, Jitter: true}
 
+	// When the node started preferring a block for the round, which is
+	// zero while it has yet to prefer one.
+	var roundStart time.Time
+
 	for {
 		select {
 		case <-l.consensusStop:
@@ -692,7 +1079,7 @@ func (l *Ledger) FinalizeBlocks() {
 			proposedBlock := l.proposeBlock()
 
 			if proposedBlock != nil {
-				logger := log.Consensus("proposal")
+				logger := log.Consensus(events.EventProposal)
 				logger.Debug().
 					Hex("block_id", proposedBlock.ID[:]).
 					Uint64("block_index", proposedBlock.Index).
@@ -717,8 +1104,21 @@ func (l *Ledger) FinalizeBlocks() {
 				}
 			}
 		} else {
+			if roundStart.IsZero() {
+				roundStart = time.Now()
+				l.roundStart.Store(roundStart.UnixNano())
+			}
+
 			if decided {
-				l.finalize(*preferred.Value().(*Block))
+				block := *preferred.Value().(*Block)
+
+				l.finalize(block, roundStart)
+
+				if l.blocks.Latest().Index == block.Index {
+					l.metrics.consensusLatency.UpdateSince(roundStart)
+					roundStart = time.Time{}
+					l.roundStart.Store(0)
+				}
 			} else {
 				l.query()
 			}
@@ -753,10 +1153,14 @@ func (l *Ledger) proposeBlock() *Block {
 	return &proposed
 }
 
-func (l *Ledger) finalize(block Block) {
+// finalize applies and commits the decided block, the node having started
+// preferring a block for the round at roundStart.
+func (l *Ledger) finalize(block Block, roundStart time.Time) {
 	current := l.blocks.Latest()
 
-	logger := log.Consensus("finalized")
+	logger := log.Consensus(events.EventFinalized)
+
+	traced := newFinalizationTrace(&block, roundStart)
 
 	results, err := l.collapseTransactions(block.Index, current, block.Transactions, true)
 	if err != nil {
@@ -779,6 +1183,8 @@ func (l *Ledger) finalize(block Block) {
 		return
 	}
 
+	traced.markApplied()
+
 	pruned := l.transactions.ReshufflePending(block)
 	l.transactionFilterLock.Lock()
 	for _, id := range pruned {
@@ -804,19 +1210,74 @@ func (l *Ledger) finalize(block Block) {
 		return
 	}
 
+	traced.markStored()
+	traced.record(results)
+
+	l.archive(block)
+
+	diffs := results.diffs
+
+	// The changes made by the block itself are keyed by the block's ID.
+	if len(results.blockDiff.Accounts) > 0 {
+		results.blockDiff.ID = block.ID
+		diffs = append(diffs, results.blockDiff)
+	}
+
+	if err = StoreTransactionDiffs(l.db, block.Index, diffs); err != nil {
+		logger := log.Node()
+		logger.Error().
+			Err(err).
+			Msg("Failed to save the state diffs of applied transactions to our database")
+	}
+
+	if l.pruner != nil {
+		if err = l.pruner.Prune(block.Index); err != nil {
+			logger := log.Node()
+			logger.Error().
+				Err(err).
+				Msg("Failed to prune the data of past blocks from our database")
+		}
+	}
+
+	if err = StoreContractLogs(l.db, results.logs); err != nil {
+		logger := log.Node()
+		logger.Error().
+			Err(err).
+			Msg("Failed to save the logs emitted by smart contracts to our database")
+	}
+
+	round := NewRound(block, results.appliedCount, results.rejectedCount, l.lastFinalized, time.Now())
+	l.lastFinalized = round.FinalizedAt
+
+	if err = StoreRound(l.db, round); err != nil {
+		logger := log.Node()
+		logger.Error().
+			Err(err).
+			Msg("Failed to save the round of the finalized block to our database")
+	}
+
 	l.metrics.acceptedTX.Mark(int64(results.appliedCount))
 	l.metrics.finalizedBlocks.Mark(1)
 
 	l.LogChanges(results)
+	logValidatorChanges(block.Index, results.diffs)
 
 	// Reset sampler(s).
 	l.finalizer.Reset()
 
+	if l.beacon {
+		l.contributeToBeacon(&block, results)
+	}
+
 	// Reset querying-related cache(s).
 	for id := range l.queryBlockValidCache {
 		delete(l.queryBlockValidCache, id)
 	}
 
+	for id := range l.queryBlockInvalidCache {
+		delete(l.queryBlockInvalidCache, id)
+	}
+
 	logger.Info().
 		Int("num_applied_tx", results.appliedCount).
 		Int("num_rejected_tx", results.rejectedCount).
@@ -826,18 +1287,68 @@ func (l *Ledger) finalize(block Block) {
 		Hex("old_block_id", current.ID[:]).
 		Hex("new_block_id", block.ID[:]).
 		Msg("Finalized block.")
+
+	logRound(round)
 }
 
-func (l *Ledger) query() {
-	snowballK := conf.GetSnowballK()
+// logRound logs round, streaming it to explorers.
+func logRound(round Round) {
+	logger := log.Consensus(events.EventRound)
+	logger.Info().
+		Uint64("index", round.Index).
+		Hex("id", round.ID[:]).
+		Hex("merkle_root", round.Merkle[:]).
+		Hex("start_id", round.StartID[:]).
+		Hex("end_id", round.EndID[:]).
+		Uint32("num_tx", round.NumTx).
+		Uint32("num_applied_tx", round.NumApplied).
+		Uint32("num_rejected_tx", round.NumRejected).
+		Str("started_at", round.StartedAt.Format(time.RFC3339Nano)).
+		Str("finalized_at", round.FinalizedAt.Format(time.RFC3339Nano)).
+		Str("duration", round.Duration().String()).
+		Msg("Finalized round.")
+}
+
+// contributeToBeacon submits our contribution to the randomness beacon of the
+// block succeeding block. Blocks carrying only beacon transactions are not
+// contributed to, such that contributions do not yield blocks endlessly.
+func (l *Ledger) contributeToBeacon(block *Block, results *collapseResults) {
+	var active bool
+
+	for _, tx := range results.applied {
+		if tx.Tag != sys.TagBeacon {
+			active = true
+			break
+		}
+	}
+
+	keys := l.client.Keys()
+
+	if stake, _ := ReadAccountStake(results.snapshot, keys.PublicKey()); !active || stake < sys.MinimumStake {
+		return
+	}
 
-	peers, err := SelectPeers(l.client.ClosestPeers(), snowballK)
+	previous, exists := ReadBeacon(results.snapshot, block.Index)
+	if !exists {
+		return
+	}
+
+	payload, err := NewBeaconContribution(keys.PrivateKey(), block.Index, previous).Marshal()
 	if err != nil {
 		return
 	}
 
+	l.AddTransaction(NewTransaction(keys, uint64(time.Now().UnixNano()), block.Index, sys.TagBeacon, payload))
+}
+
+func (l *Ledger) query() {
 	current := l.blocks.Latest()
 
+	peers, proof, err := l.samplePeers(current)
+	if err != nil {
+		return
+	}
+
 	type response struct {
 		vote finalizationVote
 	}
@@ -855,7 +1366,10 @@ func (l *Ledger) query() {
 				responseChan <- response
 			}()
 
-			req := &QueryRequest{BlockIndex: current.Index + 1}
+			req := &QueryRequest{
+				BlockIndex:  current.Index + 1,
+				SampleProof: proof,
+			}
 
 			if cached != nil {
 				req.CacheBlockId = make([]byte, SizeBlockID)
@@ -872,6 +1386,8 @@ func (l *Ledger) query() {
 
 				p := &peer.Peer{}
 
+				start := time.Now()
+
 				res, err := client.Query(ctx, req, grpc.Peer(p))
 				if err != nil {
 					logger := log.Node()
@@ -896,12 +1412,20 @@ func (l *Ledger) query() {
 
 				response.vote.voter = voter
 
+				l.reputation.Observe(voter, time.Since(start))
+
 				if res.CacheValid {
 					return
 				}
 
+				// Peers yet to prefer a block respond with none.
+				if len(res.GetBlock()) == 0 {
+					return
+				}
+
 				block, err := UnmarshalBlock(bytes.NewReader(res.GetBlock()))
 				if err != nil {
+					l.reputation.Report(voter, ViolationInvalidBlock)
 					return
 				}
 
@@ -964,6 +1488,17 @@ type collapseResults struct {
 	appliedCount  int
 	rejectedCount int
 
+	// Changes made by each applied transaction, in order of application.
+	diffs []TransactionDiff
+
+	// Changes made by the block itself rather than any transaction, being the
+	// rewards distributed to validators and reward withdrawals paid out.
+	blockDiff TransactionDiff
+
+	// Logs emitted by smart contracts while applying transactions, in order
+	// of emission.
+	logs []ContractLog
+
 	snapshot *avl.Tree
 	ctx      *CollapseContext
 }
@@ -993,12 +1528,13 @@ func (l *Ledger) collapseTransactions(
 }
 
 // LogChanges logs all changes made to an AVL tree state snapshot for the purposes
-// of logging out changes to account state to Wavelet's HTTP API.
+// of logging out changes to account state to Wavelet's HTTP API, alongside the logs
+// emitted by smart contracts.
 func (l *Ledger) LogChanges(c *collapseResults) {
-	balanceLogger := log.Accounts("balance_updated")
-	gasBalanceLogger := log.Accounts("gas_balance_updated")
-	stakeLogger := log.Accounts("stake_updated")
-	rewardLogger := log.Accounts("reward_updated")
+	balanceLogger := log.Accounts(events.EventBalanceUpdated)
+	gasBalanceLogger := log.Accounts(events.EventGasBalanceUpdated)
+	stakeLogger := log.Accounts(events.EventStakeUpdated)
+	rewardLogger := log.Accounts(events.EventRewardUpdated)
 
 	for _, id := range c.ctx.accountIDs {
 		if bal, ok := c.ctx.balances[id]; ok {
@@ -1029,6 +1565,91 @@ func (l *Ledger) LogChanges(c *collapseResults) {
 				Msg("")
 		}
 	}
+
+	contractLogger := log.Contracts(events.EventContractLog)
+
+	for _, l := range c.logs {
+		contractLogger.Log().
+			Hex("contract_id", l.Contract[:]).
+			Uint64("block", l.Block).
+			Hex("tx_id", l.TxID[:]).
+			Uint64("index", l.Index).
+			Hex("topic", l.Topic).
+			Hex("data", l.Data).
+			Msg("")
+	}
+}
+
+// validatorChange is an account joining or leaving the set of validators, its
+// stake having crossed sys.MinimumStake.
+type validatorChange struct {
+	id     AccountID
+	stake  uint64
+	joined bool
+}
+
+// validatorChanges returns the accounts which joined or left the set of
+// validators by the changes diffs made, in the order they were first changed.
+func validatorChanges(diffs []TransactionDiff) []validatorChange {
+	var (
+		ids    []AccountID
+		stakes = make(map[AccountID]*FieldDiff)
+	)
+
+	for _, diff := range diffs {
+		for _, account := range diff.Accounts {
+			if account.Stake == nil {
+				continue
+			}
+
+			if stake, ok := stakes[account.ID]; ok {
+				stake.After = account.Stake.After
+				continue
+			}
+
+			stake := *account.Stake
+			stakes[account.ID] = &stake
+
+			ids = append(ids, account.ID)
+		}
+	}
+
+	var changes []validatorChange
+
+	for _, id := range ids {
+		stake := stakes[id]
+
+		before, after := stake.Before >= sys.MinimumStake, stake.After >= sys.MinimumStake
+		if before != after {
+			changes = append(changes, validatorChange{id: id, stake: stake.After, joined: after})
+		}
+	}
+
+	return changes
+}
+
+// logValidatorChanges logs the accounts which joined or left the set of
+// validators upon finalizing the block at height, by the changes diffs made.
+func logValidatorChanges(height uint64, diffs []TransactionDiff) {
+	for _, change := range validatorChanges(diffs) {
+		if change.joined {
+			logger := log.Consensus(events.EventValidatorJoined)
+			logger.Info().
+				Hex("public_key", change.id[:]).
+				Uint64("stake", change.stake).
+				Uint64("block_height", height).
+				Msg("Validator has joined.")
+
+			continue
+		}
+
+		logger := log.Consensus(events.EventValidatorLeft)
+		logger.Info().
+			Hex("public_key", change.id[:]).
+			Uint64("stake", change.stake).
+			Uint64("block_height", height).
+			Msg("Validator has left.")
+	}
 }
 
 // filterInvalidVotes takes a slice of (*finalizationVote)'s and filters away
@@ -1048,6 +1669,14 @@ ValidateVotes:
 			continue ValidateVotes
 		}
 
+		// Reject the block if it has already failed validation before.
+		if proposers, exists := l.queryBlockInvalidCache[vote.block.ID]; exists {
+			l.reportInvalidBlock(vote.voter, proposers)
+
+			vote.block = nil
+			continue ValidateVotes
+		}
+
 		// Ignore block proposals at an unexpected height.
 		if vote.block.Index != current.Index+1 {
 			dbg("got block not suited for current height", vote.block.Index, current.Index+1)
@@ -1125,6 +1754,10 @@ ValidateVotes:
 				hex.EncodeToString(c[:]),
 			)
 
+			proposers := make(map[AccountID]struct{})
+			l.queryBlockInvalidCache[vote.block.ID] = proposers
+			l.reportInvalidBlock(vote.voter, proposers)
+
 			vote.block = nil
 			continue ValidateVotes
 		}
@@ -1132,3 +1765,19 @@ ValidateVotes:
 		l.queryBlockValidCache[vote.block.ID] = struct{}{}
 	}
 }
+
+// reportInvalidBlock penalizes a peer for proposing an invalid block, should
+// it not have been penalized for proposing the block already.
+func (l *Ledger) reportInvalidBlock(voter *skademlia.ID, proposers map[AccountID]struct{}) {
+	if voter.PublicKey() == l.client.ID().PublicKey() {
+		return
+	}
+
+	if _, reported := proposers[voter.PublicKey()]; reported {
+		return
+	}
+
+	proposers[voter.PublicKey()] = struct{}{}
+
+	l.reputation.Report(voter, ViolationInvalidBlock)
+}
@@ -4,12 +4,17 @@ package wavelet
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	mrand "math/rand"
 	"testing"
 	"time"
 
+	"github.com/perlin-network/noise/edwards25519"
 	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -297,3 +302,86 @@ DONE:
 		assert.True(t, bytes.Equal(code[:], checkCode))
 	}
 }
+
+func TestProtocol_Gossip(t *testing.T) {
+	testnet, err := NewTestNetwork()
+	FailTest(t, err)
+
+	defer testnet.Cleanup()
+
+	alice, err := testnet.AddNode()
+	FailTest(t, err)
+
+	bob, err := testnet.AddNode()
+	FailTest(t, err)
+
+	FailTest(t, testnet.WaitUntilSync())
+
+	payload, err := Transfer{Recipient: bob.PublicKey(), Amount: 1}.Marshal()
+	FailTest(t, err)
+
+	keys := testnet.Faucet().Keys()
+
+	typed := NewTransaction(keys, 1, 0, sys.TagTransfer, payload)
+
+	legacy := NewSignedTransaction(keys.PublicKey(), 2, 0, sys.TagTransfer, payload, edwards25519.Sign(
+		keys.PrivateKey(), TransactionMessage(security.SchemeEd25519, 2, 0, sys.TagTransfer, payload),
+	))
+
+	// Variants of a transaction forged by a relaying node are rejected.
+	signature := typed.Signature
+	signature[0] ^= 1
+
+	forged := NewSignedTransactionWithVersion(
+		typed.Version, typed.Scheme, typed.Sender, typed.Nonce, typed.Block, typed.Tag, typed.Payload, signature,
+	)
+
+	// Older nodes gossip binary encoded transactions only.
+	_, err = alice.Ledger().Protocol().Gossip(context.Background(), &GossipRequest{
+		Transactions: [][]byte{legacy.Marshal(), append(typed.Marshal(), 0)},
+	})
+	FailTest(t, err)
+
+	// Newer nodes gossip their protobuf definitions alongside, in which case
+	// the binary encoded transactions are ignored.
+	_, err = alice.Ledger().Protocol().Gossip(context.Background(), &GossipRequest{
+		Transactions: [][]byte{forged.Marshal()},
+		Txs:          []*ledgerpb.Transaction{typed.Proto(), forged.Proto()},
+	})
+	FailTest(t, err)
+
+	for _, tx := range []Transaction{typed, legacy} {
+		assert.True(t, alice.Ledger().Transactions().Has(tx.ID))
+	}
+
+	assert.False(t, alice.Ledger().Transactions().Has(forged.ID))
+
+	// Alice in turn gossips both of them onwards.
+	assert.NoError(t, waitFor(func() bool {
+		return bob.Ledger().Transactions().Has(typed.ID) && bob.Ledger().Transactions().Has(legacy.ID)
+	}))
+}
+
+func TestProtocol_CheckOutOfSync(t *testing.T) {
+	testnet, err := NewTestNetwork()
+	FailTest(t, err)
+
+	defer testnet.Cleanup()
+
+	alice, err := testnet.AddNode()
+	FailTest(t, err)
+
+	_, err = testnet.AddNode()
+	FailTest(t, err)
+
+	FailTest(t, testnet.WaitUntilSync())
+
+	res, err := alice.Ledger().Protocol().CheckOutOfSync(context.Background(), &OutOfSyncRequest{})
+	FailTest(t, err)
+
+	// Peers advertise the snapshot of their latest state they serve in full.
+	latest := alice.Ledger().Blocks().Latest()
+
+	assert.Equal(t, latest.Index, res.SnapshotIndex)
+	assert.Equal(t, latest.ID[:], res.SnapshotBlockId)
+}
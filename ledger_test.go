@@ -64,3 +64,30 @@ func TestLedger_FilterInvalidVotes(t *testing.T) {
 
 	assert.Nil(t, votes[len(votes)-1].(*finalizationVote).block)
 }
+
+func TestValidatorChanges(t *testing.T) {
+	a, b, c := AccountID{1}, AccountID{2}, AccountID{3}
+
+	min := sys.MinimumStake
+
+	diffs := []TransactionDiff{
+		{Accounts: []AccountDiff{
+			{ID: a, Stake: &FieldDiff{Before: 0, After: min}},
+			{ID: b, Balance: &FieldDiff{Before: 10, After: 5}},
+		}},
+		{Accounts: []AccountDiff{
+			{ID: b, Stake: &FieldDiff{Before: min, After: min - 1}},
+			{ID: c, Stake: &FieldDiff{Before: min, After: 0}},
+		}},
+		{Accounts: []AccountDiff{
+			// Leaving and joining again within the same block is no change.
+			{ID: c, Stake: &FieldDiff{Before: 0, After: min * 2}},
+			{ID: a, Stake: &FieldDiff{Before: min, After: min * 3}},
+		}},
+	}
+
+	assert.Equal(t, []validatorChange{
+		{id: a, stake: min * 3, joined: true},
+		{id: b, stake: min - 1, joined: false},
+	}, validatorChanges(diffs))
+}
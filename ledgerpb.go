@@ -0,0 +1,131 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// Proto converts tx to its protobuf definition.
+func (tx Transaction) Proto() *ledgerpb.Transaction {
+	return &ledgerpb.Transaction{
+		Sender:    tx.Sender[:],
+		Nonce:     tx.Nonce,
+		Block:     tx.Block,
+		Tag:       uint32(tx.Tag),
+		Payload:   tx.Payload,
+		Scheme:    uint32(tx.Scheme),
+		Version:   uint32(tx.Version),
+		Stamp:     tx.Stamp,
+		Tip:       tx.Tip,
+		Signature: tx.Signature[:],
+	}
+}
+
+// TransactionFromProto converts the protobuf definition of a transaction
+// back into a transaction, and computes its ID. It accepts exactly the
+// transactions UnmarshalTransaction accepts.
+func TransactionFromProto(pb *ledgerpb.Transaction) (Transaction, error) {
+	if len(pb.Sender) != SizeAccountID {
+		return Transaction{}, errors.Errorf("transaction sender must be %d bytes", SizeAccountID)
+	}
+
+	if len(pb.Signature) != SizeSignature {
+		return Transaction{}, errors.Errorf("transaction signature must be %d bytes", SizeSignature)
+	}
+
+	if pb.Tag > math.MaxUint8 || pb.Scheme > math.MaxUint8 || pb.Version > math.MaxUint8 {
+		return Transaction{}, errors.New("transaction tag, scheme and version must each fit in a byte")
+	}
+
+	if uint64(len(pb.Payload)) > math.MaxUint32 {
+		return Transaction{}, errors.New("transaction payload is too large")
+	}
+
+	var (
+		sender    AccountID
+		signature Signature
+	)
+
+	copy(sender[:], pb.Sender)
+	copy(signature[:], pb.Signature)
+
+	tx := NewSignedTippedTransaction(
+		byte(pb.Version), security.Scheme(pb.Scheme), sender, pb.Nonce, pb.Block, sys.Tag(pb.Tag), pb.Payload,
+		pb.Stamp, pb.Tip, signature,
+	)
+
+	// Round trip through the binary encoding, such that the rules of both
+	// encodings may never drift apart.
+	return ParseTransaction(tx.Marshal())
+}
+
+// Proto converts b to its protobuf definition.
+func (b Block) Proto() *ledgerpb.Block {
+	pb := &ledgerpb.Block{
+		Id:           append([]byte(nil), b.ID[:]...),
+		Index:        b.Index,
+		Merkle:       append([]byte(nil), b.Merkle[:]...),
+		Transactions: make([][]byte, 0, len(b.Transactions)),
+	}
+
+	for i := range b.Transactions {
+		pb.Transactions = append(pb.Transactions, b.Transactions[i][:])
+	}
+
+	return pb
+}
+
+// BlockFromProto converts the protobuf definition of a block back into a
+// block, and computes its ID. Should the definition carry an ID, it must
+// match the computed one.
+func BlockFromProto(pb *ledgerpb.Block) (Block, error) {
+	if len(pb.Merkle) != SizeMerkleNodeID {
+		return Block{}, errors.Errorf("block merkle root must be %d bytes", SizeMerkleNodeID)
+	}
+
+	var merkle MerkleNodeID
+
+	copy(merkle[:], pb.Merkle)
+
+	ids := make([]TransactionID, len(pb.Transactions))
+
+	for i, id := range pb.Transactions {
+		if len(id) != SizeTransactionID {
+			return Block{}, errors.Errorf("block transaction IDs must be %d bytes", SizeTransactionID)
+		}
+
+		copy(ids[i][:], id)
+	}
+
+	block := NewBlock(pb.Index, merkle, ids...)
+
+	if len(pb.Id) > 0 && !bytes.Equal(pb.Id, block.ID[:]) {
+		return Block{}, errors.Errorf("block ID %x does not match its contents", pb.Id)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionProto(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	tx := NewTransaction(keys, 2, 13, sys.TagTransfer, []byte{1, 2, 3})
+
+	buf, err := tx.Proto().Marshal()
+	assert.NoError(t, err)
+
+	pb := new(ledgerpb.Transaction)
+	assert.NoError(t, pb.Unmarshal(buf))
+
+	decoded, err := TransactionFromProto(pb)
+	assert.NoError(t, err)
+	assert.Equal(t, tx, decoded)
+	assert.True(t, decoded.VerifySignature())
+
+	// Definitions which the binary encoding would not accept are rejected.
+	for name, mutate := range map[string]func(pb *ledgerpb.Transaction){
+		"short sender":    func(pb *ledgerpb.Transaction) { pb.Sender = pb.Sender[:31] },
+		"short signature": func(pb *ledgerpb.Transaction) { pb.Signature = pb.Signature[:63] },
+		"wide tag":        func(pb *ledgerpb.Transaction) { pb.Tag = 256 },
+		"unknown tag":     func(pb *ledgerpb.Transaction) { pb.Tag = 255 },
+		"wide version":    func(pb *ledgerpb.Transaction) { pb.Version = 257 },
+		"unknown version": func(pb *ledgerpb.Transaction) { pb.Version = 2 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			invalid := *tx.Proto()
+			mutate(&invalid)
+
+			_, err := TransactionFromProto(&invalid)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBlockProto(t *testing.T) {
+	var merkle MerkleNodeID
+	_, err := rand.Read(merkle[:])
+	assert.NoError(t, err)
+
+	ids := make([]TransactionID, 3)
+
+	for i := range ids {
+		_, err := rand.Read(ids[i][:])
+		assert.NoError(t, err)
+	}
+
+	block := NewBlock(10, merkle, ids...)
+
+	decoded, err := BlockFromProto(block.Proto())
+	assert.NoError(t, err)
+	assert.Equal(t, block, decoded)
+
+	pb := block.Proto()
+	pb.Index++
+
+	_, err = BlockFromProto(pb)
+	assert.Error(t, err)
+
+	pb.Id = nil
+
+	decoded, err = BlockFromProto(pb)
+	assert.NoError(t, err)
+	assert.Equal(t, block.Index+1, decoded.Index)
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/events"
 	"github.com/perlin-network/wavelet/log"
 	"github.com/valyala/fastjson"
 )
@@ -103,7 +104,7 @@ func (c *CollapseResultsLogger) Log(results *collapseResults) {
 	timestamp := time.Now()
 
 	modTx := []byte(log.ModuleTX)
-	eventApplied := []byte("applied")
+	eventApplied := []byte(events.EventTxApplied)
 	bufTxID := make([]byte, hex.EncodedLen(SizeTransactionID))
 	bufAccount := make([]byte, hex.EncodedLen(SizeAccountID))
 
@@ -114,7 +115,7 @@ func (c *CollapseResultsLogger) Log(results *collapseResults) {
 		c.addTx(modTx, eventApplied, timestamp, int(tx.Tag), bufTxID, bufAccount, nil)
 	}
 
-	eventRejected := []byte("rejected")
+	eventRejected := []byte(events.EventTxRejected)
 
 	for i, tx := range results.rejected {
 		_ = hex.Encode(bufTxID, tx.ID[:])
@@ -22,18 +22,40 @@ package log
 import (
 	"io"
 	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
 )
 
+// moduleWriter writes the entries of the logger of a module to output, should
+// they be at or above the level of the module.
+type moduleWriter string
+
+func (m moduleWriter) Write(p []byte) (n int, err error) {
+	return output.WriteEntry(zerolog.NoLevel, string(m), p)
+}
+
+func (m moduleWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	if min, exists := levels[string(m)]; exists && level < zerolog.Level(atomic.LoadInt32(min)) {
+		return len(p), nil
+	}
+
+	return output.WriteEntry(level, string(m), p)
+}
+
 type multiWriter struct {
 	sync.RWMutex
 	writers        map[string]io.Writer
 	writersModules map[string]map[string]struct{}
+	sinks          map[string]Sink
 }
 
 func (t *multiWriter) SetWriter(key string, writer io.Writer, modules ...string) {
 	t.Lock()
 	defer t.Unlock()
 
+	delete(t.sinks, key)
+
 	t.writers[key] = writer
 
 	if len(modules) == 0 {
@@ -49,7 +71,18 @@ func (t *multiWriter) SetWriter(key string, writer io.Writer, modules ...string)
 	}
 }
 
-func (t *multiWriter) Write(p []byte) (n int, err error) {
+func (t *multiWriter) SetSink(key string, sink Sink) {
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.writers, key)
+	delete(t.writersModules, key)
+
+	t.sinks[key] = sink
+}
+
+// WriteEntry writes the entry of module at level to all writers and sinks.
+func (t *multiWriter) WriteEntry(level zerolog.Level, module string, p []byte) (n int, err error) {
 	t.RLock()
 	defer t.RUnlock()
 
@@ -60,10 +93,17 @@ func (t *multiWriter) Write(p []byte) (n int, err error) {
 		}
 	}
 
+	for _, s := range t.sinks {
+		if err = s.WriteEntry(level, module, p); err != nil {
+			return 0, err
+		}
+	}
+
 	return len(p), nil
 }
 
-// WriteFilter writes to only writers that have filter for the module.
+// WriteFilter writes to only writers that have filter for the module, and to
+// all sinks.
 func (t *multiWriter) WriteFilter(p []byte, module string) (n int, err error) {
 	t.RLock()
 	defer t.RUnlock()
@@ -81,6 +121,13 @@ func (t *multiWriter) WriteFilter(p []byte, module string) (n int, err error) {
 		}
 	}
 
+	// Entries written through Write carry no level.
+	for _, s := range t.sinks {
+		if err = s.WriteEntry(zerolog.NoLevel, module, p); err != nil {
+			return 0, err
+		}
+	}
+
 	return len(p), nil
 }
 
@@ -90,6 +137,7 @@ func (t *multiWriter) Clear(key string) {
 
 	delete(t.writers, key)
 	delete(t.writersModules, key)
+	delete(t.sinks, key)
 }
 
 func write(w io.Writer, p []byte) (n int, err error) {
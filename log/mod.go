@@ -21,7 +21,10 @@ package log
 
 import (
 	"io"
+	"sort"
+	"sync/atomic"
 
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
@@ -29,8 +32,8 @@ var (
 	output = &multiWriter{
 		writers:        make(map[string]io.Writer),
 		writersModules: make(map[string]map[string]struct{}),
+		sinks:          make(map[string]Sink),
 	}
-	logger = zerolog.New(output).With().Timestamp().Logger()
 
 	node      zerolog.Logger
 	network   zerolog.Logger
@@ -40,8 +43,24 @@ var (
 	syncer    zerolog.Logger
 	tx        zerolog.Logger
 	metrics   zerolog.Logger
+
+	// levels holds the minimum level of the entries of every module. It is
+	// checked as entries are written, for loggers obtained before the level
+	// of their module changed to abide by it.
+	levels = map[string]*int32{
+		ModuleNode:      new(int32),
+		ModuleNetwork:   new(int32),
+		ModuleAccounts:  new(int32),
+		ModuleConsensus: new(int32),
+		ModuleContract:  new(int32),
+		ModuleSync:      new(int32),
+		ModuleTX:        new(int32),
+		ModuleMetrics:   new(int32),
+	}
 )
 
+var ErrUnknownModule = errors.New("unknown module")
+
 const (
 	LoggerWavelet   = "wavelet"
 	LoggerWebsocket = "ws"
@@ -64,31 +83,114 @@ func init() { // nolint:gochecknoinits
 }
 
 func setupChildLoggers() {
-	// Default to Debug
-	node = logger.With().Str(KeyModule, ModuleNode).Logger().Level(zerolog.DebugLevel)
-	network = logger.With().Str(KeyModule, ModuleNetwork).Logger().Level(zerolog.DebugLevel)
-	accounts = logger.With().Str(KeyModule, ModuleAccounts).Logger().Level(zerolog.DebugLevel)
-	consensus = logger.With().Str(KeyModule, ModuleConsensus).Logger().Level(zerolog.DebugLevel)
-	contract = logger.With().Str(KeyModule, ModuleContract).Logger().Level(zerolog.DebugLevel)
-	syncer = logger.With().Str(KeyModule, ModuleSync).Logger().Level(zerolog.DebugLevel)
-	tx = logger.With().Str(KeyModule, ModuleTX).Logger().Level(zerolog.DebugLevel)
-	metrics = logger.With().Str(KeyModule, ModuleMetrics).Logger().Level(zerolog.DebugLevel)
+	node = newLogger(ModuleNode)
+	network = newLogger(ModuleNetwork)
+	accounts = newLogger(ModuleAccounts)
+	consensus = newLogger(ModuleConsensus)
+	contract = newLogger(ModuleContract)
+	syncer = newLogger(ModuleSync)
+	tx = newLogger(ModuleTX)
+	metrics = newLogger(ModuleMetrics)
+}
+
+// newLogger returns the logger of module. Its entries are filtered by the level
+// of module as they are written, rather than by the logger, for the level to be
+// changed while the logger is in use.
+func newLogger(module string) zerolog.Logger {
+	return zerolog.New(moduleWriter(module)).With().Timestamp().Str(KeyModule, module).Logger()
+}
+
+// updateGlobalLevel sets the global level of zerolog to the lowest level of all
+// modules, for entries no module would write to be discarded before they are
+// built.
+func updateGlobalLevel() {
+	min := zerolog.Disabled
+
+	for module := range levels {
+		if level := Level(module); level < min {
+			min = level
+		}
+	}
+
+	zerolog.SetGlobalLevel(min)
 }
 
+// SetLevel sets the level of all modules, defaulting to debug should ls not
+// be a valid level.
 func SetLevel(ls string) {
 	level, err := zerolog.ParseLevel(ls)
 	if err != nil {
 		level = zerolog.DebugLevel
 	}
 
-	node = node.Level(level)
-	network = network.Level(level)
-	accounts = accounts.Level(level)
-	consensus = consensus.Level(level)
-	contract = contract.Level(level)
-	syncer = syncer.Level(level)
-	tx = tx.Level(level)
-	metrics = metrics.Level(level)
+	for module := range levels {
+		atomic.StoreInt32(levels[module], int32(level))
+	}
+
+	updateGlobalLevel()
+}
+
+// ValidateLevel checks that module exists, and that ls is a valid level.
+func ValidateLevel(module string, ls string) error {
+	if _, exists := levels[module]; !exists {
+		return errors.Wrap(ErrUnknownModule, module)
+	}
+
+	if _, err := zerolog.ParseLevel(ls); err != nil || ls == "" {
+		return errors.Errorf("invalid level %q", ls)
+	}
+
+	return nil
+}
+
+// SetModuleLevel sets the level of module to ls, entries of module below it
+// being discarded from then on.
+func SetModuleLevel(module string, ls string) error {
+	if err := ValidateLevel(module, ls); err != nil {
+		return err
+	}
+
+	level, _ := zerolog.ParseLevel(ls)
+
+	atomic.StoreInt32(levels[module], int32(level))
+
+	updateGlobalLevel()
+
+	return nil
+}
+
+// Level returns the level of module, which is debug for unknown modules.
+func Level(module string) zerolog.Level {
+	level, exists := levels[module]
+	if !exists {
+		return zerolog.DebugLevel
+	}
+
+	return zerolog.Level(atomic.LoadInt32(level))
+}
+
+// Levels returns the level of every module.
+func Levels() map[string]zerolog.Level {
+	m := make(map[string]zerolog.Level, len(levels))
+
+	for module := range levels {
+		m[module] = Level(module)
+	}
+
+	return m
+}
+
+// Modules returns the names of all modules, sorted.
+func Modules() []string {
+	modules := make([]string, 0, len(levels))
+
+	for module := range levels {
+		modules = append(modules, module)
+	}
+
+	sort.Strings(modules)
+
+	return modules
 }
 
 func SetWriter(key string, writer io.Writer) {
@@ -103,6 +205,12 @@ func SetWriter(key string, writer io.Writer) {
 	output.SetWriter(key, writer, modules...)
 }
 
+// SetSink registers sink under key, replacing the writer or sink registered
+// under it. The sink receives every entry written from then on.
+func SetSink(key string, sink Sink) {
+	output.SetSink(key, sink)
+}
+
 func ClearWriter(key string) {
 	output.Clear(key)
 }
@@ -0,0 +1,110 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fastjson"
+)
+
+type testSink struct {
+	levels  []zerolog.Level
+	modules []string
+	entries [][]byte
+}
+
+func (s *testSink) WriteEntry(level zerolog.Level, module string, entry []byte) error {
+	s.levels = append(s.levels, level)
+	s.modules = append(s.modules, module)
+	s.entries = append(s.entries, append([]byte(nil), entry...))
+
+	return nil
+}
+
+func TestModuleLevels(t *testing.T) {
+	levels := Levels()
+	defer func() {
+		for module, level := range levels {
+			_ = SetModuleLevel(module, level.String())
+		}
+
+		updateGlobalLevel()
+	}()
+
+	sink := &testSink{}
+
+	SetSink("test", sink)
+	defer ClearWriter("test")
+
+	SetLevel("debug")
+
+	// Loggers obtained before the level of their module changes abide by it.
+	logger := TX("applied")
+
+	assert.NoError(t, SetModuleLevel(ModuleTX, "warn"))
+	assert.Equal(t, zerolog.WarnLevel, Level(ModuleTX))
+	assert.Equal(t, zerolog.DebugLevel, Level(ModuleNode))
+
+	logger.Info().Msg("dropped")
+	logger.Warn().Msg("kept")
+
+	node := Node()
+	node.Debug().Msg("node")
+
+	if assert.Len(t, sink.entries, 2) {
+		assert.Equal(t, []zerolog.Level{zerolog.WarnLevel, zerolog.DebugLevel}, sink.levels)
+		assert.Equal(t, []string{ModuleTX, ModuleNode}, sink.modules)
+
+		v, err := fastjson.ParseBytes(sink.entries[0])
+		if assert.NoError(t, err) {
+			assert.Equal(t, "kept", string(v.GetStringBytes(zerolog.MessageFieldName)))
+			assert.Equal(t, ModuleTX, string(v.GetStringBytes(KeyModule)))
+			assert.Equal(t, "applied", string(v.GetStringBytes(KeyEvent)))
+			assert.Equal(t, "warn", string(v.GetStringBytes(zerolog.LevelFieldName)))
+		}
+	}
+
+	// Entries no module writes are discarded before they are built.
+	SetLevel("error")
+	assert.Equal(t, zerolog.ErrorLevel, zerolog.GlobalLevel())
+
+	assert.EqualError(t, SetModuleLevel("nope", "info"), "nope: unknown module")
+	assert.Error(t, SetModuleLevel(ModuleTX, "loud"))
+	assert.Error(t, SetModuleLevel(ModuleTX, ""))
+	assert.Equal(t, zerolog.ErrorLevel, Level(ModuleTX))
+}
+
+func TestJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewJSONWriter(&buf, ModuleNode)
+
+	assert.NoError(t, w.WriteEntry(zerolog.InfoLevel, ModuleNode, []byte(`{"a":1}`)))
+	assert.NoError(t, w.WriteEntry(zerolog.InfoLevel, ModuleTX, []byte(`{"b":2}`)))
+	assert.NoError(t, w.WriteEntry(zerolog.InfoLevel, ModuleNode, []byte("{\"c\":3}\n")))
+
+	assert.Equal(t, "{\"a\":1}\n{\"c\":3}\n", buf.String())
+}
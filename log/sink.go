@@ -0,0 +1,76 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package log
+
+import (
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// Sink is a destination of log entries, such as a shipper of logs to ELK.
+// Entries are JSON objects carrying the time, level, module and fields of the
+// entry, and are passed alongside their level and module for sinks not to
+// decode them to filter them. Entries written through Write carry no level.
+//
+// Sinks are registered through SetSink, and must not retain entries after
+// WriteEntry returns.
+type Sink interface {
+	WriteEntry(level zerolog.Level, module string, entry []byte) error
+}
+
+// JSONWriter is a sink writing entries to Out as they are, one JSON object per
+// line.
+type JSONWriter struct {
+	// Out is the output destination.
+	Out io.Writer
+
+	// FilteredModules are the modules whose entries are written, entries of
+	// all modules being written should it be empty.
+	FilteredModules map[string]struct{}
+}
+
+// NewJSONWriter creates a JSONWriter writing the entries of modules, or of all
+// modules should none be given, to writer.
+func NewJSONWriter(writer io.Writer, modules ...string) JSONWriter {
+	w := JSONWriter{Out: writer, FilteredModules: make(map[string]struct{})}
+
+	for _, module := range modules {
+		w.FilteredModules[module] = struct{}{}
+	}
+
+	return w
+}
+
+func (w JSONWriter) WriteEntry(level zerolog.Level, module string, entry []byte) error {
+	if len(w.FilteredModules) > 0 {
+		if _, filtered := w.FilteredModules[module]; !filtered {
+			return nil
+		}
+	}
+
+	if len(entry) > 0 && entry[len(entry)-1] != '\n' {
+		entry = append(entry[:len(entry):len(entry)], '\n')
+	}
+
+	_, err := write(w.Out, entry)
+
+	return err
+}
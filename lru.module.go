@@ -0,0 +1,123 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/perlin-network/life/exec"
+	"github.com/perlin-network/wavelet/conf"
+	"go.uber.org/atomic"
+)
+
+// ContractModules caches smart contracts compiled into virtual machines, as
+// instantiated before executing any of their code, by the hash of their code.
+// It is shared by every execution of a smart contract, and holds as many
+// modules as conf.GetContractModuleCacheSize.
+var ContractModules = NewModuleLRU()
+
+// ModuleLRU is a cache of compiled smart contracts evicting the least
+// recently used ones, which keeps count of how often it is hit.
+type ModuleLRU struct {
+	sync.Mutex
+
+	elements map[[32]byte]*list.Element
+	access   *list.List
+
+	hits   *atomic.Uint64
+	misses *atomic.Uint64
+}
+
+type objectInfoModule struct {
+	key [32]byte
+	obj *exec.VirtualMachine
+}
+
+func NewModuleLRU() *ModuleLRU {
+	return &ModuleLRU{
+		elements: make(map[[32]byte]*list.Element),
+		access:   list.New(),
+
+		hits:   atomic.NewUint64(0),
+		misses: atomic.NewUint64(0),
+	}
+}
+
+// Load returns the module compiled from the code of hash key, counting
+// whether it was cached.
+func (l *ModuleLRU) Load(key [32]byte) (*exec.VirtualMachine, bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	elem, ok := l.elements[key]
+	if !ok {
+		l.misses.Inc()
+		return nil, false
+	}
+
+	l.hits.Inc()
+	l.access.MoveToFront(elem)
+
+	return elem.Value.(*objectInfoModule).obj, ok
+}
+
+func (l *ModuleLRU) Put(key [32]byte, val *exec.VirtualMachine) {
+	l.Lock()
+	defer l.Unlock()
+
+	elem, ok := l.elements[key]
+
+	if ok {
+		elem.Value.(*objectInfoModule).obj = val
+		l.access.MoveToFront(elem)
+	} else {
+		l.elements[key] = l.access.PushFront(&objectInfoModule{
+			key: key,
+			obj: val,
+		})
+	}
+
+	l.evict(conf.GetContractModuleCacheSize())
+}
+
+// Len returns the number of modules cached.
+func (l *ModuleLRU) Len() int {
+	l.Lock()
+	defer l.Unlock()
+
+	return len(l.elements)
+}
+
+// Stats returns the number of times a module was loaded from the cache, and
+// the number of times it had to be compiled instead.
+func (l *ModuleLRU) Stats() (hits, misses uint64) {
+	return l.hits.Load(), l.misses.Load()
+}
+
+// evict removes the least recently used modules until at most size remain.
+func (l *ModuleLRU) evict(size int) {
+	for len(l.elements) > size {
+		back := l.access.Back()
+		info := back.Value.(*objectInfoModule)
+		delete(l.elements, info.key)
+		l.access.Remove(back)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractModuleCache(t *testing.T) {
+	code, err := ioutil.ReadFile("testdata/transfer_back.wasm")
+	require.NoError(t, err)
+
+	hits, _ := ContractModules.Stats()
+
+	first, err := compileContract(code, &sys.GasScheduleV1)
+	require.NoError(t, err)
+
+	// Contracts of the same code share the module compiled first.
+	second, err := compileContract(code, &sys.GasScheduleV1)
+	require.NoError(t, err)
+	assert.True(t, first == second)
+
+	h, _ := ContractModules.Stats()
+	assert.True(t, h > hits)
+
+	// Code is compiled again for every version of the gas schedule.
+	third, err := compileContract(code, &sys.GasScheduleV2)
+	require.NoError(t, err)
+	assert.False(t, first == third)
+
+	// Clones of the module never modify it.
+	memory := append([]byte(nil), first.Memory...)
+
+	clone, err := CloneVM(first, nil, nil)
+	require.NoError(t, err)
+
+	clone.Memory[0]++
+	assert.Equal(t, memory, first.Memory)
+
+	_, err = compileContract([]byte("not wasm"), &sys.GasScheduleV1)
+	assert.Error(t, err)
+}
+
+func TestContractModuleCacheEviction(t *testing.T) {
+	defaultSize := conf.GetContractModuleCacheSize()
+	conf.Update(conf.WithContractModuleCacheSize(2))
+
+	defer func() {
+		conf.Update(conf.WithContractModuleCacheSize(defaultSize))
+	}()
+
+	cache := NewModuleLRU()
+
+	cache.Put([32]byte{1}, nil)
+	cache.Put([32]byte{2}, nil)
+
+	_, ok := cache.Load([32]byte{1})
+	assert.True(t, ok)
+
+	// The least recently used module is evicted first.
+	cache.Put([32]byte{3}, nil)
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = cache.Load([32]byte{2})
+	assert.False(t, ok)
+
+	_, ok = cache.Load([32]byte{1})
+	assert.True(t, ok)
+
+	hits, misses := cache.Stats()
+	assert.EqualValues(t, 2, hits)
+	assert.EqualValues(t, 1, misses)
+
+	// Nothing is cached should the size of the cache be 0.
+	conf.Update(conf.WithContractModuleCacheSize(0))
+
+	cache.Put([32]byte{4}, nil)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestContractCallUncached(t *testing.T) {
+	state := avl.New(store.NewInmem())
+	block := NewBlock(0, state.Checksum())
+
+	account, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	code, err := ioutil.ReadFile("testdata/transfer_back.wasm")
+	require.NoError(t, err)
+
+	payload, err := buildContractSpawnPayload(100000, 0, code).Marshal()
+	require.NoError(t, err)
+
+	WriteAccountBalance(state, account.PublicKey(), 100000)
+
+	spawn := buildSignedTransaction(account, sys.TagContract, 1, block.Index, payload)
+	require.NoError(t, ApplyTransaction(state, &block, &spawn))
+
+	call := func() uint64 {
+		res, err := SimulateContractCall(
+			state, &block, account.PublicKey(), spawn.ID, 1000, 500000, "on_money_received", nil,
+		)
+		require.NoError(t, err)
+		require.NoError(t, res.Err)
+
+		return res.Gas
+	}
+
+	cached := call()
+
+	defaultSize := conf.GetContractModuleCacheSize()
+	conf.Update(conf.WithContractModuleCacheSize(0))
+
+	defer func() {
+		conf.Update(conf.WithContractModuleCacheSize(defaultSize))
+	}()
+
+	// Calls spend as much gas whether or not their contract was cached.
+	assert.Equal(t, cached, call())
+}
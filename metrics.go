@@ -36,10 +36,18 @@ type Metrics struct {
 	receivedTX   metrics.Meter
 	acceptedTX   metrics.Meter
 	downloadedTX metrics.Meter
+	conflictedTX metrics.Counter
+
+	// gossipedBytes is the size of the batches of transactions gossiped, and
+	// gossipedWireBytes their size once compressed.
+	gossipedBytes     metrics.Counter
+	gossipedWireBytes metrics.Counter
 
 	finalizedBlocks metrics.Meter
 
-	queryLatency metrics.Timer
+	queryLatency     metrics.Timer
+	gossipLatency    metrics.Timer
+	consensusLatency metrics.Timer
 }
 
 func NewMetrics(ctx context.Context) *Metrics {
@@ -51,10 +59,24 @@ func NewMetrics(ctx context.Context) *Metrics {
 	receivedTX := metrics.NewRegisteredMeter("tx.received", registry)
 	acceptedTX := metrics.NewRegisteredMeter("tx.accepted", registry)
 	downloadedTX := metrics.NewRegisteredMeter("tx.downloaded", registry)
+	conflictedTX := metrics.NewRegisteredCounter("tx.conflicts", registry)
+
+	gossipedBytes := metrics.NewRegisteredCounter("gossip.bytes", registry)
+	gossipedWireBytes := metrics.NewRegisteredCounter("gossip.bytes.wire", registry)
+
+	metrics.NewRegisteredFunctionalGaugeFloat64("gossip.compression.ratio", registry, func() float64 {
+		if gossipedWireBytes.Count() == 0 {
+			return 1
+		}
+
+		return float64(gossipedBytes.Count()) / float64(gossipedWireBytes.Count())
+	})
 
 	finalizedBlocks := metrics.NewRegisteredMeter("block.finalized", registry)
 
 	queryLatency := metrics.NewRegisteredTimer("query.latency", registry)
+	gossipLatency := metrics.NewRegisteredTimer("gossip.latency", registry)
+	consensusLatency := metrics.NewRegisteredTimer("consensus.latency", registry)
 
 	go func() {
 		logger := log.Metrics()
@@ -68,6 +90,7 @@ func NewMetrics(ctx context.Context) *Metrics {
 					Int64("tx.received", receivedTX.Count()).
 					Int64("tx.accepted", acceptedTX.Count()).
 					Int64("tx.downloaded", downloadedTX.Count()).
+					Int64("tx.conflicts", conflictedTX.Count()).
 					Float64("bps.queried", queried.RateMean()).
 					Float64("tps.gossiped", gossipedTX.RateMean()).
 					Float64("tps.received", receivedTX.RateMean()).
@@ -93,13 +116,69 @@ func NewMetrics(ctx context.Context) *Metrics {
 		receivedTX:   receivedTX,
 		acceptedTX:   acceptedTX,
 		downloadedTX: downloadedTX,
+		conflictedTX: conflictedTX,
+
+		gossipedBytes:     gossipedBytes,
+		gossipedWireBytes: gossipedWireBytes,
 
 		finalizedBlocks: finalizedBlocks,
 
-		queryLatency: queryLatency,
+		queryLatency:     queryLatency,
+		gossipLatency:    gossipLatency,
+		consensusLatency: consensusLatency,
 	}
 }
 
+// Registry returns the registry all metrics of the node are registered in,
+// for other modules to register their own metrics in.
+func (m *Metrics) Registry() metrics.Registry {
+	return m.registry
+}
+
+// observe registers gauges reporting on the state of l: the number of peers
+// it is connected to, the depth of its mempool, the index of its latest
+// block, and how often compiled smart contracts are found cached.
+func (m *Metrics) observe(l *Ledger) {
+	metrics.NewRegisteredFunctionalGauge("peers", m.registry, func() int64 {
+		return int64(len(l.client.ClosestPeers()))
+	})
+
+	metrics.NewRegisteredFunctionalGauge("mempool.pending", m.registry, func() int64 {
+		return int64(l.transactions.PendingLen())
+	})
+
+	metrics.NewRegisteredFunctionalGauge("mempool.missing", m.registry, func() int64 {
+		return int64(l.transactions.MissingLen())
+	})
+
+	metrics.NewRegisteredFunctionalGauge("block.height", m.registry, func() int64 {
+		return int64(l.blocks.Latest().Index)
+	})
+
+	metrics.NewRegisteredFunctionalGauge("contract.cache.size", m.registry, func() int64 {
+		return int64(ContractModules.Len())
+	})
+
+	metrics.NewRegisteredFunctionalGauge("contract.cache.hits", m.registry, func() int64 {
+		hits, _ := ContractModules.Stats()
+		return int64(hits)
+	})
+
+	metrics.NewRegisteredFunctionalGauge("contract.cache.misses", m.registry, func() int64 {
+		_, misses := ContractModules.Stats()
+		return int64(misses)
+	})
+
+	metrics.NewRegisteredFunctionalGaugeFloat64("contract.cache.hit_rate", m.registry, func() float64 {
+		hits, misses := ContractModules.Stats()
+		if hits+misses == 0 {
+			return 0
+		}
+
+		return float64(hits) / float64(hits+misses)
+	})
+}
+
 func (m *Metrics) Stop() {
 	m.queried.Stop()
 
@@ -111,4 +190,6 @@ func (m *Metrics) Stop() {
 	m.finalizedBlocks.Stop()
 
 	m.queryLatency.Stop()
+	m.gossipLatency.Stop()
+	m.consensusLatency.Stop()
 }
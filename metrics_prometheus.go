@@ -0,0 +1,113 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// PrometheusNamespace prefixes the names of all metrics written by
+// WritePrometheus.
+const PrometheusNamespace = "wavelet"
+
+// prometheusQuantiles are the quantiles of timers and histograms written by
+// WritePrometheus.
+var prometheusQuantiles = []float64{0.5, 0.9, 0.99}
+
+// sampled is implemented by both timers and histograms.
+type sampled interface {
+	Count() int64
+	Sum() int64
+	Percentiles([]float64) []float64
+}
+
+// WritePrometheus writes all metrics of the node to w in the Prometheus text
+// exposition format. Meters and counters are written as counters, gauges as
+// gauges, and timers and histograms as summaries. Timers are measured in
+// seconds.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	var names []string
+
+	values := make(map[string]interface{})
+
+	m.registry.Each(func(name string, v interface{}) {
+		names = append(names, name)
+		values[name] = v
+	})
+
+	sort.Strings(names)
+
+	buf := bufio.NewWriter(w)
+
+	for _, name := range names {
+		metric := prometheusName(name)
+
+		switch v := values[name].(type) {
+		case metrics.Timer:
+			writePrometheusSummary(buf, metric+"_seconds", v.Snapshot(), 1e-9)
+		case metrics.Histogram:
+			writePrometheusSummary(buf, metric, v.Snapshot(), 1)
+		case metrics.Meter:
+			writePrometheusMetric(buf, metric+"_total", "counter", float64(v.Count()))
+		case metrics.Counter:
+			writePrometheusMetric(buf, metric+"_total", "counter", float64(v.Count()))
+		case metrics.Gauge:
+			writePrometheusMetric(buf, metric, "gauge", float64(v.Value()))
+		case metrics.GaugeFloat64:
+			writePrometheusMetric(buf, metric, "gauge", v.Value())
+		}
+	}
+
+	return buf.Flush()
+}
+
+// prometheusName converts the name of a metric such as "tx.accepted" into
+// a valid Prometheus metric name such as "wavelet_tx_accepted".
+func prometheusName(name string) string {
+	return PrometheusNamespace + "_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, name)
+}
+
+func writePrometheusMetric(w io.Writer, name, kind string, value float64) {
+	fmt.Fprintf(w, "# TYPE %s %s\n%s %g\n", name, kind, name, value)
+}
+
+// writePrometheusSummary writes s as a summary, with all of its samples
+// multiplied by scale.
+func writePrometheusSummary(w io.Writer, name string, s sampled, scale float64) {
+	fmt.Fprintf(w, "# TYPE %s summary\n", name)
+
+	for i, p := range s.Percentiles(prometheusQuantiles) {
+		fmt.Fprintf(w, "%s{quantile=\"%g\"} %g\n", name, prometheusQuantiles[i], p*scale)
+	}
+
+	fmt.Fprintf(w, "%s_sum %g\n%s_count %d\n", name, float64(s.Sum())*scale, name, s.Count())
+}
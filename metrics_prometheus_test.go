@@ -0,0 +1,72 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+// +build unit
+
+package wavelet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsWritePrometheus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewMetrics(ctx)
+	defer m.Stop()
+
+	m.acceptedTX.Mark(3)
+	m.conflictedTX.Inc(2)
+	m.queryLatency.Update(500 * time.Millisecond)
+	m.queryLatency.Update(1500 * time.Millisecond)
+
+	metrics.NewRegisteredFunctionalGauge("mempool.pending", m.Registry(), func() int64 {
+		return 7
+	})
+
+	var buf bytes.Buffer
+	assert.NoError(t, m.WritePrometheus(&buf))
+
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE wavelet_tx_accepted_total counter\nwavelet_tx_accepted_total 3\n")
+	assert.Contains(t, out, "# TYPE wavelet_tx_conflicts_total counter\nwavelet_tx_conflicts_total 2\n")
+	assert.Contains(t, out, "# TYPE wavelet_mempool_pending gauge\nwavelet_mempool_pending 7\n")
+
+	assert.Contains(t, out, "# TYPE wavelet_query_latency_seconds summary\n")
+	assert.Contains(t, out, "wavelet_query_latency_seconds{quantile=\"0.99\"} 1.5\n")
+	assert.Contains(t, out, "wavelet_query_latency_seconds_sum 2\n")
+	assert.Contains(t, out, "wavelet_query_latency_seconds_count 2\n")
+
+	// Metrics are written in order of their names.
+	assert.True(t, bytes.Index(buf.Bytes(), []byte("wavelet_block_finalized")) <
+		bytes.Index(buf.Bytes(), []byte("wavelet_tx_accepted")))
+}
+
+func TestPrometheusName(t *testing.T) {
+	assert.Equal(t, "wavelet_tx_accepted", prometheusName("tx.accepted"))
+	assert.Equal(t, "wavelet_api_latency", prometheusName("api.latency"))
+	assert.Equal(t, "wavelet_a_b_c", prometheusName("a-b c"))
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// Multisig accounts hold PERLs which are only moved once enough of their
+// participants approve. A multisig account is created by listing its
+// participants and a threshold, and its ID is that of the transaction which
+// created it, such that PERLs are sent to it like to any other account.
+//
+// A participant proposes a transfer from the account, which approves it on
+// their behalf. The other participants then approve the proposal, which is
+// identified by the ID of the transaction which proposed it. Once threshold
+// participants approved, any participant may execute the transfer. The
+// participant who proposed a transfer may cancel it until it is executed.
+
+// verifyMultisig checks that the sender of tx may act upon a multisig account
+// or a transfer proposed from it.
+func verifyMultisig(
+	readMultisig func(AccountID) (MultisigAccount, bool), readProposal func(TransactionID) (MultisigProposal, bool),
+	readBalance func(AccountID) (uint64, bool), tx *Transaction, payload Multisig,
+) error {
+	switch payload.Opcode {
+	case sys.CreateMultisig:
+		return nil
+	case sys.ProposeMultisig:
+		multisig, exists := readMultisig(payload.Account)
+		if !exists {
+			return errors.Errorf("multisig: %x is not a multisig account", payload.Account)
+		}
+
+		if !isGuardian(multisig.Participants, tx.Sender) {
+			return errors.Errorf("multisig: %x is not a participant of %x", tx.Sender, payload.Account)
+		}
+
+		if len(multisig.Proposals) >= sys.MaxMultisigProposals {
+			return errors.Errorf("multisig: %x already has %d pending proposals", payload.Account, len(multisig.Proposals))
+		}
+
+		return nil
+	}
+
+	proposal, pending := readProposal(payload.Proposal)
+	if !pending {
+		return errors.Errorf("multisig: %x is not a pending proposal", payload.Proposal)
+	}
+
+	multisig, _ := readMultisig(proposal.Account)
+
+	switch payload.Opcode {
+	case sys.ApproveMultisig:
+		if !isGuardian(multisig.Participants, tx.Sender) {
+			return errors.Errorf("multisig: %x is not a participant of %x", tx.Sender, proposal.Account)
+		}
+
+		if isGuardian(proposal.Approvals, tx.Sender) {
+			return errors.Errorf("multisig: %x already approved proposal %x", tx.Sender, payload.Proposal)
+		}
+	case sys.ExecuteMultisig:
+		if !isGuardian(multisig.Participants, tx.Sender) {
+			return errors.Errorf("multisig: %x is not a participant of %x", tx.Sender, proposal.Account)
+		}
+
+		if len(proposal.Approvals) < int(multisig.Threshold) {
+			return errors.Errorf(
+				"multisig: executing proposal %x requires %d approvals, but only has %d",
+				payload.Proposal, multisig.Threshold, len(proposal.Approvals),
+			)
+		}
+
+		if balance, _ := readBalance(proposal.Account); balance < proposal.Amount {
+			return errors.Wrapf(
+				ErrInsufficientBalance, "multisig: %x holds %d PERLs, but %d are to be transferred",
+				proposal.Account, balance, proposal.Amount,
+			)
+		}
+	case sys.CancelMultisig:
+		if proposal.Proposer != tx.Sender {
+			return errors.Errorf("multisig: proposal %x may only be cancelled by %x", payload.Proposal, proposal.Proposer)
+		}
+	}
+
+	return nil
+}
+
+// removeMultisigProposal returns the proposals of a multisig account without
+// the proposal id.
+func removeMultisigProposal(proposals []TransactionID, id TransactionID) []TransactionID {
+	filtered := make([]TransactionID, 0, len(proposals))
+
+	for _, proposal := range proposals {
+		if proposal != id {
+			filtered = append(filtered, proposal)
+		}
+	}
+
+	return filtered
+}
+
+func applyMultisigTransaction(ctx *CollapseContext, block *Block, tx *Transaction) error {
+	payload, err := ParseMultisig(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyMultisig(
+		ctx.ReadAccountMultisig, ctx.ReadMultisigProposal, ctx.ReadAccountBalance, tx, payload,
+	); err != nil {
+		return err
+	}
+
+	switch payload.Opcode {
+	case sys.CreateMultisig:
+		ctx.WriteAccountMultisig(AccountID(tx.ID), MultisigAccount{
+			Participants: payload.Participants,
+			Threshold:    payload.Threshold,
+		})
+	case sys.ProposeMultisig:
+		multisig, _ := ctx.ReadAccountMultisig(payload.Account)
+		multisig.Proposals = append(append([]TransactionID(nil), multisig.Proposals...), tx.ID)
+
+		ctx.WriteAccountMultisig(payload.Account, multisig)
+		ctx.WriteMultisigProposal(tx.ID, MultisigProposal{
+			Account:   payload.Account,
+			Proposer:  tx.Sender,
+			Recipient: payload.Recipient,
+			Amount:    payload.Amount,
+			Block:     block.Index + 1,
+			Approvals: []AccountID{tx.Sender},
+		})
+	case sys.ApproveMultisig:
+		proposal, _ := ctx.ReadMultisigProposal(payload.Proposal)
+		proposal.Approvals = append(append([]AccountID(nil), proposal.Approvals...), tx.Sender)
+
+		ctx.WriteMultisigProposal(payload.Proposal, proposal)
+	case sys.ExecuteMultisig, sys.CancelMultisig:
+		proposal, _ := ctx.ReadMultisigProposal(payload.Proposal)
+
+		if payload.Opcode == sys.ExecuteMultisig {
+			balance, _ := ctx.ReadAccountBalance(proposal.Account)
+			recipientBalance, _ := ctx.ReadAccountBalance(proposal.Recipient)
+
+			ctx.WriteAccountBalance(proposal.Account, balance-proposal.Amount)
+			ctx.WriteAccountBalance(proposal.Recipient, recipientBalance+proposal.Amount)
+		}
+
+		multisig, _ := ctx.ReadAccountMultisig(proposal.Account)
+		multisig.Proposals = removeMultisigProposal(multisig.Proposals, payload.Proposal)
+
+		ctx.WriteAccountMultisig(proposal.Account, multisig)
+		ctx.WriteMultisigProposal(payload.Proposal, MultisigProposal{})
+	}
+
+	return nil
+}
+
+func validateMultisigTransaction(snapshot *avl.Tree, tx Transaction) error {
+	payload, err := ParseMultisig(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	readMultisig := func(id AccountID) (MultisigAccount, bool) {
+		return ReadAccountMultisig(snapshot, id)
+	}
+
+	readProposal := func(id TransactionID) (MultisigProposal, bool) {
+		return ReadMultisigProposal(snapshot, id)
+	}
+
+	readBalance := func(id AccountID) (uint64, bool) {
+		return ReadAccountBalance(snapshot, id)
+	}
+
+	return verifyMultisig(readMultisig, readProposal, readBalance, &tx, payload)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultisigTransaction(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureMultisig)()
+
+	keys := make([]*skademlia.Keypair, 5)
+
+	for i := range keys {
+		var err error
+
+		keys[i], err = skademlia.NewKeys(1, 1)
+		require.NoError(t, err)
+	}
+
+	creator, first, second, third, recipient := keys[0], keys[1], keys[2], keys[3], keys[4]
+
+	tree := avl.New(store.NewInmem())
+
+	WriteAccountBalance(tree, recipient.PublicKey(), 10)
+
+	multisig := func(keys *skademlia.Keypair, nonce uint64, payload Multisig) Transaction {
+		buf, err := payload.Marshal()
+		require.NoError(t, err)
+
+		return NewTransaction(keys, nonce, 10, sys.TagMultisig, buf)
+	}
+
+	block := NewBlock(10, tree.Checksum())
+
+	create := multisig(creator, 1, Multisig{
+		Opcode:       sys.CreateMultisig,
+		Participants: []AccountID{first.PublicKey(), second.PublicKey(), third.PublicKey()},
+		Threshold:    2,
+	})
+	require.NoError(t, ValidateTransaction(tree, create))
+	require.NoError(t, ApplyTransaction(tree, &block, &create))
+
+	account := AccountID(create.ID)
+
+	created, exists := ReadAccountMultisig(tree, account)
+	require.True(t, exists)
+	assert.EqualValues(t, 2, created.Threshold)
+	assert.Len(t, created.Participants, 3)
+	assert.Empty(t, created.Proposals)
+
+	WriteAccountBalance(tree, account, 100)
+
+	propose := Multisig{Opcode: sys.ProposeMultisig, Account: account, Recipient: recipient.PublicKey(), Amount: 60}
+
+	// Only participants may propose transfers, and only from multisig accounts.
+	tx := multisig(recipient, 1, propose)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = multisig(first, 1, Multisig{
+		Opcode:    sys.ProposeMultisig,
+		Account:   creator.PublicKey(),
+		Recipient: recipient.PublicKey(),
+		Amount:    60,
+	})
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	proposal := multisig(first, 2, propose)
+	require.NoError(t, ValidateTransaction(tree, proposal))
+	require.NoError(t, ApplyTransaction(tree, &block, &proposal))
+
+	pending, exists := ReadMultisigProposal(tree, proposal.ID)
+	require.True(t, exists)
+	assert.Equal(t, MultisigProposal{
+		Account:   account,
+		Proposer:  first.PublicKey(),
+		Recipient: recipient.PublicKey(),
+		Amount:    60,
+		Block:     11,
+		Approvals: []AccountID{first.PublicKey()},
+	}, pending)
+
+	created, _ = ReadAccountMultisig(tree, account)
+	assert.Equal(t, []TransactionID{proposal.ID}, created.Proposals)
+
+	approve := Multisig{Opcode: sys.ApproveMultisig, Proposal: proposal.ID}
+	execute := Multisig{Opcode: sys.ExecuteMultisig, Proposal: proposal.ID}
+
+	// Proposals require the threshold of approvals to be executed.
+	tx = multisig(first, 3, execute)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// Participants may approve once, and only the participants may approve.
+	tx = multisig(first, 3, approve)
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = multisig(recipient, 1, approve)
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = multisig(second, 1, approve)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = multisig(third, 1, execute)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	balance, _ := ReadAccountBalance(tree, recipient.PublicKey())
+	assert.EqualValues(t, 70, balance)
+
+	balance, _ = ReadAccountBalance(tree, account)
+	assert.EqualValues(t, 40, balance)
+
+	_, exists = ReadMultisigProposal(tree, proposal.ID)
+	assert.False(t, exists)
+
+	created, _ = ReadAccountMultisig(tree, account)
+	assert.Empty(t, created.Proposals)
+
+	// Executed proposals may not be executed again.
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// Approved proposals are only executed should the account hold enough PERLs.
+	proposal = multisig(second, 2, propose)
+	require.NoError(t, ApplyTransaction(tree, &block, &proposal))
+
+	tx = multisig(third, 2, Multisig{Opcode: sys.ApproveMultisig, Proposal: proposal.ID})
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = multisig(first, 3, Multisig{Opcode: sys.ExecuteMultisig, Proposal: proposal.ID})
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// Only the proposer may cancel a proposal.
+	cancel := Multisig{Opcode: sys.CancelMultisig, Proposal: proposal.ID}
+
+	tx = multisig(first, 3, cancel)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = multisig(second, 3, cancel)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	_, exists = ReadMultisigProposal(tree, proposal.ID)
+	assert.False(t, exists)
+
+	created, _ = ReadAccountMultisig(tree, account)
+	assert.Empty(t, created.Proposals)
+
+	balance, _ = ReadAccountBalance(tree, account)
+	assert.EqualValues(t, 40, balance)
+}
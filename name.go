@@ -0,0 +1,117 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// The name service maps human-readable names to accounts and contracts.
+// Registering a name burns sys.NameRegistrationFee PERLs and keeps it
+// registered for sys.NameRegistrationBlocks blocks, which renewing it extends.
+// The owner of a name may point it at another target by registering it again
+// at no cost, or transfer it to another owner. Once a name expires, anyone may
+// register it.
+
+// verifyName checks that the sender of tx may act upon a name at the block at
+// index height, given its record and its balance.
+func verifyName(record NameRecord, exists bool, balance uint64, height uint64, tx *Transaction, payload Name) error {
+	live := exists && height < record.Expiry
+	owned := live && record.Owner == tx.Sender
+
+	switch payload.Opcode {
+	case sys.RegisterName:
+		if live && !owned {
+			return errors.Errorf("name: %q is already registered to %x", payload.Name, record.Owner)
+		}
+
+		if !owned && balance < sys.NameRegistrationFee {
+			return errors.Errorf(
+				"name: %x attempt to register %q for %d PERLs, but only has %d PERLs",
+				tx.Sender, payload.Name, sys.NameRegistrationFee, balance,
+			)
+		}
+	case sys.RenewName, sys.TransferName:
+		if !owned {
+			return errors.Errorf("name: %q is not registered to %x", payload.Name, tx.Sender)
+		}
+
+		if payload.Opcode == sys.RenewName && balance < sys.NameRegistrationFee {
+			return errors.Errorf(
+				"name: %x attempt to renew %q for %d PERLs, but only has %d PERLs",
+				tx.Sender, payload.Name, sys.NameRegistrationFee, balance,
+			)
+		}
+	}
+
+	return nil
+}
+
+func applyNameTransaction(ctx *CollapseContext, block *Block, tx *Transaction) error {
+	payload, err := ParseName(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	height := block.Index + 1
+
+	record, exists := ctx.ReadName(payload.Name)
+	balance, _ := ctx.ReadAccountBalance(tx.Sender)
+
+	if err := verifyName(record, exists, balance, height, tx, payload); err != nil {
+		return err
+	}
+
+	switch payload.Opcode {
+	case sys.RegisterName:
+		if exists && height < record.Expiry {
+			record.Target = payload.Target
+			break
+		}
+
+		ctx.WriteAccountBalance(tx.Sender, balance-sys.NameRegistrationFee)
+
+		record = NameRecord{Owner: tx.Sender, Target: payload.Target, Expiry: height + sys.NameRegistrationBlocks}
+	case sys.RenewName:
+		ctx.WriteAccountBalance(tx.Sender, balance-sys.NameRegistrationFee)
+
+		record.Expiry += sys.NameRegistrationBlocks
+	case sys.TransferName:
+		record.Owner = payload.Target
+	}
+
+	ctx.WriteName(payload.Name, record)
+
+	return nil
+}
+
+func validateNameTransaction(snapshot *avl.Tree, tx Transaction) error {
+	payload, err := ParseName(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	record, exists := ReadName(snapshot, payload.Name)
+	balance, _ := ReadAccountBalance(snapshot, tx.Sender)
+
+	return verifyName(record, exists, balance, tx.earliestHeight(), &tx, payload)
+}
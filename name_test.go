@@ -0,0 +1,139 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameTransaction(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureNames)()
+
+	alice, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	bob, err := skademlia.NewKeys(1, 1)
+	require.NoError(t, err)
+
+	tree := avl.New(store.NewInmem())
+
+	WriteAccountBalance(tree, alice.PublicKey(), sys.NameRegistrationFee*2)
+	WriteAccountBalance(tree, bob.PublicKey(), sys.NameRegistrationFee-1)
+
+	block := NewBlock(0, tree.Checksum())
+
+	name := func(keys *skademlia.Keypair, payload Name) Transaction {
+		buf, err := payload.Marshal()
+		require.NoError(t, err)
+
+		return NewTransaction(keys, 1, block.Index, sys.TagName, buf)
+	}
+
+	contract := AccountID{1, 2, 3}
+
+	// Registering a name burns the registration fee.
+	tx := name(bob, Name{Opcode: sys.RegisterName, Name: "alice", Target: bob.PublicKey()})
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = name(alice, Name{Opcode: sys.RegisterName, Name: "alice", Target: alice.PublicKey()})
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	balance, _ := ReadAccountBalance(tree, alice.PublicKey())
+	assert.Equal(t, sys.NameRegistrationFee, balance)
+
+	target, exists := ResolveName(tree, "alice", 1)
+	require.True(t, exists)
+	assert.EqualValues(t, alice.PublicKey(), target)
+
+	// Owners may point their names at other targets for free.
+	tx = name(alice, Name{Opcode: sys.RegisterName, Name: "alice", Target: contract})
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	target, _ = ResolveName(tree, "alice", 1)
+	assert.Equal(t, contract, target)
+
+	balance, _ = ReadAccountBalance(tree, alice.PublicKey())
+	assert.Equal(t, sys.NameRegistrationFee, balance)
+
+	// Only owners may renew or transfer names.
+	WriteAccountBalance(tree, bob.PublicKey(), sys.NameRegistrationFee*2)
+
+	for _, payload := range []Name{
+		{Opcode: sys.RegisterName, Name: "alice", Target: bob.PublicKey()},
+		{Opcode: sys.RenewName, Name: "alice"},
+		{Opcode: sys.TransferName, Name: "alice", Target: bob.PublicKey()},
+	} {
+		tx = name(bob, payload)
+		assert.Error(t, ValidateTransaction(tree, tx))
+		assert.Error(t, ApplyTransaction(tree, &block, &tx))
+	}
+
+	tx = name(alice, Name{Opcode: sys.RenewName, Name: "alice"})
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	record, _ := ReadName(tree, "alice")
+	assert.Equal(t, 1+sys.NameRegistrationBlocks*2, record.Expiry)
+
+	tx = name(alice, Name{Opcode: sys.TransferName, Name: "alice", Target: bob.PublicKey()})
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	record, _ = ReadName(tree, "alice")
+	assert.Equal(t, NameRecord{Owner: bob.PublicKey(), Target: contract, Expiry: record.Expiry}, record)
+
+	// Expired names resolve to nothing, and may be registered by anyone.
+	_, exists = ResolveName(tree, "alice", record.Expiry)
+	assert.False(t, exists)
+
+	expired := NewBlock(record.Expiry, tree.Checksum())
+
+	WriteAccountBalance(tree, alice.PublicKey(), sys.NameRegistrationFee)
+
+	tx = name(alice, Name{Opcode: sys.RegisterName, Name: "alice", Target: alice.PublicKey()})
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+	require.NoError(t, ApplyTransaction(tree, &expired, &tx))
+
+	record, _ = ReadName(tree, "alice")
+	assert.EqualValues(t, alice.PublicKey(), record.Owner)
+
+	// Name transactions may not be batched.
+	var batch Batch
+	require.NoError(t, batch.AddTransfer(Transfer{Recipient: bob.PublicKey(), Amount: 1}))
+
+	batch.Tags[0] = byte(sys.TagName)
+
+	payload, err := batch.Marshal()
+	require.NoError(t, err)
+
+	_, err = ParseBatch(payload)
+	assert.Error(t, err)
+}
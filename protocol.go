@@ -23,37 +23,140 @@ import (
 	"bytes"
 	"context"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/perlin-network/noise/skademlia"
 	"io"
+	"strings"
+	"sync"
 
 	"github.com/perlin-network/wavelet/internal/cuckoo"
 
 	"github.com/perlin-network/wavelet/conf"
 	"github.com/perlin-network/wavelet/log"
-	"github.com/perlin-network/wavelet/sys"
-	"golang.org/x/crypto/blake2b"
+	"github.com/perlin-network/wavelet/trace"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type Protocol struct {
 	ledger *Ledger
 }
 
+// screen identifies the peer making a request, rejecting the request should
+// the peer be banned, or be banned for making too many requests. Requests
+// made locally rather than by a peer are not screened.
+func (p *Protocol) screen(ctx context.Context) (*skademlia.ID, error) {
+	id, err := peerFromContext(ctx)
+	if err != nil {
+		return nil, nil
+	}
+
+	if err := p.ledger.reputation.Request(id); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// report penalizes the peer which made a request for a violation.
+func (p *Protocol) report(id *skademlia.ID, v Violation) {
+	if id != nil {
+		p.ledger.reputation.Report(id, v)
+	}
+}
+
 func (p *Protocol) Gossip(ctx context.Context, req *GossipRequest) (*empty.Empty, error) {
-	txs := make([]Transaction, 0, len(req.Transactions))
+	sender, err := p.screen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The encodings our node accepts gossip compressed with are negotiated
+	// with the sender by responding with them.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		acceptEncodingKey, strings.Join(acceptedEncodings(p.ledger.compression), ","),
+	))
+
+	if len(req.Compressed) > 0 {
+		if req, err = decompressGossip(req); err != nil {
+			logger := log.TX("gossip")
+			logger.Err(err).Str("encoding", req.Encoding).Msg("Failed to decompress gossiped transactions")
+
+			p.report(sender, ViolationInvalidTx)
 
-	for _, buf := range req.Transactions {
-		tx, err := UnmarshalTransaction(bytes.NewReader(buf))
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	txs := make([]Transaction, 0, len(req.Txs)+len(req.Transactions))
+
+	// Transactions traced by the sender join its traces.
+	md, _ := metadata.FromIncomingContext(ctx)
+	contexts := gossipedContexts(md.Get(traceMetadataKey))
+
+	add := func(tx Transaction, err error) {
 		if err != nil {
 			logger := log.TX("gossip")
 			logger.Err(err).Msg("Failed to unmarshal transaction")
 
-			continue
+			p.report(sender, ViolationInvalidTx)
+
+			return
 		}
 
 		if p.ledger.Transactions().Has(tx.ID) {
-			continue
+			return
+		}
+
+		// Signatures are verified upfront, such that no variant of a
+		// transaction forged by a relaying node takes up the mempool.
+		if !tx.VerifySignature() {
+			logger := log.TX("gossip")
+			logger.Err(ErrTxInvalidSignature).Hex("tx_id", tx.ID[:]).Msg("Rejected gossiped transaction")
+
+			p.report(sender, ViolationInvalidTx)
+
+			return
+		}
+
+		// Free transactions are admitted only as long as their stamp keeps
+		// up with the load of the network, and admission hooks may screen
+		// any transaction.
+		if err := p.ledger.AdmitTransaction(tx); err != nil {
+			logger := log.TX("gossip")
+			logger.Err(err).Hex("tx_id", tx.ID[:]).Msg("Rejected gossiped transaction")
+
+			return
 		}
 
 		txs = append(txs, tx)
+
+		if parent, traced := contexts[tx.ID]; traced {
+			span := trace.Start("gossip.receive", trace.KindConsumer, parent, TraceTx(&tx)...)
+
+			if sender != nil {
+				key := sender.PublicKey()
+				span.SetAttributes(trace.Hex("gossip.sender", key[:]))
+			}
+
+			span.End()
+
+			trace.RememberTx(tx.ID, span.Context())
+		}
+	}
+
+	for _, pb := range req.Txs {
+		add(TransactionFromProto(pb))
+	}
+
+	// Older nodes gossip binary encoded transactions only. Newer nodes
+	// gossip the same transactions in both encodings.
+	if len(req.Txs) == 0 {
+		for _, buf := range req.Transactions {
+			add(ParseTransaction(buf))
+		}
 	}
 
 	p.ledger.AddTransaction(txs...)
@@ -62,14 +165,21 @@ func (p *Protocol) Gossip(ctx context.Context, req *GossipRequest) (*empty.Empty
 }
 
 func (p *Protocol) Query(ctx context.Context, req *QueryRequest) (*QueryResponse, error) {
+	querier, err := p.screen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	res := &QueryResponse{}
 
+	if err := p.ledger.verifySample(querier, req); err != nil {
+		p.report(querier, ViolationInvalidProof)
+		return nil, err
+	}
+
 	latestBlock := p.ledger.blocks.Latest()
 
-	var (
-		block *Block
-		err   error
-	)
+	var block *Block
 
 	// Return preferred block if peer is finalizing on the same block
 	if latestBlock.Index+1 == req.BlockIndex {
@@ -105,12 +215,18 @@ func (p *Protocol) Query(ctx context.Context, req *QueryRequest) (*QueryResponse
 }
 
 func (p *Protocol) Sync(stream Wavelet_SyncServer) error {
+	if _, err := p.screen(stream.Context()); err != nil {
+		return err
+	}
+
 	req, err := stream.Recv()
 	if err != nil {
 		return err
 	}
 
-	res := &SyncResponse{}
+	if req.GetFullState() {
+		return p.syncFullState(stream)
+	}
 
 	header := &SyncInfo{Block: p.ledger.blocks.Latest().Marshal()}
 	diffBuffer := p.ledger.filePool.GetUnbounded()
@@ -132,90 +248,70 @@ func (p *Protocol) Sync(stream Wavelet_SyncServer) error {
 		return err
 	}
 
-	type chunkInfo struct {
-		Idx  int
-		Size int
-	}
-
-	chunks := map[[blake2b.Size256]byte]chunkInfo{}
-
 	// Chunk dumped diff
-	syncChunkSize := conf.GetSyncChunkSize()
-	chunkBuf := make([]byte, syncChunkSize)
-
-	var i int
-
-	for {
-		n, err := chunksBuffer.ReadAt(chunkBuf, int64(i*syncChunkSize))
-		if n > 0 {
-			chunk := make([]byte, n)
-			copy(chunk, chunkBuf[:n])
-			checksum := blake2b.Sum256(chunk)
-			header.Checksums = append(header.Checksums, checksum[:])
-
-			chunks[checksum] = chunkInfo{Idx: i, Size: n}
-			i++
-		}
-
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return err
-		}
+	checksums, chunks, err := splitChunks(chunksBuffer, conf.GetSyncChunkSize())
+	if err != nil {
+		return err
 	}
 
-	res.Data = &SyncResponse_Header{Header: header}
+	header.Checksums = checksums
 
-	if err := stream.Send(res); err != nil {
+	if err := stream.Send(&SyncResponse{Data: &SyncResponse_Header{Header: header}}); err != nil {
 		return err
 	}
 
-	res.Data = &SyncResponse_Chunk{}
-
-	for {
-		req, err := stream.Recv()
+	return serveChunks(stream, chunksBuffer, &sync.Mutex{}, chunks)
+}
 
-		if err != nil {
-			return err
-		}
+// syncFullState serves all of the latest finalized state of the ledger to a
+// peer syncing its state from scratch, out of chunks shared by all peers
+// syncing to the same state.
+func (p *Protocol) syncFullState(stream Wavelet_SyncServer) error {
+	snapshot := p.ledger.accounts.Snapshot()
 
-		var checksum [blake2b.Size256]byte
+	block, err := p.ledger.blocks.committed(snapshot.Checksum())
+	if err != nil {
+		return err
+	}
 
-		copy(checksum[:], req.GetChecksum())
+	state, err := p.ledger.stateChunks.acquire(snapshot, *block)
+	if err != nil {
+		return err
+	}
 
-		info, ok := chunks[checksum]
-		if !ok {
-			res.Data.(*SyncResponse_Chunk).Chunk = nil
+	defer p.ledger.stateChunks.release(state)
 
-			if err = stream.Send(res); err != nil {
-				return err
-			}
-		}
+	if err := stream.Send(&SyncResponse{Data: &SyncResponse_Header{Header: state.header}}); err != nil {
+		return err
+	}
 
-		if _, err := chunksBuffer.ReadAt(chunkBuf[:info.Size], int64(info.Idx*sys.SyncChunkSize)); err != nil {
-			return err
-		}
+	return serveChunks(stream, state.buf, &state.readLock, state.chunks)
+}
 
-		logger := log.Sync("provide_chunk")
-		logger.Info().
-			Hex("requested_hash", req.GetChecksum()).
-			Msg("Responded to sync chunk request.")
+func (p *Protocol) CheckOutOfSync(ctx context.Context, req *OutOfSyncRequest) (*OutOfSyncResponse, error) {
+	if _, err := p.screen(ctx); err != nil {
+		return nil, err
+	}
 
-		res.Data.(*SyncResponse_Chunk).Chunk = chunkBuf[:info.Size]
+	res := &OutOfSyncResponse{
+		OutOfSync: p.ledger.blocks.Latest().Index >= conf.GetSyncIfBlockIndicesDifferBy()+req.BlockIndex,
+	}
 
-		if err = stream.Send(res); err != nil {
-			return err
-		}
+	// Advertise the snapshot of our latest state we serve in full, for new
+	// nodes to sync from peers serving the same one.
+	if block, err := p.ledger.blocks.committed(p.ledger.accounts.Snapshot().Checksum()); err == nil {
+		res.SnapshotIndex = block.Index
+		res.SnapshotBlockId = block.ID[:]
 	}
-}
 
-func (p *Protocol) CheckOutOfSync(ctx context.Context, req *OutOfSyncRequest) (*OutOfSyncResponse, error) {
-	return &OutOfSyncResponse{
-		OutOfSync: p.ledger.blocks.Latest().Index >= conf.GetSyncIfBlockIndicesDifferBy()+req.BlockIndex,
-	}, nil
+	return res, nil
 }
 
 func (p *Protocol) SyncTransactions(stream Wavelet_SyncTransactionsServer) error {
+	if _, err := p.screen(stream.Context()); err != nil {
+		return err
+	}
+
 	req, err := stream.Recv()
 	if err != nil {
 		return err
@@ -276,6 +372,10 @@ func (p *Protocol) SyncTransactions(stream Wavelet_SyncTransactionsServer) error
 func (p *Protocol) PullTransactions(
 	ctx context.Context, req *TransactionPullRequest,
 ) (*TransactionPullResponse, error) {
+	if _, err := p.screen(ctx); err != nil {
+		return nil, err
+	}
+
 	res := &TransactionPullResponse{
 		Transactions: make([][]byte, 0, len(req.TransactionIds)),
 	}
@@ -299,3 +399,49 @@ func (p *Protocol) PullTransactions(
 
 	return res, nil
 }
+
+// ExchangePeers provides the addresses of the healthy peers our node is
+// connected to, such that the requester may connect to them.
+func (p *Protocol) ExchangePeers(ctx context.Context, req *PeerExchangeRequest) (*PeerExchangeResponse, error) {
+	requester, err := p.screen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 || limit > maxExchangedPeers {
+		limit = maxExchangedPeers
+	}
+
+	res := &PeerExchangeResponse{Addresses: make([]string, 0, limit)}
+
+	for _, addr := range p.ledger.discovery.Healthy(limit + 1) {
+		if requester != nil && addr == requester.Address() {
+			continue
+		}
+
+		if len(res.Addresses) < limit {
+			res.Addresses = append(res.Addresses, addr)
+		}
+	}
+
+	return res, nil
+}
+
+// decompressGossip decompresses the batch of transactions compressed in req.
+func decompressGossip(req *GossipRequest) (*GossipRequest, error) {
+	buf, err := decompress(req.Encoding, req.Compressed)
+	if err != nil {
+		return req, err
+	}
+
+	batch := new(GossipRequest)
+
+	if err := batch.Unmarshal(buf); err != nil {
+		return req, errors.Wrap(err, "failed to unmarshal decompressed batch")
+	}
+
+	batch.Encoding, batch.Compressed = "", nil
+
+	return batch, nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+)
+
+// PruningStatus reports on the pruning of the data our node keeps of past
+// blocks.
+type PruningStatus struct {
+	Enabled bool
+
+	// RetainedBlocks is the number of latest blocks whose data is retained.
+	RetainedBlocks uint64
+
+	// RetainedFrom is the index of the oldest block whose data is retained.
+	RetainedFrom uint64
+
+	// PrunedDiffs is the number of transaction diffs pruned since our node
+	// started.
+	PrunedDiffs uint64
+
+	// CompactInterval is how often the database is compacted, or 0 if it is
+	// not.
+	CompactInterval time.Duration
+
+	Compactions       uint64
+	LastCompaction    time.Time
+	LastCompactionErr string
+}
+
+// Pruner prunes the data our node keeps of blocks older than the latest
+// retained ones for its own API, such as the state diffs of transactions,
+// and compacts the database on a schedule, such that the disk usage of
+// long-lived nodes stays bounded. The ledger state itself only keeps its
+// latest version, and is garbage collected by Accounts.
+type Pruner struct {
+	kv store.KV
+
+	lock   sync.Mutex
+	status PruningStatus
+}
+
+// NewPruner returns a pruner retaining the data of the latest retain blocks
+// stored in kv, compacting kv every compactInterval should it be non-zero.
+func NewPruner(kv store.KV, retain uint64, compactInterval time.Duration) *Pruner {
+	if retain == 0 {
+		retain = 1
+	}
+
+	return &Pruner{
+		kv: kv,
+		status: PruningStatus{
+			Enabled:         true,
+			RetainedBlocks:  retain,
+			RetainedFrom:    ReadPrunedHeight(kv),
+			CompactInterval: compactInterval,
+		},
+	}
+}
+
+// Prune prunes the data of the blocks falling out of the retained ones, the
+// block with index height being the latest.
+func (p *Pruner) Prune(height uint64) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if height+1 < p.status.RetainedBlocks {
+		return nil
+	}
+
+	until := height + 1 - p.status.RetainedBlocks
+
+	for block := p.status.RetainedFrom; block < until; block++ {
+		n, err := PruneTransactionDiffs(p.kv, block)
+		if err != nil {
+			return errors.Wrapf(err, "failed to prune the data of block %d", block)
+		}
+
+		p.status.PrunedDiffs += uint64(n)
+		p.status.RetainedFrom = block + 1
+
+		if err := WritePrunedHeight(p.kv, p.status.RetainedFrom); err != nil {
+			return errors.Wrap(err, "failed to save pruned height")
+		}
+	}
+
+	return nil
+}
+
+// Compact compacts the database, should it support being compacted.
+func (p *Pruner) Compact() error {
+	compactor, ok := p.kv.(store.Compactor)
+	if !ok {
+		return nil
+	}
+
+	err := compactor.Compact()
+
+	p.lock.Lock()
+
+	p.status.Compactions++
+	p.status.LastCompaction = time.Now()
+	p.status.LastCompactionErr = ""
+
+	if err != nil {
+		p.status.LastCompactionErr = err.Error()
+	}
+
+	p.lock.Unlock()
+
+	return err
+}
+
+// Run compacts the database every compaction interval until ctx is done.
+func (p *Pruner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	interval := p.Status().CompactInterval
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Compact(); err != nil {
+				logger := log.Node()
+				logger.Warn().Err(err).Msg("Failed to compact the database.")
+			}
+		}
+	}
+}
+
+func (p *Pruner) Status() PruningStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.status
+}
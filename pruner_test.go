@@ -0,0 +1,73 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruner(t *testing.T) {
+	kv, err := store.NewLevelDB("")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer kv.Close()
+
+	for block := uint64(0); block < 10; block++ {
+		diffs := []TransactionDiff{{ID: TransactionID{byte(block), 1}}, {ID: TransactionID{byte(block), 2}}}
+		assert.NoError(t, StoreTransactionDiffs(kv, block, diffs))
+	}
+
+	pruner := NewPruner(kv, 3, 0)
+
+	// Nothing is pruned while there are no more blocks than those retained.
+	assert.NoError(t, pruner.Prune(2))
+	assert.EqualValues(t, 0, pruner.Status().RetainedFrom)
+
+	assert.NoError(t, pruner.Prune(9))
+
+	status := pruner.Status()
+	assert.True(t, status.Enabled)
+	assert.EqualValues(t, 7, status.RetainedFrom)
+	assert.EqualValues(t, 14, status.PrunedDiffs)
+
+	for block := uint64(0); block < 10; block++ {
+		_, err := LoadTransactionDiff(kv, TransactionID{byte(block), 2})
+
+		if block < 7 {
+			assert.Equal(t, store.ErrNotFound, errors.Cause(err))
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	// Pruning resumes from where it stopped.
+	assert.EqualValues(t, 7, NewPruner(kv, 3, 0).Status().RetainedFrom)
+
+	assert.NoError(t, pruner.Compact())
+	assert.EqualValues(t, 1, pruner.Status().Compactions)
+}
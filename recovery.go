@@ -0,0 +1,198 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// Social recovery lets the guardians of an account move its PERLs to a new
+// account should its keys be lost. An account opts in by configuring a set of
+// guardians, a threshold, and a delay. A guardian then initiates a recovery
+// to a recipient, which the other guardians approve. Once the threshold of
+// guardians approved and the delay, counted in blocks, elapsed since the
+// recovery was initiated, anyone may complete it, moving the balance, stake
+// and rewards of the account to the recipient.
+//
+// The delay gives the owner of the account time to cancel recoveries it did
+// not ask for. Configuring the account anew also cancels a pending recovery.
+
+// verifyRecovery checks that the sender of tx may act upon the recovery of an
+// account at the block at index height.
+func verifyRecovery(
+	readConfig func(AccountID) (RecoveryConfig, bool), readPending func(AccountID) (PendingRecovery, bool),
+	height uint64, tx *Transaction, payload Recovery,
+) error {
+	if payload.Opcode == sys.ConfigureRecovery {
+		if isGuardian(payload.Guardians, tx.Sender) {
+			return errors.Errorf("recovery: %x may not guard itself", tx.Sender)
+		}
+
+		return nil
+	}
+
+	if payload.Opcode == sys.CancelRecovery {
+		if _, pending := readPending(tx.Sender); !pending {
+			return errors.Errorf("recovery: %x has no pending recovery to cancel", tx.Sender)
+		}
+
+		return nil
+	}
+
+	config, exists := readConfig(payload.Account)
+	if !exists {
+		return errors.Errorf("recovery: %x has not configured any guardians", payload.Account)
+	}
+
+	recovery, pending := readPending(payload.Account)
+
+	switch payload.Opcode {
+	case sys.InitiateRecovery:
+		if !isGuardian(config.Guardians, tx.Sender) {
+			return errors.Errorf("recovery: %x is not a guardian of %x", tx.Sender, payload.Account)
+		}
+
+		if pending {
+			return errors.Errorf("recovery: %x is already being recovered to %x", payload.Account, recovery.Recipient)
+		}
+	case sys.ApproveRecovery:
+		if !isGuardian(config.Guardians, tx.Sender) {
+			return errors.Errorf("recovery: %x is not a guardian of %x", tx.Sender, payload.Account)
+		}
+
+		if !pending || recovery.Recipient != payload.Recipient {
+			return errors.Errorf("recovery: %x is not being recovered to %x", payload.Account, payload.Recipient)
+		}
+
+		if isGuardian(recovery.Approvals, tx.Sender) {
+			return errors.Errorf("recovery: %x already approved the recovery of %x", tx.Sender, payload.Account)
+		}
+	case sys.CompleteRecovery:
+		if !pending {
+			return errors.Errorf("recovery: %x is not being recovered", payload.Account)
+		}
+
+		if len(recovery.Approvals) < int(config.Threshold) {
+			return errors.Errorf(
+				"recovery: recovering %x requires %d approvals, but only has %d",
+				payload.Account, config.Threshold, len(recovery.Approvals),
+			)
+		}
+
+		if height < recovery.Block+config.Delay {
+			return errors.Errorf(
+				"recovery: recovering %x may only complete at block %d", payload.Account, recovery.Block+config.Delay,
+			)
+		}
+	}
+
+	return nil
+}
+
+func isGuardian(guardians []AccountID, id AccountID) bool {
+	for _, guardian := range guardians {
+		if guardian == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applyRecoveryTransaction(ctx *CollapseContext, block *Block, tx *Transaction) error {
+	payload, err := ParseRecovery(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	height := block.Index + 1
+
+	if err := verifyRecovery(ctx.ReadAccountRecoveryConfig, ctx.ReadAccountPendingRecovery, height, tx, payload); err != nil {
+		return err
+	}
+
+	switch payload.Opcode {
+	case sys.ConfigureRecovery:
+		ctx.WriteAccountRecoveryConfig(tx.Sender, RecoveryConfig{
+			Guardians: payload.Guardians,
+			Threshold: payload.Threshold,
+			Delay:     payload.Delay,
+		})
+
+		if _, pending := ctx.ReadAccountPendingRecovery(tx.Sender); pending {
+			ctx.WriteAccountPendingRecovery(tx.Sender, PendingRecovery{})
+		}
+	case sys.InitiateRecovery:
+		ctx.WriteAccountPendingRecovery(payload.Account, PendingRecovery{
+			Recipient: payload.Recipient,
+			Block:     height,
+			Approvals: []AccountID{tx.Sender},
+		})
+	case sys.ApproveRecovery:
+		recovery, _ := ctx.ReadAccountPendingRecovery(payload.Account)
+		recovery.Approvals = append(append([]AccountID(nil), recovery.Approvals...), tx.Sender)
+
+		ctx.WriteAccountPendingRecovery(payload.Account, recovery)
+	case sys.CancelRecovery:
+		ctx.WriteAccountPendingRecovery(tx.Sender, PendingRecovery{})
+	case sys.CompleteRecovery:
+		recovery, _ := ctx.ReadAccountPendingRecovery(payload.Account)
+
+		balance, _ := ctx.ReadAccountBalance(payload.Account)
+		stake, _ := ctx.ReadAccountStake(payload.Account)
+		reward, _ := ctx.ReadAccountReward(payload.Account)
+
+		recipientBalance, _ := ctx.ReadAccountBalance(recovery.Recipient)
+		recipientStake, _ := ctx.ReadAccountStake(recovery.Recipient)
+		recipientReward, _ := ctx.ReadAccountReward(recovery.Recipient)
+
+		ctx.WriteAccountBalance(recovery.Recipient, recipientBalance+balance)
+		ctx.WriteAccountStake(recovery.Recipient, recipientStake+stake)
+		ctx.WriteAccountReward(recovery.Recipient, recipientReward+reward)
+
+		ctx.WriteAccountBalance(payload.Account, 0)
+		ctx.WriteAccountStake(payload.Account, 0)
+		ctx.WriteAccountReward(payload.Account, 0)
+
+		ctx.WriteAccountRecoveryConfig(payload.Account, RecoveryConfig{})
+		ctx.WriteAccountPendingRecovery(payload.Account, PendingRecovery{})
+	}
+
+	return nil
+}
+
+func validateRecoveryTransaction(snapshot *avl.Tree, tx Transaction) error {
+	payload, err := ParseRecovery(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	readConfig := func(id AccountID) (RecoveryConfig, bool) {
+		return ReadAccountRecoveryConfig(snapshot, id)
+	}
+
+	readPending := func(id AccountID) (PendingRecovery, bool) {
+		return ReadAccountPendingRecovery(snapshot, id)
+	}
+
+	return verifyRecovery(readConfig, readPending, tx.earliestHeight(), &tx, payload)
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryTransaction(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureRecovery)()
+
+	keys := make([]*skademlia.Keypair, 5)
+
+	for i := range keys {
+		var err error
+
+		keys[i], err = skademlia.NewKeys(1, 1)
+		require.NoError(t, err)
+	}
+
+	owner, first, second, third, recipient := keys[0], keys[1], keys[2], keys[3], keys[4]
+
+	tree := avl.New(store.NewInmem())
+
+	WriteAccountBalance(tree, owner.PublicKey(), 1000)
+	WriteAccountStake(tree, owner.PublicKey(), sys.MinimumStake)
+	WriteAccountBalance(tree, recipient.PublicKey(), 10)
+
+	recover := func(keys *skademlia.Keypair, index uint64, recovery Recovery) Transaction {
+		payload, err := recovery.Marshal()
+		require.NoError(t, err)
+
+		return NewTransaction(keys, 1, index, sys.TagRecovery, payload)
+	}
+
+	initiate := Recovery{Opcode: sys.InitiateRecovery, Account: owner.PublicKey(), Recipient: recipient.PublicKey()}
+	approve := Recovery{Opcode: sys.ApproveRecovery, Account: owner.PublicKey(), Recipient: recipient.PublicKey()}
+	complete := Recovery{Opcode: sys.CompleteRecovery, Account: owner.PublicKey()}
+
+	block := NewBlock(10, tree.Checksum())
+
+	// Accounts may not be recovered before configuring guardians.
+	tx := recover(first, 10, initiate)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// Accounts may not guard themselves.
+	tx = recover(owner, 10, Recovery{
+		Opcode:    sys.ConfigureRecovery,
+		Guardians: []AccountID{owner.PublicKey(), first.PublicKey()},
+		Threshold: 1,
+	})
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = recover(owner, 10, Recovery{
+		Opcode:    sys.ConfigureRecovery,
+		Guardians: []AccountID{first.PublicKey(), second.PublicKey(), third.PublicKey()},
+		Threshold: 2,
+		Delay:     5,
+	})
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	config, exists := ReadAccountRecoveryConfig(tree, owner.PublicKey())
+	require.True(t, exists)
+	assert.EqualValues(t, 2, config.Threshold)
+	assert.EqualValues(t, 5, config.Delay)
+	assert.Len(t, config.Guardians, 3)
+
+	// Only guardians may initiate recoveries.
+	tx = recover(recipient, 10, initiate)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = recover(first, 10, initiate)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	recovery, exists := ReadAccountPendingRecovery(tree, owner.PublicKey())
+	require.True(t, exists)
+	assert.Equal(t, PendingRecovery{
+		Recipient: recipient.PublicKey(),
+		Block:     11,
+		Approvals: []AccountID{first.PublicKey()},
+	}, recovery)
+
+	// Guardians may approve once, and only the pending recipient.
+	tx = recover(first, 10, approve)
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = recover(second, 10, Recovery{
+		Opcode:    sys.ApproveRecovery,
+		Account:   owner.PublicKey(),
+		Recipient: second.PublicKey(),
+	})
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// The owner may cancel recoveries during the delay.
+	tx = recover(owner, 10, Recovery{Opcode: sys.CancelRecovery})
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	_, exists = ReadAccountPendingRecovery(tree, owner.PublicKey())
+	assert.False(t, exists)
+
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = recover(first, 10, initiate)
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	// Recoveries require the threshold of approvals.
+	tx = recover(recipient, 20, complete)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	tx = recover(third, 10, approve)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	// Recoveries complete only once the delay elapsed.
+	tx = recover(recipient, 10, complete)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	later := NewBlock(15, tree.Checksum())
+
+	tx = recover(recipient, 15, complete)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &later, &tx))
+
+	balance, _ := ReadAccountBalance(tree, recipient.PublicKey())
+	assert.EqualValues(t, 1010, balance)
+
+	stake, _ := ReadAccountStake(tree, recipient.PublicKey())
+	assert.EqualValues(t, sys.MinimumStake, stake)
+
+	balance, _ = ReadAccountBalance(tree, owner.PublicKey())
+	assert.EqualValues(t, 0, balance)
+
+	_, exists = ReadAccountRecoveryConfig(tree, owner.PublicKey())
+	assert.False(t, exists)
+
+	_, exists = ReadAccountPendingRecovery(tree, owner.PublicKey())
+	assert.False(t, exists)
+
+	// Recovery transactions may not be batched.
+	var batch Batch
+	require.NoError(t, batch.AddTransfer(Transfer{Recipient: recipient.PublicKey(), Amount: 1}))
+
+	batch.Tags[0] = byte(sys.TagRecovery)
+
+	payload, err := batch.Marshal()
+	require.NoError(t, err)
+
+	_, err = ParseBatch(payload)
+	assert.Error(t, err)
+}
@@ -0,0 +1,501 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This file holds a slow, deliberately naive reference implementation of how
+// a block of transfers and stake operations moves balances, stakes, rewards
+// and fees, against which collapseTransactions is differentially tested.
+//
+// The reference keeps every quantity as a big.Int in plain maps, with no
+// caching and no tree, such that an overflow or a stale read in the
+// production path shows up as a difference rather than being reproduced.
+// It does not model contracts, beacons, or transactions sent by the faucet.
+
+type refState struct {
+	balances map[AccountID]*big.Int
+	stakes   map[AccountID]*big.Int
+	rewards  map[AccountID]*big.Int
+}
+
+type refWithdrawal struct {
+	account AccountID
+	amount  uint64
+	block   uint64
+}
+
+func newRefState() *refState {
+	return &refState{
+		balances: make(map[AccountID]*big.Int),
+		stakes:   make(map[AccountID]*big.Int),
+		rewards:  make(map[AccountID]*big.Int),
+	}
+}
+
+func refGet(m map[AccountID]*big.Int, id AccountID) *big.Int {
+	if v, ok := m[id]; ok {
+		return new(big.Int).Set(v)
+	}
+
+	return new(big.Int)
+}
+
+func refSet(m map[AccountID]*big.Int, id AccountID, v *big.Int) {
+	m[id] = new(big.Int).Set(v)
+}
+
+func (s *refState) clone() *refState {
+	c := newRefState()
+
+	for id, v := range s.balances {
+		refSet(c.balances, id, v)
+	}
+
+	for id, v := range s.stakes {
+		refSet(c.stakes, id, v)
+	}
+
+	for id, v := range s.rewards {
+		refSet(c.rewards, id, v)
+	}
+
+	return c
+}
+
+// refFee is the fee of a transaction: 5% of the size of its payload, rounded
+// down, and no less than sys.DefaultTransactionFee.
+func refFee(tx *Transaction) *big.Int {
+	fee := uint64(len(tx.Payload)) * 5 / 100
+	if fee < sys.DefaultTransactionFee {
+		fee = sys.DefaultTransactionFee
+	}
+
+	return new(big.Int).Add(new(big.Int).SetUint64(fee), new(big.Int).SetUint64(tx.Tip))
+}
+
+// refApplyBlock applies txs in order to a copy of s. It returns the new
+// state, whether each transaction was applied, and the rewards requested to
+// be withdrawn.
+func refApplyBlock(s *refState, index uint64, txs []*Transaction) (*refState, []bool, []refWithdrawal) {
+	s = s.clone()
+
+	applied := make([]bool, len(txs))
+	withdrawals := []refWithdrawal{}
+
+	totalFee := new(big.Int)
+	totalWeight := new(big.Int)
+
+	var (
+		stakers []AccountID
+		weights = make(map[AccountID]*big.Int)
+	)
+
+	for i, tx := range txs {
+		// The fee is paid first, and is kept even if the transaction turns out
+		// to be invalid.
+		fee := refFee(tx)
+
+		balance := refGet(s.balances, tx.Sender)
+		if balance.Cmp(fee) < 0 {
+			continue
+		}
+
+		refSet(s.balances, tx.Sender, balance.Sub(balance, fee))
+		totalFee.Add(totalFee, fee)
+
+		// Each transaction of a validator weighs its share of the fees by its
+		// stake at the time the transaction is applied.
+		if stake := refGet(s.stakes, tx.Sender); stake.Cmp(new(big.Int).SetUint64(sys.MinimumStake)) >= 0 {
+			if _, exists := weights[tx.Sender]; !exists {
+				stakers = append(stakers, tx.Sender)
+				weights[tx.Sender] = new(big.Int)
+			}
+
+			weights[tx.Sender].Add(weights[tx.Sender], stake)
+			totalWeight.Add(totalWeight, stake)
+		}
+
+		applied[i] = refApplyTransaction(s, index, tx.Sender, tx.Tag, tx.Payload, &withdrawals)
+	}
+
+	// Fees are shared among validators by weight. The protocol computes
+	// shares in float64, which the reference must reproduce bit for bit.
+	if totalWeight.Sign() > 0 {
+		for _, id := range stakers {
+			share := float64(totalFee.Uint64()) * (float64(weights[id].Uint64()) / float64(totalWeight.Uint64()))
+
+			reward := refGet(s.rewards, id)
+			refSet(s.rewards, id, reward.Add(reward, new(big.Int).SetUint64(uint64(share))))
+		}
+	}
+
+	return s, applied, withdrawals
+}
+
+// refApplyTransaction applies a single transaction, or the entry of a batch,
+// and reports whether it was applied. The entries of a batch are applied in
+// order up to the first invalid one, the effects of those before it being
+// kept.
+func refApplyTransaction(s *refState, index uint64, sender AccountID, tag sys.Tag, data []byte, withdrawals *[]refWithdrawal) bool {
+	switch tag {
+	case sys.TagTransfer:
+		payload, err := ParseTransfer(data)
+		if err != nil || payload.GasLimit > 0 || len(payload.FuncName) > 0 || len(payload.FuncParams) > 0 {
+			return false
+		}
+
+		amount := new(big.Int).SetUint64(payload.Amount)
+
+		balance := refGet(s.balances, sender)
+		if balance.Cmp(amount) < 0 {
+			return false
+		}
+
+		refSet(s.balances, sender, balance.Sub(balance, amount))
+
+		recipient := refGet(s.balances, payload.Recipient)
+		refSet(s.balances, payload.Recipient, recipient.Add(recipient, amount))
+
+		return true
+	case sys.TagStake:
+		payload, err := ParseStake(data)
+		if err != nil {
+			return false
+		}
+
+		amount := new(big.Int).SetUint64(payload.Amount)
+
+		balance := refGet(s.balances, sender)
+		stake := refGet(s.stakes, sender)
+		reward := refGet(s.rewards, sender)
+
+		switch payload.Opcode {
+		case sys.PlaceStake:
+			if balance.Cmp(amount) < 0 {
+				return false
+			}
+
+			refSet(s.balances, sender, balance.Sub(balance, amount))
+			refSet(s.stakes, sender, stake.Add(stake, amount))
+		case sys.WithdrawStake:
+			if stake.Cmp(amount) < 0 {
+				return false
+			}
+
+			refSet(s.stakes, sender, stake.Sub(stake, amount))
+			refSet(s.balances, sender, balance.Add(balance, amount))
+		case sys.WithdrawReward:
+			if reward.Cmp(amount) < 0 {
+				return false
+			}
+
+			refSet(s.rewards, sender, reward.Sub(reward, amount))
+			*withdrawals = append(*withdrawals, refWithdrawal{account: sender, amount: payload.Amount, block: index})
+		}
+
+		return true
+	case sys.TagBatch:
+		payload, err := ParseBatch(data)
+		if err != nil {
+			return false
+		}
+
+		for i := range payload.Payloads {
+			if !refApplyTransaction(s, index, sender, sys.Tag(payload.Tags[i]), payload.Payloads[i], withdrawals) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// refScenario is a randomly generated genesis and sequence of blocks of
+// transfers and stake operations between a handful of accounts.
+type refScenario struct {
+	accounts []AccountID
+	genesis  *refState
+	blocks   [][]*Transaction
+}
+
+func (refScenario) Generate(r *rand.Rand, size int) reflect.Value {
+	sc := refScenario{genesis: newRefState()}
+
+	// Most scenarios are of small amounts, but some put the total supply
+	// right below the largest amount representable.
+	supply := uint64(1000000)
+	if r.Intn(4) == 0 {
+		supply = math.MaxUint64
+	}
+
+	remaining := supply
+
+	numAccounts := 2 + r.Intn(6)
+
+	for i := 0; i < numAccounts; i++ {
+		var id AccountID
+		r.Read(id[:])
+
+		sc.accounts = append(sc.accounts, id)
+
+		for _, m := range []map[AccountID]*big.Int{sc.genesis.balances, sc.genesis.stakes, sc.genesis.rewards} {
+			amount := refAmount(r, remaining)
+			remaining -= amount
+
+			refSet(m, id, new(big.Int).SetUint64(amount))
+		}
+	}
+
+	numBlocks := 1 + r.Intn(4)
+
+	for i := 0; i < numBlocks; i++ {
+		var block []*Transaction
+
+		numTxs := r.Intn(2 + size)
+
+		for j := 0; j < numTxs; j++ {
+			sender := sc.accounts[r.Intn(len(sc.accounts))]
+			tag, payload := refPayload(r, sc.accounts, supply, r.Intn(8) != 0)
+
+			block = append(block, &Transaction{Sender: sender, Nonce: uint64(j), Tag: tag, Payload: payload})
+		}
+
+		sc.blocks = append(sc.blocks, block)
+	}
+
+	return reflect.ValueOf(sc)
+}
+
+// refAmount returns an amount of at most max, biased towards the edge cases
+// of the state transition rules.
+func refAmount(r *rand.Rand, max uint64) uint64 {
+	var amount uint64
+
+	switch r.Intn(6) {
+	case 0:
+		amount = 0
+	case 1:
+		amount = sys.MinimumStake - 1 + uint64(r.Intn(3))
+	case 2:
+		amount = max
+	case 3:
+		amount = uint64(r.Intn(1000))
+	default:
+		amount = r.Uint64()
+	}
+
+	if amount > max {
+		amount = r.Uint64() % (max/2 + 1)
+	}
+
+	return amount
+}
+
+// refPayload returns a random transfer, stake or batch payload. Stake
+// payloads are only valid ones, for ParseStake rejects others at
+// verification already.
+func refPayload(r *rand.Rand, accounts []AccountID, supply uint64, batchable bool) (sys.Tag, []byte) {
+	var (
+		payload Payload
+		err     error
+	)
+
+	switch n := r.Intn(8); {
+	case n < 4:
+		payload = Transfer{Recipient: accounts[r.Intn(len(accounts))], Amount: refAmount(r, supply)}
+	case n < 7:
+		stake := Stake{Opcode: byte(r.Intn(3)), Amount: refAmount(r, supply)}
+
+		if stake.Amount == 0 {
+			stake.Amount = 1
+		}
+
+		if stake.Opcode == sys.WithdrawReward && stake.Amount < sys.MinimumRewardWithdraw {
+			stake.Amount = sys.MinimumRewardWithdraw
+		}
+
+		payload = stake
+	default:
+		if !batchable {
+			return refPayload(r, accounts, supply, false)
+		}
+
+		var batch Batch
+
+		for i := 0; i < 1+r.Intn(4); i++ {
+			switch entry := refEntry(r, accounts, supply).(type) {
+			case Transfer:
+				err = batch.AddTransfer(entry)
+			case Stake:
+				err = batch.AddStake(entry)
+			}
+
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		payload = batch
+	}
+
+	buf, err := payload.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return payload.Tag(), buf
+}
+
+func refEntry(r *rand.Rand, accounts []AccountID, supply uint64) Payload {
+	tag, buf := refPayload(r, accounts, supply, false)
+
+	payload, err := ParsePayload(tag, buf)
+	if err != nil {
+		panic(err)
+	}
+
+	return payload
+}
+
+// checkAgainstReference runs a scenario through both collapseTransactions and
+// the reference implementation, and reports the first difference between
+// them.
+func checkAgainstReference(sc refScenario) error {
+	accounts := NewAccounts(store.NewInmem())
+
+	tree := accounts.Snapshot()
+
+	for _, id := range sc.accounts {
+		WriteAccountBalance(tree, id, sc.genesis.balances[id].Uint64())
+		WriteAccountStake(tree, id, sc.genesis.stakes[id].Uint64())
+		WriteAccountReward(tree, id, sc.genesis.rewards[id].Uint64())
+	}
+
+	if err := accounts.Commit(tree); err != nil {
+		return err
+	}
+
+	ref := sc.genesis
+
+	for i, txs := range sc.blocks {
+		index := uint64(i) + 1
+
+		block := NewBlock(index, accounts.Snapshot().Checksum())
+
+		res, err := collapseTransactions(index, txs, &block, accounts)
+		if err != nil {
+			return err
+		}
+
+		var (
+			applied     []bool
+			withdrawals []refWithdrawal
+		)
+
+		ref, applied, withdrawals = refApplyBlock(ref, index, txs)
+
+		appliedSet := make(map[*Transaction]struct{}, len(res.applied))
+		for _, tx := range res.applied {
+			appliedSet[tx] = struct{}{}
+		}
+
+		for j, tx := range txs {
+			if _, ok := appliedSet[tx]; ok != applied[j] {
+				return fmt.Errorf("block %d: transaction %d applied: got %t, reference %t", index, j, ok, applied[j])
+			}
+		}
+
+		got := make([]refWithdrawal, 0, len(res.ctx.rewardWithdrawalRequests))
+		for _, rw := range res.ctx.rewardWithdrawalRequests {
+			got = append(got, refWithdrawal{account: rw.account, amount: rw.amount, block: rw.blockIndex})
+		}
+
+		if !reflect.DeepEqual(got, withdrawals) {
+			return fmt.Errorf("block %d: reward withdrawals: got %v, reference %v", index, got, withdrawals)
+		}
+
+		for _, id := range sc.accounts {
+			for _, field := range []struct {
+				name string
+				read func(AccountID) (uint64, bool)
+				want *big.Int
+			}{
+				{"balance", func(id AccountID) (uint64, bool) { return ReadAccountBalance(res.snapshot, id) }, refGet(ref.balances, id)},
+				{"stake", func(id AccountID) (uint64, bool) { return ReadAccountStake(res.snapshot, id) }, refGet(ref.stakes, id)},
+				{"reward", func(id AccountID) (uint64, bool) { return ReadAccountReward(res.snapshot, id) }, refGet(ref.rewards, id)},
+			} {
+				if !field.want.IsUint64() {
+					return fmt.Errorf("block %d: %s of %x overflows in the reference: %s", index, field.name, id, field.want)
+				}
+
+				if got, _ := field.read(id); got != field.want.Uint64() {
+					return fmt.Errorf("block %d: %s of %x: got %d, reference %s", index, field.name, id, got, field.want)
+				}
+			}
+		}
+
+		if err := accounts.Commit(res.snapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestCollapseMatchesReference(t *testing.T) {
+	fn := func(sc refScenario) bool {
+		if err := checkAgainstReference(sc); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		return true
+	}
+
+	assert.NoError(t, quick.Check(fn, &quick.Config{MaxCount: 500}))
+}
+
+func TestFeeMatchesReference(t *testing.T) {
+	buf := make([]byte, 1<<16)
+
+	for size := 0; size <= len(buf); size++ {
+		tx := Transaction{Payload: buf[:size]}
+
+		require.Equal(t, refFee(&tx).Uint64(), tx.Fee(), "payload of %d bytes", size)
+	}
+}
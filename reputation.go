@@ -0,0 +1,472 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise"
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Violation is a breach of the protocol by a peer, for which its score is
+// penalized.
+type Violation uint8
+
+const (
+	// ViolationInvalidTx is gossiping a malformed transaction, or one whose
+	// signature is invalid.
+	ViolationInvalidTx Violation = iota
+
+	// ViolationInvalidBlock is proposing a block whose Merkle root does not
+	// match that of applying its transactions.
+	ViolationInvalidBlock
+
+	// ViolationStaleState is reporting a latest state to sync to which is
+	// not that of the majority of peers.
+	ViolationStaleState
+
+	// ViolationInvalidChunk is serving a chunk of state which does not match
+	// the checksum it was requested by.
+	ViolationInvalidChunk
+
+	// ViolationInvalidProof is querying with an invalid or missing proof of
+	// its peer sample, or querying a peer it did not sample.
+	ViolationInvalidProof
+
+	// ViolationExcessiveTraffic is making more requests per second than the
+	// policy allows.
+	ViolationExcessiveTraffic
+
+	// NumViolations is the number of kinds of violations.
+	NumViolations
+)
+
+var violationNames = [...]string{ // nolint:gochecknoglobals
+	ViolationInvalidTx:        "invalid_tx",
+	ViolationInvalidBlock:     "invalid_block",
+	ViolationStaleState:       "stale_state",
+	ViolationInvalidChunk:     "invalid_chunk",
+	ViolationInvalidProof:     "invalid_proof",
+	ViolationExcessiveTraffic: "excessive_traffic",
+}
+
+func (v Violation) String() string {
+	if v >= NumViolations {
+		return "unknown"
+	}
+
+	return violationNames[v]
+}
+
+// PeerPolicy decides how peers are scored and banned. Peers start with a score
+// of 0, which drops by the penalty of every violation they commit and recovers
+// back up to 0 over time. Peers whose score drops to BanScore or below are
+// banned for BanDuration, after which they start over with a score of 0.
+type PeerPolicy struct {
+	// Penalties are the points a violation costs a peer.
+	Penalties [NumViolations]float64
+
+	// Recovery is the number of points the score of a peer recovers every
+	// minute.
+	Recovery float64
+
+	// BanScore is the score at or below which a peer is banned.
+	BanScore float64
+
+	// BanDuration is how long a peer is banned for.
+	BanDuration time.Duration
+
+	// MaxRequestsPerSecond is the number of requests a peer may make every
+	// second before committing ViolationExcessiveTraffic, or 0 for no limit.
+	MaxRequestsPerSecond uint64
+}
+
+// DefaultPeerPolicy returns the policy peers are scored and banned by unless
+// another is set with WithPeerPolicy.
+func DefaultPeerPolicy() PeerPolicy {
+	var policy PeerPolicy
+
+	policy.Penalties[ViolationInvalidTx] = 5
+	policy.Penalties[ViolationInvalidBlock] = 20
+	policy.Penalties[ViolationStaleState] = 10
+	policy.Penalties[ViolationInvalidChunk] = 25
+	policy.Penalties[ViolationInvalidProof] = 25
+	policy.Penalties[ViolationExcessiveTraffic] = 10
+
+	policy.Recovery = 10
+	policy.BanScore = -100
+	policy.BanDuration = 10 * time.Minute
+	policy.MaxRequestsPerSecond = 500
+
+	return policy
+}
+
+// PeerScore is the reputation of a peer.
+type PeerScore struct {
+	ID      AccountID
+	Address string
+
+	Score float64
+
+	// Latency is the moving average of the time taken by the peer to respond
+	// to our queries, or 0 should it not have responded to any.
+	Latency time.Duration
+
+	// Violations counts the violations committed by the peer by their kind.
+	Violations [NumViolations]uint64
+
+	// BannedUntil is when the ban of the peer expires, or the zero time
+	// should it not be banned.
+	BannedUntil time.Time
+}
+
+// Banned returns whether the peer is banned as of now.
+func (s PeerScore) Banned(now time.Time) bool {
+	return now.Before(s.BannedUntil)
+}
+
+// latencyWeight is the weight of the latest latency observed of a peer in its
+// moving average.
+const latencyWeight = 0.2
+
+// Reputation tracks the protocol violations and latency of peers, scoring
+// them and banning them according to a PeerPolicy.
+type Reputation struct {
+	sync.Mutex
+
+	policy PeerPolicy
+	peers  map[AccountID]*peerReputation
+
+	now func() time.Time
+
+	// onBan is called with the address of every peer banned whose address
+	// is known.
+	onBan func(address string)
+}
+
+type peerReputation struct {
+	score PeerScore
+
+	// recovered is when the score was last recovered.
+	recovered time.Time
+
+	// window is the second requests are being counted in, and requests the
+	// number of requests made within it.
+	window   time.Time
+	requests uint64
+}
+
+// NewReputation returns a Reputation scoring peers according to policy.
+func NewReputation(policy PeerPolicy) *Reputation {
+	return &Reputation{
+		policy: policy,
+		peers:  make(map[AccountID]*peerReputation),
+		now:    time.Now,
+	}
+}
+
+// Policy returns the policy peers are scored and banned by.
+func (r *Reputation) Policy() PeerPolicy {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.policy
+}
+
+// Report penalizes a peer for committing a violation, returning whether it
+// is banned as a result.
+func (r *Reputation) Report(id *skademlia.ID, v Violation) bool {
+	r.Lock()
+
+	now := r.now()
+	p := r.load(id, now)
+
+	p.score.Violations[v]++
+
+	if p.score.Banned(now) {
+		r.Unlock()
+		return true
+	}
+
+	p.score.Score -= r.policy.Penalties[v]
+
+	if p.score.Score > r.policy.BanScore {
+		r.Unlock()
+		return false
+	}
+
+	p.score.BannedUntil = now.Add(r.policy.BanDuration)
+	score := p.score
+
+	onBan := r.onBan
+
+	r.Unlock()
+
+	publicKey := id.PublicKey()
+
+	logger := log.Network(events.EventPeerBanned)
+	logger.Warn().
+		Hex("public_key", publicKey[:]).
+		Str("address", id.Address()).
+		Str("violation", v.String()).
+		Float64("score", score.Score).
+		Time("until", score.BannedUntil).
+		Msg("Peer has been banned.")
+
+	if onBan != nil {
+		onBan(id.Address())
+	}
+
+	return true
+}
+
+// Ban bans a peer for duration, or for the BanDuration of the policy should
+// duration be 0, regardless of its score.
+func (r *Reputation) Ban(id AccountID, duration time.Duration) PeerScore {
+	if duration == 0 {
+		duration = r.Policy().BanDuration
+	}
+
+	r.Lock()
+
+	now := r.now()
+	p := r.lookup(id, now)
+
+	p.score.BannedUntil = now.Add(duration)
+	score := p.score
+
+	onBan := r.onBan
+
+	r.Unlock()
+
+	logger := log.Network(events.EventPeerBanned)
+	logger.Warn().
+		Hex("public_key", id[:]).
+		Str("address", score.Address).
+		Time("until", score.BannedUntil).
+		Msg("Peer has been banned by the operator of the node.")
+
+	if onBan != nil && score.Address != "" {
+		onBan(score.Address)
+	}
+
+	return score
+}
+
+// Unban lifts the ban of a peer, having it start over with a score of 0. It
+// returns whether the peer was banned.
+func (r *Reputation) Unban(id AccountID) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.now()
+
+	p, exists := r.peers[id]
+	if !exists || !p.score.Banned(now) {
+		return false
+	}
+
+	p.score.BannedUntil = now
+	r.recover(p, now)
+
+	return true
+}
+
+// Observe records the time taken by a peer to respond to a query.
+func (r *Reputation) Observe(id *skademlia.ID, latency time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+
+	p := r.load(id, r.now())
+
+	if p.score.Latency == 0 {
+		p.score.Latency = latency
+	} else {
+		p.score.Latency += time.Duration(latencyWeight * float64(latency-p.score.Latency))
+	}
+}
+
+// Request accounts for a request made by a peer, returning an error should
+// the peer be banned, or be banned for making too many requests.
+func (r *Reputation) Request(id *skademlia.ID) error {
+	r.Lock()
+
+	now := r.now()
+	p := r.load(id, now)
+
+	if p.score.Banned(now) {
+		r.Unlock()
+		return status.Errorf(codes.PermissionDenied, "peer is banned until %s", p.score.BannedUntil.Format(time.RFC3339))
+	}
+
+	if window := now.Truncate(time.Second); !window.Equal(p.window) {
+		p.window = window
+		p.requests = 0
+	}
+
+	p.requests++
+
+	exceeded := r.policy.MaxRequestsPerSecond > 0 && p.requests == r.policy.MaxRequestsPerSecond+1
+
+	r.Unlock()
+
+	// Only the first request over the limit within a second is a violation.
+	if exceeded && r.Report(id, ViolationExcessiveTraffic) {
+		return status.Error(codes.PermissionDenied, "peer is banned for excessive traffic")
+	}
+
+	return nil
+}
+
+// Banned returns whether a peer is banned.
+func (r *Reputation) Banned(id AccountID) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	p, exists := r.peers[id]
+
+	return exists && p.score.Banned(r.now())
+}
+
+// Filter returns the peers which are not banned.
+func (r *Reputation) Filter(peers []skademlia.ClosestPeer) []skademlia.ClosestPeer {
+	filtered := peers[:0:0]
+
+	for _, p := range peers {
+		if !r.Banned(p.ID().PublicKey()) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
+
+// Score returns the reputation of a peer, being a score of 0 should the peer
+// not have been seen.
+func (r *Reputation) Score(id *skademlia.ID) PeerScore {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.load(id, r.now()).score
+}
+
+// Scores returns the reputation of all peers seen, ordered by their public
+// key.
+func (r *Reputation) Scores() []PeerScore {
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.now()
+	scores := make([]PeerScore, 0, len(r.peers))
+
+	for _, p := range r.peers {
+		r.recover(p, now)
+		scores = append(scores, p.score)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		return bytes.Compare(scores[i].ID[:], scores[j].ID[:]) < 0
+	})
+
+	return scores
+}
+
+// load returns the reputation of a peer, recovered as of now. It must be
+// called with the lock held.
+func (r *Reputation) load(id *skademlia.ID, now time.Time) *peerReputation {
+	p := r.lookup(id.PublicKey(), now)
+	p.score.Address = id.Address()
+
+	return p
+}
+
+// lookup returns the reputation of a peer by its public key, recovered as of
+// now. Peers not yet seen have no address until they are. It must be called
+// with the lock held.
+func (r *Reputation) lookup(id AccountID, now time.Time) *peerReputation {
+	p, exists := r.peers[id]
+	if !exists {
+		p = &peerReputation{
+			score:     PeerScore{ID: id},
+			recovered: now,
+		}
+
+		r.peers[id] = p
+	}
+
+	r.recover(p, now)
+
+	return p
+}
+
+// recover recovers the score of a peer up to now, and has peers whose ban has
+// expired start over. It must be called with the lock held.
+func (r *Reputation) recover(p *peerReputation, now time.Time) {
+	if !p.score.BannedUntil.IsZero() {
+		if p.score.Banned(now) {
+			p.recovered = now
+			return
+		}
+
+		p.score.Score = 0
+		p.score.BannedUntil = time.Time{}
+	}
+
+	if p.score.Score < 0 {
+		p.score.Score += r.policy.Recovery * now.Sub(p.recovered).Minutes()
+
+		if p.score.Score > 0 {
+			p.score.Score = 0
+		}
+	}
+
+	p.recovered = now
+}
+
+// peerFromContext returns the ID of the peer making a request.
+func peerFromContext(ctx context.Context) (*skademlia.ID, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("could not identify peer")
+	}
+
+	info := noise.InfoFromPeer(p)
+	if info == nil {
+		return nil, errors.New("could not identify peer")
+	}
+
+	id, ok := info.Get(skademlia.KeyID).(*skademlia.ID)
+	if !ok {
+		return nil, errors.New("could not identify peer")
+	}
+
+	return id, nil
+}
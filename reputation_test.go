@@ -0,0 +1,161 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReputation(t *testing.T) {
+	now := time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	policy := DefaultPeerPolicy()
+	policy.MaxRequestsPerSecond = 3
+
+	reputation := NewReputation(policy)
+	reputation.now = func() time.Time { return now }
+
+	var banned []string
+
+	reputation.onBan = func(address string) {
+		banned = append(banned, address)
+	}
+
+	alice := skademlia.NewID("127.0.0.1:3000", AccountID{1}, [blake2b.Size256]byte{})
+	bob := skademlia.NewID("127.0.0.1:3001", AccountID{2}, [blake2b.Size256]byte{})
+
+	// Violations cost peers points, which they recover over time.
+	assert.False(t, reputation.Report(alice, ViolationInvalidBlock))
+	assert.False(t, reputation.Report(alice, ViolationInvalidTx))
+	assert.EqualValues(t, -25, reputation.Score(alice).Score)
+
+	now = now.Add(time.Minute)
+	assert.EqualValues(t, -15, reputation.Score(alice).Score)
+
+	now = now.Add(time.Hour)
+	assert.EqualValues(t, 0, reputation.Score(alice).Score)
+
+	// Peers whose score drops low enough are banned.
+	for i := 0; i < 3; i++ {
+		assert.False(t, reputation.Report(alice, ViolationInvalidChunk))
+	}
+
+	assert.True(t, reputation.Report(alice, ViolationInvalidProof))
+	assert.Equal(t, []string{alice.Address()}, banned)
+	assert.True(t, reputation.Banned(alice.PublicKey()))
+	assert.False(t, reputation.Banned(bob.PublicKey()))
+
+	err := reputation.Request(alice)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	score := reputation.Score(alice)
+	assert.EqualValues(t, 1, score.Violations[ViolationInvalidBlock])
+	assert.EqualValues(t, 3, score.Violations[ViolationInvalidChunk])
+	assert.Equal(t, now.Add(policy.BanDuration), score.BannedUntil)
+
+	// Violations committed while banned are counted, without extending the ban.
+	assert.True(t, reputation.Report(alice, ViolationInvalidTx))
+	assert.Len(t, banned, 1)
+
+	// Banned peers start over once their ban expires.
+	now = now.Add(policy.BanDuration)
+
+	assert.False(t, reputation.Banned(alice.PublicKey()))
+	assert.NoError(t, reputation.Request(alice))
+	assert.EqualValues(t, 0, reputation.Score(alice).Score)
+
+	// Peers making too many requests within a second are penalized once per
+	// second.
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, reputation.Request(bob))
+	}
+
+	assert.EqualValues(t, -10, reputation.Score(bob).Score)
+	assert.EqualValues(t, 1, reputation.Score(bob).Violations[ViolationExcessiveTraffic])
+
+	now = now.Add(time.Second)
+
+	assert.NoError(t, reputation.Request(bob))
+	assert.EqualValues(t, 1, reputation.Score(bob).Violations[ViolationExcessiveTraffic])
+
+	// Latencies are averaged.
+	reputation.Observe(bob, 100*time.Millisecond)
+	reputation.Observe(bob, 200*time.Millisecond)
+	assert.Equal(t, 120*time.Millisecond, reputation.Score(bob).Latency)
+
+	scores := reputation.Scores()
+	if assert.Len(t, scores, 2) {
+		assert.Equal(t, AccountID{1}, scores[0].ID)
+		assert.Equal(t, "127.0.0.1:3001", scores[1].Address)
+	}
+}
+
+func TestReputationBan(t *testing.T) {
+	now := time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	policy := DefaultPeerPolicy()
+
+	reputation := NewReputation(policy)
+	reputation.now = func() time.Time { return now }
+
+	var banned []string
+
+	reputation.onBan = func(address string) {
+		banned = append(banned, address)
+	}
+
+	alice := skademlia.NewID("127.0.0.1:3000", AccountID{1}, [blake2b.Size256]byte{})
+
+	// Peers may be banned before they are seen, and are disconnected from
+	// only once their address is known.
+	score := reputation.Ban(AccountID{2}, 0)
+	assert.Equal(t, now.Add(policy.BanDuration), score.BannedUntil)
+	assert.True(t, reputation.Banned(AccountID{2}))
+	assert.Empty(t, banned)
+
+	reputation.Observe(alice, time.Millisecond)
+
+	score = reputation.Ban(alice.PublicKey(), time.Hour)
+	assert.Equal(t, now.Add(time.Hour), score.BannedUntil)
+	assert.Equal(t, []string{alice.Address()}, banned)
+
+	err := reputation.Request(alice)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Unbanned peers start over with a score of 0.
+	assert.True(t, reputation.Report(alice, ViolationInvalidTx))
+
+	assert.True(t, reputation.Unban(alice.PublicKey()))
+	assert.False(t, reputation.Unban(alice.PublicKey()))
+	assert.False(t, reputation.Unban(AccountID{3}))
+
+	assert.NoError(t, reputation.Request(alice))
+	assert.EqualValues(t, 0, reputation.Score(alice).Score)
+	assert.True(t, reputation.Score(alice).BannedUntil.IsZero())
+}
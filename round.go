@@ -0,0 +1,198 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+)
+
+// Round summarizes a block our node finalized, such that explorers need not
+// reconstruct blocks from their transactions. Like diffs and logs, rounds are
+// kept by each node for its own API, and only of the blocks it finalized
+// itself rather than synced to.
+type Round struct {
+	Index  uint64
+	ID     BlockID
+	Merkle MerkleNodeID
+
+	// StartID and EndID are the IDs of the first and last transactions of the
+	// block, being zero should it have none.
+	StartID TransactionID
+	EndID   TransactionID
+
+	NumTx       uint32
+	NumApplied  uint32
+	NumRejected uint32
+
+	// StartedAt is when our node finalized the block preceding the block, or
+	// started should it not have, and FinalizedAt when it finalized the block.
+	StartedAt   time.Time
+	FinalizedAt time.Time
+}
+
+// NewRound summarizes block, of which applied transactions were applied and
+// rejected rejected.
+func NewRound(block Block, applied, rejected int, startedAt, finalizedAt time.Time) Round {
+	r := Round{
+		Index:       block.Index,
+		ID:          block.ID,
+		Merkle:      block.Merkle,
+		NumTx:       uint32(len(block.Transactions)),
+		NumApplied:  uint32(applied),
+		NumRejected: uint32(rejected),
+		StartedAt:   startedAt,
+		FinalizedAt: finalizedAt,
+	}
+
+	if len(block.Transactions) > 0 {
+		r.StartID = block.Transactions[0]
+		r.EndID = block.Transactions[len(block.Transactions)-1]
+	}
+
+	return r
+}
+
+// Duration is how long our node took to finalize the round.
+func (r Round) Duration() time.Duration {
+	return r.FinalizedAt.Sub(r.StartedAt)
+}
+
+func (r Round) Marshal() []byte {
+	w := bytes.NewBuffer(make([]byte, 0, 8+SizeBlockID+SizeMerkleNodeID+2*SizeTransactionID+3*4+2*8))
+
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], r.Index)
+	w.Write(buf[:])
+
+	w.Write(r.ID[:])
+	w.Write(r.Merkle[:])
+	w.Write(r.StartID[:])
+	w.Write(r.EndID[:])
+
+	for _, n := range []uint32{r.NumTx, r.NumApplied, r.NumRejected} {
+		binary.BigEndian.PutUint32(buf[:4], n)
+		w.Write(buf[:4])
+	}
+
+	for _, t := range []time.Time{r.StartedAt, r.FinalizedAt} {
+		binary.BigEndian.PutUint64(buf[:], uint64(t.UnixNano()))
+		w.Write(buf[:])
+	}
+
+	return w.Bytes()
+}
+
+func UnmarshalRound(r io.Reader) (Round, error) {
+	var (
+		round Round
+		buf   [8]byte
+	)
+
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return round, errors.Wrap(err, "failed to decode round index")
+	}
+
+	round.Index = binary.BigEndian.Uint64(buf[:])
+
+	for _, field := range [][]byte{round.ID[:], round.Merkle[:], round.StartID[:], round.EndID[:]} {
+		if _, err := io.ReadFull(r, field); err != nil {
+			return round, errors.Wrap(err, "failed to decode round ID")
+		}
+	}
+
+	for _, n := range []*uint32{&round.NumTx, &round.NumApplied, &round.NumRejected} {
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return round, errors.Wrap(err, "failed to decode round number of transactions")
+		}
+
+		*n = binary.BigEndian.Uint32(buf[:4])
+	}
+
+	for _, t := range []*time.Time{&round.StartedAt, &round.FinalizedAt} {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return round, errors.Wrap(err, "failed to decode round time")
+		}
+
+		*t = time.Unix(0, int64(binary.BigEndian.Uint64(buf[:])))
+	}
+
+	return round, nil
+}
+
+// StoreRound stores round in kv, keyed by the index of its block.
+func StoreRound(kv store.KV, round Round) error {
+	if err := kv.Put(roundKey(round.Index), round.Marshal()); err != nil {
+		return errors.Wrap(err, "error storing round")
+	}
+
+	return nil
+}
+
+// LoadRound loads the round of the block with index index from kv.
+func LoadRound(kv store.KV, index uint64) (Round, error) {
+	buf, err := kv.Get(roundKey(index))
+	if err != nil {
+		return Round{}, errors.Wrapf(err, "error loading round %d", index)
+	}
+
+	return UnmarshalRound(bytes.NewReader(buf))
+}
+
+// LoadRounds loads up to limit rounds from kv, starting from the round of the
+// block with index from and going back. Rounds are loaded down to the first
+// round not kept, such as of a block our node synced to.
+func LoadRounds(kv store.KV, from, limit uint64) ([]Round, error) {
+	var rounds []Round
+
+	for index := from; uint64(len(rounds)) < limit; index-- {
+		round, err := LoadRound(kv, index)
+		if err != nil {
+			if errors.Cause(err) == store.ErrNotFound {
+				break
+			}
+
+			return nil, err
+		}
+
+		rounds = append(rounds, round)
+
+		if index == 0 {
+			break
+		}
+	}
+
+	return rounds, nil
+}
+
+func roundKey(index uint64) []byte {
+	key := make([]byte, len(keyRounds)+8)
+
+	copy(key, keyRounds[:])
+	binary.BigEndian.PutUint64(key[len(keyRounds):], index)
+
+	return key
+}
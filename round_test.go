@@ -0,0 +1,90 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+// +build unit
+
+package wavelet
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRounds(t *testing.T) {
+	kv := store.NewInmem()
+
+	start := time.Unix(1000, 0)
+
+	// Round 0 is of a block our node synced to rather than finalized.
+	for index := uint64(1); index <= 3; index++ {
+		block := NewBlock(index, MerkleNodeID{byte(index)}, TransactionID{1}, TransactionID{byte(index + 1)})
+
+		startedAt := start.Add(time.Duration(index-1) * time.Second)
+		round := NewRound(block, 1, 1, startedAt, startedAt.Add(750*time.Millisecond))
+
+		require.NoError(t, StoreRound(kv, round))
+	}
+
+	round, err := LoadRound(kv, 2)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, round.Index)
+	assert.Equal(t, MerkleNodeID{2}, round.Merkle)
+	assert.Equal(t, TransactionID{1}, round.StartID)
+	assert.Equal(t, TransactionID{3}, round.EndID)
+	assert.EqualValues(t, 2, round.NumTx)
+	assert.EqualValues(t, 1, round.NumApplied)
+	assert.EqualValues(t, 1, round.NumRejected)
+	assert.True(t, round.StartedAt.Equal(start.Add(time.Second)))
+	assert.Equal(t, 750*time.Millisecond, round.Duration())
+
+	decoded, err := UnmarshalRound(bytes.NewReader(round.Marshal()))
+	require.NoError(t, err)
+	assert.Equal(t, round, decoded)
+
+	_, err = LoadRound(kv, 0)
+	assert.Equal(t, store.ErrNotFound, errors.Cause(err))
+
+	rounds, err := LoadRounds(kv, 3, 2)
+	require.NoError(t, err)
+
+	if assert.Len(t, rounds, 2) {
+		assert.EqualValues(t, 3, rounds[0].Index)
+		assert.EqualValues(t, 2, rounds[1].Index)
+	}
+
+	// Rounds are loaded down to the first round not kept.
+	rounds, err = LoadRounds(kv, 3, 10)
+	require.NoError(t, err)
+	assert.Len(t, rounds, 3)
+
+	rounds, err = LoadRounds(kv, 5, 10)
+	require.NoError(t, err)
+	assert.Empty(t, rounds)
+
+	// A block without transactions has zero start and end IDs.
+	round = NewRound(NewBlock(4, MerkleNodeID{}), 0, 0, start, start)
+	assert.Equal(t, ZeroTransactionID, round.StartID)
+	assert.Equal(t, ZeroTransactionID, round.EndID)
+}
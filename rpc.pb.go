@@ -1,41 +1,22 @@
 // Code generated by protoc-gen-gogo. DO NOT EDIT.
-// source: src/rpc.proto
-
-/*
-	Package wavelet is a generated protocol buffer package.
-
-	It is generated from these files:
-		src/rpc.proto
-
-	It has these top-level messages:
-		QueryRequest
-		QueryResponse
-		OutOfSyncRequest
-		OutOfSyncResponse
-		SyncInfo
-		SyncRequest
-		SyncResponse
-		GossipRequest
-		TransactionsSyncRequest
-		TransactionsSyncPart
-		TransactionsSyncResponse
-		TransactionPullRequest
-		TransactionPullResponse
-*/
-package wavelet
+// source: rpc.proto
 
-import proto "github.com/gogo/protobuf/proto"
-import fmt "fmt"
-import math "math"
-import google_protobuf "github.com/golang/protobuf/ptypes/empty"
+package wavelet
 
 import (
-	context "golang.org/x/net/context"
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	ledgerpb "github.com/perlin-network/wavelet/ledgerpb"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
 )
 
-import io "io"
-
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
@@ -45,17 +26,48 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type QueryRequest struct {
 	BlockIndex   uint64 `protobuf:"varint,1,opt,name=block_index,json=blockIndex,proto3" json:"block_index,omitempty"`
 	CacheBlockId []byte `protobuf:"bytes,2,opt,name=cache_block_id,json=cacheBlockId,proto3" json:"cache_block_id,omitempty"`
+	// VRF proof of the querier's peer sample, computed over the index of the
+	// block and the ID of the block preceding it.
+	SampleProof []byte `protobuf:"bytes,3,opt,name=sample_proof,json=sampleProof,proto3" json:"sample_proof,omitempty"`
+}
+
+func (m *QueryRequest) Reset()         { *m = QueryRequest{} }
+func (m *QueryRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryRequest) ProtoMessage()    {}
+func (*QueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{0}
+}
+func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRequest.Merge(m, src)
+}
+func (m *QueryRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRequest.DiscardUnknown(m)
 }
 
-func (m *QueryRequest) Reset()                    { *m = QueryRequest{} }
-func (m *QueryRequest) String() string            { return proto.CompactTextString(m) }
-func (*QueryRequest) ProtoMessage()               {}
-func (*QueryRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{0} }
+var xxx_messageInfo_QueryRequest proto.InternalMessageInfo
 
 func (m *QueryRequest) GetBlockIndex() uint64 {
 	if m != nil {
@@ -71,15 +83,50 @@ func (m *QueryRequest) GetCacheBlockId() []byte {
 	return nil
 }
 
+func (m *QueryRequest) GetSampleProof() []byte {
+	if m != nil {
+		return m.SampleProof
+	}
+	return nil
+}
+
 type QueryResponse struct {
 	Block      []byte `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
 	CacheValid bool   `protobuf:"varint,2,opt,name=cache_valid,json=cacheValid,proto3" json:"cache_valid,omitempty"`
 }
 
-func (m *QueryResponse) Reset()                    { *m = QueryResponse{} }
-func (m *QueryResponse) String() string            { return proto.CompactTextString(m) }
-func (*QueryResponse) ProtoMessage()               {}
-func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{1} }
+func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
+func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryResponse) ProtoMessage()    {}
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{1}
+}
+func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryResponse.Merge(m, src)
+}
+func (m *QueryResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryResponse proto.InternalMessageInfo
 
 func (m *QueryResponse) GetBlock() []byte {
 	if m != nil {
@@ -99,10 +146,38 @@ type OutOfSyncRequest struct {
 	BlockIndex uint64 `protobuf:"varint,1,opt,name=block_index,json=blockIndex,proto3" json:"block_index,omitempty"`
 }
 
-func (m *OutOfSyncRequest) Reset()                    { *m = OutOfSyncRequest{} }
-func (m *OutOfSyncRequest) String() string            { return proto.CompactTextString(m) }
-func (*OutOfSyncRequest) ProtoMessage()               {}
-func (*OutOfSyncRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{2} }
+func (m *OutOfSyncRequest) Reset()         { *m = OutOfSyncRequest{} }
+func (m *OutOfSyncRequest) String() string { return proto.CompactTextString(m) }
+func (*OutOfSyncRequest) ProtoMessage()    {}
+func (*OutOfSyncRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{2}
+}
+func (m *OutOfSyncRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OutOfSyncRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OutOfSyncRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *OutOfSyncRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutOfSyncRequest.Merge(m, src)
+}
+func (m *OutOfSyncRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *OutOfSyncRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutOfSyncRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutOfSyncRequest proto.InternalMessageInfo
 
 func (m *OutOfSyncRequest) GetBlockIndex() uint64 {
 	if m != nil {
@@ -113,12 +188,45 @@ func (m *OutOfSyncRequest) GetBlockIndex() uint64 {
 
 type OutOfSyncResponse struct {
 	OutOfSync bool `protobuf:"varint,1,opt,name=out_of_sync,json=outOfSync,proto3" json:"out_of_sync,omitempty"`
+	// Snapshot of its latest state the peer serves in full to nodes syncing
+	// from scratch, being the index and ID of its block. Older peers do not
+	// advertise any.
+	SnapshotIndex   uint64 `protobuf:"varint,2,opt,name=snapshot_index,json=snapshotIndex,proto3" json:"snapshot_index,omitempty"`
+	SnapshotBlockId []byte `protobuf:"bytes,3,opt,name=snapshot_block_id,json=snapshotBlockId,proto3" json:"snapshot_block_id,omitempty"`
+}
+
+func (m *OutOfSyncResponse) Reset()         { *m = OutOfSyncResponse{} }
+func (m *OutOfSyncResponse) String() string { return proto.CompactTextString(m) }
+func (*OutOfSyncResponse) ProtoMessage()    {}
+func (*OutOfSyncResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{3}
+}
+func (m *OutOfSyncResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OutOfSyncResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OutOfSyncResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *OutOfSyncResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutOfSyncResponse.Merge(m, src)
+}
+func (m *OutOfSyncResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *OutOfSyncResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutOfSyncResponse.DiscardUnknown(m)
 }
 
-func (m *OutOfSyncResponse) Reset()                    { *m = OutOfSyncResponse{} }
-func (m *OutOfSyncResponse) String() string            { return proto.CompactTextString(m) }
-func (*OutOfSyncResponse) ProtoMessage()               {}
-func (*OutOfSyncResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{3} }
+var xxx_messageInfo_OutOfSyncResponse proto.InternalMessageInfo
 
 func (m *OutOfSyncResponse) GetOutOfSync() bool {
 	if m != nil {
@@ -127,15 +235,57 @@ func (m *OutOfSyncResponse) GetOutOfSync() bool {
 	return false
 }
 
+func (m *OutOfSyncResponse) GetSnapshotIndex() uint64 {
+	if m != nil {
+		return m.SnapshotIndex
+	}
+	return 0
+}
+
+func (m *OutOfSyncResponse) GetSnapshotBlockId() []byte {
+	if m != nil {
+		return m.SnapshotBlockId
+	}
+	return nil
+}
+
 type SyncInfo struct {
 	Block     []byte   `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
-	Checksums [][]byte `protobuf:"bytes,2,rep,name=checksums" json:"checksums,omitempty"`
+	Checksums [][]byte `protobuf:"bytes,2,rep,name=checksums,proto3" json:"checksums,omitempty"`
+}
+
+func (m *SyncInfo) Reset()         { *m = SyncInfo{} }
+func (m *SyncInfo) String() string { return proto.CompactTextString(m) }
+func (*SyncInfo) ProtoMessage()    {}
+func (*SyncInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{4}
+}
+func (m *SyncInfo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SyncInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SyncInfo.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SyncInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncInfo.Merge(m, src)
+}
+func (m *SyncInfo) XXX_Size() int {
+	return m.Size()
+}
+func (m *SyncInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncInfo.DiscardUnknown(m)
 }
 
-func (m *SyncInfo) Reset()                    { *m = SyncInfo{} }
-func (m *SyncInfo) String() string            { return proto.CompactTextString(m) }
-func (*SyncInfo) ProtoMessage()               {}
-func (*SyncInfo) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{4} }
+var xxx_messageInfo_SyncInfo proto.InternalMessageInfo
 
 func (m *SyncInfo) GetBlock() []byte {
 	if m != nil {
@@ -155,13 +305,42 @@ type SyncRequest struct {
 	// Types that are valid to be assigned to Data:
 	//	*SyncRequest_BlockId
 	//	*SyncRequest_Checksum
+	//	*SyncRequest_FullState
 	Data isSyncRequest_Data `protobuf_oneof:"Data"`
 }
 
-func (m *SyncRequest) Reset()                    { *m = SyncRequest{} }
-func (m *SyncRequest) String() string            { return proto.CompactTextString(m) }
-func (*SyncRequest) ProtoMessage()               {}
-func (*SyncRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{5} }
+func (m *SyncRequest) Reset()         { *m = SyncRequest{} }
+func (m *SyncRequest) String() string { return proto.CompactTextString(m) }
+func (*SyncRequest) ProtoMessage()    {}
+func (*SyncRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{5}
+}
+func (m *SyncRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SyncRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SyncRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SyncRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncRequest.Merge(m, src)
+}
+func (m *SyncRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SyncRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncRequest proto.InternalMessageInfo
 
 type isSyncRequest_Data interface {
 	isSyncRequest_Data()
@@ -175,9 +354,13 @@ type SyncRequest_BlockId struct {
 type SyncRequest_Checksum struct {
 	Checksum []byte `protobuf:"bytes,2,opt,name=checksum,proto3,oneof"`
 }
+type SyncRequest_FullState struct {
+	FullState bool `protobuf:"varint,3,opt,name=full_state,json=fullState,proto3,oneof"`
+}
 
-func (*SyncRequest_BlockId) isSyncRequest_Data()  {}
-func (*SyncRequest_Checksum) isSyncRequest_Data() {}
+func (*SyncRequest_BlockId) isSyncRequest_Data()   {}
+func (*SyncRequest_Checksum) isSyncRequest_Data()  {}
+func (*SyncRequest_FullState) isSyncRequest_Data() {}
 
 func (m *SyncRequest) GetData() isSyncRequest_Data {
 	if m != nil {
@@ -200,69 +383,20 @@ func (m *SyncRequest) GetChecksum() []byte {
 	return nil
 }
 
-// XXX_OneofFuncs is for the internal use of the proto package.
-func (*SyncRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
-	return _SyncRequest_OneofMarshaler, _SyncRequest_OneofUnmarshaler, _SyncRequest_OneofSizer, []interface{}{
-		(*SyncRequest_BlockId)(nil),
-		(*SyncRequest_Checksum)(nil),
-	}
-}
-
-func _SyncRequest_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
-	m := msg.(*SyncRequest)
-	// Data
-	switch x := m.Data.(type) {
-	case *SyncRequest_BlockId:
-		_ = b.EncodeVarint(1<<3 | proto.WireVarint)
-		_ = b.EncodeVarint(uint64(x.BlockId))
-	case *SyncRequest_Checksum:
-		_ = b.EncodeVarint(2<<3 | proto.WireBytes)
-		_ = b.EncodeRawBytes(x.Checksum)
-	case nil:
-	default:
-		return fmt.Errorf("SyncRequest.Data has unexpected type %T", x)
+func (m *SyncRequest) GetFullState() bool {
+	if x, ok := m.GetData().(*SyncRequest_FullState); ok {
+		return x.FullState
 	}
-	return nil
+	return false
 }
 
-func _SyncRequest_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
-	m := msg.(*SyncRequest)
-	switch tag {
-	case 1: // Data.block_id
-		if wire != proto.WireVarint {
-			return true, proto.ErrInternalBadWireType
-		}
-		x, err := b.DecodeVarint()
-		m.Data = &SyncRequest_BlockId{x}
-		return true, err
-	case 2: // Data.checksum
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		x, err := b.DecodeRawBytes(true)
-		m.Data = &SyncRequest_Checksum{x}
-		return true, err
-	default:
-		return false, nil
-	}
-}
-
-func _SyncRequest_OneofSizer(msg proto.Message) (n int) {
-	m := msg.(*SyncRequest)
-	// Data
-	switch x := m.Data.(type) {
-	case *SyncRequest_BlockId:
-		n += proto.SizeVarint(1<<3 | proto.WireVarint)
-		n += proto.SizeVarint(uint64(x.BlockId))
-	case *SyncRequest_Checksum:
-		n += proto.SizeVarint(2<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(len(x.Checksum)))
-		n += len(x.Checksum)
-	case nil:
-	default:
-		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*SyncRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*SyncRequest_BlockId)(nil),
+		(*SyncRequest_Checksum)(nil),
+		(*SyncRequest_FullState)(nil),
 	}
-	return n
 }
 
 type SyncResponse struct {
@@ -272,10 +406,38 @@ type SyncResponse struct {
 	Data isSyncResponse_Data `protobuf_oneof:"Data"`
 }
 
-func (m *SyncResponse) Reset()                    { *m = SyncResponse{} }
-func (m *SyncResponse) String() string            { return proto.CompactTextString(m) }
-func (*SyncResponse) ProtoMessage()               {}
-func (*SyncResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{6} }
+func (m *SyncResponse) Reset()         { *m = SyncResponse{} }
+func (m *SyncResponse) String() string { return proto.CompactTextString(m) }
+func (*SyncResponse) ProtoMessage()    {}
+func (*SyncResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{6}
+}
+func (m *SyncResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SyncResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SyncResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SyncResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncResponse.Merge(m, src)
+}
+func (m *SyncResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SyncResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncResponse proto.InternalMessageInfo
 
 type isSyncResponse_Data interface {
 	isSyncResponse_Data()
@@ -284,7 +446,7 @@ type isSyncResponse_Data interface {
 }
 
 type SyncResponse_Header struct {
-	Header *SyncInfo `protobuf:"bytes,1,opt,name=header,oneof"`
+	Header *SyncInfo `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
 }
 type SyncResponse_Chunk struct {
 	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
@@ -314,88 +476,82 @@ func (m *SyncResponse) GetChunk() []byte {
 	return nil
 }
 
-// XXX_OneofFuncs is for the internal use of the proto package.
-func (*SyncResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
-	return _SyncResponse_OneofMarshaler, _SyncResponse_OneofUnmarshaler, _SyncResponse_OneofSizer, []interface{}{
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*SyncResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
 		(*SyncResponse_Header)(nil),
 		(*SyncResponse_Chunk)(nil),
 	}
 }
 
-func _SyncResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
-	m := msg.(*SyncResponse)
-	// Data
-	switch x := m.Data.(type) {
-	case *SyncResponse_Header:
-		_ = b.EncodeVarint(1<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Header); err != nil {
-			return err
+type GossipRequest struct {
+	// Binary encoded transactions, as gossiped by older nodes.
+	Transactions [][]byte                `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	Txs          []*ledgerpb.Transaction `protobuf:"bytes,2,rep,name=txs,proto3" json:"txs,omitempty"`
+	// Encoding the batch of transactions gossiped is compressed with, being
+	// one the receiver accepts.
+	Encoding string `protobuf:"bytes,3,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	// Compressed GossipRequest holding the batch of transactions gossiped.
+	Compressed []byte `protobuf:"bytes,4,opt,name=compressed,proto3" json:"compressed,omitempty"`
+}
+
+func (m *GossipRequest) Reset()         { *m = GossipRequest{} }
+func (m *GossipRequest) String() string { return proto.CompactTextString(m) }
+func (*GossipRequest) ProtoMessage()    {}
+func (*GossipRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{7}
+}
+func (m *GossipRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GossipRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GossipRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-	case *SyncResponse_Chunk:
-		_ = b.EncodeVarint(2<<3 | proto.WireBytes)
-		_ = b.EncodeRawBytes(x.Chunk)
-	case nil:
-	default:
-		return fmt.Errorf("SyncResponse.Data has unexpected type %T", x)
+		return b[:n], nil
 	}
-	return nil
 }
+func (m *GossipRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GossipRequest.Merge(m, src)
+}
+func (m *GossipRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GossipRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GossipRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GossipRequest proto.InternalMessageInfo
 
-func _SyncResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
-	m := msg.(*SyncResponse)
-	switch tag {
-	case 1: // Data.header
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(SyncInfo)
-		err := b.DecodeMessage(msg)
-		m.Data = &SyncResponse_Header{msg}
-		return true, err
-	case 2: // Data.chunk
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		x, err := b.DecodeRawBytes(true)
-		m.Data = &SyncResponse_Chunk{x}
-		return true, err
-	default:
-		return false, nil
-	}
-}
-
-func _SyncResponse_OneofSizer(msg proto.Message) (n int) {
-	m := msg.(*SyncResponse)
-	// Data
-	switch x := m.Data.(type) {
-	case *SyncResponse_Header:
-		s := proto.Size(x.Header)
-		n += proto.SizeVarint(1<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *SyncResponse_Chunk:
-		n += proto.SizeVarint(2<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(len(x.Chunk)))
-		n += len(x.Chunk)
-	case nil:
-	default:
-		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+func (m *GossipRequest) GetTransactions() [][]byte {
+	if m != nil {
+		return m.Transactions
 	}
-	return n
+	return nil
 }
 
-type GossipRequest struct {
-	Transactions [][]byte `protobuf:"bytes,1,rep,name=transactions" json:"transactions,omitempty"`
+func (m *GossipRequest) GetTxs() []*ledgerpb.Transaction {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
 }
 
-func (m *GossipRequest) Reset()                    { *m = GossipRequest{} }
-func (m *GossipRequest) String() string            { return proto.CompactTextString(m) }
-func (*GossipRequest) ProtoMessage()               {}
-func (*GossipRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{7} }
+func (m *GossipRequest) GetEncoding() string {
+	if m != nil {
+		return m.Encoding
+	}
+	return ""
+}
 
-func (m *GossipRequest) GetTransactions() [][]byte {
+func (m *GossipRequest) GetCompressed() []byte {
 	if m != nil {
-		return m.Transactions
+		return m.Compressed
 	}
 	return nil
 }
@@ -407,10 +563,38 @@ type TransactionsSyncRequest struct {
 	Data isTransactionsSyncRequest_Data `protobuf_oneof:"Data"`
 }
 
-func (m *TransactionsSyncRequest) Reset()                    { *m = TransactionsSyncRequest{} }
-func (m *TransactionsSyncRequest) String() string            { return proto.CompactTextString(m) }
-func (*TransactionsSyncRequest) ProtoMessage()               {}
-func (*TransactionsSyncRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{8} }
+func (m *TransactionsSyncRequest) Reset()         { *m = TransactionsSyncRequest{} }
+func (m *TransactionsSyncRequest) String() string { return proto.CompactTextString(m) }
+func (*TransactionsSyncRequest) ProtoMessage()    {}
+func (*TransactionsSyncRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{8}
+}
+func (m *TransactionsSyncRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransactionsSyncRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransactionsSyncRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransactionsSyncRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionsSyncRequest.Merge(m, src)
+}
+func (m *TransactionsSyncRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransactionsSyncRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionsSyncRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionsSyncRequest proto.InternalMessageInfo
 
 type isTransactionsSyncRequest_Data interface {
 	isTransactionsSyncRequest_Data()
@@ -449,79 +633,50 @@ func (m *TransactionsSyncRequest) GetChunkSize() uint64 {
 	return 0
 }
 
-// XXX_OneofFuncs is for the internal use of the proto package.
-func (*TransactionsSyncRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
-	return _TransactionsSyncRequest_OneofMarshaler, _TransactionsSyncRequest_OneofUnmarshaler, _TransactionsSyncRequest_OneofSizer, []interface{}{
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*TransactionsSyncRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
 		(*TransactionsSyncRequest_Filter)(nil),
 		(*TransactionsSyncRequest_ChunkSize)(nil),
 	}
 }
 
-func _TransactionsSyncRequest_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
-	m := msg.(*TransactionsSyncRequest)
-	// Data
-	switch x := m.Data.(type) {
-	case *TransactionsSyncRequest_Filter:
-		_ = b.EncodeVarint(1<<3 | proto.WireBytes)
-		_ = b.EncodeRawBytes(x.Filter)
-	case *TransactionsSyncRequest_ChunkSize:
-		_ = b.EncodeVarint(2<<3 | proto.WireVarint)
-		_ = b.EncodeVarint(uint64(x.ChunkSize))
-	case nil:
-	default:
-		return fmt.Errorf("TransactionsSyncRequest.Data has unexpected type %T", x)
-	}
-	return nil
+type TransactionsSyncPart struct {
+	Transactions [][]byte `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
 }
 
-func _TransactionsSyncRequest_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
-	m := msg.(*TransactionsSyncRequest)
-	switch tag {
-	case 1: // Data.filter
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		x, err := b.DecodeRawBytes(true)
-		m.Data = &TransactionsSyncRequest_Filter{x}
-		return true, err
-	case 2: // Data.chunk_size
-		if wire != proto.WireVarint {
-			return true, proto.ErrInternalBadWireType
-		}
-		x, err := b.DecodeVarint()
-		m.Data = &TransactionsSyncRequest_ChunkSize{x}
-		return true, err
-	default:
-		return false, nil
-	}
-}
-
-func _TransactionsSyncRequest_OneofSizer(msg proto.Message) (n int) {
-	m := msg.(*TransactionsSyncRequest)
-	// Data
-	switch x := m.Data.(type) {
-	case *TransactionsSyncRequest_Filter:
-		n += proto.SizeVarint(1<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(len(x.Filter)))
-		n += len(x.Filter)
-	case *TransactionsSyncRequest_ChunkSize:
-		n += proto.SizeVarint(2<<3 | proto.WireVarint)
-		n += proto.SizeVarint(uint64(x.ChunkSize))
-	case nil:
-	default:
-		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+func (m *TransactionsSyncPart) Reset()         { *m = TransactionsSyncPart{} }
+func (m *TransactionsSyncPart) String() string { return proto.CompactTextString(m) }
+func (*TransactionsSyncPart) ProtoMessage()    {}
+func (*TransactionsSyncPart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{9}
+}
+func (m *TransactionsSyncPart) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransactionsSyncPart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransactionsSyncPart.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return n
 }
-
-type TransactionsSyncPart struct {
-	Transactions [][]byte `protobuf:"bytes,1,rep,name=transactions" json:"transactions,omitempty"`
+func (m *TransactionsSyncPart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionsSyncPart.Merge(m, src)
+}
+func (m *TransactionsSyncPart) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransactionsSyncPart) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionsSyncPart.DiscardUnknown(m)
 }
 
-func (m *TransactionsSyncPart) Reset()                    { *m = TransactionsSyncPart{} }
-func (m *TransactionsSyncPart) String() string            { return proto.CompactTextString(m) }
-func (*TransactionsSyncPart) ProtoMessage()               {}
-func (*TransactionsSyncPart) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{9} }
+var xxx_messageInfo_TransactionsSyncPart proto.InternalMessageInfo
 
 func (m *TransactionsSyncPart) GetTransactions() [][]byte {
 	if m != nil {
@@ -537,10 +692,38 @@ type TransactionsSyncResponse struct {
 	Data isTransactionsSyncResponse_Data `protobuf_oneof:"Data"`
 }
 
-func (m *TransactionsSyncResponse) Reset()                    { *m = TransactionsSyncResponse{} }
-func (m *TransactionsSyncResponse) String() string            { return proto.CompactTextString(m) }
-func (*TransactionsSyncResponse) ProtoMessage()               {}
-func (*TransactionsSyncResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{10} }
+func (m *TransactionsSyncResponse) Reset()         { *m = TransactionsSyncResponse{} }
+func (m *TransactionsSyncResponse) String() string { return proto.CompactTextString(m) }
+func (*TransactionsSyncResponse) ProtoMessage()    {}
+func (*TransactionsSyncResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{10}
+}
+func (m *TransactionsSyncResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransactionsSyncResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransactionsSyncResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransactionsSyncResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionsSyncResponse.Merge(m, src)
+}
+func (m *TransactionsSyncResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransactionsSyncResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionsSyncResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionsSyncResponse proto.InternalMessageInfo
 
 type isTransactionsSyncResponse_Data interface {
 	isTransactionsSyncResponse_Data()
@@ -552,7 +735,7 @@ type TransactionsSyncResponse_TransactionsNum struct {
 	TransactionsNum uint64 `protobuf:"varint,1,opt,name=transactions_num,json=transactionsNum,proto3,oneof"`
 }
 type TransactionsSyncResponse_Transactions struct {
-	Transactions *TransactionsSyncPart `protobuf:"bytes,2,opt,name=transactions,oneof"`
+	Transactions *TransactionsSyncPart `protobuf:"bytes,2,opt,name=transactions,proto3,oneof"`
 }
 
 func (*TransactionsSyncResponse_TransactionsNum) isTransactionsSyncResponse_Data() {}
@@ -579,83 +762,50 @@ func (m *TransactionsSyncResponse) GetTransactions() *TransactionsSyncPart {
 	return nil
 }
 
-// XXX_OneofFuncs is for the internal use of the proto package.
-func (*TransactionsSyncResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
-	return _TransactionsSyncResponse_OneofMarshaler, _TransactionsSyncResponse_OneofUnmarshaler, _TransactionsSyncResponse_OneofSizer, []interface{}{
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*TransactionsSyncResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
 		(*TransactionsSyncResponse_TransactionsNum)(nil),
 		(*TransactionsSyncResponse_Transactions)(nil),
 	}
 }
 
-func _TransactionsSyncResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
-	m := msg.(*TransactionsSyncResponse)
-	// Data
-	switch x := m.Data.(type) {
-	case *TransactionsSyncResponse_TransactionsNum:
-		_ = b.EncodeVarint(1<<3 | proto.WireVarint)
-		_ = b.EncodeVarint(uint64(x.TransactionsNum))
-	case *TransactionsSyncResponse_Transactions:
-		_ = b.EncodeVarint(2<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Transactions); err != nil {
-			return err
-		}
-	case nil:
-	default:
-		return fmt.Errorf("TransactionsSyncResponse.Data has unexpected type %T", x)
-	}
-	return nil
+type TransactionPullRequest struct {
+	TransactionIds [][]byte `protobuf:"bytes,1,rep,name=transaction_ids,json=transactionIds,proto3" json:"transaction_ids,omitempty"`
 }
 
-func _TransactionsSyncResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
-	m := msg.(*TransactionsSyncResponse)
-	switch tag {
-	case 1: // Data.transactions_num
-		if wire != proto.WireVarint {
-			return true, proto.ErrInternalBadWireType
-		}
-		x, err := b.DecodeVarint()
-		m.Data = &TransactionsSyncResponse_TransactionsNum{x}
-		return true, err
-	case 2: // Data.transactions
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(TransactionsSyncPart)
-		err := b.DecodeMessage(msg)
-		m.Data = &TransactionsSyncResponse_Transactions{msg}
-		return true, err
-	default:
-		return false, nil
-	}
-}
-
-func _TransactionsSyncResponse_OneofSizer(msg proto.Message) (n int) {
-	m := msg.(*TransactionsSyncResponse)
-	// Data
-	switch x := m.Data.(type) {
-	case *TransactionsSyncResponse_TransactionsNum:
-		n += proto.SizeVarint(1<<3 | proto.WireVarint)
-		n += proto.SizeVarint(uint64(x.TransactionsNum))
-	case *TransactionsSyncResponse_Transactions:
-		s := proto.Size(x.Transactions)
-		n += proto.SizeVarint(2<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case nil:
-	default:
-		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+func (m *TransactionPullRequest) Reset()         { *m = TransactionPullRequest{} }
+func (m *TransactionPullRequest) String() string { return proto.CompactTextString(m) }
+func (*TransactionPullRequest) ProtoMessage()    {}
+func (*TransactionPullRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{11}
+}
+func (m *TransactionPullRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransactionPullRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransactionPullRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return n
 }
-
-type TransactionPullRequest struct {
-	TransactionIds [][]byte `protobuf:"bytes,1,rep,name=transaction_ids,json=transactionIds" json:"transaction_ids,omitempty"`
+func (m *TransactionPullRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionPullRequest.Merge(m, src)
+}
+func (m *TransactionPullRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransactionPullRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionPullRequest.DiscardUnknown(m)
 }
 
-func (m *TransactionPullRequest) Reset()                    { *m = TransactionPullRequest{} }
-func (m *TransactionPullRequest) String() string            { return proto.CompactTextString(m) }
-func (*TransactionPullRequest) ProtoMessage()               {}
-func (*TransactionPullRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{11} }
+var xxx_messageInfo_TransactionPullRequest proto.InternalMessageInfo
 
 func (m *TransactionPullRequest) GetTransactionIds() [][]byte {
 	if m != nil {
@@ -665,13 +815,41 @@ func (m *TransactionPullRequest) GetTransactionIds() [][]byte {
 }
 
 type TransactionPullResponse struct {
-	Transactions [][]byte `protobuf:"bytes,1,rep,name=transactions" json:"transactions,omitempty"`
+	Transactions [][]byte `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+}
+
+func (m *TransactionPullResponse) Reset()         { *m = TransactionPullResponse{} }
+func (m *TransactionPullResponse) String() string { return proto.CompactTextString(m) }
+func (*TransactionPullResponse) ProtoMessage()    {}
+func (*TransactionPullResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{12}
+}
+func (m *TransactionPullResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransactionPullResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransactionPullResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransactionPullResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionPullResponse.Merge(m, src)
+}
+func (m *TransactionPullResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransactionPullResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionPullResponse.DiscardUnknown(m)
 }
 
-func (m *TransactionPullResponse) Reset()                    { *m = TransactionPullResponse{} }
-func (m *TransactionPullResponse) String() string            { return proto.CompactTextString(m) }
-func (*TransactionPullResponse) ProtoMessage()               {}
-func (*TransactionPullResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{12} }
+var xxx_messageInfo_TransactionPullResponse proto.InternalMessageInfo
 
 func (m *TransactionPullResponse) GetTransactions() [][]byte {
 	if m != nil {
@@ -680,6 +858,94 @@ func (m *TransactionPullResponse) GetTransactions() [][]byte {
 	return nil
 }
 
+type PeerExchangeRequest struct {
+	Limit uint32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (m *PeerExchangeRequest) Reset()         { *m = PeerExchangeRequest{} }
+func (m *PeerExchangeRequest) String() string { return proto.CompactTextString(m) }
+func (*PeerExchangeRequest) ProtoMessage()    {}
+func (*PeerExchangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{13}
+}
+func (m *PeerExchangeRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PeerExchangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PeerExchangeRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PeerExchangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerExchangeRequest.Merge(m, src)
+}
+func (m *PeerExchangeRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PeerExchangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerExchangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerExchangeRequest proto.InternalMessageInfo
+
+func (m *PeerExchangeRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type PeerExchangeResponse struct {
+	Addresses []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+}
+
+func (m *PeerExchangeResponse) Reset()         { *m = PeerExchangeResponse{} }
+func (m *PeerExchangeResponse) String() string { return proto.CompactTextString(m) }
+func (*PeerExchangeResponse) ProtoMessage()    {}
+func (*PeerExchangeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{14}
+}
+func (m *PeerExchangeResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PeerExchangeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PeerExchangeResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PeerExchangeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerExchangeResponse.Merge(m, src)
+}
+func (m *PeerExchangeResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PeerExchangeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerExchangeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerExchangeResponse proto.InternalMessageInfo
+
+func (m *PeerExchangeResponse) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*QueryRequest)(nil), "wavelet.QueryRequest")
 	proto.RegisterType((*QueryResponse)(nil), "wavelet.QueryResponse")
@@ -694,6 +960,67 @@ func init() {
 	proto.RegisterType((*TransactionsSyncResponse)(nil), "wavelet.TransactionsSyncResponse")
 	proto.RegisterType((*TransactionPullRequest)(nil), "wavelet.TransactionPullRequest")
 	proto.RegisterType((*TransactionPullResponse)(nil), "wavelet.TransactionPullResponse")
+	proto.RegisterType((*PeerExchangeRequest)(nil), "wavelet.PeerExchangeRequest")
+	proto.RegisterType((*PeerExchangeResponse)(nil), "wavelet.PeerExchangeResponse")
+}
+
+func init() { proto.RegisterFile("rpc.proto", fileDescriptor_77a6da22d6a3feb1) }
+
+var fileDescriptor_77a6da22d6a3feb1 = []byte{
+	// 840 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x8d, 0x54, 0xcb, 0x6e, 0xd3, 0x40,
+	0x14, 0x6d, 0xda, 0x34, 0x4d, 0x6e, 0x92, 0x36, 0x1d, 0xd2, 0x10, 0xdc, 0xb7, 0x05, 0xa2, 0xa2,
+	0x22, 0x45, 0x2d, 0x0b, 0xa8, 0x04, 0x12, 0x2d, 0x05, 0xb2, 0x69, 0x83, 0x8b, 0xe8, 0x02, 0x90,
+	0xe5, 0xd8, 0x93, 0x87, 0xea, 0xd8, 0xae, 0xc7, 0x29, 0x29, 0x1f, 0xc0, 0x0e, 0x89, 0x05, 0x6b,
+	0xbe, 0x87, 0x65, 0x97, 0x2c, 0x11, 0xfc, 0x08, 0xf3, 0xb0, 0xa7, 0x76, 0x48, 0x50, 0x17, 0x23,
+	0x79, 0xce, 0xdc, 0xb9, 0xe7, 0xde, 0x73, 0x8f, 0x07, 0x72, 0xbe, 0x67, 0xd6, 0x3c, 0xdf, 0x0d,
+	0x5c, 0x34, 0xf3, 0xd1, 0x38, 0xc7, 0x36, 0x0e, 0x94, 0xc5, 0xb6, 0xeb, 0xb6, 0x6d, 0xbc, 0xc5,
+	0xe1, 0x66, 0xbf, 0xb5, 0x85, 0x7b, 0x5e, 0x70, 0x21, 0xa2, 0x94, 0x05, 0x1b, 0x5b, 0x6d, 0xec,
+	0x7b, 0xcd, 0x2d, 0xf1, 0x21, 0x60, 0x75, 0x00, 0x85, 0xd7, 0x7d, 0xec, 0x5f, 0x68, 0xf8, 0xac,
+	0x8f, 0x49, 0x80, 0x56, 0x21, 0xdf, 0xb4, 0x5d, 0xf3, 0x54, 0xef, 0x3a, 0x16, 0x1e, 0x54, 0x53,
+	0x6b, 0xa9, 0x8d, 0xb4, 0x06, 0x1c, 0xaa, 0x33, 0x04, 0xdd, 0x86, 0x59, 0xd3, 0x30, 0x3b, 0x58,
+	0x0f, 0xc3, 0xac, 0xea, 0x24, 0x8d, 0x29, 0x68, 0x05, 0x8e, 0xee, 0xf1, 0x40, 0x0b, 0xad, 0x43,
+	0x81, 0x18, 0x3d, 0xcf, 0xc6, 0x3a, 0xa5, 0x71, 0x5b, 0xd5, 0x29, 0x1e, 0x93, 0x17, 0x58, 0x83,
+	0x41, 0xea, 0x0b, 0x28, 0x86, 0xcc, 0xc4, 0x73, 0x1d, 0x82, 0x51, 0x19, 0xa6, 0x79, 0x4e, 0x4e,
+	0x5a, 0xd0, 0xc4, 0x86, 0x15, 0x24, 0xf8, 0xce, 0x0d, 0x3b, 0x24, 0xcb, 0x6a, 0xc0, 0xa1, 0xb7,
+	0x0c, 0x51, 0x77, 0xa0, 0x74, 0xd4, 0x0f, 0x8e, 0x5a, 0xc7, 0x17, 0x8e, 0x79, 0xdd, 0x2e, 0xd4,
+	0xcf, 0x29, 0x98, 0x8f, 0xdd, 0x0a, 0x2b, 0x58, 0x81, 0xbc, 0xdb, 0x0f, 0x74, 0xb7, 0xa5, 0x13,
+	0x0a, 0xf3, 0x6b, 0x59, 0x2d, 0xe7, 0x46, 0x71, 0xe8, 0x0e, 0xcc, 0x12, 0xc7, 0xf0, 0x48, 0xc7,
+	0x0d, 0xc2, 0xcc, 0x93, 0x3c, 0x73, 0x31, 0x42, 0x85, 0x44, 0xf7, 0x60, 0x5e, 0x86, 0x49, 0x95,
+	0x84, 0x02, 0x73, 0xd1, 0x41, 0x28, 0x94, 0xfa, 0x14, 0xb2, 0x2c, 0x75, 0xdd, 0x69, 0xb9, 0x63,
+	0x04, 0x58, 0x82, 0x1c, 0xed, 0xd5, 0x3c, 0x25, 0xfd, 0x1e, 0xa1, 0x7c, 0x53, 0xf4, 0xe4, 0x0a,
+	0x50, 0xcf, 0x20, 0x1f, 0x6f, 0x7c, 0x11, 0xb2, 0x92, 0x91, 0x77, 0xfd, 0x6a, 0x42, 0x9b, 0x69,
+	0x86, 0x43, 0x59, 0x82, 0x6c, 0x74, 0x51, 0x0c, 0x8d, 0x1e, 0x4a, 0x84, 0x6a, 0x06, 0xad, 0xbe,
+	0x6d, 0xeb, 0x24, 0x30, 0x02, 0xcc, 0xcb, 0xcd, 0xd2, 0xf3, 0x1c, 0xc3, 0x8e, 0x19, 0xb4, 0x97,
+	0x81, 0xf4, 0x73, 0x23, 0x30, 0xd4, 0x77, 0x50, 0x48, 0xa8, 0xb6, 0x09, 0x99, 0x0e, 0x36, 0x2c,
+	0xec, 0x73, 0xc6, 0xfc, 0xf6, 0x7c, 0x2d, 0x34, 0x64, 0x2d, 0xea, 0x8c, 0xe6, 0x09, 0x43, 0x50,
+	0x05, 0xa6, 0xcd, 0x4e, 0xdf, 0x39, 0x95, 0x05, 0x88, 0xad, 0x4c, 0xfe, 0x3d, 0x05, 0xc5, 0x97,
+	0x2e, 0x21, 0x5d, 0x2f, 0x6a, 0x49, 0x85, 0x42, 0xe0, 0x1b, 0x0e, 0x31, 0xcc, 0xa0, 0x4b, 0xf9,
+	0x28, 0x09, 0x93, 0x20, 0x81, 0xa1, 0xfb, 0x30, 0x15, 0x0c, 0x84, 0x3a, 0xf9, 0xed, 0x45, 0xc9,
+	0x1f, 0x3a, 0xfd, 0xcd, 0x55, 0xa8, 0xc6, 0xe2, 0x90, 0x02, 0x59, 0xec, 0x98, 0xae, 0xd5, 0x75,
+	0xda, 0xbc, 0xd1, 0x9c, 0x26, 0xf7, 0xd4, 0x03, 0x60, 0xba, 0x3d, 0xcf, 0xc7, 0x84, 0x60, 0xab,
+	0x9a, 0xe6, 0x93, 0x88, 0x21, 0xea, 0x7b, 0xb8, 0x19, 0xcb, 0x47, 0xe2, 0xe2, 0x57, 0x21, 0xd3,
+	0xea, 0xda, 0x41, 0x28, 0x04, 0x6b, 0x2e, 0xdc, 0x33, 0x6d, 0x79, 0x9b, 0x3a, 0xe9, 0x7e, 0xc2,
+	0xc2, 0x34, 0x4c, 0x5b, 0x8e, 0x1d, 0x53, 0x48, 0xb6, 0xbf, 0x0b, 0xe5, 0xe1, 0xec, 0x0d, 0xc3,
+	0xbf, 0x96, 0x08, 0xea, 0xb7, 0x14, 0x54, 0xff, 0x2d, 0x4d, 0x0e, 0xa9, 0x14, 0x0f, 0xd6, 0x1d,
+	0xea, 0x81, 0xc8, 0x20, 0x73, 0xf1, 0x93, 0x43, 0x6a, 0x85, 0xfd, 0x21, 0xb6, 0x49, 0x3e, 0xd7,
+	0x65, 0xa9, 0xeb, 0xa8, 0x12, 0x69, 0x9e, 0xc4, 0x25, 0xd9, 0xd2, 0x33, 0xa8, 0xc4, 0xe2, 0x1b,
+	0xd4, 0x4e, 0x91, 0x5e, 0x77, 0x21, 0xce, 0x4c, 0x2d, 0x1b, 0xf5, 0x35, 0x1b, 0x83, 0xeb, 0x16,
+	0x51, 0x9f, 0x24, 0x34, 0x17, 0x29, 0xc2, 0xbe, 0xae, 0x23, 0xcc, 0x26, 0xdc, 0x68, 0x60, 0xec,
+	0x1f, 0x0c, 0xcc, 0x8e, 0xe1, 0xb4, 0x71, 0x44, 0x4f, 0x7f, 0x37, 0xbb, 0xdb, 0xeb, 0x06, 0x5c,
+	0x87, 0xa2, 0x26, 0x36, 0xea, 0x43, 0x28, 0x27, 0x83, 0x43, 0x22, 0xfa, 0x1b, 0x1a, 0x96, 0xc5,
+	0x4d, 0x20, 0x58, 0x72, 0xda, 0x15, 0xb0, 0xfd, 0x25, 0x0d, 0x33, 0x27, 0x42, 0x1d, 0xb4, 0x0b,
+	0x19, 0xe1, 0x60, 0x54, 0x91, 0x8a, 0x25, 0x2c, 0xad, 0x54, 0x6a, 0xe2, 0xa5, 0xae, 0x45, 0x2f,
+	0x75, 0xed, 0x80, 0xbd, 0xd4, 0xea, 0x04, 0x7a, 0x04, 0xd3, 0xfc, 0x51, 0x44, 0x0b, 0xf2, 0x6a,
+	0xfc, 0x79, 0x56, 0x2a, 0xc3, 0xb0, 0xa8, 0x8e, 0xde, 0xac, 0xc3, 0xec, 0x3e, 0xfb, 0x95, 0xe5,
+	0xab, 0x86, 0x6e, 0xc9, 0xd8, 0xe1, 0xf7, 0x51, 0x51, 0x46, 0x1d, 0xc9, 0x54, 0x8f, 0x21, 0xcd,
+	0x13, 0x94, 0x13, 0x3f, 0x72, 0x74, 0x77, 0x61, 0x08, 0x8d, 0xae, 0x6d, 0xa4, 0x1e, 0xa4, 0xd0,
+	0x09, 0x94, 0xd8, 0x78, 0xe2, 0x06, 0x41, 0xab, 0xa3, 0x7c, 0x13, 0xf3, 0x81, 0xb2, 0x36, 0x3e,
+	0x40, 0xd6, 0xf4, 0x01, 0x4a, 0x8c, 0x2e, 0x91, 0x78, 0x6d, 0xac, 0x21, 0xa3, 0xcc, 0xeb, 0xff,
+	0x89, 0x48, 0xd4, 0x7d, 0x08, 0xc5, 0x68, 0xe2, 0x6c, 0xfa, 0x04, 0x2d, 0xc9, 0x9b, 0x23, 0xac,
+	0xa3, 0x2c, 0x8f, 0x39, 0x8d, 0x72, 0xee, 0x55, 0x7f, 0xfc, 0x5e, 0x49, 0x5d, 0xd2, 0xf5, 0x8b,
+	0xae, 0xaf, 0x7f, 0x56, 0x26, 0x2e, 0xe9, 0xfa, 0x49, 0x57, 0x33, 0xc3, 0x67, 0xbe, 0xf3, 0x17,
+	0x8a, 0x93, 0xe9, 0x0e, 0xc1, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -704,15 +1031,17 @@ var _ grpc.ClientConn
 // is compatible with the grpc package it is being compiled against.
 const _ = grpc.SupportPackageIsVersion4
 
-// Client API for Wavelet service
-
+// WaveletClient is the client API for Wavelet service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type WaveletClient interface {
-	Gossip(ctx context.Context, in *GossipRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	Gossip(ctx context.Context, in *GossipRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
 	CheckOutOfSync(ctx context.Context, in *OutOfSyncRequest, opts ...grpc.CallOption) (*OutOfSyncResponse, error)
 	Sync(ctx context.Context, opts ...grpc.CallOption) (Wavelet_SyncClient, error)
 	PullTransactions(ctx context.Context, in *TransactionPullRequest, opts ...grpc.CallOption) (*TransactionPullResponse, error)
 	SyncTransactions(ctx context.Context, opts ...grpc.CallOption) (Wavelet_SyncTransactionsClient, error)
+	ExchangePeers(ctx context.Context, in *PeerExchangeRequest, opts ...grpc.CallOption) (*PeerExchangeResponse, error)
 }
 
 type waveletClient struct {
@@ -723,9 +1052,9 @@ func NewWaveletClient(cc *grpc.ClientConn) WaveletClient {
 	return &waveletClient{cc}
 }
 
-func (c *waveletClient) Gossip(ctx context.Context, in *GossipRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
-	err := grpc.Invoke(ctx, "/wavelet.Wavelet/Gossip", in, out, c.cc, opts...)
+func (c *waveletClient) Gossip(ctx context.Context, in *GossipRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/wavelet.Wavelet/Gossip", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -734,7 +1063,7 @@ func (c *waveletClient) Gossip(ctx context.Context, in *GossipRequest, opts ...g
 
 func (c *waveletClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
 	out := new(QueryResponse)
-	err := grpc.Invoke(ctx, "/wavelet.Wavelet/Query", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/wavelet.Wavelet/Query", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -743,7 +1072,7 @@ func (c *waveletClient) Query(ctx context.Context, in *QueryRequest, opts ...grp
 
 func (c *waveletClient) CheckOutOfSync(ctx context.Context, in *OutOfSyncRequest, opts ...grpc.CallOption) (*OutOfSyncResponse, error) {
 	out := new(OutOfSyncResponse)
-	err := grpc.Invoke(ctx, "/wavelet.Wavelet/CheckOutOfSync", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/wavelet.Wavelet/CheckOutOfSync", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -751,7 +1080,7 @@ func (c *waveletClient) CheckOutOfSync(ctx context.Context, in *OutOfSyncRequest
 }
 
 func (c *waveletClient) Sync(ctx context.Context, opts ...grpc.CallOption) (Wavelet_SyncClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Wavelet_serviceDesc.Streams[0], c.cc, "/wavelet.Wavelet/Sync", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Wavelet_serviceDesc.Streams[0], "/wavelet.Wavelet/Sync", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -783,7 +1112,7 @@ func (x *waveletSyncClient) Recv() (*SyncResponse, error) {
 
 func (c *waveletClient) PullTransactions(ctx context.Context, in *TransactionPullRequest, opts ...grpc.CallOption) (*TransactionPullResponse, error) {
 	out := new(TransactionPullResponse)
-	err := grpc.Invoke(ctx, "/wavelet.Wavelet/PullTransactions", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/wavelet.Wavelet/PullTransactions", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -791,7 +1120,7 @@ func (c *waveletClient) PullTransactions(ctx context.Context, in *TransactionPul
 }
 
 func (c *waveletClient) SyncTransactions(ctx context.Context, opts ...grpc.CallOption) (Wavelet_SyncTransactionsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Wavelet_serviceDesc.Streams[1], c.cc, "/wavelet.Wavelet/SyncTransactions", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Wavelet_serviceDesc.Streams[1], "/wavelet.Wavelet/SyncTransactions", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -821,15 +1150,50 @@ func (x *waveletSyncTransactionsClient) Recv() (*TransactionsSyncResponse, error
 	return m, nil
 }
 
-// Server API for Wavelet service
+func (c *waveletClient) ExchangePeers(ctx context.Context, in *PeerExchangeRequest, opts ...grpc.CallOption) (*PeerExchangeResponse, error) {
+	out := new(PeerExchangeResponse)
+	err := c.cc.Invoke(ctx, "/wavelet.Wavelet/ExchangePeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
+// WaveletServer is the server API for Wavelet service.
 type WaveletServer interface {
-	Gossip(context.Context, *GossipRequest) (*google_protobuf.Empty, error)
+	Gossip(context.Context, *GossipRequest) (*empty.Empty, error)
 	Query(context.Context, *QueryRequest) (*QueryResponse, error)
 	CheckOutOfSync(context.Context, *OutOfSyncRequest) (*OutOfSyncResponse, error)
 	Sync(Wavelet_SyncServer) error
 	PullTransactions(context.Context, *TransactionPullRequest) (*TransactionPullResponse, error)
 	SyncTransactions(Wavelet_SyncTransactionsServer) error
+	ExchangePeers(context.Context, *PeerExchangeRequest) (*PeerExchangeResponse, error)
+}
+
+// UnimplementedWaveletServer can be embedded to have forward compatible implementations.
+type UnimplementedWaveletServer struct {
+}
+
+func (*UnimplementedWaveletServer) Gossip(ctx context.Context, req *GossipRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Gossip not implemented")
+}
+func (*UnimplementedWaveletServer) Query(ctx context.Context, req *QueryRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (*UnimplementedWaveletServer) CheckOutOfSync(ctx context.Context, req *OutOfSyncRequest) (*OutOfSyncResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckOutOfSync not implemented")
+}
+func (*UnimplementedWaveletServer) Sync(srv Wavelet_SyncServer) error {
+	return status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (*UnimplementedWaveletServer) PullTransactions(ctx context.Context, req *TransactionPullRequest) (*TransactionPullResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PullTransactions not implemented")
+}
+func (*UnimplementedWaveletServer) SyncTransactions(srv Wavelet_SyncTransactionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SyncTransactions not implemented")
+}
+func (*UnimplementedWaveletServer) ExchangePeers(ctx context.Context, req *PeerExchangeRequest) (*PeerExchangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExchangePeers not implemented")
 }
 
 func RegisterWaveletServer(s *grpc.Server, srv WaveletServer) {
@@ -960,6 +1324,24 @@ func (x *waveletSyncTransactionsServer) Recv() (*TransactionsSyncRequest, error)
 	return m, nil
 }
 
+func _Wavelet_ExchangePeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerExchangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaveletServer).ExchangePeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wavelet.Wavelet/ExchangePeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaveletServer).ExchangePeers(ctx, req.(*PeerExchangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Wavelet_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "wavelet.Wavelet",
 	HandlerType: (*WaveletServer)(nil),
@@ -980,6 +1362,10 @@ var _Wavelet_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PullTransactions",
 			Handler:    _Wavelet_PullTransactions_Handler,
 		},
+		{
+			MethodName: "ExchangePeers",
+			Handler:    _Wavelet_ExchangePeers_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -995,13 +1381,13 @@ var _Wavelet_serviceDesc = grpc.ServiceDesc{
 			ClientStreams: true,
 		},
 	},
-	Metadata: "src/rpc.proto",
+	Metadata: "rpc.proto",
 }
 
 func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1009,28 +1395,41 @@ func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *QueryRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.BlockIndex != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(m.BlockIndex))
+	if len(m.SampleProof) > 0 {
+		i -= len(m.SampleProof)
+		copy(dAtA[i:], m.SampleProof)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.SampleProof)))
+		i--
+		dAtA[i] = 0x1a
 	}
 	if len(m.CacheBlockId) > 0 {
-		dAtA[i] = 0x12
-		i++
+		i -= len(m.CacheBlockId)
+		copy(dAtA[i:], m.CacheBlockId)
 		i = encodeVarintRpc(dAtA, i, uint64(len(m.CacheBlockId)))
-		i += copy(dAtA[i:], m.CacheBlockId)
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.BlockIndex != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.BlockIndex))
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *QueryResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1038,33 +1437,39 @@ func (m *QueryResponse) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *QueryResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Block) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Block)))
-		i += copy(dAtA[i:], m.Block)
-	}
 	if m.CacheValid {
-		dAtA[i] = 0x10
-		i++
+		i--
 		if m.CacheValid {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
+		i--
+		dAtA[i] = 0x10
 	}
-	return i, nil
-}
+	if len(m.Block) > 0 {
+		i -= len(m.Block)
+		copy(dAtA[i:], m.Block)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Block)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
 
 func (m *OutOfSyncRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1072,22 +1477,27 @@ func (m *OutOfSyncRequest) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *OutOfSyncRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OutOfSyncRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if m.BlockIndex != 0 {
-		dAtA[i] = 0x8
-		i++
 		i = encodeVarintRpc(dAtA, i, uint64(m.BlockIndex))
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *OutOfSyncResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1095,27 +1505,44 @@ func (m *OutOfSyncResponse) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *OutOfSyncResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OutOfSyncResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if len(m.SnapshotBlockId) > 0 {
+		i -= len(m.SnapshotBlockId)
+		copy(dAtA[i:], m.SnapshotBlockId)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.SnapshotBlockId)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.SnapshotIndex != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.SnapshotIndex))
+		i--
+		dAtA[i] = 0x10
+	}
 	if m.OutOfSync {
-		dAtA[i] = 0x8
-		i++
+		i--
 		if m.OutOfSync {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *SyncInfo) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1123,31 +1550,38 @@ func (m *SyncInfo) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *SyncInfo) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SyncInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Block) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Block)))
-		i += copy(dAtA[i:], m.Block)
-	}
 	if len(m.Checksums) > 0 {
-		for _, b := range m.Checksums {
+		for iNdEx := len(m.Checksums) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Checksums[iNdEx])
+			copy(dAtA[i:], m.Checksums[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Checksums[iNdEx])))
+			i--
 			dAtA[i] = 0x12
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(len(b)))
-			i += copy(dAtA[i:], b)
 		}
 	}
-	return i, nil
+	if len(m.Block) > 0 {
+		i -= len(m.Block)
+		copy(dAtA[i:], m.Block)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Block)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
 func (m *SyncRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1155,41 +1589,73 @@ func (m *SyncRequest) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *SyncRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SyncRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if m.Data != nil {
-		nn1, err := m.Data.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size := m.Data.Size()
+			i -= size
+			if _, err := m.Data.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
 		}
-		i += nn1
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *SyncRequest_BlockId) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	dAtA[i] = 0x8
-	i++
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *SyncRequest_BlockId) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	i = encodeVarintRpc(dAtA, i, uint64(m.BlockId))
-	return i, nil
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
 func (m *SyncRequest_Checksum) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *SyncRequest_Checksum) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Checksum != nil {
-		dAtA[i] = 0x12
-		i++
+		i -= len(m.Checksum)
+		copy(dAtA[i:], m.Checksum)
 		i = encodeVarintRpc(dAtA, i, uint64(len(m.Checksum)))
-		i += copy(dAtA[i:], m.Checksum)
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+func (m *SyncRequest_FullState) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *SyncRequest_FullState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i--
+	if m.FullState {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
 	}
-	return i, nil
+	i--
+	dAtA[i] = 0x18
+	return len(dAtA) - i, nil
 }
 func (m *SyncResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1197,48 +1663,66 @@ func (m *SyncResponse) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *SyncResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SyncResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if m.Data != nil {
-		nn2, err := m.Data.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size := m.Data.Size()
+			i -= size
+			if _, err := m.Data.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
 		}
-		i += nn2
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *SyncResponse_Header) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *SyncResponse_Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Header != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(m.Header.Size()))
-		n3, err := m.Header.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.Header.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
 		}
-		i += n3
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *SyncResponse_Chunk) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *SyncResponse_Chunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Chunk != nil {
-		dAtA[i] = 0x12
-		i++
+		i -= len(m.Chunk)
+		copy(dAtA[i:], m.Chunk)
 		i = encodeVarintRpc(dAtA, i, uint64(len(m.Chunk)))
-		i += copy(dAtA[i:], m.Chunk)
+		i--
+		dAtA[i] = 0x12
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *GossipRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1246,25 +1730,59 @@ func (m *GossipRequest) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *GossipRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GossipRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if len(m.Compressed) > 0 {
+		i -= len(m.Compressed)
+		copy(dAtA[i:], m.Compressed)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Compressed)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Encoding) > 0 {
+		i -= len(m.Encoding)
+		copy(dAtA[i:], m.Encoding)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Encoding)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Txs) > 0 {
+		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Txs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.Transactions) > 0 {
-		for _, b := range m.Transactions {
+		for iNdEx := len(m.Transactions) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Transactions[iNdEx])
+			copy(dAtA[i:], m.Transactions[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Transactions[iNdEx])))
+			i--
 			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(len(b)))
-			i += copy(dAtA[i:], b)
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *TransactionsSyncRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1272,41 +1790,57 @@ func (m *TransactionsSyncRequest) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *TransactionsSyncRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransactionsSyncRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if m.Data != nil {
-		nn4, err := m.Data.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size := m.Data.Size()
+			i -= size
+			if _, err := m.Data.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
 		}
-		i += nn4
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *TransactionsSyncRequest_Filter) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *TransactionsSyncRequest_Filter) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Filter != nil {
-		dAtA[i] = 0xa
-		i++
+		i -= len(m.Filter)
+		copy(dAtA[i:], m.Filter)
 		i = encodeVarintRpc(dAtA, i, uint64(len(m.Filter)))
-		i += copy(dAtA[i:], m.Filter)
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *TransactionsSyncRequest_ChunkSize) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	dAtA[i] = 0x10
-	i++
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *TransactionsSyncRequest_ChunkSize) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	i = encodeVarintRpc(dAtA, i, uint64(m.ChunkSize))
-	return i, nil
+	i--
+	dAtA[i] = 0x10
+	return len(dAtA) - i, nil
 }
 func (m *TransactionsSyncPart) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1314,25 +1848,31 @@ func (m *TransactionsSyncPart) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *TransactionsSyncPart) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransactionsSyncPart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Transactions) > 0 {
-		for _, b := range m.Transactions {
+		for iNdEx := len(m.Transactions) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Transactions[iNdEx])
+			copy(dAtA[i:], m.Transactions[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Transactions[iNdEx])))
+			i--
 			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(len(b)))
-			i += copy(dAtA[i:], b)
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *TransactionsSyncResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1340,45 +1880,62 @@ func (m *TransactionsSyncResponse) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *TransactionsSyncResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransactionsSyncResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if m.Data != nil {
-		nn5, err := m.Data.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size := m.Data.Size()
+			i -= size
+			if _, err := m.Data.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
 		}
-		i += nn5
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *TransactionsSyncResponse_TransactionsNum) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	dAtA[i] = 0x8
-	i++
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *TransactionsSyncResponse_TransactionsNum) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	i = encodeVarintRpc(dAtA, i, uint64(m.TransactionsNum))
-	return i, nil
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
 func (m *TransactionsSyncResponse_Transactions) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *TransactionsSyncResponse_Transactions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Transactions != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(m.Transactions.Size()))
-		n6, err := m.Transactions.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.Transactions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
 		}
-		i += n6
+		i--
+		dAtA[i] = 0x12
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *TransactionPullRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1386,25 +1943,31 @@ func (m *TransactionPullRequest) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *TransactionPullRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransactionPullRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.TransactionIds) > 0 {
-		for _, b := range m.TransactionIds {
+		for iNdEx := len(m.TransactionIds) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TransactionIds[iNdEx])
+			copy(dAtA[i:], m.TransactionIds[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.TransactionIds[iNdEx])))
+			i--
 			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(len(b)))
-			i += copy(dAtA[i:], b)
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *TransactionPullResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1412,49 +1975,102 @@ func (m *TransactionPullResponse) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *TransactionPullResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransactionPullResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Transactions) > 0 {
-		for _, b := range m.Transactions {
+		for iNdEx := len(m.Transactions) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Transactions[iNdEx])
+			copy(dAtA[i:], m.Transactions[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Transactions[iNdEx])))
+			i--
 			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(len(b)))
-			i += copy(dAtA[i:], b)
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
-func encodeFixed64Rpc(dAtA []byte, offset int, v uint64) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	dAtA[offset+4] = uint8(v >> 32)
-	dAtA[offset+5] = uint8(v >> 40)
-	dAtA[offset+6] = uint8(v >> 48)
-	dAtA[offset+7] = uint8(v >> 56)
-	return offset + 8
-}
-func encodeFixed32Rpc(dAtA []byte, offset int, v uint32) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	return offset + 4
+func (m *PeerExchangeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
+
+func (m *PeerExchangeRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PeerExchangeRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Limit != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PeerExchangeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PeerExchangeResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PeerExchangeResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Addresses) > 0 {
+		for iNdEx := len(m.Addresses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Addresses[iNdEx])
+			copy(dAtA[i:], m.Addresses[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Addresses[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintRpc(dAtA []byte, offset int, v uint64) int {
+	offset -= sovRpc(v)
+	base := offset
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
 		v >>= 7
 		offset++
 	}
 	dAtA[offset] = uint8(v)
-	return offset + 1
+	return base
 }
 func (m *QueryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.BlockIndex != 0 {
@@ -1464,10 +2080,17 @@ func (m *QueryRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRpc(uint64(l))
 	}
+	l = len(m.SampleProof)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
 	return n
 }
 
 func (m *QueryResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.Block)
@@ -1481,6 +2104,9 @@ func (m *QueryResponse) Size() (n int) {
 }
 
 func (m *OutOfSyncRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.BlockIndex != 0 {
@@ -1490,15 +2116,28 @@ func (m *OutOfSyncRequest) Size() (n int) {
 }
 
 func (m *OutOfSyncResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.OutOfSync {
 		n += 2
 	}
+	if m.SnapshotIndex != 0 {
+		n += 1 + sovRpc(uint64(m.SnapshotIndex))
+	}
+	l = len(m.SnapshotBlockId)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
 	return n
 }
 
 func (m *SyncInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.Block)
@@ -1515,6 +2154,9 @@ func (m *SyncInfo) Size() (n int) {
 }
 
 func (m *SyncRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Data != nil {
@@ -1524,12 +2166,18 @@ func (m *SyncRequest) Size() (n int) {
 }
 
 func (m *SyncRequest_BlockId) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	n += 1 + sovRpc(uint64(m.BlockId))
 	return n
 }
 func (m *SyncRequest_Checksum) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Checksum != nil {
@@ -1538,7 +2186,19 @@ func (m *SyncRequest_Checksum) Size() (n int) {
 	}
 	return n
 }
+func (m *SyncRequest_FullState) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	return n
+}
 func (m *SyncResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Data != nil {
@@ -1548,6 +2208,9 @@ func (m *SyncResponse) Size() (n int) {
 }
 
 func (m *SyncResponse_Header) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Header != nil {
@@ -1557,6 +2220,9 @@ func (m *SyncResponse_Header) Size() (n int) {
 	return n
 }
 func (m *SyncResponse_Chunk) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Chunk != nil {
@@ -1566,6 +2232,9 @@ func (m *SyncResponse_Chunk) Size() (n int) {
 	return n
 }
 func (m *GossipRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Transactions) > 0 {
@@ -1574,10 +2243,27 @@ func (m *GossipRequest) Size() (n int) {
 			n += 1 + l + sovRpc(uint64(l))
 		}
 	}
+	if len(m.Txs) > 0 {
+		for _, e := range m.Txs {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	l = len(m.Encoding)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Compressed)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
 	return n
 }
 
 func (m *TransactionsSyncRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Data != nil {
@@ -1587,6 +2273,9 @@ func (m *TransactionsSyncRequest) Size() (n int) {
 }
 
 func (m *TransactionsSyncRequest_Filter) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Filter != nil {
@@ -1596,12 +2285,18 @@ func (m *TransactionsSyncRequest_Filter) Size() (n int) {
 	return n
 }
 func (m *TransactionsSyncRequest_ChunkSize) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	n += 1 + sovRpc(uint64(m.ChunkSize))
 	return n
 }
 func (m *TransactionsSyncPart) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Transactions) > 0 {
@@ -1614,6 +2309,9 @@ func (m *TransactionsSyncPart) Size() (n int) {
 }
 
 func (m *TransactionsSyncResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Data != nil {
@@ -1623,12 +2321,18 @@ func (m *TransactionsSyncResponse) Size() (n int) {
 }
 
 func (m *TransactionsSyncResponse_TransactionsNum) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	n += 1 + sovRpc(uint64(m.TransactionsNum))
 	return n
 }
 func (m *TransactionsSyncResponse_Transactions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Transactions != nil {
@@ -1638,6 +2342,9 @@ func (m *TransactionsSyncResponse_Transactions) Size() (n int) {
 	return n
 }
 func (m *TransactionPullRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.TransactionIds) > 0 {
@@ -1650,6 +2357,9 @@ func (m *TransactionPullRequest) Size() (n int) {
 }
 
 func (m *TransactionPullResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Transactions) > 0 {
@@ -1661,16 +2371,36 @@ func (m *TransactionPullResponse) Size() (n int) {
 	return n
 }
 
-func sovRpc(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+func (m *PeerExchangeRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Limit != 0 {
+		n += 1 + sovRpc(uint64(m.Limit))
+	}
+	return n
+}
+
+func (m *PeerExchangeResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Addresses) > 0 {
+		for _, s := range m.Addresses {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
 		}
 	}
 	return n
 }
+
+func sovRpc(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
 func sozRpc(x uint64) (n int) {
 	return sovRpc(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
@@ -1689,7 +2419,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1717,7 +2447,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.BlockIndex |= (uint64(b) & 0x7F) << shift
+				m.BlockIndex |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1736,7 +2466,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1745,6 +2475,9 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1753,6 +2486,40 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 				m.CacheBlockId = []byte{}
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SampleProof", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SampleProof = append(m.SampleProof[:0], dAtA[iNdEx:postIndex]...)
+			if m.SampleProof == nil {
+				m.SampleProof = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRpc(dAtA[iNdEx:])
@@ -1762,6 +2529,9 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1789,7 +2559,7 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1817,7 +2587,7 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1826,6 +2596,9 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1848,7 +2621,7 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1863,6 +2636,9 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1890,7 +2666,7 @@ func (m *OutOfSyncRequest) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1918,7 +2694,7 @@ func (m *OutOfSyncRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.BlockIndex |= (uint64(b) & 0x7F) << shift
+				m.BlockIndex |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1932,6 +2708,9 @@ func (m *OutOfSyncRequest) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1959,7 +2738,7 @@ func (m *OutOfSyncResponse) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1987,12 +2766,65 @@ func (m *OutOfSyncResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 			m.OutOfSync = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SnapshotIndex", wireType)
+			}
+			m.SnapshotIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SnapshotIndex |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SnapshotBlockId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SnapshotBlockId = append(m.SnapshotBlockId[:0], dAtA[iNdEx:postIndex]...)
+			if m.SnapshotBlockId == nil {
+				m.SnapshotBlockId = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRpc(dAtA[iNdEx:])
@@ -2002,6 +2834,9 @@ func (m *OutOfSyncResponse) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2029,7 +2864,7 @@ func (m *SyncInfo) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2057,7 +2892,7 @@ func (m *SyncInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2066,6 +2901,9 @@ func (m *SyncInfo) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2088,7 +2926,7 @@ func (m *SyncInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2097,6 +2935,9 @@ func (m *SyncInfo) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2112,6 +2953,9 @@ func (m *SyncInfo) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2139,7 +2983,7 @@ func (m *SyncRequest) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2167,7 +3011,7 @@ func (m *SyncRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (uint64(b) & 0x7F) << shift
+				v |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2187,7 +3031,7 @@ func (m *SyncRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2196,6 +3040,9 @@ func (m *SyncRequest) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2203,6 +3050,27 @@ func (m *SyncRequest) Unmarshal(dAtA []byte) error {
 			copy(v, dAtA[iNdEx:postIndex])
 			m.Data = &SyncRequest_Checksum{v}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FullState", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.Data = &SyncRequest_FullState{b}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRpc(dAtA[iNdEx:])
@@ -2212,6 +3080,9 @@ func (m *SyncRequest) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2239,7 +3110,7 @@ func (m *SyncResponse) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2267,7 +3138,7 @@ func (m *SyncResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2276,6 +3147,9 @@ func (m *SyncResponse) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2299,7 +3173,7 @@ func (m *SyncResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2308,6 +3182,9 @@ func (m *SyncResponse) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2324,6 +3201,9 @@ func (m *SyncResponse) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2351,7 +3231,7 @@ func (m *GossipRequest) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2379,7 +3259,7 @@ func (m *GossipRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2388,12 +3268,115 @@ func (m *GossipRequest) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
 			m.Transactions = append(m.Transactions, make([]byte, postIndex-iNdEx))
 			copy(m.Transactions[len(m.Transactions)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Txs = append(m.Txs, &ledgerpb.Transaction{})
+			if err := m.Txs[len(m.Txs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Encoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compressed", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Compressed = append(m.Compressed[:0], dAtA[iNdEx:postIndex]...)
+			if m.Compressed == nil {
+				m.Compressed = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRpc(dAtA[iNdEx:])
@@ -2403,6 +3386,9 @@ func (m *GossipRequest) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2430,7 +3416,7 @@ func (m *TransactionsSyncRequest) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2458,7 +3444,7 @@ func (m *TransactionsSyncRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2467,6 +3453,9 @@ func (m *TransactionsSyncRequest) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2488,7 +3477,7 @@ func (m *TransactionsSyncRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (uint64(b) & 0x7F) << shift
+				v |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2503,6 +3492,9 @@ func (m *TransactionsSyncRequest) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2530,7 +3522,7 @@ func (m *TransactionsSyncPart) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2558,7 +3550,7 @@ func (m *TransactionsSyncPart) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2567,6 +3559,9 @@ func (m *TransactionsSyncPart) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2582,6 +3577,9 @@ func (m *TransactionsSyncPart) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2609,7 +3607,7 @@ func (m *TransactionsSyncResponse) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2637,7 +3635,7 @@ func (m *TransactionsSyncResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (uint64(b) & 0x7F) << shift
+				v |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2657,7 +3655,7 @@ func (m *TransactionsSyncResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2666,6 +3664,9 @@ func (m *TransactionsSyncResponse) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2684,6 +3685,9 @@ func (m *TransactionsSyncResponse) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2711,7 +3715,7 @@ func (m *TransactionPullRequest) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2739,7 +3743,7 @@ func (m *TransactionPullRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2748,6 +3752,9 @@ func (m *TransactionPullRequest) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2763,6 +3770,9 @@ func (m *TransactionPullRequest) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2790,7 +3800,7 @@ func (m *TransactionPullResponse) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2818,7 +3828,7 @@ func (m *TransactionPullResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2827,6 +3837,9 @@ func (m *TransactionPullResponse) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRpc
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2842,6 +3855,166 @@ func (m *TransactionPullResponse) Unmarshal(dAtA []byte) error {
 			if skippy < 0 {
 				return ErrInvalidLengthRpc
 			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PeerExchangeRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerExchangeRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerExchangeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PeerExchangeResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerExchangeResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerExchangeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Addresses = append(m.Addresses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2908,10 +4081,13 @@ func skipRpc(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			iNdEx += length
 			if length < 0 {
 				return 0, ErrInvalidLengthRpc
 			}
+			iNdEx += length
+			if iNdEx < 0 {
+				return 0, ErrInvalidLengthRpc
+			}
 			return iNdEx, nil
 		case 3:
 			for {
@@ -2940,6 +4116,9 @@ func skipRpc(dAtA []byte) (n int, err error) {
 					return 0, err
 				}
 				iNdEx = start + next
+				if iNdEx < 0 {
+					return 0, ErrInvalidLengthRpc
+				}
 			}
 			return iNdEx, nil
 		case 4:
@@ -2958,50 +4137,3 @@ var (
 	ErrInvalidLengthRpc = fmt.Errorf("proto: negative length found during unmarshaling")
 	ErrIntOverflowRpc   = fmt.Errorf("proto: integer overflow")
 )
-
-func init() { proto.RegisterFile("src/rpc.proto", fileDescriptorRpc) }
-
-var fileDescriptorRpc = []byte{
-	// 649 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcb, 0x6e, 0xd3, 0x40,
-	0x14, 0xb5, 0xfb, 0x48, 0xd3, 0x1b, 0xb7, 0xa4, 0xa3, 0x36, 0x18, 0xb7, 0xa4, 0x61, 0x84, 0x44,
-	0x24, 0x24, 0x17, 0x35, 0x1b, 0xa8, 0x04, 0x12, 0x2d, 0x8f, 0x64, 0x43, 0x8b, 0x0b, 0x74, 0x01,
-	0xc8, 0x72, 0xed, 0x49, 0x63, 0xd5, 0xf1, 0x04, 0x8f, 0x5d, 0x48, 0xbf, 0x82, 0x05, 0xbf, 0xc3,
-	0x9e, 0x25, 0x9f, 0x80, 0xca, 0x8f, 0x20, 0x8f, 0xed, 0x61, 0x1c, 0x1a, 0x94, 0x55, 0x34, 0x27,
-	0xe7, 0x9e, 0x7b, 0xee, 0xb9, 0xe3, 0x81, 0x15, 0x16, 0xb9, 0x3b, 0xd1, 0xc8, 0x35, 0x47, 0x11,
-	0x8d, 0x29, 0x5a, 0xfa, 0xec, 0x5c, 0x90, 0x80, 0xc4, 0xc6, 0xe6, 0x19, 0xa5, 0x67, 0x01, 0xd9,
-	0xe1, 0xf0, 0x69, 0xd2, 0xdf, 0x21, 0xc3, 0x51, 0x3c, 0xce, 0x58, 0xf8, 0x2d, 0x68, 0xaf, 0x13,
-	0x12, 0x8d, 0x2d, 0xf2, 0x29, 0x21, 0x2c, 0x46, 0xdb, 0x50, 0x3b, 0x0d, 0xa8, 0x7b, 0x6e, 0xfb,
-	0xa1, 0x47, 0xbe, 0xe8, 0x6a, 0x4b, 0x6d, 0x2f, 0x58, 0xc0, 0xa1, 0x5e, 0x8a, 0xa0, 0xbb, 0xb0,
-	0xea, 0x3a, 0xee, 0x80, 0xd8, 0x39, 0xcd, 0xd3, 0xe7, 0x5a, 0x6a, 0x5b, 0xb3, 0x34, 0x8e, 0xee,
-	0x73, 0xa2, 0x87, 0x5f, 0xc0, 0x4a, 0x2e, 0xcb, 0x46, 0x34, 0x64, 0x04, 0xad, 0xc3, 0x22, 0x2f,
-	0xe0, 0x8a, 0x9a, 0x95, 0x1d, 0xd2, 0x6e, 0x99, 0xd8, 0x85, 0x13, 0xe4, 0x4a, 0x55, 0x0b, 0x38,
-	0xf4, 0x2e, 0x45, 0x70, 0x07, 0xea, 0x87, 0x49, 0x7c, 0xd8, 0x3f, 0x1e, 0x87, 0xee, 0xac, 0x16,
-	0x71, 0x07, 0xd6, 0xa4, 0xa2, 0xdc, 0x40, 0x13, 0x6a, 0x34, 0x89, 0x6d, 0xda, 0xb7, 0xd9, 0x38,
-	0x74, 0x79, 0x55, 0xd5, 0x5a, 0xa6, 0x05, 0x0f, 0x3f, 0x81, 0x6a, 0xfa, 0xdb, 0x0b, 0xfb, 0x74,
-	0x8a, 0xd9, 0x2d, 0x58, 0x76, 0x07, 0xc4, 0x3d, 0x67, 0xc9, 0x90, 0xe9, 0x73, 0xad, 0xf9, 0xb6,
-	0x66, 0xfd, 0x05, 0xf0, 0x11, 0xd4, 0x64, 0x93, 0x9b, 0x50, 0x15, 0x01, 0x71, 0x87, 0x5d, 0xc5,
-	0x5a, 0xca, 0x3c, 0x7a, 0x68, 0x0b, 0xaa, 0x45, 0x61, 0x96, 0x5e, 0x57, 0xb1, 0x04, 0xb2, 0x5f,
-	0x81, 0x85, 0x67, 0x4e, 0xec, 0xe0, 0xf7, 0xa0, 0x95, 0x26, 0xb8, 0x0f, 0x95, 0x01, 0x71, 0x3c,
-	0x12, 0x71, 0xc1, 0xda, 0xee, 0x9a, 0x99, 0x6f, 0xd8, 0x2c, 0x8c, 0x77, 0x15, 0x2b, 0xa7, 0xa0,
-	0x06, 0x2c, 0xba, 0x83, 0x24, 0x3c, 0x17, 0xfa, 0xd9, 0x51, 0x88, 0x77, 0x60, 0xe5, 0x25, 0x65,
-	0xcc, 0x1f, 0x15, 0x86, 0x31, 0x68, 0x71, 0xe4, 0x84, 0xcc, 0x71, 0x63, 0x9f, 0x86, 0x4c, 0x57,
-	0xf9, 0x80, 0x25, 0x0c, 0x7f, 0x80, 0x9b, 0x6f, 0xa4, 0xb3, 0x3c, 0xaf, 0x0e, 0x95, 0xbe, 0x1f,
-	0xc4, 0xb9, 0xb9, 0xb4, 0x61, 0x7e, 0x46, 0xdb, 0x00, 0xbc, 0xb5, 0xcd, 0xfc, 0x4b, 0xc2, 0xed,
-	0xa4, 0x59, 0x2c, 0x73, 0xec, 0xd8, 0xbf, 0x24, 0xc2, 0xd2, 0x1e, 0xac, 0x4f, 0xaa, 0x1f, 0x39,
-	0xd1, 0x6c, 0xce, 0xbe, 0xa9, 0xa0, 0xff, 0x6b, 0x4d, 0x04, 0x57, 0x97, 0xc9, 0x76, 0x98, 0x0c,
-	0xc5, 0x4e, 0x6e, 0xc8, 0xff, 0xbc, 0x4a, 0x86, 0xe8, 0x60, 0xa2, 0xdb, 0x1c, 0xcf, 0xfa, 0xb6,
-	0xc8, 0xfa, 0x3a, 0x8b, 0x5d, 0xa5, 0x6c, 0x47, 0x8c, 0xf4, 0x14, 0x1a, 0x12, 0xff, 0x28, 0x09,
-	0x82, 0x22, 0xaf, 0x7b, 0x20, 0x77, 0xb6, 0x7d, 0xaf, 0x98, 0x6b, 0x55, 0x82, 0x7b, 0x1e, 0xc3,
-	0x8f, 0x4b, 0x99, 0x67, 0x12, 0xf9, 0x5c, 0x33, 0x04, 0xb3, 0xfb, 0x7d, 0x1e, 0x96, 0x4e, 0x32,
-	0xeb, 0x68, 0x0f, 0x2a, 0xd9, 0xce, 0x51, 0x43, 0x8c, 0x53, 0xba, 0x04, 0x46, 0xc3, 0xcc, 0xde,
-	0x0a, 0xb3, 0x78, 0x2b, 0xcc, 0xe7, 0xe9, 0x5b, 0x81, 0x15, 0xf4, 0x10, 0x16, 0xf9, 0x07, 0x8d,
-	0x36, 0x44, 0xa9, 0xfc, 0x6e, 0x18, 0x8d, 0x49, 0x38, 0xf3, 0x88, 0x15, 0xd4, 0x83, 0xd5, 0x83,
-	0xf4, 0x6a, 0x8b, 0x4f, 0x12, 0xdd, 0x12, 0xdc, 0xc9, 0x6f, 0xdb, 0x30, 0xae, 0xfb, 0x4b, 0x48,
-	0x3d, 0x82, 0x05, 0x2e, 0xb0, 0x5e, 0xba, 0xf9, 0x45, 0xed, 0xc6, 0x04, 0x5a, 0x94, 0xb5, 0xd5,
-	0x07, 0x2a, 0x3a, 0x81, 0x7a, 0x9a, 0x9d, 0xbc, 0x3d, 0xb4, 0x7d, 0xdd, 0x52, 0xa5, 0x25, 0x19,
-	0xad, 0xe9, 0x04, 0xe1, 0xe9, 0x23, 0xd4, 0xd3, 0x76, 0x25, 0xe1, 0xd6, 0xd4, 0xdb, 0x52, 0x28,
-	0xdf, 0xf9, 0x0f, 0x43, 0xf6, 0xbd, 0x5f, 0xff, 0x71, 0xd5, 0x54, 0x7f, 0x5e, 0x35, 0xd5, 0x5f,
-	0x57, 0x4d, 0xf5, 0xeb, 0xef, 0xa6, 0x72, 0x5a, 0xe1, 0xbb, 0xe9, 0xfc, 0x09, 0x00, 0x00, 0xff,
-	0xff, 0xce, 0x20, 0xb0, 0x69, 0xef, 0x05, 0x00, 0x00,
-}
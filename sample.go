@@ -0,0 +1,309 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/security/vrf"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Peers to query are sampled by stake, such that no one but the querying
+// node may predict whom it queries next, and thus target its sample with DoS
+// attacks. Once sys.FeatureSampleProofs is active, validators are instead
+// drawn with a VRF keyed by the querying node over the block being finalized,
+// leaving the node no input to grind for a sample of its liking. The node
+// queries the first of the validators drawn it is connected to, until the
+// block is finalized. The VRF proof is sent alongside each query, and queried
+// peers verify that they were drawn.
+
+// sampleInput returns the VRF input of the peer sample queried about the
+// block at index, succeeding the block with ID previous.
+func sampleInput(index uint64, previous BlockID) []byte {
+	const prefix = "wavelet-sample"
+
+	buf := make([]byte, len(prefix)+8+SizeBlockID)
+
+	copy(buf, prefix)
+	binary.BigEndian.PutUint64(buf[len(prefix):], index)
+	copy(buf[len(prefix)+8:], previous[:])
+
+	return buf
+}
+
+// sampleCandidates returns the IDs of the validators of tree but for querier
+// in ascending order, which a sample of peers to be queried by querier is
+// drawn from, and their stakes.
+func sampleCandidates(tree *avl.Tree, querier AccountID) ([]AccountID, func(AccountID) uint64) {
+	validators := ReadValidators(tree)
+
+	ids := make([]AccountID, 0, len(validators))
+	stakes := make(map[AccountID]uint64, len(validators))
+
+	for _, v := range validators {
+		if v.ID != querier {
+			ids = append(ids, v.ID)
+			stakes[v.ID] = v.Stake
+		}
+	}
+
+	return ids, func(id AccountID) uint64 {
+		return stakes[id]
+	}
+}
+
+// sampleValidators returns the IDs of amount validators of tree, drawn by
+// stake to be queried by querier with seed, in the order they were drawn.
+func sampleValidators(tree *avl.Tree, querier AccountID, amount int, seed vrf.Output) []AccountID {
+	ids, stake := sampleCandidates(tree, querier)
+
+	drawn := make([]AccountID, 0, amount)
+	for _, i := range sampleByStake(ids, amount, seed, stake) {
+		drawn = append(drawn, ids[i])
+	}
+
+	return drawn
+}
+
+// firstReachable returns the first amount of the validators drawn which are
+// reachable, in the order they were drawn.
+func firstReachable(drawn []AccountID, amount int, reachable func(AccountID) bool) []AccountID {
+	selected := make([]AccountID, 0, amount)
+
+	for _, id := range drawn {
+		if len(selected) == amount {
+			break
+		}
+
+		if reachable(id) {
+			selected = append(selected, id)
+		}
+	}
+
+	return selected
+}
+
+// samplePeers returns the peers to query about the block succeeding current,
+// and the proof of their sample should they have been drawn from the
+// validators. Peers are sampled from those we are connected to instead while
+// sys.FeatureSampleProofs is inactive, or while there are fewer validators
+// than peers to sample. Otherwise, sys.SampleDraws times as many validators
+// as there are peers to sample are drawn, and the first of them we are
+// connected to are queried.
+func (l *Ledger) samplePeers(current *Block) ([]skademlia.ClosestPeer, []byte, error) {
+	snowballK := conf.GetSnowballK()
+	keys := l.client.Keys()
+	peers := l.reputation.Filter(l.client.ClosestPeers())
+	snapshot := l.accounts.Snapshot()
+
+	ids, stake := sampleCandidates(snapshot, keys.PublicKey())
+
+	if !sys.FeatureActive(sys.FeatureSampleProofs, current.Index+1) || len(ids) < snowballK {
+		var seed vrf.Output
+		if _, err := rand.Read(seed[:]); err != nil {
+			return nil, nil, err
+		}
+
+		peers, err := SelectPeersByStake(peers, snowballK, seed, stake)
+
+		return peers, nil, err
+	}
+
+	proof, seed := vrf.Prove(keys.PrivateKey(), sampleInput(current.Index+1, current.ID))
+	drawn := sampleValidators(snapshot, keys.PublicKey(), sys.SampleDraws*snowballK, seed)
+
+	connected := make(map[AccountID]skademlia.ClosestPeer, len(peers))
+
+	for _, p := range peers {
+		if p.Conn().GetState() == connectivity.Ready {
+			connected[p.ID().PublicKey()] = p
+		}
+	}
+
+	reachable := func(id AccountID) bool {
+		_, ok := connected[id]
+		return ok
+	}
+
+	selected := make([]skademlia.ClosestPeer, 0, snowballK)
+	for _, id := range firstReachable(drawn, snowballK, reachable) {
+		selected = append(selected, connected[id])
+	}
+
+	if len(selected) == 0 {
+		return nil, nil, errors.Errorf("connected to none of the %d validator(s) drawn", len(drawn))
+	}
+
+	return selected, proof[:], nil
+}
+
+// SelectPeersByStake is SelectPeers with peers sampled by stake, using seed
+// as the source of randomness. Each peer is sampled with a weight of
+// its stake, or sys.MinimumStake should it stake less.
+func SelectPeersByStake(
+	peers []skademlia.ClosestPeer, amount int, seed vrf.Output, stake func(AccountID) uint64,
+) ([]skademlia.ClosestPeer, error) {
+	if len(peers) < amount {
+		return peers, errors.Errorf("only connected to %d peer(s), but require a minimum of %d peer(s)", len(peers), amount)
+	}
+
+	activePeers := make([]skademlia.ClosestPeer, 0, len(peers))
+	ids := make([]AccountID, 0, len(peers))
+
+	for _, p := range peers {
+		if p.Conn().GetState() == connectivity.Ready {
+			activePeers = append(activePeers, p)
+			ids = append(ids, p.ID().PublicKey())
+		}
+	}
+
+	if len(activePeers) <= amount {
+		return activePeers, nil
+	}
+
+	selected := make([]skademlia.ClosestPeer, 0, amount)
+
+	for _, i := range sampleByStake(ids, amount, seed, stake) {
+		selected = append(selected, activePeers[i])
+	}
+
+	return selected, nil
+}
+
+// sampleByStake returns the indices of amount distinct ids, drawn without
+// replacement by their stake, in the order they were drawn. Each id is
+// weighed by its stake, or sys.MinimumStake should it stake less. Every draw
+// derives a number from seed and the count of prior draws, and picks the id
+// within whose range of the cumulative weight of the ids yet to be drawn the
+// number modulo their total weight falls. Draws only involve integer
+// arithmetic, such that every node draws the same ids from the same seed.
+func sampleByStake(ids []AccountID, amount int, seed vrf.Output, stake func(AccountID) uint64) []int {
+	weights := make([]uint64, len(ids))
+
+	// Stakes are bounded by the supply of PERLs, and hence so is their sum.
+	var total uint64
+
+	for i, id := range ids {
+		weight := stake(id)
+		if weight < sys.MinimumStake {
+			weight = sys.MinimumStake
+		}
+
+		// Ids may be drawn even should the minimum stake be configured to 0.
+		if weight == 0 {
+			weight = 1
+		}
+
+		weights[i] = weight
+		total += weight
+	}
+
+	if amount > len(ids) {
+		amount = len(ids)
+	}
+
+	indices := make([]int, 0, amount)
+	buf := append(seed[:], 0, 0, 0, 0)
+
+	for draw := 0; draw < amount; draw++ {
+		binary.BigEndian.PutUint32(buf[len(seed):], uint32(draw))
+		hash := blake2b.Sum256(buf)
+
+		target := binary.BigEndian.Uint64(hash[:8]) % total
+
+		for i, weight := range weights {
+			if target < weight {
+				indices = append(indices, i)
+
+				total -= weight
+				weights[i] = 0
+
+				break
+			}
+
+			target -= weight
+		}
+	}
+
+	return indices
+}
+
+// verifySample checks that our node was sampled to be queried by querier
+// about the block req is about, should it be the block we are finalizing and
+// sys.FeatureSampleProofs be active.
+func (l *Ledger) verifySample(querier *skademlia.ID, req *QueryRequest) error {
+	latest := l.blocks.Latest()
+
+	// Samples are only verified by peers finalizing the same block, whose
+	// validators the sample was drawn from.
+	if req.BlockIndex != latest.Index+1 || !sys.FeatureActive(sys.FeatureSampleProofs, req.BlockIndex) {
+		return nil
+	}
+
+	if querier == nil {
+		return errors.New("could not identify querier")
+	}
+
+	return verifySampleProof(
+		l.accounts.Snapshot(), querier.PublicKey(), l.client.Keys().PublicKey(), conf.GetSnowballK(),
+		req.BlockIndex, latest.ID, req.SampleProof,
+	)
+}
+
+// verifySampleProof checks that proof is the VRF proof of sys.SampleDraws
+// times amount validators of tree drawn by querier, which self is one of, to
+// be queried about the block at index succeeding the block with ID previous.
+// Samples need no proof while there are fewer validators than peers to
+// sample.
+func verifySampleProof(
+	tree *avl.Tree, querier, self AccountID, amount int, index uint64, previous BlockID, proof []byte,
+) error {
+	if ids, _ := sampleCandidates(tree, querier); len(ids) < amount {
+		return nil
+	}
+
+	if len(proof) != vrf.SizeProof {
+		return errors.Errorf("sample proof must be %d bytes, but got %d bytes", vrf.SizeProof, len(proof))
+	}
+
+	var p vrf.Proof
+	copy(p[:], proof)
+
+	seed, err := vrf.Verify(querier, sampleInput(index, previous), p)
+	if err != nil {
+		return errors.Wrapf(err, "invalid sample proof from %x", querier)
+	}
+
+	for _, id := range sampleValidators(tree, querier, sys.SampleDraws*amount, seed) {
+		if id == self {
+			return nil
+		}
+	}
+
+	return errors.Errorf("%x queried us without having drawn us", querier)
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/security/vrf"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleByStake(t *testing.T) {
+	ids := make([]AccountID, 10)
+	for i := range ids {
+		ids[i][0] = byte(i)
+	}
+
+	// The heavily staked id 0 is all but certain to be sampled.
+	stake := func(id AccountID) uint64 {
+		if id[0] == 0 {
+			return sys.MinimumStake * 1000000
+		}
+
+		return 0
+	}
+
+	var seed vrf.Output
+
+	counts := make(map[int]int)
+
+	for i := 0; i < 100; i++ {
+		seed[0], seed[1] = byte(i), byte(i>>8)
+
+		sample := sampleByStake(ids, 3, seed, stake)
+		assert.Len(t, sample, 3)
+
+		// Samples are deterministic given the seed.
+		assert.Equal(t, sample, sampleByStake(ids, 3, seed, stake))
+
+		seen := make(map[int]struct{})
+		for _, i := range sample {
+			seen[i] = struct{}{}
+			counts[i]++
+		}
+
+		assert.Len(t, seen, 3)
+	}
+
+	assert.Equal(t, 100, counts[0])
+	assert.True(t, len(counts) > 3, "samples should vary with the seed")
+
+	// Draws only involve integer arithmetic, and hence never vary across
+	// platforms.
+	assert.Equal(t, []int{9, 2, 8, 6}, sampleByStake(ids, 4, vrf.Output{}, func(id AccountID) uint64 {
+		return 1000000 * uint64(id[0]+1)
+	}))
+}
+
+func TestSampleProof(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	proof, _ := vrf.Prove(key, sampleInput(10, BlockID{1}))
+
+	_, err = vrf.Verify(key.Public(), sampleInput(10, BlockID{1}), proof)
+	assert.NoError(t, err)
+
+	_, err = vrf.Verify(key.Public(), sampleInput(10, BlockID{2}), proof)
+	assert.Error(t, err)
+
+	_, err = vrf.Verify(key.Public(), sampleInput(11, BlockID{1}), proof)
+	assert.Error(t, err)
+}
+
+func TestVerifySampleProof(t *testing.T) {
+	querier, key, err := edwards25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	tree := avl.New(store.NewInmem())
+
+	validators := make([]AccountID, 10)
+	for i := range validators {
+		validators[i][0] = byte(i + 1)
+		WriteAccountStake(tree, validators[i], sys.MinimumStake*uint64(i+1))
+	}
+
+	WriteAccountStake(tree, querier, sys.MinimumStake)
+
+	const amount = 3
+
+	proof, seed := vrf.Prove(key, sampleInput(10, BlockID{1}))
+
+	drawn := sampleValidators(tree, querier, sys.SampleDraws*amount, seed)
+	assert.Len(t, drawn, sys.SampleDraws*amount)
+	assert.NotContains(t, drawn, querier, "queriers should not draw themselves")
+
+	sampled := make(map[AccountID]struct{}, len(drawn))
+	for _, id := range drawn {
+		sampled[id] = struct{}{}
+	}
+
+	assert.Len(t, sampled, len(drawn), "validators should be drawn at most once")
+
+	// Only the validators drawn may be queried.
+	for _, id := range validators {
+		err := verifySampleProof(tree, querier, id, amount, 10, BlockID{1}, proof[:])
+
+		if _, ok := sampled[id]; ok {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+
+	for id := range sampled {
+		// Proofs are bound to the block queried about, and to the querier.
+		assert.Error(t, verifySampleProof(tree, querier, id, amount, 11, BlockID{1}, proof[:]))
+		assert.Error(t, verifySampleProof(tree, querier, id, amount, 10, BlockID{2}, proof[:]))
+		assert.Error(t, verifySampleProof(tree, validators[0], id, amount, 10, BlockID{1}, proof[:]))
+
+		// Queries carrying no proof are rejected.
+		assert.Error(t, verifySampleProof(tree, querier, id, amount, 10, BlockID{1}, nil))
+	}
+
+	// Samples need no proof while there are fewer validators than peers to
+	// sample.
+	assert.NoError(t, verifySampleProof(tree, querier, validators[0], len(validators)+1, 10, BlockID{1}, nil))
+}
+
+func TestFirstReachable(t *testing.T) {
+	drawn := make([]AccountID, 6)
+	for i := range drawn {
+		drawn[i][0] = byte(i)
+	}
+
+	unreachable := map[byte]bool{0: true, 2: true}
+
+	reachable := func(id AccountID) bool {
+		return !unreachable[id[0]]
+	}
+
+	// Validators drawn which are unreachable are skipped over, in favor of
+	// those drawn after them.
+	assert.Equal(t, []AccountID{drawn[1], drawn[3], drawn[4]}, firstReachable(drawn, 3, reachable))
+
+	// Fewer validators than requested are queried should not enough of them
+	// be reachable, and none should none of them be.
+	for i := 3; i < len(drawn); i++ {
+		unreachable[byte(i)] = true
+	}
+
+	assert.Equal(t, []AccountID{drawn[1]}, firstReachable(drawn, 3, reachable))
+
+	unreachable[1] = true
+
+	assert.Empty(t, firstReachable(drawn, 3, reachable))
+}
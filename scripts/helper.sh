@@ -99,4 +99,32 @@ exit 0
             .
     )
 
+    (
+        cd cmd/txblast || exit 1
+        CGO_ENABLED=0 go build \
+            -a \
+            -o ${BUILD_BIN}/${OS}-${ARCH}/txblast${BINARY_POSTFIX} \
+            -ldflags "\
+                -X ${PROJ_DIR}/sys.GitCommit=${GIT_COMMIT} \
+                -X ${PROJ_DIR}/sys.GoVersion=${GO_VERSION} \
+                -X ${PROJ_DIR}/sys.OSArch=${os_arch} \
+                -X ${PROJ_DIR}/sys.VersionMeta=${BUILD_NETWORK} \
+                -X ${PROJ_DIR}/sys.GoExe=${BINARY_POSTFIX}" \
+            .
+    )
+
+    (
+        cd cmd/watchtower || exit 1
+        CGO_ENABLED=0 go build \
+            -a \
+            -o ${BUILD_BIN}/${OS}-${ARCH}/watchtower${BINARY_POSTFIX} \
+            -ldflags "\
+                -X ${PROJ_DIR}/sys.GitCommit=${GIT_COMMIT} \
+                -X ${PROJ_DIR}/sys.GoVersion=${GO_VERSION} \
+                -X ${PROJ_DIR}/sys.OSArch=${os_arch} \
+                -X ${PROJ_DIR}/sys.VersionMeta=${BUILD_NETWORK} \
+                -X ${PROJ_DIR}/sys.GoExe=${BINARY_POSTFIX}" \
+            .
+    )
+
 done
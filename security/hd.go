@@ -0,0 +1,128 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package security
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+)
+
+// Keys are derived hierarchically from seeds as specified by SLIP-0010 for
+// ed25519, which only allows for hardened derivation. Accounts are derived
+// from the master key of a seed as its children at hardened indices, such
+// that account i is at path m/i'.
+
+const (
+	// slip10Curve is the key the master key of SLIP-0010 is derived with for
+	// ed25519.
+	slip10Curve = "ed25519 seed"
+
+	// HardenedOffset is the index of the first hardened child of a key.
+	HardenedOffset uint32 = 1 << 31
+
+	// ChainCodeSize is the size, in bytes, of the chain codes of HD keys.
+	ChainCodeSize = 32
+)
+
+// ErrNonHardenedIndex is returned when deriving a child of an HD key at an
+// index below HardenedOffset, which SLIP-0010 does not support for ed25519.
+var ErrNonHardenedIndex = errors.New("security: ed25519 keys may only be derived at hardened indices")
+
+// HDKey is a private key, along with the chain code its children are derived
+// with.
+type HDKey struct {
+	PrivateKey edwards25519.PrivateKey
+	ChainCode  [ChainCodeSize]byte
+}
+
+// NewMasterKey derives the master key of seed.
+func NewMasterKey(seed []byte) (HDKey, error) {
+	return newHDKey([]byte(slip10Curve), seed)
+}
+
+// HDKeyFromMnemonic derives the master key of the seed of a mnemonic and an
+// optional passphrase.
+func HDKeyFromMnemonic(mnemonic, passphrase string) (HDKey, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return HDKey{}, err
+	}
+
+	return NewMasterKey(MnemonicToSeed(mnemonic, passphrase))
+}
+
+// Derive derives the child of the key at index, which must be hardened.
+func (k HDKey) Derive(index uint32) (HDKey, error) {
+	if index < HardenedOffset {
+		return HDKey{}, errors.Wrapf(ErrNonHardenedIndex, "index %d", index)
+	}
+
+	data := make([]byte, 1+edwards25519.SizePrivateKey/2+4)
+	copy(data[1:], k.PrivateKey[:edwards25519.SizePrivateKey/2])
+	binary.BigEndian.PutUint32(data[1+edwards25519.SizePrivateKey/2:], index)
+
+	return newHDKey(k.ChainCode[:], data)
+}
+
+// DerivePath derives the descendant of the key along path, being a list of
+// hardened indices.
+func (k HDKey) DerivePath(path ...uint32) (HDKey, error) {
+	var err error
+
+	for _, index := range path {
+		if k, err = k.Derive(index); err != nil {
+			return k, err
+		}
+	}
+
+	return k, nil
+}
+
+// DeriveAccount derives the key of the account at index, at path m/index'
+// relative to the key.
+func (k HDKey) DeriveAccount(index uint32) (HDKey, error) {
+	if index >= HardenedOffset {
+		return HDKey{}, errors.Errorf("security: account index must be below %d", HardenedOffset)
+	}
+
+	return k.Derive(HardenedOffset + index)
+}
+
+func newHDKey(key, data []byte) (HDKey, error) {
+	var k HDKey
+
+	mac := hmac.New(sha512.New, key)
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+
+	_, private, err := edwards25519.GenerateKey(bytes.NewReader(sum[:edwards25519.SizePrivateKey/2]))
+	if err != nil {
+		return k, errors.Wrap(err, "security: failed to derive private key")
+	}
+
+	k.PrivateKey = private
+	copy(k.ChainCode[:], sum[edwards25519.SizePrivateKey/2:])
+
+	return k, nil
+}
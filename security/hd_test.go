@@ -0,0 +1,79 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package security
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHDKeyDerivePath(t *testing.T) {
+	// Test vector 1 of SLIP-0010 for ed25519, at path
+	// m/0'/1'/2'/2'/1000000000'.
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	assert.NoError(t, err)
+
+	master, err := NewMasterKey(seed)
+	assert.NoError(t, err)
+	assert.Equal(t, "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb", hex.EncodeToString(master.ChainCode[:]))
+
+	child, err := master.Derive(HardenedOffset)
+	assert.NoError(t, err)
+	assert.Equal(t, "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3", hex.EncodeToString(child.PrivateKey[:32]))
+	assert.Equal(t, "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69", hex.EncodeToString(child.ChainCode[:]))
+
+	leaf, err := master.DerivePath(HardenedOffset, HardenedOffset+1, HardenedOffset+2, HardenedOffset+2, HardenedOffset+1000000000)
+	assert.NoError(t, err)
+	assert.Equal(t, "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793", hex.EncodeToString(leaf.PrivateKey[:32]))
+	assert.Equal(t, "3c24da049451555d51a7014a37337aa4e12d41e485abccfa46b47dfb2af54b7a", hex.EncodeToString(leaf.PrivateKey[32:]))
+	assert.Equal(t, "68789923a0cac2cd5a29172a475fe9e0fb14cd6adb5ad98a3fa70333e7afa230", hex.EncodeToString(leaf.ChainCode[:]))
+
+	_, err = master.Derive(1)
+	assert.Equal(t, ErrNonHardenedIndex, errors.Cause(err))
+}
+
+func TestHDKeyDeriveAccount(t *testing.T) {
+	master, err := HDKeyFromMnemonic(mnemonicVectors[2].mnemonic, "")
+	assert.NoError(t, err)
+
+	key, err := KeyFromMnemonic(mnemonicVectors[2].mnemonic, "")
+	assert.NoError(t, err)
+	assert.Equal(t, key, master.PrivateKey)
+
+	first, err := master.DeriveAccount(0)
+	assert.NoError(t, err)
+
+	child, err := master.Derive(HardenedOffset)
+	assert.NoError(t, err)
+	assert.Equal(t, child, first)
+
+	second, err := master.DeriveAccount(1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.PrivateKey, second.PrivateKey)
+	assert.NotEqual(t, master.PrivateKey, first.PrivateKey)
+
+	_, err = master.DeriveAccount(HardenedOffset)
+	assert.Error(t, err)
+}
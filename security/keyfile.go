@@ -0,0 +1,324 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package security
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Key files store a single private key encrypted with a passphrase as JSON,
+// modelled after the Web3 Secret Storage format. The key sealing the private
+// key is derived from the passphrase with scrypt, and the private key is
+// sealed with AES-256-GCM, authenticating the address alongside it:
+//
+//	{
+//	  "version": 1,
+//	  "address": "<hex-encoded public key>",
+//	  "crypto": {
+//	    "cipher": "aes-256-gcm",
+//	    "ciphertext": "<hex>",
+//	    "cipherparams": {"nonce": "<hex>"},
+//	    "kdf": "scrypt",
+//	    "kdfparams": {"n": 262144, "r": 8, "p": 1, "dklen": 32, "salt": "<hex>"}
+//	  }
+//	}
+
+const (
+	// KeyFileVersion is the version of newly written key files.
+	KeyFileVersion = 1
+
+	// MaxScryptN bounds the cost scrypt may be configured with, such that a
+	// crafted key file may not exhaust the host when decrypting it.
+	MaxScryptN = 1 << 22
+
+	// MaxScryptR and MaxScryptP bound the block size and parallelization
+	// scrypt may be configured with, and MaxScryptMemory the memory it may
+	// use, being 128 * N * r bytes.
+	MaxScryptR      = 32
+	MaxScryptP      = 16
+	MaxScryptMemory = 1 << 30
+
+	keyFileCipher = "aes-256-gcm"
+	keyFileKDF    = "scrypt"
+)
+
+var (
+	// ErrInvalidKeyFile is returned when decrypting a key file that is not
+	// well-formed.
+	ErrInvalidKeyFile = errors.New("security: invalid key file")
+
+	// ErrNoChainCode is returned when decrypting an HD key from a key file
+	// that only holds a private key.
+	ErrNoChainCode = errors.New("security: key file holds no chain code")
+
+	// ErrInvalidScryptParams is returned for scrypt parameters that are out
+	// of bounds.
+	ErrInvalidScryptParams = errors.New("security: invalid scrypt parameters")
+)
+
+// ScryptParams are the scrypt parameters key files are derived with.
+type ScryptParams struct {
+	// N is the CPU and memory cost, being a power of two.
+	N int
+
+	// R is the block size.
+	R int
+
+	// P is the parallelization.
+	P int
+}
+
+// DefaultScryptParams are the scrypt parameters of the Web3 Secret Storage
+// format.
+var DefaultScryptParams = ScryptParams{N: 1 << 18, R: 8, P: 1}
+
+// Validate checks that the parameters are within bounds, such that deriving
+// a key with them takes at most MaxScryptMemory bytes of memory.
+func (p ScryptParams) Validate() error {
+	if p.N <= 1 || p.N&(p.N-1) != 0 {
+		return errors.Wrap(ErrInvalidScryptParams, "n must be a power of two greater than one")
+	}
+
+	if p.N > MaxScryptN {
+		return errors.Wrapf(ErrInvalidScryptParams, "n must be at most %d", MaxScryptN)
+	}
+
+	if p.R <= 0 || p.R > MaxScryptR {
+		return errors.Wrapf(ErrInvalidScryptParams, "r must be between 1 and %d", MaxScryptR)
+	}
+
+	if p.P <= 0 || p.P > MaxScryptP {
+		return errors.Wrapf(ErrInvalidScryptParams, "p must be between 1 and %d", MaxScryptP)
+	}
+
+	if 128*int64(p.N)*int64(p.R) > MaxScryptMemory {
+		return errors.Wrapf(ErrInvalidScryptParams, "128 * n * r must be at most %d bytes", MaxScryptMemory)
+	}
+
+	return nil
+}
+
+type keyFile struct {
+	Version int           `json:"version"`
+	Address string        `json:"address"`
+	Crypto  keyFileCrypto `json:"crypto"`
+}
+
+type keyFileCrypto struct {
+	Cipher       string `json:"cipher"`
+	CipherText   string `json:"ciphertext"`
+	CipherParams struct {
+		Nonce string `json:"nonce"`
+	} `json:"cipherparams"`
+	KDF       string `json:"kdf"`
+	KDFParams struct {
+		N     int    `json:"n"`
+		R     int    `json:"r"`
+		P     int    `json:"p"`
+		DKLen int    `json:"dklen"`
+		Salt  string `json:"salt"`
+	} `json:"kdfparams"`
+}
+
+// IsKeyFile reports whether data looks like a key file, being a JSON object.
+func IsKeyFile(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// EncryptKeyFile seals a private key into a key file, with a key derived
+// from passphrase with params.
+func EncryptKeyFile(key edwards25519.PrivateKey, passphrase []byte, params ScryptParams) ([]byte, error) {
+	return sealKeyFile(key.Public(), key[:], passphrase, params)
+}
+
+// EncryptHDKeyFile seals an HD key into a key file, with a key derived from
+// passphrase with params.
+func EncryptHDKeyFile(k HDKey, passphrase []byte, params ScryptParams) ([]byte, error) {
+	plaintext := append(append([]byte(nil), k.PrivateKey[:]...), k.ChainCode[:]...)
+	return sealKeyFile(k.PrivateKey.Public(), plaintext, passphrase, params)
+}
+
+func sealKeyFile(address edwards25519.PublicKey, plaintext, passphrase []byte, params ScryptParams) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	salt, nonce := make([]byte, saltSize), make([]byte, nonceSize)
+
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	aead, err := newKeyFileAEAD(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	f := keyFile{Version: KeyFileVersion, Address: hex.EncodeToString(address[:])}
+	f.Crypto.Cipher = keyFileCipher
+	f.Crypto.CipherText = hex.EncodeToString(aead.Seal(nil, nonce, plaintext, address[:]))
+	f.Crypto.CipherParams.Nonce = hex.EncodeToString(nonce)
+	f.Crypto.KDF = keyFileKDF
+	f.Crypto.KDFParams.N = params.N
+	f.Crypto.KDFParams.R = params.R
+	f.Crypto.KDFParams.P = params.P
+	f.Crypto.KDFParams.DKLen = keySize
+	f.Crypto.KDFParams.Salt = hex.EncodeToString(salt)
+
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// DecryptKeyFile opens a private key sealed by EncryptKeyFile or
+// EncryptHDKeyFile with the same passphrase.
+func DecryptKeyFile(data, passphrase []byte) (edwards25519.PrivateKey, error) {
+	k, _, err := openKeyFile(data, passphrase)
+	return k.PrivateKey, err
+}
+
+// DecryptHDKeyFile opens an HD key sealed by EncryptHDKeyFile with the same
+// passphrase, or returns ErrNoChainCode should the key file only hold a
+// private key.
+func DecryptHDKeyFile(data, passphrase []byte) (HDKey, error) {
+	k, hd, err := openKeyFile(data, passphrase)
+	if err == nil && !hd {
+		return HDKey{}, ErrNoChainCode
+	}
+
+	return k, err
+}
+
+// openKeyFile opens the key sealed in a key file, reporting whether it is an
+// HD key.
+func openKeyFile(data, passphrase []byte) (HDKey, bool, error) {
+	var k HDKey
+
+	f, address, err := parseKeyFile(data)
+	if err != nil {
+		return k, false, err
+	}
+
+	if f.Crypto.Cipher != keyFileCipher || f.Crypto.KDF != keyFileKDF || f.Crypto.KDFParams.DKLen != keySize {
+		return k, false, errors.Wrapf(ErrInvalidKeyFile, "unsupported cipher %q or kdf %q", f.Crypto.Cipher, f.Crypto.KDF)
+	}
+
+	params := ScryptParams{N: f.Crypto.KDFParams.N, R: f.Crypto.KDFParams.R, P: f.Crypto.KDFParams.P}
+	if err := params.Validate(); err != nil {
+		return k, false, err
+	}
+
+	salt, err := hex.DecodeString(f.Crypto.KDFParams.Salt)
+	if err != nil {
+		return k, false, errors.Wrap(ErrInvalidKeyFile, "salt must be hex-encoded")
+	}
+
+	nonce, err := hex.DecodeString(f.Crypto.CipherParams.Nonce)
+	if err != nil || len(nonce) != nonceSize {
+		return k, false, errors.Wrapf(ErrInvalidKeyFile, "nonce must be %d hex characters", hex.EncodedLen(nonceSize))
+	}
+
+	ciphertext, err := hex.DecodeString(f.Crypto.CipherText)
+	if err != nil {
+		return k, false, errors.Wrap(ErrInvalidKeyFile, "ciphertext must be hex-encoded")
+	}
+
+	aead, err := newKeyFileAEAD(passphrase, salt, params)
+	if err != nil {
+		return k, false, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, address[:])
+	if err != nil {
+		return k, false, ErrDecrypt
+	}
+
+	hd := len(plaintext) == edwards25519.SizePrivateKey+ChainCodeSize
+
+	if len(plaintext) != edwards25519.SizePrivateKey && !hd {
+		return k, false, errors.Errorf("security: decrypted private key is %d bytes, expected %d", len(plaintext), edwards25519.SizePrivateKey)
+	}
+
+	copy(k.PrivateKey[:], plaintext)
+	copy(k.ChainCode[:], plaintext[edwards25519.SizePrivateKey:])
+
+	if k.PrivateKey.Public() != address {
+		return k, false, errors.Wrap(ErrInvalidKeyFile, "private key does not match address")
+	}
+
+	return k, hd, nil
+}
+
+// KeyFileAddress returns the address of the private key sealed in a key
+// file, without decrypting it.
+func KeyFileAddress(data []byte) (edwards25519.PublicKey, error) {
+	_, address, err := parseKeyFile(data)
+	return address, err
+}
+
+func parseKeyFile(data []byte) (keyFile, edwards25519.PublicKey, error) {
+	var (
+		f       keyFile
+		address edwards25519.PublicKey
+	)
+
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, address, errors.Wrap(ErrInvalidKeyFile, err.Error())
+	}
+
+	if f.Version != KeyFileVersion {
+		return f, address, errors.Wrapf(ErrUnsupportedVersion, "version %d", f.Version)
+	}
+
+	buf, err := hex.DecodeString(f.Address)
+	if err != nil || len(buf) != len(address) {
+		return f, address, errors.Wrapf(ErrInvalidKeyFile, "address must be %d hex characters", hex.EncodedLen(len(address)))
+	}
+
+	copy(address[:], buf)
+
+	return f, address, nil
+}
+
+func newKeyFileAEAD(passphrase, salt []byte, params ScryptParams) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, params.N, params.R, params.P, keySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive key")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package security
+
+import (
+	"fmt"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// testScryptParams are cheap scrypt parameters, such that tests run quickly.
+var testScryptParams = ScryptParams{N: 16, R: 8, P: 1}
+
+func TestEncryptKeyFile(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	data, err := EncryptKeyFile(key, []byte("passphrase"), testScryptParams)
+	assert.NoError(t, err)
+	assert.True(t, IsKeyFile(data))
+	assert.False(t, IsEncrypted(data))
+
+	var f map[string]interface{}
+	if assert.NoError(t, json.Unmarshal(data, &f)) {
+		assert.EqualValues(t, KeyFileVersion, f["version"])
+		assert.Equal(t, "scrypt", f["crypto"].(map[string]interface{})["kdf"])
+	}
+
+	address, err := KeyFileAddress(data)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Public(), address)
+
+	decrypted, err := DecryptKeyFile(data, []byte("passphrase"))
+	assert.NoError(t, err)
+	assert.Equal(t, key, decrypted)
+
+	_, err = DecryptKeyFile(data, []byte("wrong"))
+	assert.Equal(t, ErrDecrypt, err)
+
+	// The address is authenticated alongside the private key.
+	other := key.Public()
+	other[0] ^= 1
+
+	swapped := strings.Replace(string(data), hexString(key.Public()), hexString(other), 1)
+
+	_, err = DecryptKeyFile([]byte(swapped), []byte("passphrase"))
+	assert.Equal(t, ErrDecrypt, err)
+}
+
+func TestDecryptKeyFileInvalid(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	data, err := EncryptKeyFile(key, nil, testScryptParams)
+	assert.NoError(t, err)
+
+	_, err = DecryptKeyFile([]byte("not a key file"), nil)
+	assert.Equal(t, ErrInvalidKeyFile, errors.Cause(err))
+
+	future := strings.Replace(string(data), `"version": 1`, `"version": 2`, 1)
+
+	_, err = DecryptKeyFile([]byte(future), nil)
+	assert.Equal(t, ErrUnsupportedVersion, errors.Cause(err))
+
+	// Key files demanding excessive memory are rejected before deriving a key.
+	greedy := strings.Replace(string(data), `"n": 16`, `"n": 67108864`, 1)
+
+	_, err = DecryptKeyFile([]byte(greedy), nil)
+	assert.Equal(t, ErrInvalidScryptParams, errors.Cause(err))
+
+	// So are those within the bound of n, whose block size makes up for it.
+	hostile := strings.Replace(strings.Replace(string(data),
+		`"n": 16`, fmt.Sprintf(`"n": %d`, MaxScryptN), 1),
+		`"r": 8`, `"r": 1048576`, 1)
+
+	_, err = DecryptKeyFile([]byte(hostile), nil)
+	assert.Equal(t, ErrInvalidScryptParams, errors.Cause(err))
+
+	ctr := strings.Replace(string(data), `"aes-256-gcm"`, `"aes-128-ctr"`, 1)
+
+	_, err = DecryptKeyFile([]byte(ctr), nil)
+	assert.Equal(t, ErrInvalidKeyFile, errors.Cause(err))
+}
+
+func TestScryptParamsValidate(t *testing.T) {
+	assert.NoError(t, DefaultScryptParams.Validate())
+	assert.NoError(t, testScryptParams.Validate())
+
+	for _, params := range []ScryptParams{
+		{N: 1, R: 8, P: 1},
+		{N: 15, R: 8, P: 1},
+		{N: MaxScryptN * 2, R: 8, P: 1},
+		{N: 16, R: 0, P: 1},
+		{N: 16, R: 8, P: 0},
+		{N: 16, R: MaxScryptR + 1, P: 1},
+		{N: 16, R: 8, P: MaxScryptP + 1},
+		{N: MaxScryptN, R: 8, P: 1},
+	} {
+		assert.Equal(t, ErrInvalidScryptParams, errors.Cause(params.Validate()), params)
+
+		_, err := EncryptKeyFile(edwards25519.PrivateKey{}, nil, params)
+		assert.Equal(t, ErrInvalidScryptParams, errors.Cause(err), params)
+	}
+}
+
+func hexString(key edwards25519.PublicKey) string {
+	return hex.EncodeToString(key[:])
+}
+
+func TestEncryptHDKeyFile(t *testing.T) {
+	hd, err := NewMasterKey([]byte("seed"))
+	assert.NoError(t, err)
+
+	data, err := EncryptHDKeyFile(hd, []byte("passphrase"), testScryptParams)
+	assert.NoError(t, err)
+
+	address, err := KeyFileAddress(data)
+	assert.NoError(t, err)
+	assert.Equal(t, hd.PrivateKey.Public(), address)
+
+	decrypted, err := DecryptHDKeyFile(data, []byte("passphrase"))
+	assert.NoError(t, err)
+	assert.Equal(t, hd, decrypted)
+
+	// The private key of HD key files may be decrypted on its own.
+	key, err := DecryptKeyFile(data, []byte("passphrase"))
+	assert.NoError(t, err)
+	assert.Equal(t, hd.PrivateKey, key)
+
+	data, err = EncryptKeyFile(hd.PrivateKey, []byte("passphrase"), testScryptParams)
+	assert.NoError(t, err)
+
+	_, err = DecryptHDKeyFile(data, []byte("passphrase"))
+	assert.Equal(t, ErrNoChainCode, err)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Mnemonics encode entropy as a phrase of words from the English wordlist of
+// BIP-0039, with every word encoding 11 bits, and a checksum of the entropy
+// being appended to it. The entropy is stretched into a seed with
+// PBKDF2-HMAC-SHA512, from which the private key is derived as the master key
+// of SLIP-0010 for ed25519, as described in hd.go. Phrases generated by this package are 24 words
+// long, encoding 256 bits of entropy.
+
+const (
+	// MnemonicEntropySize is the size, in bytes, of the entropy of newly
+	// generated mnemonics.
+	MnemonicEntropySize = 32
+
+	// MnemonicSeedSize is the size, in bytes, of the seeds derived from
+	// mnemonics.
+	MnemonicSeedSize = 64
+
+	mnemonicSeedIterations = 2048
+	mnemonicSeedSalt       = "mnemonic"
+)
+
+// ErrInvalidMnemonic is returned for mnemonics that are not well-formed, or
+// whose checksum does not match.
+var ErrInvalidMnemonic = errors.New("security: invalid mnemonic")
+
+// wordIndices maps the words of the wordlist to their index.
+var wordIndices = func() map[string]int {
+	indices := make(map[string]int, len(wordlist))
+
+	for i, word := range wordlist {
+		indices[word] = i
+	}
+
+	return indices
+}()
+
+// NewMnemonic generates a 24-word mnemonic from 256 bits of entropy.
+func NewMnemonic() (string, error) {
+	entropy := make([]byte, MnemonicEntropySize)
+
+	if _, err := io.ReadFull(rand.Reader, entropy); err != nil {
+		return "", errors.Wrap(err, "security: failed to generate entropy")
+	}
+
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes entropy as a mnemonic. The entropy must be
+// between 128 and 256 bits, in multiples of 32 bits.
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", errors.Wrapf(ErrInvalidMnemonic, "entropy is %d bytes, expected 16 to 32 bytes in multiples of 4", len(entropy))
+	}
+
+	checksumBits := uint(len(entropy) / 4)
+	checksum := sha256.Sum256(entropy)
+
+	// Append the checksum to the entropy, and split it into 11-bit words from
+	// the least significant bits up.
+	bits := new(big.Int).SetBytes(entropy)
+	bits.Lsh(bits, checksumBits)
+	bits.Or(bits, big.NewInt(int64(checksum[0]>>(8-checksumBits))))
+
+	words := make([]string, (uint(len(entropy))*8+checksumBits)/11)
+	mask := big.NewInt(2047)
+
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = wordlist[new(big.Int).And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes the entropy a mnemonic encodes, verifying its
+// checksum.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, errors.Wrapf(ErrInvalidMnemonic, "mnemonic is %d words, expected 12 to 24 words in multiples of 3", len(words))
+	}
+
+	bits := new(big.Int)
+
+	for _, word := range words {
+		index, ok := wordIndices[strings.ToLower(word)]
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidMnemonic, "unknown word %q", word)
+		}
+
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(len(words) / 3)
+	checksum := byte(new(big.Int).And(bits, big.NewInt(1<<checksumBits-1)).Int64())
+
+	entropy := make([]byte, (uint(len(words))*11-checksumBits)/8)
+	b := bits.Rsh(bits, checksumBits).Bytes()
+	copy(entropy[len(entropy)-len(b):], b)
+
+	if expected := sha256.Sum256(entropy); expected[0]>>(8-checksumBits) != checksum {
+		return nil, errors.Wrap(ErrInvalidMnemonic, "checksum mismatch")
+	}
+
+	return entropy, nil
+}
+
+// ValidateMnemonic checks that a mnemonic is made up of words from the
+// wordlist, and that its checksum matches.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// MnemonicToSeed stretches a mnemonic into a seed, salted with an optional
+// passphrase. The mnemonic is not validated.
+func MnemonicToSeed(mnemonic, passphrase string) []byte {
+	password := norm.NFKD.String(strings.Join(strings.Fields(strings.ToLower(mnemonic)), " "))
+	salt := norm.NFKD.String(mnemonicSeedSalt + passphrase)
+
+	return pbkdf2.Key([]byte(password), []byte(salt), mnemonicSeedIterations, MnemonicSeedSize, sha512.New)
+}
+
+// KeyFromMnemonic deterministically derives a private key from a mnemonic
+// and an optional passphrase, being the master key of its seed. Different
+// passphrases yield different keys.
+func KeyFromMnemonic(mnemonic, passphrase string) (edwards25519.PrivateKey, error) {
+	k, err := HDKeyFromMnemonic(mnemonic, passphrase)
+	return k.PrivateKey, err
+}
+
+// KeyFromSeed derives the private key of the SLIP-0010 master key of seed.
+func KeyFromSeed(seed []byte) (edwards25519.PrivateKey, error) {
+	k, err := NewMasterKey(seed)
+	return k.PrivateKey, err
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package security
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// mnemonicVectors are test vectors of BIP-0039, seeded with the passphrase
+// "TREZOR".
+var mnemonicVectors = []struct {
+	entropy, mnemonic, seed string
+}{
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		entropy:  strings.Repeat("00", 32),
+		mnemonic: strings.Repeat("abandon ", 23) + "art",
+		seed:     "bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+	{
+		entropy:  "f585c11aec520db57dd353c69554b21a89b20fb0650966fa0a9d6f74fd989d8f",
+		mnemonic: "void come effort suffer camp survey warrior heavy shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold",
+		seed:     "01f5bced59dec48e362f2c45b5de68b9fd6c92c6634f44d6d40aab69056506f0e35524a518034ddc1192e1dacd32c1ed3eaa3c3b131c88ed8e7e54c49a5d0998",
+	},
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	for _, v := range mnemonicVectors {
+		entropy, err := hex.DecodeString(v.entropy)
+		assert.NoError(t, err)
+
+		mnemonic, err := EntropyToMnemonic(entropy)
+		assert.NoError(t, err)
+		assert.Equal(t, v.mnemonic, mnemonic)
+
+		decoded, err := MnemonicToEntropy(mnemonic)
+		assert.NoError(t, err)
+		assert.Equal(t, entropy, decoded)
+
+		assert.Equal(t, v.seed, hex.EncodeToString(MnemonicToSeed(mnemonic, "TREZOR")))
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	assert.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 24)
+	assert.NoError(t, ValidateMnemonic(mnemonic))
+
+	other, err := NewMnemonic()
+	assert.NoError(t, err)
+	assert.NotEqual(t, mnemonic, other)
+}
+
+func TestValidateMnemonicInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		strings.Repeat("abandon ", 11),
+		strings.Repeat("abandon ", 24),
+		strings.Repeat("abandon ", 23) + "notaword",
+		"legal winner thank year wave sausage worth useful legal winner thank",
+	}
+
+	for _, mnemonic := range invalid {
+		assert.Equal(t, ErrInvalidMnemonic, errors.Cause(ValidateMnemonic(mnemonic)), mnemonic)
+	}
+
+	// Mnemonics are accepted regardless of case and surrounding whitespace.
+	assert.NoError(t, ValidateMnemonic("  Legal winner thank year wave sausage\nworth useful legal winner thank YELLOW "))
+}
+
+func TestKeyFromMnemonic(t *testing.T) {
+	mnemonic := mnemonicVectors[2].mnemonic
+
+	key, err := KeyFromMnemonic(mnemonic, "")
+	assert.NoError(t, err)
+
+	again, err := KeyFromMnemonic(strings.ToUpper(mnemonic), "")
+	assert.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	other, err := KeyFromMnemonic(mnemonic, "passphrase")
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	_, err = KeyFromMnemonic(strings.Repeat("abandon ", 24), "")
+	assert.Equal(t, ErrInvalidMnemonic, errors.Cause(err))
+}
+
+func TestKeyFromSeed(t *testing.T) {
+	// Test vector 1 of SLIP-0010 for ed25519.
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	assert.NoError(t, err)
+
+	key, err := KeyFromSeed(seed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7", hex.EncodeToString(key[:32]))
+	assert.Equal(t, "a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed", hex.EncodeToString(key[32:]))
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package security implements passphrase-based encryption of secrets such as
+// private keys, and the signature schemes transactions may be signed with.
+//
+// Encrypted secrets are prefixed with a versioned header describing how
+// their key was derived, such that the key derivation parameters may be tuned
+// over time without breaking older secrets. Version 1 derives a 256-bit key
+// from a passphrase with argon2id, and seals the secret with AES-256-GCM. The
+// header is authenticated alongside the secret.
+//
+//	magic (4) | version (1) | time (4) | memory (4) | threads (1) | salt (16) | nonce (12) | ciphertext
+package security
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// Version is the version of the header of newly encrypted secrets.
+	Version byte = 1
+
+	// MaxTime and MaxMemory bound the number of passes and the memory in
+	// KiB argon2id may be configured with, such that a crafted header may
+	// not exhaust the host when decrypting it.
+	MaxTime   = 64
+	MaxMemory = 4 * 1024 * 1024
+
+	keySize   = 32
+	saltSize  = 16
+	nonceSize = 12
+
+	headerSize = len(magic) + 1 + 4 + 4 + 1 + saltSize + nonceSize
+)
+
+var magic = [4]byte{'W', 'K', 'E', 'Y'}
+
+var (
+	// ErrInvalidHeader is returned when decrypting data that was not
+	// encrypted by this package.
+	ErrInvalidHeader = errors.New("security: invalid header")
+
+	// ErrUnsupportedVersion is returned when decrypting data whose header
+	// version is unknown.
+	ErrUnsupportedVersion = errors.New("security: unsupported version")
+
+	// ErrInvalidParams is returned for argon2id parameters that are out of
+	// bounds.
+	ErrInvalidParams = errors.New("security: invalid argon2id parameters")
+
+	// ErrDecrypt is returned when the passphrase is wrong, or the encrypted
+	// data was tampered with.
+	ErrDecrypt = errors.New("security: wrong passphrase or corrupted data")
+)
+
+// Params are the argon2id parameters keys are derived with.
+type Params struct {
+	// Time is the number of passes over the memory.
+	Time uint32
+
+	// Memory is the size of the memory in KiB.
+	Memory uint32
+
+	// Threads is the number of threads used.
+	Threads uint8
+}
+
+// DefaultParams are the argon2id parameters recommended by RFC 9106 for
+// memory-constrained environments.
+var DefaultParams = Params{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// Validate checks that the parameters are within bounds.
+func (p Params) Validate() error {
+	if p.Time == 0 || p.Threads == 0 {
+		return errors.Wrap(ErrInvalidParams, "time and threads must be greater than zero")
+	}
+
+	if p.Time > MaxTime {
+		return errors.Wrapf(ErrInvalidParams, "time must be at most %d", MaxTime)
+	}
+
+	if p.Memory < 8*uint32(p.Threads) {
+		return errors.Wrapf(ErrInvalidParams, "memory must be at least %d KiB for %d thread(s)", 8*uint32(p.Threads), p.Threads)
+	}
+
+	if p.Memory > MaxMemory {
+		return errors.Wrapf(ErrInvalidParams, "memory must be at most %d KiB", MaxMemory)
+	}
+
+	return nil
+}
+
+// IsEncrypted reports whether data starts with the header of a secret
+// encrypted by this package.
+func IsEncrypted(data []byte) bool {
+	return len(data) >= len(magic) && bytes.Equal(data[:len(magic)], magic[:])
+}
+
+// Encrypt seals plaintext with a key derived from passphrase with params.
+func Encrypt(plaintext, passphrase []byte, params Params) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, headerSize)
+
+	copy(header, magic[:])
+	header[4] = Version
+	binary.BigEndian.PutUint32(header[5:9], params.Time)
+	binary.BigEndian.PutUint32(header[9:13], params.Memory)
+	header[13] = params.Threads
+
+	// Fill in the salt and nonce.
+	if _, err := io.ReadFull(rand.Reader, header[14:]); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt and nonce")
+	}
+
+	aead, err := newAEAD(passphrase, header[14:14+saltSize], params)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(header, header[14+saltSize:], plaintext, header), nil
+}
+
+// Decrypt opens data sealed by Encrypt with the same passphrase.
+func Decrypt(data, passphrase []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, ErrInvalidHeader
+	}
+
+	if len(data) < 5 {
+		return nil, ErrInvalidHeader
+	}
+
+	if data[4] != Version {
+		return nil, errors.Wrapf(ErrUnsupportedVersion, "version %d", data[4])
+	}
+
+	if len(data) < headerSize {
+		return nil, ErrInvalidHeader
+	}
+
+	header := data[:headerSize]
+
+	params := Params{
+		Time:    binary.BigEndian.Uint32(header[5:9]),
+		Memory:  binary.BigEndian.Uint32(header[9:13]),
+		Threads: header[13],
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, header[14:14+saltSize], params)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, header[14+saltSize:], data[headerSize:], header)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return plaintext, nil
+}
+
+// EncryptKey seals a private key with a key derived from passphrase.
+func EncryptKey(key edwards25519.PrivateKey, passphrase []byte, params Params) ([]byte, error) {
+	return Encrypt(key[:], passphrase, params)
+}
+
+// DecryptKey opens a private key sealed by EncryptKey.
+func DecryptKey(data, passphrase []byte) (edwards25519.PrivateKey, error) {
+	var key edwards25519.PrivateKey
+
+	plaintext, err := Decrypt(data, passphrase)
+	if err != nil {
+		return key, err
+	}
+
+	if len(plaintext) != edwards25519.SizePrivateKey {
+		return key, errors.Errorf("security: decrypted private key is %d bytes, expected %d", len(plaintext), edwards25519.SizePrivateKey)
+	}
+
+	copy(key[:], plaintext)
+
+	return key, nil
+}
+
+func newAEAD(passphrase, salt []byte, params Params) (cipher.AEAD, error) {
+	key := argon2.IDKey(passphrase, salt, params.Time, params.Memory, params.Threads, keySize)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package security
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// testParams are cheap argon2id parameters, such that tests run quickly.
+var testParams = Params{Time: 1, Memory: 64, Threads: 1}
+
+func TestEncryptDecrypt(t *testing.T) {
+	secret := []byte("the quick brown fox")
+
+	data, err := Encrypt(secret, []byte("passphrase"), testParams)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(data))
+	assert.Len(t, data, headerSize+len(secret)+16)
+
+	// Salts and nonces are random.
+	again, err := Encrypt(secret, []byte("passphrase"), testParams)
+	assert.NoError(t, err)
+	assert.NotEqual(t, data, again)
+
+	plaintext, err := Decrypt(data, []byte("passphrase"))
+	assert.NoError(t, err)
+	assert.Equal(t, secret, plaintext)
+
+	_, err = Decrypt(data, []byte("wrong"))
+	assert.Equal(t, ErrDecrypt, err)
+
+	// Tampering with either the header or the ciphertext is detected.
+	for _, i := range []int{5, 14, headerSize - 1, len(data) - 1} {
+		tampered := append([]byte(nil), data...)
+		tampered[i] ^= 1
+
+		_, err = Decrypt(tampered, []byte("passphrase"))
+		assert.Error(t, err, i)
+	}
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	data, err := Encrypt([]byte("secret"), nil, testParams)
+	assert.NoError(t, err)
+
+	_, err = Decrypt([]byte("not encrypted"), nil)
+	assert.Equal(t, ErrInvalidHeader, err)
+
+	_, err = Decrypt(data[:headerSize-1], nil)
+	assert.Equal(t, ErrInvalidHeader, err)
+
+	future := append([]byte(nil), data...)
+	future[4] = Version + 1
+
+	_, err = Decrypt(future, nil)
+	assert.Equal(t, ErrUnsupportedVersion, errors.Cause(err))
+
+	// Headers demanding excessive memory are rejected before deriving a key.
+	greedy := append([]byte(nil), data...)
+	greedy[9] = 0xff
+
+	_, err = Decrypt(greedy, nil)
+	assert.Equal(t, ErrInvalidParams, errors.Cause(err))
+}
+
+func TestParamsValidate(t *testing.T) {
+	assert.NoError(t, DefaultParams.Validate())
+	assert.NoError(t, testParams.Validate())
+
+	for _, params := range []Params{
+		{Time: 0, Memory: 64, Threads: 1},
+		{Time: 1, Memory: 64, Threads: 0},
+		{Time: 1, Memory: 7, Threads: 1},
+		{Time: 1, Memory: 16, Threads: 4},
+		{Time: MaxTime + 1, Memory: 64, Threads: 1},
+		{Time: 1, Memory: MaxMemory + 1, Threads: 1},
+	} {
+		assert.Equal(t, ErrInvalidParams, errors.Cause(params.Validate()), params)
+
+		_, err := Encrypt(nil, nil, params)
+		assert.Equal(t, ErrInvalidParams, errors.Cause(err), params)
+	}
+}
+
+func TestEncryptKey(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	data, err := EncryptKey(key, []byte("passphrase"), testParams)
+	assert.NoError(t, err)
+
+	decrypted, err := DecryptKey(data, []byte("passphrase"))
+	assert.NoError(t, err)
+	assert.Equal(t, key, decrypted)
+
+	// Secrets other than private keys are not mistaken for them.
+	data, err = Encrypt([]byte("short"), []byte("passphrase"), testParams)
+	assert.NoError(t, err)
+
+	_, err = DecryptKey(data, []byte("passphrase"))
+	assert.Error(t, err)
+}
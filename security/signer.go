@@ -0,0 +1,198 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package security
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+)
+
+// Scheme identifies a signature scheme.
+//
+// Public keys of every scheme must fit within the 32-byte account IDs, and
+// signatures within the 64 bytes reserved for them in transactions. BIP-340
+// Schnorr signatures over secp256k1 would, for instance, fit these sizes.
+type Scheme byte
+
+const (
+	// SchemeEd25519 is the default signature scheme, being Ed25519.
+	SchemeEd25519 Scheme = 0
+)
+
+func (s Scheme) String() string {
+	if s == SchemeEd25519 {
+		return "ed25519"
+	}
+
+	return fmt.Sprintf("scheme(%d)", byte(s))
+}
+
+var (
+	// ErrUnknownScheme is returned when verifying a signature of a scheme
+	// whose Verifier was not registered.
+	ErrUnknownScheme = errors.New("security: unknown signature scheme")
+
+	// ErrInvalidSignature is returned by Verify for invalid signatures.
+	ErrInvalidSignature = errors.New("security: invalid signature")
+)
+
+// Signer signs messages on behalf of a single public key.
+type Signer interface {
+	Scheme() Scheme
+	PublicKey() []byte
+	Sign(message []byte) ([]byte, error)
+}
+
+// Verifier verifies the signatures of a scheme.
+//
+// Verifiers must only accept the canonical encoding of a signature, such
+// that no valid signature may be malleated into another valid signature of
+// the same message. Transactions would otherwise be re-encodable under new
+// IDs by anyone relaying them.
+type Verifier interface {
+	Scheme() Scheme
+	Verify(publicKey, message, signature []byte) bool
+}
+
+var (
+	mu        sync.RWMutex
+	verifiers = map[Scheme]Verifier{
+		SchemeEd25519: ed25519Verifier{},
+	}
+)
+
+// RegisterVerifier makes the scheme of v available to Verify. It panics if a
+// verifier of the same scheme was already registered.
+func RegisterVerifier(v Verifier) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, dup := verifiers[v.Scheme()]; dup {
+		panic(fmt.Sprintf("security: verifier of scheme %d registered twice", v.Scheme()))
+	}
+
+	verifiers[v.Scheme()] = v
+}
+
+// LookupVerifier returns the registered verifier of scheme.
+func LookupVerifier(scheme Scheme) (Verifier, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	v, ok := verifiers[scheme]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownScheme, "scheme %d", scheme)
+	}
+
+	return v, nil
+}
+
+// Verify verifies the signature of message by publicKey with the verifier
+// registered for scheme.
+func Verify(scheme Scheme, publicKey, message, signature []byte) error {
+	v, err := LookupVerifier(scheme)
+	if err != nil {
+		return err
+	}
+
+	if !v.Verify(publicKey, message, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+var _ Signer = (*Ed25519Signer)(nil)
+
+// Ed25519Signer signs messages with an Ed25519 private key.
+type Ed25519Signer struct {
+	key edwards25519.PrivateKey
+}
+
+// NewEd25519Signer creates a Signer of an Ed25519 private key.
+func NewEd25519Signer(key edwards25519.PrivateKey) *Ed25519Signer {
+	return &Ed25519Signer{key: key}
+}
+
+func (s *Ed25519Signer) Scheme() Scheme {
+	return SchemeEd25519
+}
+
+func (s *Ed25519Signer) PublicKey() []byte {
+	pub := s.key.Public()
+	return pub[:]
+}
+
+func (s *Ed25519Signer) Sign(message []byte) ([]byte, error) {
+	sig := edwards25519.Sign(s.key, message)
+	return sig[:], nil
+}
+
+type ed25519Verifier struct{}
+
+func (ed25519Verifier) Scheme() Scheme {
+	return SchemeEd25519
+}
+
+func (ed25519Verifier) Verify(publicKey, message, signature []byte) bool {
+	if len(publicKey) != edwards25519.SizePublicKey || len(signature) != edwards25519.SizeSignature {
+		return false
+	}
+
+	var (
+		pub edwards25519.PublicKey
+		sig edwards25519.Signature
+	)
+
+	// Only accept the scalar S of a signature in its reduced form, as S+l
+	// would otherwise verify all the same.
+	if !isReducedScalar(signature[edwards25519.SizeSignature/2:]) {
+		return false
+	}
+
+	copy(pub[:], publicKey)
+	copy(sig[:], signature)
+
+	return edwards25519.Verify(pub, message, sig)
+}
+
+// order is the order l of the Ed25519 base point, in little-endian.
+var order = [32]byte{
+	0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58, 0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+}
+
+// isReducedScalar returns whether the 32-byte little-endian scalar s is less
+// than l.
+func isReducedScalar(s []byte) bool {
+	for i := len(order) - 1; i >= 0; i-- {
+		switch {
+		case s[i] < order[i]:
+			return true
+		case s[i] > order[i]:
+			return false
+		}
+	}
+
+	return false
+}
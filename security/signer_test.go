@@ -0,0 +1,119 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package security
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEd25519(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	signer := NewEd25519Signer(key)
+	assert.Equal(t, SchemeEd25519, signer.Scheme())
+
+	pub := key.Public()
+	assert.Equal(t, pub[:], signer.PublicKey())
+
+	sig, err := signer.Sign([]byte("message"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, Verify(SchemeEd25519, signer.PublicKey(), []byte("message"), sig))
+	assert.Equal(t, ErrInvalidSignature, Verify(SchemeEd25519, signer.PublicKey(), []byte("other"), sig))
+	assert.Equal(t, ErrInvalidSignature, Verify(SchemeEd25519, signer.PublicKey()[:31], []byte("message"), sig))
+	assert.Equal(t, ErrInvalidSignature, Verify(SchemeEd25519, signer.PublicKey(), []byte("message"), sig[:63]))
+}
+
+// malleate returns the signature sig with l added to its scalar S, which
+// is equally valid to a verifier not checking S to be reduced.
+func malleate(sig []byte) []byte {
+	out := append([]byte(nil), sig...)
+
+	var carry uint16
+
+	for i := range order {
+		sum := uint16(out[32+i]) + uint16(order[i]) + carry
+		out[32+i], carry = byte(sum), sum>>8
+	}
+
+	return out
+}
+
+func TestEd25519Malleability(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	signer := NewEd25519Signer(key)
+
+	for i := 0; i < 16; i++ {
+		message := []byte{byte(i)}
+
+		sig, err := signer.Sign(message)
+		assert.NoError(t, err)
+
+		malleated := malleate(sig)
+
+		var raw edwards25519.Signature
+		copy(raw[:], malleated)
+
+		// Should S+l fit in 253 bits, the underlying implementation alone
+		// accepts the malleated signature.
+		if malleated[63]&0xe0 == 0 {
+			assert.True(t, edwards25519.Verify(key.Public(), message, raw))
+		}
+
+		assert.NoError(t, Verify(SchemeEd25519, signer.PublicKey(), message, sig))
+		assert.Equal(t, ErrInvalidSignature, Verify(SchemeEd25519, signer.PublicKey(), message, malleated))
+	}
+}
+
+type rejectingVerifier struct{ scheme Scheme }
+
+func (v rejectingVerifier) Scheme() Scheme { return v.scheme }
+
+func (rejectingVerifier) Verify(publicKey, message, signature []byte) bool { return false }
+
+func TestRegisterVerifier(t *testing.T) {
+	const scheme Scheme = 0xf0
+
+	_, err := LookupVerifier(scheme)
+	assert.Equal(t, ErrUnknownScheme, errors.Cause(err))
+	assert.Equal(t, ErrUnknownScheme, errors.Cause(Verify(scheme, nil, nil, nil)))
+
+	RegisterVerifier(rejectingVerifier{scheme: scheme})
+
+	v, err := LookupVerifier(scheme)
+	assert.NoError(t, err)
+	assert.Equal(t, scheme, v.Scheme())
+	assert.Equal(t, ErrInvalidSignature, Verify(scheme, nil, nil, nil))
+
+	assert.Panics(t, func() { RegisterVerifier(rejectingVerifier{scheme: scheme}) })
+	assert.Panics(t, func() { RegisterVerifier(rejectingVerifier{scheme: SchemeEd25519}) })
+
+	assert.Equal(t, "ed25519", SchemeEd25519.String())
+	assert.Equal(t, "scheme(240)", scheme.String())
+}
@@ -0,0 +1,587 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package threshold implements t-of-n threshold signing of Ed25519 keys
+// through the two-round FROST protocol, following the FROST(Ed25519,
+// SHA-512) ciphersuite of RFC 9591.
+//
+// A key is split by a trusted dealer into n shares, any t of which may
+// jointly sign a message. Signatures aggregated by a Coordinator are plain
+// Ed25519 signatures under the key that was split, such that a validator may
+// keep signing transactions without any single host holding its key. The
+// key should be destroyed by the dealer once its shares were distributed.
+//
+// Participants are served to coordinators over HTTP by NewHandler, and
+// reached through Remote. A validator's node keeps its own key, which it
+// handshakes with peers and contributes to the beacon with, while the
+// account holding its stake and delegations is split, and has its
+// transactions signed through `wctl threshold`.
+package threshold
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/internal/curve"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidThreshold is returned when splitting a key into fewer shares
+	// than the threshold, or with a threshold below 1.
+	ErrInvalidThreshold = errors.New("threshold: invalid threshold")
+
+	// ErrTooFewSigners is returned when fewer participants than the
+	// threshold partake in a signing session.
+	ErrTooFewSigners = errors.New("threshold: too few signers")
+
+	// ErrUnknownCommitment is returned by a participant asked to sign with a
+	// commitment it did not make, or whose nonces were already used.
+	ErrUnknownCommitment = errors.New("threshold: unknown or used commitment")
+
+	// ErrInvalidShare is returned when a participant's signature share does
+	// not verify against its public key share.
+	ErrInvalidShare = errors.New("threshold: invalid signature share")
+)
+
+// contextString domain-separates the hashes of the FROST(Ed25519, SHA-512)
+// ciphersuite.
+const contextString = "FROST-ED25519-SHA512-v1"
+
+const (
+	// SizeKeyShare is the size of a marshaled KeyShare.
+	SizeKeyShare = 2 + 2 + 32 + edwards25519.SizePublicKey
+)
+
+// KeyShare is the share of a split key held by a single participant.
+type KeyShare struct {
+	// ID identifies the participant, starting from 1.
+	ID        uint16
+	Threshold uint16
+
+	// Secret is the participant's share of the secret scalar of the key.
+	Secret [32]byte
+
+	// GroupKey is the public key of the key that was split.
+	GroupKey edwards25519.PublicKey
+}
+
+// PublicKey returns the public key share of s.
+func (s KeyShare) PublicKey() [32]byte {
+	return curve.EncodePoint(curve.ScalarMultBase(s.Secret))
+}
+
+// Marshal encodes s into SizeKeyShare bytes, which are best encrypted with
+// security.Encrypt before being handed to a participant.
+func (s KeyShare) Marshal() []byte {
+	buf := make([]byte, SizeKeyShare)
+
+	binary.BigEndian.PutUint16(buf[0:2], s.ID)
+	binary.BigEndian.PutUint16(buf[2:4], s.Threshold)
+	copy(buf[4:36], s.Secret[:])
+	copy(buf[36:], s.GroupKey[:])
+
+	return buf
+}
+
+// UnmarshalKeyShare decodes a key share encoded by KeyShare.Marshal.
+func UnmarshalKeyShare(buf []byte) (KeyShare, error) {
+	var s KeyShare
+
+	if len(buf) != SizeKeyShare {
+		return s, errors.Errorf("key share must be %d bytes, but got %d bytes", SizeKeyShare, len(buf))
+	}
+
+	s.ID = binary.BigEndian.Uint16(buf[0:2])
+	s.Threshold = binary.BigEndian.Uint16(buf[2:4])
+	copy(s.Secret[:], buf[4:36])
+	copy(s.GroupKey[:], buf[36:])
+
+	if s.ID == 0 || s.Threshold == 0 || !curve.ScalarIsCanonical(s.Secret) {
+		return s, errors.New("malformed key share")
+	}
+
+	return s, nil
+}
+
+// Group describes a split key to the coordinator of signing sessions.
+type Group struct {
+	Threshold uint16
+	PublicKey edwards25519.PublicKey
+
+	// Shares are the public key shares of all participants by their ID.
+	Shares map[uint16][32]byte
+}
+
+// Verify checks that share belongs to the group, guarding participants
+// against a dealer handing out an inconsistent share.
+func (g Group) Verify(share KeyShare) error {
+	public, ok := g.Shares[share.ID]
+
+	if !ok || share.Threshold != g.Threshold || share.GroupKey != g.PublicKey || share.PublicKey() != public {
+		return errors.Errorf("key share of participant %d does not belong to the group", share.ID)
+	}
+
+	return nil
+}
+
+// Split splits the secret scalar of key into n shares, any threshold of
+// which may sign on behalf of key. Randomness is read from rand, or from
+// crypto/rand should it be nil.
+func Split(key edwards25519.PrivateKey, threshold, n int, rand io.Reader) ([]KeyShare, Group, error) {
+	if threshold < 1 || n < threshold || n > 1<<16-1 {
+		return nil, Group{}, errors.Wrapf(ErrInvalidThreshold, "%d-of-%d", threshold, n)
+	}
+
+	rand = randReader(rand)
+
+	public := key.Public()
+
+	// The secret is the constant term of a random polynomial of degree
+	// threshold-1, whose evaluations at each participant ID are the shares.
+	coefficients := make([][32]byte, threshold)
+	coefficients[0] = curve.ExpandSecret(key)
+
+	if curve.EncodePoint(curve.ScalarMultBase(coefficients[0])) != public {
+		return nil, Group{}, errors.New("threshold: private key does not match its public key")
+	}
+
+	for i := 1; i < threshold; i++ {
+		c, err := curve.RandomScalar(rand)
+		if err != nil {
+			return nil, Group{}, err
+		}
+
+		coefficients[i] = c
+	}
+
+	shares := make([]KeyShare, n)
+	group := Group{
+		Threshold: uint16(threshold),
+		PublicKey: public,
+		Shares:    make(map[uint16][32]byte, n),
+	}
+
+	for i := range shares {
+		id := uint16(i + 1)
+		x := curve.ScalarFromUint(uint64(id))
+
+		// Evaluate the polynomial at x with Horner's method.
+		y := coefficients[threshold-1]
+		for j := threshold - 2; j >= 0; j-- {
+			y = curve.ScalarMulAdd(y, x, coefficients[j])
+		}
+
+		shares[i] = KeyShare{ID: id, Threshold: uint16(threshold), Secret: y, GroupKey: public}
+		group.Shares[id] = shares[i].PublicKey()
+	}
+
+	return shares, group, nil
+}
+
+// Commitment is a participant's commitment to the pair of single-use nonces
+// it signs with in the second round of a signing session.
+type Commitment struct {
+	ID      uint16
+	Hiding  [32]byte
+	Binding [32]byte
+}
+
+// SignatureShare is a participant's share of a signature.
+type SignatureShare struct {
+	ID uint16
+	Z  [32]byte
+}
+
+type nonces struct {
+	hiding, binding [32]byte
+}
+
+// Participant holds a key share, and signs on its behalf in signing sessions.
+//
+// A Participant is safe for concurrent use.
+type Participant struct {
+	share KeyShare
+	rand  io.Reader
+
+	mu     sync.Mutex
+	nonces map[Commitment]nonces
+}
+
+// NewParticipant creates a participant holding share.
+func NewParticipant(share KeyShare) *Participant {
+	return &Participant{
+		share:  share,
+		rand:   randReader(nil),
+		nonces: make(map[Commitment]nonces),
+	}
+}
+
+func (p *Participant) ID() uint16 {
+	return p.share.ID
+}
+
+// Commit generates a pair of nonces for the first round of a signing session,
+// and returns the commitment to them. The nonces are kept until they are used
+// in Sign.
+func (p *Participant) Commit() (Commitment, error) {
+	hiding, err := p.nonce()
+	if err != nil {
+		return Commitment{}, err
+	}
+
+	binding, err := p.nonce()
+	if err != nil {
+		return Commitment{}, err
+	}
+
+	commitment := Commitment{
+		ID:      p.share.ID,
+		Hiding:  curve.EncodePoint(curve.ScalarMultBase(hiding)),
+		Binding: curve.EncodePoint(curve.ScalarMultBase(binding)),
+	}
+
+	p.mu.Lock()
+	p.nonces[commitment] = nonces{hiding: hiding, binding: binding}
+	p.mu.Unlock()
+
+	return commitment, nil
+}
+
+// Sign returns the participant's share of the signature of message, given
+// the commitments of all signers of the session. The nonces committed to are
+// discarded, such that they may never be used twice.
+func (p *Participant) Sign(message []byte, commitments []Commitment) (SignatureShare, error) {
+	var own *Commitment
+
+	for i := range commitments {
+		if commitments[i].ID == p.share.ID {
+			own = &commitments[i]
+			break
+		}
+	}
+
+	if own == nil {
+		return SignatureShare{}, errors.Errorf("threshold: participant %d is not a signer", p.share.ID)
+	}
+
+	p.mu.Lock()
+	n, ok := p.nonces[*own]
+	delete(p.nonces, *own)
+	p.mu.Unlock()
+
+	if !ok {
+		return SignatureShare{}, ErrUnknownCommitment
+	}
+
+	s, err := newSession(p.share.GroupKey, p.share.Threshold, message, commitments)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	// z = hiding + binding*rho + lambda*c*secret
+	z := curve.ScalarMulAdd(n.binding, s.factors[p.share.ID], n.hiding)
+	z = curve.ScalarMulAdd(curve.ScalarMul(s.lagrange(p.share.ID), s.challenge), p.share.Secret, z)
+
+	return SignatureShare{ID: p.share.ID, Z: z}, nil
+}
+
+func (p *Participant) nonce() ([32]byte, error) {
+	var random [32]byte
+	if _, err := io.ReadFull(p.rand, random[:]); err != nil {
+		return curve.ScalarZero, errors.Wrap(err, "failed to read randomness")
+	}
+
+	return curve.HashToScalar([]byte(contextString), []byte("nonce"), random[:], p.share.Secret[:]), nil
+}
+
+// session holds the values shared by all signers of a message.
+type session struct {
+	commitments []Commitment
+	factors     map[uint16][32]byte
+
+	// r is the encoded group commitment, and the first half of the
+	// signature.
+	r         [32]byte
+	challenge [32]byte
+}
+
+func newSession(groupKey edwards25519.PublicKey, threshold uint16, message []byte, commitments []Commitment) (*session, error) {
+	if len(commitments) < int(threshold) {
+		return nil, errors.Wrapf(ErrTooFewSigners, "got %d of %d", len(commitments), threshold)
+	}
+
+	commitments = append([]Commitment(nil), commitments...)
+	sort.Slice(commitments, func(i, j int) bool { return commitments[i].ID < commitments[j].ID })
+
+	encoded := make([]byte, 0, len(commitments)*(32+32+32))
+
+	for i, c := range commitments {
+		if c.ID == 0 || (i > 0 && commitments[i-1].ID == c.ID) {
+			return nil, errors.Errorf("threshold: invalid or duplicate signer %d", c.ID)
+		}
+
+		id := curve.ScalarFromUint(uint64(c.ID))
+
+		encoded = append(encoded, id[:]...)
+		encoded = append(encoded, c.Hiding[:]...)
+		encoded = append(encoded, c.Binding[:]...)
+	}
+
+	messageDigest := curve.Hash([]byte(contextString), []byte("msg"), message)
+	commitmentsDigest := curve.Hash([]byte(contextString), []byte("com"), encoded)
+
+	s := &session{
+		commitments: commitments,
+		factors:     make(map[uint16][32]byte, len(commitments)),
+	}
+
+	r := curve.Identity()
+
+	for _, c := range commitments {
+		id := curve.ScalarFromUint(uint64(c.ID))
+
+		factor := curve.HashToScalar(
+			[]byte(contextString), []byte("rho"),
+			groupKey[:], messageDigest[:], commitmentsDigest[:], id[:],
+		)
+
+		hiding, err := decodeCommitment(c.Hiding)
+		if err != nil {
+			return nil, errors.Wrapf(err, "threshold: bad hiding commitment of signer %d", c.ID)
+		}
+
+		binding, err := decodeCommitment(c.Binding)
+		if err != nil {
+			return nil, errors.Wrapf(err, "threshold: bad binding commitment of signer %d", c.ID)
+		}
+
+		s.factors[c.ID] = factor
+		r = curve.AddPoints(r, curve.AddPoints(hiding, curve.ScalarMult(factor, binding)))
+	}
+
+	s.r = curve.EncodePoint(r)
+	s.challenge = curve.HashToScalar(s.r[:], groupKey[:], message)
+
+	return s, nil
+}
+
+// decodeCommitment decodes a nonce commitment, which RFC 9591 requires be of
+// prime order. A signer committing to the identity or to a point of small
+// order would otherwise cancel out, or leak, the nonces of other signers.
+func decodeCommitment(b [32]byte) (*curve.Point, error) {
+	p, err := curve.DecodePoint(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if !curve.InPrimeOrderSubgroup(p) {
+		return nil, errors.New("commitment is not of prime order")
+	}
+
+	return p, nil
+}
+
+// lagrange returns the Lagrange coefficient of signer id at zero over the IDs
+// of all signers of the session.
+func (s *session) lagrange(id uint16) [32]byte {
+	num, den := curve.ScalarToBig(curve.ScalarOne), curve.ScalarToBig(curve.ScalarOne)
+	x := curve.ScalarToBig(curve.ScalarFromUint(uint64(id)))
+
+	for _, c := range s.commitments {
+		if c.ID == id {
+			continue
+		}
+
+		xj := curve.ScalarToBig(curve.ScalarFromUint(uint64(c.ID)))
+
+		num.Mul(num, xj).Mod(num, curve.Order)
+		den.Mul(den, xj.Sub(xj, x)).Mod(den, curve.Order)
+	}
+
+	return curve.ScalarFromBig(num.Mul(num, den.ModInverse(den, curve.Order)))
+}
+
+// Signatory is a participant as seen by a coordinator, which may reside on
+// another host. *Participant implements Signatory.
+type Signatory interface {
+	ID() uint16
+	Commit() (Commitment, error)
+	Sign(message []byte, commitments []Commitment) (SignatureShare, error)
+}
+
+var _ Signatory = (*Participant)(nil)
+
+var _ security.Signer = (*Coordinator)(nil)
+
+// Coordinator runs signing sessions amongst the signatories of a group, and
+// aggregates their shares into Ed25519 signatures. It implements
+// security.Signer, and may hence sign transactions on behalf of the group.
+type Coordinator struct {
+	group       Group
+	signatories []Signatory
+}
+
+// NewCoordinator creates a coordinator of the signatories of group. At least
+// group.Threshold signatories must be given, though giving more allows
+// sessions to proceed should some be unavailable.
+func NewCoordinator(group Group, signatories ...Signatory) (*Coordinator, error) {
+	if len(signatories) < int(group.Threshold) {
+		return nil, errors.Wrapf(ErrTooFewSigners, "got %d of %d", len(signatories), group.Threshold)
+	}
+
+	for _, s := range signatories {
+		if _, ok := group.Shares[s.ID()]; !ok {
+			return nil, errors.Errorf("threshold: signatory %d is not part of the group", s.ID())
+		}
+	}
+
+	return &Coordinator{group: group, signatories: signatories}, nil
+}
+
+func (c *Coordinator) Scheme() security.Scheme {
+	return security.SchemeEd25519
+}
+
+func (c *Coordinator) PublicKey() []byte {
+	return c.group.PublicKey[:]
+}
+
+// Sign collects commitments from the first signatories to respond until the
+// threshold is met, asks them for their signature shares, and aggregates the
+// shares into a signature of message.
+func (c *Coordinator) Sign(message []byte) ([]byte, error) {
+	signers := make([]Signatory, 0, c.group.Threshold)
+	commitments := make([]Commitment, 0, c.group.Threshold)
+
+	for _, s := range c.signatories {
+		if len(commitments) == int(c.group.Threshold) {
+			break
+		}
+
+		commitment, err := s.Commit()
+		if err != nil || commitment.ID != s.ID() {
+			continue
+		}
+
+		signers = append(signers, s)
+		commitments = append(commitments, commitment)
+	}
+
+	if len(commitments) < int(c.group.Threshold) {
+		return nil, errors.Wrapf(ErrTooFewSigners, "only %d of %d signatories committed", len(commitments), c.group.Threshold)
+	}
+
+	shares := make([]SignatureShare, 0, len(signers))
+
+	for _, s := range signers {
+		share, err := s.Sign(message, commitments)
+		if err != nil {
+			return nil, errors.Wrapf(err, "signatory %d failed to sign", s.ID())
+		}
+
+		shares = append(shares, share)
+	}
+
+	signature, err := c.Aggregate(message, commitments, shares)
+	if err != nil {
+		return nil, err
+	}
+
+	return signature[:], nil
+}
+
+// Aggregate verifies the signature shares of all signers of a session, and
+// combines them into a signature of message.
+func (c *Coordinator) Aggregate(message []byte, commitments []Commitment, shares []SignatureShare) (edwards25519.Signature, error) {
+	var signature edwards25519.Signature
+
+	s, err := newSession(c.group.PublicKey, c.group.Threshold, message, commitments)
+	if err != nil {
+		return signature, err
+	}
+
+	if len(shares) != len(s.commitments) {
+		return signature, errors.Errorf("threshold: got %d signature shares for %d signers", len(shares), len(s.commitments))
+	}
+
+	byID := make(map[uint16]SignatureShare, len(shares))
+	for _, share := range shares {
+		byID[share.ID] = share
+	}
+
+	z := curve.ScalarZero
+
+	for _, commitment := range s.commitments {
+		share, ok := byID[commitment.ID]
+		if !ok {
+			return signature, errors.Errorf("threshold: missing signature share of signer %d", commitment.ID)
+		}
+
+		if err := c.verifyShare(s, commitment, share); err != nil {
+			return signature, err
+		}
+
+		z = curve.ScalarAdd(z, share.Z)
+	}
+
+	copy(signature[:32], s.r[:])
+	copy(signature[32:], z[:])
+
+	return signature, nil
+}
+
+// verifyShare checks that z*B = hiding + rho*binding + lambda*c*public.
+func (c *Coordinator) verifyShare(s *session, commitment Commitment, share SignatureShare) error {
+	public, ok := c.group.Shares[share.ID]
+	if !ok || !curve.ScalarIsCanonical(share.Z) {
+		return errors.Wrapf(ErrInvalidShare, "signer %d", share.ID)
+	}
+
+	// The commitments were decoded successfully by newSession.
+	hiding, _ := curve.DecodePoint(commitment.Hiding)
+	binding, _ := curve.DecodePoint(commitment.Binding)
+
+	y, err := curve.DecodePoint(public)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidShare, "bad public key share of signer %d", share.ID)
+	}
+
+	expected := curve.AddPoints(hiding, curve.ScalarMult(s.factors[share.ID], binding))
+	expected = curve.AddPoints(expected, curve.ScalarMult(curve.ScalarMul(s.lagrange(share.ID), s.challenge), y))
+
+	if curve.EncodePoint(curve.ScalarMultBase(share.Z)) != curve.EncodePoint(expected) {
+		return errors.Wrapf(ErrInvalidShare, "signer %d", share.ID)
+	}
+
+	return nil
+}
+
+func randReader(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+
+	return r
+}
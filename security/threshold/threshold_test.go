@@ -0,0 +1,210 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package threshold
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGroup(t *testing.T, threshold, n int) (edwards25519.PrivateKey, []*Participant, Group) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	shares, group, err := Split(key, threshold, n, nil)
+	assert.NoError(t, err)
+	assert.Len(t, shares, n)
+
+	participants := make([]*Participant, n)
+
+	for i, share := range shares {
+		assert.NoError(t, group.Verify(share))
+		participants[i] = NewParticipant(share)
+	}
+
+	return key, participants, group
+}
+
+func TestThresholdSign(t *testing.T) {
+	key, participants, group := newTestGroup(t, 3, 5)
+	message := []byte("place stake")
+
+	// Any 3 of the 5 participants may sign.
+	for _, signers := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}} {
+		var signatories []Signatory
+		for _, i := range signers {
+			signatories = append(signatories, participants[i])
+		}
+
+		coordinator, err := NewCoordinator(group, signatories...)
+		assert.NoError(t, err)
+		assert.Equal(t, key.Public(), group.PublicKey)
+
+		signature, err := coordinator.Sign(message)
+		assert.NoError(t, err)
+		assert.NoError(t, security.Verify(coordinator.Scheme(), coordinator.PublicKey(), message, signature))
+	}
+}
+
+type offline struct{ *Participant }
+
+func (offline) Commit() (Commitment, error) {
+	return Commitment{}, errors.New("offline")
+}
+
+func TestThresholdSignTooFewSigners(t *testing.T) {
+	_, participants, group := newTestGroup(t, 3, 4)
+
+	_, err := NewCoordinator(group, participants[0], participants[1])
+	assert.Equal(t, ErrTooFewSigners, errors.Cause(err))
+
+	// Unavailable signatories are skipped, so long as the threshold is met.
+	coordinator, err := NewCoordinator(group, offline{participants[0]}, participants[1], participants[2], participants[3])
+	assert.NoError(t, err)
+
+	_, err = coordinator.Sign([]byte("message"))
+	assert.NoError(t, err)
+
+	coordinator, err = NewCoordinator(group, offline{participants[0]}, offline{participants[1]}, participants[2], participants[3])
+	assert.NoError(t, err)
+
+	_, err = coordinator.Sign([]byte("message"))
+	assert.Equal(t, ErrTooFewSigners, errors.Cause(err))
+}
+
+func TestThresholdSignShares(t *testing.T) {
+	_, participants, group := newTestGroup(t, 2, 3)
+	message := []byte("message")
+
+	coordinator, err := NewCoordinator(group, participants[0], participants[1])
+	assert.NoError(t, err)
+
+	c0, err := participants[0].Commit()
+	assert.NoError(t, err)
+
+	c1, err := participants[1].Commit()
+	assert.NoError(t, err)
+
+	commitments := []Commitment{c0, c1}
+
+	s0, err := participants[0].Sign(message, commitments)
+	assert.NoError(t, err)
+
+	// Nonces may only be used once.
+	_, err = participants[0].Sign(message, commitments)
+	assert.Equal(t, ErrUnknownCommitment, err)
+
+	// A participant may not sign without being part of the session.
+	_, err = participants[2].Sign(message, commitments)
+	assert.Error(t, err)
+
+	s1, err := participants[1].Sign(message, commitments)
+	assert.NoError(t, err)
+
+	signature, err := coordinator.Aggregate(message, commitments, []SignatureShare{s1, s0})
+	assert.NoError(t, err)
+	assert.True(t, edwards25519.Verify(group.PublicKey, message, signature))
+
+	// Tampered shares are detected and attributed.
+	bad := s1
+	bad.Z[0] ^= 1
+
+	_, err = coordinator.Aggregate(message, commitments, []SignatureShare{s0, bad})
+	assert.Equal(t, ErrInvalidShare, errors.Cause(err))
+	assert.Contains(t, err.Error(), "signer 2")
+
+	// Shares of one message do not aggregate into a signature of another.
+	_, err = coordinator.Aggregate([]byte("other"), commitments, []SignatureShare{s0, s1})
+	assert.Equal(t, ErrInvalidShare, errors.Cause(err))
+}
+
+func TestThresholdRejectsWeakCommitments(t *testing.T) {
+	_, participants, group := newTestGroup(t, 2, 2)
+	message := []byte("message")
+
+	coordinator, err := NewCoordinator(group, participants[0], participants[1])
+	assert.NoError(t, err)
+
+	c0, err := participants[0].Commit()
+	assert.NoError(t, err)
+
+	identity := [32]byte{1}
+	torsion := [32]byte{} // A point of order 4.
+
+	for _, weak := range [][32]byte{identity, torsion} {
+		for _, c1 := range []Commitment{
+			{ID: 2, Hiding: weak, Binding: c0.Binding},
+			{ID: 2, Hiding: c0.Hiding, Binding: weak},
+		} {
+			commitments := []Commitment{c0, c1}
+
+			_, err = coordinator.Aggregate(message, commitments, nil)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "signer 2")
+
+			// Participants refuse to sign, though their nonces are discarded
+			// all the same.
+			_, err = participants[0].Sign(message, commitments)
+			assert.Error(t, err)
+			assert.NotEqual(t, ErrUnknownCommitment, err)
+
+			c0, err = participants[0].Commit()
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestKeyShareMarshal(t *testing.T) {
+	_, participants, group := newTestGroup(t, 2, 2)
+
+	share := participants[1].share
+
+	decoded, err := UnmarshalKeyShare(share.Marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, share, decoded)
+	assert.NoError(t, group.Verify(decoded))
+
+	_, err = UnmarshalKeyShare(share.Marshal()[1:])
+	assert.Error(t, err)
+
+	decoded.Secret[0] ^= 1
+	assert.Error(t, group.Verify(decoded))
+}
+
+func TestSplitInvalidThreshold(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	for _, params := range [][2]int{{0, 3}, {4, 3}, {1, 1 << 16}} {
+		_, _, err := Split(key, params[0], params[1], nil)
+		assert.Equal(t, ErrInvalidThreshold, errors.Cause(err))
+	}
+
+	// A 1-of-1 split is the key itself.
+	_, group, err := Split(key, 1, 1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Public(), group.PublicKey)
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package threshold
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Participants are reached by their coordinator over HTTP. A participant
+// served by NewHandler answers POST /commit with a fresh Commitment, and
+// POST /sign with its SignatureShare of a message. Requests must carry the
+// participant's bearer token, and all byte strings are hex-encoded.
+
+// maxRequestSize bounds the size of the bodies of requests and responses
+// exchanged between a coordinator and its participants.
+const maxRequestSize = 1 << 20
+
+type commitmentJSON struct {
+	ID      uint16 `json:"id"`
+	Hiding  string `json:"hiding"`
+	Binding string `json:"binding"`
+}
+
+type signRequestJSON struct {
+	Message     string       `json:"message"`
+	Commitments []Commitment `json:"commitments"`
+}
+
+type signatureShareJSON struct {
+	ID uint16 `json:"id"`
+	Z  string `json:"z"`
+}
+
+func (c Commitment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(commitmentJSON{
+		ID:      c.ID,
+		Hiding:  hex.EncodeToString(c.Hiding[:]),
+		Binding: hex.EncodeToString(c.Binding[:]),
+	})
+}
+
+func (c *Commitment) UnmarshalJSON(buf []byte) error {
+	var v commitmentJSON
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return err
+	}
+
+	c.ID = v.ID
+
+	if err := decodeHex32(&c.Hiding, v.Hiding); err != nil {
+		return errors.Wrap(err, "invalid hiding commitment")
+	}
+
+	return errors.Wrap(decodeHex32(&c.Binding, v.Binding), "invalid binding commitment")
+}
+
+func (s SignatureShare) MarshalJSON() ([]byte, error) {
+	return json.Marshal(signatureShareJSON{ID: s.ID, Z: hex.EncodeToString(s.Z[:])})
+}
+
+func (s *SignatureShare) UnmarshalJSON(buf []byte) error {
+	var v signatureShareJSON
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return err
+	}
+
+	s.ID = v.ID
+
+	return errors.Wrap(decodeHex32(&s.Z, v.Z), "invalid signature share")
+}
+
+type groupJSON struct {
+	Threshold uint16            `json:"threshold"`
+	PublicKey string            `json:"public_key"`
+	Shares    map[string]string `json:"shares"`
+}
+
+// MarshalJSON encodes g, such that it may be handed to coordinators.
+func (g Group) MarshalJSON() ([]byte, error) {
+	v := groupJSON{
+		Threshold: g.Threshold,
+		PublicKey: hex.EncodeToString(g.PublicKey[:]),
+		Shares:    make(map[string]string, len(g.Shares)),
+	}
+
+	for id, public := range g.Shares {
+		v.Shares[strconv.FormatUint(uint64(id), 10)] = hex.EncodeToString(public[:])
+	}
+
+	return json.Marshal(v)
+}
+
+func (g *Group) UnmarshalJSON(buf []byte) error {
+	var v groupJSON
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return err
+	}
+
+	if v.Threshold < 1 || len(v.Shares) < int(v.Threshold) {
+		return errors.Wrapf(ErrInvalidThreshold, "%d-of-%d", v.Threshold, len(v.Shares))
+	}
+
+	g.Threshold = v.Threshold
+	g.Shares = make(map[uint16][32]byte, len(v.Shares))
+
+	if err := decodeHex32((*[32]byte)(&g.PublicKey), v.PublicKey); err != nil {
+		return errors.Wrap(err, "invalid group public key")
+	}
+
+	for s, public := range v.Shares {
+		id, err := strconv.ParseUint(s, 10, 16)
+		if err != nil || id == 0 {
+			return errors.Errorf("invalid participant ID %q", s)
+		}
+
+		var share [32]byte
+		if err := decodeHex32(&share, public); err != nil {
+			return errors.Wrapf(err, "invalid public key share of participant %d", id)
+		}
+
+		g.Shares[uint16(id)] = share
+	}
+
+	return nil
+}
+
+func decodeHex32(dst *[32]byte, s string) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+
+	if len(buf) != len(dst) {
+		return errors.Errorf("expected %d bytes, but got %d bytes", len(dst), len(buf))
+	}
+
+	copy(dst[:], buf)
+
+	return nil
+}
+
+// NewHandler serves p to coordinators presenting token as their bearer
+// token.
+func NewHandler(p *Participant, token string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/commit", func(w http.ResponseWriter, r *http.Request) {
+		commitment, err := p.Commit()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, commitment)
+	})
+
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		var req signRequestJSON
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(&req); err != nil {
+			http.Error(w, "invalid sign request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		message, err := hex.DecodeString(req.Message)
+		if err != nil {
+			http.Error(w, "invalid message: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		share, err := p.Sign(message, req.Commitments)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, share)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+var _ Signatory = (*Remote)(nil)
+
+// Remote is a participant served by NewHandler on another host.
+type Remote struct {
+	id     uint16
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewRemote creates a signatory of the participant with ID id, served at url
+// to coordinators presenting token.
+func NewRemote(id uint16, url, token string) *Remote {
+	return &Remote{
+		id:     id,
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (r *Remote) ID() uint16 {
+	return r.id
+}
+
+func (r *Remote) Commit() (Commitment, error) {
+	var commitment Commitment
+	err := r.post("/commit", nil, &commitment)
+
+	return commitment, err
+}
+
+func (r *Remote) Sign(message []byte, commitments []Commitment) (SignatureShare, error) {
+	req := signRequestJSON{Message: hex.EncodeToString(message), Commitments: commitments}
+
+	var share SignatureShare
+	err := r.post("/sign", req, &share)
+
+	return share, err
+}
+
+func (r *Remote) post(path string, body, res interface{}) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.url+path, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.token)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to reach signatory %d", r.id)
+	}
+
+	defer resp.Body.Close()
+
+	buf, err = ioutil.ReadAll(io.LimitReader(resp.Body, maxRequestSize))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read response of signatory %d", r.id)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("signatory %d responded with %s: %s", r.id, resp.Status, bytes.TrimSpace(buf))
+	}
+
+	return errors.Wrapf(json.Unmarshal(buf, res), "invalid response of signatory %d", r.id)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package threshold
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/perlin-network/wavelet/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteSign(t *testing.T) {
+	_, participants, group := newTestGroup(t, 2, 3)
+	message := []byte("delegate stake")
+
+	var signatories []Signatory
+
+	for _, p := range participants {
+		server := httptest.NewServer(NewHandler(p, "token"))
+		defer server.Close()
+
+		signatories = append(signatories, NewRemote(p.ID(), server.URL, "token"))
+	}
+
+	coordinator, err := NewCoordinator(group, signatories...)
+	assert.NoError(t, err)
+
+	signature, err := coordinator.Sign(message)
+	assert.NoError(t, err)
+	assert.NoError(t, security.Verify(coordinator.Scheme(), coordinator.PublicKey(), message, signature))
+
+	// Coordinators without the token are turned away.
+	server := httptest.NewServer(NewHandler(participants[0], "token"))
+	defer server.Close()
+
+	_, err = NewRemote(participants[0].ID(), server.URL, "wrong").Commit()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
+
+func TestGroupJSON(t *testing.T) {
+	_, _, group := newTestGroup(t, 2, 3)
+
+	buf, err := json.Marshal(group)
+	assert.NoError(t, err)
+
+	var decoded Group
+	assert.NoError(t, json.Unmarshal(buf, &decoded))
+	assert.Equal(t, group, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"threshold":3,"public_key":"","shares":{}}`), &decoded))
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package vrf implements the ECVRF-EDWARDS25519-SHA512-TAI verifiable random
+// function of RFC 9381 over Ed25519 keys.
+//
+// A VRF output is unpredictable to anyone but the holder of a private key,
+// yet unique for each input, and its proof convinces anyone knowing the
+// public key that the output was computed correctly.
+package vrf
+
+import (
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/internal/curve"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SizeProof is the size of a VRF proof.
+	SizeProof = 32 + 16 + 32
+
+	// SizeOutput is the size of a VRF output.
+	SizeOutput = 64
+)
+
+// ErrInvalidProof is returned when verifying an invalid VRF proof.
+var ErrInvalidProof = errors.New("vrf: invalid proof")
+
+// suite identifies ECVRF-EDWARDS25519-SHA512-TAI.
+const suite = 0x03
+
+type (
+	Proof  [SizeProof]byte
+	Output [SizeOutput]byte
+)
+
+// Prove computes the VRF output of key over alpha, alongside its proof.
+func Prove(key edwards25519.PrivateKey, alpha []byte) (Proof, Output) {
+	var proof Proof
+
+	x := curve.ExpandSecret(key)
+	public := key.Public()
+
+	h := encodeToCurve(public, alpha)
+	hBytes := curve.EncodePoint(h)
+
+	gamma := curve.ScalarMult(x, h)
+
+	// The nonce is derived as in RFC 8032, from the second half of the hashed
+	// private key.
+	digest := curve.Hash(key[:edwards25519.SizePrivateKey/2])
+	k := curve.HashToScalar(digest[32:], hBytes[:])
+
+	c := challenge(public, h, gamma, curve.ScalarMultBase(k), curve.ScalarMult(k, h))
+	s := curve.ScalarMulAdd(c, x, k)
+
+	gammaBytes := curve.EncodePoint(gamma)
+
+	copy(proof[:32], gammaBytes[:])
+	copy(proof[32:48], c[:16])
+	copy(proof[48:], s[:])
+
+	return proof, proofToHash(gamma)
+}
+
+// Verify checks proof to be a valid proof of a VRF output of the key public
+// over alpha, and returns said output.
+func Verify(public edwards25519.PublicKey, alpha []byte, proof Proof) (Output, error) {
+	y, err := curve.DecodePoint(public)
+	if err != nil || curve.IsIdentity(curve.MulByCofactor(y)) {
+		return Output{}, errors.Wrap(ErrInvalidProof, "bad public key")
+	}
+
+	var gammaBytes, c, s [32]byte
+
+	copy(gammaBytes[:], proof[:32])
+	copy(c[:16], proof[32:48])
+	copy(s[:], proof[48:])
+
+	gamma, err := curve.DecodePoint(gammaBytes)
+	if err != nil || !curve.ScalarIsCanonical(s) {
+		return Output{}, ErrInvalidProof
+	}
+
+	h := encodeToCurve(public, alpha)
+
+	// U = s*B - c*Y, V = s*H - c*Gamma
+	negC := curve.ScalarNeg(c)
+
+	u := curve.AddPoints(curve.ScalarMultBase(s), curve.ScalarMult(negC, y))
+	v := curve.AddPoints(curve.ScalarMult(s, h), curve.ScalarMult(negC, gamma))
+
+	if challenge(public, h, gamma, u, v) != c {
+		return Output{}, ErrInvalidProof
+	}
+
+	return proofToHash(gamma), nil
+}
+
+// encodeToCurve hashes alpha onto the curve by try-and-increment.
+func encodeToCurve(public edwards25519.PublicKey, alpha []byte) *curve.Point {
+	for ctr := 0; ; ctr++ {
+		digest := curve.Hash([]byte{suite, 0x01}, public[:], alpha, []byte{byte(ctr), 0x00})
+
+		var candidate [32]byte
+		copy(candidate[:], digest[:32])
+
+		h, err := curve.DecodePoint(candidate)
+		if err != nil {
+			continue
+		}
+
+		if h = curve.MulByCofactor(h); !curve.IsIdentity(h) {
+			return h
+		}
+	}
+}
+
+// challenge returns the 16-byte challenge of a proof as a scalar.
+func challenge(public edwards25519.PublicKey, h, gamma, u, v *curve.Point) [32]byte {
+	hBytes, gammaBytes := curve.EncodePoint(h), curve.EncodePoint(gamma)
+	uBytes, vBytes := curve.EncodePoint(u), curve.EncodePoint(v)
+
+	digest := curve.Hash(
+		[]byte{suite, 0x02}, public[:], hBytes[:], gammaBytes[:], uBytes[:], vBytes[:], []byte{0x00},
+	)
+
+	var c [32]byte
+	copy(c[:16], digest[:16])
+
+	return c
+}
+
+func proofToHash(gamma *curve.Point) Output {
+	gammaBytes := curve.EncodePoint(curve.MulByCofactor(gamma))
+
+	return curve.Hash([]byte{suite, 0x03}, gammaBytes[:], []byte{0x00})
+}
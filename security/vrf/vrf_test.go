@@ -0,0 +1,88 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package vrf
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/stretchr/testify/assert"
+)
+
+// Example 16 of RFC 9381.
+func TestVRFVector(t *testing.T) {
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	public, _ := hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+
+	var key edwards25519.PrivateKey
+	copy(key[:32], seed)
+	copy(key[32:], public)
+
+	proof, output := Prove(key, nil)
+
+	assert.Equal(t,
+		"8657106690b5526245a92b003bb079ccd1a92130477671f6fc01ad16f26f723f"+
+			"26f8a57ccaed74ee1b190bed1f479d9727d2d0f9b005a6e456a35d4fb0daab12"+
+			"68a1b0db10836d9826a528ca76567805",
+		hex.EncodeToString(proof[:]),
+	)
+	assert.Equal(t,
+		"90cf1df3b703cce59e2a35b925d411164068269d7b2d29f3301c03dd757876ff"+
+			"66b71dda49d2de59d03450451af026798e8f81cd2e333de5cdf4f3e140fdd8ae",
+		hex.EncodeToString(output[:]),
+	)
+}
+
+func TestVRF(t *testing.T) {
+	public, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	alpha := []byte("round 42")
+
+	proof, output := Prove(key, alpha)
+
+	verified, err := Verify(public, alpha, proof)
+	assert.NoError(t, err)
+	assert.Equal(t, output, verified)
+
+	// Outputs are unique to each input.
+	_, other := Prove(key, []byte("round 43"))
+	assert.NotEqual(t, output, other)
+
+	_, err = Verify(public, []byte("round 43"), proof)
+	assert.Equal(t, ErrInvalidProof, err)
+
+	otherPublic, _, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	_, err = Verify(otherPublic, alpha, proof)
+	assert.Equal(t, ErrInvalidProof, err)
+
+	for _, i := range []int{0, 40, 70} {
+		tampered := proof
+		tampered[i] ^= 1
+
+		_, err = Verify(public, alpha, tampered)
+		assert.Equal(t, ErrInvalidProof, err)
+	}
+}
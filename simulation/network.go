@@ -0,0 +1,218 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package simulation
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Topology models the links between nodes. Nodes are placed in regions, and
+// the latency of a link is that between the regions of its ends.
+type Topology struct {
+	// Latency is the one-way latency between each pair of regions, indexed by
+	// region.
+	Latency [][]time.Duration
+
+	// Regions, if set, holds the region of each node. Nodes are otherwise
+	// assigned to regions round-robin.
+	Regions []int
+
+	// Jitter is the fraction of the latency of a link by which the delivery
+	// of each message is randomly delayed further.
+	Jitter float64
+
+	// Loss is the probability of any message being lost.
+	Loss float64
+}
+
+// Uniform returns a topology of a single region, in which every link has the
+// given latency.
+func Uniform(latency time.Duration) Topology {
+	return Topology{Latency: [][]time.Duration{{latency}}}
+}
+
+// Global returns a topology of nodes spread across North America, Europe and
+// Asia, with latencies between regions approximating those between public
+// cloud data centers.
+func Global() Topology {
+	ms := time.Millisecond
+
+	return Topology{
+		Latency: [][]time.Duration{
+			// us-east, us-west, eu-west, ap-southeast
+			{5 * ms, 35 * ms, 40 * ms, 110 * ms},
+			{35 * ms, 5 * ms, 70 * ms, 85 * ms},
+			{40 * ms, 70 * ms, 5 * ms, 90 * ms},
+			{110 * ms, 85 * ms, 90 * ms, 5 * ms},
+		},
+		Jitter: 0.1,
+	}
+}
+
+func (t Topology) validate(nodes int) error {
+	if len(t.Latency) == 0 {
+		return errors.New("latency matrix must have at least one region")
+	}
+
+	for i, row := range t.Latency {
+		if len(row) != len(t.Latency) {
+			return errors.Errorf("latency matrix must be square, but row %d has %d columns for %d regions",
+				i, len(row), len(t.Latency))
+		}
+
+		for _, latency := range row {
+			if latency < 0 {
+				return errors.New("latencies must not be negative")
+			}
+		}
+	}
+
+	if t.Regions != nil {
+		if len(t.Regions) != nodes {
+			return errors.Errorf("got regions for %d nodes, but there are %d nodes", len(t.Regions), nodes)
+		}
+
+		for i, region := range t.Regions {
+			if region < 0 || region >= len(t.Latency) {
+				return errors.Errorf("node %d is placed in unknown region %d", i, region)
+			}
+		}
+	}
+
+	if t.Jitter < 0 {
+		return errors.New("jitter must not be negative")
+	}
+
+	if t.Loss < 0 || t.Loss >= 1 {
+		return errors.New("loss must be in [0, 1)")
+	}
+
+	return nil
+}
+
+func (t Topology) region(node int) int {
+	if t.Regions != nil {
+		return t.Regions[node]
+	}
+
+	return node % len(t.Latency)
+}
+
+// delay returns how long a message from one node to another takes to be
+// delivered.
+func (t Topology) delay(rng *rand.Rand, from, to int) time.Duration {
+	latency := t.Latency[t.region(from)][t.region(to)]
+
+	return latency + time.Duration(rng.Float64()*t.Jitter*float64(latency))
+}
+
+// Partition splits the network into groups of nodes which may not reach one
+// another for a period of time. Nodes in no group form a group of their own.
+type Partition struct {
+	Start, End time.Duration
+
+	Groups [][]int
+}
+
+func (p Partition) validate(nodes int) error {
+	if p.End <= p.Start {
+		return errors.New("partition must end after it starts")
+	}
+
+	seen := make(map[int]struct{})
+
+	for _, group := range p.Groups {
+		for _, node := range group {
+			if node < 0 || node >= nodes {
+				return errors.Errorf("unknown node %d", node)
+			}
+
+			if _, exists := seen[node]; exists {
+				return errors.Errorf("node %d is in more than one group", node)
+			}
+
+			seen[node] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// separates reports whether nodes a and b are partitioned away from each
+// other at the given time.
+func (p Partition) separates(now time.Duration, a, b int) bool {
+	if now < p.Start || now >= p.End {
+		return false
+	}
+
+	return p.group(a) != p.group(b)
+}
+
+func (p Partition) group(node int) int {
+	for i, group := range p.Groups {
+		for _, n := range group {
+			if n == node {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Churn models nodes leaving and rejoining the network. Nodes subject to
+// churn alternate between being online and offline for exponentially
+// distributed periods of time. Nodes keep their finalized blocks while
+// offline, as they would after a restart.
+type Churn struct {
+	// Fraction is the fraction of nodes subject to churn.
+	Fraction float64
+
+	// MeanUptime and MeanDowntime are the mean periods of time nodes subject
+	// to churn stay online and offline for.
+	MeanUptime, MeanDowntime time.Duration
+}
+
+func (c Churn) validate() error {
+	if c.Fraction < 0 || c.Fraction > 1 {
+		return errors.New("fraction must be in [0, 1]")
+	}
+
+	if c.Fraction > 0 && (c.MeanUptime <= 0 || c.MeanDowntime <= 0) {
+		return errors.New("mean uptime and downtime must be positive")
+	}
+
+	return nil
+}
+
+func (c Churn) churns(rng *rand.Rand) bool {
+	return c.Fraction > 0 && rng.Float64() < c.Fraction
+}
+
+func (c Churn) uptime(rng *rand.Rand) time.Duration {
+	return time.Duration(rng.ExpFloat64() * float64(c.MeanUptime))
+}
+
+func (c Churn) downtime(rng *rand.Rand) time.Duration {
+	return time.Duration(rng.ExpFloat64() * float64(c.MeanDowntime))
+}
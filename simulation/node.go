@@ -0,0 +1,387 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package simulation
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/sys"
+	"golang.org/x/crypto/blake2b"
+)
+
+// block is a proposed block, reduced to what votes are weighed by.
+type block struct {
+	id     wavelet.VoteID
+	height uint64
+	txs    int
+}
+
+// vote is a finalization vote of a node for a block, or for no block at all.
+type vote struct {
+	voter *node
+	block *block
+	tally float64
+}
+
+var _ wavelet.Vote = (*vote)(nil)
+
+func (v *vote) ID() wavelet.VoteID {
+	if v.block == nil {
+		return wavelet.ZeroVoteID
+	}
+
+	return v.block.id
+}
+
+func (v *vote) VoterID() wavelet.AccountID {
+	return v.voter.id
+}
+
+func (v *vote) Length() float64 {
+	if v.block == nil {
+		return 0
+	}
+
+	return float64(v.block.txs)
+}
+
+func (v *vote) Value() interface{} {
+	return v.block
+}
+
+func (v *vote) Tally() float64 {
+	return v.tally
+}
+
+func (v *vote) SetTally(tally float64) {
+	v.tally = tally
+}
+
+// node is a simulated node, which repeatedly proposes a block, queries peers
+// for their preferred block until its finalizer decides on one, and then
+// finalizes it, as the ledger does.
+type node struct {
+	sim   *Simulator
+	index int
+	id    wavelet.AccountID
+
+	online bool
+
+	// epoch is incremented whenever the node leaves the network, such that
+	// events scheduled before it left are discarded.
+	epoch uint64
+
+	finalizer *wavelet.Snowball
+	finalized []*block
+
+	// started is when the node started finalizing its current height, and
+	// latencies holds how long it took to finalize each height.
+	started   time.Duration
+	latencies []time.Duration
+
+	pending *query
+}
+
+// query is a round of queries to a sample of peers.
+type query struct {
+	height   uint64
+	expected int
+	votes    []wavelet.Vote
+	voters   map[int]struct{}
+}
+
+func newNode(sim *Simulator, index int) *node {
+	n := &node{
+		sim:       sim,
+		index:     index,
+		online:    true,
+		finalizer: wavelet.NewSnowball(),
+	}
+
+	binary.BigEndian.PutUint64(n.id[:], uint64(index))
+
+	return n
+}
+
+// height returns the index of the block the node is finalizing.
+func (n *node) height() uint64 {
+	return uint64(len(n.finalized)) + 1
+}
+
+// after schedules fn to be run once d has elapsed, unless the node leaves the
+// network in the meantime.
+func (n *node) after(d time.Duration, fn func()) {
+	epoch := n.epoch
+
+	n.sim.after(d, func() {
+		if n.online && n.epoch == epoch {
+			fn()
+		}
+	})
+}
+
+func (n *node) step() {
+	preferred := n.finalizer.Preferred()
+
+	switch {
+	case preferred == nil:
+		n.finalizer.Prefer(&vote{voter: n, block: n.sim.propose(n.height())})
+		n.after(n.sim.cfg.RoundDelay, n.step)
+	case n.finalizer.Decided():
+		n.finalize(preferred.Value().(*block))
+		n.after(n.sim.cfg.RoundDelay, n.step)
+	default:
+		n.query()
+	}
+}
+
+func (n *node) finalize(b *block) {
+	n.finalized = append(n.finalized, b)
+	n.latencies = append(n.latencies, n.sim.now-n.started)
+	n.started = n.sim.now
+
+	n.finalizer.Reset()
+
+	if uint64(len(n.finalized)) == n.sim.cfg.Heights {
+		n.sim.complete++
+	}
+}
+
+func (n *node) query() {
+	peers := n.sim.sample(n, conf.GetSnowballK())
+
+	q := &query{
+		height:   n.height(),
+		expected: len(peers),
+		votes:    make([]wavelet.Vote, 0, len(peers)+1),
+		voters:   make(map[int]struct{}, len(peers)),
+	}
+
+	n.pending = q
+	n.sim.queries++
+
+	for _, p := range peers {
+		p := p
+
+		n.sim.send(n, p, func() {
+			b := p.preferredAt(q.height)
+
+			n.sim.send(p, n, func() {
+				n.receive(q, p, b)
+			})
+		})
+	}
+
+	n.after(conf.GetQueryTimeout(), func() {
+		if n.pending == q {
+			n.conclude(q)
+		}
+	})
+}
+
+// preferredAt returns the block the node votes for when queried about the
+// given height: the block it prefers should it be finalizing that height, or
+// the block it finalized at that height should it be past it.
+func (n *node) preferredAt(height uint64) *block {
+	switch {
+	case height == n.height():
+		if preferred := n.finalizer.Preferred(); preferred != nil {
+			return preferred.Value().(*block)
+		}
+	case height < n.height():
+		return n.finalized[height-1]
+	}
+
+	return nil
+}
+
+func (n *node) receive(q *query, from *node, b *block) {
+	if n.pending != q {
+		return
+	}
+
+	if _, recorded := q.voters[from.index]; recorded {
+		return // To make sure the sampling process is fair, only allow one vote per peer.
+	}
+
+	q.voters[from.index] = struct{}{}
+	q.votes = append(q.votes, &vote{voter: from, block: b})
+
+	if len(q.votes) == q.expected {
+		n.conclude(q)
+	}
+}
+
+func (n *node) conclude(q *query) {
+	n.pending = nil
+
+	// Include our own vote as well.
+	q.votes = append(q.votes, &vote{voter: n, block: n.preferredAt(q.height)})
+
+	n.finalizer.Tick(n.sim.tally(q.votes))
+	n.after(n.sim.cfg.RoundDelay, n.step)
+}
+
+// leave takes the node offline, dropping its preferred block as a restart
+// would, until it rejoins the network.
+func (n *node) leave() {
+	n.online = false
+	n.epoch++
+	n.pending = nil
+
+	n.finalizer.Reset()
+
+	n.sim.after(n.sim.cfg.Churn.downtime(n.sim.rng), n.rejoin)
+}
+
+func (n *node) rejoin() {
+	n.online = true
+
+	n.after(n.sim.jitter(n.sim.cfg.RoundDelay), n.step)
+	n.after(n.sim.cfg.Churn.uptime(n.sim.rng), n.leave)
+}
+
+// propose returns one of the cfg.Conflicts blocks that may be proposed at
+// the given height, at random. The i-th such block has i transactions less
+// than the first, as nodes' views of the mempool would differ.
+func (s *Simulator) propose(height uint64) *block {
+	candidate := s.rng.Intn(s.cfg.Conflicts)
+
+	var buf [16]byte
+
+	binary.BigEndian.PutUint64(buf[:8], height)
+	binary.BigEndian.PutUint64(buf[8:], uint64(candidate))
+
+	return &block{id: blake2b.Sum256(buf[:]), height: height, txs: s.cfg.Transactions - candidate}
+}
+
+// sample returns amount distinct peers of n to query, sampled by stake should
+// stakes be set, or uniformly otherwise.
+func (s *Simulator) sample(n *node, amount int) []*node {
+	peers := make([]*node, 0, len(s.nodes)-1)
+
+	for _, p := range s.nodes {
+		if p != n {
+			peers = append(peers, p)
+		}
+	}
+
+	if s.cfg.Stakes == nil {
+		s.rng.Shuffle(len(peers), func(i, j int) {
+			peers[i], peers[j] = peers[j], peers[i]
+		})
+
+		return peers[:amount]
+	}
+
+	// Sample without replacement by stake, as wavelet.SelectPeersByStake does.
+	keys := make([]float64, len(s.nodes))
+
+	for _, p := range peers {
+		keys[p.index] = math.Log(1-s.rng.Float64()) / float64(s.stake(p))
+	}
+
+	sort.SliceStable(peers, func(i, j int) bool {
+		return keys[peers[i].index] > keys[peers[j].index]
+	})
+
+	return peers[:amount]
+}
+
+// stake returns the stake n's votes are weighed by.
+func (s *Simulator) stake(n *node) uint64 {
+	if s.cfg.Stakes == nil || s.cfg.Stakes[n.index] < sys.MinimumStake {
+		return sys.MinimumStake
+	}
+
+	return s.cfg.Stakes[n.index]
+}
+
+// tally calculates the tallies of votes as the ledger does, weighing votes
+// by the number of transactions of the block voted for, and the stake of
+// their voters. Tallies are returned in the order votes were first cast for
+// each block, rather than in map order, to keep simulations deterministic.
+func (s *Simulator) tally(responses []wavelet.Vote) []wavelet.Vote {
+	votes := make(map[wavelet.VoteID]wavelet.Vote, len(responses))
+	order := make([]wavelet.VoteID, 0, len(responses))
+
+	for _, res := range responses {
+		if _, exists := votes[res.ID()]; !exists {
+			votes[res.ID()] = res
+			order = append(order, res.ID())
+		}
+
+		votes[res.ID()].SetTally(votes[res.ID()].Tally() + 1.0/float64(len(responses)))
+	}
+
+	for id, weight := range wavelet.Normalize(wavelet.WeighByTransactions(responses)) {
+		votes[id].SetTally(votes[id].Tally() * weight)
+	}
+
+	for id, weight := range wavelet.Normalize(s.weighByStake(responses)) {
+		votes[id].SetTally(votes[id].Tally() * weight)
+	}
+
+	total := float64(0)
+	for _, id := range order {
+		total += votes[id].Tally()
+	}
+
+	tallies := make([]wavelet.Vote, 0, len(votes))
+
+	for _, id := range order {
+		vote := votes[id]
+		vote.SetTally(vote.Tally() / total)
+		tallies = append(tallies, vote)
+	}
+
+	return tallies
+}
+
+// weighByStake is wavelet.WeighByStake, with stakes read from the
+// configuration rather than from accounts.
+func (s *Simulator) weighByStake(responses []wavelet.Vote) map[wavelet.VoteID]float64 {
+	weights := make(map[wavelet.VoteID]float64, len(responses))
+
+	var max float64
+
+	for _, res := range responses {
+		if res.ID() == wavelet.ZeroVoteID {
+			continue
+		}
+
+		weights[res.ID()] += float64(s.stake(res.(*vote).voter))
+
+		if weights[res.ID()] > max {
+			max = weights[res.ID()]
+		}
+	}
+
+	for id := range weights {
+		weights[id] /= max
+	}
+
+	return weights
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package simulation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Result is the outcome of a simulation.
+type Result struct {
+	// Elapsed is the simulated time the simulation ran for.
+	Elapsed time.Duration
+
+	// Complete reports whether every node finalized all heights before the
+	// simulation timed out.
+	Complete bool
+
+	Heights []Height
+
+	// Queries is the number of rounds of queries sent, and Messages the
+	// number of queries and responses sent, Dropped of which were lost.
+	Queries  uint64
+	Messages uint64
+	Dropped  uint64
+}
+
+// Height summarizes the finalization of a single height across all nodes.
+type Height struct {
+	Index uint64
+
+	// Finalized is the number of nodes which finalized a block at this
+	// height.
+	Finalized int
+
+	// Blocks is the number of distinct blocks finalized at this height. More
+	// than one means that nodes disagree, and that safety was violated.
+	Blocks int
+
+	// P50, P90 and Max are percentiles of the time nodes took to finalize
+	// this height, from having finalized the previous one.
+	P50, P90, Max time.Duration
+}
+
+// Safe reports whether all nodes agree on every block finalized.
+func (r Result) Safe() bool {
+	for _, h := range r.Heights {
+		if h.Blocks > 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Simulator) result() Result {
+	r := Result{
+		Elapsed:  s.now,
+		Complete: s.complete == len(s.nodes),
+		Queries:  s.queries,
+		Messages: s.messages,
+		Dropped:  s.dropped,
+	}
+
+	for i := 0; i < int(s.cfg.Heights); i++ {
+		h := Height{Index: uint64(i) + 1}
+
+		blocks := make(map[[32]byte]struct{})
+
+		var latencies []time.Duration
+
+		for _, n := range s.nodes {
+			if i >= len(n.finalized) {
+				continue
+			}
+
+			h.Finalized++
+			blocks[n.finalized[i].id] = struct{}{}
+			latencies = append(latencies, n.latencies[i])
+		}
+
+		h.Blocks = len(blocks)
+
+		sort.Slice(latencies, func(i, j int) bool {
+			return latencies[i] < latencies[j]
+		})
+
+		h.P50 = percentile(latencies, 50)
+		h.P90 = percentile(latencies, 90)
+		h.Max = percentile(latencies, 100)
+
+		r.Heights = append(r.Heights, h)
+	}
+
+	return r
+}
+
+// percentile returns the p-th percentile of sorted using the nearest-rank
+// method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// WriteText writes the result as a human-readable table.
+func (r Result) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "elapsed: %s, complete: %t, safe: %t\n", r.Elapsed, r.Complete, r.Safe())
+	fmt.Fprintf(w, "queries: %d, messages: %d, dropped: %d\n\n", r.Queries, r.Messages, r.Dropped)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(tw, "height\tfinalized\tblocks\tp50\tp90\tmax\t")
+
+	for _, h := range r.Heights {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%s\t%s\t\n", h.Index, h.Finalized, h.Blocks, h.P50, h.P90, h.Max)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package simulation runs tens to hundreds of lightweight, in-process nodes
+// over a modeled network, so that changes to consensus and gossip may be
+// evaluated at scale before being deployed to a testnet.
+//
+// Nodes finalize blocks with the same Snowball finalizer, query protocol and
+// vote tallying as the ledger, though blocks are reduced to an ID and a
+// number of transactions. Messages between nodes are delayed according to a
+// latency matrix between regions, and may be lost, cut off by partitions or
+// dropped by nodes having churned out of the network. Time is simulated, such
+// that a run is deterministic for a given seed and takes no longer than the
+// computation it involves.
+//
+// Consensus parameters such as the Snowball K, alpha and beta, and the query
+// timeout, are read from package conf.
+package simulation
+
+import (
+	"container/heap"
+	"math/rand"
+	"time"
+
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/pkg/errors"
+)
+
+// Config configures a simulation.
+type Config struct {
+	// Nodes is the number of nodes in the network.
+	Nodes int
+
+	// Topology models the links between nodes.
+	Topology Topology
+
+	// Partitions lists the partitions the network undergoes.
+	Partitions []Partition
+
+	// Churn models nodes leaving and rejoining the network.
+	Churn Churn
+
+	// Stakes, if set, holds the stake of each node, by which peers are
+	// sampled and votes are weighed. Nodes are otherwise weighed equally.
+	Stakes []uint64
+
+	// Conflicts is the number of distinct blocks proposed at each height, of
+	// which each node proposes one at random. It models nodes having
+	// different views of the mempool.
+	Conflicts int
+
+	// Transactions is the number of transactions of the largest block
+	// proposed at each height. Each other block proposed has one transaction
+	// less than the last.
+	Transactions int
+
+	// RoundDelay is the time a node takes between rounds of queries,
+	// accounting for the processing of votes.
+	RoundDelay time.Duration
+
+	// Heights is the number of blocks every node is to finalize for the
+	// simulation to complete.
+	Heights uint64
+
+	// Timeout is the simulated time past which the simulation is stopped,
+	// should not every node have finalized Heights blocks by then.
+	Timeout time.Duration
+
+	// Seed seeds all randomness of the simulation.
+	Seed int64
+}
+
+// DefaultConfig returns a configuration of 50 nodes spread across a global
+// network, finalizing 10 blocks.
+func DefaultConfig() Config {
+	return Config{
+		Nodes:        50,
+		Topology:     Global(),
+		Conflicts:    1,
+		Transactions: 100,
+		RoundDelay:   time.Millisecond,
+		Heights:      10,
+		Timeout:      time.Hour,
+		Seed:         1,
+	}
+}
+
+func (c Config) validate() error {
+	if c.Nodes <= conf.GetSnowballK() {
+		return errors.Errorf("require more than %d nodes to sample queries from, but got %d", conf.GetSnowballK(), c.Nodes)
+	}
+
+	if c.Stakes != nil && len(c.Stakes) != c.Nodes {
+		return errors.Errorf("got %d stakes for %d nodes", len(c.Stakes), c.Nodes)
+	}
+
+	if c.Conflicts < 1 {
+		return errors.New("at least one block must be proposed per height")
+	}
+
+	if c.Transactions < c.Conflicts {
+		return errors.Errorf("proposed blocks must have at least %d transactions to differ in size", c.Conflicts)
+	}
+
+	if c.Heights == 0 {
+		return errors.New("at least one height must be finalized")
+	}
+
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+
+	if err := c.Topology.validate(c.Nodes); err != nil {
+		return errors.Wrap(err, "invalid topology")
+	}
+
+	for i, p := range c.Partitions {
+		if err := p.validate(c.Nodes); err != nil {
+			return errors.Wrapf(err, "invalid partition %d", i)
+		}
+	}
+
+	if err := c.Churn.validate(); err != nil {
+		return errors.Wrap(err, "invalid churn")
+	}
+
+	return nil
+}
+
+// Simulator runs a simulation. It is not safe for concurrent use.
+type Simulator struct {
+	cfg Config
+	rng *rand.Rand
+
+	now    time.Duration
+	seq    uint64
+	events eventQueue
+
+	nodes []*node
+
+	// complete is the number of nodes which finalized cfg.Heights blocks.
+	complete int
+
+	messages uint64
+	dropped  uint64
+	queries  uint64
+}
+
+// New creates a simulator of the network described by cfg.
+func New(cfg Config) (*Simulator, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	s := &Simulator{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed))}
+
+	s.nodes = make([]*node, cfg.Nodes)
+
+	for i := range s.nodes {
+		s.nodes[i] = newNode(s, i)
+	}
+
+	return s, nil
+}
+
+// Run runs the simulation until every node has finalized cfg.Heights blocks,
+// or cfg.Timeout has elapsed in simulated time.
+func (s *Simulator) Run() Result {
+	for _, n := range s.nodes {
+		n := n
+
+		s.after(s.jitter(s.cfg.RoundDelay), n.step)
+
+		if s.cfg.Churn.churns(s.rng) {
+			s.after(s.cfg.Churn.uptime(s.rng), n.leave)
+		}
+	}
+
+	for s.events.Len() > 0 && s.complete < len(s.nodes) {
+		e := heap.Pop(&s.events).(*event)
+
+		if e.at > s.cfg.Timeout {
+			s.now = s.cfg.Timeout
+			break
+		}
+
+		s.now = e.at
+		e.fn()
+	}
+
+	return s.result()
+}
+
+// Now returns the current simulated time.
+func (s *Simulator) Now() time.Duration {
+	return s.now
+}
+
+// after schedules fn to be run once d has elapsed.
+func (s *Simulator) after(d time.Duration, fn func()) {
+	s.seq++
+	heap.Push(&s.events, &event{at: s.now + d, seq: s.seq, fn: fn})
+}
+
+// jitter returns a random duration in [0, d), such that nodes do not all
+// act in lockstep.
+func (s *Simulator) jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(s.rng.Int63n(int64(d)))
+}
+
+// send delivers a message from one node to another after the latency of the
+// link between them, by running deliver. The message is lost should either
+// node be offline, or be partitioned away from the other.
+func (s *Simulator) send(from, to *node, deliver func()) {
+	s.messages++
+
+	if !from.online || !to.online || s.partitioned(from.index, to.index) || s.rng.Float64() < s.cfg.Topology.Loss {
+		s.dropped++
+		return
+	}
+
+	epoch := to.epoch
+
+	s.after(s.cfg.Topology.delay(s.rng, from.index, to.index), func() {
+		// Drop the message should the recipient have left in the meantime.
+		if !to.online || to.epoch != epoch {
+			s.dropped++
+			return
+		}
+
+		deliver()
+	})
+}
+
+func (s *Simulator) partitioned(a, b int) bool {
+	for _, p := range s.cfg.Partitions {
+		if p.separates(s.now, a, b) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type event struct {
+	at  time.Duration
+	seq uint64
+	fn  func()
+}
+
+// eventQueue is a min-heap of events ordered by time, and then by the order
+// in which they were scheduled.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+
+	return e
+}
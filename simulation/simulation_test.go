@@ -0,0 +1,171 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package simulation
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func smallConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Nodes = 20
+	cfg.Heights = 3
+
+	return cfg
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := map[string]func(cfg *Config){
+		"too few nodes":     func(cfg *Config) { cfg.Nodes = 1 },
+		"missing stakes":    func(cfg *Config) { cfg.Stakes = []uint64{1} },
+		"no conflicts":      func(cfg *Config) { cfg.Conflicts = 0 },
+		"too small blocks":  func(cfg *Config) { cfg.Conflicts, cfg.Transactions = 3, 2 },
+		"no heights":        func(cfg *Config) { cfg.Heights = 0 },
+		"no timeout":        func(cfg *Config) { cfg.Timeout = 0 },
+		"non-square matrix": func(cfg *Config) { cfg.Topology.Latency[0] = cfg.Topology.Latency[0][:1] },
+		"unknown region":    func(cfg *Config) { cfg.Topology.Regions = make([]int, cfg.Nodes); cfg.Topology.Regions[3] = 9 },
+		"certain loss":      func(cfg *Config) { cfg.Topology.Loss = 1 },
+		"empty partition":   func(cfg *Config) { cfg.Partitions = []Partition{{Start: time.Second, End: time.Second}} },
+		"unknown partitioned node": func(cfg *Config) {
+			cfg.Partitions = []Partition{{End: time.Second, Groups: [][]int{{cfg.Nodes}}}}
+		},
+		"overlapping groups": func(cfg *Config) {
+			cfg.Partitions = []Partition{{End: time.Second, Groups: [][]int{{0, 1}, {1, 2}}}}
+		},
+		"churn without uptime": func(cfg *Config) { cfg.Churn = Churn{Fraction: 0.5, MeanDowntime: time.Second} },
+	}
+
+	_, err := New(smallConfig())
+	require.NoError(t, err)
+
+	for name, mutate := range tests {
+		cfg := smallConfig()
+		mutate(&cfg)
+
+		_, err := New(cfg)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	cfg := smallConfig()
+	cfg.Conflicts = 2
+
+	run := func() Result {
+		s, err := New(cfg)
+		require.NoError(t, err)
+
+		return s.Run()
+	}
+
+	assert.Equal(t, run(), run())
+}
+
+func TestRunFinalizes(t *testing.T) {
+	cfg := smallConfig()
+
+	s, err := New(cfg)
+	require.NoError(t, err)
+
+	res := s.Run()
+
+	assert.True(t, res.Complete)
+	assert.True(t, res.Safe())
+	assert.Zero(t, res.Dropped)
+	require.Len(t, res.Heights, int(cfg.Heights))
+
+	for _, h := range res.Heights {
+		assert.Equal(t, cfg.Nodes, h.Finalized)
+		assert.Equal(t, 1, h.Blocks)
+		assert.True(t, h.P50 <= h.P90 && h.P90 <= h.Max)
+	}
+
+	// Finalizing a height takes at least beta rounds, each of which take a
+	// round trip within a region.
+	assert.True(t, res.Heights[1].P50 > 150*10*time.Millisecond, "p50 of %s", res.Heights[1].P50)
+
+	var buf bytes.Buffer
+	require.NoError(t, res.WriteText(&buf))
+	assert.Contains(t, buf.String(), "complete: true, safe: true")
+}
+
+func TestRunStallsDuringPartition(t *testing.T) {
+	cfg := smallConfig()
+	cfg.Topology = Uniform(10 * time.Millisecond)
+	cfg.Partitions = []Partition{{
+		Start:  0,
+		End:    5 * time.Minute,
+		Groups: [][]int{{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+	}}
+
+	s, err := New(cfg)
+	require.NoError(t, err)
+
+	res := s.Run()
+
+	assert.True(t, res.Complete)
+	assert.True(t, res.Safe())
+	assert.NotZero(t, res.Dropped)
+
+	// Queries across the partition time out, such that the first height may
+	// only be finalized by all nodes once the partition heals.
+	assert.True(t, res.Heights[0].Max > 5*time.Minute, "max of %s", res.Heights[0].Max)
+	assert.True(t, res.Heights[1].Max < 5*time.Minute, "max of %s", res.Heights[1].Max)
+}
+
+func TestRunWithChurn(t *testing.T) {
+	cfg := smallConfig()
+	cfg.Churn = Churn{Fraction: 0.2, MeanUptime: 5 * time.Minute, MeanDowntime: 10 * time.Second}
+
+	s, err := New(cfg)
+	require.NoError(t, err)
+
+	res := s.Run()
+
+	assert.True(t, res.Complete)
+	assert.True(t, res.Safe())
+}
+
+func TestSampleByStake(t *testing.T) {
+	cfg := smallConfig()
+	cfg.Stakes = make([]uint64, cfg.Nodes)
+	cfg.Stakes[1] = 1000000
+
+	s, err := New(cfg)
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		peers := s.sample(s.nodes[0], 2)
+		require.Len(t, peers, 2)
+
+		assert.NotEqual(t, peers[0], peers[1])
+		assert.NotEqual(t, s.nodes[0], peers[0])
+		assert.NotEqual(t, s.nodes[0], peers[1])
+
+		assert.Contains(t, peers, s.nodes[1])
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
+)
+
+// Slashing penalizes validators for misbehaving. Anyone may report a
+// validator for either of the following, slashing a fraction of the PERLs the
+// validator staked:
+//
+//	Double-signing, evidenced by two distinct transactions of the validator
+//	of the same nonce paying the same fee, neither of which may hence
+//	replace the other. sys.SlashingDoubleSignFraction of its stake is
+//	slashed, once per nonce.
+//
+//	Downtime, which is having had no transaction applied for more than
+//	sys.SlashingDowntimeBlocks blocks. sys.SlashingDowntimeFraction of its
+//	stake is slashed, after which the validator is deemed active again.
+//
+// sys.SlashingReporterShare of the PERLs slashed are rewarded to the reporter,
+// and the rest are burned. PERLs delegated to a validator are not slashed.
+
+// slashingFractionScale is what fractions of stake slashed are expressed
+// against, them being in hundredths of a percent.
+const slashingFractionScale = 10000
+
+// verifySlashing checks that the validator reported by tx may be slashed at
+// the block at index height.
+func verifySlashing(
+	readStake, readLastActive func(AccountID) (uint64, bool),
+	readSlashings func(AccountID) ([]SlashingEvent, bool),
+	height uint64, tx *Transaction, payload Slashing,
+) error {
+	if payload.Validator == tx.Sender {
+		return errors.Errorf("slashing: %x may not report itself", tx.Sender)
+	}
+
+	stake, _ := readStake(payload.Validator)
+
+	switch payload.Opcode {
+	case sys.ReportDoubleSign:
+		a, b := payload.Evidence[0], payload.Evidence[1]
+
+		if !a.VerifySignature() || !b.VerifySignature() {
+			return errors.Wrap(ErrTxInvalidSignature, "slashing: evidence")
+		}
+
+		if a.Fee() != b.Fee() {
+			return errors.New("slashing: evidence pays different fees, such that one replaces the other")
+		}
+
+		if stake == 0 {
+			return errors.Errorf("slashing: %x has no stake to slash", payload.Validator)
+		}
+
+		events, _ := readSlashings(payload.Validator)
+
+		for _, event := range events {
+			if event.Opcode == sys.ReportDoubleSign && event.Nonce == a.Nonce {
+				return errors.Errorf(
+					"slashing: %x was already slashed for double-signing at nonce %d", payload.Validator, a.Nonce,
+				)
+			}
+		}
+	case sys.ReportDowntime:
+		if stake < sys.MinimumStake {
+			return errors.Errorf(
+				"slashing: %x stakes %d PERLs, but validators must stake at least %d PERLs",
+				payload.Validator, stake, sys.MinimumStake,
+			)
+		}
+
+		lastActive, exists := readLastActive(payload.Validator)
+		if !exists {
+			return errors.Errorf("slashing: %x was never seen active", payload.Validator)
+		}
+
+		if height <= lastActive+sys.SlashingDowntimeBlocks {
+			return errors.Errorf(
+				"slashing: %x was last active at block %d, less than %d blocks ago",
+				payload.Validator, lastActive, sys.SlashingDowntimeBlocks,
+			)
+		}
+	}
+
+	return nil
+}
+
+// markActiveValidators records the validators among the senders of txs as
+// having been active at the block at index height.
+func markActiveValidators(ctx *CollapseContext, txs []*Transaction, height uint64) {
+	seen := make(map[AccountID]struct{}, len(txs))
+	validators := make([]AccountID, 0, len(txs))
+
+	for _, tx := range txs {
+		if _, ok := seen[tx.Sender]; ok {
+			continue
+		}
+
+		seen[tx.Sender] = struct{}{}
+
+		if stake, _ := ctx.ReadAccountStake(tx.Sender); stake >= sys.MinimumStake {
+			validators = append(validators, tx.Sender)
+		}
+	}
+
+	// Validators are marked in order, such that their accounts are added to
+	// the context in the same order by all nodes.
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i][:], validators[j][:]) < 0
+	})
+
+	for _, id := range validators {
+		ctx.WriteAccountLastActive(id, height)
+	}
+}
+
+func applySlashingTransaction(ctx *CollapseContext, block *Block, tx *Transaction) error {
+	payload, err := ParseSlashing(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	height := block.Index + 1
+
+	if err := verifySlashing(
+		ctx.ReadAccountStake, ctx.ReadAccountLastActive, ctx.ReadAccountSlashings, height, tx, payload,
+	); err != nil {
+		return err
+	}
+
+	fraction := sys.SlashingDowntimeFraction
+	if payload.Opcode == sys.ReportDoubleSign {
+		fraction = sys.SlashingDoubleSignFraction
+	}
+
+	stake, _ := ctx.ReadAccountStake(payload.Validator)
+
+	slashed := mulDiv(stake, uint64(fraction), slashingFractionScale)
+	reward := mulDiv(slashed, uint64(sys.SlashingReporterShare), slashingFractionScale)
+
+	ctx.WriteAccountStake(payload.Validator, stake-slashed)
+
+	if reward > 0 {
+		balance, _ := ctx.ReadAccountBalance(tx.Sender)
+		ctx.WriteAccountBalance(tx.Sender, balance+reward)
+	}
+
+	event := SlashingEvent{
+		Opcode:   payload.Opcode,
+		Reporter: tx.Sender,
+		TxID:     tx.ID,
+		Amount:   slashed,
+		Burned:   slashed - reward,
+		Block:    height,
+	}
+
+	if payload.Opcode == sys.ReportDoubleSign {
+		event.Nonce = payload.Evidence[0].Nonce
+	} else {
+		// The downtime of the validator starts over, such that it is not
+		// slashed for the same downtime twice.
+		ctx.WriteAccountLastActive(payload.Validator, height)
+	}
+
+	events, _ := ctx.ReadAccountSlashings(payload.Validator)
+	ctx.WriteAccountSlashings(payload.Validator, append(append([]SlashingEvent(nil), events...), event))
+
+	return nil
+}
+
+func validateSlashingTransaction(snapshot *avl.Tree, tx Transaction) error {
+	payload, err := ParseSlashing(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	readStake := func(id AccountID) (uint64, bool) {
+		return ReadAccountStake(snapshot, id)
+	}
+
+	readLastActive := func(id AccountID) (uint64, bool) {
+		return ReadAccountLastActive(snapshot, id)
+	}
+
+	readSlashings := func(id AccountID) ([]SlashingEvent, bool) {
+		return ReadAccountSlashings(snapshot, id)
+	}
+
+	return verifySlashing(readStake, readLastActive, readSlashings, tx.earliestHeight(), &tx, payload)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+func TestSlashingTransaction(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureSlashing)()
+
+	keys := make([]*skademlia.Keypair, 2)
+
+	for i := range keys {
+		var err error
+
+		keys[i], err = skademlia.NewKeys(1, 1)
+		require.NoError(t, err)
+	}
+
+	validator, reporter := keys[0], keys[1]
+
+	tree := avl.New(store.NewInmem())
+
+	report := func(index uint64, slashing Slashing) Transaction {
+		payload, err := slashing.Marshal()
+		require.NoError(t, err)
+
+		return NewTransaction(reporter, 1, index, sys.TagSlashing, payload)
+	}
+
+	block := NewBlock(10, tree.Checksum())
+
+	a := NewTransaction(validator, 5, 3, sys.TagTransfer, []byte{1})
+	b := NewTransaction(validator, 5, 4, sys.TagTransfer, []byte{2})
+
+	doubleSign := Slashing{Opcode: sys.ReportDoubleSign, Evidence: [2]Transaction{a, b}}
+
+	// Only accounts with a stake may be slashed.
+	tx := report(10, doubleSign)
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	WriteAccountStake(tree, validator.PublicKey(), 10000)
+
+	// Transactions of the same nonce paying different fees are not
+	// conflicting, the one paying more replacing the other.
+	tipped, err := NewTippedTransaction(security.NewEd25519Signer(validator.PrivateKey()), 5, 3, sys.TagTransfer, []byte{1}, 1)
+	require.NoError(t, err)
+
+	tx = report(10, Slashing{Opcode: sys.ReportDoubleSign, Evidence: [2]Transaction{a, tipped}})
+	assert.Error(t, ValidateTransaction(tree, tx))
+
+	tx = report(10, doubleSign)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &block, &tx))
+
+	reported := tx.ID
+
+	// Of the 500 PERLs slashed, 10% are rewarded to the reporter.
+	stake, _ := ReadAccountStake(tree, validator.PublicKey())
+	assert.EqualValues(t, 9500, stake)
+
+	balance, _ := ReadAccountBalance(tree, reporter.PublicKey())
+	assert.EqualValues(t, 50, balance)
+
+	// Validators are slashed once per nonce they double-signed at.
+	tx = report(10, Slashing{Opcode: sys.ReportDoubleSign, Evidence: [2]Transaction{b, a}})
+	assert.Error(t, ValidateTransaction(tree, tx))
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	// Validators may only be slashed for downtime once inactive for long
+	// enough.
+	downtime := Slashing{Opcode: sys.ReportDowntime, Validator: validator.PublicKey()}
+
+	tx = report(10, downtime)
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	ctx := NewCollapseContext(tree)
+	markActiveValidators(ctx, []*Transaction{&a, &tx}, 11)
+	require.NoError(t, ctx.Flush())
+
+	lastActive, _ := ReadAccountLastActive(tree, validator.PublicKey())
+	assert.EqualValues(t, 11, lastActive)
+
+	_, active := ReadAccountLastActive(tree, reporter.PublicKey())
+	assert.False(t, active)
+
+	tx = report(10, downtime)
+	assert.Error(t, ApplyTransaction(tree, &block, &tx))
+
+	later := NewBlock(11+sys.SlashingDowntimeBlocks, tree.Checksum())
+
+	tx = report(later.Index, downtime)
+	require.NoError(t, ValidateTransaction(tree, tx))
+	require.NoError(t, ApplyTransaction(tree, &later, &tx))
+
+	stake, _ = ReadAccountStake(tree, validator.PublicKey())
+	assert.EqualValues(t, 9405, stake)
+
+	// The downtime of a validator is slashed for once.
+	assert.Error(t, ApplyTransaction(tree, &later, &tx))
+
+	events, _ := ReadAccountSlashings(tree, validator.PublicKey())
+	assert.Equal(t, []SlashingEvent{
+		{
+			Opcode: sys.ReportDoubleSign, Reporter: reporter.PublicKey(), TxID: reported,
+			Nonce: 5, Amount: 500, Burned: 450, Block: 11,
+		},
+		{
+			Opcode: sys.ReportDowntime, Reporter: reporter.PublicKey(), TxID: tx.ID,
+			Amount: 95, Burned: 86, Block: later.Index + 1,
+		},
+	}, events)
+}
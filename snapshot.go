@@ -0,0 +1,244 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+)
+
+// snapshotMagic prefixes snapshots, followed by the version of their format.
+var snapshotMagic = []byte("WAVESNAP") // nolint:gochecknoglobals
+
+const snapshotVersion = 1
+
+// ErrInvalidSnapshot is returned when reading a snapshot which is malformed,
+// whose checksum does not match its contents, or whose state does not match
+// the Merkle root of its block.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// WriteSnapshot writes a snapshot of the ledger state tree as of block to w.
+//
+// A snapshot is made of a magic prefix, the version of its format, and the
+// BLAKE2b-256 checksum of its body, followed by its body: the gzip-compressed
+// block, followed by all nodes of tree.
+func WriteSnapshot(w io.Writer, tree *avl.Tree, block Block) error {
+	if checksum := tree.Checksum(); checksum != block.Merkle {
+		return errors.Errorf("state has merkle root %x, but block %d has merkle root %x", checksum, block.Index, block.Merkle)
+	}
+
+	var body bytes.Buffer
+
+	gz := gzip.NewWriter(&body)
+
+	if _, err := gz.Write(block.Marshal()); err != nil {
+		return errors.Wrap(err, "failed to write block to snapshot")
+	}
+
+	if err := tree.Dump(gz); err != nil {
+		return errors.Wrap(err, "failed to write state to snapshot")
+	}
+
+	if err := gz.Close(); err != nil {
+		return errors.Wrap(err, "failed to compress snapshot")
+	}
+
+	checksum := blake2b.Sum256(body.Bytes())
+
+	header := make([]byte, 0, len(snapshotMagic)+1+len(checksum))
+	header = append(header, snapshotMagic...)
+	header = append(header, snapshotVersion)
+	header = append(header, checksum[:]...)
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	_, err := body.WriteTo(w)
+
+	return err
+}
+
+// ReadSnapshot reads a snapshot from r, rebuilding the ledger state tree it
+// holds into tree, and returns the block whose state it is. The checksum of
+// the snapshot and the Merkle root of its state are both verified.
+func ReadSnapshot(r io.Reader, tree *avl.Tree) (Block, error) {
+	header := make([]byte, len(snapshotMagic)+1+blake2b.Size256)
+
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Block{}, errors.Wrapf(ErrInvalidSnapshot, "failed to read header: %v", err)
+	}
+
+	if !bytes.Equal(header[:len(snapshotMagic)], snapshotMagic) {
+		return Block{}, errors.Wrap(ErrInvalidSnapshot, "not a snapshot")
+	}
+
+	if version := header[len(snapshotMagic)]; version != snapshotVersion {
+		return Block{}, errors.Wrapf(ErrInvalidSnapshot, "unsupported version %d", version)
+	}
+
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Block{}, errors.Wrap(err, "failed to read snapshot")
+	}
+
+	if checksum := blake2b.Sum256(body); !bytes.Equal(checksum[:], header[len(snapshotMagic)+1:]) {
+		return Block{}, errors.Wrap(ErrInvalidSnapshot, "checksum mismatch")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return Block{}, errors.Wrapf(ErrInvalidSnapshot, "failed to decompress: %v", err)
+	}
+
+	// The state is decompressed whole, as ApplyDump expects reads not to
+	// come up short.
+	contents, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return Block{}, errors.Wrapf(ErrInvalidSnapshot, "failed to decompress: %v", err)
+	}
+
+	buf := bytes.NewReader(contents)
+
+	block, err := UnmarshalBlock(buf)
+	if err != nil {
+		return Block{}, errors.Wrapf(ErrInvalidSnapshot, "%v", err)
+	}
+
+	if err := tree.ApplyDump(buf); err != nil {
+		return Block{}, errors.Wrapf(ErrInvalidSnapshot, "failed to rebuild state: %v", err)
+	}
+
+	if checksum := tree.Checksum(); checksum != block.Merkle {
+		return Block{}, errors.Wrapf(ErrInvalidSnapshot, "got merkle root %x but block %d has merkle root %x",
+			checksum, block.Index, block.Merkle)
+	}
+
+	return block, nil
+}
+
+// SnapshotHeight returns the index of the block whose state is stored in kv,
+// being the only block a snapshot may be exported at.
+func SnapshotHeight(kv store.KV) (uint64, error) {
+	_, block, err := loadCommittedState(kv)
+	if err != nil {
+		return 0, err
+	}
+
+	return block.Index, nil
+}
+
+// ExportSnapshot writes a snapshot of the ledger state stored in kv to w.
+// As only the latest state is kept, index must be the index of the block
+// SnapshotHeight returns.
+func ExportSnapshot(kv store.KV, index uint64, w io.Writer) (*Block, error) {
+	tree, block, err := loadCommittedState(kv)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Index != index {
+		return nil, errors.Errorf("state of block %d is not kept, only that of block %d", index, block.Index)
+	}
+
+	if err := WriteSnapshot(w, tree, *block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// ImportSnapshot restores the ledger state stored in kv from the snapshot
+// read from r, such that a node started on kv picks up from the block of the
+// snapshot. The state stored in kv must be of an earlier block, if any.
+func ImportSnapshot(kv store.KV, r io.Reader) (*Block, error) {
+	blocks, err := NewBlocks(kv, conf.GetPruningLimit())
+	if err != nil && errors.Cause(err) != store.ErrNotFound {
+		return nil, errors.Wrap(err, "error getting blocks from db")
+	}
+
+	empty := err != nil
+
+	accounts := NewAccounts(kv)
+	snapshot := accounts.Snapshot()
+
+	block, err := ReadSnapshot(r, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if !empty && blocks.LatestHeight() >= block.Index {
+		return nil, errors.Errorf("database already holds block %d, which is not older than block %d of the snapshot",
+			blocks.LatestHeight(), block.Index)
+	}
+
+	if _, err := blocks.Save(&block); err != nil {
+		return nil, errors.Wrap(err, "failed to save block of snapshot")
+	}
+
+	if err := accounts.Commit(snapshot); err != nil {
+		return nil, err
+	}
+
+	return &block, nil
+}
+
+// ExportSnapshot writes a snapshot of the latest state of the ledger to w,
+// returning the block it is the state of.
+func (l *Ledger) ExportSnapshot(w io.Writer) (*Block, error) {
+	snapshot := l.accounts.Snapshot()
+
+	block, err := l.blocks.committed(snapshot.Checksum())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := WriteSnapshot(w, snapshot, *block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// loadCommittedState loads the ledger state tree stored in kv, alongside the
+// block whose state it is.
+func loadCommittedState(kv store.KV) (*avl.Tree, *Block, error) {
+	blocks, err := NewBlocks(kv, conf.GetPruningLimit())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error getting blocks from db")
+	}
+
+	tree := NewAccounts(kv).Snapshot()
+
+	block, err := blocks.committed(tree.Checksum())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tree, block, nil
+}
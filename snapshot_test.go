@@ -0,0 +1,109 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	kv := store.NewInmem()
+
+	accounts := NewAccounts(kv)
+	snapshot := accounts.Snapshot()
+
+	for i := 0; i < 100; i++ {
+		WriteAccountBalance(snapshot, AccountID{byte(i)}, uint64(i))
+	}
+
+	WriteAccountStake(snapshot, AccountID{1}, 42)
+
+	if !assert.NoError(t, accounts.Commit(snapshot)) {
+		return
+	}
+
+	blocks, _ := NewBlocks(kv, conf.GetPruningLimit())
+
+	block := NewBlock(7, snapshot.Checksum())
+	if _, err := blocks.Save(&block); !assert.NoError(t, err) {
+		return
+	}
+
+	height, err := SnapshotHeight(kv)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, height)
+
+	// Only the state of the latest block is kept.
+	_, err = ExportSnapshot(kv, 6, new(bytes.Buffer))
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+
+	exported, err := ExportSnapshot(kv, 7, &buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, block.ID, exported.ID)
+
+	// A snapshot whose contents were tampered with is rejected.
+	tampered := append([]byte{}, buf.Bytes()...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	_, err = ImportSnapshot(store.NewInmem(), bytes.NewReader(tampered))
+	assert.Equal(t, ErrInvalidSnapshot, errors.Cause(err))
+
+	imported := store.NewInmem()
+
+	restored, err := ImportSnapshot(imported, bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, block.ID, restored.ID)
+
+	tree, latest, err := loadCommittedState(imported)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, block.ID, latest.ID)
+	assert.Equal(t, block.Merkle, tree.Checksum())
+
+	for i := 0; i < 100; i++ {
+		balance, _ := ReadAccountBalance(tree, AccountID{byte(i)})
+		assert.EqualValues(t, i, balance)
+	}
+
+	stake, _ := ReadAccountStake(tree, AccountID{1})
+	assert.EqualValues(t, 42, stake)
+
+	// A snapshot may not roll back the state of a database.
+	_, err = ImportSnapshot(imported, bytes.NewReader(buf.Bytes()))
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"math/bits"
+
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/sys"
+	"golang.org/x/crypto/blake2b"
+)
+
+// StampDifficulty returns the difficulty of the proof-of-work stamp of tx,
+// being the number of leading zero bits of the BLAKE2b-256 hash of the
+// message its signature signs. The message covering every field of tx
+// along with its stamp, a stamp may not be reused for another transaction.
+// Unstamped transactions, and transactions not of version
+// canonical.Version, have a difficulty of 0.
+func (tx Transaction) StampDifficulty() int {
+	if tx.Stamp == 0 || tx.Version != canonical.Version {
+		return 0
+	}
+
+	return leadingZeroBits(blake2b.Sum256(tx.Message()))
+}
+
+// FindStamp searches for the first stamp which brings tx to the given
+// proof-of-work difficulty, expecting to try 2^difficulty stamps.
+func FindStamp(tx Transaction, difficulty int) uint64 {
+	tx.Version = canonical.Version
+
+	for tx.Stamp = 1; ; tx.Stamp++ {
+		if tx.StampDifficulty() >= difficulty {
+			return tx.Stamp
+		}
+	}
+}
+
+// StampDifficultyForLoad returns the proof-of-work difficulty nodes require
+// of stamped transactions to admit them given the number of transactions
+// pending finalization, being sys.MinStampDifficulty plus one bit for every
+// doubling of the load past sys.StampDifficultyLoadStep.
+func StampDifficultyForLoad(pending int) int {
+	return sys.MinStampDifficulty + bits.Len(uint(pending/sys.StampDifficultyLoadStep))
+}
+
+func leadingZeroBits(hash [blake2b.Size256]byte) int {
+	n := 0
+
+	for _, b := range hash {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+
+		n += 8
+	}
+
+	return n
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"io"
+	"sync"
+
+	"github.com/djherbis/buffer"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/internal/filebuffer"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+)
+
+// chunkInfo locates a chunk within the buffer it was split out of.
+type chunkInfo struct {
+	offset int64
+	size   int
+}
+
+// splitChunks splits the contents of buf into chunks of at most chunkSize
+// bytes, returning the checksums of the chunks in order alongside where each
+// chunk lies in buf by its checksum.
+func splitChunks(buf buffer.BufferAt, chunkSize int) ([][]byte, map[[blake2b.Size256]byte]chunkInfo, error) {
+	var checksums [][]byte
+
+	chunks := make(map[[blake2b.Size256]byte]chunkInfo)
+	chunk := make([]byte, chunkSize)
+
+	for offset := int64(0); ; offset += int64(chunkSize) {
+		n, err := buf.ReadAt(chunk, offset)
+		if n > 0 {
+			checksum := blake2b.Sum256(chunk[:n])
+			checksums = append(checksums, checksum[:])
+
+			chunks[checksum] = chunkInfo{offset: offset, size: n}
+		}
+
+		if err == io.EOF || n < chunkSize {
+			return checksums, chunks, nil
+		} else if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// serveChunks responds to the requests of a syncing peer for chunks of buf by
+// their checksum, until the peer closes the stream. Requests for chunks not
+// found in buf are responded to with no chunk.
+func serveChunks(
+	stream Wavelet_SyncServer, buf buffer.BufferAt, readLock *sync.Mutex, chunks map[[blake2b.Size256]byte]chunkInfo,
+) error {
+	logger := log.Sync("provide_chunk")
+
+	res := &SyncResponse{Data: &SyncResponse_Chunk{}}
+	chunk := make([]byte, 0, conf.GetSyncChunkSize())
+
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		var checksum [blake2b.Size256]byte
+
+		copy(checksum[:], req.GetChecksum())
+
+		info, ok := chunks[checksum]
+		if !ok {
+			res.Data.(*SyncResponse_Chunk).Chunk = nil
+
+			if err = stream.Send(res); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if cap(chunk) < info.size {
+			chunk = make([]byte, info.size)
+		}
+
+		readLock.Lock()
+		_, err = buf.ReadAt(chunk[:info.size], info.offset)
+		readLock.Unlock()
+
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		logger.Info().
+			Hex("requested_hash", req.GetChecksum()).
+			Msg("Responded to sync chunk request.")
+
+		res.Data.(*SyncResponse_Chunk).Chunk = chunk[:info.size]
+
+		if err = stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+// stateChunks is a dump of all of the finalized state of the ledger as of a
+// block, split into chunks to serve to peers syncing their state from scratch.
+type stateChunks struct {
+	header *SyncInfo
+	root   MerkleNodeID
+
+	buf      buffer.BufferAt
+	readLock sync.Mutex
+	chunks   map[[blake2b.Size256]byte]chunkInfo
+
+	// refs counts the peers the chunks are being served to.
+	refs int
+}
+
+// stateChunksCache keeps the chunks of the latest finalized state of the
+// ledger by its Merkle root. Unlike the difference of the state since some
+// block, which is dumped anew for every peer, a dump of all of the state is
+// the same for every peer, and is thus shared by all peers syncing to the same
+// state at once. The dump is dropped once no peer is syncing to it, rather
+// than holding a copy of all of the state for the life of the node.
+type stateChunksCache struct {
+	sync.Mutex
+
+	pool   *filebuffer.Pool
+	latest *stateChunks
+}
+
+func newStateChunksCache(pool *filebuffer.Pool) *stateChunksCache {
+	return &stateChunksCache{pool: pool}
+}
+
+// acquire returns the chunks of tree, being the state of block, dumping and
+// splitting tree should they not already be kept. The chunks must be released
+// once served.
+func (c *stateChunksCache) acquire(tree *avl.Tree, block Block) (*stateChunks, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.latest != nil && c.latest.root == block.Merkle {
+		c.latest.refs++
+		return c.latest, nil
+	}
+
+	dump := c.pool.GetUnbounded()
+	defer c.pool.Put(dump)
+
+	if err := tree.Dump(dump); err != nil {
+		return nil, errors.Wrap(err, "failed to dump state")
+	}
+
+	buf, err := c.pool.GetBounded(dump.Len())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.Copy(buf, dump); err != nil {
+		c.pool.Put(buf)
+		return nil, err
+	}
+
+	checksums, chunks, err := splitChunks(buf, conf.GetSyncChunkSize())
+	if err != nil {
+		c.pool.Put(buf)
+		return nil, err
+	}
+
+	previous := c.latest
+
+	c.latest = &stateChunks{
+		header: &SyncInfo{Block: block.Marshal(), Checksums: checksums},
+		root:   block.Merkle,
+		buf:    buf,
+		chunks: chunks,
+		refs:   1,
+	}
+
+	if previous != nil {
+		c.evict(previous)
+	}
+
+	return c.latest, nil
+}
+
+// release marks the chunks as no longer being served to a peer.
+func (c *stateChunksCache) release(s *stateChunks) {
+	c.Lock()
+	defer c.Unlock()
+
+	s.refs--
+	c.evict(s)
+}
+
+// evict puts the buffer of the chunks back into the pool, should they no
+// longer be served to any peer.
+func (c *stateChunksCache) evict(s *stateChunks) {
+	if s.refs > 0 {
+		return
+	}
+
+	if s == c.latest {
+		c.latest = nil
+	}
+
+	c.pool.Put(s.buf)
+}
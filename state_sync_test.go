@@ -0,0 +1,198 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package wavelet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/perlin-network/wavelet/conf"
+	"github.com/perlin-network/wavelet/internal/filebuffer"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestStateChunks(t *testing.T) {
+	defer conf.Update(conf.WithSyncChunkSize(conf.GetSyncChunkSize()))
+	conf.Update(conf.WithSyncChunkSize(256))
+
+	accounts := NewAccounts(store.NewInmem())
+	snapshot := accounts.Snapshot()
+
+	for i := 0; i < 100; i++ {
+		WriteAccountBalance(snapshot, AccountID{byte(i)}, uint64(i))
+	}
+
+	if !assert.NoError(t, accounts.Commit(snapshot)) {
+		return
+	}
+
+	block := NewBlock(7, snapshot.Checksum())
+
+	cache := newStateChunksCache(filebuffer.NewPool(sys.SyncPooledFileSize, ""))
+
+	state, err := cache.acquire(snapshot, block)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, len(state.header.Checksums) > 1)
+
+	// Peers syncing to the same state are served the same chunks.
+	same, err := cache.acquire(accounts.Snapshot(), block)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, same == state)
+	assert.Equal(t, 2, state.refs)
+
+	cache.release(same)
+
+	// The chunks, reassembled in order, rebuild the state from scratch.
+	var dump bytes.Buffer
+
+	for _, checksum := range state.header.Checksums {
+		var key [blake2b.Size256]byte
+		copy(key[:], checksum)
+
+		info, ok := state.chunks[key]
+		if !assert.True(t, ok) {
+			return
+		}
+
+		chunk := make([]byte, info.size)
+		_, err := state.buf.ReadAt(chunk, info.offset)
+		assert.NoError(t, err)
+
+		assert.Equal(t, key, blake2b.Sum256(chunk))
+
+		dump.Write(chunk)
+	}
+
+	rebuilt := NewAccounts(store.NewInmem()).Snapshot()
+
+	if !assert.NoError(t, rebuilt.ApplyDump(&dump)) {
+		return
+	}
+
+	assert.Equal(t, block.Merkle, rebuilt.Checksum())
+
+	balance, _ := ReadAccountBalance(rebuilt, AccountID{42})
+	assert.EqualValues(t, 42, balance)
+
+	// A newer state replaces the chunks kept.
+	WriteAccountBalance(snapshot, AccountID{1}, 1000)
+
+	newer := NewBlock(8, snapshot.Checksum())
+
+	latest, err := cache.acquire(snapshot, newer)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, latest == state)
+	assert.Equal(t, newer.Merkle, latest.root)
+
+	cache.release(state)
+	assert.True(t, cache.latest == latest)
+
+	// The chunks are dropped once no peer is syncing to them.
+	cache.release(latest)
+	assert.Nil(t, cache.latest)
+}
+
+func TestSyncRequestFullState(t *testing.T) {
+	buf, err := (&SyncRequest{Data: &SyncRequest_FullState{FullState: true}}).Marshal()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var req SyncRequest
+
+	if !assert.NoError(t, req.Unmarshal(buf)) {
+		return
+	}
+
+	assert.True(t, req.GetFullState())
+	assert.EqualValues(t, 0, req.GetBlockId())
+}
+
+func TestOutOfSyncResponseSnapshot(t *testing.T) {
+	id := BlockID{1, 2, 3}
+
+	buf, err := (&OutOfSyncResponse{OutOfSync: true, SnapshotIndex: 42, SnapshotBlockId: id[:]}).Marshal()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var res OutOfSyncResponse
+
+	if !assert.NoError(t, res.Unmarshal(buf)) {
+		return
+	}
+
+	assert.True(t, res.GetOutOfSync())
+	assert.EqualValues(t, 42, res.GetSnapshotIndex())
+	assert.Equal(t, id[:], res.GetSnapshotBlockId())
+
+	// Older peers advertise no snapshot.
+	res = OutOfSyncResponse{}
+
+	if !assert.NoError(t, res.Unmarshal([]byte{0x8, 0x1})) {
+		return
+	}
+
+	assert.True(t, res.GetOutOfSync())
+	assert.Empty(t, res.GetSnapshotBlockId())
+}
+
+func TestPreferredSnapshot(t *testing.T) {
+	a := snapshotAd{index: 10, id: BlockID{10}}
+	b := snapshotAd{index: 11, id: BlockID{11}}
+	c := snapshotAd{index: 11, id: BlockID{12}}
+
+	snapshots := map[AccountID]snapshotAd{
+		{1}: a,
+		{2}: b,
+		{3}: b,
+		{4}: c,
+		{5}: a,
+	}
+
+	// The snapshot advertised by the most peers is preferred, regardless of
+	// peers which advertise none.
+	snapshot, advertisers := preferredSnapshot([]AccountID{{1}, {2}, {3}, {4}, {6}}, snapshots)
+	assert.Equal(t, b, snapshot)
+	assert.Equal(t, []AccountID{{2}, {3}}, advertisers)
+
+	// The latest snapshot is preferred should several be advertised by as
+	// many peers.
+	snapshot, advertisers = preferredSnapshot([]AccountID{{1}, {2}, {5}, {3}}, snapshots)
+	assert.Equal(t, b, snapshot)
+	assert.Equal(t, []AccountID{{2}, {3}}, advertisers)
+
+	_, advertisers = preferredSnapshot([]AccountID{{6}}, snapshots)
+	assert.Empty(t, advertisers)
+}
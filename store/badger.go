@@ -164,6 +164,20 @@ func (b *badgerKV) CommitWriteBatch(batch WriteBatch) error {
 	return wb.batch.Flush()
 }
 
+// Compact flattens the LSM tree of the database, and garbage collects its
+// value log.
+func (b *badgerKV) Compact() error {
+	if err := b.db.Flatten(1); err != nil {
+		return err
+	}
+
+	// When there's no more GC, an error will be returned.
+	for b.db.RunValueLogGC(0.05) == nil {
+	}
+
+	return nil
+}
+
 func (b *badgerKV) gc(interval time.Duration) {
 	b.closeWg.Add(1)
 
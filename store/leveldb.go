@@ -25,6 +25,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 var _ WriteBatch = (*leveldbWriteBatch)(nil)
@@ -117,6 +118,11 @@ func (l *leveldbKV) Delete(key []byte) error {
 	return l.db.Delete(key, nil)
 }
 
+// Compact compacts the whole key range of the database.
+func (l *leveldbKV) Compact() error {
+	return l.db.CompactRange(util.Range{})
+}
+
 func NewLevelDB(dir string) (*leveldbKV, error) { // nolint:golint
 	opts := &opt.Options{
 		Filter:       filter.NewBloomFilter(10),
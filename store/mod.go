@@ -44,6 +44,12 @@ type KV interface {
 	Dir() string
 }
 
+// Compactor is implemented by stores which may compact their underlying
+// files, reclaiming the space of deleted keys.
+type Compactor interface {
+	Compact() error
+}
+
 // WriteBatch batches a collection of put operations in memory before
 // it's committed to disk.
 //
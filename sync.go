@@ -14,6 +14,7 @@ import (
 	"github.com/rs/zerolog"
 	"go.uber.org/atomic"
 	"golang.org/x/crypto/blake2b"
+	"google.golang.org/grpc/connectivity"
 	"io"
 	"math/rand"
 	"sync"
@@ -27,16 +28,31 @@ type SyncManager struct {
 
 	filePool *filebuffer.Pool
 
+	reputation *Reputation
+
 	logger zerolog.Logger
 	exit   chan struct{}
 	exited atomic.Bool
 
+	// reconciled is whether peers have yet told our node whether it is out
+	// of sync, syncing whether it is syncing its state from them, and target
+	// the index of the latest block of the state being synced to, or 0
+	// should it not yet be known.
+	reconciled atomic.Bool
+	syncing    atomic.Bool
+	target     atomic.Uint64
+
+	// snapshots are the snapshots of their latest state peers advertised
+	// serving in full while telling our node whether it is out of sync.
+	snapshots     map[AccountID]snapshotAd
+	snapshotsLock sync.Mutex
+
 	OnStateReconciled []func(outOfSync bool)
 	OnSynced          []func(block Block)
 }
 
 func NewSyncManager(
-	client *skademlia.Client, accounts *Accounts, blocks *Blocks, filePool *filebuffer.Pool,
+	client *skademlia.Client, accounts *Accounts, blocks *Blocks, filePool *filebuffer.Pool, reputation *Reputation,
 ) *SyncManager {
 	return &SyncManager{
 		client:   client,
@@ -45,6 +61,10 @@ func NewSyncManager(
 
 		filePool: filePool,
 
+		reputation: reputation,
+
+		snapshots: make(map[AccountID]snapshotAd),
+
 		logger: log.Sync("sync"),
 		exit:   make(chan struct{}),
 	}
@@ -70,6 +90,9 @@ func (s *SyncManager) Start() {
 				continue
 			}
 
+			s.reconciled.Store(true)
+			s.syncing.Store(outOfSync)
+
 			for _, fn := range s.OnStateReconciled {
 				fn(outOfSync)
 			}
@@ -109,12 +132,53 @@ func (s *SyncManager) Start() {
 		for _, fn := range s.OnSynced {
 			fn(block)
 		}
+
+		s.syncing.Store(false)
+		s.target.Store(0)
+	}
+}
+
+// SyncStatus reports on whether our node is in sync with its peers.
+type SyncStatus struct {
+	// Reconciled is whether peers have yet told our node whether it is out
+	// of sync.
+	Reconciled bool
+
+	// Syncing is whether our node is syncing its state from its peers, for
+	// it being out of sync with them.
+	Syncing bool
+
+	// Lag is the number of blocks our node is behind its peers. While the
+	// latest block of its peers has yet to be known, it is the number of
+	// blocks past which nodes sync, which it is behind at least by.
+	Lag uint64
+}
+
+// Status reports on whether our node is in sync with its peers.
+func (s *SyncManager) Status() SyncStatus {
+	status := SyncStatus{Reconciled: s.reconciled.Load(), Syncing: s.syncing.Load()}
+
+	if !status.Syncing {
+		return status
 	}
+
+	status.Lag = conf.GetSyncIfBlockIndicesDifferBy()
+
+	if target, latest := s.target.Load(), s.blocks.LatestHeight(); target > latest {
+		status.Lag = target - latest
+	}
+
+	return status
 }
 
 func (s *SyncManager) sync(b *backoff.Backoff) (Block, error) {
 	b.Reset()
 
+	// New nodes sync all of the latest state, rather than the difference
+	// since the genesis block, such that they may be served chunks shared
+	// with all other new nodes.
+	full := s.blocks.LatestHeight() == 0
+
 	var (
 		peers []syncPeer
 
@@ -126,7 +190,7 @@ func (s *SyncManager) sync(b *backoff.Backoff) (Block, error) {
 	)
 
 	for {
-		peers, err = s.findPeersToDownloadStateFrom(conf.GetSnowballK())
+		peers, err = s.findPeersToDownloadStateFrom(conf.GetSnowballK(), full)
 		if err != nil {
 			s.wait(b.Duration())
 
@@ -149,6 +213,8 @@ func (s *SyncManager) sync(b *backoff.Backoff) (Block, error) {
 			continue
 		}
 
+		s.target.Store(block.Index)
+
 		break
 	}
 
@@ -170,7 +236,7 @@ func (s *SyncManager) sync(b *backoff.Backoff) (Block, error) {
 
 	// TODO(kenta): make number of attempts to download chunked state configurable
 	for i := 0; i < 3; i++ {
-		if err = s.downloadStateInChunks(checksums, streams, chunksBuffer, diffBuffer); err != nil {
+		if err = s.downloadStateInChunks(peers, checksums, streams, chunksBuffer, diffBuffer); err != nil {
 			s.wait(b.Duration())
 
 			if s.closed() {
@@ -191,7 +257,13 @@ func (s *SyncManager) sync(b *backoff.Backoff) (Block, error) {
 
 	snapshot := s.accounts.Snapshot()
 
-	if err := snapshot.ApplyDiff(diffBuffer); err != nil {
+	if full {
+		err = snapshot.ApplyDump(diffBuffer)
+	} else {
+		err = snapshot.ApplyDiff(diffBuffer)
+	}
+
+	if err != nil {
 		return block, err
 	}
 
@@ -209,6 +281,7 @@ func (s *SyncManager) sync(b *backoff.Backoff) (Block, error) {
 
 	s.logger.Info().
 		Int("num_chunks", len(checksums)).
+		Bool("full_state", full).
 		Uint64("new_block_height", block.Index).
 		Hex("new_block_id", block.ID[:]).
 		Hex("new_merkle_root", block.Merkle[:]).
@@ -222,6 +295,11 @@ func (s *SyncManager) sync(b *backoff.Backoff) (Block, error) {
 func (s *SyncManager) stateOutOfSync() (bool, error) {
 	samplerK := conf.GetSnowballK()
 
+	// Only snapshots advertised since we last asked are current.
+	s.snapshotsLock.Lock()
+	s.snapshots = make(map[AccountID]snapshotAd)
+	s.snapshotsLock.Unlock()
+
 	// Our initial belief is that we're not out-of-sync.
 	sampler := NewSnowball()
 	sampler.Prefer(&syncVote{outOfSync: false})
@@ -258,6 +336,15 @@ func (s *SyncManager) askIfWereOutOfSync(ctx context.Context, peer skademlia.Clo
 		return false, errors.Wrap(err, "failed to ask peer if they believe we are out-of-sync")
 	}
 
+	if len(res.SnapshotBlockId) == SizeBlockID {
+		ad := snapshotAd{index: res.SnapshotIndex}
+		copy(ad.id[:], res.SnapshotBlockId)
+
+		s.snapshotsLock.Lock()
+		s.snapshots[peer.ID().PublicKey()] = ad
+		s.snapshotsLock.Unlock()
+	}
+
 	return res.OutOfSync, nil
 }
 
@@ -265,7 +352,7 @@ func (s *SyncManager) askIfWereOutOfSync(ctx context.Context, peer skademlia.Clo
 func (s *SyncManager) collectVotesFromPeers(sampler *Snowball, votes chan<- Vote, samplerK int) (bool, error) {
 	latestHeight := s.blocks.LatestHeight()
 
-	peers, err := SelectPeers(s.client.ClosestPeers(), samplerK)
+	peers, err := SelectPeers(s.reputation.Filter(s.client.ClosestPeers()), samplerK)
 	if err != nil {
 		s.logger.Warn().Msg("It looks like there are no peers for us to sync with. Retrying after 1 second...")
 		s.wait(1 * time.Second)
@@ -341,12 +428,107 @@ type syncPeer struct {
 	checksums [][blake2b.Size256]byte
 }
 
-// Find and establish sessions with a fixed number of peers to download the latest state from.
-func (s *SyncManager) findPeersToDownloadStateFrom(numPeers int) ([]syncPeer, error) {
+// key identifies the state reported by the peer, being its latest block and
+// the checksums of the chunks of its state.
+func (p syncPeer) key() []byte {
+	key := append([]byte{}, p.block.ID[:]...)
+	for _, checksum := range p.checksums {
+		key = append(key, checksum[:]...)
+	}
+
+	return key
+}
+
+// snapshotAd is a snapshot of its latest state a peer advertised serving in
+// full, being the index and ID of its block.
+type snapshotAd struct {
+	index uint64
+	id    BlockID
+}
+
+// preferredSnapshot returns the snapshot advertised by the most of the peers
+// ids, the latest one should several be advertised by as many, alongside the
+// peers which advertised it.
+func preferredSnapshot(ids []AccountID, snapshots map[AccountID]snapshotAd) (snapshotAd, []AccountID) {
+	advertisers := make(map[snapshotAd][]AccountID)
+
+	var preferred snapshotAd
+
+	for _, id := range ids {
+		ad, exists := snapshots[id]
+		if !exists {
+			continue
+		}
+
+		advertisers[ad] = append(advertisers[ad], id)
+
+		count, most := len(advertisers[ad]), len(advertisers[preferred])
+		if count > most || (count == most && ad.index > preferred.index) {
+			preferred = ad
+		}
+	}
+
+	return preferred, advertisers[preferred]
+}
+
+// snapshotPeers narrows down peers to those which advertised serving the
+// snapshot advertised by the most of them, should at least amount of them we
+// are connected to have. Otherwise, as older peers advertise no snapshot,
+// peers are returned as they are.
+func (s *SyncManager) snapshotPeers(peers []skademlia.ClosestPeer, amount int) []skademlia.ClosestPeer {
+	ids := make([]AccountID, 0, len(peers))
+
+	for _, p := range peers {
+		if p.Conn().GetState() == connectivity.Ready {
+			ids = append(ids, p.ID().PublicKey())
+		}
+	}
+
+	s.snapshotsLock.Lock()
+	snapshot, advertisers := preferredSnapshot(ids, s.snapshots)
+	s.snapshotsLock.Unlock()
+
+	if len(advertisers) < amount {
+		return peers
+	}
+
+	advertised := make(map[AccountID]struct{}, len(advertisers))
+	for _, id := range advertisers {
+		advertised[id] = struct{}{}
+	}
+
+	filtered := peers[:0:0]
+
+	for _, p := range peers {
+		if _, exists := advertised[p.ID().PublicKey()]; exists {
+			filtered = append(filtered, p)
+		}
+	}
+
+	s.logger.Debug().
+		Uint64("block_index", snapshot.index).
+		Hex("block_id", snapshot.id[:]).
+		Int("num_peers", len(filtered)).
+		Msg("Syncing all of the latest state from the peers advertising the same snapshot.")
+
+	return filtered
+}
+
+// Find and establish sessions with a fixed number of peers to download the latest state from, being either
+// all of the state or the difference since our latest block.
+func (s *SyncManager) findPeersToDownloadStateFrom(numPeers int, full bool) ([]syncPeer, error) {
 	sessions := make([]syncPeer, 0, numPeers)
 	sessionsLock := sync.Mutex{}
 
-	peers, err := SelectPeers(s.client.ClosestPeers(), numPeers)
+	candidates := s.reputation.Filter(s.client.ClosestPeers())
+
+	// New nodes sync from the peers serving the same snapshot, such that
+	// the majority of them agree on the state to sync to.
+	if full {
+		candidates = s.snapshotPeers(candidates, numPeers)
+	}
+
+	peers, err := SelectPeers(candidates, numPeers)
 	if err != nil {
 		s.logger.Warn().
 			Msg("It looks like there are no peers for us to download state from. Retrying after 1 second...")
@@ -362,6 +544,10 @@ func (s *SyncManager) findPeersToDownloadStateFrom(numPeers int) ([]syncPeer, er
 	height := s.blocks.LatestHeight()
 	req := &SyncRequest{Data: &SyncRequest_BlockId{BlockId: height}}
 
+	if full {
+		req.Data = &SyncRequest_FullState{FullState: true}
+	}
+
 	var wg sync.WaitGroup
 
 	wg.Add(len(peers))
@@ -459,10 +645,7 @@ func (s *SyncManager) collateLatestStateDetails(peers []syncPeer) (
 	clients := make(map[string][]Wavelet_SyncClient)
 
 	for _, peer := range peers {
-		key := peer.block.ID[:]
-		for _, checksum := range peer.checksums {
-			key = append(key, checksum[:]...)
-		}
+		key := peer.key()
 
 		clients[string(key)] = append(clients[string(key)], peer.stream)
 		counts[string(key)]++
@@ -474,10 +657,7 @@ func (s *SyncManager) collateLatestStateDetails(peers []syncPeer) (
 		}
 	}
 
-	key := block.ID[:]
-	for _, checksum := range checksums {
-		key = append(key, checksum[:]...)
-	}
+	key := max
 
 	if counts[string(key)] < 2*len(peers)/3 {
 		return block, checksums, streams, errors.Errorf(
@@ -487,10 +667,18 @@ func (s *SyncManager) collateLatestStateDetails(peers []syncPeer) (
 		)
 	}
 
+	// Penalize peers which are not on the same state as the majority.
+	for _, peer := range peers {
+		if !bytes.Equal(peer.key(), key) {
+			s.reputation.Report(peer.peer.ID(), ViolationStaleState)
+		}
+	}
+
 	return block, checksums, clients[string(key)], nil
 }
 
 func (s *SyncManager) downloadStateInChunks(
+	peers []syncPeer,
 	checksums [][blake2b.Size256]byte,
 	streams []Wavelet_SyncClient,
 	chunksBuffer buffer.BufferAt,
@@ -498,6 +686,11 @@ func (s *SyncManager) downloadStateInChunks(
 ) error {
 	mutices := make(map[Wavelet_SyncClient]*sync.Mutex)
 
+	owners := make(map[Wavelet_SyncClient]*skademlia.ID, len(peers))
+	for _, peer := range peers {
+		owners[peer.stream] = peer.peer.ID()
+	}
+
 	var (
 		muticesLock sync.Mutex
 		chunkLock   sync.Mutex
@@ -532,6 +725,11 @@ func (s *SyncManager) downloadStateInChunks(
 				chunk, err := s.downloadStateChunk(checksum, stream)
 				if err != nil {
 					mutex.Unlock()
+
+					if errors.Cause(err) == errInvalidChunk {
+						s.reputation.Report(owners[stream], ViolationInvalidChunk)
+					}
+
 					continue
 				}
 				mutex.Unlock()
@@ -565,6 +763,9 @@ func (s *SyncManager) downloadStateInChunks(
 	return nil
 }
 
+// errInvalidChunk is returned when a peer serves a chunk which does not match the checksum it was requested by.
+var errInvalidChunk = errors.New("invalid chunk")
+
 func (s *SyncManager) downloadStateChunk(checksum [blake2b.Size256]byte, stream Wavelet_SyncClient) ([]byte, error) {
 	req := &SyncRequest{Data: &SyncRequest_Checksum{Checksum: checksum[:]}}
 
@@ -583,7 +784,8 @@ func (s *SyncManager) downloadStateChunk(checksum [blake2b.Size256]byte, stream
 	}
 
 	if len(chunk) > conf.GetSyncChunkSize() {
-		return nil, errors.Errorf(
+		return nil, errors.Wrapf(
+			errInvalidChunk,
 			"got chunk of size %d but chunk can be no larger than %d bytes",
 			len(chunk),
 			conf.GetSyncChunkSize(),
@@ -593,7 +795,8 @@ func (s *SyncManager) downloadStateChunk(checksum [blake2b.Size256]byte, stream
 	recovered := blake2b.Sum256(chunk)
 
 	if recovered != checksum {
-		return nil, errors.Errorf(
+		return nil, errors.Wrapf(
+			errInvalidChunk,
 			"chunk downloaded was hashed to %x, but was trying to download chunk with a has of %x",
 			recovered,
 			checksum,
@@ -29,6 +29,15 @@ const (
 	TagContract
 	TagStake
 	TagBatch
+	TagBeacon
+	TagFeeGrant
+	TagRecovery
+	TagName
+	TagData
+	TagUpgrade
+	TagMultisig
+	TagDelegation
+	TagSlashing
 )
 
 const (
@@ -37,6 +46,45 @@ const (
 	WithdrawReward
 )
 
+const (
+	ConfigureRecovery byte = iota
+	InitiateRecovery
+	ApproveRecovery
+	CancelRecovery
+	CompleteRecovery
+)
+
+const (
+	RegisterName byte = iota
+	RenewName
+	TransferName
+)
+
+const (
+	ConfigureUpgrader byte = iota
+	UpgradeContract
+)
+
+const (
+	CreateMultisig byte = iota
+	ProposeMultisig
+	ApproveMultisig
+	ExecuteMultisig
+	CancelMultisig
+)
+
+const (
+	DelegateStake byte = iota
+	UndelegateStake
+	ClaimUnbonded
+	SetCommission
+)
+
+const (
+	ReportDoubleSign byte = iota
+	ReportDowntime
+)
+
 const (
 	// Size of individual chunks sent for a syncing peer.
 	SyncChunkSize = 16 * 1024 // 64KB
@@ -63,9 +111,87 @@ var (
 
 	RewardWithdrawalsBlockLimit = 50
 
+	// BeaconRetention Number of blocks the randomness beacon values of are kept in the ledger state. It exceeds the
+	// largest pruning limit of pending transactions, such that beacon transactions may be applied for as long as they
+	// are kept pending.
+	BeaconRetention uint64 = 256
+
+	// SampleDraws Number of times as many validators as there are peers to query about a block are drawn to sample
+	// them from. Queries go to the first of the validators drawn which are reachable, such that finalization does not
+	// stall on a few unreachable validators being drawn.
+	SampleDraws = 2
+
+	// MaxRecoveryGuardians Maximum number of guardians an account may configure to recover it.
+	MaxRecoveryGuardians = 16
+
+	// MaxMultisigParticipants Maximum number of participants of a multisig account.
+	MaxMultisigParticipants = 16
+
+	// MaxMultisigProposals Maximum number of transfers which may be pending approval from a multisig account at once.
+	MaxMultisigProposals = 16
+
+	// MaxDelegators Maximum number of accounts which may delegate PERLs to a single validator.
+	MaxDelegators = 64
+
+	// MaxDelegations Maximum number of validators a single account may delegate PERLs to.
+	MaxDelegations = 16
+
+	// MaxUnbondings Maximum number of undelegated amounts of PERLs an account may have unbonding at once.
+	MaxUnbondings = 16
+
+	// DelegationUnbondingBlocks Number of blocks PERLs undelegated from a validator stay bonded for before they
+	// may be claimed back.
+	DelegationUnbondingBlocks uint64 = 1000
+
+	// MaxCommission Highest commission a validator may charge on the rewards of its delegators, commissions being
+	// expressed in hundredths of a percent.
+	MaxCommission uint16 = 10000
+
+	// SlashingDoubleSignFraction Fraction of its stake slashed from a validator which signed two conflicting
+	// transactions, in hundredths of a percent.
+	SlashingDoubleSignFraction uint16 = 500
+
+	// SlashingDowntimeFraction Fraction of its stake slashed from a validator which has had no transaction applied
+	// for SlashingDowntimeBlocks blocks, in hundredths of a percent.
+	SlashingDowntimeFraction uint16 = 100
+
+	// SlashingDowntimeBlocks Number of blocks a validator may go without having a transaction applied before it may
+	// be slashed for downtime.
+	SlashingDowntimeBlocks uint64 = 10000
+
+	// SlashingReporterShare Share of the PERLs slashed from a validator rewarded to the reporter of its misbehavior,
+	// in hundredths of a percent. The rest are burned.
+	SlashingReporterShare uint16 = 1000
+
+	// MinNameLength and MaxNameLength Bounds of the length of names in the name service.
+	MinNameLength = 3
+	MaxNameLength = 32
+
+	// NameRegistrationFee Amount of PERLs burned to register or renew a name.
+	NameRegistrationFee uint64 = 100
+
+	// NameRegistrationBlocks Number of blocks a name stays registered for after registering or renewing it.
+	NameRegistrationBlocks uint64 = 1000000
+
+	// MaxDataSize Maximum number of bytes a data transaction may anchor.
+	MaxDataSize = 1024
+
+	// DataFeePerByte Fee paid for every byte anchored by a data transaction, on top of the default fee.
+	DataFeePerByte uint64 = 1
+
+	// MinStampDifficulty Minimum number of leading zero bits the proof-of-work stamp of a transaction
+	// must have for the transaction to pay no fee.
+	MinStampDifficulty = 16
+
+	// StampDifficultyLoadStep Number of pending transactions past which nodes require stamps of one more
+	// bit of difficulty to admit them, doubling every step.
+	StampDifficultyLoadStep = 1024
+
 	FaucetAddress = "0f569c84d434fb0ca682c733176f7c0c2d853fce04d95ae131d2f9b4124d93d8"
 
-	GasTable = map[string]uint64{ // nolint:unused
+	// GasTable Costs of the second version of the gas schedule, of instructions by their WebAssembly name, and of
+	// functions of the ledger by their name prefixed with "wavelet.".
+	GasTable = map[string]uint64{
 		"nop":                     1,
 		"unreachable":             1,
 		"select":                  12,
@@ -243,13 +369,49 @@ var (
 		"wavelet.hash.sha256":     2500, // TODO: Review
 		"wavelet.hash.sha512":     3000, // TODO: Review
 		"wavelet.verify.ed25519":  5000, // TODO: Review
+
+		// Hashing and verifying signatures is linear in the size of the data.
+		"wavelet.hash.blake2b256.byte": 3,
+		"wavelet.hash.blake2b512.byte": 3,
+		"wavelet.hash.sha256.byte":     5,
+		"wavelet.hash.sha512.byte":     4,
+		"wavelet.verify.ed25519.byte":  5,
+
+		// Groth16 verification costs a fixed four pairings, plus a G1 scalar
+		// multiplication for every public input.
+		"wavelet.verify.groth16":       400000,
+		"wavelet.verify.groth16.input": 15000,
+
+		"wavelet.randomness": 500,
+
+		// Calls to other contracts are charged on top of the gas the callee spends.
+		"wavelet.call": 2000,
+
+		// Storage is kept by every node, and so writes are charged for by the byte.
+		"wavelet.storage.read":       200,
+		"wavelet.storage.read.byte":  1,
+		"wavelet.storage.write":      5000,
+		"wavelet.storage.write.byte": 20,
+
+		// Logs are kept by every node, and so are charged for by the byte.
+		"wavelet.log":      1000,
+		"wavelet.log.byte": 10,
 	}
 
 	TagLabels = map[string]Tag{
-		`transfer`: TagTransfer,
-		`contract`: TagContract,
-		`batch`:    TagBatch,
-		`stake`:    TagStake,
+		`transfer`:   TagTransfer,
+		`contract`:   TagContract,
+		`batch`:      TagBatch,
+		`stake`:      TagStake,
+		`beacon`:     TagBeacon,
+		`fee_grant`:  TagFeeGrant,
+		`recovery`:   TagRecovery,
+		`name`:       TagName,
+		`data`:       TagData,
+		`upgrade`:    TagUpgrade,
+		`multisig`:   TagMultisig,
+		`delegation`: TagDelegation,
+		`slashing`:   TagSlashing,
 	}
 
 	ContractDefaultMemoryPages = 4
@@ -258,6 +420,23 @@ var (
 	ContractMaxValueSlots      = 8192
 	ContractMaxCallStackDepth  = 256
 	ContractMaxGlobals         = 64
+
+	// ContractMaxCodeSize bounding the size of the code of a smart contract, in bytes.
+	ContractMaxCodeSize = 1 << 20
+
+	// ContractMaxCallDepth bounding the number of calls through _call_contract a call may be nested in.
+	ContractMaxCallDepth = 8
+
+	// Bounds of the keys and values of the storage of a smart contract, set through _storage_set.
+	ContractMaxStorageKeySize   = 64
+	ContractMaxStorageValueSize = 4096
+
+	// Bounds of the logs a smart contract may emit through _emit_log, with
+	// ContractMaxLogs bounding the number of logs emitted by a single
+	// function call.
+	ContractMaxLogTopicSize = 32
+	ContractMaxLogDataSize  = 1024
+	ContractMaxLogs         = 64
 )
 
 func init() { // nolint:gochecknoinits
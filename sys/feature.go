@@ -0,0 +1,175 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sys
+
+import (
+	"math"
+	"sort"
+)
+
+// Feature is a change to the protocol, such as a new transaction tag, fee rule or VM change, which activates
+// network-wide at a coordinated block height. Nodes must agree on when every feature activates, lest they disagree
+// on the outcome of the blocks they finalize.
+type Feature string
+
+// Features of the protocol which may be scheduled to activate.
+const (
+	FeatureFeeGrants Feature = "fee_grants"
+	FeatureRecovery  Feature = "recovery"
+	FeatureNames     Feature = "names"
+	FeatureData      Feature = "data"
+
+	// FeatureContractCalls lets smart contracts call one another through _call_contract.
+	FeatureContractCalls Feature = "contract_calls"
+
+	// FeatureContractStorage lets smart contracts keep key-value storage through _storage_get and _storage_set.
+	FeatureContractStorage Feature = "contract_storage"
+
+	// FeatureContractUpgrades lets the upgrader of a smart contract replace its code through upgrade transactions.
+	FeatureContractUpgrades Feature = "contract_upgrades"
+
+	// FeatureDeterministicContracts rejects smart contracts whose code may execute differently across platforms, such
+	// as by non-deterministic float instructions, or which import functions the ledger does not provide.
+	FeatureDeterministicContracts Feature = "deterministic_contracts"
+
+	// FeatureMultisig lets accounts of several participants move their PERLs once enough of the participants approve.
+	FeatureMultisig Feature = "multisig"
+
+	// FeatureDelegation lets accounts bond PERLs to validators, sharing in the rewards of the validators for a
+	// commission.
+	FeatureDelegation Feature = "delegation"
+
+	// FeatureSlashing slashes the stake of validators reported to have signed conflicting transactions, or to have
+	// gone without having a transaction applied for too long.
+	FeatureSlashing Feature = "slashing"
+
+	// FeatureReplacement lets transactions pay a tip on top of their fee, lets a transaction pending finalization be
+	// replaced by another from the same sender and of the same nonce paying a higher fee, and rejects transactions of a
+	// nonce their sender had a transaction applied with before.
+	FeatureReplacement Feature = "replacement"
+
+	// FeatureBeacon records a randomness beacon value for every block, which validators contribute to through beacon
+	// transactions, and smart contracts read through _randomness.
+	FeatureBeacon Feature = "beacon"
+
+	// FeatureSignatureSchemes lets transactions be signed with signature schemes other than Ed25519.
+	FeatureSignatureSchemes Feature = "signature_schemes"
+
+	// FeatureCanonicalTransactions lets transactions sign a version of their canonical encoding rather than the legacy
+	// encoding of their fields.
+	FeatureCanonicalTransactions Feature = "canonical_transactions"
+
+	// FeatureStamps lets transactions carry a proof-of-work stamp, exempting those whose stamp meets
+	// MinStampDifficulty from paying fees.
+	FeatureStamps Feature = "stamps"
+
+	// FeatureContractLogs lets smart contracts emit logs through _emit_log, which nodes store for clients to query.
+	FeatureContractLogs Feature = "contract_logs"
+
+	// FeatureGroth16 lets smart contracts verify Groth16 proofs over BN254 through _verify_groth16.
+	FeatureGroth16 Feature = "groth16"
+
+	// FeatureSampleProofs samples the peers queried to finalize a block from the validators, and has queried peers
+	// verify that they were sampled, rejecting queries which carry no proof of their sample.
+	FeatureSampleProofs Feature = "sample_proofs"
+)
+
+// Unscheduled is the activation height of features yet to be scheduled, which never activate.
+const Unscheduled uint64 = math.MaxUint64
+
+var (
+	// FeatureActivations Heights of the blocks from which each feature applies. Every feature is unscheduled by
+	// default, for existing ledgers to be replayed as they were finalized, and is to be scheduled at a height all nodes
+	// of a network agree on, such as from genesis on devnets. Features absent from the map never activate.
+	FeatureActivations = map[Feature]uint64{
+		FeatureFeeGrants: Unscheduled,
+		FeatureRecovery:  Unscheduled,
+		FeatureNames:     Unscheduled,
+		FeatureData:      Unscheduled,
+
+		FeatureContractCalls:    Unscheduled,
+		FeatureContractStorage:  Unscheduled,
+		FeatureContractUpgrades: Unscheduled,
+
+		FeatureDeterministicContracts: Unscheduled,
+		FeatureMultisig:               Unscheduled,
+		FeatureDelegation:             Unscheduled,
+		FeatureSlashing:               Unscheduled,
+		FeatureReplacement:            Unscheduled,
+		FeatureBeacon:                 Unscheduled,
+		FeatureSignatureSchemes:       Unscheduled,
+		FeatureCanonicalTransactions:  Unscheduled,
+		FeatureStamps:                 Unscheduled,
+		FeatureContractLogs:           Unscheduled,
+		FeatureGroth16:                Unscheduled,
+		FeatureSampleProofs:           Unscheduled,
+		FeatureGasScheduleV2:          Unscheduled,
+	}
+
+	// TagFeatures Features gating the transaction tags introduced by them. Transactions with a tag whose feature is
+	// yet to activate are rejected. Tags absent from the map are always active.
+	TagFeatures = map[Tag]Feature{
+		TagBeacon:     FeatureBeacon,
+		TagFeeGrant:   FeatureFeeGrants,
+		TagRecovery:   FeatureRecovery,
+		TagName:       FeatureNames,
+		TagData:       FeatureData,
+		TagUpgrade:    FeatureContractUpgrades,
+		TagMultisig:   FeatureMultisig,
+		TagDelegation: FeatureDelegation,
+		TagSlashing:   FeatureSlashing,
+	}
+)
+
+// FeatureActive returns whether feature f applies to the block at the given height.
+func FeatureActive(f Feature, height uint64) bool {
+	activation, scheduled := FeatureActivations[f]
+	return scheduled && activation != Unscheduled && height >= activation
+}
+
+// ScheduleFeatures schedules every feature to activate from the given height, such as from genesis on devnets.
+func ScheduleFeatures(height uint64) {
+	for f := range FeatureActivations {
+		FeatureActivations[f] = height
+	}
+}
+
+// TagActive returns whether transactions with the given tag may be applied to the block at the given height.
+func TagActive(tag Tag, height uint64) bool {
+	f, gated := TagFeatures[tag]
+	return !gated || FeatureActive(f, height)
+}
+
+// ActiveFeatures returns the features applying to the block at the given height, sorted by name.
+func ActiveFeatures(height uint64) []Feature {
+	features := make([]Feature, 0, len(FeatureActivations))
+
+	for f := range FeatureActivations {
+		if FeatureActive(f, height) {
+			features = append(features, f)
+		}
+	}
+
+	sort.Slice(features, func(i, j int) bool {
+		return features[i] < features[j]
+	})
+
+	return features
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package sys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Not parallel, as the schedule of features is global.
+func TestFeaturesUnscheduled(t *testing.T) {
+	for f, activation := range FeatureActivations {
+		assert.Equal(t, Unscheduled, activation, f)
+		assert.False(t, FeatureActive(f, 0), f)
+		assert.False(t, FeatureActive(f, Unscheduled), f)
+	}
+
+	for tag := range TagFeatures {
+		assert.False(t, TagActive(tag, 0), tag)
+	}
+
+	assert.True(t, TagActive(TagTransfer, 0))
+	assert.Empty(t, ActiveFeatures(Unscheduled))
+	assert.Equal(t, &GasScheduleV1, GasScheduleAt(Unscheduled))
+}
+
+func TestScheduleFeatures(t *testing.T) {
+	previous := make(map[Feature]uint64, len(FeatureActivations))
+	for f, activation := range FeatureActivations {
+		previous[f] = activation
+	}
+
+	defer func() {
+		FeatureActivations = previous
+	}()
+
+	ScheduleFeatures(10)
+
+	assert.Empty(t, ActiveFeatures(9))
+	assert.Len(t, ActiveFeatures(10), len(FeatureActivations))
+	assert.Equal(t, &GasScheduleV2, GasScheduleAt(10))
+}
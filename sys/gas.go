@@ -0,0 +1,161 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sys
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GasSchedule is the cost in gas of executing smart contracts, being the cost of every WebAssembly instruction they
+// execute, and of every call they make to a function of the ledger. Nodes must agree on the schedule applied to every
+// block, and so every change to the schedule is a new version of it, activating through a feature.
+type GasSchedule struct {
+	Version uint32 `json:"version"`
+
+	// Feature activating the schedule, which always applies should it be empty.
+	Feature Feature `json:"feature,omitempty"`
+
+	// DefaultInstruction Cost of instructions absent from Instructions.
+	DefaultInstruction int64 `json:"default_instruction"`
+
+	// Instructions Costs of instructions by their WebAssembly name, such as "i32.add".
+	Instructions map[string]int64 `json:"instructions"`
+
+	// HostFunctions Costs of functions of the ledger called by smart contracts, such as "wavelet.verify.ed25519", and
+	// of every byte or input they process, such as "wavelet.log.byte". Functions absent from the map cost
+	// DefaultInstruction.
+	HostFunctions map[string]int64 `json:"host_functions"`
+}
+
+// GetCost returns the cost of the instruction or function of the ledger named key, such that a schedule is the gas
+// policy smart contracts are compiled with.
+func (s *GasSchedule) GetCost(key string) int64 {
+	if cost, ok := s.HostFunctions[key]; ok {
+		return cost
+	}
+
+	if cost, ok := s.Instructions[key]; ok {
+		return cost
+	}
+
+	return s.DefaultInstruction
+}
+
+// FeatureGasScheduleV2 activates the second version of the gas schedule. Like every feature, it is unscheduled by
+// default, for existing ledgers to be replayed with the gas they were charged, and must be scheduled at a future
+// height, such as with --sys.feature gas_schedule_v2=<height>.
+const FeatureGasScheduleV2 Feature = "gas_schedule_v2"
+
+var (
+	// GasScheduleV1 charges a single unit of gas for every instruction, and every call to a function of the ledger,
+	// alongside every byte of logs.
+	GasScheduleV1 = GasSchedule{
+		Version:            1,
+		DefaultInstruction: 1,
+		Instructions:       map[string]int64{},
+		HostFunctions: map[string]int64{
+			"wavelet.verify.ed25519.byte":  0,
+			"wavelet.hash.blake2b256.byte": 0,
+			"wavelet.hash.blake2b512.byte": 0,
+			"wavelet.hash.sha256.byte":     0,
+			"wavelet.hash.sha512.byte":     0,
+		},
+	}
+
+	// GasScheduleV2 charges instructions and functions of the ledger as per GasTable, according to the time they
+	// take to execute, and functions processing data for every byte of it.
+	GasScheduleV2 = newGasSchedule(2, FeatureGasScheduleV2, GasTable)
+
+	// GasSchedules Every version of the gas schedule, in order of version.
+	GasSchedules = []*GasSchedule{&GasScheduleV1, &GasScheduleV2}
+)
+
+// newGasSchedule returns the version of the gas schedule charging the costs of table, of instructions and functions of
+// the ledger alike.
+func newGasSchedule(version uint32, feature Feature, table map[string]uint64) GasSchedule {
+	schedule := GasSchedule{
+		Version:            version,
+		Feature:            feature,
+		DefaultInstruction: 1,
+		Instructions:       make(map[string]int64),
+		HostFunctions:      make(map[string]int64),
+	}
+
+	for key, cost := range table {
+		if strings.HasPrefix(key, "wavelet.") {
+			schedule.HostFunctions[key] = int64(cost)
+		} else {
+			schedule.Instructions[key] = int64(cost)
+		}
+	}
+
+	return schedule
+}
+
+// GasScheduleAt returns the latest version of the gas schedule applying to the block at the given height.
+func GasScheduleAt(height uint64) *GasSchedule {
+	for i := len(GasSchedules) - 1; i > 0; i-- {
+		if FeatureActive(GasSchedules[i].Feature, height) {
+			return GasSchedules[i]
+		}
+	}
+
+	return GasSchedules[0]
+}
+
+// LoadGasSchedule replaces the costs of the version of the gas schedule read from r as JSON, such as to tune the costs
+// of a private network. All nodes of a network must load the same costs.
+func LoadGasSchedule(r io.Reader) error {
+	var schedule GasSchedule
+
+	if err := json.NewDecoder(r).Decode(&schedule); err != nil {
+		return errors.Wrap(err, "failed to decode gas schedule")
+	}
+
+	if schedule.DefaultInstruction < 0 {
+		return errors.Errorf("gas schedule version %d has a negative default cost", schedule.Version)
+	}
+
+	for _, costs := range []map[string]int64{schedule.Instructions, schedule.HostFunctions} {
+		for key, cost := range costs {
+			if cost < 0 {
+				return errors.Errorf("gas schedule version %d has a negative cost for %q", schedule.Version, key)
+			}
+		}
+	}
+
+	for i, s := range GasSchedules {
+		if s.Version == schedule.Version {
+			if schedule.Feature == "" {
+				schedule.Feature = s.Feature
+			}
+
+			GasSchedules[i] = &schedule
+
+			return nil
+		}
+	}
+
+	return errors.Errorf("unknown gas schedule version %d", schedule.Version)
+}
@@ -0,0 +1,54 @@
+;; Source of call_contract.wasm, a smart contract calling other contracts through _call_contract.
+;;
+;; The parameters of _contract_call are the ID of the contract to call, the PERLs to send it and the gas to forward
+;; to it as 64-bit little-endian integers, the length of the name of the function to call as a byte, the name, and
+;; the parameters of the function.
+(module
+  (import "env" "_call_contract" (func $call_contract (param i32 i64 i64 i32 i32 i32 i32) (result i32)))
+  (import "env" "_call_result_len" (func $call_result_len (result i32)))
+  (import "env" "_call_result" (func $call_result (param i32) (result i32)))
+  (import "env" "_payload_len" (func $payload_len (result i32)))
+  (import "env" "_payload" (func $payload (param i32) (result i32)))
+  (import "env" "_emit_log" (func $emit_log (param i32 i32 i32 i32) (result i32)))
+  (import "env" "_result" (func $result (param i32 i32) (result i32)))
+  (memory (export "memory") 1)
+
+  ;; The number of functions called, other than _contract_init, is kept at 0.
+  (data (i32.const 16) "call")
+  (data (i32.const 32) "ok")
+
+  (func (export "_contract_init"))
+
+  ;; Calls a function of another contract, and emits a log under the topic "call" of the byte _call_contract
+  ;; returned followed by the result of the function, which it reports as its own result.
+  (func (export "_contract_call") (local $name_len i32) (local $result_len i32)
+    (i32.store (i32.const 0) (i32.add (i32.load (i32.const 0)) (i32.const 1)))
+
+    ;; The payload is read to 1024, with the parameters following its header of 112 bytes.
+    (drop (call $payload (i32.const 1024)))
+    (set_local $name_len (i32.load8_u offset=1184 (i32.const 0)))
+
+    (i32.store8 (i32.const 511)
+      (call $call_contract
+        (i32.const 1136)
+        (i64.load offset=1168 (i32.const 0))
+        (i64.load offset=1176 (i32.const 0))
+        (i32.const 1185) (get_local $name_len)
+        (i32.add (i32.const 1185) (get_local $name_len))
+        (i32.sub (i32.sub (call $payload_len) (i32.const 161)) (get_local $name_len))))
+
+    (set_local $result_len (call $call_result_len))
+    (drop (call $call_result (i32.const 512)))
+
+    (drop (call $emit_log (i32.const 16) (i32.const 4) (i32.const 511) (i32.add (get_local $result_len) (i32.const 1))))
+    (drop (call $result (i32.const 511) (i32.add (get_local $result_len) (i32.const 1)))))
+
+  ;; Reports "ok" as its result.
+  (func (export "_contract_ok")
+    (i32.store (i32.const 0) (i32.add (i32.load (i32.const 0)) (i32.const 1)))
+    (drop (call $result (i32.const 32) (i32.const 2))))
+
+  ;; Traps, such that the call fails.
+  (func (export "_contract_fail")
+    (i32.store (i32.const 0) (i32.add (i32.load (i32.const 0)) (i32.const 1)))
+    unreachable))
@@ -0,0 +1,22 @@
+;; Source of emit_log.wasm, a smart contract emitting logs through _emit_log.
+(module
+  (import "env" "_emit_log" (func $emit_log (param i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+
+  (data (i32.const 0) "greeting")
+  (data (i32.const 16) "hello")
+  (data (i32.const 24) "world")
+
+  ;; Emits "hello" under the topic "greeting".
+  (func (export "_contract_init")
+    (drop (call $emit_log (i32.const 0) (i32.const 8) (i32.const 16) (i32.const 5))))
+
+  ;; Emits "hello", and then "world" under the topic "greeting".
+  (func (export "_contract_emit")
+    (drop (call $emit_log (i32.const 0) (i32.const 8) (i32.const 16) (i32.const 5)))
+    (drop (call $emit_log (i32.const 0) (i32.const 8) (i32.const 24) (i32.const 5))))
+
+  ;; Emits "hello" under the topic "greeting", and then traps.
+  (func (export "_contract_fail")
+    (drop (call $emit_log (i32.const 0) (i32.const 8) (i32.const 16) (i32.const 5)))
+    unreachable))
@@ -0,0 +1,164 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package testnet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/genesis"
+	"github.com/pkg/errors"
+)
+
+const (
+	// dockerPort and dockerAPIPort are the ports nodes listen for peers and
+	// host their HTTP API at within their containers.
+	dockerPort    = 3000
+	dockerAPIPort = 9000
+)
+
+// docker runs nodes as containers of an image built from the Dockerfile at
+// the root of the repository, attached to a network of their own. The
+// genesis is mounted into every container, and the HTTP API of every node is
+// published on a random port of the loopback interface of the host.
+type docker struct {
+	image   string
+	network string
+	dir     string
+}
+
+func newDocker(image string, g *genesis.Genesis) (*docker, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, errors.Wrap(err, "docker is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "testnet")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.Write(dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	var suffix [4]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	d := &docker{image: image, network: "wavelet-testnet-" + hex.EncodeToString(suffix[:]), dir: dir}
+
+	if _, err := d.run("network", "create", d.network); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (d *docker) start(i int, keys *skademlia.Keypair, peers []string) (*Node, error) {
+	name := fmt.Sprintf("%s-%d", d.network, i)
+	key := keys.PrivateKey()
+
+	args := []string{
+		"run", "--detach", "--interactive",
+		"--name", name,
+		"--network", d.network,
+		"--publish", "127.0.0.1::" + strconv.Itoa(dockerAPIPort),
+		"--volume", d.dir + ":/testnet:ro",
+		d.image, "./wavelet",
+		"--host", name,
+		"--port", strconv.Itoa(dockerPort),
+		"--api.port", strconv.Itoa(dockerAPIPort),
+		"--wallet", hex.EncodeToString(key[:]),
+		"--genesis", "/testnet/genesis",
+		"--sys.feature", "all=0",
+	}
+
+	if _, err := d.run(append(args, peers...)...); err != nil {
+		return nil, err
+	}
+
+	stop := func() error {
+		_, err := d.run("rm", "--force", name)
+		return err
+	}
+
+	out, err := d.run("port", name, strconv.Itoa(dockerAPIPort)+"/tcp")
+	if err != nil {
+		_ = stop()
+		return nil, err
+	}
+
+	host, port, err := net.SplitHostPort(strings.TrimSpace(strings.SplitN(out, "\n", 2)[0]))
+	if err != nil {
+		_ = stop()
+		return nil, errors.Wrapf(err, "unexpected port mapping %q", out)
+	}
+
+	apiPort, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		_ = stop()
+		return nil, errors.Wrapf(err, "unexpected port mapping %q", out)
+	}
+
+	return &Node{
+		Keys:    keys,
+		Addr:    net.JoinHostPort(name, strconv.Itoa(dockerPort)),
+		APIHost: host,
+		APIPort: uint16(apiPort),
+		stop:    stop,
+	}, nil
+}
+
+func (d *docker) close() error {
+	_, err := d.run("network", "rm", d.network)
+
+	if rmErr := os.RemoveAll(d.dir); err == nil {
+		err = rmErr
+	}
+
+	return err
+}
+
+func (d *docker) run(args ...string) (string, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("docker", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "docker %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package testnet
+
+import (
+	"encoding/hex"
+	"net"
+	"strconv"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/cmd/wavelet/node"
+	"github.com/perlin-network/wavelet/genesis"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// inProcess runs nodes within the current process, keeping their state in
+// memory.
+type inProcess struct {
+	genesis string
+}
+
+func newInProcess(g *genesis.Genesis) (*inProcess, error) {
+	buf, err := g.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	// Every feature activates from genesis, as it does on the nodes run as
+	// containers. The schedule is shared by all nodes of the process.
+	sys.ScheduleFeatures(0)
+
+	return &inProcess{genesis: string(buf)}, nil
+}
+
+func (p *inProcess) start(i int, keys *skademlia.Keypair, peers []string) (*Node, error) {
+	apiPort, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.PrivateKey()
+
+	w, err := node.New(&node.Config{
+		Host:    "127.0.0.1",
+		Wallet:  hex.EncodeToString(key[:]),
+		Genesis: &p.genesis,
+		APIPort: uint(apiPort),
+		Peers:   peers,
+		NoGC:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w.Start()
+
+	return &Node{
+		Keys:    w.Keys,
+		Addr:    w.Net.ID().Address(),
+		APIHost: "127.0.0.1",
+		APIPort: uint16(apiPort),
+		stop:    w.Close,
+	}, nil
+}
+
+func (p *inProcess) close() error {
+	return nil
+}
+
+// freePort returns a port which is free to listen on at the time of calling.
+func freePort() (int, error) {
+	ln, err := net.Listen("tcp4", ":0")
+	if err != nil {
+		return 0, err
+	}
+
+	defer ln.Close()
+
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(port)
+}
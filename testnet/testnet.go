@@ -0,0 +1,446 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package testnet launches networks of wavelet nodes for tests, either
+// in-process or as Docker containers, and hands out clients of accounts
+// funded at genesis.
+//
+// A network is started from a generated genesis in which every node is a
+// validator, and every account, nodes included, has the same balance:
+//
+//	network, err := testnet.Start(testnet.DefaultConfig())
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer network.Close()
+//
+//	client, err := network.Client(0)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	tx, err := client.Pay(network.Accounts()[1].Keys.PublicKey(), 1000)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	if _, err := network.WaitForTransaction(tx.ID); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// In-process nodes keep their state in memory, which is released once the
+// network is closed.
+//
+// In-process nodes share the global configuration and loggers of the
+// process, hence the events streamed by their websockets may be those of any
+// node of the network. Run nodes as Docker containers to isolate them.
+package testnet
+
+import (
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/genesis"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+)
+
+// Config configures a network.
+type Config struct {
+	// Nodes is the number of nodes, each of which is a validator.
+	Nodes int
+
+	// Accounts is the number of funded accounts besides those of nodes.
+	Accounts int
+
+	// Balance is the balance of every account at genesis, nodes included.
+	Balance uint64
+
+	// Stake is the stake of every node at genesis.
+	Stake uint64
+
+	// DockerImage, if not empty, is the image nodes are run from as Docker
+	// containers. Otherwise, nodes are run in-process.
+	DockerImage string
+
+	// Timeout bounds how long to wait for the nodes to discover each other,
+	// and for them to finalize a block when waiting for liveness.
+	Timeout time.Duration
+}
+
+// DefaultConfig returns the configuration of an in-process network of 3
+// nodes and 10 funded accounts.
+func DefaultConfig() Config {
+	return Config{
+		Nodes:    3,
+		Accounts: 10,
+		Balance:  1000000000000,
+		Stake:    sys.MinimumStake * 100,
+		Timeout:  30 * time.Second,
+	}
+}
+
+// Node is a node of a network.
+type Node struct {
+	Keys *skademlia.Keypair
+
+	// Addr is the address other nodes reach the node at.
+	Addr string
+
+	// APIHost and APIPort are where the HTTP API of the node is reachable
+	// from the process which started the network.
+	APIHost string
+	APIPort uint16
+
+	stop   func() error
+	client *wctl.Client
+}
+
+// Client connects to the HTTP API of the node on behalf of the account whose
+// private key is key.
+func (n *Node) Client(key edwards25519.PrivateKey) (*wctl.Client, error) {
+	client, err := wctl.NewClient(wctl.Config{APIHost: n.APIHost, APIPort: n.APIPort, PrivateKey: key})
+	if err != nil {
+		// NewClient may return a client alongside an error.
+		if client != nil {
+			client.Close()
+		}
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// connect returns a client of the node on behalf of its own account,
+// connecting to the node first should it not have been connected to yet.
+func (n *Node) connect() (*wctl.Client, error) {
+	if n.client == nil {
+		client, err := n.Client(n.Keys.PrivateKey())
+		if err != nil {
+			return nil, err
+		}
+
+		n.client = client
+	}
+
+	return n.client, nil
+}
+
+// status returns the status of the ledger of the node.
+func (n *Node) status() (*wctl.LedgerStatusResponse, error) {
+	client, err := n.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.LedgerStatus()
+}
+
+// backend starts nodes.
+type backend interface {
+	// start starts the i-th node of the network, which bootstraps off of
+	// peers.
+	start(i int, keys *skademlia.Keypair, peers []string) (*Node, error)
+
+	// close releases the resources of the backend, once all of its nodes
+	// have been stopped.
+	close() error
+}
+
+// Network is a running network of nodes.
+type Network struct {
+	cfg     Config
+	genesis *genesis.Genesis
+	backend backend
+	nodes   []*Node
+
+	clientsLock sync.Mutex
+	clients     []*wctl.Client
+}
+
+// Start starts a network as configured by cfg, and waits for every node to
+// discover all others.
+func Start(cfg Config) (*Network, error) {
+	if cfg.Nodes <= 0 {
+		return nil, errors.New("there must be at least one node")
+	}
+
+	if cfg.Accounts < 0 {
+		return nil, errors.New("the number of accounts must not be negative")
+	}
+
+	hi, supply := bits.Mul64(cfg.Balance, uint64(cfg.Nodes+cfg.Accounts))
+	if hi != 0 {
+		return nil, errors.New("the balances of all accounts add up to more than 2^64-1 PERLs")
+	}
+
+	g, err := genesis.Generate(genesis.Options{
+		Accounts:     cfg.Nodes + cfg.Accounts,
+		Validators:   cfg.Nodes,
+		Distribution: "equal",
+		Supply:       supply,
+		Stake:        cfg.Stake,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate genesis")
+	}
+
+	var b backend
+
+	if cfg.DockerImage != "" {
+		b, err = newDocker(cfg.DockerImage, g)
+	} else {
+		b, err = newInProcess(g)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Network{cfg: cfg, genesis: g, backend: b}
+
+	for i := 0; i < cfg.Nodes; i++ {
+		var peers []string
+		if i > 0 {
+			peers = append(peers, n.nodes[0].Addr)
+		}
+
+		node, err := b.start(i, g.Accounts[i].Keys, peers)
+		if err != nil {
+			_ = n.Close()
+			return nil, errors.Wrapf(err, "failed to start node %d", i)
+		}
+
+		n.nodes = append(n.nodes, node)
+	}
+
+	err = n.waitFor(func() bool {
+		for _, node := range n.nodes {
+			status, err := node.status()
+			if err != nil || len(status.Peers) < len(n.nodes)-1 {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	if err != nil {
+		_ = n.Close()
+		return nil, errors.Wrap(err, "network failed to bootstrap")
+	}
+
+	return n, nil
+}
+
+// Nodes returns the nodes of the network.
+func (n *Network) Nodes() []*Node {
+	return n.nodes
+}
+
+// Accounts returns the funded accounts of the network, excluding those of
+// nodes.
+func (n *Network) Accounts() []genesis.Account {
+	return n.genesis.Accounts[n.cfg.Nodes:]
+}
+
+// Client connects to a node on behalf of the i-th funded account. Accounts
+// are spread evenly amongst nodes. The client is closed along with the
+// network.
+func (n *Network) Client(i int) (*wctl.Client, error) {
+	accounts := n.Accounts()
+
+	if i < 0 || i >= len(accounts) {
+		return nil, errors.Errorf("there are only %d funded accounts", len(accounts))
+	}
+
+	client, err := n.nodes[i%len(n.nodes)].Client(accounts[i].Keys.PrivateKey())
+	if err != nil {
+		return nil, err
+	}
+
+	n.clientsLock.Lock()
+	n.clients = append(n.clients, client)
+	n.clientsLock.Unlock()
+
+	return client, nil
+}
+
+// WaitForLiveness has the first node submit a transfer, and waits for every
+// node to finalize a block past the latest block any node had finalized.
+func (n *Network) WaitForLiveness() error {
+	var latest uint64
+
+	for i, node := range n.nodes {
+		status, err := node.status()
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the status of node %d", i)
+		}
+
+		if status.Block.Index > latest {
+			latest = status.Block.Index
+		}
+	}
+
+	if _, err := n.nodes[0].client.Pay(n.nodes[len(n.nodes)-1].Keys.PublicKey(), 1); err != nil {
+		return errors.Wrap(err, "failed to submit a transfer")
+	}
+
+	err := n.waitFor(func() bool {
+		for _, node := range n.nodes {
+			status, err := node.status()
+			if err != nil || status.Block.Index <= latest {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	return errors.Wrap(err, "no block was finalized")
+}
+
+// WaitForBlock waits for every node to finalize the block at the given
+// height.
+func (n *Network) WaitForBlock(index uint64) error {
+	err := n.waitFor(func() bool {
+		for _, node := range n.nodes {
+			status, err := node.status()
+			if err != nil || status.Block.Index < index {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	return errors.Wrapf(err, "block %d was not finalized", index)
+}
+
+// WaitForTransaction waits for every node to apply the transaction with ID
+// id, and returns it as reported by the first node. As nodes do not report
+// transactions they reject, waiting for a rejected transaction times out.
+func (n *Network) WaitForTransaction(id [32]byte) (*wctl.Transaction, error) {
+	var tx *wctl.Transaction
+
+	err := n.waitFor(func() bool {
+		for i, node := range n.nodes {
+			client, err := node.connect()
+			if err != nil {
+				return false
+			}
+
+			reported, err := client.GetTransaction(id)
+			if err != nil || reported.Status != "applied" {
+				return false
+			}
+
+			if i == 0 {
+				tx = reported
+			}
+		}
+
+		return true
+	})
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "transaction %x was not applied", id)
+	}
+
+	return tx, nil
+}
+
+// WaitForBalance waits for every node to report the given balance for
+// account, and otherwise reports the balances last seen by each node.
+func (n *Network) WaitForBalance(account [32]byte, balance uint64) error {
+	balances := make([]uint64, len(n.nodes))
+
+	err := n.waitFor(func() bool {
+		done := true
+
+		for i, node := range n.nodes {
+			client, err := node.connect()
+			if err != nil {
+				return false
+			}
+
+			reported, err := client.GetAccount(account)
+			if err != nil {
+				return false
+			}
+
+			balances[i] = reported.Balance
+			done = done && reported.Balance == balance
+		}
+
+		return done
+	})
+
+	return errors.Wrapf(err, "account %x does not have a balance of %d on every node, but of %v",
+		account, balance, balances)
+}
+
+// Close closes all clients handed out by the network, and stops its nodes.
+func (n *Network) Close() error {
+	n.clientsLock.Lock()
+	for _, client := range n.clients {
+		client.Close()
+	}
+	n.clients = nil
+	n.clientsLock.Unlock()
+
+	var first error
+
+	for i := len(n.nodes) - 1; i >= 0; i-- {
+		if n.nodes[i].client != nil {
+			n.nodes[i].client.Close()
+		}
+
+		if err := n.nodes[i].stop(); err != nil && first == nil {
+			first = errors.Wrapf(err, "failed to stop node %d", i)
+		}
+	}
+
+	n.nodes = nil
+
+	if err := n.backend.close(); err != nil && first == nil {
+		first = err
+	}
+
+	return first
+}
+
+func (n *Network) waitFor(fn func() bool) error {
+	deadline := time.Now().Add(n.cfg.Timeout)
+
+	for !fn() {
+		if time.Now().After(deadline) {
+			return errors.New("timed out")
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build integration
+
+package testnet
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNetwork(t *testing.T, cfg Config) {
+	network, err := Start(cfg)
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, network.Close())
+	}()
+
+	require.Len(t, network.Nodes(), cfg.Nodes)
+	require.Len(t, network.Accounts(), cfg.Accounts)
+
+	require.NoError(t, network.WaitForLiveness())
+
+	sender, err := network.Client(0)
+	require.NoError(t, err)
+
+	recipient := network.Accounts()[1].Keys.PublicKey()
+
+	tx, err := sender.Pay(recipient, 1000)
+	require.NoError(t, err)
+
+	applied, err := network.WaitForTransaction(tx.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sender.PublicKey, edwards25519.PublicKey(applied.Sender))
+
+	require.NoError(t, network.WaitForBalance(recipient, cfg.Balance+1000))
+
+	// Query the recipient from another node than the one the transfer was
+	// submitted to.
+	observer, err := network.Client(1)
+	require.NoError(t, err)
+
+	account, err := observer.GetAccount(recipient)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Balance+1000, account.Balance)
+
+	status, err := observer.LedgerStatus()
+	require.NoError(t, err)
+	require.NoError(t, network.WaitForBlock(status.Block.Index))
+}
+
+func TestInProcess(t *testing.T) {
+	log.ClearWriter(log.LoggerWavelet)
+
+	testNetwork(t, DefaultConfig())
+}
+
+func TestDocker(t *testing.T) {
+	image := os.Getenv("WAVELET_TESTNET_IMAGE")
+	if image == "" {
+		t.Skip("WAVELET_TESTNET_IMAGE is not set to the Docker image of a node")
+	}
+
+	cfg := DefaultConfig()
+	cfg.DockerImage = image
+	cfg.Timeout = time.Minute
+
+	testNetwork(t, cfg)
+}
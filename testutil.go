@@ -741,6 +741,23 @@ func waitFor(fn func() bool) error {
 	}
 }
 
+// ScheduleFeatures schedules features to activate from genesis, as they are on devnets, returning a function which
+// restores their previous schedule. Tests scheduling features may not run in parallel, as the schedule is global.
+func ScheduleFeatures(features ...sys.Feature) func() {
+	previous := make(map[sys.Feature]uint64, len(features))
+
+	for _, f := range features {
+		previous[f] = sys.FeatureActivations[f]
+		sys.FeatureActivations[f] = 0
+	}
+
+	return func() {
+		for f, height := range previous {
+			sys.FeatureActivations[f] = height
+		}
+	}
+}
+
 func FailTest(t *testing.T, err error) {
 	t.Helper()
 
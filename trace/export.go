@@ -0,0 +1,379 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/wavelet/log"
+	"github.com/pkg/errors"
+)
+
+// Config configures the export of spans to an OpenTelemetry collector.
+type Config struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver of the collector,
+	// such as http://localhost:4318. Spans are posted to its /v1/traces path
+	// encoded as JSON.
+	Endpoint string
+
+	// Headers are set on every export request, such as to authenticate to
+	// the collector.
+	Headers map[string]string
+
+	// SampleRatio is the ratio of traces started by the node which are
+	// sampled, between 0 and 1. Traces started by clients or other nodes are
+	// sampled as their trace context says.
+	SampleRatio float64
+
+	// Resource describes the node, such as by service.name.
+	Resource map[string]string
+
+	// BatchSize is the maximum number of spans exported per request, and
+	// Interval the longest spans wait to be exported.
+	BatchSize int
+	Interval  time.Duration
+
+	// QueueSize is the number of spans which may be waiting to be exported,
+	// spans ended past it being dropped.
+	QueueSize int
+
+	// Timeout bounds every export request.
+	Timeout time.Duration
+}
+
+// DefaultConfig returns the configuration of an exporter to endpoint which
+// samples all traces.
+func DefaultConfig(endpoint string) Config {
+	return Config{
+		Endpoint:    endpoint,
+		SampleRatio: 1,
+		Resource:    map[string]string{"service.name": "wavelet"},
+		BatchSize:   512,
+		Interval:    5 * time.Second,
+		QueueSize:   4096,
+		Timeout:     10 * time.Second,
+	}
+}
+
+type exporter struct {
+	cfg    Config
+	url    string
+	client *http.Client
+
+	sampleLock sync.Mutex
+	sampler    *rand.Rand
+
+	queue chan *Span
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	// dropped counts the spans dropped since the last export, for it to be
+	// logged.
+	droppedLock sync.Mutex
+	dropped     int
+}
+
+func newExporter(cfg Config) (*exporter, error) {
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Errorf("OTLP endpoint must be an absolute http or https URL, but got %q", cfg.Endpoint)
+	}
+
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, errors.Errorf("sample ratio must be between 0 and 1, but got %g", cfg.SampleRatio)
+	}
+
+	if cfg.BatchSize <= 0 || cfg.QueueSize <= 0 || cfg.Interval <= 0 || cfg.Timeout <= 0 {
+		return nil, errors.New("batch size, queue size, interval and timeout must be positive")
+	}
+
+	e := &exporter{
+		cfg:     cfg,
+		url:     strings.TrimSuffix(cfg.Endpoint, "/") + "/v1/traces",
+		client:  &http.Client{Timeout: cfg.Timeout},
+		sampler: rand.New(rand.NewSource(time.Now().UnixNano())), // nolint:gosec
+		queue:   make(chan *Span, cfg.QueueSize),
+		done:    make(chan struct{}),
+	}
+
+	e.wg.Add(1)
+
+	go e.run()
+
+	return e, nil
+}
+
+func (e *exporter) sample() bool {
+	e.sampleLock.Lock()
+	defer e.sampleLock.Unlock()
+
+	return e.sampler.Float64() < e.cfg.SampleRatio
+}
+
+// enqueue queues s to be exported, dropping it should the queue be full.
+func (e *exporter) enqueue(s *Span) {
+	select {
+	case e.queue <- s:
+	default:
+		e.droppedLock.Lock()
+		e.dropped++
+		e.droppedLock.Unlock()
+	}
+}
+
+func (e *exporter) stop() {
+	close(e.done)
+	e.wg.Wait()
+}
+
+func (e *exporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, e.cfg.BatchSize)
+
+	// failing is whether the last export failed, for failures to only be
+	// logged once until exports succeed again.
+	failing := false
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		err := e.export(batch)
+		batch = batch[:0]
+
+		e.droppedLock.Lock()
+		dropped := e.dropped
+		e.dropped = 0
+		e.droppedLock.Unlock()
+
+		logger := log.Node()
+
+		switch {
+		case err != nil && !failing:
+			logger.Warn().Err(err).Str("endpoint", e.url).Msg("Failed to export spans.")
+		case err == nil && failing:
+			logger.Info().Str("endpoint", e.url).Msg("Resumed exporting spans.")
+		}
+
+		if dropped > 0 {
+			logger.Warn().Int("num_dropped", dropped).Msg("Dropped spans, as too many were waiting to be exported.")
+		}
+
+		failing = err != nil
+	}
+
+	for {
+		select {
+		case s := <-e.queue:
+			batch = append(batch, s)
+
+			if len(batch) == e.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.done:
+			for {
+				select {
+				case s := <-e.queue:
+					batch = append(batch, s)
+
+					if len(batch) == e.cfg.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// export posts spans to the collector.
+func (e *exporter) export(spans []*Span) error {
+	body, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		return errors.Wrap(err, "failed to encode spans")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_ = res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("collector responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// The types below are the JSON encoding of an OTLP ExportTraceServiceRequest.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              Kind            `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+const (
+	otlpStatusUnset = 0
+	otlpStatusError = 2
+)
+
+func (e *exporter) encode(spans []*Span) otlpRequest {
+	keys := make([]string, 0, len(e.cfg.Resource))
+	for k := range e.cfg.Resource {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	resource := make([]otlpAttribute, 0, len(keys))
+	for _, k := range keys {
+		resource = append(resource, encodeAttribute(String(k, e.cfg.Resource[k])))
+	}
+
+	encoded := make([]otlpSpan, 0, len(spans))
+
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(s.ctx.TraceID[:]),
+			SpanID:            hex.EncodeToString(s.ctx.SpanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Status:            otlpStatus{Code: otlpStatusUnset},
+		}
+
+		if s.parent != (SpanID{}) {
+			span.ParentSpanID = hex.EncodeToString(s.parent[:])
+		}
+
+		for _, attr := range s.attrs {
+			span.Attributes = append(span.Attributes, encodeAttribute(attr))
+		}
+
+		if s.err != "" {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: s.err}
+		}
+
+		encoded = append(encoded, span)
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: resource},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "github.com/perlin-network/wavelet"}, Spans: encoded}},
+	}}}
+}
+
+func encodeAttribute(attr Attribute) otlpAttribute {
+	var v otlpValue
+
+	switch value := attr.Value.(type) {
+	case string:
+		v.StringValue = &value
+	case int64:
+		s := strconv.FormatInt(value, 10)
+		v.IntValue = &s
+	case bool:
+		v.BoolValue = &value
+	default:
+		s := fmt.Sprint(value)
+		v.StringValue = &s
+	}
+
+	return otlpAttribute{Key: attr.Key, Value: v}
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package trace records spans of the work a node does on transactions, from
+// their receipt by the API through gossip, consensus and the application of
+// their block, and exports them to an OpenTelemetry collector over OTLP/HTTP.
+//
+// Trace context is propagated in the W3C Trace Context format, through the
+// traceparent header of API requests and the metadata of gossip. The span
+// context of every traced transaction is remembered by its ID, for the spans
+// of later stages of its life to join its trace.
+//
+// Tracing is disabled until Enable is called, in which case starting a span
+// returns nil, and the methods of nil spans do nothing.
+package trace
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HeaderTraceparent is the header of API requests carrying the W3C trace
+// context of the client.
+const HeaderTraceparent = "traceparent"
+
+type TraceID [16]byte
+type SpanID [8]byte
+
+// SpanContext identifies a span across the processes its trace spans.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid returns true should neither the trace nor the span ID of sc be zero.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// Traceparent encodes sc as the value of a traceparent header.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// ParseTraceparent decodes the value of a traceparent header.
+func ParseTraceparent(s string) (SpanContext, error) {
+	var sc SpanContext
+
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return sc, errors.Errorf("invalid traceparent %q", s)
+	}
+
+	if len(parts[1]) != 2*len(sc.TraceID) || len(parts[2]) != 2*len(sc.SpanID) || len(parts[3]) != 2 {
+		return sc, errors.Errorf("invalid traceparent %q", s)
+	}
+
+	var flags [1]byte
+
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, errors.Wrap(err, "invalid trace ID")
+	}
+
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, errors.Wrap(err, "invalid span ID")
+	}
+
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return sc, errors.Wrap(err, "invalid trace flags")
+	}
+
+	if !sc.IsValid() {
+		return sc, errors.Errorf("invalid traceparent %q", s)
+	}
+
+	sc.Sampled = flags[0]&1 == 1
+
+	return sc, nil
+}
+
+// Kind is the role of a span in the trace, as defined by OpenTelemetry.
+type Kind int
+
+const (
+	KindInternal Kind = iota + 1
+	KindServer
+	KindClient
+	KindProducer
+	KindConsumer
+)
+
+// Attribute is a key-value pair describing a span. Values are strings, int64s
+// or bools.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Hex is a string attribute of value encoded as hex, such as an ID.
+func Hex(key string, value []byte) Attribute {
+	return Attribute{Key: key, Value: hex.EncodeToString(value)}
+}
+
+// Span is an operation of a trace. Spans are not safe for concurrent use.
+type Span struct {
+	name   string
+	kind   Kind
+	ctx    SpanContext
+	parent SpanID
+
+	start, end time.Time
+
+	attrs []Attribute
+	err   string
+
+	exporter *exporter
+}
+
+// Start starts a span of kind named name, being a child of parent should it
+// be valid, or the root of a new trace otherwise. It returns nil should tracing
+// be disabled, or the span not be sampled.
+func Start(name string, kind Kind, parent SpanContext, attrs ...Attribute) *Span {
+	return StartAt(name, kind, parent, time.Now(), attrs...)
+}
+
+// StartAt is Start, for a span which started at start.
+func StartAt(name string, kind Kind, parent SpanContext, start time.Time, attrs ...Attribute) *Span {
+	e := current()
+	if e == nil {
+		return nil
+	}
+
+	s := &Span{name: name, kind: kind, start: start, attrs: attrs, exporter: e}
+
+	if parent.IsValid() {
+		if !parent.Sampled {
+			return nil
+		}
+
+		s.ctx.TraceID = parent.TraceID
+		s.parent = parent.SpanID
+	} else {
+		if !e.sample() {
+			return nil
+		}
+
+		randomID(s.ctx.TraceID[:])
+	}
+
+	randomID(s.ctx.SpanID[:])
+	s.ctx.Sampled = true
+
+	return s
+}
+
+// Context returns the context of s, which is invalid should s be nil.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+
+	return s.ctx
+}
+
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// SetError marks s as failed by err, should err not be nil.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.err = err.Error()
+}
+
+// End ends s, queuing it to be exported.
+func (s *Span) End() {
+	s.EndAt(time.Now())
+}
+
+// EndAt is End, for a span which ended at end.
+func (s *Span) EndAt(end time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.end = end
+	s.exporter.enqueue(s)
+}
+
+var (
+	random     = rand.New(rand.NewSource(time.Now().UnixNano())) // nolint:gosec
+	randomLock sync.Mutex
+)
+
+// randomID fills id with random bytes, not all of them being zero.
+func randomID(id []byte) {
+	randomLock.Lock()
+	defer randomLock.Unlock()
+
+	for {
+		_, _ = random.Read(id)
+
+		for _, b := range id {
+			if b != 0 {
+				return
+			}
+		}
+	}
+}
+
+var enabled atomic.Value
+
+// current returns the exporter of the spans of the node, or nil should
+// tracing be disabled.
+func current() *exporter {
+	e, _ := enabled.Load().(*exporter)
+	return e
+}
+
+// Enabled returns true should tracing be enabled, for callers to skip the
+// work of tracing otherwise.
+func Enabled() bool {
+	return current() != nil
+}
+
+// Enable starts exporting spans as configured by cfg, stopping the exporter
+// previously enabled, if any.
+func Enable(cfg Config) error {
+	e, err := newExporter(cfg)
+	if err != nil {
+		return err
+	}
+
+	if previous := current(); previous != nil {
+		previous.stop()
+	}
+
+	enabled.Store(e)
+
+	return nil
+}
+
+// Disable stops tracing, exporting spans already ended before returning.
+func Disable() {
+	e := current()
+	if e == nil {
+		return
+	}
+
+	enabled.Store((*exporter)(nil))
+	e.stop()
+}
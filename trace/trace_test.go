@@ -0,0 +1,207 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// +build unit
+
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, sc.IsValid())
+	assert.True(t, sc.Sampled)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	sc.Sampled = false
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", sc.Traceparent())
+
+	// Future versions may append fields.
+	_, err = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra")
+	assert.NoError(t, err)
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	assert.Nil(t, Start("disabled", KindInternal, SpanContext{}))
+
+	cfg := DefaultConfig("http://127.0.0.1:1")
+	cfg.SampleRatio = 0
+
+	if !assert.NoError(t, Enable(cfg)) {
+		return
+	}
+	defer Disable()
+
+	// Roots are sampled by the ratio, and children as their parent is.
+	assert.Nil(t, Start("root", KindInternal, SpanContext{}))
+
+	parent, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(t, err)
+
+	span := Start("child", KindServer, parent)
+	if assert.NotNil(t, span) {
+		assert.Equal(t, parent.TraceID, span.Context().TraceID)
+		assert.NotEqual(t, parent.SpanID, span.Context().SpanID)
+		assert.True(t, span.Context().Sampled)
+	}
+
+	parent.Sampled = false
+	assert.Nil(t, Start("unsampled", KindServer, parent))
+
+	// Nil spans are safe to use.
+	var nilSpan *Span
+
+	nilSpan.SetAttributes(String("key", "value"))
+	nilSpan.SetError(errors.New("error"))
+	nilSpan.End()
+	assert.False(t, nilSpan.Context().IsValid())
+}
+
+func TestEnableInvalidConfig(t *testing.T) {
+	assert.Error(t, Enable(DefaultConfig("localhost:4318")))
+
+	cfg := DefaultConfig("http://localhost:4318")
+	cfg.SampleRatio = 2
+	assert.Error(t, Enable(cfg))
+
+	assert.False(t, Enabled())
+}
+
+func TestExport(t *testing.T) {
+	requests := make(chan otlpRequest, 1)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var req otlpRequest
+		assert.NoError(t, json.Unmarshal(body, &req))
+
+		requests <- req
+	}))
+	defer collector.Close()
+
+	cfg := DefaultConfig(collector.URL)
+	cfg.Headers = map[string]string{"X-Token": "secret"}
+	cfg.Interval = time.Hour
+
+	if !assert.NoError(t, Enable(cfg)) {
+		return
+	}
+
+	root := Start("api.sendTransaction", KindServer, SpanContext{}, String("http.method", "POST"))
+	child := Start("state.apply", KindInternal, root.Context(), Int64("block.index", 7))
+	child.SetAttributes(Bool("tx.rejected", true))
+	child.SetError(errors.New("insufficient balance"))
+	child.End()
+	root.End()
+
+	// Disabling tracing exports the spans ended.
+	Disable()
+
+	var req otlpRequest
+
+	select {
+	case req = <-requests:
+	default:
+		t.Fatal("spans were not exported")
+	}
+
+	if !assert.Len(t, req.ResourceSpans, 1) || !assert.Len(t, req.ResourceSpans[0].ScopeSpans, 1) {
+		return
+	}
+
+	assert.Equal(t, "service.name", req.ResourceSpans[0].Resource.Attributes[0].Key)
+	assert.Equal(t, "wavelet", *req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if !assert.Len(t, spans, 2) {
+		return
+	}
+
+	assert.Equal(t, "state.apply", spans[0].Name)
+	assert.Equal(t, KindInternal, spans[0].Kind)
+	assert.Equal(t, spans[1].TraceID, spans[0].TraceID)
+	assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	assert.Equal(t, otlpStatus{Code: otlpStatusError, Message: "insufficient balance"}, spans[0].Status)
+
+	if assert.Len(t, spans[0].Attributes, 2) {
+		assert.Equal(t, "7", *spans[0].Attributes[0].Value.IntValue)
+		assert.True(t, *spans[0].Attributes[1].Value.BoolValue)
+	}
+
+	assert.Equal(t, "api.sendTransaction", spans[1].Name)
+	assert.Empty(t, spans[1].ParentSpanID)
+	assert.Equal(t, otlpStatusUnset, spans[1].Status.Code)
+	assert.Equal(t, "POST", *spans[1].Attributes[0].Value.StringValue)
+}
+
+func TestRememberTx(t *testing.T) {
+	var id [32]byte
+	randomID(id[:])
+
+	_, exists := TxContext(id)
+	assert.False(t, exists)
+
+	// Invalid contexts are not remembered.
+	RememberTx(id, SpanContext{})
+
+	_, exists = TxContext(id)
+	assert.False(t, exists)
+
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(t, err)
+
+	RememberTx(id, sc)
+
+	remembered, exists := TxContext(id)
+	assert.True(t, exists)
+	assert.Equal(t, sc, remembered)
+}
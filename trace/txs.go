@@ -0,0 +1,74 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package trace
+
+import (
+	"sync"
+)
+
+// maxTxContexts bounds the number of transactions whose span context is
+// remembered, the oldest being forgotten first.
+const maxTxContexts = 1 << 16
+
+// txs remembers the span contexts of traced transactions by their ID.
+var txs = struct {
+	sync.RWMutex
+
+	contexts map[[32]byte]SpanContext
+	order    [][32]byte
+	next     int
+}{
+	contexts: make(map[[32]byte]SpanContext),
+}
+
+// RememberTx remembers sc as the span context of the transaction with ID id,
+// for the spans of later stages of its life to join its trace.
+func RememberTx(id [32]byte, sc SpanContext) {
+	if !sc.IsValid() {
+		return
+	}
+
+	txs.Lock()
+	defer txs.Unlock()
+
+	if _, exists := txs.contexts[id]; !exists {
+		if len(txs.order) < maxTxContexts {
+			txs.order = append(txs.order, id)
+		} else {
+			delete(txs.contexts, txs.order[txs.next])
+
+			txs.order[txs.next] = id
+			txs.next = (txs.next + 1) % maxTxContexts
+		}
+	}
+
+	txs.contexts[id] = sc
+}
+
+// TxContext returns the span context remembered of the transaction with ID
+// id, and whether any is.
+func TxContext(id [32]byte) (SpanContext, bool) {
+	txs.RLock()
+	defer txs.RUnlock()
+
+	sc, exists := txs.contexts[id]
+
+	return sc, exists
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2019 Perlin
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package wavelet
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/perlin-network/wavelet/ledgerpb"
+	"github.com/perlin-network/wavelet/trace"
+)
+
+// TraceTx returns the attributes identifying tx on the spans of its life.
+func TraceTx(tx *Transaction) []trace.Attribute {
+	return []trace.Attribute{
+		trace.Hex("tx.id", tx.ID[:]),
+		trace.Hex("tx.sender", tx.Sender[:]),
+		trace.Int64("tx.tag", int64(tx.Tag)),
+		trace.Int64("tx.nonce", int64(tx.Nonce)),
+	}
+}
+
+// finalizationTrace times the stages of finalizing a block, for spans of them
+// to be recorded in the trace of every traced transaction of the block.
+type finalizationTrace struct {
+	block *Block
+
+	// voting is when the node started preferring a block for the round,
+	// decided when the block was decided on, applied when the transactions
+	// of the block were applied to the state, and stored when the block and
+	// the state were committed to the database.
+	voting, decided, applied, stored time.Time
+}
+
+func newFinalizationTrace(block *Block, voting time.Time) *finalizationTrace {
+	if !trace.Enabled() {
+		return nil
+	}
+
+	if voting.IsZero() {
+		voting = time.Now()
+	}
+
+	return &finalizationTrace{block: block, voting: voting, decided: time.Now()}
+}
+
+func (f *finalizationTrace) markApplied() {
+	if f != nil {
+		f.applied = time.Now()
+	}
+}
+
+func (f *finalizationTrace) markStored() {
+	if f != nil {
+		f.stored = time.Now()
+	}
+}
+
+// record records the spans of the finalization of the block for every traced
+// transaction of it, rejected transactions being marked as failed by why they
+// were rejected.
+func (f *finalizationTrace) record(results *collapseResults) {
+	if f == nil {
+		return
+	}
+
+	var rejected map[TransactionID]error
+
+	for _, id := range f.block.Transactions {
+		parent, traced := trace.TxContext(id)
+		if !traced {
+			continue
+		}
+
+		if rejected == nil {
+			rejected = make(map[TransactionID]error, len(results.rejected))
+
+			for i, tx := range results.rejected {
+				rejected[tx.ID] = results.rejectedErrors[i]
+			}
+		}
+
+		attrs := []trace.Attribute{
+			trace.Hex("tx.id", id[:]),
+			trace.Int64("block.index", int64(f.block.Index)),
+			trace.Hex("block.id", f.block.ID[:]),
+		}
+
+		span := trace.StartAt("consensus.finalize", trace.KindInternal, parent, f.voting, attrs...)
+		span.SetAttributes(trace.Int64("block.num_transactions", int64(len(f.block.Transactions))))
+
+		vote := trace.StartAt("consensus.vote", trace.KindInternal, span.Context(), f.voting, attrs...)
+		vote.EndAt(f.decided)
+
+		apply := trace.StartAt("state.apply", trace.KindInternal, span.Context(), f.decided, attrs...)
+		apply.SetError(rejected[id])
+		apply.EndAt(f.applied)
+
+		store := trace.StartAt("storage.commit", trace.KindInternal, span.Context(), f.applied, attrs...)
+		store.EndAt(f.stored)
+
+		span.SetError(rejected[id])
+		span.EndAt(f.stored)
+	}
+}
+
+// traceMetadataKey is the key of the metadata of gossip carrying the trace
+// contexts of the traced transactions gossiped, each value being the hex-encoded
+// ID of a transaction followed by a space and its traceparent.
+const traceMetadataKey = "wavelet-traceparent"
+
+// startGossipSpans starts a span of the gossip of every traced transaction of
+// batch, returning the spans alongside the metadata carrying their contexts to
+// the peers gossiped to.
+func startGossipSpans(batch []*ledgerpb.Transaction) ([]*trace.Span, []string) {
+	if !trace.Enabled() {
+		return nil, nil
+	}
+
+	var (
+		spans []*trace.Span
+		md    []string
+	)
+
+	for _, pb := range batch {
+		tx, err := TransactionFromProto(pb)
+		if err != nil {
+			continue
+		}
+
+		parent, traced := trace.TxContext(tx.ID)
+		if !traced {
+			continue
+		}
+
+		span := trace.Start("gossip.send", trace.KindProducer, parent, TraceTx(&tx)...)
+		if span == nil {
+			continue
+		}
+
+		spans = append(spans, span)
+		md = append(md, traceMetadataKey, hex.EncodeToString(tx.ID[:])+" "+span.Context().Traceparent())
+	}
+
+	return spans, md
+}
+
+// gossipedContexts decodes the trace contexts of the transactions gossiped to
+// the node from the values of the metadata keyed by traceMetadataKey.
+func gossipedContexts(values []string) map[TransactionID]trace.SpanContext {
+	if len(values) == 0 || !trace.Enabled() {
+		return nil
+	}
+
+	contexts := make(map[TransactionID]trace.SpanContext, len(values))
+
+	for _, v := range values {
+		parts := strings.SplitN(v, " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		var id TransactionID
+
+		if n, err := hex.Decode(id[:], []byte(parts[0])); err != nil || n != len(id) {
+			continue
+		}
+
+		sc, err := trace.ParseTraceparent(parts[1])
+		if err != nil {
+			continue
+		}
+
+		contexts[id] = sc
+	}
+
+	return contexts
+}
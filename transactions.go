@@ -5,6 +5,7 @@ import (
 
 	"github.com/perlin-network/wavelet/conf"
 	"github.com/perlin-network/wavelet/internal/btree"
+	"github.com/perlin-network/wavelet/sys"
 	"github.com/pkg/errors"
 )
 
@@ -16,6 +17,10 @@ type Transactions struct {
 	finalized map[TransactionID]struct{}
 	index     btree.BTree
 
+	// ID of the transaction from a sender with a given nonce which may be
+	// proposed into a block, being the first one seen unless replaced.
+	nonces map[senderNonce]TransactionID
+
 	latest Block // The latest block height the node is aware of.
 }
 
@@ -24,6 +29,7 @@ func NewTransactions(latest Block) *Transactions {
 		buffer:    make(map[TransactionID]*Transaction),
 		missing:   make(map[TransactionID]uint64),
 		finalized: make(map[TransactionID]struct{}),
+		nonces:    make(map[senderNonce]TransactionID),
 
 		latest: latest,
 	}
@@ -38,13 +44,21 @@ func (t *Transactions) Add(tx Transaction) {
 	t.add(tx)
 }
 
-func (t *Transactions) BatchAdd(transactions []Transaction) {
+// BatchAdd adds transactions into the node. It returns all conflicts between
+// the added transactions and those already in the node.
+func (t *Transactions) BatchAdd(transactions []Transaction) []TransactionConflict {
 	t.Lock()
 	defer t.Unlock()
 
+	var conflicts []TransactionConflict
+
 	for _, tx := range transactions {
-		t.add(tx)
+		if conflict, ok := t.add(tx); ok {
+			conflicts = append(conflicts, conflict)
+		}
 	}
+
+	return conflicts
 }
 
 // BatchUnsafeAdd adds transactions to buffer without adding them to index
@@ -55,27 +69,98 @@ func (t *Transactions) BatchUnsafeAdd(txs []*Transaction) {
 
 	for _, tx := range txs {
 		t.buffer[tx.ID] = tx
+		t.addNonce(tx)
 	}
 }
 
-func (t *Transactions) add(tx Transaction) {
+func (t *Transactions) add(tx Transaction) (TransactionConflict, bool) {
 	if t.latest.Index >= tx.Block+uint64(conf.GetPruningLimit()) {
 		delete(t.missing, tx.ID)
 
-		return
+		return TransactionConflict{}, false
 	}
 
 	if _, exists := t.buffer[tx.ID]; exists {
-		return
+		return TransactionConflict{}, false
 	}
 
-	if _, finalized := t.finalized[tx.ID]; !finalized {
+	t.buffer[tx.ID] = &tx
+
+	delete(t.missing, tx.ID) // In case the transaction was previously missing, mark it as no longer missing.
+
+	conflict, conflicted := t.addNonce(&tx)
+
+	// Transactions conflicting with one they may not replace are kept and
+	// reported as conflicts, but never proposed, as at most one transaction
+	// of a sender and nonce is to be applied.
+	if _, finalized := t.finalized[tx.ID]; !finalized && !conflicted {
 		t.index.Set(tx.ComputeIndex(t.latest.ID), tx.ID)
 	}
 
-	t.buffer[tx.ID] = &tx
+	return conflict, conflicted
+}
 
-	delete(t.missing, tx.ID) // In case the transaction was previously missing, mark it as no longer missing.
+// addNonce indexes tx by its sender and nonce, returning the conflict between
+// tx and the transaction indexed before it should there be one. Should tx
+// replace the transaction indexed before it, the latter is removed from the
+// mempool instead.
+func (t *Transactions) addNonce(tx *Transaction) (TransactionConflict, bool) {
+	key := senderNonce{sender: tx.Sender, nonce: tx.Nonce}
+
+	first, exists := t.nonces[key]
+	if !exists {
+		t.nonces[key] = tx.ID
+		return TransactionConflict{}, false
+	}
+
+	if first == tx.ID {
+		return TransactionConflict{}, false
+	}
+
+	if t.mayReplace(tx, first) {
+		t.index.Delete(t.buffer[first].ComputeIndex(t.latest.ID))
+		t.nonces[key] = tx.ID
+
+		return TransactionConflict{}, false
+	}
+
+	return TransactionConflict{Sender: tx.Sender, Nonce: tx.Nonce, First: first, Second: tx.ID}, true
+}
+
+// mayReplace returns whether tx may replace the transaction with the given
+// id from the same sender and of the same nonce, which it may once
+// sys.FeatureReplacement is active should the latter be pending finalization
+// and pay a lower fee.
+func (t *Transactions) mayReplace(tx *Transaction, id TransactionID) bool {
+	if !sys.FeatureActive(sys.FeatureReplacement, t.latest.Index+1) {
+		return false
+	}
+
+	prev, exists := t.buffer[id]
+	if !exists {
+		return false
+	}
+
+	if _, finalized := t.finalized[id]; finalized {
+		return false
+	}
+
+	return tx.Fee() > prev.Fee()
+}
+
+// CheckReplacement returns ErrTxNotReplaceable should the node have another
+// transaction from the sender of tx and of the same nonce, which tx may not
+// replace.
+func (t *Transactions) CheckReplacement(tx Transaction) error {
+	t.RLock()
+	defer t.RUnlock()
+
+	id, exists := t.nonces[senderNonce{sender: tx.Sender, nonce: tx.Nonce}]
+	if !exists || id == tx.ID || t.mayReplace(&tx, id) {
+		return nil
+	}
+
+	return errors.Wrapf(ErrTxNotReplaceable, "%x", id)
 }
 
 // MarkMissing marks that the node was expected to have archived a transaction with a specified id, but
@@ -139,6 +224,12 @@ func (t *Transactions) ReshufflePending(next Block) []TransactionID {
 
 	for _, id := range next.Transactions {
 		t.finalized[id] = struct{}{}
+
+		// Once finalized, a transaction may no longer be replaced, and
+		// supersedes any transaction from the same sender and nonce instead.
+		if tx, exists := t.buffer[id]; exists {
+			t.nonces[senderNonce{sender: tx.Sender, nonce: tx.Nonce}] = id
+		}
 	}
 
 	// Recompute indices of all items in the mempool.
@@ -153,6 +244,10 @@ func (t *Transactions) ReshufflePending(next Block) []TransactionID {
 
 		tx := t.buffer[id]
 
+		if t.nonces[senderNonce{sender: tx.Sender, nonce: tx.Nonce}] != id {
+			return true
+		}
+
 		if next.Index < tx.Block+uint64(conf.GetPruningLimit()) {
 			updated.Set(tx.ComputeIndex(next.ID), id)
 		}
@@ -173,6 +268,10 @@ func (t *Transactions) ReshufflePending(next Block) []TransactionID {
 			delete(t.buffer, tx.ID)
 			delete(t.finalized, tx.ID)
 
+			if key := (senderNonce{sender: tx.Sender, nonce: tx.Nonce}); t.nonces[key] == tx.ID {
+				delete(t.nonces, key)
+			}
+
 			pruned = append(pruned, tx.ID)
 		}
 	}
@@ -264,6 +363,22 @@ func (t *Transactions) PendingLen() int {
 	return t.index.Len()
 }
 
+// PendingIDs returns the IDs of the transactions the node has archived that
+// may be proposed into a block, in the order they would be proposed in.
+func (t *Transactions) PendingIDs() []TransactionID {
+	t.RLock()
+	defer t.RUnlock()
+
+	ids := make([]TransactionID, 0, t.index.Len())
+
+	t.index.Scan(func(key []byte, value interface{}) bool {
+		ids = append(ids, value.(TransactionID))
+		return true
+	})
+
+	return ids
+}
+
 // MissingLen returns the number of transactions that the node is looking to pull from
 // its peers.
 func (t *Transactions) MissingLen() int {
@@ -51,6 +51,10 @@ func TestTransactions(t *testing.T) {
 			return false
 		}
 
+		if !assert.Len(t, manager.PendingIDs(), len(transactions)) {
+			return false
+		}
+
 		// Check that transactions are properly stored in the manager.
 
 		for _, tx := range transactions {
@@ -25,12 +25,52 @@ import (
 	"fmt"
 	"github.com/perlin-network/noise/edwards25519"
 	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
 	"github.com/perlin-network/wavelet/sys"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/blake2b"
 	"io"
+	"math"
 )
 
+// tagFlagScheme is set on the tag byte of marshaled transactions that are
+// not signed with the default signature scheme, signaling that the scheme
+// follows the tag. Transactions of the default scheme are hence marshaled and
+// signed the same way they were before schemes were introduced.
+const tagFlagScheme = 0x80
+
+// tagFlagVersion is set on the tag byte of marshaled transactions whose
+// signature signs a version of their canonical encoding, signaling that the
+// version follows the tag and scheme. Transactions without it sign the legacy
+// encoding of TransactionMessage.
+const tagFlagVersion = 0x40
+
+// tagFlagStamp is set on the tag byte of marshaled transactions carrying a
+// proof-of-work stamp, signaling that the stamp follows the version.
+const tagFlagStamp = 0x20
+
+// tagFlagTip is set on the tag byte of marshaled transactions paying a tip,
+// signaling that the tip follows the stamp.
+const tagFlagTip = 0x10
+
+// tagFlags are all of the flags of the tag byte, which leave its lower four
+// bits to the tag, and hence room for at most 16 tags. The tag space is nearly
+// full: further flags, or tags past 15, are to be carried by a flags byte or a
+// versioned header instead.
+const tagFlags = tagFlagScheme | tagFlagVersion | tagFlagStamp | tagFlagTip
+
+// maxTag is the last tag of transactions, which is to be updated as tags are
+// added.
+const maxTag = sys.TagSlashing
+
+// Tags may not overlap the flags of the tag byte, lest the array length be
+// negative and fail compilation.
+var _ [tagFlagTip - 1 - maxTag]struct{}
+
+// transactionDomain is the domain of the canonical encoding of transactions.
+const transactionDomain = "wavelet/transaction"
+
 type Transaction struct {
 	Sender AccountID // Transaction sender.
 	Nonce  uint64
@@ -40,38 +80,233 @@ type Transaction struct {
 	Tag     sys.Tag
 	Payload []byte
 
+	// Scheme is the scheme of Signature, defaulting to Ed25519.
+	Scheme security.Scheme
+
+	// Version is the version of the canonical encoding Signature signs, 0
+	// being the legacy encoding of TransactionMessage.
+	Version byte
+
+	// Stamp is the proof-of-work stamp of the transaction, 0 being no stamp.
+	// Transactions whose stamp meets sys.MinStampDifficulty pay no fee.
+	Stamp uint64
+
+	// Tip is paid on top of the fee of the transaction, 0 being no tip. It
+	// lets a transaction replace a pending transaction of the same sender
+	// and nonce by paying a higher fee.
+	Tip uint64
+
 	Signature Signature
 
-	ID TransactionID // BLAKE2b(*).
+	// ID is the BLAKE2b-256 hash of the encoding of the transaction by
+	// Marshal, which is canonical and covers every signed field along with
+	// the signature itself.
+	ID TransactionID
 }
 
 func NewTransaction(sender *skademlia.Keypair, nonce, block uint64, tag sys.Tag, payload []byte) Transaction {
-	var nonceBuf [8]byte
+	tx := Transaction{
+		Sender: sender.PublicKey(), Nonce: nonce, Block: block, Tag: tag, Payload: payload,
+		Version: TransactionVersionAt(block),
+	}
 
-	binary.BigEndian.PutUint64(nonceBuf[:], nonce)
+	signature := edwards25519.Sign(sender.PrivateKey(), tx.Message())
 
-	var blockBuf [8]byte
+	return NewSignedTransactionWithVersion(
+		tx.Version, security.SchemeEd25519, tx.Sender, nonce, block, tag, payload, signature,
+	)
+}
+
+// TransactionVersionAt returns the version of the encoding signed by
+// transactions created against the given block, being canonical.Version once
+// sys.FeatureCanonicalTransactions activates at the block succeeding it, and
+// the legacy encoding before.
+func TransactionVersionAt(block uint64) byte {
+	if sys.FeatureActive(sys.FeatureCanonicalTransactions, block+1) {
+		return canonical.Version
+	}
 
-	binary.BigEndian.PutUint64(blockBuf[:], block)
+	return 0
+}
 
-	signature := edwards25519.Sign(
-		sender.PrivateKey(), append(nonceBuf[:], append(blockBuf[:], append([]byte{byte(tag)}, payload...)...)...),
+// NewTransactionWithSigner creates a transaction signed by signer, whose
+// public key becomes the sender of the transaction.
+func NewTransactionWithSigner(
+	signer security.Signer, nonce, block uint64, tag sys.Tag, payload []byte,
+) (Transaction, error) {
+	return newTransactionWithSigner(signer, nonce, block, tag, payload, 0, 0)
+}
+
+// NewStampedTransaction is NewTransactionWithSigner for a transaction
+// stamped with the first stamp meeting the given proof-of-work difficulty.
+func NewStampedTransaction(
+	signer security.Signer, nonce, block uint64, tag sys.Tag, payload []byte, difficulty int,
+) (Transaction, error) {
+	return newTransactionWithSigner(signer, nonce, block, tag, payload, difficulty, 0)
+}
+
+// NewTippedTransaction is NewTransactionWithSigner for a transaction paying
+// the given tip on top of its fee.
+func NewTippedTransaction(
+	signer security.Signer, nonce, block uint64, tag sys.Tag, payload []byte, tip uint64,
+) (Transaction, error) {
+	return newTransactionWithSigner(signer, nonce, block, tag, payload, 0, tip)
+}
+
+func newTransactionWithSigner(
+	signer security.Signer, nonce, block uint64, tag sys.Tag, payload []byte, difficulty int, tip uint64,
+) (Transaction, error) {
+	var (
+		sender    AccountID
+		signature Signature
 	)
 
-	return NewSignedTransaction(sender.PublicKey(), nonce, block, tag, payload, signature)
+	pub := signer.PublicKey()
+	if len(pub) != SizeAccountID {
+		return Transaction{}, errors.Errorf("public key of scheme %s must be %d bytes to sign transactions", signer.Scheme(), SizeAccountID)
+	}
+
+	copy(sender[:], pub)
+
+	tx := Transaction{
+		Sender: sender, Nonce: nonce, Block: block, Tag: tag, Payload: payload,
+		Scheme: signer.Scheme(), Version: TransactionVersionAt(block), Tip: tip,
+	}
+
+	// Only the canonical encoding signs stamps and tips.
+	if difficulty > 0 || tip > 0 {
+		tx.Version = canonical.Version
+	}
+
+	if difficulty > 0 {
+		tx.Stamp = FindStamp(tx, difficulty)
+	}
+
+	sig, err := signer.Sign(tx.Message())
+	if err != nil {
+		return Transaction{}, errors.Wrap(err, "failed to sign transaction")
+	}
+
+	if len(sig) != SizeSignature {
+		return Transaction{}, errors.Errorf("signature of scheme %s must be %d bytes to sign transactions", signer.Scheme(), SizeSignature)
+	}
+
+	copy(signature[:], sig)
+
+	return NewSignedTippedTransaction(
+		tx.Version, signer.Scheme(), sender, nonce, block, tag, payload, tx.Stamp, tip, signature,
+	), nil
 }
 
 func NewSignedTransaction(
 	sender edwards25519.PublicKey, nonce, block uint64, tag sys.Tag, payload []byte, signature edwards25519.Signature,
 ) Transaction {
-	tx := Transaction{Sender: sender, Nonce: nonce, Block: block, Tag: tag, Payload: payload, Signature: signature}
+	return NewSignedTransactionWithScheme(security.SchemeEd25519, sender, nonce, block, tag, payload, signature)
+}
+
+// NewSignedTransactionWithScheme is NewSignedTransaction for a signature of
+// the given scheme.
+func NewSignedTransactionWithScheme(
+	scheme security.Scheme, sender AccountID, nonce, block uint64, tag sys.Tag, payload []byte, signature Signature,
+) Transaction {
+	return NewSignedTransactionWithVersion(0, scheme, sender, nonce, block, tag, payload, signature)
+}
+
+// NewSignedTransactionWithVersion is NewSignedTransactionWithScheme for a
+// signature of the given version of the canonical encoding of the
+// transaction.
+func NewSignedTransactionWithVersion(
+	version byte, scheme security.Scheme, sender AccountID, nonce, block uint64, tag sys.Tag, payload []byte,
+	signature Signature,
+) Transaction {
+	return NewSignedStampedTransaction(version, scheme, sender, nonce, block, tag, payload, 0, signature)
+}
+
+// NewSignedStampedTransaction is NewSignedTransactionWithVersion for a
+// transaction carrying the given proof-of-work stamp.
+func NewSignedStampedTransaction(
+	version byte, scheme security.Scheme, sender AccountID, nonce, block uint64, tag sys.Tag, payload []byte,
+	stamp uint64, signature Signature,
+) Transaction {
+	return NewSignedTippedTransaction(version, scheme, sender, nonce, block, tag, payload, stamp, 0, signature)
+}
+
+// NewSignedTippedTransaction is NewSignedStampedTransaction for a transaction
+// paying the given tip on top of its fee.
+func NewSignedTippedTransaction(
+	version byte, scheme security.Scheme, sender AccountID, nonce, block uint64, tag sys.Tag, payload []byte,
+	stamp, tip uint64, signature Signature,
+) Transaction {
+	tx := Transaction{
+		Sender: sender, Nonce: nonce, Block: block, Tag: tag, Payload: payload,
+		Scheme: scheme, Version: version, Stamp: stamp, Tip: tip, Signature: signature,
+	}
 	tx.ID = blake2b.Sum256(tx.Marshal())
 
 	return tx
 }
 
+// Message returns the message the signature of tx signs.
+//
+// Transactions of version canonical.Version sign the canonical encoding of
+// their sender, scheme, nonce, block, tag and payload, in that order, under
+// the domain "wavelet/transaction", followed by their stamp if they carry
+// one. Transactions paying a tip are followed by their stamp, 0 if they
+// carry none, and their tip. Transactions of version 0 sign the
+// legacy encoding of TransactionMessage instead. Transactions of any other
+// version have no message, and hence no valid signature.
+func (tx Transaction) Message() []byte {
+	switch tx.Version {
+	case 0:
+		return TransactionMessage(tx.Scheme, tx.Nonce, tx.Block, tx.Tag, tx.Payload)
+	case canonical.Version:
+		e := canonical.New(transactionDomain).
+			Fixed(tx.Sender[:]).
+			Uint8(byte(tx.Scheme)).
+			Uint64(tx.Nonce).
+			Uint64(tx.Block).
+			Uint8(byte(tx.Tag)).
+			Bytes(tx.Payload)
+
+		if tx.Stamp != 0 || tx.Tip != 0 {
+			e.Uint64(tx.Stamp)
+		}
+
+		if tx.Tip != 0 {
+			e.Uint64(tx.Tip)
+		}
+
+		return e.Encode()
+	}
+
+	return nil
+}
+
+// TransactionMessage returns the legacy message a transaction's signature
+// signs, which transactions of version 0 are signed with. Signatures of
+// schemes other than Ed25519 additionally sign the scheme.
+func TransactionMessage(scheme security.Scheme, nonce, block uint64, tag sys.Tag, payload []byte) []byte {
+	message := make([]byte, 0, 8+8+2+len(payload))
+
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], nonce)
+	message = append(message, buf[:]...)
+
+	binary.BigEndian.PutUint64(buf[:], block)
+	message = append(message, buf[:]...)
+
+	if scheme == security.SchemeEd25519 {
+		message = append(message, byte(tag))
+	} else {
+		message = append(message, byte(tag)|tagFlagScheme, byte(scheme))
+	}
+
+	return append(message, payload...)
+}
+
 func (tx Transaction) Marshal() []byte {
-	w := bytes.NewBuffer(make([]byte, 0, 32+8+8+1+4+len(tx.Payload)+64))
+	w := bytes.NewBuffer(make([]byte, 0, 32+8+8+2+4+len(tx.Payload)+64))
 
 	w.Write(tx.Sender[:])
 
@@ -83,7 +318,43 @@ func (tx Transaction) Marshal() []byte {
 	binary.BigEndian.PutUint64(buf[:8], tx.Block)
 	w.Write(buf[:8])
 
-	w.WriteByte(byte(tx.Tag))
+	tag := byte(tx.Tag)
+
+	if tx.Scheme != security.SchemeEd25519 {
+		tag |= tagFlagScheme
+	}
+
+	if tx.Version != 0 {
+		tag |= tagFlagVersion
+	}
+
+	if tx.Stamp != 0 {
+		tag |= tagFlagStamp
+	}
+
+	if tx.Tip != 0 {
+		tag |= tagFlagTip
+	}
+
+	w.WriteByte(tag)
+
+	if tx.Scheme != security.SchemeEd25519 {
+		w.WriteByte(byte(tx.Scheme))
+	}
+
+	if tx.Version != 0 {
+		w.WriteByte(tx.Version)
+	}
+
+	if tx.Stamp != 0 {
+		binary.BigEndian.PutUint64(buf[:8], tx.Stamp)
+		w.Write(buf[:8])
+	}
+
+	if tx.Tip != 0 {
+		binary.BigEndian.PutUint64(buf[:8], tx.Tip)
+		w.Write(buf[:8])
+	}
 
 	binary.BigEndian.PutUint32(buf[:4], uint32(len(tx.Payload)))
 	w.Write(buf[:4])
@@ -122,21 +393,92 @@ func UnmarshalTransaction(r io.Reader) (t Transaction, err error) {
 		return
 	}
 
-	t.Tag = sys.Tag(buf[0])
+	flags := buf[0] & tagFlags
+	t.Tag = sys.Tag(buf[0] &^ flags)
 
-	if t.Tag < sys.TagTransfer || t.Tag > sys.TagBatch {
-		err = errors.Wrapf(err, "got an unknown tag %d", t.Tag)
+	if t.Tag < sys.TagTransfer || t.Tag > maxTag {
+		err = errors.Errorf("got an unknown tag %d", t.Tag)
 		return
 	}
 
+	if flags&tagFlagScheme != 0 {
+		if _, err = io.ReadFull(r, buf[:1]); err != nil {
+			err = errors.Wrap(err, "failed to read signature scheme")
+			return
+		}
+
+		t.Scheme = security.Scheme(buf[0])
+
+		// The default scheme is never marshaled, such that every transaction
+		// has exactly one encoding, and hence one ID.
+		if t.Scheme == security.SchemeEd25519 {
+			err = errors.New("default signature scheme must not be marshaled explicitly")
+			return
+		}
+	}
+
+	if flags&tagFlagVersion != 0 {
+		if _, err = io.ReadFull(r, buf[:1]); err != nil {
+			err = errors.Wrap(err, "failed to read transaction version")
+			return
+		}
+
+		t.Version = buf[0]
+
+		// Likewise, the legacy version is never marshaled.
+		if t.Version != canonical.Version {
+			err = errors.Errorf("got an unsupported transaction version %d", t.Version)
+			return
+		}
+	}
+
+	if flags&tagFlagStamp != 0 {
+		// Only the canonical encoding signs the stamp.
+		if t.Version != canonical.Version {
+			err = errors.Errorf("stamped transactions must be of version %d", canonical.Version)
+			return
+		}
+
+		if _, err = io.ReadFull(r, buf[:8]); err != nil {
+			err = errors.Wrap(err, "failed to read transaction stamp")
+			return
+		}
+
+		t.Stamp = binary.BigEndian.Uint64(buf[:8])
+
+		// Nor is the lack of a stamp marshaled.
+		if t.Stamp == 0 {
+			err = errors.New("empty stamp must not be marshaled explicitly")
+			return
+		}
+	}
+
+	if flags&tagFlagTip != 0 {
+		// Only the canonical encoding signs the tip.
+		if t.Version != canonical.Version {
+			err = errors.Errorf("tipped transactions must be of version %d", canonical.Version)
+			return
+		}
+
+		if _, err = io.ReadFull(r, buf[:8]); err != nil {
+			err = errors.Wrap(err, "failed to read transaction tip")
+			return
+		}
+
+		t.Tip = binary.BigEndian.Uint64(buf[:8])
+
+		if t.Tip == 0 {
+			err = errors.New("empty tip must not be marshaled explicitly")
+			return
+		}
+	}
+
 	if _, err = io.ReadFull(r, buf[:4]); err != nil {
 		err = errors.Wrap(err, "could not read transaction payload length")
 		return
 	}
 
-	t.Payload = make([]byte, binary.BigEndian.Uint32(buf[:4]))
-
-	if _, err = io.ReadFull(r, t.Payload); err != nil {
+	if t.Payload, err = readBytes(r, binary.BigEndian.Uint32(buf[:4])); err != nil {
 		err = errors.Wrap(err, "could not read transaction payload")
 		return
 	}
@@ -148,15 +490,100 @@ func UnmarshalTransaction(r io.Reader) (t Transaction, err error) {
 
 	t.ID = blake2b.Sum256(t.Marshal())
 
+	if err = t.checkFeatures(); err != nil {
+		return
+	}
+
 	return t, nil
 }
 
+// ParseTransaction unmarshals the transaction encoded by buf, which must be
+// its one and only encoding. The ID of a transaction being derived from its
+// encoding, no variant of a transaction re-encoded under another ID is hence
+// accepted.
+func ParseTransaction(buf []byte) (Transaction, error) {
+	r := bytes.NewReader(buf)
+
+	tx, err := UnmarshalTransaction(r)
+	if err != nil {
+		return tx, err
+	}
+
+	if r.Len() > 0 {
+		return tx, errors.Errorf("got %d trailing bytes after transaction", r.Len())
+	}
+
+	return tx, nil
+}
+
 func (tx Transaction) ComputeIndex(id BlockID) []byte {
 	idx := blake2b.Sum256(append(tx.ID[:], id[:]...))
 	return idx[:]
 }
 
+// earliestHeight returns the height of the earliest block tx may be applied
+// at, being the block succeeding the one it was created against. Checks of tx
+// made ahead of applying it are made at this height.
+func (tx Transaction) earliestHeight() uint64 {
+	return tx.Block + 1
+}
+
+// checkFeatures returns an error should tx make use of a field whose feature
+// is yet to activate at the earliest height tx may be applied at, such that
+// nodes ahead of the schedule of features accept no transaction the rest of
+// the network rejects.
+func (tx Transaction) checkFeatures() error {
+	height := tx.earliestHeight()
+
+	if tx.Scheme != security.SchemeEd25519 && !sys.FeatureActive(sys.FeatureSignatureSchemes, height) {
+		return errors.Errorf("signature scheme %s is not yet active at block %d", tx.Scheme, height)
+	}
+
+	if tx.Version != 0 && !sys.FeatureActive(sys.FeatureCanonicalTransactions, height) {
+		return errors.Errorf("transaction version %d is not yet active at block %d", tx.Version, height)
+	}
+
+	if tx.Stamp != 0 && !sys.FeatureActive(sys.FeatureStamps, height) {
+		return errors.Errorf("transaction stamps are not yet active at block %d", height)
+	}
+
+	if tx.Tip != 0 && !sys.FeatureActive(sys.FeatureReplacement, height) {
+		return errors.Errorf("transaction tips are not yet active at block %d", height)
+	}
+
+	return nil
+}
+
+// Fee returns the fee paid for tx, including its tip once
+// sys.FeatureReplacement activates. Transactions stamped with a proof of work
+// meeting sys.MinStampDifficulty pay no fee but their tip once
+// sys.FeatureStamps activates. Data transactions pay for every byte they
+// anchor, on top of the default fee.
 func (tx Transaction) Fee() uint64 {
+	fee := tx.baseFee()
+
+	if !sys.FeatureActive(sys.FeatureReplacement, tx.earliestHeight()) {
+		return fee
+	}
+
+	// Tips past the balance of any account may only saturate the fee.
+	if fee > math.MaxUint64-tx.Tip {
+		return math.MaxUint64
+	}
+
+	return fee + tx.Tip
+}
+
+func (tx Transaction) baseFee() uint64 {
+	if tx.Stamp != 0 && sys.FeatureActive(sys.FeatureStamps, tx.earliestHeight()) &&
+		tx.StampDifficulty() >= sys.MinStampDifficulty {
+		return 0
+	}
+
+	if tx.Tag == sys.TagData {
+		return sys.DefaultTransactionFee + sys.DataFeePerByte*uint64(len(tx.Payload))
+	}
+
 	fee := uint64(sys.TransactionFeeMultiplier * float64(len(tx.Payload)))
 	if fee < sys.DefaultTransactionFee {
 		return sys.DefaultTransactionFee
@@ -185,20 +612,13 @@ func (tx Transaction) String() string {
 	return fmt.Sprintf("Transaction{ID: %x}", tx.ID)
 }
 
+// VerifySignature verifies the signature of the transaction with the
+// verifier registered for its scheme.
 func (tx Transaction) VerifySignature() bool {
-	var (
-		nonceBuf [8]byte
-		blockBuf [8]byte
-	)
-
-	binary.BigEndian.PutUint64(nonceBuf[:], tx.Nonce)
-	binary.BigEndian.PutUint64(blockBuf[:], tx.Block)
-
-	message := make([]byte, 0, 8+8+1+len(tx.Payload))
-	message = append(message, nonceBuf[:]...)
-	message = append(message, blockBuf[:]...)
-	message = append(message, byte(tx.Tag))
-	message = append(message, tx.Payload...)
+	message := tx.Message()
+	if message == nil {
+		return false
+	}
 
-	return edwards25519.Verify(tx.Sender, message, tx.Signature)
+	return security.Verify(tx.Scheme, tx.Sender[:], message, tx.Signature[:]) == nil
 }
@@ -22,7 +22,6 @@ package wavelet
 import (
 	"encoding/hex"
 
-	wasm "github.com/perlin-network/life/wasm-validation"
 	"github.com/perlin-network/wavelet/avl"
 
 	"github.com/perlin-network/wavelet/log"
@@ -34,6 +33,7 @@ type contractExecutorState struct {
 	GasPayer      AccountID
 	GasLimit      uint64
 	GasLimitIsSet bool
+	GasUsed       uint64
 	Context       *CollapseContext
 }
 
@@ -49,6 +49,10 @@ func ApplyTransaction(tree *avl.Tree, block *Block, tx *Transaction) error {
 }
 
 func applyTransaction(block *Block, ctx *CollapseContext, tx *Transaction, executorState *contractExecutorState) error {
+	if !sys.TagActive(tx.Tag, block.Index+1) {
+		return errors.Wrapf(ErrTagInactive, "tag %d at block %d", tx.Tag, block.Index+1)
+	}
+
 	switch tx.Tag {
 	case sys.TagTransfer:
 		if err := applyTransferTransaction(ctx, block, tx, executorState); err != nil {
@@ -66,6 +70,42 @@ func applyTransaction(block *Block, ctx *CollapseContext, tx *Transaction, execu
 		if err := applyBatchTransaction(ctx, block, tx, executorState); err != nil {
 			return errors.Wrap(err, "could not apply batch transaction")
 		}
+	case sys.TagBeacon:
+		if err := applyBeaconTransaction(ctx, block, tx); err != nil {
+			return errors.Wrap(err, "could not apply beacon transaction")
+		}
+	case sys.TagFeeGrant:
+		if err := applyFeeGrantTransaction(ctx, tx); err != nil {
+			return errors.Wrap(err, "could not apply fee grant transaction")
+		}
+	case sys.TagRecovery:
+		if err := applyRecoveryTransaction(ctx, block, tx); err != nil {
+			return errors.Wrap(err, "could not apply recovery transaction")
+		}
+	case sys.TagName:
+		if err := applyNameTransaction(ctx, block, tx); err != nil {
+			return errors.Wrap(err, "could not apply name transaction")
+		}
+	case sys.TagData:
+		if err := applyDataTransaction(ctx, tx); err != nil {
+			return errors.Wrap(err, "could not apply data transaction")
+		}
+	case sys.TagUpgrade:
+		if err := applyUpgradeTransaction(ctx, block, tx); err != nil {
+			return errors.Wrap(err, "could not apply upgrade transaction")
+		}
+	case sys.TagMultisig:
+		if err := applyMultisigTransaction(ctx, block, tx); err != nil {
+			return errors.Wrap(err, "could not apply multisig transaction")
+		}
+	case sys.TagDelegation:
+		if err := applyDelegationTransaction(ctx, block, tx); err != nil {
+			return errors.Wrap(err, "could not apply delegation transaction")
+		}
+	case sys.TagSlashing:
+		if err := applySlashingTransaction(ctx, block, tx); err != nil {
+			return errors.Wrap(err, "could not apply slashing transaction")
+		}
 	}
 
 	return nil
@@ -201,8 +241,8 @@ func applyContractTransaction(ctx *CollapseContext, block *Block, tx *Transactio
 	}
 
 	// Record the code of the smart contract into the ledgers state.
-	if err := wasm.GetValidator().ValidateWasm(payload.Code); err != nil {
-		return errors.Wrap(err, "invalid wasm")
+	if err := validateContractCode(payload.Code, block.Index+1); err != nil {
+		return err
 	}
 
 	ctx.WriteAccountContractCode(tx.ID, payload.Code)
@@ -247,6 +287,19 @@ func applyBatchTransaction(ctx *CollapseContext, block *Block, tx *Transaction,
 	return nil
 }
 
+// applyDataTransaction anchors the blob carried by tx in the ledger state,
+// keyed by the ID of tx.
+func applyDataTransaction(ctx *CollapseContext, tx *Transaction) error {
+	payload, err := ParseData(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	ctx.WriteData(tx.ID, payload.Blob)
+
+	return nil
+}
+
 // Transfers value of any form (balance, gasDeposit/gasBalance).
 func transferValue(
 	unitName string,
@@ -314,7 +367,7 @@ func executeContractInTransactionContext(
 		)
 	}
 
-	executor := &ContractExecutor{}
+	executor := &ContractExecutor{calls: newContractCalls(ctx)}
 
 	var contractState *VMState
 	contractState, _ = ctx.GetContractState(contractID)
@@ -347,6 +400,7 @@ func executeContractInTransactionContext(
 		}
 
 		state.GasLimit -= executor.Gas
+		state.GasUsed += executor.Gas
 
 		if executor.GasLimitExceeded {
 			logger.Info().
@@ -361,6 +415,7 @@ func executeContractInTransactionContext(
 	} else {
 		// Contract invocation succeeded. VM state can be safely saved now.
 		ctx.SetContractState(contractID, newContractState)
+		ctx.emitLogs(executor.Logs)
 
 		if executor.Gas > contractGasBalance {
 			ctx.WriteAccountContractGasBalance(contractID, 0)
@@ -372,6 +427,12 @@ func executeContractInTransactionContext(
 			ctx.WriteAccountContractGasBalance(contractID, contractGasBalance-executor.Gas)
 		}
 		state.GasLimit -= executor.Gas
+		state.GasUsed += executor.Gas
+
+		// Changes made by the contracts called are only written once the
+		// gas payer is charged, as they are charged against its balance
+		// read before the contract was executed.
+		executor.calls.commit()
 
 		//logger.Info().
 		//	Uint64("gas", executor.Gas).
@@ -33,6 +33,7 @@ import (
 	"github.com/perlin-network/wavelet/avl"
 	"github.com/perlin-network/wavelet/store"
 	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -256,6 +257,85 @@ func TestApplyStakeTransaction(t *testing.T) {
 	assert.Equal(t, finalBalance, uint64(100))
 }
 
+// Not parallel, as the schedule of features is global.
+func TestApplyDataTransaction(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureData)()
+
+	accounts := NewAccounts(store.NewInmem())
+	block := NewBlock(0, accounts.tree.Checksum())
+
+	account, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	blob := make([]byte, sys.MaxDataSize)
+	rand.Read(blob)
+
+	tx := NewTransaction(account, 1, block.Index, sys.TagData, blob)
+
+	// Data transactions pay for every byte they anchor.
+	fee := sys.DefaultTransactionFee + sys.DataFeePerByte*uint64(len(blob))
+	assert.Equal(t, fee, tx.Fee())
+
+	WriteAccountBalance(accounts.tree, account.PublicKey(), fee-1)
+	assert.Error(t, ValidateTransaction(accounts.tree, tx))
+
+	WriteAccountBalance(accounts.tree, account.PublicKey(), fee)
+	assert.NoError(t, ValidateTransaction(accounts.tree, tx))
+
+	res, err := collapseTransactions(block.Index+1, []*Transaction{&tx}, &block, accounts)
+	if !assert.NoError(t, err) || !assert.Len(t, res.applied, 1) {
+		return
+	}
+
+	balance, _ := ReadAccountBalance(res.snapshot, account.PublicKey())
+	assert.EqualValues(t, 0, balance)
+
+	anchored, exists := ReadData(res.snapshot, tx.ID)
+	assert.True(t, exists)
+	assert.Equal(t, blob, anchored)
+
+	// Data may not exceed the size limit.
+	tx = NewTransaction(account, 2, block.Index, sys.TagData, append(blob, 0))
+	assert.Error(t, ValidateTransaction(accounts.tree, tx))
+	assert.Error(t, ApplyTransaction(accounts.tree, &block, &tx))
+}
+
+// Not parallel, as the schedule of features is global.
+func TestApplyTransactionFeatureGate(t *testing.T) {
+	activation := sys.FeatureActivations[sys.FeatureData]
+	sys.FeatureActivations[sys.FeatureData] = 2
+
+	defer func() {
+		sys.FeatureActivations[sys.FeatureData] = activation
+	}()
+
+	assert.NotContains(t, sys.ActiveFeatures(1), sys.FeatureData)
+	assert.Contains(t, sys.ActiveFeatures(2), sys.FeatureData)
+
+	accounts := NewAccounts(store.NewInmem())
+
+	account, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	WriteAccountBalance(accounts.tree, account.PublicKey(), 1000)
+
+	// Transactions may not be created against blocks succeeding which their
+	// tag is inactive.
+	tx := NewTransaction(account, 1, 0, sys.TagData, []byte("data"))
+	assert.Equal(t, ErrTagInactive, errors.Cause(ValidateTransaction(accounts.tree, tx)))
+
+	tx = NewTransaction(account, 1, 1, sys.TagData, []byte("data"))
+	assert.NoError(t, ValidateTransaction(accounts.tree, tx))
+
+	// Nor may they be applied to blocks succeeding which their tag is
+	// inactive.
+	before := NewBlock(0, accounts.tree.Checksum())
+	assert.Equal(t, ErrTagInactive, errors.Cause(ApplyTransaction(accounts.tree, &before, &tx)))
+
+	after := NewBlock(1, accounts.tree.Checksum())
+	assert.NoError(t, ApplyTransaction(accounts.tree, &after, &tx))
+}
+
 func TestApplyBatchTransaction(t *testing.T) {
 	t.Parallel()
 
@@ -7,10 +7,34 @@ import (
 	"io"
 	"io/ioutil"
 
+	"github.com/perlin-network/wavelet/security/vrf"
 	"github.com/perlin-network/wavelet/sys"
 	"github.com/pkg/errors"
 )
 
+// Payload is the decoded payload of a transaction, which may be marshaled back
+// into its binary representation.
+type Payload interface {
+	Tag() sys.Tag
+	Marshal() ([]byte, error)
+}
+
+var (
+	_ Payload = (*Transfer)(nil)
+	_ Payload = (*Stake)(nil)
+	_ Payload = (*Contract)(nil)
+	_ Payload = (*Batch)(nil)
+	_ Payload = (*Beacon)(nil)
+	_ Payload = (*FeeGrant)(nil)
+	_ Payload = (*Recovery)(nil)
+	_ Payload = (*Name)(nil)
+	_ Payload = (*Data)(nil)
+	_ Payload = (*Upgrade)(nil)
+	_ Payload = (*Multisig)(nil)
+	_ Payload = (*Delegation)(nil)
+	_ Payload = (*Slashing)(nil)
+)
+
 type (
 	Transfer struct {
 		Recipient AccountID
@@ -45,8 +69,153 @@ type (
 		Tags     []uint8
 		Payloads [][]byte
 	}
+
+	Beacon struct {
+		Proof vrf.Proof
+	}
+
+	// FeeGrant grants Grantee an allowance of PERLs the sender pays the fees
+	// of the transactions of Grantee with. An allowance of zero revokes the
+	// grant.
+	FeeGrant struct {
+		Grantee   AccountID
+		Allowance uint64
+	}
+
+	// Recovery configures, or acts upon the recovery of, an account whose
+	// keys were lost. Which fields are set depends on Opcode:
+	//
+	//	ConfigureRecovery: Guardians, Threshold, Delay (no guardians removes the configuration)
+	//	InitiateRecovery, ApproveRecovery: Account, Recipient
+	//	CancelRecovery: none
+	//	CompleteRecovery: Account
+	Recovery struct {
+		Opcode byte
+
+		Guardians []AccountID
+		Threshold uint8
+		Delay     uint64
+
+		Account   AccountID
+		Recipient AccountID
+	}
+
+	// Name registers, renews, or transfers a name of the name service. Target
+	// is the account or contract the name resolves to when registering, and
+	// the new owner of the name when transferring it.
+	Name struct {
+		Opcode byte
+		Name   string
+		Target AccountID
+	}
+
+	// Data anchors an arbitrary blob, such as a document hash or an
+	// attestation, into the ledger under the ID of its transaction.
+	Data struct {
+		Blob []byte
+	}
+
+	// Upgrade configures the upgrader of a smart contract, or replaces the
+	// code of the contract on behalf of its upgrader. Which fields are set
+	// depends on Opcode:
+	//
+	//	ConfigureUpgrader: Contract, Upgrader (a zero upgrader freezes the code of the contract for good)
+	//	UpgradeContract: Contract, Code
+	//
+	// A zero Contract configures the upgrader of the contract spawned by the
+	// batch the upgrade is an entry of.
+	Upgrade struct {
+		Opcode   byte
+		Contract AccountID
+
+		Upgrader AccountID
+		Code     []byte
+	}
+
+	// Multisig creates an account whose PERLs are only moved once Threshold
+	// of its participants approve, or acts upon a transfer proposed from
+	// such an account. Which fields are set depends on Opcode:
+	//
+	//	CreateMultisig: Participants, Threshold
+	//	ProposeMultisig: Account, Recipient, Amount
+	//	ApproveMultisig, ExecuteMultisig, CancelMultisig: Proposal
+	Multisig struct {
+		Opcode byte
+
+		Participants []AccountID
+		Threshold    uint8
+
+		Account   AccountID
+		Recipient AccountID
+		Amount    uint64
+
+		Proposal TransactionID
+	}
+
+	// Delegation bonds PERLs to a validator, sharing in its rewards, or
+	// unbonds or claims them back. Which fields are set depends on Opcode:
+	//
+	//	DelegateStake, UndelegateStake: Validator, Amount
+	//	ClaimUnbonded: nothing
+	//	SetCommission: Commission, in hundredths of a percent
+	Delegation struct {
+		Opcode byte
+
+		Validator AccountID
+		Amount    uint64
+
+		Commission uint16
+	}
+
+	// Slashing reports a validator for misbehaving, slashing a fraction of
+	// its stake. Which fields are set depends on Opcode:
+	//
+	//	ReportDoubleSign: Evidence, two conflicting transactions signed by
+	//	the validator, which is their sender
+	//	ReportDowntime: Validator
+	Slashing struct {
+		Opcode byte
+
+		Validator AccountID
+		Evidence  [2]Transaction
+	}
 )
 
+// ParsePayload parses and performs sanity checks on the payload of a transaction
+// given its tag.
+func ParsePayload(tag sys.Tag, payload []byte) (Payload, error) {
+	switch tag {
+	case sys.TagTransfer:
+		return ParseTransfer(payload)
+	case sys.TagStake:
+		return ParseStake(payload)
+	case sys.TagContract:
+		return ParseContract(payload)
+	case sys.TagBatch:
+		return ParseBatch(payload)
+	case sys.TagBeacon:
+		return ParseBeacon(payload)
+	case sys.TagFeeGrant:
+		return ParseFeeGrant(payload)
+	case sys.TagRecovery:
+		return ParseRecovery(payload)
+	case sys.TagName:
+		return ParseName(payload)
+	case sys.TagData:
+		return ParseData(payload)
+	case sys.TagUpgrade:
+		return ParseUpgrade(payload)
+	case sys.TagMultisig:
+		return ParseMultisig(payload)
+	case sys.TagDelegation:
+		return ParseDelegation(payload)
+	case sys.TagSlashing:
+		return ParseSlashing(payload)
+	}
+
+	return nil, errors.Errorf("payload: unknown transaction tag %d", tag)
+}
+
 // ParseTransfer parses and performs sanity checks on the payload of a transfer transaction.
 func ParseTransfer(payload []byte) (Transfer, error) {
 	r := bytes.NewReader(payload)
@@ -243,6 +412,38 @@ func ParseBatch(payload []byte) (Batch, error) {
 			return batch, errors.New("batch: entries inside batch cannot be batch transactions themselves")
 		}
 
+		if sys.Tag(b[0]) == sys.TagBeacon {
+			return batch, errors.New("batch: entries inside batch cannot be beacon transactions")
+		}
+
+		if sys.Tag(b[0]) == sys.TagFeeGrant {
+			return batch, errors.New("batch: entries inside batch cannot be fee grant transactions")
+		}
+
+		if sys.Tag(b[0]) == sys.TagRecovery {
+			return batch, errors.New("batch: entries inside batch cannot be recovery transactions")
+		}
+
+		if sys.Tag(b[0]) == sys.TagName {
+			return batch, errors.New("batch: entries inside batch cannot be name transactions")
+		}
+
+		if sys.Tag(b[0]) == sys.TagData {
+			return batch, errors.New("batch: entries inside batch cannot be data transactions")
+		}
+
+		if sys.Tag(b[0]) == sys.TagMultisig {
+			return batch, errors.New("batch: entries inside batch cannot be multisig transactions")
+		}
+
+		if sys.Tag(b[0]) == sys.TagDelegation {
+			return batch, errors.New("batch: entries inside batch cannot be delegation transactions")
+		}
+
+		if sys.Tag(b[0]) == sys.TagSlashing {
+			return batch, errors.New("batch: entries inside batch cannot be slashing transactions")
+		}
+
 		batch.Tags[i] = b[0]
 
 		if _, err := io.ReadFull(r, b[:4]); err != nil {
@@ -264,7 +465,416 @@ func ParseBatch(payload []byte) (Batch, error) {
 	return batch, nil
 }
 
+// ParseBeacon parses and performs sanity checks on the payload of a beacon transaction.
+func ParseBeacon(payload []byte) (Beacon, error) {
+	var beacon Beacon
+
+	if len(payload) != vrf.SizeProof {
+		return beacon, errors.Errorf("beacon: payload must be exactly %d bytes", vrf.SizeProof)
+	}
+
+	copy(beacon.Proof[:], payload)
+
+	return beacon, nil
+}
+
+// ParseFeeGrant parses and performs sanity checks on the payload of a fee grant transaction.
+func ParseFeeGrant(payload []byte) (FeeGrant, error) {
+	var grant FeeGrant
+
+	if len(payload) != SizeAccountID+8 {
+		return grant, errors.Errorf("fee grant: payload must be exactly %d bytes", SizeAccountID+8)
+	}
+
+	copy(grant.Grantee[:], payload[:SizeAccountID])
+	grant.Allowance = binary.LittleEndian.Uint64(payload[SizeAccountID:])
+
+	return grant, nil
+}
+
+// ParseRecovery parses and performs sanity checks on the payload of a recovery transaction.
+func ParseRecovery(payload []byte) (Recovery, error) {
+	var recovery Recovery
+
+	if len(payload) == 0 {
+		return recovery, errors.New("recovery: payload must not be empty")
+	}
+
+	recovery.Opcode = payload[0]
+	payload = payload[1:]
+
+	switch recovery.Opcode {
+	case sys.ConfigureRecovery:
+		if len(payload) < 1+8+1 {
+			return recovery, errors.New("recovery: configuration must comprise a threshold, delay and guardians")
+		}
+
+		recovery.Threshold = payload[0]
+		recovery.Delay = binary.LittleEndian.Uint64(payload[1:9])
+
+		count := int(payload[9])
+		payload = payload[10:]
+
+		if count > sys.MaxRecoveryGuardians {
+			return recovery, errors.Errorf("recovery: at most %d guardians may be configured", sys.MaxRecoveryGuardians)
+		}
+
+		if len(payload) != count*SizeAccountID {
+			return recovery, errors.Errorf("recovery: expected %d guardians", count)
+		}
+
+		if count == 0 {
+			if recovery.Threshold != 0 || recovery.Delay != 0 {
+				return recovery, errors.New("recovery: removing a configuration must not specify a threshold or delay")
+			}
+
+			return recovery, nil
+		}
+
+		if recovery.Threshold == 0 || int(recovery.Threshold) > count {
+			return recovery, errors.Errorf("recovery: threshold must be between 1 and %d", count)
+		}
+
+		recovery.Guardians = make([]AccountID, count)
+
+		for i := range recovery.Guardians {
+			copy(recovery.Guardians[i][:], payload[i*SizeAccountID:])
+
+			for j := 0; j < i; j++ {
+				if recovery.Guardians[i] == recovery.Guardians[j] {
+					return recovery, errors.Errorf("recovery: guardian %x is listed twice", recovery.Guardians[i])
+				}
+			}
+		}
+	case sys.InitiateRecovery, sys.ApproveRecovery:
+		if len(payload) != SizeAccountID*2 {
+			return recovery, errors.New("recovery: an account and recipient must be specified")
+		}
+
+		copy(recovery.Account[:], payload[:SizeAccountID])
+		copy(recovery.Recipient[:], payload[SizeAccountID:])
+
+		if recovery.Account == recovery.Recipient {
+			return recovery, errors.New("recovery: an account may not be recovered to itself")
+		}
+	case sys.CancelRecovery:
+		if len(payload) != 0 {
+			return recovery, errors.New("recovery: cancellations must not specify anything")
+		}
+	case sys.CompleteRecovery:
+		if len(payload) != SizeAccountID {
+			return recovery, errors.New("recovery: an account must be specified")
+		}
+
+		copy(recovery.Account[:], payload)
+	default:
+		return recovery, errors.Errorf("recovery: unknown opcode %d", recovery.Opcode)
+	}
+
+	return recovery, nil
+}
+
+// ParseName parses and performs sanity checks on the payload of a name transaction.
+func ParseName(payload []byte) (Name, error) {
+	var name Name
+
+	if len(payload) < 2 {
+		return name, errors.New("name: payload must comprise an opcode and a name")
+	}
+
+	name.Opcode = payload[0]
+
+	if name.Opcode > sys.TransferName {
+		return name, errors.New("name: opcode must be 0, 1, or 2")
+	}
+
+	size := int(payload[1])
+	payload = payload[2:]
+
+	if len(payload) < size {
+		return name, errors.New("name: payload is too short to comprise the name")
+	}
+
+	name.Name = string(payload[:size])
+	payload = payload[size:]
+
+	if err := ValidateName(name.Name); err != nil {
+		return name, err
+	}
+
+	if name.Opcode == sys.RenewName {
+		if len(payload) != 0 {
+			return name, errors.New("name: renewals must only specify a name")
+		}
+
+		return name, nil
+	}
+
+	if len(payload) != SizeAccountID {
+		return name, errors.New("name: registrations and transfers must specify a target account")
+	}
+
+	copy(name.Target[:], payload)
+
+	return name, nil
+}
+
+// ParseData parses and performs sanity checks on the payload of a data transaction.
+func ParseData(payload []byte) (Data, error) {
+	var data Data
+
+	if len(payload) == 0 || len(payload) > sys.MaxDataSize {
+		return data, errors.Errorf("data: payload must be between 1 and %d bytes", sys.MaxDataSize)
+	}
+
+	data.Blob = payload
+
+	return data, nil
+}
+
+// ParseUpgrade parses and performs sanity checks on the payload of an upgrade transaction.
+func ParseUpgrade(payload []byte) (Upgrade, error) {
+	var upgrade Upgrade
+
+	if len(payload) < 1+SizeAccountID {
+		return upgrade, errors.New("upgrade: payload must comprise an opcode and a contract")
+	}
+
+	upgrade.Opcode = payload[0]
+	copy(upgrade.Contract[:], payload[1:1+SizeAccountID])
+
+	payload = payload[1+SizeAccountID:]
+
+	switch upgrade.Opcode {
+	case sys.ConfigureUpgrader:
+		if len(payload) != SizeAccountID {
+			return upgrade, errors.New("upgrade: configuring the upgrader of a contract must specify the upgrader")
+		}
+
+		copy(upgrade.Upgrader[:], payload)
+	case sys.UpgradeContract:
+		if len(payload) == 0 {
+			return upgrade, errors.New("upgrade: smart contract must have code of length greater than zero")
+		}
+
+		upgrade.Code = payload
+	default:
+		return upgrade, errors.Errorf("upgrade: unknown opcode %d", upgrade.Opcode)
+	}
+
+	return upgrade, nil
+}
+
+// ParseMultisig parses and performs sanity checks on the payload of a multisig transaction.
+func ParseMultisig(payload []byte) (Multisig, error) {
+	var multisig Multisig
+
+	if len(payload) == 0 {
+		return multisig, errors.New("multisig: payload must not be empty")
+	}
+
+	multisig.Opcode = payload[0]
+	payload = payload[1:]
+
+	switch multisig.Opcode {
+	case sys.CreateMultisig:
+		if len(payload) < 1+1 {
+			return multisig, errors.New("multisig: creation must comprise a threshold and participants")
+		}
+
+		multisig.Threshold = payload[0]
+
+		count := int(payload[1])
+		payload = payload[2:]
+
+		if count == 0 || count > sys.MaxMultisigParticipants {
+			return multisig, errors.Errorf("multisig: must have between 1 and %d participants", sys.MaxMultisigParticipants)
+		}
+
+		if len(payload) != count*SizeAccountID {
+			return multisig, errors.Errorf("multisig: expected %d participants", count)
+		}
+
+		if multisig.Threshold == 0 || int(multisig.Threshold) > count {
+			return multisig, errors.Errorf("multisig: threshold must be between 1 and %d", count)
+		}
+
+		multisig.Participants = make([]AccountID, count)
+
+		for i := range multisig.Participants {
+			copy(multisig.Participants[i][:], payload[i*SizeAccountID:])
+
+			for j := 0; j < i; j++ {
+				if multisig.Participants[i] == multisig.Participants[j] {
+					return multisig, errors.Errorf("multisig: participant %x is listed twice", multisig.Participants[i])
+				}
+			}
+		}
+	case sys.ProposeMultisig:
+		if len(payload) != SizeAccountID*2+8 {
+			return multisig, errors.New("multisig: proposals must specify an account, recipient and amount")
+		}
+
+		copy(multisig.Account[:], payload[:SizeAccountID])
+		copy(multisig.Recipient[:], payload[SizeAccountID:SizeAccountID*2])
+		multisig.Amount = binary.LittleEndian.Uint64(payload[SizeAccountID*2:])
+
+		if multisig.Amount == 0 {
+			return multisig, errors.New("multisig: proposals must transfer more than zero PERLs")
+		}
+
+		if multisig.Account == multisig.Recipient {
+			return multisig, errors.New("multisig: an account may not transfer to itself")
+		}
+	case sys.ApproveMultisig, sys.ExecuteMultisig, sys.CancelMultisig:
+		if len(payload) != SizeTransactionID {
+			return multisig, errors.New("multisig: a proposal must be specified")
+		}
+
+		copy(multisig.Proposal[:], payload)
+	default:
+		return multisig, errors.Errorf("multisig: unknown opcode %d", multisig.Opcode)
+	}
+
+	return multisig, nil
+}
+
+// ParseDelegation parses and performs sanity checks on the payload of a delegation transaction.
+func ParseDelegation(payload []byte) (Delegation, error) {
+	var delegation Delegation
+
+	if len(payload) == 0 {
+		return delegation, errors.New("delegation: payload must not be empty")
+	}
+
+	delegation.Opcode = payload[0]
+	payload = payload[1:]
+
+	switch delegation.Opcode {
+	case sys.DelegateStake, sys.UndelegateStake:
+		if len(payload) != SizeAccountID+8 {
+			return delegation, errors.New("delegation: a validator and amount must be specified")
+		}
+
+		copy(delegation.Validator[:], payload[:SizeAccountID])
+		delegation.Amount = binary.LittleEndian.Uint64(payload[SizeAccountID:])
+
+		if delegation.Amount == 0 {
+			return delegation, errors.New("delegation: amount must be more than zero PERLs")
+		}
+	case sys.ClaimUnbonded:
+		if len(payload) != 0 {
+			return delegation, errors.New("delegation: claims must not specify anything")
+		}
+	case sys.SetCommission:
+		if len(payload) != 2 {
+			return delegation, errors.New("delegation: a commission must be specified")
+		}
+
+		delegation.Commission = binary.LittleEndian.Uint16(payload)
+
+		if delegation.Commission > sys.MaxCommission {
+			return delegation, errors.Errorf("delegation: commission must be at most %d", sys.MaxCommission)
+		}
+	default:
+		return delegation, errors.Errorf("delegation: unknown opcode %d", delegation.Opcode)
+	}
+
+	return delegation, nil
+}
+
+// ParseSlashing parses and performs sanity checks on the payload of a slashing transaction.
+func ParseSlashing(payload []byte) (Slashing, error) {
+	var slashing Slashing
+
+	if len(payload) == 0 {
+		return slashing, errors.New("slashing: payload must not be empty")
+	}
+
+	slashing.Opcode = payload[0]
+	payload = payload[1:]
+
+	switch slashing.Opcode {
+	case sys.ReportDoubleSign:
+		for i := range slashing.Evidence {
+			if len(payload) < 4 {
+				return slashing, errors.New("slashing: could not read size of evidence")
+			}
+
+			size := binary.BigEndian.Uint32(payload[:4])
+			payload = payload[4:]
+
+			if uint64(size) > uint64(len(payload)) {
+				return slashing, errors.New("slashing: evidence is truncated")
+			}
+
+			tx, err := ParseTransaction(payload[:size])
+			if err != nil {
+				return slashing, errors.Wrap(err, "slashing: could not parse evidence")
+			}
+
+			slashing.Evidence[i] = tx
+			payload = payload[size:]
+		}
+
+		if len(payload) != 0 {
+			return slashing, errors.New("slashing: evidence must comprise exactly two transactions")
+		}
+
+		a, b := slashing.Evidence[0], slashing.Evidence[1]
+
+		if a.Sender != b.Sender || a.Nonce != b.Nonce {
+			return slashing, errors.New("slashing: evidence must be of the same sender and nonce")
+		}
+
+		if a.ID == b.ID {
+			return slashing, errors.New("slashing: evidence must comprise two distinct transactions")
+		}
+
+		slashing.Validator = a.Sender
+	case sys.ReportDowntime:
+		if len(payload) != SizeAccountID {
+			return slashing, errors.New("slashing: a validator must be specified")
+		}
+
+		copy(slashing.Validator[:], payload)
+	default:
+		return slashing, errors.Errorf("slashing: unknown opcode %d", slashing.Opcode)
+	}
+
+	return slashing, nil
+}
+
+// ValidateName checks that name may be registered with the name service.
+// Names comprise lowercase letters, digits, and inner hyphens.
+func ValidateName(name string) error {
+	if len(name) < sys.MinNameLength || len(name) > sys.MaxNameLength {
+		return errors.Errorf("name: names must be between %d and %d characters long", sys.MinNameLength, sys.MaxNameLength)
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '-' && i > 0 && i < len(name)-1:
+		default:
+			return errors.Errorf("name: names may only contain lowercase letters, digits and inner hyphens, got %q", name)
+		}
+	}
+
+	return nil
+}
+
+func (Transfer) Tag() sys.Tag {
+	return sys.TagTransfer
+}
+
 func (t Transfer) Marshal() ([]byte, error) {
+	if len(t.FuncName) == 0 && len(t.FuncParams) > 0 {
+		return nil, errors.New("error marshaling func params: func params specified without a func name")
+	}
+
 	buf := bytes.NewBuffer(make([]byte, 0, 32+8+8+8+4+4))
 
 	buf.Write(t.Recipient[:])
@@ -300,6 +910,10 @@ func (t Transfer) Marshal() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+func (Stake) Tag() sys.Tag {
+	return sys.TagStake
+}
+
 func (s Stake) Marshal() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0, 1+8))
 
@@ -312,6 +926,10 @@ func (s Stake) Marshal() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+func (Contract) Tag() sys.Tag {
+	return sys.TagContract
+}
+
 func (c Contract) Marshal() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0, 8+8+4+len(c.Params)+len(c.Code)))
 
@@ -390,8 +1008,58 @@ func (b *Batch) AddContract(c Contract) error {
 	return nil
 }
 
+// AddUpgrade adds an Upgrade payload into a batch. Smart contracts are
+// deployed with an upgrader by configuring it in the batch spawning them.
+func (b *Batch) AddUpgrade(u Upgrade) error {
+	if b.Size == 255 {
+		return fmt.Errorf("batch cannot have more than 255 transactions")
+	}
+
+	b.Size++
+	b.Tags = append(b.Tags, uint8(sys.TagUpgrade))
+
+	payload, err := u.Marshal()
+	if err != nil {
+		return errors.Wrap(err, "error marshaling upgrade")
+	}
+
+	b.Payloads = append(b.Payloads, payload)
+
+	return nil
+}
+
+// Entries parses the payloads of all transactions in a batch.
+func (b Batch) Entries() ([]Payload, error) {
+	entries := make([]Payload, 0, len(b.Payloads))
+
+	for i := range b.Payloads {
+		if sys.Tag(b.Tags[i]) == sys.TagBatch {
+			return nil, errors.New("batch: entries inside batch cannot be batch transactions themselves")
+		}
+
+		entry, err := ParsePayload(sys.Tag(b.Tags[i]), b.Payloads[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "batch: failed to parse entry %d", i)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (Batch) Tag() sys.Tag {
+	return sys.TagBatch
+}
+
 func (b Batch) Marshal() ([]byte, error) {
-	buf := bytes.NewBuffer(make([]byte, 0, 1+(b.Size*(1+4))))
+	if len(b.Tags) != int(b.Size) || len(b.Payloads) != int(b.Size) {
+		return nil, errors.Errorf(
+			"error marshaling batch: size is %d, but got %d tags and %d payloads", b.Size, len(b.Tags), len(b.Payloads),
+		)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 1+int(b.Size)*(1+4)))
 
 	buf.WriteByte(b.Size)
 
@@ -407,3 +1075,214 @@ func (b Batch) Marshal() ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+func (Beacon) Tag() sys.Tag {
+	return sys.TagBeacon
+}
+
+func (b Beacon) Marshal() ([]byte, error) {
+	return append([]byte(nil), b.Proof[:]...), nil
+}
+
+func (FeeGrant) Tag() sys.Tag {
+	return sys.TagFeeGrant
+}
+
+func (g FeeGrant) Marshal() ([]byte, error) {
+	buf := make([]byte, SizeAccountID+8)
+
+	copy(buf, g.Grantee[:])
+	binary.LittleEndian.PutUint64(buf[SizeAccountID:], g.Allowance)
+
+	return buf, nil
+}
+
+func (Recovery) Tag() sys.Tag {
+	return sys.TagRecovery
+}
+
+func (r Recovery) Marshal() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 1+1+8+1+len(r.Guardians)*SizeAccountID))
+
+	buf.WriteByte(r.Opcode)
+
+	switch r.Opcode {
+	case sys.ConfigureRecovery:
+		if len(r.Guardians) > sys.MaxRecoveryGuardians {
+			return nil, errors.Errorf("at most %d guardians may be configured", sys.MaxRecoveryGuardians)
+		}
+
+		buf.WriteByte(r.Threshold)
+
+		if err := binary.Write(buf, binary.LittleEndian, r.Delay); err != nil {
+			return nil, errors.Wrap(err, "error marshaling delay")
+		}
+
+		buf.WriteByte(byte(len(r.Guardians)))
+
+		for _, guardian := range r.Guardians {
+			buf.Write(guardian[:])
+		}
+	case sys.InitiateRecovery, sys.ApproveRecovery:
+		buf.Write(r.Account[:])
+		buf.Write(r.Recipient[:])
+	case sys.CompleteRecovery:
+		buf.Write(r.Account[:])
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (Name) Tag() sys.Tag {
+	return sys.TagName
+}
+
+func (n Name) Marshal() ([]byte, error) {
+	if len(n.Name) > sys.MaxNameLength {
+		return nil, errors.Errorf("name must be at most %d characters long", sys.MaxNameLength)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 1+1+len(n.Name)+SizeAccountID))
+
+	buf.WriteByte(n.Opcode)
+	buf.WriteByte(byte(len(n.Name)))
+	buf.WriteString(n.Name)
+
+	if n.Opcode != sys.RenewName {
+		buf.Write(n.Target[:])
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (Data) Tag() sys.Tag {
+	return sys.TagData
+}
+
+func (d Data) Marshal() ([]byte, error) {
+	if len(d.Blob) > sys.MaxDataSize {
+		return nil, errors.Errorf("data must be at most %d bytes", sys.MaxDataSize)
+	}
+
+	return append([]byte(nil), d.Blob...), nil
+}
+
+func (Upgrade) Tag() sys.Tag {
+	return sys.TagUpgrade
+}
+
+func (u Upgrade) Marshal() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 1+SizeAccountID+SizeAccountID+len(u.Code)))
+
+	buf.WriteByte(u.Opcode)
+	buf.Write(u.Contract[:])
+
+	switch u.Opcode {
+	case sys.ConfigureUpgrader:
+		buf.Write(u.Upgrader[:])
+	case sys.UpgradeContract:
+		if len(u.Code) == 0 {
+			return nil, errors.New("code must be of length greater than zero")
+		}
+
+		buf.Write(u.Code)
+	default:
+		return nil, errors.Errorf("unknown upgrade opcode %d", u.Opcode)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (Multisig) Tag() sys.Tag {
+	return sys.TagMultisig
+}
+
+func (m Multisig) Marshal() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 1+1+1+len(m.Participants)*SizeAccountID+SizeAccountID*2+8))
+
+	buf.WriteByte(m.Opcode)
+
+	switch m.Opcode {
+	case sys.CreateMultisig:
+		if len(m.Participants) > sys.MaxMultisigParticipants {
+			return nil, errors.Errorf("at most %d participants may be specified", sys.MaxMultisigParticipants)
+		}
+
+		buf.WriteByte(m.Threshold)
+		buf.WriteByte(byte(len(m.Participants)))
+
+		for _, participant := range m.Participants {
+			buf.Write(participant[:])
+		}
+	case sys.ProposeMultisig:
+		buf.Write(m.Account[:])
+		buf.Write(m.Recipient[:])
+
+		if err := binary.Write(buf, binary.LittleEndian, m.Amount); err != nil {
+			return nil, errors.Wrap(err, "error marshaling amount")
+		}
+	case sys.ApproveMultisig, sys.ExecuteMultisig, sys.CancelMultisig:
+		buf.Write(m.Proposal[:])
+	default:
+		return nil, errors.Errorf("unknown multisig opcode %d", m.Opcode)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (Delegation) Tag() sys.Tag {
+	return sys.TagDelegation
+}
+
+func (d Delegation) Marshal() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 1+SizeAccountID+8))
+
+	buf.WriteByte(d.Opcode)
+
+	switch d.Opcode {
+	case sys.DelegateStake, sys.UndelegateStake:
+		buf.Write(d.Validator[:])
+
+		if err := binary.Write(buf, binary.LittleEndian, d.Amount); err != nil {
+			return nil, errors.Wrap(err, "error marshaling amount")
+		}
+	case sys.ClaimUnbonded:
+	case sys.SetCommission:
+		if err := binary.Write(buf, binary.LittleEndian, d.Commission); err != nil {
+			return nil, errors.Wrap(err, "error marshaling commission")
+		}
+	default:
+		return nil, errors.Errorf("unknown delegation opcode %d", d.Opcode)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (Slashing) Tag() sys.Tag {
+	return sys.TagSlashing
+}
+
+func (s Slashing) Marshal() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 1+SizeAccountID))
+
+	buf.WriteByte(s.Opcode)
+
+	switch s.Opcode {
+	case sys.ReportDoubleSign:
+		for _, tx := range s.Evidence {
+			evidence := tx.Marshal()
+
+			if err := binary.Write(buf, binary.BigEndian, uint32(len(evidence))); err != nil {
+				return nil, errors.Wrap(err, "error marshaling size of evidence")
+			}
+
+			buf.Write(evidence)
+		}
+	case sys.ReportDowntime:
+		buf.Write(s.Validator[:])
+	default:
+		return nil, errors.Errorf("unknown slashing opcode %d", s.Opcode)
+	}
+
+	return buf.Bytes(), nil
+}
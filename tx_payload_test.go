@@ -3,10 +3,14 @@
 package wavelet
 
 import (
+	"bytes"
 	"fmt"
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/security/vrf"
 	"github.com/perlin-network/wavelet/sys"
 	"github.com/stretchr/testify/assert"
 )
@@ -416,6 +420,428 @@ func TestParseBatch_Errors(t *testing.T) {
 	}
 }
 
+func TestParseMultisig_Errors(t *testing.T) {
+	create := func(threshold uint8, participants ...AccountID) func() []byte {
+		return func() []byte {
+			payload, _ := Multisig{
+				Opcode:       sys.CreateMultisig,
+				Participants: participants,
+				Threshold:    threshold,
+			}.Marshal()
+			return payload
+		}
+	}
+
+	propose := func(account, recipient AccountID, amount uint64) func() []byte {
+		return func() []byte {
+			payload, _ := Multisig{
+				Opcode:    sys.ProposeMultisig,
+				Account:   account,
+				Recipient: recipient,
+				Amount:    amount,
+			}.Marshal()
+			return payload
+		}
+	}
+
+	tests := []struct {
+		Err     string
+		Payload func() []byte
+	}{
+		{"payload must not be empty", func() []byte { return nil }},
+		{"unknown opcode 5", func() []byte { return []byte{sys.CancelMultisig + 1} }},
+		{"must have between 1 and 16 participants", create(1)},
+		{"threshold must be between 1 and 2", create(0, AccountID{1}, AccountID{2})},
+		{"threshold must be between 1 and 2", create(3, AccountID{1}, AccountID{2})},
+		{"participant 02", create(1, AccountID{1}, AccountID{2}, AccountID{2})},
+		{
+			"expected 2 participants",
+			func() []byte {
+				payload := create(1, AccountID{1}, AccountID{2})()
+				return payload[:len(payload)-1]
+			},
+		},
+		{
+			"must have between 1 and 16 participants",
+			func() []byte {
+				payload := make([]byte, 1+1+1+17*SizeAccountID)
+				payload[1], payload[2] = 1, 17
+				return payload
+			},
+		},
+		{"proposals must transfer more than zero PERLs", propose(AccountID{1}, AccountID{2}, 0)},
+		{"an account may not transfer to itself", propose(AccountID{1}, AccountID{1}, 1)},
+		{"proposals must specify an account, recipient and amount", func() []byte { return []byte{sys.ProposeMultisig} }},
+		{"a proposal must be specified", func() []byte { return []byte{sys.ExecuteMultisig, 1} }},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.Err, func(t *testing.T) {
+			_, err := ParseMultisig(tt.Payload())
+			if err == nil {
+				t.Fatal("expecting an error, got nil instead")
+			}
+			assert.Contains(t, err.Error(), fmt.Sprintf("multisig: %s", tt.Err))
+		})
+	}
+}
+
+func TestParseDelegation_Errors(t *testing.T) {
+	tests := []struct {
+		Err     string
+		Payload func() []byte
+	}{
+		{"payload must not be empty", func() []byte { return nil }},
+		{"unknown opcode 4", func() []byte { return []byte{sys.SetCommission + 1} }},
+		{"a validator and amount must be specified", func() []byte { return []byte{sys.DelegateStake, 1} }},
+		{
+			"amount must be more than zero PERLs",
+			func() []byte {
+				payload, _ := Delegation{Opcode: sys.UndelegateStake, Validator: AccountID{1}}.Marshal()
+				return payload
+			},
+		},
+		{"claims must not specify anything", func() []byte { return []byte{sys.ClaimUnbonded, 0} }},
+		{"a commission must be specified", func() []byte { return []byte{sys.SetCommission, 1} }},
+		{
+			"commission must be at most 10000",
+			func() []byte {
+				payload, _ := Delegation{Opcode: sys.SetCommission, Commission: 10001}.Marshal()
+				return payload
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.Err, func(t *testing.T) {
+			_, err := ParseDelegation(tt.Payload())
+			if err == nil {
+				t.Fatal("expecting an error, got nil instead")
+			}
+			assert.Contains(t, err.Error(), fmt.Sprintf("delegation: %s", tt.Err))
+		})
+	}
+}
+
+func TestParseSlashing_Errors(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	others, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	doubleSign := func(a, b Transaction) func() []byte {
+		return func() []byte {
+			payload, _ := Slashing{Opcode: sys.ReportDoubleSign, Evidence: [2]Transaction{a, b}}.Marshal()
+			return payload
+		}
+	}
+
+	tx := NewTransaction(keys, 1, 1, sys.TagTransfer, nil)
+
+	tests := []struct {
+		Err     string
+		Payload func() []byte
+	}{
+		{"payload must not be empty", func() []byte { return nil }},
+		{"unknown opcode 2", func() []byte { return []byte{sys.ReportDowntime + 1} }},
+		{"a validator must be specified", func() []byte { return []byte{sys.ReportDowntime, 1} }},
+		{"could not read size of evidence", func() []byte { return []byte{sys.ReportDoubleSign, 0} }},
+		{"evidence is truncated", func() []byte { return []byte{sys.ReportDoubleSign, 0, 0, 0, 1} }},
+		{"could not parse evidence", func() []byte { return []byte{sys.ReportDoubleSign, 0, 0, 0, 1, 0} }},
+		{"evidence must comprise exactly two transactions", func() []byte {
+			return append(doubleSign(tx, NewTransaction(keys, 1, 2, sys.TagTransfer, nil))(), 0)
+		}},
+		{
+			"evidence must be of the same sender and nonce",
+			doubleSign(tx, NewTransaction(others, 1, 1, sys.TagTransfer, nil)),
+		},
+		{
+			"evidence must be of the same sender and nonce",
+			doubleSign(tx, NewTransaction(keys, 2, 1, sys.TagTransfer, nil)),
+		},
+		{"evidence must comprise two distinct transactions", doubleSign(tx, tx)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.Err, func(t *testing.T) {
+			_, err := ParseSlashing(tt.Payload())
+			if err == nil {
+				t.Fatal("expecting an error, got nil instead")
+			}
+			assert.Contains(t, err.Error(), fmt.Sprintf("slashing: %s", tt.Err))
+		})
+	}
+}
+
+func TestParseRecovery_Errors(t *testing.T) {
+	configure := func(threshold uint8, delay uint64, guardians ...AccountID) func() []byte {
+		return func() []byte {
+			payload, _ := Recovery{
+				Opcode:    sys.ConfigureRecovery,
+				Guardians: guardians,
+				Threshold: threshold,
+				Delay:     delay,
+			}.Marshal()
+			return payload
+		}
+	}
+
+	tests := []struct {
+		Err     string
+		Payload func() []byte
+	}{
+		{"payload must not be empty", func() []byte { return nil }},
+		{"unknown opcode 5", func() []byte { return []byte{sys.CompleteRecovery + 1} }},
+		{"threshold must be between 1 and 2", configure(0, 0, AccountID{1}, AccountID{2})},
+		{"threshold must be between 1 and 2", configure(3, 0, AccountID{1}, AccountID{2})},
+		{"guardian 02", configure(1, 0, AccountID{1}, AccountID{2}, AccountID{2})},
+		{"removing a configuration must not specify a threshold or delay", configure(0, 1)},
+		{
+			"expected 2 guardians",
+			func() []byte {
+				payload := configure(1, 0, AccountID{1}, AccountID{2})()
+				return payload[:len(payload)-1]
+			},
+		},
+		{
+			"at most 16 guardians may be configured",
+			func() []byte {
+				payload := make([]byte, 1+1+8+1+17*SizeAccountID)
+				payload[1+1+8] = 17
+				return payload
+			},
+		},
+		{
+			"an account may not be recovered to itself",
+			func() []byte {
+				payload, _ := Recovery{Opcode: sys.InitiateRecovery, Account: AccountID{1}, Recipient: AccountID{1}}.Marshal()
+				return payload
+			},
+		},
+		{"an account must be specified", func() []byte { return []byte{sys.CompleteRecovery} }},
+		{"cancellations must not specify anything", func() []byte { return []byte{sys.CancelRecovery, 0} }},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.Err, func(t *testing.T) {
+			_, err := ParseRecovery(tt.Payload())
+			if err == nil {
+				t.Fatal("expecting an error, got nil instead")
+			}
+			assert.Contains(t, err.Error(), fmt.Sprintf("recovery: %s", tt.Err))
+		})
+	}
+}
+
+func TestParseName_Errors(t *testing.T) {
+	name := func(opcode byte, name string) func() []byte {
+		return func() []byte {
+			payload, _ := Name{Opcode: opcode, Name: name}.Marshal()
+			return payload
+		}
+	}
+
+	tests := []struct {
+		Err     string
+		Payload func() []byte
+	}{
+		{"payload must comprise an opcode and a name", func() []byte { return []byte{sys.RenewName} }},
+		{"opcode must be 0, 1, or 2", name(sys.TransferName+1, "alice")},
+		{"payload is too short to comprise the name", func() []byte { return []byte{sys.RenewName, 5, 'a'} }},
+		{"names must be between 3 and 32 characters long", name(sys.RenewName, "al")},
+		{"names must be between 3 and 32 characters long", func() []byte {
+			return append([]byte{sys.RenewName, 33}, bytes.Repeat([]byte{'a'}, 33)...)
+		}},
+		{"names may only contain lowercase letters, digits and inner hyphens", name(sys.RenewName, "Alice")},
+		{"names may only contain lowercase letters, digits and inner hyphens", name(sys.RenewName, "-alice")},
+		{"names may only contain lowercase letters, digits and inner hyphens", name(sys.RenewName, "alice-")},
+		{"renewals must only specify a name", func() []byte { return append(name(sys.RenewName, "alice")(), 0) }},
+		{"registrations and transfers must specify a target account", func() []byte {
+			payload := name(sys.RegisterName, "alice")()
+			return payload[:len(payload)-1]
+		}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.Err, func(t *testing.T) {
+			_, err := ParseName(tt.Payload())
+			if err == nil {
+				t.Fatal("expecting an error, got nil instead")
+			}
+			assert.Contains(t, err.Error(), fmt.Sprintf("name: %s", tt.Err))
+		})
+	}
+}
+
+func TestParsePayload(t *testing.T) {
+	transfer := validTransfer(t)
+
+	keys, err := skademlia.NewKeys(1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	evidence := [2]Transaction{
+		NewTransaction(keys, 1, 1, sys.TagTransfer, []byte{1}), NewTransaction(keys, 1, 2, sys.TagTransfer, []byte{2}),
+	}
+
+	var batch Batch
+	assert.NoError(t, batch.AddTransfer(transfer))
+	assert.NoError(t, batch.AddStake(validStake(sys.PlaceStake)))
+	assert.NoError(t, batch.AddContract(validContract()))
+
+	payloads := []Payload{
+		transfer,
+		Transfer{Recipient: transfer.Recipient, Amount: 1},
+		validStake(sys.WithdrawStake),
+		validStake(sys.PlaceStake),
+		Stake{Opcode: sys.WithdrawReward, Amount: sys.MinimumRewardWithdraw},
+		validContract(),
+		batch,
+		Beacon{Proof: vrf.Proof{1, 2, 3}},
+		FeeGrant{Grantee: transfer.Recipient, Allowance: 1},
+		FeeGrant{Grantee: transfer.Recipient},
+		Recovery{
+			Opcode:    sys.ConfigureRecovery,
+			Guardians: []AccountID{{1}, {2}, {3}},
+			Threshold: 2,
+			Delay:     100,
+		},
+		Recovery{Opcode: sys.ConfigureRecovery},
+		Recovery{Opcode: sys.InitiateRecovery, Account: AccountID{1}, Recipient: AccountID{2}},
+		Recovery{Opcode: sys.ApproveRecovery, Account: AccountID{1}, Recipient: AccountID{2}},
+		Recovery{Opcode: sys.CancelRecovery},
+		Recovery{Opcode: sys.CompleteRecovery, Account: AccountID{1}},
+		Name{Opcode: sys.RegisterName, Name: "alice", Target: AccountID{1}},
+		Name{Opcode: sys.RenewName, Name: "alice"},
+		Name{Opcode: sys.TransferName, Name: "alice-2", Target: AccountID{2}},
+		Data{Blob: []byte("document hash")},
+		Upgrade{Opcode: sys.ConfigureUpgrader, Contract: AccountID{1}, Upgrader: AccountID{2}},
+		Upgrade{Opcode: sys.UpgradeContract, Contract: AccountID{1}, Code: []byte("code")},
+		Multisig{Opcode: sys.CreateMultisig, Participants: []AccountID{{1}, {2}, {3}}, Threshold: 2},
+		Multisig{Opcode: sys.ProposeMultisig, Account: AccountID{1}, Recipient: AccountID{2}, Amount: 10},
+		Multisig{Opcode: sys.ApproveMultisig, Proposal: TransactionID{1}},
+		Multisig{Opcode: sys.ExecuteMultisig, Proposal: TransactionID{1}},
+		Multisig{Opcode: sys.CancelMultisig, Proposal: TransactionID{1}},
+		Delegation{Opcode: sys.DelegateStake, Validator: AccountID{1}, Amount: 10},
+		Delegation{Opcode: sys.UndelegateStake, Validator: AccountID{1}, Amount: 10},
+		Delegation{Opcode: sys.ClaimUnbonded},
+		Delegation{Opcode: sys.SetCommission, Commission: 500},
+		Slashing{Opcode: sys.ReportDoubleSign, Validator: keys.PublicKey(), Evidence: evidence},
+		Slashing{Opcode: sys.ReportDowntime, Validator: AccountID{1}},
+	}
+
+	for _, p := range payloads {
+		buf, err := p.Marshal()
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		p2, err := ParsePayload(p.Tag(), buf)
+		assert.NoError(t, err)
+		assert.Equal(t, p, p2)
+
+		buf2, err := p2.Marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, buf, buf2)
+	}
+
+	entries, err := batch.Entries()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []Payload{transfer, validStake(sys.PlaceStake), validContract()}, entries)
+
+	_, err = ParsePayload(sys.Tag(0xff), nil)
+	assert.Error(t, err)
+}
+
+func TestPayloadRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+
+	randBytes := func(max int) []byte {
+		n := rng.Intn(max) + 1
+		buf := make([]byte, n)
+		rng.Read(buf)
+
+		return buf
+	}
+
+	for i := 0; i < 1000; i++ {
+		var transfer Transfer
+
+		rng.Read(transfer.Recipient[:])
+		transfer.Amount = rng.Uint64()
+		transfer.GasLimit = rng.Uint64()
+		transfer.GasDeposit = rng.Uint64()
+
+		if rng.Intn(2) == 0 {
+			transfer.FuncName = randBytes(1024)
+
+			if rng.Intn(2) == 0 {
+				transfer.FuncParams = randBytes(4096)
+			}
+		}
+
+		stake := Stake{Opcode: byte(rng.Intn(2)), Amount: rng.Uint64()%math.MaxUint32 + 1}
+
+		contract := Contract{
+			GasLimit:   rng.Uint64()%math.MaxUint32 + 1,
+			GasDeposit: rng.Uint64(),
+			Params:     randBytes(4096),
+			Code:       randBytes(4096),
+		}
+
+		var batch Batch
+
+		for j := rng.Intn(16); j >= 0; j-- {
+			switch rng.Intn(3) {
+			case 0:
+				assert.NoError(t, batch.AddTransfer(transfer))
+			case 1:
+				assert.NoError(t, batch.AddStake(stake))
+			case 2:
+				assert.NoError(t, batch.AddContract(contract))
+			}
+		}
+
+		for _, p := range []Payload{transfer, stake, contract, batch} {
+			buf, err := p.Marshal()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			p2, err := ParsePayload(p.Tag(), buf)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if !assert.Equal(t, p, p2) {
+				return
+			}
+		}
+	}
+}
+
+func TestMarshalPayload_Errors(t *testing.T) {
+	_, err := Transfer{FuncParams: []byte("foobar")}.Marshal()
+	assert.Error(t, err)
+
+	_, err = Batch{Size: 1}.Marshal()
+	assert.Error(t, err)
+}
+
 func validTransfer(t *testing.T) Transfer {
 	keys, err := skademlia.NewKeys(sys.SKademliaC1, sys.SKademliaC2)
 	if err != nil {
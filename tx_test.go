@@ -23,12 +23,114 @@ package wavelet
 
 import (
 	"bytes"
+	"github.com/perlin-network/noise/edwards25519"
 	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
 	"github.com/perlin-network/wavelet/sys"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
+// testScheme is a signature scheme signing like Ed25519, albeit under a
+// different identifier.
+const testScheme security.Scheme = 0x7f
+
+type testSchemeSigner struct{ *security.Ed25519Signer }
+
+func (testSchemeSigner) Scheme() security.Scheme { return testScheme }
+
+type testSchemeVerifier struct{}
+
+func (testSchemeVerifier) Scheme() security.Scheme { return testScheme }
+
+func (testSchemeVerifier) Verify(publicKey, message, signature []byte) bool {
+	return security.Verify(security.SchemeEd25519, publicKey, message, signature) == nil
+}
+
+func init() {
+	security.RegisterVerifier(testSchemeVerifier{})
+}
+
+// Not parallel, as the schedule of features is global.
+func TestTransactionSchemes(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureSignatureSchemes, sys.FeatureCanonicalTransactions)()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	// Transactions of the default scheme only carry the version of their
+	// canonical encoding alongside the tag.
+	tx := NewTransaction(keys, 2, 13, sys.TagTransfer, []byte{1, 2, 3})
+	assert.Equal(t, security.SchemeEd25519, tx.Scheme)
+	assert.Equal(t, canonical.Version, tx.Version)
+	assert.Len(t, tx.Marshal(), 32+8+8+1+1+4+3+64)
+	assert.True(t, tx.VerifySignature())
+
+	signed, err := NewTransactionWithSigner(security.NewEd25519Signer(keys.PrivateKey()), 2, 13, sys.TagTransfer, []byte{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, tx, signed)
+
+	// Other schemes are carried alongside the tag.
+	other, err := NewTransactionWithSigner(
+		testSchemeSigner{security.NewEd25519Signer(keys.PrivateKey())}, 2, 13, sys.TagTransfer, []byte{1, 2, 3},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, testScheme, other.Scheme)
+	assert.True(t, other.VerifySignature())
+	assert.NotEqual(t, tx.ID, other.ID)
+
+	buf := other.Marshal()
+	assert.Len(t, buf, 32+8+8+2+1+4+3+64)
+
+	decoded, err := UnmarshalTransaction(bytes.NewReader(buf))
+	assert.NoError(t, err)
+	assert.Equal(t, other, decoded)
+
+	// The scheme is signed, such that signatures may not be replayed under
+	// another scheme.
+	replayed := NewSignedTransactionWithVersion(
+		other.Version, security.SchemeEd25519, other.Sender, other.Nonce, other.Block, other.Tag, other.Payload,
+		other.Signature,
+	)
+	assert.False(t, replayed.VerifySignature())
+
+	// Signatures of unknown schemes never verify.
+	unknown := NewSignedTransactionWithScheme(0x7e, tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Signature)
+	assert.False(t, unknown.VerifySignature())
+
+	// The default scheme may not be marshaled explicitly.
+	explicit := append([]byte(nil), buf...)
+	explicit[32+8+8+1] = byte(security.SchemeEd25519)
+
+	_, err = UnmarshalTransaction(bytes.NewReader(explicit))
+	assert.Error(t, err)
+
+	// Other schemes are rejected until they activate at the block succeeding
+	// the one the transaction was created against.
+	sys.FeatureActivations[sys.FeatureSignatureSchemes] = 15
+
+	_, err = UnmarshalTransaction(bytes.NewReader(buf))
+	assert.Error(t, err)
+
+	sys.FeatureActivations[sys.FeatureSignatureSchemes] = 14
+
+	_, err = UnmarshalTransaction(bytes.NewReader(buf))
+	assert.NoError(t, err)
+}
+
+func TestUnmarshalTransactionUnknownTag(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	buf := NewTransaction(keys, 0, 0, sys.TagTransfer, nil).Marshal()
+	buf[32+8+8] = byte(sys.TagSlashing + 1)
+
+	_, err = UnmarshalTransaction(bytes.NewReader(buf))
+	assert.Error(t, err)
+}
+
 func BenchmarkNewTX(b *testing.B) {
 	keys, err := skademlia.NewKeys(1, 1)
 	assert.NoError(b, err)
@@ -77,3 +179,291 @@ func BenchmarkMarshalUnmarshalTX(b *testing.B) {
 //
 //	fmt.Println(len(buf), len(b), unsafe.Sizeof(tx))
 //}
+
+// Not parallel, as the schedule of features is global.
+func TestTransactionVersions(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureCanonicalTransactions)()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	// Transactions signing the legacy encoding are marshaled and signed as
+	// they were before versions were introduced.
+	signature := edwards25519.Sign(
+		keys.PrivateKey(), TransactionMessage(security.SchemeEd25519, 2, 13, sys.TagTransfer, []byte{1, 2, 3}),
+	)
+
+	legacy := NewSignedTransaction(keys.PublicKey(), 2, 13, sys.TagTransfer, []byte{1, 2, 3}, signature)
+	assert.Zero(t, legacy.Version)
+	assert.Len(t, legacy.Marshal(), 32+8+8+1+4+3+64)
+	assert.True(t, legacy.VerifySignature())
+
+	decoded, err := UnmarshalTransaction(bytes.NewReader(legacy.Marshal()))
+	assert.NoError(t, err)
+	assert.Equal(t, legacy, decoded)
+
+	tx := NewTransaction(keys, 2, 13, sys.TagTransfer, []byte{1, 2, 3})
+	assert.NotEqual(t, legacy.ID, tx.ID)
+
+	buf := tx.Marshal()
+
+	decoded, err = UnmarshalTransaction(bytes.NewReader(buf))
+	assert.NoError(t, err)
+	assert.Equal(t, tx, decoded)
+
+	// The version is signed, such that signatures may not be replayed under
+	// another version.
+	replayed := NewSignedTransaction(tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Signature)
+	assert.False(t, replayed.VerifySignature())
+
+	replayed = NewSignedTransactionWithVersion(
+		canonical.Version, legacy.Scheme, legacy.Sender, legacy.Nonce, legacy.Block, legacy.Tag, legacy.Payload,
+		legacy.Signature,
+	)
+	assert.False(t, replayed.VerifySignature())
+
+	// Signatures of unknown versions never verify.
+	unknown := NewSignedTransactionWithVersion(
+		canonical.Version+1, tx.Scheme, tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Signature,
+	)
+	assert.False(t, unknown.VerifySignature())
+
+	// Neither the legacy version nor unknown versions may be marshaled.
+	for _, version := range []byte{0, canonical.Version + 1} {
+		explicit := append([]byte(nil), buf...)
+		explicit[32+8+8+1] = version
+
+		_, err = UnmarshalTransaction(bytes.NewReader(explicit))
+		assert.Error(t, err)
+	}
+
+	// The message of a transaction is its canonical encoding.
+	assert.Equal(t,
+		canonical.New("wavelet/transaction").
+			Fixed(tx.Sender[:]).Uint8(0).Uint64(2).Uint64(13).Uint8(byte(sys.TagTransfer)).Bytes([]byte{1, 2, 3}).
+			Encode(),
+		tx.Message(),
+	)
+
+	// The canonical encoding is rejected until it activates at the block
+	// succeeding the one the transaction was created against, before which
+	// new transactions sign the legacy encoding.
+	sys.FeatureActivations[sys.FeatureCanonicalTransactions] = 15
+
+	_, err = UnmarshalTransaction(bytes.NewReader(buf))
+	assert.Error(t, err)
+	assert.Equal(t, legacy, NewTransaction(keys, 2, 13, sys.TagTransfer, []byte{1, 2, 3}))
+
+	sys.FeatureActivations[sys.FeatureCanonicalTransactions] = 14
+
+	_, err = UnmarshalTransaction(bytes.NewReader(buf))
+	assert.NoError(t, err)
+}
+
+func TestTransactionMalleability(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	tx := NewTransaction(keys, 2, 13, sys.TagTransfer, []byte{1, 2, 3})
+	buf := tx.Marshal()
+
+	parsed, err := ParseTransaction(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tx, parsed)
+
+	fromProto, err := TransactionFromProto(tx.Proto())
+	assert.NoError(t, err)
+	assert.Equal(t, tx.ID, fromProto.ID)
+
+	// Adding the order l of the base point to the scalar S of the signature
+	// yields a transaction under a distinct ID, which must never verify.
+	var (
+		l     = [32]byte{0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58, 0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14, 31: 0x10}
+		carry uint16
+	)
+
+	malleated := tx
+
+	for i := range l {
+		sum := uint16(malleated.Signature[32+i]) + uint16(l[i]) + carry
+		malleated.Signature[32+i], carry = byte(sum), sum>>8
+	}
+
+	malleated = NewSignedTransactionWithVersion(
+		malleated.Version, malleated.Scheme, malleated.Sender, malleated.Nonce, malleated.Block, malleated.Tag,
+		malleated.Payload, malleated.Signature,
+	)
+
+	assert.NotEqual(t, tx.ID, malleated.ID)
+	assert.False(t, malleated.VerifySignature())
+	assert.Equal(t, ErrTxInvalidSignature, ValidateTransaction(nil, malleated))
+
+	// Neither may the encoding be padded, nor its defaults be marshaled.
+	_, err = ParseTransaction(append(append([]byte(nil), buf...), 0))
+	assert.Error(t, err)
+
+	explicit := append([]byte(nil), buf[:32+8+8]...)
+	explicit = append(explicit, buf[32+8+8]|tagFlagScheme, byte(security.SchemeEd25519))
+	explicit = append(explicit, buf[32+8+8+1:]...)
+
+	_, err = ParseTransaction(explicit)
+	assert.Error(t, err)
+}
+
+// Not parallel, as the schedule of features is global.
+func TestTransactionStamps(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureCanonicalTransactions, sys.FeatureStamps)()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	signer := security.NewEd25519Signer(keys.PrivateKey())
+
+	tx, err := NewStampedTransaction(signer, 2, 13, sys.TagTransfer, []byte{1, 2, 3}, 8)
+	assert.NoError(t, err)
+	assert.NotZero(t, tx.Stamp)
+	assert.True(t, tx.StampDifficulty() >= 8)
+	assert.True(t, tx.VerifySignature())
+
+	buf := tx.Marshal()
+
+	parsed, err := ParseTransaction(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tx, parsed)
+
+	fromProto, err := TransactionFromProto(tx.Proto())
+	assert.NoError(t, err)
+	assert.Equal(t, tx, fromProto)
+
+	// Stamps weaker than the minimum difficulty waive no fee.
+	if tx.StampDifficulty() < sys.MinStampDifficulty {
+		assert.Equal(t, sys.DefaultTransactionFee, tx.Fee())
+	}
+
+	free, err := NewStampedTransaction(signer, 2, 13, sys.TagTransfer, []byte{1, 2, 3}, sys.MinStampDifficulty)
+	assert.NoError(t, err)
+	assert.Zero(t, free.Fee())
+
+	// Stamps are rejected, and waive no fee, until they activate at the block
+	// succeeding the one the transaction was created against.
+	sys.FeatureActivations[sys.FeatureStamps] = 15
+
+	_, err = ParseTransaction(free.Marshal())
+	assert.Error(t, err)
+	assert.Equal(t, sys.DefaultTransactionFee, free.Fee())
+
+	sys.FeatureActivations[sys.FeatureStamps] = 14
+
+	_, err = ParseTransaction(free.Marshal())
+	assert.NoError(t, err)
+	assert.Zero(t, free.Fee())
+
+	// The stamp is signed, such that it may neither be swapped nor stripped.
+	swapped := NewSignedStampedTransaction(
+		tx.Version, tx.Scheme, tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Stamp+1, tx.Signature,
+	)
+	assert.NotEqual(t, tx.ID, swapped.ID)
+	assert.False(t, swapped.VerifySignature())
+
+	stripped := NewSignedTransactionWithVersion(
+		tx.Version, tx.Scheme, tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Signature,
+	)
+	assert.False(t, stripped.VerifySignature())
+
+	// An empty stamp may not be marshaled.
+	explicit := append([]byte(nil), buf...)
+	copy(explicit[32+8+8+1+1:], make([]byte, 8))
+
+	_, err = ParseTransaction(explicit)
+	assert.Error(t, err)
+
+	// Nor may transactions signing the legacy encoding, which does not sign
+	// the stamp, be stamped.
+	signature := edwards25519.Sign(
+		keys.PrivateKey(), TransactionMessage(security.SchemeEd25519, 2, 13, sys.TagTransfer, []byte{1, 2, 3}),
+	)
+
+	legacy := NewSignedTransaction(keys.PublicKey(), 2, 13, sys.TagTransfer, []byte{1, 2, 3}, signature).Marshal()
+
+	stamped := append([]byte(nil), legacy[:32+8+8]...)
+	stamped = append(stamped, legacy[32+8+8]|tagFlagStamp, 0, 0, 0, 0, 0, 0, 0, 1)
+	stamped = append(stamped, legacy[32+8+8+1:]...)
+
+	_, err = ParseTransaction(stamped)
+	assert.Error(t, err)
+
+	// The difficulty admitted rises by a bit every time the load doubles.
+	assert.Equal(t, sys.MinStampDifficulty, StampDifficultyForLoad(0))
+	assert.Equal(t, sys.MinStampDifficulty, StampDifficultyForLoad(sys.StampDifficultyLoadStep-1))
+	assert.Equal(t, sys.MinStampDifficulty+1, StampDifficultyForLoad(sys.StampDifficultyLoadStep))
+	assert.Equal(t, sys.MinStampDifficulty+2, StampDifficultyForLoad(3*sys.StampDifficultyLoadStep))
+	assert.Equal(t, sys.MinStampDifficulty+3, StampDifficultyForLoad(4*sys.StampDifficultyLoadStep))
+}
+
+// Not parallel, as the schedule of features is global.
+func TestTransactionTips(t *testing.T) {
+	defer ScheduleFeatures(sys.FeatureCanonicalTransactions, sys.FeatureReplacement)()
+
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	signer := security.NewEd25519Signer(keys.PrivateKey())
+
+	tx, err := NewTippedTransaction(signer, 2, 13, sys.TagTransfer, []byte{1, 2, 3}, 42)
+	assert.NoError(t, err)
+	assert.True(t, tx.VerifySignature())
+
+	// The tip is paid on top of the fee.
+	assert.Equal(t, sys.DefaultTransactionFee+42, tx.Fee())
+
+	buf := tx.Marshal()
+
+	parsed, err := ParseTransaction(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tx, parsed)
+
+	fromProto, err := TransactionFromProto(tx.Proto())
+	assert.NoError(t, err)
+	assert.Equal(t, tx, fromProto)
+
+	// The tip is signed, such that it may neither be swapped nor stripped,
+	// nor be passed off as a stamp.
+	swapped := NewSignedTippedTransaction(
+		tx.Version, tx.Scheme, tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, 0, tx.Tip+1, tx.Signature,
+	)
+	assert.False(t, swapped.VerifySignature())
+
+	stripped := NewSignedTransactionWithVersion(
+		tx.Version, tx.Scheme, tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Signature,
+	)
+	assert.False(t, stripped.VerifySignature())
+
+	stamp := NewSignedStampedTransaction(
+		tx.Version, tx.Scheme, tx.Sender, tx.Nonce, tx.Block, tx.Tag, tx.Payload, tx.Tip, tx.Signature,
+	)
+	assert.False(t, stamp.VerifySignature())
+
+	// An empty tip may not be marshaled.
+	explicit := append([]byte(nil), buf...)
+	copy(explicit[32+8+8+1+1:], make([]byte, 8))
+
+	_, err = ParseTransaction(explicit)
+	assert.Error(t, err)
+
+	// Tips are rejected, and go unpaid, until they activate at the block
+	// succeeding the one the transaction was created against.
+	sys.FeatureActivations[sys.FeatureReplacement] = 15
+
+	_, err = ParseTransaction(buf)
+	assert.Error(t, err)
+	assert.Equal(t, sys.DefaultTransactionFee, tx.Fee())
+
+	sys.FeatureActivations[sys.FeatureReplacement] = 14
+
+	_, err = ParseTransaction(buf)
+	assert.NoError(t, err)
+
+	// Tips past the balance of any account saturate the fee.
+	tx.Tip = math.MaxUint64
+	assert.Equal(t, uint64(math.MaxUint64), tx.Fee())
+}
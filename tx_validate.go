@@ -25,7 +25,17 @@ import (
 	"github.com/pkg/errors"
 )
 
-var ErrContractAlreadyExists = errors.New("contract: already exists")
+var (
+	ErrContractAlreadyExists = errors.New("contract: already exists")
+
+	// ErrTagInactive is returned for transactions whose tag is gated behind a
+	// feature which has yet to activate.
+	ErrTagInactive = errors.New("tag: feature not yet active")
+
+	// ErrInsufficientBalance is returned for transactions whose sender may
+	// not afford their fees, along with whatever amounts they move.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+)
 
 // ValidateTransaction validates signature, and state to make sure that the transaction is acceptable.
 func ValidateTransaction(snapshot *avl.Tree, tx Transaction) error {
@@ -37,6 +47,16 @@ func validateTransaction(snapshot *avl.Tree, tx Transaction, verifySignature boo
 		return ErrTxInvalidSignature
 	}
 
+	// Transactions may only be created against blocks succeeding which their
+	// tag is active, and hence will be active by the time they are applied.
+	if !sys.TagActive(tx.Tag, tx.earliestHeight()) {
+		return errors.Wrapf(ErrTagInactive, "tag %d at block %d", tx.Tag, tx.earliestHeight())
+	}
+
+	if err := tx.checkFeatures(); err != nil {
+		return err
+	}
+
 	switch tx.Tag {
 	case sys.TagTransfer:
 		return validateTransferTransaction(snapshot, tx)
@@ -46,6 +66,24 @@ func validateTransaction(snapshot *avl.Tree, tx Transaction, verifySignature boo
 		return validateContractTransaction(snapshot, tx)
 	case sys.TagBatch:
 		return validateBatchTransaction(snapshot, tx)
+	case sys.TagBeacon:
+		return validateBeaconTransaction(snapshot, tx)
+	case sys.TagFeeGrant:
+		return validateFeeGrantTransaction(snapshot, tx)
+	case sys.TagRecovery:
+		return validateRecoveryTransaction(snapshot, tx)
+	case sys.TagName:
+		return validateNameTransaction(snapshot, tx)
+	case sys.TagData:
+		return validateDataTransaction(snapshot, tx)
+	case sys.TagUpgrade:
+		return validateUpgradeTransaction(snapshot, tx)
+	case sys.TagMultisig:
+		return validateMultisigTransaction(snapshot, tx)
+	case sys.TagDelegation:
+		return validateDelegationTransaction(snapshot, tx)
+	case sys.TagSlashing:
+		return validateSlashingTransaction(snapshot, tx)
 	}
 
 	return nil
@@ -64,10 +102,12 @@ func validateTransferTransaction(snapshot *avl.Tree, tx Transaction) error {
 		)
 	}
 
-	if bal, exist := ReadAccountBalance(snapshot, tx.Sender); !exist {
+	fee := sponsoredFee(snapshot, tx)
+
+	if bal, exist := ReadAccountBalance(snapshot, tx.Sender); !exist && fee > 0 {
 		return errors.New("sender does not exist")
-	} else if bal < tx.Fee()+payload.Amount+payload.GasLimit+payload.GasDeposit {
-		return errors.Errorf("sender current balance %d is not enough", bal)
+	} else if bal < fee+payload.Amount+payload.GasLimit+payload.GasDeposit {
+		return errors.Wrapf(ErrInsufficientBalance, "sender current balance %d is not enough", bal)
 	}
 
 	return nil
@@ -129,8 +169,12 @@ func validateContractTransaction(snapshot *avl.Tree, tx Transaction) error {
 		return ErrContractAlreadyExists
 	}
 
-	if bal, _ := ReadAccountBalance(snapshot, tx.Sender); bal < tx.Fee()+payload.GasDeposit+payload.GasLimit {
-		return errors.Errorf("sender current balance %d is not enough", bal)
+	if err := validateContractCode(payload.Code, tx.Block); err != nil {
+		return err
+	}
+
+	if bal, _ := ReadAccountBalance(snapshot, tx.Sender); bal < sponsoredFee(snapshot, tx)+payload.GasDeposit+payload.GasLimit {
+		return errors.Wrapf(ErrInsufficientBalance, "sender current balance %d is not enough", bal)
 	}
 
 	return nil
@@ -147,6 +191,7 @@ func validateBatchTransaction(snapshot *avl.Tree, tx Transaction) error {
 			ID:      tx.ID,
 			Sender:  tx.Sender,
 			Nonce:   tx.Nonce,
+			Block:   tx.Block,
 			Tag:     sys.Tag(payload.Tags[i]),
 			Payload: payload.Payloads[i],
 		}
@@ -157,3 +202,32 @@ func validateBatchTransaction(snapshot *avl.Tree, tx Transaction) error {
 
 	return nil
 }
+
+func validateBeaconTransaction(snapshot *avl.Tree, tx Transaction) error {
+	payload, err := ParseBeacon(tx.Payload)
+	if err != nil {
+		return err
+	}
+
+	stake, _ := ReadAccountStake(snapshot, tx.Sender)
+
+	readBeacon := func(index uint64) ([32]byte, bool) {
+		return ReadBeacon(snapshot, index)
+	}
+
+	_, err = verifyBeaconContribution(readBeacon, stake, &tx, payload)
+
+	return err
+}
+
+func validateDataTransaction(snapshot *avl.Tree, tx Transaction) error {
+	if _, err := ParseData(tx.Payload); err != nil {
+		return err
+	}
+
+	if bal, _ := ReadAccountBalance(snapshot, tx.Sender); bal < sponsoredFee(snapshot, tx) {
+		return errors.Wrapf(ErrInsufficientBalance, "sender current balance %d is not enough", bal)
+	}
+
+	return nil
+}
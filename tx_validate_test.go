@@ -31,6 +31,7 @@ import (
 	"github.com/perlin-network/wavelet/avl"
 	"github.com/perlin-network/wavelet/store"
 	"github.com/perlin-network/wavelet/sys"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -169,7 +170,7 @@ func TestValidateTransferTransaction(t *testing.T) {
 		}
 
 		tx := buildSignedTransaction(keys, sys.TagTransfer, 1, 1, payload)
-		assert.Error(t, ValidateTransaction(state, tx))
+		assert.Equal(t, ErrInsufficientBalance, errors.Cause(ValidateTransaction(state, tx)))
 	})
 
 	t.Run("sender not enough balance - contract tx", func(t *testing.T) {
@@ -250,13 +251,10 @@ func TestValidateContractTransaction(t *testing.T) {
 	})
 
 	t.Run("sender not enough balance", func(t *testing.T) {
-		var contractCode [32]byte
-		_, err = rand.Read(contractCode[:])
-		if !assert.NoError(t, err) {
-			return
-		}
+		// Code is validated as a WebAssembly module prior to the balance of the sender.
+		contractCode := wasmFloatModule(0x9a, 0x1a)
 
-		payload, err := buildContractSpawnPayload(5004, 5004, contractCode[:]).Marshal()
+		payload, err := buildContractSpawnPayload(5004, 5004, contractCode).Marshal()
 		if !assert.NoError(t, err) {
 			return
 		}
@@ -267,13 +265,9 @@ func TestValidateContractTransaction(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		var contractCode [32]byte
-		_, err = rand.Read(contractCode[:])
-		if !assert.NoError(t, err) {
-			return
-		}
+		contractCode := wasmFloatModule(0x9a, 0x1a)
 
-		payload, err := buildContractSpawnPayload(1, 1, contractCode[:]).Marshal()
+		payload, err := buildContractSpawnPayload(1, 1, contractCode).Marshal()
 		if !assert.NoError(t, err) {
 			return
 		}
@@ -20,6 +20,8 @@
 package wavelet
 
 import (
+	"bytes"
+	"io"
 	"math/rand"
 
 	"github.com/perlin-network/noise/skademlia"
@@ -51,3 +53,25 @@ func SelectPeers(peers []skademlia.ClosestPeer, amount int) ([]skademlia.Closest
 
 	return activePeers, nil
 }
+
+// readBytes reads exactly n bytes from r. As n is usually decoded from
+// untrusted input, the bytes read are buffered as they arrive rather than
+// allocated upfront, such that a bogus length may not exhaust memory.
+func readBytes(r io.Reader, n uint32) ([]byte, error) {
+	size := int(n)
+	if size > bytes.MinRead {
+		size = bytes.MinRead
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+
+	if _, err := io.CopyN(buf, r, int64(n)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,165 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrKeyringUnsupported is returned by SystemKeyring on operating
+	// systems without a supported keyring.
+	ErrKeyringUnsupported = errors.New("wallet: no keyring is supported on this operating system")
+
+	// ErrSecretNotFound is returned by a Keyring for secrets it does not
+	// hold.
+	ErrSecretNotFound = errors.New("wallet: secret not found in keyring")
+)
+
+// Keyring is a store of secrets provided by the operating system, such as
+// the macOS Keychain, the Windows Credential Manager, or a Secret Service
+// implementation on Linux. Secrets are addressed by a service and a user.
+type Keyring interface {
+	Get(service, user string) (string, error)
+	Set(service, user, secret string) error
+	Delete(service, user string) error
+}
+
+// DefaultKeyringService is the keyring service accounts are stored under by
+// default.
+const DefaultKeyringService = "wavelet-wallet"
+
+// indexUser is the user the newline-separated names of all accounts are
+// stored under, within the keyring service suffixed with ".index". Keyrings
+// may not be enumerated portably.
+const indexUser = "names"
+
+var _ Keystore = (*KeyringKeystore)(nil)
+
+// KeyringKeystore stores the private keys of accounts in a Keyring, such that
+// they never exist as files.
+type KeyringKeystore struct {
+	ring    Keyring
+	service string
+
+	mu sync.Mutex
+}
+
+// NewKeyringKeystore creates a keystore storing keys within the given service
+// of ring.
+func NewKeyringKeystore(ring Keyring, service string) *KeyringKeystore {
+	return &KeyringKeystore{ring: ring, service: service}
+}
+
+func (k *KeyringKeystore) Names() ([]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.names()
+}
+
+func (k *KeyringKeystore) Load(name string) (edwards25519.PrivateKey, error) {
+	var key edwards25519.PrivateKey
+
+	if err := ValidateName(name); err != nil {
+		return key, err
+	}
+
+	secret, err := k.ring.Get(k.service, name)
+	if err != nil {
+		if errors.Cause(err) == ErrSecretNotFound {
+			return key, errors.Wrapf(ErrAccountNotFound, "%q", name)
+		}
+
+		return key, errors.Wrapf(err, "failed to read key of account %q from keyring", name)
+	}
+
+	if key, err = decodeHexKey([]byte(secret)); err != nil {
+		return key, errors.Wrapf(err, "failed to decode key of account %q", name)
+	}
+
+	return key, nil
+}
+
+func (k *KeyringKeystore) Store(name string, key edwards25519.PrivateKey) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	names, err := k.names()
+	if err != nil {
+		return err
+	}
+
+	i := sort.SearchStrings(names, name)
+	if i < len(names) && names[i] == name {
+		return errors.Wrapf(ErrAccountExists, "%q", name)
+	}
+
+	if err := k.ring.Set(k.service, name, hex.EncodeToString(key[:])); err != nil {
+		return errors.Wrapf(err, "failed to write key of account %q to keyring", name)
+	}
+
+	names = append(names[:i], append([]string{name}, names[i:]...)...)
+
+	return k.setNames(names)
+}
+
+func (k *KeyringKeystore) Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.ring.Delete(k.service, name); err != nil {
+		if errors.Cause(err) == ErrSecretNotFound {
+			return errors.Wrapf(ErrAccountNotFound, "%q", name)
+		}
+
+		return errors.Wrapf(err, "failed to delete key of account %q from keyring", name)
+	}
+
+	names, err := k.names()
+	if err != nil {
+		return err
+	}
+
+	if i := sort.SearchStrings(names, name); i < len(names) && names[i] == name {
+		return k.setNames(append(names[:i], names[i+1:]...))
+	}
+
+	return nil
+}
+
+func (k *KeyringKeystore) names() ([]string, error) {
+	index, err := k.ring.Get(k.service+".index", indexUser)
+	if err != nil {
+		if errors.Cause(err) == ErrSecretNotFound {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to read account names from keyring")
+	}
+
+	names := strings.Fields(index)
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (k *KeyringKeystore) setNames(names []string) error {
+	if err := k.ring.Set(k.service+".index", indexUser, strings.Join(names, "\n")); err != nil {
+		return errors.Wrap(err, "failed to write account names to keyring")
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SystemKeyring returns the macOS Keychain, accessed through the security
+// command.
+func SystemKeyring() (Keyring, error) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil, errors.Wrap(ErrKeyringUnsupported, "the security command is not installed")
+	}
+
+	return keychain{}, nil
+}
+
+// errKeychainNotFound is the exit code of the security command for items
+// that could not be found.
+const errKeychainNotFound = 44
+
+type keychain struct{}
+
+func (keychain) Get(service, user string) (string, error) {
+	out, err := runKeychain(nil, "find-generic-password", "-s", service, "-a", user, "-w")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+func (keychain) Set(service, user, secret string) error {
+	// The secret is passed through stdin in interactive mode, such that it
+	// never appears within the arguments of a process.
+	cmd := fmt.Sprintf("add-generic-password -U -s %q -a %q -w %q\n", service, user, secret)
+
+	_, err := runKeychain(strings.NewReader(cmd), "-i")
+
+	return err
+}
+
+func (keychain) Delete(service, user string) error {
+	_, err := runKeychain(nil, "delete-generic-password", "-s", service, "-a", user)
+
+	return err
+}
+
+func runKeychain(stdin *strings.Reader, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("security", args...) // nolint:gosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	if err := cmd.Run(); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok && exit.ExitCode() == errKeychainNotFound {
+			return "", ErrSecretNotFound
+		}
+
+		return "", errors.Wrapf(err, "security: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	// In interactive mode, failures are only reported through stderr.
+	if stdin != nil && stderr.Len() > 0 {
+		return "", errors.Errorf("security: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SystemKeyring returns the Secret Service of the desktop session (e.g.
+// GNOME Keyring or KWallet), accessed through the secret-tool command of
+// libsecret.
+func SystemKeyring() (Keyring, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, errors.Wrap(ErrKeyringUnsupported, "the secret-tool command of libsecret is not installed")
+	}
+
+	return secretService{}, nil
+}
+
+type secretService struct{}
+
+func (secretService) Get(service, user string) (string, error) {
+	out, err := runSecretTool(nil, "lookup", "service", service, "username", user)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+func (secretService) Set(service, user, secret string) error {
+	// The secret is read from stdin, such that it never appears within the
+	// arguments of a process.
+	_, err := runSecretTool(strings.NewReader(secret),
+		"store", "--label", service+" "+user, "service", service, "username", user,
+	)
+
+	return err
+}
+
+func (s secretService) Delete(service, user string) error {
+	// secret-tool clear does not report whether anything was cleared.
+	if _, err := s.Get(service, user); err != nil {
+		return err
+	}
+
+	_, err := runSecretTool(nil, "clear", "service", service, "username", user)
+
+	return err
+}
+
+func runSecretTool(stdin *strings.Reader, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("secret-tool", args...) // nolint:gosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	if err := cmd.Run(); err != nil {
+		// secret-tool fails silently for secrets that do not exist.
+		if _, ok := err.(*exec.ExitError); ok && stdout.Len() == 0 && stderr.Len() == 0 {
+			return "", ErrSecretNotFound
+		}
+
+		return "", errors.Wrapf(err, "secret-tool: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,9 @@
+// +build !darwin,!linux,!windows
+
+package wallet
+
+// SystemKeyring returns ErrKeyringUnsupported, as no keyring is supported on
+// this operating system.
+func SystemKeyring() (Keyring, error) {
+	return nil, ErrKeyringUnsupported
+}
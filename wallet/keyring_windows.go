@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+
+	errorNotFound syscall.Errno = 1168
+)
+
+// credential mirrors the CREDENTIALW struct of wincred.h.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// SystemKeyring returns the Windows Credential Manager.
+func SystemKeyring() (Keyring, error) {
+	if err := advapi32.Load(); err != nil {
+		return nil, errors.Wrap(ErrKeyringUnsupported, err.Error())
+	}
+
+	return credentialManager{}, nil
+}
+
+// credentialManager stores secrets as generic credentials, targeted at
+// "service:user".
+type credentialManager struct{}
+
+func (credentialManager) Get(service, user string) (string, error) {
+	target, err := syscall.UTF16PtrFromString(service + ":" + user)
+	if err != nil {
+		return "", err
+	}
+
+	var cred *credential
+
+	r, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		return "", credError(err)
+	}
+
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred))) // nolint:errcheck
+
+	blob := (*[1 << 20]byte)(unsafe.Pointer(cred.CredentialBlob))[:cred.CredentialBlobSize:cred.CredentialBlobSize]
+
+	return string(blob), nil
+}
+
+func (credentialManager) Set(service, user, secret string) error {
+	target, err := syscall.UTF16PtrFromString(service + ":" + user)
+	if err != nil {
+		return err
+	}
+
+	username, err := syscall.UTF16PtrFromString(user)
+	if err != nil {
+		return err
+	}
+
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(secret)),
+		Persist:            credPersistLocalMachine,
+		UserName:           username,
+	}
+
+	blob := []byte(secret)
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	if r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return credError(err)
+	}
+
+	return nil
+}
+
+func (credentialManager) Delete(service, user string) error {
+	target, err := syscall.UTF16PtrFromString(service + ":" + user)
+	if err != nil {
+		return err
+	}
+
+	if r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); r == 0 {
+		return credError(err)
+	}
+
+	return nil
+}
+
+func credError(err error) error {
+	if err == errorNotFound {
+		return ErrSecretNotFound
+	}
+
+	return errors.Wrap(err, "credential manager")
+}
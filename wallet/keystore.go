@@ -0,0 +1,376 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrAccountNotFound is returned when no account under a name exists.
+	ErrAccountNotFound = errors.New("wallet: account not found")
+
+	// ErrAccountExists is returned when storing an account under a name that
+	// is already taken.
+	ErrAccountExists = errors.New("wallet: account already exists")
+
+	// ErrNoHDKeys is returned when loading or storing HD keys in a keystore
+	// that does not hold them.
+	ErrNoHDKeys = errors.New("wallet: keystore does not hold HD keys")
+
+	// ErrInvalidName is returned for account names that are empty, or that
+	// contain anything other than letters, digits, '-', '_' and '.'.
+	ErrInvalidName = errors.New("wallet: invalid account name")
+)
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidateName checks that name may be used as an account name.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) || strings.Trim(name, ".") == "" {
+		return errors.Wrapf(ErrInvalidName, "%q", name)
+	}
+
+	return nil
+}
+
+// Keystore persists the private keys of named accounts.
+type Keystore interface {
+	// Names lists the names of all stored accounts in ascending order.
+	Names() ([]string, error)
+
+	// Load returns the private key of an account, or ErrAccountNotFound.
+	Load(name string) (edwards25519.PrivateKey, error)
+
+	// Store saves the private key of a new account, or returns
+	// ErrAccountExists should the name be taken.
+	Store(name string, key edwards25519.PrivateKey) error
+
+	// Delete removes an account, or returns ErrAccountNotFound.
+	Delete(name string) error
+}
+
+var _ Keystore = (*DirKeystore)(nil)
+
+const (
+	// plainExt is the extension of plain key files, matching the wallets
+	// generated by cmd/wallet.
+	plainExt = ".txt"
+
+	// encryptedExt is the extension of key files encrypted with a
+	// passphrase by package security.
+	encryptedExt = ".key"
+
+	// keyFileExt is the extension of JSON key files encrypted with a
+	// passphrase by package security.
+	keyFileExt = ".json"
+)
+
+// DirKeystore stores each account's private key in a file named after the
+// account within a directory. Keys are either stored hex-encoded, or
+// encrypted with a passphrase.
+type DirKeystore struct {
+	dir string
+	ext string
+
+	encode func(key edwards25519.PrivateKey) ([]byte, error)
+	decode func(buf []byte) (edwards25519.PrivateKey, error)
+
+	// HD keys are only supported by keystores of JSON key files.
+	encodeHD func(k security.HDKey) ([]byte, error)
+	decodeHD func(buf []byte) (security.HDKey, error)
+}
+
+// NewDirKeystore opens a keystore of hex-encoded keys in dir, creating the
+// directory if it does not exist.
+func NewDirKeystore(dir string) (*DirKeystore, error) {
+	return newDirKeystore(dir, plainExt, encodeHexKey, decodeHexKey)
+}
+
+// NewEncryptedDirKeystore opens a keystore in dir whose keys are encrypted
+// with passphrase, creating the directory if it does not exist. Newly stored
+// keys are encrypted with params.
+func NewEncryptedDirKeystore(dir string, passphrase []byte, params security.Params) (*DirKeystore, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	encode := func(key edwards25519.PrivateKey) ([]byte, error) {
+		return security.EncryptKey(key, passphrase, params)
+	}
+
+	decode := func(buf []byte) (edwards25519.PrivateKey, error) {
+		return security.DecryptKey(buf, passphrase)
+	}
+
+	return newDirKeystore(dir, encryptedExt, encode, decode)
+}
+
+// NewKeyFileDirKeystore opens a keystore in dir whose keys are stored as
+// JSON key files encrypted with passphrase, creating the directory if it
+// does not exist. Newly stored keys are encrypted with params.
+func NewKeyFileDirKeystore(dir string, passphrase []byte, params security.ScryptParams) (*DirKeystore, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	encode := func(key edwards25519.PrivateKey) ([]byte, error) {
+		return security.EncryptKeyFile(key, passphrase, params)
+	}
+
+	decode := func(buf []byte) (edwards25519.PrivateKey, error) {
+		return security.DecryptKeyFile(buf, passphrase)
+	}
+
+	keystore, err := newDirKeystore(dir, keyFileExt, encode, decode)
+	if err != nil {
+		return nil, err
+	}
+
+	keystore.encodeHD = func(k security.HDKey) ([]byte, error) {
+		return security.EncryptHDKeyFile(k, passphrase, params)
+	}
+
+	keystore.decodeHD = func(buf []byte) (security.HDKey, error) {
+		return security.DecryptHDKeyFile(buf, passphrase)
+	}
+
+	return keystore, nil
+}
+
+// Path returns the path of the file the key of an account is stored in.
+func (k *DirKeystore) Path(name string) (string, error) {
+	return k.path(name)
+}
+
+func newDirKeystore(
+	dir, ext string,
+	encode func(key edwards25519.PrivateKey) ([]byte, error),
+	decode func(buf []byte) (edwards25519.PrivateKey, error),
+) (*DirKeystore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create keystore directory %q", dir)
+	}
+
+	return &DirKeystore{dir: dir, ext: ext, encode: encode, decode: decode}, nil
+}
+
+// Dir returns the directory the keystore is located in.
+func (k *DirKeystore) Dir() string {
+	return k.dir
+}
+
+func (k *DirKeystore) Names() ([]string, error) {
+	files, err := ioutil.ReadDir(k.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list keystore directory")
+	}
+
+	var names []string
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != k.ext {
+			continue
+		}
+
+		name := strings.TrimSuffix(file.Name(), k.ext)
+
+		if ValidateName(name) == nil {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (k *DirKeystore) Load(name string) (edwards25519.PrivateKey, error) {
+	var key edwards25519.PrivateKey
+
+	buf, err := k.read(name)
+	if err != nil {
+		return key, err
+	}
+
+	if key, err = k.decode(buf); err != nil {
+		return key, errors.Wrapf(err, "failed to decode key of account %q", name)
+	}
+
+	return key, nil
+}
+
+func (k *DirKeystore) Store(name string, key edwards25519.PrivateKey) error {
+	buf, err := k.encode(key)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode key of account %q", name)
+	}
+
+	return k.write(name, buf)
+}
+
+// LoadHD returns the HD key of an account, or ErrAccountNotFound. Only
+// keystores of JSON key files hold HD keys, and only for accounts stored
+// with StoreHD.
+func (k *DirKeystore) LoadHD(name string) (security.HDKey, error) {
+	if k.decodeHD == nil {
+		return security.HDKey{}, ErrNoHDKeys
+	}
+
+	buf, err := k.read(name)
+	if err != nil {
+		return security.HDKey{}, err
+	}
+
+	hd, err := k.decodeHD(buf)
+	if err != nil {
+		return hd, errors.Wrapf(err, "failed to decode key of account %q", name)
+	}
+
+	return hd, nil
+}
+
+// StoreHD saves the HD key of a new account, or returns ErrAccountExists
+// should the name be taken. The account signs with the private key of the
+// HD key, as if it were stored with Store.
+func (k *DirKeystore) StoreHD(name string, hd security.HDKey) error {
+	if k.encodeHD == nil {
+		return ErrNoHDKeys
+	}
+
+	buf, err := k.encodeHD(hd)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode key of account %q", name)
+	}
+
+	return k.write(name, buf)
+}
+
+func (k *DirKeystore) read(name string) ([]byte, error) {
+	path, err := k.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Wrapf(ErrAccountNotFound, "%q", name)
+		}
+
+		return nil, errors.Wrapf(err, "failed to read key of account %q", name)
+	}
+
+	return buf, nil
+}
+
+func (k *DirKeystore) write(name string, buf []byte) error {
+	path, err := k.path(name)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return errors.Wrapf(ErrAccountExists, "%q", name)
+		}
+
+		return errors.Wrapf(err, "failed to create key file of account %q", name)
+	}
+
+	if _, err := f.Write(buf); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+
+		return errors.Wrapf(err, "failed to write key of account %q", name)
+	}
+
+	return f.Close()
+}
+
+func (k *DirKeystore) Delete(name string) error {
+	path, err := k.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return errors.Wrapf(ErrAccountNotFound, "%q", name)
+		}
+
+		return errors.Wrapf(err, "failed to delete key of account %q", name)
+	}
+
+	return nil
+}
+
+func (k *DirKeystore) path(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(k.dir, name+k.ext), nil
+}
+
+func encodeHexKey(key edwards25519.PrivateKey) ([]byte, error) {
+	return []byte(hex.EncodeToString(key[:])), nil
+}
+
+func decodeHexKey(buf []byte) (edwards25519.PrivateKey, error) {
+	var key edwards25519.PrivateKey
+
+	buf = []byte(strings.TrimSpace(string(buf)))
+
+	if hex.DecodedLen(len(buf)) != edwards25519.SizePrivateKey {
+		return key, errors.Errorf("key must be %d hex characters", hex.EncodedLen(edwards25519.SizePrivateKey))
+	}
+
+	if _, err := hex.Decode(key[:], buf); err != nil {
+		return key, err
+	}
+
+	return key, nil
+}
+
+// accountKeystore loads the keys of accounts derived from the HD keys of a
+// keystore at an account index.
+type accountKeystore struct {
+	*DirKeystore
+	index uint32
+}
+
+// NewAccountKeystore wraps keystore such that every account signs with the
+// key derived from its HD key at account index, as by
+// security.HDKey.DeriveAccount. Accounts only holding a private key may not
+// be loaded, and new accounts may not be stored through it.
+func NewAccountKeystore(keystore *DirKeystore, index uint32) Keystore {
+	return &accountKeystore{DirKeystore: keystore, index: index}
+}
+
+func (k *accountKeystore) Load(name string) (edwards25519.PrivateKey, error) {
+	hd, err := k.LoadHD(name)
+	if err != nil {
+		return hd.PrivateKey, err
+	}
+
+	account, err := hd.DeriveAccount(k.index)
+	if err != nil {
+		return account.PrivateKey, errors.Wrapf(err, "failed to derive account %d of %q", k.index, name)
+	}
+
+	return account.PrivateKey, nil
+}
+
+func (k *accountKeystore) Store(name string, key edwards25519.PrivateKey) error {
+	return errors.Errorf("wallet: account %q may not be stored at account index %d", name, k.index)
+}
@@ -0,0 +1,452 @@
+// Package wallet manages multiple named accounts whose private keys are kept
+// in a Keystore. The balances of opened accounts are kept up to date through
+// the accounts websocket of a node.
+package wallet
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/pkg/errors"
+)
+
+// ErrClosed is returned when using a wallet after it was closed.
+var ErrClosed = errors.New("wallet: closed")
+
+// Dialer connects to a node on behalf of the account with the given key.
+type Dialer func(key edwards25519.PrivateKey) (wctl.LedgerClient, error)
+
+// DialConfig returns a Dialer creating a wctl.Client from config, with its
+// private key replaced by that of the account being dialed.
+func DialConfig(config wctl.Config) Dialer {
+	return func(key edwards25519.PrivateKey) (wctl.LedgerClient, error) {
+		config.PrivateKey = key
+
+		client, err := wctl.NewClient(config)
+		if err != nil {
+			// NewClient may return a client alongside an error.
+			if client != nil {
+				client.Close()
+			}
+
+			return nil, err
+		}
+
+		return client, nil
+	}
+}
+
+// Account is a snapshot of a named account. Its balances are only known once
+// the account is opened.
+type Account struct {
+	Name      string
+	PublicKey edwards25519.PublicKey
+
+	Open       bool
+	Balance    uint64
+	GasBalance uint64
+	Stake      uint64
+	Reward     uint64
+}
+
+// account is the state of an opened account.
+type account struct {
+	Account
+
+	client wctl.LedgerClient
+	stop   func()
+
+	// Updates received before the account was fetched, replayed on top of
+	// the fetched balances.
+	pending []func()
+}
+
+// Wallet manages the named accounts of a Keystore. Accounts are opened on
+// demand by Send and History, or explicitly with Open.
+//
+// A Wallet is safe for concurrent use.
+type Wallet struct {
+	keystore Keystore
+	dial     Dialer
+
+	// OnUpdate, if set, is called whenever the balances of an opened
+	// account change. It must be set before any account is opened.
+	OnUpdate func(Account)
+
+	mu       sync.Mutex
+	closed   bool
+	accounts map[string]*account
+}
+
+// New creates a Wallet over the accounts of keystore, connecting to nodes
+// with dial.
+func New(keystore Keystore, dial Dialer) *Wallet {
+	return &Wallet{
+		keystore: keystore,
+		dial:     dial,
+		accounts: make(map[string]*account),
+	}
+}
+
+// Keystore returns the keystore of the wallet.
+func (w *Wallet) Keystore() Keystore {
+	return w.keystore
+}
+
+// Create generates a new account under name.
+func (w *Wallet) Create(name string) (Account, error) {
+	keys, err := skademlia.NewKeys(sys.SKademliaC1, sys.SKademliaC2)
+	if err != nil {
+		return Account{}, errors.Wrap(err, "failed to generate keypair")
+	}
+
+	return w.Import(name, keys.PrivateKey())
+}
+
+// Import stores an existing private key as a new account under name.
+func (w *Wallet) Import(name string, key edwards25519.PrivateKey) (Account, error) {
+	if err := w.keystore.Store(name, key); err != nil {
+		return Account{}, err
+	}
+
+	return Account{Name: name, PublicKey: key.Public()}, nil
+}
+
+// Remove closes and deletes the account under name from the keystore.
+func (w *Wallet) Remove(name string) error {
+	w.mu.Lock()
+	a, open := w.accounts[name]
+	delete(w.accounts, name)
+	w.mu.Unlock()
+
+	if open {
+		a.close()
+	}
+
+	return w.keystore.Delete(name)
+}
+
+// List returns all accounts of the keystore, ordered by name.
+func (w *Wallet) List() ([]Account, error) {
+	names, err := w.keystore.Names()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]Account, 0, len(names))
+
+	for _, name := range names {
+		a, err := w.Account(name)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, a)
+	}
+
+	return accounts, nil
+}
+
+// Account returns a snapshot of the account under name, without opening it.
+func (w *Wallet) Account(name string) (Account, error) {
+	w.mu.Lock()
+	if a, open := w.accounts[name]; open {
+		snapshot := a.Account
+		w.mu.Unlock()
+
+		return snapshot, nil
+	}
+	w.mu.Unlock()
+
+	key, err := w.keystore.Load(name)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return Account{Name: name, PublicKey: key.Public()}, nil
+}
+
+// Open connects the account under name to a node, fetches its balances, and
+// subscribes to their updates. Opening an opened account is a no-op.
+func (w *Wallet) Open(name string) (Account, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return Account{}, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return a.Account, nil
+}
+
+// Receive returns the address others may send PERLs to the account under
+// name with.
+func (w *Wallet) Receive(name string) (string, error) {
+	a, err := w.Account(name)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(a.PublicKey[:]), nil
+}
+
+// Send transfers amount PERLs from the account under name to recipient.
+func (w *Wallet) Send(name string, recipient [32]byte, amount uint64) (*wctl.TxResponse, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.Pay(recipient, amount)
+}
+
+// Deploy spawns a smart contract from the account under name.
+func (w *Wallet) Deploy(name string, spawn wctl.ContractSpawn) (*wctl.TxResponse, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.SendContract(spawn)
+}
+
+// Call calls a smart contract function from the account under name.
+func (w *Wallet) Call(name string, contract [32]byte, fn wctl.FunctionCall) (*wctl.TxResponse, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.Call(contract, fn)
+}
+
+// Simulate simulates calling a smart contract function from the account
+// under name, without anything being committed.
+func (w *Wallet) Simulate(name string, contract [32]byte, fn wctl.FunctionCall) (*wctl.ContractCallResult, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.SimulateContractCall(contract, fn)
+}
+
+// CreateMultisig creates a multisig account from the account under name,
+// whose PERLs are only moved once threshold of participants approve.
+func (w *Wallet) CreateMultisig(name string, participants [][32]byte, threshold uint8) (*wctl.TxResponse, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.CreateMultisig(participants, threshold)
+}
+
+// ProposeMultisig proposes transferring amount PERLs from the multisig
+// account to recipient as the participant under name.
+func (w *Wallet) ProposeMultisig(name string, account, recipient [32]byte, amount uint64) (*wctl.TxResponse, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.ProposeMultisig(account, recipient, amount)
+}
+
+// ApproveMultisig approves a transfer proposed from a multisig account as the
+// participant under name.
+func (w *Wallet) ApproveMultisig(name string, proposal [32]byte) (*wctl.TxResponse, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.ApproveMultisig(proposal)
+}
+
+// ExecuteMultisig executes an approved transfer proposed from a multisig
+// account as the participant under name.
+func (w *Wallet) ExecuteMultisig(name string, proposal [32]byte) (*wctl.TxResponse, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.ExecuteMultisig(proposal)
+}
+
+// CancelMultisig cancels a transfer the account under name proposed from a
+// multisig account.
+func (w *Wallet) CancelMultisig(name string, proposal [32]byte) (*wctl.TxResponse, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.CancelMultisig(proposal)
+}
+
+// Estimate estimates what sending a transaction with the given tag and
+// payload from the account under name would cost.
+func (w *Wallet) Estimate(name string, tag byte, payload []byte) (*wctl.FeeEstimate, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.EstimateFee(tag, payload)
+}
+
+// History lists the transactions sent by the account under name.
+func (w *Wallet) History(name string, offset, limit uint64) ([]wctl.Transaction, error) {
+	a, err := w.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.ListTransactions(hex.EncodeToString(a.PublicKey[:]), "", offset, limit)
+}
+
+// Close closes all opened accounts. The wallet may not be used afterwards.
+func (w *Wallet) Close() {
+	w.mu.Lock()
+	accounts := w.accounts
+	w.accounts = make(map[string]*account)
+	w.closed = true
+	w.mu.Unlock()
+
+	for _, a := range accounts {
+		a.close()
+	}
+}
+
+func (w *Wallet) open(name string) (*account, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil, ErrClosed
+	}
+
+	if a, open := w.accounts[name]; open {
+		w.mu.Unlock()
+		return a, nil
+	}
+	w.mu.Unlock()
+
+	key, err := w.keystore.Load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := w.dial(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect account %q", name)
+	}
+
+	a := &account{
+		Account: Account{Name: name, PublicKey: key.Public()},
+		client:  client,
+	}
+
+	w.subscribe(a)
+
+	if a.stop, err = client.PollAccounts(); err != nil {
+		client.Close()
+		return nil, errors.Wrapf(err, "failed to subscribe to account %q", name)
+	}
+
+	// Fetch the balances after subscribing, such that no update is missed.
+	state, err := client.GetAccount(a.PublicKey)
+	if err != nil {
+		a.close()
+		return nil, errors.Wrapf(err, "failed to fetch account %q", name)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Another goroutine may have opened the account, or closed the wallet
+	// in the meantime.
+	if existing, open := w.accounts[name]; open || w.closed {
+		go a.close()
+
+		if w.closed {
+			return nil, ErrClosed
+		}
+
+		return existing, nil
+	}
+
+	a.Balance = state.Balance
+	a.GasBalance = state.GasBalance
+	a.Stake = state.Stake
+	a.Reward = state.Reward
+
+	for _, fn := range a.pending {
+		fn()
+	}
+
+	a.pending = nil
+	a.Open = true
+
+	w.accounts[name] = a
+
+	return a, nil
+}
+
+// subscribe registers callbacks keeping the balances of a up to date.
+func (w *Wallet) subscribe(a *account) {
+	events := a.client.Events()
+
+	events.OnBalanceUpdated = func(u wctl.BalanceUpdate) {
+		w.update(a, u.AccountID, func() { a.Balance = u.Balance })
+	}
+
+	events.OnGasBalanceUpdated = func(u wctl.GasBalanceUpdate) {
+		w.update(a, u.AccountID, func() { a.GasBalance = u.GasBalance })
+	}
+
+	events.OnStakeUpdated = func(u wctl.StakeUpdated) {
+		w.update(a, u.AccountID, func() { a.Stake = u.Stake })
+	}
+
+	events.OnRewardUpdated = func(u wctl.RewardUpdated) {
+		w.update(a, u.AccountID, func() { a.Reward = u.Reward })
+	}
+}
+
+// update applies fn to a should id be its public key, and notifies OnUpdate.
+func (w *Wallet) update(a *account, id [32]byte, fn func()) {
+	if id != a.PublicKey {
+		return
+	}
+
+	w.mu.Lock()
+	if !a.Open {
+		a.pending = append(a.pending, fn)
+		w.mu.Unlock()
+
+		return
+	}
+
+	fn()
+	snapshot := a.Account
+	w.mu.Unlock()
+
+	if w.OnUpdate != nil {
+		w.OnUpdate(snapshot)
+	}
+}
+
+func (a *account) close() {
+	if a.stop != nil {
+		a.stop()
+	}
+
+	a.client.Close()
+}
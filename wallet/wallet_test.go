@@ -0,0 +1,438 @@
+// +build unit
+
+package wallet
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/perlin-network/wavelet/wctl/clientmock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeystore(t *testing.T) (*DirKeystore, func()) {
+	dir, err := ioutil.TempDir("", "wallet")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	ks, err := NewDirKeystore(filepath.Join(dir, "keys"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return ks, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestDirKeystore(t *testing.T) {
+	ks, cleanup := newTestKeystore(t)
+	defer cleanup()
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ks.Store("bob", key))
+	assert.NoError(t, ks.Store("alice", key))
+	assert.Equal(t, ErrAccountExists, errors.Cause(ks.Store("alice", key)))
+
+	// Files of other extensions, and directories, are not accounts.
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(ks.Dir(), "notes.md"), nil, 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(ks.Dir(), "dir.txt"), 0700))
+
+	names, err := ks.Names()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, names)
+
+	loaded, err := ks.Load("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	// Wallets generated by cmd/wallet may end with a newline.
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(ks.Dir(), "carol.txt"), []byte(hex.EncodeToString(key[:])+"\n"), 0600))
+
+	loaded, err = ks.Load("carol")
+	assert.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	assert.NoError(t, ks.Delete("bob"))
+	assert.Equal(t, ErrAccountNotFound, errors.Cause(ks.Delete("bob")))
+
+	_, err = ks.Load("bob")
+	assert.Equal(t, ErrAccountNotFound, errors.Cause(err))
+
+	for _, name := range []string{"", ".", "..", "a/b", "a b", "../escape"} {
+		assert.Equal(t, ErrInvalidName, errors.Cause(ks.Store(name, key)), name)
+	}
+}
+
+func TestEncryptedDirKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	params := security.Params{Time: 1, Memory: 64, Threads: 1}
+
+	ks, err := NewEncryptedDirKeystore(dir, []byte("passphrase"), params)
+	assert.NoError(t, err)
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ks.Store("alice", key))
+
+	buf, err := ioutil.ReadFile(filepath.Join(dir, "alice.key"))
+	assert.NoError(t, err)
+	assert.True(t, security.IsEncrypted(buf))
+
+	loaded, err := ks.Load("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	// Plain keys in the same directory are not listed.
+	plain, err := NewDirKeystore(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, plain.Store("bob", key))
+
+	names, err := ks.Names()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, names)
+
+	wrong, err := NewEncryptedDirKeystore(dir, []byte("wrong"), params)
+	assert.NoError(t, err)
+
+	_, err = wrong.Load("alice")
+	assert.Equal(t, security.ErrDecrypt, errors.Cause(err))
+
+	_, err = NewEncryptedDirKeystore(dir, []byte("passphrase"), security.Params{})
+	assert.Equal(t, security.ErrInvalidParams, errors.Cause(err))
+}
+
+func TestKeyFileDirKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	params := security.ScryptParams{N: 16, R: 8, P: 1}
+
+	ks, err := NewKeyFileDirKeystore(dir, []byte("passphrase"), params)
+	assert.NoError(t, err)
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ks.Store("alice", key))
+
+	path, err := ks.Path("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "alice.json"), path)
+
+	buf, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, security.IsKeyFile(buf))
+
+	loaded, err := ks.Load("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	// Keys encrypted otherwise in the same directory are not listed.
+	encrypted, err := NewEncryptedDirKeystore(dir, []byte("passphrase"), security.Params{Time: 1, Memory: 64, Threads: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, encrypted.Store("bob", key))
+
+	names, err := ks.Names()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, names)
+
+	wrong, err := NewKeyFileDirKeystore(dir, []byte("wrong"), params)
+	assert.NoError(t, err)
+
+	_, err = wrong.Load("alice")
+	assert.Equal(t, security.ErrDecrypt, errors.Cause(err))
+
+	_, err = NewKeyFileDirKeystore(dir, []byte("passphrase"), security.ScryptParams{})
+	assert.Equal(t, security.ErrInvalidScryptParams, errors.Cause(err))
+}
+
+func TestAccountKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	ks, err := NewKeyFileDirKeystore(dir, []byte("passphrase"), security.ScryptParams{N: 16, R: 8, P: 1})
+	assert.NoError(t, err)
+
+	hd, err := security.NewMasterKey([]byte("seed"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ks.StoreHD("alice", hd))
+
+	// HD keys sign with their own private key, unless derived at an account
+	// index.
+	loaded, err := ks.Load("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, hd.PrivateKey, loaded)
+
+	for _, index := range []uint32{0, 7} {
+		account, err := hd.DeriveAccount(index)
+		assert.NoError(t, err)
+
+		loaded, err = NewAccountKeystore(ks, index).Load("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, account.PrivateKey, loaded)
+	}
+
+	accounts := NewAccountKeystore(ks, 1)
+
+	names, err := accounts.Names()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, names)
+
+	assert.Error(t, accounts.Store("bob", hd.PrivateKey))
+
+	// Accounts only holding a private key may not be derived from.
+	assert.NoError(t, ks.Store("bob", hd.PrivateKey))
+
+	_, err = accounts.Load("bob")
+	assert.Equal(t, security.ErrNoChainCode, errors.Cause(err))
+
+	plain, err := NewDirKeystore(dir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrNoHDKeys, plain.StoreHD("carol", hd))
+}
+
+// memKeyring is an in-memory Keyring.
+type memKeyring map[string]string
+
+func (m memKeyring) Get(service, user string) (string, error) {
+	secret, ok := m[service+":"+user]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+
+	return secret, nil
+}
+
+func (m memKeyring) Set(service, user, secret string) error {
+	m[service+":"+user] = secret
+	return nil
+}
+
+func (m memKeyring) Delete(service, user string) error {
+	if _, ok := m[service+":"+user]; !ok {
+		return ErrSecretNotFound
+	}
+
+	delete(m, service+":"+user)
+
+	return nil
+}
+
+func TestKeyringKeystore(t *testing.T) {
+	ring := make(memKeyring)
+	ks := NewKeyringKeystore(ring, DefaultKeyringService)
+
+	names, err := ks.Names()
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ks.Store("carol", key))
+	assert.NoError(t, ks.Store("alice", key))
+	assert.NoError(t, ks.Store("bob", key))
+	assert.Equal(t, ErrAccountExists, errors.Cause(ks.Store("alice", key)))
+	assert.Equal(t, ErrInvalidName, errors.Cause(ks.Store("a b", key)))
+
+	assert.Equal(t, hex.EncodeToString(key[:]), ring[DefaultKeyringService+":alice"])
+
+	names, err = ks.Names()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob", "carol"}, names)
+
+	loaded, err := ks.Load("bob")
+	assert.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	assert.NoError(t, ks.Delete("bob"))
+	assert.Equal(t, ErrAccountNotFound, errors.Cause(ks.Delete("bob")))
+
+	_, err = ks.Load("bob")
+	assert.Equal(t, ErrAccountNotFound, errors.Cause(err))
+
+	// Accounts are persisted within the keyring alone.
+	names, err = NewKeyringKeystore(ring, DefaultKeyringService).Names()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice", "carol"}, names)
+
+	// Services are isolated from one another.
+	names, err = NewKeyringKeystore(ring, "other").Names()
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestWallet(t *testing.T) {
+	ks, cleanup := newTestKeystore(t)
+	defer cleanup()
+
+	clients := make(map[edwards25519.PublicKey]*clientmock.Client)
+
+	w := New(ks, func(key edwards25519.PrivateKey) (wctl.LedgerClient, error) {
+		client := &clientmock.Client{
+			PublicKey: key.Public(),
+			GetAccountFunc: func(account [32]byte) (*wctl.Account, error) {
+				return &wctl.Account{PublicKey: account, Balance: 100}, nil
+			},
+			SendTransactionFunc: func(tag byte, payload []byte) (*wctl.TxResponse, error) {
+				return &wctl.TxResponse{}, nil
+			},
+			ListTransactionsFunc: func(sender string, _ string, _ uint64, _ uint64) ([]wctl.Transaction, error) {
+				pub := key.Public()
+				assert.Equal(t, hex.EncodeToString(pub[:]), sender)
+				return []wctl.Transaction{{Tag: byte(sys.TagTransfer)}}, nil
+			},
+		}
+
+		clients[key.Public()] = client
+
+		return client, nil
+	})
+	defer w.Close()
+
+	var updates []Account
+	w.OnUpdate = func(a Account) {
+		updates = append(updates, a)
+	}
+
+	alice, err := w.Create("alice")
+	assert.NoError(t, err)
+	assert.False(t, alice.Open)
+
+	_, err = w.Create("alice")
+	assert.Equal(t, ErrAccountExists, errors.Cause(err))
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	bob, err := w.Import("bob", key)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Public(), bob.PublicKey)
+
+	accounts, err := w.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []Account{alice, bob}, accounts)
+
+	address, err := w.Receive("bob")
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(bob.PublicKey[:]), address)
+
+	// Accounts are only connected once they are used.
+	assert.Empty(t, clients)
+
+	_, err = w.Send("alice", bob.PublicKey, 10)
+	assert.NoError(t, err)
+
+	client := clients[alice.PublicKey]
+	if !assert.NotNil(t, client) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, []string{"PollAccounts", "GetAccount", "Pay"}, client.Calls())
+
+	alice, err = w.Account("alice")
+	assert.NoError(t, err)
+	assert.True(t, alice.Open)
+	assert.EqualValues(t, 100, alice.Balance)
+
+	// Only updates of the account itself are tracked.
+	assert.NoError(t, client.Emit(wctl.BalanceUpdate{AccountID: bob.PublicKey, Balance: 1}))
+	assert.NoError(t, client.Emit(wctl.BalanceUpdate{AccountID: alice.PublicKey, Balance: 90}))
+	assert.NoError(t, client.Emit(wctl.StakeUpdated{AccountID: alice.PublicKey, Stake: 5}))
+
+	alice, err = w.Account("alice")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 90, alice.Balance)
+	assert.EqualValues(t, 5, alice.Stake)
+
+	if assert.Len(t, updates, 2) {
+		assert.Equal(t, alice, updates[1])
+	}
+
+	history, err := w.History("alice", 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	_, err = w.Deploy("alice", wctl.ContractSpawn{Code: []byte("code")})
+	assert.NoError(t, err)
+	assert.Contains(t, client.Calls(), "SendContract")
+
+	_, err = w.Call("alice", bob.PublicKey, wctl.FunctionCall{Name: "on_money_received", GasLimit: 1})
+	assert.NoError(t, err)
+	assert.Contains(t, client.Calls(), "Call")
+
+	client.SimulateContractCallFunc = func(contract [32]byte, fn wctl.FunctionCall) (*wctl.ContractCallResult, error) {
+		return &wctl.ContractCallResult{Gas: 42}, nil
+	}
+
+	call, err := w.Simulate("alice", bob.PublicKey, wctl.FunctionCall{Name: "on_money_received"})
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, 42, call.Gas)
+	}
+
+	// Reuses the connection of the account.
+	assert.Len(t, clients, 1)
+
+	assert.NoError(t, w.Remove("alice"))
+	assert.Contains(t, client.Calls(), "Close")
+
+	_, err = w.Account("alice")
+	assert.Equal(t, ErrAccountNotFound, errors.Cause(err))
+
+	w.Close()
+
+	_, err = w.Send("bob", alice.PublicKey, 1)
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestWalletPendingUpdates(t *testing.T) {
+	ks, cleanup := newTestKeystore(t)
+	defer cleanup()
+
+	var client *clientmock.Client
+
+	w := New(ks, func(key edwards25519.PrivateKey) (wctl.LedgerClient, error) {
+		client = &clientmock.Client{}
+		client.GetAccountFunc = func(account [32]byte) (*wctl.Account, error) {
+			// An update arriving while the account is being fetched.
+			assert.NoError(t, client.Emit(wctl.BalanceUpdate{AccountID: account, Balance: 7}))
+
+			return &wctl.Account{PublicKey: account, Balance: 3, Reward: 2}, nil
+		}
+
+		return client, nil
+	})
+	defer w.Close()
+
+	_, err := w.Create("alice")
+	assert.NoError(t, err)
+
+	alice, err := w.Open("alice")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, alice.Balance)
+	assert.EqualValues(t, 2, alice.Reward)
+}
@@ -1,7 +1,9 @@
 package wctl
 
 import (
+	"context"
 	"encoding/hex"
+	"strconv"
 
 	"github.com/valyala/fastjson"
 )
@@ -13,10 +15,46 @@ func (c *Client) GetSelf() (*Account, error) {
 	return c.GetAccount(c.PublicKey)
 }
 
-// GetAccount calls the /accounts endpoint of the API.
+// GetAccount calls the /accounts endpoint of the API, unless the account is
+// cached (see Config.AccountCacheTTL).
 func (c *Client) GetAccount(account [32]byte) (*Account, error) {
+	return c.GetAccountCtx(context.Background(), account)
+}
+
+// GetAccountCtx is GetAccount, which gives up once ctx is done.
+func (c *Client) GetAccountCtx(ctx context.Context, account [32]byte) (*Account, error) {
+	if c.accounts != nil {
+		if cached, ok := c.accounts.get(account); ok {
+			return &cached, nil
+		}
+
+		c.accounts.begin(account)
+	}
+
 	path := RouteAccount + "/" + hex.EncodeToString(account[:])
 
+	var res Account
+	if err := c.RequestJSONCtx(ctx, path, ReqGet, nil, &res); err != nil {
+		if c.accounts != nil {
+			c.accounts.abort(account)
+		}
+
+		return nil, err
+	}
+
+	if c.accounts != nil {
+		c.accounts.put(account, res)
+	}
+
+	return &res, nil
+}
+
+// GetAccountAt calls the /accounts endpoint of the API for the account as of
+// the block with index round, which archival nodes keep. Accounts as of past
+// rounds are not cached.
+func (c *Client) GetAccountAt(account [32]byte, round uint64) (*Account, error) {
+	path := RouteAccount + "/" + hex.EncodeToString(account[:]) + "?round=" + strconv.FormatUint(round, 10)
+
 	var res Account
 	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
 		return nil, err
@@ -43,6 +81,47 @@ type Account struct {
 	Reward     uint64   `json:"reward"`
 	IsContract bool     `json:"is_contract"`
 	NumPages   uint64   `json:"num_mem_pages,omitempty"`
+
+	Sponsor      [32]byte `json:"sponsor,omitempty"`
+	FeeAllowance uint64   `json:"fee_allowance,omitempty"`
+
+	Recovery        *Recovery        `json:"recovery,omitempty"`
+	PendingRecovery *PendingRecovery `json:"pending_recovery,omitempty"`
+
+	Multisig *Multisig `json:"multisig,omitempty"`
+}
+
+// Recovery is the set of guardians who may recover an account.
+type Recovery struct {
+	Guardians [][32]byte `json:"guardians"`
+	Threshold uint8      `json:"threshold"`
+	Delay     uint64     `json:"delay"`
+}
+
+// PendingRecovery is a recovery of an account awaiting approvals or its delay.
+type PendingRecovery struct {
+	Recipient [32]byte   `json:"recipient"`
+	Block     uint64     `json:"block"`
+	Approvals [][32]byte `json:"approvals"`
+}
+
+// Multisig is the set of participants of a multisig account, threshold of
+// whom must approve transfers proposed from it.
+type Multisig struct {
+	Participants [][32]byte         `json:"participants"`
+	Threshold    uint8              `json:"threshold"`
+	Proposals    []MultisigProposal `json:"proposals"`
+}
+
+// MultisigProposal is a transfer proposed from a multisig account which is
+// yet to be executed or cancelled.
+type MultisigProposal struct {
+	ID        [32]byte   `json:"id"`
+	Proposer  [32]byte   `json:"proposer"`
+	Recipient [32]byte   `json:"recipient"`
+	Amount    uint64     `json:"amount"`
+	Block     uint64     `json:"block"`
+	Approvals [][32]byte `json:"approvals"`
 }
 
 func (a *Account) UnmarshalJSON(b []byte) error {
@@ -64,5 +143,85 @@ func (a *Account) UnmarshalJSON(b []byte) error {
 	a.IsContract = v.GetBool("is_contract")
 	a.NumPages = v.GetUint64("num_mem_pages")
 
+	if v.Exists("sponsor") {
+		if err := jsonHex(v, a.Sponsor[:], "sponsor"); err != nil {
+			return err
+		}
+
+		a.FeeAllowance = v.GetUint64("fee_allowance")
+	}
+
+	if r := v.Get("recovery"); r != nil {
+		guardians, err := jsonAccountIDs(r, "guardians")
+		if err != nil {
+			return err
+		}
+
+		a.Recovery = &Recovery{
+			Guardians: guardians,
+			Threshold: uint8(r.GetUint("threshold")),
+			Delay:     r.GetUint64("delay"),
+		}
+	}
+
+	if r := v.Get("pending_recovery"); r != nil {
+		a.PendingRecovery = &PendingRecovery{Block: r.GetUint64("block")}
+
+		if err := jsonHex(r, a.PendingRecovery.Recipient[:], "recipient"); err != nil {
+			return err
+		}
+
+		approvals, err := jsonAccountIDs(r, "approvals")
+		if err != nil {
+			return err
+		}
+
+		a.PendingRecovery.Approvals = approvals
+	}
+
+	if m := v.Get("multisig"); m != nil {
+		participants, err := jsonAccountIDs(m, "participants")
+		if err != nil {
+			return err
+		}
+
+		a.Multisig = &Multisig{Participants: participants, Threshold: uint8(m.GetUint("threshold"))}
+
+		for _, p := range m.GetArray("proposals") {
+			proposal := MultisigProposal{Amount: p.GetUint64("amount"), Block: p.GetUint64("block")}
+
+			if err := jsonHex(p, proposal.ID[:], "id"); err != nil {
+				return err
+			}
+
+			if err := jsonHex(p, proposal.Proposer[:], "proposer"); err != nil {
+				return err
+			}
+
+			if err := jsonHex(p, proposal.Recipient[:], "recipient"); err != nil {
+				return err
+			}
+
+			if proposal.Approvals, err = jsonAccountIDs(p, "approvals"); err != nil {
+				return err
+			}
+
+			a.Multisig.Proposals = append(a.Multisig.Proposals, proposal)
+		}
+	}
+
 	return nil
 }
+
+func jsonAccountIDs(v *fastjson.Value, key string) ([][32]byte, error) {
+	values := v.GetArray(key)
+	ids := make([][32]byte, len(values))
+
+	for i, value := range values {
+		if err := jsonHex(value, ids[i][:]); err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
+}
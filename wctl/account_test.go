@@ -0,0 +1,70 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountUnmarshalJSON(t *testing.T) {
+	id := func(b byte) string {
+		return strings.Repeat(fmt.Sprintf("%02x", b), 32)
+	}
+
+	body := fmt.Sprintf(`{
+		"public_key": "%s",
+		"balance": 10,
+		"is_contract": false,
+		"sponsor": "%s",
+		"fee_allowance": 5,
+		"recovery": {"guardians": ["%s", "%s"], "threshold": 2, "delay": 100},
+		"pending_recovery": {"recipient": "%s", "block": 7, "approvals": ["%s"]},
+		"multisig": {"participants": ["%s", "%s"], "threshold": 2, "proposals": [
+			{"id": "%s", "proposer": "%s", "recipient": "%s", "amount": 60, "block": 9, "approvals": ["%s"]}
+		]}
+	}`, id(1), id(2), id(3), id(4), id(5), id(3), id(3), id(4), id(6), id(3), id(5), id(3))
+
+	var a Account
+	require.NoError(t, a.UnmarshalJSON([]byte(body)))
+
+	assert.EqualValues(t, 10, a.Balance)
+	assert.EqualValues(t, 2, a.Sponsor[0])
+	assert.EqualValues(t, 5, a.FeeAllowance)
+
+	require.NotNil(t, a.Recovery)
+	assert.Len(t, a.Recovery.Guardians, 2)
+	assert.EqualValues(t, 4, a.Recovery.Guardians[1][0])
+	assert.EqualValues(t, 2, a.Recovery.Threshold)
+	assert.EqualValues(t, 100, a.Recovery.Delay)
+
+	require.NotNil(t, a.PendingRecovery)
+	assert.EqualValues(t, 5, a.PendingRecovery.Recipient[31])
+	assert.EqualValues(t, 7, a.PendingRecovery.Block)
+	assert.Len(t, a.PendingRecovery.Approvals, 1)
+
+	require.NotNil(t, a.Multisig)
+	assert.Len(t, a.Multisig.Participants, 2)
+	assert.EqualValues(t, 2, a.Multisig.Threshold)
+	require.Len(t, a.Multisig.Proposals, 1)
+	assert.EqualValues(t, 6, a.Multisig.Proposals[0].ID[0])
+	assert.EqualValues(t, 5, a.Multisig.Proposals[0].Recipient[0])
+	assert.EqualValues(t, 60, a.Multisig.Proposals[0].Amount)
+	assert.EqualValues(t, 9, a.Multisig.Proposals[0].Block)
+	assert.Len(t, a.Multisig.Proposals[0].Approvals, 1)
+
+	// Accounts without sponsors or guardians leave them unset.
+	var plain Account
+	require.NoError(t, plain.UnmarshalJSON([]byte(fmt.Sprintf(`{"public_key": "%s"}`, id(1)))))
+	assert.Nil(t, plain.Recovery)
+	assert.Nil(t, plain.PendingRecovery)
+	assert.Nil(t, plain.Multisig)
+
+	// Guardians must be valid account IDs.
+	body = fmt.Sprintf(`{"public_key": "%s", "recovery": {"guardians": ["00"]}}`, id(1))
+	assert.Error(t, plain.UnmarshalJSON([]byte(body)))
+}
@@ -0,0 +1,53 @@
+package wctl
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+)
+
+// adminRequestJSON is RequestJSON, which requests the admin API of the node
+// should the client have been configured with one.
+func (c *Client) adminRequestJSON(path, method string, body MarshalableJSON, out UnmarshalableJSON) error {
+	if c.adminURL == "" {
+		return c.RequestJSON(path, method, body, out)
+	}
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+
+	req.URI().Update(c.adminURL + path)
+	req.Header.SetMethod(method)
+	req.Header.SetContentType("application/json")
+
+	if c.AdminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AdminToken)
+	}
+
+	if body != nil {
+		raw, err := body.MarshalJSON()
+		if err != nil {
+			return err
+		}
+
+		req.SetBody(raw)
+	}
+
+	res := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(res)
+
+	if err := c.do(context.Background(), req, res); err != nil {
+		return err
+	}
+
+	if res.StatusCode() != http.StatusOK {
+		return parseAPIError(res.StatusCode(), req.Body(), res.Body())
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return out.UnmarshalJSON(res.Body())
+}
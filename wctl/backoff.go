@@ -0,0 +1,136 @@
+package wctl
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff is a policy for retrying failed operations. It is configured once
+// through Config.Backoff, and shared by the HTTP requests and websockets of
+// a Client, as well as by anything else built atop the client.
+//
+// Delays between attempts grow from Initial by a factor of Multiplier, up to
+// Max, each being randomized by up to a fraction Jitter either way. Retrying
+// stops once MaxAttempts attempts were made, or should the next attempt start
+// more than MaxElapsed after the first. A policy with neither MaxAttempts nor
+// MaxElapsed set retries forever.
+//
+// RetryStatuses are the HTTP statuses BackoffInterceptor retries requests
+// upon, besides failures to deliver them, being 502, 503 and 504 if empty.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     float64
+
+	MaxAttempts int
+	MaxElapsed  time.Duration
+
+	RetryStatuses []int
+}
+
+// DefaultBackoff retries for up to a minute, starting at 100 milliseconds
+// between attempts and doubling up to 10 seconds.
+var DefaultBackoff = Backoff{ // nolint:gochecknoglobals
+	Initial:    100 * time.Millisecond,
+	Max:        10 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+	MaxElapsed: time.Minute,
+}
+
+// Delay returns how long to wait before the retry-th retry, counting from 1.
+// A Multiplier less than 1 keeps delays constant.
+func (b Backoff) Delay(retry int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(b.Initial) * math.Pow(multiplier, float64(retry-1))
+
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	if b.Jitter > 0 {
+		delay *= 1 + b.Jitter*(2*rand.Float64()-1) // nolint:gosec
+	}
+
+	return time.Duration(delay)
+}
+
+// retryableStatus reports whether requests yielding code are retried.
+func (b Backoff) retryableStatus(code int) bool {
+	if len(b.RetryStatuses) == 0 {
+		return retryableStatus(code)
+	}
+
+	for _, status := range b.RetryStatuses {
+		if status == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Retry calls fn until it succeeds, or until the policy gives up, returning
+// the last error fn returned. Errors wrapped by Permanent are returned
+// straight away without being retried.
+func (b Backoff) Retry(fn func() error) error {
+	return b.retry(nil, fn)
+}
+
+// retry is Retry, which additionally gives up once stop is closed.
+func (b Backoff) retry(stop <-chan struct{}, fn func() error) error {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if p, ok := err.(*permanentError); ok {
+			return p.err
+		}
+
+		if b.MaxAttempts > 0 && attempt >= b.MaxAttempts {
+			return err
+		}
+
+		delay := b.Delay(attempt)
+
+		if b.MaxElapsed > 0 && time.Since(start)+delay > b.MaxElapsed {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Permanent wraps err for Backoff.Retry to give up on straight away.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
@@ -0,0 +1,85 @@
+// +build unit
+
+package wctl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+
+	assert.Equal(t, 100*time.Millisecond, b.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, b.Delay(2))
+	assert.Equal(t, 800*time.Millisecond, b.Delay(4))
+	assert.Equal(t, time.Second, b.Delay(5))
+	assert.Equal(t, time.Second, b.Delay(50))
+
+	constant := Backoff{Initial: 50 * time.Millisecond}
+	assert.Equal(t, 50*time.Millisecond, constant.Delay(1))
+	assert.Equal(t, 50*time.Millisecond, constant.Delay(10))
+
+	jittered := Backoff{Initial: 100 * time.Millisecond, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		delay := jittered.Delay(1)
+		assert.True(t, delay >= 50*time.Millisecond && delay <= 150*time.Millisecond, delay)
+	}
+}
+
+func TestBackoffRetry(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	// Succeeds after a few failed attempts.
+	attempts := 0
+	assert.NoError(t, Backoff{Initial: time.Millisecond, MaxAttempts: 5}.Retry(func() error {
+		attempts++
+		if attempts < 3 {
+			return errFailed
+		}
+		return nil
+	}))
+	assert.Equal(t, 3, attempts)
+
+	// Gives up after MaxAttempts.
+	attempts = 0
+	assert.Equal(t, errFailed, Backoff{Initial: time.Millisecond, MaxAttempts: 4}.Retry(func() error {
+		attempts++
+		return errFailed
+	}))
+	assert.Equal(t, 4, attempts)
+
+	// Gives up on permanent errors straight away.
+	attempts = 0
+	assert.Equal(t, errFailed, Backoff{Initial: time.Millisecond, MaxAttempts: 4}.Retry(func() error {
+		attempts++
+		return Permanent(errFailed)
+	}))
+	assert.Equal(t, 1, attempts)
+
+	// Gives up before waiting past MaxElapsed: the second attempt is made after
+	// 20 milliseconds, with a third being due past 30 milliseconds.
+	attempts = 0
+	assert.Equal(t, errFailed, Backoff{Initial: 20 * time.Millisecond, MaxElapsed: 30 * time.Millisecond}.Retry(
+		func() error {
+			attempts++
+			return errFailed
+		},
+	))
+	assert.Equal(t, 2, attempts)
+}
+
+func TestBackoffRetryStop(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+
+	attempts := 0
+	assert.Error(t, Backoff{Initial: time.Hour}.retry(stop, func() error {
+		attempts++
+		return errors.New("failed")
+	}))
+	assert.Equal(t, 1, attempts)
+}
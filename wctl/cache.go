@@ -0,0 +1,223 @@
+package wctl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/valyala/fastjson"
+)
+
+// accountCache keeps recently queried accounts warm, and keeps them up to
+// date with the account updates streamed from the node's websocket.
+//
+// Accounts are only served from the cache while the websocket is connected,
+// as otherwise updates to them might have been missed.
+type accountCache struct {
+	sync.Mutex
+
+	ttl     time.Duration
+	live    bool
+	entries map[[32]byte]accountCacheEntry
+
+	// Accounts being queried, recording whether or not they were updated
+	// while the query was in flight.
+	pending map[[32]byte]*pendingAccount
+}
+
+type pendingAccount struct {
+	queries int
+	dirty   bool
+}
+
+type accountCacheEntry struct {
+	account Account
+	expires time.Time
+}
+
+func newAccountCache(ttl time.Duration) *accountCache {
+	return &accountCache{
+		ttl:     ttl,
+		entries: make(map[[32]byte]accountCacheEntry),
+		pending: make(map[[32]byte]*pendingAccount),
+	}
+}
+
+func (a *accountCache) get(id [32]byte) (Account, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	if !a.live {
+		return Account{}, false
+	}
+
+	entry, exists := a.entries[id]
+	if !exists {
+		return Account{}, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(a.entries, id)
+		return Account{}, false
+	}
+
+	return entry.account, true
+}
+
+// begin marks that the account of id is about to be queried from the node.
+// Every call to begin must be followed by a call to put or abort.
+func (a *accountCache) begin(id [32]byte) {
+	a.Lock()
+	defer a.Unlock()
+
+	p, exists := a.pending[id]
+	if !exists {
+		p = &pendingAccount{}
+		a.pending[id] = p
+	}
+
+	p.queries++
+}
+
+func (a *accountCache) finish(id [32]byte) (dirty bool) {
+	p, exists := a.pending[id]
+	if !exists {
+		return true
+	}
+
+	if p.queries--; p.queries == 0 {
+		delete(a.pending, id)
+	}
+
+	return p.dirty
+}
+
+// abort marks that the query of the account of id failed.
+func (a *accountCache) abort(id [32]byte) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.finish(id)
+}
+
+// put caches a queried account, unless it was updated while being queried,
+// in which case the queried account might be stale.
+func (a *accountCache) put(id [32]byte, account Account) {
+	a.Lock()
+	defer a.Unlock()
+
+	if dirty := a.finish(id); dirty || !a.live {
+		return
+	}
+
+	a.entries[id] = accountCacheEntry{
+		account: account,
+		expires: time.Now().Add(a.ttl),
+	}
+}
+
+// update applies fn to the cached account of id, should it be cached.
+func (a *accountCache) update(id [32]byte, fn func(account *Account)) {
+	a.Lock()
+	defer a.Unlock()
+
+	if p, exists := a.pending[id]; exists {
+		p.dirty = true
+	}
+
+	entry, exists := a.entries[id]
+	if !exists {
+		return
+	}
+
+	fn(&entry.account)
+	a.entries[id] = entry
+}
+
+// reset evicts all cached accounts, and sets whether or not accounts may be
+// served from the cache.
+func (a *accountCache) reset(live bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.live = live
+	a.entries = make(map[[32]byte]accountCacheEntry)
+
+	// Queries in flight might have missed updates.
+	for _, p := range a.pending {
+		p.dirty = true
+	}
+}
+
+func (a *accountCache) handle(v *fastjson.Value) error {
+	if err := checkMod(v, log.ModuleAccounts); err != nil {
+		return err
+	}
+
+	switch ev := jsonString(v, "event"); ev {
+	case events.EventBalanceUpdated:
+		var u events.BalanceUpdate
+		if err := u.UnmarshalValue(v); err != nil {
+			return err
+		}
+
+		a.update(u.AccountID, func(account *Account) { account.Balance = u.Balance })
+	case events.EventGasBalanceUpdated:
+		var u events.GasBalanceUpdate
+		if err := u.UnmarshalValue(v); err != nil {
+			return err
+		}
+
+		a.update(u.AccountID, func(account *Account) { account.GasBalance = u.GasBalance })
+	case events.EventNumPagesUpdated:
+		var u events.NumPagesUpdated
+		if err := u.UnmarshalValue(v); err != nil {
+			return err
+		}
+
+		a.update(u.AccountID, func(account *Account) { account.NumPages = u.NumPages })
+	case events.EventStakeUpdated:
+		var u events.StakeUpdated
+		if err := u.UnmarshalValue(v); err != nil {
+			return err
+		}
+
+		a.update(u.AccountID, func(account *Account) { account.Stake = u.Stake })
+	case events.EventRewardUpdated:
+		var u events.RewardUpdated
+		if err := u.UnmarshalValue(v); err != nil {
+			return err
+		}
+
+		a.update(u.AccountID, func(account *Account) { account.Reward = u.Reward })
+	default:
+		return errInvalidEvent(v, ev)
+	}
+
+	return nil
+}
+
+// pollAccountCache keeps the account cache up to date with the node.
+func (c *Client) pollAccountCache() (func(), error) {
+	cancel, err := c.pollWSWithClose(context.Background(), RouteWSAccounts, func(v *fastjson.Value) {
+		if err := c.accounts.handle(v); err != nil && c.OnError != nil {
+			c.OnError(err)
+		}
+	}, func() {
+		c.accounts.reset(false)
+	}, func() {
+		c.accounts.reset(true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.accounts.reset(true)
+
+	return func() {
+		cancel()
+		c.accounts.reset(false)
+	}, nil
+}
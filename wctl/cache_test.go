@@ -0,0 +1,131 @@
+// +build unit
+
+package wctl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fastjson"
+)
+
+func TestAccountCache(t *testing.T) {
+	cache := newAccountCache(time.Minute)
+	id := [32]byte{1}
+
+	event := func(event, key string, value uint64) *fastjson.Value {
+		return fastjson.MustParse(fmt.Sprintf(
+			`{"mod":"accounts","event":"%s","account_id":"%s","%s":%d,"time":"%s"}`,
+			event, hex.EncodeToString(id[:]), key, value, time.Now().Format(time.RFC3339),
+		))
+	}
+
+	// Accounts are not cached until the websocket is live.
+	cache.begin(id)
+	cache.put(id, Account{PublicKey: id, Balance: 1})
+
+	_, ok := cache.get(id)
+	assert.False(t, ok)
+
+	cache.reset(true)
+
+	cache.begin(id)
+	cache.put(id, Account{PublicKey: id, Balance: 1})
+
+	account, ok := cache.get(id)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, account.Balance)
+
+	assert.NoError(t, cache.handle(event("balance_updated", "balance", 2)))
+	assert.NoError(t, cache.handle(event("gas_balance_updated", "gas_balance", 3)))
+	assert.NoError(t, cache.handle(event("stake_updated", "stake", 4)))
+	assert.NoError(t, cache.handle(event("reward_updated", "reward", 5)))
+	assert.NoError(t, cache.handle(event("num_pages_updated", "num_pages_updated", 6)))
+	assert.Error(t, cache.handle(event("unknown", "balance", 0)))
+
+	account, ok = cache.get(id)
+	assert.True(t, ok)
+	assert.Equal(t, Account{PublicKey: id, Balance: 2, GasBalance: 3, Stake: 4, Reward: 5, NumPages: 6}, account)
+
+	// An account updated while being queried is not cached, as the queried
+	// account may be stale.
+	cache.reset(true)
+	cache.begin(id)
+	assert.NoError(t, cache.handle(event("balance_updated", "balance", 7)))
+	cache.put(id, Account{PublicKey: id, Balance: 2})
+
+	_, ok = cache.get(id)
+	assert.False(t, ok)
+	assert.Len(t, cache.pending, 0)
+
+	// Losing the websocket evicts all accounts.
+	cache.begin(id)
+	cache.put(id, Account{PublicKey: id})
+	cache.reset(false)
+
+	_, ok = cache.get(id)
+	assert.False(t, ok)
+
+	// Accounts expire after their TTL.
+	cache = newAccountCache(time.Nanosecond)
+	cache.reset(true)
+	cache.begin(id)
+	cache.put(id, Account{PublicKey: id})
+	time.Sleep(time.Millisecond)
+
+	_, ok = cache.get(id)
+	assert.False(t, ok)
+}
+
+func TestClientAccountCache(t *testing.T) {
+	cfg, stop := fakeNode(t, 50*time.Millisecond)
+	defer stop()
+
+	cfg.AccountCacheTTL = time.Minute
+
+	c, err := NewClient(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	var zero [32]byte
+
+	// The fake node streams a balance update for the zero account every
+	// 50 milliseconds. Updates that race with a query prevent the queried account
+	// from being cached, so query until it is.
+	var cached Account
+
+	assert.NoError(t, waitFor(func() bool {
+		_, err := c.GetAccount(zero)
+		assert.NoError(t, err)
+
+		var ok bool
+		cached, ok = c.accounts.get(zero)
+
+		return ok
+	}))
+
+	// The cached account should then follow the streamed updates.
+	assert.NoError(t, waitFor(func() bool {
+		account, err := c.GetAccount(zero)
+		return err == nil && account.Balance > cached.Balance
+	}))
+}
+
+func waitFor(fn func() bool) error {
+	timeout := time.After(5 * time.Second)
+
+	for !fn() {
+		select {
+		case <-timeout:
+			return fmt.Errorf("timed out")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	return nil
+}
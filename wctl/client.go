@@ -0,0 +1,67 @@
+package wctl
+
+import (
+	"context"
+
+	"github.com/perlin-network/wavelet"
+)
+
+var _ LedgerClient = (*Client)(nil)
+
+// LedgerClient is the API surface of a Client, such that applications may
+// swap it out for a mock (see package clientmock) in tests.
+type LedgerClient interface {
+	// Events returns the callbacks invoked by the Poll* functions.
+	Events() *EventHandlers
+
+	Close()
+
+	LedgerStatus() (*LedgerStatusResponse, error)
+	GasSchedule() (*GasSchedule, error)
+
+	GetSelf() (*Account, error)
+	GetAccount(account [32]byte) (*Account, error)
+	RecipientIsContract(recipient [32]byte) bool
+	Find(address [32]byte) (*Account, *Transaction, error)
+
+	ListTransactions(senderID string, creatorID string, offset uint64, limit uint64) ([]Transaction, error)
+	GetTransaction(txID [32]byte) (*Transaction, error)
+	WaitForTransaction(ctx context.Context, txID [32]byte) (*Transaction, error)
+
+	EstimateFee(tag byte, payload []byte) (*FeeEstimate, error)
+
+	SendTransaction(tag byte, payload []byte) (*TxResponse, error)
+	CraftTransaction(tag byte, payload []byte) *TxRequest
+	BroadcastTransaction(req *TxRequest) (*TxResponse, error)
+	SendBatch(batch wavelet.Batch) (*TxResponse, error)
+	Pay(recipient [32]byte, amount uint64) (*TxResponse, error)
+	Call(recipient [32]byte, fn FunctionCall) (*TxResponse, error)
+	DepositGas(recipient [32]byte, gasAmount uint64) (*TxResponse, error)
+	Spawn(code []byte, gasLimit uint64) (*TxResponse, error)
+	SendContract(spawn ContractSpawn) (*TxResponse, error)
+	PlaceStake(amount uint64) (*TxResponse, error)
+	WithdrawStake(amount uint64) (*TxResponse, error)
+	WithdrawReward(amount uint64) (*TxResponse, error)
+
+	CreateMultisig(participants [][32]byte, threshold uint8) (*TxResponse, error)
+	ProposeMultisig(account, recipient [32]byte, amount uint64) (*TxResponse, error)
+	ApproveMultisig(proposal [32]byte) (*TxResponse, error)
+	ExecuteMultisig(proposal [32]byte) (*TxResponse, error)
+	CancelMultisig(proposal [32]byte) (*TxResponse, error)
+
+	GetContractCode(contractID string) (string, error)
+	GetContractPages(contractID string, index *uint64) (string, error)
+	SimulateContractCall(contract [32]byte, fn FunctionCall) (*ContractCallResult, error)
+
+	Connect(address string) (*MsgResponse, error)
+	Disconnect(address string) (*MsgResponse, error)
+	Restart(hard bool) (*MsgResponse, error)
+
+	PollAccounts() (func(), error)
+	PollAccountsFiltered(id [32]byte) (func(), error)
+	PollContracts() (func(), error)
+	PollMetrics() (func(), error)
+	PollNetwork() (func(), error)
+	PollTransactions() (func(), error)
+	PollTransactionsFiltered(filter TxEventFilter) (func(), error)
+}
@@ -0,0 +1,584 @@
+// Package clientmock provides a programmable mock of wctl.LedgerClient, such
+// that applications embedding wctl may be unit-tested without a live node.
+//
+// Responses are programmed by setting the *Func fields of a Client, and
+// websocket events are injected with Emit.
+package clientmock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+)
+
+var (
+	// ErrNotMocked is returned by methods whose response was not programmed.
+	ErrNotMocked = errors.New("clientmock: method is not mocked")
+
+	// ErrNotPolling is returned by Emit when the event's module is not being
+	// polled.
+	ErrNotPolling = errors.New("clientmock: event module is not being polled")
+)
+
+var _ wctl.LedgerClient = (*Client)(nil)
+
+// modError is the pseudo-module of errors passed to Emit, which are always
+// delivered.
+const modError = "error"
+
+// Client is a mock wctl.LedgerClient. The zero value is ready for use, and
+// returns ErrNotMocked for all requests.
+type Client struct {
+	wctl.EventHandlers
+
+	LedgerStatusFunc func() (*wctl.LedgerStatusResponse, error)
+	GasScheduleFunc  func() (*wctl.GasSchedule, error)
+
+	GetAccountFunc func(account [32]byte) (*wctl.Account, error)
+
+	ListTransactionsFunc func(senderID string, creatorID string, offset uint64, limit uint64) ([]wctl.Transaction, error)
+	GetTransactionFunc   func(txID [32]byte) (*wctl.Transaction, error)
+
+	WaitForTransactionFunc func(ctx context.Context, txID [32]byte) (*wctl.Transaction, error)
+
+	// SendTransactionFunc is invoked by all functions that send a transaction,
+	// with the tag and payload they would have sent.
+	SendTransactionFunc func(tag byte, payload []byte) (*wctl.TxResponse, error)
+
+	EstimateFeeFunc func(tag byte, payload []byte) (*wctl.FeeEstimate, error)
+
+	SimulateContractCallFunc func(contract [32]byte, fn wctl.FunctionCall) (*wctl.ContractCallResult, error)
+
+	GetContractCodeFunc  func(contractID string) (string, error)
+	GetContractPagesFunc func(contractID string, index *uint64) (string, error)
+
+	ConnectFunc    func(address string) (*wctl.MsgResponse, error)
+	DisconnectFunc func(address string) (*wctl.MsgResponse, error)
+	RestartFunc    func(hard bool) (*wctl.MsgResponse, error)
+
+	// PublicKey is the account returned by GetSelf.
+	PublicKey [32]byte
+
+	mu      sync.Mutex
+	calls   []string
+	polling map[string]int
+	closed  bool
+}
+
+// Calls returns the names of all methods invoked on the client, in order.
+func (c *Client) Calls() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.calls...)
+}
+
+func (c *Client) record(method string) {
+	c.mu.Lock()
+	c.calls = append(c.calls, method)
+	c.mu.Unlock()
+}
+
+func (c *Client) Events() *wctl.EventHandlers {
+	return &c.EventHandlers
+}
+
+func (c *Client) Close() {
+	c.record("Close")
+
+	c.mu.Lock()
+	c.closed = true
+	c.polling = nil
+	c.mu.Unlock()
+}
+
+func (c *Client) LedgerStatus() (*wctl.LedgerStatusResponse, error) {
+	c.record("LedgerStatus")
+
+	if c.LedgerStatusFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.LedgerStatusFunc()
+}
+
+func (c *Client) GasSchedule() (*wctl.GasSchedule, error) {
+	c.record("GasSchedule")
+
+	if c.GasScheduleFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.GasScheduleFunc()
+}
+
+func (c *Client) GetSelf() (*wctl.Account, error) {
+	return c.GetAccount(c.PublicKey)
+}
+
+func (c *Client) GetAccount(account [32]byte) (*wctl.Account, error) {
+	c.record("GetAccount")
+
+	if c.GetAccountFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.GetAccountFunc(account)
+}
+
+func (c *Client) RecipientIsContract(recipient [32]byte) bool {
+	a, err := c.GetAccount(recipient)
+	if err != nil {
+		return false
+	}
+
+	return a.IsContract
+}
+
+func (c *Client) Find(address [32]byte) (*wctl.Account, *wctl.Transaction, error) {
+	a, err := c.GetAccount(address)
+	if err == nil {
+		if a.Balance > 0 || a.Stake > 0 || a.IsContract || a.NumPages > 0 {
+			return a, nil, nil
+		}
+	}
+
+	t, err := c.GetTransaction(address)
+	if err == nil {
+		return nil, t, nil
+	}
+
+	return nil, nil, errors.New("address not found")
+}
+
+func (c *Client) ListTransactions(
+	senderID string, creatorID string, offset uint64, limit uint64,
+) ([]wctl.Transaction, error) {
+	c.record("ListTransactions")
+
+	if c.ListTransactionsFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.ListTransactionsFunc(senderID, creatorID, offset, limit)
+}
+
+func (c *Client) GetTransaction(txID [32]byte) (*wctl.Transaction, error) {
+	c.record("GetTransaction")
+
+	if c.GetTransactionFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.GetTransactionFunc(txID)
+}
+
+func (c *Client) WaitForTransaction(ctx context.Context, txID [32]byte) (*wctl.Transaction, error) {
+	c.record("WaitForTransaction")
+
+	if c.WaitForTransactionFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.WaitForTransactionFunc(ctx, txID)
+}
+
+func (c *Client) SendTransaction(tag byte, payload []byte) (*wctl.TxResponse, error) {
+	c.record("SendTransaction")
+
+	return c.send(tag, payload)
+}
+
+// CraftTransaction returns the transaction from PublicKey with the given tag
+// and payload, left unsigned.
+func (c *Client) CraftTransaction(tag byte, payload []byte) *wctl.TxRequest {
+	c.record("CraftTransaction")
+
+	return &wctl.TxRequest{Sender: c.PublicKey, Tag: tag, Payload: payload}
+}
+
+func (c *Client) BroadcastTransaction(req *wctl.TxRequest) (*wctl.TxResponse, error) {
+	c.record("BroadcastTransaction")
+
+	return c.send(req.Tag, req.Payload)
+}
+
+func (c *Client) EstimateFee(tag byte, payload []byte) (*wctl.FeeEstimate, error) {
+	c.record("EstimateFee")
+
+	if c.EstimateFeeFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.EstimateFeeFunc(tag, payload)
+}
+
+func (c *Client) SimulateContractCall(contract [32]byte, fn wctl.FunctionCall) (*wctl.ContractCallResult, error) {
+	c.record("SimulateContractCall")
+
+	if c.SimulateContractCallFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.SimulateContractCallFunc(contract, fn)
+}
+
+func (c *Client) SendBatch(batch wavelet.Batch) (*wctl.TxResponse, error) {
+	c.record("SendBatch")
+
+	return c.sendPayload(batch)
+}
+
+func (c *Client) Pay(recipient [32]byte, amount uint64) (*wctl.TxResponse, error) {
+	c.record("Pay")
+
+	return c.sendPayload(wavelet.Transfer{Recipient: recipient, Amount: amount})
+}
+
+func (c *Client) Call(recipient [32]byte, fn wctl.FunctionCall) (*wctl.TxResponse, error) {
+	c.record("Call")
+
+	payload, err := wctl.NewTransfer(recipient).
+		Amount(fn.Amount).
+		GasLimit(fn.GasLimit).
+		Invoke(fn.Name, fn.Params...).
+		Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.send(byte(sys.TagTransfer), payload)
+}
+
+func (c *Client) DepositGas(recipient [32]byte, gasAmount uint64) (*wctl.TxResponse, error) {
+	c.record("DepositGas")
+
+	return c.sendPayload(wavelet.Transfer{Recipient: recipient, GasDeposit: gasAmount})
+}
+
+func (c *Client) Spawn(code []byte, gasLimit uint64) (*wctl.TxResponse, error) {
+	c.record("Spawn")
+
+	return c.sendPayload(wavelet.Contract{GasLimit: gasLimit, Code: code})
+}
+
+func (c *Client) SendContract(spawn wctl.ContractSpawn) (*wctl.TxResponse, error) {
+	c.record("SendContract")
+
+	ct := wavelet.Contract{GasLimit: spawn.GasLimit, GasDeposit: spawn.GasDeposit, Code: spawn.Code}
+
+	for _, p := range spawn.Params {
+		ct.Params = append(ct.Params, p...)
+	}
+
+	return c.sendPayload(ct)
+}
+
+func (c *Client) PlaceStake(amount uint64) (*wctl.TxResponse, error) {
+	c.record("PlaceStake")
+
+	return c.sendPayload(wavelet.Stake{Opcode: sys.PlaceStake, Amount: amount})
+}
+
+func (c *Client) WithdrawStake(amount uint64) (*wctl.TxResponse, error) {
+	c.record("WithdrawStake")
+
+	return c.sendPayload(wavelet.Stake{Opcode: sys.WithdrawStake, Amount: amount})
+}
+
+func (c *Client) WithdrawReward(amount uint64) (*wctl.TxResponse, error) {
+	c.record("WithdrawReward")
+
+	return c.sendPayload(wavelet.Stake{Opcode: sys.WithdrawReward, Amount: amount})
+}
+
+func (c *Client) CreateMultisig(participants [][32]byte, threshold uint8) (*wctl.TxResponse, error) {
+	c.record("CreateMultisig")
+
+	payload := wavelet.Multisig{Opcode: sys.CreateMultisig, Threshold: threshold}
+
+	for _, participant := range participants {
+		payload.Participants = append(payload.Participants, participant)
+	}
+
+	return c.sendPayload(payload)
+}
+
+func (c *Client) ProposeMultisig(account, recipient [32]byte, amount uint64) (*wctl.TxResponse, error) {
+	c.record("ProposeMultisig")
+
+	return c.sendPayload(wavelet.Multisig{
+		Opcode: sys.ProposeMultisig, Account: account, Recipient: recipient, Amount: amount,
+	})
+}
+
+func (c *Client) ApproveMultisig(proposal [32]byte) (*wctl.TxResponse, error) {
+	c.record("ApproveMultisig")
+
+	return c.sendPayload(wavelet.Multisig{Opcode: sys.ApproveMultisig, Proposal: proposal})
+}
+
+func (c *Client) ExecuteMultisig(proposal [32]byte) (*wctl.TxResponse, error) {
+	c.record("ExecuteMultisig")
+
+	return c.sendPayload(wavelet.Multisig{Opcode: sys.ExecuteMultisig, Proposal: proposal})
+}
+
+func (c *Client) CancelMultisig(proposal [32]byte) (*wctl.TxResponse, error) {
+	c.record("CancelMultisig")
+
+	return c.sendPayload(wavelet.Multisig{Opcode: sys.CancelMultisig, Proposal: proposal})
+}
+
+func (c *Client) sendPayload(p wavelet.Payload) (*wctl.TxResponse, error) {
+	payload, err := p.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.send(byte(p.Tag()), payload)
+}
+
+func (c *Client) send(tag byte, payload []byte) (*wctl.TxResponse, error) {
+	if c.SendTransactionFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.SendTransactionFunc(tag, payload)
+}
+
+func (c *Client) GetContractCode(contractID string) (string, error) {
+	c.record("GetContractCode")
+
+	if c.GetContractCodeFunc == nil {
+		return "", ErrNotMocked
+	}
+
+	return c.GetContractCodeFunc(contractID)
+}
+
+func (c *Client) GetContractPages(contractID string, index *uint64) (string, error) {
+	c.record("GetContractPages")
+
+	if c.GetContractPagesFunc == nil {
+		return "", ErrNotMocked
+	}
+
+	return c.GetContractPagesFunc(contractID, index)
+}
+
+func (c *Client) Connect(address string) (*wctl.MsgResponse, error) {
+	c.record("Connect")
+
+	if c.ConnectFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.ConnectFunc(address)
+}
+
+func (c *Client) Disconnect(address string) (*wctl.MsgResponse, error) {
+	c.record("Disconnect")
+
+	if c.DisconnectFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.DisconnectFunc(address)
+}
+
+func (c *Client) Restart(hard bool) (*wctl.MsgResponse, error) {
+	c.record("Restart")
+
+	if c.RestartFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return c.RestartFunc(hard)
+}
+
+func (c *Client) PollAccounts() (func(), error) {
+	c.record("PollAccounts")
+	return c.poll(log.ModuleAccounts), nil
+}
+
+func (c *Client) PollAccountsFiltered(id [32]byte) (func(), error) {
+	c.record("PollAccountsFiltered")
+	return c.poll(log.ModuleAccounts), nil
+}
+
+func (c *Client) PollContracts() (func(), error) {
+	c.record("PollContracts")
+	return c.poll(log.ModuleContract), nil
+}
+
+func (c *Client) PollMetrics() (func(), error) {
+	c.record("PollMetrics")
+	return c.poll(log.ModuleMetrics), nil
+}
+
+func (c *Client) PollNetwork() (func(), error) {
+	c.record("PollNetwork")
+	return c.poll(log.ModuleNetwork), nil
+}
+
+func (c *Client) PollTransactions() (func(), error) {
+	c.record("PollTransactions")
+	return c.poll(log.ModuleTX), nil
+}
+
+func (c *Client) PollTransactionsFiltered(filter wctl.TxEventFilter) (func(), error) {
+	c.record("PollTransactionsFiltered")
+	return c.poll(log.ModuleTX), nil
+}
+
+func (c *Client) poll(mod string) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.polling == nil {
+		c.polling = make(map[string]int)
+	}
+
+	c.polling[mod]++
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			if c.polling[mod] > 0 {
+				c.polling[mod]--
+			}
+		})
+	}
+}
+
+func (c *Client) isPolling(mod string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Consensus events are always polled by a client until it is closed.
+	if mod == log.ModuleConsensus {
+		return !c.closed
+	}
+
+	return c.polling[mod] > 0
+}
+
+// Emit injects a websocket event into the client, invoking its respective
+// callback should the event's module be polled. Errors may be emitted as
+// well, which invoke OnError.
+func (c *Client) Emit(ev interface{}) error {
+	mod, fn := c.handler(ev)
+	if mod == "" {
+		return fmt.Errorf("clientmock: unknown event type %T", ev)
+	}
+
+	if mod != modError && !c.isPolling(mod) {
+		return ErrNotPolling
+	}
+
+	if fn != nil {
+		fn()
+	}
+
+	return nil
+}
+
+// handler returns the module of an event, and a function calling its callback
+// should the callback be set.
+func (c *Client) handler(ev interface{}) (string, func()) { // nolint:gocyclo
+	switch ev := ev.(type) {
+	case error:
+		if c.OnError == nil {
+			return modError, nil
+		}
+		return modError, func() { c.OnError(ev) }
+	case wctl.BalanceUpdate:
+		if c.OnBalanceUpdated == nil {
+			return log.ModuleAccounts, nil
+		}
+		return log.ModuleAccounts, func() { c.OnBalanceUpdated(ev) }
+	case wctl.GasBalanceUpdate:
+		if c.OnGasBalanceUpdated == nil {
+			return log.ModuleAccounts, nil
+		}
+		return log.ModuleAccounts, func() { c.OnGasBalanceUpdated(ev) }
+	case wctl.NumPagesUpdated:
+		if c.OnNumPagesUpdated == nil {
+			return log.ModuleAccounts, nil
+		}
+		return log.ModuleAccounts, func() { c.OnNumPagesUpdated(ev) }
+	case wctl.StakeUpdated:
+		if c.OnStakeUpdated == nil {
+			return log.ModuleAccounts, nil
+		}
+		return log.ModuleAccounts, func() { c.OnStakeUpdated(ev) }
+	case wctl.RewardUpdated:
+		if c.OnRewardUpdated == nil {
+			return log.ModuleAccounts, nil
+		}
+		return log.ModuleAccounts, func() { c.OnRewardUpdated(ev) }
+	case wctl.PeerJoin:
+		if c.OnPeerJoin == nil {
+			return log.ModuleNetwork, nil
+		}
+		return log.ModuleNetwork, func() { c.OnPeerJoin(ev) }
+	case wctl.PeerLeave:
+		if c.OnPeerLeave == nil {
+			return log.ModuleNetwork, nil
+		}
+		return log.ModuleNetwork, func() { c.OnPeerLeave(ev) }
+	case wctl.Proposal:
+		if c.OnProposal == nil {
+			return log.ModuleConsensus, nil
+		}
+		return log.ModuleConsensus, func() { c.OnProposal(ev) }
+	case wctl.Finalized:
+		if c.OnFinalized == nil {
+			return log.ModuleConsensus, nil
+		}
+		return log.ModuleConsensus, func() { c.OnFinalized(ev) }
+	case wctl.ContractGas:
+		if c.OnContractGas == nil {
+			return log.ModuleContract, nil
+		}
+		return log.ModuleContract, func() { c.OnContractGas(ev) }
+	case wctl.ContractLog:
+		if c.OnContractLog == nil {
+			return log.ModuleContract, nil
+		}
+		return log.ModuleContract, func() { c.OnContractLog(ev) }
+	case wctl.TxApplied:
+		if c.OnTxApplied == nil {
+			return log.ModuleTX, nil
+		}
+		return log.ModuleTX, func() { c.OnTxApplied(ev) }
+	case wctl.TxGossipError:
+		if c.OnTxGossipError == nil {
+			return log.ModuleTX, nil
+		}
+		return log.ModuleTX, func() { c.OnTxGossipError(ev) }
+	case wctl.TxFailed:
+		if c.OnTxFailed == nil {
+			return log.ModuleTX, nil
+		}
+		return log.ModuleTX, func() { c.OnTxFailed(ev) }
+	case wctl.Metrics:
+		if c.OnMetrics == nil {
+			return log.ModuleMetrics, nil
+		}
+		return log.ModuleMetrics, func() { c.OnMetrics(ev) }
+	}
+
+	return "", nil
+}
@@ -0,0 +1,84 @@
+// +build unit
+
+package clientmock
+
+import (
+	"testing"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/perlin-network/wavelet/wctl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientResponses(t *testing.T) {
+	var c Client
+
+	var client wctl.LedgerClient = &c
+
+	_, err := client.LedgerStatus()
+	assert.Equal(t, ErrNotMocked, err)
+
+	c.GetAccountFunc = func(account [32]byte) (*wctl.Account, error) {
+		return &wctl.Account{PublicKey: account, Balance: 100}, nil
+	}
+
+	var sent []byte
+
+	c.SendTransactionFunc = func(tag byte, payload []byte) (*wctl.TxResponse, error) {
+		assert.Equal(t, byte(sys.TagTransfer), tag)
+		sent = payload
+
+		return &wctl.TxResponse{ID: [32]byte{1}}, nil
+	}
+
+	account, err := client.GetAccount([32]byte{2})
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, 100, account.Balance)
+	}
+
+	res, err := client.Pay([32]byte{3}, 10)
+	if assert.NoError(t, err) {
+		assert.Equal(t, [32]byte{1}, res.ID)
+	}
+
+	transfer, err := wavelet.ParseTransfer(sent)
+	if assert.NoError(t, err) {
+		assert.Equal(t, [32]byte{3}, transfer.Recipient)
+		assert.EqualValues(t, 10, transfer.Amount)
+	}
+
+	assert.Equal(t, []string{"LedgerStatus", "GetAccount", "Pay"}, c.Calls())
+}
+
+func TestClientEmit(t *testing.T) {
+	var c Client
+
+	var balances []uint64
+
+	c.Events().OnBalanceUpdated = func(u wctl.BalanceUpdate) {
+		balances = append(balances, u.Balance)
+	}
+
+	assert.Equal(t, ErrNotPolling, c.Emit(wctl.BalanceUpdate{Balance: 1}))
+
+	stop, err := c.PollAccounts()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, c.Emit(wctl.BalanceUpdate{Balance: 2}))
+
+	stop()
+	stop()
+
+	assert.Equal(t, ErrNotPolling, c.Emit(wctl.BalanceUpdate{Balance: 3}))
+	assert.Equal(t, []uint64{2}, balances)
+
+	// Consensus events are delivered until the client is closed.
+	assert.NoError(t, c.Emit(wctl.Finalized{BlockHeight: 1}))
+	c.Close()
+	assert.Equal(t, ErrNotPolling, c.Emit(wctl.Finalized{BlockHeight: 2}))
+
+	assert.Error(t, c.Emit(struct{}{}))
+}
@@ -0,0 +1,86 @@
+package wctl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*TimeResponse)(nil)
+
+// TimeResponse is the time on the clock of a node.
+type TimeResponse struct {
+	Time time.Time `json:"time"`
+}
+
+func (t *TimeResponse) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	raw := v.Get("time")
+	if raw == nil {
+		return errUnmarshalFail(v, "time", errors.New("missing"))
+	}
+
+	millis, err := raw.Int64()
+	if err != nil {
+		return errUnmarshalFail(v, "time", err)
+	}
+
+	t.Time = time.Unix(0, millis*int64(time.Millisecond))
+
+	return nil
+}
+
+// NodeTime calls the /time endpoint of the API.
+func (c *Client) NodeTime() (*TimeResponse, error) {
+	var res TimeResponse
+
+	if err := c.RequestJSON(RouteTime, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ClockSkew measures how far the clock of the node is ahead of the local
+// clock, assuming the node read its clock halfway through the round trip.
+// The measurement is only accurate to the millisecond, give or take half the
+// round trip time.
+func (c *Client) ClockSkew() (time.Duration, error) {
+	start := time.Now()
+
+	res, err := c.NodeTime()
+	if err != nil {
+		return 0, err
+	}
+
+	rtt := time.Since(start)
+
+	return res.Time.Sub(start.Add(rtt / 2)), nil
+}
+
+// SyncWithNodeClock measures the clock skew between the node and the local
+// clock, correcting for it in Now, and returns it.
+func (c *Client) SyncWithNodeClock() (time.Duration, error) {
+	skew, err := c.ClockSkew()
+	if err != nil {
+		return 0, err
+	}
+
+	c.clockOffset.Store(int64(skew))
+
+	return skew, nil
+}
+
+// Now returns the local time, corrected for the skew between the node and
+// the local clock as last measured by SyncWithNodeClock. Nonces of
+// transactions sent by the client are derived from it.
+func (c *Client) Now() time.Time {
+	return time.Now().Add(time.Duration(c.clockOffset.Load()))
+}
@@ -0,0 +1,47 @@
+package wctl
+
+import (
+	"encoding/hex"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*AnchoredData)(nil)
+
+// AnchoredData is a blob anchored into the ledger by a data transaction.
+type AnchoredData struct {
+	ID   [32]byte `json:"id"`
+	Data []byte   `json:"data"`
+}
+
+func (d *AnchoredData) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, d.ID[:], "id"); err != nil {
+		return err
+	}
+
+	d.Data, err = hex.DecodeString(string(v.GetStringBytes("data")))
+	if err != nil {
+		return errUnmarshalFail(v, "data", err)
+	}
+
+	return nil
+}
+
+// GetData returns the blob anchored by the data transaction with ID id.
+func (c *Client) GetData(id [32]byte) (*AnchoredData, error) {
+	path := RouteTxList + "/" + hex.EncodeToString(id[:]) + "/data"
+
+	var res AnchoredData
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
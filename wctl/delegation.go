@@ -0,0 +1,104 @@
+package wctl
+
+import (
+	"encoding/hex"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*Delegations)(nil)
+
+// Delegations are the PERLs delegated to an account as a validator, and the
+// PERLs it delegates to validators.
+type Delegations struct {
+	PublicKey [32]byte `json:"public_key"`
+
+	// Commission is charged by the account on the rewards of its
+	// delegators, in hundredths of a percent.
+	Commission uint16           `json:"commission"`
+	Delegated  uint64           `json:"delegated"`
+	Delegators []DelegatedStake `json:"delegators"`
+	Bonded     []DelegatedStake `json:"delegations"`
+	Unbonding  []UnbondingStake `json:"unbonding"`
+}
+
+// DelegatedStake is an amount of PERLs delegated by or to Account.
+type DelegatedStake struct {
+	Account [32]byte `json:"account"`
+	Amount  uint64   `json:"amount"`
+}
+
+// UnbondingStake is an amount of PERLs undelegated from Validator, which may
+// be claimed back from block Block onwards.
+type UnbondingStake struct {
+	Validator [32]byte `json:"validator"`
+	Amount    uint64   `json:"amount"`
+	Block     uint64   `json:"block"`
+}
+
+func (d *Delegations) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, d.PublicKey[:], "public_key"); err != nil {
+		return err
+	}
+
+	d.Commission = uint16(v.GetUint("commission"))
+	d.Delegated = v.GetUint64("delegated")
+
+	if d.Delegators, err = jsonDelegatedStakes(v, "delegators", "delegator"); err != nil {
+		return err
+	}
+
+	if d.Bonded, err = jsonDelegatedStakes(v, "delegations", "validator"); err != nil {
+		return err
+	}
+
+	d.Unbonding = d.Unbonding[:0]
+
+	for _, o := range v.GetArray("unbonding") {
+		unbonding := UnbondingStake{Amount: o.GetUint64("amount"), Block: o.GetUint64("block")}
+
+		if err := jsonHex(o, unbonding.Validator[:], "validator"); err != nil {
+			return err
+		}
+
+		d.Unbonding = append(d.Unbonding, unbonding)
+	}
+
+	return nil
+}
+
+func jsonDelegatedStakes(v *fastjson.Value, key, accountKey string) ([]DelegatedStake, error) {
+	var stakes []DelegatedStake
+
+	for _, o := range v.GetArray(key) {
+		stake := DelegatedStake{Amount: o.GetUint64("amount")}
+
+		if err := jsonHex(o, stake.Account[:], accountKey); err != nil {
+			return nil, err
+		}
+
+		stakes = append(stakes, stake)
+	}
+
+	return stakes, nil
+}
+
+// GetDelegations calls the /accounts/<id>/delegations endpoint of the API,
+// returning the PERLs delegated to and by the account.
+func (c *Client) GetDelegations(account [32]byte) (*Delegations, error) {
+	path := RouteAccount + "/" + hex.EncodeToString(account[:]) + "/delegations"
+
+	var res Delegations
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
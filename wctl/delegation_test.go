@@ -0,0 +1,57 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDelegations(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RouteAccount+"/"+strings.Repeat("01", 32)+"/delegations" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"public_key":"%s","commission":500,"delegated":300,`+
+			`"delegators":[{"delegator":"%s","amount":300}],"delegations":[{"validator":"%s","amount":20}],`+
+			`"unbonding":[{"validator":"%s","amount":5,"block":1011}]}`,
+			strings.Repeat("01", 32), strings.Repeat("02", 32), strings.Repeat("03", 32), strings.Repeat("03", 32))
+	})
+	defer stop()
+
+	var account [32]byte
+	copy(account[:], strings.Repeat("\x01", 32))
+
+	res, err := c.GetDelegations(account)
+	require.NoError(t, err)
+
+	assert.Equal(t, account, res.PublicKey)
+	assert.EqualValues(t, 500, res.Commission)
+	assert.EqualValues(t, 300, res.Delegated)
+
+	if assert.Len(t, res.Delegators, 1) {
+		assert.Equal(t, byte(2), res.Delegators[0].Account[0])
+		assert.EqualValues(t, 300, res.Delegators[0].Amount)
+	}
+
+	if assert.Len(t, res.Bonded, 1) {
+		assert.Equal(t, byte(3), res.Bonded[0].Account[0])
+		assert.EqualValues(t, 20, res.Bonded[0].Amount)
+	}
+
+	if assert.Len(t, res.Unbonding, 1) {
+		assert.Equal(t, byte(3), res.Unbonding[0].Validator[0])
+		assert.EqualValues(t, 5, res.Unbonding[0].Amount)
+		assert.EqualValues(t, 1011, res.Unbonding[0].Block)
+	}
+
+	_, err = c.GetDelegations([32]byte{})
+	assert.Error(t, err)
+}
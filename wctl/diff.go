@@ -0,0 +1,102 @@
+package wctl
+
+import (
+	"encoding/hex"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*TransactionDiff)(nil)
+
+// FieldDiff is the value of an account field before and after a transaction.
+type FieldDiff struct {
+	Before uint64 `json:"before"`
+	After  uint64 `json:"after"`
+}
+
+// AccountDiff is the set of changes a transaction made to an account. Fields
+// the transaction did not change are nil.
+type AccountDiff struct {
+	ID [32]byte `json:"id"`
+
+	Balance    *FieldDiff `json:"balance,omitempty"`
+	Stake      *FieldDiff `json:"stake,omitempty"`
+	Reward     *FieldDiff `json:"reward,omitempty"`
+	GasBalance *FieldDiff `json:"gas_balance,omitempty"`
+
+	ContractCreated bool     `json:"contract_created,omitempty"`
+	ContractPages   []uint64 `json:"contract_pages,omitempty"`
+}
+
+// TransactionDiff is the set of accounts changed by applying a transaction.
+type TransactionDiff struct {
+	ID       [32]byte      `json:"id"`
+	Accounts []AccountDiff `json:"accounts"`
+}
+
+func (d *TransactionDiff) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, d.ID[:], "id"); err != nil {
+		return err
+	}
+
+	d.Accounts = d.Accounts[:0]
+
+	for _, a := range v.GetArray("accounts") {
+		var account AccountDiff
+
+		if err := jsonHex(a, account.ID[:], "id"); err != nil {
+			return err
+		}
+
+		fields := []struct {
+			key  string
+			dest **FieldDiff
+		}{
+			{"balance", &account.Balance},
+			{"stake", &account.Stake},
+			{"reward", &account.Reward},
+			{"gas_balance", &account.GasBalance},
+		}
+
+		for _, field := range fields {
+			if f := a.Get(field.key); f != nil {
+				*field.dest = &FieldDiff{Before: f.GetUint64("before"), After: f.GetUint64("after")}
+			}
+		}
+
+		account.ContractCreated = a.GetBool("contract_created")
+
+		for _, page := range a.GetArray("contract_pages") {
+			index, err := page.Uint64()
+			if err != nil {
+				return errUnmarshalFail(a, "contract_pages", err)
+			}
+
+			account.ContractPages = append(account.ContractPages, index)
+		}
+
+		d.Accounts = append(d.Accounts, account)
+	}
+
+	return nil
+}
+
+// GetTransactionDiff returns the changes made to accounts by the applied
+// transaction with ID id.
+func (c *Client) GetTransactionDiff(id [32]byte) (*TransactionDiff, error) {
+	path := RouteTxList + "/" + hex.EncodeToString(id[:]) + "/diff"
+
+	var res TransactionDiff
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,190 @@
+package wctl
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"go.uber.org/atomic"
+)
+
+// Endpoint is the address of the HTTP API of a node.
+type Endpoint struct {
+	Host string
+	Port uint16
+}
+
+func (e Endpoint) String() string {
+	return net.JoinHostPort(e.Host, strconv.FormatUint(uint64(e.Port), 10))
+}
+
+// endpointPool keeps track of which endpoints of a client are healthy, and
+// which endpoint requests stick to.
+type endpointPool struct {
+	list    []Endpoint
+	healthy []atomic.Bool
+
+	// The endpoint requests stick to, and the endpoint the next balanced
+	// request is made to.
+	active atomic.Uint32
+	next   atomic.Uint32
+}
+
+func newEndpointPool(endpoints []Endpoint) *endpointPool {
+	p := &endpointPool{
+		list:    endpoints,
+		healthy: make([]atomic.Bool, len(endpoints)),
+	}
+
+	for i := range p.healthy {
+		p.healthy[i].Store(true)
+	}
+
+	return p
+}
+
+// order returns the indices of the endpoints in the order a request is to
+// be attempted against them: starting from the active endpoint, or from the
+// next endpoint in round-robin order should balance be set. Unhealthy
+// endpoints are attempted last, should every healthy endpoint fail.
+func (p *endpointPool) order(balance bool) []int {
+	n := uint32(len(p.list))
+
+	start := p.active.Load()
+	if balance {
+		start = p.next.Inc() - 1
+	}
+
+	healthy := make([]int, 0, n)
+	var unhealthy []int
+
+	for i := uint32(0); i < n; i++ {
+		idx := int((start + i) % n)
+
+		if p.healthy[idx].Load() {
+			healthy = append(healthy, idx)
+		} else {
+			unhealthy = append(unhealthy, idx)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// succeeded marks endpoint i as healthy, and should sticky be set, as the
+// endpoint requests stick to from now on.
+func (p *endpointPool) succeeded(i int, sticky bool) {
+	p.healthy[i].Store(true)
+
+	if sticky {
+		p.active.Store(uint32(i))
+	}
+}
+
+// failed marks endpoint i as unhealthy.
+func (p *endpointPool) failed(i int) {
+	p.healthy[i].Store(false)
+}
+
+// balanced reports whether req may be load balanced across endpoints. It
+// must be read-only, and not be authenticated with a token other than
+// Config.APISecret, as such tokens may only be accepted by the node which
+// issued them.
+func (c *Client) balanced(req *fasthttp.Request) bool {
+	if !c.LoadBalance || !req.Header.IsGet() {
+		return false
+	}
+
+	return string(req.Header.Peek("Authorization")) == "Bearer "+c.APISecret
+}
+
+// failover performs req against the endpoints of the client, moving on to
+// the next endpoint should it fail to be delivered, or yield a 502, 503 or
+// 504. Requests given up on due to ctx are not attempted again.
+func (c *Client) failover(ctx context.Context, req *fasthttp.Request, res *fasthttp.Response) error {
+	if c.endpoints == nil {
+		return c.do(ctx, req, res)
+	}
+
+	balance := c.balanced(req)
+
+	var err error
+
+	for n, i := range c.endpoints.order(balance) {
+		if n > 0 {
+			res.Reset()
+		}
+
+		req.URI().SetHost(c.endpoints.list[i].String())
+
+		err = c.do(ctx, req, res)
+		if _, ok := err.(*permanentError); ok {
+			return err
+		}
+
+		if err == nil && !retryableStatus(res.StatusCode()) {
+			c.endpoints.succeeded(i, !balance)
+			return nil
+		}
+
+		c.endpoints.failed(i)
+	}
+
+	// Have the caller handle the last response should every endpoint yield
+	// a retryable status.
+	return err
+}
+
+// checkEndpoints checks the health of every endpoint of the client every
+// interval, until the returned function is called.
+func (c *Client) checkEndpoints(interval time.Duration) func() {
+	stop := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			for i, e := range c.endpoints.list {
+				c.endpoints.healthy[i].Store(c.checkEndpoint(e))
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(stop)
+		})
+	}
+}
+
+// checkEndpoint reports whether the ledger status of the node at e may be
+// queried, bypassing the interceptors of the client.
+func (c *Client) checkEndpoint(e Endpoint) bool {
+	req, res := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(res)
+
+	req.URI().Update(c.url + RouteLedger)
+	req.URI().SetHost(e.String())
+	req.Header.SetMethod(ReqGet)
+	req.Header.Set("Authorization", "Bearer "+c.APISecret)
+
+	if err := c.doDeadline(req, res, time.Now().Add(c.Config.Timeout)); err != nil {
+		return false
+	}
+
+	return res.StatusCode() == http.StatusOK
+}
@@ -0,0 +1,178 @@
+// +build unit
+
+package wctl
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPoolClient returns a client failing over between the given
+// servers, in order.
+func newTestPoolClient(t *testing.T, servers ...*httptest.Server) *Client {
+	endpoints := make([]Endpoint, 0, len(servers))
+
+	for _, srv := range servers {
+		host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		p, err := strconv.ParseUint(port, 10, 16)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		endpoints = append(endpoints, Endpoint{Host: host, Port: uint16(p)})
+	}
+
+	return &Client{
+		Config: Config{
+			APIHost:   endpoints[0].Host,
+			APIPort:   endpoints[0].Port,
+			Endpoints: endpoints[1:],
+			Timeout:   time.Second,
+		},
+		url:       servers[0].URL,
+		endpoints: newEndpointPool(endpoints),
+	}
+}
+
+// newNamedServer returns a server responding with its name.
+func newNamedServer(name string, status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(name))
+	}))
+}
+
+func TestEndpointFailover(t *testing.T) {
+	down := newNamedServer("down", http.StatusOK)
+	down.Close()
+
+	unavailable := newNamedServer("unavailable", http.StatusServiceUnavailable)
+	defer unavailable.Close()
+
+	up := newNamedServer("up", http.StatusOK)
+	defer up.Close()
+
+	c := newTestPoolClient(t, down, unavailable, up)
+
+	res, err := c.Request(RouteLedger, ReqGet, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "up", string(res))
+
+	assert.False(t, c.endpoints.healthy[0].Load())
+	assert.False(t, c.endpoints.healthy[1].Load())
+	assert.True(t, c.endpoints.healthy[2].Load())
+
+	// Requests stick to the endpoint which served them last.
+	assert.EqualValues(t, 2, c.endpoints.active.Load())
+	assert.Equal(t, []int{2, 0, 1}, c.endpoints.order(false))
+
+	// Every endpoint is attempted should all of them be unhealthy, with the
+	// response of the last endpoint being reported.
+	c = newTestPoolClient(t, down, unavailable)
+
+	_, err = c.Request(RouteLedger, ReqGet, nil)
+	if assert.IsType(t, &APIError{}, err) {
+		assert.Equal(t, http.StatusServiceUnavailable, err.(*APIError).StatusCode)
+	}
+
+	// Requests given up on are not attempted against other endpoints.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c = newTestPoolClient(t, up, up)
+
+	_, err = c.RequestCtx(ctx, RouteLedger, ReqGet, nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, c.endpoints.healthy[0].Load())
+	assert.True(t, c.endpoints.healthy[1].Load())
+}
+
+func TestEndpointLoadBalance(t *testing.T) {
+	a := newNamedServer("a", http.StatusOK)
+	defer a.Close()
+
+	b := newNamedServer("b", http.StatusOK)
+	defer b.Close()
+
+	c := newTestPoolClient(t, a, b)
+	c.LoadBalance = true
+
+	var served []string
+
+	for i := 0; i < 4; i++ {
+		res, err := c.Request(RouteLedger, ReqGet, nil)
+		assert.NoError(t, err)
+
+		served = append(served, string(res))
+	}
+
+	assert.Equal(t, []string{"a", "b", "a", "b"}, served)
+
+	// Requests which are not read-only stick to the active endpoint.
+	for i := 0; i < 2; i++ {
+		res, err := c.Request(RouteTxSend, ReqPost, []byte("{}"))
+		assert.NoError(t, err)
+		assert.Equal(t, "a", string(res))
+	}
+
+	// So do requests authenticated with a token other than APISecret.
+	c.interceptors = []Interceptor{AuthInterceptor(func() string { return "token" })}
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Request(RouteLedger, ReqGet, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "a", string(res))
+	}
+}
+
+func TestEndpointHealthCheck(t *testing.T) {
+	a := newNamedServer("a", http.StatusServiceUnavailable)
+	defer a.Close()
+
+	b := newNamedServer("b", http.StatusOK)
+	defer b.Close()
+
+	c := newTestPoolClient(t, a, b)
+
+	stop := c.checkEndpoints(10 * time.Millisecond)
+	defer stop()
+
+	assert.Eventually(t, func() bool {
+		return !c.endpoints.healthy[0].Load() && c.endpoints.healthy[1].Load()
+	}, time.Second, 10*time.Millisecond)
+
+	// Unhealthy endpoints are skipped, without being attempted.
+	assert.Equal(t, []int{1, 0}, c.endpoints.order(false))
+
+	res, err := c.Request(RouteLedger, ReqGet, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", string(res))
+}
+
+func TestEndpointWebsocketFailover(t *testing.T) {
+	down := newNamedServer("down", http.StatusOK)
+	down.Close()
+
+	rejecting := newNamedServer("rejecting", http.StatusBadRequest)
+	defer rejecting.Close()
+
+	// Endpoints rejecting a websocket are not failed over from.
+	c := newTestPoolClient(t, down, rejecting, rejecting)
+
+	_, err := c.EstablishWS(RouteWSConsensus)
+	assert.Error(t, err)
+
+	assert.False(t, c.endpoints.healthy[0].Load())
+	assert.True(t, c.endpoints.healthy[1].Load())
+}
@@ -1,44 +1,116 @@
 package wctl
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/valyala/fastjson"
 )
 
-type RequestError struct {
-	Status      string `json:"status"`
-	ErrorString string `json:"error"`
+var (
+	// ErrInvalidSignature is returned when the node rejects a transaction for
+	// its signature.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrStampTooWeak is returned when the node rejects a transaction for its
+	// stamp not meeting the required difficulty.
+	ErrStampTooWeak = errors.New("stamp too weak")
+
+	// ErrNotReplaceable is returned when the node rejects a transaction for
+	// sharing its nonce with another transaction which it may not replace.
+	ErrNotReplaceable = errors.New("not replaceable")
+
+	// ErrTagInactive is returned when the node rejects a transaction for its
+	// tag being gated behind a feature which has yet to activate.
+	ErrTagInactive = errors.New("tag inactive")
+
+	// ErrInvalidContract is returned when the node rejects a transaction for
+	// the code of the smart contract it spawns, or upgrades to, such as for
+	// executing non-deterministic float instructions.
+	ErrInvalidContract = errors.New("invalid contract")
+
+	// ErrUnauthorized is returned when the node rejects a request for its
+	// credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrNotFound is returned when the node could not find what was
+	// requested.
+	ErrNotFound = errors.New("not found")
+
+	// ErrRateLimited is returned when the node rejects a request for too many
+	// having been made.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// serverCodes are the errors reported by the node through the code of an
+// APIError.
+var serverCodes = map[string]error{
+	"insufficient_balance": ErrInsufficientPerls,
+	"invalid_signature":    ErrInvalidSignature,
+	"stamp_too_weak":       ErrStampTooWeak,
+	"not_replaceable":      ErrNotReplaceable,
+	"tag_inactive":         ErrTagInactive,
+	"invalid_contract":     ErrInvalidContract,
+}
+
+// statusCodes are the errors reported by the node through the status code of
+// an APIError.
+var statusCodes = map[int]error{
+	http.StatusUnauthorized:    ErrUnauthorized,
+	http.StatusNotFound:        ErrNotFound,
+	http.StatusTooManyRequests: ErrRateLimited,
+}
+
+// APIError is returned for requests the node responded to with a status code
+// other than 200 OK. Use errors.Is against the errors of this package to
+// branch on why a request failed.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	ServerCode string `json:"code"`
+	Message    string `json:"error"`
 
 	RequestBody  []byte
 	ResponseBody []byte
-	StatusCode   int
 }
 
-func ParseRequestError(b []byte) *RequestError {
-	var (
-		parser fastjson.Parser
-		e      RequestError
-	)
+// parseAPIError parses the error the node responded to a request with. The
+// error message is left empty should the response not be a JSON error.
+func parseAPIError(statusCode int, reqBody, resBody []byte) *APIError {
+	var parser fastjson.Parser
 
-	v, err := parser.ParseBytes(b)
+	e := &APIError{
+		StatusCode:   statusCode,
+		RequestBody:  append([]byte(nil), reqBody...),
+		ResponseBody: append([]byte(nil), resBody...),
+	}
+
+	v, err := parser.ParseBytes(resBody)
 	if err != nil {
-		return nil
+		return e
 	}
 
-	e.Status = string(v.GetStringBytes("status"))
-	e.ErrorString = string(v.GetStringBytes("error"))
+	e.ServerCode = string(v.GetStringBytes("code"))
+	e.Message = string(v.GetStringBytes("error"))
+
+	return e
+}
 
-	if e.Status == "" || e.ErrorString == "" {
-		return nil
+// Is reports whether target is the error of this package the node reported,
+// either through the code or the status code of the response.
+func (e *APIError) Is(target error) bool {
+	if err, ok := serverCodes[e.ServerCode]; ok && err == target {
+		return true
 	}
 
-	return &e
+	err, ok := statusCodes[e.StatusCode]
+
+	return ok && err == target
 }
 
-func (e *RequestError) Error() string {
-	if e.ErrorString != "" {
-		return e.ErrorString
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return e.Message
 	}
 
 	return fmt.Sprintf(`Unexpected error code %d.
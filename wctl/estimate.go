@@ -0,0 +1,69 @@
+package wctl
+
+import (
+	"encoding/hex"
+
+	"github.com/valyala/fastjson"
+)
+
+var (
+	_ UnmarshalableJSON = (*FeeEstimate)(nil)
+	_ MarshalableJSON   = (*estimateRequest)(nil)
+)
+
+// FeeEstimate is what sending a transaction would cost its sender.
+type FeeEstimate struct {
+	// Fee is the transaction fee, being none should the fee of the sender be
+	// sponsored.
+	Fee uint64 `json:"fee"`
+
+	// Gas is the gas spent invoking smart contracts.
+	Gas uint64 `json:"gas"`
+}
+
+func (f *FeeEstimate) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	f.Fee = v.GetUint64("fee")
+	f.Gas = v.GetUint64("gas")
+
+	return nil
+}
+
+type estimateRequest struct {
+	sender  [32]byte
+	tag     byte
+	payload []byte
+}
+
+func (s *estimateRequest) MarshalJSON() ([]byte, error) {
+	var arena fastjson.Arena
+	o := arena.NewObject()
+
+	o.Set("sender", arena.NewString(hex.EncodeToString(s.sender[:])))
+	o.Set("tag", arena.NewNumberInt(int(s.tag)))
+	o.Set("payload", arena.NewString(hex.EncodeToString(s.payload)))
+
+	return o.MarshalTo(nil), nil
+}
+
+// EstimateFee calls the /tx/estimate endpoint of the API, which dry-runs the
+// transaction the client would send with the given tag and payload against
+// the latest state of the ledger. Payloads are best crafted like for
+// SendTransaction.
+func (c *Client) EstimateFee(tag byte, payload []byte) (*FeeEstimate, error) {
+	var res FeeEstimate
+
+	req := &estimateRequest{sender: c.PublicKey, tag: tag, payload: payload}
+
+	if err := c.RequestJSON(RouteTxEstimate, ReqPost, req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
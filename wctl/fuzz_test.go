@@ -0,0 +1,82 @@
+// +build unit
+
+package wctl
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/valyala/fastjson"
+)
+
+// unmarshalers are all decoders of responses and events sent by a node.
+var unmarshalers = []func(b []byte) error{
+	func(b []byte) error { return json.Unmarshal(b, new(Account)) },
+	func(b []byte) error { return json.Unmarshal(b, new(ContractStorageList)) },
+	func(b []byte) error { return json.Unmarshal(b, new(ContractStorageValue)) },
+	func(b []byte) error { return json.Unmarshal(b, new(ContractUpgrades)) },
+	func(b []byte) error { return json.Unmarshal(b, new(GasSchedule)) },
+	func(b []byte) error { return json.Unmarshal(b, new(LedgerStatusResponse)) },
+	func(b []byte) error { return json.Unmarshal(b, new(MsgResponse)) },
+	func(b []byte) error { return json.Unmarshal(b, new(Transaction)) },
+	func(b []byte) error { return json.Unmarshal(b, new(TransactionList)) },
+	func(b []byte) error { return json.Unmarshal(b, new(TxResponse)) },
+	func(b []byte) error { return json.Unmarshal(b, new(Validators)) },
+	func(b []byte) error { return json.Unmarshal(b, new(PeerScores)) },
+	func(b []byte) error { return json.Unmarshal(b, new(Round)) },
+	func(b []byte) error { return json.Unmarshal(b, new(RoundList)) },
+	event(parseAccountsBalanceUpdated),
+	event(parseAccountsGasBalanceUpdated),
+	event(parseAccountNumPagesUpdated),
+	event(parseAccountStakeUpdated),
+	event(parseAccountRewardUpdated),
+	event(parseConsensusProposal),
+	event(parseConsensusFinalized),
+	event(parseValidatorJoin),
+	event(parseValidatorLeave),
+	event(parseRound),
+	event(parseContractGas),
+	event(parseContractLog),
+	event(parsePeerJoin),
+	event(parsePeerLeave),
+	event(parsePeerBan),
+	event(parseTxApplied),
+	event(parseTxGossipError),
+	event(parseTxFailed),
+}
+
+func event(parse func(c *Client, v *fastjson.Value) error) func(b []byte) error {
+	return func(b []byte) error {
+		v, err := fastjson.ParseBytes(b)
+		if err != nil {
+			return err
+		}
+
+		return parse(&Client{}, v)
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	zero := `"0000000000000000000000000000000000000000000000000000000000000000"`
+
+	seeds := []string{
+		`{"public_key":` + zero + `,"balance":1,"stake":2,"reward":3,"nonce":4,"is_contract":false}`,
+		`{"public_key":` + zero + `,"address":"127.0.0.1:3000","num_accounts":1,` +
+			`"block":{"merkle_root":"00000000000000000000000000000000","height":1,"id":` + zero + `},` +
+			`"preferred_id":"","sync_status":"Node is fully synced","peers":[{"address":"","public_key":` + zero + `}]}`,
+		`{"msg":"hello"}`,
+		`[{"id":` + zero + `,"sender":` + zero + `,"status":"applied","nonce":1,"tag":1,"payload":"","signature":""}]`,
+		`{"tx_id":` + zero + `}`,
+		`{"mod":"tx","event":"applied","tx_id":` + zero + `,"sender_id":` + zero + `,"depth":1,"tag":1,"time":"2019-01-01T00:00:00Z"}`,
+	}
+
+	for i := range unmarshalers {
+		for _, seed := range seeds {
+			f.Add(uint8(i), []byte(seed))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, kind uint8, b []byte) {
+		_ = unmarshalers[int(kind)%len(unmarshalers)](b)
+	})
+}
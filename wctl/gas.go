@@ -0,0 +1,109 @@
+package wctl
+
+import (
+	"context"
+	"errors"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*GasSchedule)(nil)
+
+// GasSchedule is the cost in gas of executing smart contracts, as applying
+// to the block of index Height.
+type GasSchedule struct {
+	Version uint32 `json:"version"`
+	Height  uint64 `json:"height"`
+
+	// DefaultInstruction is the cost of instructions absent from
+	// Instructions, and of functions of the ledger absent from HostFunctions.
+	DefaultInstruction int64            `json:"default_instruction"`
+	Instructions       map[string]int64 `json:"instructions"`
+	HostFunctions      map[string]int64 `json:"host_functions"`
+}
+
+// GetCost returns the cost of the instruction or function of the ledger
+// named key, such as "i32.add" or "wavelet.hash.sha256".
+func (s *GasSchedule) GetCost(key string) int64 {
+	if cost, ok := s.HostFunctions[key]; ok {
+		return cost
+	}
+
+	if cost, ok := s.Instructions[key]; ok {
+		return cost
+	}
+
+	return s.DefaultInstruction
+}
+
+// GasSchedule calls the /ledger/gas endpoint of the API, returning the gas
+// schedule applying to the next block to be finalized.
+func (c *Client) GasSchedule() (*GasSchedule, error) {
+	return c.GasScheduleCtx(context.Background())
+}
+
+// GasScheduleCtx is GasSchedule, which gives up once ctx is done.
+func (c *Client) GasScheduleCtx(ctx context.Context) (*GasSchedule, error) {
+	var res GasSchedule
+
+	if err := c.RequestJSONCtx(ctx, RouteLedgerGas, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (s *GasSchedule) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if !v.Exists("version") {
+		return errUnmarshalFail(v, "version", errors.New("missing"))
+	}
+
+	version, err := v.Get("version").Uint()
+	if err != nil {
+		return errUnmarshalFail(v, "version", err)
+	}
+
+	s.Version = uint32(version)
+	s.Height = v.GetUint64("height")
+	s.DefaultInstruction = v.GetInt64("default_instruction")
+
+	if s.Instructions, err = unmarshalGasCosts(v, "instructions"); err != nil {
+		return err
+	}
+
+	if s.HostFunctions, err = unmarshalGasCosts(v, "host_functions"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func unmarshalGasCosts(v *fastjson.Value, key string) (map[string]int64, error) {
+	o, err := v.Get(key).Object()
+	if err != nil {
+		return nil, errUnmarshalFail(v, key, err)
+	}
+
+	costs := make(map[string]int64, o.Len())
+
+	o.Visit(func(k []byte, cost *fastjson.Value) {
+		if err != nil {
+			return
+		}
+
+		costs[string(k)], err = cost.Int64()
+	})
+
+	if err != nil {
+		return nil, errUnmarshalFail(v, key, err)
+	}
+
+	return costs, nil
+}
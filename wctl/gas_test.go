@@ -0,0 +1,40 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGasSchedule(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RouteLedgerGas {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, `{"version":2,"height":10,"default_instruction":1,`+
+			`"instructions":{"i64.div_s":10},"host_functions":{"wavelet.log":100,"wavelet.log.byte":0}}`)
+	})
+	defer stop()
+
+	schedule, err := c.GasSchedule()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, schedule.Version)
+	assert.EqualValues(t, 10, schedule.Height)
+
+	assert.EqualValues(t, 10, schedule.GetCost("i64.div_s"))
+	assert.EqualValues(t, 100, schedule.GetCost("wavelet.log"))
+	assert.EqualValues(t, 0, schedule.GetCost("wavelet.log.byte"))
+	assert.EqualValues(t, 1, schedule.GetCost("i32.add"))
+
+	var invalid GasSchedule
+	assert.Error(t, invalid.UnmarshalJSON([]byte(`{"version":2,"instructions":{"i32.add":"1"},"host_functions":{}}`)))
+	assert.Error(t, invalid.UnmarshalJSON([]byte(`{"instructions":{},"host_functions":{}}`)))
+}
@@ -0,0 +1,161 @@
+package wctl
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*Health)(nil)
+
+// Health reports on the conditions of the health of a node, as checked by
+// /healthz for its liveness or by /readyz for its readiness.
+type Health struct {
+	// Status is "ok", or "unavailable" should any of the conditions checked
+	// not be met.
+	Status string       `json:"status"`
+	Checks HealthChecks `json:"checks"`
+}
+
+// OK returns true should all of the conditions checked have been met.
+func (h *Health) OK() bool {
+	return h.Status == "ok"
+}
+
+// HealthChecks are the conditions checked of the health of a node. Peers and
+// Sync are only checked of its readiness, and are nil otherwise.
+type HealthChecks struct {
+	Storage   StorageHealth   `json:"storage"`
+	Consensus ConsensusHealth `json:"consensus"`
+	Peers     *PeersHealth    `json:"peers,omitempty"`
+	Sync      *SyncHealth     `json:"sync,omitempty"`
+}
+
+// StorageHealth reports on whether the database of the node may be written
+// to and read from.
+type StorageHealth struct {
+	OK      bool          `json:"ok"`
+	Latency time.Duration `json:"latency"`
+
+	// Error is why the database failed to be written to or read from.
+	Error string `json:"error,omitempty"`
+}
+
+// ConsensusHealth reports on whether consensus has stalled, being the round
+// being finalized longer than MaxStall, should it not be 0.
+type ConsensusHealth struct {
+	OK            bool          `json:"ok"`
+	BlockIndex    uint64        `json:"block_index"`
+	RoundDuration time.Duration `json:"round_duration"`
+	MaxStall      time.Duration `json:"max_stall"`
+}
+
+// PeersHealth reports on whether the node is connected to at least MinPeers
+// peers.
+type PeersHealth struct {
+	OK       bool `json:"ok"`
+	NumPeers int  `json:"num_peers"`
+	MinPeers int  `json:"min_peers"`
+}
+
+// SyncHealth reports on whether the node is in sync with its peers, being it
+// at most MaxLag blocks behind them.
+type SyncHealth struct {
+	OK bool `json:"ok"`
+
+	// Reconciled is whether peers have yet told the node whether it is out
+	// of sync, and Syncing whether it is syncing its state from them.
+	Reconciled bool   `json:"reconciled"`
+	Syncing    bool   `json:"syncing"`
+	Lag        uint64 `json:"lag"`
+	MaxLag     uint64 `json:"max_lag"`
+}
+
+func (h *Health) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	h.Status = jsonString(v, "status")
+	h.Checks = HealthChecks{}
+
+	storage := v.Get("checks", "storage")
+
+	h.Checks.Storage.OK = storage.GetBool("ok")
+	h.Checks.Storage.Error = jsonString(storage, "error")
+
+	if h.Checks.Storage.Latency, err = time.ParseDuration(jsonString(storage, "latency")); err != nil {
+		return errUnmarshalFail(v, "checks.storage.latency", err)
+	}
+
+	consensus := v.Get("checks", "consensus")
+
+	h.Checks.Consensus.OK = consensus.GetBool("ok")
+	h.Checks.Consensus.BlockIndex = consensus.GetUint64("block_index")
+
+	if h.Checks.Consensus.RoundDuration, err = time.ParseDuration(jsonString(consensus, "round_duration")); err != nil {
+		return errUnmarshalFail(v, "checks.consensus.round_duration", err)
+	}
+
+	if h.Checks.Consensus.MaxStall, err = time.ParseDuration(jsonString(consensus, "max_stall")); err != nil {
+		return errUnmarshalFail(v, "checks.consensus.max_stall", err)
+	}
+
+	if peers := v.Get("checks", "peers"); peers != nil {
+		h.Checks.Peers = &PeersHealth{
+			OK:       peers.GetBool("ok"),
+			NumPeers: peers.GetInt("num_peers"),
+			MinPeers: peers.GetInt("min_peers"),
+		}
+	}
+
+	if sync := v.Get("checks", "sync"); sync != nil {
+		h.Checks.Sync = &SyncHealth{
+			OK:         sync.GetBool("ok"),
+			Reconciled: sync.GetBool("reconciled"),
+			Syncing:    sync.GetBool("syncing"),
+			Lag:        sync.GetUint64("lag"),
+			MaxLag:     sync.GetUint64("max_lag"),
+		}
+	}
+
+	return nil
+}
+
+// Health calls the /healthz endpoint of the API, returning whether the node
+// is live, being its database reachable and consensus not stalled.
+func (c *Client) Health() (*Health, error) {
+	return c.health(RouteHealthz)
+}
+
+// Ready calls the /readyz endpoint of the API, returning whether the node is
+// ready to serve clients, being it live, connected to enough peers and in
+// sync with them.
+func (c *Client) Ready() (*Health, error) {
+	return c.health(RouteReadyz)
+}
+
+// health requests the report at path, which the node responds to with 503
+// should any of the conditions checked not be met.
+func (c *Client) health(path string) (*Health, error) {
+	var res Health
+
+	raw, err := c.Request(path, ReqGet, nil)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusServiceUnavailable {
+		raw, err = apiErr.ResponseBody, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := res.UnmarshalJSON(raw); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
@@ -1,12 +1,14 @@
 package wctl
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/hex"
-	"errors"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/perlin-network/wavelet/events"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fastjson"
 )
@@ -22,6 +24,14 @@ type UnmarshalableJSON interface {
 // RequestJSON will make a request to a given path, with a given body and
 // return the JSON bytes result into `out` to unmarshal.
 func (c *Client) RequestJSON(path, method string, body MarshalableJSON, out UnmarshalableJSON) error {
+	return c.RequestJSONCtx(context.Background(), path, method, body, out)
+}
+
+// RequestJSONCtx is RequestJSON, which gives up on the request once ctx is
+// done.
+func (c *Client) RequestJSONCtx(
+	ctx context.Context, path, method string, body MarshalableJSON, out UnmarshalableJSON,
+) error {
 	var bytes []byte
 
 	if body != nil {
@@ -33,7 +43,7 @@ func (c *Client) RequestJSON(path, method string, body MarshalableJSON, out Unma
 		bytes = raw
 	}
 
-	resBody, err := c.Request(path, method, bytes)
+	resBody, err := c.RequestCtx(ctx, path, method, bytes)
 	if err != nil {
 		return err
 	}
@@ -48,6 +58,30 @@ func (c *Client) RequestJSON(path, method string, body MarshalableJSON, out Unma
 // Request will make a request to a given path, with a given body and return
 // the result in raw bytes.
 func (c *Client) Request(path string, method string, body []byte) ([]byte, error) {
+	return c.RequestCtx(context.Background(), path, method, body)
+}
+
+// RequestCtx is Request, which gives up on the request once ctx is done, in
+// which case ctx.Err() is returned.
+func (c *Client) RequestCtx(ctx context.Context, path string, method string, body []byte) ([]byte, error) {
+	return c.request(ctx, path, method, body, nil)
+}
+
+var defaultHTTPClient = &fasthttp.Client{}
+
+type traceparentKey struct{}
+
+// WithTraceparent returns a copy of ctx whose requests carry the W3C trace
+// context traceparent in HeaderTraceparent, for the node to trace them, and
+// any transaction they submit, as part of the trace of the caller.
+func WithTraceparent(ctx context.Context, traceparent string) context.Context {
+	return context.WithValue(ctx, traceparentKey{}, traceparent)
+}
+
+// request is RequestCtx with additional headers set on the request.
+func (c *Client) request(
+	ctx context.Context, path string, method string, body []byte, headers map[string]string,
+) ([]byte, error) {
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
 
@@ -58,6 +92,14 @@ func (c *Client) Request(path string, method string, body []byte) ([]byte, error
 	req.Header.SetContentType("application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APISecret)
 
+	if traceparent, ok := ctx.Value(traceparentKey{}).(string); ok && traceparent != "" {
+		req.Header.Set(HeaderTraceparent, traceparent)
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
 	if body != nil {
 		req.SetBody(body)
 	}
@@ -65,23 +107,113 @@ func (c *Client) Request(path string, method string, body []byte) ([]byte, error
 	res := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseResponse(res)
 
-	if err := fasthttp.DoTimeout(req, res, 5*time.Second); err != nil {
+	if err := c.invoke(ctx, req, res); err != nil {
+		// Report requests given up on as such, rather than as however
+		// the interceptors failed them.
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if p, ok := err.(*permanentError); ok {
+			return nil, p.err
+		}
+
 		return nil, err
 	}
 
 	if res.StatusCode() != http.StatusOK {
-		if err := ParseRequestError(res.Body()); err != nil {
-			return nil, err
-		}
+		return nil, parseAPIError(res.StatusCode(), req.Body(), res.Body())
+	}
+
+	// The response is released upon returning, so its body must be copied.
+	return append([]byte(nil), res.Body()...), nil
+}
+
+// invoke performs req through the interceptors of the client.
+func (c *Client) invoke(ctx context.Context, req *fasthttp.Request, res *fasthttp.Response) error {
+	return chainInterceptors(func(req *fasthttp.Request, res *fasthttp.Response) error {
+		return c.failover(ctx, req, res)
+	}, c.interceptors...)(req, res)
+}
+
+// doDeadline performs req with the client of c, being the default client of
+// fasthttp should c not have been created by NewClient.
+func (c *Client) doDeadline(req *fasthttp.Request, res *fasthttp.Response, deadline time.Time) error {
+	if c.httpClient == nil {
+		return defaultHTTPClient.DoDeadline(req, res, deadline)
+	}
+
+	// TLS is left to the dial function of the client, as fasthttp drops
+	// parts of the TLS configuration such as the check of pinned
+	// certificates.
+	if c.tlsConfig != nil {
+		req.URI().SetScheme("http")
+		defer req.URI().SetScheme("https")
+	}
+
+	return c.httpClient.DoDeadline(req, res, deadline)
+}
 
-		return nil, &RequestError{
-			RequestBody:  req.Body(),
-			ResponseBody: res.Body(),
-			StatusCode:   res.StatusCode(),
+// do performs req, for no longer than Config.Timeout, or until ctx is done.
+// Requests given up on due to ctx fail permanently, so as to not be retried.
+func (c *Client) do(ctx context.Context, req *fasthttp.Request, res *fasthttp.Response) error {
+	deadline := time.Now().Add(c.Config.Timeout)
+
+	// fasthttp may time out ahead of ctx when bounded by the deadline of ctx.
+	ctxDeadline, bounded := ctx.Deadline()
+	if bounded && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	timedOut := func(err error) error {
+		if err == fasthttp.ErrTimeout && bounded && !time.Now().Before(ctxDeadline) {
+			return Permanent(context.DeadlineExceeded)
 		}
+
+		return err
+	}
+
+	if ctx.Done() == nil {
+		return c.doDeadline(req, res, deadline)
 	}
 
-	return res.Body(), nil
+	if err := ctx.Err(); err != nil {
+		return Permanent(err)
+	}
+
+	// Requests in flight may not be aborted, so perform the request on copies
+	// of req and res which may outlive them, should ctx be done first.
+	reqCopy, resCopy := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	req.CopyTo(reqCopy)
+
+	release := func() {
+		fasthttp.ReleaseRequest(reqCopy)
+		fasthttp.ReleaseResponse(resCopy)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- c.doDeadline(reqCopy, resCopy, deadline)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			resCopy.CopyTo(res)
+		}
+
+		release()
+
+		return timedOut(err)
+	case <-ctx.Done():
+		go func() {
+			<-done
+			release()
+		}()
+
+		return Permanent(ctx.Err())
+	}
 }
 
 type jsonRaw []byte
@@ -90,10 +222,18 @@ func (j jsonRaw) MarshalJSON() ([]byte, error) {
 	return j, nil
 }
 
-var ErrInvalidHexLength = errors.New("invalid hex bytes length")
+var ErrInvalidHexLength = events.ErrInvalidHexLength
 
 func jsonHex(v *fastjson.Value, dst []byte, keys ...string) error {
-	i, err := hex.Decode(dst, v.GetStringBytes(keys...))
+	src := v.GetStringBytes(keys...)
+
+	// Check the length upfront, as hex.Decode panics should dst be too short.
+	if hex.DecodedLen(len(src)) != len(dst) {
+		return errUnmarshalFail(v, strings.Join(keys, "."),
+			ErrInvalidHexLength)
+	}
+
+	i, err := hex.Decode(dst, src)
 	if err != nil {
 		return errUnmarshalFail(v, strings.Join(keys, "."), err)
 	}
@@ -120,3 +260,14 @@ func jsonTime(v *fastjson.Value, t *time.Time, keys ...string) error {
 func jsonString(v *fastjson.Value, keys ...string) string {
 	return string(v.GetStringBytes(keys...))
 }
+
+// newIdempotencyKey returns a random key for HeaderIdempotencyKey.
+func newIdempotencyKey() string {
+	var key [16]byte
+
+	if _, err := rand.Read(key[:]); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(key[:])
+}
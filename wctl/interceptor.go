@@ -0,0 +1,270 @@
+package wctl
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
+	"github.com/valyala/fasthttp"
+)
+
+// errRetryableStatus has a request be retried on yielding a retryable status.
+var errRetryableStatus = errors.New("retryable status")
+
+// Invoker performs an HTTP request, writing the result into res.
+type Invoker func(req *fasthttp.Request, res *fasthttp.Response) error
+
+// Interceptor wraps the invocation of every HTTP request made by a Client.
+// It may modify req before calling next, inspect res after calling next,
+// call next multiple times, or not call next at all.
+type Interceptor func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error
+
+// chainInterceptors composes interceptors around invoker, with the first
+// interceptor being the outermost.
+func chainInterceptors(invoker Invoker, interceptors ...Interceptor) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+
+		invoker = func(req *fasthttp.Request, res *fasthttp.Response) error {
+			return interceptor(req, res, next)
+		}
+	}
+
+	return invoker
+}
+
+// HeaderInterceptor sets the given headers on every request.
+func HeaderInterceptor(headers map[string]string) Interceptor {
+	return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		return next(req, res)
+	}
+}
+
+// AuthInterceptor authenticates every request with the given bearer token,
+// overriding Config.APISecret.
+func AuthInterceptor(token func() string) Interceptor {
+	return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+		req.Header.Set("Authorization", "Bearer "+token())
+		return next(req, res)
+	}
+}
+
+// RefreshingAuthInterceptor authenticates every request with a bearer token
+// obtained from refresh, overriding Config.APISecret. The token is refreshed
+// once older than interval, should interval be positive, and whenever the
+// node rejects it, in which case the request is retried once with the
+// refreshed token.
+func RefreshingAuthInterceptor(refresh func() (string, error), interval time.Duration) Interceptor {
+	var (
+		lock      sync.Mutex
+		token     string
+		refreshed time.Time
+	)
+
+	// current returns the token to authenticate with, refreshing it should it
+	// be older than interval, or be the rejected token.
+	current := func(rejected *string) (string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		fresh := !refreshed.IsZero() && (interval <= 0 || time.Since(refreshed) < interval)
+		if fresh && (rejected == nil || *rejected != token) {
+			return token, nil
+		}
+
+		t, err := refresh()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to refresh auth token")
+		}
+
+		token, refreshed = t, time.Now()
+
+		return token, nil
+	}
+
+	return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+		t, err := current(nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+t)
+
+		// Nodes reject bearer tokens with a challenge, unlike other
+		// unauthorized requests such as those with invalid signatures.
+		err = next(req, res)
+		if err != nil || res.StatusCode() != http.StatusUnauthorized || len(res.Header.Peek("WWW-Authenticate")) == 0 {
+			return err
+		}
+
+		if t, err = current(&t); err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+t)
+		res.Reset()
+
+		return next(req, res)
+	}
+}
+
+// SignatureInterceptor signs the JSON body of every request which has one
+// with signer, as the canonical JSON of the given domain. The signature is
+// carried in the headers of package canonical, for the node to verify.
+func SignatureInterceptor(signer security.Signer, domain string) Interceptor {
+	return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+		if body := req.Body(); len(body) > 0 {
+			signature, err := canonical.SignJSON(signer, domain, body)
+			if err != nil {
+				return err
+			}
+
+			req.Header.Set(canonical.HeaderPublicKey, hex.EncodeToString(signer.PublicKey()))
+			req.Header.Set(canonical.HeaderSignature, hex.EncodeToString(signature))
+			req.Header.Set(canonical.HeaderScheme, strconv.Itoa(int(signer.Scheme())))
+		}
+
+		return next(req, res)
+	}
+}
+
+// Hooks are callbacks observing every request made by a Client, such as to
+// propagate traces, log requests or record their latencies, without writing
+// an Interceptor. See HookInterceptor.
+type Hooks struct {
+	// OnRequest, if set, is called before a request is sent, and may modify
+	// it, such as to set tracing headers.
+	OnRequest func(req *fasthttp.Request)
+
+	// OnResponse, if set, is called once a request completed, err being
+	// non-nil should it have failed to be delivered.
+	OnResponse func(req *fasthttp.Request, res *fasthttp.Response, err error, latency time.Duration)
+}
+
+// HookInterceptor calls hooks around every request. Requests retried by
+// interceptors further down the chain, such as BackoffInterceptor, are
+// observed once for all attempts.
+func HookInterceptor(hooks Hooks) Interceptor {
+	return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+		if hooks.OnRequest != nil {
+			hooks.OnRequest(req)
+		}
+
+		start := time.Now()
+		err := next(req, res)
+
+		if hooks.OnResponse != nil {
+			hooks.OnResponse(req, res, err, time.Since(start))
+		}
+
+		return err
+	}
+}
+
+// LogInterceptor logs the method, URI, status code and latency of every
+// request.
+func LogInterceptor(logger zerolog.Logger) Interceptor {
+	return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+		start := time.Now()
+		err := next(req, res)
+
+		ev := logger.Debug()
+		if err != nil {
+			ev = logger.Error().Err(err)
+		}
+
+		ev.
+			Bytes("method", req.Header.Method()).
+			Str("uri", req.URI().String()).
+			Int("status", res.StatusCode()).
+			Dur("latency", time.Since(start)).
+			Msg("Requested API.")
+
+		return err
+	}
+}
+
+// MetricsInterceptor records the latency of every request under the timer
+// "wctl.request.latency", and the rate of failed requests under the meter
+// "wctl.request.errors" of the given registry.
+func MetricsInterceptor(registry metrics.Registry) Interceptor {
+	latency := metrics.GetOrRegisterTimer("wctl.request.latency", registry)
+	errs := metrics.GetOrRegisterMeter("wctl.request.errors", registry)
+
+	return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+		start := time.Now()
+		err := next(req, res)
+
+		latency.UpdateSince(start)
+
+		if err != nil || res.StatusCode() >= http.StatusInternalServerError {
+			errs.Mark(1)
+		}
+
+		return err
+	}
+}
+
+// RetryInterceptor retries requests up to attempts times in total should
+// they fail to be delivered, or yield a 502, 503 or 504, waiting delay in
+// between each attempt.
+func RetryInterceptor(attempts int, delay time.Duration) Interceptor {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return BackoffInterceptor(Backoff{Initial: delay, MaxAttempts: attempts})
+}
+
+// BackoffInterceptor retries requests according to policy should they fail
+// to be delivered, or yield one of policy.RetryStatuses.
+func BackoffInterceptor(policy Backoff) Interceptor {
+	return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+		var retried bool
+
+		err := policy.Retry(func() error {
+			if retried {
+				res.Reset()
+			}
+
+			retried = true
+
+			if err := next(req, res); err != nil {
+				return err
+			}
+
+			if policy.retryableStatus(res.StatusCode()) {
+				return errRetryableStatus
+			}
+
+			return nil
+		})
+
+		// Have the caller handle the last response should it never succeed.
+		if err == errRetryableStatus {
+			return nil
+		}
+
+		return err
+	}
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,349 @@
+// +build unit
+
+package wctl
+
+import (
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/skademlia"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+// newTestClient creates a Client against a test HTTP server, without
+// connecting to the consensus websocket.
+func newTestClient(t *testing.T, handler http.HandlerFunc, interceptors ...Interceptor) (*Client, func()) {
+	srv := httptest.NewServer(handler)
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	c := &Client{
+		Config: Config{
+			APIHost:      host,
+			APIPort:      uint16(p),
+			Timeout:      time.Second,
+			Interceptors: interceptors,
+		},
+		url:          srv.URL,
+		interceptors: interceptors,
+	}
+
+	return c, srv.Close
+}
+
+func TestInterceptorChain(t *testing.T) {
+	var order []string
+
+	record := func(name string) Interceptor {
+		return func(req *fasthttp.Request, res *fasthttp.Response, next Invoker) error {
+			order = append(order, name+":before")
+			err := next(req, res)
+			order = append(order, name+":after")
+
+			return err
+		}
+	}
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "bar", r.Header.Get("X-Foo"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		_, _ = w.Write([]byte("ok"))
+	},
+		record("a"),
+		record("b"),
+		HeaderInterceptor(map[string]string{"X-Foo": "bar"}),
+		AuthInterceptor(func() string { return "token" }),
+	)
+	defer stop()
+
+	res, err := c.Request("/", ReqGet, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(res))
+	assert.Equal(t, []string{"a:before", "b:before", "b:after", "a:after"}, order)
+}
+
+func TestHookInterceptor(t *testing.T) {
+	var (
+		statuses []int
+		latency  time.Duration
+	)
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+
+		if r.Header.Get("X-Trace-Id") != "trace" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}, HookInterceptor(Hooks{
+		OnRequest: func(req *fasthttp.Request) {
+			req.Header.Set("X-Trace-Id", "trace")
+		},
+		OnResponse: func(req *fasthttp.Request, res *fasthttp.Response, err error, l time.Duration) {
+			assert.NoError(t, err)
+			assert.Equal(t, "trace", string(req.Header.Peek("X-Trace-Id")))
+
+			statuses = append(statuses, res.StatusCode())
+			latency = l
+		},
+	}))
+	defer stop()
+
+	res, err := c.Request("/", ReqGet, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(res))
+
+	assert.Equal(t, []int{http.StatusOK}, statuses)
+	assert.True(t, latency >= 5*time.Millisecond, latency)
+
+	// Either hook may be left unset.
+	c, stop = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}, HookInterceptor(Hooks{}))
+	defer stop()
+
+	_, err = c.Request("/", ReqGet, nil)
+	assert.NoError(t, err)
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	attempts := 0
+
+	registry := metrics.NewRegistry()
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}, RetryInterceptor(3, time.Millisecond), MetricsInterceptor(registry))
+	defer stop()
+
+	res, err := c.Request("/", ReqGet, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(res))
+	assert.Equal(t, 3, attempts)
+
+	assert.EqualValues(t, 3, metrics.GetOrRegisterTimer("wctl.request.latency", registry).Count())
+	assert.EqualValues(t, 2, metrics.GetOrRegisterMeter("wctl.request.errors", registry).Count())
+
+	attempts = -10
+
+	_, err = c.Request("/", ReqGet, nil)
+	assert.Error(t, err)
+	assert.Equal(t, -7, attempts)
+}
+
+func TestBackoffInterceptorRetryStatuses(t *testing.T) {
+	attempts := 0
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if attempts < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}
+
+	c, stop := newTestClient(t, handler, BackoffInterceptor(Backoff{
+		Initial:       time.Millisecond,
+		MaxAttempts:   3,
+		RetryStatuses: []int{http.StatusTooManyRequests},
+	}))
+	defer stop()
+
+	res, err := c.Request("/", ReqGet, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(res))
+	assert.Equal(t, 3, attempts)
+
+	// 429s are not retried by default.
+	attempts = 0
+
+	c, stop = newTestClient(t, handler, BackoffInterceptor(Backoff{Initial: time.Millisecond, MaxAttempts: 3}))
+	defer stop()
+
+	_, err = c.Request("/", ReqGet, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestBroadcastTransactionIdempotencyKey(t *testing.T) {
+	var keys []string
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(HeaderIdempotencyKey))
+
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":"` + hex.EncodeToString(make([]byte, 32)) + `"}`))
+	}, RetryInterceptor(2, time.Millisecond))
+	defer stop()
+
+	_, err := c.BroadcastTransaction(&TxRequest{})
+	assert.NoError(t, err)
+
+	// Retries carry the key of the first attempt.
+	if assert.Len(t, keys, 2) {
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1])
+	}
+
+	_, err = c.BroadcastTransaction(&TxRequest{})
+	assert.NoError(t, err)
+
+	if assert.Len(t, keys, 3) {
+		assert.NotEqual(t, keys[0], keys[2])
+	}
+}
+
+func TestRefreshingAuthInterceptor(t *testing.T) {
+	var (
+		secret    = "b"
+		attempts  int
+		refreshes int
+		tokens    = []string{"a", "b", "c"}
+	)
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if r.Header.Get("Authorization") != "Bearer "+secret {
+			w.Header().Set("WWW-Authenticate", "Bearer realm=Restricted")
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}, RefreshingAuthInterceptor(func() (string, error) {
+		refreshes++
+		return tokens[refreshes-1], nil
+	}, 0))
+	defer stop()
+
+	// The first token is rejected, and the request retried with the next.
+	res, err := c.Request("/", ReqGet, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(res))
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 2, refreshes)
+
+	// Accepted tokens are reused.
+	_, err = c.Request("/", ReqGet, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 2, refreshes)
+
+	// Requests are only retried once.
+	secret = "d"
+
+	_, err = c.Request("/", ReqGet, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 5, attempts)
+	assert.Equal(t, 3, refreshes)
+}
+
+func TestRefreshingAuthInterceptorInterval(t *testing.T) {
+	refreshes := 0
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("ok"))
+	}, RefreshingAuthInterceptor(func() (string, error) {
+		refreshes++
+		return "token", nil
+	}, time.Nanosecond))
+	defer stop()
+
+	for i := 0; i < 3; i++ {
+		_, err := c.Request("/", ReqGet, nil)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, refreshes)
+}
+
+func TestSignatureInterceptor(t *testing.T) {
+	keys, err := skademlia.NewKeys(1, 1)
+	assert.NoError(t, err)
+
+	signer := security.NewEd25519Signer(keys.PrivateKey())
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		publicKey, err := hex.DecodeString(r.Header.Get(canonical.HeaderPublicKey))
+		assert.NoError(t, err)
+
+		signature, err := hex.DecodeString(r.Header.Get(canonical.HeaderSignature))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "0", r.Header.Get(canonical.HeaderScheme))
+		assert.NoError(t, canonical.VerifyJSON(security.SchemeEd25519, publicKey, "test", body, signature))
+
+		_, _ = w.Write([]byte("ok"))
+	}, SignatureInterceptor(signer, "test"))
+	defer stop()
+
+	res, err := c.Request("/", ReqPost, []byte(`{"b": 2, "a": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(res))
+
+	// Bodies which are not JSON may not be signed.
+	_, err = c.Request("/", ReqPost, []byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestWithTraceparent(t *testing.T) {
+	var traceparents []string
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get(HeaderTraceparent))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	defer stop()
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	_, err := c.RequestCtx(WithTraceparent(context.Background(), traceparent), "/", ReqGet, nil)
+	assert.NoError(t, err)
+
+	_, err = c.Request("/", ReqGet, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{traceparent, ""}, traceparents)
+}
@@ -0,0 +1,43 @@
+package wctl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/security"
+)
+
+// LoadKeyFile loads a private key from the file at path, being either a JSON
+// key file or a key encrypted by package security, which are decrypted with
+// passphrase, or a hex-encoded private key.
+func LoadKeyFile(path string, passphrase []byte) (edwards25519.PrivateKey, error) {
+	var key edwards25519.PrivateKey
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return key, err
+	}
+
+	switch {
+	case security.IsKeyFile(buf):
+		return security.DecryptKeyFile(buf, passphrase)
+	case security.IsEncrypted(buf):
+		return security.DecryptKey(buf, passphrase)
+	}
+
+	raw := strings.TrimSpace(string(buf))
+
+	if hex.DecodedLen(len(raw)) != edwards25519.SizePrivateKey {
+		return key, fmt.Errorf("private key in %s must be %d hex characters", path,
+			hex.EncodedLen(edwards25519.SizePrivateKey))
+	}
+
+	if _, err := hex.Decode(key[:], []byte(raw)); err != nil {
+		return key, fmt.Errorf("private key in %s must be hex-encoded: %v", path, err)
+	}
+
+	return key, nil
+}
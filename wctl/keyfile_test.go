@@ -0,0 +1,62 @@
+// +build unit
+
+package wctl
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadKeyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	keyFile, err := security.EncryptKeyFile(key, []byte("passphrase"), security.ScryptParams{N: 16, R: 8, P: 1})
+	assert.NoError(t, err)
+
+	encrypted, err := security.EncryptKey(key, []byte("passphrase"), security.Params{Time: 1, Memory: 64, Threads: 1})
+	assert.NoError(t, err)
+
+	files := map[string][]byte{
+		"key.json": keyFile,
+		"key.key":  encrypted,
+		"key.txt":  []byte(hex.EncodeToString(key[:]) + "\n"),
+	}
+
+	for name, buf := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, ioutil.WriteFile(path, buf, 0600))
+
+		loaded, err := LoadKeyFile(path, []byte("passphrase"))
+		assert.NoError(t, err, name)
+		assert.Equal(t, key, loaded, name)
+	}
+
+	_, err = LoadKeyFile(filepath.Join(dir, "key.json"), []byte("wrong"))
+	assert.Equal(t, security.ErrDecrypt, err)
+
+	_, err = LoadKeyFile(filepath.Join(dir, "missing"), nil)
+	assert.Error(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "short.txt"), []byte("abcd"), 0600))
+
+	_, err = LoadKeyFile(filepath.Join(dir, "short.txt"), nil)
+	assert.Error(t, err)
+
+	// Clients fail to be created should their key file fail to load.
+	_, err = NewClient(Config{APIHost: "127.0.0.1", KeyFile: filepath.Join(dir, "key.json"), Passphrase: []byte("wrong")})
+	assert.Equal(t, security.ErrDecrypt, err)
+}
@@ -1,17 +1,44 @@
 package wctl
 
 import (
+	"context"
+	"strconv"
+	"time"
+
 	"github.com/valyala/fastjson"
 )
 
-var _ UnmarshalableJSON = (*LedgerStatusResponse)(nil)
+var (
+	_ UnmarshalableJSON = (*LedgerStatusResponse)(nil)
+	_ UnmarshalableJSON = (*SnowballParams)(nil)
+)
 
 // GetLedgerStatus calls the /ledger endpoint of the API. All arguments are
 // optional.
 func (c *Client) LedgerStatus() (*LedgerStatusResponse, error) {
+	return c.LedgerStatusCtx(context.Background())
+}
+
+// LedgerStatusCtx is LedgerStatus, which gives up once ctx is done.
+func (c *Client) LedgerStatusCtx(ctx context.Context) (*LedgerStatusResponse, error) {
 	var res LedgerStatusResponse
 
-	if err := c.RequestJSON(RouteLedger, ReqGet, nil, &res); err != nil {
+	if err := c.RequestJSONCtx(ctx, RouteLedger, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// LedgerStatusAt calls the /ledger endpoint of the API for the ledger as of
+// the block with index round, which archival nodes keep. Only the block and
+// the number of accounts are reported.
+func (c *Client) LedgerStatusAt(round uint64) (*LedgerStatusResponse, error) {
+	var res LedgerStatusResponse
+
+	path := RouteLedger + "?round=" + strconv.FormatUint(round, 10)
+
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
 		return nil, err
 	}
 
@@ -36,6 +63,20 @@ type LedgerStatusResponse struct {
 	NumTxInStore uint64 `json:"num_tx_in_store"`
 	AccountsLen  uint64 `json:"num_accounts_in_store"`
 
+	StampDifficulty int `json:"stamp_difficulty"`
+
+	// Archival is whether the node keeps the state of the ledger as of every
+	// block it finalizes.
+	Archival bool `json:"archival"`
+
+	// Features of the protocol applying to the next block to be finalized.
+	Features []string `json:"features"`
+
+	// Snowball consensus protocol parameters the node currently runs with.
+	Snowball SnowballParams `json:"snowball"`
+
+	Pruning PruningStatus `json:"pruning"`
+
 	Preferred *struct {
 		MerkleRoot [16]byte `json:"merkle_root"`
 		Index      uint64   `json:"height"`
@@ -48,6 +89,47 @@ type LedgerStatusResponse struct {
 	Peers []Peer `json:"peers"`
 }
 
+// PruningStatus reports on the pruning of the data the node keeps of past
+// blocks.
+type PruningStatus struct {
+	Enabled             bool          `json:"enabled"`
+	RetainedBlocks      uint64        `json:"retained_blocks"`
+	RetainedFrom        uint64        `json:"retained_from"`
+	NumPrunedDiffs      uint64        `json:"num_pruned_diffs"`
+	CompactInterval     time.Duration `json:"compact_interval"`
+	NumCompactions      uint64        `json:"num_compactions"`
+	LastCompaction      time.Time     `json:"last_compaction_at"`
+	LastCompactionError string        `json:"last_compaction_error"`
+}
+
+// SnowballParams are the parameters of the Snowball consensus protocol, being
+// the number of peers K queried, the fraction Alpha of them which must agree,
+// and the number of consecutive queries Beta which must agree.
+type SnowballParams struct {
+	K     int     `json:"k"`
+	Alpha float64 `json:"alpha"`
+	Beta  int     `json:"beta"`
+}
+
+func (s *SnowballParams) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	s.unmarshalValue(v)
+
+	return nil
+}
+
+func (s *SnowballParams) unmarshalValue(v *fastjson.Value) {
+	s.K = v.GetInt("k")
+	s.Alpha = v.GetFloat64("alpha")
+	s.Beta = v.GetInt("beta")
+}
+
 type Peer struct {
 	Address   string   `json:"address"`
 	PublicKey [32]byte `json:"public_key"`
@@ -86,6 +168,17 @@ func (l *LedgerStatusResponse) UnmarshalJSON(b []byte) error {
 	l.NumTx = v.GetUint64("num_tx")
 	l.NumMissingTx = v.GetUint64("num_missing_tx")
 	l.NumTxInStore = v.GetUint64("num_tx_in_store")
+	l.StampDifficulty = v.GetInt("stamp_difficulty")
+	l.Archival = v.GetBool("archival")
+
+	l.Features = l.Features[:0]
+	for _, f := range v.GetArray("features") {
+		l.Features = append(l.Features, string(f.GetStringBytes()))
+	}
+
+	if snowball := v.Get("snowball"); snowball != nil {
+		l.Snowball.unmarshalValue(snowball)
+	}
 
 	if v.Exists("preferred") && v.Get("preferred").Type() != fastjson.TypeNull {
 		l.Preferred = &struct {
@@ -110,6 +203,33 @@ func (l *LedgerStatusResponse) UnmarshalJSON(b []byte) error {
 
 	l.PreferredVotes = v.GetInt("preferred_votes")
 
+	if pruning := v.Get("pruning"); pruning != nil {
+		l.Pruning.Enabled = pruning.GetBool("enabled")
+		l.Pruning.RetainedBlocks = pruning.GetUint64("retained_blocks")
+		l.Pruning.RetainedFrom = pruning.GetUint64("retained_from")
+		l.Pruning.NumPrunedDiffs = pruning.GetUint64("num_pruned_diffs")
+		l.Pruning.NumCompactions = pruning.GetUint64("num_compactions")
+		l.Pruning.LastCompactionError = string(pruning.GetStringBytes("last_compaction_error"))
+
+		if s := pruning.GetStringBytes("compact_interval"); len(s) > 0 {
+			interval, err := time.ParseDuration(string(s))
+			if err != nil {
+				return err
+			}
+
+			l.Pruning.CompactInterval = interval
+		}
+
+		if s := pruning.GetStringBytes("last_compaction_at"); len(s) > 0 {
+			at, err := time.Parse(time.RFC3339, string(s))
+			if err != nil {
+				return err
+			}
+
+			l.Pruning.LastCompaction = at
+		}
+	}
+
 	peerValue := v.GetArray("peers")
 	l.Peers = make([]Peer, len(peerValue))
 
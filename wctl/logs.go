@@ -0,0 +1,88 @@
+package wctl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*ContractLogList)(nil)
+
+// ContractLogList is a page of the logs of a smart contract. Neither the
+// time nor the message of the logs are set.
+type ContractLogList struct {
+	// Total is the number of logs the contract has emitted overall.
+	Total uint64        `json:"total"`
+	Logs  []ContractLog `json:"logs"`
+}
+
+func (l *ContractLogList) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	l.Total = v.GetUint64("total")
+	l.Logs = l.Logs[:0]
+
+	for _, o := range v.GetArray("logs") {
+		log := ContractLog{
+			Block: o.GetUint64("block"),
+			Index: o.GetUint64("index"),
+		}
+
+		if err := jsonHex(o, log.TxID[:], "tx_id"); err != nil {
+			return err
+		}
+
+		if log.Topic, err = hex.DecodeString(string(o.GetStringBytes("topic"))); err != nil {
+			return errUnmarshalFail(o, "topic", err)
+		}
+
+		if log.Data, err = hex.DecodeString(string(o.GetStringBytes("data"))); err != nil {
+			return errUnmarshalFail(o, "data", err)
+		}
+
+		l.Logs = append(l.Logs, log)
+	}
+
+	return nil
+}
+
+// ContractLogs calls the /contract/<id>/logs endpoint of the API, returning
+// up to limit logs of the smart contract starting from offset. Should
+// fromBlock be non-zero, offset counts from the first log emitted in or after
+// block fromBlock. The node caps the limit should none be given.
+func (c *Client) ContractLogs(contract [32]byte, offset, limit, fromBlock uint64) (*ContractLogList, error) {
+	vals := url.Values{}
+
+	if offset != 0 {
+		vals.Set("offset", strconv.FormatUint(offset, 10))
+	}
+
+	if limit != 0 {
+		vals.Set("limit", strconv.FormatUint(limit, 10))
+	}
+
+	if fromBlock != 0 {
+		vals.Set("from_block", strconv.FormatUint(fromBlock, 10))
+	}
+
+	path := fmt.Sprintf("%s/%x/logs?%s", RouteContract, contract, vals.Encode())
+
+	var res ContractLogList
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	for i := range res.Logs {
+		res.Logs[i].ContractID = contract
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,95 @@
+package wctl
+
+import (
+	"encoding/hex"
+	"net/url"
+	"strconv"
+
+	"github.com/valyala/fastjson"
+)
+
+var (
+	_ UnmarshalableJSON = (*MempoolCount)(nil)
+	_ UnmarshalableJSON = (*mempoolContains)(nil)
+)
+
+// MempoolCount counts the transactions pending finalization on a node, and
+// those the node is looking to pull from its peers.
+type MempoolCount struct {
+	Count   uint64 `json:"count"`
+	Missing uint64 `json:"missing"`
+}
+
+func (m *MempoolCount) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	m.Count = v.GetUint64("count")
+	m.Missing = v.GetUint64("missing")
+
+	return nil
+}
+
+type mempoolContains struct {
+	contains bool
+}
+
+func (m *mempoolContains) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	m.contains = v.GetBool("contains")
+
+	return nil
+}
+
+// ListMempool lists a page of the transactions pending finalization on the
+// node, in the order they would be proposed in. A zero limit lists as many
+// as the node allows.
+func (c *Client) ListMempool(offset, limit uint64) ([]Transaction, error) {
+	vals := url.Values{}
+
+	if offset != 0 {
+		vals.Set("offset", strconv.FormatUint(offset, 10))
+	}
+
+	if limit != 0 {
+		vals.Set("limit", strconv.FormatUint(limit, 10))
+	}
+
+	var res TransactionList
+	if err := c.RequestJSON(RouteMempool+"?"+vals.Encode(), ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CountMempool counts the transactions pending finalization on the node.
+func (c *Client) CountMempool() (*MempoolCount, error) {
+	var res MempoolCount
+	if err := c.RequestJSON(RouteMempool+"/count", ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// MempoolContains returns whether the transaction with ID id is pending
+// finalization on the node.
+func (c *Client) MempoolContains(id [32]byte) (bool, error) {
+	var res mempoolContains
+	if err := c.RequestJSON(RouteMempool+"/contains/"+hex.EncodeToString(id[:]), ReqGet, nil, &res); err != nil {
+		return false, err
+	}
+
+	return res.contains, nil
+}
@@ -0,0 +1,37 @@
+// +build unit
+
+package wctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientMempool(t *testing.T) {
+	cfg, stop := fakeNode(t, time.Second)
+	defer stop()
+
+	c, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	defer c.Close()
+
+	txs, err := c.ListMempool(0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+
+	count, err := c.CountMempool()
+	require.NoError(t, err)
+	assert.Equal(t, MempoolCount{Count: 2, Missing: 1}, *count)
+
+	contains, err := c.MempoolContains([32]byte{})
+	require.NoError(t, err)
+	assert.True(t, contains)
+
+	contains, err = c.MempoolContains([32]byte{1})
+	require.NoError(t, err)
+	assert.False(t, contains)
+}
@@ -0,0 +1,71 @@
+package wctl
+
+import (
+	"encoding/hex"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*NameRecord)(nil)
+
+// NameRecord is a name registered with the name service.
+type NameRecord struct {
+	Name   string   `json:"name"`
+	Owner  [32]byte `json:"owner"`
+	Target [32]byte `json:"target"`
+	Expiry uint64   `json:"expiry"`
+}
+
+func (n *NameRecord) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	n.Name = string(v.GetStringBytes("name"))
+	n.Expiry = v.GetUint64("expiry")
+
+	if err := jsonHex(v, n.Owner[:], "owner"); err != nil {
+		return err
+	}
+
+	return jsonHex(v, n.Target[:], "target")
+}
+
+// GetName calls the /name endpoint of the API.
+func (c *Client) GetName(name string) (*NameRecord, error) {
+	var res NameRecord
+	if err := c.RequestJSON(RouteName+"/"+name, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// Resolve returns the ID address refers to, which is either a hex-encoded ID
+// or a name registered with the name service.
+func (c *Client) Resolve(address string) ([32]byte, error) {
+	var id [32]byte
+
+	if len(address) == hex.EncodedLen(len(id)) {
+		if _, err := hex.Decode(id[:], []byte(address)); err != nil {
+			return id, err
+		}
+
+		return id, nil
+	}
+
+	if err := wavelet.ValidateName(address); err != nil {
+		return id, err
+	}
+
+	record, err := c.GetName(address)
+	if err != nil {
+		return id, err
+	}
+
+	return record.Target, nil
+}
@@ -0,0 +1,39 @@
+// +build unit
+
+package wctl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientResolve(t *testing.T) {
+	cfg, stop := fakeNode(t, time.Second)
+	defer stop()
+
+	c, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	defer c.Close()
+
+	target := [32]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+
+	id, err := c.Resolve("alice")
+	require.NoError(t, err)
+	assert.Equal(t, target, id)
+
+	// IDs resolve to themselves without querying the name service.
+	id, err = c.Resolve(strings.Repeat("01", 32))
+	require.NoError(t, err)
+	assert.Equal(t, target, id)
+
+	_, err = c.Resolve("bob")
+	assert.Error(t, err)
+
+	_, err = c.Resolve("Not A Name")
+	assert.Error(t, err)
+}
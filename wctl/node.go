@@ -1,6 +1,7 @@
 package wctl
 
 import (
+	"github.com/pkg/errors"
 	"github.com/valyala/fastjson"
 )
 
@@ -14,7 +15,7 @@ func (c *Client) Connect(address string) (*MsgResponse, error) {
 	j := jsonRaw(o.MarshalTo(nil))
 
 	var resp MsgResponse
-	if err := c.RequestJSON(RouteConnect, ReqPost, j, &resp); err != nil {
+	if err := c.adminRequestJSON(RouteConnect, ReqPost, j, &resp); err != nil {
 		return nil, err
 	}
 
@@ -31,7 +32,7 @@ func (c *Client) Disconnect(address string) (*MsgResponse, error) {
 	j := jsonRaw(o.MarshalTo(nil))
 
 	var resp MsgResponse
-	if err := c.RequestJSON(RouteDisconnect, ReqPost, j, &resp); err != nil {
+	if err := c.adminRequestJSON(RouteDisconnect, ReqPost, j, &resp); err != nil {
 		return nil, err
 	}
 
@@ -53,9 +54,128 @@ func (c *Client) Restart(hard bool) (*MsgResponse, error) {
 	j := jsonRaw(o.MarshalTo(nil))
 
 	var resp MsgResponse
-	if err := c.RequestJSON(RouteRestart, ReqPost, j, &resp); err != nil {
+	if err := c.adminRequestJSON(RouteRestart, ReqPost, j, &resp); err != nil {
 		return nil, err
 	}
 
 	return &resp, nil
 }
+
+// Prune calls the /node/prune endpoint of the API, having the node prune the
+// data of past blocks and compact its database right away.
+func (c *Client) Prune() (*MsgResponse, error) {
+	var resp MsgResponse
+	if err := c.adminRequestJSON(RoutePrune, ReqPost, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UpdateSnowball calls the /node/snowball endpoint of the API, adjusting the
+// Snowball consensus protocol parameters the node runs with. Parameters left
+// zero are kept as they are. The parameters the node runs with afterwards are
+// returned.
+func (c *Client) UpdateSnowball(params SnowballParams) (*SnowballParams, error) {
+	var arena fastjson.Arena
+
+	o := arena.NewObject()
+
+	if params.K != 0 {
+		o.Set("k", arena.NewNumberInt(params.K))
+	}
+
+	if params.Alpha != 0 {
+		o.Set("alpha", arena.NewNumberFloat64(params.Alpha))
+	}
+
+	if params.Beta != 0 {
+		o.Set("beta", arena.NewNumberInt(params.Beta))
+	}
+
+	j := jsonRaw(o.MarshalTo(nil))
+
+	var res SnowballParams
+	if err := c.adminRequestJSON(RouteSnowball, ReqPost, j, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+var _ UnmarshalableJSON = (*LogLevels)(nil)
+
+// LogLevels maps the modules of a node, such as "tx" or "consensus", to the
+// minimum level of the log entries they write, such as "debug" or "warn".
+type LogLevels map[string]string
+
+func (l *LogLevels) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	o, err := v.Object()
+	if err != nil {
+		return err
+	}
+
+	levels := make(LogLevels)
+
+	o.Visit(func(key []byte, val *fastjson.Value) {
+		if err != nil {
+			return
+		}
+
+		var b []byte
+		if b, err = val.StringBytes(); err != nil {
+			err = errors.Wrapf(err, "level of %q", key)
+			return
+		}
+
+		levels[string(key)] = string(b)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	*l = levels
+
+	return nil
+}
+
+// LogLevels calls the /node/loglevels endpoint of the API, returning the log
+// level of every module of the node.
+func (c *Client) LogLevels() (LogLevels, error) {
+	var res LogLevels
+	if err := c.adminRequestJSON(RouteLogLevels, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateLogLevels calls the /node/loglevels endpoint of the API, setting the
+// log levels of the modules keyed in levels at runtime. The levels of all
+// modules of the node afterwards are returned.
+func (c *Client) UpdateLogLevels(levels LogLevels) (LogLevels, error) {
+	var arena fastjson.Arena
+
+	o := arena.NewObject()
+
+	for module, level := range levels {
+		o.Set(module, arena.NewString(level))
+	}
+
+	j := jsonRaw(o.MarshalTo(nil))
+
+	var res LogLevels
+	if err := c.adminRequestJSON(RouteLogLevels, ReqPost, j, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
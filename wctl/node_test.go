@@ -0,0 +1,183 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fastjson"
+)
+
+func TestClientSnowball(t *testing.T) {
+	params := SnowballParams{K: 2, Alpha: 0.8, Beta: 150}
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RouteLedger:
+			_, _ = fmt.Fprintf(w,
+				`{"public_key":"%s","block":{"merkle_root":"%s","height":1,"id":"%s"},`+
+					`"snowball":{"k":%d,"alpha":%g,"beta":%d},"peers":[]}`,
+				strings.Repeat("00", 32), strings.Repeat("00", 16), strings.Repeat("00", 32),
+				params.K, params.Alpha, params.Beta,
+			)
+		case RouteSnowball:
+			body, _ := ioutil.ReadAll(r.Body)
+
+			v, err := fastjson.ParseBytes(body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			// Parameters left out are kept as they are.
+			if v.Exists("k") {
+				params.K = v.GetInt("k")
+			}
+
+			if v.Exists("alpha") {
+				params.Alpha = v.GetFloat64("alpha")
+			}
+
+			if v.Exists("beta") {
+				params.Beta = v.GetInt("beta")
+			}
+
+			_, _ = fmt.Fprintf(w, `{"k":%d,"alpha":%g,"beta":%d}`, params.K, params.Alpha, params.Beta)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer stop()
+
+	status, err := c.LedgerStatus()
+	require.NoError(t, err)
+	assert.Equal(t, SnowballParams{K: 2, Alpha: 0.8, Beta: 150}, status.Snowball)
+
+	res, err := c.UpdateSnowball(SnowballParams{K: 10, Alpha: 0.9})
+	require.NoError(t, err)
+	assert.Equal(t, SnowballParams{K: 10, Alpha: 0.9, Beta: 150}, *res)
+
+	status, err = c.LedgerStatus()
+	require.NoError(t, err)
+	assert.Equal(t, *res, status.Snowball)
+}
+
+func TestClientLogLevels(t *testing.T) {
+	levels := LogLevels{"node": "debug", "tx": "debug"}
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == RouteLedger:
+			_, _ = fmt.Fprintf(w,
+				`{"public_key":"%s","block":{"merkle_root":"%s","height":1,"id":"%s"},"peers":[]}`,
+				strings.Repeat("00", 32), strings.Repeat("00", 16), strings.Repeat("00", 32),
+			)
+			return
+		case r.URL.Path != RouteLogLevels:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.Method == http.MethodPost:
+			body, _ := ioutil.ReadAll(r.Body)
+
+			v, err := fastjson.ParseBytes(body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			v.GetObject().Visit(func(key []byte, val *fastjson.Value) {
+				levels[string(key)] = string(val.GetStringBytes())
+			})
+		}
+
+		_, _ = fmt.Fprintf(w, `{"node":%q,"tx":%q}`, levels["node"], levels["tx"])
+	})
+	defer stop()
+
+	res, err := c.LogLevels()
+	require.NoError(t, err)
+	assert.Equal(t, LogLevels{"node": "debug", "tx": "debug"}, res)
+
+	// Modules left out are kept as they are.
+	res, err = c.UpdateLogLevels(LogLevels{"tx": "warn"})
+	require.NoError(t, err)
+	assert.Equal(t, LogLevels{"node": "debug", "tx": "warn"}, res)
+}
+
+func TestClientHealth(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		consensus := `"consensus":{"ok":true,"block_index":7,"round_duration":"1.5s","max_stall":"2m0s"}`
+
+		switch r.URL.Path {
+		case RouteHealthz:
+			_, _ = fmt.Fprintf(w, `{"status":"ok","checks":{"storage":{"ok":true,"latency":"1ms"},%s}}`, consensus)
+		case RouteReadyz:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = fmt.Fprintf(w, `{"status":"unavailable","checks":{"storage":{"ok":true,"latency":"1ms"},%s,`+
+				`"peers":{"ok":false,"num_peers":0,"min_peers":1},`+
+				`"sync":{"ok":false,"reconciled":false,"syncing":false,"lag":0,"max_lag":0}}}`, consensus)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer stop()
+
+	health, err := c.Health()
+	require.NoError(t, err)
+	assert.True(t, health.OK())
+	assert.Equal(t, StorageHealth{OK: true, Latency: time.Millisecond}, health.Checks.Storage)
+	assert.Equal(t, ConsensusHealth{
+		OK: true, BlockIndex: 7, RoundDuration: 1500 * time.Millisecond, MaxStall: 2 * time.Minute,
+	}, health.Checks.Consensus)
+	assert.Nil(t, health.Checks.Peers)
+	assert.Nil(t, health.Checks.Sync)
+
+	// Nodes which are not ready respond with 503, alongside why.
+	health, err = c.Ready()
+	require.NoError(t, err)
+	assert.False(t, health.OK())
+	assert.Equal(t, &PeersHealth{MinPeers: 1}, health.Checks.Peers)
+	assert.Equal(t, &SyncHealth{}, health.Checks.Sync)
+}
+
+func TestClientAdmin(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer stop()
+
+	admin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer admin" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, `{"node":"info"}`)
+	}))
+	defer admin.Close()
+
+	// Privileged requests are made to the API should there be no admin API.
+	_, err := c.LogLevels()
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*APIError).StatusCode)
+
+	c.adminURL = admin.URL
+
+	_, err = c.LogLevels()
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*APIError).StatusCode)
+
+	c.AdminToken = "admin"
+
+	res, err := c.LogLevels()
+	require.NoError(t, err)
+	assert.Equal(t, LogLevels{"node": "info"}, res)
+}
@@ -0,0 +1,151 @@
+package wctl
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*PeerScores)(nil)
+
+// PeerScores is the reputation of the peers a node is connected to or has
+// scored, alongside the policy the node bans peers by.
+type PeerScores struct {
+	// BanScore is the score at or below which peers are banned, for
+	// BanDuration.
+	BanScore    float64       `json:"ban_score"`
+	BanDuration time.Duration `json:"ban_duration"`
+
+	// MaxRequestsPerSecond is the number of requests a peer may make every
+	// second before being penalized, or 0 should there be no limit.
+	MaxRequestsPerSecond uint64 `json:"max_requests_per_second"`
+
+	Peers []PeerScore `json:"peers"`
+}
+
+// PeerScore is the reputation of a peer. Peers start with a score of 0, which
+// drops with every protocol violation they commit and recovers over time.
+type PeerScore struct {
+	PublicKey [32]byte `json:"public_key"`
+	Address   string   `json:"address"`
+	Connected bool     `json:"connected"`
+
+	Score float64 `json:"score"`
+
+	// Latency is the moving average of the time taken by the peer to respond
+	// to queries of the node.
+	Latency time.Duration `json:"latency"`
+
+	// Violations counts the violations committed by the peer by their kind,
+	// such as "invalid_tx".
+	Violations map[string]uint64 `json:"violations"`
+
+	// BannedUntil is when the ban of the peer expires, nil should it not be
+	// banned.
+	BannedUntil *time.Time `json:"banned_until"`
+}
+
+func (p *PeerScores) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	p.BanScore = v.GetFloat64("ban_score")
+	p.MaxRequestsPerSecond = v.GetUint64("max_requests_per_second")
+
+	if p.BanDuration, err = time.ParseDuration(jsonString(v, "ban_duration")); err != nil {
+		return errUnmarshalFail(v, "ban_duration", err)
+	}
+
+	p.Peers = p.Peers[:0]
+
+	for _, o := range v.GetArray("peers") {
+		peer := PeerScore{
+			Address:    jsonString(o, "address"),
+			Connected:  o.GetBool("connected"),
+			Score:      o.GetFloat64("score"),
+			Violations: make(map[string]uint64),
+		}
+
+		if err := jsonHex(o, peer.PublicKey[:], "public_key"); err != nil {
+			return err
+		}
+
+		if peer.Latency, err = time.ParseDuration(jsonString(o, "latency")); err != nil {
+			return errUnmarshalFail(o, "latency", err)
+		}
+
+		if violations := o.GetObject("violations"); violations != nil {
+			violations.Visit(func(key []byte, v *fastjson.Value) {
+				peer.Violations[string(key)] = v.GetUint64()
+			})
+		}
+
+		if until := o.Get("banned_until"); until != nil && until.Type() == fastjson.TypeString {
+			var t time.Time
+
+			if err := jsonTime(o, &t, "banned_until"); err != nil {
+				return err
+			}
+
+			peer.BannedUntil = &t
+		}
+
+		p.Peers = append(p.Peers, peer)
+	}
+
+	return nil
+}
+
+// GetPeerScores calls the /node/peers endpoint of the API, returning the
+// reputation of the peers of the node.
+func (c *Client) GetPeerScores() (*PeerScores, error) {
+	var res PeerScores
+	if err := c.RequestJSON(RoutePeers, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// BanPeer calls the /node/peers/:id/ban endpoint of the API, banning the peer
+// with public key id for duration, or for the ban duration of the node should
+// duration be 0.
+func (c *Client) BanPeer(id [32]byte, duration time.Duration) (*MsgResponse, error) {
+	var j MarshalableJSON
+
+	if duration != 0 {
+		var arena fastjson.Arena
+
+		o := arena.NewObject()
+		o.Set("duration", arena.NewString(duration.String()))
+
+		j = jsonRaw(o.MarshalTo(nil))
+	}
+
+	var resp MsgResponse
+	if err := c.adminRequestJSON(peerBanRoute(id), ReqPost, j, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UnbanPeer calls the /node/peers/:id/ban endpoint of the API, lifting the ban
+// of the peer with public key id.
+func (c *Client) UnbanPeer(id [32]byte) (*MsgResponse, error) {
+	var resp MsgResponse
+	if err := c.adminRequestJSON(peerBanRoute(id), ReqDelete, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func peerBanRoute(id [32]byte) string {
+	return RoutePeers + "/" + hex.EncodeToString(id[:]) + "/ban"
+}
@@ -0,0 +1,89 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPeerScores(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RoutePeers {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"ban_score":-100,"ban_duration":"10m0s","max_requests_per_second":500,"peers":[`+
+			`{"public_key":"%s","address":"127.0.0.1:3000","connected":true,"score":-12.5,"latency":"25ms",`+
+			`"violations":{"invalid_tx":1,"invalid_block":0},"banned_until":null},`+
+			`{"public_key":"%s","address":"127.0.0.1:3001","connected":false,"score":-105,"latency":"0s",`+
+			`"violations":{"invalid_tx":0,"invalid_block":5},"banned_until":"2019-10-15T00:10:00Z"}]}`,
+			strings.Repeat("01", 32), strings.Repeat("02", 32))
+	})
+	defer stop()
+
+	res, err := c.GetPeerScores()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, -100, res.BanScore)
+	assert.Equal(t, 10*time.Minute, res.BanDuration)
+	assert.EqualValues(t, 500, res.MaxRequestsPerSecond)
+
+	if !assert.Len(t, res.Peers, 2) {
+		return
+	}
+
+	assert.Equal(t, byte(1), res.Peers[0].PublicKey[0])
+	assert.Equal(t, "127.0.0.1:3000", res.Peers[0].Address)
+	assert.True(t, res.Peers[0].Connected)
+	assert.EqualValues(t, -12.5, res.Peers[0].Score)
+	assert.Equal(t, 25*time.Millisecond, res.Peers[0].Latency)
+	assert.EqualValues(t, 1, res.Peers[0].Violations["invalid_tx"])
+	assert.Nil(t, res.Peers[0].BannedUntil)
+
+	assert.False(t, res.Peers[1].Connected)
+	assert.EqualValues(t, 5, res.Peers[1].Violations["invalid_block"])
+
+	if assert.NotNil(t, res.Peers[1].BannedUntil) {
+		assert.Equal(t, time.Date(2019, 10, 15, 0, 10, 0, 0, time.UTC), res.Peers[1].BannedUntil.UTC())
+	}
+}
+
+func TestClientBanPeer(t *testing.T) {
+	id := [32]byte{1}
+
+	var requests []string
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+		_, _ = fmt.Fprint(w, `{"msg":"ok"}`)
+	})
+	defer stop()
+
+	_, err := c.BanPeer(id, time.Hour)
+	require.NoError(t, err)
+
+	_, err = c.BanPeer(id, 0)
+	require.NoError(t, err)
+
+	_, err = c.UnbanPeer(id)
+	require.NoError(t, err)
+
+	path := RoutePeers + "/01" + strings.Repeat("00", 31) + "/ban"
+
+	assert.Equal(t, []string{
+		"POST " + path + ` {"duration":"1h0m0s"}`,
+		"POST " + path + " ",
+		"DELETE " + path + " ",
+	}, requests)
+}
@@ -0,0 +1,105 @@
+package wctl
+
+import (
+	"encoding/hex"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*AccountProof)(nil)
+
+// AccountProof proves the balance, stake, reward and gas balance of an account
+// against the Merkle root of a block. It is to be checked with VerifyProof
+// against a Merkle root known to be of a finalized block, as neither the block
+// nor the balances the node reports may be trusted otherwise.
+type AccountProof struct {
+	Block struct {
+		MerkleRoot [16]byte `json:"merkle_root"`
+		Index      uint64   `json:"height"`
+		ID         [32]byte `json:"id"`
+	} `json:"block"`
+
+	Proof wavelet.AccountProof `json:"-"`
+}
+
+func (p *AccountProof) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, p.Proof.ID[:], "id"); err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, p.Block.MerkleRoot[:], "block", "merkle_root"); err != nil {
+		return err
+	}
+
+	p.Block.Index = v.GetUint64("block", "height")
+
+	if err := jsonHex(v, p.Block.ID[:], "block", "id"); err != nil {
+		return err
+	}
+
+	fields := []struct {
+		key   string
+		proof *avl.Proof
+	}{
+		{"balance", &p.Proof.Balance},
+		{"stake", &p.Proof.Stake},
+		{"reward", &p.Proof.Reward},
+		{"gas_balance", &p.Proof.GasBalance},
+	}
+
+	for _, field := range fields {
+		var proof avl.Proof
+
+		for _, list := range []struct {
+			key string
+			dst *[][]byte
+		}{{"path", &proof.Path}, {"lefts", &proof.Lefts}} {
+			for _, item := range v.GetArray(field.key, list.key) {
+				buf, err := hex.DecodeString(string(item.GetStringBytes()))
+				if err != nil {
+					return errUnmarshalFail(v, field.key+"."+list.key, err)
+				}
+
+				if len(buf) == 0 {
+					buf = nil
+				}
+
+				*list.dst = append(*list.dst, buf)
+			}
+		}
+
+		*field.proof = proof
+	}
+
+	return nil
+}
+
+// GetAccountProof calls the /accounts/<id>/proof endpoint of the API, which
+// proves the balances of the account against the Merkle root of the latest
+// block whose state the node committed.
+func (c *Client) GetAccountProof(account [32]byte) (*AccountProof, error) {
+	path := RouteAccount + "/" + hex.EncodeToString(account[:]) + "/proof"
+
+	var res AccountProof
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// VerifyProof checks proof against root, being a Merkle root the caller
+// trusts, returning the balances of the account it proves. Balances the
+// account does not have set are proven to be zero.
+func VerifyProof(proof *AccountProof, root [16]byte) (wavelet.AccountBalances, error) {
+	return proof.Proof.Verify(root)
+}
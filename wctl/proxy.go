@@ -0,0 +1,118 @@
+package wctl
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// proxyFunc returns the function choosing the proxy requests to a URL are
+// made through, being Config.ProxyURL if set, or otherwise taken from the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func (config Config) proxyFunc() (func(*url.URL) (*url.URL, error), error) {
+	if config.ProxyURL == "" {
+		return httpproxy.FromEnvironment().ProxyFunc(), nil
+	}
+
+	proxyURL, err := url.Parse(config.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %v", config.ProxyURL, err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "socks5":
+	default:
+		return nil, fmt.Errorf("proxy URL %q must be of scheme http or socks5", config.ProxyURL)
+	}
+
+	return func(*url.URL) (*url.URL, error) {
+		return proxyURL, nil
+	}, nil
+}
+
+// proxyDial returns a dial function for fasthttp, dialing addresses through
+// the proxy chosen for them by proxyFunc, should there be any. Connections to
+// HTTP proxies are tunneled through CONNECT requests.
+func proxyDial(proxyFunc func(*url.URL) (*url.URL, error), scheme string, timeout time.Duration) fasthttp.DialFunc {
+	return func(addr string) (net.Conn, error) {
+		proxyURL, err := proxyFunc(&url.URL{Scheme: scheme, Host: addr})
+		if err != nil {
+			return nil, err
+		}
+
+		if proxyURL == nil {
+			return fasthttp.DialTimeout(addr, timeout)
+		}
+
+		if proxyURL.Scheme == "socks5" {
+			dialer, err := proxy.FromURL(proxyURL, &net.Dialer{Timeout: timeout})
+			if err != nil {
+				return nil, err
+			}
+
+			return dialer.Dial("tcp", addr)
+		}
+
+		return dialHTTPProxy(proxyURL, addr, timeout)
+	}
+}
+
+// dialHTTPProxy opens a tunnel to addr through the HTTP proxy at proxyURL.
+func dialHTTPProxy(proxyURL *url.URL, addr string, timeout time.Duration) (net.Conn, error) {
+	host := proxyURL.Host
+	if proxyURL.Port() == "" {
+		host = net.JoinHostPort(proxyURL.Hostname(), "80")
+	}
+
+	conn, err := net.DialTimeout("tcp", host, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	// The proxy sends nothing past its response until the tunnel is used,
+	// so the buffered reader may be discarded.
+	res, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	_ = res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", proxyURL.Host, addr, res.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return conn, nil
+}
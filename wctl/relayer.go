@@ -0,0 +1,88 @@
+package wctl
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*RelayerStats)(nil)
+
+// RelayerStats accounts for the transactions a relayer submitted through a
+// node since it started.
+type RelayerStats struct {
+	ID        [32]byte `json:"id"`
+	Submitted uint64   `json:"submitted"`
+	Accepted  uint64   `json:"accepted"`
+	Rejected  uint64   `json:"rejected"`
+
+	// Fees sums up the fees of the transactions accepted.
+	Fees uint64 `json:"fees"`
+}
+
+func (r *RelayerStats) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, r.ID[:], "id"); err != nil {
+		return err
+	}
+
+	r.Submitted = v.GetUint64("submitted")
+	r.Accepted = v.GetUint64("accepted")
+	r.Rejected = v.GetUint64("rejected")
+	r.Fees = v.GetUint64("fees")
+
+	return nil
+}
+
+// RelayTransaction submits req, a transaction signed by its sender, on the
+// sender's behalf. The request is signed with the client's private key, for
+// the node to account for the client as the relayer of the transaction.
+func (c *Client) RelayTransaction(req *TxRequest) (*TxResponse, error) {
+	body, err := req.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	signer := security.NewEd25519Signer(c.PrivateKey)
+
+	signature, err := canonical.SignJSON(signer, canonical.DomainRelay, body)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.request(context.Background(), RouteTxRelay, ReqPost, body, map[string]string{
+		canonical.HeaderPublicKey: hex.EncodeToString(signer.PublicKey()),
+		canonical.HeaderSignature: hex.EncodeToString(signature),
+		HeaderIdempotencyKey:      newIdempotencyKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res TxResponse
+	if err := res.UnmarshalJSON(raw); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetRelayer returns the transactions the relayer with public key id
+// submitted through the node.
+func (c *Client) GetRelayer(id [32]byte) (*RelayerStats, error) {
+	var res RelayerStats
+	if err := c.RequestJSON(RouteRelayer+"/"+hex.EncodeToString(id[:]), ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
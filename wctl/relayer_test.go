@@ -0,0 +1,33 @@
+// +build unit
+
+package wctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRelayTransaction(t *testing.T) {
+	cfg, stop := fakeNode(t, time.Second)
+	defer stop()
+
+	c, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	defer c.Close()
+
+	// The transaction is signed by its sender, and relayed by the client.
+	_, sender, err := edwards25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	req := signTransaction(sender, 1, 2, byte(sys.TagTransfer), []byte{1, 2, 3})
+
+	res, err := c.RelayTransaction(&req)
+	require.NoError(t, err)
+	assert.Equal(t, [32]byte{}, res.ID)
+}
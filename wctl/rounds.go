@@ -0,0 +1,140 @@
+package wctl
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+var (
+	_ UnmarshalableJSON = (*Round)(nil)
+	_ UnmarshalableJSON = (*RoundList)(nil)
+)
+
+// Round summarizes a block the node finalized. StartID and EndID are the IDs
+// of the first and last transactions of the block, and Duration how long the
+// node took to finalize it since finalizing the block preceding it.
+type Round struct {
+	Index       uint64        `json:"index"`
+	ID          [32]byte      `json:"id"`
+	MerkleRoot  [16]byte      `json:"merkle_root"`
+	StartID     [32]byte      `json:"start_id"`
+	EndID       [32]byte      `json:"end_id"`
+	NumTx       int           `json:"num_tx"`
+	NumApplied  int           `json:"num_applied_tx"`
+	NumRejected int           `json:"num_rejected_tx"`
+	StartedAt   time.Time     `json:"started_at"`
+	FinalizedAt time.Time     `json:"finalized_at"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// RoundList is a list of rounds, the latest first.
+type RoundList []Round
+
+func (r *Round) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	return r.unmarshalValue(v)
+}
+
+func (r *Round) unmarshalValue(v *fastjson.Value) error {
+	r.Index = v.GetUint64("index")
+
+	if err := jsonHex(v, r.ID[:], "id"); err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, r.MerkleRoot[:], "merkle_root"); err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, r.StartID[:], "start_id"); err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, r.EndID[:], "end_id"); err != nil {
+		return err
+	}
+
+	r.NumTx = v.GetInt("num_tx")
+	r.NumApplied = v.GetInt("num_applied_tx")
+	r.NumRejected = v.GetInt("num_rejected_tx")
+
+	var err error
+
+	if r.StartedAt, err = time.Parse(time.RFC3339Nano, string(v.GetStringBytes("started_at"))); err != nil {
+		return err
+	}
+
+	if r.FinalizedAt, err = time.Parse(time.RFC3339Nano, string(v.GetStringBytes("finalized_at"))); err != nil {
+		return err
+	}
+
+	if r.Duration, err = time.ParseDuration(string(v.GetStringBytes("duration"))); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (l *RoundList) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	a, err := v.Array()
+	if err != nil {
+		return err
+	}
+
+	rounds := make(RoundList, len(a))
+
+	for i := range a {
+		if err := rounds[i].unmarshalValue(a[i]); err != nil {
+			return err
+		}
+	}
+
+	*l = rounds
+
+	return nil
+}
+
+// GetRounds calls the /rounds endpoint of the API, returning up to limit
+// rounds the node finalized, starting from the round of the block with index
+// from and going back. Rounds are listed from the latest block should from be
+// nil, and up to the node's maximum should limit be 0.
+func (c *Client) GetRounds(from *uint64, limit uint64) (RoundList, error) {
+	path := RouteRounds + "?limit=" + strconv.FormatUint(limit, 10)
+
+	if from != nil {
+		path += "&from=" + strconv.FormatUint(*from, 10)
+	}
+
+	var res RoundList
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetRound calls the /rounds/<index> endpoint of the API, returning the round
+// of the block with index index.
+func (c *Client) GetRound(index uint64) (*Round, error) {
+	var res Round
+	if err := c.RequestJSON(RouteRounds+"/"+strconv.FormatUint(index, 10), ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
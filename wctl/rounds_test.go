@@ -0,0 +1,74 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRounds(t *testing.T) {
+	round := func(index int) string {
+		return fmt.Sprintf(`{"index":%d,"id":"%s","merkle_root":"%s","start_id":"%s","end_id":"%s","num_tx":3,`+
+			`"num_applied_tx":2,"num_rejected_tx":1,"started_at":"2019-10-15T00:00:0%dZ",`+
+			`"finalized_at":"2019-10-15T00:00:0%d.5Z","duration":"500ms"}`,
+			index, strings.Repeat("01", 32), strings.Repeat("02", 16), strings.Repeat("03", 32), strings.Repeat("04", 32),
+			index, index,
+		)
+	}
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == RouteRounds && r.URL.Query().Get("from") == "2":
+			_, _ = fmt.Fprintf(w, `[%s,%s]`, round(2), round(1))
+		case r.URL.Path == RouteRounds:
+			_, _ = fmt.Fprintf(w, `[%s]`, round(3))
+		case r.URL.Path == RouteRounds+"/2":
+			_, _ = fmt.Fprint(w, round(2))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer stop()
+
+	res, err := c.GetRound(2)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, res.Index)
+	assert.Equal(t, byte(1), res.ID[0])
+	assert.Equal(t, byte(2), res.MerkleRoot[0])
+	assert.Equal(t, byte(3), res.StartID[0])
+	assert.Equal(t, byte(4), res.EndID[0])
+	assert.Equal(t, 3, res.NumTx)
+	assert.Equal(t, 2, res.NumApplied)
+	assert.Equal(t, 1, res.NumRejected)
+	assert.True(t, res.StartedAt.Equal(time.Date(2019, 10, 15, 0, 0, 2, 0, time.UTC)))
+	assert.Equal(t, 500*time.Millisecond, res.FinalizedAt.Sub(res.StartedAt))
+	assert.Equal(t, 500*time.Millisecond, res.Duration)
+
+	_, err = c.GetRound(5)
+	assert.Error(t, err)
+
+	rounds, err := c.GetRounds(nil, 0)
+	require.NoError(t, err)
+
+	if assert.Len(t, rounds, 1) {
+		assert.EqualValues(t, 3, rounds[0].Index)
+	}
+
+	from := uint64(2)
+
+	rounds, err = c.GetRounds(&from, 2)
+	require.NoError(t, err)
+
+	if assert.Len(t, rounds, 2) {
+		assert.EqualValues(t, 2, rounds[0].Index)
+		assert.EqualValues(t, 1, rounds[1].Index)
+	}
+}
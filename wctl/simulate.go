@@ -0,0 +1,94 @@
+package wctl
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/valyala/fastjson"
+)
+
+var (
+	_ UnmarshalableJSON = (*ContractCallResult)(nil)
+	_ MarshalableJSON   = (*contractCallRequest)(nil)
+)
+
+// ContractCallResult is the outcome of a simulated smart contract function
+// call, none of whose effects were committed.
+type ContractCallResult struct {
+	// Result is the data the function reported as its result, if any.
+	Result []byte `json:"result"`
+
+	// Gas is the gas the function spent.
+	Gas              uint64 `json:"gas"`
+	GasLimitExceeded bool   `json:"gas_limit_exceeded"`
+
+	// Failure is why the function failed, being empty should it have
+	// succeeded.
+	Failure string `json:"failure"`
+
+	// Queued is the number of transactions the function would have sent.
+	Queued uint64 `json:"queued"`
+}
+
+func (r *ContractCallResult) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if r.Result, err = hex.DecodeString(string(v.GetStringBytes("result"))); err != nil {
+		return fmt.Errorf("invalid result: %v", err)
+	}
+
+	r.Gas = v.GetUint64("gas")
+	r.GasLimitExceeded = v.GetBool("gas_limit_exceeded")
+	r.Failure = string(v.GetStringBytes("failure"))
+	r.Queued = v.GetUint64("queued")
+
+	return nil
+}
+
+type contractCallRequest struct {
+	sender [32]byte
+	fn     FunctionCall
+}
+
+func (s *contractCallRequest) MarshalJSON() ([]byte, error) {
+	var (
+		arena  fastjson.Arena
+		params []byte
+	)
+
+	for _, p := range s.fn.Params {
+		params = append(params, p...)
+	}
+
+	o := arena.NewObject()
+
+	o.Set("sender", arena.NewString(hex.EncodeToString(s.sender[:])))
+	o.Set("func", arena.NewString(s.fn.Name))
+	o.Set("params", arena.NewString(hex.EncodeToString(params)))
+	o.Set("amount", arena.NewNumberString(fmt.Sprint(s.fn.Amount)))
+	o.Set("gas_limit", arena.NewNumberString(fmt.Sprint(s.fn.GasLimit)))
+
+	return o.MarshalTo(nil), nil
+}
+
+// SimulateContractCall calls the /contract/<id>/call endpoint of the API,
+// which calls a smart contract function against the latest state of the
+// ledger as if the client were to Call it, without anything being committed.
+// The node caps the gas limit should none be given.
+func (c *Client) SimulateContractCall(contract [32]byte, fn FunctionCall) (*ContractCallResult, error) {
+	var res ContractCallResult
+
+	req := &contractCallRequest{sender: c.PublicKey, fn: fn}
+	path := fmt.Sprintf("%s/%x/call", RouteContract, contract)
+
+	if err := c.RequestJSON(path, ReqPost, req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
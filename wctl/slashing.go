@@ -0,0 +1,94 @@
+package wctl
+
+import (
+	"encoding/hex"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*Slashings)(nil)
+
+// Slashings are the PERLs slashed from the stake of an account as a validator
+// for misbehaving.
+type Slashings struct {
+	PublicKey [32]byte `json:"public_key"`
+
+	// LastActive is the last block the account had a transaction applied at
+	// as a validator, nil should it never have been seen active.
+	LastActive *uint64 `json:"last_active"`
+
+	Slashed uint64          `json:"slashed"`
+	Events  []SlashingEvent `json:"events"`
+}
+
+// SlashingEvent is Amount PERLs slashed from a validator at block Block for
+// Reason, either "double_sign" or "downtime", upon the report of Reporter. Of
+// the PERLs slashed, Burned PERLs were burned and the rest rewarded to
+// Reporter.
+type SlashingEvent struct {
+	Reason   string   `json:"reason"`
+	Reporter [32]byte `json:"reporter"`
+	TxID     [32]byte `json:"tx_id"`
+	Nonce    uint64   `json:"nonce"`
+	Amount   uint64   `json:"amount"`
+	Burned   uint64   `json:"burned"`
+	Block    uint64   `json:"block"`
+}
+
+func (s *Slashings) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, s.PublicKey[:], "public_key"); err != nil {
+		return err
+	}
+
+	s.LastActive = nil
+
+	if lastActive := v.Get("last_active"); lastActive != nil && lastActive.Type() == fastjson.TypeNumber {
+		block := lastActive.GetUint64()
+		s.LastActive = &block
+	}
+
+	s.Slashed = v.GetUint64("slashed")
+	s.Events = s.Events[:0]
+
+	for _, o := range v.GetArray("events") {
+		event := SlashingEvent{
+			Reason: string(o.GetStringBytes("reason")),
+			Nonce:  o.GetUint64("nonce"),
+			Amount: o.GetUint64("amount"),
+			Burned: o.GetUint64("burned"),
+			Block:  o.GetUint64("block"),
+		}
+
+		if err := jsonHex(o, event.Reporter[:], "reporter"); err != nil {
+			return err
+		}
+
+		if err := jsonHex(o, event.TxID[:], "tx_id"); err != nil {
+			return err
+		}
+
+		s.Events = append(s.Events, event)
+	}
+
+	return nil
+}
+
+// GetSlashings calls the /accounts/<id>/slashings endpoint of the API,
+// returning the PERLs slashed from the account as a validator.
+func (c *Client) GetSlashings(account [32]byte) (*Slashings, error) {
+	path := RouteAccount + "/" + hex.EncodeToString(account[:]) + "/slashings"
+
+	var res Slashings
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
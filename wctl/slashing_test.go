@@ -0,0 +1,64 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientSlashings(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RouteAccount + "/" + strings.Repeat("01", 32) + "/slashings":
+			_, _ = fmt.Fprintf(w, `{"public_key":"%s","last_active":120,"slashed":500,`+
+				`"events":[{"reason":"double_sign","nonce":5,"reporter":"%s","tx_id":"%s","amount":500,`+
+				`"burned":450,"block":11}]}`,
+				strings.Repeat("01", 32), strings.Repeat("02", 32), strings.Repeat("03", 32))
+		case RouteAccount + "/" + strings.Repeat("02", 32) + "/slashings":
+			_, _ = fmt.Fprintf(w, `{"public_key":"%s","last_active":null,"slashed":0,"events":[]}`,
+				strings.Repeat("02", 32))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer stop()
+
+	var account [32]byte
+	copy(account[:], strings.Repeat("\x01", 32))
+
+	res, err := c.GetSlashings(account)
+	require.NoError(t, err)
+
+	assert.Equal(t, account, res.PublicKey)
+	assert.EqualValues(t, 500, res.Slashed)
+
+	if assert.NotNil(t, res.LastActive) {
+		assert.EqualValues(t, 120, *res.LastActive)
+	}
+
+	if assert.Len(t, res.Events, 1) {
+		assert.Equal(t, "double_sign", res.Events[0].Reason)
+		assert.Equal(t, byte(2), res.Events[0].Reporter[0])
+		assert.Equal(t, byte(3), res.Events[0].TxID[0])
+		assert.EqualValues(t, 5, res.Events[0].Nonce)
+		assert.EqualValues(t, 500, res.Events[0].Amount)
+		assert.EqualValues(t, 450, res.Events[0].Burned)
+		assert.EqualValues(t, 11, res.Events[0].Block)
+	}
+
+	copy(account[:], strings.Repeat("\x02", 32))
+
+	res, err = c.GetSlashings(account)
+	require.NoError(t, err)
+	assert.Nil(t, res.LastActive)
+	assert.Empty(t, res.Events)
+
+	_, err = c.GetSlashings([32]byte{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,211 @@
+package wctl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/valyala/fastjson"
+)
+
+var (
+	_ UnmarshalableJSON = (*ContractStorageValue)(nil)
+	_ UnmarshalableJSON = (*ContractStorageList)(nil)
+)
+
+// ContractStorageValue is the value of a key of the storage of a smart
+// contract. Should it have been requested with a proof, the value is to be
+// checked with VerifyContractStorageProof against a Merkle root known to be of
+// a finalized block, as neither the block nor the value the node reports may
+// be trusted otherwise.
+type ContractStorageValue struct {
+	Key    []byte `json:"key"`
+	Value  []byte `json:"value"`
+	Exists bool   `json:"exists"`
+
+	// Block is only set alongside Proof.
+	Block struct {
+		MerkleRoot [16]byte `json:"merkle_root"`
+		Index      uint64   `json:"height"`
+		ID         [32]byte `json:"id"`
+	} `json:"block"`
+
+	Proof *wavelet.ContractStorageProof `json:"-"`
+}
+
+func (s *ContractStorageValue) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	var id [32]byte
+
+	if err := jsonHex(v, id[:], "contract_id"); err != nil {
+		return err
+	}
+
+	if s.Key, err = hex.DecodeString(string(v.GetStringBytes("key"))); err != nil {
+		return errUnmarshalFail(v, "key", err)
+	}
+
+	if s.Value, err = hex.DecodeString(string(v.GetStringBytes("value"))); err != nil {
+		return errUnmarshalFail(v, "value", err)
+	}
+
+	s.Exists = v.GetBool("exists")
+	s.Proof = nil
+
+	if !v.Exists("proof") {
+		return nil
+	}
+
+	if err := jsonHex(v, s.Block.MerkleRoot[:], "block", "merkle_root"); err != nil {
+		return err
+	}
+
+	s.Block.Index = v.GetUint64("block", "height")
+
+	if err := jsonHex(v, s.Block.ID[:], "block", "id"); err != nil {
+		return err
+	}
+
+	s.Proof = &wavelet.ContractStorageProof{ID: id, Key: s.Key}
+
+	for _, list := range []struct {
+		key string
+		dst *[][]byte
+	}{{"path", &s.Proof.Proof.Path}, {"lefts", &s.Proof.Proof.Lefts}} {
+		for _, item := range v.GetArray("proof", list.key) {
+			buf, err := hex.DecodeString(string(item.GetStringBytes()))
+			if err != nil {
+				return errUnmarshalFail(v, "proof."+list.key, err)
+			}
+
+			if len(buf) == 0 {
+				buf = nil
+			}
+
+			*list.dst = append(*list.dst, buf)
+		}
+	}
+
+	return nil
+}
+
+// ContractStorageEntry is a key of the storage of a smart contract, and its
+// value.
+type ContractStorageEntry struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// ContractStorageList is a page of the keys of the storage of a smart
+// contract, in order.
+type ContractStorageList struct {
+	Entries []ContractStorageEntry `json:"entries"`
+}
+
+func (l *ContractStorageList) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	l.Entries = l.Entries[:0]
+
+	for _, o := range v.GetArray("entries") {
+		var entry ContractStorageEntry
+
+		if entry.Key, err = hex.DecodeString(string(o.GetStringBytes("key"))); err != nil {
+			return errUnmarshalFail(o, "key", err)
+		}
+
+		if entry.Value, err = hex.DecodeString(string(o.GetStringBytes("value"))); err != nil {
+			return errUnmarshalFail(o, "value", err)
+		}
+
+		l.Entries = append(l.Entries, entry)
+	}
+
+	return nil
+}
+
+// GetContractStorage calls the /contract/<id>/storage/<key> endpoint of the
+// API, returning the value of key of the storage of the smart contract.
+// ErrNotFound is returned should the key not be set.
+func (c *Client) GetContractStorage(contract [32]byte, key []byte) (*ContractStorageValue, error) {
+	path := fmt.Sprintf("%s/%x/storage/%x", RouteContract, contract, key)
+
+	var res ContractStorageValue
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetContractStorageProof calls the /contract/<id>/storage/<key>?proof=true
+// endpoint of the API, which proves the value of key of the storage of the
+// smart contract, or the key being unset, against the Merkle root of the
+// latest block whose state the node committed.
+func (c *Client) GetContractStorageProof(contract [32]byte, key []byte) (*ContractStorageValue, error) {
+	path := fmt.Sprintf("%s/%x/storage/%x?proof=true", RouteContract, contract, key)
+
+	var res ContractStorageValue
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Proof == nil {
+		return nil, fmt.Errorf("node did not prove key %x of the storage of contract %x", key, contract)
+	}
+
+	return &res, nil
+}
+
+// ScanContractStorage calls the /contract/<id>/storage endpoint of the API,
+// returning up to limit keys of the storage of the smart contract which start
+// with prefix, in order, starting from the first key after after should it be
+// set. The node caps the limit should none be given.
+func (c *Client) ScanContractStorage(contract [32]byte, prefix, after []byte, limit uint64) (*ContractStorageList, error) {
+	vals := url.Values{}
+
+	if len(prefix) > 0 {
+		vals.Set("prefix", hex.EncodeToString(prefix))
+	}
+
+	if len(after) > 0 {
+		vals.Set("after", hex.EncodeToString(after))
+	}
+
+	if limit != 0 {
+		vals.Set("limit", strconv.FormatUint(limit, 10))
+	}
+
+	path := fmt.Sprintf("%s/%x/storage?%s", RouteContract, contract, vals.Encode())
+
+	var res ContractStorageList
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// VerifyContractStorageProof checks the proof of value against root, being a
+// Merkle root the caller trusts, returning the value of the key it proves,
+// and whether the key is set.
+func VerifyContractStorageProof(value *ContractStorageValue, root [16]byte) ([]byte, bool, error) {
+	if value.Proof == nil {
+		return nil, false, fmt.Errorf("value of key %x carries no proof", value.Key)
+	}
+
+	return value.Proof.Verify(root)
+}
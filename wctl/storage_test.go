@@ -0,0 +1,69 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientContractStorage(t *testing.T) {
+	var contract [32]byte
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RouteContract + "/" + strings.Repeat("00", 32) + "/storage/6b6579":
+			if r.URL.Query().Get("proof") != "true" {
+				_, _ = fmt.Fprintf(w, `{"contract_id":"%s","key":"6b6579","value":"0102","exists":true}`,
+					strings.Repeat("00", 32))
+				return
+			}
+
+			_, _ = fmt.Fprintf(w, `{"contract_id":"%s","key":"6b6579","value":"","exists":false,`+
+				`"block":{"merkle_root":"%s","height":3,"id":"%s"},"proof":{"path":[],"lefts":[]}}`,
+				strings.Repeat("00", 32), strings.Repeat("00", 16), strings.Repeat("01", 32))
+		case RouteContract + "/" + strings.Repeat("00", 32) + "/storage":
+			assert.Equal(t, "6b", r.URL.Query().Get("prefix"))
+			assert.Equal(t, "6b01", r.URL.Query().Get("after"))
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+
+			_, _ = fmt.Fprint(w, `{"entries":[{"key":"6b6579","value":"0102"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer stop()
+
+	value, err := c.GetContractStorage(contract, []byte("key"))
+	require.NoError(t, err)
+
+	assert.True(t, value.Exists)
+	assert.Equal(t, []byte{1, 2}, value.Value)
+	assert.Nil(t, value.Proof)
+
+	// An empty proof proves keys to be unset in an empty tree.
+	value, err = c.GetContractStorageProof(contract, []byte("key"))
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, value.Block.Index)
+
+	_, exists, err := VerifyContractStorageProof(value, value.Block.MerkleRoot)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, _, err = VerifyContractStorageProof(value, [16]byte{1})
+	assert.Error(t, err)
+
+	list, err := c.ScanContractStorage(contract, []byte("k"), []byte{'k', 1}, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ContractStorageEntry{{Key: []byte("key"), Value: []byte{1, 2}}}, list.Entries)
+
+	_, err = c.GetContractStorage(contract, []byte("missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package wctl
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+var (
+	ErrTLSWithoutHTTPS      = errors.New("TLS options require UseHTTPS")
+	ErrCertificateNotPinned = errors.New("certificate of the node is not pinned")
+)
+
+// usesTLS reports whether any of the TLS options of config are set.
+func (config Config) usesTLS() bool {
+	return config.TLSConfig != nil || config.TLSCAFile != "" || config.TLSCertFile != "" ||
+		config.TLSKeyFile != "" || len(config.PinnedCertificates) > 0
+}
+
+// tlsConfig returns the TLS configuration described by the TLS options of
+// config, or nil should it not use HTTPS. TLS sessions are resumed across
+// connections unless TLSConfig specifies a session cache of its own.
+func (config Config) tlsConfig() (*tls.Config, error) {
+	if !config.UseHTTPS {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if config.TLSConfig != nil {
+		tlsConfig = config.TLSConfig.Clone()
+	}
+
+	if tlsConfig.ClientSessionCache == nil {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+
+	if config.TLSCAFile != "" {
+		buf, err := ioutil.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(buf) {
+			return nil, fmt.Errorf("no PEM-encoded certificates found in %s", config.TLSCAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+
+	if len(config.PinnedCertificates) > 0 {
+		pinned := make(map[[sha256.Size]byte]struct{}, len(config.PinnedCertificates))
+
+		for _, pin := range config.PinnedCertificates {
+			fingerprint, err := ParseFingerprint(pin)
+			if err != nil {
+				return nil, err
+			}
+
+			pinned[fingerprint] = struct{}{}
+		}
+
+		// Pinned certificates are trusted regardless of who issued them,
+		// unless certificate authorities to verify them against are given.
+		if tlsConfig.RootCAs == nil {
+			tlsConfig.InsecureSkipVerify = true
+		}
+
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return ErrCertificateNotPinned
+			}
+
+			if _, ok := pinned[sha256.Sum256(rawCerts[0])]; !ok {
+				return ErrCertificateNotPinned
+			}
+
+			return nil
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// tlsDial returns a dial function for fasthttp, completing a TLS handshake
+// with tlsConfig over connections established by dial.
+func tlsDial(dial fasthttp.DialFunc, tlsConfig *tls.Config, timeout time.Duration) fasthttp.DialFunc {
+	return func(addr string) (net.Conn, error) {
+		conn, err := dial(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg := tlsConfig
+		if cfg.ServerName == "" {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				_ = conn.Close()
+				return nil, err
+			}
+
+			cfg = tlsConfig.Clone()
+			cfg.ServerName = host
+		}
+
+		tlsConn := tls.Client(conn, cfg)
+
+		_ = tlsConn.SetDeadline(time.Now().Add(timeout))
+
+		if err := tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		_ = tlsConn.SetDeadline(time.Time{})
+
+		return tlsConn, nil
+	}
+}
+
+// ParseFingerprint parses the hex-encoded SHA-256 fingerprint of a
+// certificate, optionally separated by colons, as printed by
+// `openssl x509 -noout -fingerprint -sha256`.
+func ParseFingerprint(s string) ([sha256.Size]byte, error) {
+	var fingerprint [sha256.Size]byte
+
+	raw := strings.Replace(strings.TrimSpace(s), ":", "", -1)
+
+	if hex.DecodedLen(len(raw)) != sha256.Size {
+		return fingerprint, fmt.Errorf("certificate fingerprint %q must be %d hex characters", s,
+			hex.EncodedLen(sha256.Size))
+	}
+
+	if _, err := hex.Decode(fingerprint[:], []byte(raw)); err != nil {
+		return fingerprint, fmt.Errorf("certificate fingerprint %q must be hex-encoded: %v", s, err)
+	}
+
+	return fingerprint, nil
+}
@@ -0,0 +1,113 @@
+// +build unit
+
+package wctl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// colonFingerprint formats the SHA-256 fingerprint of cert as openssl does.
+func colonFingerprint(cert []byte) string {
+	sum := sha256.Sum256(cert)
+	raw := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	parts := make([]string, 0, len(sum))
+	for i := 0; i < len(raw); i += 2 {
+		parts = append(parts, raw[i:i+2])
+	}
+
+	return strings.Join(parts, ":")
+}
+
+func TestParseFingerprint(t *testing.T) {
+	sum := sha256.Sum256([]byte("certificate"))
+
+	fingerprint, err := ParseFingerprint(hex.EncodeToString(sum[:]))
+	assert.NoError(t, err)
+	assert.Equal(t, sum, fingerprint)
+
+	fingerprint, err = ParseFingerprint(colonFingerprint([]byte("certificate")))
+	assert.NoError(t, err)
+	assert.Equal(t, sum, fingerprint)
+
+	_, err = ParseFingerprint("abcd")
+	assert.Error(t, err)
+
+	_, err = ParseFingerprint(strings.Repeat("zz", sha256.Size))
+	assert.Error(t, err)
+}
+
+func TestClientPinnedCertificate(t *testing.T) {
+	cfg, cert, cleanup := fakeTLSNode(t, time.Hour)
+	defer cleanup()
+
+	// The certificate of the node is self-signed, yet trusted once pinned.
+	cfg.PinnedCertificates = []string{colonFingerprint(cert.Raw)}
+
+	c, err := NewClient(cfg)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	c.Close()
+
+	other := sha256.Sum256([]byte("other"))
+	cfg.PinnedCertificates = []string{hex.EncodeToString(other[:])}
+
+	_, err = NewClient(cfg)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), ErrCertificateNotPinned.Error())
+	}
+}
+
+func TestClientTLSCAFile(t *testing.T) {
+	cfg, cert, cleanup := fakeTLSNode(t, time.Hour)
+	defer cleanup()
+
+	// The certificate of the node is not trusted by the system.
+	_, err := NewClient(cfg)
+	assert.Error(t, err)
+
+	dir, err := ioutil.TempDir("", "wctl")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	defer os.RemoveAll(dir)
+
+	cfg.TLSCAFile = filepath.Join(dir, "ca.pem")
+
+	if !assert.NoError(t, ioutil.WriteFile(cfg.TLSCAFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600),
+	) {
+		t.FailNow()
+	}
+
+	c, err := NewClient(cfg)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	c.Close()
+
+	// Pins are checked on top of the certificate authorities.
+	cfg.PinnedCertificates = []string{strings.Repeat("00", sha256.Size)}
+
+	_, err = NewClient(cfg)
+	assert.Error(t, err)
+}
+
+func TestClientTLSWithoutHTTPS(t *testing.T) {
+	_, err := NewClient(Config{APIHost: "127.0.0.1", PinnedCertificates: []string{strings.Repeat("00", sha256.Size)}})
+	assert.Equal(t, ErrTLSWithoutHTTPS, err)
+}
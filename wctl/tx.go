@@ -1,14 +1,16 @@
 package wctl
 
 import (
-	"encoding/binary"
+	"context"
 	"encoding/hex"
 	"errors"
 	"net/url"
 	"strconv"
 	"time"
 
-	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
 	"github.com/valyala/fastjson"
 )
 
@@ -17,6 +19,7 @@ var (
 	_ UnmarshalableJSON = (*Transaction)(nil)
 	_ UnmarshalableJSON = (*TransactionList)(nil)
 	_ MarshalableJSON   = (*TxRequest)(nil)
+	_ UnmarshalableJSON = (*TxRequest)(nil)
 )
 
 var (
@@ -33,7 +36,8 @@ type TransactionEvent struct {
 }
 
 // ListTransactions calls the /tx endpoint of the API to list all transactions.
-// The arguments are optional, zero values would default them.
+// The arguments are optional, zero values would default them. Transactions
+// are best paged through with IterateTransactions.
 func (c *Client) ListTransactions(
 	senderID string, creatorID string, offset uint64, limit uint64,
 ) ([]Transaction, error) {
@@ -47,6 +51,13 @@ func (c *Client) ListTransactions(
 		vals.Set("creator", creatorID)
 	}
 
+	return c.listTransactions(context.Background(), vals, offset, limit)
+}
+
+// listTransactions lists a page of the transactions matching the query vals.
+func (c *Client) listTransactions(
+	ctx context.Context, vals url.Values, offset uint64, limit uint64,
+) ([]Transaction, error) {
 	if offset != 0 {
 		vals.Set("offset", strconv.FormatUint(offset, 10))
 	}
@@ -58,7 +69,7 @@ func (c *Client) ListTransactions(
 	path := RouteTxList + "?" + vals.Encode()
 
 	var res TransactionList
-	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+	if err := c.RequestJSONCtx(ctx, path, ReqGet, nil, &res); err != nil {
 		return nil, err
 	}
 
@@ -67,10 +78,15 @@ func (c *Client) ListTransactions(
 
 // GetTransaction calls the /tx endpoint to query a single transaction.
 func (c *Client) GetTransaction(txID [32]byte) (*Transaction, error) {
+	return c.GetTransactionCtx(context.Background(), txID)
+}
+
+// GetTransactionCtx is GetTransaction, which gives up once ctx is done.
+func (c *Client) GetTransactionCtx(ctx context.Context, txID [32]byte) (*Transaction, error) {
 	path := RouteTxList + "/" + hex.EncodeToString(txID[:])
 
 	var res Transaction
-	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+	if err := c.RequestJSONCtx(ctx, path, ReqGet, nil, &res); err != nil {
 		return nil, err
 	}
 
@@ -80,40 +96,91 @@ func (c *Client) GetTransaction(txID [32]byte) (*Transaction, error) {
 // SendTransaction calls the /tx/send endpoint to send a raw payload.
 // Payloads are best crafted with wavelet.Transfer.
 func (c *Client) SendTransaction(tag byte, payload []byte) (*TxResponse, error) {
-	var res TxResponse
-
-	nonce := uint64(time.Now().UnixNano())
-	block := c.Block.Load()
+	return c.SendTransactionCtx(context.Background(), tag, payload)
+}
 
-	var nonceBuf [8]byte
+// SendTransactionCtx is SendTransaction, which gives up once ctx is done. The
+// transaction may nonetheless have reached the node.
+func (c *Client) SendTransactionCtx(ctx context.Context, tag byte, payload []byte) (*TxResponse, error) {
+	return c.BroadcastTransactionCtx(ctx, c.CraftTransaction(tag, payload))
+}
 
-	binary.BigEndian.PutUint64(nonceBuf[:], nonce)
+// CraftTransaction signs a transaction with the private key of the client,
+// created at the latest block known to the client, without contacting the
+// node. It may be broadcast later on, by any client, with
+// BroadcastTransaction.
+func (c *Client) CraftTransaction(tag byte, payload []byte) *TxRequest {
+	req := signTransaction(c.PrivateKey, c.NextNonce(), c.Block.Load(), tag, payload)
 
-	var blockBuf [8]byte
+	return &req
+}
 
-	binary.BigEndian.PutUint64(blockBuf[:], block)
+// BroadcastTransaction calls the /tx/send endpoint to submit a transaction
+// signed beforehand, such as by CraftTransaction or TxBuilder.
+func (c *Client) BroadcastTransaction(req *TxRequest) (*TxResponse, error) {
+	return c.BroadcastTransactionCtx(context.Background(), req)
+}
 
-	signature := edwards25519.Sign(
-		c.PrivateKey,
-		append(nonceBuf[:], append(blockBuf[:], append([]byte{tag}, payload...)...)...),
-	)
+// BroadcastTransactionCtx is BroadcastTransaction, which gives up once ctx is
+// done. The transaction may nonetheless have reached the node.
+func (c *Client) BroadcastTransactionCtx(ctx context.Context, req *TxRequest) (*TxResponse, error) {
+	body, err := req.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
 
-	req := TxRequest{
-		Sender:    c.PublicKey,
-		Nonce:     nonce,
-		Block:     block,
-		Tag:       tag,
-		Payload:   payload,
-		Signature: signature,
+	// Retries of the request are responded to as the first attempt was,
+	// rather than being rejected should the transaction have been added.
+	raw, err := c.request(ctx, RouteTxSend, ReqPost, body, map[string]string{
+		HeaderIdempotencyKey: newIdempotencyKey(),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := c.RequestJSON(RouteTxSend, ReqPost, &req, &res); err != nil {
+	var res TxResponse
+	if err := res.UnmarshalJSON(raw); err != nil {
 		return nil, err
 	}
 
 	return &res, nil
 }
 
+// SendStampedTransaction is SendTransaction for a transaction which pays no
+// fee, being stamped instead with a proof of work of the difficulty the node
+// currently requires. The stamp is searched for locally, and takes longer to
+// find the busier the network is.
+func (c *Client) SendStampedTransaction(tag byte, payload []byte) (*TxResponse, error) {
+	status, err := c.LedgerStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	req := signStampedTransaction(
+		c.PrivateKey, c.NextNonce(), c.Block.Load(), tag, payload, status.StampDifficulty,
+	)
+
+	return c.BroadcastTransaction(&req)
+}
+
+// NextNonce returns the nonce of the next transaction to be sent by the
+// client. Nonces are the time in nanoseconds as per Now, bumped past the
+// last nonce returned such that no two transactions sent share a nonce, be
+// they sent concurrently or across clock corrections.
+func (c *Client) NextNonce() uint64 {
+	c.nonceLock.Lock()
+	defer c.nonceLock.Unlock()
+
+	nonce := uint64(c.Now().UnixNano())
+	if nonce <= c.nonce {
+		nonce = c.nonce + 1
+	}
+
+	c.nonce = nonce
+
+	return nonce
+}
+
 // SendTransfer sends a wavelet.Transfer instead of a Payload.
 func (c *Client) sendTransfer(tag byte, transfer Marshalable) (*TxResponse, error) {
 	payload, err := transfer.Marshal()
@@ -133,8 +200,13 @@ type Transaction struct {
 	Sender    [32]byte `json:"sender"`
 	Status    string   `json:"status"`
 	Nonce     uint64   `json:"nonce"`
+	Height    uint64   `json:"height"`
 	Tag       byte     `json:"tag"`
 	Payload   []byte   `json:"payload"`
+	Scheme    byte     `json:"scheme,omitempty"`
+	Version   byte     `json:"version,omitempty"`
+	Stamp     uint64   `json:"stamp,omitempty"`
+	Tip       uint64   `json:"tip,omitempty"`
 	Signature [64]byte `json:"signature"`
 }
 
@@ -160,8 +232,13 @@ func (t *Transaction) ParseJSON(v *fastjson.Value) error {
 
 	t.Status = string(v.GetStringBytes("status"))
 	t.Nonce = v.GetUint64("nonce")
+	t.Height = v.GetUint64("height")
 	t.Tag = byte(v.GetUint("tag"))
 	t.Payload = v.GetStringBytes("payload")
+	t.Scheme = byte(v.GetUint("scheme"))
+	t.Version = byte(v.GetUint("version"))
+	t.Stamp = v.GetUint64("stamp")
+	t.Tip = v.GetUint64("tip")
 
 	if err := jsonHex(v, t.Signature[:], "signature"); err != nil {
 		return err
@@ -207,6 +284,10 @@ type TxRequest struct {
 	Block     uint64   `json:"block"`
 	Tag       byte     `json:"tag"`
 	Payload   []byte   `json:"payload"`
+	Scheme    byte     `json:"scheme,omitempty"`
+	Version   byte     `json:"version,omitempty"`
+	Stamp     uint64   `json:"stamp,omitempty"`
+	Tip       uint64   `json:"tip,omitempty"`
 	Signature [64]byte `json:"signature"`
 }
 
@@ -219,11 +300,75 @@ func (s *TxRequest) MarshalJSON() ([]byte, error) {
 	o.Set("block", arena.NewNumberInt(int(s.Block)))
 	o.Set("tag", arena.NewNumberInt(int(s.Tag)))
 	o.Set("payload", arena.NewString(hex.EncodeToString(s.Payload)))
+
+	if s.Scheme != 0 {
+		o.Set("scheme", arena.NewNumberInt(int(s.Scheme)))
+	}
+
+	if s.Version != 0 {
+		o.Set("version", arena.NewNumberInt(int(s.Version)))
+	}
+
+	if s.Stamp != 0 {
+		o.Set("stamp", arena.NewNumberString(strconv.FormatUint(s.Stamp, 10)))
+	}
+
+	if s.Tip != 0 {
+		o.Set("tip", arena.NewNumberString(strconv.FormatUint(s.Tip, 10)))
+	}
+
 	o.Set("signature", arena.NewString(hex.EncodeToString(s.Signature[:])))
 
 	return o.MarshalTo(nil), nil
 }
 
+// UnmarshalJSON parses a TxRequest as marshaled by MarshalJSON, such that
+// signed transactions may be stored, and broadcast later on.
+func (s *TxRequest) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, s.Sender[:], "sender"); err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, s.Signature[:], "signature"); err != nil {
+		return err
+	}
+
+	if s.Payload, err = hex.DecodeString(string(v.GetStringBytes("payload"))); err != nil {
+		return errUnmarshalFail(v, "payload", err)
+	}
+
+	for _, key := range []string{"nonce", "block", "tag"} {
+		if v.Get(key) == nil {
+			return errUnmarshalFail(v, key, errors.New("missing"))
+		}
+	}
+
+	s.Nonce = v.GetUint64("nonce")
+	s.Block = v.GetUint64("block")
+	s.Tag = byte(v.GetUint("tag"))
+	s.Scheme = byte(v.GetUint("scheme"))
+	s.Version = byte(v.GetUint("version"))
+	s.Stamp = v.GetUint64("stamp")
+	s.Tip = v.GetUint64("tip")
+
+	return nil
+}
+
+// ID returns the ID the node will assign to the transaction.
+func (s *TxRequest) ID() [32]byte {
+	return wavelet.NewSignedTippedTransaction(
+		s.Version, security.Scheme(s.Scheme), s.Sender, s.Nonce, s.Block, sys.Tag(s.Tag), s.Payload, s.Stamp,
+		s.Tip, s.Signature,
+	).ID
+}
+
 type TxResponse struct {
 	ID [32]byte `json:"id"`
 	// Parents  [][32]byte `json:"parent_ids"`
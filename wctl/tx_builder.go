@@ -0,0 +1,209 @@
+package wctl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+var (
+	// ErrInitNotCallable is returned when attempting to invoke a smart
+	// contract's init function, which only runs upon spawning.
+	ErrInitNotCallable = errors.New("not allowed to invoke init function of smart contract")
+
+	// ErrNoGasLimit is returned when invoking a smart contract function
+	// without specifying a gas limit.
+	ErrNoGasLimit = errors.New("gas limit for invoking smart contract function must be greater than zero")
+)
+
+// TxBuilder fluently crafts a transfer transaction, and signs it into a
+// TxRequest that may be submitted to /tx/send. For example:
+//
+//	req, err := wctl.NewTransfer(recipient).
+//		Amount(100).
+//		GasLimit(100000).
+//		Invoke("balance", wctl.EncodeString("hello")).
+//		Sign(privateKey)
+type TxBuilder struct {
+	transfer wavelet.Transfer
+
+	nonce uint64
+	block uint64
+}
+
+// NewTransfer creates a new TxBuilder for a transfer to recipient.
+func NewTransfer(recipient [32]byte) *TxBuilder {
+	return &TxBuilder{transfer: wavelet.Transfer{Recipient: recipient}}
+}
+
+// Amount sets the amount of PERLs to transfer.
+func (b *TxBuilder) Amount(amount uint64) *TxBuilder {
+	b.transfer.Amount = amount
+	return b
+}
+
+// GasLimit sets the maximum amount of PERLs that may be spent on gas
+// should the recipient be a smart contract.
+func (b *TxBuilder) GasLimit(limit uint64) *TxBuilder {
+	b.transfer.GasLimit = limit
+	return b
+}
+
+// GasDeposit sets the amount of PERLs to deposit into the gas balance of
+// the recipient smart contract.
+func (b *TxBuilder) GasDeposit(deposit uint64) *TxBuilder {
+	b.transfer.GasDeposit = deposit
+	return b
+}
+
+// Invoke sets the smart contract function to invoke, alongside its
+// parameters. Parameters are concatenated in order, and are best encoded
+// using the Encode* helpers.
+func (b *TxBuilder) Invoke(fn string, params ...[]byte) *TxBuilder {
+	b.transfer.FuncName = []byte(fn)
+	b.transfer.FuncParams = nil
+
+	for _, p := range params {
+		b.transfer.FuncParams = append(b.transfer.FuncParams, p...)
+	}
+
+	return b
+}
+
+// Nonce overrides the nonce of the transaction. By default, the current
+// time in nanoseconds is used.
+func (b *TxBuilder) Nonce(nonce uint64) *TxBuilder {
+	b.nonce = nonce
+	return b
+}
+
+// Block sets the block index the transaction is to be created at.
+func (b *TxBuilder) Block(block uint64) *TxBuilder {
+	b.block = block
+	return b
+}
+
+// Payload validates and marshals the transfer payload.
+func (b *TxBuilder) Payload() ([]byte, error) {
+	if string(b.transfer.FuncName) == "init" {
+		return nil, ErrInitNotCallable
+	}
+
+	if len(b.transfer.FuncName) > 0 && b.transfer.GasLimit == 0 {
+		return nil, ErrNoGasLimit
+	}
+
+	return b.transfer.Marshal()
+}
+
+// Sign marshals the transfer payload, and signs it with the given private
+// key into a TxRequest ready to be broadcasted.
+func (b *TxBuilder) Sign(key edwards25519.PrivateKey) (*TxRequest, error) {
+	return b.SignWith(security.NewEd25519Signer(key))
+}
+
+// SignWith is Sign for a signer of any signature scheme.
+func (b *TxBuilder) SignWith(signer security.Signer) (*TxRequest, error) {
+	payload, err := b.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	return SignTransaction(signer, b.nonce, b.block, byte(sys.TagTransfer), payload)
+}
+
+// SignTransaction signs a transaction of any tag and payload with signer
+// into a TxRequest ready to be broadcasted, such as by a threshold.Coordinator
+// signing on behalf of a split key. The current time in nanoseconds is used
+// as the nonce should nonce be 0.
+func SignTransaction(signer security.Signer, nonce, block uint64, tag byte, payload []byte) (*TxRequest, error) {
+	if nonce == 0 {
+		nonce = uint64(time.Now().UnixNano())
+	}
+
+	tx, err := wavelet.NewTransactionWithSigner(signer, nonce, block, sys.Tag(tag), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TxRequest{
+		Sender:    tx.Sender,
+		Nonce:     tx.Nonce,
+		Block:     tx.Block,
+		Tag:       byte(tx.Tag),
+		Payload:   tx.Payload,
+		Scheme:    byte(tx.Scheme),
+		Version:   tx.Version,
+		Stamp:     tx.Stamp,
+		Signature: tx.Signature,
+	}, nil
+}
+
+// Send signs the transfer with the client's private key at the client's
+// latest known block, and broadcasts it.
+func (b *TxBuilder) Send(c *Client) (*TxResponse, error) {
+	req, err := b.Block(c.Block.Load()).Sign(c.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.BroadcastTransaction(req)
+}
+
+// signTransaction signs the given transaction contents the same way the
+// node verifies them, and returns them as a TxRequest.
+func signTransaction(key edwards25519.PrivateKey, nonce, block uint64, tag byte, payload []byte) TxRequest {
+	return signStampedTransaction(key, nonce, block, tag, payload, 0)
+}
+
+// signStampedTransaction is signTransaction for a transaction stamped with a
+// proof of work of the given difficulty, which is searched for locally.
+// A difficulty of 0 leaves the transaction unstamped.
+func signStampedTransaction(
+	key edwards25519.PrivateKey, nonce, block uint64, tag byte, payload []byte, difficulty int,
+) TxRequest {
+	return signTippedTransaction(key, nonce, block, tag, payload, difficulty, 0)
+}
+
+// signTippedTransaction is signStampedTransaction for a transaction paying
+// the given tip on top of its fee.
+func signTippedTransaction(
+	key edwards25519.PrivateKey, nonce, block uint64, tag byte, payload []byte, difficulty int, tip uint64,
+) TxRequest {
+	tx := wavelet.Transaction{
+		Sender:  key.Public(),
+		Nonce:   nonce,
+		Block:   block,
+		Tag:     sys.Tag(tag),
+		Payload: payload,
+		Tip:     tip,
+	}
+
+	// The legacy encoding is signed unless a stamp or tip is, which only the
+	// canonical encoding signs, such that transactions are accepted by nodes
+	// yet to activate the latter.
+	if difficulty > 0 || tip > 0 {
+		tx.Version = canonical.Version
+	}
+
+	if difficulty > 0 {
+		tx.Stamp = wavelet.FindStamp(tx, difficulty)
+	}
+
+	return TxRequest{
+		Sender:    tx.Sender,
+		Nonce:     tx.Nonce,
+		Block:     tx.Block,
+		Tag:       tag,
+		Payload:   tx.Payload,
+		Version:   tx.Version,
+		Stamp:     tx.Stamp,
+		Tip:       tx.Tip,
+		Signature: edwards25519.Sign(key, tx.Message()),
+	}
+}
@@ -0,0 +1,91 @@
+// +build unit
+
+package wctl
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxBuilder(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	recipient := [32]byte{1, 2, 3}
+
+	req, err := NewTransfer(recipient).
+		Amount(100).
+		GasLimit(200).
+		GasDeposit(300).
+		Invoke("fn", EncodeUint64(1), EncodeString("hello")).
+		Nonce(1).
+		Block(2).
+		Sign(key)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, byte(sys.TagTransfer), req.Tag)
+	assert.EqualValues(t, 1, req.Nonce)
+	assert.EqualValues(t, 2, req.Block)
+
+	tx := wavelet.NewSignedTransactionWithVersion(
+		req.Version, security.Scheme(req.Scheme), req.Sender, req.Nonce, req.Block, sys.Tag(req.Tag), req.Payload,
+		req.Signature,
+	)
+	assert.True(t, tx.VerifySignature())
+
+	transfer, err := wavelet.ParseTransfer(req.Payload)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, wavelet.Transfer{
+		Recipient:  recipient,
+		Amount:     100,
+		GasLimit:   200,
+		GasDeposit: 300,
+		FuncName:   []byte("fn"),
+		FuncParams: append(EncodeUint64(1), EncodeString("hello")...),
+	}, transfer)
+}
+
+func TestTxBuilderInvalid(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = NewTransfer([32]byte{}).Invoke("fn").Sign(key)
+	assert.Equal(t, ErrNoGasLimit, err)
+
+	_, err = NewTransfer([32]byte{}).GasLimit(1).Invoke("init").Sign(key)
+	assert.Equal(t, ErrInitNotCallable, err)
+}
+
+func TestSignStampedTransaction(t *testing.T) {
+	_, key, err := edwards25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	req := signStampedTransaction(key, 1, 2, byte(sys.TagTransfer), []byte{1, 2, 3}, 8)
+	assert.NotZero(t, req.Stamp)
+
+	tx := wavelet.NewSignedStampedTransaction(
+		req.Version, security.Scheme(req.Scheme), req.Sender, req.Nonce, req.Block, sys.Tag(req.Tag), req.Payload,
+		req.Stamp, req.Signature,
+	)
+	assert.True(t, tx.VerifySignature())
+	assert.True(t, tx.StampDifficulty() >= 8)
+
+	// Without a difficulty, transactions are left unstamped.
+	assert.Zero(t, signTransaction(key, 1, 2, byte(sys.TagTransfer), []byte{1, 2, 3}).Stamp)
+}
@@ -4,9 +4,8 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
-
-	"github.com/perlin-network/wavelet"
-	"github.com/perlin-network/wavelet/sys"
+	"fmt"
+	"strconv"
 )
 
 var ErrNotContract = errors.New("address is not smart contract")
@@ -26,7 +25,7 @@ func (c *Client) Call(recipient [32]byte, fn FunctionCall) (*TxResponse, error)
 		return nil, ErrInsufficientPerls
 	}
 
-	return c.sendTransfer(byte(sys.TagTransfer), fn.toTransfer(recipient))
+	return fn.toBuilder(recipient).Send(c)
 }
 
 // FunctionCall is the struct containing parameters to call a function.
@@ -41,19 +40,56 @@ func (fn *FunctionCall) AddParams(params ...[]byte) {
 	fn.Params = append(fn.Params, params...)
 }
 
-func (fn FunctionCall) toTransfer(recipient [32]byte) wavelet.Transfer {
-	t := wavelet.Transfer{
-		Recipient: recipient,
-		Amount:    fn.Amount,
-		GasLimit:  fn.GasLimit,
-		FuncName:  []byte(fn.Name),
+func (fn FunctionCall) toBuilder(recipient [32]byte) *TxBuilder {
+	return NewTransfer(recipient).
+		Amount(fn.Amount).
+		GasLimit(fn.GasLimit).
+		Invoke(fn.Name, fn.Params...)
+}
+
+// ParseParam encodes a smart contract function parameter given as text,
+// whose first character denotes its type:
+//
+//	S<string>	a null-terminated string
+//	B<bytes>	a length-prefixed byte string
+//	1<int>, 2<int>, 4<int>, 8<int>	an unsigned integer of that many bytes
+//	H<hex>	raw bytes, hex-encoded
+func ParseParam(arg string) ([]byte, error) {
+	if arg == "" {
+		return nil, errors.New("empty parameter")
 	}
 
-	for _, p := range fn.Params {
-		t.FuncParams = append(t.FuncParams, p...)
+	switch arg[0] {
+	case 'S':
+		return EncodeString(arg[1:]), nil
+	case 'B':
+		return EncodeBytes([]byte(arg[1:])), nil
+	case '1', '2', '4', '8':
+		val, err := strconv.ParseUint(arg[1:], 10, int(arg[0]-'0')*8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %c-byte integer %q: %v", arg[0], arg[1:], err)
+		}
+
+		switch arg[0] {
+		case '1':
+			return EncodeByte(byte(val)), nil
+		case '2':
+			return EncodeUint16(uint16(val)), nil
+		case '4':
+			return EncodeUint32(uint32(val)), nil
+		default:
+			return EncodeUint64(val), nil
+		}
+	case 'H':
+		buf, err := DecodeHex(arg[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex %q: %v", arg[1:], err)
+		}
+
+		return buf, nil
 	}
 
-	return t
+	return nil, fmt.Errorf("invalid parameter prefix %q", arg[0])
 }
 
 func DecodeHex(s string) ([]byte, error) {
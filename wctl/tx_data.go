@@ -0,0 +1,12 @@
+package wctl
+
+import (
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// AnchorData anchors blob into the ledger, paying a fee for every byte of it.
+// The blob may be retrieved by the ID of the transaction through GetData.
+func (c *Client) AnchorData(blob []byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagData), wavelet.Data{Blob: blob})
+}
@@ -1,10 +1,5 @@
 package wctl
 
-import (
-	"github.com/perlin-network/wavelet"
-	"github.com/perlin-network/wavelet/sys"
-)
-
 func (c *Client) DepositGas(recipient [32]byte, gasAmount uint64) (*TxResponse, error) {
 	a, err := c.GetSelf()
 	if err != nil {
@@ -19,8 +14,5 @@ func (c *Client) DepositGas(recipient [32]byte, gasAmount uint64) (*TxResponse,
 		return nil, ErrInsufficientPerls
 	}
 
-	return c.sendTransfer(byte(sys.TagTransfer), wavelet.Transfer{
-		Recipient:  recipient,
-		GasDeposit: gasAmount,
-	})
+	return NewTransfer(recipient).GasDeposit(gasAmount).Send(c)
 }
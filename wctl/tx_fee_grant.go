@@ -0,0 +1,21 @@
+package wctl
+
+import (
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// GrantFees sponsors the transaction fees of grantee up to allowance PERLs.
+func (c *Client) GrantFees(grantee [32]byte, allowance uint64) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagFeeGrant), wavelet.FeeGrant{
+		Grantee:   grantee,
+		Allowance: allowance,
+	})
+}
+
+// RevokeFees revokes the fee grant made to grantee.
+func (c *Client) RevokeFees(grantee [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagFeeGrant), wavelet.FeeGrant{
+		Grantee: grantee,
+	})
+}
@@ -0,0 +1,134 @@
+package wctl
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+)
+
+// MaxTransactionPageSize is the maximum number of transactions the node lists
+// at once.
+const MaxTransactionPageSize = 5000
+
+// TransactionFilter narrows down the transactions listed by the node. Zero
+// fields match all transactions.
+type TransactionFilter struct {
+	Sender [32]byte
+	Tag    byte
+
+	// Transactions created against blocks within [FromHeight, ToHeight].
+	FromHeight uint64
+	ToHeight   uint64
+}
+
+func (f TransactionFilter) values() url.Values {
+	vals := url.Values{}
+
+	if f.Sender != ([32]byte{}) {
+		vals.Set("sender", hex.EncodeToString(f.Sender[:]))
+	}
+
+	if f.Tag != 0 {
+		vals.Set("tag", strconv.FormatUint(uint64(f.Tag), 10))
+	}
+
+	if f.FromHeight != 0 {
+		vals.Set("from_height", strconv.FormatUint(f.FromHeight, 10))
+	}
+
+	if f.ToHeight != 0 {
+		vals.Set("to_height", strconv.FormatUint(f.ToHeight, 10))
+	}
+
+	return vals
+}
+
+// TransactionIterator pages through the transactions listed by the node,
+// ordered by height. For example:
+//
+//	it := client.IterateTransactions(wctl.TransactionFilter{Sender: id}, 0)
+//	for it.Next() {
+//		tx := it.Transaction()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Transactions the node receives while paging, at heights already paged
+// through, are skipped, and cause the transaction last listed to be listed
+// once more.
+type TransactionIterator struct {
+	client   *Client
+	ctx      context.Context
+	filter   TransactionFilter
+	pageSize uint64
+
+	offset uint64
+	page   []Transaction
+	tx     Transaction
+	done   bool
+	err    error
+}
+
+// IterateTransactions returns an iterator over the transactions matching
+// filter, requesting pageSize transactions at a time, or as many as the node
+// allows at a time should pageSize be zero.
+func (c *Client) IterateTransactions(filter TransactionFilter, pageSize uint64) *TransactionIterator {
+	return c.IterateTransactionsCtx(context.Background(), filter, pageSize)
+}
+
+// IterateTransactionsCtx is IterateTransactions, whose requests give up once
+// ctx is done.
+func (c *Client) IterateTransactionsCtx(
+	ctx context.Context, filter TransactionFilter, pageSize uint64,
+) *TransactionIterator {
+	if pageSize == 0 || pageSize > MaxTransactionPageSize {
+		pageSize = MaxTransactionPageSize
+	}
+
+	return &TransactionIterator{client: c, ctx: ctx, filter: filter, pageSize: pageSize}
+}
+
+// Next advances the iterator to the next transaction, requesting the next
+// page of transactions should the current one be exhausted. It returns false
+// once there are no more transactions, or a request failed.
+func (it *TransactionIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.page) == 0 {
+		if it.done {
+			return false
+		}
+
+		page, err := it.client.listTransactions(it.ctx, it.filter.values(), it.offset, it.pageSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = page
+		it.offset += uint64(len(page))
+		it.done = uint64(len(page)) < it.pageSize
+
+		if len(page) == 0 {
+			return false
+		}
+	}
+
+	it.tx, it.page = it.page[0], it.page[1:]
+
+	return true
+}
+
+// Transaction returns the transaction the iterator was last advanced to.
+func (it *TransactionIterator) Transaction() Transaction {
+	return it.tx
+}
+
+// Err returns the error which stopped the iterator, if any.
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
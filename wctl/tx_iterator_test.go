@@ -0,0 +1,61 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionIterator(t *testing.T) {
+	zero := strings.Repeat("00", 32)
+	signature := strings.Repeat("00", 64)
+
+	var queries []string
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var txs []string
+
+		for height := offset + 1; height <= 7 && len(txs) < limit; height++ {
+			txs = append(txs, fmt.Sprintf(
+				`{"id":"%s","sender":"%s","height":%d,"tag":1,"signature":"%s"}`, zero, zero, height, signature,
+			))
+		}
+
+		_, _ = fmt.Fprintf(w, "[%s]", strings.Join(txs, ","))
+	})
+	defer stop()
+
+	it := c.IterateTransactions(TransactionFilter{Tag: 1, FromHeight: 1}, 3)
+
+	var heights []uint64
+	for it.Next() {
+		heights = append(heights, it.Transaction().Height)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7}, heights)
+	assert.Equal(t, []string{
+		"from_height=1&limit=3&tag=1",
+		"from_height=1&limit=3&offset=3&tag=1",
+		"from_height=1&limit=3&offset=6&tag=1",
+	}, queries)
+
+	// Iterators stop on the first failed request.
+	stop()
+
+	it = c.IterateTransactions(TransactionFilter{}, 0)
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+	assert.False(t, it.Next())
+}
@@ -0,0 +1,62 @@
+package wctl
+
+import (
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// CreateMultisig creates a multisig account, whose PERLs are only moved once
+// threshold of participants approve. The ID of the account is that of the
+// transaction returned.
+func (c *Client) CreateMultisig(participants [][32]byte, threshold uint8) (*TxResponse, error) {
+	payload := wavelet.Multisig{
+		Opcode:       sys.CreateMultisig,
+		Participants: make([]wavelet.AccountID, len(participants)),
+		Threshold:    threshold,
+	}
+
+	for i, participant := range participants {
+		payload.Participants[i] = participant
+	}
+
+	return c.sendTransfer(byte(sys.TagMultisig), payload)
+}
+
+// ProposeMultisig proposes transferring amount PERLs from the multisig
+// account to recipient as its participant, approving the transfer. The ID of
+// the proposal is that of the transaction returned.
+func (c *Client) ProposeMultisig(account, recipient [32]byte, amount uint64) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagMultisig), wavelet.Multisig{
+		Opcode:    sys.ProposeMultisig,
+		Account:   account,
+		Recipient: recipient,
+		Amount:    amount,
+	})
+}
+
+// ApproveMultisig approves the transfer proposed from a multisig account as
+// its participant.
+func (c *Client) ApproveMultisig(proposal [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagMultisig), wavelet.Multisig{
+		Opcode:   sys.ApproveMultisig,
+		Proposal: proposal,
+	})
+}
+
+// ExecuteMultisig transfers the PERLs of an approved proposal from its
+// multisig account as its participant.
+func (c *Client) ExecuteMultisig(proposal [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagMultisig), wavelet.Multisig{
+		Opcode:   sys.ExecuteMultisig,
+		Proposal: proposal,
+	})
+}
+
+// CancelMultisig cancels a transfer the client proposed from a multisig
+// account.
+func (c *Client) CancelMultisig(proposal [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagMultisig), wavelet.Multisig{
+		Opcode:   sys.CancelMultisig,
+		Proposal: proposal,
+	})
+}
@@ -0,0 +1,33 @@
+package wctl
+
+import (
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// RegisterName registers name to resolve to target, or points name at target
+// should the client already own it.
+func (c *Client) RegisterName(name string, target [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagName), wavelet.Name{
+		Opcode: sys.RegisterName,
+		Name:   name,
+		Target: target,
+	})
+}
+
+// RenewName extends the registration of name.
+func (c *Client) RenewName(name string) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagName), wavelet.Name{
+		Opcode: sys.RenewName,
+		Name:   name,
+	})
+}
+
+// TransferName transfers the ownership of name to owner.
+func (c *Client) TransferName(name string, owner [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagName), wavelet.Name{
+		Opcode: sys.TransferName,
+		Name:   name,
+		Target: owner,
+	})
+}
@@ -0,0 +1,59 @@
+package wctl
+
+import (
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// ConfigureRecovery lets threshold of guardians recover the account of the
+// client, delay blocks after initiating a recovery. Configuring no guardians
+// removes the configuration.
+func (c *Client) ConfigureRecovery(guardians [][32]byte, threshold uint8, delay uint64) (*TxResponse, error) {
+	payload := wavelet.Recovery{Opcode: sys.ConfigureRecovery}
+
+	if len(guardians) > 0 {
+		payload.Guardians = make([]wavelet.AccountID, len(guardians))
+		payload.Threshold = threshold
+		payload.Delay = delay
+
+		for i, guardian := range guardians {
+			payload.Guardians[i] = guardian
+		}
+	}
+
+	return c.sendTransfer(byte(sys.TagRecovery), payload)
+}
+
+// InitiateRecovery starts recovering account to recipient as its guardian.
+func (c *Client) InitiateRecovery(account, recipient [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagRecovery), wavelet.Recovery{
+		Opcode:    sys.InitiateRecovery,
+		Account:   account,
+		Recipient: recipient,
+	})
+}
+
+// ApproveRecovery approves recovering account to recipient as its guardian.
+func (c *Client) ApproveRecovery(account, recipient [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagRecovery), wavelet.Recovery{
+		Opcode:    sys.ApproveRecovery,
+		Account:   account,
+		Recipient: recipient,
+	})
+}
+
+// CancelRecovery cancels the pending recovery of the account of the client.
+func (c *Client) CancelRecovery() (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagRecovery), wavelet.Recovery{
+		Opcode: sys.CancelRecovery,
+	})
+}
+
+// CompleteRecovery moves the PERLs of account to the recipient of its
+// approved recovery.
+func (c *Client) CompleteRecovery(account [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagRecovery), wavelet.Recovery{
+		Opcode:  sys.CompleteRecovery,
+		Account: account,
+	})
+}
@@ -0,0 +1,76 @@
+package wctl
+
+import (
+	"context"
+	"encoding/base64"
+	"math"
+
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// ReplaceTransaction replaces the transaction with the given ID the client
+// sent, which must be pending finalization, by sending a transaction of the
+// same nonce carrying tag and payload instead. The replacement pays a tip
+// such that its fee exceeds the fee of the original transaction, which nodes
+// require of replacements. Nodes only accept replacements once the
+// replacement feature is active, from which at most one of the two
+// transactions is applied, though the original may nonetheless be finalized
+// before the replacement reaches the network. ErrNotReplaceable is returned
+// should the transaction not be pending, or not have been sent by the client.
+func (c *Client) ReplaceTransaction(id [32]byte, tag byte, payload []byte) (*TxResponse, error) {
+	return c.ReplaceTransactionCtx(context.Background(), id, tag, payload)
+}
+
+// ReplaceTransactionCtx is ReplaceTransaction, which gives up once ctx is
+// done. The replacement may nonetheless have reached the node.
+func (c *Client) ReplaceTransactionCtx(
+	ctx context.Context, id [32]byte, tag byte, payload []byte,
+) (*TxResponse, error) {
+	original, err := c.GetTransactionCtx(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.Sender != c.PublicKey || original.Status != "received" {
+		return nil, ErrNotReplaceable
+	}
+
+	fee, err := original.fee()
+	if err != nil {
+		return nil, err
+	}
+
+	var tip uint64
+
+	if base := (wavelet.Transaction{Tag: sys.Tag(tag), Payload: payload}).Fee(); base <= fee {
+		tip = fee - base + 1
+	}
+
+	req := signTippedTransaction(c.PrivateKey, original.Nonce, c.Block.Load(), tag, payload, 0, tip)
+
+	return c.BroadcastTransactionCtx(ctx, &req)
+}
+
+// fee returns the fee paid for t, including its tip.
+func (t *Transaction) fee() (uint64, error) {
+	// Payloads are rendered in base64.
+	payload, err := base64.StdEncoding.DecodeString(string(t.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	fee := wavelet.NewSignedStampedTransaction(
+		t.Version, security.Scheme(t.Scheme), t.Sender, t.Nonce, t.Height, sys.Tag(t.Tag), payload, t.Stamp,
+		t.Signature,
+	).Fee()
+
+	// Nodes accepting replacements pay tips, whichever features the client
+	// knows to be active.
+	if fee > math.MaxUint64-t.Tip {
+		return math.MaxUint64, nil
+	}
+
+	return fee + t.Tip, nil
+}
@@ -0,0 +1,92 @@
+// +build unit
+
+package wctl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+// Not parallel, as the schedule of features is global.
+func TestClientReplaceTransaction(t *testing.T) {
+	defer wavelet.ScheduleFeatures(sys.FeatureReplacement)()
+
+	public, key, err := edwards25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	sent := make(chan TxRequest, 1)
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		original := func(status string) {
+			// The original transaction pays a tip of 10 PERLs.
+			_, _ = fmt.Fprintf(w,
+				`{"id":"%s","sender":"%s","status":"%s","nonce":7,"height":1,"tag":1,"payload":"",`+
+					`"version":%d,"tip":10,"signature":"%s"}`,
+				strings.Repeat("00", 32), hex.EncodeToString(public[:]), status, canonical.Version,
+				strings.Repeat("00", 64),
+			)
+		}
+
+		switch r.URL.Path {
+		case RouteTxList + "/" + strings.Repeat("00", 32):
+			original("received")
+		case RouteTxList + "/" + strings.Repeat("01", 32):
+			original("applied")
+		case RouteTxSend:
+			body, _ := ioutil.ReadAll(r.Body)
+
+			var req TxRequest
+			if !assert.NoError(t, req.UnmarshalJSON(body)) {
+				return
+			}
+
+			sent <- req
+
+			id := req.ID()
+
+			_, _ = fmt.Fprintf(w, `{"id":"%s"}`, hex.EncodeToString(id[:]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer stop()
+
+	c.PrivateKey, c.PublicKey, c.Block = key, public, atomic.NewUint64(2)
+
+	res, err := c.ReplaceTransaction([32]byte{}, byte(sys.TagTransfer), []byte{1, 2, 3})
+	require.NoError(t, err)
+
+	req := <-sent
+	assert.Equal(t, req.ID(), res.ID)
+
+	// The replacement shares the nonce of the original, and pays a PERL more.
+	assert.EqualValues(t, 7, req.Nonce)
+	assert.EqualValues(t, 2, req.Block)
+
+	tx := wavelet.NewSignedTippedTransaction(
+		req.Version, security.Scheme(req.Scheme), req.Sender, req.Nonce, req.Block, sys.Tag(req.Tag), req.Payload,
+		req.Stamp, req.Tip, req.Signature,
+	)
+	assert.True(t, tx.VerifySignature())
+	assert.Equal(t, sys.DefaultTransactionFee+11, tx.Fee())
+
+	// Transactions which are no longer pending may not be replaced.
+	var applied [32]byte
+	copy(applied[:], strings.Repeat("\x01", 32))
+
+	_, err = c.ReplaceTransaction(applied, byte(sys.TagTransfer), []byte{1, 2, 3})
+	assert.Equal(t, ErrNotReplaceable, err)
+}
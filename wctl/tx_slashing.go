@@ -0,0 +1,27 @@
+package wctl
+
+import (
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// ReportDoubleSign reports the sender of a and b for signing two conflicting
+// transactions, which must be of the same nonce and pay the same fee. Part of
+// the stake slashed from the sender is rewarded to the client.
+func (c *Client) ReportDoubleSign(a, b wavelet.Transaction) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagSlashing), wavelet.Slashing{
+		Opcode:    sys.ReportDoubleSign,
+		Validator: a.Sender,
+		Evidence:  [2]wavelet.Transaction{a, b},
+	})
+}
+
+// ReportDowntime reports validator for having had no transaction applied for
+// too long. Part of the stake slashed from validator is rewarded to the
+// client.
+func (c *Client) ReportDowntime(validator [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagSlashing), wavelet.Slashing{
+		Opcode:    sys.ReportDowntime,
+		Validator: validator,
+	})
+}
@@ -1,24 +1,57 @@
 package wctl
 
 import (
-	wasm "github.com/perlin-network/life/wasm-validation"
 	"github.com/perlin-network/wavelet"
 	"github.com/perlin-network/wavelet/sys"
 )
 
+// DefaultSpawnGasLimit is the gas limit smart contracts are spawned with
+// should none be given.
+const DefaultSpawnGasLimit = 100000000
+
+// Spawn spawns a smart contract without calling its init function with any
+// parameters.
 func (c *Client) Spawn(code []byte, gasLimit uint64) (*TxResponse, error) {
+	return c.SendContract(ContractSpawn{Code: code, GasLimit: gasLimit})
+}
+
+// SendContract spawns a smart contract, whose init function is called with
+// the parameters of spawn.
+func (c *Client) SendContract(spawn ContractSpawn) (*TxResponse, error) {
+	if err := wavelet.ValidateContractCode(spawn.Code); err != nil {
+		return nil, err
+	}
+
+	return c.sendTransfer(byte(sys.TagContract), spawn.payload())
+}
+
+// ContractSpawn is the struct containing the code of a smart contract to
+// spawn, and the parameters to call its init function with.
+type ContractSpawn struct {
+	Code       []byte
+	GasLimit   uint64
+	GasDeposit uint64
+	Params     [][]byte
+}
+
+func (s *ContractSpawn) AddParams(params ...[]byte) {
+	s.Params = append(s.Params, params...)
+}
+
+func (s ContractSpawn) payload() wavelet.Contract {
 	ct := wavelet.Contract{
-		GasLimit: 100000000,
-		Code:     code,
+		GasLimit:   DefaultSpawnGasLimit,
+		GasDeposit: s.GasDeposit,
+		Code:       s.Code,
 	}
 
-	if gasLimit > 0 {
-		ct.GasLimit = gasLimit
+	if s.GasLimit > 0 {
+		ct.GasLimit = s.GasLimit
 	}
 
-	if err := wasm.GetValidator().ValidateWasm(code); err != nil {
-		return nil, err
+	for _, p := range s.Params {
+		ct.Params = append(ct.Params, p...)
 	}
 
-	return c.sendTransfer(byte(sys.TagContract), ct)
+	return ct
 }
@@ -25,3 +25,40 @@ func (c *Client) WithdrawReward(amount uint64) (*TxResponse, error) {
 		Amount: amount,
 	})
 }
+
+// Delegate bonds amount PERLs to validator, sharing in its rewards less its
+// commission.
+func (c *Client) Delegate(validator [32]byte, amount uint64) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagDelegation), wavelet.Delegation{
+		Opcode:    sys.DelegateStake,
+		Validator: validator,
+		Amount:    amount,
+	})
+}
+
+// Undelegate unbonds amount PERLs delegated to validator, which may be
+// claimed back with ClaimUnbonded once their unbonding period elapsed.
+func (c *Client) Undelegate(validator [32]byte, amount uint64) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagDelegation), wavelet.Delegation{
+		Opcode:    sys.UndelegateStake,
+		Validator: validator,
+		Amount:    amount,
+	})
+}
+
+// ClaimUnbonded moves the undelegated PERLs of the client whose unbonding
+// period elapsed back to its balance.
+func (c *Client) ClaimUnbonded() (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagDelegation), wavelet.Delegation{
+		Opcode: sys.ClaimUnbonded,
+	})
+}
+
+// SetCommission sets the commission the client charges on the rewards of its
+// delegators as a validator, in hundredths of a percent.
+func (c *Client) SetCommission(commission uint16) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagDelegation), wavelet.Delegation{
+		Opcode:     sys.SetCommission,
+		Commission: commission,
+	})
+}
@@ -0,0 +1,54 @@
+package wctl
+
+import (
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/sys"
+)
+
+// SendUpgradableContract spawns a smart contract whose code may be upgraded
+// by upgrader, alongside the parameters of spawn. The ID of the contract is
+// the ID of the transaction.
+func (c *Client) SendUpgradableContract(spawn ContractSpawn, upgrader [32]byte) (*TxResponse, error) {
+	if err := wavelet.ValidateContractCode(spawn.Code); err != nil {
+		return nil, err
+	}
+
+	var batch wavelet.Batch
+
+	if err := batch.AddContract(spawn.payload()); err != nil {
+		return nil, err
+	}
+
+	// The contract spawned by the batch is referred to by the zero ID.
+	if err := batch.AddUpgrade(wavelet.Upgrade{Opcode: sys.ConfigureUpgrader, Upgrader: upgrader}); err != nil {
+		return nil, err
+	}
+
+	return c.SendBatch(batch)
+}
+
+// UpgradeContract replaces the code of contract, which the client must be the
+// upgrader of. The balances and storage of the contract are kept, while its
+// memory is discarded.
+func (c *Client) UpgradeContract(contract [32]byte, code []byte) (*TxResponse, error) {
+	if err := wavelet.ValidateContractCode(code); err != nil {
+		return nil, err
+	}
+
+	return c.sendTransfer(byte(sys.TagUpgrade), wavelet.Upgrade{
+		Opcode:   sys.UpgradeContract,
+		Contract: contract,
+		Code:     code,
+	})
+}
+
+// SetContractUpgrader hands the role of upgrader of contract, which the
+// client must be the upgrader of, over to upgrader. A zero upgrader freezes
+// the code of the contract for good.
+func (c *Client) SetContractUpgrader(contract, upgrader [32]byte) (*TxResponse, error) {
+	return c.sendTransfer(byte(sys.TagUpgrade), wavelet.Upgrade{
+		Opcode:   sys.ConfigureUpgrader,
+		Contract: contract,
+		Upgrader: upgrader,
+	})
+}
@@ -0,0 +1,119 @@
+package wctl
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/valyala/fastjson"
+)
+
+// DefaultTxPollInterval is the interval at which WaitForTransaction queries
+// the status of a transaction, should Config.TxPollInterval not be set.
+const DefaultTxPollInterval = time.Second
+
+// ErrTxRejected is returned by WaitForTransaction, wrapped in a
+// TxRejectedError, for transactions rejected upon being applied.
+var ErrTxRejected = errors.New("tx rejected")
+
+// TxRejectedError reports why a transaction was rejected upon being applied.
+type TxRejectedError struct {
+	ID     [32]byte
+	Reason string
+}
+
+func (e *TxRejectedError) Error() string {
+	return fmt.Sprintf("transaction %x was rejected: %s", e.ID, e.Reason)
+}
+
+// Is reports whether target is ErrTxRejected.
+func (e *TxRejectedError) Is(target error) bool {
+	return target == ErrTxRejected
+}
+
+// WaitForTransaction waits for the transaction txID to be finalized, and
+// returns it as reported by the node. Transactions rejected upon being
+// applied are reported by a *TxRejectedError instead. ctx.Err() is returned
+// should ctx be done first, such that timeouts are given by its deadline.
+//
+// The transaction is awaited through the websocket of transaction events,
+// and its status is queried every Config.TxPollInterval regardless, should
+// the websocket be unavailable or miss the event. As the node only tells
+// rejections apart from applied transactions by their event, transactions
+// rejected before the websocket is connected are returned as applied.
+func (c *Client) WaitForTransaction(ctx context.Context, txID [32]byte) (*Transaction, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rejected := make(chan string, 1)
+	applied := make(chan struct{}, 1)
+
+	path := RouteWSTransactions + "?id=" + hex.EncodeToString(txID[:])
+
+	// Should the websocket be unavailable, the transaction is only polled for.
+	stop, err := c.pollWS(ctx, path, func(v *fastjson.Value) {
+		for _, o := range v.GetArray() {
+			if checkMod(o, log.ModuleTX) != nil {
+				continue
+			}
+
+			switch jsonString(o, "event") {
+			case events.EventTxApplied:
+				select {
+				case applied <- struct{}{}:
+				default:
+				}
+			case events.EventTxRejected, events.EventTxFailed:
+				select {
+				case rejected <- jsonString(o, "error"):
+				default:
+				}
+			}
+		}
+	})
+	if err == nil {
+		defer stop()
+	}
+
+	interval := c.Config.TxPollInterval
+	if interval <= 0 {
+		interval = DefaultTxPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		tx, err := c.GetTransactionCtx(ctx, txID)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err == nil && tx.Status == "applied":
+			// The event of a rejection may have arrived alongside the
+			// transaction being finalized.
+			select {
+			case reason := <-rejected:
+				return nil, &TxRejectedError{ID: txID, Reason: reason}
+			default:
+			}
+
+			return tx, nil
+		case err != nil && !errors.Is(err, ErrNotFound):
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case reason := <-rejected:
+			return nil, &TxRejectedError{ID: txID, Reason: reason}
+		case <-applied:
+		case <-ticker.C:
+		}
+	}
+}
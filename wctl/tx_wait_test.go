@@ -0,0 +1,105 @@
+// +build unit
+
+package wctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestClientWaitForTransaction(t *testing.T) {
+	var upgrader websocket.Upgrader
+
+	queried := atomic.NewUint32(0)
+
+	// The websocket reports the transaction 02.. as rejected, while its status
+	// is only ever queried as received.
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		tx := func(id, status string) {
+			_, _ = fmt.Fprintf(w, `{"id":"%s","sender":"%s","status":"%s","nonce":1,"height":1,"tag":1,`+
+				`"payload":"","signature":"%s"}`,
+				id, strings.Repeat("00", 32), status, strings.Repeat("00", 64),
+			)
+		}
+
+		switch r.URL.Path {
+		case RouteWSTransactions:
+			if r.URL.Query().Get("id") != strings.Repeat("02", 32) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+				`[{"mod":"%s","event":"failed","tx_id":"%s","error":"insufficient balance"}]`,
+				log.ModuleTX, strings.Repeat("02", 32),
+			)))
+
+			_, _, _ = conn.ReadMessage()
+		case RouteTxList + "/" + strings.Repeat("01", 32):
+			// The transaction is first unknown, then received, then applied.
+			switch queried.Inc() {
+			case 1:
+				w.WriteHeader(http.StatusNotFound)
+			case 2:
+				tx(strings.Repeat("01", 32), "received")
+			default:
+				tx(strings.Repeat("01", 32), "applied")
+			}
+		case RouteTxList + "/" + strings.Repeat("02", 32):
+			tx(strings.Repeat("02", 32), "received")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer stop()
+
+	c.Config.TxPollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id := [32]byte{}
+	copy(id[:], strings.Repeat("\x01", 32))
+
+	tx, err := c.WaitForTransaction(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "applied", tx.Status)
+	assert.EqualValues(t, 3, queried.Load())
+
+	copy(id[:], strings.Repeat("\x02", 32))
+
+	_, err = c.WaitForTransaction(ctx, id)
+	assert.True(t, errors.Is(err, ErrTxRejected))
+
+	var rejected *TxRejectedError
+	if assert.True(t, errors.As(err, &rejected)) {
+		assert.Equal(t, "insufficient balance", rejected.Reason)
+		assert.Equal(t, id, rejected.ID)
+	}
+
+	// Transactions which are never finalized are waited for until timing out.
+	copy(id[:], strings.Repeat("\x03", 32))
+
+	timeout, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = c.WaitForTransaction(timeout, id)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -0,0 +1,87 @@
+package wctl
+
+import (
+	"fmt"
+
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*ContractUpgrades)(nil)
+
+// ContractUpgrades is the upgrader of a smart contract, and the upgrades of
+// its code so far, from the earliest to the latest.
+type ContractUpgrades struct {
+	CodeHash [32]byte `json:"code_hash"`
+
+	// Upgradable is false should the code of the contract be frozen.
+	Upgradable bool     `json:"-"`
+	Upgrader   [32]byte `json:"upgrader"`
+
+	Upgrades []ContractUpgrade `json:"upgrades"`
+}
+
+// ContractUpgrade is an upgrade of the code of a smart contract to code of
+// hash CodeHash, applied at block Block.
+type ContractUpgrade struct {
+	Block    uint64   `json:"block"`
+	TxID     [32]byte `json:"tx_id"`
+	Upgrader [32]byte `json:"upgrader"`
+	CodeHash [32]byte `json:"code_hash"`
+}
+
+func (u *ContractUpgrades) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	v, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	if err := jsonHex(v, u.CodeHash[:], "code_hash"); err != nil {
+		return err
+	}
+
+	u.Upgrader = [32]byte{}
+	u.Upgradable = v.Exists("upgrader") && v.Get("upgrader").Type() != fastjson.TypeNull
+
+	if u.Upgradable {
+		if err := jsonHex(v, u.Upgrader[:], "upgrader"); err != nil {
+			return err
+		}
+	}
+
+	u.Upgrades = u.Upgrades[:0]
+
+	for _, o := range v.GetArray("upgrades") {
+		upgrade := ContractUpgrade{Block: o.GetUint64("block")}
+
+		if err := jsonHex(o, upgrade.TxID[:], "tx_id"); err != nil {
+			return err
+		}
+
+		if err := jsonHex(o, upgrade.Upgrader[:], "upgrader"); err != nil {
+			return err
+		}
+
+		if err := jsonHex(o, upgrade.CodeHash[:], "code_hash"); err != nil {
+			return err
+		}
+
+		u.Upgrades = append(u.Upgrades, upgrade)
+	}
+
+	return nil
+}
+
+// GetContractUpgrades calls the /contract/<id>/upgrades endpoint of the API,
+// returning the upgrader of the smart contract and the upgrades of its code.
+func (c *Client) GetContractUpgrades(contract [32]byte) (*ContractUpgrades, error) {
+	path := fmt.Sprintf("%s/%x/upgrades", RouteContract, contract)
+
+	var res ContractUpgrades
+	if err := c.RequestJSON(path, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,58 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientContractUpgrades(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RouteContract + "/" + strings.Repeat("00", 32) + "/upgrades":
+			_, _ = fmt.Fprintf(w, `{"code_hash":"%s","upgrader":null,"upgrades":[]}`, strings.Repeat("01", 32))
+		case RouteContract + "/" + strings.Repeat("02", 32) + "/upgrades":
+			_, _ = fmt.Fprintf(w, `{"code_hash":"%s","upgrader":"%s","upgrades":[`+
+				`{"block":4,"tx_id":"%s","upgrader":"%s","code_hash":"%s"}]}`,
+				strings.Repeat("05", 32), strings.Repeat("03", 32), strings.Repeat("04", 32),
+				strings.Repeat("03", 32), strings.Repeat("05", 32))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer stop()
+
+	frozen, err := c.GetContractUpgrades([32]byte{})
+	require.NoError(t, err)
+
+	assert.Equal(t, strings.Repeat("\x01", 32), string(frozen.CodeHash[:]))
+	assert.False(t, frozen.Upgradable)
+	assert.Empty(t, frozen.Upgrades)
+
+	var contract [32]byte
+	copy(contract[:], strings.Repeat("\x02", 32))
+
+	upgradable, err := c.GetContractUpgrades(contract)
+	require.NoError(t, err)
+
+	assert.True(t, upgradable.Upgradable)
+	assert.Equal(t, byte(3), upgradable.Upgrader[0])
+
+	if assert.Len(t, upgradable.Upgrades, 1) {
+		assert.EqualValues(t, 4, upgradable.Upgrades[0].Block)
+		assert.Equal(t, byte(4), upgradable.Upgrades[0].TxID[31])
+		assert.Equal(t, upgradable.CodeHash, upgradable.Upgrades[0].CodeHash)
+	}
+
+	var unknown [32]byte
+	copy(unknown[:], strings.Repeat("\x09", 32))
+
+	_, err = c.GetContractUpgrades(unknown)
+	assert.Error(t, err)
+}
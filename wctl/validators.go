@@ -0,0 +1,78 @@
+package wctl
+
+import (
+	"github.com/valyala/fastjson"
+)
+
+var _ UnmarshalableJSON = (*Validators)(nil)
+
+// Validators are the accounts staking at least the minimum stake as of block
+// Block, with TotalStake PERLs staked between them.
+type Validators struct {
+	Block      uint64      `json:"block"`
+	TotalStake uint64      `json:"total_stake"`
+	Validators []Validator `json:"validators"`
+}
+
+// Validator is an account staking Stake PERLs, with Delegated PERLs delegated
+// to it by other accounts.
+type Validator struct {
+	PublicKey [32]byte `json:"public_key"`
+	Stake     uint64   `json:"stake"`
+	Delegated uint64   `json:"delegated"`
+
+	// LastActive is the last block the validator had a transaction applied
+	// at, nil should it never have been seen active.
+	LastActive *uint64 `json:"last_active"`
+
+	// Connected is whether the node is connected to the validator, and Uptime
+	// the number of seconds it has been connected for.
+	Connected bool   `json:"connected"`
+	Uptime    uint64 `json:"uptime"`
+}
+
+func (v *Validators) UnmarshalJSON(b []byte) error {
+	var parser fastjson.Parser
+
+	val, err := parser.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+
+	v.Block = val.GetUint64("block")
+	v.TotalStake = val.GetUint64("total_stake")
+	v.Validators = v.Validators[:0]
+
+	for _, o := range val.GetArray("validators") {
+		validator := Validator{
+			Stake:     o.GetUint64("stake"),
+			Delegated: o.GetUint64("delegated"),
+			Connected: o.GetBool("connected"),
+			Uptime:    o.GetUint64("uptime"),
+		}
+
+		if err := jsonHex(o, validator.PublicKey[:], "public_key"); err != nil {
+			return err
+		}
+
+		if lastActive := o.Get("last_active"); lastActive != nil && lastActive.Type() == fastjson.TypeNumber {
+			block := lastActive.GetUint64()
+			validator.LastActive = &block
+		}
+
+		v.Validators = append(v.Validators, validator)
+	}
+
+	return nil
+}
+
+// GetValidators calls the /validators endpoint of the API, returning the
+// current set of validators.
+func (c *Client) GetValidators() (*Validators, error) {
+	var res Validators
+	if err := c.RequestJSON(RouteValidators, ReqGet, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
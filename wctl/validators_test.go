@@ -0,0 +1,52 @@
+// +build unit
+
+package wctl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientValidators(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RouteValidators {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"block":12,"total_stake":30000,"validators":[`+
+			`{"public_key":"%s","stake":10000,"delegated":500,"last_active":11,"connected":true,"uptime":3600},`+
+			`{"public_key":"%s","stake":20000,"delegated":0,"last_active":null,"connected":false,"uptime":0}]}`,
+			strings.Repeat("01", 32), strings.Repeat("02", 32))
+	})
+	defer stop()
+
+	res, err := c.GetValidators()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 12, res.Block)
+	assert.EqualValues(t, 30000, res.TotalStake)
+
+	if !assert.Len(t, res.Validators, 2) {
+		return
+	}
+
+	assert.Equal(t, byte(1), res.Validators[0].PublicKey[0])
+	assert.EqualValues(t, 10000, res.Validators[0].Stake)
+	assert.EqualValues(t, 500, res.Validators[0].Delegated)
+	assert.True(t, res.Validators[0].Connected)
+	assert.EqualValues(t, 3600, res.Validators[0].Uptime)
+
+	if assert.NotNil(t, res.Validators[0].LastActive) {
+		assert.EqualValues(t, 11, *res.Validators[0].LastActive)
+	}
+
+	assert.Equal(t, byte(2), res.Validators[1].PublicKey[0])
+	assert.Nil(t, res.Validators[1].LastActive)
+	assert.False(t, res.Validators[1].Connected)
+}
@@ -0,0 +1,1001 @@
+// +build unit
+
+package wctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/perlin-network/noise/edwards25519"
+	"github.com/perlin-network/wavelet"
+	"github.com/perlin-network/wavelet/avl"
+	"github.com/perlin-network/wavelet/canonical"
+	"github.com/perlin-network/wavelet/security"
+	"github.com/perlin-network/wavelet/store"
+	"github.com/perlin-network/wavelet/sys"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fastjson"
+	"go.uber.org/atomic"
+)
+
+// fakeNode serves the subset of a node's HTTP API needed to construct a
+// Client, and pushes events every interval through its websockets.
+func fakeNode(t *testing.T, interval time.Duration) (Config, func()) {
+	cfg, _, cleanup := newFakeNode(t, interval, false)
+	return cfg, cleanup
+}
+
+// fakeTLSNode is fakeNode served over HTTPS, additionally returning the
+// certificate of the node.
+func fakeTLSNode(t *testing.T, interval time.Duration) (Config, *x509.Certificate, func()) {
+	return newFakeNode(t, interval, true)
+}
+
+func newFakeNode(t *testing.T, interval time.Duration, secure bool) (Config, *x509.Certificate, func()) {
+	zero := hex.EncodeToString(make([]byte, 32))
+	merkle := hex.EncodeToString(make([]byte, 16))
+
+	var (
+		upgrader websocket.Upgrader
+		wg       sync.WaitGroup
+		stop     = make(chan struct{})
+	)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == RouteLedger:
+			_, _ = fmt.Fprintf(w,
+				`{"public_key":"%s","block":{"merkle_root":"%s","height":1,"id":"%s"},"peers":[]}`,
+				zero, merkle, zero,
+			)
+		case r.URL.Path == RouteTime:
+			// The clock of the fake node runs an hour ahead.
+			_, _ = fmt.Fprintf(w, `{"time":%d}`, time.Now().Add(time.Hour).UnixNano()/int64(time.Millisecond))
+		case strings.HasPrefix(r.URL.Path, RouteAccount):
+			_, _ = fmt.Fprintf(w, `{"public_key":"%s","balance":1}`, zero)
+		case r.URL.Path == RouteName+"/alice":
+			_, _ = fmt.Fprintf(w,
+				`{"name":"alice","owner":"%s","target":"%s","expiry":10}`, zero, strings.Repeat("01", 32),
+			)
+		case r.URL.Path == RouteMempool:
+			_, _ = fmt.Fprint(w, `[]`)
+		case r.URL.Path == RouteMempool+"/count":
+			_, _ = fmt.Fprint(w, `{"count":2,"missing":1}`)
+		case strings.HasPrefix(r.URL.Path, RouteMempool+"/contains/"):
+			// Only the transaction with the zero ID is pending.
+			id := strings.TrimPrefix(r.URL.Path, RouteMempool+"/contains/")
+			_, _ = fmt.Fprintf(w, `{"id":"%s","contains":%t}`, id, id == zero)
+		case r.URL.Path == RouteTxRelay:
+			body, _ := ioutil.ReadAll(r.Body)
+			publicKey, _ := hex.DecodeString(r.Header.Get(canonical.HeaderPublicKey))
+			signature, _ := hex.DecodeString(r.Header.Get(canonical.HeaderSignature))
+
+			if err := canonical.VerifyJSON(
+				security.SchemeEd25519, publicKey, canonical.DomainRelay, body, signature,
+			); err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			_, _ = fmt.Fprintf(w, `{"id":"%s"}`, zero)
+		case strings.HasPrefix(r.URL.Path, "/poll/"):
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+
+			wg.Add(1)
+			defer wg.Done()
+			defer conn.Close()
+
+			for height := 2; ; height++ {
+				var msg string
+
+				switch r.URL.Path {
+				case RouteWSConsensus:
+					msg = fmt.Sprintf(
+						`{"mod":"consensus","event":"finalized","new_block_id":"%s","new_block_height":%d}`,
+						zero, height,
+					)
+				case RouteWSAccounts:
+					msg = fmt.Sprintf(
+						`{"mod":"accounts","event":"balance_updated","account_id":"%s","balance":%d,"time":"%s"}`,
+						zero, height, time.Now().Format(time.RFC3339),
+					)
+				}
+
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+					return
+				}
+
+				select {
+				case <-stop:
+					return
+				case <-time.After(interval):
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	if secure {
+		srv.StartTLS()
+	} else {
+		srv.Start()
+	}
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	cfg := Config{APIHost: host, APIPort: uint16(p), PrivateKey: key, UseHTTPS: secure}
+
+	return cfg, srv.Certificate(), func() {
+		close(stop)
+		srv.CloseClientConnections()
+		wg.Wait()
+		srv.Close()
+	}
+}
+
+func TestClientConcurrentUse(t *testing.T) {
+	cfg, stop := fakeNode(t, time.Millisecond)
+	defer stop()
+
+	c, err := NewClient(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var (
+		balancesLock sync.Mutex
+		balances     int
+	)
+
+	c.OnError = func(err error) {
+		t.Errorf("unexpected websocket error: %v", err)
+	}
+
+	c.OnBalanceUpdated = func(BalanceUpdate) {
+		balancesLock.Lock()
+		balances++
+		balancesLock.Unlock()
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				_, err := c.GetSelf()
+				assert.NoError(t, err)
+
+				_, err = c.LedgerStatus()
+				assert.NoError(t, err)
+			}
+
+			cancel, err := c.PollAccounts()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			// Cancelling a poller twice, or leaving it for Close, must be safe.
+			if i%2 == 0 {
+				cancel()
+				cancel()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	c.Close()
+	c.Close()
+
+	assert.True(t, c.Block.Load() > 1)
+
+	balancesLock.Lock()
+	assert.True(t, balances > 0)
+	balancesLock.Unlock()
+
+	c.socketsLock.Lock()
+	assert.Len(t, c.sockets, 0)
+	c.socketsLock.Unlock()
+}
+
+func TestClientSyncClock(t *testing.T) {
+	cfg, stop := fakeNode(t, 50*time.Millisecond)
+	defer stop()
+
+	cfg.SyncClock = true
+
+	c, err := NewClient(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	offset := c.Now().Sub(time.Now())
+	assert.True(t, offset > time.Hour-time.Second && offset < time.Hour+time.Second, offset)
+
+	skew, err := c.ClockSkew()
+	assert.NoError(t, err)
+	assert.True(t, skew > time.Hour-time.Second && skew < time.Hour+time.Second, skew)
+}
+
+func TestClientNextNonce(t *testing.T) {
+	var (
+		c  Client
+		wg sync.WaitGroup
+	)
+
+	nonces := make([][]uint64, 8)
+
+	for i := range nonces {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				nonces[i] = append(nonces[i], c.NextNonce())
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	seen := make(map[uint64]struct{})
+
+	for _, n := range nonces {
+		for j := range n {
+			if j > 0 {
+				assert.True(t, n[j] > n[j-1])
+			}
+
+			seen[n[j]] = struct{}{}
+		}
+	}
+
+	assert.Len(t, seen, 8*1000)
+
+	// Nonces keep increasing should the clock be corrected backwards.
+	last := c.NextNonce()
+	c.clockOffset.Store(int64(-time.Hour))
+	assert.Equal(t, last+1, c.NextNonce())
+}
+
+func TestClientPollTransactionsFiltered(t *testing.T) {
+	var upgrader websocket.Upgrader
+
+	queries := make(chan url.Values, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		assert.Equal(t, RouteWSTransactions, r.URL.Path)
+		queries <- r.URL.Query()
+
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c := &Client{Config: Config{APIHost: host, APIPort: uint16(p)}}
+
+	var sender [32]byte
+	sender[0] = 0xab
+
+	cancel, err := c.PollTransactionsFiltered(TxEventFilter{Sender: sender, Tag: 1, Event: "applied"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer cancel()
+
+	select {
+	case query := <-queries:
+		assert.Equal(t, url.Values{
+			"sender": {hex.EncodeToString(sender[:])},
+			"tag":    {"1"},
+			"event":  {"applied"},
+		}, query)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the websocket to connect")
+	}
+
+	// Zero filters are left out of the query altogether.
+	cancel, err = c.PollTransactions()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer cancel()
+
+	select {
+	case query := <-queries:
+		assert.Empty(t, query)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the websocket to connect")
+	}
+}
+
+func TestClientPollAccountsFiltered(t *testing.T) {
+	var upgrader websocket.Upgrader
+
+	queries := make(chan url.Values, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		assert.Equal(t, RouteWSAccounts, r.URL.Path)
+		queries <- r.URL.Query()
+
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c := &Client{Config: Config{APIHost: host, APIPort: uint16(p)}}
+
+	var id [32]byte
+	id[0] = 0xab
+
+	cancel, err := c.PollAccountsFiltered(id)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer cancel()
+
+	select {
+	case query := <-queries:
+		assert.Equal(t, url.Values{"id": {hex.EncodeToString(id[:])}}, query)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the websocket to connect")
+	}
+}
+
+func TestClientReconnectWS(t *testing.T) {
+	var (
+		upgrader websocket.Upgrader
+		conns    = atomic.NewUint32(0)
+	)
+
+	// Every connection is sent its ordinal, and then closed by the server.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"conn":%d}`, conns.Inc())))
+	}))
+	defer srv.Close()
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c := &Client{Config: Config{
+		APIHost: host,
+		APIPort: uint16(p),
+		Backoff: &Backoff{Initial: time.Millisecond},
+	}}
+
+	received := atomic.NewUint32(0)
+	closes := atomic.NewUint32(0)
+	reconnects := atomic.NewUint32(0)
+
+	disconnected := atomic.NewUint32(0)
+	reconnected := atomic.NewUint32(0)
+
+	c.OnDisconnect = func(route string, err error) {
+		assert.Equal(t, "/poll/test", route)
+		assert.Error(t, err)
+		disconnected.Inc()
+	}
+
+	c.OnReconnect = func(route string) {
+		assert.Equal(t, "/poll/test", route)
+		reconnected.Inc()
+	}
+
+	cancel, err := c.pollWSWithClose(context.Background(), "/poll/test", func(v *fastjson.Value) {
+		received.Inc()
+	}, func() {
+		closes.Inc()
+	}, func() {
+		reconnects.Inc()
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, waitFor(func() bool {
+		return received.Load() >= 3 && reconnects.Load() >= 3
+	}))
+
+	cancel()
+
+	assert.True(t, closes.Load() >= reconnects.Load())
+	assert.Equal(t, closes.Load(), disconnected.Load())
+	assert.Equal(t, reconnects.Load(), reconnected.Load())
+
+	c.socketsLock.Lock()
+	assert.Len(t, c.sockets, 0)
+	c.socketsLock.Unlock()
+}
+
+func TestClientRequestCtx(t *testing.T) {
+	release := make(chan struct{})
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte("ok"))
+	})
+	defer stop()
+	defer close(release)
+
+	// Requests are given up on past the deadline of their context.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.RequestCtx(ctx, "/", ReqGet, nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < time.Second)
+
+	// As well as once their context is cancelled, retries included.
+	c.interceptors = []Interceptor{BackoffInterceptor(Backoff{Initial: time.Millisecond})}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err = c.RequestCtx(ctx, "/", ReqGet, nil)
+	assert.Equal(t, context.Canceled, err)
+
+	// Requests with contexts which are already done are not made at all.
+	_, err = c.RequestCtx(ctx, "/", ReqGet, nil)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestClientAPIError(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/balance":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(
+				`{"status":"Bad Request","error":"sender current balance 1 is not enough: insufficient balance",` +
+					`"code":"insufficient_balance"}`,
+			))
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(http.StatusText(http.StatusTooManyRequests)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":"Not Found","error":"account not found"}`))
+		}
+	})
+	defer stop()
+
+	_, err := c.Request("/balance", ReqPost, nil)
+	assert.True(t, errors.Is(err, ErrInsufficientPerls))
+	assert.False(t, errors.Is(err, ErrInvalidSignature))
+
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		assert.Equal(t, "insufficient_balance", apiErr.ServerCode)
+		assert.Equal(t, "sender current balance 1 is not enough: insufficient balance", apiErr.Message)
+		assert.Equal(t, apiErr.Message, apiErr.Error())
+	}
+
+	// Errors are otherwise told apart by their status code.
+	_, err = c.Request("/limited", ReqGet, nil)
+	assert.True(t, errors.Is(err, ErrRateLimited))
+
+	_, err = c.Request("/account", ReqGet, nil)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.EqualError(t, err, "account not found")
+}
+
+func TestClientEstimateFee(t *testing.T) {
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RouteTxEstimate {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+
+		var p fastjson.Parser
+
+		v, err := p.ParseBytes(body)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, strings.Repeat("00", 32), string(v.GetStringBytes("sender")))
+		assert.Equal(t, 1, v.GetInt("tag"))
+		assert.Equal(t, "abcd", string(v.GetStringBytes("payload")))
+
+		_, _ = w.Write([]byte(`{"fee":2,"gas":1337}`))
+	})
+	defer stop()
+
+	estimate, err := c.EstimateFee(1, []byte{0xab, 0xcd})
+	assert.NoError(t, err)
+	assert.Equal(t, &FeeEstimate{Fee: 2, Gas: 1337}, estimate)
+}
+
+func TestClientSimulateContractCall(t *testing.T) {
+	var contract [32]byte
+	contract[0] = 0x01
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RouteContract+"/01"+strings.Repeat("00", 31)+"/call" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":"Not Found","error":"contract: not found"}`))
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+
+		var p fastjson.Parser
+
+		v, err := p.ParseBytes(body)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, strings.Repeat("00", 32), string(v.GetStringBytes("sender")))
+		assert.Equal(t, "balance", string(v.GetStringBytes("func")))
+		assert.Equal(t, "0100000000000000ff", string(v.GetStringBytes("params")))
+		assert.EqualValues(t, 10, v.GetUint64("amount"))
+		assert.EqualValues(t, 500, v.GetUint64("gas_limit"))
+
+		_, _ = w.Write([]byte(`{"result":"abcd","gas":42,"gas_limit_exceeded":false,"queued":1}`))
+	})
+	defer stop()
+
+	fn := FunctionCall{Name: "balance", Amount: 10, GasLimit: 500}
+	fn.AddParams(EncodeUint64(1), EncodeByte(0xff))
+
+	res, err := c.SimulateContractCall(contract, fn)
+	assert.NoError(t, err)
+	assert.Equal(t, &ContractCallResult{Result: []byte{0xab, 0xcd}, Gas: 42, Queued: 1}, res)
+
+	_, err = c.SimulateContractCall([32]byte{}, fn)
+	assert.Error(t, err)
+}
+
+func TestClientContractLogs(t *testing.T) {
+	var contract [32]byte
+	contract[0] = 0x01
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RouteContract+"/01"+strings.Repeat("00", 31)+"/logs" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":"Not Found","error":"contract: not found"}`))
+			return
+		}
+
+		q := r.URL.Query()
+
+		assert.Equal(t, "1", q.Get("offset"))
+		assert.Equal(t, "", q.Get("limit"))
+		assert.Equal(t, "7", q.Get("from_block"))
+
+		_, _ = w.Write([]byte(`{"total":3,"logs":[{"index":2,"block":8,"tx_id":"` + strings.Repeat("02", 32) +
+			`","topic":"6869","data":""}]}`))
+	})
+	defer stop()
+
+	var txID [32]byte
+	for i := range txID {
+		txID[i] = 0x02
+	}
+
+	res, err := c.ContractLogs(contract, 1, 0, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, &ContractLogList{
+		Total: 3,
+		Logs: []ContractLog{
+			{ContractID: contract, Block: 8, TxID: txID, Index: 2, Topic: []byte("hi"), Data: []byte{}},
+		},
+	}, res)
+
+	_, err = c.ContractLogs([32]byte{}, 0, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestClientGetAccountProof(t *testing.T) {
+	tree := avl.New(store.NewInmem())
+
+	var account [32]byte
+	account[0] = 0x01
+
+	wavelet.WriteAccountBalance(tree, account, 42)
+	wavelet.WriteAccountStake(tree, [32]byte{0x02}, 7)
+
+	root := tree.Checksum()
+
+	proof, err := wavelet.ProveAccount(tree, account)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RouteAccount+"/01"+strings.Repeat("00", 31)+"/proof" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var arena fastjson.Arena
+
+		o := arena.NewObject()
+		o.Set("id", arena.NewString(hex.EncodeToString(account[:])))
+
+		block := arena.NewObject()
+		block.Set("merkle_root", arena.NewString(hex.EncodeToString(root[:])))
+		block.Set("height", arena.NewNumberInt(3))
+		block.Set("id", arena.NewString(strings.Repeat("ab", 32)))
+		o.Set("block", block)
+
+		for key, p := range map[string]avl.Proof{
+			"balance": proof.Balance, "stake": proof.Stake, "reward": proof.Reward, "gas_balance": proof.GasBalance,
+		} {
+			path, lefts := arena.NewArray(), arena.NewArray()
+
+			for i := range p.Path {
+				path.SetArrayItem(i, arena.NewString(hex.EncodeToString(p.Path[i])))
+				lefts.SetArrayItem(i, arena.NewString(hex.EncodeToString(p.Lefts[i])))
+			}
+
+			field := arena.NewObject()
+			field.Set("path", path)
+			field.Set("lefts", lefts)
+			o.Set(key, field)
+		}
+
+		_, _ = w.Write(o.MarshalTo(nil))
+	})
+	defer stop()
+
+	res, err := c.GetAccountProof(account)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, proof, res.Proof)
+	assert.Equal(t, root, res.Block.MerkleRoot)
+	assert.EqualValues(t, 3, res.Block.Index)
+
+	balances, err := VerifyProof(res, root)
+	assert.NoError(t, err)
+	assert.Equal(t, wavelet.AccountBalances{Balance: 42}, balances)
+
+	// A proof is only valid against the root it was made against.
+	wavelet.WriteAccountBalance(tree, account, 43)
+
+	_, err = VerifyProof(res, tree.Checksum())
+	assert.Error(t, err)
+
+	_, err = c.GetAccountProof([32]byte{})
+	assert.Error(t, err)
+}
+
+func TestClientCraftBroadcastTransaction(t *testing.T) {
+	var received TxRequest
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != RouteTxSend {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if !assert.NoError(t, received.UnmarshalJSON(body)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		id := received.ID()
+		_, _ = fmt.Fprintf(w, `{"id":"%x"}`, id)
+	})
+	defer stop()
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c.PrivateKey = key
+	c.Block = atomic.NewUint64(5)
+
+	// Transactions are crafted without contacting the node.
+	req := c.CraftTransaction(byte(sys.TagTransfer), []byte{1, 2, 3})
+
+	assert.Equal(t, key.Public(), edwards25519.PublicKey(req.Sender))
+	assert.EqualValues(t, 5, req.Block)
+
+	tx := wavelet.NewSignedTransactionWithVersion(
+		req.Version, security.Scheme(req.Scheme), req.Sender, req.Nonce, req.Block, sys.Tag(req.Tag), req.Payload,
+		req.Signature,
+	)
+	assert.True(t, tx.VerifySignature())
+
+	// Crafted transactions survive being stored as JSON.
+	buf, err := req.MarshalJSON()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var stored TxRequest
+	if assert.NoError(t, stored.UnmarshalJSON(buf)) {
+		assert.Equal(t, *req, stored)
+	}
+
+	res, err := c.BroadcastTransaction(&stored)
+	if assert.NoError(t, err) {
+		assert.Equal(t, *req, received)
+		assert.Equal(t, tx.ID, res.ID)
+		assert.Equal(t, tx.ID, req.ID())
+	}
+
+	assert.Error(t, stored.UnmarshalJSON([]byte(`{"sender":"00"}`)))
+	assert.Error(t, stored.UnmarshalJSON(bytes.Replace(buf, []byte(`"nonce"`), []byte(`"n"`), 1)))
+}
+
+func TestClientPollCtx(t *testing.T) {
+	cfg, stop := fakeNode(t, time.Millisecond)
+	defer stop()
+
+	c, err := NewClient(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	sockets := func() int {
+		c.socketsLock.Lock()
+		defer c.socketsLock.Unlock()
+
+		return len(c.sockets)
+	}
+
+	before := sockets()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err = c.PollAccountsCtx(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, before+1, sockets())
+
+	// Cancelling the context closes the websocket.
+	cancel()
+
+	assert.NoError(t, waitFor(func() bool {
+		return sockets() == before
+	}))
+}
+
+func TestClientSendContract(t *testing.T) {
+	var received TxRequest
+
+	c, stop := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !assert.NoError(t, received.UnmarshalJSON(body)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"id":"%x"}`, received.ID())
+	})
+	defer stop()
+
+	_, key, err := edwards25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c.PrivateKey = key
+	c.Block = atomic.NewUint64(0)
+
+	code, err := ioutil.ReadFile("../testdata/transfer_back.wasm")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	spawn := ContractSpawn{Code: code, GasDeposit: 10}
+	spawn.AddParams(EncodeString("name"), EncodeUint64(42))
+
+	_, err = c.SendContract(spawn)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.EqualValues(t, sys.TagContract, received.Tag)
+
+	ct, err := wavelet.ParseContract(received.Payload)
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, DefaultSpawnGasLimit, ct.GasLimit)
+		assert.EqualValues(t, 10, ct.GasDeposit)
+		assert.Equal(t, append(EncodeString("name"), EncodeUint64(42)...), ct.Params)
+		assert.Equal(t, code, ct.Code)
+	}
+
+	_, err = c.SendContract(ContractSpawn{Code: []byte("not wasm")})
+	assert.Error(t, err)
+}
+
+func TestParseParam(t *testing.T) {
+	valid := map[string][]byte{
+		"Sfoo":    EncodeString("foo"),
+		"Bfoo":    EncodeBytes([]byte("foo")),
+		"1255":    EncodeByte(255),
+		"21337":   EncodeUint16(1337),
+		"4666":    EncodeUint32(666),
+		"8314":    EncodeUint64(314),
+		"Hbada55": {0xba, 0xda, 0x55},
+	}
+
+	for arg, expected := range valid {
+		param, err := ParseParam(arg)
+		assert.NoError(t, err, arg)
+		assert.Equal(t, expected, param, arg)
+	}
+
+	for _, arg := range []string{"", "1256", "8-1", "Hxyz", "Xfoo"} {
+		_, err := ParseParam(arg)
+		assert.Error(t, err, arg)
+	}
+}
+
+func TestClientKeepAlive(t *testing.T) {
+	var (
+		upgrader websocket.Upgrader
+
+		lock    sync.Mutex
+		remotes = make(map[string]struct{})
+		resumed bool
+	)
+
+	zero := hex.EncodeToString(make([]byte, 32))
+	merkle := hex.EncodeToString(make([]byte, 16))
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == RouteWSConsensus {
+			lock.Lock()
+			resumed = r.TLS.DidResume
+			lock.Unlock()
+
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+
+			defer conn.Close()
+
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+
+		lock.Lock()
+		remotes[r.RemoteAddr] = struct{}{}
+		lock.Unlock()
+
+		_, _ = fmt.Fprintf(w, `{"public_key":"%s","block":{"merkle_root":"%s","height":1,"id":"%s"},"peers":[]}`,
+			zero, merkle, zero)
+	}))
+
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	port, err := strconv.ParseUint(u.Port(), 10, 16)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	sum := sha256.Sum256(srv.Certificate().Raw)
+
+	c, err := NewClient(Config{
+		APIHost:            u.Hostname(),
+		APIPort:            uint16(port),
+		UseHTTPS:           true,
+		PinnedCertificates: []string{hex.EncodeToString(sum[:])},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	defer c.Close()
+
+	for i := 0; i < 10; i++ {
+		_, err := c.LedgerStatus()
+		assert.NoError(t, err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Requests are made over the same connection, and the websocket resumes
+	// the TLS session of the first request.
+	assert.Len(t, remotes, 1)
+	assert.True(t, resumed)
+}
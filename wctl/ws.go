@@ -1,11 +1,17 @@
 package wctl
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/gorilla/websocket"
+	"github.com/perlin-network/wavelet/events"
 	"github.com/valyala/fastjson"
+	"go.uber.org/atomic"
 )
 
 const (
@@ -19,39 +25,166 @@ const (
 
 // EstablishWS will create a websocket connection.
 func (c *Client) EstablishWS(path string) (*websocket.Conn, error) {
+	return c.EstablishWSCtx(context.Background(), path)
+}
+
+// EstablishWSCtx is EstablishWS, which gives up on connecting once ctx is
+// done. path may carry a query string. Should the client have multiple
+// endpoints, the websocket is established with the first endpoint to accept
+// it, starting from the endpoint which served the client last.
+func (c *Client) EstablishWSCtx(ctx context.Context, path string) (*websocket.Conn, error) {
+	ref, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.endpoints == nil {
+		conn, _, err := c.dialWS(ctx, fmt.Sprintf("%s:%d", c.APIHost, c.APIPort), ref)
+		return conn, err
+	}
+
+	for _, i := range c.endpoints.order(false) {
+		var (
+			conn *websocket.Conn
+			res  *http.Response
+		)
+
+		conn, res, err = c.dialWS(ctx, c.endpoints.list[i].String(), ref)
+		if err == nil {
+			c.endpoints.succeeded(i, true)
+			return conn, nil
+		}
+
+		// Only fail over should the endpoint be unreachable or unavailable,
+		// rather than reject the websocket.
+		if ctx.Err() != nil || (res != nil && !retryableStatus(res.StatusCode)) {
+			return nil, err
+		}
+
+		c.endpoints.failed(i)
+	}
+
+	return nil, err
+}
+
+// dialWS establishes a websocket to path of the node at host.
+func (c *Client) dialWS(ctx context.Context, host string, path *url.URL) (*websocket.Conn, *http.Response, error) {
 	prot := "ws"
 	if c.UseHTTPS {
 		prot = "wss"
 	}
 
-	host := fmt.Sprintf("%s:%d", c.APIHost, c.APIPort)
 	uri := url.URL{
-		Scheme: prot,
-		Host:   host,
-		Path:   path,
+		Scheme:   prot,
+		Host:     host,
+		Path:     path.Path,
+		RawQuery: path.RawQuery,
 	}
 
 	dialer := &websocket.Dialer{
 		HandshakeTimeout: c.Config.Timeout,
+		TLSClientConfig:  c.tlsConfig,
 	}
 
-	conn, _, err := dialer.Dial(uri.String(), nil)
+	if c.proxy != nil {
+		dialer.Proxy = func(req *http.Request) (*url.URL, error) {
+			return c.proxy(req.URL)
+		}
+	}
 
-	return conn, err
+	return dialer.DialContext(ctx, uri.String(), nil)
 }
 
-// callback is spawned in a goroutine
-func (c *Client) pollWS(path string, callback func(*fastjson.Value)) (func(), error) {
-	ws, err := c.EstablishWS(path)
+// callback is spawned in a goroutine. The websocket is closed once ctx is done,
+// or the returned function is called.
+func (c *Client) pollWS(ctx context.Context, path string, callback func(*fastjson.Value)) (func(), error) {
+	return c.pollWSWithClose(ctx, path, callback, nil, nil)
+}
+
+// pollWSWithClose is pollWS, with onClose being called should the websocket
+// be closed for any reason other than being cancelled, and onReconnect being
+// called should it then be reconnected according to Config.Backoff.
+func (c *Client) pollWSWithClose( // nolint:gocognit
+	ctx context.Context, path string, callback func(*fastjson.Value), onClose, onReconnect func(),
+) (func(), error) {
+	ws, err := c.EstablishWSCtx(ctx, path)
 	if err != nil {
 		return nil, err
 	}
 
+	var wsLock sync.Mutex
+
+	stopped := atomic.NewBool(false)
+	stop := make(chan struct{})
+
+	// reconnect replaces ws with a new connection, returning false should
+	// the websocket have been cancelled, or the backoff policy given up.
+	reconnect := func() bool {
+		if c.Config.Backoff == nil {
+			return false
+		}
+
+		return c.Config.Backoff.retry(stop, func() error {
+			conn, err := c.EstablishWSCtx(ctx, path)
+			if err != nil {
+				return err
+			}
+
+			wsLock.Lock()
+			defer wsLock.Unlock()
+
+			if stopped.Load() {
+				_ = conn.Close()
+				return Permanent(errors.New("websocket cancelled"))
+			}
+
+			ws = conn
+
+			return nil
+		}) == nil
+	}
+
 	go func() {
 		for {
-			_, message, err := ws.ReadMessage()
+			wsLock.Lock()
+			conn := ws
+			wsLock.Unlock()
+
+			_, message, err := conn.ReadMessage()
 			if err != nil {
-				c.OnError(err)
+				// Errors caused by the socket being cancelled are not reported.
+				if stopped.Load() {
+					return
+				}
+
+				if onClose != nil {
+					onClose()
+				}
+
+				if c.OnDisconnect != nil {
+					c.OnDisconnect(path, err)
+				}
+
+				if reconnect() {
+					if onReconnect != nil {
+						onReconnect()
+					}
+
+					if c.OnReconnect != nil {
+						c.OnReconnect(path)
+					}
+
+					continue
+				}
+
+				if stopped.Load() {
+					return
+				}
+
+				if c.OnError != nil {
+					c.OnError(err)
+				}
+
 				return
 			}
 
@@ -61,7 +194,9 @@ func (c *Client) pollWS(path string, callback func(*fastjson.Value)) (func(), er
 
 				o, err := p.ParseBytes(message)
 				if err != nil {
-					c.OnError(err)
+					if c.OnError != nil {
+						c.OnError(err)
+					}
 					return
 				}
 
@@ -70,83 +205,63 @@ func (c *Client) pollWS(path string, callback func(*fastjson.Value)) (func(), er
 		}
 	}()
 
-	cancel := func() {
-		// Also kills the for loop above
-		ws.Close()
+	c.socketsLock.Lock()
+	defer c.socketsLock.Unlock()
+
+	if c.sockets == nil {
+		c.sockets = make(map[uint64]func())
 	}
 
-	c.stopSockets = append(c.stopSockets, cancel)
+	id := c.socketsID
+	c.socketsID++
 
-	return cancel, nil
-}
+	cancel := func() {
+		if !stopped.CAS(false, true) {
+			return
+		}
 
-type ErrInvalidPayload struct {
-	JSONValue string
-}
+		close(stop)
 
-type ErrMismatchMod struct {
-	ErrInvalidPayload
-	WantedMod string
-	GotMod    string
-}
+		c.socketsLock.Lock()
+		delete(c.sockets, id)
+		c.socketsLock.Unlock()
 
-func checkMod(v *fastjson.Value, mod string) error {
-	if string(v.GetStringBytes("mod")) != mod {
-		return errMismatchMod(v, mod)
+		// Also kills the for loop above
+		wsLock.Lock()
+		_ = ws.Close()
+		wsLock.Unlock()
 	}
 
-	return nil
-}
+	c.sockets[id] = cancel
 
-func errMismatchMod(v *fastjson.Value, wantedMod string) *ErrMismatchMod {
-	return &ErrMismatchMod{
-		ErrInvalidPayload: ErrInvalidPayload{
-			JSONValue: v.String(),
-		},
-		WantedMod: wantedMod,
-		GotMod:    string(v.GetStringBytes("mod")),
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				cancel()
+			case <-stop:
+			}
+		}()
 	}
-}
 
-func (err *ErrMismatchMod) Error() string {
-	return "Mismatched \"mod\" field in JSON: expected " + err.WantedMod +
-		", got " + err.GotMod
-}
-
-type ErrInvalidEvent struct {
-	ErrInvalidPayload
-	Event string
-}
-
-func errInvalidEvent(v *fastjson.Value, event string) *ErrInvalidEvent { // nolint:interfacer
-	return &ErrInvalidEvent{
-		ErrInvalidPayload: ErrInvalidPayload{
-			JSONValue: v.String(),
-		},
-		Event: event,
-	}
+	return cancel, nil
 }
 
-func (err *ErrInvalidEvent) Error() string {
-	return "Unsupported event: " + err.Event
-}
+type (
+	ErrInvalidPayload = events.ErrInvalidPayload
+	ErrMismatchMod    = events.ErrMismatchMod
+	ErrInvalidEvent   = events.ErrInvalidEvent
+	ErrUnmarshalFail  = events.ErrUnmarshalFail
+)
 
-type ErrUnmarshalFail struct {
-	ErrInvalidPayload
-	Key        string
-	Underlying error
+func checkMod(v *fastjson.Value, mod string) error {
+	return events.CheckMod(v, mod)
 }
 
-func errUnmarshalFail(v *fastjson.Value, key string, err error) *ErrUnmarshalFail { // nolint:interfacer
-	return &ErrUnmarshalFail{
-		ErrInvalidPayload: ErrInvalidPayload{
-			JSONValue: v.String(),
-		},
-		Key:        key,
-		Underlying: err,
-	}
+func errInvalidEvent(v *fastjson.Value, event string) *ErrInvalidEvent {
+	return events.NewErrInvalidEvent(v, event)
 }
 
-func (err *ErrUnmarshalFail) Error() string {
-	return "Error unmarshalling key " + err.Key + ": " + err.Underlying.Error()
+func errUnmarshalFail(v *fastjson.Value, key string, err error) *ErrUnmarshalFail {
+	return events.NewErrUnmarshalFail(v, key, err)
 }
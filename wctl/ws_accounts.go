@@ -1,12 +1,40 @@
 package wctl
 
-import "github.com/valyala/fastjson"
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/valyala/fastjson"
+)
 
 func (c *Client) PollAccounts() (func(), error) {
-	return c.pollWS(RouteWSAccounts, func(o *fastjson.Value) {
+	return c.PollAccountsCtx(context.Background())
+}
+
+// PollAccountsCtx is PollAccounts, with the websocket being closed once ctx is done.
+func (c *Client) PollAccountsCtx(ctx context.Context) (func(), error) {
+	return c.pollAccounts(ctx, RouteWSAccounts)
+}
+
+// PollAccountsFiltered is PollAccounts, with the node only sending the
+// events of the account id.
+func (c *Client) PollAccountsFiltered(id [32]byte) (func(), error) {
+	return c.PollAccountsFilteredCtx(context.Background(), id)
+}
+
+// PollAccountsFilteredCtx is PollAccountsFiltered, with the websocket being
+// closed once ctx is done.
+func (c *Client) PollAccountsFilteredCtx(ctx context.Context, id [32]byte) (func(), error) {
+	return c.pollAccounts(ctx, RouteWSAccounts+"?id="+hex.EncodeToString(id[:]))
+}
+
+func (c *Client) pollAccounts(ctx context.Context, path string) (func(), error) {
+	return c.pollWS(ctx, path, func(o *fastjson.Value) {
 		var err error
 
-		if err := checkMod(o, "accounts"); err != nil {
+		if err := checkMod(o, log.ModuleAccounts); err != nil {
 			if c.OnError != nil {
 				c.OnError(err)
 			}
@@ -14,15 +42,15 @@ func (c *Client) PollAccounts() (func(), error) {
 		}
 
 		switch ev := jsonString(o, "event"); ev {
-		case "balance_updated":
+		case events.EventBalanceUpdated:
 			err = parseAccountsBalanceUpdated(c, o)
-		case "gas_balance_updated":
+		case events.EventGasBalanceUpdated:
 			err = parseAccountsGasBalanceUpdated(c, o)
-		case "num_pages_updated":
+		case events.EventNumPagesUpdated:
 			err = parseAccountNumPagesUpdated(c, o)
-		case "stake_updated":
+		case events.EventStakeUpdated:
 			err = parseAccountStakeUpdated(c, o)
-		case "reward_updated":
+		case events.EventRewardUpdated:
 			err = parseAccountRewardUpdated(c, o)
 		default:
 			err = errInvalidEvent(o, ev)
@@ -39,16 +67,10 @@ func (c *Client) PollAccounts() (func(), error) {
 func parseAccountsBalanceUpdated(c *Client, v *fastjson.Value) error {
 	var a BalanceUpdate
 
-	if err := jsonHex(v, a.AccountID[:], "account_id"); err != nil {
-		return err
-	}
-
-	if err := jsonTime(v, &a.Time, "time"); err != nil {
+	if err := a.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	a.Balance = v.GetUint64("balance")
-
 	if c.OnBalanceUpdated != nil {
 		c.OnBalanceUpdated(a)
 	}
@@ -59,16 +81,10 @@ func parseAccountsBalanceUpdated(c *Client, v *fastjson.Value) error {
 func parseAccountsGasBalanceUpdated(c *Client, v *fastjson.Value) error {
 	var a GasBalanceUpdate
 
-	if err := jsonHex(v, a.AccountID[:], "account_id"); err != nil {
+	if err := a.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	if err := jsonTime(v, &a.Time, "time"); err != nil {
-		return err
-	}
-
-	a.GasBalance = v.GetUint64("gas_balance")
-
 	if c.OnGasBalanceUpdated != nil {
 		c.OnGasBalanceUpdated(a)
 	}
@@ -79,16 +95,10 @@ func parseAccountsGasBalanceUpdated(c *Client, v *fastjson.Value) error {
 func parseAccountNumPagesUpdated(c *Client, v *fastjson.Value) error {
 	var a NumPagesUpdated
 
-	if err := jsonHex(v, a.AccountID[:], "account_id"); err != nil {
+	if err := a.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	if err := jsonTime(v, &a.Time, "time"); err != nil {
-		return err
-	}
-
-	a.NumPages = v.GetUint64("num_pages_updated")
-
 	if c.OnNumPagesUpdated != nil {
 		c.OnNumPagesUpdated(a)
 	}
@@ -99,16 +109,10 @@ func parseAccountNumPagesUpdated(c *Client, v *fastjson.Value) error {
 func parseAccountStakeUpdated(c *Client, v *fastjson.Value) error {
 	var a StakeUpdated
 
-	if err := jsonHex(v, a.AccountID[:], "account_id"); err != nil {
-		return err
-	}
-
-	if err := jsonTime(v, &a.Time, "time"); err != nil {
+	if err := a.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	a.Stake = v.GetUint64("stake")
-
 	if c.OnStakeUpdated != nil {
 		c.OnStakeUpdated(a)
 	}
@@ -119,16 +123,10 @@ func parseAccountStakeUpdated(c *Client, v *fastjson.Value) error {
 func parseAccountRewardUpdated(c *Client, v *fastjson.Value) error {
 	var a RewardUpdated
 
-	if err := jsonHex(v, a.AccountID[:], "account_id"); err != nil {
+	if err := a.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	if err := jsonTime(v, &a.Time, "time"); err != nil {
-		return err
-	}
-
-	a.Reward = v.GetUint64("reward")
-
 	if c.OnRewardUpdated != nil {
 		c.OnRewardUpdated(a)
 	}
@@ -1,14 +1,31 @@
 package wctl
 
 import (
+	"context"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
 	"github.com/valyala/fastjson"
 )
 
 func (c *Client) pollConsensus() (func(), error) {
-	return c.pollWS(RouteWSConsensus, func(v *fastjson.Value) {
+	// Blocks finalized while disconnected are caught up on once reconnected.
+	onReconnect := func() {
+		status, err := c.LedgerStatus()
+		if err != nil {
+			if c.OnError != nil {
+				c.OnError(err)
+			}
+			return
+		}
+
+		c.Block.Store(status.Block.Index)
+	}
+
+	return c.pollWSWithClose(context.Background(), RouteWSConsensus, func(v *fastjson.Value) {
 		var err error
 
-		if err := checkMod(v, "consensus"); err != nil {
+		if err := checkMod(v, log.ModuleConsensus); err != nil {
 			if c.OnError != nil {
 				c.OnError(err)
 			}
@@ -18,10 +35,16 @@ func (c *Client) pollConsensus() (func(), error) {
 		switch ev := jsonString(v, "event"); ev {
 		case "pull-transactions":
 			// err = parseConsensusProposal(c, v)
-		case "proposal":
+		case events.EventProposal:
 			err = parseConsensusProposal(c, v)
-		case "finalized":
+		case events.EventFinalized:
 			err = parseConsensusFinalized(c, v)
+		case events.EventValidatorJoined:
+			err = parseValidatorJoin(c, v)
+		case events.EventValidatorLeft:
+			err = parseValidatorLeave(c, v)
+		case events.EventRound:
+			err = parseRound(c, v)
 		default:
 			err = errInvalidEvent(v, ev)
 		}
@@ -31,7 +54,7 @@ func (c *Client) pollConsensus() (func(), error) {
 				c.OnError(err)
 			}
 		}
-	})
+	}, nil, onReconnect)
 }
 
 /* TODO
@@ -43,14 +66,10 @@ func parseConsensusPullTxs(c *Client, v *fastjson.Value) error {
 func parseConsensusProposal(c *Client, v *fastjson.Value) error {
 	var p Proposal
 
-	if err := jsonHex(v, p.BlockID[:], "block_id"); err != nil {
+	if err := p.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	p.BlockIndex = v.GetUint64("block_index")
-	p.NumTxs = v.GetUint64("num_transactions")
-	p.Message = string(v.GetStringBytes("message"))
-
 	if c.OnProposal != nil {
 		c.OnProposal(p)
 	}
@@ -61,18 +80,10 @@ func parseConsensusProposal(c *Client, v *fastjson.Value) error {
 func parseConsensusFinalized(c *Client, v *fastjson.Value) error {
 	var f Finalized
 
-	if err := jsonHex(v, f.BlockID[:], "new_block_id"); err != nil {
+	if err := f.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	f.BlockHeight = v.GetUint64("new_block_height")
-
-	f.NumApplied = v.GetInt("num_applied_tx")
-	f.NumRejected = v.GetInt("num_rejected_tx")
-	f.NumPruned = v.GetInt("num_pruned_tx")
-
-	f.Message = string(v.GetStringBytes("message"))
-
 	c.Block.Store(f.BlockHeight)
 
 	if c.OnFinalized != nil {
@@ -81,3 +92,45 @@ func parseConsensusFinalized(c *Client, v *fastjson.Value) error {
 
 	return nil
 }
+
+func parseValidatorJoin(c *Client, v *fastjson.Value) error {
+	var u ValidatorUpdate
+
+	if err := u.UnmarshalValue(v); err != nil {
+		return err
+	}
+
+	if c.OnValidatorJoin != nil {
+		c.OnValidatorJoin(ValidatorJoin{ValidatorUpdate: u})
+	}
+
+	return nil
+}
+
+func parseValidatorLeave(c *Client, v *fastjson.Value) error {
+	var u ValidatorUpdate
+
+	if err := u.UnmarshalValue(v); err != nil {
+		return err
+	}
+
+	if c.OnValidatorLeave != nil {
+		c.OnValidatorLeave(ValidatorLeave{ValidatorUpdate: u})
+	}
+
+	return nil
+}
+
+func parseRound(c *Client, v *fastjson.Value) error {
+	var r RoundFinalized
+
+	if err := r.UnmarshalValue(v); err != nil {
+		return err
+	}
+
+	if c.OnRound != nil {
+		c.OnRound(r)
+	}
+
+	return nil
+}
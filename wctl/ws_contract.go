@@ -1,13 +1,24 @@
 package wctl
 
-import "github.com/valyala/fastjson"
+import (
+	"context"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/valyala/fastjson"
+)
 
 func (c *Client) PollContracts() (func(), error) {
-	return c.pollWS(RouteWSContracts, func(v *fastjson.Value) {
+	return c.PollContractsCtx(context.Background())
+}
+
+// PollContractsCtx is PollContracts, with the websocket being closed once ctx is done.
+func (c *Client) PollContractsCtx(ctx context.Context) (func(), error) {
+	return c.pollWS(ctx, RouteWSContracts, func(v *fastjson.Value) {
 		var err error
 
 		for _, o := range v.GetArray() {
-			if err := checkMod(o, "contract"); err != nil {
+			if err := checkMod(o, log.ModuleContract); err != nil {
 				if c.OnError != nil {
 					c.OnError(err)
 				}
@@ -15,9 +26,9 @@ func (c *Client) PollContracts() (func(), error) {
 			}
 
 			switch ev := jsonString(o, "event"); ev {
-			case "gas":
+			case events.EventContractGas:
 				err = parseContractGas(c, o)
-			case "log":
+			case events.EventContractLog:
 				err = parseContractLog(c, o)
 			default:
 				err = errInvalidEvent(o, ev)
@@ -35,22 +46,10 @@ func (c *Client) PollContracts() (func(), error) {
 func parseContractGas(c *Client, v *fastjson.Value) error {
 	var g ContractGas
 
-	if err := jsonHex(v, g.SenderID[:], "sender_id"); err != nil {
-		return err
-	}
-
-	if err := jsonHex(v, g.ContractID[:], "contract_id"); err != nil {
-		return err
-	}
-
-	if err := jsonTime(v, &g.Time, "time"); err != nil {
+	if err := g.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	g.Gas = v.GetUint64("gas")
-	g.GasLimit = v.GetUint64("gas_limit")
-	g.Message = string(v.GetStringBytes("message"))
-
 	if c.OnContractGas != nil {
 		c.OnContractGas(g)
 	}
@@ -61,16 +60,10 @@ func parseContractGas(c *Client, v *fastjson.Value) error {
 func parseContractLog(c *Client, v *fastjson.Value) error {
 	var l ContractLog
 
-	if err := jsonHex(v, l.ContractID[:], "contract_id"); err != nil {
+	if err := l.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	if err := jsonTime(v, &l.Time, "time"); err != nil {
-		return err
-	}
-
-	l.Message = string(v.GetStringBytes("message"))
-
 	if c.OnContractLog != nil {
 		c.OnContractLog(l)
 	}
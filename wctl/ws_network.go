@@ -1,12 +1,23 @@
 package wctl
 
-import "github.com/valyala/fastjson"
+import (
+	"context"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
+	"github.com/valyala/fastjson"
+)
 
 func (c *Client) PollNetwork() (func(), error) {
-	return c.pollWS(RouteWSNetwork, func(v *fastjson.Value) {
+	return c.PollNetworkCtx(context.Background())
+}
+
+// PollNetworkCtx is PollNetwork, with the websocket being closed once ctx is done.
+func (c *Client) PollNetworkCtx(ctx context.Context) (func(), error) {
+	return c.pollWS(ctx, RouteWSNetwork, func(v *fastjson.Value) {
 		var err error
 
-		if err := checkMod(v, "network"); err != nil {
+		if err := checkMod(v, log.ModuleNetwork); err != nil {
 			if c.OnError != nil {
 				c.OnError(err)
 			}
@@ -14,10 +25,12 @@ func (c *Client) PollNetwork() (func(), error) {
 		}
 
 		switch ev := jsonString(v, "event"); ev {
-		case "joined":
+		case events.EventPeerJoined:
 			err = parsePeerJoin(c, v)
-		case "left":
+		case events.EventPeerLeft:
 			err = parsePeerLeave(c, v)
+		case events.EventPeerBanned:
+			err = parsePeerBan(c, v)
 		default:
 			err = errInvalidEvent(v, ev)
 		}
@@ -31,18 +44,8 @@ func (c *Client) PollNetwork() (func(), error) {
 }
 
 func parsePeerUpdate(v *fastjson.Value) (p PeerUpdate, err error) {
-	if err = jsonHex(v, p.AccountID[:], "public_key"); err != nil {
-		return
-	}
-
-	if err = jsonTime(v, &p.Time, "time"); err != nil {
-		return
-	}
-
-	p.Address = string(v.GetStringBytes("address"))
-	p.Message = string(v.GetStringBytes("message"))
-
-	return p, nil
+	err = p.UnmarshalValue(v)
+	return
 }
 
 func parsePeerJoin(c *Client, v *fastjson.Value) error {
@@ -52,7 +55,7 @@ func parsePeerJoin(c *Client, v *fastjson.Value) error {
 	}
 
 	if c.OnPeerJoin != nil {
-		c.OnPeerJoin(PeerJoin{u})
+		c.OnPeerJoin(PeerJoin{PeerUpdate: u})
 	}
 
 	return nil
@@ -65,7 +68,20 @@ func parsePeerLeave(c *Client, v *fastjson.Value) error {
 	}
 
 	if c.OnPeerLeave != nil {
-		c.OnPeerLeave(PeerLeave{u})
+		c.OnPeerLeave(PeerLeave{PeerUpdate: u})
+	}
+
+	return nil
+}
+
+func parsePeerBan(c *Client, v *fastjson.Value) error {
+	var b PeerBan
+	if err := b.UnmarshalValue(v); err != nil {
+		return err
+	}
+
+	if c.OnPeerBan != nil {
+		c.OnPeerBan(b)
 	}
 
 	return nil
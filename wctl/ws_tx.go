@@ -1,17 +1,79 @@
 package wctl
 
 import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+
+	"github.com/perlin-network/wavelet/events"
+	"github.com/perlin-network/wavelet/log"
 	"github.com/valyala/fastjson"
 )
 
+// TxEventFilter narrows down the transaction events the node sends over the
+// websocket. Zero fields match all events.
+type TxEventFilter struct {
+	ID     [32]byte
+	Sender [32]byte
+	Tag    byte
+
+	// Event is the name of the event, such as events.EventTxApplied.
+	Event string
+}
+
+func (f TxEventFilter) values() url.Values {
+	vals := url.Values{}
+
+	if f.ID != ([32]byte{}) {
+		vals.Set("id", hex.EncodeToString(f.ID[:]))
+	}
+
+	if f.Sender != ([32]byte{}) {
+		vals.Set("sender", hex.EncodeToString(f.Sender[:]))
+	}
+
+	if f.Tag != 0 {
+		vals.Set("tag", strconv.FormatUint(uint64(f.Tag), 10))
+	}
+
+	if f.Event != "" {
+		vals.Set("event", f.Event)
+	}
+
+	return vals
+}
+
 // PollTransactions calls the callback for each WS event received. On error, the
 // callback may be called twice.
 func (c *Client) PollTransactions() (func(), error) {
-	return c.pollWS(RouteWSTransactions, func(v *fastjson.Value) {
+	return c.PollTransactionsCtx(context.Background())
+}
+
+// PollTransactionsCtx is PollTransactions, with the websocket being closed once ctx is done.
+func (c *Client) PollTransactionsCtx(ctx context.Context) (func(), error) {
+	return c.PollTransactionsFilteredCtx(ctx, TxEventFilter{})
+}
+
+// PollTransactionsFiltered is PollTransactions, with the node only sending
+// the events matching filter.
+func (c *Client) PollTransactionsFiltered(filter TxEventFilter) (func(), error) {
+	return c.PollTransactionsFilteredCtx(context.Background(), filter)
+}
+
+// PollTransactionsFilteredCtx is PollTransactionsFiltered, with the websocket
+// being closed once ctx is done.
+func (c *Client) PollTransactionsFilteredCtx(ctx context.Context, filter TxEventFilter) (func(), error) {
+	path := RouteWSTransactions
+	if vals := filter.values(); len(vals) > 0 {
+		path += "?" + vals.Encode()
+	}
+
+	return c.pollWS(ctx, path, func(v *fastjson.Value) {
 		var err error
 
 		for _, o := range v.GetArray() {
-			if err := checkMod(o, "tx"); err != nil {
+			if err := checkMod(o, log.ModuleTX); err != nil {
 				if c.OnError != nil {
 					c.OnError(err)
 				}
@@ -19,12 +81,14 @@ func (c *Client) PollTransactions() (func(), error) {
 			}
 
 			switch ev := jsonString(o, "event"); {
-			case ev == "applied":
+			case ev == events.EventTxApplied:
 				err = parseTxApplied(c, o)
-			case ev == "gossip" && jsonString(o, "level") == "error":
+			case ev == events.EventTxGossip && jsonString(o, "level") == "error":
 				err = parseTxGossipError(c, o)
-			case ev == "failed":
+			case ev == events.EventTxRejected || ev == events.EventTxFailed:
 				err = parseTxFailed(c, o)
+			case ev == events.EventTxConflict:
+				err = parseTxConflict(c, o)
 			default:
 				err = errInvalidEvent(o, ev)
 			}
@@ -39,21 +103,10 @@ func (c *Client) PollTransactions() (func(), error) {
 }
 
 // parse<mod><event>
-
 func parseTxApplied(c *Client, v *fastjson.Value) error {
 	var t TxApplied
 
-	if err := jsonHex(v, t.TxID[:], "tx_id"); err != nil {
-		return err
-	}
-
-	if err := jsonHex(v, t.SenderID[:], "sender_id"); err != nil {
-		return err
-	}
-
-	t.Tag = byte(v.GetUint("tag"))
-
-	if err := jsonTime(v, &t.Time, "time"); err != nil {
+	if err := t.UnmarshalValue(v); err != nil {
 		return err
 	}
 
@@ -67,13 +120,10 @@ func parseTxApplied(c *Client, v *fastjson.Value) error {
 func parseTxGossipError(c *Client, v *fastjson.Value) error {
 	var t TxGossipError
 
-	if err := jsonTime(v, &t.Time, "time"); err != nil {
+	if err := t.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	t.Error = jsonString(v, "error")
-	t.Message = jsonString(v, "message")
-
 	if c.OnTxGossipError != nil {
 		c.OnTxGossipError(t)
 	}
@@ -84,23 +134,26 @@ func parseTxGossipError(c *Client, v *fastjson.Value) error {
 func parseTxFailed(c *Client, v *fastjson.Value) error {
 	var t TxFailed
 
-	if err := jsonHex(v, t.TxID[:], "tx_id"); err != nil {
+	if err := t.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	if err := jsonHex(v, t.SenderID[:], "sender_id"); err != nil {
-		return err
+	if c.OnTxFailed != nil {
+		c.OnTxFailed(t)
 	}
 
-	t.Tag = byte(v.GetUint("tag"))
-	t.Error = jsonString(v, "error")
+	return nil
+}
+
+func parseTxConflict(c *Client, v *fastjson.Value) error {
+	var t TxConflict
 
-	if err := jsonTime(v, &t.Time, "time"); err != nil {
+	if err := t.UnmarshalValue(v); err != nil {
 		return err
 	}
 
-	if c.OnTxFailed != nil {
-		c.OnTxFailed(t)
+	if c.OnTxConflict != nil {
+		c.OnTxConflict(t)
 	}
 
 	return nil